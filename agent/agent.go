@@ -0,0 +1,197 @@
+// Package agent runs AI coding sessions in-process by calling model
+// APIs directly. The agent loop and its tools (reading, writing, and
+// editing workspace files, and running commands) are implemented in
+// Go, so the dev container image only needs the project's own build
+// tools, not an AI CLI.
+//
+// File tools operate on the host-side workspace directory, confined
+// to it with [os.Root]. Commands run inside the job's dev container
+// through the [ExecFunc] supplied with each [Request].
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WorkspaceMount is the path at which the workspace is mounted in the
+// dev container. Paths the model uses are relative to it.
+const WorkspaceMount = "/workspace"
+
+// ErrMaxTurns is returned when a session reaches the configured turn
+// limit before the model finishes.
+var ErrMaxTurns = errors.New("agent reached the maximum number of turns")
+
+// ErrOutputLimit is returned when a session produces more output than
+// [Request.MaxOutputBytes] allows, e.g. a model stuck repeating
+// itself or a command flooding its log.
+var ErrOutputLimit = errors.New("agent exceeded the output limit")
+
+// ExecFunc runs a command in the job's dev container and returns its
+// combined output and exit code.
+type ExecFunc func(ctx context.Context, cmd []string) (output string, exitCode int, err error)
+
+// SecretResolver resolves secret references in configured
+// credentials. Implemented by *secrets.Store.
+type SecretResolver interface {
+	Resolve(value string) string
+}
+
+// Request describes a single agent session.
+type Request struct {
+	// Dir is the host path of the workspace.
+	Dir string
+
+	// Prompt is the initial user message.
+	Prompt string
+
+	// Model overrides the runner's default model. Empty means the
+	// default.
+	Model string
+
+	// Exec runs commands in the dev container.
+	Exec ExecFunc
+
+	// OnEvent, if set, is called as the session progresses: after
+	// each tool call and each model message. Calls are made from the
+	// goroutine running the session.
+	OnEvent func(Event)
+
+	// MaxOutputBytes aborts the session with [ErrOutputLimit] once
+	// the model's text and tool results together exceed this many
+	// bytes. Zero means no limit.
+	MaxOutputBytes int
+
+	// Transcript, if set, receives the session as JSON lines, one
+	// [TranscriptEntry] per prompt, model message, and tool call.
+	Transcript io.Writer
+}
+
+// TranscriptEntry is one line of a session transcript.
+type TranscriptEntry struct {
+	// Kind is "prompt", EventMessage, or EventToolCall.
+	Kind string `json:"kind"`
+	Turn int    `json:"turn"`
+
+	// Text is the prompt or message text.
+	Text string `json:"text,omitempty"`
+
+	// Tool, Input, Output, and Error describe a tool call.
+	Tool   string          `json:"tool,omitempty"`
+	Input  json.RawMessage `json:"input,omitempty"`
+	Output string          `json:"output,omitempty"`
+	Error  bool            `json:"error,omitempty"`
+}
+
+// Event kinds reported through [Request.OnEvent].
+const (
+	EventMessage  = "message"
+	EventToolCall = "tool_call"
+)
+
+// Event describes progress within a session.
+type Event struct {
+	// Kind is EventMessage or EventToolCall.
+	Kind string
+
+	// Turn is the 1-based model call the event belongs to.
+	Turn int
+
+	// Tool is the tool name, for tool calls.
+	Tool string
+
+	// Detail is the file path or command of a tool call, or the
+	// start of a message's text.
+	Detail string
+
+	// Error reports whether the tool call failed.
+	Error bool
+}
+
+// maxEventDetail caps Event.Detail so that long commands or messages
+// don't flood the log.
+const maxEventDetail = 200
+
+// session tracks progress reporting and the output budget shared by
+// the provider runners.
+type session struct {
+	req    Request
+	turn   int
+	output int
+}
+
+// prompt records the initial prompt in the transcript.
+func (s *session) prompt(text string) {
+	s.record(TranscriptEntry{Kind: "prompt", Text: text})
+}
+
+// message records model text, reporting it and charging it against
+// the output budget.
+func (s *session) message(text string) error {
+	s.record(TranscriptEntry{Kind: EventMessage, Text: text})
+	s.emit(Event{Kind: EventMessage, Detail: text})
+	return s.charge(len(text))
+}
+
+// toolCall records a finished tool call, charging both the model's
+// input (e.g., written file content) and the result.
+func (s *session) toolCall(tool string, input json.RawMessage, output string, err error) error {
+	s.record(TranscriptEntry{Kind: EventToolCall, Tool: tool, Input: input, Output: output, Error: err != nil})
+	s.emit(Event{Kind: EventToolCall, Tool: tool, Detail: describeCall(input), Error: err != nil})
+	return s.charge(len(input) + len(output))
+}
+
+// record appends an entry to the transcript. Write errors are ignored:
+// the transcript is an audit aid and must not end the session.
+func (s *session) record(e TranscriptEntry) {
+	if s.req.Transcript == nil {
+		return
+	}
+	e.Turn = s.turn
+	if line, err := json.Marshal(e); err == nil {
+		_, _ = s.req.Transcript.Write(append(line, '\n'))
+	}
+}
+
+func (s *session) emit(e Event) {
+	if s.req.OnEvent == nil {
+		return
+	}
+	e.Turn = s.turn
+	if len(e.Detail) > maxEventDetail {
+		e.Detail = e.Detail[:maxEventDetail] + "..."
+	}
+	s.req.OnEvent(e)
+}
+
+func (s *session) charge(n int) error {
+	s.output += n
+	if s.req.MaxOutputBytes > 0 && s.output > s.req.MaxOutputBytes {
+		return fmt.Errorf("%w (%d bytes)", ErrOutputLimit, s.req.MaxOutputBytes)
+	}
+	return nil
+}
+
+// Result summarizes a finished agent session.
+type Result struct {
+	// Turns is the number of model calls made.
+	Turns int
+
+	// InputTokens and OutputTokens are the totals across all turns.
+	InputTokens  int
+	OutputTokens int
+
+	// CachedTokens is the portion of InputTokens served from the
+	// provider's context cache, where reported.
+	CachedTokens int
+
+	// CostUSD is estimated from token counts and the configured
+	// prices.
+	CostUSD float64
+
+	// Summary is the model's final text response.
+	Summary string
+}
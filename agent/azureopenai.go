@@ -0,0 +1,364 @@
+package agent
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Azure OpenAI defaults used when the corresponding AzureOpenAIConfig
+// field is zero.
+const (
+	DefaultAzureOpenAIAPIVersion = "2024-10-21"
+	DefaultAzureOpenAIMaxTurns   = 100
+	DefaultAzureOpenAIMaxTokens  = 8192
+	DefaultEntraAuthorityURL     = "https://login.microsoftonline.com"
+
+	// azureOpenAIScope is the Entra ID scope of Azure OpenAI tokens.
+	azureOpenAIScope = "https://cognitiveservices.azure.com/.default"
+)
+
+// AzureOpenAIConfig configures an [AzureOpenAIRunner].
+type AzureOpenAIConfig struct {
+	// Endpoint is the resource endpoint, e.g.
+	// "https://my-resource.openai.azure.com".
+	Endpoint string
+
+	// Deployment is the default model deployment. A session's
+	// [Request.Model] names another deployment.
+	Deployment string
+
+	// APIVersion is the api-version query parameter. Defaults to
+	// DefaultAzureOpenAIAPIVersion.
+	APIVersion string
+
+	// APIKey authenticates with the resource's key. When empty,
+	// requests carry a Microsoft Entra ID token obtained for the
+	// application TenantID/ClientID with ClientSecret.
+	APIKey       string
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+
+	// AuthorityURL is the Entra ID endpoint. Defaults to
+	// DefaultEntraAuthorityURL.
+	AuthorityURL string
+
+	// Secrets resolves secret references in APIKey and ClientSecret.
+	// Optional.
+	Secrets SecretResolver
+
+	// MaxTurns bounds the number of model calls per session.
+	// Defaults to DefaultAzureOpenAIMaxTurns.
+	MaxTurns int
+
+	// MaxTokens bounds the output of a single model call. Defaults
+	// to DefaultAzureOpenAIMaxTokens.
+	MaxTokens int
+
+	// Token prices in USD per million tokens, used to estimate
+	// session cost. Cached input tokens are billed at CachedPerMTok.
+	InputPerMTok  float64
+	OutputPerMTok float64
+	CachedPerMTok float64
+
+	// HTTPClient is used for API calls. Defaults to a client with
+	// a five-minute timeout.
+	HTTPClient *http.Client
+}
+
+// AzureOpenAIRunner runs agent sessions against an Azure OpenAI
+// deployment's Chat Completions API.
+type AzureOpenAIRunner struct {
+	cfg    AzureOpenAIConfig
+	logger *zap.Logger
+
+	// sleep waits between retries; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewAzureOpenAIRunner creates an AzureOpenAIRunner, applying defaults
+// to unset configuration fields.
+func NewAzureOpenAIRunner(cfg AzureOpenAIConfig, logger *zap.Logger) *AzureOpenAIRunner {
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	cfg.APIVersion = cmp.Or(cfg.APIVersion, DefaultAzureOpenAIAPIVersion)
+	cfg.AuthorityURL = strings.TrimRight(cmp.Or(cfg.AuthorityURL, DefaultEntraAuthorityURL), "/")
+	cfg.MaxTurns = cmp.Or(cfg.MaxTurns, DefaultAzureOpenAIMaxTurns)
+	cfg.MaxTokens = cmp.Or(cfg.MaxTokens, DefaultAzureOpenAIMaxTokens)
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 5 * time.Minute}
+	}
+	return &AzureOpenAIRunner{cfg: cfg, logger: logger, sleep: sleepContext}
+}
+
+type openAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type openAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function openAIFunctionCall `json:"function"`
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	} `json:"function"`
+}
+
+type openAIRequest struct {
+	Messages            []openAIMessage `json:"messages"`
+	Tools               []openAITool    `json:"tools"`
+	MaxCompletionTokens int             `json:"max_completion_tokens"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens        int `json:"prompt_tokens"`
+		CompletionTokens    int `json:"completion_tokens"`
+		PromptTokensDetails struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"prompt_tokens_details"`
+	} `json:"usage"`
+}
+
+// Run executes a session: it sends the prompt, runs the tools the
+// model calls, and returns once the model replies without calling a
+// tool. The returned Result is populated even when an error is
+// returned.
+func (r *AzureOpenAIRunner) Run(ctx context.Context, req Request) (Result, error) {
+	var result Result
+
+	root, err := os.OpenRoot(req.Dir)
+	if err != nil {
+		return result, fmt.Errorf("open workspace: %w", err)
+	}
+	defer func() { _ = root.Close() }()
+	tools := &toolbox{root: root, exec: req.Exec}
+	progress := &session{req: req}
+	progress.prompt(req.Prompt)
+
+	deployment := cmp.Or(req.Model, r.cfg.Deployment)
+	apiReq := openAIRequest{
+		MaxCompletionTokens: r.cfg.MaxTokens,
+		Tools:               make([]openAITool, 0, len(Tools)),
+		Messages: []openAIMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: req.Prompt},
+		},
+	}
+	for _, t := range Tools {
+		tool := openAITool{Type: "function"}
+		tool.Function.Name, tool.Function.Description, tool.Function.Parameters = t.Name, t.Description, t.InputSchema
+		apiReq.Tools = append(apiReq.Tools, tool)
+	}
+
+	for result.Turns < r.cfg.MaxTurns {
+		resp, err := r.send(ctx, deployment, apiReq)
+		if err != nil {
+			return result, err
+		}
+		result.Turns++
+		progress.turn = result.Turns
+		result.InputTokens += resp.Usage.PromptTokens
+		result.OutputTokens += resp.Usage.CompletionTokens
+		result.CachedTokens += resp.Usage.PromptTokensDetails.CachedTokens
+		result.CostUSD = r.cost(result)
+
+		if len(resp.Choices) == 0 {
+			return result, errors.New("azure openai returned no choices")
+		}
+		choice := resp.Choices[0]
+		apiReq.Messages = append(apiReq.Messages, choice.Message)
+
+		if text := strings.TrimSpace(choice.Message.Content); text != "" {
+			result.Summary = text
+			if err := progress.message(text); err != nil {
+				return result, err
+			}
+		}
+		for _, call := range choice.Message.ToolCalls {
+			args := json.RawMessage(call.Function.Arguments)
+			output, err := tools.call(ctx, call.Function.Name, args)
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if err != nil {
+				output = err.Error()
+			}
+			apiReq.Messages = append(apiReq.Messages, openAIMessage{Role: "tool", ToolCallID: call.ID, Content: output})
+			if err := progress.toolCall(call.Function.Name, args, output, err); err != nil {
+				return result, err
+			}
+		}
+
+		switch {
+		case len(choice.Message.ToolCalls) > 0:
+			apiReq.Messages = compactOpenAIHistory(apiReq.Messages)
+		case choice.FinishReason == "length":
+			apiReq.Messages = append(apiReq.Messages, openAIMessage{Role: "user", Content: "Continue."})
+		default:
+			return result, nil
+		}
+	}
+	return result, ErrMaxTurns
+}
+
+// send makes one Chat Completions call to deployment, retrying
+// rate-limited and unavailable responses with exponential backoff.
+func (r *AzureOpenAIRunner) send(ctx context.Context, deployment string, apiReq openAIRequest) (*openAIResponse, error) {
+	body, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	endpoint := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		r.cfg.Endpoint, url.PathEscape(deployment), url.QueryEscape(r.cfg.APIVersion))
+
+	delay := initialRetryDelay
+	for attempt := 0; ; attempt++ {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if err := r.authorize(ctx, httpReq); err != nil {
+			return nil, err
+		}
+
+		resp, err := r.cfg.HTTPClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("call chat completions API: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read chat completions API response: %w", err)
+		}
+
+		if retryable(resp.StatusCode) && attempt < maxAPIRetries {
+			r.logger.Warn("Chat completions API call failed, retrying",
+				zap.Int("status", resp.StatusCode),
+				zap.Duration("delay", delay))
+			if err := r.sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+			delay *= 2
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("chat completions API returned status %d: %s", resp.StatusCode, truncate(respBody, 200))
+		}
+
+		var out openAIResponse
+		if err := json.Unmarshal(respBody, &out); err != nil {
+			return nil, fmt.Errorf("decode chat completions API response: %w", err)
+		}
+		return &out, nil
+	}
+}
+
+// authorize adds the API key, or an Entra ID token, to req.
+func (r *AzureOpenAIRunner) authorize(ctx context.Context, req *http.Request) error {
+	if r.cfg.APIKey != "" {
+		req.Header.Set("api-key", r.resolve(r.cfg.APIKey))
+		return nil
+	}
+	token, err := r.entraToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// entraToken returns an Entra ID access token for Azure OpenAI,
+// requesting a new one with the client credentials when the cached
+// token is within a minute of expiring.
+func (r *AzureOpenAIRunner) entraToken(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != "" && time.Now().Before(r.tokenExpiry) {
+		return r.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {r.cfg.ClientID},
+		"client_secret": {r.resolve(r.cfg.ClientSecret)},
+		"scope":         {azureOpenAIScope},
+	}
+	tokenURL := r.cfg.AuthorityURL + "/" + url.PathEscape(r.cfg.TenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := r.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request Entra ID token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read Entra ID token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("entra ID token request returned status %d: %s", resp.StatusCode, truncate(respBody, 200))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(respBody, &token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("decode Entra ID token response: %w", cmp.Or(err, errors.New("no access_token")))
+	}
+	r.token = token.AccessToken
+	r.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return r.token, nil
+}
+
+func (r *AzureOpenAIRunner) resolve(value string) string {
+	if r.cfg.Secrets != nil {
+		return r.cfg.Secrets.Resolve(value)
+	}
+	return value
+}
+
+func (r *AzureOpenAIRunner) cost(result Result) float64 {
+	cached := min(result.CachedTokens, result.InputTokens)
+	return float64(result.InputTokens-cached)*r.cfg.InputPerMTok/1_000_000 +
+		float64(cached)*r.cfg.CachedPerMTok/1_000_000 +
+		float64(result.OutputTokens)*r.cfg.OutputPerMTok/1_000_000
+}
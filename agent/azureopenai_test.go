@@ -0,0 +1,120 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/agent"
+)
+
+func TestAzureOpenAIRunner_RunsToolsUntilStop(t *testing.T) {
+	responses := []string{
+		`{"choices":[{"finish_reason":"tool_calls","message":{"role":"assistant","content":null,"tool_calls":[
+			{"id":"call_1","type":"function","function":{"name":"write_file","arguments":"{\"path\":\"hello.txt\",\"content\":\"hi\"}"}}]}}],
+		  "usage":{"prompt_tokens":1000,"completion_tokens":100,"prompt_tokens_details":{"cached_tokens":400}}}`,
+		`{"choices":[{"finish_reason":"stop","message":{"role":"assistant","content":"Added hello.txt."}}],
+		  "usage":{"prompt_tokens":2000,"completion_tokens":200}}`,
+	}
+	var requests []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4.1/chat/completions" || r.URL.Query().Get("api-version") == "" ||
+			r.Header.Get("api-key") != "test-key" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		requests = append(requests, body)
+		if len(requests) > len(responses) {
+			t.Errorf("unexpected request #%d", len(requests))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(responses[len(requests)-1]))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	runner := agent.NewAzureOpenAIRunner(agent.AzureOpenAIConfig{
+		Endpoint:      srv.URL,
+		Deployment:    "gpt-4.1",
+		APIKey:        "test-key",
+		InputPerMTok:  2,
+		OutputPerMTok: 8,
+		CachedPerMTok: 0.5,
+	}, zap.NewNop())
+
+	result, err := runner.Run(context.Background(), agent.Request{Dir: dir, Prompt: "Add hello.txt"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "hello.txt")); err != nil || string(data) != "hi" {
+		t.Errorf("hello.txt = %q, %v; want \"hi\"", data, err)
+	}
+	if result.Turns != 2 || result.InputTokens != 3000 || result.OutputTokens != 300 || result.CachedTokens != 400 {
+		t.Errorf("result = %+v", result)
+	}
+	// 2600 uncached input at $2, 400 cached at $0.50, 300 output at $8.
+	if want := 0.0078; math.Abs(result.CostUSD-want) > 1e-9 {
+		t.Errorf("CostUSD = %v, want %v", result.CostUSD, want)
+	}
+	if result.Summary != "Added hello.txt." {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+
+	// The second request carries the tool result for call_1.
+	msgs := requests[1]["messages"].([]any)
+	last := msgs[len(msgs)-1].(map[string]any)
+	if last["role"] != "tool" || last["tool_call_id"] != "call_1" {
+		t.Errorf("last message = %v, want a tool result for call_1", last)
+	}
+}
+
+func TestAzureOpenAIRunner_EntraIDToken(t *testing.T) {
+	tokenRequests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tenant/oauth2/v2.0/token":
+			tokenRequests++
+			if r.FormValue("client_id") != "app" || r.FormValue("client_secret") != "secret" ||
+				r.FormValue("scope") != "https://cognitiveservices.azure.com/.default" {
+				t.Errorf("token request form = %v", r.Form)
+			}
+			_, _ = w.Write([]byte(`{"access_token":"entra-token","expires_in":3600}`))
+		case "/openai/deployments/gpt-4.1/chat/completions":
+			if got := r.Header.Get("Authorization"); got != "Bearer entra-token" {
+				t.Errorf("Authorization = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"choices":[{"finish_reason":"stop","message":{"role":"assistant","content":"ok"}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	runner := agent.NewAzureOpenAIRunner(agent.AzureOpenAIConfig{
+		Endpoint:     srv.URL,
+		Deployment:   "gpt-4.1",
+		TenantID:     "tenant",
+		ClientID:     "app",
+		ClientSecret: "secret",
+		AuthorityURL: srv.URL,
+	}, zap.NewNop())
+	for range 2 {
+		if _, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"}); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Errorf("token requests = %d, want 1 (cached)", tokenRequests)
+	}
+}
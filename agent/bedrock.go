@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// bedrockAnthropicVersion is the Messages API version Bedrock expects
+// in the request body.
+const bedrockAnthropicVersion = "bedrock-2023-05-31"
+
+// BedrockConfig configures a [ClaudeRunner] that calls Claude through
+// Amazon Bedrock's InvokeModel API.
+type BedrockConfig struct {
+	// Region is the AWS region of the Bedrock endpoint (e.g.,
+	// "us-east-1").
+	Region string
+
+	// AccessKeyID, SecretAccessKey, and SessionToken are the AWS
+	// credentials requests are signed with (Signature Version 4).
+	// SessionToken is only needed for temporary credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// APIKey is a Bedrock API key, sent as a bearer token instead of
+	// signing requests. Used when AccessKeyID is empty.
+	APIKey string
+
+	// Secrets resolves secret references in the credentials before
+	// each call. Optional.
+	Secrets SecretResolver
+
+	// BaseURL overrides the endpoint, e.g. for a VPC endpoint.
+	// Defaults to https://bedrock-runtime.<Region>.amazonaws.com.
+	BaseURL string
+
+	// Model is the default Bedrock model or inference profile ID
+	// (e.g., "us.anthropic.claude-sonnet-4-5-20250929-v1:0").
+	Model string
+
+	// MaxTurns, MaxTokens, the prices, and HTTPClient are as for
+	// [ClaudeConfig].
+	MaxTurns      int
+	MaxTokens     int
+	InputPerMTok  float64
+	OutputPerMTok float64
+	HTTPClient    *http.Client
+}
+
+// NewBedrockRunner creates a ClaudeRunner that sends its Messages API
+// calls to Bedrock, applying defaults to unset configuration fields.
+func NewBedrockRunner(cfg BedrockConfig, logger *zap.Logger) *ClaudeRunner {
+	r := NewClaudeRunner(ClaudeConfig{
+		BaseURL:       cmp.Or(cfg.BaseURL, "https://bedrock-runtime."+cfg.Region+".amazonaws.com"),
+		Model:         cfg.Model,
+		MaxTurns:      cfg.MaxTurns,
+		MaxTokens:     cfg.MaxTokens,
+		InputPerMTok:  cfg.InputPerMTok,
+		OutputPerMTok: cfg.OutputPerMTok,
+		HTTPClient:    cfg.HTTPClient,
+	}, logger)
+	r.newRequest = func(ctx context.Context, apiReq claudeRequest) (*http.Request, error) {
+		return bedrockRequest(ctx, cfg, r.cfg.BaseURL, apiReq, time.Now())
+	}
+	return r
+}
+
+// bedrockRequest builds an InvokeModel request for apiReq, signed at
+// now.
+func bedrockRequest(ctx context.Context, cfg BedrockConfig, baseURL string, apiReq claudeRequest, now time.Time) (*http.Request, error) {
+	model := apiReq.Model
+	apiReq.Model = ""
+	apiReq.AnthropicVersion = bedrockAnthropicVersion
+	body, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	// Model IDs contain ":", which must reach Bedrock escaped.
+	escaped := "/model/" + awsURIEncode(model) + "/invoke"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+escaped, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resolve := func(v string) string {
+		if cfg.Secrets != nil {
+			return cfg.Secrets.Resolve(v)
+		}
+		return v
+	}
+	if cfg.AccessKeyID == "" {
+		httpReq.Header.Set("Authorization", "Bearer "+resolve(cfg.APIKey))
+		return httpReq, nil
+	}
+	signAWSRequest(httpReq, body, awsCredentials{
+		AccessKeyID:     resolve(cfg.AccessKeyID),
+		SecretAccessKey: resolve(cfg.SecretAccessKey),
+		SessionToken:    resolve(cfg.SessionToken),
+	}, cfg.Region, "bedrock", now)
+	return httpReq, nil
+}
+
+// awsCredentials are the credentials an AWS request is signed with.
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// signAWSRequest adds AWS Signature Version 4 headers to req, whose
+// body is body, for service in region at time now. The signed headers
+// are Host, X-Amz-Date, X-Amz-Security-Token (with a session token),
+// and any Content-Type.
+func signAWSRequest(req *http.Request, body []byte, creds awsCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for _, name := range []string{"Content-Type", "X-Amz-Date", "X-Amz-Security-Token"} {
+		if v := req.Header.Get(name); v != "" {
+			headers[strings.ToLower(name)] = strings.TrimSpace(v)
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Services other than S3 sign the path escaped once more.
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		segments[i] = awsURIEncode(seg)
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		strings.Join(segments, "/"),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonicalRequest))
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query string as Signature Version 4
+// signs it: encoded and sorted by name, then value.
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, v := range values {
+			pairs = append(pairs, awsURIEncode(name)+"="+awsURIEncode(v))
+		}
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes every byte of s except the unreserved
+// characters of RFC 3986, as AWS signing requires.
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,83 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/agent"
+)
+
+func TestSignAWSRequest_MatchesAWSTestSuite(t *testing.T) {
+	// The "get-vanilla" case of the AWS Signature Version 4 test suite.
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent.SignAWSRequest(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		"us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestBedrockRunner_InvokesModel(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        agent.BedrockConfig
+		wantPrefix string
+	}{
+		{
+			name:       "access keys",
+			cfg:        agent.BedrockConfig{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"},
+			wantPrefix: "AWS4-HMAC-SHA256 Credential=AKID/",
+		},
+		{
+			name:       "API key",
+			cfg:        agent.BedrockConfig{APIKey: "bedrock-key"},
+			wantPrefix: "Bearer bedrock-key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if want := "/model/us.anthropic.claude-sonnet-v1%3A0/invoke"; r.URL.EscapedPath() != want {
+					t.Errorf("path = %s, want %s", r.URL.EscapedPath(), want)
+				}
+				if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, tt.wantPrefix) {
+					t.Errorf("Authorization = %q, want prefix %q", auth, tt.wantPrefix)
+				}
+				if tt.cfg.SessionToken != "" && r.Header.Get("X-Amz-Security-Token") != tt.cfg.SessionToken {
+					t.Errorf("X-Amz-Security-Token = %q", r.Header.Get("X-Amz-Security-Token"))
+				}
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{"stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":5},
+					"content":[{"type":"text","text":"Done."}]}`))
+			}))
+			defer srv.Close()
+
+			cfg := tt.cfg
+			cfg.Region, cfg.BaseURL, cfg.Model = "us-east-1", srv.URL, "us.anthropic.claude-sonnet-v1:0"
+			result, err := agent.NewBedrockRunner(cfg, zap.NewNop()).Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if result.Summary != "Done." || result.InputTokens != 10 {
+				t.Errorf("result = %+v", result)
+			}
+			if _, ok := body["model"]; ok || body["anthropic_version"] != "bedrock-2023-05-31" {
+				t.Errorf("body has model %v and anthropic_version %v; want no model and bedrock-2023-05-31", body["model"], body["anthropic_version"])
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package agent
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Claude defaults used when the corresponding ClaudeConfig field is
+// zero.
+const (
+	DefaultClaudeBaseURL   = "https://api.anthropic.com"
+	DefaultClaudeModel     = "claude-sonnet-4-6"
+	DefaultClaudeMaxTurns  = 100
+	DefaultClaudeMaxTokens = 8192
+
+	anthropicVersion = "2023-06-01"
+)
+
+// Retry settings for rate-limited or overloaded API responses.
+const (
+	maxAPIRetries     = 4
+	initialRetryDelay = 2 * time.Second
+)
+
+// systemPrompt frames every session. The task itself is in the user
+// prompt.
+const systemPrompt = `You are an autonomous software engineer working on the repository mounted at /workspace.
+No user is available to answer questions: make reasonable decisions and complete the task on your own.
+Use the tools to inspect the code, make changes, and build and test them.
+Do not commit or push; the changes you leave in the working tree are collected automatically.
+When you are finished, reply with a short summary of what you changed.`
+
+// ClaudeConfig configures a [ClaudeRunner].
+type ClaudeConfig struct {
+	// APIKey authenticates with the Anthropic API. It may be a
+	// secret reference, resolved through Secrets before each session.
+	APIKey string
+
+	// Secrets resolves secret references in APIKey. Optional.
+	Secrets SecretResolver
+
+	// BaseURL is the API endpoint. Defaults to
+	// DefaultClaudeBaseURL.
+	BaseURL string
+
+	// Model is the default model. Defaults to DefaultClaudeModel.
+	Model string
+
+	// MaxTurns bounds the number of model calls per session.
+	// Defaults to DefaultClaudeMaxTurns.
+	MaxTurns int
+
+	// MaxTokens bounds the output of a single model call. Defaults
+	// to DefaultClaudeMaxTokens.
+	MaxTokens int
+
+	// InputPerMTok and OutputPerMTok are token prices in USD per
+	// million tokens, used to estimate session cost.
+	InputPerMTok  float64
+	OutputPerMTok float64
+
+	// HTTPClient is used for API calls. Defaults to a client with
+	// a five-minute timeout.
+	HTTPClient *http.Client
+}
+
+// ClaudeRunner runs agent sessions against the Anthropic Messages
+// API, or against Claude on Amazon Bedrock (see [NewBedrockRunner]).
+type ClaudeRunner struct {
+	cfg    ClaudeConfig
+	logger *zap.Logger
+
+	// newRequest builds the HTTP request for one API call; called
+	// again for each retry.
+	newRequest func(ctx context.Context, apiReq claudeRequest) (*http.Request, error)
+
+	// sleep waits between retries; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewClaudeRunner creates a ClaudeRunner, applying defaults to unset
+// configuration fields.
+func NewClaudeRunner(cfg ClaudeConfig, logger *zap.Logger) *ClaudeRunner {
+	cfg.BaseURL = strings.TrimRight(cmp.Or(cfg.BaseURL, DefaultClaudeBaseURL), "/")
+	cfg.Model = cmp.Or(cfg.Model, DefaultClaudeModel)
+	cfg.MaxTurns = cmp.Or(cfg.MaxTurns, DefaultClaudeMaxTurns)
+	cfg.MaxTokens = cmp.Or(cfg.MaxTokens, DefaultClaudeMaxTokens)
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 5 * time.Minute}
+	}
+	r := &ClaudeRunner{cfg: cfg, logger: logger, sleep: sleepContext}
+	r.newRequest = r.messagesRequest
+	return r
+}
+
+// claudeBlock is the union of the content block types the runner
+// sends and receives.
+type claudeBlock struct {
+	Type string `json:"type"`
+
+	// text
+	Text string `json:"text,omitempty"`
+
+	// tool_use
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// tool_result
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+type claudeMessage struct {
+	Role    string        `json:"role"`
+	Content []claudeBlock `json:"content"`
+}
+
+type claudeTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type claudeRequest struct {
+	Model     string          `json:"model,omitempty"`
+	MaxTokens int             `json:"max_tokens"`
+	System    string          `json:"system"`
+	Tools     []claudeTool    `json:"tools"`
+	Messages  []claudeMessage `json:"messages"`
+
+	// AnthropicVersion is set for Bedrock, which takes the model
+	// from the URL instead.
+	AnthropicVersion string `json:"anthropic_version,omitempty"`
+}
+
+type claudeResponse struct {
+	Content    []claudeBlock `json:"content"`
+	StopReason string        `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// Run executes a session: it sends the prompt, runs the tools the
+// model calls, and returns once the model ends its turn without
+// calling a tool. The returned Result is populated even when an
+// error is returned.
+func (r *ClaudeRunner) Run(ctx context.Context, req Request) (Result, error) {
+	var result Result
+
+	root, err := os.OpenRoot(req.Dir)
+	if err != nil {
+		return result, fmt.Errorf("open workspace: %w", err)
+	}
+	defer func() { _ = root.Close() }()
+	tools := &toolbox{root: root, exec: req.Exec}
+	progress := &session{req: req}
+	progress.prompt(req.Prompt)
+
+	apiReq := claudeRequest{
+		Model:     cmp.Or(req.Model, r.cfg.Model),
+		MaxTokens: r.cfg.MaxTokens,
+		System:    systemPrompt,
+		Tools:     make([]claudeTool, 0, len(Tools)),
+		Messages: []claudeMessage{{
+			Role:    "user",
+			Content: []claudeBlock{{Type: "text", Text: req.Prompt}},
+		}},
+	}
+	for _, t := range Tools {
+		apiReq.Tools = append(apiReq.Tools, claudeTool{Name: t.Name, Description: t.Description, InputSchema: t.InputSchema})
+	}
+
+	for result.Turns < r.cfg.MaxTurns {
+		resp, err := r.send(ctx, apiReq)
+		if err != nil {
+			return result, err
+		}
+		result.Turns++
+		progress.turn = result.Turns
+		result.InputTokens += resp.Usage.InputTokens
+		result.OutputTokens += resp.Usage.OutputTokens
+		result.CostUSD = r.cost(result)
+
+		apiReq.Messages = append(apiReq.Messages, claudeMessage{Role: "assistant", Content: resp.Content})
+
+		var toolResults []claudeBlock
+		for _, block := range resp.Content {
+			switch block.Type {
+			case "text":
+				if text := strings.TrimSpace(block.Text); text != "" {
+					result.Summary = text
+					if err := progress.message(text); err != nil {
+						return result, err
+					}
+				}
+			case "tool_use":
+				output, err := tools.call(ctx, block.Name, block.Input)
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				toolResult := claudeBlock{Type: "tool_result", ToolUseID: block.ID, Content: output}
+				if err != nil {
+					toolResult.Content = err.Error()
+					toolResult.IsError = true
+				}
+				toolResults = append(toolResults, toolResult)
+				if err := progress.toolCall(block.Name, block.Input, toolResult.Content, err); err != nil {
+					return result, err
+				}
+			}
+		}
+
+		switch {
+		case len(toolResults) > 0:
+			apiReq.Messages = append(apiReq.Messages, claudeMessage{Role: "user", Content: toolResults})
+			apiReq.Messages = compactClaudeHistory(apiReq.Messages)
+		case resp.StopReason == "max_tokens":
+			apiReq.Messages = append(apiReq.Messages, claudeMessage{
+				Role:    "user",
+				Content: []claudeBlock{{Type: "text", Text: "Continue."}},
+			})
+		default:
+			return result, nil
+		}
+	}
+	return result, ErrMaxTurns
+}
+
+// messagesRequest builds an Anthropic Messages API request.
+func (r *ClaudeRunner) messagesRequest(ctx context.Context, apiReq claudeRequest) (*http.Request, error) {
+	body, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	apiKey := r.cfg.APIKey
+	if r.cfg.Secrets != nil {
+		apiKey = r.cfg.Secrets.Resolve(apiKey)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.BaseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+	return httpReq, nil
+}
+
+// send makes one Messages API call, retrying rate-limited and
+// overloaded responses with exponential backoff.
+func (r *ClaudeRunner) send(ctx context.Context, apiReq claudeRequest) (*claudeResponse, error) {
+	delay := initialRetryDelay
+	for attempt := 0; ; attempt++ {
+		httpReq, err := r.newRequest(ctx, apiReq)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := r.cfg.HTTPClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("call messages API: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read messages API response: %w", err)
+		}
+
+		if retryable(resp.StatusCode) && attempt < maxAPIRetries {
+			r.logger.Warn("Messages API call failed, retrying",
+				zap.Int("status", resp.StatusCode),
+				zap.Duration("delay", delay))
+			if err := r.sleep(ctx, delay); err != nil {
+				return nil, err
+			}
+			delay *= 2
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("messages API returned status %d: %s", resp.StatusCode, truncate(respBody, 200))
+		}
+
+		var out claudeResponse
+		if err := json.Unmarshal(respBody, &out); err != nil {
+			return nil, fmt.Errorf("decode messages API response: %w", err)
+		}
+		return &out, nil
+	}
+}
+
+func (r *ClaudeRunner) cost(result Result) float64 {
+	return float64(result.InputTokens)*r.cfg.InputPerMTok/1_000_000 +
+		float64(result.OutputTokens)*r.cfg.OutputPerMTok/1_000_000
+}
+
+// retryable reports whether an API status code indicates a transient
+// failure: rate limiting (429), server errors, or overload (529).
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) <= n {
+		return string(b)
+	}
+	return string(b[:n]) + "..."
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
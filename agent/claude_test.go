@@ -0,0 +1,310 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/agent"
+)
+
+// scriptedServer replies to successive Messages API calls with the
+// given responses and records the decoded requests.
+func scriptedServer(t *testing.T, responses ...string) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var requests []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "test-key" ||
+			r.Header.Get("anthropic-version") == "" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		requests = append(requests, body)
+		if len(requests) > len(responses) {
+			t.Errorf("unexpected request #%d", len(requests))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(responses[len(requests)-1]))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestClaudeRunner_RunsToolsUntilEndTurn(t *testing.T) {
+	srv, requests := scriptedServer(t,
+		`{"stop_reason":"tool_use","usage":{"input_tokens":1000,"output_tokens":100},"content":[
+			{"type":"text","text":"I'll add the file."},
+			{"type":"tool_use","id":"tu_1","name":"write_file","input":{"path":"hello.txt","content":"hi"}}]}`,
+		`{"stop_reason":"end_turn","usage":{"input_tokens":2000,"output_tokens":200},"content":[
+			{"type":"text","text":"Added hello.txt."}]}`,
+	)
+	dir := t.TempDir()
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{
+		APIKey:        "test-key",
+		BaseURL:       srv.URL,
+		InputPerMTok:  3,
+		OutputPerMTok: 15,
+	}, zap.NewNop())
+
+	result, err := runner.Run(context.Background(), agent.Request{Dir: dir, Prompt: "Add hello.txt"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "hello.txt")); err != nil || string(data) != "hi" {
+		t.Errorf("hello.txt = %q, %v; want \"hi\"", data, err)
+	}
+	if result.Turns != 2 || result.InputTokens != 3000 || result.OutputTokens != 300 {
+		t.Errorf("result = %+v", result)
+	}
+	if want := 0.0135; math.Abs(result.CostUSD-want) > 1e-9 {
+		t.Errorf("CostUSD = %v, want %v", result.CostUSD, want)
+	}
+	if result.Summary != "Added hello.txt." {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+
+	// The second request carries the tool result for tu_1.
+	msgs := (*requests)[1]["messages"].([]any)
+	last := msgs[len(msgs)-1].(map[string]any)
+	block := last["content"].([]any)[0].(map[string]any)
+	if last["role"] != "user" || block["type"] != "tool_result" || block["tool_use_id"] != "tu_1" {
+		t.Errorf("last message = %v, want a tool_result for tu_1", last)
+	}
+	if (*requests)[0]["model"] != agent.DefaultClaudeModel {
+		t.Errorf("model = %v, want %s", (*requests)[0]["model"], agent.DefaultClaudeModel)
+	}
+}
+
+func TestClaudeRunner_ToolErrorsAreReportedToModel(t *testing.T) {
+	srv, requests := scriptedServer(t,
+		`{"stop_reason":"tool_use","content":[
+			{"type":"tool_use","id":"tu_1","name":"read_file","input":{"path":"/etc/passwd"}}]}`,
+		`{"stop_reason":"end_turn","content":[{"type":"text","text":"Done."}]}`,
+	)
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "test-key", BaseURL: srv.URL}, zap.NewNop())
+
+	if _, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	msgs := (*requests)[1]["messages"].([]any)
+	block := msgs[len(msgs)-1].(map[string]any)["content"].([]any)[0].(map[string]any)
+	if block["is_error"] != true {
+		t.Errorf("tool result = %v, want is_error", block)
+	}
+}
+
+func TestClaudeRunner_MaxTurns(t *testing.T) {
+	toolUse := `{"stop_reason":"tool_use","content":[
+		{"type":"tool_use","id":"tu","name":"list_files","input":{}}]}`
+	srv, _ := scriptedServer(t, toolUse, toolUse)
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "test-key", BaseURL: srv.URL, MaxTurns: 2}, zap.NewNop())
+
+	result, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"})
+	if !errors.Is(err, agent.ErrMaxTurns) {
+		t.Errorf("err = %v, want ErrMaxTurns", err)
+	}
+	if result.Turns != 2 {
+		t.Errorf("Turns = %d, want 2", result.Turns)
+	}
+}
+
+func TestClaudeRunner_RetriesOverloaded(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(529)
+			return
+		}
+		_, _ = w.Write([]byte(`{"stop_reason":"end_turn","content":[{"type":"text","text":"ok"}]}`))
+	}))
+	defer srv.Close()
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "k", BaseURL: srv.URL}, zap.NewNop())
+	runner.SetSleep(func(context.Context, time.Duration) error { return nil })
+
+	if _, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestClaudeRunner_ClientErrorIsNotRetried(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad"}}`))
+	}))
+	defer srv.Close()
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "k", BaseURL: srv.URL}, zap.NewNop())
+
+	if _, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClaudeRunner_ReportsProgress(t *testing.T) {
+	srv, _ := scriptedServer(t,
+		`{"stop_reason":"tool_use","content":[
+			{"type":"text","text":"Running tests."},
+			{"type":"tool_use","id":"tu_1","name":"run_command","input":{"command":"make test"}}]}`,
+		`{"stop_reason":"end_turn","content":[{"type":"text","text":"Done."}]}`,
+	)
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "test-key", BaseURL: srv.URL}, zap.NewNop())
+	var events []agent.Event
+	exec := func(context.Context, []string) (string, int, error) { return "PASS", 0, nil }
+
+	if _, err := runner.Run(context.Background(), agent.Request{
+		Dir:     t.TempDir(),
+		Prompt:  "x",
+		Exec:    exec,
+		OnEvent: func(e agent.Event) { events = append(events, e) },
+	}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []agent.Event{
+		{Kind: agent.EventMessage, Turn: 1, Detail: "Running tests."},
+		{Kind: agent.EventToolCall, Turn: 1, Tool: "run_command", Detail: "make test"},
+		{Kind: agent.EventMessage, Turn: 2, Detail: "Done."},
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+}
+
+func TestClaudeRunner_OutputLimit(t *testing.T) {
+	srv, _ := scriptedServer(t,
+		`{"stop_reason":"tool_use","content":[
+			{"type":"tool_use","id":"tu_1","name":"run_command","input":{"command":"yes"}}]}`,
+	)
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "test-key", BaseURL: srv.URL}, zap.NewNop())
+	exec := func(context.Context, []string) (string, int, error) {
+		return strings.Repeat("y\n", 1000), 0, nil
+	}
+
+	_, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x", Exec: exec, MaxOutputBytes: 1000})
+	if !errors.Is(err, agent.ErrOutputLimit) {
+		t.Errorf("err = %v, want ErrOutputLimit", err)
+	}
+}
+
+func TestClaudeRunner_WritesTranscript(t *testing.T) {
+	srv, _ := scriptedServer(t,
+		`{"stop_reason":"tool_use","content":[
+			{"type":"tool_use","id":"tu_1","name":"list_files","input":{}}]}`,
+		`{"stop_reason":"end_turn","content":[{"type":"text","text":"Done."}]}`,
+	)
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "test-key", BaseURL: srv.URL}, zap.NewNop())
+	var transcript strings.Builder
+
+	if _, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "Do it", Transcript: &transcript}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var kinds []string
+	for _, line := range strings.Split(strings.TrimSpace(transcript.String()), "\n") {
+		var e agent.TranscriptEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("bad transcript line %q: %v", line, err)
+		}
+		kinds = append(kinds, e.Kind+":"+e.Tool+e.Text)
+	}
+	want := []string{"prompt:Do it", "tool_call:list_files", "message:Done."}
+	if !slices.Equal(kinds, want) {
+		t.Errorf("transcript = %q, want %q", kinds, want)
+	}
+}
+
+// toolTurn is a scripted response that calls tool with input.
+func toolTurn(id, tool, input string) string {
+	return `{"stop_reason":"tool_use","usage":{"input_tokens":1,"output_tokens":1},"content":[
+		{"type":"tool_use","id":"` + id + `","name":"` + tool + `","input":` + input + `}]}`
+}
+
+const endTurn = `{"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1},"content":[
+	{"type":"text","text":"Done."}]}`
+
+func TestClaudeRunner_ElidesOldToolResults(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("x", 50<<10)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	read := `{"path":"big.txt"}`
+	srv, requests := scriptedServer(t,
+		toolTurn("tu_1", "read_file", read),
+		toolTurn("tu_2", "read_file", read),
+		toolTurn("tu_3", "read_file", read),
+		toolTurn("tu_4", "read_file", read),
+		toolTurn("tu_5", "read_file", read),
+		endTurn,
+	)
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "test-key", BaseURL: srv.URL}, zap.NewNop())
+
+	if _, err := runner.Run(context.Background(), agent.Request{Dir: dir, Prompt: "Read big.txt"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	msgs := (*requests)[5]["messages"].([]any)
+	result := func(i int) string {
+		block := msgs[i].(map[string]any)["content"].([]any)[0].(map[string]any)
+		return block["content"].(string)
+	}
+	if got := result(2); len(got) > 3<<10 || !strings.Contains(got, "elided") {
+		t.Errorf("oldest tool result is %d bytes, want it elided", len(got))
+	}
+	if got := result(len(msgs) - 1); len(got) < 50<<10 {
+		t.Errorf("latest tool result is %d bytes, want it in full", len(got))
+	}
+}
+
+func TestClaudeRunner_CapsHistorySize(t *testing.T) {
+	write := `{"path":"big.txt","content":"` + strings.Repeat("x", 100<<10) + `"}`
+	responses := make([]string, 0, 9)
+	for i := range 8 {
+		responses = append(responses, toolTurn(fmt.Sprintf("tu_%d", i), "write_file", write))
+	}
+	srv, requests := scriptedServer(t, append(responses, endTurn)...)
+	runner := agent.NewClaudeRunner(agent.ClaudeConfig{APIKey: "test-key", BaseURL: srv.URL}, zap.NewNop())
+
+	if _, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "Write big.txt"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	last := (*requests)[len(*requests)-1]
+	body, _ := json.Marshal(last["messages"])
+	if len(body) > 500<<10 {
+		t.Errorf("history is %d bytes, want it capped", len(body))
+	}
+	msgs := last["messages"].([]any)
+	first := msgs[0].(map[string]any)["content"].([]any)[0].(map[string]any)
+	if first["text"] != "Write big.txt" {
+		t.Errorf("first message = %v, want the prompt kept", first)
+	}
+	if msgs[1].(map[string]any)["role"] != "assistant" {
+		t.Errorf("second message = %v, want an assistant turn", msgs[1])
+	}
+}
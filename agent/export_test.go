@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+)
+
+// SetSleep replaces the retry sleep for testing.
+func (r *ClaudeRunner) SetSleep(sleep func(ctx context.Context, d time.Duration) error) {
+	r.sleep = sleep
+}
+
+// SetSleep replaces the retry sleep for testing.
+func (r *GeminiRunner) SetSleep(sleep func(ctx context.Context, d time.Duration) error) {
+	r.sleep = sleep
+}
+
+// CallTool runs a single tool against dir for testing.
+func CallTool(ctx context.Context, dir string, exec ExecFunc, name string, input any) (string, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = root.Close() }()
+
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	return (&toolbox{root: root, exec: exec}).call(ctx, name, raw)
+}
+
+// SignAWSRequest signs req with AWS Signature Version 4 for testing.
+func SignAWSRequest(req *http.Request, body []byte, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	signAWSRequest(req, body, awsCredentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey}, region, service, now)
+}
+
+// SetSleep replaces the retry sleep for testing.
+func (r *AzureOpenAIRunner) SetSleep(sleep func(ctx context.Context, d time.Duration) error) {
+	r.sleep = sleep
+}
@@ -0,0 +1,236 @@
+package agent
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/genai"
+)
+
+// Gemini defaults used when the corresponding GeminiConfig field is
+// zero.
+const (
+	DefaultGeminiModel     = "gemini-2.5-pro"
+	DefaultGeminiMaxTurns  = 100
+	DefaultGeminiMaxTokens = 8192
+)
+
+// GeminiConfig configures a [GeminiRunner].
+type GeminiConfig struct {
+	// APIKey authenticates with the Gemini API. It may be a secret
+	// reference, resolved through Secrets before each session.
+	APIKey string
+
+	// Secrets resolves secret references in APIKey. Optional.
+	Secrets SecretResolver
+
+	// BaseURL overrides the API endpoint. Empty means the SDK
+	// default.
+	BaseURL string
+
+	// Model is the default model. Defaults to DefaultGeminiModel.
+	Model string
+
+	// MaxTurns bounds the number of model calls per session.
+	// Defaults to DefaultGeminiMaxTurns.
+	MaxTurns int
+
+	// MaxTokens bounds the output of a single model call. Defaults
+	// to DefaultGeminiMaxTokens.
+	MaxTokens int
+
+	// Token prices in USD per million tokens, used to estimate
+	// session cost. Cached input tokens are billed at CachedPerMTok.
+	InputPerMTok  float64
+	OutputPerMTok float64
+	CachedPerMTok float64
+
+	// HTTPClient is used for API calls. Defaults to a client with
+	// a five-minute timeout.
+	HTTPClient *http.Client
+}
+
+// GeminiRunner runs agent sessions against the Gemini API through
+// the Google Gen AI SDK.
+type GeminiRunner struct {
+	cfg    GeminiConfig
+	logger *zap.Logger
+
+	// sleep waits between retries; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewGeminiRunner creates a GeminiRunner, applying defaults to unset
+// configuration fields.
+func NewGeminiRunner(cfg GeminiConfig, logger *zap.Logger) *GeminiRunner {
+	cfg.Model = cmp.Or(cfg.Model, DefaultGeminiModel)
+	cfg.MaxTurns = cmp.Or(cfg.MaxTurns, DefaultGeminiMaxTurns)
+	cfg.MaxTokens = cmp.Or(cfg.MaxTokens, DefaultGeminiMaxTokens)
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 5 * time.Minute}
+	}
+	return &GeminiRunner{cfg: cfg, logger: logger, sleep: sleepContext}
+}
+
+// Run executes a session: it sends the prompt, runs the functions the
+// model calls, and returns once the model replies without calling a
+// function. The returned Result is populated even when an error is
+// returned.
+func (r *GeminiRunner) Run(ctx context.Context, req Request) (Result, error) {
+	var result Result
+
+	root, err := os.OpenRoot(req.Dir)
+	if err != nil {
+		return result, fmt.Errorf("open workspace: %w", err)
+	}
+	defer func() { _ = root.Close() }()
+	tools := &toolbox{root: root, exec: req.Exec}
+	progress := &session{req: req}
+	progress.prompt(req.Prompt)
+
+	apiKey := r.cfg.APIKey
+	if r.cfg.Secrets != nil {
+		apiKey = r.cfg.Secrets.Resolve(apiKey)
+	}
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:      apiKey,
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  r.cfg.HTTPClient,
+		HTTPOptions: genai.HTTPOptions{BaseURL: r.cfg.BaseURL},
+	})
+	if err != nil {
+		return result, fmt.Errorf("create Gemini client: %w", err)
+	}
+
+	model := cmp.Or(req.Model, r.cfg.Model)
+	config := &genai.GenerateContentConfig{
+		SystemInstruction: genai.NewContentFromText(systemPrompt, genai.RoleUser),
+		MaxOutputTokens:   int32(r.cfg.MaxTokens), // #nosec G115 -- small configured value
+		Tools:             []*genai.Tool{{FunctionDeclarations: geminiFunctions()}},
+	}
+	history := []*genai.Content{genai.NewContentFromText(req.Prompt, genai.RoleUser)}
+
+	for result.Turns < r.cfg.MaxTurns {
+		resp, err := r.generate(ctx, client, model, history, config)
+		if err != nil {
+			return result, err
+		}
+		result.Turns++
+		progress.turn = result.Turns
+		if u := resp.UsageMetadata; u != nil {
+			result.InputTokens += int(u.PromptTokenCount)
+			result.OutputTokens += int(u.CandidatesTokenCount + u.ThoughtsTokenCount)
+			result.CachedTokens += int(u.CachedContentTokenCount)
+		}
+		result.CostUSD = r.cost(result)
+
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+			return result, errors.New("gemini returned no candidates")
+		}
+		candidate := resp.Candidates[0]
+		history = append(history, candidate.Content)
+
+		var responses []*genai.Part
+		for _, part := range candidate.Content.Parts {
+			if text := strings.TrimSpace(part.Text); text != "" && !part.Thought {
+				result.Summary = text
+				if err := progress.message(text); err != nil {
+					return result, err
+				}
+			}
+			call := part.FunctionCall
+			if call == nil {
+				continue
+			}
+			args, err := json.Marshal(call.Args)
+			if err != nil {
+				return result, fmt.Errorf("marshal function args: %w", err)
+			}
+			output, err := tools.call(ctx, call.Name, args)
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			response := map[string]any{"output": output}
+			if err != nil {
+				output = err.Error()
+				response = map[string]any{"error": output}
+			}
+			if err := progress.toolCall(call.Name, args, output, err); err != nil {
+				return result, err
+			}
+			responses = append(responses, &genai.Part{FunctionResponse: &genai.FunctionResponse{
+				ID:       call.ID,
+				Name:     call.Name,
+				Response: response,
+			}})
+		}
+
+		switch {
+		case len(responses) > 0:
+			history = append(history, genai.NewContentFromParts(responses, genai.RoleUser))
+			history = compactGeminiHistory(history)
+		case candidate.FinishReason == genai.FinishReasonMaxTokens:
+			history = append(history, genai.NewContentFromText("Continue.", genai.RoleUser))
+		default:
+			return result, nil
+		}
+	}
+	return result, ErrMaxTurns
+}
+
+// generate makes one GenerateContent call, retrying rate-limited and
+// unavailable responses with exponential backoff.
+func (r *GeminiRunner) generate(
+	ctx context.Context,
+	client *genai.Client,
+	model string,
+	history []*genai.Content,
+	config *genai.GenerateContentConfig,
+) (*genai.GenerateContentResponse, error) {
+	delay := initialRetryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Models.GenerateContent(ctx, model, history, config)
+		if err == nil {
+			return resp, nil
+		}
+		var apiErr genai.APIError
+		if !errors.As(err, &apiErr) || !retryable(apiErr.Code) || attempt >= maxAPIRetries {
+			return nil, fmt.Errorf("generate content: %w", err)
+		}
+		r.logger.Warn("Gemini API call failed, retrying",
+			zap.Int("status", apiErr.Code),
+			zap.Duration("delay", delay))
+		if err := r.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+func (r *GeminiRunner) cost(result Result) float64 {
+	cached := min(result.CachedTokens, result.InputTokens)
+	return float64(result.InputTokens-cached)*r.cfg.InputPerMTok/1_000_000 +
+		float64(cached)*r.cfg.CachedPerMTok/1_000_000 +
+		float64(result.OutputTokens)*r.cfg.OutputPerMTok/1_000_000
+}
+
+// geminiFunctions converts [Tools] to Gemini function declarations.
+func geminiFunctions() []*genai.FunctionDeclaration {
+	decls := make([]*genai.FunctionDeclaration, 0, len(Tools))
+	for _, t := range Tools {
+		decls = append(decls, &genai.FunctionDeclaration{
+			Name:                 t.Name,
+			Description:          t.Description,
+			ParametersJsonSchema: t.InputSchema,
+		})
+	}
+	return decls
+}
@@ -0,0 +1,130 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/agent"
+)
+
+// scriptedGemini replies to successive generateContent calls with the
+// given responses and records the decoded requests.
+func scriptedGemini(t *testing.T, responses ...string) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var requests []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/models/gemini-2.5-pro:generateContent") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		requests = append(requests, body)
+		if len(requests) > len(responses) {
+			t.Errorf("unexpected request #%d", len(requests))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responses[len(requests)-1]))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestGeminiRunner_RunsFunctionsUntilDone(t *testing.T) {
+	srv, requests := scriptedGemini(t,
+		`{"candidates":[{"finishReason":"STOP","content":{"role":"model","parts":[
+			{"functionCall":{"name":"write_file","args":{"path":"hello.txt","content":"hi"}}}]}}],
+		  "usageMetadata":{"promptTokenCount":1000,"candidatesTokenCount":100,"cachedContentTokenCount":400}}`,
+		`{"candidates":[{"finishReason":"STOP","content":{"role":"model","parts":[{"text":"Added hello.txt."}]}}],
+		  "usageMetadata":{"promptTokenCount":2000,"candidatesTokenCount":200}}`,
+	)
+	dir := t.TempDir()
+	runner := agent.NewGeminiRunner(agent.GeminiConfig{
+		APIKey:        "test-key",
+		BaseURL:       srv.URL,
+		InputPerMTok:  1,
+		OutputPerMTok: 10,
+		CachedPerMTok: 0.5,
+	}, zap.NewNop())
+
+	result, err := runner.Run(context.Background(), agent.Request{Dir: dir, Prompt: "Add hello.txt"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "hello.txt")); err != nil || string(data) != "hi" {
+		t.Errorf("hello.txt = %q, %v; want \"hi\"", data, err)
+	}
+	if result.Turns != 2 || result.InputTokens != 3000 || result.OutputTokens != 300 || result.CachedTokens != 400 {
+		t.Errorf("result = %+v", result)
+	}
+	// 2600 uncached input at $1, 400 cached at $0.50, 300 output at $10.
+	if want := 0.0026 + 0.0002 + 0.003; math.Abs(result.CostUSD-want) > 1e-9 {
+		t.Errorf("CostUSD = %v, want %v", result.CostUSD, want)
+	}
+	if result.Summary != "Added hello.txt." {
+		t.Errorf("Summary = %q", result.Summary)
+	}
+
+	// The second request carries the function response.
+	contents := (*requests)[1]["contents"].([]any)
+	last := contents[len(contents)-1].(map[string]any)
+	part := last["parts"].([]any)[0].(map[string]any)
+	fr, ok := part["functionResponse"].(map[string]any)
+	if !ok || fr["name"] != "write_file" {
+		t.Errorf("last content = %v, want a write_file functionResponse", last)
+	}
+}
+
+func TestGeminiRunner_MaxTurns(t *testing.T) {
+	call := `{"candidates":[{"finishReason":"STOP","content":{"role":"model","parts":[
+		{"functionCall":{"name":"list_files","args":{}}}]}}]}`
+	srv, _ := scriptedGemini(t, call, call)
+	runner := agent.NewGeminiRunner(agent.GeminiConfig{APIKey: "test-key", BaseURL: srv.URL, MaxTurns: 2}, zap.NewNop())
+
+	result, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"})
+	if !errors.Is(err, agent.ErrMaxTurns) {
+		t.Errorf("err = %v, want ErrMaxTurns", err)
+	}
+	if result.Turns != 2 {
+		t.Errorf("Turns = %d, want 2", result.Turns)
+	}
+}
+
+func TestGeminiRunner_RetriesRateLimit(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":429,"message":"quota","status":"RESOURCE_EXHAUSTED"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"candidates":[{"finishReason":"STOP","content":{"role":"model","parts":[{"text":"ok"}]}}]}`))
+	}))
+	defer srv.Close()
+	runner := agent.NewGeminiRunner(agent.GeminiConfig{APIKey: "k", BaseURL: srv.URL}, zap.NewNop())
+	runner.SetSleep(func(context.Context, time.Duration) error { return nil })
+
+	if _, err := runner.Run(context.Background(), agent.Request{Dir: t.TempDir(), Prompt: "x"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
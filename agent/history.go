@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"encoding/json"
+	"slices"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+)
+
+// Limits on the conversation history resent with every model call.
+// Without them a long session would outgrow the model's context
+// window, and the cost of each call would grow with every turn.
+const (
+	// recentHistoryMessages is how many of the latest messages are
+	// always sent in full, so that the model sees the output of the
+	// tools it just ran.
+	recentHistoryMessages = 6
+
+	// staleToolOutput caps tool results older than the recent
+	// messages; the model can run a tool again when it needs more.
+	staleToolOutput = 2 << 10
+
+	// maxHistoryBytes caps the whole history. Beyond it the oldest
+	// turns after the prompt are dropped.
+	maxHistoryBytes = 400 << 10
+)
+
+// elideOutput shortens a tool result that has left the recent
+// history to staleToolOutput bytes.
+func elideOutput(output string) string {
+	if len(output) <= staleToolOutput {
+		return output
+	}
+	cut := staleToolOutput
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+	return output[:cut] + "\n[rest of this earlier tool output elided from the history; run the tool again if you need it]"
+}
+
+// compactClaudeHistory elides old tool results in messages and drops
+// the oldest turns while the history exceeds maxHistoryBytes. The
+// first message is the prompt; each turn after it is an assistant
+// message and the user message answering it.
+func compactClaudeHistory(messages []claudeMessage) []claudeMessage {
+	for i := 1; i < len(messages)-recentHistoryMessages; i++ {
+		for j := range messages[i].Content {
+			if b := &messages[i].Content[j]; b.Type == "tool_result" {
+				b.Content = elideOutput(b.Content)
+			}
+		}
+	}
+	size := func() int {
+		n := 0
+		for _, m := range messages {
+			for _, b := range m.Content {
+				n += len(b.Text) + len(b.Input) + len(b.Content)
+			}
+		}
+		return n
+	}
+	for size() > maxHistoryBytes && len(messages) > recentHistoryMessages+2 {
+		messages = slices.Delete(messages, 1, 3)
+	}
+	return messages
+}
+
+// compactOpenAIHistory elides old tool results in messages and drops
+// the oldest turns while the history exceeds maxHistoryBytes. The
+// first two messages are the system prompt and the prompt; each turn
+// after them is an assistant message and the tool or user messages
+// answering it.
+func compactOpenAIHistory(messages []openAIMessage) []openAIMessage {
+	for i := 2; i < len(messages)-recentHistoryMessages; i++ {
+		if messages[i].Role == "tool" {
+			messages[i].Content = elideOutput(messages[i].Content)
+		}
+	}
+	size := func() int {
+		n := 0
+		for _, m := range messages {
+			n += len(m.Content)
+			for _, c := range m.ToolCalls {
+				n += len(c.Function.Arguments)
+			}
+		}
+		return n
+	}
+	for size() > maxHistoryBytes && len(messages) > recentHistoryMessages+3 {
+		end := 3
+		for end < len(messages) && messages[end].Role != "assistant" {
+			end++
+		}
+		if end >= len(messages)-recentHistoryMessages {
+			break
+		}
+		messages = slices.Delete(messages, 2, end)
+	}
+	return messages
+}
+
+// compactGeminiHistory elides old function responses in history and
+// drops the oldest turns while the history exceeds maxHistoryBytes.
+// The first content is the prompt; each turn after it is a model
+// content and the user content answering it.
+func compactGeminiHistory(history []*genai.Content) []*genai.Content {
+	for i := 1; i < len(history)-recentHistoryMessages; i++ {
+		for _, part := range history[i].Parts {
+			if fr := part.FunctionResponse; fr != nil {
+				for key, value := range fr.Response {
+					if s, ok := value.(string); ok {
+						fr.Response[key] = elideOutput(s)
+					}
+				}
+			}
+		}
+	}
+	size := func() int {
+		n := 0
+		for _, c := range history {
+			for _, part := range c.Parts {
+				n += len(part.Text)
+				if fc := part.FunctionCall; fc != nil {
+					args, _ := json.Marshal(fc.Args)
+					n += len(args)
+				}
+				if fr := part.FunctionResponse; fr != nil {
+					for _, value := range fr.Response {
+						if s, ok := value.(string); ok {
+							n += len(s)
+						}
+					}
+				}
+			}
+		}
+		return n
+	}
+	for size() > maxHistoryBytes && len(history) > recentHistoryMessages+2 {
+		history = slices.Delete(history, 1, 3)
+	}
+	return history
+}
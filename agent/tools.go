@@ -0,0 +1,300 @@
+package agent
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// maxToolOutput caps the size of a single tool result sent back to the
+// model, keeping one large file or log from exhausting the context.
+const maxToolOutput = 64 << 10
+
+// Tool describes a tool offered to the model. InputSchema is a JSON
+// Schema object.
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema map[string]any
+}
+
+// ToolRunCommand is the name of the tool that runs a shell command in
+// the dev container.
+const ToolRunCommand = "run_command"
+
+// Tools lists the tools every agent session offers.
+var Tools = []Tool{
+	{
+		Name:        "read_file",
+		Description: "Read a text file from the workspace.",
+		InputSchema: objectSchema(map[string]any{
+			"path": stringProp("File path relative to /workspace."),
+		}, "path"),
+	},
+	{
+		Name:        "write_file",
+		Description: "Create or overwrite a file in the workspace. Parent directories are created as needed.",
+		InputSchema: objectSchema(map[string]any{
+			"path":    stringProp("File path relative to /workspace."),
+			"content": stringProp("The complete new file content."),
+		}, "path", "content"),
+	},
+	{
+		Name:        "edit_file",
+		Description: "Replace one exact, unique occurrence of old_string with new_string in a workspace file.",
+		InputSchema: objectSchema(map[string]any{
+			"path":       stringProp("File path relative to /workspace."),
+			"old_string": stringProp("Text to replace. Must occur exactly once in the file."),
+			"new_string": stringProp("Replacement text."),
+		}, "path", "old_string", "new_string"),
+	},
+	{
+		Name:        "list_files",
+		Description: "List the entries of a workspace directory. Directories end with a slash.",
+		InputSchema: objectSchema(map[string]any{
+			"path": stringProp("Directory path relative to /workspace. Empty means the workspace root."),
+		}),
+	},
+	{
+		Name:        ToolRunCommand,
+		Description: "Run a bash command in /workspace inside the dev container, e.g. to build, test, or search the code. Returns the exit code and combined output.",
+		InputSchema: objectSchema(map[string]any{
+			"command": stringProp("The bash command to run."),
+		}, "command"),
+	},
+}
+
+func objectSchema(props map[string]any, required ...string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProp(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+// toolbox executes tool calls for one session.
+type toolbox struct {
+	root *os.Root
+	exec ExecFunc
+}
+
+// toolInput holds the union of all tool arguments.
+type toolInput struct {
+	Path      string `json:"path"`
+	Content   string `json:"content"`
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
+	Command   string `json:"command"`
+}
+
+// call runs the named tool. Errors describe problems the model can
+// act on (e.g., a missing file) and are returned to it as error
+// results rather than ending the session.
+func (t *toolbox) call(ctx context.Context, name string, raw json.RawMessage) (string, error) {
+	in, err := parseToolInput(raw)
+	if err != nil {
+		return "", err
+	}
+
+	switch name {
+	case "read_file":
+		return t.readFile(in.Path)
+	case "write_file":
+		return t.writeFile(in.Path, in.Content)
+	case "edit_file":
+		return t.editFile(in.Path, in.OldString, in.NewString)
+	case "list_files":
+		return t.listFiles(in.Path)
+	case ToolRunCommand:
+		return t.runCommand(ctx, in.Command)
+	default:
+		return "", fmt.Errorf("unknown tool %q", name)
+	}
+}
+
+func parseToolInput(raw json.RawMessage) (toolInput, error) {
+	var in toolInput
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &in); err != nil {
+			return in, fmt.Errorf("invalid input: %w", err)
+		}
+	}
+	return in, nil
+}
+
+// describeCall returns the path or command a tool call acts on, for
+// progress reporting.
+func describeCall(raw json.RawMessage) string {
+	in, _ := parseToolInput(raw)
+	return cmp.Or(in.Command, in.Path)
+}
+
+// relPath converts a model-supplied path into one relative to the
+// workspace root. Paths under /workspace are accepted in either form;
+// other absolute paths are rejected. Escapes via ".." or symlinks are
+// rejected by os.Root.
+func relPath(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if rest, ok := strings.CutPrefix(p, WorkspaceMount); ok && (rest == "" || rest[0] == '/') {
+		p = strings.TrimPrefix(rest, "/")
+	}
+	if path.IsAbs(p) {
+		return "", fmt.Errorf("path %q is outside %s", p, WorkspaceMount)
+	}
+	p = path.Clean(p)
+	if p == "" {
+		p = "."
+	}
+	return p, nil
+}
+
+func (t *toolbox) readFile(p string) (string, error) {
+	rel, err := relPath(p)
+	if err != nil {
+		return "", err
+	}
+	f, err := t.root.Open(rel)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxToolOutput+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxToolOutput {
+		return string(data[:maxToolOutput]) + "\n[file truncated]", nil
+	}
+	return string(data), nil
+}
+
+func (t *toolbox) writeFile(p, content string) (string, error) {
+	rel, err := relPath(p)
+	if err != nil {
+		return "", err
+	}
+	if err := t.mkdirAll(path.Dir(rel)); err != nil {
+		return "", err
+	}
+	f, err := t.root.OpenFile(rel, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Wrote %d bytes to %s", len(content), rel), nil
+}
+
+func (t *toolbox) editFile(p, oldString, newString string) (string, error) {
+	if oldString == "" {
+		return "", errors.New("old_string must not be empty")
+	}
+	rel, err := relPath(p)
+	if err != nil {
+		return "", err
+	}
+	f, err := t.root.Open(rel)
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil {
+		return "", err
+	}
+
+	content := string(data)
+	switch n := strings.Count(content, oldString); n {
+	case 0:
+		return "", fmt.Errorf("old_string not found in %s", rel)
+	case 1:
+	default:
+		return "", fmt.Errorf("old_string occurs %d times in %s; include more context to make it unique", n, rel)
+	}
+	if _, err := t.writeFile(rel, strings.Replace(content, oldString, newString, 1)); err != nil {
+		return "", err
+	}
+	return "Edited " + rel, nil
+}
+
+func (t *toolbox) listFiles(p string) (string, error) {
+	rel, err := relPath(p)
+	if err != nil {
+		return "", err
+	}
+	dir, err := t.root.Open(rel)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = dir.Close() }()
+
+	entries, err := dir.ReadDir(-1)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Name() == ".git" {
+			continue
+		}
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\n"), nil
+}
+
+func (t *toolbox) runCommand(ctx context.Context, command string) (string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", errors.New("command must not be empty")
+	}
+	output, exitCode, err := t.exec(ctx, []string{"bash", "-c", "cd " + WorkspaceMount + " && " + command})
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("command failed to run: %w", err)
+	}
+	// Keep the tail: build and test failures are reported last.
+	if len(output) > maxToolOutput {
+		output = "[output truncated]\n" + output[len(output)-maxToolOutput:]
+	}
+	return fmt.Sprintf("exit code: %d\n%s", exitCode, output), nil
+}
+
+// mkdirAll creates dir and its parents inside the root.
+func (t *toolbox) mkdirAll(dir string) error {
+	if dir == "." {
+		return nil
+	}
+	current := ""
+	for _, part := range strings.Split(dir, "/") {
+		current = path.Join(current, part)
+		err := t.root.Mkdir(current, 0o755)
+		if err != nil && !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package agent_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+)
+
+func noExec(t *testing.T) agent.ExecFunc {
+	return func(context.Context, []string) (string, int, error) {
+		t.Error("exec should not be called")
+		return "", 0, nil
+	}
+}
+
+func TestTools_WriteAndReadFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	if _, err := agent.CallTool(ctx, dir, noExec(t), "write_file",
+		map[string]string{"path": "/workspace/pkg/sub/a.go", "content": "package sub\n"}); err != nil {
+		t.Fatalf("write_file: %v", err)
+	}
+	got, err := agent.CallTool(ctx, dir, noExec(t), "read_file", map[string]string{"path": "pkg/sub/a.go"})
+	if err != nil {
+		t.Fatalf("read_file: %v", err)
+	}
+	if got != "package sub\n" {
+		t.Errorf("read_file = %q, want %q", got, "package sub\n")
+	}
+}
+
+func TestTools_RejectsPathsOutsideWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	for _, p := range []string{"/etc/passwd", "../outside.txt", "/workspace/../outside.txt", "/workspacefoo/a"} {
+		if _, err := agent.CallTool(ctx, dir, noExec(t), "write_file",
+			map[string]string{"path": p, "content": "x"}); err == nil {
+			t.Errorf("write_file(%q) succeeded, want error", p)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "outside.txt")); err == nil {
+		t.Error("file was written outside the workspace")
+	}
+}
+
+func TestTools_EditFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one two two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := agent.CallTool(ctx, dir, noExec(t), "edit_file",
+		map[string]string{"path": "a.txt", "old_string": "two", "new_string": "2"}); err == nil ||
+		!strings.Contains(err.Error(), "occurs 2 times") {
+		t.Errorf("ambiguous edit error = %v, want 'occurs 2 times'", err)
+	}
+	if _, err := agent.CallTool(ctx, dir, noExec(t), "edit_file",
+		map[string]string{"path": "a.txt", "old_string": "one", "new_string": "1"}); err != nil {
+		t.Fatalf("edit_file: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "a.txt"))
+	if string(data) != "1 two two" {
+		t.Errorf("content = %q, want %q", data, "1 two two")
+	}
+}
+
+func TestTools_ListFilesSkipsGit(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{".git", "cmd"} {
+		if err := os.Mkdir(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := agent.CallTool(context.Background(), dir, noExec(t), "list_files", map[string]string{})
+	if err != nil {
+		t.Fatalf("list_files: %v", err)
+	}
+	if got != "cmd/\ngo.mod" {
+		t.Errorf("list_files = %q, want %q", got, "cmd/\ngo.mod")
+	}
+}
+
+func TestTools_RunCommand(t *testing.T) {
+	var gotCmd []string
+	exec := func(_ context.Context, cmd []string) (string, int, error) {
+		gotCmd = cmd
+		return "FAIL\n", 1, nil
+	}
+
+	got, err := agent.CallTool(context.Background(), t.TempDir(), exec, "run_command", map[string]string{"command": "go test ./..."})
+	if err != nil {
+		t.Fatalf("run_command: %v", err)
+	}
+	if got != "exit code: 1\nFAIL\n" {
+		t.Errorf("output = %q", got)
+	}
+	if len(gotCmd) != 3 || gotCmd[2] != "cd /workspace && go test ./..." {
+		t.Errorf("cmd = %q", gotCmd)
+	}
+}
@@ -0,0 +1,239 @@
+// Package ailimit bounds how many AI sessions run at once and how
+// quickly they start, so that a burst of tickets neither exhausts a
+// provider's API rate limits nor saturates the machine.
+//
+// A [Limiter] has a global concurrency limit and, per provider, a
+// concurrency limit and a minimum interval between session starts.
+// [Limiter.Acquire] blocks until a session may start; [Limiter.Stats]
+// reports running and waiting sessions and the time spent waiting,
+// for the health endpoint.
+package ailimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProviderLimits are the limits for one AI provider. Zero values mean
+// no limit.
+type ProviderLimits struct {
+	// MaxConcurrent is the number of the provider's sessions that may
+	// run at once.
+	MaxConcurrent int
+
+	// MinInterval is the minimum time between the starts of two of
+	// the provider's sessions.
+	MinInterval time.Duration
+}
+
+// Config holds construction parameters for [Limiter].
+type Config struct {
+	// MaxConcurrent is the number of sessions of all providers that
+	// may run at once. Zero means no limit.
+	MaxConcurrent int
+
+	// Providers holds per-provider limits, keyed by provider name
+	// (e.g., "claude"). Providers not listed are limited only by
+	// MaxConcurrent.
+	Providers map[string]ProviderLimits
+}
+
+// Stats describes the limiter's current and cumulative workload.
+type Stats struct {
+	// Running and Waiting count sessions of all providers.
+	Running int
+	Waiting int
+
+	// MaxConcurrent is the global limit; zero means none.
+	MaxConcurrent int
+
+	// Providers lists each provider that is limited or has run a
+	// session, sorted by name.
+	Providers []ProviderStats
+}
+
+// ProviderStats describes one provider's sessions.
+type ProviderStats struct {
+	Provider      string
+	Running       int
+	Waiting       int
+	MaxConcurrent int
+	MinInterval   time.Duration
+
+	// Started counts sessions started since the bot started, and
+	// Delayed those of them that had to wait for a limit.
+	Started int64
+	Delayed int64
+
+	// TotalWait is the time started sessions spent waiting.
+	TotalWait time.Duration
+}
+
+// Limiter admits AI sessions within the configured limits. It is safe
+// for concurrent use.
+type Limiter struct {
+	maxConcurrent int
+	global        chan struct{} // nil when unlimited
+
+	mu        sync.Mutex
+	providers map[string]*provider
+	running   int
+	waiting   int
+}
+
+// provider is a provider's limits and counters. Counters are guarded
+// by Limiter.mu.
+type provider struct {
+	limits ProviderLimits
+	slots  chan struct{} // nil when unlimited
+	next   time.Time     // earliest start of the next session
+
+	running, waiting int
+	started, delayed int64
+	totalWait        time.Duration
+}
+
+// NewLimiter creates a Limiter enforcing cfg.
+func NewLimiter(cfg Config) (*Limiter, error) {
+	if cfg.MaxConcurrent < 0 {
+		return nil, errors.New("max concurrent AI sessions must not be negative")
+	}
+	l := &Limiter{maxConcurrent: cfg.MaxConcurrent, providers: make(map[string]*provider)}
+	if cfg.MaxConcurrent > 0 {
+		l.global = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	for name, limits := range cfg.Providers {
+		if limits.MaxConcurrent < 0 {
+			return nil, fmt.Errorf("%s: max concurrent sessions must not be negative", name)
+		}
+		if limits.MinInterval < 0 {
+			return nil, fmt.Errorf("%s: minimum interval between sessions must not be negative", name)
+		}
+		p := &provider{limits: limits}
+		if limits.MaxConcurrent > 0 {
+			p.slots = make(chan struct{}, limits.MaxConcurrent)
+		}
+		l.providers[name] = p
+	}
+	return l, nil
+}
+
+// Acquire blocks until a session of the named provider may start and
+// returns the function that ends it, which must be called once the
+// session finishes. It returns ctx's error if ctx is done first.
+func (l *Limiter) Acquire(ctx context.Context, name string) (release func(), err error) {
+	start := time.Now()
+
+	l.mu.Lock()
+	p := l.providers[name]
+	if p == nil {
+		p = &provider{}
+		l.providers[name] = p
+	}
+	p.waiting++
+	l.waiting++
+	l.mu.Unlock()
+
+	var slots []chan struct{}
+	defer func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		p.waiting--
+		l.waiting--
+		if err != nil {
+			return
+		}
+		p.running++
+		l.running++
+		p.started++
+		if wait := time.Since(start); wait >= time.Millisecond {
+			p.delayed++
+			p.totalWait += wait
+		}
+	}()
+
+	// The provider's slot is taken before the global one so that a
+	// session waiting on its provider does not hold a global slot.
+	for _, sem := range []chan struct{}{p.slots, l.global} {
+		if sem == nil {
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+			slots = append(slots, sem)
+		case <-ctx.Done():
+			freeSlots(slots)
+			return nil, ctx.Err()
+		}
+	}
+
+	if p.limits.MinInterval > 0 {
+		l.mu.Lock()
+		at := time.Now()
+		if p.next.After(at) {
+			at = p.next
+		}
+		p.next = at.Add(p.limits.MinInterval)
+		l.mu.Unlock()
+
+		if wait := time.Until(at); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				freeSlots(slots)
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			p.running--
+			l.running--
+			l.mu.Unlock()
+			freeSlots(slots)
+		})
+	}, nil
+}
+
+// Stats returns a snapshot of the limiter's workload.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := Stats{
+		Running:       l.running,
+		Waiting:       l.waiting,
+		MaxConcurrent: l.maxConcurrent,
+		Providers:     make([]ProviderStats, 0, len(l.providers)),
+	}
+	for name, p := range l.providers {
+		stats.Providers = append(stats.Providers, ProviderStats{
+			Provider:      name,
+			Running:       p.running,
+			Waiting:       p.waiting,
+			MaxConcurrent: p.limits.MaxConcurrent,
+			MinInterval:   p.limits.MinInterval,
+			Started:       p.started,
+			Delayed:       p.delayed,
+			TotalWait:     p.totalWait,
+		})
+	}
+	sort.Slice(stats.Providers, func(i, j int) bool {
+		return stats.Providers[i].Provider < stats.Providers[j].Provider
+	})
+	return stats
+}
+
+func freeSlots(slots []chan struct{}) {
+	for _, sem := range slots {
+		<-sem
+	}
+}
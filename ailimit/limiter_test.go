@@ -0,0 +1,167 @@
+package ailimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/ailimit"
+)
+
+func newLimiter(t *testing.T, cfg ailimit.Config) *ailimit.Limiter {
+	t.Helper()
+	l, err := ailimit.NewLimiter(cfg)
+	if err != nil {
+		t.Fatalf("NewLimiter: %v", err)
+	}
+	return l
+}
+
+// acquireAsync starts an Acquire and returns a channel that receives
+// its release function once it succeeds.
+func acquireAsync(t *testing.T, l *ailimit.Limiter, provider string) <-chan func() {
+	t.Helper()
+	ch := make(chan func(), 1)
+	go func() {
+		release, err := l.Acquire(context.Background(), provider)
+		if err != nil {
+			t.Errorf("Acquire(%s): %v", provider, err)
+			return
+		}
+		ch <- release
+	}()
+	return ch
+}
+
+// waitFor polls until cond holds or fails the test after a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiter_ProviderConcurrency(t *testing.T) {
+	l := newLimiter(t, ailimit.Config{
+		Providers: map[string]ailimit.ProviderLimits{"claude": {MaxConcurrent: 1}},
+	})
+
+	first, err := l.Acquire(context.Background(), "claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := acquireAsync(t, l, "claude")
+	waitFor(t, "second session to queue", func() bool { return l.Stats().Waiting == 1 })
+
+	// Another provider is not held up.
+	other, err := l.Acquire(context.Background(), "gemini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+
+	select {
+	case <-second:
+		t.Fatal("second claude session started while the first was running")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	first()
+	first() // releasing twice frees one slot only
+	(<-second)()
+
+	stats := l.Stats()
+	if stats.Running != 0 || stats.Waiting != 0 {
+		t.Errorf("running %d, waiting %d; want 0, 0", stats.Running, stats.Waiting)
+	}
+	if len(stats.Providers) != 2 || stats.Providers[0].Provider != "claude" || stats.Providers[1].Provider != "gemini" {
+		t.Fatalf("providers = %+v, want claude and gemini", stats.Providers)
+	}
+	claude := stats.Providers[0]
+	if claude.Started != 2 || claude.Delayed != 1 || claude.TotalWait < 20*time.Millisecond || claude.MaxConcurrent != 1 {
+		t.Errorf("claude stats = %+v, want 2 started, 1 delayed by at least 20ms", claude)
+	}
+}
+
+func TestLimiter_GlobalConcurrency(t *testing.T) {
+	l := newLimiter(t, ailimit.Config{MaxConcurrent: 1})
+
+	first, err := l.Acquire(context.Background(), "claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := acquireAsync(t, l, "gemini")
+	waitFor(t, "gemini session to queue", func() bool { return l.Stats().Waiting == 1 })
+	if stats := l.Stats(); stats.Running != 1 || stats.MaxConcurrent != 1 {
+		t.Errorf("stats = %+v, want 1 running of 1", stats)
+	}
+
+	first()
+	(<-second)()
+}
+
+func TestLimiter_MinInterval(t *testing.T) {
+	interval := 30 * time.Millisecond
+	l := newLimiter(t, ailimit.Config{
+		Providers: map[string]ailimit.ProviderLimits{"claude": {MinInterval: interval}},
+	})
+
+	start := time.Now()
+	for range 3 {
+		release, err := l.Acquire(context.Background(), "claude")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("three sessions started within %s, want at least %s", elapsed, 2*interval)
+	}
+}
+
+func TestLimiter_AcquireCancelled(t *testing.T) {
+	l := newLimiter(t, ailimit.Config{MaxConcurrent: 1})
+	release, err := l.Acquire(context.Background(), "claude")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, "claude"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire with expired context: err = %v, want deadline exceeded", err)
+	}
+	if stats := l.Stats(); stats.Waiting != 0 || stats.Running != 1 || stats.Providers[0].Started != 1 {
+		t.Errorf("stats after cancelled wait = %+v, want only the first session", stats)
+	}
+
+	release()
+	next, err := l.Acquire(context.Background(), "claude")
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	next()
+}
+
+func TestNewLimiter_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ailimit.Config
+	}{
+		{name: "negative global limit", cfg: ailimit.Config{MaxConcurrent: -1}},
+		{name: "negative provider limit", cfg: ailimit.Config{Providers: map[string]ailimit.ProviderLimits{"claude": {MaxConcurrent: -1}}}},
+		{name: "negative interval", cfg: ailimit.Config{Providers: map[string]ailimit.ProviderLimits{"claude": {MinInterval: -time.Second}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ailimit.NewLimiter(tt.cfg); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package circuit stops calls to an external service while it is
+// down, so that an outage is met with one trial call per cooldown
+// instead of a request for every ticket.
+//
+// A [Breaker] opens after a configured number of consecutive failed
+// calls. While open, [Breaker.Allow] rejects calls; once the cooldown
+// has passed it admits a single trial call. A successful trial closes
+// the breaker, and a failed one reopens it with the cooldown doubled,
+// up to a maximum, so that a long outage is polled less and less
+// often. [Breaker.Stats] reports the state for the health endpoint.
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned, wrapped, by callers that skipped a call because
+// the breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State values reported in [Stats].
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half_open"
+)
+
+// Config holds construction parameters for [Breaker].
+type Config struct {
+	// Threshold is the number of consecutive failures that opens the
+	// breaker. Zero disables the breaker.
+	Threshold int
+
+	// Cooldown is how long the breaker stays open the first time it
+	// opens. Each failed trial call doubles it.
+	Cooldown time.Duration
+
+	// MaxCooldown caps the doubled cooldown. Zero or less than
+	// Cooldown means the cooldown never grows.
+	MaxCooldown time.Duration
+
+	// Clock returns the current time. Defaults to [time.Now].
+	// Exposed for testing.
+	Clock func() time.Time
+}
+
+// Stats describes the breaker's current state.
+type Stats struct {
+	// State is StateClosed, StateOpen, or StateHalfOpen.
+	State string
+
+	// Failures is the number of consecutive failed calls.
+	Failures int
+
+	// Threshold is the configured failure threshold; zero means the
+	// breaker is disabled.
+	Threshold int
+
+	// Cooldown is the time the breaker stays open on its next trip,
+	// or on its current one while open.
+	Cooldown time.Duration
+
+	// RetryAt is when the next trial call is admitted. Zero unless
+	// the breaker is open.
+	RetryAt time.Time
+
+	// Trips counts the times the breaker opened since the bot
+	// started, including reopenings after failed trials.
+	Trips int64
+}
+
+// Breaker tracks the outcome of calls to one service. It is safe for
+// concurrent use.
+type Breaker struct {
+	cfg Config
+
+	mu       sync.Mutex
+	failures int
+	cooldown time.Duration
+	open     bool
+	openedAt time.Time
+	trial    bool // a trial call is in flight
+	trips    int64
+}
+
+// NewBreaker creates a Breaker enforcing cfg.
+func NewBreaker(cfg Config) (*Breaker, error) {
+	if cfg.Threshold < 0 {
+		return nil, errors.New("circuit breaker threshold must not be negative")
+	}
+	if cfg.Threshold > 0 && cfg.Cooldown <= 0 {
+		return nil, errors.New("circuit breaker cooldown must be positive")
+	}
+	if cfg.MaxCooldown < cfg.Cooldown {
+		cfg.MaxCooldown = cfg.Cooldown
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	return &Breaker{cfg: cfg, cooldown: cfg.Cooldown}, nil
+}
+
+// Allow reports whether a call may be made. Every allowed call must be
+// followed by [Breaker.Record] with its outcome; an allowed trial call
+// that is never recorded keeps the breaker from admitting another.
+func (b *Breaker) Allow() bool {
+	if b.cfg.Threshold == 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if b.trial || b.cfg.Clock().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// Record reports the outcome of an allowed call. It returns true when
+// a failure opened the breaker, so that the caller can log the outage
+// once.
+func (b *Breaker) Record(ok bool) (opened bool) {
+	if b.cfg.Threshold == 0 {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ok {
+		b.failures = 0
+		b.cooldown = b.cfg.Cooldown
+		b.open = false
+		b.trial = false
+		return false
+	}
+
+	b.failures++
+	switch {
+	case b.trial:
+		// The service is still down: wait longer before the next trial.
+		b.trial = false
+		b.cooldown = min(2*b.cooldown, b.cfg.MaxCooldown)
+	case b.open || b.failures < b.cfg.Threshold:
+		// Calls allowed before the breaker opened may still report.
+		return false
+	}
+	b.open = true
+	b.openedAt = b.cfg.Clock()
+	b.trips++
+	return true
+}
+
+// Stats returns a snapshot of the breaker's state.
+func (b *Breaker) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := Stats{
+		State:     StateClosed,
+		Failures:  b.failures,
+		Threshold: b.cfg.Threshold,
+		Cooldown:  b.cooldown,
+		Trips:     b.trips,
+	}
+	switch {
+	case b.trial:
+		s.State = StateHalfOpen
+	case b.open:
+		s.State = StateOpen
+		s.RetryAt = b.openedAt.Add(b.cooldown)
+	}
+	return s
+}
@@ -0,0 +1,133 @@
+package circuit_test
+
+import (
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/circuit"
+)
+
+// fakeClock is a settable clock for breaker tests.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newBreaker(t *testing.T, clock *fakeClock) *circuit.Breaker {
+	t.Helper()
+	b, err := circuit.NewBreaker(circuit.Config{
+		Threshold:   3,
+		Cooldown:    time.Minute,
+		MaxCooldown: 3 * time.Minute,
+		Clock:       clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewBreaker: %v", err)
+	}
+	return b
+}
+
+// fail records n failed calls.
+func fail(b *circuit.Breaker, n int) (opened bool) {
+	for range n {
+		if b.Allow() {
+			opened = b.Record(false) || opened
+		}
+	}
+	return opened
+}
+
+func TestNewBreaker_Validation(t *testing.T) {
+	if _, err := circuit.NewBreaker(circuit.Config{Threshold: -1}); err == nil {
+		t.Error("negative threshold: want error")
+	}
+	if _, err := circuit.NewBreaker(circuit.Config{Threshold: 1}); err == nil {
+		t.Error("missing cooldown: want error")
+	}
+	if _, err := circuit.NewBreaker(circuit.Config{}); err != nil {
+		t.Errorf("disabled breaker: %v", err)
+	}
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := newBreaker(t, clock)
+
+	if fail(b, 2) {
+		t.Fatal("opened before the threshold")
+	}
+	b.Allow()
+	b.Record(true)
+	if fail(b, 2) {
+		t.Fatal("a success should reset the failure count")
+	}
+	if !fail(b, 1) {
+		t.Fatal("want the breaker to open at the threshold")
+	}
+	if b.Allow() {
+		t.Error("open breaker allowed a call")
+	}
+
+	s := b.Stats()
+	if s.State != circuit.StateOpen || s.Trips != 1 || !s.RetryAt.Equal(clock.now.Add(time.Minute)) {
+		t.Errorf("stats = %+v, want open until a minute from now", s)
+	}
+}
+
+func TestBreaker_SingleTrialAfterCooldown(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := newBreaker(t, clock)
+	fail(b, 3)
+
+	clock.Advance(time.Minute)
+	if !b.Allow() {
+		t.Fatal("want a trial call after the cooldown")
+	}
+	if b.Allow() {
+		t.Error("a second call was allowed while the trial is in flight")
+	}
+	if got := b.Stats().State; got != circuit.StateHalfOpen {
+		t.Errorf("state = %s, want %s", got, circuit.StateHalfOpen)
+	}
+
+	b.Record(true)
+	if !b.Allow() {
+		t.Error("a successful trial should close the breaker")
+	}
+}
+
+func TestBreaker_FailedTrialDoublesCooldown(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := newBreaker(t, clock)
+	fail(b, 3)
+
+	for _, want := range []time.Duration{2 * time.Minute, 3 * time.Minute, 3 * time.Minute} {
+		clock.Advance(b.Stats().Cooldown)
+		if !b.Allow() {
+			t.Fatal("want a trial call after the cooldown")
+		}
+		if !b.Record(false) {
+			t.Error("a failed trial should reopen the breaker")
+		}
+		if got := b.Stats().Cooldown; got != want {
+			t.Errorf("cooldown = %v, want %v", got, want)
+		}
+	}
+
+	clock.Advance(3 * time.Minute)
+	b.Allow()
+	b.Record(true)
+	if got := b.Stats().Cooldown; got != time.Minute {
+		t.Errorf("cooldown after recovery = %v, want the initial minute", got)
+	}
+}
+
+func TestBreaker_DisabledAlwaysAllows(t *testing.T) {
+	b, err := circuit.NewBreaker(circuit.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fail(b, 100) || !b.Allow() {
+		t.Error("disabled breaker should never open")
+	}
+}
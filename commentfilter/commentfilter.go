@@ -8,7 +8,12 @@
 //
 // Filtering rules:
 //   - Comments from ignored usernames are removed entirely
+//   - Review comments on files matching an ignored path pattern are
+//     removed entirely
 //   - Comments containing only slash commands (e.g. /lgtm) are removed
+//   - Bodies of approving reviews are removed unless they contain more
+//     than approval phrases and slash commands (e.g. "LGTM, but please
+//     fix the typo" is kept)
 //   - Comments containing @<botUsername> ignore are removed
 //   - Known bot comments replying to our bot are removed (prevents
 //     bot-to-bot ping-pong)
@@ -22,10 +27,12 @@ package commentfilter
 
 import (
 	"fmt"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"jira-ai-issue-solver/models"
 )
@@ -58,6 +65,18 @@ func parseAddressedIDs(body string) []int64 {
 	return ids
 }
 
+// QuestionMarker is embedded in bot replies that ask the reviewer a
+// follow-up question instead of changing the code. The comment stays
+// pending until the reviewer answers in the thread (or with a new
+// conversation comment), which starts the next feedback round.
+const QuestionMarker = "<!-- ai-bot: question -->"
+
+// IsQuestion reports whether a bot comment body is a follow-up
+// question (see [QuestionMarker]).
+func IsQuestion(body string) bool {
+	return strings.Contains(body, QuestionMarker)
+}
+
 // Config holds bot-loop prevention settings.
 type Config struct {
 	// BotUsername is the bot's GitHub username, used to identify
@@ -69,6 +88,12 @@ type Config struct {
 	// review feedback (e.g., packit-as-a-service[bot]).
 	IgnoredUsernames []string
 
+	// IgnoredPaths lists file path patterns whose review comments
+	// are removed entirely (see matchPath for the pattern syntax). Use for generated or
+	// vendored files where review comments should not trigger code
+	// changes.
+	IgnoredPaths []string
+
 	// KnownBotUsernames lists usernames of other bots. Their
 	// top-level comments are kept, but replies to our bot's
 	// comments are removed to prevent bot-to-bot loops.
@@ -107,10 +132,20 @@ func Filter(comments []models.PRComment, cfg Config) []models.PRComment {
 			continue
 		}
 
+		if c.FilePath != "" && matchesAnyPath(c.FilePath, cfg.IgnoredPaths) {
+			continue
+		}
+
 		if isSlashCommandOnly(c.Body) {
 			continue
 		}
 
+		// An approval ("LGTM, thanks!") asks for no change, but
+		// "approved, just fix the typo" does.
+		if c.Approval && isApprovalOnly(c.Body) {
+			continue
+		}
+
 		if ignoreRe.MatchString(c.Body) {
 			continue
 		}
@@ -137,20 +172,28 @@ func Filter(comments []models.PRComment, cfg Config) []models.PRComment {
 // after bot-loop prevention AND excluding comments the bot has
 // already replied to.
 func HasNewActionable(comments []models.PRComment, cfg Config) bool {
+	return len(NewActionable(comments, cfg)) > 0
+}
+
+// NewActionable returns the comments that remain actionable after
+// bot-loop prevention, excluding the bot's own comments and comments
+// the bot has already replied to.
+func NewActionable(comments []models.PRComment, cfg Config) []models.PRComment {
 	filtered := Filter(comments, cfg)
 	normBot := normalizeUsername(cfg.BotUsername)
 	botRepliedTo := BotRepliedTo(filtered, normBot)
 
+	var result []models.PRComment
 	for _, c := range filtered {
 		if normalizeUsername(c.Author.Username) == normBot {
 			continue
 		}
 		if !botRepliedTo[c.ID] {
-			return true
+			result = append(result, c)
 		}
 	}
 
-	return false
+	return result
 }
 
 // BotRepliedTo builds the set of comment IDs that the bot has
@@ -250,6 +293,35 @@ func isSlashCommandOnly(body string) bool {
 	return hasCommand
 }
 
+// approvalWords are the words an approving review body may consist of
+// and still ask for no change.
+var approvalWords = map[string]bool{
+	"lgtm": true, "looks": true, "look": true, "good": true, "great": true,
+	"fine": true, "to": true, "me": true, "thanks": true, "thank": true,
+	"you": true, "ty": true, "nice": true, "work": true, "job": true,
+	"ship": true, "it": true, "approved": true, "approve": true,
+	"awesome": true, "perfect": true, "well": true, "done": true,
+}
+
+// isApprovalOnly reports whether an approving review body only
+// approves: apart from slash-command lines, it consists of
+// [approvalWords], punctuation, and emoji. An empty body qualifies.
+func isApprovalOnly(body string) bool {
+	notWord := func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "/") {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(strings.ToLower(line), notWord) {
+			if !approvalWords[word] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // botIgnoreDirectiveRe builds a regexp matching @<botUsername> ignore
 // (case-insensitive), where the [bot] suffix is optional. The word
 // boundary after "ignore" prevents false positives like "ignoring".
@@ -336,3 +408,30 @@ func parentChainBotCount(commentID int64, normBot string, byID map[int64]models.
 
 	return depth
 }
+
+// matchPath reports whether the repository-relative file path name
+// matches pattern. Patterns use [path.Match] syntax and are matched
+// against the whole path, except that a pattern without a slash is
+// matched against the file's base name (so "*.pb.go" matches at any
+// depth) and a pattern ending in "/**" matches everything under that
+// directory (so "vendor/**" matches "vendor/a/b.go").
+func matchPath(pattern, name string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return strings.HasPrefix(name, dir+"/")
+	}
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(name)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// matchesAnyPath reports whether name matches any of the patterns.
+func matchesAnyPath(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if matchPath(p, name) {
+			return true
+		}
+	}
+	return false
+}
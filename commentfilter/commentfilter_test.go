@@ -32,6 +32,55 @@ func TestFilter_RemovesIgnoredUsers(t *testing.T) {
 	}
 }
 
+func TestFilter_RemovesCommentsOnIgnoredPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		filePath string
+		ignored  bool
+	}{
+		{"exact path", "api/openapi.yaml", "api/openapi.yaml", true},
+		{"glob in directory", "api/*.gen.go", "api/types.gen.go", true},
+		{"glob does not cross directories", "api/*.go", "api/v1/types.go", false},
+		{"base name pattern at any depth", "*.pb.go", "internal/proto/foo.pb.go", true},
+		{"directory subtree", "vendor/**", "vendor/github.com/x/y.go", true},
+		{"directory subtree excludes sibling prefix", "vendor/**", "vendored/y.go", false},
+		{"no match", "*.pb.go", "main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := []models.PRComment{
+				{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Fix this", FilePath: tt.filePath, IsReviewComment: true},
+			}
+			cfg := commentfilter.Config{
+				BotUsername:  "ai-bot",
+				IgnoredPaths: []string{tt.pattern},
+			}
+
+			result := commentfilter.Filter(comments, cfg)
+
+			if got := len(result) == 0; got != tt.ignored {
+				t.Errorf("pattern %q, path %q: ignored = %v, want %v", tt.pattern, tt.filePath, got, tt.ignored)
+			}
+		})
+	}
+}
+
+func TestFilter_IgnoredPathsKeepGeneralComments(t *testing.T) {
+	comments := []models.PRComment{
+		{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Overall looks good"},
+	}
+	cfg := commentfilter.Config{
+		BotUsername:  "ai-bot",
+		IgnoredPaths: []string{"*"},
+	}
+
+	if result := commentfilter.Filter(comments, cfg); len(result) != 1 {
+		t.Errorf("got %d comments, want 1 (general comments have no path)", len(result))
+	}
+}
+
 func TestFilter_RemovesKnownBotReplyingToOurBot(t *testing.T) {
 	comments := []models.PRComment{
 		{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Fix this"},
@@ -302,6 +351,36 @@ func TestFilter_RemovesSlashCommandOnlyComments(t *testing.T) {
 	assertIDs(t, ids, []int64{2})
 }
 
+func TestFilter_RemovesApprovals(t *testing.T) {
+	comments := []models.PRComment{
+		{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "LGTM, thanks!", Approval: true},
+		{ID: 2, Author: models.Author{Username: "reviewer2"}, Body: "Please rename this"},
+		{ID: 3, Author: models.Author{Username: "reviewer"}, Body: "Looks good to me 🚀\n/lgtm", Approval: true},
+		{ID: 4, Author: models.Author{Username: "reviewer"}, Approval: true},
+	}
+
+	cfg := commentfilter.Config{BotUsername: "ai-bot"}
+
+	result := commentfilter.Filter(comments, cfg)
+
+	ids := extractIDs(result)
+	assertIDs(t, ids, []int64{2})
+}
+
+func TestFilter_KeepsApprovalsAskingForChanges(t *testing.T) {
+	comments := []models.PRComment{
+		{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "LGTM, but please fix the typo in the README", Approval: true},
+		{ID: 2, Author: models.Author{Username: "reviewer"}, Body: "Approved.\nNit: rename foo to bar", Approval: true},
+	}
+
+	cfg := commentfilter.Config{BotUsername: "ai-bot"}
+
+	result := commentfilter.Filter(comments, cfg)
+
+	ids := extractIDs(result)
+	assertIDs(t, ids, []int64{1, 2})
+}
+
 func TestFilter_KeepsSlashCommandWithSurroundingText(t *testing.T) {
 	comments := []models.PRComment{
 		{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Looks good!\n/lgtm"},
@@ -535,6 +614,18 @@ func TestHasNewActionable_FalseWhenOnlySlashCommands(t *testing.T) {
 	}
 }
 
+func TestHasNewActionable_FalseWhenOnlyApprovals(t *testing.T) {
+	comments := []models.PRComment{
+		{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Looks great", Approval: true},
+	}
+
+	cfg := commentfilter.Config{BotUsername: "ai-bot"}
+
+	if commentfilter.HasNewActionable(comments, cfg) {
+		t.Error("expected false: only approvals")
+	}
+}
+
 // --- HasNewActionable ---
 
 func TestHasNewActionable_TrueWhenNewComments(t *testing.T) {
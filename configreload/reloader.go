@@ -0,0 +1,236 @@
+// Package configreload reloads the bot's configuration at runtime.
+//
+// A [Reloader] watches the configuration file for changes and listens
+// for SIGHUP. On either trigger it re-reads the file through a
+// caller-supplied [LoadFunc] and, if the result is valid, hands it to
+// an [ApplyFunc] that pushes the new settings into long-lived
+// components (project resolver, scanners). A configuration that fails
+// to load or validate is logged and discarded; the running
+// configuration stays in effect.
+//
+// The watcher observes the file's parent directory rather than the
+// file itself so that atomic replacements (editors writing a temp
+// file and renaming it, Kubernetes ConfigMap symlink swaps) are
+// detected. Bursts of events are coalesced by a short debounce.
+//
+// Which settings take effect without a restart is decided by the
+// ApplyFunc, not by this package.
+package configreload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// defaultDebounce is how long the watcher waits after the last file
+// event before reloading, when [Config.Debounce] is not set.
+const defaultDebounce = 500 * time.Millisecond
+
+// LoadFunc reads and validates the configuration at path. Satisfied
+// by [models.LoadConfig].
+type LoadFunc func(path string) (*models.Config, error)
+
+// ApplyFunc installs a newly loaded configuration. It is called from
+// the reloader's goroutine, one call at a time.
+type ApplyFunc func(cfg *models.Config)
+
+// Config holds construction parameters for [Reloader].
+type Config struct {
+	// Path is the configuration file to watch. Empty disables file
+	// watching; SIGHUP still triggers a reload.
+	Path string
+
+	// Debounce coalesces bursts of file events. Defaults to 500ms.
+	Debounce time.Duration
+
+	// Signals lists the signals that trigger a reload. Defaults to
+	// SIGHUP. Exposed for testing.
+	Signals []os.Signal
+}
+
+// Reloader triggers configuration reloads from file changes and
+// signals.
+type Reloader struct {
+	cfg    Config
+	load   LoadFunc
+	apply  ApplyFunc
+	logger *zap.Logger
+
+	reloadMu sync.Mutex
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReloader creates a Reloader. Returns an error if any required
+// parameter is invalid.
+func NewReloader(cfg Config, load LoadFunc, apply ApplyFunc, logger *zap.Logger) (*Reloader, error) {
+	if load == nil {
+		return nil, errors.New("load func must not be nil")
+	}
+	if apply == nil {
+		return nil, errors.New("apply func must not be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if cfg.Debounce < 0 {
+		return nil, errors.New("debounce must not be negative")
+	}
+	if cfg.Debounce == 0 {
+		cfg.Debounce = defaultDebounce
+	}
+	if len(cfg.Signals) == 0 {
+		cfg.Signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	return &Reloader{
+		cfg:    cfg,
+		load:   load,
+		apply:  apply,
+		logger: logger,
+	}, nil
+}
+
+// Reload loads the configuration and applies it. Returns the load
+// error, if any, in which case the running configuration is left
+// unchanged.
+func (r *Reloader) Reload() error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	cfg, err := r.load(r.cfg.Path)
+	if err != nil {
+		r.logger.Error("Configuration reload failed, keeping current configuration",
+			zap.String("path", r.cfg.Path),
+			zap.Error(err))
+		return fmt.Errorf("loading configuration: %w", err)
+	}
+
+	r.apply(cfg)
+	r.logger.Info("Configuration reloaded", zap.String("path", r.cfg.Path))
+	return nil
+}
+
+// Start begins watching for reload triggers in a background
+// goroutine. Returns an error if already running or if the file
+// watcher cannot be created.
+func (r *Reloader) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return errors.New("reloader already running")
+	}
+
+	var watcher *fsnotify.Watcher
+	if r.cfg.Path != "" {
+		w, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("creating file watcher: %w", err)
+		}
+		dir := filepath.Dir(r.cfg.Path)
+		if err := w.Add(dir); err != nil {
+			_ = w.Close()
+			return fmt.Errorf("watching %s: %w", dir, err)
+		}
+		watcher = w
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, r.cfg.Signals...)
+
+	done := make(chan struct{})
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = done
+	go r.run(ctx, done, watcher, sigCh)
+	return nil
+}
+
+// Stop cancels watching and blocks until the goroutine exits. Safe
+// to call multiple times or without a prior Start.
+func (r *Reloader) Stop() {
+	r.mu.Lock()
+	cancel := r.cancel
+	done := r.done
+	r.cancel = nil
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (r *Reloader) run(ctx context.Context, done chan struct{}, watcher *fsnotify.Watcher, sigCh chan os.Signal) {
+	defer close(done)
+	defer signal.Stop(sigCh)
+
+	var events <-chan fsnotify.Event
+	var watchErrs <-chan error
+	if watcher != nil {
+		defer func() { _ = watcher.Close() }()
+		events = watcher.Events
+		watchErrs = watcher.Errors
+	}
+
+	debounce := time.NewTimer(r.cfg.Debounce)
+	if !debounce.Stop() {
+		<-debounce.C
+	}
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigCh:
+			r.logger.Info("Received signal, reloading configuration",
+				zap.String("signal", sig.String()))
+			_ = r.Reload()
+		case ev, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if r.relevant(ev) {
+				debounce.Reset(r.cfg.Debounce)
+			}
+		case err, ok := <-watchErrs:
+			if !ok {
+				watchErrs = nil
+				continue
+			}
+			r.logger.Warn("Configuration file watcher error", zap.Error(err))
+		case <-debounce.C:
+			r.logger.Info("Configuration file changed, reloading",
+				zap.String("path", r.cfg.Path))
+			_ = r.Reload()
+		}
+	}
+}
+
+// relevant reports whether a directory event may have changed the
+// watched file. Besides events on the file itself, Kubernetes
+// ConfigMap mounts update by swapping a "..data" symlink, so events
+// on that name also count.
+func (r *Reloader) relevant(ev fsnotify.Event) bool {
+	if !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Rename) {
+		return false
+	}
+	name := filepath.Base(ev.Name)
+	return name == filepath.Base(r.cfg.Path) || name == "..data"
+}
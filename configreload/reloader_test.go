@@ -0,0 +1,152 @@
+package configreload_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/configreload"
+	"jira-ai-issue-solver/models"
+)
+
+func TestNewReloader_Validation(t *testing.T) {
+	load := func(string) (*models.Config, error) { return &models.Config{}, nil }
+	apply := func(*models.Config) {}
+
+	tests := []struct {
+		name   string
+		cfg    configreload.Config
+		load   configreload.LoadFunc
+		apply  configreload.ApplyFunc
+		logger *zap.Logger
+	}{
+		{"nil load", configreload.Config{}, nil, apply, zap.NewNop()},
+		{"nil apply", configreload.Config{}, load, nil, zap.NewNop()},
+		{"nil logger", configreload.Config{}, load, apply, nil},
+		{"negative debounce", configreload.Config{Debounce: -time.Second}, load, apply, zap.NewNop()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := configreload.NewReloader(tt.cfg, tt.load, tt.apply, tt.logger); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestReload_LoadErrorKeepsCurrentConfig(t *testing.T) {
+	applied := 0
+	r, err := configreload.NewReloader(configreload.Config{Path: "config.yaml"},
+		func(string) (*models.Config, error) { return nil, errors.New("invalid") },
+		func(*models.Config) { applied++ },
+		zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected error")
+	}
+	if applied != 0 {
+		t.Errorf("apply called %d times, want 0", applied)
+	}
+}
+
+func TestReloader_FileChangeTriggersReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("v1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	applied := make(chan string, 10)
+	r, err := configreload.NewReloader(
+		configreload.Config{Path: path, Debounce: 10 * time.Millisecond},
+		func(p string) (*models.Config, error) {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			return &models.Config{AIProvider: string(data)}, nil
+		},
+		func(cfg *models.Config) { applied <- cfg.AIProvider },
+		zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	if err := os.WriteFile(path, []byte("v2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-applied:
+		if got != "v2" {
+			t.Errorf("applied config = %q, want %q", got, "v2")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("file change did not trigger reload")
+	}
+}
+
+func TestReloader_SignalTriggersReload(t *testing.T) {
+	applied := make(chan struct{}, 1)
+	r, err := configreload.NewReloader(
+		configreload.Config{Signals: []os.Signal{syscall.SIGUSR1}},
+		func(string) (*models.Config, error) { return &models.Config{}, nil },
+		func(*models.Config) { applied <- struct{}{} },
+		zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-applied:
+	case <-time.After(2 * time.Second):
+		t.Fatal("signal did not trigger reload")
+	}
+}
+
+func TestReloader_StartTwice(t *testing.T) {
+	r, err := configreload.NewReloader(configreload.Config{},
+		func(string) (*models.Config, error) { return &models.Config{}, nil },
+		func(*models.Config) {},
+		zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := r.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	if err := r.Start(ctx); err == nil {
+		t.Error("expected error on second Start")
+	}
+}
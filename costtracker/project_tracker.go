@@ -0,0 +1,212 @@
+package costtracker
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// monthFormat is the Go reference-time layout used for monthly cost
+// periods.
+const monthFormat = "2006-01"
+
+// projectCostRecord is the on-disk representation of a project's
+// spend in the current day and month.
+type projectCostRecord struct {
+	Day        string  `json:"day"`
+	DailyUSD   float64 `json:"daily_usd"`
+	Month      string  `json:"month"`
+	MonthlyUSD float64 `json:"monthly_usd"`
+}
+
+// ProjectSpend is a Jira project's AI spend in the current UTC day and
+// month, and how many of its tickets were deferred for lack of budget
+// since the bot started.
+type ProjectSpend struct {
+	Project    string
+	DailyUSD   float64
+	MonthlyUSD float64
+	Deferred   int64
+}
+
+// ProjectTracker persists AI cost totals per Jira project key for the
+// current UTC day and month, so that per-project budgets can be
+// enforced. Totals reset when the day or month changes. It is safe
+// for concurrent use.
+type ProjectTracker struct {
+	mu        sync.Mutex
+	path      string
+	records   map[string]*projectCostRecord
+	deferred  map[string]int64
+	clockFunc func() time.Time
+	logger    *zap.Logger
+}
+
+// NewProjectTracker creates a ProjectTracker that persists totals to
+// the given path. Missing or corrupt files start at zero.
+func NewProjectTracker(path string, logger *zap.Logger) *ProjectTracker {
+	return NewProjectTrackerWithClock(path, time.Now, logger)
+}
+
+// NewProjectTrackerWithClock is like [NewProjectTracker] but accepts a
+// custom clock function for testing.
+func NewProjectTrackerWithClock(path string, clock func() time.Time, logger *zap.Logger) *ProjectTracker {
+	t := &ProjectTracker{
+		path:      path,
+		records:   make(map[string]*projectCostRecord),
+		deferred:  make(map[string]int64),
+		clockFunc: clock,
+		logger:    logger,
+	}
+	t.loadFromDisk()
+	return t
+}
+
+// Record adds amount to the project's daily and monthly totals and
+// persists them. Negative amounts are ignored.
+func (t *ProjectTracker) Record(project string, amount float64) {
+	if amount <= 0 || project == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec := t.current(project)
+	rec.DailyUSD += amount
+	rec.MonthlyUSD += amount
+	t.writeToDisk()
+	t.logger.Debug("Project cost recorded",
+		zap.String("project", project),
+		zap.Float64("amount", amount),
+		zap.Float64("daily_total", rec.DailyUSD),
+		zap.Float64("monthly_total", rec.MonthlyUSD))
+}
+
+// Spent returns the combined daily and monthly totals of the given
+// projects.
+func (t *ProjectTracker) Spent(projects ...string) (daily, monthly float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, project := range projects {
+		if t.records[project] == nil {
+			continue
+		}
+		rec := t.current(project)
+		daily += rec.DailyUSD
+		monthly += rec.MonthlyUSD
+	}
+	return daily, monthly
+}
+
+// RecordDeferral counts a ticket of the project deferred because its
+// budget was exhausted. Deferrals are reported by [ProjectTracker.Stats]
+// and not persisted.
+func (t *ProjectTracker) RecordDeferral(project string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.deferred[project]++
+}
+
+// Stats returns the spend of each project that has spent or been
+// deferred, sorted by project key.
+func (t *ProjectTracker) Stats() []ProjectSpend {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	seen := make(map[string]bool)
+	for project := range t.records {
+		seen[project] = true
+	}
+	for project := range t.deferred {
+		seen[project] = true
+	}
+	stats := make([]ProjectSpend, 0, len(seen))
+	for project := range seen {
+		rec := t.current(project)
+		stats = append(stats, ProjectSpend{
+			Project:    project,
+			DailyUSD:   rec.DailyUSD,
+			MonthlyUSD: rec.MonthlyUSD,
+			Deferred:   t.deferred[project],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Project < stats[j].Project })
+	return stats
+}
+
+// current returns the project's record, resetting the totals of a
+// day or month that has ended. Must be called with t.mu held.
+func (t *ProjectTracker) current(project string) *projectCostRecord {
+	now := t.clockFunc().UTC()
+	rec := t.records[project]
+	if rec == nil {
+		rec = &projectCostRecord{}
+		t.records[project] = rec
+	}
+	if day := now.Format(dateFormat); rec.Day != day {
+		rec.Day = day
+		rec.DailyUSD = 0
+	}
+	if month := now.Format(monthFormat); rec.Month != month {
+		rec.Month = month
+		rec.MonthlyUSD = 0
+	}
+	return rec
+}
+
+// loadFromDisk reads the project records from the JSON file.
+func (t *ProjectTracker) loadFromDisk() {
+	data, err := os.ReadFile(t.path) // #nosec G304 -- path is caller-controlled workspace file
+	if err != nil {
+		if !os.IsNotExist(err) {
+			t.logger.Warn("failed to read project cost file, starting fresh",
+				zap.String("path", t.path),
+				zap.Error(err))
+		}
+		return
+	}
+	var records map[string]*projectCostRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.logger.Warn("corrupt project cost file, starting fresh",
+			zap.String("path", t.path),
+			zap.Error(err))
+		return
+	}
+	for project, rec := range records {
+		if rec == nil {
+			continue
+		}
+		if !validTotal(rec.DailyUSD) {
+			rec.DailyUSD = 0
+		}
+		if !validTotal(rec.MonthlyUSD) {
+			rec.MonthlyUSD = 0
+		}
+		t.records[project] = rec
+	}
+}
+
+// writeToDisk persists the project records to the JSON file. Write
+// failures are logged but do not lose in-memory state. Must be called
+// with t.mu held.
+func (t *ProjectTracker) writeToDisk() {
+	data, err := json.Marshal(t.records)
+	if err != nil {
+		t.logger.Warn("failed to marshal project cost records",
+			zap.Error(err))
+		return
+	}
+	if err := os.WriteFile(t.path, data, 0o600); err != nil {
+		t.logger.Warn("failed to write project cost file",
+			zap.String("path", t.path),
+			zap.Error(err))
+	}
+}
+
+// validTotal reports whether a loaded total is a usable amount.
+func validTotal(v float64) bool {
+	return v >= 0 && !math.IsInf(v, 0) && !math.IsNaN(v)
+}
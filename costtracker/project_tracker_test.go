@@ -0,0 +1,112 @@
+package costtracker_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/costtracker"
+)
+
+func TestProjectTracker_SpentSumsProjects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project-cost.json")
+	tracker := costtracker.NewProjectTrackerWithClock(path,
+		fixedClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)), zap.NewNop())
+
+	tracker.Record("PROJ", 10)
+	tracker.Record("PROJ", 5)
+	tracker.Record("OPS", 2.5)
+	tracker.Record("OTHER", 100)
+	tracker.Record("PROJ", -3)
+
+	daily, monthly := tracker.Spent("PROJ", "OPS")
+	if daily != 17.5 || monthly != 17.5 {
+		t.Errorf("Spent(PROJ, OPS) = %v, %v; want 17.5, 17.5", daily, monthly)
+	}
+}
+
+func TestProjectTracker_ResetsDayAndMonth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project-cost.json")
+	now := time.Date(2026, 3, 30, 23, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	tracker := costtracker.NewProjectTrackerWithClock(path, clock, zap.NewNop())
+
+	tracker.Record("PROJ", 10)
+
+	now = time.Date(2026, 3, 31, 1, 0, 0, 0, time.UTC)
+	tracker.Record("PROJ", 4)
+	if daily, monthly := tracker.Spent("PROJ"); daily != 4 || monthly != 14 {
+		t.Errorf("next day: Spent = %v, %v; want 4, 14", daily, monthly)
+	}
+
+	now = time.Date(2026, 4, 1, 1, 0, 0, 0, time.UTC)
+	if daily, monthly := tracker.Spent("PROJ"); daily != 0 || monthly != 0 {
+		t.Errorf("next month: Spent = %v, %v; want 0, 0", daily, monthly)
+	}
+}
+
+func TestProjectTracker_PersistsTotals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project-cost.json")
+	clock := fixedClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+
+	first := costtracker.NewProjectTrackerWithClock(path, clock, zap.NewNop())
+	first.Record("PROJ", 12.5)
+	first.RecordDeferral("PROJ")
+
+	second := costtracker.NewProjectTrackerWithClock(path, clock, zap.NewNop())
+	if daily, monthly := second.Spent("PROJ"); daily != 12.5 || monthly != 12.5 {
+		t.Errorf("after reload: Spent = %v, %v; want 12.5, 12.5", daily, monthly)
+	}
+	stats := second.Stats()
+	if len(stats) != 1 || stats[0].Deferred != 0 {
+		t.Errorf("after reload: Stats = %+v, want PROJ without deferrals", stats)
+	}
+}
+
+func TestProjectTracker_Stats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project-cost.json")
+	tracker := costtracker.NewProjectTrackerWithClock(path,
+		fixedClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)), zap.NewNop())
+
+	tracker.Record("PROJ", 3)
+	tracker.RecordDeferral("OPS")
+	tracker.RecordDeferral("OPS")
+
+	stats := tracker.Stats()
+	want := []costtracker.ProjectSpend{
+		{Project: "OPS", Deferred: 2},
+		{Project: "PROJ", DailyUSD: 3, MonthlyUSD: 3},
+	}
+	if len(stats) != len(want) || stats[0] != want[0] || stats[1] != want[1] {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestProjectTracker_InvalidTotalsReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project-cost.json")
+	data := `{"PROJ": {"day": "2026-03-10", "daily_usd": 1e+999, "month": "2026-03", "monthly_usd": -5}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := costtracker.NewProjectTrackerWithClock(path,
+		fixedClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)), zap.NewNop())
+	if daily, monthly := tracker.Spent("PROJ"); daily != 0 || monthly != 0 {
+		t.Errorf("Spent = %v, %v; want 0, 0", daily, monthly)
+	}
+}
+
+func TestProjectTracker_CorruptFileStartsFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project-cost.json")
+	if err := os.WriteFile(path, []byte("not json!!!"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := costtracker.NewProjectTracker(path, zap.NewNop())
+	if daily, monthly := tracker.Spent("PROJ"); daily != 0 || monthly != 0 {
+		t.Errorf("Spent = %v, %v; want 0, 0", daily, monthly)
+	}
+}
@@ -31,7 +31,7 @@ echo ""
 echo "Available debugging options:"
 echo "1. VS Code: Press F5 or use the debug panel"
 echo "2. Command line: make debug"
-echo "3. Direct Delve: dlv debug main.go -- -config config.yaml"
+echo "3. Direct Delve: dlv debug . -- -config config.yaml"
 echo "4. Debug tests: make debug-tests"
 echo ""
 echo "Common Delve commands:"
@@ -51,5 +51,5 @@ read -p "Start debugging now? (y/n): " -n 1 -r
 echo
 if [[ $REPLY =~ ^[Yy]$ ]]; then
     echo "Starting debug session..."
-    $HOME/go/bin/dlv debug main.go -- -config config.yaml
+    $HOME/go/bin/dlv debug . -- -config config.yaml
 fi 
\ No newline at end of file
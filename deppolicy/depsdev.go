@@ -0,0 +1,124 @@
+package deppolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultDepsDevURL is the public deps.dev API.
+const DefaultDepsDevURL = "https://api.deps.dev"
+
+// DepsDevClient is a [LicenseSource] backed by the deps.dev API,
+// which reports the licenses of Go, npm, and PyPI packages.
+type DepsDevClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewDepsDevClient creates a DepsDevClient for the API at baseURL
+// (normally [DefaultDepsDevURL]). A nil client uses
+// [http.DefaultClient].
+func NewDepsDevClient(baseURL string, client *http.Client) (*DepsDevClient, error) {
+	if baseURL == "" {
+		return nil, errors.New("deps.dev URL must not be empty")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &DepsDevClient{baseURL: strings.TrimRight(baseURL, "/"), client: client}, nil
+}
+
+// Licenses returns the licenses of dep's version. When the manifest
+// gives a range rather than an exact version (e.g., "^1.2.0"), the
+// package's default version, usually its latest release, is used.
+func (c *DepsDevClient) Licenses(ctx context.Context, dep Dependency) ([]string, error) {
+	pkgURL := fmt.Sprintf("%s/v3/systems/%s/packages/%s",
+		c.baseURL, dep.Ecosystem, url.PathEscape(dep.Name))
+
+	version := exactVersion(dep)
+	if version == "" {
+		var pkg struct {
+			Versions []struct {
+				VersionKey struct {
+					Version string `json:"version"`
+				} `json:"versionKey"`
+				IsDefault bool `json:"isDefault"`
+			} `json:"versions"`
+		}
+		if err := c.get(ctx, pkgURL, &pkg); err != nil {
+			return nil, err
+		}
+		for _, v := range pkg.Versions {
+			if v.IsDefault {
+				version = v.VersionKey.Version
+			}
+		}
+		if version == "" {
+			return nil, errors.New("deps.dev reports no default version")
+		}
+	}
+
+	var v struct {
+		Licenses []string `json:"licenses"`
+	}
+	if err := c.get(ctx, pkgURL+"/versions/"+url.PathEscape(version), &v); err != nil {
+		return nil, err
+	}
+	return v.Licenses, nil
+}
+
+// get fetches endpoint and decodes the JSON response into out.
+func (c *DepsDevClient) get(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting deps.dev: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading deps.dev response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("deps.dev returned status %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decoding deps.dev response: %w", err)
+	}
+	return nil
+}
+
+// semver matches an exact semantic version, with optional pre-release
+// and build suffixes.
+var semver = regexp.MustCompile(`^\d+\.\d+\.\d+([-+][0-9A-Za-z.+-]+)?$`)
+
+// exactVersion returns the exact version dep pins, or "" when its
+// manifest gives a range or nothing.
+func exactVersion(dep Dependency) string {
+	v := strings.TrimSpace(dep.Version)
+	switch dep.Ecosystem {
+	case EcosystemGo:
+		return v
+	case EcosystemNPM:
+		v = strings.TrimPrefix(strings.TrimPrefix(v, "="), "v")
+		if semver.MatchString(v) {
+			return v
+		}
+	case EcosystemPyPI:
+		if pinned, ok := strings.CutPrefix(v, "=="); ok && !strings.ContainsAny(pinned, ",*") {
+			return strings.TrimSpace(pinned)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package deppolicy_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"jira-ai-issue-solver/deppolicy"
+)
+
+func TestDepsDevClient_Licenses(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		switch r.URL.EscapedPath() {
+		case "/v3/systems/go/packages/github.com%2Facme%2Fkit/versions/v1.2.0":
+			_, _ = w.Write([]byte(`{"licenses": ["Apache-2.0"]}`))
+		case "/v3/systems/npm/packages/left-pad":
+			_, _ = w.Write([]byte(`{"versions": [
+				{"versionKey": {"version": "1.2.0"}},
+				{"versionKey": {"version": "1.3.0"}, "isDefault": true}]}`))
+		case "/v3/systems/npm/packages/left-pad/versions/1.3.0":
+			_, _ = w.Write([]byte(`{"licenses": ["WTFPL"]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := deppolicy.NewDepsDevClient(srv.URL+"/", srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.Licenses(context.Background(), deppolicy.Dependency{
+		Ecosystem: deppolicy.EcosystemGo, Name: "github.com/acme/kit", Version: "v1.2.0",
+	})
+	if err != nil || !slices.Equal(got, []string{"Apache-2.0"}) {
+		t.Errorf("go licenses = %v, %v", got, err)
+	}
+
+	// A version range resolves to the package's default version.
+	got, err = client.Licenses(context.Background(), deppolicy.Dependency{
+		Ecosystem: deppolicy.EcosystemNPM, Name: "left-pad", Version: "^1.2.0",
+	})
+	if err != nil || !slices.Equal(got, []string{"WTFPL"}) {
+		t.Errorf("npm licenses = %v, %v (requests: %v)", got, err, paths)
+	}
+
+	if _, err := client.Licenses(context.Background(), deppolicy.Dependency{
+		Ecosystem: deppolicy.EcosystemPyPI, Name: "missing", Version: "==1.0",
+	}); err == nil {
+		t.Error("expected error for unknown package")
+	}
+}
+
+func TestNewDepsDevClient_RequiresURL(t *testing.T) {
+	if _, err := deppolicy.NewDepsDevClient("", nil); err == nil {
+		t.Error("expected error for empty URL")
+	}
+}
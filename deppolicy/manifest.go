@@ -0,0 +1,202 @@
+// Package deppolicy finds the dependencies a change adds to a
+// repository's manifests and checks them against a project's
+// [models.DependencyPolicy].
+//
+// [Added] compares a manifest (go.mod, package.json, or
+// requirements.txt) before and after the change. [Check] rejects added
+// dependencies whose name the policy denies or whose license it does
+// not allow, looking licenses up through a [LicenseSource] such as
+// [DepsDevClient].
+package deppolicy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Ecosystems, named as in the deps.dev API.
+const (
+	EcosystemGo   = "go"
+	EcosystemNPM  = "npm"
+	EcosystemPyPI = "pypi"
+)
+
+// Dependency is a package required by a manifest.
+type Dependency struct {
+	// Ecosystem is the package ecosystem (e.g., [EcosystemGo]).
+	Ecosystem string
+
+	// Name is the package name: a module path for Go, a package name
+	// for npm, and a normalized project name for PyPI.
+	Name string
+
+	// Version is the version or version constraint as written in the
+	// manifest. It may be empty.
+	Version string
+
+	// Manifest is the manifest's path relative to the repository root.
+	Manifest string
+}
+
+// manifestEcosystems maps manifest file names to their ecosystem.
+var manifestEcosystems = map[string]string{
+	"go.mod":           EcosystemGo,
+	"package.json":     EcosystemNPM,
+	"requirements.txt": EcosystemPyPI,
+}
+
+// IsManifest reports whether the file at the slash-separated path is a
+// manifest that [Added] can parse.
+func IsManifest(file string) bool {
+	_, ok := manifestEcosystems[path.Base(file)]
+	return ok
+}
+
+// Added returns the dependencies required by the manifest at file
+// (relative to the repository root) after the change but not before
+// it, sorted by name. base is nil when the manifest is new. Version
+// changes of existing dependencies are not reported.
+func Added(file string, base, head []byte) ([]Dependency, error) {
+	ecosystem, ok := manifestEcosystems[path.Base(file)]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a supported manifest", file)
+	}
+	before, err := parse(ecosystem, base)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s on base branch: %w", file, err)
+	}
+	after, err := parse(ecosystem, head)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+
+	var added []Dependency
+	for name, version := range after {
+		if _, ok := before[name]; !ok {
+			added = append(added, Dependency{Ecosystem: ecosystem, Name: name, Version: version, Manifest: file})
+		}
+	}
+	slices.SortFunc(added, func(a, b Dependency) int { return strings.Compare(a.Name, b.Name) })
+	return added, nil
+}
+
+// parse returns the dependencies in a manifest, mapping names to
+// versions. Empty data yields no dependencies.
+func parse(ecosystem string, data []byte) (map[string]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	switch ecosystem {
+	case EcosystemGo:
+		return parseGoMod(data), nil
+	case EcosystemNPM:
+		return parsePackageJSON(data)
+	default:
+		return parseRequirements(data), nil
+	}
+}
+
+// parseGoMod returns the modules in a go.mod file's require
+// directives, both single-line and block form.
+func parseGoMod(data []byte) map[string]string {
+	deps := make(map[string]string)
+	inBlock := false
+	sc := bufio.NewScanner(strings.NewReader(string(data)))
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case !inBlock && fields[0] == "require":
+			if len(fields) > 1 && fields[1] == "(" {
+				inBlock = true
+				continue
+			}
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+		if len(fields) >= 2 {
+			deps[strings.Trim(fields[0], `"`)] = fields[1]
+		}
+	}
+	return deps
+}
+
+// parsePackageJSON returns the packages in a package.json file's
+// dependency sections.
+func parsePackageJSON(data []byte) (map[string]string, error) {
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	deps := make(map[string]string)
+	for _, section := range []map[string]string{
+		pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies, pkg.OptionalDependencies,
+	} {
+		for name, version := range section {
+			deps[name] = version
+		}
+	}
+	return deps, nil
+}
+
+// parseRequirements returns the projects in a pip requirements file.
+// Options (lines starting with "-"), and bare URLs and paths, are
+// skipped.
+func parseRequirements(data []byte) map[string]string {
+	deps := make(map[string]string)
+	sc := bufio.NewScanner(strings.NewReader(string(data)))
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.ContainsAny(line[:1], "-./") {
+			continue
+		}
+		end := strings.IndexAny(line, "=<>!~;[@ ")
+		if end < 0 {
+			end = len(line)
+		}
+		if strings.Contains(line[:end], ":") {
+			continue
+		}
+		name := normalizePyPIName(line[:end])
+		spec := strings.TrimSpace(line[end:])
+		if strings.HasPrefix(spec, "[") {
+			if i := strings.Index(spec, "]"); i >= 0 {
+				spec = strings.TrimSpace(spec[i+1:])
+			}
+		}
+		if i := strings.Index(spec, ";"); i >= 0 {
+			spec = strings.TrimSpace(spec[:i])
+		}
+		deps[name] = spec
+	}
+	return deps
+}
+
+// normalizePyPIName normalizes a project name as PyPI does (PEP 503),
+// so that "Foo_Bar" and "foo-bar" compare equal.
+func normalizePyPIName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
@@ -0,0 +1,116 @@
+package deppolicy_test
+
+import (
+	"slices"
+	"testing"
+
+	"jira-ai-issue-solver/deppolicy"
+)
+
+func names(deps []deppolicy.Dependency) []string {
+	var out []string
+	for _, d := range deps {
+		out = append(out, d.Name+"@"+d.Version)
+	}
+	return out
+}
+
+func TestAdded_GoMod(t *testing.T) {
+	base := `module example.com/app
+
+go 1.24
+
+require github.com/spf13/viper v1.19.0
+
+require (
+	go.uber.org/zap v1.27.0
+	golang.org/x/sys v0.33.0 // indirect
+)
+`
+	head := `module example.com/app
+
+go 1.24
+
+require github.com/spf13/viper v1.20.0
+
+require (
+	go.uber.org/zap v1.27.0
+	github.com/acme/left-pad v0.1.0
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+)
+
+replace example.com/old => example.com/new v1.0.0
+`
+	added, err := deppolicy.Added("go.mod", []byte(base), []byte(head))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"github.com/acme/left-pad@v0.1.0", "golang.org/x/text@v0.26.0"}
+	if got := names(added); !slices.Equal(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+	if added[0].Ecosystem != deppolicy.EcosystemGo || added[0].Manifest != "go.mod" {
+		t.Errorf("dependency = %+v", added[0])
+	}
+}
+
+func TestAdded_PackageJSON(t *testing.T) {
+	base := `{"dependencies": {"react": "^18.0.0"}}`
+	head := `{
+  "dependencies": {"react": "^18.2.0", "left-pad": "1.3.0"},
+  "devDependencies": {"@acme/lint": "~2.0.0"}
+}`
+	added, err := deppolicy.Added("web/package.json", []byte(base), []byte(head))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"@acme/lint@~2.0.0", "left-pad@1.3.0"}
+	if got := names(added); !slices.Equal(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+}
+
+func TestAdded_NewManifest(t *testing.T) {
+	head := `# pinned
+Requests==2.32.0
+python_dateutil>=2.8 ; python_version >= "3.8"
+uvicorn[standard]==0.30.1
+-r base.txt
+./local-package
+mylib @ https://example.com/mylib.tar.gz
+`
+	added, err := deppolicy.Added("requirements.txt", nil, []byte(head))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"mylib@@ https://example.com/mylib.tar.gz", "python-dateutil@>=2.8", "requests@==2.32.0", "uvicorn@==0.30.1"}
+	if got := names(added); !slices.Equal(got, want) {
+		t.Errorf("Added = %v, want %v", got, want)
+	}
+}
+
+func TestAdded_InvalidManifest(t *testing.T) {
+	if _, err := deppolicy.Added("package.json", nil, []byte("{")); err == nil {
+		t.Error("expected error for malformed package.json")
+	}
+	if _, err := deppolicy.Added("Cargo.toml", nil, nil); err == nil {
+		t.Error("expected error for unsupported manifest")
+	}
+}
+
+func TestIsManifest(t *testing.T) {
+	for file, want := range map[string]bool{
+		"go.mod":                true,
+		"tools/go.mod":          true,
+		"web/package.json":      true,
+		"requirements.txt":      true,
+		"go.sum":                false,
+		"web/package-lock.json": false,
+		"docs/requirements.md":  false,
+	} {
+		if got := deppolicy.IsManifest(file); got != want {
+			t.Errorf("IsManifest(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package deppolicy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// LicenseSource looks up the licenses of a dependency as SPDX
+// expressions (e.g., "MIT" or "Apache-2.0 OR MIT").
+type LicenseSource interface {
+	Licenses(ctx context.Context, dep Dependency) ([]string, error)
+}
+
+// Violation is an added dependency that the policy rejects.
+type Violation struct {
+	Dependency Dependency
+
+	// Reason explains the rejection, e.g. "license GPL-3.0 is denied".
+	Reason string
+}
+
+// String formats the violation for a ticket comment.
+func (v Violation) String() string {
+	dep := v.Dependency
+	name := dep.Name
+	if dep.Version != "" {
+		name += " " + dep.Version
+	}
+	return fmt.Sprintf("`%s` (%s): %s", name, dep.Manifest, v.Reason)
+}
+
+// Check returns the dependencies in deps that policy rejects, in
+// order. Licenses are looked up only when the policy restricts them,
+// and only for dependencies whose name it allows. A dependency whose
+// license cannot be determined (licenses is nil, the lookup fails, or
+// no license is known) is rejected. An error is returned only when ctx
+// is done.
+func Check(ctx context.Context, policy models.DependencyPolicy, deps []Dependency, licenses LicenseSource) ([]Violation, error) {
+	var violations []Violation
+	for _, dep := range deps {
+		if pattern, ok := matchAny(policy.Deny, dep.Name); ok {
+			violations = append(violations, Violation{dep, fmt.Sprintf("denied by pattern %q", pattern)})
+			continue
+		}
+		if _, ok := matchAny(policy.Allow, dep.Name); len(policy.Allow) > 0 && !ok {
+			violations = append(violations, Violation{dep, "not in the allow list"})
+			continue
+		}
+		if !policy.ChecksLicenses() {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var exprs []string
+		var err error
+		if licenses != nil {
+			exprs, err = licenses.Licenses(ctx, dep)
+		}
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err != nil:
+			violations = append(violations, Violation{dep, fmt.Sprintf("license could not be determined: %v", err)})
+			continue
+		case len(exprs) == 0:
+			violations = append(violations, Violation{dep, "license could not be determined"})
+			continue
+		}
+		for _, expr := range exprs {
+			if !licenseAccepted(policy, expr) {
+				violations = append(violations, Violation{dep, fmt.Sprintf("license %s is not permitted", expr)})
+				break
+			}
+		}
+	}
+	return violations, nil
+}
+
+// matchAny returns the first pattern matching name.
+func matchAny(patterns []string, name string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// licenseAccepted reports whether the policy accepts an SPDX license
+// expression. An expression is accepted when any of its OR
+// alternatives is; an alternative is accepted when every license it
+// ANDs together is allowed and none is denied. Parentheses are
+// ignored, so nested expressions are evaluated approximately.
+func licenseAccepted(policy models.DependencyPolicy, expr string) bool {
+	expr = strings.NewReplacer("(", " ", ")", " ").Replace(expr)
+	for alt := range strings.SplitSeq(expr, " OR ") {
+		accepted := true
+		for id := range strings.SplitSeq(alt, " AND ") {
+			// "Apache-2.0 WITH LLVM-exception" is judged by its license.
+			id, _, _ = strings.Cut(strings.TrimSpace(id), " WITH ")
+			id = strings.TrimSpace(id)
+			if containsFold(policy.DeniedLicenses, id) ||
+				len(policy.AllowedLicenses) > 0 && !containsFold(policy.AllowedLicenses, id) {
+				accepted = false
+				break
+			}
+		}
+		if accepted {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(e string) bool { return strings.EqualFold(e, s) })
+}
@@ -0,0 +1,142 @@
+package deppolicy_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/deppolicy"
+	"jira-ai-issue-solver/models"
+)
+
+// licenseMap is a LicenseSource returning fixed licenses by name.
+type licenseMap map[string][]string
+
+func (m licenseMap) Licenses(_ context.Context, dep deppolicy.Dependency) ([]string, error) {
+	licenses, ok := m[dep.Name]
+	if !ok {
+		return nil, errors.New("package not found")
+	}
+	return licenses, nil
+}
+
+func dep(name string) deppolicy.Dependency {
+	return deppolicy.Dependency{Ecosystem: deppolicy.EcosystemGo, Name: name, Version: "v1.0.0", Manifest: "go.mod"}
+}
+
+func reasons(violations []deppolicy.Violation) map[string]string {
+	out := make(map[string]string)
+	for _, v := range violations {
+		out[v.Dependency.Name] = v.Reason
+	}
+	return out
+}
+
+func TestCheck_NamePatterns(t *testing.T) {
+	policy := models.DependencyPolicy{
+		Allow: []string{"github.com/acme/*", "go.uber.org/*"},
+		Deny:  []string{"github.com/acme/legacy*"},
+	}
+	deps := []deppolicy.Dependency{
+		dep("github.com/acme/kit"),
+		dep("github.com/acme/legacy-db"),
+		dep("github.com/other/lib"),
+		dep("go.uber.org/zap"),
+	}
+
+	violations, err := deppolicy.Check(context.Background(), policy, deps, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reasons(violations)
+	if len(got) != 2 {
+		t.Fatalf("violations = %v, want 2", violations)
+	}
+	if got["github.com/acme/legacy-db"] != `denied by pattern "github.com/acme/legacy*"` {
+		t.Errorf("legacy-db reason = %q", got["github.com/acme/legacy-db"])
+	}
+	if got["github.com/other/lib"] != "not in the allow list" {
+		t.Errorf("other/lib reason = %q", got["github.com/other/lib"])
+	}
+}
+
+func TestCheck_Licenses(t *testing.T) {
+	policy := models.DependencyPolicy{
+		AllowedLicenses: []string{"MIT", "Apache-2.0", "BSD-3-Clause"},
+	}
+	licenses := licenseMap{
+		"mit":        {"mit"},
+		"dual":       {"GPL-2.0-only OR Apache-2.0"},
+		"combined":   {"(MIT AND GPL-3.0-only)"},
+		"exception":  {"Apache-2.0 WITH LLVM-exception"},
+		"gpl":        {"GPL-3.0-only"},
+		"multiple":   {"MIT", "AGPL-3.0-only"},
+		"unlicensed": {},
+	}
+	deps := []deppolicy.Dependency{
+		dep("mit"), dep("dual"), dep("combined"), dep("exception"),
+		dep("gpl"), dep("multiple"), dep("unlicensed"), dep("unknown"),
+	}
+
+	violations, err := deppolicy.Check(context.Background(), policy, deps, licenses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reasons(violations)
+	want := map[string]string{
+		"combined":   "license (MIT AND GPL-3.0-only) is not permitted",
+		"gpl":        "license GPL-3.0-only is not permitted",
+		"multiple":   "license AGPL-3.0-only is not permitted",
+		"unlicensed": "license could not be determined",
+		"unknown":    "license could not be determined: package not found",
+	}
+	if len(got) != len(want) {
+		t.Errorf("violations = %v, want %v", got, want)
+	}
+	for name, reason := range want {
+		if got[name] != reason {
+			t.Errorf("%s reason = %q, want %q", name, got[name], reason)
+		}
+	}
+}
+
+func TestCheck_DeniedLicenses(t *testing.T) {
+	policy := models.DependencyPolicy{DeniedLicenses: []string{"AGPL-3.0-only"}}
+	licenses := licenseMap{"a": {"agpl-3.0-only"}, "b": {"MIT"}}
+
+	violations, err := deppolicy.Check(context.Background(), policy, []deppolicy.Dependency{dep("a"), dep("b")}, licenses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Dependency.Name != "a" {
+		t.Errorf("violations = %v, want only a", violations)
+	}
+}
+
+func TestCheck_SkipsLicenseLookupWithoutLicenseRules(t *testing.T) {
+	policy := models.DependencyPolicy{Deny: []string{"left-pad"}}
+	violations, err := deppolicy.Check(context.Background(), policy, []deppolicy.Dependency{dep("lib")}, licenseMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("violations = %v, want none", violations)
+	}
+}
+
+func TestCheck_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	policy := models.DependencyPolicy{AllowedLicenses: []string{"MIT"}}
+	if _, err := deppolicy.Check(ctx, policy, []deppolicy.Dependency{dep("lib")}, licenseMap{}); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestViolation_String(t *testing.T) {
+	v := deppolicy.Violation{Dependency: dep("github.com/acme/kit"), Reason: "not in the allow list"}
+	if got := v.String(); !strings.Contains(got, "`github.com/acme/kit v1.0.0` (go.mod): not in the allow list") {
+		t.Errorf("String() = %q", got)
+	}
+}
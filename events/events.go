@@ -0,0 +1,242 @@
+// Package events provides an in-process bus for the bot's lifecycle
+// events (a ticket started, its workspace was prepared, an AI session
+// completed, a PR was created, feedback was applied, a ticket was
+// dead-lettered).
+//
+// The pipeline publishes events without knowing who consumes them;
+// cross-cutting features such as metrics, audit logs, and
+// notifications subscribe to the bus instead of being called from the
+// pipeline's code. Delivery is asynchronous and best-effort: each
+// subscriber has its own queue, so a slow subscriber delays neither
+// the pipeline nor other subscribers, and events it cannot keep up
+// with are dropped and logged.
+package events
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Type identifies the kind of an [Event].
+type Type string
+
+const (
+	// TicketStarted is published when a job starts work on a new
+	// ticket.
+	TicketStarted Type = "ticket.started"
+
+	// WorkspaceReady is published when a job's workspace was cloned,
+	// or found and reused (Reused is set).
+	WorkspaceReady Type = "workspace.ready"
+
+	// AICompleted is published when an AI session ends, whatever
+	// its outcome. Provider, ExitCode, and CostUSD are set.
+	AICompleted Type = "ai.completed"
+
+	// PRCreated is published for each pull request the bot opens.
+	// Repo, PRNumber, and PRURL are set.
+	PRCreated Type = "pr.created"
+
+	// FeedbackApplied is published when changes addressing PR
+	// feedback were pushed. Repo, PRNumber, PRURL, CommitSHA, and
+	// Comments are set.
+	FeedbackApplied Type = "feedback.applied"
+
+	// TicketDeadLettered is published when a ticket exhausted its
+	// retries and was given the dead-letter label, so that it is no
+	// longer picked up until a human removes the label.
+	TicketDeadLettered Type = "ticket.dead_lettered"
+)
+
+// Event is a lifecycle event. Fields that do not apply to the event's
+// type are zero.
+type Event struct {
+	Type Type
+	Time time.Time
+
+	TicketKey string
+	JobID     string
+	JobType   string
+	Attempt   int
+
+	Reused bool
+
+	Provider string
+	ExitCode int
+	CostUSD  float64
+
+	Repo      string // "owner/repo"
+	PRNumber  int
+	PRURL     string
+	CommitSHA string
+	Comments  int // PR comments addressed
+}
+
+// Handler consumes events. Handlers of one subscription are called
+// one event at a time, in publication order.
+type Handler func(Event)
+
+// subscriberQueueSize is how many events a subscriber may fall behind
+// before further events are dropped for it.
+const subscriberQueueSize = 256
+
+// Bus delivers published events to subscribers. The zero value is not
+// usable; create one with [NewBus].
+type Bus struct {
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu     sync.RWMutex
+	subs   []*subscription
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type subscription struct {
+	name    string
+	types   []Type
+	handler Handler
+	queue   chan Event
+}
+
+// NewBus creates a Bus.
+func NewBus(logger *zap.Logger) *Bus {
+	return &Bus{logger: logger, now: time.Now}
+}
+
+// Subscribe registers handler for events of types, or of every type
+// when none is given. name identifies the subscriber in logs. The
+// returned function removes the subscription once its queued events
+// are handled.
+func (b *Bus) Subscribe(name string, handler Handler, types ...Type) (unsubscribe func()) {
+	sub := &subscription{
+		name:    name,
+		types:   types,
+		handler: handler,
+		queue:   make(chan Event, subscriberQueueSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return func() {}
+	}
+	b.subs = append(b.subs, sub)
+	b.wg.Add(1)
+	go b.deliver(sub)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if i := slices.Index(b.subs, sub); i >= 0 {
+				b.subs = slices.Delete(b.subs, i, i+1)
+				close(sub.queue)
+			}
+		})
+	}
+}
+
+// Publish queues e for every subscriber of its type and returns
+// without waiting for them. A zero e.Time is set to the current time.
+// Publishing to a closed bus does nothing.
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = b.now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	for _, sub := range b.subs {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, e.Type) {
+			continue
+		}
+		select {
+		case sub.queue <- e:
+		default:
+			b.logger.Warn("Event subscriber is falling behind, dropping event",
+				zap.String("subscriber", sub.name),
+				zap.String("event", string(e.Type)),
+				zap.String("ticket", e.TicketKey))
+		}
+	}
+}
+
+// Close stops accepting events and waits until subscribers have
+// handled the ones already queued.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, sub := range b.subs {
+			close(sub.queue)
+		}
+		b.subs = nil
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// deliver runs sub's handler for each queued event until the queue is
+// closed.
+func (b *Bus) deliver(sub *subscription) {
+	defer b.wg.Done()
+	for e := range sub.queue {
+		b.handle(sub, e)
+	}
+}
+
+// handle runs sub's handler for e, containing a panic so that one
+// faulty subscriber neither stops its own delivery nor the process.
+func (b *Bus) handle(sub *subscription, e Event) {
+	defer func() {
+		if v := recover(); v != nil {
+			b.logger.Error("Event subscriber panicked",
+				zap.String("subscriber", sub.name),
+				zap.String("event", string(e.Type)),
+				zap.Any("panic", v),
+				zap.Stack("stack"))
+		}
+	}()
+	sub.handler(e)
+}
+
+// LogHandler returns a Handler that logs each event at info level,
+// as an audit trail of what the bot did.
+func LogHandler(logger *zap.Logger) Handler {
+	return func(e Event) {
+		fields := []zap.Field{
+			zap.String("event", string(e.Type)),
+			zap.Time("time", e.Time),
+			zap.String("ticket", e.TicketKey),
+			zap.String("job_id", e.JobID),
+		}
+		switch e.Type {
+		case AICompleted:
+			fields = append(fields,
+				zap.String("provider", e.Provider),
+				zap.Int("exit_code", e.ExitCode),
+				zap.Float64("cost_usd", e.CostUSD))
+		case PRCreated, FeedbackApplied:
+			fields = append(fields,
+				zap.String("repo", e.Repo),
+				zap.Int("pr_number", e.PRNumber),
+				zap.String("pr_url", e.PRURL))
+			if e.Type == FeedbackApplied {
+				fields = append(fields,
+					zap.String("commit", e.CommitSHA),
+					zap.Int("comments", e.Comments))
+			}
+		case TicketDeadLettered:
+			fields = append(fields, zap.Int("attempts", e.Attempt))
+		}
+		logger.Info("Pipeline event", fields...)
+	}
+}
@@ -0,0 +1,124 @@
+package events_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"jira-ai-issue-solver/events"
+)
+
+// recorder collects the events a subscriber handled.
+type recorder struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (r *recorder) handle(e events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recorder) types() []events.Type {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []events.Type
+	for _, e := range r.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestBus_DeliversByType(t *testing.T) {
+	bus := events.NewBus(zap.NewNop())
+	var all, prs recorder
+	bus.Subscribe("all", all.handle)
+	bus.Subscribe("prs", prs.handle, events.PRCreated)
+
+	bus.Publish(events.Event{Type: events.TicketStarted, TicketKey: "PROJ-1"})
+	bus.Publish(events.Event{Type: events.PRCreated, TicketKey: "PROJ-1"})
+	bus.Publish(events.Event{Type: events.AICompleted, TicketKey: "PROJ-1"})
+	bus.Close()
+
+	if got, want := all.types(), []events.Type{events.TicketStarted, events.PRCreated, events.AICompleted}; !slices.Equal(got, want) {
+		t.Errorf("all subscriber got %v, want %v", got, want)
+	}
+	if got, want := prs.types(), []events.Type{events.PRCreated}; !slices.Equal(got, want) {
+		t.Errorf("PR subscriber got %v, want %v", got, want)
+	}
+	if all.events[0].Time.IsZero() {
+		t.Error("event time was not set")
+	}
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := events.NewBus(zap.NewNop())
+	var rec recorder
+	unsubscribe := bus.Subscribe("rec", rec.handle)
+
+	bus.Publish(events.Event{Type: events.TicketStarted})
+	unsubscribe()
+	unsubscribe()
+	bus.Publish(events.Event{Type: events.PRCreated})
+	bus.Close()
+
+	if got, want := rec.types(), []events.Type{events.TicketStarted}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBus_ContainsPanickingSubscriber(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	bus := events.NewBus(zap.New(core))
+	var rec recorder
+	bus.Subscribe("faulty", func(e events.Event) {
+		if e.Type == events.TicketStarted {
+			panic("boom")
+		}
+		rec.handle(e)
+	})
+
+	bus.Publish(events.Event{Type: events.TicketStarted})
+	bus.Publish(events.Event{Type: events.PRCreated})
+	bus.Close()
+
+	if got, want := rec.types(), []events.Type{events.PRCreated}; !slices.Equal(got, want) {
+		t.Errorf("got %v after panic, want %v", got, want)
+	}
+	if logs.FilterMessage("Event subscriber panicked").Len() != 1 {
+		t.Errorf("panic was not logged: %v", logs.All())
+	}
+}
+
+func TestBus_DropsForSlowSubscriber(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	bus := events.NewBus(zap.New(core))
+	release := make(chan struct{})
+	var handled recorder
+	bus.Subscribe("slow", func(e events.Event) {
+		<-release
+		handled.handle(e)
+	})
+
+	// One event is being handled and a queue's worth waits; the rest
+	// are dropped instead of blocking Publish.
+	const published = 300
+	for range published {
+		bus.Publish(events.Event{Type: events.AICompleted})
+	}
+	close(release)
+	bus.Close()
+
+	dropped := logs.FilterMessage("Event subscriber is falling behind, dropping event").Len()
+	if dropped == 0 {
+		t.Error("expected dropped events to be logged")
+	}
+	if got := len(handled.types()); got+dropped != published {
+		t.Errorf("handled %d and dropped %d events, want %d in total", got, dropped, published)
+	}
+}
@@ -0,0 +1,27 @@
+package executor
+
+import (
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// loadAcceptanceCriteria fills in workItem.AcceptanceCriteria from the
+// project's acceptance criteria field, when one is configured. Lookup
+// failures are logged and leave the criteria empty: the description
+// still gives the AI the ticket's intent.
+func (p *Pipeline) loadAcceptanceCriteria(logger *zap.Logger, workItem *models.WorkItem, settings *models.ProjectSettings) {
+	field := settings.AcceptanceCriteriaFieldName
+	if field == "" {
+		return
+	}
+	criteria, err := p.tracker.GetFieldValue(workItem.Key, field)
+	if err != nil {
+		logger.Warn("Failed to read acceptance criteria",
+			zap.String("ticket", workItem.Key),
+			zap.String("field", field),
+			zap.Error(err))
+		return
+	}
+	workItem.AcceptanceCriteria = criteria
+}
@@ -0,0 +1,75 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+// withAcceptanceCriteriaField sets the project's acceptance criteria
+// field.
+func withAcceptanceCriteriaField(d *testDeps, field string) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.AcceptanceCriteriaFieldName = field
+		}
+		return settings, err
+	}
+}
+
+func TestExecuteNewTicket_AcceptanceCriteriaInTaskFiles(t *testing.T) {
+	d := newTestDeps(t)
+	withAcceptanceCriteriaField(d, "Definition of Done")
+	d.tracker.GetFieldValueFunc = func(key, field string) (string, error) {
+		if key != "PROJ-1" || field != "Definition of Done" {
+			t.Errorf("GetFieldValue(%q, %q)", key, field)
+		}
+		return "- Login works with SSO", nil
+	}
+	var issueCriteria, taskCriteria string
+	d.taskWriter.WriteIssueFunc = func(workItem models.WorkItem, _ string, _ []string, _ []models.Comment) error {
+		issueCriteria = workItem.AcceptanceCriteria
+		return nil
+	}
+	d.taskWriter.WriteNewTicketTaskFunc = func(workItem models.WorkItem, dir, _, _ string) error {
+		taskCriteria = workItem.AcceptanceCriteria
+		writeTaskFile(t, dir)
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if issueCriteria != "- Login works with SSO" || taskCriteria != "- Login works with SSO" {
+		t.Errorf("acceptance criteria = %q (issue), %q (task)", issueCriteria, taskCriteria)
+	}
+}
+
+func TestExecuteNewTicket_AcceptanceCriteriaLookupFailureIgnored(t *testing.T) {
+	d := newTestDeps(t)
+	withAcceptanceCriteriaField(d, "Definition of Done")
+	d.tracker.GetFieldValueFunc = func(key, field string) (string, error) {
+		return "", errors.New("field not found")
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("a failed criteria lookup should not fail the job: %v", err)
+	}
+}
+
+func TestExecuteNewTicket_NoAcceptanceCriteriaFieldSkipsLookup(t *testing.T) {
+	d := newTestDeps(t)
+	d.tracker.GetFieldValueFunc = func(key, field string) (string, error) {
+		t.Errorf("unexpected GetFieldValue(%q, %q)", key, field)
+		return "", nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/events"
+	"jira-ai-issue-solver/jobmanager"
+)
+
+// runAISession runs the AI agent for a job and returns its exit code.
+// Providers with a registered [AgentRunner] run in-process, with
+// commands executed in ctr; others run their CLI inside ctr via
+// [buildExecCommand]. Either way the session metadata is left in
+// session-output.json for [readSessionOutput].
+//
+// An error is returned only when the session could not be run at
+// all (e.g., the context was cancelled); an agent that fails
+// mid-session is reported as exit code 1. Sessions that ran are
+// published as [events.AICompleted]. With an [AILimiter] configured,
+// the session first waits for the limiter to admit it.
+func (p *Pipeline) runAISession(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	ctr *container.Container,
+	wsPath string,
+	sp scriptParams,
+) (int, error) {
+	if p.cfg.AILimiter != nil {
+		start := time.Now()
+		release, err := p.cfg.AILimiter.Acquire(ctx, sp.Provider)
+		if err != nil {
+			return 0, fmt.Errorf("wait for AI session slot: %w", err)
+		}
+		defer release()
+		waited := time.Since(start)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("ai.queue_wait_ms", waited.Milliseconds()))
+		if waited >= time.Second {
+			logger.Info("AI session waited for rate limits",
+				zap.String("provider", sp.Provider), zap.Duration("waited", waited))
+		}
+	}
+
+	markAISession(ctx)
+	exitCode, err := p.execAISession(ctx, logger, job, ctr, wsPath, sp)
+	if err == nil && p.cfg.Events != nil {
+		session := readSessionOutput(wsPath)
+		p.applyCostEstimate(&session)
+		p.publish(job, events.Event{
+			Type:     events.AICompleted,
+			Provider: sp.Provider,
+			ExitCode: exitCode,
+			CostUSD:  session.CostUSD,
+		})
+	}
+	return exitCode, err
+}
+
+// execAISession runs the AI agent for [Pipeline.runAISession].
+func (p *Pipeline) execAISession(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	ctr *container.Container,
+	wsPath string,
+	sp scriptParams,
+) (int, error) {
+	runner, ok := p.cfg.Agents[sp.Provider]
+	if !ok {
+		// The CLI reports nothing until it exits, so its progress is
+		// not streamed and MaxAgentOutputBytes does not apply.
+		_, exitCode, err := p.containers.Exec(ctx, ctr, buildExecCommand(sp))
+		if err == nil {
+			p.attachTranscript(logger, job, wsPath, false)
+		}
+		return exitCode, err
+	}
+
+	// Output left by an earlier CLI session would otherwise be
+	// picked up by readSessionOutput.
+	_ = os.Remove(filepath.Join(wsPath, cliOutputPath))
+
+	var transcript io.Writer
+	if p.cfg.AttachTranscripts {
+		f, err := openAgentTranscript(wsPath)
+		if err != nil {
+			logger.Warn("Failed to create AI transcript", zap.Error(err))
+		} else {
+			defer func() { _ = f.Close() }()
+			transcript = f
+		}
+	}
+
+	span := trace.SpanFromContext(ctx)
+	var commands []string
+	result, err := runner.Run(ctx, agent.Request{
+		Dir:    wsPath,
+		Prompt: taskPrompt,
+		Model:  sp.Model,
+		Exec: func(ctx context.Context, cmd []string) (string, int, error) {
+			return p.containers.Exec(ctx, ctr, cmd)
+		},
+		OnEvent: func(e agent.Event) {
+			reportAgentEvent(logger, span, e)
+			if e.Kind == agent.EventToolCall && e.Tool == agent.ToolRunCommand {
+				commands = append(commands, e.Detail)
+			}
+		},
+		MaxOutputBytes: p.cfg.MaxAgentOutputBytes,
+		Transcript:     transcript,
+	})
+	if err != nil && ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	exitCode := 0
+	if err != nil {
+		logger.Warn("AI agent session failed", zap.Error(err), zap.Int("turns", result.Turns))
+		exitCode = 1
+	}
+	writeAgentSessionOutput(logger, wsPath, SessionOutput{
+		ExitCode:     exitCode,
+		CostUSD:      result.CostUSD,
+		InputTokens:  result.InputTokens,
+		OutputTokens: result.OutputTokens,
+		CachedTokens: result.CachedTokens,
+		Summary:      result.Summary,
+		Commands:     commands,
+	})
+	p.attachTranscript(logger, job, wsPath, true)
+	return exitCode, nil
+}
+
+// reportAgentEvent logs an in-process session's progress as it
+// happens and records it on the AI session span, so that long
+// sessions can be followed live rather than only after they end.
+func reportAgentEvent(logger *zap.Logger, span trace.Span, e agent.Event) {
+	switch e.Kind {
+	case agent.EventToolCall:
+		logger.Info("AI agent tool call",
+			zap.Int("turn", e.Turn),
+			zap.String("tool", e.Tool),
+			zap.String("detail", e.Detail),
+			zap.Bool("error", e.Error))
+		span.AddEvent("ai.tool_call", trace.WithAttributes(
+			attribute.String("ai.tool", e.Tool),
+			attribute.String("ai.tool.detail", e.Detail),
+			attribute.Bool("ai.tool.error", e.Error)))
+	case agent.EventMessage:
+		logger.Debug("AI agent message",
+			zap.Int("turn", e.Turn),
+			zap.String("text", e.Detail))
+	}
+}
+
+// writeAgentSessionOutput records an in-process session's metadata
+// where the wrapper script would have written it for a CLI session.
+func writeAgentSessionOutput(logger *zap.Logger, wsPath string, output SessionOutput) {
+	path := filepath.Join(wsPath, sessionOutputPath)
+	data, err := json.Marshal(output)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o750)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0o600)
+	}
+	if err != nil {
+		logger.Warn("Failed to write session output", zap.Error(err))
+	}
+}
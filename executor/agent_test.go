@@ -0,0 +1,149 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/executor/executortest"
+)
+
+func agentConfig(runner executor.AgentRunner) executor.Config {
+	return executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		MaxRetries:      3,
+		Agents:          map[string]executor.AgentRunner{"claude": runner},
+	}
+}
+
+func TestExecuteNewTicket_RunsRegisteredAgent(t *testing.T) {
+	d := newTestDeps(t)
+	var execCmds [][]string
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		execCmds = append(execCmds, cmd)
+		return "ok", 0, nil
+	}
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			if req.Dir != d.wsDir {
+				t.Errorf("Dir = %q, want %q", req.Dir, d.wsDir)
+			}
+			if req.MaxOutputBytes != 1<<20 || req.OnEvent == nil {
+				t.Errorf("MaxOutputBytes = %d, OnEvent set = %v", req.MaxOutputBytes, req.OnEvent != nil)
+			}
+			if _, _, err := req.Exec(ctx, []string{"make", "test"}); err != nil {
+				t.Errorf("Exec: %v", err)
+			}
+			return agent.Result{CostUSD: 0.42, Summary: "Fixed the bug"}, nil
+		},
+	}
+
+	cfg := agentConfig(runner)
+	cfg.MaxAgentOutputBytes = 1 << 20
+	result, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the agent's command runs in the container; the CLI wrapper
+	// script is not used.
+	if len(execCmds) != 1 || !slices.Equal(execCmds[0], []string{"make", "test"}) {
+		t.Errorf("container commands = %q, want only [make test]", execCmds)
+	}
+	if result.CostUSD != 0.42 {
+		t.Errorf("CostUSD = %v, want 0.42", result.CostUSD)
+	}
+}
+
+func TestExecuteNewTicket_AgentFailureIsNonzeroExit(t *testing.T) {
+	d := newTestDeps(t)
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			return agent.Result{Turns: 100}, agent.ErrMaxTurns
+		},
+	}
+	var labels []string
+	d.git.AddPRLabelFunc = func(owner, repo string, number int, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Contains(labels, "ai-nonzero-exit") {
+		t.Errorf("PR labels = %v, want ai-nonzero-exit", labels)
+	}
+}
+
+func TestExecuteNewTicket_AgentCancelled(t *testing.T) {
+	d := newTestDeps(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			cancel()
+			return agent.Result{}, ctx.Err()
+		},
+	}
+
+	_, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(ctx, newTicketJob("PROJ-1"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestExecuteNewTicket_AILimiterHoldsSession(t *testing.T) {
+	d := newTestDeps(t)
+	var events []string
+	limiter := &executortest.StubAILimiter{
+		AcquireFunc: func(_ context.Context, provider string) (func(), error) {
+			events = append(events, "acquire "+provider)
+			return func() { events = append(events, "release") }, nil
+		},
+	}
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			events = append(events, "run")
+			return agent.Result{}, nil
+		},
+	}
+
+	cfg := agentConfig(runner)
+	cfg.AILimiter = limiter
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"acquire claude", "run", "release"}; !slices.Equal(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
+
+func TestExecuteNewTicket_AILimiterWaitCancelled(t *testing.T) {
+	d := newTestDeps(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	limiter := &executortest.StubAILimiter{
+		AcquireFunc: func(ctx context.Context, _ string) (func(), error) {
+			cancel()
+			return nil, ctx.Err()
+		},
+	}
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			t.Error("agent ran without being admitted")
+			return agent.Result{}, nil
+		},
+	}
+
+	cfg := agentConfig(runner)
+	cfg.AILimiter = limiter
+	_, err := d.pipelineWithConfig(t, cfg).Execute(ctx, newTicketJob("PROJ-1"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
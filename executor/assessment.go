@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// Changes above either threshold are considered large enough that a
+// passing validation no longer warrants high confidence.
+const (
+	largeChangeLines = 400
+	largeChangeFiles = 15
+)
+
+// Confidence levels written to the assessment field.
+const (
+	confidenceHigh   = "High"
+	confidenceMedium = "Medium"
+	confidenceLow    = "Low"
+)
+
+// branchDiff identifies a workspace directory and the base branch its
+// changes are measured against.
+type branchDiff struct {
+	dir        string
+	baseBranch string
+}
+
+// recordAssessment writes the bot's assessment of a new PR to the
+// project's assessment field, so that reviewers and sprint planners
+// can see at a glance how much scrutiny the change needs. The diff
+// size is summed across all given branches. Errors are logged and
+// otherwise ignored.
+func (p *Pipeline) recordAssessment(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	session SessionOutput,
+	exitCode int,
+	diffs []branchDiff,
+) {
+	if settings.AssessmentFieldName == "" {
+		return
+	}
+
+	var total models.DiffStat
+	known := true
+	for _, d := range diffs {
+		stat, err := p.git.DiffStat(d.dir, d.baseBranch)
+		if err != nil {
+			logger.Warn("Failed to compute diff stat for assessment", zap.Error(err))
+			known = false
+			break
+		}
+		total.FilesChanged += stat.FilesChanged
+		total.Insertions += stat.Insertions
+		total.Deletions += stat.Deletions
+	}
+	var stat *models.DiffStat
+	if known {
+		stat = &total
+	}
+
+	value := assessChange(session, exitCode, stat)
+	if err := p.tracker.SetFieldValue(ticketKey, settings.AssessmentFieldName, value); err != nil {
+		logger.Warn("Failed to set assessment field", zap.Error(err))
+	}
+}
+
+// assessChange summarizes how far a new PR can be trusted, e.g.
+// "High: validation passed; 3 files changed, +40 -5". stat is nil
+// when the diff size is unknown.
+func assessChange(session SessionOutput, exitCode int, stat *models.DiffStat) string {
+	confidence, validation := rateChange(session, exitCode, stat)
+	if stat == nil {
+		return fmt.Sprintf("%s: %s; diff size unknown", confidence, validation)
+	}
+	files := "files"
+	if stat.FilesChanged == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%s: %s; %d %s changed, +%d -%d",
+		confidence, validation, stat.FilesChanged, files, stat.Insertions, stat.Deletions)
+}
+
+// rateChange returns the confidence level of a change and the
+// validation result it is based on. Confidence is Low when validation
+// failed or the AI exited with an error, High when validation passed
+// and the change is small, and Medium otherwise. stat is nil when the
+// diff size is unknown.
+func rateChange(session SessionOutput, exitCode int, stat *models.DiffStat) (confidence, validation string) {
+	switch {
+	case session.ValidationPassed != nil && !*session.ValidationPassed:
+		return confidenceLow, "validation failed"
+	case exitCode != 0:
+		return confidenceLow, fmt.Sprintf("AI exited with code %d", exitCode)
+	case session.ValidationPassed == nil:
+		return confidenceMedium, "validation not reported"
+	case stat == nil ||
+		stat.Insertions+stat.Deletions > largeChangeLines ||
+		stat.FilesChanged > largeChangeFiles:
+		return confidenceMedium, "validation passed"
+	default:
+		return confidenceHigh, "validation passed"
+	}
+}
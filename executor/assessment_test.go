@@ -0,0 +1,69 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+)
+
+func TestExecuteNewTicket_RecordsAssessment(t *testing.T) {
+	tests := []struct {
+		name       string
+		validation *bool
+		stat       models.DiffStat
+		want       string
+	}{
+		{name: "small passing change", validation: boolPtr(true), stat: models.DiffStat{FilesChanged: 3, Insertions: 40, Deletions: 5}, want: "High: validation passed; 3 files changed, +40 -5"},
+		{name: "large passing change", validation: boolPtr(true), stat: models.DiffStat{FilesChanged: 4, Insertions: 380, Deletions: 60}, want: "Medium: validation passed; 4 files changed, +380 -60"},
+		{name: "validation not reported", stat: models.DiffStat{FilesChanged: 1, Insertions: 2}, want: "Medium: validation not reported; 1 file changed, +2 -0"},
+		{name: "validation failed", validation: boolPtr(false), stat: models.DiffStat{FilesChanged: 1, Insertions: 2}, want: "Low: validation failed; 1 file changed, +2 -0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			resolve := d.projects.ResolveProjectFunc
+			d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+				settings, err := resolve(workItem)
+				if err != nil {
+					return nil, err
+				}
+				settings.AssessmentFieldName = "AI Confidence"
+				return settings, nil
+			}
+			d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+				writeSessionOutput(t, d.wsDir, executor.SessionOutput{ValidationPassed: tt.validation})
+				return "", 0, nil
+			}
+			d.git.DiffStatFunc = func(dir, baseBranch string) (models.DiffStat, error) {
+				return tt.stat, nil
+			}
+			fields := map[string]string{}
+			d.tracker.SetFieldValueFunc = func(key, field, value string) error {
+				fields[field] = value
+				return nil
+			}
+
+			if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := fields["AI Confidence"]; got != tt.want {
+				t.Errorf("assessment = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteNewTicket_NoAssessmentFieldSkipsDiffStat(t *testing.T) {
+	d := newTestDeps(t)
+	d.git.DiffStatFunc = func(dir, baseBranch string) (models.DiffStat, error) {
+		t.Error("DiffStat should not be called without an assessment field")
+		return models.DiffStat{}, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// assignBot assigns the ticket to the project's bot account while a
+// job works on it and returns a func that restores the original
+// assignee, or unassigns the ticket if it had none. The work item
+// keeps the original assignee, so commits still credit them as
+// co-author. Does nothing when the project does not set assign_bot,
+// when the bot is already the assignee (a previous attempt did not
+// restore it, so the original is unknown), or when the original
+// assignee's account ID is unknown and could not be restored. Errors
+// are logged and otherwise ignored.
+func (p *Pipeline) assignBot(logger *zap.Logger, ticketKey string, workItem *models.WorkItem, settings *models.ProjectSettings) func() {
+	bot := settings.BotAssignee
+	if bot == "" {
+		return func() {}
+	}
+	var original string
+	if workItem.Assignee != nil {
+		original = workItem.Assignee.AccountID
+		if original == bot {
+			return func() {}
+		}
+		if original == "" {
+			logger.Warn("Assignee has no account ID, not assigning the bot",
+				zap.String("assignee", workItem.Assignee.Name))
+			return func() {}
+		}
+	}
+
+	if err := p.tracker.AssignWorkItem(ticketKey, bot); err != nil {
+		logger.Warn("Failed to assign ticket to the bot", zap.Error(err))
+		return func() {}
+	}
+	return func() {
+		if err := p.tracker.AssignWorkItem(ticketKey, original); err != nil {
+			logger.Warn("Failed to restore ticket assignee",
+				zap.String("account_id", original), zap.Error(err))
+		}
+	}
+}
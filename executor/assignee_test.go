@@ -0,0 +1,117 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+// enableAssignBot makes the project assign tickets to bot, gives the
+// ticket assignee, and returns the account IDs the ticket was
+// assigned to, in order, and the co-author of each commit.
+func enableAssignBot(d *testDeps, bot string, assignee *models.Author) (*[]string, *[]*models.Author) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.BotAssignee = bot
+		}
+		return settings, err
+	}
+	get := d.tracker.GetWorkItemFunc
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		item, err := get(key)
+		if err == nil {
+			item.Assignee = assignee
+		}
+		return item, err
+	}
+	var assigned []string
+	d.tracker.AssignWorkItemFunc = func(_, accountID string) error {
+		assigned = append(assigned, accountID)
+		return nil
+	}
+	var coAuthors []*models.Author
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, coAuthor *models.Author, _ []string, _ bool) (string, error) {
+		coAuthors = append(coAuthors, coAuthor)
+		return "abc123", nil
+	}
+	return &assigned, &coAuthors
+}
+
+func TestExecuteNewTicket_AssignsBotAndRestoresAssignee(t *testing.T) {
+	d := newTestDeps(t)
+	alice := &models.Author{Name: "Alice", Email: "alice@example.com", AccountID: "alice-id"}
+	assigned, coAuthors := enableAssignBot(d, "bot-id", alice)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"bot-id", "alice-id"}; !slices.Equal(*assigned, want) {
+		t.Errorf("assigned %q, want %q", *assigned, want)
+	}
+	if len(*coAuthors) == 0 || (*coAuthors)[0] != alice {
+		t.Errorf("commit co-authors = %v, want the original assignee", *coAuthors)
+	}
+}
+
+func TestExecuteNewTicket_AssignBotRestores(t *testing.T) {
+	tests := []struct {
+		name     string
+		assignee *models.Author
+		createPR error
+		want     []string
+	}{
+		{
+			name: "unassigned ticket",
+			want: []string{"bot-id", ""},
+		},
+		{
+			name:     "failed job",
+			assignee: &models.Author{AccountID: "alice-id"},
+			createPR: errors.New("boom"),
+			want:     []string{"bot-id", "alice-id"},
+		},
+		{
+			name:     "bot already assigned",
+			assignee: &models.Author{AccountID: "bot-id"},
+		},
+		{
+			name:     "assignee without account ID",
+			assignee: &models.Author{Name: "alice"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			assigned, _ := enableAssignBot(d, "bot-id", tt.assignee)
+			if tt.createPR != nil {
+				d.git.CreatePRFunc = func(models.PRParams) (*models.PR, error) {
+					return nil, tt.createPR
+				}
+			}
+
+			_, _ = d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+			if !slices.Equal(*assigned, tt.want) {
+				t.Errorf("assigned %q, want %q", *assigned, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteNewTicket_AssignBotDisabled(t *testing.T) {
+	d := newTestDeps(t)
+	assigned, _ := enableAssignBot(d, "", &models.Author{AccountID: "alice-id"})
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*assigned) != 0 {
+		t.Errorf("assigned %q, want the assignee left alone", *assigned)
+	}
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// enableAutoMerge enables the host's auto-merge on a new PR when the
+// project's auto_merge settings allow it for the change in wsPath:
+// the PR is not a draft, the AI's validation passed, and the risk the
+// AI reported is within auto_merge.max_risk. The host then merges the
+// PR once its required approvals and checks pass. Errors are logged
+// and otherwise ignored; the PR then waits for a human to merge it.
+func (p *Pipeline) enableAutoMerge(
+	logger *zap.Logger,
+	settings *models.ProjectSettings,
+	owner, repo string,
+	number int,
+	draft bool,
+	wsPath string,
+	session SessionOutput,
+	exitCode int,
+) {
+	autoMerge := settings.AutoMerge
+	if !autoMerge.IsEnabled() {
+		return
+	}
+	var risk string
+	if s := readPRSummary(wsPath); s != nil {
+		risk = s.Risk
+	}
+	switch {
+	case draft:
+		logger.Info("Not enabling auto-merge on a draft PR", zap.Int("pr", number))
+		return
+	case !validationPassed(session, exitCode):
+		logger.Info("Not enabling auto-merge: AI validation did not pass", zap.Int("pr", number))
+		return
+	case !autoMerge.Allows(risk):
+		logger.Info("Not enabling auto-merge: change risk above auto_merge.max_risk",
+			zap.Int("pr", number), zap.String("risk", risk), zap.String("max_risk", autoMerge.MaxRisk))
+		return
+	}
+
+	if err := p.git.EnablePRAutoMerge(owner, repo, number, autoMerge.Method); err != nil {
+		logger.Warn("Failed to enable auto-merge", zap.Int("pr", number), zap.Error(err))
+		return
+	}
+	logger.Info("Auto-merge enabled", zap.Int("pr", number), zap.String("method", autoMerge.Method))
+}
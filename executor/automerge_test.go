@@ -0,0 +1,84 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// enableAutoMerge sets the project's auto-merge settings and returns
+// the merge methods auto-merge was enabled with.
+func enableAutoMerge(d *testDeps, cfg models.AutoMerge) *[]string {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.AutoMerge = cfg
+		}
+		return settings, err
+	}
+	var methods []string
+	d.git.EnablePRAutoMergeFunc = func(_, _ string, _ int, method string) error {
+		methods = append(methods, method)
+		return nil
+	}
+	return &methods
+}
+
+func TestExecuteNewTicket_EnablesAutoMerge(t *testing.T) {
+	d := newTestDeps(t)
+	methods := enableAutoMerge(d, models.AutoMerge{Method: "squash", MaxRisk: "low"})
+	writeSessionFile(t, d, taskfile.PRSummaryPath, `{"changes": ["Fixed a typo"], "risk": "low"}`)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*methods) != 1 || (*methods)[0] != "squash" {
+		t.Errorf("auto-merge enabled with %q, want squash once", *methods)
+	}
+}
+
+func TestExecuteNewTicket_AutoMergeSkipsRiskyChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+	}{
+		{name: "risk above max", summary: `{"changes": ["Rewrote the scheduler"], "risk": "high"}`},
+		{name: "risk not reported", summary: `{"changes": ["Rewrote the scheduler"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			methods := enableAutoMerge(d, models.AutoMerge{Method: "squash", MaxRisk: "medium"})
+			writeSessionFile(t, d, taskfile.PRSummaryPath, tt.summary)
+
+			if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*methods) != 0 {
+				t.Errorf("auto-merge enabled with %q, want it left off", *methods)
+			}
+		})
+	}
+}
+
+func TestExecuteNewTicket_AutoMergeSkipsFailedValidation(t *testing.T) {
+	d := newTestDeps(t)
+	methods := enableAutoMerge(d, models.AutoMerge{Method: "rebase"})
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		writeSessionOutput(t, d.wsDir, executor.SessionOutput{ValidationPassed: boolPtr(false)})
+		return "", 0, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*methods) != 0 {
+		t.Errorf("auto-merge enabled with %q despite failed validation", *methods)
+	}
+}
@@ -0,0 +1,419 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoconfig"
+	"jira-ai-issue-solver/services"
+)
+
+// executeBackport opens a PR cherry-picking the ticket's merged change
+// onto each release branch its backport labels request. Release
+// branches that already have a backport PR, whether open, merged, or
+// closed, are skipped, so the job can be resubmitted safely. Cherry-
+// pick conflicts are handed to the AI; a change it cannot resolve is
+// reported on the ticket instead of opening a PR.
+func (p *Pipeline) executeBackport(ctx context.Context, job *jobmanager.Job) (result jobmanager.JobResult, retErr error) {
+	logger := p.logger.With(
+		zap.String("ticket", job.TicketKey),
+		zap.String("job_id", job.ID),
+		zap.Int("attempt", job.AttemptNum),
+	)
+	logger.Info("Starting backport pipeline")
+
+	// --- Step 1: Fetch work item ---
+	workItem, err := p.tracker.GetWorkItem(job.TicketKey)
+	if err != nil {
+		return result, fmt.Errorf("get work item: %w", err)
+	}
+
+	// --- Step 2: Resolve project settings ---
+	settings, err := p.projects.ResolveProject(*workItem)
+	if err != nil {
+		return result, fmt.Errorf("resolve project: %w", err)
+	}
+
+	defer func() {
+		if retErr != nil {
+			p.handleBackportFailure(logger, job.TicketKey, settings, retErr)
+		}
+	}()
+
+	targets := settings.Backport.TargetBranches(workItem.Labels)
+	if len(targets) == 0 {
+		logger.Info("No backports requested")
+		return result, nil
+	}
+	if settings.IsMultiRepo() {
+		return result, errors.New("backports are not supported for multi-repo workspaces")
+	}
+	repo := settings.Repos[0]
+
+	// --- Step 3: Find the merged PR ---
+	branchName := fmt.Sprintf("%s/%s", p.cfg.BotUsername, job.TicketKey)
+	merged, err := p.findMergedPR(repo, settings.PRHeads(branchName))
+	if err != nil {
+		return result, err
+	}
+
+	// --- Step 4: Backport to each release branch ---
+	var errs []error
+	for _, target := range targets {
+		backportBranch := models.BackportBranchName(p.cfg.BotUsername, job.TicketKey, target)
+		exists, err := p.backportPRExists(repo, settings.PRHeads(backportBranch))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("backport to %s: %w", target, err))
+			continue
+		}
+		if exists {
+			logger.Debug("Backport PR already exists, skipping", zap.String("target", target))
+			continue
+		}
+
+		pr, cost, err := p.backportTo(ctx, logger, job, workItem, settings, merged, target, backportBranch)
+		result.CostUSD += cost
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, fmt.Errorf("job cancelled: %w", ctx.Err())
+			}
+			errs = append(errs, fmt.Errorf("backport to %s: %w", target, err))
+			continue
+		}
+
+		result.PRURL = pr.URL
+		result.PRNumber = pr.Number
+		logger.Info("Backport PR created",
+			zap.String("target", target),
+			zap.String("url", pr.URL),
+			zap.Int("number", pr.Number))
+
+		comment := fmt.Sprintf("Opened a backport of %s to %s: %s", merged.URL, target, pr.URL)
+		if err := p.tracker.AddComment(job.TicketKey, comment); err != nil {
+			logger.Warn("Failed to post backport comment", zap.Error(err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// backportTo cherry-picks the merged PR onto a new branch from target,
+// has the AI resolve any conflicts, and opens the backport PR. Returns
+// the PR and the AI session cost.
+//
+//nolint:cyclop
+func (p *Pipeline) backportTo(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	workItem *models.WorkItem,
+	settings *models.ProjectSettings,
+	merged *models.PRDetails,
+	target, branchName string,
+) (*models.PR, float64, error) {
+	repo := settings.Repos[0]
+	logger = logger.With(zap.String("target", target))
+
+	wsPath, _, err := p.workspaces.FindOrCreate(job.TicketKey, repo.CloneURL, repo.SparsePaths)
+	if err != nil {
+		return nil, 0, fmt.Errorf("prepare workspace: %w", err)
+	}
+	if err := p.ensureForkRemote(wsPath, settings); err != nil {
+		return nil, 0, err
+	}
+	if forkOwner := settings.ForkOwner(); forkOwner != "" {
+		if err := p.git.SyncFork(forkOwner, repo.Repo, target); err != nil {
+			logger.Warn("Failed to sync fork with upstream",
+				zap.String("fork", forkOwner+"/"+repo.Repo),
+				zap.Error(err))
+		}
+	}
+	if err := p.git.CreateBranch(wsPath, branchName, target); err != nil {
+		return nil, 0, fmt.Errorf("create branch: %w", err)
+	}
+
+	conflictFiles, pickErr := p.git.CherryPickPR(wsPath, p.upstreamMergeURL(settings, repo), merged.Number, merged.MergeCommitSHA)
+	if pickErr != nil && !errors.Is(pickErr, services.ErrMergeConflict) {
+		return nil, 0, fmt.Errorf("cherry-pick PR #%d: %w", merged.Number, pickErr)
+	}
+
+	var cost float64
+	if pickErr != nil {
+		logger.Info("Backport conflicts detected, invoking AI for resolution",
+			zap.Strings("files", conflictFiles))
+		cost, err = p.resolveBackportConflicts(ctx, logger, job, workItem, settings, wsPath, merged, target, conflictFiles)
+		if err != nil {
+			return nil, cost, err
+		}
+		if unresolved := unresolvedConflicts(wsPath, conflictFiles); len(unresolved) > 0 {
+			return nil, cost, fmt.Errorf("AI could not resolve the conflicts in %s", strings.Join(unresolved, ", "))
+		}
+	}
+
+	hasChanges, err := p.git.HasChanges(wsPath, target)
+	if err != nil {
+		return nil, cost, fmt.Errorf("check changes: %w", err)
+	}
+	if !hasChanges {
+		return nil, cost, fmt.Errorf("nothing to backport: %s already contains the change", target)
+	}
+
+	commitMsg := fmt.Sprintf("%s: backport #%d to %s", job.TicketKey, merged.Number, target)
+	_, err = p.git.CommitChanges(
+		repo.Owner, settings.CommitOwner(), repo.Repo, branchName,
+		commitMsg, wsPath, target, workItem.Assignee, nil, skipFileGuardrail,
+	)
+	if errors.Is(err, services.ErrNoChanges) {
+		return nil, cost, fmt.Errorf("nothing to backport: %s already contains the change", target)
+	}
+	if err != nil {
+		return nil, cost, fmt.Errorf("commit changes: %w", err)
+	}
+
+	repoCfg, err := repoconfig.Load(wsPath)
+	if err != nil {
+		logger.Warn("Failed to load repo config, using defaults", zap.Error(err))
+		repoCfg = repoconfig.Default()
+	}
+	pr, err := p.git.CreatePR(models.PRParams{
+		Owner:     repo.Owner,
+		Repo:      repo.Repo,
+		Title:     fmt.Sprintf("[%s] %s", target, merged.Title),
+		Body:      backportPRBody(job.TicketKey, merged, target, conflictFiles),
+		Head:      settings.PRHead(branchName),
+		Base:      target,
+		Draft:     repoCfg.PR.Draft,
+		Labels:    repoCfg.PR.Labels,
+		Assignees: assigneesFromSettings(settings),
+	})
+	if err != nil {
+		return nil, cost, fmt.Errorf("create PR: %w", err)
+	}
+
+	p.postOrUpdateCostComment(logger, repo.Owner, repo.Repo, pr.Number, cost, "Backport conflict resolution", 0)
+	return pr, cost, nil
+}
+
+// resolveBackportConflicts runs an AI session to resolve the conflicts
+// left by cherry-picking the merged PR onto target. Returns the
+// session cost.
+func (p *Pipeline) resolveBackportConflicts(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	workItem *models.WorkItem,
+	settings *models.ProjectSettings,
+	wsPath string,
+	merged *models.PRDetails,
+	target string,
+	conflictFiles []string,
+) (float64, error) {
+	repo := settings.Repos[0]
+
+	repoCfg, err := repoconfig.Load(wsPath)
+	if err != nil {
+		logger.Warn("Failed to load repo config, using defaults", zap.Error(err))
+		repoCfg = repoconfig.Default()
+	}
+
+	comments := p.fetchTicketComments(logger, *workItem)
+	if err := p.taskWriter.WriteIssue(*workItem, wsPath, nil, comments); err != nil {
+		return 0, fmt.Errorf("write issue file: %w", err)
+	}
+	if err := p.taskWriter.WriteBackportConflictTask(
+		*merged, target, conflictFiles, wsPath, repo.Instructions,
+	); err != nil {
+		return 0, fmt.Errorf("write backport task file: %w", err)
+	}
+
+	session, err := p.runRepoSession(ctx, logger, job, settings, repoCfg, wsPath, p.cfg.SessionTimeout)
+	return session.CostUSD, err
+}
+
+// errSessionTimeout reports an AI session stopped by its timeout.
+var errSessionTimeout = errors.New("session timeout exceeded")
+
+// runRepoSession runs an AI session on the task already written to
+// the single-repo workspace at wsPath, with the remote's credentials
+// stripped so that the AI cannot push. A positive timeout stops the
+// session with [errSessionTimeout]. Returns the session output, whose
+// cost is set even when the session fails.
+func (p *Pipeline) runRepoSession(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	settings *models.ProjectSettings,
+	repoCfg *repoconfig.Config,
+	wsPath string,
+	timeout time.Duration,
+) (SessionOutput, error) {
+	repo := settings.Repos[0]
+	provider := p.resolveProvider(settings)
+	sp := buildScriptParams(provider, p.cfg.DefaultClaudeModel, p.cfg.DefaultGeminiModel, repoCfg)
+
+	ctr, err := p.startContainer(ctx, wsPath, job.TicketKey, provider, settings)
+	if err != nil {
+		return SessionOutput{}, fmt.Errorf("start container: %w", err)
+	}
+	defer func(ctr *container.Container) {
+		if stopErr := p.containers.Stop(context.Background(), ctr); stopErr != nil {
+			logger.Warn("Failed to stop container", zap.Error(stopErr))
+		}
+	}(ctr)
+
+	if err := p.git.StripRemoteAuth(wsPath); err != nil {
+		return SessionOutput{}, fmt.Errorf("strip remote auth: %w", err)
+	}
+	authStripped := true
+	defer func() {
+		if authStripped {
+			if restoreErr := p.git.RestoreRemoteAuth(wsPath, settings.CommitOwner(), repo.Repo); restoreErr != nil {
+				logger.Warn("Failed to restore remote auth", zap.Error(restoreErr))
+			}
+		}
+	}()
+
+	execCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	exitCode, execErr := p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
+	if execErr != nil && ctx.Err() != nil {
+		return SessionOutput{}, fmt.Errorf("job cancelled: %w", ctx.Err())
+	}
+
+	session := readSessionOutput(wsPath)
+	p.applyCostEstimate(&session)
+	logger.Info("AI session completed",
+		zap.Int("exit_code", exitCode),
+		zap.Float64("cost_usd", session.CostUSD))
+
+	if err := p.git.RestoreRemoteAuth(wsPath, settings.CommitOwner(), repo.Repo); err != nil {
+		return session, fmt.Errorf("restore remote auth: %w", err)
+	}
+	authStripped = false
+
+	if execErr != nil {
+		if execCtx.Err() != nil {
+			return session, fmt.Errorf("%w: %w", errSessionTimeout, execErr)
+		}
+		return session, fmt.Errorf("AI session failed: %w", execErr)
+	}
+	return session, nil
+}
+
+// findMergedPR returns the merged PR for the first of heads that has
+// one.
+func (p *Pipeline) findMergedPR(repo models.RepoSettings, heads []string) (*models.PRDetails, error) {
+	for _, head := range heads {
+		pr, err := p.git.GetMergedPRForBranch(repo.Owner, repo.Repo, head)
+		if err != nil {
+			return nil, fmt.Errorf("find merged PR: %w", err)
+		}
+		if pr != nil {
+			return pr, nil
+		}
+	}
+	return nil, fmt.Errorf("no merged PR found for heads %v", heads)
+}
+
+// backportPRExists reports whether any of heads has an open, merged,
+// or closed PR. A closed backport PR means a human rejected it, so it
+// is not recreated.
+func (p *Pipeline) backportPRExists(repo models.RepoSettings, heads []string) (bool, error) {
+	lookups := []func(owner, repo, head string) (*models.PRDetails, error){
+		p.git.GetPRForBranch,
+		p.git.GetMergedPRForBranch,
+		p.git.GetClosedPRForBranch,
+	}
+	for _, head := range heads {
+		for _, lookup := range lookups {
+			pr, err := lookup(repo.Owner, repo.Repo, head)
+			if err != nil {
+				return false, fmt.Errorf("look up backport PR: %w", err)
+			}
+			if pr != nil {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// backportPRBody links the backport PR to the original PR and the
+// ticket, listing the files whose conflicts the AI resolved.
+func backportPRBody(ticketKey string, merged *models.PRDetails, target string, conflictFiles []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Backport of #%d to `%s`.\n\nRelated to %s", merged.Number, target, ticketKey)
+	if len(conflictFiles) > 0 {
+		b.WriteString("\n\n## Resolved Conflicts\n\nThe cherry-pick conflicted in these files; the conflicts were resolved by AI:\n")
+		for _, f := range conflictFiles {
+			fmt.Fprintf(&b, "\n- `%s`", f)
+		}
+	}
+	return b.String()
+}
+
+// unresolvedConflicts returns the files, among those that conflicted,
+// that still contain conflict markers. Files that no longer exist are
+// considered resolved.
+func unresolvedConflicts(dir string, files []string) []string {
+	var unresolved []string
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file))) // #nosec G304 -- path from git in the workspace
+		if err != nil {
+			continue
+		}
+		if hasConflictMarkers(data) {
+			unresolved = append(unresolved, file)
+		}
+	}
+	return unresolved
+}
+
+// hasConflictMarkers reports whether data contains a line that opens
+// or closes a conflict hunk.
+func hasConflictMarkers(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// handleBackportFailure posts an error comment when a backport fails.
+// The ticket's status and labels are left unchanged: its own PR is
+// already merged.
+func (p *Pipeline) handleBackportFailure(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	jobErr error,
+) {
+	if settings.DisableErrorComments {
+		return
+	}
+
+	comment := fmt.Sprintf("AI backport failed: %s", jobErr.Error())
+	if err := p.tracker.AddComment(ticketKey, comment); err != nil {
+		logger.Error("Failed to post error comment", zap.Error(err))
+	}
+}
@@ -0,0 +1,254 @@
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+)
+
+// withBackports labels the ticket for backports to release-4.17 and
+// release-4.16 and makes its PR #42 merged.
+func withBackports(d *testDeps) {
+	getWorkItem := d.tracker.GetWorkItemFunc
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		item, err := getWorkItem(key)
+		if err != nil {
+			return nil, err
+		}
+		item.Labels = []string{"backport-4.17", "backport-4.16"}
+		return item, nil
+	}
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(item models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(item)
+		if err != nil {
+			return nil, err
+		}
+		settings.Backport = models.Backport{LabelPrefix: "backport-"}
+		return settings, nil
+	}
+	d.git.GetMergedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		if head != "ai-bot/PROJ-1" {
+			return nil, nil
+		}
+		return &models.PRDetails{
+			Number:         42,
+			Title:          "PROJ-1: Fix a bug",
+			BaseBranch:     "main",
+			URL:            "https://github.com/org/repo/pull/42",
+			MergeCommitSHA: "merge123",
+		}, nil
+	}
+}
+
+func backportJob(ticketKey string) *jobmanager.Job {
+	return &jobmanager.Job{
+		ID:         "backport-job-1",
+		TicketKey:  ticketKey,
+		Type:       jobmanager.JobTypeBackport,
+		AttemptNum: 1,
+	}
+}
+
+func TestExecuteBackport_OpensPRPerReleaseBranch(t *testing.T) {
+	d := newTestDeps(t)
+	withBackports(d)
+
+	var branches []string
+	d.git.CreateBranchFunc = func(_, name, base string) error {
+		branches = append(branches, name+"<-"+base)
+		return nil
+	}
+	d.git.CherryPickPRFunc = func(_, _ string, prNumber int, sha string) ([]string, error) {
+		if prNumber != 42 || sha != "merge123" {
+			t.Errorf("CherryPickPR(#%d, %s), want #42 merge123", prNumber, sha)
+		}
+		return []string{}, nil
+	}
+	var commitBases []string
+	d.git.CommitChangesFunc = func(_, _, _, branch, _, _, base string, _ *models.Author, _ []string, _ bool) (string, error) {
+		commitBases = append(commitBases, branch+"->"+base)
+		return "abc123", nil
+	}
+	var prs []models.PRParams
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		prs = append(prs, params)
+		return &models.PR{Number: 50 + len(prs), URL: fmt.Sprintf("https://github.com/org/repo/pull/%d", 50+len(prs))}, nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+
+	result, err := d.pipeline(t).Execute(context.Background(), backportJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantBranches := []string{
+		"ai-bot/PROJ-1-backport-release-4.17<-release-4.17",
+		"ai-bot/PROJ-1-backport-release-4.16<-release-4.16",
+	}
+	if strings.Join(branches, ",") != strings.Join(wantBranches, ",") {
+		t.Errorf("branches = %v, want %v", branches, wantBranches)
+	}
+	if len(commitBases) != 2 || commitBases[0] != "ai-bot/PROJ-1-backport-release-4.17->release-4.17" {
+		t.Errorf("commits = %v", commitBases)
+	}
+	if len(prs) != 2 {
+		t.Fatalf("created %d PRs, want 2", len(prs))
+	}
+	if prs[0].Base != "release-4.17" || prs[0].Head != "ai-bot/PROJ-1-backport-release-4.17" {
+		t.Errorf("PR head/base = %s/%s", prs[0].Head, prs[0].Base)
+	}
+	if prs[0].Title != "[release-4.17] PROJ-1: Fix a bug" {
+		t.Errorf("PR title = %q", prs[0].Title)
+	}
+	if !strings.Contains(prs[0].Body, "Backport of #42") || !strings.Contains(prs[0].Body, "PROJ-1") {
+		t.Errorf("PR body should link the original PR and ticket, got %q", prs[0].Body)
+	}
+	if len(comments) != 2 || !strings.Contains(comments[0], "https://github.com/org/repo/pull/51") {
+		t.Errorf("ticket comments = %v, want one per backport PR", comments)
+	}
+	if result.PRNumber != 52 {
+		t.Errorf("result PR = %d, want the last backport PR", result.PRNumber)
+	}
+}
+
+func TestExecuteBackport_SkipsExistingBackportPRs(t *testing.T) {
+	d := newTestDeps(t)
+	withBackports(d)
+
+	merged := d.git.GetMergedPRForBranchFunc
+	d.git.GetMergedPRForBranchFunc = func(owner, repo, head string) (*models.PRDetails, error) {
+		if head == "ai-bot/PROJ-1-backport-release-4.17" {
+			return &models.PRDetails{Number: 51}, nil
+		}
+		return merged(owner, repo, head)
+	}
+	d.git.GetClosedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		if head == "ai-bot/PROJ-1-backport-release-4.16" {
+			return &models.PRDetails{Number: 52}, nil
+		}
+		return nil, nil
+	}
+	picked := false
+	d.git.CherryPickPRFunc = func(_, _ string, _ int, _ string) ([]string, error) {
+		picked = true
+		return []string{}, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), backportJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if picked {
+		t.Error("expected no cherry-pick when every release branch has a backport PR")
+	}
+}
+
+func TestExecuteBackport_ConflictResolvedByAI(t *testing.T) {
+	d := newTestDeps(t)
+	withBackports(d)
+
+	d.git.CherryPickPRFunc = func(dir, _ string, _ int, _ string) ([]string, error) {
+		if err := os.WriteFile(filepath.Join(dir, "file.go"), []byte("<<<<<<< HEAD\nold\n=======\nnew\n>>>>>>> change\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return []string{"file.go"}, fmt.Errorf("%w: conflicted files: [file.go]", services.ErrMergeConflict)
+	}
+	var taskTarget string
+	d.taskWriter.WriteBackportConflictTaskFunc = func(pr models.PRDetails, target string, files []string, _, _ string) error {
+		if taskTarget == "" {
+			taskTarget = target
+		}
+		return nil
+	}
+	d.containers.ExecFunc = func(_ context.Context, _ *container.Container, _ []string) (string, int, error) {
+		return "", 0, os.WriteFile(filepath.Join(d.wsDir, "file.go"), []byte("new\n"), 0o644)
+	}
+	var bodies []string
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		bodies = append(bodies, params.Body)
+		return &models.PR{Number: 51, URL: "https://github.com/org/repo/pull/51"}, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), backportJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if taskTarget != "release-4.17" {
+		t.Errorf("backport task target = %q, want release-4.17", taskTarget)
+	}
+	if len(bodies) != 2 || !strings.Contains(bodies[0], "- `file.go`") {
+		t.Errorf("PR body should list the resolved conflicts, got %v", bodies)
+	}
+}
+
+func TestExecuteBackport_UnresolvedConflictFails(t *testing.T) {
+	d := newTestDeps(t)
+	withBackports(d)
+
+	d.git.CherryPickPRFunc = func(dir, _ string, _ int, _ string) ([]string, error) {
+		if err := os.WriteFile(filepath.Join(dir, "file.go"), []byte("<<<<<<< HEAD\nold\n=======\nnew\n>>>>>>> change\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return []string{"file.go"}, fmt.Errorf("%w: conflicted files: [file.go]", services.ErrMergeConflict)
+	}
+	prCreated := false
+	d.git.CreatePRFunc = func(_ models.PRParams) (*models.PR, error) {
+		prCreated = true
+		return &models.PR{Number: 51}, nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comment = body
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), backportJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "could not resolve the conflicts in file.go") {
+		t.Fatalf("err = %v, want unresolved conflicts", err)
+	}
+	if prCreated {
+		t.Error("expected no PR with unresolved conflicts")
+	}
+	if !strings.Contains(comment, "AI backport failed") || !strings.Contains(comment, "release-4.16") {
+		t.Errorf("failure comment = %q, want both release branches reported", comment)
+	}
+}
+
+func TestExecuteBackport_NoMergedPR(t *testing.T) {
+	d := newTestDeps(t)
+	withBackports(d)
+	d.git.GetMergedPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+		return nil, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), backportJob("PROJ-1")); err == nil {
+		t.Fatal("expected an error when the ticket's PR is not merged")
+	}
+}
+
+func TestExecuteBackport_NoLabelsIsNoop(t *testing.T) {
+	d := newTestDeps(t)
+	withBackports(d)
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Labels: []string{"bug"}}, nil
+	}
+	d.workspaces.FindOrCreateFunc = func(_, _ string, _ []string) (string, bool, error) {
+		t.Error("expected no workspace without backport labels")
+		return d.wsDir, false, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), backportJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
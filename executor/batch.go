@@ -0,0 +1,301 @@
+package executor
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// batchMember is a ticket solved together with the job's ticket,
+// along with its own project settings (statuses differ by type).
+type batchMember struct {
+	item     models.WorkItem
+	settings *models.ProjectSettings
+}
+
+// collectBatch returns the tickets to solve in the same AI session
+// and PR as workItem. When workItem carries the project's batch label,
+// other labeled tickets linked to it or sharing its parent are
+// batched with it; when its parent (e.g., its epic) carries the
+// label, all of the parent's children are. Only tickets still in
+// their todo status, targeting the same repositories, and matching
+// the new-ticket scan filters are included.
+// Members are sorted by key. Lookup errors are logged and the
+// affected tickets left out.
+func (p *Pipeline) collectBatch(logger *zap.Logger, workItem *models.WorkItem, settings *models.ProjectSettings) []batchMember {
+	label := settings.BatchLabel
+	if label == "" {
+		return nil
+	}
+	parentBatched := false
+	if workItem.Parent != "" {
+		parent, err := p.tracker.GetWorkItem(workItem.Parent)
+		if err != nil {
+			logger.Warn("Failed to fetch parent ticket for batching",
+				zap.String("parent", workItem.Parent), zap.Error(err))
+		} else {
+			parentBatched = slices.Contains(parent.Labels, label)
+		}
+	}
+	if !parentBatched && !slices.Contains(workItem.Labels, label) {
+		return nil
+	}
+
+	seen := map[string]bool{workItem.Key: true}
+	var members []batchMember
+	add := func(item models.WorkItem, sibling bool) {
+		if seen[item.Key] || !(sibling && parentBatched) && !slices.Contains(item.Labels, label) {
+			return
+		}
+		seen[item.Key] = true
+		s, err := p.projects.ResolveProject(item)
+		if err != nil {
+			logger.Warn("Failed to resolve project for batched ticket",
+				zap.String("batched", item.Key), zap.Error(err))
+			return
+		}
+		if item.Status != s.TodoStatus || !sameRepos(s, settings) {
+			return
+		}
+		members = append(members, batchMember{item: item, settings: s})
+	}
+
+	for _, link := range workItem.Links {
+		if link.Resolved || seen[link.Key] {
+			continue
+		}
+		item, err := p.tracker.GetWorkItem(link.Key)
+		if err != nil {
+			logger.Warn("Failed to fetch linked ticket for batching",
+				zap.String("linked", link.Key), zap.Error(err))
+			continue
+		}
+		add(*item, false)
+	}
+	if workItem.Parent != "" {
+		criteria := models.SearchCriteria{Parent: workItem.Parent}
+		if !parentBatched {
+			criteria.Labels = []string{label}
+		}
+		siblings, err := p.tracker.SearchWorkItems(criteria)
+		if err != nil {
+			logger.Warn("Failed to search sibling tickets for batching",
+				zap.String("parent", workItem.Parent), zap.Error(err))
+		}
+		for _, item := range siblings {
+			add(item, true)
+		}
+	}
+
+	members = p.scannableMembers(logger, members)
+	slices.SortFunc(members, func(a, b batchMember) int { return compareKeys(a.item.Key, b.item.Key) })
+	return members
+}
+
+// scannableMembers returns the members the new-ticket scanner would
+// pick up itself: tickets the bot is a contributor on that pass their
+// project's sprint and fix version filters and are not dead-lettered.
+// Other tickets were never handed to the bot, and a batch led by one
+// of them would never be solved. Members are searched for once per
+// distinct set of filters; on a search error the affected members are
+// left out.
+func (p *Pipeline) scannableMembers(logger *zap.Logger, members []batchMember) []batchMember {
+	if len(members) == 0 {
+		return members
+	}
+	groups := make(map[string]models.SearchCriteria)
+	for _, m := range members {
+		group := fmt.Sprint(m.settings.ActiveSprintOnly, m.settings.FixVersions)
+		criteria, ok := groups[group]
+		if !ok {
+			criteria = models.SearchCriteria{
+				ContributorIsCurrentUser: true,
+				ActiveSprint:             m.settings.ActiveSprintOnly,
+				FixVersions:              m.settings.FixVersions,
+			}
+			if p.cfg.DeadLetterLabel != "" {
+				criteria.ExcludeLabels = []string{p.cfg.DeadLetterLabel}
+			}
+		}
+		criteria.Keys = append(criteria.Keys, m.item.Key)
+		groups[group] = criteria
+	}
+
+	found := make(map[string]bool)
+	for _, criteria := range groups {
+		items, err := p.tracker.SearchWorkItems(criteria)
+		if err != nil {
+			logger.Warn("Failed to check batched tickets against the scan filters, solving without them",
+				zap.Strings("batched", criteria.Keys), zap.Error(err))
+			continue
+		}
+		for _, item := range items {
+			found[item.Key] = true
+		}
+	}
+	return slices.DeleteFunc(members, func(m batchMember) bool {
+		if found[m.item.Key] {
+			return false
+		}
+		logger.Info("Linked ticket is not picked up by the bot, not batching it",
+			zap.String("batched", m.item.Key))
+		return true
+	})
+}
+
+// sameRepos reports whether two tickets' settings target the same
+// repositories and subdirectories, so that one PR per repository can
+// resolve both.
+func sameRepos(a, b *models.ProjectSettings) bool {
+	return slices.EqualFunc(a.Repos, b.Repos, func(x, y models.RepoSettings) bool {
+		return x.CloneURL == y.CloneURL && x.BaseBranch == y.BaseBranch && x.Subdirectory == y.Subdirectory
+	})
+}
+
+// compareKeys orders ticket keys by project, then numerically by
+// issue number, so that "PROJ-9" sorts before "PROJ-10".
+func compareKeys(a, b string) int {
+	ap, an, _ := strings.Cut(a, "-")
+	bp, bn, _ := strings.Cut(b, "-")
+	if c := cmp.Compare(ap, bp); c != 0 {
+		return c
+	}
+	ai, aErr := strconv.Atoi(an)
+	bi, bErr := strconv.Atoi(bn)
+	if aErr != nil || bErr != nil {
+		return cmp.Compare(an, bn)
+	}
+	return cmp.Compare(ai, bi)
+}
+
+// checkBatchLead defers the job when another ticket in its batch
+// leads it. The batch is solved by the job for its lowest-keyed
+// ticket, so that concurrent jobs for the same batch do not open
+// conflicting PRs.
+func (p *Pipeline) checkBatchLead(logger *zap.Logger, ticketKey string, members []batchMember) error {
+	if len(members) == 0 || compareKeys(ticketKey, members[0].item.Key) < 0 {
+		return nil
+	}
+	lead := members[0].item.Key
+	logger.Info("Ticket is batched with a lower-keyed ticket, deferring",
+		zap.String("lead", lead))
+	p.upsertStatusComment(logger, ticketKey, fmt.Sprintf(
+		"%s Batched: this ticket will be solved together with %s in a single pull request.",
+		statusCommentMarker, lead))
+	return fmt.Errorf("batched with %s: %w", lead, jobmanager.ErrDeferred)
+}
+
+// withoutBatchLinks returns workItem without its links to batch
+// members, so that members blocking each other do not defer the
+// batch they are solved in.
+func withoutBatchLinks(workItem *models.WorkItem, members []batchMember) *models.WorkItem {
+	if len(members) == 0 {
+		return workItem
+	}
+	filtered := *workItem
+	filtered.Links = slices.DeleteFunc(slices.Clone(workItem.Links), func(l models.IssueLink) bool {
+		return slices.ContainsFunc(members, func(m batchMember) bool { return m.item.Key == l.Key })
+	})
+	return &filtered
+}
+
+// combineBatch returns the lead work item with its members' summaries,
+// descriptions, and acceptance criteria appended to the description
+// and their attachments added, so that one AI session works on all of them. A security
+// level on any member carries over to the combined item.
+func combineBatch(lead *models.WorkItem, members []batchMember) *models.WorkItem {
+	if len(members) == 0 {
+		return lead
+	}
+	combined := *lead
+	combined.Attachments = slices.Clone(lead.Attachments)
+
+	var b strings.Builder
+	b.WriteString(lead.Description)
+	b.WriteString("\n\n## Batched Tickets\n\nThis change must also resolve the following related tickets.\n")
+	for _, m := range members {
+		fmt.Fprintf(&b, "\n### %s: %s\n", m.item.Key, m.item.Summary)
+		if m.item.Description != "" {
+			fmt.Fprintf(&b, "\n%s\n", m.item.Description)
+		}
+		if m.item.AcceptanceCriteria != "" {
+			fmt.Fprintf(&b, "\nAcceptance criteria:\n\n%s\n", m.item.AcceptanceCriteria)
+		}
+		combined.Attachments = append(combined.Attachments, m.item.Attachments...)
+		if combined.SecurityLevel == "" {
+			combined.SecurityLevel = m.item.SecurityLevel
+		}
+	}
+	combined.Description = b.String()
+	return &combined
+}
+
+// batchKeys returns the keys of the batch members.
+func batchKeys(members []batchMember) []string {
+	keys := make([]string, len(members))
+	for i, m := range members {
+		keys[i] = m.item.Key
+	}
+	return keys
+}
+
+// withBatchKeys appends the batch members' keys to a PR body.
+func withBatchKeys(body string, keys []string) string {
+	if len(keys) == 0 {
+		return body
+	}
+	return fmt.Sprintf("%s\n\nAlso resolves %s", body, strings.Join(keys, ", "))
+}
+
+// claimBatch moves the batch members to their in-progress status so
+// that the scanner does not pick them up separately. Members that
+// cannot be transitioned are dropped from the batch.
+func (p *Pipeline) claimBatch(logger *zap.Logger, members []batchMember) []batchMember {
+	claimed := members[:0:0]
+	for _, m := range members {
+		if err := p.tracker.TransitionStatus(m.item.Key, m.settings.InProgressStatus); err != nil {
+			logger.Warn("Failed to claim batched ticket, solving without it",
+				zap.String("batched", m.item.Key), zap.Error(err))
+			continue
+		}
+		claimed = append(claimed, m)
+	}
+	return claimed
+}
+
+// releaseBatch returns the batch members to their todo status after
+// the batch failed.
+func (p *Pipeline) releaseBatch(logger *zap.Logger, members []batchMember) {
+	for _, m := range members {
+		if err := p.tracker.TransitionStatus(m.item.Key, m.settings.TodoStatus); err != nil {
+			logger.Error("Failed to revert batched ticket status",
+				zap.String("batched", m.item.Key), zap.Error(err))
+		}
+	}
+}
+
+// finishBatch records the batch's PR on each member and moves it to
+// review alongside the lead ticket.
+func (p *Pipeline) finishBatch(logger *zap.Logger, leadKey, prURL string, members []batchMember) {
+	for _, m := range members {
+		p.setPRURL(logger, m.item.Key, m.settings, prURL)
+		if err := p.tracker.AddComment(m.item.Key, fmt.Sprintf(
+			"Solved together with %s in %s.", leadKey, prURL)); err != nil {
+			logger.Warn("Failed to comment on batched ticket", zap.Error(err))
+		}
+		p.cleanupStatusComment(logger, m.item.Key)
+		allLabels := models.AllPipelineLabels(m.settings.FailureLabels, m.settings.LifecycleLabels)
+		p.setPipelineLabel(logger, m.item.Key, allLabels, m.settings.LifecycleLabels.Review)
+		if err := p.tracker.TransitionStatus(m.item.Key, m.settings.InReviewStatus); err != nil {
+			logger.Warn("Failed to transition batched ticket to in-review",
+				zap.String("batched", m.item.Key), zap.Error(err))
+		}
+	}
+}
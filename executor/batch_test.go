@@ -0,0 +1,172 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// withBatch configures batching on the test project and serves a
+// linked pair of labeled tickets, PROJ-1 and PROJ-2, plus the
+// unlabeled PROJ-3 linked to PROJ-1. Searches by key find every
+// ticket except those in unscanned.
+func withBatch(d *testDeps, unscanned ...string) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.BatchLabel = "ai-batch"
+		}
+		return settings, err
+	}
+	items := map[string]*models.WorkItem{
+		"PROJ-1": {Key: "PROJ-1", Summary: "Fix login", Description: "Login fails.", Status: "To Do",
+			Labels: []string{"ai-batch"},
+			Links:  []models.IssueLink{{Key: "PROJ-2"}, {Key: "PROJ-3"}}},
+		"PROJ-2": {Key: "PROJ-2", Summary: "Fix logout", Description: "Logout fails.", Status: "To Do",
+			Labels: []string{"ai-batch"},
+			Links:  []models.IssueLink{{Key: "PROJ-1"}}},
+		"PROJ-3": {Key: "PROJ-3", Summary: "Unrelated", Status: "To Do",
+			Labels: []string{}},
+	}
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		item, ok := items[key]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return item, nil
+	}
+	d.tracker.SearchWorkItemsFunc = func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+		var found []models.WorkItem
+		for _, key := range criteria.Keys {
+			if item, ok := items[key]; ok && criteria.ContributorIsCurrentUser && !slices.Contains(unscanned, key) {
+				found = append(found, *item)
+			}
+		}
+		return found, nil
+	}
+}
+
+func TestExecuteNewTicket_BatchesLinkedTickets(t *testing.T) {
+	d := newTestDeps(t)
+	withBatch(d)
+	var description string
+	d.taskWriter.WriteIssueFunc = func(workItem models.WorkItem, _ string, _ []string, _ []models.Comment) error {
+		description = workItem.Description
+		return nil
+	}
+	var prBody string
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		prBody = params.Body
+		return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+	}
+	transitions := map[string][]string{}
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions[key] = append(transitions[key], status)
+		return nil
+	}
+	comments := map[string][]string{}
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comments[key] = append(comments[key], body)
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"Login fails.", "### PROJ-2: Fix logout", "Logout fails."} {
+		if !strings.Contains(description, want) {
+			t.Errorf("issue description missing %q:\n%s", want, description)
+		}
+	}
+	if strings.Contains(description, "PROJ-3") {
+		t.Errorf("unlabeled ticket was batched:\n%s", description)
+	}
+	if !strings.Contains(prBody, "Also resolves PROJ-2") {
+		t.Errorf("PR body should list batched tickets, got:\n%s", prBody)
+	}
+	if want := []string{"In Progress", "In Review"}; !slices.Equal(transitions["PROJ-2"], want) {
+		t.Errorf("PROJ-2 transitions = %v, want %v", transitions["PROJ-2"], want)
+	}
+	if !slices.ContainsFunc(comments["PROJ-2"], func(c string) bool {
+		return strings.Contains(c, "https://github.com/org/repo/pull/1")
+	}) {
+		t.Errorf("PROJ-2 comments = %q, want the PR URL", comments["PROJ-2"])
+	}
+	if len(transitions["PROJ-3"]) != 0 {
+		t.Errorf("PROJ-3 transitions = %v, want none", transitions["PROJ-3"])
+	}
+}
+
+func TestExecuteNewTicket_BatchReleasedOnFailure(t *testing.T) {
+	d := newTestDeps(t)
+	withBatch(d)
+	d.git.HasChangesFunc = func(dir, baseBranch string) (bool, error) {
+		return false, nil
+	}
+	transitions := map[string][]string{}
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions[key] = append(transitions[key], status)
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if want := []string{"In Progress", "To Do"}; !slices.Equal(transitions["PROJ-2"], want) {
+		t.Errorf("PROJ-2 transitions = %v, want %v", transitions["PROJ-2"], want)
+	}
+}
+
+func TestExecuteNewTicket_BatchMemberDefersToLead(t *testing.T) {
+	d := newTestDeps(t)
+	withBatch(d)
+	var statusComment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		statusComment = body
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-2"))
+	if !errors.Is(err, jobmanager.ErrDeferred) {
+		t.Fatalf("err = %v, want ErrDeferred", err)
+	}
+	if !strings.Contains(statusComment, "solved together with PROJ-1") {
+		t.Errorf("status comment = %q, want mention of PROJ-1", statusComment)
+	}
+}
+
+// A lower-keyed ticket the scanner never picks up (e.g., the bot is
+// not a contributor on it) neither leads the batch nor joins it.
+func TestExecuteNewTicket_BatchSkipsUnscannedTickets(t *testing.T) {
+	d := newTestDeps(t)
+	withBatch(d, "PROJ-1")
+	transitions := map[string][]string{}
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions[key] = append(transitions[key], status)
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transitions["PROJ-1"]) != 0 {
+		t.Errorf("PROJ-1 transitions = %v, want none", transitions["PROJ-1"])
+	}
+}
+
+func TestCompareKeys(t *testing.T) {
+	keys := []string{"PROJ-10", "ABC-3", "PROJ-9", "PROJ-100"}
+	slices.SortFunc(keys, executor.CompareKeys)
+	if want := []string{"ABC-3", "PROJ-9", "PROJ-10", "PROJ-100"}; !slices.Equal(keys, want) {
+		t.Errorf("sorted = %v, want %v", keys, want)
+	}
+}
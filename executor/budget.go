@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// checkProjectBudget defers the job when the project's AI spend has
+// reached its daily or monthly budget. Like the open PR limit, the
+// ticket stays in its todo status with a status comment explaining
+// the wait, and the scanner resubmits it until the budget resets.
+// Returns nil when no budget is configured or it is not spent.
+func (p *Pipeline) checkProjectBudget(logger *zap.Logger, ticketKey string, settings *models.ProjectSettings) error {
+	if p.cfg.ProjectCosts == nil || !settings.Budget.Enabled() {
+		return nil
+	}
+
+	daily, monthly := p.cfg.ProjectCosts.Spent(settings.BudgetProjects...)
+	budget := settings.Budget
+	var period string
+	var spent, limit float64
+	switch {
+	case budget.MonthlyUSD > 0 && monthly >= budget.MonthlyUSD:
+		period, spent, limit = "monthly", monthly, budget.MonthlyUSD
+	case budget.DailyUSD > 0 && daily >= budget.DailyUSD:
+		period, spent, limit = "daily", daily, budget.DailyUSD
+	default:
+		return nil
+	}
+
+	project := ticketProject(ticketKey)
+	p.cfg.ProjectCosts.RecordDeferral(project)
+	logger.Info("Project AI budget spent, deferring ticket",
+		zap.String("period", period),
+		zap.Float64("spent_usd", spent),
+		zap.Float64("budget_usd", limit))
+	p.upsertStatusComment(logger, ticketKey, formatBudgetComment(period, limit))
+	return fmt.Errorf("%s AI budget of $%.2f spent ($%.2f): %w",
+		period, limit, spent, jobmanager.ErrDeferred)
+}
+
+// recordProjectCost adds a job's AI cost to its ticket's project.
+func (p *Pipeline) recordProjectCost(ticketKey string, costUSD float64) {
+	if p.cfg.ProjectCosts == nil || costUSD <= 0 {
+		return
+	}
+	p.cfg.ProjectCosts.Record(ticketProject(ticketKey), costUSD)
+}
+
+// ticketProject returns the Jira project key of a ticket key
+// (e.g., "PROJ" for "PROJ-123").
+func ticketProject(ticketKey string) string {
+	project, _, _ := strings.Cut(ticketKey, "-")
+	return project
+}
+
+// formatBudgetComment builds the status comment posted while a
+// ticket waits for its project's budget to reset. The body depends
+// only on the period and budget so that repeated deferrals do not
+// rewrite the comment.
+func formatBudgetComment(period string, limit float64) string {
+	reset := "at 00:00 UTC"
+	if period == "monthly" {
+		reset = "on the 1st of next month (UTC)"
+	}
+	return fmt.Sprintf("%s Waiting to start: this project has spent its %s AI budget of $%.2f. "+
+		"This ticket stays queued and will be picked up automatically once the budget resets %s.",
+		statusCommentMarker, period, limit, reset)
+}
@@ -0,0 +1,135 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// withBudget wraps the default project resolver to set the project
+// budget, counted over the PROJ and OPS keys.
+func withBudget(d *testDeps, budget models.CostBudget) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err != nil {
+			return nil, err
+		}
+		settings.Budget = budget
+		settings.BudgetProjects = []string{"PROJ", "OPS"}
+		return settings, nil
+	}
+}
+
+func budgetConfig(costs executor.ProjectCosts) executor.Config {
+	return executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": "test-key"},
+		MaxRetries:      3,
+		ProjectCosts:    costs,
+	}
+}
+
+func TestExecuteNewTicket_ProjectBudgetSpentDefers(t *testing.T) {
+	tests := []struct {
+		name           string
+		daily, monthly float64
+		want           string
+	}{
+		{name: "daily", daily: 50, monthly: 60, want: "daily AI budget of $50.00"},
+		{name: "monthly", daily: 10, monthly: 500, want: "monthly AI budget of $500.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			withBudget(d, models.CostBudget{DailyUSD: 50, MonthlyUSD: 500})
+
+			var counted []string
+			var deferred []string
+			costs := &executortest.StubProjectCosts{
+				SpentFunc: func(projects ...string) (float64, float64) {
+					counted = projects
+					return tt.daily, tt.monthly
+				},
+				RecordDeferralFunc: func(project string) { deferred = append(deferred, project) },
+			}
+			var comments []string
+			d.tracker.AddCommentFunc = func(key, body string) error {
+				comments = append(comments, body)
+				return nil
+			}
+			var transitions []string
+			d.tracker.TransitionStatusFunc = func(key, status string) error {
+				transitions = append(transitions, status)
+				return nil
+			}
+
+			_, err := d.pipelineWithConfig(t, budgetConfig(costs)).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+			if !errors.Is(err, jobmanager.ErrDeferred) {
+				t.Fatalf("error = %v, want ErrDeferred", err)
+			}
+			if !slices.Equal(counted, []string{"PROJ", "OPS"}) {
+				t.Errorf("spend counted over %v, want [PROJ OPS]", counted)
+			}
+			if !slices.Equal(deferred, []string{"PROJ"}) {
+				t.Errorf("deferrals = %v, want [PROJ]", deferred)
+			}
+			if len(transitions) != 0 {
+				t.Errorf("status transitions = %v, want none", transitions)
+			}
+			if len(comments) != 1 || !strings.Contains(comments[0], "[AI-BOT-STATUS]") || !strings.Contains(comments[0], tt.want) {
+				t.Errorf("comments = %q, want one status comment containing %q", comments, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteNewTicket_ProjectBudgetRecordsCost(t *testing.T) {
+	d := newTestDeps(t)
+	withBudget(d, models.CostBudget{DailyUSD: 50})
+	d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+		writeSessionOutput(t, d.wsDir, executor.SessionOutput{ExitCode: 0, CostUSD: 1.25})
+		return "", 0, nil
+	}
+
+	var recorded []string
+	var amount float64
+	costs := &executortest.StubProjectCosts{
+		SpentFunc: func(...string) (float64, float64) { return 49.99, 49.99 },
+		RecordFunc: func(project string, usd float64) {
+			recorded = append(recorded, project)
+			amount += usd
+		},
+	}
+
+	if _, err := d.pipelineWithConfig(t, budgetConfig(costs)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(recorded, []string{"PROJ"}) || amount != 1.25 {
+		t.Errorf("recorded %v for %v, want $1.25 for [PROJ]", amount, recorded)
+	}
+}
+
+func TestExecuteNewTicket_NoProjectBudgetProceeds(t *testing.T) {
+	d := newTestDeps(t)
+	costs := &executortest.StubProjectCosts{
+		SpentFunc: func(...string) (float64, float64) {
+			t.Error("Spent called without a budget")
+			return 1000, 1000
+		},
+	}
+
+	if _, err := d.pipelineWithConfig(t, budgetConfig(costs)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+const (
+	// checkRunName is the name of the check run published on the
+	// bot's commits.
+	checkRunName = "AI summary"
+
+	// maxCheckRunOutput keeps a check run's summary and text under
+	// GitHub's 65535-character limit.
+	maxCheckRunOutput = 60000
+)
+
+// checkRunReport is what a check run tells reviewers about the AI
+// session behind a commit.
+type checkRunReport struct {
+	// label names the session, e.g. "New ticket" or "Feedback".
+	label string
+
+	// changes summarizes the change; summary is the fallback when
+	// the AI wrote no structured PR summary.
+	changes []string
+	summary string
+
+	// review is the self-review verdict, or nil when self-review did
+	// not run.
+	review *selfReview
+
+	session  SessionOutput
+	exitCode int
+	costUSD  float64
+
+	// redact leaves out the change summary, the review summary, and
+	// the commands (security-level tickets), since they may describe
+	// the vulnerability.
+	redact bool
+}
+
+// newTicketCheckRun collects what the check run of a new-ticket
+// change reports. costUSD is the cost of the job's sessions so far.
+func newTicketCheckRun(wsPath string, workItem *models.WorkItem, review *selfReview, session SessionOutput, exitCode int, costUSD float64) checkRunReport {
+	r := checkRunReport{
+		label:    "New ticket",
+		summary:  session.Summary,
+		review:   review,
+		session:  session,
+		exitCode: exitCode,
+		costUSD:  costUSD,
+		redact:   workItem.HasSecurityLevel(),
+	}
+	if s := readPRSummary(wsPath); s != nil {
+		r.changes = s.Changes
+	}
+	return r
+}
+
+// publishCheckRun publishes r as a check run on the commit sha when
+// the project has check runs enabled. Errors are logged and otherwise
+// ignored: the check run only mirrors what the PR already reports.
+func (p *Pipeline) publishCheckRun(logger *zap.Logger, settings *models.ProjectSettings, owner, repo, sha string, r checkRunReport) {
+	if !settings.CheckRun || sha == "" {
+		return
+	}
+	if err := p.git.CreateCheckRun(owner, repo, buildCheckRun(sha, r)); err != nil {
+		logger.Warn("Failed to publish check run",
+			zap.String("repo", owner+"/"+repo), zap.String("sha", sha), zap.Error(err))
+	}
+}
+
+// buildCheckRun renders r as a completed check run on sha. The
+// conclusion is success when the AI's validation passed and neutral
+// otherwise; it is never failure, so that the check run cannot block a
+// merge.
+func buildCheckRun(sha string, r checkRunReport) models.CheckRun {
+	passed := validationPassed(r.session, r.exitCode)
+	conclusion := "neutral"
+	if passed {
+		conclusion = "success"
+	}
+
+	title := []string{r.label}
+	if passed {
+		title = append(title, "validation passed")
+	} else {
+		title = append(title, "validation failed")
+	}
+	if r.review != nil {
+		title = append(title, "self-review "+reviewOutcome(r.review))
+	}
+	title = append(title, fmt.Sprintf("$%.2f", r.costUSD))
+
+	var b strings.Builder
+	b.WriteString("## Changes\n\n")
+	switch {
+	case r.redact:
+		b.WriteString("Details redacted due to security level.\n")
+	case len(r.changes) > 0:
+		for _, c := range r.changes {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	case strings.TrimSpace(r.summary) != "":
+		fmt.Fprintf(&b, "%s\n", strings.TrimSpace(r.summary))
+	default:
+		b.WriteString("The AI did not summarize its changes.\n")
+	}
+
+	b.WriteString("\n## Self-Review\n\n")
+	if r.review == nil {
+		b.WriteString("Self-review did not run.\n")
+	} else {
+		fmt.Fprintf(&b, "Verdict: **%s**\n", reviewOutcome(r.review))
+		if s := strings.TrimSpace(r.review.Summary); s != "" && !r.redact && r.review.Verdict != "" {
+			fmt.Fprintf(&b, "\n%s\n", s)
+		}
+	}
+
+	b.WriteString("\n## Validation\n\n")
+	switch {
+	case r.session.ValidationPassed == nil:
+		fmt.Fprintf(&b, "Not reported by the AI; the session exited with code %d.\n", r.exitCode)
+	case *r.session.ValidationPassed:
+		b.WriteString("The AI reported that its validation passed.\n")
+	default:
+		b.WriteString("The AI reported that its validation failed.\n")
+	}
+
+	fmt.Fprintf(&b, "\n## Cost\n\n$%.2f for the AI sessions of this job.\n", r.costUSD)
+
+	return models.CheckRun{
+		Name:       checkRunName,
+		HeadSHA:    sha,
+		Conclusion: conclusion,
+		Title:      strings.Join(title, " · "),
+		Summary:    truncateCheckRunOutput(b.String()),
+		Text:       truncateCheckRunOutput(formatCheckRunCommands(r.session.Commands, r.redact)),
+	}
+}
+
+// reviewOutcome describes a self-review verdict in a few words.
+func reviewOutcome(review *selfReview) string {
+	switch review.Verdict {
+	case verdictApprove:
+		return "approved"
+	case verdictAmend:
+		return "amended"
+	default:
+		return "incomplete"
+	}
+}
+
+// formatCheckRunCommands lists the commands the AI ran for a check
+// run's details.
+func formatCheckRunCommands(commands []string, redact bool) string {
+	var b strings.Builder
+	b.WriteString("## Commands Run by the AI\n\n")
+	switch {
+	case redact:
+		b.WriteString("Redacted due to security level.\n")
+	case len(commands) == 0:
+		b.WriteString("No commands were recorded for this session.\n")
+	default:
+		b.WriteString("```\n")
+		for _, c := range commands {
+			fmt.Fprintf(&b, "$ %s\n", strings.ReplaceAll(c, "```", "'''"))
+		}
+		b.WriteString("```\n")
+	}
+	return b.String()
+}
+
+// truncateCheckRunOutput cuts s to maxCheckRunOutput bytes, closing an
+// open code block.
+func truncateCheckRunOutput(s string) string {
+	if len(s) <= maxCheckRunOutput {
+		return s
+	}
+	s = strings.ToValidUTF8(s[:maxCheckRunOutput], "")
+	if strings.Count(s, "```")%2 == 1 {
+		s += "\n```"
+	}
+	return s + "\n\n[truncated]\n"
+}
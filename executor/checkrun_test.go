@@ -0,0 +1,135 @@
+package executor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+)
+
+// enableCheckRun enables check runs in the project settings and
+// returns the published check runs.
+func enableCheckRun(d *testDeps) *[]models.CheckRun {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.CheckRun = true
+		}
+		return settings, err
+	}
+	var runs []models.CheckRun
+	d.git.CreateCheckRunFunc = func(_, _ string, run models.CheckRun) error {
+		runs = append(runs, run)
+		return nil
+	}
+	return &runs
+}
+
+// commandRunner returns an agent runner that reports running cmd.
+func commandRunner(cmd string) *executortest.StubAgentRunner {
+	return &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			req.OnEvent(agent.Event{Kind: agent.EventToolCall, Tool: agent.ToolRunCommand, Detail: cmd})
+			req.OnEvent(agent.Event{Kind: agent.EventToolCall, Tool: "read_file", Detail: "main.go"})
+			return agent.Result{CostUSD: 0.75, Summary: "Fixed the nil check in the parser."}, nil
+		},
+	}
+}
+
+func TestExecuteNewTicket_PublishesCheckRun(t *testing.T) {
+	d := newTestDeps(t)
+	runs := enableCheckRun(d)
+
+	_, err := d.pipelineWithConfig(t, agentConfig(commandRunner("make test"))).
+		Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*runs) != 1 {
+		t.Fatalf("published %d check runs, want 1", len(*runs))
+	}
+	run := (*runs)[0]
+	if run.HeadSHA != "abc123" || run.Conclusion != "success" {
+		t.Errorf("check run on %q with conclusion %q, want abc123 and success", run.HeadSHA, run.Conclusion)
+	}
+	if !strings.Contains(run.Title, "validation passed") || !strings.Contains(run.Title, "$0.75") {
+		t.Errorf("Title = %q, want the validation result and cost", run.Title)
+	}
+	for _, want := range []string{"Fixed the nil check in the parser.", "Self-review did not run.", "$0.75"} {
+		if !strings.Contains(run.Summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, run.Summary)
+		}
+	}
+	if !strings.Contains(run.Text, "$ make test") || strings.Contains(run.Text, "main.go") {
+		t.Errorf("Text = %q, want only the command the AI ran", run.Text)
+	}
+}
+
+func TestExecuteNewTicket_CheckRunWithSelfReview(t *testing.T) {
+	d := newTestDeps(t)
+	enableSelfReview(d)
+	runs := enableCheckRun(d)
+	sessions := 0
+	runner := reviewRunner(t, &sessions, `{"verdict": "amend", "summary": "Added the missing test."}`)
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*runs) != 1 {
+		t.Fatalf("published %d check runs, want 1", len(*runs))
+	}
+	run := (*runs)[0]
+	if !strings.Contains(run.Title, "self-review amended") ||
+		!strings.Contains(run.Summary, "Verdict: **amended**\n\nAdded the missing test.") {
+		t.Errorf("check run missing the self-review verdict:\n%s\n%s", run.Title, run.Summary)
+	}
+}
+
+func TestExecuteNewTicket_CheckRunRedactsSecurityTickets(t *testing.T) {
+	d := newTestDeps(t)
+	getWorkItem := d.tracker.GetWorkItemFunc
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		item, err := getWorkItem(key)
+		if err == nil {
+			item.SecurityLevel = "Embargoed"
+		}
+		return item, err
+	}
+	runs := enableCheckRun(d)
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(commandRunner("curl exploit"))).
+		Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*runs) != 1 {
+		t.Fatalf("published %d check runs, want 1", len(*runs))
+	}
+	run := (*runs)[0]
+	if strings.Contains(run.Summary, "nil check") || strings.Contains(run.Text, "curl exploit") {
+		t.Errorf("check run leaks details of a security ticket:\n%s\n%s", run.Summary, run.Text)
+	}
+}
+
+func TestExecuteNewTicket_NoCheckRunWhenDisabled(t *testing.T) {
+	d := newTestDeps(t)
+	published := false
+	d.git.CreateCheckRunFunc = func(string, string, models.CheckRun) error {
+		published = true
+		return nil
+	}
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(commandRunner("make test"))).
+		Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published {
+		t.Error("check run published with check runs disabled")
+	}
+}
@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// readQuestions returns the clarifying questions the AI wrote to
+// [taskfile.NeedsInfoPath] in dir. Returns nil if the file does not
+// exist, is malformed, or lists no questions.
+func readQuestions(dir string) []string {
+	data, err := os.ReadFile(filepath.Join(dir, taskfile.NeedsInfoPath)) // #nosec G304 -- path is dir + constant
+	if err != nil {
+		return nil
+	}
+	var needsInfo struct {
+		Questions []string `json:"questions"`
+	}
+	if err := json.Unmarshal(data, &needsInfo); err != nil {
+		return nil
+	}
+	return nonEmpty(needsInfo.Questions)
+}
+
+// askForInfo posts the AI's clarifying questions on the ticket and
+// moves it to the waiting-for-info status, from which the
+// clarification scanner resubmits it once someone replies. Without a
+// waiting-for-info status, a [noChangesError] quoting the questions is
+// returned, so that the ticket is escalated to a human instead.
+func (p *Pipeline) askForInfo(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	questions []string,
+) error {
+	var list strings.Builder
+	for i, q := range questions {
+		fmt.Fprintf(&list, "%d. %s\n", i+1, q)
+	}
+	if settings.WaitingForInfoStatus == "" {
+		return &noChangesError{
+			msg:      "AI needs more information to solve the ticket",
+			analysis: "The AI needs answers to these questions:\n" + list.String(),
+		}
+	}
+
+	body := fmt.Sprintf("%s The AI needs more information to work on this ticket:\n\n%s\n"+
+		"Please answer in a comment on this ticket. The bot picks the ticket up again once you reply.",
+		models.ClarificationMarker, list.String())
+	if err := p.tracker.AddComment(ticketKey, body); err != nil {
+		return fmt.Errorf("post clarifying questions: %w", err)
+	}
+	if err := p.tracker.TransitionStatus(ticketKey, settings.WaitingForInfoStatus); err != nil {
+		return fmt.Errorf("transition to waiting for info: %w", err)
+	}
+
+	p.cleanupStatusComment(logger, ticketKey)
+	p.clearFailureLabels(logger, ticketKey, settings.FailureLabels)
+	logger.Info("Asked for more information",
+		zap.Int("questions", len(questions)),
+		zap.String("status", settings.WaitingForInfoStatus))
+	return nil
+}
@@ -0,0 +1,84 @@
+package executor_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestExecuteNewTicket_AsksForInfo(t *testing.T) {
+	tests := []struct {
+		name          string
+		waitingStatus string
+		wantStatus    string
+		wantErr       bool
+	}{
+		{"waiting status configured", "Waiting for Info", "Waiting for Info", false},
+		{"no waiting status", "", "To Do", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			resolve := d.projects.ResolveProjectFunc
+			d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+				settings, err := resolve(workItem)
+				if err == nil {
+					settings.WaitingForInfoStatus = tt.waitingStatus
+				}
+				return settings, err
+			}
+			d.git.HasChangesFunc = func(dir, baseBranch string) (bool, error) {
+				return false, nil
+			}
+			sessions := 0
+			d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+				sessions++
+				writeSessionFile(t, d, taskfile.NeedsInfoPath,
+					`{"questions": ["Should expired tokens be refreshed or rejected?", " "]}`)
+				return "", 0, nil
+			}
+			var comments []string
+			d.tracker.AddCommentFunc = func(key, body string) error {
+				comments = append(comments, body)
+				return nil
+			}
+			var statuses []string
+			d.tracker.TransitionStatusFunc = func(key, status string) error {
+				statuses = append(statuses, status)
+				return nil
+			}
+
+			_, err := d.pipelineWithConfig(t, executor.Config{
+				BotUsername:     "ai-bot",
+				DefaultProvider: "claude",
+				AIAPIKeys:       map[string]string{"claude": "test-key"},
+				MaxRetries:      3,
+				MaxAIRetries:    2,
+			}).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if sessions != 1 {
+				t.Errorf("ran %d AI sessions, want 1 (questions are not retried)", sessions)
+			}
+			if last := statuses[len(statuses)-1]; last != tt.wantStatus {
+				t.Errorf("transitions = %v, want the ticket to end in %q", statuses, tt.wantStatus)
+			}
+			want := "1. Should expired tokens be refreshed or rejected?\n"
+			if !slices.ContainsFunc(comments, func(c string) bool { return strings.Contains(c, want) }) {
+				t.Errorf("comments = %q, want the questions listed", comments)
+			}
+			asked := slices.ContainsFunc(comments, func(c string) bool { return strings.Contains(c, models.ClarificationMarker) })
+			if asked == tt.wantErr {
+				t.Errorf("questions comment posted = %v, want %v", asked, !tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// settleClosedPRs handles a feedback job whose ticket no longer has an
+// open PR in any of repos. A PR merged or closed after the scan that
+// queued the job leaves nothing to address; cloning and running the AI
+// would only push to a dead branch. Instead the ticket gets what the
+// feedback scanner would give it on its next cycle: the merged label
+// and the merged status when a PR was merged, otherwise the rejected
+// label for a PR closed without merging. Returns false when no repo
+// has a merged or closed PR either, so that the caller reports the
+// missing PR.
+func (p *Pipeline) settleClosedPRs(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	repos []models.RepoSettings,
+	heads []string,
+) (bool, error) {
+	var merged, closed *models.PRDetails
+	for _, repo := range repos {
+		for _, head := range heads {
+			pr, err := p.git.GetMergedPRForBranch(repo.Owner, repo.Repo, head)
+			if err != nil {
+				return false, fmt.Errorf("look up merged PR: %w", err)
+			}
+			if pr != nil && merged == nil {
+				merged = pr
+			}
+			pr, err = p.git.GetClosedPRForBranch(repo.Owner, repo.Repo, head)
+			if err != nil {
+				return false, fmt.Errorf("look up closed PR: %w", err)
+			}
+			if pr != nil && closed == nil {
+				closed = pr
+			}
+		}
+	}
+
+	allLabels := models.AllPipelineLabels(settings.FailureLabels, settings.LifecycleLabels)
+	switch {
+	case merged != nil:
+		logger.Info("PR already merged, skipping feedback",
+			zap.String("pr_url", merged.URL))
+		p.setPipelineLabel(logger, ticketKey, allLabels, settings.LifecycleLabels.Merged)
+		if settings.MergedStatus != "" {
+			if err := p.tracker.TransitionStatus(ticketKey, settings.MergedStatus); err != nil {
+				logger.Warn("Failed to transition to merged status",
+					zap.String("status", settings.MergedStatus), zap.Error(err))
+			}
+		}
+	case closed != nil:
+		logger.Info("PR closed without merge, skipping feedback",
+			zap.String("pr_url", closed.URL))
+		p.setPipelineLabel(logger, ticketKey, allLabels, settings.FailureLabels.Rejected)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// prepareCompanionRepos clones the project's companion repositories
+// into the workspace and describes them at the end of the task file.
+// Companions already present (workspace reuse) are not cloned again.
+// A companion that fails to clone is logged and left out; the AI
+// works without it.
+func (p *Pipeline) prepareCompanionRepos(logger *zap.Logger, wsPath string, settings *models.ProjectSettings) {
+	if len(settings.CompanionRepos) == 0 {
+		return
+	}
+
+	var present []models.CompanionRepo
+	for _, repo := range settings.CompanionRepos {
+		rel := path.Join(taskfile.CompanionsDirPath, repo.Name)
+		destDir := filepath.Join(wsPath, filepath.FromSlash(rel))
+		_, err := os.Stat(destDir)
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Info("Cloning companion repository",
+				zap.String("repo", repo.URL),
+				zap.String("path", rel),
+				zap.String("ref", repo.Ref))
+			err = p.git.CloneImport(repo.URL, destDir, repo.Ref)
+		}
+		if err != nil {
+			logger.Warn("Failed to prepare companion repository, continuing without it",
+				zap.String("companion", repo.Name), zap.Error(err))
+			continue
+		}
+		present = append(present, repo)
+	}
+	if len(present) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("\n## Companion Repositories\n\n")
+	b.WriteString("These related repositories are checked out for reference. Read them\n")
+	b.WriteString("as needed, but do not change them: they are not part of this change\n")
+	b.WriteString("and anything written there is discarded.\n\n")
+	for _, repo := range present {
+		fmt.Fprintf(&b, "- `%s/%s/`", taskfile.CompanionsDirPath, repo.Name)
+		if desc := strings.TrimSpace(repo.Description); desc != "" {
+			fmt.Fprintf(&b, ": %s", desc)
+		}
+		b.WriteString("\n")
+	}
+	if err := appendToTaskFile(wsPath, b.String()); err != nil {
+		logger.Warn("Failed to add companion repositories to task file", zap.Error(err))
+	}
+}
@@ -0,0 +1,106 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// withCompanions adds companion repositories to the project settings.
+func withCompanions(d *testDeps, repos ...models.CompanionRepo) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.CompanionRepos = repos
+		}
+		return settings, err
+	}
+}
+
+// taskCapturingRunner returns an agent runner that stores the task
+// file of the first session in task.
+func taskCapturingRunner(task *string) *executortest.StubAgentRunner {
+	return &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			if *task == "" {
+				data, _ := os.ReadFile(filepath.Join(req.Dir, taskfile.TaskFilePath))
+				*task = string(data)
+			}
+			return agent.Result{}, nil
+		},
+	}
+}
+
+func TestExecuteNewTicket_ClonesCompanionRepos(t *testing.T) {
+	d := newTestDeps(t)
+	withCompanions(d,
+		models.CompanionRepo{Name: "e2e", URL: "https://github.com/org/e2e", Ref: "main", Description: "End-to-end tests."},
+		models.CompanionRepo{Name: "docs", URL: "https://github.com/org/docs"},
+		models.CompanionRepo{Name: "broken", URL: "https://github.com/org/broken"},
+	)
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir, _, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	// "docs" is left over from an earlier job.
+	if err := os.MkdirAll(filepath.Join(d.wsDir, taskfile.CompanionsDirPath, "docs"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	cloned := map[string]string{}
+	d.git.CloneImportFunc = func(url, destDir, ref string) error {
+		if strings.HasSuffix(url, "/broken") {
+			return errors.New("repository not found")
+		}
+		cloned[destDir] = url + "@" + ref
+		return nil
+	}
+	var task string
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(taskCapturingRunner(&task))).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e2eDir := filepath.Join(d.wsDir, taskfile.CompanionsDirPath, "e2e")
+	if len(cloned) != 1 || cloned[e2eDir] != "https://github.com/org/e2e@main" {
+		t.Errorf("cloned %v, want only e2e at main into %s", cloned, e2eDir)
+	}
+	for _, want := range []string{
+		"## Companion Repositories",
+		"- `.ai-session/companions/e2e/`: End-to-end tests.",
+		"- `.ai-session/companions/docs/`\n",
+	} {
+		if !strings.Contains(task, want) {
+			t.Errorf("task file missing %q:\n%s", want, task)
+		}
+	}
+	if strings.Contains(task, "broken") {
+		t.Errorf("task file lists a companion that failed to clone:\n%s", task)
+	}
+}
+
+func TestExecuteFeedback_ListsCompanionRepos(t *testing.T) {
+	d := newFeedbackDeps(t)
+	withCompanions(d, models.CompanionRepo{Name: "e2e", URL: "https://github.com/org/e2e"})
+	d.taskWriter.WriteFeedbackTaskFunc = func(_ models.PRDetails, _, _ []models.PRComment, _ []models.CheckRunFailure, dir, _, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	var task string
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(taskCapturingRunner(&task))).Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(task, "- `.ai-session/companions/e2e/`") {
+		t.Errorf("task file missing companion:\n%s", task)
+	}
+}
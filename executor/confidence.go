@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// maxProposedDiffBytes caps the diff posted to the ticket when a
+// change is held back for review, keeping the comment well under
+// Jira's comment size limit.
+const maxProposedDiffBytes = 20 * 1024
+
+// checkConfidence holds back a change whose AI-reported confidence is
+// below settings.MinConfidence. The change's diff is returned in a
+// [rejectedChangeError], so that it is posted to the ticket for a
+// human to review instead of being committed. A change without a
+// reported confidence passes.
+func (p *Pipeline) checkConfidence(logger *zap.Logger, wsPath string, settings *models.ProjectSettings) error {
+	summary := readPRSummary(wsPath)
+	if summary == nil || summary.Confidence == nil {
+		logger.Info("AI reported no confidence; not gating the change")
+		return nil
+	}
+	confidence := *summary.Confidence
+	if confidence >= settings.MinConfidence {
+		logger.Info("AI confidence meets the threshold",
+			zap.Int("confidence", confidence), zap.Int("min_confidence", settings.MinConfidence))
+		return nil
+	}
+
+	diff, err := p.proposedDiff(wsPath, settings)
+	if err != nil {
+		return err
+	}
+
+	logger.Warn("AI confidence below threshold; posting the change for review",
+		zap.Int("confidence", confidence), zap.Int("min_confidence", settings.MinConfidence))
+	details := fmt.Sprintf("The AI rated its confidence in the change %d/100, below the project's threshold of %d.",
+		confidence, settings.MinConfidence)
+	if notes := strings.TrimSpace(summary.RiskNotes); notes != "" {
+		details += "\n\nAI notes: " + notes
+	}
+	if diff != "" {
+		details += "\n\nProposed change:\n\n" + diff
+	}
+	return &rejectedChangeError{
+		msg:     fmt.Sprintf("AI confidence %d is below the threshold of %d", confidence, settings.MinConfidence),
+		reason:  "Low-confidence change held for review",
+		details: details,
+	}
+}
+
+// proposedDiff renders the uncommitted changes in the workspace for a
+// ticket comment: each repository's diff against its base branch in a
+// fenced block, truncated so that together they stay within
+// maxProposedDiffBytes. Returns "" when nothing changed.
+func (p *Pipeline) proposedDiff(wsPath string, settings *models.ProjectSettings) (string, error) {
+	// Repositories share the size cap.
+	limit := maxProposedDiffBytes / len(settings.Repos)
+	var diffs []string
+	for _, repo := range settings.Repos {
+		repoDir := wsPath
+		if settings.IsMultiRepo() {
+			repoDir = filepath.Join(wsPath, repo.Name)
+		}
+		diff, err := p.git.Diff(repoDir, repo.BaseBranch)
+		if err != nil {
+			return "", fmt.Errorf("diff changes for %s: %w", repo.Name, err)
+		}
+		if diff = strings.TrimSpace(diff); diff == "" {
+			continue
+		}
+		diff = truncateDiff(diff, limit)
+		if settings.IsMultiRepo() {
+			diff = fmt.Sprintf("%s:\n\n```diff\n%s\n```", repo.Name, diff)
+		} else {
+			diff = "```diff\n" + diff + "\n```"
+		}
+		diffs = append(diffs, diff)
+	}
+	return strings.Join(diffs, "\n\n"), nil
+}
+
+// truncateDiff trims diff to at most limit bytes, cutting at a line
+// boundary.
+func truncateDiff(diff string, limit int) string {
+	if len(diff) <= limit {
+		return diff
+	}
+	cut := strings.ToValidUTF8(diff[:limit], "")
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "\n... (diff truncated)"
+}
@@ -0,0 +1,75 @@
+package executor_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestExecuteNewTicket_ConfidenceGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		blocked bool
+	}{
+		{"below threshold", `{"changes": ["Fixed it"], "confidence": 40, "risk_notes": "Unsure about the cache path."}`, true},
+		{"at threshold", `{"changes": ["Fixed it"], "confidence": 70}`, false},
+		{"not reported", `{"changes": ["Fixed it"]}`, false},
+		{"out of range", `{"changes": ["Fixed it"], "confidence": 140}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			resolve := d.projects.ResolveProjectFunc
+			d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+				settings, err := resolve(workItem)
+				if err == nil {
+					settings.MinConfidence = 70
+				}
+				return settings, err
+			}
+			writeSessionFile(t, d, taskfile.PRSummaryPath, tt.summary)
+			d.git.DiffFunc = func(dir, baseBranch string) (string, error) {
+				return "diff --git a/cache.go b/cache.go\n+evict()\n", nil
+			}
+			prCreated := false
+			d.git.CreatePRFunc = func(models.PRParams) (*models.PR, error) {
+				prCreated = true
+				return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+			}
+			var comment string
+			d.tracker.AddCommentFunc = func(key, body string) error {
+				comment = body
+				return nil
+			}
+
+			_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+			if !tt.blocked {
+				if err != nil || !prCreated {
+					t.Fatalf("err = %v, PR created = %v; want a PR", err, prCreated)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "below the threshold") {
+				t.Fatalf("err = %v, want a low-confidence error", err)
+			}
+			if prCreated {
+				t.Error("PR created for a low-confidence change")
+			}
+			for _, want := range []string{
+				"Low-confidence change held for review",
+				"40/100, below the project's threshold of 70",
+				"AI notes: Unsure about the cache path.",
+				"```diff\ndiff --git a/cache.go b/cache.go\n+evict()\n```",
+			} {
+				if !strings.Contains(comment, want) {
+					t.Errorf("comment missing %q, got:\n%s", want, comment)
+				}
+			}
+		})
+	}
+}
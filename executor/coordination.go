@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// coordinatePRs ties together the pull requests one ticket opened in
+// several repositories: each PR body gets a section linking its
+// siblings in the workspace's merge order, and PRs that must wait for
+// an earlier one get the do-not-merge label (the feedback scanner
+// removes it once their predecessors merge). Errors are logged; the
+// PRs stay open either way.
+func (p *Pipeline) coordinatePRs(logger *zap.Logger, settings *models.ProjectSettings, prs []repoPR) {
+	if len(prs) < 2 {
+		return
+	}
+	ordered := mergeOrdered(settings, prs)
+
+	for _, pr := range prs {
+		body := pr.body + relatedPRsSection(settings, ordered, pr)
+		if err := p.git.UpdatePRBody(pr.owner, pr.repo, pr.number, body); err != nil {
+			logger.Warn("Failed to link sibling PRs",
+				zap.String("repo", pr.name), zap.Int("pr", pr.number), zap.Error(err))
+		}
+	}
+
+	if settings.DoNotMergeLabel == "" {
+		return
+	}
+	for _, pr := range ordered {
+		if !waitsForEarlierPR(settings, prs, pr) {
+			continue
+		}
+		if err := p.git.AddPRLabel(pr.owner, pr.repo, pr.number, settings.DoNotMergeLabel); err != nil {
+			logger.Warn("Failed to add do-not-merge label",
+				zap.String("repo", pr.name), zap.Int("pr", pr.number), zap.Error(err))
+			continue
+		}
+		logger.Info("PR held until earlier PRs merge",
+			zap.String("repo", pr.name), zap.Int("pr", pr.number),
+			zap.String("label", settings.DoNotMergeLabel))
+	}
+}
+
+// mergeOrdered returns prs sorted by merge order: repositories listed
+// in the merge order first, in that order, then the rest in workspace
+// order.
+func mergeOrdered(settings *models.ProjectSettings, prs []repoPR) []repoPR {
+	ordered := slices.Clone(prs)
+	slices.SortStableFunc(ordered, func(a, b repoPR) int {
+		return cmp.Compare(unlistedLast(settings.MergeRank(a.name)), unlistedLast(settings.MergeRank(b.name)))
+	})
+	return ordered
+}
+
+func unlistedLast(rank int) int {
+	if rank < 0 {
+		return math.MaxInt
+	}
+	return rank
+}
+
+// waitsForEarlierPR reports whether pr comes after another of prs in
+// the merge order.
+func waitsForEarlierPR(settings *models.ProjectSettings, prs []repoPR, pr repoPR) bool {
+	rank := settings.MergeRank(pr.name)
+	if rank <= 0 {
+		return false
+	}
+	for _, other := range prs {
+		if r := settings.MergeRank(other.name); r >= 0 && r < rank {
+			return true
+		}
+	}
+	return false
+}
+
+// relatedPRsSection renders the PR body section listing the ticket's
+// pull requests, ordered by mergeOrdered, from the point of view of
+// self.
+func relatedPRsSection(settings *models.ProjectSettings, ordered []repoPR, self repoPR) string {
+	var inOrder, anyTime []repoPR
+	for _, pr := range ordered {
+		if settings.MergeRank(pr.name) >= 0 {
+			inOrder = append(inOrder, pr)
+		} else {
+			anyTime = append(anyTime, pr)
+		}
+	}
+	entry := func(pr repoPR) string {
+		s := fmt.Sprintf("%s/%s#%d", pr.owner, pr.repo, pr.number)
+		if pr.number == self.number && pr.owner == self.owner && pr.repo == self.repo {
+			s += " (this pull request)"
+		}
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n## Related Pull Requests\n\n")
+	b.WriteString("This change spans several repositories.")
+	if len(inOrder) > 1 {
+		b.WriteString(" Merge these pull requests in this order:\n\n")
+		for i, pr := range inOrder {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, entry(pr))
+		}
+		if len(anyTime) > 0 {
+			b.WriteString("\nThese can be merged at any time:\n\n")
+		}
+	} else {
+		anyTime = ordered
+		b.WriteString(" Its pull requests can be merged in any order:\n\n")
+	}
+	for _, pr := range anyTime {
+		fmt.Fprintf(&b, "- %s\n", entry(pr))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
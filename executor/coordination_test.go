@@ -0,0 +1,128 @@
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+// withMergeOrder sets the workspace merge order and do-not-merge label.
+func withMergeOrder(d *testDeps, label string, order ...string) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.MergeOrder = order
+			settings.DoNotMergeLabel = label
+		}
+		return settings, err
+	}
+}
+
+// recordPRCoordination numbers each repo's PR after its name's last
+// letter (svc-a → 1) and records the updated PR bodies and labels
+// added, keyed by repo.
+func recordPRCoordination(d *testDeps) (bodies map[string]string, labels map[string][]string) {
+	bodies = map[string]string{}
+	labels = map[string][]string{}
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		number := int(params.Repo[len(params.Repo)-1]-'a') + 1
+		return &models.PR{
+			Number: number,
+			URL:    fmt.Sprintf("https://github.com/%s/%s/pull/%d", params.Owner, params.Repo, number),
+		}, nil
+	}
+	d.git.UpdatePRBodyFunc = func(_, repo string, _ int, body string) error {
+		bodies[repo] = body
+		return nil
+	}
+	d.git.AddPRLabelFunc = func(_, repo string, _ int, label string) error {
+		labels[repo] = append(labels[repo], label)
+		return nil
+	}
+	return bodies, labels
+}
+
+func TestMultiRepoNewTicket_MergeOrder_LinksAndHoldsPRs(t *testing.T) {
+	d := newMultiRepoTestDeps(t)
+	withMergeOrder(d, "do-not-merge", "svc-b", "svc-a")
+	bodies, labels := recordPRCoordination(d)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("updated %d PR bodies, want 3", len(bodies))
+	}
+	want := "Merge these pull requests in this order:\n\n" +
+		"1. org/svc-b#2\n" +
+		"2. org/svc-a#1 (this pull request)\n\n" +
+		"These can be merged at any time:\n\n" +
+		"- org/svc-c#3"
+	if !strings.Contains(bodies["svc-a"], want) {
+		t.Errorf("svc-a body = %q, want it to contain %q", bodies["svc-a"], want)
+	}
+	if !strings.Contains(bodies["svc-c"], "- org/svc-c#3 (this pull request)") {
+		t.Errorf("svc-c body = %q, want it to mark its own PR", bodies["svc-c"])
+	}
+
+	var held []string
+	for repo, added := range labels {
+		for _, label := range added {
+			if label == "do-not-merge" {
+				held = append(held, repo)
+			}
+		}
+	}
+	if len(held) != 1 || held[0] != "svc-a" {
+		t.Errorf("do-not-merge added to %v, want [svc-a]", held)
+	}
+}
+
+func TestMultiRepoNewTicket_NoMergeOrder_LinksPRsOnly(t *testing.T) {
+	d := newMultiRepoTestDeps(t)
+	bodies, labels := recordPRCoordination(d)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "can be merged in any order:\n\n" +
+		"- org/svc-a#1\n" +
+		"- org/svc-b#2 (this pull request)\n" +
+		"- org/svc-c#3"
+	if !strings.Contains(bodies["svc-b"], want) {
+		t.Errorf("svc-b body = %q, want it to contain %q", bodies["svc-b"], want)
+	}
+	for repo, added := range labels {
+		for _, label := range added {
+			if label == "do-not-merge" {
+				t.Errorf("unexpected do-not-merge label on %s", repo)
+			}
+		}
+	}
+}
+
+func TestMultiRepoNewTicket_SinglePR_NotLinked(t *testing.T) {
+	d := newMultiRepoTestDeps(t)
+	withMergeOrder(d, "do-not-merge", "svc-a", "svc-b")
+	d.git.HasChangesFunc = func(dir, _ string) (bool, error) {
+		return strings.HasSuffix(dir, "svc-b"), nil
+	}
+	bodies, labels := recordPRCoordination(d)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(bodies) != 0 {
+		t.Errorf("updated PR bodies %v, want none", bodies)
+	}
+	if len(labels["svc-b"]) != 0 {
+		t.Errorf("labels on svc-b = %v, want none", labels["svc-b"])
+	}
+}
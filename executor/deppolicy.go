@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/deppolicy"
+	"jira-ai-issue-solver/models"
+)
+
+// checkDependencyPolicy checks the dependencies the AI added to each
+// repository's manifests against the project's dependency policy.
+// When any is rejected, a [rejectedChangeError] listing the violations
+// is returned so that the change is not committed. Failures to read
+// or parse a manifest are returned as ordinary errors.
+func (p *Pipeline) checkDependencyPolicy(
+	ctx context.Context,
+	logger *zap.Logger,
+	wsPath string,
+	settings *models.ProjectSettings,
+) error {
+	var added []deppolicy.Dependency
+	for _, repo := range settings.Repos {
+		repoDir, prefix := wsPath, ""
+		if settings.IsMultiRepo() {
+			repoDir = filepath.Join(wsPath, repo.Name)
+			prefix = repo.Name + "/"
+		}
+
+		files, err := p.git.ChangedFiles(repoDir, repo.BaseBranch)
+		if err != nil {
+			return fmt.Errorf("list changed files for %s: %w", repo.Name, err)
+		}
+		for _, file := range files {
+			if !deppolicy.IsManifest(file) {
+				continue
+			}
+			head, err := os.ReadFile(filepath.Join(repoDir, filepath.FromSlash(file))) // #nosec G304 -- path from git in the workspace
+			if err != nil {
+				return fmt.Errorf("read %s%s: %w", prefix, file, err)
+			}
+			base, _, err := p.git.BaseFile(repoDir, repo.BaseBranch, file)
+			if err != nil {
+				return err
+			}
+			deps, err := deppolicy.Added(prefix+file, base, head)
+			if err != nil {
+				return err
+			}
+			added = append(added, deps...)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	logger.Info("Checking added dependencies against policy", zap.Int("dependencies", len(added)))
+	violations, err := deppolicy.Check(ctx, settings.DependencyPolicy, added, p.cfg.Licenses)
+	if err != nil {
+		return fmt.Errorf("job cancelled: %w", err)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = "- " + v.String()
+	}
+	logger.Warn("AI change violates the dependency policy", zap.Int("violations", len(violations)))
+	return &rejectedChangeError{
+		msg:     "AI change violates the dependency policy",
+		reason:  "Dependency policy blocked the change",
+		details: "The change adds dependencies the project's policy does not permit:\n\n" + strings.Join(lines, "\n"),
+	}
+}
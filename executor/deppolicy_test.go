@@ -0,0 +1,110 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/deppolicy"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+)
+
+// withDependencyPolicy sets the project's dependency policy.
+func withDependencyPolicy(d *testDeps, policy models.DependencyPolicy) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.DependencyPolicy = policy
+		}
+		return settings, err
+	}
+}
+
+// licenseSource returns fixed licenses by dependency name.
+type licenseSource map[string][]string
+
+func (m licenseSource) Licenses(_ context.Context, dep deppolicy.Dependency) ([]string, error) {
+	if licenses, ok := m[dep.Name]; ok {
+		return licenses, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// addGoDependency makes the AI's change add module to go.mod.
+func addGoDependency(t *testing.T, d *testDeps, module string) {
+	t.Helper()
+	base := "module example.com/app\n\nrequire go.uber.org/zap v1.27.0\n"
+	head := base + "require " + module + " v1.0.0\n"
+	if err := os.WriteFile(filepath.Join(d.wsDir, "go.mod"), []byte(head), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	d.git.ChangedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"go.mod", "main.go"}, nil
+	}
+	d.git.BaseFileFunc = func(dir, baseBranch, path string) ([]byte, bool, error) {
+		if path != "go.mod" || baseBranch != "main" {
+			t.Errorf("BaseFile(%q, %q)", baseBranch, path)
+		}
+		return []byte(base), true, nil
+	}
+}
+
+func TestExecuteNewTicket_DependencyPolicyBlocksPR(t *testing.T) {
+	d := newTestDeps(t)
+	withDependencyPolicy(d, models.DependencyPolicy{DeniedLicenses: []string{"AGPL-3.0-only"}})
+	addGoDependency(t, d, "github.com/acme/copyleft")
+	committed := false
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		committed = true
+		return "abc123", nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+
+	cfg := agentConfig(&executortest.StubAgentRunner{})
+	cfg.Licenses = licenseSource{"github.com/acme/copyleft": {"AGPL-3.0-only"}}
+	_, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "dependency policy") {
+		t.Fatalf("err = %v, want dependency policy error", err)
+	}
+
+	if committed {
+		t.Error("change violating the dependency policy was committed")
+	}
+	for _, want := range []string{
+		"Dependency policy blocked the change",
+		"`github.com/acme/copyleft v1.0.0` (go.mod): license AGPL-3.0-only is not permitted",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("comment missing %q, got:\n%s", want, comment)
+		}
+	}
+}
+
+func TestExecuteNewTicket_DependencyPolicyAllowsPR(t *testing.T) {
+	d := newTestDeps(t)
+	withDependencyPolicy(d, models.DependencyPolicy{Allow: []string{"github.com/acme/*"}})
+	addGoDependency(t, d, "github.com/acme/kit")
+	prCreated := false
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		prCreated = true
+		return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+	}
+
+	_, err := d.pipelineWithConfig(t, agentConfig(&executortest.StubAgentRunner{})).
+		Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !prCreated {
+		t.Error("PR should be created when added dependencies are allowed")
+	}
+}
@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoconfig"
+)
+
+// postPreview posts the uncommitted change in wsPath to the ticket for
+// approval instead of opening a PR, and parks the ticket in review
+// with the awaiting-approval label. The workspace keeps the change
+// until [Pipeline.publishPreview] commits it. details, when set,
+// replaces the plain diff (e.g., with the confidence gate's report).
+func (p *Pipeline) postPreview(
+	logger *zap.Logger,
+	ticketKey, wsPath, details string,
+	settings *models.ProjectSettings,
+) error {
+	preview := settings.DiffPreview
+	if details == "" {
+		diff, err := p.proposedDiff(wsPath, settings)
+		if err != nil {
+			return err
+		}
+		details = "Proposed change:\n\n" + diff
+	}
+
+	body := fmt.Sprintf("%s Change awaiting approval\n\n%s\n\n"+
+		"To open a pull request with this change, add the label %q to the ticket or comment %q. "+
+		"To have the change redone instead, move the ticket back to %q.",
+		models.DiffPreviewMarker, details,
+		preview.ApprovedLabel(), preview.ApprovalCommand(), settings.TodoStatus)
+	if err := p.tracker.AddComment(ticketKey, body); err != nil {
+		return fmt.Errorf("post change preview: %w", err)
+	}
+	if err := p.tracker.AddLabel(ticketKey, preview.AwaitingLabel()); err != nil {
+		return fmt.Errorf("add %s label: %w", preview.AwaitingLabel(), err)
+	}
+	if err := p.tracker.TransitionStatus(ticketKey, settings.InReviewStatus); err != nil {
+		return fmt.Errorf("transition to in-review: %w", err)
+	}
+
+	p.cleanupStatusComment(logger, ticketKey)
+	p.clearFailureLabels(logger, ticketKey, settings.FailureLabels)
+	logger.Info("Change preview posted, awaiting approval",
+		zap.String("label", preview.AwaitingLabel()))
+	return nil
+}
+
+// publishPreview opens the PR for a ticket whose change preview was
+// approved, committing the change kept in its workspace. It reports
+// whether it handled the ticket: when the preview is not approved
+// (e.g., the ticket was moved back to be redone) or its workspace is
+// gone, the preview is discarded and the caller solves the ticket
+// again.
+func (p *Pipeline) publishPreview(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	workItem *models.WorkItem,
+	settings *models.ProjectSettings,
+	result *jobmanager.JobResult,
+) (bool, error) {
+	comments, err := p.tracker.GetComments(job.TicketKey)
+	if err != nil {
+		return false, fmt.Errorf("get comments: %w", err)
+	}
+	if !settings.DiffPreview.Approved(workItem.Labels, comments, p.cfg.JiraUsername) {
+		logger.Info("Change preview not approved, solving the ticket again")
+		p.discardPreview(logger, job.TicketKey, workItem.Labels, settings)
+		return false, nil
+	}
+
+	wsPath, ok := p.workspaces.Find(job.TicketKey)
+	if ok {
+		diff, err := p.git.Diff(wsPath, settings.Repos[0].BaseBranch)
+		if err != nil {
+			return false, fmt.Errorf("diff previewed change: %w", err)
+		}
+		ok = diff != ""
+	}
+	if !ok {
+		logger.Warn("Previewed change is no longer in the workspace, solving the ticket again")
+		p.discardPreview(logger, job.TicketKey, workItem.Labels, settings)
+		return false, nil
+	}
+
+	repoCfg, err := repoconfig.Load(wsPath)
+	if err != nil {
+		return true, fmt.Errorf("load repo config: %w", err)
+	}
+
+	logger.Info("Change preview approved, opening the PR")
+	err = p.openTicketPR(ctx, logger, job, result, ticketChange{
+		workItem:   workItem,
+		settings:   settings,
+		repoCfg:    repoCfg,
+		wsPath:     wsPath,
+		branchName: fmt.Sprintf("%s/%s", p.cfg.BotUsername, job.TicketKey),
+		excludes:   collectExcludes(mergeImports(settings, repoCfg)),
+		session:    readSessionOutput(wsPath),
+	})
+	if err != nil {
+		return true, err
+	}
+	p.removePreviewLabels(logger, job.TicketKey, workItem.Labels, settings)
+	return true, nil
+}
+
+// discardPreview drops a ticket's change preview: its labels and the
+// workspace holding the change.
+func (p *Pipeline) discardPreview(logger *zap.Logger, ticketKey string, labels []string, settings *models.ProjectSettings) {
+	p.removePreviewLabels(logger, ticketKey, labels, settings)
+	if err := p.workspaces.Cleanup(ticketKey); err != nil {
+		logger.Warn("Failed to delete previewed workspace", zap.Error(err))
+	}
+}
+
+// removePreviewLabels removes the preview labels among labels from the
+// ticket. Errors are logged.
+func (p *Pipeline) removePreviewLabels(logger *zap.Logger, ticketKey string, labels []string, settings *models.ProjectSettings) {
+	for _, label := range []string{settings.DiffPreview.AwaitingLabel(), settings.DiffPreview.ApprovedLabel()} {
+		if !slices.Contains(labels, label) {
+			continue
+		}
+		if err := p.tracker.RemoveLabel(ticketKey, label); err != nil {
+			logger.Warn("Failed to remove preview label",
+				zap.String("label", label), zap.Error(err))
+		}
+	}
+}
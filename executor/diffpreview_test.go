@@ -0,0 +1,169 @@
+package executor_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// withDiffPreview enables change previews in the given mode.
+func withDiffPreview(d *testDeps, mode string, minConfidence int) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.DiffPreview = models.DiffPreview{Mode: mode}
+			settings.MinConfidence = minConfidence
+		}
+		return settings, err
+	}
+	d.git.DiffFunc = func(dir, baseBranch string) (string, error) {
+		return "diff --git a/cache.go b/cache.go\n+evict()\n", nil
+	}
+}
+
+func TestExecuteNewTicket_DiffPreview(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		summary    string
+		wantDetail string
+		wantPR     bool
+	}{
+		{"always", models.DiffPreviewAlways, `{"changes": ["Fixed it"], "confidence": 90}`, "Proposed change:", false},
+		{"low confidence", models.DiffPreviewLowConfidence, `{"changes": ["Fixed it"], "confidence": 40}`, "40/100, below the project's threshold of 70", false},
+		{"confident", models.DiffPreviewLowConfidence, `{"changes": ["Fixed it"], "confidence": 90}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			withDiffPreview(d, tt.mode, 70)
+			writeSessionFile(t, d, taskfile.PRSummaryPath, tt.summary)
+			prCreated := false
+			d.git.CreatePRFunc = func(models.PRParams) (*models.PR, error) {
+				prCreated = true
+				return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+			}
+			var comment string
+			d.tracker.AddCommentFunc = func(key, body string) error {
+				comment = body
+				return nil
+			}
+			var labels, statuses []string
+			d.tracker.AddLabelFunc = func(key, label string) error {
+				labels = append(labels, label)
+				return nil
+			}
+			d.tracker.TransitionStatusFunc = func(key, status string) error {
+				statuses = append(statuses, status)
+				return nil
+			}
+
+			_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if prCreated != tt.wantPR {
+				t.Fatalf("PR created = %v, want %v", prCreated, tt.wantPR)
+			}
+			if tt.wantPR {
+				return
+			}
+			for _, want := range []string{
+				models.DiffPreviewMarker,
+				tt.wantDetail,
+				"```diff\ndiff --git a/cache.go b/cache.go\n+evict()\n```",
+				`add the label "ai-approved" to the ticket or comment "/approve"`,
+			} {
+				if !strings.Contains(comment, want) {
+					t.Errorf("comment missing %q, got:\n%s", want, comment)
+				}
+			}
+			if !slices.Contains(labels, "ai-awaiting-approval") {
+				t.Errorf("labels added = %v, want ai-awaiting-approval", labels)
+			}
+			if want := []string{"In Progress", "In Review"}; !slices.Equal(statuses, want) {
+				t.Errorf("transitions = %v, want %v", statuses, want)
+			}
+		})
+	}
+}
+
+func TestExecuteNewTicket_DiffPreviewApproved(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []string
+		wantPR   bool
+	}{
+		{"approved", []string{models.DiffPreviewMarker + " Change awaiting approval", "/approve"}, true},
+		{"not approved", []string{models.DiffPreviewMarker + " Change awaiting approval"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			withDiffPreview(d, models.DiffPreviewAlways, 0)
+			d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+				return &models.WorkItem{Key: key, Summary: "Fix a bug", Type: "Bug", Labels: []string{"ai-awaiting-approval"}}, nil
+			}
+			d.tracker.GetCommentsFunc = func(key string) ([]models.Comment, error) {
+				var comments []models.Comment
+				for _, body := range tt.comments {
+					comments = append(comments, models.Comment{Body: body})
+				}
+				return comments, nil
+			}
+			d.workspaces.FindFunc = func(ticketKey string) (string, bool) {
+				return d.wsDir, true
+			}
+			cleaned := false
+			d.workspaces.CleanupFunc = func(ticketKey string) error {
+				cleaned = true
+				return nil
+			}
+			var removed []string
+			d.tracker.RemoveLabelFunc = func(key, label string) error {
+				removed = append(removed, label)
+				return nil
+			}
+			var statuses []string
+			d.tracker.TransitionStatusFunc = func(key, status string) error {
+				statuses = append(statuses, status)
+				return nil
+			}
+			var prs int
+			d.git.CreatePRFunc = func(models.PRParams) (*models.PR, error) {
+				prs++
+				return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+			}
+
+			result, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if !slices.Contains(removed, "ai-awaiting-approval") {
+				t.Errorf("labels removed = %v, want ai-awaiting-approval", removed)
+			}
+			if tt.wantPR {
+				if prs != 1 || result.PRURL == "" || cleaned {
+					t.Errorf("PRs = %d, result = %+v, workspace cleaned = %v; want the previewed change's PR", prs, result, cleaned)
+				}
+				if slices.Contains(statuses, "In Progress") {
+					t.Errorf("transitions = %v; the approved change should not be solved again", statuses)
+				}
+				return
+			}
+			// An unapproved preview is discarded and the ticket solved
+			// again, which previews the new change.
+			if !cleaned || prs != 0 {
+				t.Errorf("workspace cleaned = %v, PRs = %d; want the preview discarded and redone", cleaned, prs)
+			}
+			if !slices.Contains(statuses, "In Progress") {
+				t.Errorf("transitions = %v, want the ticket solved again", statuses)
+			}
+		})
+	}
+}
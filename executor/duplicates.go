@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/ticketindex"
+)
+
+// checkLikelyDuplicates holds back a new ticket that closely resembles
+// a ticket the bot recently started on, so that AI budget is not spent
+// solving the same problem twice. The ticket is linked to the tickets
+// it resembles and waits, with a status comment listing them, until a
+// human resolves it as a duplicate or adds the not-duplicate label.
+// Tickets in exclude (those batched with it) are not counted. A ticket
+// that is not held back is added to the index.
+func (p *Pipeline) checkLikelyDuplicates(logger *zap.Logger, workItem *models.WorkItem, exclude []string) error {
+	if p.cfg.Tickets == nil {
+		return nil
+	}
+
+	if !slices.Contains(workItem.Labels, p.cfg.NotDuplicateLabel) {
+		matches := slices.DeleteFunc(p.cfg.Tickets.Similar(*workItem), func(m ticketindex.Match) bool {
+			return slices.Contains(exclude, m.Key)
+		})
+		if len(matches) > 0 {
+			return p.holdLikelyDuplicate(logger, workItem, matches)
+		}
+	}
+
+	if err := p.cfg.Tickets.Add(*workItem); err != nil {
+		logger.Warn("Failed to index ticket for duplicate detection", zap.Error(err))
+	}
+	return nil
+}
+
+// holdLikelyDuplicate links workItem to the matches it is not linked
+// to yet, posts the status comment, and returns the deferral error.
+// Link failures are logged; the comment still names every match.
+func (p *Pipeline) holdLikelyDuplicate(logger *zap.Logger, workItem *models.WorkItem, matches []ticketindex.Match) error {
+	keys := make([]string, len(matches))
+	for i, m := range matches {
+		keys[i] = m.Key
+		linked := slices.ContainsFunc(workItem.Links, func(l models.IssueLink) bool {
+			return l.Key == m.Key
+		})
+		if linked {
+			continue
+		}
+		if err := p.tracker.LinkWorkItems(workItem.Key, m.Key, p.cfg.DuplicateLinkType); err != nil {
+			logger.Warn("Failed to link likely duplicate",
+				zap.String("match", m.Key),
+				zap.Error(err))
+		}
+	}
+
+	logger.Info("Ticket resembles recently processed tickets, deferring",
+		zap.Strings("matches", keys))
+	p.upsertStatusComment(logger, workItem.Key, formatLikelyDuplicateComment(matches, p.cfg.NotDuplicateLabel))
+	return fmt.Errorf("likely duplicates %s: %w", strings.Join(keys, ", "), jobmanager.ErrDeferred)
+}
+
+// formatLikelyDuplicateComment builds the status comment posted while
+// a ticket is held back as a likely duplicate. The body depends only
+// on the matched tickets so that repeated deferrals do not rewrite
+// the comment.
+func formatLikelyDuplicateComment(matches []ticketindex.Match, notDuplicateLabel string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s Not starting: this ticket looks like a duplicate of recently processed tickets:\n", statusCommentMarker)
+	for _, m := range matches {
+		fmt.Fprintf(&b, "- %s: %s\n", m.Key, m.Summary)
+	}
+	fmt.Fprintf(&b, "If it is not a duplicate, add the label %q and it will be picked up automatically.", notDuplicateLabel)
+	return b.String()
+}
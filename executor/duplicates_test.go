@@ -0,0 +1,122 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/ticketindex"
+)
+
+// duplicatesConfig returns a pipeline configuration with duplicate
+// detection backed by index.
+func duplicatesConfig(index executor.TicketIndex) executor.Config {
+	return executor.Config{
+		BotUsername:       "ai-bot",
+		DefaultProvider:   "claude",
+		AIAPIKeys:         map[string]string{"claude": "test-key"},
+		MaxRetries:        3,
+		Tickets:           index,
+		DuplicateLinkType: "Relates",
+		NotDuplicateLabel: "ai-not-duplicate",
+	}
+}
+
+// similarTo returns an index stub matching every ticket with the
+// given tickets and recording the tickets added.
+func similarTo(added *[]string, matches ...ticketindex.Match) *executortest.StubTicketIndex {
+	return &executortest.StubTicketIndex{
+		SimilarFunc: func(models.WorkItem) []ticketindex.Match { return matches },
+		AddFunc: func(item models.WorkItem) error {
+			*added = append(*added, item.Key)
+			return nil
+		},
+	}
+}
+
+func TestExecuteNewTicket_LikelyDuplicateDefers(t *testing.T) {
+	d := newTestDeps(t)
+	withLinks(d, models.IssueLink{Type: "Relates", Relation: "relates to", Key: "PROJ-8"})
+	var added []string
+	index := similarTo(&added,
+		ticketindex.Match{Key: "PROJ-7", Summary: "Expired tokens are not refreshed", Score: 0.8},
+		ticketindex.Match{Key: "PROJ-8", Summary: "Session ends after an hour", Score: 0.6},
+	)
+	var linked []string
+	d.tracker.LinkWorkItemsFunc = func(key, otherKey, linkType string) error {
+		linked = append(linked, key+" "+linkType+" "+otherKey)
+		return nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+
+	_, err := d.pipelineWithConfig(t, duplicatesConfig(index)).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if !errors.Is(err, jobmanager.ErrDeferred) {
+		t.Fatalf("error = %v, want ErrDeferred", err)
+	}
+	if !slices.Equal(linked, []string{"PROJ-1 Relates PROJ-7"}) {
+		t.Errorf("links = %q, want only the unlinked PROJ-7", linked)
+	}
+	if len(transitions) != 0 {
+		t.Errorf("status transitions = %v, want none", transitions)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("comments = %q, want one status comment", comments)
+	}
+	for _, want := range []string{"PROJ-7: Expired tokens are not refreshed", "PROJ-8", `"ai-not-duplicate"`} {
+		if !strings.Contains(comments[0], want) {
+			t.Errorf("comment missing %q:\n%s", want, comments[0])
+		}
+	}
+	if len(added) != 0 {
+		t.Errorf("indexed %v, want a held-back ticket left out", added)
+	}
+}
+
+func TestExecuteNewTicket_NotDuplicateLabelProceeds(t *testing.T) {
+	d := newTestDeps(t)
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Summary: "Test ticket", Type: "Bug", Labels: []string{"ai-not-duplicate"}}, nil
+	}
+	var added []string
+	index := similarTo(&added, ticketindex.Match{Key: "PROJ-7", Summary: "Expired tokens are not refreshed", Score: 0.8})
+	d.tracker.LinkWorkItemsFunc = func(key, otherKey, linkType string) error {
+		t.Errorf("linked %s to %s despite the not-duplicate label", key, otherKey)
+		return nil
+	}
+
+	if _, err := d.pipelineWithConfig(t, duplicatesConfig(index)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(added, []string{"PROJ-1"}) {
+		t.Errorf("indexed %v, want PROJ-1", added)
+	}
+}
+
+func TestExecuteNewTicket_NoLikelyDuplicatesIndexesTicket(t *testing.T) {
+	d := newTestDeps(t)
+	var added []string
+	index := similarTo(&added)
+
+	if _, err := d.pipelineWithConfig(t, duplicatesConfig(index)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(added, []string{"PROJ-1"}) {
+		t.Errorf("indexed %v, want PROJ-1", added)
+	}
+}
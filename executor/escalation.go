@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// escalationConfigured reports whether the project routes tickets
+// the bot gives up on to a human, via a needs-human status or label.
+func escalationConfigured(settings *models.ProjectSettings) bool {
+	return settings.NeedsHumanStatus != "" || settings.FailureLabels.NeedsHuman != ""
+}
+
+// lastAnalysis returns the AI's final message from the ticket's most
+// recent session, or "" when there is no workspace or the session
+// left no summary.
+func (p *Pipeline) lastAnalysis(ticketKey string) string {
+	wsPath, found := p.workspaces.Find(ticketKey)
+	if !found {
+		return ""
+	}
+	return readSessionOutput(wsPath).Summary
+}
+
+// reviewLabel returns the lifecycle label for a ticket whose new PRs
+// were just created. When the project escalates low-confidence
+// changes and this one is rated Low, a comment explaining why the PRs
+// need a closer look is posted and the needs-human label is returned
+// instead of the review label.
+func (p *Pipeline) reviewLabel(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	session SessionOutput,
+	exitCode int,
+	prURLs []string,
+) string {
+	if !settings.EscalateLowConfidence {
+		return settings.LifecycleLabels.Review
+	}
+	confidence, validation := rateChange(session, exitCode, nil)
+	if confidence != confidenceLow {
+		return settings.LifecycleLabels.Review
+	}
+
+	logger.Info("Escalating low-confidence change to a human",
+		zap.String("reason", validation))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "The AI opened %s, but rated the change low confidence (%s), "+
+		"so it needs a closer look from a human before review.",
+		strings.Join(prURLs, ", "), validation)
+	if summary := strings.TrimSpace(session.Summary); summary != "" {
+		fmt.Fprintf(&b, "\n\nAI summary:\n%s", summary)
+	}
+	if err := p.tracker.AddComment(ticketKey, b.String()); err != nil {
+		logger.Warn("Failed to post escalation comment", zap.Error(err))
+	}
+	return cmp.Or(settings.FailureLabels.NeedsHuman, settings.LifecycleLabels.Review)
+}
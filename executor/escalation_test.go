@@ -0,0 +1,165 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+)
+
+// withEscalation configures the test project's needs-human status and
+// label.
+func withEscalation(d *testDeps, lowConfidence bool) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.NeedsHumanStatus = "Needs Human"
+			settings.FailureLabels.Blocked = "ai-blocked"
+			settings.FailureLabels.NeedsHuman = "ai-needs-human"
+			settings.LifecycleLabels.Review = "ai-review"
+			settings.EscalateLowConfidence = lowConfidence
+		}
+		return settings, err
+	}
+}
+
+func TestExecuteNewTicket_EscalatesWhenRetriesExhausted(t *testing.T) {
+	d := newTestDeps(t)
+	withEscalation(d, false)
+	d.containers.StartFunc = func(ctx context.Context, cfg *container.Config, wsDir, ticketKey string, env map[string]string) (*container.Container, error) {
+		return nil, errors.New("image pull failed")
+	}
+	var transitions, labels []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	d.tracker.AddLabelFunc = func(key, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+
+	job := newTicketJob("PROJ-1")
+	job.AttemptNum = 4 // MaxRetries is 3: this is the final attempt.
+	if _, err := d.pipeline(t).Execute(context.Background(), job); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if last := transitions[len(transitions)-1]; last != "Needs Human" {
+		t.Errorf("transitions = %v, want last Needs Human", transitions)
+	}
+	if !slices.Equal(labels, []string{"ai-needs-human"}) {
+		t.Errorf("labels = %v, want [ai-needs-human]", labels)
+	}
+	for _, want := range []string{"after 4 attempts", "needs a human", "image pull failed"} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("comment missing %q:\n%s", want, comment)
+		}
+	}
+}
+
+func TestExecuteNewTicket_EarlyFailureNotEscalated(t *testing.T) {
+	d := newTestDeps(t)
+	withEscalation(d, false)
+	d.containers.StartFunc = func(ctx context.Context, cfg *container.Config, wsDir, ticketKey string, env map[string]string) (*container.Container, error) {
+		return nil, errors.New("image pull failed")
+	}
+	var transitions, labels []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	d.tracker.AddLabelFunc = func(key, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if last := transitions[len(transitions)-1]; last != "To Do" {
+		t.Errorf("transitions = %v, want last To Do", transitions)
+	}
+	if !slices.Equal(labels, []string{"ai-blocked"}) {
+		t.Errorf("labels = %v, want [ai-blocked]", labels)
+	}
+}
+
+func TestExecuteNewTicket_EscalatesLowConfidence(t *testing.T) {
+	d := newTestDeps(t)
+	withEscalation(d, true)
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			return agent.Result{Summary: "Partial fix; tests still fail."}, agent.ErrMaxTurns
+		},
+	}
+	var labels []string
+	d.tracker.AddLabelFunc = func(key, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+
+	result, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Contains(labels, "ai-needs-human") || slices.Contains(labels, "ai-review") {
+		t.Errorf("labels = %v, want ai-needs-human instead of ai-review", labels)
+	}
+	escalation := comments[len(comments)-1]
+	for _, want := range []string{result.PRURL, "low confidence (AI exited with code 1)", "Partial fix; tests still fail."} {
+		if !strings.Contains(escalation, want) {
+			t.Errorf("escalation comment missing %q:\n%s", want, escalation)
+		}
+	}
+	// The ticket stays in review so that PR feedback is still handled.
+	if last := transitions[len(transitions)-1]; last != "In Review" {
+		t.Errorf("transitions = %v, want last In Review", transitions)
+	}
+}
+
+func TestExecuteNewTicket_LowConfidenceNotEscalatedByDefault(t *testing.T) {
+	d := newTestDeps(t)
+	withEscalation(d, false)
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			return agent.Result{}, agent.ErrMaxTurns
+		},
+	}
+	var labels []string
+	d.tracker.AddLabelFunc = func(key, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(labels, []string{"ai-review"}) {
+		t.Errorf("labels = %v, want [ai-review]", labels)
+	}
+}
@@ -0,0 +1,18 @@
+package executor
+
+import (
+	"jira-ai-issue-solver/events"
+	"jira-ai-issue-solver/jobmanager"
+)
+
+// publish sends e, tagged with job, to the configured [EventPublisher].
+func (p *Pipeline) publish(job *jobmanager.Job, e events.Event) {
+	if p.cfg.Events == nil {
+		return
+	}
+	e.TicketKey = job.TicketKey
+	e.JobID = job.ID
+	e.JobType = string(job.Type)
+	e.Attempt = job.AttemptNum
+	p.cfg.Events.Publish(e)
+}
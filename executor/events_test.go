@@ -0,0 +1,151 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/events"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/executor/executortest"
+)
+
+func eventsConfig(publisher executor.EventPublisher) executor.Config {
+	return executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": "test-key"},
+		MaxRetries:      3,
+		Events:          publisher,
+	}
+}
+
+func eventTypes(published []events.Event) []events.Type {
+	types := make([]events.Type, len(published))
+	for i, e := range published {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestExecuteNewTicket_PublishesEvents(t *testing.T) {
+	d := newTestDeps(t)
+	d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+		writeSessionOutput(t, d.wsDir, executor.SessionOutput{ExitCode: 0, CostUSD: 1.25})
+		return "", 0, nil
+	}
+	var published []events.Event
+	publisher := &executortest.StubEventPublisher{PublishFunc: func(e events.Event) {
+		published = append(published, e)
+	}}
+
+	p := d.pipelineWithConfig(t, eventsConfig(publisher))
+	if _, err := p.Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []events.Type{events.TicketStarted, events.WorkspaceReady, events.AICompleted, events.PRCreated}
+	if got := eventTypes(published); !slices.Equal(got, want) {
+		t.Fatalf("published = %v, want %v", got, want)
+	}
+	for _, e := range published {
+		if e.TicketKey != "PROJ-1" || e.JobID != "job-1" || e.JobType != "new_ticket" || e.Attempt != 1 {
+			t.Errorf("%s event job fields = %q %q %q %d", e.Type, e.TicketKey, e.JobID, e.JobType, e.Attempt)
+		}
+	}
+	if ai := published[2]; ai.Provider != "claude" || ai.ExitCode != 0 || ai.CostUSD != 1.25 {
+		t.Errorf("ai.completed = %+v, want claude, exit 0, $1.25", ai)
+	}
+	if pr := published[3]; pr.PRNumber != 1 || pr.PRURL != "https://github.com/org/repo/pull/1" || pr.Repo == "" {
+		t.Errorf("pr.created = %+v, want PR 1", pr)
+	}
+}
+
+func TestExecuteNewTicket_DeadLettersWhenRetriesExhausted(t *testing.T) {
+	d := newTestDeps(t)
+	d.containers.StartFunc = func(ctx context.Context, cfg *container.Config, wsDir, ticketKey string, env map[string]string) (*container.Container, error) {
+		return nil, errors.New("image pull failed")
+	}
+	var labels []string
+	d.tracker.AddLabelFunc = func(key, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+	var published []events.Event
+	publisher := &executortest.StubEventPublisher{PublishFunc: func(e events.Event) {
+		published = append(published, e)
+	}}
+
+	cfg := eventsConfig(publisher)
+	cfg.RetryLabel = "ai-retry"
+	cfg.DeadLetterLabel = "ai-dead-letter"
+	job := newTicketJob("PROJ-1")
+	job.AttemptNum = 4 // MaxRetries is 3: this is the final attempt.
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), job); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if !slices.Contains(labels, "ai-dead-letter") {
+		t.Errorf("labels = %v, want ai-dead-letter", labels)
+	}
+	if !slices.Contains(eventTypes(published), events.TicketDeadLettered) {
+		t.Errorf("published = %v, want %s", eventTypes(published), events.TicketDeadLettered)
+	}
+	if !strings.Contains(comment, `remove the label`) {
+		t.Errorf("comment missing dead-letter hint:\n%s", comment)
+	}
+}
+
+func TestExecuteNewTicket_NoDeadLetterBeforeRetriesExhausted(t *testing.T) {
+	d := newTestDeps(t)
+	d.containers.StartFunc = func(ctx context.Context, cfg *container.Config, wsDir, ticketKey string, env map[string]string) (*container.Container, error) {
+		return nil, errors.New("image pull failed")
+	}
+	var labels []string
+	d.tracker.AddLabelFunc = func(key, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+
+	cfg := eventsConfig(nil)
+	cfg.DeadLetterLabel = "ai-dead-letter"
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err == nil {
+		t.Fatal("expected error")
+	}
+	if slices.Contains(labels, "ai-dead-letter") {
+		t.Errorf("labels = %v, dead-letter label added on the first attempt", labels)
+	}
+}
+
+func TestExecuteFeedback_PublishesFeedbackApplied(t *testing.T) {
+	d := newFeedbackDeps(t)
+	var published []events.Event
+	publisher := &executortest.StubEventPublisher{PublishFunc: func(e events.Event) {
+		published = append(published, e)
+	}}
+
+	p := d.pipelineWithConfig(t, eventsConfig(publisher))
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []events.Type{events.WorkspaceReady, events.AICompleted, events.FeedbackApplied}
+	if got := eventTypes(published); !slices.Equal(got, want) {
+		t.Fatalf("published = %v, want %v", got, want)
+	}
+	if !published[0].Reused {
+		t.Error("workspace.ready Reused = false, want true for a reused workspace")
+	}
+	applied := published[2]
+	if applied.PRNumber != 42 || applied.CommitSHA != "abc123" || applied.Comments != 1 {
+		t.Errorf("feedback.applied = %+v, want PR 42, commit abc123, 1 comment", applied)
+	}
+}
@@ -30,7 +30,8 @@
 //  3. Transition ticket to "in progress"
 //  4. Prepare workspace (clone or reuse)
 //  5. Create or switch to ticket branch
-//  6. Write task file for the AI agent
+//  6. Write task file for the AI agent, listing relevant files when
+//     a repo index is configured
 //  7. Write provider-specific wrapper script
 //  8. Load repo-level configuration hints
 //  9. Resolve and start dev container
@@ -61,6 +62,19 @@
 // reuses the existing workspace and PR branch, and replies to review
 // comments after committing.
 //
+// If a ticket returns to the todo status while its bot PR is still
+// open, the new-ticket pipeline does not generate a second PR: it
+// moves the ticket back to "in review" so the feedback pipeline
+// picks it up again.
+//
+// # Tracing
+//
+// [Pipeline.Execute] opens a root span per job and child spans for
+// the Jira fetch, workspace preparation, AI session, commit, and PR
+// creation stages. Every span carries the ticket key as the
+// "ticket.key" attribute. Spans are no-ops unless a tracer provider
+// is configured (see the tracing package).
+//
 // Test doubles are provided in the [executortest] subpackage.
 package executor
 
@@ -68,8 +82,17 @@ import (
 	"context"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/deppolicy"
+	"jira-ai-issue-solver/events"
 	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/linkcontext"
+	"jira-ai-issue-solver/modelroute"
 	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoindex"
+	"jira-ai-issue-solver/ticketindex"
 )
 
 // Executor runs jobs to completion. The Execute method matches
@@ -85,8 +108,8 @@ type Executor interface {
 // The underlying implementation (e.g., services.GitHubServiceImpl)
 // satisfies this interface.
 type GitService interface {
-	// SyncFork syncs a fork's default branch with its upstream
-	// parent via the GitHub merge-upstream API. Called before
+	// SyncFork syncs a fork's branch (the repo's base branch) with
+	// its upstream parent via the GitHub merge-upstream API. Called before
 	// CreateBranch in fork-based workflows to prevent stale
 	// branches that produce massive diffs.
 	SyncFork(forkOwner, repo, branch string) error
@@ -117,6 +140,28 @@ type GitService interface {
 	// branch does not exist.
 	HasChanges(dir, baseBranch string) (bool, error)
 
+	// DiffStat summarizes the committed changes on the current
+	// branch relative to origin/<baseBranch>.
+	DiffStat(dir, baseBranch string) (models.DiffStat, error)
+
+	// ChangedFiles lists the files that differ between
+	// origin/<baseBranch> and the working tree, including untracked
+	// files and excluding deleted ones.
+	ChangedFiles(dir, baseBranch string) ([]string, error)
+
+	// DeletedFiles lists the files that exist on origin/<baseBranch>
+	// but not in the working tree.
+	DeletedFiles(dir, baseBranch string) ([]string, error)
+
+	// Diff returns the changes between origin/<baseBranch> and the
+	// working tree as a unified diff, including untracked files.
+	Diff(dir, baseBranch string) (string, error)
+
+	// BaseFile returns the content of the file at path, relative to
+	// the repository root, on origin/<baseBranch>. The second result
+	// is false when the file does not exist there.
+	BaseFile(dir, baseBranch, path string) ([]byte, bool, error)
+
 	// CommitChanges creates a verified commit via the GitHub API
 	// from local workspace changes. Returns the commit SHA.
 	// Returns services.ErrNoChanges if all changes are bot
@@ -132,15 +177,16 @@ type GitService interface {
 	CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string,
 		coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error)
 
-	// StripRemoteAuth removes authentication credentials from the
-	// workspace's origin remote URL, preventing push operations.
-	// Used before handing control to the AI agent.
+	// StripRemoteAuth removes any credentials embedded in the
+	// workspace's origin remote URL (left by earlier versions of the
+	// bot). Used before handing control to the AI agent.
 	StripRemoteAuth(dir string) error
 
-	// RestoreRemoteAuth restores authentication credentials on the
-	// workspace's origin remote URL using a fresh token. Must be
-	// called after AI execution, before any operation that needs
-	// remote access (e.g., SyncWithRemote).
+	// RestoreRemoteAuth points the workspace's origin remote at
+	// owner/repo. The URL carries no credentials; git operations that
+	// need remote access (e.g., SyncWithRemote) authenticate per
+	// command. Called after AI execution and to switch origin to a
+	// fork.
 	RestoreRemoteAuth(dir, owner, repo string) error
 
 	// FetchRemote fetches all refs from the origin remote. Used in
@@ -161,6 +207,21 @@ type GitService interface {
 	// matching PR is found.
 	GetPRForBranch(owner, repo, head string) (*models.PRDetails, error)
 
+	// GetMergedPRForBranch finds a merged pull request whose head
+	// branch matches the given name. Returns nil, nil when no merged
+	// PR exists.
+	GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+
+	// GetClosedPRForBranch finds a closed (not merged) pull request
+	// whose head branch matches the given name. Returns nil, nil when
+	// no such PR exists.
+	GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+
+	// CountOpenPRs returns the number of open pull requests in the
+	// repository whose head branch starts with branchPrefix. Used to
+	// enforce the per-repository open PR limit.
+	CountOpenPRs(owner, repo, branchPrefix string) (int, error)
+
 	// GetPRComments returns comments on the given pull request.
 	// If since is the zero time, all comments are returned.
 	GetPRComments(owner, repo string, number int,
@@ -198,6 +259,21 @@ type GitService interface {
 	// paths (conflict markers are left in the working tree).
 	MergeBase(dir, branch, fetchURL string) ([]string, error)
 
+	// CherryPickPR applies the changes of the merged pull request
+	// prNumber, merged as mergeCommitSHA, to the current branch
+	// without committing them. fetchURL works as for MergeBase. On
+	// conflict, returns [services.ErrMergeConflict] and the list of
+	// conflicted file paths (conflict markers are left in the working
+	// tree).
+	CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error)
+
+	// RebaseOnRemote replays the local work on branch made since
+	// base, the remote head it started from (uncommitted changes
+	// included), onto the branch's current remote head. On conflict,
+	// the rebase is aborted and [services.ErrMergeConflict] is
+	// returned with the list of conflicted file paths.
+	RebaseOnRemote(dir, branch, base string) ([]string, error)
+
 	// CloneImport clones an auxiliary repository into destDir. If ref
 	// is non-empty, that branch/tag/commit is checked out after
 	// cloning. Used to make shared resources (workflow skills,
@@ -215,6 +291,10 @@ type GitService interface {
 	// workflow job steps, keyed by job name.
 	GetFailedJobLogs(owner, repo, headSHA string, maxBytesPerStep int) (map[string][]models.FailedStep, error)
 
+	// CreateCheckRun publishes a completed check run on the commit
+	// run.HeadSHA. Hosts without check runs ignore it.
+	CreateCheckRun(owner, repo string, run models.CheckRun) error
+
 	// AddPRLabel adds a label to a GitHub pull request. Creates the
 	// label if it does not already exist on the repository.
 	AddPRLabel(owner, repo string, number int, label string) error
@@ -222,6 +302,14 @@ type GitService interface {
 	// RemovePRLabel removes a label from a GitHub pull request.
 	// Returns nil if the label is not present (idempotent).
 	RemovePRLabel(owner, repo string, number int, label string) error
+
+	// UpdatePRBody replaces the description of a pull request.
+	UpdatePRBody(owner, repo string, number int, body string) error
+
+	// EnablePRAutoMerge has the host merge a pull request with method
+	// ("merge", "squash", or "rebase") once its required approvals
+	// and checks pass.
+	EnablePRAutoMerge(owner, repo string, number int, method string) error
 }
 
 // ProjectResolver maps work items to their project-specific settings.
@@ -234,6 +322,85 @@ type ProjectResolver interface {
 	ResolveProject(workItem models.WorkItem) (*models.ProjectSettings, error)
 }
 
+// SecretResolver returns the current plaintext value of a config
+// value that may be a secret manager reference. Satisfied by
+// secrets.Store.
+type SecretResolver interface {
+	Resolve(value string) string
+}
+
+// AgentRunner runs an AI session in-process, calling the model API
+// directly instead of an AI CLI inside the container. Satisfied by
+// *agent.ClaudeRunner.
+type AgentRunner interface {
+	Run(ctx context.Context, req agent.Request) (agent.Result, error)
+}
+
+// RepoIndex finds the files of a repository relevant to a ticket.
+// Satisfied by *repoindex.Cache.
+type RepoIndex interface {
+	// Relevant returns the files of the repository checked out at dir
+	// that best match query. repoURL identifies the repository's
+	// index.
+	Relevant(repoURL, dir, query string) ([]repoindex.Entry, error)
+}
+
+// TicketIndex remembers the tickets the bot has started on and finds
+// likely duplicates among them. Satisfied by *ticketindex.Index.
+type TicketIndex interface {
+	// Similar returns the indexed tickets that likely describe the
+	// same problem as item, most similar first.
+	Similar(item models.WorkItem) []ticketindex.Match
+
+	// Add indexes item.
+	Add(item models.WorkItem) error
+}
+
+// ModelRouter chooses the model for a ticket's AI sessions by the
+// ticket's complexity. Satisfied by *modelroute.Router.
+type ModelRouter interface {
+	// Route decides the model provider should use for item. Returns
+	// false if provider's models are not routed.
+	Route(provider string, item models.WorkItem) (modelroute.Decision, bool)
+}
+
+// AILimiter bounds concurrent AI sessions and their start rate.
+// Satisfied by *ailimit.Limiter.
+type AILimiter interface {
+	// Acquire blocks until a session of provider may start and
+	// returns the function that ends it.
+	Acquire(ctx context.Context, provider string) (release func(), err error)
+}
+
+// LinkFetcher fetches the pages a ticket links to. Satisfied by
+// *linkcontext.Fetcher.
+type LinkFetcher interface {
+	// URLs returns the links in text that may be fetched.
+	URLs(text string) []string
+
+	// Fetch returns the text of the page at url.
+	Fetch(ctx context.Context, url string) (linkcontext.Page, error)
+}
+
+// ProjectCosts tracks AI spend per Jira project key for project
+// budgets. Satisfied by *costtracker.ProjectTracker.
+type ProjectCosts interface {
+	// Record adds amount to the project's daily and monthly spend.
+	Record(project string, amount float64)
+
+	// Spent returns the combined daily and monthly spend of projects.
+	Spent(projects ...string) (daily, monthly float64)
+
+	// RecordDeferral counts a ticket deferred for lack of budget.
+	RecordDeferral(project string)
+}
+
+// EventPublisher receives the pipeline's lifecycle events. Satisfied
+// by *events.Bus.
+type EventPublisher interface {
+	Publish(e events.Event)
+}
+
 // Config holds construction parameters for [Pipeline].
 type Config struct {
 	// BotUsername is used for branch naming
@@ -246,14 +413,89 @@ type Config struct {
 
 	// AIAPIKeys maps provider names to API key values injected
 	// into the container environment (e.g., {"claude": "sk-..."}).
+	// Values may be secret references when Secrets is set.
 	AIAPIKeys map[string]string
 
+	// Secrets resolves AIAPIKeys values at container start, so that
+	// rotated keys are picked up without a restart. Nil means the
+	// values are plaintext.
+	Secrets SecretResolver
+
 	// ClaudeVertex holds Vertex AI authentication settings for
 	// Claude. When configured, the pipeline injects Vertex-specific
 	// env vars and mounts the credentials file into the container
 	// instead of setting ANTHROPIC_API_KEY.
 	ClaudeVertex *ClaudeVertexConfig
 
+	// Agents maps provider names to in-process agent runners. A
+	// provider with a runner is executed through it, with commands
+	// run in the dev container; other providers run their CLI
+	// inside the container.
+	Agents map[string]AgentRunner
+
+	// AttachTranscripts uploads each AI session's transcript to the
+	// ticket as an attachment.
+	AttachTranscripts bool
+
+	// MaxAgentOutputBytes aborts an in-process agent session once its
+	// messages and tool results exceed this size. CLI sessions are
+	// not limited. Zero means no limit.
+	MaxAgentOutputBytes int
+
+	// MaxAIRetries is how many times a new-ticket AI session that
+	// fails or produces no changes is rerun, with the reason added
+	// to the task file. Zero disables retries.
+	MaxAIRetries int
+
+	// MaxParallelRepos is how many repositories of a multi-repo
+	// ticket are committed and get their PRs concurrently. Zero or
+	// one processes them sequentially.
+	MaxParallelRepos int
+
+	// AILimiter holds each AI session until the global and
+	// per-provider limits admit it. Nil runs sessions without delay.
+	AILimiter AILimiter
+
+	// ProjectCosts records each job's AI cost against its ticket's
+	// project and defers new tickets of projects whose budget is
+	// spent. Nil disables project budgets.
+	ProjectCosts ProjectCosts
+
+	// RepoIndex lists the files relevant to a new ticket in its task
+	// file. Nil disables the listing.
+	RepoIndex RepoIndex
+
+	// Tickets holds back new tickets that resemble a ticket the bot
+	// already started on, linking them with DuplicateLinkType. Nil
+	// disables duplicate detection.
+	Tickets TicketIndex
+
+	// DuplicateLinkType is the link type connecting a likely
+	// duplicate to the tickets it resembles.
+	DuplicateLinkType string
+
+	// NotDuplicateLabel marks a ticket a human has confirmed is not
+	// a duplicate, so that it is solved anyway.
+	NotDuplicateLabel string
+
+	// Models routes new-ticket and feedback sessions to a model by
+	// the ticket's complexity. Nil uses the configured models.
+	Models ModelRouter
+
+	// Links fetches the allow-listed pages a new ticket's description
+	// and comments link to, for the AI to read. Nil disables fetching.
+	Links LinkFetcher
+
+	// Licenses looks up the licenses of dependencies a change adds,
+	// for projects whose dependency policy restricts licenses. Nil
+	// rejects every such dependency as having an unknown license.
+	Licenses deppolicy.LicenseSource
+
+	// SetupCacheDir is the host directory holding the dependency
+	// caches of repositories' setup commands. Empty runs setup
+	// commands before every session without caching.
+	SetupCacheDir string
+
 	// SessionTimeout is the maximum duration for an AI session
 	// inside the container. Zero means no explicit timeout (only
 	// the parent context controls cancellation).
@@ -263,6 +505,11 @@ type Config struct {
 	// from feedback processing entirely (e.g., CI bots).
 	IgnoredUsernames []string
 
+	// IgnoredCommentPaths lists file path patterns (e.g., generated
+	// files) whose review comments are excluded from feedback
+	// processing entirely.
+	IgnoredCommentPaths []string
+
 	// KnownBotUsernames lists other bots for loop prevention.
 	// Their replies to our bot's comments are excluded.
 	KnownBotUsernames []string
@@ -287,6 +534,11 @@ type Config struct {
 	// silently and looping.
 	MaxRetries int
 
+	// Events receives the pipeline's lifecycle events (ticket
+	// started, AI session completed, PR created, feedback applied).
+	// Nil publishes none.
+	Events EventPublisher
+
 	// GeminiPricing holds per-million-token prices for computing
 	// Gemini session costs from token counts.
 	GeminiPricing GeminiPricing
@@ -303,6 +555,11 @@ type Config struct {
 	// after exhaustion. Included in the status comment hint.
 	RetryLabel string
 
+	// DeadLetterLabel is added to a ticket that exhausted its
+	// retries. Users remove it to request a retry. Empty disables
+	// dead-lettering.
+	DeadLetterLabel string
+
 	// JiraUsername is the Jira account email used to filter out
 	// the bot's own comments when building the issue file.
 	JiraUsername string
@@ -310,6 +567,29 @@ type Config struct {
 	// MinCommentLength is the minimum character length for Jira
 	// ticket comments to be included in the AI task file.
 	MinCommentLength int
+
+	// Worklog configures recording each job's duration as a tracker
+	// worklog entry. The zero value disables worklogs.
+	Worklog WorklogConfig
+
+	// TracerProvider supplies the tracer for pipeline stage spans.
+	// Nil uses the global OpenTelemetry provider, which is a no-op
+	// unless tracing has been configured.
+	TracerProvider trace.TracerProvider
+}
+
+// WorklogConfig controls the worklog entry added to a ticket after
+// each job.
+type WorklogConfig struct {
+	// Enabled turns worklog entries on.
+	Enabled bool
+
+	// Author replaces {{author}} in Description.
+	Author string
+
+	// Description is the worklog comment template. {{activity}} is
+	// replaced by the kind of work the job did.
+	Description string
 }
 
 // ClaudeVertexConfig holds Vertex AI authentication settings for
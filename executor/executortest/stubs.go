@@ -5,9 +5,15 @@ import (
 	"context"
 	"time"
 
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/events"
 	"jira-ai-issue-solver/executor"
 	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/linkcontext"
+	"jira-ai-issue-solver/modelroute"
 	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoindex"
+	"jira-ai-issue-solver/ticketindex"
 )
 
 // Compile-time checks.
@@ -15,6 +21,14 @@ var (
 	_ executor.Executor        = (*Stub)(nil)
 	_ executor.GitService      = (*StubGitService)(nil)
 	_ executor.ProjectResolver = (*StubProjectResolver)(nil)
+	_ executor.AgentRunner     = (*StubAgentRunner)(nil)
+	_ executor.RepoIndex       = (*StubRepoIndex)(nil)
+	_ executor.TicketIndex     = (*StubTicketIndex)(nil)
+	_ executor.LinkFetcher     = (*StubLinkFetcher)(nil)
+	_ executor.ModelRouter     = (*StubModelRouter)(nil)
+	_ executor.AILimiter       = (*StubAILimiter)(nil)
+	_ executor.ProjectCosts    = (*StubProjectCosts)(nil)
+	_ executor.EventPublisher  = (*StubEventPublisher)(nil)
 )
 
 // Stub is a test double for [executor.Executor].
@@ -41,6 +55,11 @@ type StubGitService struct {
 	RemoteBranchExistsFunc      func(owner, repo, branch string) (bool, error)
 	DeleteRemoteBranchFunc      func(owner, repo, branch string) error
 	HasChangesFunc              func(dir, baseBranch string) (bool, error)
+	DiffStatFunc                func(dir, baseBranch string) (models.DiffStat, error)
+	ChangedFilesFunc            func(dir, baseBranch string) ([]string, error)
+	DeletedFilesFunc            func(dir, baseBranch string) ([]string, error)
+	DiffFunc                    func(dir, baseBranch string) (string, error)
+	BaseFileFunc                func(dir, baseBranch, path string) ([]byte, bool, error)
 	CommitChangesFunc           func(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail bool) (string, error)
 	StripRemoteAuthFunc         func(dir string) error
 	RestoreRemoteAuthFunc       func(dir, owner, repo string) error
@@ -48,12 +67,17 @@ type StubGitService struct {
 	SyncWithRemoteFunc          func(dir, branch string, importExcludes []string) error
 	CreatePRFunc                func(params models.PRParams) (*models.PR, error)
 	GetPRForBranchFunc          func(owner, repo, head string) (*models.PRDetails, error)
+	GetMergedPRForBranchFunc    func(owner, repo, head string) (*models.PRDetails, error)
+	GetClosedPRForBranchFunc    func(owner, repo, head string) (*models.PRDetails, error)
+	CountOpenPRsFunc            func(owner, repo, branchPrefix string) (int, error)
 	GetPRCommentsFunc           func(owner, repo string, number int, since time.Time) ([]models.PRComment, error)
 	ReplyToCommentFunc          func(owner, repo string, prNumber int, commentID int64, body string) error
 	PostIssueCommentFunc        func(owner, repo string, prNumber int, body string) error
 	ListIssueCommentsFunc       func(owner, repo string, prNumber int) ([]models.IssueComment, error)
 	UpdateIssueCommentFunc      func(owner, repo string, commentID int64, body string) error
 	MergeBaseFunc               func(dir, branch, fetchURL string) ([]string, error)
+	CherryPickPRFunc            func(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error)
+	RebaseOnRemoteFunc          func(dir, branch, base string) ([]string, error)
 	CloneImportFunc             func(url, destDir, ref string) error
 	ListCheckRunsForRefFunc     func(owner, repo, ref string) ([]models.CheckRunFailure, bool, error)
 	ListCheckRunAnnotationsFunc func(owner, repo string, checkRunID int64) ([]models.CheckAnnotation, error)
@@ -61,6 +85,9 @@ type StubGitService struct {
 	AddCommentReactionFunc      func(owner, repo string, comment models.PRComment, reaction string) error
 	AddPRLabelFunc              func(owner, repo string, number int, label string) error
 	RemovePRLabelFunc           func(owner, repo string, number int, label string) error
+	UpdatePRBodyFunc            func(owner, repo string, number int, body string) error
+	CreateCheckRunFunc          func(owner, repo string, run models.CheckRun) error
+	EnablePRAutoMergeFunc       func(owner, repo string, number int, method string) error
 }
 
 func (s *StubGitService) SyncFork(forkOwner, repo, branch string) error {
@@ -105,6 +132,41 @@ func (s *StubGitService) HasChanges(dir, baseBranch string) (bool, error) {
 	return false, nil
 }
 
+func (s *StubGitService) DiffStat(dir, baseBranch string) (models.DiffStat, error) {
+	if s.DiffStatFunc != nil {
+		return s.DiffStatFunc(dir, baseBranch)
+	}
+	return models.DiffStat{}, nil
+}
+
+func (s *StubGitService) ChangedFiles(dir, baseBranch string) ([]string, error) {
+	if s.ChangedFilesFunc != nil {
+		return s.ChangedFilesFunc(dir, baseBranch)
+	}
+	return nil, nil
+}
+
+func (s *StubGitService) DeletedFiles(dir, baseBranch string) ([]string, error) {
+	if s.DeletedFilesFunc != nil {
+		return s.DeletedFilesFunc(dir, baseBranch)
+	}
+	return nil, nil
+}
+
+func (s *StubGitService) Diff(dir, baseBranch string) (string, error) {
+	if s.DiffFunc != nil {
+		return s.DiffFunc(dir, baseBranch)
+	}
+	return "", nil
+}
+
+func (s *StubGitService) BaseFile(dir, baseBranch, path string) ([]byte, bool, error) {
+	if s.BaseFileFunc != nil {
+		return s.BaseFileFunc(dir, baseBranch, path)
+	}
+	return nil, false, nil
+}
+
 func (s *StubGitService) CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error) {
 	skip := len(skipFileGuardrail) > 0 && skipFileGuardrail[0]
 	if s.CommitChangesFunc != nil {
@@ -152,7 +214,28 @@ func (s *StubGitService) GetPRForBranch(owner, repo, head string) (*models.PRDet
 	if s.GetPRForBranchFunc != nil {
 		return s.GetPRForBranchFunc(owner, repo, head)
 	}
-	return &models.PRDetails{}, nil
+	return nil, nil
+}
+
+func (s *StubGitService) GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	if s.GetMergedPRForBranchFunc != nil {
+		return s.GetMergedPRForBranchFunc(owner, repo, head)
+	}
+	return nil, nil
+}
+
+func (s *StubGitService) GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	if s.GetClosedPRForBranchFunc != nil {
+		return s.GetClosedPRForBranchFunc(owner, repo, head)
+	}
+	return nil, nil
+}
+
+func (s *StubGitService) CountOpenPRs(owner, repo, branchPrefix string) (int, error) {
+	if s.CountOpenPRsFunc != nil {
+		return s.CountOpenPRsFunc(owner, repo, branchPrefix)
+	}
+	return 0, nil
 }
 
 func (s *StubGitService) GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error) {
@@ -197,6 +280,20 @@ func (s *StubGitService) MergeBase(dir, branch, fetchURL string) ([]string, erro
 	return []string{}, nil
 }
 
+func (s *StubGitService) CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error) {
+	if s.CherryPickPRFunc != nil {
+		return s.CherryPickPRFunc(dir, fetchURL, prNumber, mergeCommitSHA)
+	}
+	return []string{}, nil
+}
+
+func (s *StubGitService) RebaseOnRemote(dir, branch, base string) ([]string, error) {
+	if s.RebaseOnRemoteFunc != nil {
+		return s.RebaseOnRemoteFunc(dir, branch, base)
+	}
+	return []string{}, nil
+}
+
 func (s *StubGitService) CloneImport(url, destDir, ref string) error {
 	if s.CloneImportFunc != nil {
 		return s.CloneImportFunc(url, destDir, ref)
@@ -246,6 +343,27 @@ func (s *StubGitService) RemovePRLabel(owner, repo string, number int, label str
 	return nil
 }
 
+func (s *StubGitService) UpdatePRBody(owner, repo string, number int, body string) error {
+	if s.UpdatePRBodyFunc != nil {
+		return s.UpdatePRBodyFunc(owner, repo, number, body)
+	}
+	return nil
+}
+
+func (s *StubGitService) EnablePRAutoMerge(owner, repo string, number int, method string) error {
+	if s.EnablePRAutoMergeFunc != nil {
+		return s.EnablePRAutoMergeFunc(owner, repo, number, method)
+	}
+	return nil
+}
+
+func (s *StubGitService) CreateCheckRun(owner, repo string, run models.CheckRun) error {
+	if s.CreateCheckRunFunc != nil {
+		return s.CreateCheckRunFunc(owner, repo, run)
+	}
+	return nil
+}
+
 // StubProjectResolver is a test double for [executor.ProjectResolver].
 // Set the corresponding Func field to control each method's behavior.
 // When a Func field is nil, the method returns zero values.
@@ -259,3 +377,144 @@ func (s *StubProjectResolver) ResolveProject(workItem models.WorkItem) (*models.
 	}
 	return &models.ProjectSettings{Repos: []models.RepoSettings{{}}}, nil
 }
+
+// StubAgentRunner is a test double for [executor.AgentRunner].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, the method returns zero values.
+type StubAgentRunner struct {
+	RunFunc func(ctx context.Context, req agent.Request) (agent.Result, error)
+}
+
+func (s *StubAgentRunner) Run(ctx context.Context, req agent.Request) (agent.Result, error) {
+	if s.RunFunc != nil {
+		return s.RunFunc(ctx, req)
+	}
+	return agent.Result{}, nil
+}
+
+// StubRepoIndex is a test double for [executor.RepoIndex].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, the method returns zero values.
+type StubRepoIndex struct {
+	RelevantFunc func(repoURL, dir, query string) ([]repoindex.Entry, error)
+}
+
+func (s *StubRepoIndex) Relevant(repoURL, dir, query string) ([]repoindex.Entry, error) {
+	if s.RelevantFunc != nil {
+		return s.RelevantFunc(repoURL, dir, query)
+	}
+	return nil, nil
+}
+
+// StubTicketIndex is a test double for [executor.TicketIndex].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, the method returns zero values.
+type StubTicketIndex struct {
+	SimilarFunc func(item models.WorkItem) []ticketindex.Match
+	AddFunc     func(item models.WorkItem) error
+}
+
+func (s *StubTicketIndex) Similar(item models.WorkItem) []ticketindex.Match {
+	if s.SimilarFunc != nil {
+		return s.SimilarFunc(item)
+	}
+	return nil
+}
+
+func (s *StubTicketIndex) Add(item models.WorkItem) error {
+	if s.AddFunc != nil {
+		return s.AddFunc(item)
+	}
+	return nil
+}
+
+// StubAILimiter is a test double for [executor.AILimiter].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, Acquire admits the session at once.
+type StubAILimiter struct {
+	AcquireFunc func(ctx context.Context, provider string) (func(), error)
+}
+
+func (s *StubAILimiter) Acquire(ctx context.Context, provider string) (func(), error) {
+	if s.AcquireFunc != nil {
+		return s.AcquireFunc(ctx, provider)
+	}
+	return func() {}, nil
+}
+
+// StubProjectCosts is a test double for [executor.ProjectCosts].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, the method returns zero values.
+type StubProjectCosts struct {
+	RecordFunc         func(project string, amount float64)
+	SpentFunc          func(projects ...string) (float64, float64)
+	RecordDeferralFunc func(project string)
+}
+
+func (s *StubProjectCosts) Record(project string, amount float64) {
+	if s.RecordFunc != nil {
+		s.RecordFunc(project, amount)
+	}
+}
+
+func (s *StubProjectCosts) Spent(projects ...string) (float64, float64) {
+	if s.SpentFunc != nil {
+		return s.SpentFunc(projects...)
+	}
+	return 0, 0
+}
+
+func (s *StubProjectCosts) RecordDeferral(project string) {
+	if s.RecordDeferralFunc != nil {
+		s.RecordDeferralFunc(project)
+	}
+}
+
+// StubLinkFetcher is a test double for [executor.LinkFetcher].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, the method returns zero values.
+type StubLinkFetcher struct {
+	URLsFunc  func(text string) []string
+	FetchFunc func(ctx context.Context, url string) (linkcontext.Page, error)
+}
+
+func (s *StubLinkFetcher) URLs(text string) []string {
+	if s.URLsFunc != nil {
+		return s.URLsFunc(text)
+	}
+	return nil
+}
+
+func (s *StubLinkFetcher) Fetch(ctx context.Context, url string) (linkcontext.Page, error) {
+	if s.FetchFunc != nil {
+		return s.FetchFunc(ctx, url)
+	}
+	return linkcontext.Page{}, nil
+}
+
+// StubModelRouter is a test double for [executor.ModelRouter].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, the method returns zero values.
+type StubModelRouter struct {
+	RouteFunc func(provider string, item models.WorkItem) (modelroute.Decision, bool)
+}
+
+func (s *StubModelRouter) Route(provider string, item models.WorkItem) (modelroute.Decision, bool) {
+	if s.RouteFunc != nil {
+		return s.RouteFunc(provider, item)
+	}
+	return modelroute.Decision{}, false
+}
+
+// StubEventPublisher is a test double for [executor.EventPublisher].
+// Set the corresponding Func field to control each method's behavior.
+// When a Func field is nil, the method does nothing.
+type StubEventPublisher struct {
+	PublishFunc func(e events.Event)
+}
+
+func (s *StubEventPublisher) Publish(e events.Event) {
+	if s.PublishFunc != nil {
+		s.PublishFunc(e)
+	}
+}
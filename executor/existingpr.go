@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// findExistingPR looks for an open PR on the ticket's bot branch in
+// any of the workspace's repositories. A ticket that bounces back to
+// the todo status (e.g., moved by hand, or reverted after a
+// post-PR failure) still has its PR open; generating a new one would
+// at best fail and at worst duplicate the work. Lookup errors are
+// logged and treated as "no PR" so that a GitHub hiccup does not
+// block processing; PR creation rejects true duplicates anyway.
+func (p *Pipeline) findExistingPR(logger *zap.Logger, ticketKey string, settings *models.ProjectSettings) *models.PRDetails {
+	branchName := fmt.Sprintf("%s/%s", p.cfg.BotUsername, ticketKey)
+	heads := settings.PRHeads(branchName)
+	for _, repo := range settings.Repos {
+		pr, err := p.findPRByHeadsOptional(repo.Owner, repo.Repo, heads)
+		if err != nil {
+			logger.Warn("Failed to check for an existing PR",
+				zap.String("repo", repo.Owner+"/"+repo.Repo),
+				zap.Error(err))
+			continue
+		}
+		if pr != nil {
+			return pr
+		}
+	}
+	return nil
+}
+
+// resumeExistingPR hands a ticket with an open PR back to the
+// feedback flow instead of generating new changes: the ticket moves
+// to the in-review status, where the feedback scanner picks up any
+// review comments on the existing PR.
+func (p *Pipeline) resumeExistingPR(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	pr *models.PRDetails,
+) (jobmanager.JobResult, error) {
+	logger.Info("Open PR already exists, switching ticket to feedback mode",
+		zap.String("url", pr.URL),
+		zap.Int("number", pr.Number))
+
+	if err := p.tracker.TransitionStatus(ticketKey, settings.InReviewStatus); err != nil {
+		return jobmanager.JobResult{}, fmt.Errorf("transition to in-review for existing PR: %w", err)
+	}
+
+	p.cleanupStatusComment(logger, ticketKey)
+	allLabels := models.AllPipelineLabels(settings.FailureLabels, settings.LifecycleLabels)
+	p.setPipelineLabel(logger, ticketKey, allLabels, settings.LifecycleLabels.Review)
+
+	return jobmanager.JobResult{
+		PRURL:    pr.URL,
+		PRNumber: pr.Number,
+	}, nil
+}
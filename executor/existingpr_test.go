@@ -0,0 +1,80 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestExecuteNewTicket_ExistingPRSwitchesToFeedback(t *testing.T) {
+	d := newTestDeps(t)
+
+	d.git.GetPRForBranchFunc = func(owner, repo, head string) (*models.PRDetails, error) {
+		if head != "ai-bot/PROJ-1" {
+			t.Errorf("head = %q, want ai-bot/PROJ-1", head)
+		}
+		return &models.PRDetails{Number: 7, URL: "https://github.com/org/repo/pull/7"}, nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		t.Error("CreatePR should not be called when a PR already exists")
+		return &models.PR{}, nil
+	}
+	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string, _ []string) (string, bool, error) {
+		t.Error("workspace should not be prepared when a PR already exists")
+		return d.wsDir, false, nil
+	}
+
+	result, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.PRNumber != 7 || result.PRURL != "https://github.com/org/repo/pull/7" {
+		t.Errorf("result = %+v, want existing PR #7", result)
+	}
+	if len(transitions) != 1 || transitions[0] != "In Review" {
+		t.Errorf("transitions = %v, want [In Review]", transitions)
+	}
+}
+
+func TestExecuteNewTicket_ExistingPRFoundInForkHead(t *testing.T) {
+	d := newTestDeps(t)
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		return &models.ProjectSettings{
+			Repos:          []models.RepoSettings{{Owner: "org", Repo: "repo", BaseBranch: "main"}},
+			InReviewStatus: "In Review",
+			ForkMode:       true,
+			GitHubUsername: "alice",
+		}, nil
+	}
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Assignee: &models.Author{Email: "alice@example.com"}}, nil
+	}
+
+	var heads []string
+	d.git.GetPRForBranchFunc = func(owner, repo, head string) (*models.PRDetails, error) {
+		heads = append(heads, head)
+		if head == "ai-bot/PROJ-1" {
+			return &models.PRDetails{Number: 3}, nil
+		}
+		return nil, nil
+	}
+
+	result, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.PRNumber != 3 {
+		t.Errorf("PRNumber = %d, want 3 (direct-mode PR found via fallback head)", result.PRNumber)
+	}
+	if len(heads) != 2 || heads[0] != "alice:ai-bot/PROJ-1" {
+		t.Errorf("heads = %v, want fork head first then direct head", heads)
+	}
+}
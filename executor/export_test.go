@@ -99,3 +99,11 @@ func CheckTicketCostCap(p *Pipeline, logger *zap.Logger, wsPath string, maxCost
 func RecordTicketCost(p *Pipeline, logger *zap.Logger, wsPath string, maxCost float64, cost float64) {
 	p.recordTicketCost(logger, wsPath, maxCost, cost)
 }
+
+// CompareKeys exposes compareKeys for testing.
+func CompareKeys(a, b string) int { return compareKeys(a, b) }
+
+// IsTestFile exposes isTestFile for testing.
+func IsTestFile(file string) bool {
+	return isTestFile(file)
+}
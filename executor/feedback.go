@@ -10,10 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"jira-ai-issue-solver/commentfilter"
 	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/events"
 	"jira-ai-issue-solver/jobmanager"
 	"jira-ai-issue-solver/models"
 	"jira-ai-issue-solver/repoconfig"
@@ -30,7 +32,9 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 	logger.Info("Starting feedback pipeline")
 
 	// --- Step 1: Fetch work item ---
-	workItem, err := p.tracker.GetWorkItem(job.TicketKey)
+	_, span := p.startStage(ctx, spanFetchWorkItem, job.TicketKey)
+	workItem, err := p.jobWorkItem(job)
+	endStage(span, err)
 	if err != nil {
 		return result, fmt.Errorf("get work item: %w", err)
 	}
@@ -81,19 +85,34 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 
 	// --- Step 3: Find PR by branch ---
 	branchName := fmt.Sprintf("%s/%s", p.cfg.BotUsername, job.TicketKey)
-	prDetails, err := p.findPRByHeads(settings.Repos[0].Owner, settings.Repos[0].Repo, settings.PRHeads(branchName))
+	heads := settings.PRHeads(branchName)
+	prDetails, err := p.findPRByHeadsOptional(settings.Repos[0].Owner, settings.Repos[0].Repo, heads)
 	if err != nil {
 		return result, err
 	}
+	if prDetails == nil {
+		// --- Step 3a: Settle a PR merged or closed since the scan ---
+		settled, err := p.settleClosedPRs(logger, job.TicketKey, settings, settings.Repos, heads)
+		if err != nil {
+			return result, err
+		}
+		if settled {
+			return result, nil
+		}
+		return result, fmt.Errorf("no open PR found for heads %v", heads)
+	}
 
 	// --- Step 4: Find or create workspace (self-healing) ---
-	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, settings.Repos[0].CloneURL)
+	_, span = p.startStage(ctx, spanPrepareWS, job.TicketKey)
+	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, settings.Repos[0].CloneURL, settings.Repos[0].SparsePaths)
+	endStage(span, err)
 	if err != nil {
 		return result, fmt.Errorf("prepare workspace: %w", err)
 	}
 	logger.Info("Workspace ready",
 		zap.String("path", wsPath),
 		zap.Bool("reused", reused))
+	p.publish(job, events.Event{Type: events.WorkspaceReady, Reused: reused})
 
 	// --- Step 4a: Set origin to fork and fetch ---
 	if err := p.ensureForkRemote(wsPath, settings); err != nil {
@@ -111,8 +130,10 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 	// --- Step 6: Fetch and categorize comments + CI analysis ---
 	owner := settings.Repos[0].Owner
 	repo := settings.Repos[0].Repo
+	_, span = p.startStage(ctx, spanFetchComments, job.TicketKey)
 	newComments, addressedComments, ciFailures, err := p.fetchFeedbackContext(
 		logger, owner, repo, prDetails)
+	endStage(span, err)
 	if err != nil {
 		return result, err
 	}
@@ -121,8 +142,6 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 		return result, nil
 	}
 
-	p.reactToComments(logger, owner, repo, newComments)
-
 	// --- Step 7: Load repo config ---
 	repoCfg, err := repoconfig.Load(wsPath)
 	if err != nil {
@@ -143,6 +162,8 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 		return result, err
 	}
 
+	p.prepareCompanionRepos(logger, wsPath, settings)
+
 	// --- Step 9a: Remove stale AI outputs from prior session ---
 	cleanAIOutputs(logger, wsPath)
 
@@ -150,9 +171,9 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 	provider := p.resolveProvider(settings)
 	logger.Info("AI provider selected", zap.String("provider", provider))
 
-	// --- Step 11: Build AI command ---
+	// --- Step 11: Build AI session parameters ---
 	sp := buildScriptParams(provider, p.cfg.DefaultClaudeModel, p.cfg.DefaultGeminiModel, repoCfg)
-	execCommand := buildExecCommand(sp)
+	p.routeModel(logger, *workItem, &sp, repoCfg)
 
 	// --- Step 12: Resolve and start container ---
 	ctr, err = p.startContainer(ctx, wsPath, job.TicketKey, provider, settings)
@@ -179,15 +200,38 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 	}()
 
 	// --- Step 13: Execute AI agent ---
-	execCtx := ctx
-	if p.cfg.SessionTimeout > 0 {
-		var cancel context.CancelFunc
-		execCtx, cancel = context.WithTimeout(ctx, p.cfg.SessionTimeout)
-		defer cancel()
-	}
-
-	_, exitCode, execErr := p.containers.Exec(
-		execCtx, ctr, execCommand)
+	// Large rounds spanning several files may be split into one
+	// session per file (see splitFeedback).
+	execCtx, span := p.startStage(ctx, spanAISession, job.TicketKey,
+		attribute.String("ai.provider", provider))
+	var (
+		exitCode int
+		execErr  error
+		timedOut bool
+		session  SessionOutput
+	)
+	if groups := splitFeedback(newComments, ciFailures, settings.FeedbackSplitThreshold); groups != nil {
+		logger.Info("Splitting feedback into one AI session per file",
+			zap.Int("comments", len(newComments)),
+			zap.Int("sessions", len(groups)))
+		span.SetAttributes(attribute.Int("ai.sessions", len(groups)))
+		split := p.runSplitFeedbackSessions(execCtx, logger, job, ctr, wsPath, sp,
+			*prDetails, addressedComments, settings, groups)
+		exitCode, execErr, timedOut, session = split.exitCode, split.execErr, split.timedOut, split.session
+	} else {
+		if p.cfg.SessionTimeout > 0 {
+			var cancel context.CancelFunc
+			execCtx, cancel = context.WithTimeout(execCtx, p.cfg.SessionTimeout)
+			defer cancel()
+		}
+		p.reactToComments(logger, owner, repo, newComments, reactionWorking)
+		exitCode, execErr = p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
+		timedOut = execCtx.Err() != nil
+		session = readSessionOutput(wsPath)
+		p.applyCostEstimate(&session)
+	}
+	span.SetAttributes(attribute.Int("ai.exit_code", exitCode))
+	endStage(span, execErr)
 	if execErr != nil {
 		if ctx.Err() != nil {
 			return result, fmt.Errorf("job cancelled: %w", ctx.Err())
@@ -195,9 +239,6 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 		logger.Warn("AI agent exec failed", zap.Error(execErr))
 	}
 
-	session := readSessionOutput(wsPath)
-	p.applyCostEstimate(&session)
-
 	logger.Info("AI session completed",
 		zap.Int("exit_code", exitCode),
 		zap.Float64("cost_usd", session.CostUSD),
@@ -215,7 +256,7 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 	authStripped = false
 
 	if execErr != nil {
-		if execCtx.Err() != nil {
+		if timedOut {
 			return result, fmt.Errorf("session timeout exceeded: %w", execErr)
 		}
 		return result, fmt.Errorf("AI session failed: %w", execErr)
@@ -230,13 +271,26 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 		return p.handleNoChanges(logger, settings, prDetails, newComments, ciFailures, wsPath, result, exitCode, job.AttemptNum)
 	}
 
+	// --- Step 14a: Keep commits pushed during the session ---
+	deferred, err := p.reconcileHumanPush(logger, wsPath, owner, repo,
+		settings.PRHeads(branchName), branchName, prDetails, settings.HumanPushPolicy)
+	if err != nil {
+		return result, err
+	}
+	if deferred {
+		p.postOrUpdateCostComment(logger, owner, repo, prDetails.Number, result.CostUSD, "Feedback (deferred)", job.AttemptNum)
+		return result, nil
+	}
+
 	// --- Step 15: Commit via GitHub API ---
 	importExcludes := collectExcludes(mergedImports)
 	commitMsg := fmt.Sprintf("%s: address PR feedback", job.TicketKey)
+	_, span = p.startStage(ctx, spanCommit, job.TicketKey)
 	sha, err := p.git.CommitChanges(
 		settings.Repos[0].Owner, settings.CommitOwner(), settings.Repos[0].Repo, branchName,
 		commitMsg, wsPath, settings.Repos[0].BaseBranch, workItem.Assignee, importExcludes,
 	)
+	endStage(span, err)
 	if errors.Is(err, services.ErrNoChanges) {
 		return p.handleErrNoChanges(logger, settings, prDetails, newComments, result, exitCode, job.AttemptNum)
 	}
@@ -252,10 +306,20 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 	// --- Step 17: Clear failure labels and reply to addressed comments ---
 	p.clearFailureLabels(logger, job.TicketKey, settings.FailureLabels)
 	aiResponses := readCommentResponses(wsPath)
-	p.replyToComments(logger, settings, prDetails, newComments, sha, aiResponses) // best-effort: commit is the primary outcome
+	handled := p.reportPartialFeedback(logger, owner, repo, prDetails.Number, newComments, aiResponses, sha)
+	p.replyToComments(logger, settings, prDetails, handled, sha, aiResponses) // best-effort: commit is the primary outcome
+	p.reactToComments(logger, owner, repo, withoutQuestions(handled, aiResponses), reactionFixPushed)
 
 	// --- Step 17a: Post CI fix attempt marker ---
 	p.postCIFixMarker(logger, owner, repo, prDetails.Number, ciFailures, sha)
+	p.publishCheckRun(logger, settings, owner, repo, sha, checkRunReport{
+		label:    "Feedback",
+		summary:  session.Summary,
+		session:  session,
+		exitCode: exitCode,
+		costUSD:  result.CostUSD,
+		redact:   workItem.HasSecurityLevel(),
+	})
 
 	p.postOrUpdateCostComment(logger,
 		settings.Repos[0].Owner, settings.Repos[0].Repo,
@@ -279,6 +343,14 @@ func (p *Pipeline) executeFeedback(ctx context.Context, job *jobmanager.Job) (re
 		zap.String("url", prDetails.URL),
 		zap.Int("number", prDetails.Number),
 		zap.Int("new_comments_addressed", len(newComments)))
+	p.publish(job, events.Event{
+		Type:      events.FeedbackApplied,
+		Repo:      owner + "/" + repo,
+		PRNumber:  prDetails.Number,
+		PRURL:     prDetails.URL,
+		CommitSHA: sha,
+		Comments:  len(handled),
+	})
 
 	return result, nil
 }
@@ -314,13 +386,9 @@ func (p *Pipeline) executeMultiRepoFeedback(
 		}
 	}()
 
-	// --- Step 3: Prepare multi-repo workspace ---
-	wsPath, _, err := p.prepareMultiRepoWorkspace(logger, job.TicketKey, settings)
-	if err != nil {
-		return result, err
-	}
-
-	// --- Step 4: Find PRs across all repos ---
+	// --- Step 3: Find PRs across all repos ---
+	// Before the workspace is prepared, so that a ticket whose PRs
+	// were all merged or closed since the scan is not cloned.
 	branchName := fmt.Sprintf("%s/%s", p.cfg.BotUsername, job.TicketKey)
 	heads := settings.PRHeads(branchName)
 	var repoInfos []repoPRInfo
@@ -334,9 +402,23 @@ func (p *Pipeline) executeMultiRepoFeedback(
 		repoInfos = append(repoInfos, repoPRInfo{repo: repo, pr: pr})
 	}
 	if len(repoInfos) == 0 {
+		settled, err := p.settleClosedPRs(logger, job.TicketKey, settings, settings.Repos, heads)
+		if err != nil {
+			return result, err
+		}
+		if settled {
+			return result, nil
+		}
 		return result, fmt.Errorf("no PRs found for branch %s in any repository", branchName)
 	}
 
+	// --- Step 4: Prepare multi-repo workspace ---
+	wsPath, reused, err := p.prepareMultiRepoWorkspace(logger, job.TicketKey, settings)
+	if err != nil {
+		return result, err
+	}
+	p.publish(job, events.Event{Type: events.WorkspaceReady, Reused: reused})
+
 	// --- Step 5: Per-repo branch setup (only repos with PRs) ---
 	if err := p.syncMultiRepoBranches(wsPath, branchName, settings, repoInfos); err != nil {
 		return result, err
@@ -356,10 +438,6 @@ func (p *Pipeline) executeMultiRepoFeedback(
 		return result, nil
 	}
 
-	for _, ri := range repoInfos {
-		p.reactToComments(logger, ri.repo.Owner, ri.repo.Repo, ri.newCmts)
-	}
-
 	// --- Step 7: Load repo configs and merge imports ---
 	repoConfigs := make([]*repoconfig.Config, len(settings.Repos))
 	for i, repo := range settings.Repos {
@@ -385,13 +463,15 @@ func (p *Pipeline) executeMultiRepoFeedback(
 		return result, err
 	}
 
+	p.prepareCompanionRepos(logger, wsPath, settings)
+
 	// --- Step 8a: Remove stale AI outputs from prior session ---
 	cleanAIOutputs(logger, wsPath)
 
 	// --- Step 9: Provider, command, container ---
 	provider := p.resolveProvider(settings)
 	sp := buildScriptParams(provider, p.cfg.DefaultClaudeModel, p.cfg.DefaultGeminiModel, repoConfigs[0])
-	execCommand := buildExecCommand(sp)
+	p.routeModel(logger, *workItem, &sp, repoConfigs[0])
 
 	ctr, err = p.startContainer(ctx, wsPath, job.TicketKey, provider, settings)
 	if err != nil {
@@ -431,7 +511,10 @@ func (p *Pipeline) executeMultiRepoFeedback(
 		defer cancel()
 	}
 
-	_, exitCode, execErr := p.containers.Exec(execCtx, ctr, execCommand)
+	for _, ri := range repoInfos {
+		p.reactToComments(logger, ri.repo.Owner, ri.repo.Repo, ri.newCmts, reactionWorking)
+	}
+	exitCode, execErr := p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
 	if execErr != nil {
 		if ctx.Err() != nil {
 			return result, fmt.Errorf("job cancelled: %w", ctx.Err())
@@ -528,6 +611,18 @@ func (p *Pipeline) executeMultiRepoFeedback(
 	logger.Info("Multi-repo feedback processed",
 		zap.Int("repos_with_prs", len(repoInfos)),
 		zap.Int("new_comments_addressed", len(allNew)))
+	for _, ri := range repoInfos {
+		if sha := repoSHAs[ri.repo.Name]; sha != "" {
+			p.publish(job, events.Event{
+				Type:      events.FeedbackApplied,
+				Repo:      ri.repo.Owner + "/" + ri.repo.Repo,
+				PRNumber:  ri.pr.Number,
+				PRURL:     ri.pr.URL,
+				CommitSHA: sha,
+				Comments:  len(ri.newCmts),
+			})
+		}
+	}
 
 	return result, nil
 }
@@ -591,7 +686,7 @@ func (p *Pipeline) writeMultiRepoFeedbackFiles(
 	if err != nil {
 		return fmt.Errorf("download attachments: %w", err)
 	}
-	comments := p.fetchTicketComments(logger, workItem.Key)
+	comments := p.fetchTicketComments(logger, workItem)
 	if err := p.taskWriter.WriteIssue(workItem, wsPath, downloaded, comments); err != nil {
 		return fmt.Errorf("write issue file: %w", err)
 	}
@@ -628,7 +723,7 @@ func (p *Pipeline) writeFeedbackFiles(
 	if err != nil {
 		return fmt.Errorf("download attachments: %w", err)
 	}
-	comments := p.fetchTicketComments(logger, workItem.Key)
+	comments := p.fetchTicketComments(logger, workItem)
 	if err := p.taskWriter.WriteIssue(workItem, wsPath, downloaded, comments); err != nil {
 		return fmt.Errorf("write issue file: %w", err)
 	}
@@ -683,13 +778,13 @@ func (p *Pipeline) commitMultiRepoFeedback(
 		if aiResponses != nil {
 			logger.Info("AI produced no code changes but provided comment responses")
 			totalPosted := 0
-			for _, ri := range params.repoInfos {
-				totalPosted += p.replyToCommentsOnRepo(logger, ri.repo.Owner, ri.repo.Repo,
-					ri.pr, ri.newCmts, "", aiResponses)
-			}
 			totalComments := 0
 			for _, ri := range params.repoInfos {
-				totalComments += len(ri.newCmts)
+				handled := p.reportPartialFeedback(logger, ri.repo.Owner, ri.repo.Repo,
+					ri.pr.Number, ri.newCmts, aiResponses, "")
+				totalPosted += p.replyToCommentsOnRepo(logger, ri.repo.Owner, ri.repo.Repo,
+					ri.pr, handled, "", aiResponses)
+				totalComments += len(handled)
 			}
 			if totalPosted == 0 && totalComments > 0 {
 				return nil, fmt.Errorf("AI provided comment responses but failed to post any replies")
@@ -713,6 +808,24 @@ func (p *Pipeline) commitMultiRepoFeedback(
 		return nil, fmt.Errorf("AI produced no changes (exit code: %d)", params.exitCode)
 	}
 
+	// Keep commits pushed to any PR branch during the session. A
+	// deferred repo defers the whole round, so that the repos' PRs
+	// stay consistent.
+	for i, ri := range params.repoInfos {
+		if !repoHasChanges[i] {
+			continue
+		}
+		deferred, err := p.reconcileHumanPush(logger, filepath.Join(params.wsPath, ri.repo.Name),
+			ri.repo.Owner, ri.repo.Repo, params.settings.PRHeads(params.branchName),
+			params.branchName, ri.pr, params.settings.HumanPushPolicy)
+		if err != nil {
+			return nil, err
+		}
+		if deferred {
+			return nil, nil
+		}
+	}
+
 	// Commit per repo.
 	commitMsg := fmt.Sprintf("%s: address PR feedback", params.ticketKey)
 	repoSHAs := make(map[string]string)
@@ -772,8 +885,12 @@ func (p *Pipeline) commitMultiRepoFeedback(
 		shortSHA = shortSHA[:7]
 	}
 	for _, ri := range params.repoInfos {
+		handled := p.reportPartialFeedback(logger, ri.repo.Owner, ri.repo.Repo,
+			ri.pr.Number, ri.newCmts, aiResponses, firstSHA)
 		p.replyToCommentsOnRepo(logger, ri.repo.Owner, ri.repo.Repo,
-			ri.pr, ri.newCmts, shortSHA, aiResponses)
+			ri.pr, handled, shortSHA, aiResponses)
+		p.reactToComments(logger, ri.repo.Owner, ri.repo.Repo,
+			withoutQuestions(handled, aiResponses), reactionFixPushed)
 	}
 
 	return repoSHAs, nil
@@ -808,25 +925,30 @@ func (p *Pipeline) replyToCommentsOnRepo(
 	pr *models.PRDetails,
 	comments []models.PRComment,
 	sha string,
-	aiResponses ...map[int64]string,
+	aiResponses ...map[int64]CommentResponse,
 ) int {
-	var responses map[int64]string
+	var responses map[int64]CommentResponse
 	if len(aiResponses) > 0 {
 		responses = aiResponses[0]
 	}
 
 	posted := 0
 	for _, c := range comments {
+		r := responses[c.ID]
 		var replyBody string
 		switch {
 		case sha == "unable":
 			replyBody = "I was unable to produce code changes to address this comment after multiple attempts."
-		case sha != "" && responses[c.ID] != "":
-			replyBody = fmt.Sprintf("%s\n\nAddressed in %s.", responses[c.ID], sha)
+		case r.Question != "":
+			replyBody = questionReplyBody(r.Question)
+		case r.NotAddressed:
+			replyBody = notAddressedReplyBody(r)
+		case sha != "" && r.Response != "":
+			replyBody = fmt.Sprintf("%s\n\nAddressed in %s.", r.Response, sha)
 		case sha != "":
 			replyBody = fmt.Sprintf("Addressed in %s.", sha)
-		case responses[c.ID] != "":
-			replyBody = responses[c.ID]
+		case r.Response != "":
+			replyBody = r.Response
 		default:
 			replyBody = "Reviewed — no code changes needed."
 		}
@@ -856,7 +978,9 @@ func (p *Pipeline) replyToCommentsOnRepo(
 
 // CategorizeComments separates PR comments into new (requiring action)
 // and addressed (bot has already replied). Bot's own comments are
-// excluded from both lists.
+// excluded from both lists, except follow-up questions (see
+// [commentfilter.QuestionMarker]), which are included in addressed so
+// the session handling the reviewer's answer sees what was asked.
 //
 // A review comment is "addressed" when the bot has a threaded reply
 // to it (InReplyTo match). A conversation comment is "addressed"
@@ -871,6 +995,9 @@ func CategorizeComments(comments []models.PRComment, botUsername string) (newCom
 	// Categorize non-bot comments.
 	for _, c := range comments {
 		if normalizeUsername(c.Author.Username) == normBot {
+			if commentfilter.IsQuestion(c.Body) {
+				addressed = append(addressed, c)
+			}
 			continue
 		}
 		if botRepliedTo[c.ID] {
@@ -895,6 +1022,7 @@ func (p *Pipeline) commentFilterConfig() commentfilter.Config {
 	return commentfilter.Config{
 		BotUsername:       p.cfg.BotUsername,
 		IgnoredUsernames:  p.cfg.IgnoredUsernames,
+		IgnoredPaths:      p.cfg.IgnoredCommentPaths,
 		KnownBotUsernames: p.cfg.KnownBotUsernames,
 		MaxThreadDepth:    p.cfg.MaxThreadDepth,
 	}
@@ -938,14 +1066,28 @@ func (p *Pipeline) handleFeedbackFailure(
 	}
 }
 
-// reactToComments adds an eyes emoji reaction to each comment to signal
-// that the bot has noticed the feedback and is working on it. Failures
-// are logged but not fatal — reactions are best-effort.
-func (p *Pipeline) reactToComments(logger *zap.Logger, owner, repo string, comments []models.PRComment) {
+// Reactions the bot adds to review comments it processes, giving
+// reviewers feedback before the textual reply is posted.
+const (
+	// reactionWorking (👀) marks a comment the bot has started
+	// addressing.
+	reactionWorking = "eyes"
+	// reactionFixPushed (🚀) marks a comment whose fix has been
+	// committed to the PR branch.
+	reactionFixPushed = "rocket"
+)
+
+// reactToComments adds the given emoji reaction to each comment.
+// reactionWorking is added just before the AI session the comments
+// are handed to, so a job that fails while preparing the session
+// leaves no comment marked as being worked on. Failures are logged
+// but not fatal — reactions are best-effort.
+func (p *Pipeline) reactToComments(logger *zap.Logger, owner, repo string, comments []models.PRComment, reaction string) {
 	for _, c := range comments {
-		if err := p.git.AddCommentReaction(owner, repo, c, "eyes"); err != nil {
+		if err := p.git.AddCommentReaction(owner, repo, c, reaction); err != nil {
 			logger.Warn("Failed to add reaction to comment",
 				zap.Int64("comment_id", c.ID),
+				zap.String("reaction", reaction),
 				zap.Error(err))
 		}
 	}
@@ -956,7 +1098,9 @@ func (p *Pipeline) reactToComments(logger *zap.Logger, owner, repo string, comme
 // When the AI provides a per-comment response summary (via
 // comment-responses.json), the reply includes that summary alongside
 // the commit reference. Otherwise, a generic "Addressed in <sha>"
-// reply is used.
+// reply is used. When the AI asked a follow-up question instead, the
+// reply is the question, and the comment stays pending until the
+// reviewer answers.
 //
 // Review comments are replied to via the threaded review comment API.
 // Conversation comments are replied to via a new issue comment that
@@ -969,7 +1113,7 @@ func (p *Pipeline) replyToComments(
 	prDetails *models.PRDetails,
 	comments []models.PRComment,
 	commitSHA string,
-	aiResponses map[int64]string,
+	aiResponses map[int64]CommentResponse,
 ) int {
 	shortSHA := commitSHA
 	if len(shortSHA) > 7 {
@@ -977,12 +1121,17 @@ func (p *Pipeline) replyToComments(
 	}
 	posted := 0
 	for _, c := range comments {
+		r := aiResponses[c.ID]
 		var replyBody string
-		if summary, ok := aiResponses[c.ID]; ok {
+		if r.Question != "" {
+			replyBody = questionReplyBody(r.Question)
+		} else if r.NotAddressed {
+			replyBody = notAddressedReplyBody(r)
+		} else if r.Response != "" {
 			if shortSHA != "" {
-				replyBody = fmt.Sprintf("%s\n\nAddressed in %s.", summary, shortSHA)
+				replyBody = fmt.Sprintf("%s\n\nAddressed in %s.", r.Response, shortSHA)
 			} else {
-				replyBody = summary
+				replyBody = r.Response
 			}
 		} else if shortSHA != "" {
 			replyBody = fmt.Sprintf("Addressed in %s.", shortSHA)
@@ -1015,6 +1164,35 @@ func (p *Pipeline) replyToComments(
 	return posted
 }
 
+// questionReplyBody formats a follow-up question the AI asked instead
+// of changing the code for an ambiguous comment. The marker keeps the
+// question visible to the next feedback session as context.
+func questionReplyBody(question string) string {
+	return fmt.Sprintf("%s\n\nI have not changed the code for this comment yet. "+
+		"Reply with an answer and I will address it in the next round.\n%s",
+		question, commentfilter.QuestionMarker)
+}
+
+// notAddressedReplyBody formats the reply to a comment the AI left
+// unaddressed for [maxUnaddressedRounds] rounds, after which the bot
+// stops retrying it.
+func notAddressedReplyBody(r CommentResponse) string {
+	return fmt.Sprintf("I was unable to address this comment after %d attempts: %s",
+		maxUnaddressedRounds, notAddressedReason(r))
+}
+
+// withoutQuestions returns the comments the AI did not answer with a
+// follow-up question or leave unaddressed.
+func withoutQuestions(comments []models.PRComment, responses map[int64]CommentResponse) []models.PRComment {
+	var answered []models.PRComment
+	for _, c := range comments {
+		if r := responses[c.ID]; r.Question == "" && !r.NotAddressed {
+			answered = append(answered, c)
+		}
+	}
+	return answered
+}
+
 // isFinalAttempt returns true when the current attempt is the last
 // one before the job manager stops retrying. This accounts for the
 // coordinator's check (failureCounts > maxRetries), which allows
@@ -1182,9 +1360,10 @@ func (p *Pipeline) handleNoChanges(
 	aiResponses := readCommentResponses(wsPath)
 	if aiResponses != nil {
 		logger.Info("AI produced no code changes but provided comment responses")
-		posted := p.replyToComments(logger, settings, prDetails, newComments, "", aiResponses)
+		handled := p.reportPartialFeedback(logger, owner, repo, prDetails.Number, newComments, aiResponses, "")
+		posted := p.replyToComments(logger, settings, prDetails, handled, "", aiResponses)
 		p.postOrUpdateCostComment(logger, owner, repo, prDetails.Number, result.CostUSD, "Feedback (no changes)", attemptNum)
-		if posted == 0 && len(newComments) > 0 {
+		if posted == 0 && len(handled) > 0 {
 			return result, fmt.Errorf("AI provided comment responses but failed to post any replies")
 		}
 		return result, nil
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"jira-ai-issue-solver/commentfilter"
 	"jira-ai-issue-solver/container"
 	"jira-ai-issue-solver/container/containertest"
 	"jira-ai-issue-solver/executor"
@@ -117,6 +119,220 @@ func TestExecuteFeedback_AIGeneratedReplies(t *testing.T) {
 	}
 }
 
+func TestExecuteFeedback_AIQuestionPostedInsteadOfFix(t *testing.T) {
+	d := newFeedbackDeps(t)
+
+	// The AI found the comment ambiguous and changed nothing.
+	d.git.HasChangesFunc = func(_, _ string) (bool, error) { return false, nil }
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		writeCommentResponses(t, d.wsDir, `[
+			{"comment_id": 1, "question": "Should this apply to retries too?"}
+		]`)
+		return "", 0, nil
+	}
+
+	var replyBodies []string
+	d.git.ReplyToCommentFunc = func(_, _ string, _ int, _ int64, body string) error {
+		replyBodies = append(replyBodies, body)
+		return nil
+	}
+	var reactions []string
+	d.git.AddCommentReactionFunc = func(_, _ string, _ models.PRComment, reaction string) error {
+		reactions = append(reactions, reaction)
+		return nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("a posted question should complete the job, got: %v", err)
+	}
+
+	if len(replyBodies) != 1 {
+		t.Fatalf("reply count = %d, want 1", len(replyBodies))
+	}
+	if !strings.Contains(replyBodies[0], "Should this apply to retries too?") ||
+		!strings.Contains(replyBodies[0], commentfilter.QuestionMarker) {
+		t.Errorf("reply should be the marked question, got %q", replyBodies[0])
+	}
+	if strings.Contains(replyBodies[0], "Addressed in") {
+		t.Errorf("question reply should not claim the comment was addressed, got %q", replyBodies[0])
+	}
+	if slices.Contains(reactions, "rocket") {
+		t.Errorf("reactions = %v, want no rocket on a question", reactions)
+	}
+}
+
+func TestExecuteFeedback_AIQuestionAlongsideFixes(t *testing.T) {
+	d := newFeedbackDeps(t)
+
+	d.git.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{
+			{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Rename this", IsReviewComment: true},
+			{ID: 2, Author: models.Author{Username: "reviewer"}, Body: "Handle it better", IsReviewComment: true},
+		}, nil
+	}
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		return "abc1234567890", nil
+	}
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		writeCommentResponses(t, d.wsDir, `[
+			{"comment_id": 1, "response": "Renamed."},
+			{"comment_id": 2, "question": "Retry, or surface the error?"}
+		]`)
+		return "", 0, nil
+	}
+
+	replies := make(map[int64]string)
+	d.git.ReplyToCommentFunc = func(_, _ string, _ int, id int64, body string) error {
+		replies[id] = body
+		return nil
+	}
+	var rocketed []int64
+	d.git.AddCommentReactionFunc = func(_, _ string, c models.PRComment, reaction string) error {
+		if reaction == "rocket" {
+			rocketed = append(rocketed, c.ID)
+		}
+		return nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(replies[1], "Renamed.") || !strings.Contains(replies[1], "abc1234") {
+		t.Errorf("reply to 1 = %q, want the response and commit", replies[1])
+	}
+	if !strings.Contains(replies[2], "Retry, or surface the error?") || strings.Contains(replies[2], "abc1234") {
+		t.Errorf("reply to 2 = %q, want only the question", replies[2])
+	}
+	if !slices.Equal(rocketed, []int64{1}) {
+		t.Errorf("rocket reactions on %v, want [1]", rocketed)
+	}
+}
+
+func TestExecuteFeedback_PartialSuccessPostsSummary(t *testing.T) {
+	d := newFeedbackDeps(t)
+
+	d.git.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{
+			{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Rename this", FilePath: "a.go", Line: 3, IsReviewComment: true},
+			{ID: 2, Author: models.Author{Username: "reviewer"}, Body: "Add a migration", FilePath: "b.go", Line: 7, IsReviewComment: true},
+			{ID: 3, Author: models.Author{Username: "reviewer"}, Body: "Document this", IsReviewComment: true},
+		}, nil
+	}
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		return "abc1234567890", nil
+	}
+	// Comment 3 is missing from the responses.
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		writeCommentResponses(t, d.wsDir, `[
+			{"comment_id": 1, "response": "Renamed."},
+			{"comment_id": 2, "not_addressed": true, "response": "Needs a schema migration."}
+		]`)
+		return "", 0, nil
+	}
+
+	replies := make(map[int64]string)
+	d.git.ReplyToCommentFunc = func(_, _ string, _ int, id int64, body string) error {
+		replies[id] = body
+		return nil
+	}
+	var rocketed []int64
+	d.git.AddCommentReactionFunc = func(_, _ string, c models.PRComment, reaction string) error {
+		if reaction == "rocket" {
+			rocketed = append(rocketed, c.ID)
+		}
+		return nil
+	}
+	var summaries []string
+	d.git.PostIssueCommentFunc = func(_, _ string, _ int, body string) error {
+		if strings.Contains(body, "AI Feedback Summary") {
+			summaries = append(summaries, body)
+		}
+		return nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Unaddressed comments get no reply, so they are retried.
+	if len(replies) != 1 || !strings.Contains(replies[1], "Renamed.") {
+		t.Errorf("replies = %v, want only a reply to comment 1", replies)
+	}
+	if !slices.Equal(rocketed, []int64{1}) {
+		t.Errorf("rocket reactions on %v, want [1]", rocketed)
+	}
+
+	if len(summaries) != 1 {
+		t.Fatalf("summary comments = %d, want 1", len(summaries))
+	}
+	for _, want := range []string{
+		"Addressed 1 of 3 review comments in abc1234.",
+		"Needs a schema migration. (round 1 of 3)",
+		"did not report on this comment",
+		"<!-- not-addressed: 2=1,3=1 -->",
+	} {
+		if !strings.Contains(summaries[0], want) {
+			t.Errorf("summary missing %q:\n%s", want, summaries[0])
+		}
+	}
+}
+
+func TestExecuteFeedback_GivesUpOnCommentAfterMaxRounds(t *testing.T) {
+	d := newFeedbackDeps(t)
+
+	d.git.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{
+			{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Rename this", IsReviewComment: true},
+			{ID: 2, Author: models.Author{Username: "reviewer"}, Body: "Add a migration", IsReviewComment: true},
+		}, nil
+	}
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		return "abc1234567890", nil
+	}
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		writeCommentResponses(t, d.wsDir, `[
+			{"comment_id": 1, "response": "Renamed."},
+			{"comment_id": 2, "not_addressed": true, "response": "Needs a schema migration."}
+		]`)
+		return "", 0, nil
+	}
+	d.git.ListIssueCommentsFunc = func(_, _ string, _ int) ([]models.IssueComment, error) {
+		return []models.IssueComment{{
+			ID:   55,
+			Body: "<!-- AI-BOT-FEEDBACK-SUMMARY -->\n**AI Feedback Summary**\n<!-- not-addressed: 2=2 -->",
+		}}, nil
+	}
+
+	replies := make(map[int64]string)
+	d.git.ReplyToCommentFunc = func(_, _ string, _ int, id int64, body string) error {
+		replies[id] = body
+		return nil
+	}
+	var updated string
+	d.git.UpdateIssueCommentFunc = func(_, _ string, id int64, body string) error {
+		if id == 55 {
+			updated = body
+		}
+		return nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(replies[2], "unable to address this comment after 3 attempts: Needs a schema migration.") {
+		t.Errorf("reply to 2 = %q, want the give-up reply", replies[2])
+	}
+	if !strings.Contains(updated, "gave up after 3 rounds") || !strings.Contains(updated, "2=3") {
+		t.Errorf("updated summary = %q, want comment 2 given up", updated)
+	}
+}
+
 func TestExecuteFeedback_FallbackWhenNoResponsesFile(t *testing.T) {
 	d := newFeedbackDeps(t)
 
@@ -152,7 +368,7 @@ func TestExecuteFeedback_FallbackWhenNoResponsesFile(t *testing.T) {
 
 func TestExecuteFeedback_WorkspaceRecreated(t *testing.T) {
 	d := newFeedbackDeps(t)
-	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string) (string, bool, error) {
+	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string, _ []string) (string, bool, error) {
 		return d.wsDir, false, nil // newly created
 	}
 
@@ -352,6 +568,91 @@ func TestExecuteFeedback_PRNotFound(t *testing.T) {
 	}
 }
 
+func TestExecuteFeedback_PRMergedSinceScan(t *testing.T) {
+	d := newFeedbackDeps(t)
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.LifecycleLabels.Merged = "ai-merged"
+			settings.FailureLabels.Rejected = "ai-rejected"
+			settings.MergedStatus = "Done"
+		}
+		return settings, err
+	}
+	d.git.GetPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+		return nil, nil
+	}
+	d.git.GetMergedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, URL: "https://github.com/org/repo/pull/42"}, nil
+	}
+	// An earlier PR from the same branch was closed; the merge wins.
+	d.git.GetClosedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 41, Branch: head, URL: "https://github.com/org/repo/pull/41"}, nil
+	}
+	cloned := false
+	d.workspaces.FindOrCreateFunc = func(_, _ string, _ []string) (string, bool, error) {
+		cloned = true
+		return d.wsDir, true, nil
+	}
+	var labels, statuses []string
+	d.tracker.AddLabelFunc = func(_, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+	d.tracker.TransitionStatusFunc = func(_, status string) error {
+		statuses = append(statuses, status)
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("expected success for a merged PR, got %v", err)
+	}
+	if cloned {
+		t.Error("workspace should not be prepared for a merged PR")
+	}
+	if !slices.Equal(labels, []string{"ai-merged"}) {
+		t.Errorf("labels added = %v, want [ai-merged]", labels)
+	}
+	if !slices.Equal(statuses, []string{"Done"}) {
+		t.Errorf("status transitions = %v, want [Done]", statuses)
+	}
+}
+
+func TestExecuteFeedback_PRClosedSinceScan(t *testing.T) {
+	d := newFeedbackDeps(t)
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.FailureLabels.Rejected = "ai-rejected"
+		}
+		return settings, err
+	}
+	d.git.GetPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+		return nil, nil
+	}
+	d.git.GetClosedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, URL: "https://github.com/org/repo/pull/42"}, nil
+	}
+	var labels []string
+	d.tracker.AddLabelFunc = func(_, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		t.Errorf("unexpected ticket comment for a closed PR: %s", body)
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("expected success for a closed PR, got %v", err)
+	}
+	if !slices.Equal(labels, []string{"ai-rejected"}) {
+		t.Errorf("labels added = %v, want [ai-rejected]", labels)
+	}
+}
+
 // --- No new comments ---
 
 func TestExecuteFeedback_NoNewComments(t *testing.T) {
@@ -779,6 +1080,25 @@ func TestCategorizeComments_ExcludesBotComments(t *testing.T) {
 	}
 }
 
+func TestCategorizeComments_IncludesBotQuestionsAsContext(t *testing.T) {
+	comments := []models.PRComment{
+		{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Make this configurable", IsReviewComment: true},
+		{ID: 2, Author: models.Author{Username: "bot"}, Body: "Per repo or per project?\n" + commentfilter.QuestionMarker,
+			InReplyTo: 1, IsReviewComment: true, Timestamp: time.Unix(100, 0)},
+		{ID: 3, Author: models.Author{Username: "reviewer"}, Body: "Per project.",
+			InReplyTo: 1, IsReviewComment: true, Timestamp: time.Unix(200, 0)},
+	}
+
+	newC, addrC := executor.CategorizeComments(comments, "bot")
+
+	if len(newC) != 1 || newC[0].ID != 3 {
+		t.Errorf("new = %v, want [the answer, comment 3]", newC)
+	}
+	if len(addrC) != 2 || addrC[0].ID != 1 || addrC[1].ID != 2 {
+		t.Errorf("addressed = %v, want [comment 1, question 2]", addrC)
+	}
+}
+
 func TestCategorizeComments_AllNew(t *testing.T) {
 	comments := []models.PRComment{
 		{ID: 1, Author: models.Author{Username: "reviewer1"}, Body: "Fix this"},
@@ -1144,7 +1464,7 @@ func newFeedbackDeps(t *testing.T) *testDeps {
 	d := newTestDeps(t)
 
 	// Override defaults for feedback-specific methods.
-	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string) (string, bool, error) {
+	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string, _ []string) (string, bool, error) {
 		return d.wsDir, true, nil // reused workspace
 	}
 	d.git.GetPRForBranchFunc = func(owner, repo, head string) (*models.PRDetails, error) {
@@ -1319,6 +1639,29 @@ func TestMultiRepoFeedback_NoPRsFound(t *testing.T) {
 	}
 }
 
+func TestMultiRepoFeedback_PRsMergedSinceScan(t *testing.T) {
+	d := newMultiRepoFeedbackDeps(t)
+
+	d.git.GetPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+		return nil, nil
+	}
+	d.git.GetMergedPRForBranchFunc = func(_, repo, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, URL: "https://github.com/org/" + repo + "/pull/42"}, nil
+	}
+	cloned := false
+	d.workspaces.FindOrCreateMultiRepoFunc = func(_ string, _ []workspace.RepoEntry, _ string) (string, bool, error) {
+		cloned = true
+		return d.wsDir, true, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("expected success for merged PRs, got %v", err)
+	}
+	if cloned {
+		t.Error("workspace should not be prepared for merged PRs")
+	}
+}
+
 func TestMultiRepoFeedback_SkipsSyncForReposWithoutPRs(t *testing.T) {
 	d := newMultiRepoFeedbackDeps(t)
 
@@ -1429,12 +1772,9 @@ func TestExecuteFeedback_ReactsToNewComments(t *testing.T) {
 		return "abc123", nil
 	}
 
-	var reacted []int64
+	var reacted []string
 	d.git.AddCommentReactionFunc = func(_, _ string, comment models.PRComment, reaction string) error {
-		if reaction != "eyes" {
-			t.Errorf("reaction = %q, want eyes", reaction)
-		}
-		reacted = append(reacted, comment.ID)
+		reacted = append(reacted, fmt.Sprintf("%d:%s", comment.ID, reaction))
 		return nil
 	}
 
@@ -1443,8 +1783,47 @@ func TestExecuteFeedback_ReactsToNewComments(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(reacted) != 1 || reacted[0] != 1 {
-		t.Errorf("reacted comment IDs = %v, want [1]", reacted)
+	// Eyes when work starts, rocket once the fix is pushed.
+	want := []string{"1:eyes", "1:rocket"}
+	if !slices.Equal(reacted, want) {
+		t.Errorf("reactions = %v, want %v", reacted, want)
+	}
+}
+
+func TestExecuteFeedback_NoFixPushedReactionWithoutCommit(t *testing.T) {
+	d := newFeedbackDeps(t)
+
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		return "", errors.New("commit failed")
+	}
+
+	var reactions []string
+	d.git.AddCommentReactionFunc = func(_, _ string, _ models.PRComment, reaction string) error {
+		reactions = append(reactions, reaction)
+		return nil
+	}
+
+	p := d.pipeline(t)
+	_, _ = p.Execute(context.Background(), newFeedbackJob("PROJ-1"))
+	if slices.Contains(reactions, "rocket") {
+		t.Errorf("reactions = %v, want no rocket when nothing was pushed", reactions)
+	}
+}
+
+func TestExecuteFeedback_NoReactionsWhenAINeverRan(t *testing.T) {
+	d := newFeedbackDeps(t)
+
+	d.containers.StartFunc = func(ctx context.Context, cfg *container.Config, wsDir, ticketKey string, env map[string]string) (*container.Container, error) {
+		return nil, errors.New("runtime unavailable")
+	}
+	d.git.AddCommentReactionFunc = func(_, _ string, _ models.PRComment, reaction string) error {
+		t.Errorf("unexpected %s reaction: the comments never reached the AI", reaction)
+		return nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err == nil {
+		t.Fatal("expected an error when the container fails to start")
 	}
 }
 
@@ -1659,13 +2038,11 @@ func TestExecuteMultiRepoFeedback_ReactsPerRepo(t *testing.T) {
 	type reactionCall struct {
 		repo      string
 		commentID int64
+		reaction  string
 	}
 	var reactions []reactionCall
 	d.git.AddCommentReactionFunc = func(_, repo string, comment models.PRComment, reaction string) error {
-		if reaction != "eyes" {
-			t.Errorf("reaction = %q, want eyes", reaction)
-		}
-		reactions = append(reactions, reactionCall{repo: repo, commentID: comment.ID})
+		reactions = append(reactions, reactionCall{repo: repo, commentID: comment.ID, reaction: reaction})
 		return nil
 	}
 
@@ -1674,13 +2051,11 @@ func TestExecuteMultiRepoFeedback_ReactsPerRepo(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(reactions) != 1 {
-		t.Fatalf("expected 1 reaction, got %d", len(reactions))
-	}
-	if reactions[0].repo != "svc-a" {
-		t.Errorf("reaction repo = %q, want svc-a", reactions[0].repo)
+	want := []reactionCall{
+		{repo: "svc-a", commentID: 100, reaction: "eyes"},
+		{repo: "svc-a", commentID: 100, reaction: "rocket"},
 	}
-	if reactions[0].commentID != 100 {
-		t.Errorf("reaction comment ID = %d, want 100", reactions[0].commentID)
+	if !slices.Equal(reactions, want) {
+		t.Errorf("reactions = %+v, want %+v", reactions, want)
 	}
 }
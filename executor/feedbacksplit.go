@@ -0,0 +1,211 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// feedbackGroup is the part of a feedback round addressed by one AI
+// session when the round is split (see
+// models.ProjectConfig.FeedbackSplitThreshold).
+type feedbackGroup struct {
+	// name identifies the group in logs: a file path, or "general"
+	// for the group of general comments and CI failures.
+	name       string
+	comments   []models.PRComment
+	ciFailures []models.CheckRunFailure
+}
+
+// splitFeedback partitions a feedback round into one group per file
+// with review comments, in file path order, followed by a group with
+// the general (non-file) comments and the CI failures. It returns nil
+// when the round should be addressed in a single session: splitting
+// is disabled (threshold zero), the round has fewer than threshold
+// new comments, or its comments are all on one file.
+func splitFeedback(comments []models.PRComment, ciFailures []models.CheckRunFailure, threshold int) []feedbackGroup {
+	if threshold <= 0 || len(comments) < threshold {
+		return nil
+	}
+
+	grouped := make(map[string][]models.PRComment)
+	for _, c := range comments {
+		grouped[c.FilePath] = append(grouped[c.FilePath], c)
+	}
+	general := grouped[""]
+	delete(grouped, "")
+	if len(grouped) < 2 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(grouped))
+	for path := range grouped {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	groups := make([]feedbackGroup, 0, len(paths)+1)
+	for _, path := range paths {
+		groups = append(groups, feedbackGroup{name: path, comments: grouped[path]})
+	}
+	if len(general) > 0 || len(ciFailures) > 0 {
+		groups = append(groups, feedbackGroup{name: "general", comments: general, ciFailures: ciFailures})
+	}
+	return groups
+}
+
+// splitSessionsResult is the combined outcome of the AI sessions run
+// for a split feedback round.
+type splitSessionsResult struct {
+	// exitCode is the first non-zero session exit code, or zero when
+	// every session succeeded.
+	exitCode int
+
+	// session combines the sessions' outputs: costs and token counts
+	// are summed, validation passes only if no session reported a
+	// failure, and the summaries are joined.
+	session SessionOutput
+
+	// execErr is the error of a session that could not be run. No
+	// further sessions are started after one.
+	execErr error
+
+	// timedOut reports that execErr was caused by the session
+	// timeout.
+	timedOut bool
+}
+
+// runSplitFeedbackSessions addresses a split feedback round with one
+// AI session per group. The sessions run one after another in the
+// same workspace and container, so each sees the changes made by the
+// ones before it, and the changes are committed together afterwards.
+// Each session gets its own feedback task file, listing only its
+// group's comments (with all addressed comments as context), and its
+// own session timeout.
+//
+// The per-comment responses of all sessions are merged into
+// taskfile.CommentResponsesPath so replies are posted as for a single
+// session.
+func (p *Pipeline) runSplitFeedbackSessions(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	ctr *container.Container,
+	wsPath string,
+	sp scriptParams,
+	prDetails models.PRDetails,
+	addressedComments []models.PRComment,
+	settings *models.ProjectSettings,
+	groups []feedbackGroup,
+) splitSessionsResult {
+	var res splitSessionsResult
+	responses := make(map[int64]CommentResponse)
+	var summaries []string
+
+	for i, g := range groups {
+		logger := logger.With(
+			zap.String("feedback_group", g.name),
+			zap.Int("group", i+1),
+			zap.Int("groups", len(groups)))
+
+		if err := p.taskWriter.WriteFeedbackTask(
+			prDetails, g.comments, addressedComments, g.ciFailures, wsPath,
+			settings.Repos[0].Instructions, settings.Repos[0].FeedbackWorkflow,
+		); err != nil {
+			res.execErr = fmt.Errorf("write task file for %s: %w", g.name, err)
+			break
+		}
+		cleanAIOutputs(logger, wsPath)
+		p.reactToComments(logger, settings.Repos[0].Owner, settings.Repos[0].Repo, g.comments, reactionWorking)
+
+		sessCtx := ctx
+		cancel := func() {}
+		if p.cfg.SessionTimeout > 0 {
+			sessCtx, cancel = context.WithTimeout(ctx, p.cfg.SessionTimeout)
+		}
+		exitCode, err := p.runAISession(sessCtx, logger, job, ctr, wsPath, sp)
+		timedOut := sessCtx.Err() != nil && ctx.Err() == nil
+		cancel()
+
+		session := readSessionOutput(wsPath)
+		p.applyCostEstimate(&session)
+		addSessionOutput(&res.session, session)
+		if session.Summary != "" {
+			summaries = append(summaries, session.Summary)
+		}
+		for id, r := range readCommentResponses(wsPath) {
+			responses[id] = r
+		}
+
+		logger.Info("AI feedback group session completed",
+			zap.Int("comments", len(g.comments)),
+			zap.Int("exit_code", exitCode),
+			zap.Float64("cost_usd", session.CostUSD))
+
+		if err != nil {
+			res.execErr = err
+			res.timedOut = timedOut
+			break
+		}
+		if exitCode != 0 && res.exitCode == 0 {
+			res.exitCode = exitCode
+		}
+	}
+
+	res.session.ExitCode = res.exitCode
+	res.session.Summary = strings.Join(summaries, "\n\n")
+	writeCommentResponses(logger, wsPath, responses)
+	return res
+}
+
+// addSessionOutput adds the cost, token counts, and validation result
+// of session to total.
+func addSessionOutput(total *SessionOutput, session SessionOutput) {
+	total.CostUSD += session.CostUSD
+	total.InputTokens += session.InputTokens
+	total.OutputTokens += session.OutputTokens
+	total.CachedTokens += session.CachedTokens
+
+	if session.ValidationPassed != nil && (total.ValidationPassed == nil || *total.ValidationPassed) {
+		passed := *session.ValidationPassed
+		total.ValidationPassed = &passed
+	}
+}
+
+// writeCommentResponses writes per-comment responses to
+// taskfile.CommentResponsesPath in the format the AI uses, for
+// [readCommentResponses]. Nothing is written when there are none.
+func writeCommentResponses(logger *zap.Logger, wsPath string, responses map[int64]CommentResponse) {
+	if len(responses) == 0 {
+		return
+	}
+
+	list := make([]CommentResponse, 0, len(responses))
+	for _, r := range responses {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CommentID < list[j].CommentID })
+
+	path := filepath.Join(wsPath, taskfile.CommentResponsesPath)
+	data, err := json.Marshal(list)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o750)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0o600)
+	}
+	if err != nil {
+		logger.Warn("Failed to write merged comment responses", zap.Error(err))
+	}
+}
@@ -0,0 +1,123 @@
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/models"
+)
+
+// newSplitFeedbackDeps returns feedback deps for a round of four new
+// comments: two on a.go, one on b.go, and one general comment.
+func newSplitFeedbackDeps(t *testing.T, threshold int) *testDeps {
+	t.Helper()
+	d := newFeedbackDeps(t)
+
+	d.git.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{
+			{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "a1", FilePath: "a.go", Line: 3, IsReviewComment: true},
+			{ID: 2, Author: models.Author{Username: "reviewer"}, Body: "b1", FilePath: "b.go", Line: 7, IsReviewComment: true},
+			{ID: 3, Author: models.Author{Username: "reviewer"}, Body: "a2", FilePath: "a.go", Line: 9, IsReviewComment: true},
+			{ID: 4, Author: models.Author{Username: "reviewer"}, Body: "general"},
+		}, nil
+	}
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		return "abc1234567890", nil
+	}
+
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err != nil {
+			return nil, err
+		}
+		settings.FeedbackSplitThreshold = threshold
+		return settings, nil
+	}
+	return d
+}
+
+func TestExecuteFeedback_SplitsLargeRoundByFile(t *testing.T) {
+	d := newSplitFeedbackDeps(t, 3)
+
+	var taskComments [][]int64
+	d.taskWriter.WriteFeedbackTaskFunc = func(_ models.PRDetails, newC, _ []models.PRComment, _ []models.CheckRunFailure, _, _, _ string) error {
+		var ids []int64
+		for _, c := range newC {
+			ids = append(ids, c.ID)
+		}
+		taskComments = append(taskComments, ids)
+		return nil
+	}
+
+	// Each session answers the comments in the task file it was given.
+	sessions := 0
+	d.containers.ExecFunc = func(_ context.Context, _ *container.Container, _ []string) (string, int, error) {
+		sessions++
+		var responses []string
+		for _, id := range taskComments[len(taskComments)-1] {
+			responses = append(responses, fmt.Sprintf(`{"comment_id": %d, "response": "Fixed %d."}`, id, id))
+		}
+		writeCommentResponses(t, d.wsDir, "["+strings.Join(responses, ",")+"]")
+		return "", 0, nil
+	}
+
+	replies := make(map[int64]string)
+	d.git.ReplyToCommentFunc = func(_, _ string, _ int, id int64, body string) error {
+		replies[id] = body
+		return nil
+	}
+	var issueComments []string
+	d.git.PostIssueCommentFunc = func(_, _ string, _ int, body string) error {
+		issueComments = append(issueComments, body)
+		return nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sessions != 3 {
+		t.Errorf("AI sessions = %d, want 3 (a.go, b.go, general)", sessions)
+	}
+	// The first task file lists the whole round; the split sessions
+	// each get their own.
+	want := [][]int64{{1, 2, 3, 4}, {1, 3}, {2}, {4}}
+	if !slices.EqualFunc(taskComments, want, slices.Equal[[]int64]) {
+		t.Errorf("task file comments = %v, want %v", taskComments, want)
+	}
+
+	// Responses from every session are posted.
+	for _, id := range []int64{1, 2, 3} {
+		if !strings.Contains(replies[id], fmt.Sprintf("Fixed %d.", id)) {
+			t.Errorf("reply to %d = %q, want the AI response", id, replies[id])
+		}
+	}
+	if len(issueComments) != 1 || !strings.Contains(issueComments[0], "Fixed 4.") {
+		t.Errorf("conversation replies = %q, want the response to comment 4", issueComments)
+	}
+}
+
+func TestExecuteFeedback_NoSplitBelowThreshold(t *testing.T) {
+	d := newSplitFeedbackDeps(t, 5)
+
+	sessions := 0
+	d.containers.ExecFunc = func(_ context.Context, _ *container.Container, _ []string) (string, int, error) {
+		sessions++
+		return "", 0, nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sessions != 1 {
+		t.Errorf("AI sessions = %d, want 1", sessions)
+	}
+}
@@ -0,0 +1,251 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+const feedbackSummaryMarker = "<!-- AI-BOT-FEEDBACK-SUMMARY -->"
+
+// maxUnaddressedRounds is the number of feedback rounds a comment may
+// be left unaddressed before the bot gives up on it and replies, which
+// stops it from being picked up again.
+const maxUnaddressedRounds = 3
+
+// unaddressedRe matches the summary comment's record of how many
+// rounds each comment has been left unaddressed, e.g.
+// "<!-- not-addressed: 12=1,15=2 -->".
+var unaddressedRe = regexp.MustCompile(`<!-- not-addressed: ([\d=,]*) -->`)
+
+// noReportReason explains why a comment the AI did not mention in its
+// comment responses is treated as not addressed.
+const noReportReason = "The AI session did not report on this comment."
+
+// partitionUnaddressed splits a feedback round's comments into those
+// the AI handled (addressed, or answered with a question) and those it
+// left unaddressed: reported as not_addressed, or missing from its
+// responses. Missing comments are added to responses as not addressed
+// with [noReportReason]. When the AI wrote no responses at all, every
+// comment counts as handled.
+func partitionUnaddressed(comments []models.PRComment, responses map[int64]CommentResponse) (handled, unaddressed []models.PRComment) {
+	if responses == nil {
+		return comments, nil
+	}
+	for _, c := range comments {
+		r, ok := responses[c.ID]
+		if !ok {
+			responses[c.ID] = CommentResponse{CommentID: c.ID, NotAddressed: true, Response: noReportReason}
+			unaddressed = append(unaddressed, c)
+			continue
+		}
+		if r.NotAddressed && r.Question == "" {
+			unaddressed = append(unaddressed, c)
+			continue
+		}
+		handled = append(handled, c)
+	}
+	return handled, unaddressed
+}
+
+// reportPartialFeedback handles a feedback round in which the AI left
+// some comments unaddressed. Unaddressed comments get no reply, so
+// they stay eligible for the next feedback round, and a summary of
+// addressed and unaddressed comments (with the AI's reasons) is posted
+// to the PR, or updated in place on later rounds. A comment left
+// unaddressed for [maxUnaddressedRounds] rounds is given up on: it is
+// returned for a reply so it is not picked up again.
+//
+// Returns the comments to reply to. The summary is best-effort;
+// errors are logged.
+func (p *Pipeline) reportPartialFeedback(
+	logger *zap.Logger,
+	owner, repo string,
+	prNumber int,
+	comments []models.PRComment,
+	responses map[int64]CommentResponse,
+	sha string,
+) []models.PRComment {
+	handled, unaddressed := partitionUnaddressed(comments, responses)
+	if len(unaddressed) == 0 {
+		return comments
+	}
+
+	existing, err := p.git.ListIssueComments(owner, repo, prNumber)
+	if err != nil {
+		logger.Warn("Failed to list PR comments for feedback summary", zap.Error(err))
+	}
+	var previous *models.IssueComment
+	for i := range existing {
+		if strings.Contains(existing[i].Body, feedbackSummaryMarker) {
+			previous = &existing[i]
+			break
+		}
+	}
+	rounds := map[int64]int{}
+	if previous != nil {
+		rounds = parseUnaddressedRounds(previous.Body)
+	}
+
+	var pending, givenUp []models.PRComment
+	for _, c := range unaddressed {
+		rounds[c.ID]++
+		if rounds[c.ID] >= maxUnaddressedRounds {
+			givenUp = append(givenUp, c)
+		} else {
+			pending = append(pending, c)
+		}
+	}
+	logger.Info("AI left review comments unaddressed",
+		zap.Int("addressed", len(handled)),
+		zap.Int("unaddressed", len(unaddressed)),
+		zap.Int("given_up", len(givenUp)))
+
+	body := formatFeedbackSummary(handled, pending, givenUp, responses, rounds, sha)
+	if previous != nil {
+		err = p.git.UpdateIssueComment(owner, repo, previous.ID, body)
+	} else {
+		err = p.git.PostIssueComment(owner, repo, prNumber, body)
+	}
+	if err != nil {
+		logger.Warn("Failed to post feedback summary", zap.Error(err))
+	}
+
+	return append(handled, givenUp...)
+}
+
+// formatFeedbackSummary renders the partial-success summary comment.
+func formatFeedbackSummary(
+	handled, pending, givenUp []models.PRComment,
+	responses map[int64]CommentResponse,
+	rounds map[int64]int,
+	sha string,
+) string {
+	var addressed, questions []models.PRComment
+	for _, c := range handled {
+		if responses[c.ID].Question != "" {
+			questions = append(questions, c)
+		} else {
+			addressed = append(addressed, c)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(feedbackSummaryMarker)
+	b.WriteString("\n**AI Feedback Summary**\n\n")
+	total := len(handled) + len(pending) + len(givenUp)
+	fmt.Fprintf(&b, "Addressed %d of %d review comments", len(addressed), total)
+	if sha != "" {
+		fmt.Fprintf(&b, " in %s", shortCommitSHA(sha))
+	}
+	b.WriteString(".")
+	if len(pending) > 0 {
+		b.WriteString(" Comments not addressed stay open and are retried in the next feedback round.")
+	}
+	b.WriteString("\n")
+
+	if len(addressed) > 0 {
+		b.WriteString("\n**Addressed**\n")
+		for _, c := range addressed {
+			fmt.Fprintf(&b, "- %s\n", describeComment(c))
+		}
+	}
+	if len(questions) > 0 {
+		b.WriteString("\n**Awaiting an answer**\n")
+		for _, c := range questions {
+			fmt.Fprintf(&b, "- %s\n", describeComment(c))
+		}
+	}
+	if len(pending) > 0 || len(givenUp) > 0 {
+		b.WriteString("\n**Not addressed**\n")
+		for _, c := range pending {
+			fmt.Fprintf(&b, "- %s: %s (round %d of %d)\n",
+				describeComment(c), notAddressedReason(responses[c.ID]), rounds[c.ID], maxUnaddressedRounds)
+		}
+		for _, c := range givenUp {
+			fmt.Fprintf(&b, "- %s: %s (gave up after %d rounds)\n",
+				describeComment(c), notAddressedReason(responses[c.ID]), maxUnaddressedRounds)
+		}
+	}
+
+	b.WriteString(formatUnaddressedRounds(rounds))
+	return b.String()
+}
+
+// describeComment renders a short reference to a PR comment for the
+// feedback summary.
+func describeComment(c models.PRComment) string {
+	desc := "@" + c.Author.Username
+	switch {
+	case c.FilePath != "" && c.Line > 0:
+		desc += fmt.Sprintf(" on `%s` line %d", c.FilePath, c.Line)
+	case c.FilePath != "":
+		desc += fmt.Sprintf(" on `%s`", c.FilePath)
+	}
+	if c.URL != "" {
+		desc = fmt.Sprintf("[%s](%s)", desc, c.URL)
+	}
+	return desc
+}
+
+// notAddressedReason returns the AI's reason for not addressing a
+// comment, collapsed to one line.
+func notAddressedReason(r CommentResponse) string {
+	reason := strings.Join(strings.Fields(r.Response), " ")
+	if reason == "" {
+		return "no reason given"
+	}
+	return reason
+}
+
+// parseUnaddressedRounds reads the per-comment unaddressed round
+// counts recorded in a feedback summary comment.
+func parseUnaddressedRounds(body string) map[int64]int {
+	rounds := make(map[int64]int)
+	m := unaddressedRe.FindStringSubmatch(body)
+	if m == nil {
+		return rounds
+	}
+	for _, entry := range strings.Split(m[1], ",") {
+		idStr, nStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		id, err1 := strconv.ParseInt(idStr, 10, 64)
+		n, err2 := strconv.Atoi(nStr)
+		if err1 == nil && err2 == nil {
+			rounds[id] = n
+		}
+	}
+	return rounds
+}
+
+// formatUnaddressedRounds renders round counts for
+// [parseUnaddressedRounds], in comment ID order.
+func formatUnaddressedRounds(rounds map[int64]int) string {
+	ids := make([]int64, 0, len(rounds))
+	for id := range rounds {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	entries := make([]string, len(ids))
+	for i, id := range ids {
+		entries[i] = fmt.Sprintf("%d=%d", id, rounds[id])
+	}
+	return fmt.Sprintf("\n<!-- not-addressed: %s -->", strings.Join(entries, ","))
+}
+
+// shortCommitSHA abbreviates a commit SHA for display.
+func shortCommitSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+)
+
+// reconcileHumanPush checks, before the bot pushes feedback changes,
+// whether the PR branch has moved past pr.HeadSHA, the head the
+// workspace was synced to. Commits pushed during the AI session would
+// be lost if the bot's commit replaced them, so they are handled as
+// policy (see [models.ProjectConfig.HumanPushPolicy]) selects:
+//
+//   - rebase (the default): the AI's changes in dir are replayed onto
+//     the new head, and pr.HeadSHA is updated. A conflicting rebase is
+//     deferred.
+//   - defer: nothing is pushed, and a PR comment tells the reviewers
+//     why.
+//
+// Returns true when the round was deferred; its comments get no reply,
+// so they are retried in the next feedback round.
+func (p *Pipeline) reconcileHumanPush(
+	logger *zap.Logger,
+	dir, owner, repo string,
+	heads []string,
+	branchName string,
+	pr *models.PRDetails,
+	policy string,
+) (bool, error) {
+	if pr.HeadSHA == "" {
+		return false, nil
+	}
+	current, err := p.findPRByHeads(owner, repo, heads)
+	if err != nil {
+		return false, fmt.Errorf("check PR head: %w", err)
+	}
+	if current.HeadSHA == "" || current.HeadSHA == pr.HeadSHA {
+		return false, nil
+	}
+
+	logger = logger.With(
+		zap.String("repo", owner+"/"+repo),
+		zap.String("synced_sha", pr.HeadSHA),
+		zap.String("head_sha", current.HeadSHA))
+	logger.Info("PR branch was pushed to during the AI session")
+
+	var conflicts []string
+	if policy != models.HumanPushDefer {
+		conflicts, err = p.git.RebaseOnRemote(dir, branchName, pr.HeadSHA)
+		switch {
+		case err == nil:
+			logger.Info("Rebased AI changes onto the new commits")
+			pr.HeadSHA = current.HeadSHA
+			return false, nil
+		case errors.Is(err, services.ErrMergeConflict):
+			logger.Info("Rebasing AI changes onto the new commits conflicted, deferring",
+				zap.Strings("conflicts", conflicts))
+		default:
+			return false, fmt.Errorf("rebase onto new commits: %w", err)
+		}
+	}
+
+	body := formatHumanPushComment(pr.HeadSHA, current.HeadSHA, conflicts)
+	if err := p.git.PostIssueComment(owner, repo, pr.Number, body); err != nil {
+		logger.Warn("Failed to post deferred feedback comment", zap.Error(err))
+	}
+	return true, nil
+}
+
+// formatHumanPushComment renders the PR comment posted when a feedback
+// round is deferred because the branch moved from syncedSHA to headSHA
+// during the AI session. conflicts lists the files a rebase conflicted
+// in, if one was attempted.
+func formatHumanPushComment(syncedSHA, headSHA string, conflicts []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "New commits were pushed to this branch while I was addressing review feedback (%s → %s). ",
+		shortCommitSHA(syncedSHA), shortCommitSHA(headSHA))
+	b.WriteString("To avoid overwriting them, I have not pushed my changes.")
+	if len(conflicts) > 0 {
+		quoted := make([]string, len(conflicts))
+		for i, f := range conflicts {
+			quoted[i] = "`" + f + "`"
+		}
+		fmt.Fprintf(&b, " Rebasing my changes onto the new commits conflicted in %s.", strings.Join(quoted, ", "))
+	}
+	b.WriteString("\n\nThe review comments stay open and will be addressed on top of the new commits in the next feedback round.")
+	return b.String()
+}
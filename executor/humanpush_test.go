@@ -0,0 +1,165 @@
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+)
+
+// newHumanPushDeps returns feedback deps whose PR head is "aaa1111"
+// when the workspace is synced and "bbb2222" when re-checked before
+// committing, as if someone pushed during the AI session. The commit
+// count is recorded in *commits.
+func newHumanPushDeps(t *testing.T, policy string, commits *int) *testDeps {
+	t.Helper()
+	d := newFeedbackDeps(t)
+
+	lookups := 0
+	d.git.GetPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		lookups++
+		sha := "aaa1111"
+		if lookups > 1 {
+			sha = "bbb2222"
+		}
+		return &models.PRDetails{Number: 42, Branch: head, HeadSHA: sha}, nil
+	}
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		*commits++
+		return "ccc3333", nil
+	}
+
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err != nil {
+			return nil, err
+		}
+		settings.HumanPushPolicy = policy
+		return settings, nil
+	}
+	return d
+}
+
+func TestExecuteFeedback_RebasesOntoCommitsPushedDuringSession(t *testing.T) {
+	var commits int
+	d := newHumanPushDeps(t, "", &commits)
+
+	var rebaseBase string
+	d.git.RebaseOnRemoteFunc = func(dir, _, base string) ([]string, error) {
+		if dir != d.wsDir {
+			t.Errorf("rebase dir = %q, want %q", dir, d.wsDir)
+		}
+		rebaseBase = base
+		return []string{}, nil
+	}
+	var replies int
+	d.git.ReplyToCommentFunc = func(_, _ string, _ int, _ int64, _ string) error {
+		replies++
+		return nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rebaseBase != "aaa1111" {
+		t.Errorf("rebase base = %q, want the synced head aaa1111", rebaseBase)
+	}
+	if commits != 1 {
+		t.Errorf("commits = %d, want 1", commits)
+	}
+	if replies != 1 {
+		t.Errorf("replies = %d, want 1", replies)
+	}
+}
+
+func TestExecuteFeedback_DefersToCommitsPushedDuringSession(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		rebaseErr     error
+		wantRebase    bool
+		wantInComment string
+	}{
+		{
+			name:       "defer policy",
+			policy:     models.HumanPushDefer,
+			wantRebase: false,
+		},
+		{
+			name:          "rebase conflict",
+			policy:        models.HumanPushRebase,
+			rebaseErr:     fmt.Errorf("%w: conflicted files: [main.go]", services.ErrMergeConflict),
+			wantRebase:    true,
+			wantInComment: "conflicted in `main.go`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commits int
+			d := newHumanPushDeps(t, tt.policy, &commits)
+
+			rebased := false
+			d.git.RebaseOnRemoteFunc = func(_, _, _ string) ([]string, error) {
+				rebased = true
+				if tt.rebaseErr != nil {
+					return []string{"main.go"}, tt.rebaseErr
+				}
+				return []string{}, nil
+			}
+			var replies int
+			d.git.ReplyToCommentFunc = func(_, _ string, _ int, _ int64, _ string) error {
+				replies++
+				return nil
+			}
+			var comments []string
+			d.git.PostIssueCommentFunc = func(_, _ string, _ int, body string) error {
+				comments = append(comments, body)
+				return nil
+			}
+
+			p := d.pipeline(t)
+			if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if rebased != tt.wantRebase {
+				t.Errorf("rebased = %v, want %v", rebased, tt.wantRebase)
+			}
+			if commits != 0 {
+				t.Errorf("commits = %d, want 0", commits)
+			}
+			// The comments stay open for the next round.
+			if replies != 0 {
+				t.Errorf("replies = %d, want 0", replies)
+			}
+			if len(comments) != 1 || !strings.Contains(comments[0], "aaa1111 → bbb2222") {
+				t.Fatalf("PR comments = %q, want one deferral comment", comments)
+			}
+			if !strings.Contains(comments[0], tt.wantInComment) {
+				t.Errorf("PR comment = %q, want it to contain %q", comments[0], tt.wantInComment)
+			}
+		})
+	}
+}
+
+func TestExecuteFeedback_NoRebaseWhenHeadUnchanged(t *testing.T) {
+	d := newFeedbackDeps(t)
+	d.git.GetPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, HeadSHA: "aaa1111"}, nil
+	}
+	d.git.RebaseOnRemoteFunc = func(_, _, _ string) ([]string, error) {
+		t.Error("unexpected rebase")
+		return []string{}, nil
+	}
+
+	p := d.pipeline(t)
+	if _, err := p.Execute(context.Background(), newFeedbackJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// checkIssueLinks defers tickets whose links say they should not be
+// worked on yet. A ticket blocked by an unresolved issue waits until
+// every blocker is resolved. A ticket marked as a duplicate of an issue
+// that already has an open bot PR waits too, with a status comment
+// pointing at that PR, so that the bot does not solve the same problem
+// twice. Duplicates of issues without a PR are processed normally.
+func (p *Pipeline) checkIssueLinks(logger *zap.Logger, workItem *models.WorkItem, settings *models.ProjectSettings) error {
+	if blockers := workItem.UnresolvedBlockers(); len(blockers) > 0 {
+		keys := make([]string, len(blockers))
+		for i, b := range blockers {
+			keys[i] = b.Key
+		}
+		logger.Info("Ticket is blocked by unresolved issues, deferring",
+			zap.Strings("blockers", keys))
+		p.upsertStatusComment(logger, workItem.Key, formatBlockedComment(blockers))
+		return fmt.Errorf("blocked by %s: %w", strings.Join(keys, ", "), jobmanager.ErrDeferred)
+	}
+
+	for _, original := range workItem.DuplicateOf() {
+		pr := p.findExistingPR(logger, original.Key, settings)
+		if pr == nil {
+			continue
+		}
+		logger.Info("Ticket duplicates an issue with an open PR, deferring",
+			zap.String("original", original.Key),
+			zap.String("url", pr.URL))
+		p.upsertStatusComment(logger, workItem.Key, formatDuplicateComment(original.Key, pr.URL))
+		return fmt.Errorf("duplicates %s, which has PR %s: %w", original.Key, pr.URL, jobmanager.ErrDeferred)
+	}
+	return nil
+}
+
+// formatBlockedComment builds the status comment posted while a ticket
+// waits for its blockers. The body depends only on the blockers so
+// that repeated deferrals do not rewrite the comment.
+func formatBlockedComment(blockers []models.IssueLink) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s Waiting to start: this ticket is blocked by unresolved issues:\n", statusCommentMarker)
+	for _, l := range blockers {
+		fmt.Fprintf(&b, "- %s (%s): %s\n", l.Key, l.Status, l.Summary)
+	}
+	b.WriteString("It stays queued and will be picked up automatically once they are resolved.")
+	return b.String()
+}
+
+// formatDuplicateComment builds the status comment posted on a ticket
+// that duplicates an issue the bot already opened a PR for.
+func formatDuplicateComment(originalKey, prURL string) string {
+	return fmt.Sprintf("%s Not starting: this ticket duplicates %s, which already has a pull request: %s. "+
+		"Remove the duplicate link if this ticket needs separate changes.",
+		statusCommentMarker, originalKey, prURL)
+}
@@ -0,0 +1,84 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// withLinks makes the tracker return work items with the given links.
+func withLinks(d *testDeps, links ...models.IssueLink) {
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Summary: "Test ticket", Type: "Bug", Links: links}, nil
+	}
+}
+
+func TestExecuteNewTicket_BlockedByUnresolvedIssueDefers(t *testing.T) {
+	d := newTestDeps(t)
+	withLinks(d,
+		models.IssueLink{Type: "Blocks", Relation: "is blocked by", Key: "PROJ-2", Summary: "Schema change", Status: "In Progress"},
+		models.IssueLink{Type: "Blocks", Relation: "is blocked by", Key: "PROJ-3", Status: "Done", Resolved: true},
+	)
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if !errors.Is(err, jobmanager.ErrDeferred) {
+		t.Fatalf("error = %v, want ErrDeferred", err)
+	}
+	if len(transitions) != 0 {
+		t.Errorf("status transitions = %v, want none", transitions)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "PROJ-2") || strings.Contains(comments[0], "PROJ-3") {
+		t.Errorf("comments = %q, want one status comment naming only PROJ-2", comments)
+	}
+}
+
+func TestExecuteNewTicket_DuplicateOfIssueWithPRDefers(t *testing.T) {
+	d := newTestDeps(t)
+	withLinks(d, models.IssueLink{Type: "Duplicate", Relation: "duplicates", Outward: true, Key: "PROJ-9"})
+	d.git.GetPRForBranchFunc = func(owner, repo, head string) (*models.PRDetails, error) {
+		if head == "ai-bot/PROJ-9" {
+			return &models.PRDetails{Number: 12, URL: "https://github.com/org/repo/pull/12"}, nil
+		}
+		return nil, nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if !errors.Is(err, jobmanager.ErrDeferred) {
+		t.Fatalf("error = %v, want ErrDeferred", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "https://github.com/org/repo/pull/12") {
+		t.Errorf("comments = %q, want one status comment linking the original's PR", comments)
+	}
+}
+
+func TestExecuteNewTicket_DuplicateWithoutPRProceeds(t *testing.T) {
+	d := newTestDeps(t)
+	withLinks(d, models.IssueLink{Type: "Duplicate", Relation: "duplicates", Outward: true, Key: "PROJ-9"})
+
+	_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
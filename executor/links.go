@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// appendLinkContext saves the text of the allow-listed pages the
+// ticket's description and comments link to under
+// [taskfile.LinksDirPath] and lists them at the end of the task file,
+// so that a ticket that only says "see link" still tells the AI what
+// went wrong. Fetch errors are logged and the link skipped; the pages
+// are only context.
+func (p *Pipeline) appendLinkContext(ctx context.Context, logger *zap.Logger, workItem models.WorkItem, wsPath string) {
+	if p.cfg.Links == nil {
+		return
+	}
+	texts := []string{workItem.Description}
+	for _, c := range p.fetchTicketComments(logger, workItem) {
+		texts = append(texts, c.Body)
+	}
+	urls := p.cfg.Links.URLs(strings.Join(texts, "\n"))
+	if len(urls) == 0 {
+		return
+	}
+
+	dir := filepath.Join(wsPath, filepath.FromSlash(taskfile.LinksDirPath))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		logger.Warn("Failed to create linked pages directory", zap.Error(err))
+		return
+	}
+
+	var b strings.Builder
+	saved := 0
+	for _, u := range urls {
+		page, err := p.cfg.Links.Fetch(ctx, u)
+		if err != nil {
+			logger.Warn("Failed to fetch linked page", zap.String("url", u), zap.Error(err))
+			continue
+		}
+		if strings.TrimSpace(page.Text) == "" {
+			continue
+		}
+		saved++
+		name := fmt.Sprintf("%d-%s.txt", saved, linkFileName(u))
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(page.Text), 0o600); err != nil {
+			logger.Warn("Failed to save linked page", zap.String("url", u), zap.Error(err))
+			saved--
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: `%s`", u, path.Join(taskfile.LinksDirPath, name))
+		if page.Truncated {
+			b.WriteString(" (truncated)")
+		}
+		b.WriteString("\n")
+	}
+	if saved == 0 {
+		return
+	}
+
+	section := "\n## Linked Pages\n\n" +
+		"The ticket links to these pages. Their text was saved for you to read; " +
+		"it may be out of date or include unrelated content.\n\n" + b.String()
+	if err := appendToTaskFile(wsPath, section); err != nil {
+		logger.Warn("Failed to add linked pages to task file", zap.Error(err))
+		return
+	}
+	logger.Info("Saved linked pages for the AI", zap.Int("pages", saved))
+}
+
+// unsafeFileChars matches runs of characters kept out of linked page
+// file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// linkFileName returns a short file name derived from the link's host
+// and last path element, such as "github.com-42".
+func linkFileName(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "page"
+	}
+	name := u.Hostname()
+	if base := path.Base(u.Path); base != "." && base != "/" {
+		name += "-" + base
+	}
+	name = strings.Trim(unsafeFileChars.ReplaceAllString(name, "_"), "._-")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+	if name == "" {
+		return "page"
+	}
+	return name
+}
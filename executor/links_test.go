@@ -0,0 +1,120 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/linkcontext"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestExecuteNewTicket_SavesLinkedPages(t *testing.T) {
+	d := newTestDeps(t)
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{
+			Key:         key,
+			Summary:     "CI is red",
+			Description: "See https://ci.example.com/runs/42",
+			Type:        "Bug",
+			Components:  []string{},
+			Labels:      []string{},
+		}, nil
+	}
+	d.tracker.GetCommentsFunc = func(string) ([]models.Comment, error) {
+		return []models.Comment{{Body: "Also failing: https://ci.example.com/runs/43 and https://down.example.com/x"}}, nil
+	}
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir string, _ string, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+
+	var searched string
+	links := &executortest.StubLinkFetcher{
+		URLsFunc: func(text string) []string {
+			searched = text
+			return []string{"https://ci.example.com/runs/42", "https://down.example.com/x", "https://ci.example.com/runs/43"}
+		},
+		FetchFunc: func(_ context.Context, url string) (linkcontext.Page, error) {
+			switch url {
+			case "https://down.example.com/x":
+				return linkcontext.Page{}, errors.New("connection refused")
+			case "https://ci.example.com/runs/43":
+				return linkcontext.Page{URL: url, Text: "FAIL TestBar", Truncated: true}, nil
+			}
+			return linkcontext.Page{URL: url, Text: "FAIL TestFoo"}, nil
+		},
+	}
+	var task, saved string
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			data, _ := os.ReadFile(filepath.Join(req.Dir, taskfile.TaskFilePath))
+			task = string(data)
+			data, _ = os.ReadFile(filepath.Join(req.Dir, taskfile.LinksDirPath, "2-ci.example.com-43.txt"))
+			saved = string(data)
+			return agent.Result{}, nil
+		},
+	}
+
+	cfg := agentConfig(runner)
+	cfg.Links = links
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(searched, "runs/42") || !strings.Contains(searched, "runs/43") {
+		t.Errorf("links searched for in %q, want description and comments", searched)
+	}
+	for _, want := range []string{
+		"## Linked Pages",
+		"- https://ci.example.com/runs/42: `.ai-session/links/1-ci.example.com-42.txt`\n",
+		"- https://ci.example.com/runs/43: `.ai-session/links/2-ci.example.com-43.txt` (truncated)\n",
+	} {
+		if !strings.Contains(task, want) {
+			t.Errorf("task file missing %q:\n%s", want, task)
+		}
+	}
+	if strings.Contains(task, "down.example.com") {
+		t.Errorf("task file lists the page that failed to fetch:\n%s", task)
+	}
+	if saved != "FAIL TestBar" {
+		t.Errorf("saved page = %q, want its text", saved)
+	}
+}
+
+func TestExecuteNewTicket_NoLinkedPages(t *testing.T) {
+	d := newTestDeps(t)
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir string, _ string, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	links := &executortest.StubLinkFetcher{
+		FetchFunc: func(context.Context, string) (linkcontext.Page, error) {
+			t.Error("Fetch called without links")
+			return linkcontext.Page{}, nil
+		},
+	}
+	var task string
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			data, _ := os.ReadFile(filepath.Join(req.Dir, taskfile.TaskFilePath))
+			task = string(data)
+			return agent.Result{}, nil
+		},
+	}
+
+	cfg := agentConfig(runner)
+	cfg.Links = links
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(task, "## Linked Pages") {
+		t.Errorf("task file has a linked pages section without links:\n%s", task)
+	}
+}
@@ -27,7 +27,7 @@ func (p *Pipeline) executeMerge(ctx context.Context, job *jobmanager.Job) (resul
 	logger.Info("Starting merge pipeline")
 
 	// --- Step 1: Fetch work item ---
-	workItem, err := p.tracker.GetWorkItem(job.TicketKey)
+	workItem, err := p.jobWorkItem(job)
 	if err != nil {
 		return result, fmt.Errorf("get work item: %w", err)
 	}
@@ -78,7 +78,7 @@ func (p *Pipeline) executeSingleRepoMerge(
 	}
 
 	// --- Step 4: Find or create workspace ---
-	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, repo.CloneURL)
+	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, repo.CloneURL, repo.SparsePaths)
 	if err != nil {
 		return result, fmt.Errorf("prepare workspace: %w", err)
 	}
@@ -133,7 +133,7 @@ func (p *Pipeline) executeSingleRepoMerge(
 	if dlErr != nil {
 		return result, fmt.Errorf("download attachments: %w", dlErr)
 	}
-	comments := p.fetchTicketComments(logger, workItem.Key)
+	comments := p.fetchTicketComments(logger, *workItem)
 	if err := p.taskWriter.WriteIssue(*workItem, wsPath, downloaded, comments); err != nil {
 		return result, fmt.Errorf("write issue file: %w", err)
 	}
@@ -146,7 +146,6 @@ func (p *Pipeline) executeSingleRepoMerge(
 	// --- Step 10: Resolve and start container ---
 	provider := p.resolveProvider(settings)
 	sp := buildScriptParams(provider, p.cfg.DefaultClaudeModel, p.cfg.DefaultGeminiModel, repoCfg)
-	execCommand := buildExecCommand(sp)
 
 	ctr, err = p.startContainer(ctx, wsPath, job.TicketKey, provider, settings)
 	if err != nil {
@@ -178,7 +177,7 @@ func (p *Pipeline) executeSingleRepoMerge(
 		defer cancel()
 	}
 
-	_, exitCode, execErr := p.containers.Exec(execCtx, ctr, execCommand)
+	exitCode, execErr := p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
 	if execErr != nil {
 		if ctx.Err() != nil {
 			return result, fmt.Errorf("job cancelled: %w", ctx.Err())
@@ -456,7 +455,6 @@ func (p *Pipeline) runMultiRepoMergeAI(
 
 	provider := p.resolveProvider(settings)
 	sp := buildScriptParams(provider, p.cfg.DefaultClaudeModel, p.cfg.DefaultGeminiModel, repoConfigs[0])
-	execCommand := buildExecCommand(sp)
 
 	ctr, err := p.startContainer(ctx, wsPath, job.TicketKey, provider, settings)
 	if err != nil {
@@ -496,7 +494,7 @@ func (p *Pipeline) runMultiRepoMergeAI(
 		defer cancel()
 	}
 
-	_, exitCode, execErr := p.containers.Exec(execCtx, ctr, execCommand)
+	exitCode, execErr := p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
 	if execErr != nil && ctx.Err() != nil {
 		return ctr, result, fmt.Errorf("job cancelled: %w", ctx.Err())
 	}
@@ -551,7 +549,7 @@ func (p *Pipeline) writeMultiRepoMergeFiles(
 	if err != nil {
 		return fmt.Errorf("download attachments: %w", err)
 	}
-	comments := p.fetchTicketComments(logger, workItem.Key)
+	comments := p.fetchTicketComments(logger, workItem)
 	if err := p.taskWriter.WriteIssue(workItem, wsPath, downloaded, comments); err != nil {
 		return fmt.Errorf("write issue file: %w", err)
 	}
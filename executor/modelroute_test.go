@@ -0,0 +1,57 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/modelroute"
+	"jira-ai-issue-solver/models"
+)
+
+func TestExecuteNewTicket_RoutesModelByComplexity(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision modelroute.Decision
+		routed   bool
+		want     string
+	}{
+		{"routed", modelroute.Decision{Tier: modelroute.Complex, Model: "big-model", Score: 2}, true, "big-model"},
+		{"provider not routed", modelroute.Decision{}, false, "default-model"},
+		{"no model for tier", modelroute.Decision{Tier: modelroute.Simple}, true, "default-model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			var gotModel string
+			runner := &executortest.StubAgentRunner{
+				RunFunc: func(_ context.Context, req agent.Request) (agent.Result, error) {
+					gotModel = req.Model
+					return agent.Result{}, nil
+				},
+			}
+			var gotProvider, gotKey string
+			router := &executortest.StubModelRouter{
+				RouteFunc: func(provider string, item models.WorkItem) (modelroute.Decision, bool) {
+					gotProvider, gotKey = provider, item.Key
+					return tt.decision, tt.routed
+				},
+			}
+
+			cfg := agentConfig(runner)
+			cfg.DefaultClaudeModel = "default-model"
+			cfg.Models = router
+			if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotProvider != "claude" || gotKey != "PROJ-1" {
+				t.Errorf("routed %q for %q, want claude for PROJ-1", gotProvider, gotKey)
+			}
+			if gotModel != tt.want {
+				t.Errorf("session model = %q, want %q", gotModel, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+)
+
+func parallelReposConfig() executor.Config {
+	return executor.Config{
+		BotUsername:      "ai-bot",
+		DefaultProvider:  "claude",
+		AIAPIKeys:        map[string]string{"claude": "test-key"},
+		MaxRetries:       3,
+		MaxParallelRepos: 3,
+	}
+}
+
+func TestMultiRepoNewTicket_OpensPRsInParallel(t *testing.T) {
+	d := newMultiRepoTestDeps(t)
+
+	// Each CreatePR waits until all three are in flight, which only
+	// happens when the repos are processed concurrently.
+	var arrived sync.WaitGroup
+	arrived.Add(3)
+	allArrived := make(chan struct{})
+	go func() {
+		arrived.Wait()
+		close(allArrived)
+	}()
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		arrived.Done()
+		select {
+		case <-allArrived:
+		case <-time.After(5 * time.Second):
+			return nil, errors.New("repos were not processed concurrently")
+		}
+		return &models.PR{
+			Number: 1,
+			URL:    fmt.Sprintf("https://github.com/%s/%s/pull/1", params.Owner, params.Repo),
+		}, nil
+	}
+
+	var mu sync.Mutex
+	var comments []string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		comments = append(comments, body)
+		return nil
+	}
+
+	result, err := d.pipelineWithConfig(t, parallelReposConfig()).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// PR URLs keep the workspace's repo order.
+	if result.PRURL != "https://github.com/org/svc-a/pull/1" {
+		t.Errorf("result.PRURL = %q, want svc-a PR", result.PRURL)
+	}
+	want := []string{
+		"[AI-BOT-PR] https://github.com/org/svc-a/pull/1",
+		"[AI-BOT-PR] https://github.com/org/svc-b/pull/1",
+		"[AI-BOT-PR] https://github.com/org/svc-c/pull/1",
+	}
+	if !equalSlice(comments, want) {
+		t.Errorf("comments = %v, want %v", comments, want)
+	}
+}
+
+func TestMultiRepoNewTicket_PartialPRFailure(t *testing.T) {
+	d := newMultiRepoTestDeps(t)
+
+	var mu sync.Mutex
+	var prRepos []string
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		if params.Repo == "svc-b" {
+			return nil, errors.New("head branch rejected")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		prRepos = append(prRepos, params.Repo)
+		return &models.PR{
+			Number: 1,
+			URL:    fmt.Sprintf("https://github.com/%s/%s/pull/1", params.Owner, params.Repo),
+		}, nil
+	}
+
+	var comments []string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(_, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+
+	result, err := d.pipelineWithConfig(t, parallelReposConfig()).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(prRepos) != 2 {
+		t.Errorf("PR repos = %v, want svc-a and svc-c", prRepos)
+	}
+	if result.PRURL != "https://github.com/org/svc-a/pull/1" {
+		t.Errorf("result.PRURL = %q, want svc-a PR", result.PRURL)
+	}
+
+	var report string
+	for _, c := range comments {
+		if strings.Contains(c, "could not be published") {
+			report = c
+		}
+	}
+	if !strings.Contains(report, "svc-b: create PR for svc-b: head branch rejected") {
+		t.Errorf("failure report = %q, want svc-b and its error", report)
+	}
+	if strings.Contains(report, "svc-a") || strings.Contains(report, "svc-c") {
+		t.Errorf("failure report = %q, want only svc-b", report)
+	}
+	if len(transitions) == 0 || transitions[len(transitions)-1] != "In Review" {
+		t.Errorf("transitions = %v, want to end in In Review", transitions)
+	}
+}
+
+func TestMultiRepoNewTicket_AllPRsFail(t *testing.T) {
+	d := newMultiRepoTestDeps(t)
+
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		return nil, fmt.Errorf("%s unavailable", params.Repo)
+	}
+
+	_, err := d.pipelineWithConfig(t, parallelReposConfig()).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil {
+		t.Fatal("expected error when no repo got a PR")
+	}
+	for _, repo := range []string{"svc-a", "svc-b", "svc-c"} {
+		if !strings.Contains(err.Error(), repo+" unavailable") {
+			t.Errorf("error = %v, want %s's failure", err, repo)
+		}
+	}
+}
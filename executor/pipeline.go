@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -8,11 +9,16 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/events"
 	"jira-ai-issue-solver/jobmanager"
 	"jira-ai-issue-solver/models"
 	"jira-ai-issue-solver/repoconfig"
@@ -36,6 +42,7 @@ type Pipeline struct {
 	taskWriter taskfile.Writer
 	projects   ProjectResolver
 	cfg        Config
+	tracer     trace.Tracer
 	logger     *zap.Logger
 }
 
@@ -82,6 +89,11 @@ func NewPipeline(
 		return nil, errors.New("logger must not be nil")
 	}
 
+	tp := cfg.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
 	return &Pipeline{
 		tracker:    issueTracker,
 		git:        git,
@@ -90,12 +102,40 @@ func NewPipeline(
 		taskWriter: taskWriter,
 		projects:   projects,
 		cfg:        cfg,
+		tracer:     tp.Tracer(tracerName),
 		logger:     logger,
 	}, nil
 }
 
 // Execute dispatches a job by type. Matches [jobmanager.ExecuteFunc].
-func (p *Pipeline) Execute(ctx context.Context, job *jobmanager.Job) (jobmanager.JobResult, error) {
+// Each job runs under a root span named "job.<type>".
+func (p *Pipeline) Execute(ctx context.Context, job *jobmanager.Job) (result jobmanager.JobResult, err error) {
+	ctx, span := p.startStage(ctx, "job."+string(job.Type), job.TicketKey,
+		attrJobID.String(job.ID),
+		attrJobType.String(string(job.Type)),
+		attrJobAttempt.Int(job.AttemptNum))
+	defer func() {
+		if result.PRURL != "" {
+			span.SetAttributes(attribute.String("pr.url", result.PRURL))
+		}
+		span.SetAttributes(attribute.Float64("ai.cost_usd", result.CostUSD))
+		endStage(span, err)
+	}()
+	defer func() { p.recordProjectCost(job.TicketKey, result.CostUSD) }()
+
+	started := time.Now()
+	ctx, ranAI := trackAISessions(ctx)
+	defer func() { p.recordWorklog(job, started, ranAI.Load()) }()
+
+	// A timed-out job may have been interrupted mid-step; start the
+	// next attempt from a fresh clone.
+	defer func() {
+		err = wrapTimeout(ctx, err)
+		if errors.Is(err, jobmanager.ErrJobTimeout) {
+			p.discardTimedOutWorkspace(job.TicketKey)
+		}
+	}()
+
 	switch job.Type {
 	case jobmanager.JobTypeNewTicket:
 		return p.executeNewTicket(ctx, job)
@@ -103,11 +143,32 @@ func (p *Pipeline) Execute(ctx context.Context, job *jobmanager.Job) (jobmanager
 		return p.executeFeedback(ctx, job)
 	case jobmanager.JobTypeMerge:
 		return p.executeMerge(ctx, job)
+	case jobmanager.JobTypeBackport:
+		return p.executeBackport(ctx, job)
+	case jobmanager.JobTypeTriage:
+		return p.executeTriage(ctx, job)
+	case jobmanager.JobTypeReleaseNote:
+		return p.executeReleaseNote(ctx, job)
 	default:
 		return jobmanager.JobResult{}, fmt.Errorf("unknown job type: %s", job.Type)
 	}
 }
 
+// maxWorkItemSnapshotAge bounds the age of the scanner's snapshot of a
+// ticket that a job may use instead of fetching the ticket. Jobs that
+// waited longer in the queue fetch it again.
+const maxWorkItemSnapshotAge = 5 * time.Minute
+
+// jobWorkItem returns the job's work item: the scanner's snapshot when
+// it is recent, or the current one from the tracker otherwise.
+func (p *Pipeline) jobWorkItem(job *jobmanager.Job) (*models.WorkItem, error) {
+	if job.WorkItem != nil && time.Since(job.CreatedAt) < maxWorkItemSnapshotAge {
+		item := *job.WorkItem
+		return &item, nil
+	}
+	return p.tracker.GetWorkItem(job.TicketKey)
+}
+
 func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (result jobmanager.JobResult, retErr error) {
 	logger := p.logger.With(
 		zap.String("ticket", job.TicketKey),
@@ -115,9 +176,12 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 		zap.Int("attempt", job.AttemptNum),
 	)
 	logger.Info("Starting new ticket pipeline")
+	p.publish(job, events.Event{Type: events.TicketStarted})
 
 	// --- Step 1: Fetch work item ---
-	workItem, err := p.tracker.GetWorkItem(job.TicketKey)
+	_, span := p.startStage(ctx, spanFetchWorkItem, job.TicketKey)
+	workItem, err := p.jobWorkItem(job)
+	endStage(span, err)
 	if err != nil {
 		return result, fmt.Errorf("get work item: %w", err)
 	}
@@ -143,6 +207,54 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 		return result, errTicketCostCapExceeded
 	}
 
+	// --- Explore a spike instead of solving the ticket ---
+	if settings.Spike.Requested(workItem.Labels) {
+		return p.executeSpike(ctx, logger, job, workItem, settings)
+	}
+
+	// --- Step 2c: Resume an existing PR instead of duplicating it ---
+	if pr := p.findExistingPR(logger, job.TicketKey, settings); pr != nil {
+		return p.resumeExistingPR(logger, job.TicketKey, settings, pr)
+	}
+
+	// --- Open the PR of an approved change preview ---
+	if settings.DiffPreview.Awaiting(workItem.Labels) {
+		if handled, err := p.publishPreview(ctx, logger, job, workItem, settings, &result); handled || err != nil {
+			return result, err
+		}
+	}
+
+	// --- Step 2d: Collect tickets batched with this one ---
+	batch := p.collectBatch(logger, workItem, settings)
+	if err := p.checkBatchLead(logger, job.TicketKey, batch); err != nil {
+		return result, err
+	}
+
+	// --- Step 2e: Wait for blockers and skip duplicates ---
+	if err := p.checkIssueLinks(logger, withoutBatchLinks(workItem, batch), settings); err != nil {
+		return result, err
+	}
+	if err := p.checkLikelyDuplicates(logger, workItem, batchKeys(batch)); err != nil {
+		return result, err
+	}
+
+	// --- Step 2f: Check per-repository open PR limit ---
+	if err := p.checkOpenPRLimit(logger, job.TicketKey, settings); err != nil {
+		return result, err
+	}
+
+	// --- Step 2g: Check project AI budget ---
+	if err := p.checkProjectBudget(logger, job.TicketKey, settings); err != nil {
+		return result, err
+	}
+
+	// --- Step 2h: Read acceptance criteria ---
+	p.loadAcceptanceCriteria(logger, workItem, settings)
+	workItem.TypeGuidance = settings.PromptStrategy.Guidance
+	for i := range batch {
+		p.loadAcceptanceCriteria(logger, &batch[i].item, batch[i].settings)
+	}
+
 	// --- Clean retry: delete stale branches and workspace ---
 	if job.CleanRetry {
 		p.cleanRetryState(logger, job.TicketKey, settings)
@@ -154,6 +266,17 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 	}
 	statusTransitioned := true
 
+	// --- Step 3a: Assign the bot while working on the ticket ---
+	restoreAssignee := p.assignBot(logger, job.TicketKey, workItem, settings)
+
+	// --- Step 3b: Claim batched tickets ---
+	if len(batch) > 0 {
+		batch = p.claimBatch(logger, batch)
+		workItem = combineBatch(workItem, batch)
+		logger.Info("Solving batched tickets together",
+			zap.Strings("batched", batchKeys(batch)))
+	}
+
 	// Track container for cleanup.
 	var ctr *container.Container
 
@@ -165,27 +288,41 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 			}
 		}
 		// On failure: revert status and optionally post error comment.
+		retErr = wrapTimeout(ctx, retErr)
 		if retErr != nil && statusTransitioned {
-			p.handleFailure(logger, job.TicketKey, settings, job.AttemptNum, retErr)
+			p.handleFailure(logger, job, settings, retErr)
+			p.releaseBatch(logger, batch)
+		}
+		if retErr == nil && result.PRURL != "" {
+			p.finishBatch(logger, job.TicketKey, result.PRURL, batch)
 		}
+		restoreAssignee()
 	}()
 
 	if settings.IsMultiRepo() {
-		return p.executeMultiRepoNewTicket(ctx, job, logger, workItem, settings)
+		return p.executeMultiRepoNewTicket(ctx, job, logger, workItem, settings, batchKeys(batch))
 	}
 
+	// Previews apply to single-repository, unbatched tickets.
+	preview := settings.DiffPreview.Mode == models.DiffPreviewAlways && len(batch) == 0
+
 	// --- Step 4: Prepare workspace ---
-	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, settings.Repos[0].CloneURL)
+	_, span = p.startStage(ctx, spanPrepareWS, job.TicketKey)
+	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, settings.Repos[0].CloneURL, settings.Repos[0].SparsePaths)
 	if err != nil {
+		endStage(span, err)
 		return result, fmt.Errorf("prepare workspace: %w", err)
 	}
 	logger.Info("Workspace ready",
 		zap.String("path", wsPath),
 		zap.Bool("reused", reused))
+	p.publish(job, events.Event{Type: events.WorkspaceReady, Reused: reused})
 
 	// --- Step 5: Create or switch to branch ---
 	branchName := fmt.Sprintf("%s/%s", p.cfg.BotUsername, job.TicketKey)
-	if err := p.prepareBranch(logger, wsPath, branchName, reused, settings); err != nil {
+	err = p.prepareBranch(logger, wsPath, branchName, reused, settings)
+	endStage(span, err)
+	if err != nil {
 		return result, err
 	}
 
@@ -206,14 +343,18 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 	if err := p.writeNewTicketFiles(logger, *workItem, wsPath, settings); err != nil {
 		return result, err
 	}
+	p.appendRelevantCode(logger, *workItem, wsPath, settings)
+	p.appendLinkContext(ctx, logger, *workItem, wsPath)
+	p.appendSubdirectoryScope(logger, wsPath, settings)
+	p.prepareCompanionRepos(logger, wsPath, settings)
 
 	// --- Step 9: Determine AI provider ---
 	provider := p.resolveProvider(settings)
 	logger.Info("AI provider selected", zap.String("provider", provider))
 
-	// --- Step 10: Build AI command ---
+	// --- Step 10: Build AI session parameters ---
 	sp := buildScriptParams(provider, p.cfg.DefaultClaudeModel, p.cfg.DefaultGeminiModel, repoCfg)
-	execCommand := buildExecCommand(sp)
+	p.routeModel(logger, *workItem, &sp, repoCfg)
 
 	// --- Step 11: Resolve and start container ---
 	ctr, err = p.startContainer(ctx, wsPath, job.TicketKey, provider, settings)
@@ -240,35 +381,74 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 		}
 	}()
 
-	// --- Step 12: Execute AI agent ---
-	execCtx := ctx
-	if p.cfg.SessionTimeout > 0 {
-		var cancel context.CancelFunc
-		execCtx, cancel = context.WithTimeout(ctx, p.cfg.SessionTimeout)
-		defer cancel()
+	// --- Step 12: Execute AI agent, retrying if it makes no changes ---
+	hasChanges := func() (bool, error) { return p.git.HasChanges(wsPath, settings.Repos[0].BaseBranch) }
+	ai, err := p.runAIWithRetries(ctx, logger, job, ctr, wsPath, sp, settings.MaxTicketCostUSD, hasChanges)
+	if err != nil {
+		return result, err
+	}
+	exitCode, session := ai.ExitCode, ai.Session
+	result.CostUSD = ai.CostUSD
+
+	// --- Step 12b: Self-review the changes ---
+	var review *selfReview
+	if settings.SelfReview && ai.ExecErr == nil && ai.HasChanges {
+		review, err = p.runSelfReview(ctx, logger, job, ctr, wsPath, sp, settings.MaxTicketCostUSD, &ai,
+			func() error {
+				return p.taskWriter.WriteSelfReviewTask(*workItem, wsPath, settings.Repos[0].Instructions)
+			}, hasChanges)
+		result.CostUSD = ai.CostUSD
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// --- Step 12c: Require a regression test for bug fixes ---
+	var missingTest bool
+	if needsRegressionTest(workItem, settings) && ai.ExecErr == nil && ai.HasChanges {
+		missingTest, err = p.requireRegressionTest(ctx, logger, job, ctr, wsPath, sp, settings, &ai,
+			func() error {
+				return p.taskWriter.WriteRegressionTestTask(*workItem, wsPath, settings.Repos[0].Instructions)
+			}, hasChanges)
+		result.CostUSD = ai.CostUSD
+		if err != nil {
+			return result, err
+		}
 	}
 
-	_, exitCode, execErr := p.containers.Exec(
-		execCtx, ctr, execCommand)
-	if execErr != nil {
-		if ctx.Err() != nil {
-			// Parent context cancelled (shutdown).
-			return result, fmt.Errorf("job cancelled: %w", ctx.Err())
+	// --- Step 12d: Security-scan the changes ---
+	if len(settings.SecurityScans) > 0 && ai.ExecErr == nil && ai.HasChanges {
+		if err := p.runSecurityScans(ctx, logger, ctr, wsPath, settings); err != nil {
+			return result, err
+		}
+	}
+
+	// --- Step 12e: Check added dependencies against the policy ---
+	if settings.DependencyPolicy.IsEnabled() && ai.ExecErr == nil && ai.HasChanges {
+		if err := p.checkDependencyPolicy(ctx, logger, wsPath, settings); err != nil {
+			return result, err
 		}
-		logger.Warn("AI agent exec failed", zap.Error(execErr))
 	}
 
-	// Read session metadata (may be absent on abnormal exit).
-	session := readSessionOutput(wsPath)
-	p.applyCostEstimate(&session)
+	// --- Step 12f: Keep the changes inside component subdirectories ---
+	if settings.HasSubdirectories() && ai.ExecErr == nil && ai.HasChanges {
+		if err := p.checkSubdirectories(logger, wsPath, settings); err != nil {
+			return result, err
+		}
+	}
 
-	logger.Info("AI session completed",
-		zap.Int("exit_code", exitCode),
-		zap.Float64("cost_usd", session.CostUSD),
-		zap.Any("validation_passed", session.ValidationPassed),
-		zap.String("summary", session.Summary))
-	result.CostUSD = session.CostUSD
-	p.recordTicketCost(logger, wsPath, settings.MaxTicketCostUSD, result.CostUSD)
+	// --- Step 12g: Hold back low-confidence changes for review ---
+	var previewDetails string
+	if settings.MinConfidence > 0 && ai.ExecErr == nil && ai.HasChanges {
+		err := p.checkConfidence(logger, wsPath, settings)
+		var rejected *rejectedChangeError
+		switch {
+		case errors.As(err, &rejected) && settings.DiffPreview.Mode == models.DiffPreviewLowConfidence && len(batch) == 0:
+			preview, previewDetails = true, rejected.details
+		case err != nil:
+			return result, err
+		}
+	}
 
 	// --- Step 12a: Restore remote auth ---
 	// Must happen before SyncWithRemote which needs fetch access.
@@ -280,45 +460,98 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 	authStripped = false
 
 	// Exec runtime error (not just non-zero exit) is fatal.
-	if execErr != nil {
-		if execCtx.Err() != nil {
-			return result, fmt.Errorf("session timeout exceeded: %w", execErr)
+	if ai.ExecErr != nil {
+		if ai.TimedOut {
+			return result, fmt.Errorf("session timeout exceeded: %w", ai.ExecErr)
 		}
-		return result, fmt.Errorf("AI session failed: %w", execErr)
+		return result, fmt.Errorf("AI session failed: %w", ai.ExecErr)
 	}
 
 	// --- Step 13: Check for changes ---
-	hasChanges, err := p.git.HasChanges(wsPath, settings.Repos[0].BaseBranch)
-	if err != nil {
-		return result, fmt.Errorf("check changes: %w", err)
+	if !ai.HasChanges {
+		// Batched tickets are not parked; they escalate below.
+		if len(ai.Questions) > 0 && len(batch) == 0 {
+			return result, p.askForInfo(logger, job.TicketKey, settings, ai.Questions)
+		}
+		return result, &noChangesError{
+			msg:      fmt.Sprintf("AI produced no changes (exit code: %d)", exitCode),
+			analysis: session.Summary,
+		}
 	}
-	if !hasChanges {
-		return result, fmt.Errorf("AI produced no changes (exit code: %d)", exitCode)
+
+	// --- Step 13a: Post the change for approval instead of a PR ---
+	if preview {
+		return result, p.postPreview(logger, job.TicketKey, wsPath, previewDetails, settings)
 	}
 
+	// --- Steps 14–17: Commit, open the PR, and update the ticket ---
+	err = p.openTicketPR(ctx, logger, job, &result, ticketChange{
+		workItem:    workItem,
+		settings:    settings,
+		repoCfg:     repoCfg,
+		wsPath:      wsPath,
+		branchName:  branchName,
+		excludes:    collectExcludes(mergedImports),
+		batchKeys:   batchKeys(batch),
+		review:      review,
+		missingTest: missingTest,
+		session:     session,
+		exitCode:    exitCode,
+	})
+	return result, err
+}
+
+// ticketChange is a single-repository new-ticket change ready to be
+// committed, with what its PR reports about the AI session.
+type ticketChange struct {
+	workItem    *models.WorkItem
+	settings    *models.ProjectSettings
+	repoCfg     *repoconfig.Config
+	wsPath      string
+	branchName  string
+	excludes    []string
+	batchKeys   []string
+	review      *selfReview
+	missingTest bool
+	session     SessionOutput
+	exitCode    int
+}
+
+// openTicketPR commits c, opens its PR, and moves the ticket to
+// review, filling in result. Used by the single-repository new-ticket
+// pipeline and to open the PR of an approved change preview.
+func (p *Pipeline) openTicketPR(ctx context.Context, logger *zap.Logger, job *jobmanager.Job, result *jobmanager.JobResult, c ticketChange) error {
+	workItem, settings, repoCfg := c.workItem, c.settings, c.repoCfg
+	wsPath, branchName, session, exitCode := c.wsPath, c.branchName, c.session, c.exitCode
+
 	// --- Step 14: Commit via GitHub API ---
-	importExcludes := collectExcludes(mergedImports)
-	commitMsg := fmt.Sprintf("%s: %s", job.TicketKey, workItem.Summary)
-	_, err = p.git.CommitChanges(
+	commitMsg := settings.CommitMessage.Render(job.TicketKey, *workItem)
+	_, span := p.startStage(ctx, spanCommit, job.TicketKey)
+	sha, err := p.git.CommitChanges(
 		settings.Repos[0].Owner, settings.CommitOwner(), settings.Repos[0].Repo, branchName,
-		commitMsg, wsPath, settings.Repos[0].BaseBranch, workItem.Assignee, importExcludes,
+		commitMsg, wsPath, settings.Repos[0].BaseBranch, workItem.Assignee, c.excludes,
 	)
+	endStage(span, err)
 	if errors.Is(err, services.ErrNoChanges) {
-		return result, fmt.Errorf("AI produced no committable changes (exit code: %d)", exitCode)
+		return fmt.Errorf("AI produced no committable changes (exit code: %d)", exitCode)
 	}
 	if err != nil {
-		return result, fmt.Errorf("commit changes: %w", err)
+		return fmt.Errorf("commit changes: %w", err)
 	}
 
 	// --- Step 15: Post-commit sync ---
-	if err := p.git.SyncWithRemote(wsPath, branchName, importExcludes); err != nil {
-		return result, fmt.Errorf("sync with remote: %w", err)
+	if err := p.git.SyncWithRemote(wsPath, branchName, c.excludes); err != nil {
+		return fmt.Errorf("sync with remote: %w", err)
 	}
 
 	// --- Step 16: Create PR ---
-	aiPR := readPRDescription(wsPath)
+	aiPR := withPRSummary(readPRDescription(wsPath), readPRSummary(wsPath))
 	prTitle, prBody := buildPRContent(workItem, job.TicketKey, repoCfg.PR.TitlePrefix, aiPR)
+	prBody = withBatchKeys(prBody, c.batchKeys)
+	prBody = withSelfReview(prBody, c.review, workItem.HasSecurityLevel())
+	prBody = withRegressionTestWarning(prBody, c.missingTest)
 
+	_, span = p.startStage(ctx, spanCreatePR, job.TicketKey)
 	pr, err := p.git.CreatePR(models.PRParams{
 		Owner:     settings.Repos[0].Owner,
 		Repo:      settings.Repos[0].Repo,
@@ -330,8 +563,9 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 		Labels:    repoCfg.PR.Labels,
 		Assignees: assigneesFromSettings(settings),
 	})
+	endStage(span, err)
 	if err != nil {
-		return result, fmt.Errorf("create PR: %w", err)
+		return fmt.Errorf("create PR: %w", err)
 	}
 
 	result.PRURL = pr.URL
@@ -343,6 +577,12 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 		zap.String("url", pr.URL),
 		zap.Int("number", pr.Number),
 		zap.Bool("draft", repoCfg.PR.Draft))
+	p.publish(job, events.Event{
+		Type:     events.PRCreated,
+		Repo:     settings.Repos[0].Owner + "/" + settings.Repos[0].Repo,
+		PRNumber: pr.Number,
+		PRURL:    pr.URL,
+	})
 
 	// --- Step 16a: Apply validation labels ---
 	vlTarget := validationLabel(session, exitCode, settings.PRValidationLabels)
@@ -350,25 +590,33 @@ func (p *Pipeline) executeNewTicket(ctx context.Context, job *jobmanager.Job) (r
 		p.setPRValidationLabel(logger, settings.Repos[0].Owner, settings.Repos[0].Repo,
 			pr.Number, settings.PRValidationLabels, vlTarget)
 	}
+	p.publishCheckRun(logger, settings, settings.Repos[0].Owner, settings.Repos[0].Repo, sha,
+		newTicketCheckRun(wsPath, workItem, c.review, session, exitCode, result.CostUSD))
+	p.enableAutoMerge(logger, settings, settings.Repos[0].Owner, settings.Repos[0].Repo,
+		pr.Number, repoCfg.PR.Draft, wsPath, session, exitCode)
 
 	// --- Step 17: Update ticket ---
 	p.setPRURL(logger, job.TicketKey, settings, pr.URL)
+	p.recordAssessment(logger, job.TicketKey, settings, session, exitCode,
+		[]branchDiff{{dir: wsPath, baseBranch: settings.Repos[0].BaseBranch}})
 	p.cleanupStatusComment(logger, job.TicketKey)
 	p.clearFailureLabels(logger, job.TicketKey, settings.FailureLabels)
 	p.postOrUpdateCostComment(logger,
 		settings.Repos[0].Owner, settings.Repos[0].Repo,
 		pr.Number, result.CostUSD, "New ticket", 0)
 
+	label := p.reviewLabel(logger, job.TicketKey, settings, session, exitCode, []string{pr.URL})
 	allLabels := models.AllPipelineLabels(settings.FailureLabels, settings.LifecycleLabels)
-	p.setPipelineLabel(logger, job.TicketKey, allLabels, settings.LifecycleLabels.Review)
+	p.setPipelineLabel(logger, job.TicketKey, allLabels, label)
 	if err := p.tracker.TransitionStatus(job.TicketKey, settings.InReviewStatus); err != nil {
 		logger.Warn("Failed to transition to in-review", zap.Error(err))
 	}
 
-	return result, nil
+	return nil
 }
 
-// startContainer resolves configuration and starts a container.
+// startContainer resolves configuration, starts a container, and runs
+// the repositories' setup commands in it.
 func (p *Pipeline) startContainer(
 	ctx context.Context,
 	wsPath, ticketKey, provider string,
@@ -389,8 +637,20 @@ func (p *Pipeline) startContainer(
 		})
 	}
 
+	logger := p.logger.With(zap.String("ticket", ticketKey))
+	setups := p.planSetups(logger, wsPath, containerCfg.Image, settings)
+	for _, s := range setups {
+		containerCfg.ExtraMounts = append(containerCfg.ExtraMounts, s.mounts...)
+	}
+
 	env := p.buildContainerEnv(provider)
-	return p.containers.Start(ctx, containerCfg, wsPath, ticketKey, env)
+	ctr, err := p.containers.Start(ctx, containerCfg, wsPath, ticketKey, env)
+	if err != nil {
+		releaseSetupLocks(setups)
+		return nil, err
+	}
+	p.runSetups(ctx, logger, ctr, setups)
+	return ctr, nil
 }
 
 // toSettingsOverride converts profile container settings to the
@@ -643,17 +903,26 @@ func (p *Pipeline) buildContainerEnv(provider string) map[string]string {
 			env["CLOUD_ML_REGION"] = p.cfg.ClaudeVertex.Region
 			env["GOOGLE_APPLICATION_CREDENTIALS"] = containerCredsMountTarget
 		} else if key, ok := p.cfg.AIAPIKeys["claude"]; ok {
-			env["ANTHROPIC_API_KEY"] = key
+			env["ANTHROPIC_API_KEY"] = p.resolveSecret(key)
 		}
 	case "gemini":
 		if key, ok := p.cfg.AIAPIKeys["gemini"]; ok {
-			env["GEMINI_API_KEY"] = key
+			env["GEMINI_API_KEY"] = p.resolveSecret(key)
 		}
 	}
 
 	return env
 }
 
+// resolveSecret returns the current value of a configured credential,
+// resolving secret references when a resolver is configured.
+func (p *Pipeline) resolveSecret(value string) string {
+	if p.cfg.Secrets == nil {
+		return value
+	}
+	return p.cfg.Secrets.Resolve(value)
+}
+
 // setPRURL stores the PR URL on the ticket via either a custom field
 // or a structured comment.
 func (p *Pipeline) setPRURL(logger *zap.Logger, ticketKey string, settings *models.ProjectSettings, prURL string) {
@@ -709,22 +978,104 @@ func (p *Pipeline) cleanupStatusComment(logger *zap.Logger, ticketKey string) {
 // If a previous [AI-BOT-STATUS] comment exists, it is updated in place;
 // otherwise a new comment is created. This keeps at most one status
 // comment per ticket.
-func (p *Pipeline) handleFailure(logger *zap.Logger, ticketKey string, settings *models.ProjectSettings, attempt int, jobErr error) {
-	if err := p.tracker.TransitionStatus(ticketKey, settings.TodoStatus); err != nil {
+//
+// When the AI produced no changes, its change was rejected before
+// committing, or retries are exhausted and the project configures
+// escalation, the ticket is escalated instead: it moves to the
+// needs-human status with the needs-human label, and the comment
+// summarizes what the bot tried.
+//
+// When retries are exhausted and a dead-letter label is configured,
+// the ticket also gets that label, which keeps it out of new-ticket
+// scans until a human removes it, and [events.TicketDeadLettered] is
+// published.
+func (p *Pipeline) handleFailure(logger *zap.Logger, job *jobmanager.Job, settings *models.ProjectSettings, jobErr error) {
+	ticketKey, attempt := job.TicketKey, job.AttemptNum
+	var noChanges *noChangesError
+	isNoChanges := errors.As(jobErr, &noChanges)
+	var rejected *rejectedChangeError
+	isRejected := errors.As(jobErr, &rejected)
+	exhausted := p.cfg.MaxRetries >= 0 && attempt > p.cfg.MaxRetries
+	escalate := isNoChanges || isRejected || (exhausted && escalationConfigured(settings))
+
+	target, label := settings.TodoStatus, settings.FailureLabels.Blocked
+	if escalate {
+		target = cmp.Or(settings.NeedsHumanStatus, target)
+		label = cmp.Or(settings.FailureLabels.NeedsHuman, label)
+		if escalationConfigured(settings) {
+			logger.Info("Escalating ticket to a human",
+				zap.String("target_status", target),
+				zap.String("label", label))
+		}
+	}
+	if err := p.tracker.TransitionStatus(ticketKey, target); err != nil {
 		logger.Error("Failed to revert ticket status",
-			zap.String("target_status", settings.TodoStatus),
+			zap.String("target_status", target),
 			zap.Error(err))
 	}
 
 	allLabels := models.AllPipelineLabels(settings.FailureLabels, settings.LifecycleLabels)
-	p.setPipelineLabel(logger, ticketKey, allLabels, settings.FailureLabels.Blocked)
+	p.setPipelineLabel(logger, ticketKey, allLabels, label)
+
+	hint := retryHint(p.cfg.RetryLabel, "")
+	if exhausted && p.cfg.DeadLetterLabel != "" {
+		if err := p.tracker.AddLabel(ticketKey, p.cfg.DeadLetterLabel); err != nil {
+			logger.Error("Failed to add dead-letter label",
+				zap.String("label", p.cfg.DeadLetterLabel),
+				zap.Error(err))
+		} else {
+			logger.Warn("Ticket dead-lettered after exhausting its retries",
+				zap.String("label", p.cfg.DeadLetterLabel))
+			p.publish(job, events.Event{Type: events.TicketDeadLettered})
+			hint = retryHint(p.cfg.RetryLabel, p.cfg.DeadLetterLabel)
+		}
+	}
 
 	if settings.DisableErrorComments {
 		return
 	}
 
-	body := formatStatusComment(attempt, p.cfg.MaxRetries, p.cfg.RetryLabel, jobErr, time.Now())
+	var body string
+	switch {
+	case isNoChanges:
+		body = formatNoChangesComment(attempt, p.cfg.MaxRetries, hint, noChanges.analysis, time.Now())
+	case isRejected:
+		body = formatRejectedChangeComment(attempt, p.cfg.MaxRetries, hint, rejected.reason, rejected.details, time.Now())
+	case escalate:
+		body = formatEscalationComment(attempt, hint, jobErr, p.lastAnalysis(ticketKey), time.Now())
+	default:
+		body = formatStatusComment(attempt, p.cfg.MaxRetries, hint, jobErr, time.Now())
+	}
+	p.upsertStatusComment(logger, ticketKey, body)
+}
+
+// wrapTimeout marks err as a job timeout when the job's context hit
+// its deadline, so failure comments report the timeout rather than
+// whichever step happened to notice the cancellation.
+func wrapTimeout(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, jobmanager.ErrJobTimeout) || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w (guardrails.max_job_runtime_minutes): %w", jobmanager.ErrJobTimeout, err)
+}
 
+// discardTimedOutWorkspace removes the workspace of a job that timed
+// out. Failures are logged; the next attempt reuses the workspace.
+func (p *Pipeline) discardTimedOutWorkspace(ticketKey string) {
+	if err := p.workspaces.Cleanup(ticketKey); err != nil {
+		p.logger.Warn("Failed to delete workspace of timed-out job",
+			zap.String("ticket", ticketKey),
+			zap.Error(err))
+		return
+	}
+	p.logger.Info("Deleted workspace of timed-out job",
+		zap.String("ticket", ticketKey))
+}
+
+// upsertStatusComment writes body as the ticket's [AI-BOT-STATUS]
+// comment, updating the existing one in place when present. An
+// existing comment whose body already matches is left untouched.
+func (p *Pipeline) upsertStatusComment(logger *zap.Logger, ticketKey, body string) {
 	comments, err := p.tracker.GetComments(ticketKey)
 	if err != nil {
 		logger.Warn("Failed to fetch comments for status upsert, falling back to new comment", zap.Error(err))
@@ -735,6 +1086,9 @@ func (p *Pipeline) handleFailure(logger *zap.Logger, ticketKey string, settings
 	}
 
 	if existing := findStatusComment(comments); existing != nil {
+		if existing.Body == body {
+			return
+		}
 		if err := p.tracker.UpdateComment(ticketKey, existing.ID, body); err != nil {
 			logger.Error("Failed to update status comment", zap.Error(err))
 		}
@@ -811,6 +1165,7 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 	logger *zap.Logger,
 	workItem *models.WorkItem,
 	settings *models.ProjectSettings,
+	alsoResolves []string,
 ) (result jobmanager.JobResult, retErr error) {
 	var ctr *container.Container
 
@@ -823,10 +1178,13 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 	}()
 
 	// --- Step 4: Prepare multi-repo workspace ---
+	_, span := p.startStage(ctx, spanPrepareWS, job.TicketKey)
 	wsPath, reused, err := p.prepareMultiRepoWorkspace(logger, job.TicketKey, settings)
+	endStage(span, err)
 	if err != nil {
 		return result, err
 	}
+	p.publish(job, events.Event{Type: events.WorkspaceReady, Reused: reused})
 
 	// --- Step 5: Create or switch to branch per repo ---
 	branchName := fmt.Sprintf("%s/%s", p.cfg.BotUsername, job.TicketKey)
@@ -861,7 +1219,7 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 	if err != nil {
 		return result, fmt.Errorf("download attachments: %w", err)
 	}
-	comments := p.fetchTicketComments(logger, workItem.Key)
+	comments := p.fetchTicketComments(logger, *workItem)
 	if err := p.taskWriter.WriteIssue(*workItem, wsPath, downloaded, comments); err != nil {
 		return result, fmt.Errorf("write issue file: %w", err)
 	}
@@ -872,20 +1230,24 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 			Name:                      repo.Name,
 			Dir:                       filepath.Join(wsPath, repo.Name),
 			OverrideInstructions:      repo.Instructions,
-			OverrideNewTicketWorkflow: repo.NewTicketWorkflow,
+			OverrideNewTicketWorkflow: cmp.Or(settings.PromptStrategy.Workflow, repo.NewTicketWorkflow),
 		}
 	}
 	if err := p.taskWriter.WriteMultiRepoNewTicketTask(*workItem, wsPath, repoContexts); err != nil {
 		return result, fmt.Errorf("write task file: %w", err)
 	}
+	p.appendRelevantCode(logger, *workItem, wsPath, settings)
+	p.appendLinkContext(ctx, logger, *workItem, wsPath)
+	p.appendSubdirectoryScope(logger, wsPath, settings)
+	p.prepareCompanionRepos(logger, wsPath, settings)
 
 	// --- Step 9: Determine AI provider ---
 	provider := p.resolveProvider(settings)
 	logger.Info("AI provider selected", zap.String("provider", provider))
 
-	// --- Step 10: Build AI command (use first repo's config for AI settings) ---
+	// --- Step 10: Build AI session parameters (use first repo's config for AI settings) ---
 	sp := buildScriptParams(provider, p.cfg.DefaultClaudeModel, p.cfg.DefaultGeminiModel, repoConfigs[0])
-	execCommand := buildExecCommand(sp)
+	p.routeModel(logger, *workItem, &sp, repoConfigs[0])
 
 	// --- Step 11: Resolve and start container ---
 	ctr, err = p.startContainer(ctx, wsPath, job.TicketKey, provider, settings)
@@ -918,31 +1280,68 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 		}
 	}()
 
-	// --- Step 12: Execute AI agent ---
-	execCtx := ctx
-	if p.cfg.SessionTimeout > 0 {
-		var cancel context.CancelFunc
-		execCtx, cancel = context.WithTimeout(ctx, p.cfg.SessionTimeout)
-		defer cancel()
+	// --- Step 12: Execute AI agent, retrying if it makes no changes ---
+	hasChanges := func() (bool, error) { return p.anyRepoHasChanges(wsPath, settings.Repos) }
+	ai, err := p.runAIWithRetries(ctx, logger, job, ctr, wsPath, sp, settings.MaxTicketCostUSD, hasChanges)
+	if err != nil {
+		return result, err
+	}
+	exitCode, session := ai.ExitCode, ai.Session
+	result.CostUSD = ai.CostUSD
+
+	// --- Step 12b: Self-review the changes ---
+	var review *selfReview
+	if settings.SelfReview && ai.ExecErr == nil && ai.HasChanges {
+		review, err = p.runSelfReview(ctx, logger, job, ctr, wsPath, sp, settings.MaxTicketCostUSD, &ai,
+			func() error {
+				return p.taskWriter.WriteMultiRepoSelfReviewTask(*workItem, wsPath, repoContexts)
+			}, hasChanges)
+		result.CostUSD = ai.CostUSD
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// --- Step 12c: Require a regression test for bug fixes ---
+	var missingTest bool
+	if needsRegressionTest(workItem, settings) && ai.ExecErr == nil && ai.HasChanges {
+		missingTest, err = p.requireRegressionTest(ctx, logger, job, ctr, wsPath, sp, settings, &ai,
+			func() error {
+				return p.taskWriter.WriteMultiRepoRegressionTestTask(*workItem, wsPath, repoContexts)
+			}, hasChanges)
+		result.CostUSD = ai.CostUSD
+		if err != nil {
+			return result, err
+		}
+	}
+
+	// --- Step 12d: Security-scan the changes ---
+	if len(settings.SecurityScans) > 0 && ai.ExecErr == nil && ai.HasChanges {
+		if err := p.runSecurityScans(ctx, logger, ctr, wsPath, settings); err != nil {
+			return result, err
+		}
 	}
 
-	_, exitCode, execErr := p.containers.Exec(execCtx, ctr, execCommand)
-	if execErr != nil {
-		if ctx.Err() != nil {
-			return result, fmt.Errorf("job cancelled: %w", ctx.Err())
+	// --- Step 12e: Check added dependencies against the policy ---
+	if settings.DependencyPolicy.IsEnabled() && ai.ExecErr == nil && ai.HasChanges {
+		if err := p.checkDependencyPolicy(ctx, logger, wsPath, settings); err != nil {
+			return result, err
 		}
-		logger.Warn("AI agent exec failed", zap.Error(execErr))
 	}
 
-	session := readSessionOutput(wsPath)
-	p.applyCostEstimate(&session)
-	logger.Info("AI session completed",
-		zap.Int("exit_code", exitCode),
-		zap.Float64("cost_usd", session.CostUSD),
-		zap.Any("validation_passed", session.ValidationPassed),
-		zap.String("summary", session.Summary))
-	result.CostUSD = session.CostUSD
-	p.recordTicketCost(logger, wsPath, settings.MaxTicketCostUSD, result.CostUSD)
+	// --- Step 12f: Keep the changes inside component subdirectories ---
+	if settings.HasSubdirectories() && ai.ExecErr == nil && ai.HasChanges {
+		if err := p.checkSubdirectories(logger, wsPath, settings); err != nil {
+			return result, err
+		}
+	}
+
+	// --- Step 12g: Hold back low-confidence changes for review ---
+	if settings.MinConfidence > 0 && ai.ExecErr == nil && ai.HasChanges {
+		if err := p.checkConfidence(logger, wsPath, settings); err != nil {
+			return result, err
+		}
+	}
 
 	// --- Step 12a: Restore remote auth per repo ---
 	for _, repo := range settings.Repos {
@@ -954,19 +1353,24 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 	}
 	authStripped = false
 
-	if execErr != nil {
-		if execCtx.Err() != nil {
-			return result, fmt.Errorf("session timeout exceeded: %w", execErr)
+	if ai.ExecErr != nil {
+		if ai.TimedOut {
+			return result, fmt.Errorf("session timeout exceeded: %w", ai.ExecErr)
 		}
-		return result, fmt.Errorf("AI session failed: %w", execErr)
+		return result, fmt.Errorf("AI session failed: %w", ai.ExecErr)
+	}
+
+	// --- Step 13: Ask for missing details instead of changing anything ---
+	if !ai.HasChanges && len(ai.Questions) > 0 && len(alsoResolves) == 0 {
+		return result, p.askForInfo(logger, job.TicketKey, settings, ai.Questions)
 	}
 
 	// --- Step 13–16: Per-repo fan-out (changes → commit → PR) ---
 	importExcludes := collectExcludes(mergedImports)
-	aiPR := readPRDescription(wsPath)
+	aiPR := withPRSummary(readPRDescription(wsPath), readPRSummary(wsPath))
 	vlTarget := validationLabel(session, exitCode, settings.PRValidationLabels)
 
-	prs, err := p.fanOutCommitAndPR(logger, fanOutParams{
+	prs, err := p.fanOutCommitAndPR(ctx, logger, fanOutParams{
 		settings:    settings,
 		workItem:    workItem,
 		wsPath:      wsPath,
@@ -976,16 +1380,46 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 		excludes:    importExcludes,
 		aiPR:        aiPR,
 		vlTarget:    vlTarget,
+
+		alsoResolves: alsoResolves,
+		review:       review,
+		missingTest:  missingTest,
+		checkRun:     newTicketCheckRun(wsPath, workItem, review, session, exitCode, ai.CostUSD),
 	})
-	if err != nil {
+	var partial *partialFanOutError
+	if errors.As(err, &partial) {
+		logger.Warn("Some repositories got no PR", zap.Error(err))
+	} else if err != nil {
 		return result, err
 	}
+	for _, pr := range prs {
+		p.publish(job, events.Event{
+			Type:     events.PRCreated,
+			Repo:     pr.owner + "/" + pr.repo,
+			PRNumber: pr.number,
+			PRURL:    pr.url,
+		})
+	}
 	if len(prs) == 0 {
-		return result, fmt.Errorf("AI produced no changes in any repository (exit code: %d)", exitCode)
+		return result, &noChangesError{
+			msg:      fmt.Sprintf("AI produced no changes in any repository (exit code: %d)", exitCode),
+			analysis: session.Summary,
+		}
 	}
 
+	// --- Step 16a: Link sibling PRs and hold back dependent ones ---
+	p.coordinatePRs(logger, settings, prs)
+
 	// --- Step 17: Update ticket with all PR URLs ---
 	p.setMultiRepoPRURLs(logger, job.TicketKey, settings, prs)
+	if partial != nil {
+		p.reportPartialFanOut(logger, job.TicketKey, partial)
+	}
+	diffs := make([]branchDiff, len(prs))
+	for i, pr := range prs {
+		diffs[i] = pr.diff
+	}
+	p.recordAssessment(logger, job.TicketKey, settings, session, exitCode, diffs)
 	p.cleanupStatusComment(logger, job.TicketKey)
 	p.clearFailureLabels(logger, job.TicketKey, settings.FailureLabels)
 
@@ -1000,8 +1434,13 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 	result.Draft = prs[0].draft
 	result.ValidationPassed = validationPassed(session, exitCode)
 
+	prURLs := make([]string, len(prs))
+	for i, pr := range prs {
+		prURLs[i] = pr.url
+	}
+	label := p.reviewLabel(logger, job.TicketKey, settings, session, exitCode, prURLs)
 	allLabels := models.AllPipelineLabels(settings.FailureLabels, settings.LifecycleLabels)
-	p.setPipelineLabel(logger, job.TicketKey, allLabels, settings.LifecycleLabels.Review)
+	p.setPipelineLabel(logger, job.TicketKey, allLabels, label)
 	if err := p.tracker.TransitionStatus(job.TicketKey, settings.InReviewStatus); err != nil {
 		logger.Warn("Failed to transition to in-review", zap.Error(err))
 	}
@@ -1009,15 +1448,20 @@ func (p *Pipeline) executeMultiRepoNewTicket(
 	return result, nil
 }
 
-// fetchTicketComments fetches comments from the tracker and filters
-// out bot-authored and trivially short comments. Errors are logged
-// and result in an empty slice — missing comments should not block
-// ticket processing.
-func (p *Pipeline) fetchTicketComments(logger *zap.Logger, ticketKey string) []models.Comment {
-	all, err := p.tracker.GetComments(ticketKey)
-	if err != nil {
-		logger.Warn("Failed to fetch ticket comments", zap.String("ticket", ticketKey), zap.Error(err))
-		return []models.Comment{}
+// fetchTicketComments returns the work item's comments, fetching them
+// from the tracker unless they came with it, and filters out
+// bot-authored and trivially short comments. Errors are logged and
+// result in an empty slice — missing comments should not block ticket
+// processing.
+func (p *Pipeline) fetchTicketComments(logger *zap.Logger, workItem models.WorkItem) []models.Comment {
+	all := workItem.Comments
+	if all == nil {
+		var err error
+		all, err = p.tracker.GetComments(workItem.Key)
+		if err != nil {
+			logger.Warn("Failed to fetch ticket comments", zap.String("ticket", workItem.Key), zap.Error(err))
+			return []models.Comment{}
+		}
 	}
 	return FilterTicketComments(all, p.cfg.JiraUsername, p.cfg.MinCommentLength)
 }
@@ -1051,12 +1495,13 @@ func (p *Pipeline) writeNewTicketFiles(
 	if err != nil {
 		return fmt.Errorf("download attachments: %w", err)
 	}
-	comments := p.fetchTicketComments(logger, workItem.Key)
+	comments := p.fetchTicketComments(logger, workItem)
 	if err := p.taskWriter.WriteIssue(workItem, wsPath, downloaded, comments); err != nil {
 		return fmt.Errorf("write issue file: %w", err)
 	}
 	if err := p.taskWriter.WriteNewTicketTask(
-		workItem, wsPath, settings.Repos[0].Instructions, settings.Repos[0].NewTicketWorkflow,
+		workItem, wsPath, settings.Repos[0].Instructions,
+		cmp.Or(settings.PromptStrategy.Workflow, settings.Repos[0].NewTicketWorkflow),
 	); err != nil {
 		return fmt.Errorf("write task file: %w", err)
 	}
@@ -1073,86 +1518,217 @@ type fanOutParams struct {
 	excludes    []string
 	aiPR        *PRDescription
 	vlTarget    string
+
+	// alsoResolves lists batched tickets the PRs resolve besides
+	// ticketKey.
+	alsoResolves []string
+
+	// review is the self-review verdict added to PR bodies, or nil
+	// when self-review is disabled.
+	review *selfReview
+
+	// missingTest puts a warning in PR bodies that the bug fix
+	// includes no regression test.
+	missingTest bool
+
+	// checkRun is published on each repo's commit when the project
+	// has check runs enabled.
+	checkRun checkRunReport
 }
 
 type repoPR struct {
+	name   string // workspace repo name
 	owner  string
 	repo   string
 	url    string
 	number int
 	draft  bool
+	body   string
+	diff   branchDiff
 }
 
-// fanOutCommitAndPR iterates each repo, commits changes via the GitHub
-// API, syncs the workspace, and creates a PR. Repos without changes are
-// skipped. Returns the list of created PRs (may be empty).
+// anyRepoHasChanges reports whether any repo in a multi-repo
+// workspace has changes.
+func (p *Pipeline) anyRepoHasChanges(wsPath string, repos []models.RepoSettings) (bool, error) {
+	for _, repo := range repos {
+		has, err := p.git.HasChanges(filepath.Join(wsPath, repo.Name), repo.BaseBranch)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", repo.Name, err)
+		}
+		if has {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fanOutCommitAndPR commits each repo's changes via the GitHub API,
+// syncs the workspace, and creates a PR, working on up to
+// Config.MaxParallelRepos repos at a time. Repos without changes are
+// skipped. Returns the created PRs in workspace order (may be empty).
+// When some repos fail and others got a PR, those PRs are returned
+// with a [partialFanOutError]; when none got one, the repos' errors
+// are returned.
 func (p *Pipeline) fanOutCommitAndPR(
+	ctx context.Context,
 	logger *zap.Logger,
 	params fanOutParams,
 ) ([]repoPR, error) {
-	var prs []repoPR
-
-	for i, repo := range params.settings.Repos {
-		repoDir := filepath.Join(params.wsPath, repo.Name)
+	repos := params.settings.Repos
+	results := make([]*repoPR, len(repos))
+	errs := make([]error, len(repos))
+	next := make(chan int, len(repos))
+	for i := range repos {
+		next <- i
+	}
+	close(next)
+	var wg sync.WaitGroup
+	for range min(max(p.cfg.MaxParallelRepos, 1), len(repos)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = p.commitAndOpenPR(ctx, logger, params, i)
+			}
+		}()
+	}
+	wg.Wait()
 
-		hasChanges, err := p.git.HasChanges(repoDir, repo.BaseBranch)
-		if err != nil {
-			return nil, fmt.Errorf("check changes for %s: %w", repo.Name, err)
+	var prs []repoPR
+	var failed []repoFailure
+	for i, repo := range repos {
+		if errs[i] != nil {
+			failed = append(failed, repoFailure{repo: repo.Name, err: errs[i]})
+		} else if results[i] != nil {
+			prs = append(prs, *results[i])
 		}
-		if !hasChanges {
-			logger.Info("No changes in repo, skipping", zap.String("repo", repo.Name))
-			continue
+	}
+	if len(failed) == 0 {
+		return prs, nil
+	}
+	if len(prs) == 0 {
+		failures := make([]error, len(failed))
+		for i, f := range failed {
+			failures[i] = f.err
 		}
+		return nil, errors.Join(failures...)
+	}
+	return prs, &partialFanOutError{failed: failed}
+}
 
-		commitMsg := fmt.Sprintf("%s: %s", params.ticketKey, params.workItem.Summary)
-		_, err = p.git.CommitChanges(
-			repo.Owner, params.settings.CommitOwnerFor(repo), repo.Repo, params.branchName,
-			commitMsg, repoDir, repo.BaseBranch, params.workItem.Assignee, params.excludes,
-		)
-		if errors.Is(err, services.ErrNoChanges) {
-			logger.Info("No committable changes in repo", zap.String("repo", repo.Name))
-			continue
-		}
-		if err != nil {
-			return nil, fmt.Errorf("commit changes for %s: %w", repo.Name, err)
-		}
+// repoFailure is a repository whose changes could not be published.
+type repoFailure struct {
+	repo string
+	err  error
+}
 
-		if err := p.git.SyncWithRemote(repoDir, params.branchName, params.excludes); err != nil {
-			return nil, fmt.Errorf("sync with remote for %s: %w", repo.Name, err)
-		}
+// partialFanOutError reports the repositories that failed while the
+// others got their PRs.
+type partialFanOutError struct {
+	failed []repoFailure
+}
 
-		prTitle, prBody := buildPRContent(
-			params.workItem, params.ticketKey, params.repoConfigs[i].PR.TitlePrefix, params.aiPR)
+func (e *partialFanOutError) Error() string {
+	msgs := make([]string, len(e.failed))
+	for i, f := range e.failed {
+		msgs[i] = f.err.Error()
+	}
+	return "publish changes failed in some repositories: " + strings.Join(msgs, "; ")
+}
 
-		pr, err := p.git.CreatePR(models.PRParams{
-			Owner:     repo.Owner,
-			Repo:      repo.Repo,
-			Title:     prTitle,
-			Body:      prBody,
-			Head:      params.settings.PRHead(params.branchName),
-			Base:      repo.BaseBranch,
-			Draft:     params.repoConfigs[i].PR.Draft,
-			Labels:    params.repoConfigs[i].PR.Labels,
-			Assignees: assigneesFromSettings(params.settings),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("create PR for %s: %w", repo.Name, err)
-		}
+// reportPartialFanOut tells the ticket which repositories' changes
+// were not published alongside the opened PRs. Errors are logged.
+func (p *Pipeline) reportPartialFanOut(logger *zap.Logger, ticketKey string, partial *partialFanOutError) {
+	var b strings.Builder
+	b.WriteString("Pull requests were opened for the other repositories, but the changes in these could not be published:\n")
+	for _, f := range partial.failed {
+		fmt.Fprintf(&b, "\n* %s: %v", f.repo, f.err)
+	}
+	b.WriteString("\n\nPublish them manually, or remove the PRs and retry the ticket.")
+	if err := p.tracker.AddComment(ticketKey, b.String()); err != nil {
+		logger.Warn("Failed to report repositories without PRs", zap.Error(err))
+	}
+}
 
-		if params.vlTarget != "" {
-			p.setPRValidationLabel(logger, repo.Owner, repo.Repo,
-				pr.Number, params.settings.PRValidationLabels, params.vlTarget)
-		}
+// commitAndOpenPR commits the changes in the i-th repository, pushes
+// them, and opens its PR. Returns nil without error when the
+// repository has no changes.
+func (p *Pipeline) commitAndOpenPR(
+	ctx context.Context,
+	logger *zap.Logger,
+	params fanOutParams,
+	i int,
+) (*repoPR, error) {
+	repo := params.settings.Repos[i]
+	repoDir := filepath.Join(params.wsPath, repo.Name)
 
-		prs = append(prs, repoPR{owner: repo.Owner, repo: repo.Repo, url: pr.URL, number: pr.Number, draft: params.repoConfigs[i].PR.Draft})
-		logger.Info("PR created",
-			zap.String("repo", repo.Name),
-			zap.String("url", pr.URL),
-			zap.Int("number", pr.Number),
-			zap.Bool("draft", params.repoConfigs[i].PR.Draft))
+	hasChanges, err := p.git.HasChanges(repoDir, repo.BaseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("check changes for %s: %w", repo.Name, err)
+	}
+	if !hasChanges {
+		logger.Info("No changes in repo, skipping", zap.String("repo", repo.Name))
+		return nil, nil
 	}
 
-	return prs, nil
+	commitMsg := params.settings.CommitMessage.Render(params.ticketKey, *params.workItem)
+	_, span := p.startStage(ctx, spanCommit, params.ticketKey, attrRepo.String(repo.Name))
+	sha, err := p.git.CommitChanges(
+		repo.Owner, params.settings.CommitOwnerFor(repo), repo.Repo, params.branchName,
+		commitMsg, repoDir, repo.BaseBranch, params.workItem.Assignee, params.excludes,
+	)
+	endStage(span, err)
+	if errors.Is(err, services.ErrNoChanges) {
+		logger.Info("No committable changes in repo", zap.String("repo", repo.Name))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("commit changes for %s: %w", repo.Name, err)
+	}
+
+	if err := p.git.SyncWithRemote(repoDir, params.branchName, params.excludes); err != nil {
+		return nil, fmt.Errorf("sync with remote for %s: %w", repo.Name, err)
+	}
+
+	prTitle, prBody := buildPRContent(
+		params.workItem, params.ticketKey, params.repoConfigs[i].PR.TitlePrefix, params.aiPR)
+	prBody = withBatchKeys(prBody, params.alsoResolves)
+	prBody = withSelfReview(prBody, params.review, params.workItem.HasSecurityLevel())
+	prBody = withRegressionTestWarning(prBody, params.missingTest)
+
+	_, span = p.startStage(ctx, spanCreatePR, params.ticketKey, attrRepo.String(repo.Name))
+	pr, err := p.git.CreatePR(models.PRParams{
+		Owner:     repo.Owner,
+		Repo:      repo.Repo,
+		Title:     prTitle,
+		Body:      prBody,
+		Head:      params.settings.PRHead(params.branchName),
+		Base:      repo.BaseBranch,
+		Draft:     params.repoConfigs[i].PR.Draft,
+		Labels:    params.repoConfigs[i].PR.Labels,
+		Assignees: assigneesFromSettings(params.settings),
+	})
+	endStage(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("create PR for %s: %w", repo.Name, err)
+	}
+
+	if params.vlTarget != "" {
+		p.setPRValidationLabel(logger, repo.Owner, repo.Repo,
+			pr.Number, params.settings.PRValidationLabels, params.vlTarget)
+	}
+	p.publishCheckRun(logger, params.settings, repo.Owner, repo.Repo, sha, params.checkRun)
+
+	logger.Info("PR created",
+		zap.String("repo", repo.Name),
+		zap.String("url", pr.URL),
+		zap.Int("number", pr.Number),
+		zap.Bool("draft", params.repoConfigs[i].PR.Draft))
+	return &repoPR{
+		name: repo.Name, owner: repo.Owner, repo: repo.Repo, url: pr.URL, number: pr.Number,
+		draft: params.repoConfigs[i].PR.Draft, body: prBody,
+		diff: branchDiff{dir: repoDir, baseBranch: repo.BaseBranch},
+	}, nil
 }
 
 // prepareBranchForRepo sets up the working branch for a single repo
@@ -1407,6 +1983,49 @@ func buildScriptParams(provider, defaultClaudeModel, defaultGeminiModel string,
 	return params
 }
 
+// routeModel replaces the session's model with the one the model
+// router picks for the ticket, and logs the decision. A model the
+// repository's .ai-bot/config.yaml pins is kept unless a ticket label
+// decided the tier.
+func (p *Pipeline) routeModel(logger *zap.Logger, workItem models.WorkItem, sp *scriptParams, repoCfg *repoconfig.Config) {
+	if p.cfg.Models == nil {
+		return
+	}
+	d, ok := p.cfg.Models.Route(sp.Provider, workItem)
+	if !ok {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("tier", string(d.Tier)),
+		zap.Int("score", d.Score),
+		zap.Strings("reasons", d.Reasons),
+		zap.Bool("label_override", d.Override),
+	}
+	switch {
+	case d.Model == "":
+		logger.Info("Model routing kept the configured model", append(fields, zap.String("model", sp.Model))...)
+	case repoPinsModel(sp.Provider, repoCfg) && !d.Override:
+		logger.Info("Model routing kept the repository's model", append(fields, zap.String("model", sp.Model))...)
+	default:
+		sp.Model = d.Model
+		logger.Info("Model routed by ticket complexity", append(fields, zap.String("model", sp.Model))...)
+	}
+}
+
+// repoPinsModel reports whether repoCfg sets the model for provider.
+func repoPinsModel(provider string, repoCfg *repoconfig.Config) bool {
+	if repoCfg == nil {
+		return false
+	}
+	switch provider {
+	case "claude":
+		return repoCfg.AI.Claude != nil && repoCfg.AI.Claude.Model != ""
+	case "gemini":
+		return repoCfg.AI.Gemini != nil && repoCfg.AI.Gemini.Model != ""
+	}
+	return false
+}
+
 // prepareMultiRepoWorkspace creates or reuses a multi-repo workspace
 // and filters settings.Repos to only repos whose directories exist
 // on disk. Repos added to the config after a workspace was created
@@ -1419,7 +2038,7 @@ func (p *Pipeline) prepareMultiRepoWorkspace(
 ) (string, bool, error) {
 	repoEntries := make([]workspace.RepoEntry, len(settings.Repos))
 	for i, r := range settings.Repos {
-		repoEntries[i] = workspace.RepoEntry{Name: r.Name, URL: r.CloneURL}
+		repoEntries[i] = workspace.RepoEntry{Name: r.Name, URL: r.CloneURL, SparsePaths: r.SparsePaths}
 	}
 	wsPath, reused, err := p.workspaces.FindOrCreateMultiRepo(ticketKey, repoEntries, settings.RootRepoURL)
 	if err != nil {
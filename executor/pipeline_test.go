@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -244,6 +245,58 @@ func TestExecuteNewTicket_HappyPath(t *testing.T) {
 	}
 }
 
+func TestExecuteNewTicket_UsesRecentScannerSnapshot(t *testing.T) {
+	comments := []models.Comment{{ID: "1", Body: "Please keep the public API unchanged.", Author: "Alice"}}
+	tests := []struct {
+		name          string
+		age           time.Duration
+		wantFetches   int
+		wantSnapshots bool
+	}{
+		{name: "recent snapshot", age: time.Minute, wantFetches: 0, wantSnapshots: true},
+		{name: "stale snapshot", age: time.Hour, wantFetches: 1, wantSnapshots: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			fetches := 0
+			getWorkItem := d.tracker.GetWorkItemFunc
+			d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+				fetches++
+				return getWorkItem(key)
+			}
+			var written []models.Comment
+			d.taskWriter.WriteIssueFunc = func(_ models.WorkItem, _ string, _ []string, c []models.Comment) error {
+				written = c
+				return nil
+			}
+
+			job := newTicketJob("PROJ-123")
+			job.CreatedAt = time.Now().Add(-tt.age)
+			job.WorkItem = &models.WorkItem{
+				Key:        "PROJ-123",
+				Summary:    "Fix a bug",
+				Type:       "Bug",
+				Components: []string{},
+				Labels:     []string{},
+				Comments:   comments,
+			}
+
+			p := d.pipeline(t)
+			if _, err := p.Execute(context.Background(), job); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fetches != tt.wantFetches {
+				t.Errorf("GetWorkItem calls = %d, want %d", fetches, tt.wantFetches)
+			}
+			if got := slices.Equal(written, comments); got != tt.wantSnapshots {
+				t.Errorf("task comments = %+v, want snapshot comments: %v", written, tt.wantSnapshots)
+			}
+		})
+	}
+}
+
 // --- No changes ---
 
 func TestExecuteNewTicket_NoChanges(t *testing.T) {
@@ -339,6 +392,49 @@ func TestExecuteNewTicket_ParentContextCancelled(t *testing.T) {
 	}
 }
 
+func TestExecuteNewTicket_JobTimeout(t *testing.T) {
+	d := newTestDeps(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	d.containers.ExecFunc = func(execCtx context.Context, _ *container.Container, _ []string) (string, int, error) {
+		<-execCtx.Done() // hung AI CLI, killed when the job times out
+		return "", -1, execCtx.Err()
+	}
+	var cleaned string
+	d.workspaces.CleanupFunc = func(ticketKey string) error {
+		cleaned = ticketKey
+		return nil
+	}
+	var reverted bool
+	d.tracker.TransitionStatusFunc = func(_, status string) error {
+		if status == "To Do" {
+			reverted = true
+		}
+		return nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comment = body
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(ctx, newTicketJob("PROJ-1"))
+
+	if !errors.Is(err, jobmanager.ErrJobTimeout) {
+		t.Fatalf("err = %v, want ErrJobTimeout", err)
+	}
+	if cleaned != "PROJ-1" {
+		t.Errorf("cleaned workspace = %q, want PROJ-1", cleaned)
+	}
+	if !reverted {
+		t.Error("expected status to be reverted to todo")
+	}
+	if !strings.Contains(comment, "job timed out") {
+		t.Errorf("status comment = %q, want the timeout reason", comment)
+	}
+}
+
 // --- Container start failure ---
 
 func TestExecuteNewTicket_ContainerStartFails(t *testing.T) {
@@ -660,6 +756,44 @@ func TestExecuteNewTicket_NoAssignee_NilCoAuthor(t *testing.T) {
 	}
 }
 
+func TestExecuteNewTicket_CommitMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		commit models.CommitMessage
+		want   string
+	}{
+		{name: "default", want: "PROJ-1: Fix a bug"},
+		{name: "conventional", commit: models.CommitMessage{Conventional: true}, want: "fix: Fix a bug (PROJ-1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+				return &models.ProjectSettings{
+					Repos:            []models.RepoSettings{{Owner: "org", Repo: "repo", CloneURL: "https://github.com/org/repo.git", BaseBranch: "main"}},
+					InProgressStatus: "In Progress",
+					InReviewStatus:   "In Review",
+					TodoStatus:       "To Do",
+					CommitMessage:    tt.commit,
+				}, nil
+			}
+
+			var gotMsg string
+			d.git.CommitChangesFunc = func(_, _, _, _, msg, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+				gotMsg = msg
+				return "abc123", nil
+			}
+
+			if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotMsg != tt.want {
+				t.Errorf("commit message = %q, want %q", gotMsg, tt.want)
+			}
+		})
+	}
+}
+
 // --- Error comments disabled ---
 
 func TestExecuteNewTicket_ErrorCommentsDisabled(t *testing.T) {
@@ -799,7 +933,7 @@ func TestExecuteNewTicket_StatusComment_DeletedOnPRCreation(t *testing.T) {
 
 func TestExecuteNewTicket_WorkspaceReused_SwitchesBranch(t *testing.T) {
 	d := newTestDeps(t)
-	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string) (string, bool, error) {
+	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string, _ []string) (string, bool, error) {
 		return d.wsDir, true, nil // reused
 	}
 	d.git.RemoteBranchExistsFunc = func(owner, repo, branch string) (bool, error) {
@@ -837,7 +971,7 @@ func TestExecuteNewTicket_WorkspaceReused_SwitchesBranch(t *testing.T) {
 
 func TestExecuteNewTicket_WorkspaceReused_RemoteBranchDeleted_RecreatesBranch(t *testing.T) {
 	d := newTestDeps(t)
-	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string) (string, bool, error) {
+	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string, _ []string) (string, bool, error) {
 		return d.wsDir, true, nil // reused
 	}
 	d.git.RemoteBranchExistsFunc = func(owner, repo, branch string) (bool, error) {
@@ -1063,6 +1197,36 @@ func TestExecuteNewTicket_ProjectOverridesDefaultProvider(t *testing.T) {
 	}
 }
 
+type stubSecretResolver map[string]string
+
+func (r stubSecretResolver) Resolve(value string) string { return r[value] }
+
+func TestExecuteNewTicket_ResolvesSecretAPIKey(t *testing.T) {
+	d := newTestDeps(t)
+
+	var envVars map[string]string
+	d.containers.StartFunc = func(ctx context.Context, cfg *container.Config, wsDir, ticketKey string, env map[string]string) (*container.Container, error) {
+		envVars = env
+		return &container.Container{ID: "c1", Name: "test"}, nil
+	}
+
+	ref := "awssm://prod/ai-bot#claude"
+	p := d.pipelineWithConfig(t, executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": ref},
+		Secrets:         stubSecretResolver{ref: "resolved-key"},
+	})
+	_, err := p.Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if envVars["ANTHROPIC_API_KEY"] != "resolved-key" {
+		t.Errorf("ANTHROPIC_API_KEY = %q, want resolved-key", envVars["ANTHROPIC_API_KEY"])
+	}
+}
+
 // --- Vertex AI authentication ---
 
 func TestExecuteNewTicket_VertexAI_SetsEnvVars(t *testing.T) {
@@ -2677,7 +2841,7 @@ func TestPrepareBranch_ForkMode(t *testing.T) {
 	}
 
 	// Workspace is reused and remote branch exists.
-	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string) (string, bool, error) {
+	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string, _ []string) (string, bool, error) {
 		return d.wsDir, true, nil // reused
 	}
 
@@ -2719,7 +2883,7 @@ func TestPrepareBranch_ForkMode_SyncsForkBeforeCreateBranch(t *testing.T) {
 	}
 
 	// Fresh workspace (not reused) triggers CreateBranch path.
-	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string) (string, bool, error) {
+	d.workspaces.FindOrCreateFunc = func(ticketKey, repoURL string, _ []string) (string, bool, error) {
 		return d.wsDir, false, nil
 	}
 
@@ -3977,7 +4141,7 @@ func newTestDeps(t *testing.T) *testDeps {
 			},
 		},
 		workspaces: &workspacetest.Stub{
-			FindOrCreateFunc: func(ticketKey, repoURL string) (string, bool, error) {
+			FindOrCreateFunc: func(ticketKey, repoURL string, _ []string) (string, bool, error) {
 				return wsDir, false, nil
 			},
 		},
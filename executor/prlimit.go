@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// checkOpenPRLimit defers the job when any of the workspace's
+// repositories already has settings.MaxOpenPRsPerRepo or more open
+// bot PRs. The ticket stays in its todo status and gets a status
+// comment explaining the wait; the scanner resubmits it on later
+// cycles until a slot frees up. Returns nil when the limit is
+// disabled or not reached. Errors counting PRs are logged and do
+// not block the ticket.
+func (p *Pipeline) checkOpenPRLimit(logger *zap.Logger, ticketKey string, settings *models.ProjectSettings) error {
+	limit := settings.MaxOpenPRsPerRepo
+	if limit <= 0 {
+		return nil
+	}
+
+	prefix := p.cfg.BotUsername + "/"
+	for _, repo := range settings.Repos {
+		open, err := p.git.CountOpenPRs(repo.Owner, repo.Repo, prefix)
+		if err != nil {
+			logger.Warn("Failed to count open PRs, skipping PR limit check",
+				zap.String("repo", repo.Owner+"/"+repo.Repo),
+				zap.Error(err))
+			continue
+		}
+		if open < limit {
+			continue
+		}
+
+		logger.Info("Repository at open PR limit, deferring ticket",
+			zap.String("repo", repo.Owner+"/"+repo.Repo),
+			zap.Int("open_prs", open),
+			zap.Int("limit", limit))
+		p.upsertStatusComment(logger, ticketKey, formatPRLimitComment(repo.Owner, repo.Repo, limit))
+		return fmt.Errorf("%s/%s has %d open bot PRs (limit %d): %w",
+			repo.Owner, repo.Repo, open, limit, jobmanager.ErrDeferred)
+	}
+	return nil
+}
+
+// formatPRLimitComment builds the status comment posted while a
+// ticket waits for its repository to drop below the open PR limit.
+// The body depends only on the repository and limit so that repeated
+// deferrals do not rewrite the comment.
+func formatPRLimitComment(owner, repo string, limit int) string {
+	return fmt.Sprintf("%s Waiting to start: %s/%s already has %d or more open AI pull requests, "+
+		"the configured limit. This ticket stays queued and will be picked up automatically "+
+		"once one of them is merged or closed.", statusCommentMarker, owner, repo, limit)
+}
@@ -0,0 +1,119 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// withOpenPRLimit wraps the default project resolver to set the
+// per-repository open PR limit.
+func withOpenPRLimit(d *testDeps, limit int) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err != nil {
+			return nil, err
+		}
+		settings.MaxOpenPRsPerRepo = limit
+		return settings, nil
+	}
+}
+
+func TestExecuteNewTicket_OpenPRLimitReachedDefers(t *testing.T) {
+	d := newTestDeps(t)
+	withOpenPRLimit(d, 2)
+
+	var gotPrefix string
+	d.git.CountOpenPRsFunc = func(owner, repo, branchPrefix string) (int, error) {
+		gotPrefix = branchPrefix
+		return 2, nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if !errors.Is(err, jobmanager.ErrDeferred) {
+		t.Fatalf("error = %v, want ErrDeferred", err)
+	}
+	if gotPrefix != "ai-bot/" {
+		t.Errorf("branch prefix = %q, want ai-bot/", gotPrefix)
+	}
+	if len(transitions) != 0 {
+		t.Errorf("status transitions = %v, want none", transitions)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "[AI-BOT-STATUS]") || !strings.Contains(comments[0], "org/repo") {
+		t.Errorf("comments = %q, want one status comment naming org/repo", comments)
+	}
+}
+
+func TestExecuteNewTicket_OpenPRLimitDoesNotRewriteSameComment(t *testing.T) {
+	d := newTestDeps(t)
+	withOpenPRLimit(d, 1)
+	d.git.CountOpenPRsFunc = func(_, _, _ string) (int, error) { return 3, nil }
+
+	var existing string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		existing = body
+		return nil
+	}
+	d.tracker.GetCommentsFunc = func(key string) ([]models.Comment, error) {
+		if existing == "" {
+			return nil, nil
+		}
+		return []models.Comment{{ID: "10", Body: existing}}, nil
+	}
+	updates := 0
+	d.tracker.UpdateCommentFunc = func(_, _, _ string) error {
+		updates++
+		return nil
+	}
+
+	p := d.pipeline(t)
+	for range 2 {
+		if _, err := p.Execute(context.Background(), newTicketJob("PROJ-1")); !errors.Is(err, jobmanager.ErrDeferred) {
+			t.Fatalf("error = %v, want ErrDeferred", err)
+		}
+	}
+	if updates != 0 {
+		t.Errorf("UpdateComment called %d times, want 0 for an unchanged comment", updates)
+	}
+}
+
+func TestExecuteNewTicket_OpenPRLimitBelowLimitProceeds(t *testing.T) {
+	d := newTestDeps(t)
+	withOpenPRLimit(d, 2)
+	d.git.CountOpenPRsFunc = func(_, _, _ string) (int, error) { return 1, nil }
+
+	result, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.PRNumber != 1 {
+		t.Errorf("PRNumber = %d, want 1", result.PRNumber)
+	}
+}
+
+func TestExecuteNewTicket_OpenPRLimitCountErrorProceeds(t *testing.T) {
+	d := newTestDeps(t)
+	withOpenPRLimit(d, 2)
+	d.git.CountOpenPRsFunc = func(_, _, _ string) (int, error) { return 0, errors.New("rate limited") }
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package executor_test
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestExecuteNewTicket_AppliesPromptStrategy(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategy     models.PromptStrategy
+		wantGuidance string
+		wantWorkflow string
+	}{
+		{name: "no strategy keeps profile workflow", wantWorkflow: "profile workflow"},
+		{
+			name:         "strategy overrides",
+			strategy:     models.PromptStrategy{Guidance: "Reproduce first.", Workflow: "bug workflow"},
+			wantGuidance: "Reproduce first.",
+			wantWorkflow: "bug workflow",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			resolve := d.projects.ResolveProjectFunc
+			d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+				settings, err := resolve(workItem)
+				if err == nil {
+					settings.Repos[0].NewTicketWorkflow = "profile workflow"
+					settings.PromptStrategy = tt.strategy
+				}
+				return settings, err
+			}
+			var guidance, workflow string
+			d.taskWriter.WriteNewTicketTaskFunc = func(workItem models.WorkItem, dir, _, overrideWorkflow string) error {
+				guidance, workflow = workItem.TypeGuidance, overrideWorkflow
+				writeTaskFile(t, dir)
+				return nil
+			}
+
+			if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if guidance != tt.wantGuidance || workflow != tt.wantWorkflow {
+				t.Errorf("guidance, workflow = %q, %q; want %q, %q", guidance, workflow, tt.wantGuidance, tt.wantWorkflow)
+			}
+		})
+	}
+}
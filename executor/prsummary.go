@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jira-ai-issue-solver/taskfile"
+)
+
+// prSummary is the AI's structured description of a new-ticket
+// change, read from taskfile.PRSummaryPath.
+type prSummary struct {
+	Changes   []string `json:"changes"`
+	TestPlan  []string `json:"test_plan"`
+	Risk      string   `json:"risk"`
+	RiskNotes string   `json:"risk_notes"`
+
+	// Confidence is how confident the AI is, from 0 to 100, that the
+	// change is correct and complete. Nil when not reported or out of
+	// range.
+	Confidence *int `json:"confidence"`
+}
+
+// readPRSummary reads the AI's PR summary from the workspace. Returns
+// nil if the file is missing, malformed, or lists neither changes nor
+// a test plan.
+func readPRSummary(dir string) *prSummary {
+	data, err := os.ReadFile(filepath.Join(dir, taskfile.PRSummaryPath)) // #nosec G304 -- path is dir + constant
+	if err != nil {
+		return nil
+	}
+	var s prSummary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil
+	}
+	s.Changes = nonEmpty(s.Changes)
+	s.TestPlan = nonEmpty(s.TestPlan)
+	if len(s.Changes) == 0 && len(s.TestPlan) == 0 {
+		return nil
+	}
+	s.Risk = strings.ToLower(strings.TrimSpace(s.Risk))
+	switch s.Risk {
+	case "low", "medium", "high":
+	default:
+		s.Risk = ""
+	}
+	if s.Confidence != nil && (*s.Confidence < 0 || *s.Confidence > 100) {
+		s.Confidence = nil
+	}
+	return &s
+}
+
+// nonEmpty returns items without blank entries, trimmed.
+func nonEmpty(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// render formats the summary as PR body sections.
+func (s *prSummary) render() string {
+	var b strings.Builder
+	writeList := func(heading string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "## %s\n", heading)
+		for _, item := range items {
+			fmt.Fprintf(&b, "\n- %s", item)
+		}
+	}
+	writeList("Changes Made", s.Changes)
+	writeList("Test Plan", s.TestPlan)
+
+	notes := strings.TrimSpace(s.RiskNotes)
+	if s.Risk == "" && notes == "" {
+		return b.String()
+	}
+	b.WriteString("\n\n## Risk\n\n")
+	switch {
+	case s.Risk != "" && notes != "":
+		fmt.Fprintf(&b, "**%s%s**: %s", strings.ToUpper(s.Risk[:1]), s.Risk[1:], notes)
+	case s.Risk != "":
+		fmt.Fprintf(&b, "**%s%s**", strings.ToUpper(s.Risk[:1]), s.Risk[1:])
+	default:
+		b.WriteString(notes)
+	}
+	return b.String()
+}
+
+// withPRSummary returns aiPR with the summary's sections appended to
+// its body. When the AI wrote no PR description, the summary becomes
+// the body, so that it replaces the ticket description the PR body
+// would otherwise copy. A nil summary leaves aiPR unchanged.
+func withPRSummary(aiPR *PRDescription, summary *prSummary) *PRDescription {
+	if summary == nil {
+		return aiPR
+	}
+	merged := PRDescription{Body: summary.render()}
+	if aiPR != nil {
+		merged.Title = aiPR.Title
+		if aiPR.Body != "" {
+			merged.Body = aiPR.Body + "\n\n" + merged.Body
+		}
+	}
+	return &merged
+}
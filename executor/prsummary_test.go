@@ -0,0 +1,107 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// writeSessionFile writes content to rel under the workspace.
+func writeSessionFile(t *testing.T, d *testDeps, rel, content string) {
+	t.Helper()
+	path := filepath.Join(d.wsDir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// capturePRBody records the body of the created PR.
+func capturePRBody(d *testDeps) *string {
+	var body string
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		body = params.Body
+		return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+	}
+	return &body
+}
+
+func TestExecuteNewTicket_PRSummaryReplacesTicketDescription(t *testing.T) {
+	d := newTestDeps(t)
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Summary: "Fix a bug", Description: "Long ticket description."}, nil
+	}
+	writeSessionFile(t, d, taskfile.PRSummaryPath, `{
+		"changes": ["Retry webhook delivery with backoff", " "],
+		"test_plan": ["Added TestDeliver_Retries", "Ran make test"],
+		"risk": "Medium",
+		"risk_notes": "Changes delivery timing."
+	}`)
+	body := capturePRBody(d)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Resolves PROJ-1\n\n" +
+		"## Changes Made\n\n- Retry webhook delivery with backoff\n\n" +
+		"## Test Plan\n\n- Added TestDeliver_Retries\n- Ran make test\n\n" +
+		"## Risk\n\n**Medium**: Changes delivery timing."
+	if *body != want {
+		t.Errorf("PR body =\n%s\nwant\n%s", *body, want)
+	}
+}
+
+func TestExecuteNewTicket_PRSummaryFollowsAIDescription(t *testing.T) {
+	d := newTestDeps(t)
+	writeSessionFile(t, d, taskfile.PRDescriptionPath, "## Title\n\nRetry webhook delivery\n\n## Why\n\nDeliveries were lost.\n")
+	writeSessionFile(t, d, taskfile.PRSummaryPath, `{"changes": ["Added retries"], "risk": "low"}`)
+	body := capturePRBody(d)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Resolves PROJ-1\n\n## Why\n\nDeliveries were lost.\n\n## Changes Made\n\n- Added retries\n\n## Risk\n\n**Low**"
+	if *body != want {
+		t.Errorf("PR body =\n%s\nwant\n%s", *body, want)
+	}
+}
+
+func TestExecuteNewTicket_InvalidPRSummaryIgnored(t *testing.T) {
+	d := newTestDeps(t)
+	writeSessionFile(t, d, taskfile.PRSummaryPath, `{"changes": [], "risk": "high"}`)
+	body := capturePRBody(d)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(*body, "## Risk") || !strings.Contains(*body, "## Summary\nFix a bug") {
+		t.Errorf("PR body should fall back to the ticket, got:\n%s", *body)
+	}
+}
+
+func TestExecuteNewTicket_PRSummaryRedactedForSecurityLevel(t *testing.T) {
+	d := newTestDeps(t)
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Summary: "Fix auth bypass", SecurityLevel: "Embargoed"}, nil
+	}
+	writeSessionFile(t, d, taskfile.PRSummaryPath, `{"changes": ["Closed the auth bypass in login"]}`)
+	body := capturePRBody(d)
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("SEC-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(*body, "auth bypass") {
+		t.Errorf("PR body should be redacted, got:\n%s", *body)
+	}
+}
@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// bugTicketType is the Jira issue type whose changes must include a
+// regression test when [models.ProjectSettings.RegressionTests] is
+// set.
+const bugTicketType = "bug"
+
+// needsRegressionTest reports whether the changes for workItem must be
+// checked for a regression test.
+func needsRegressionTest(workItem *models.WorkItem, settings *models.ProjectSettings) bool {
+	return settings.RegressionTests != "" && strings.EqualFold(workItem.Type, bugTicketType)
+}
+
+// requireRegressionTest checks that the uncommitted changes of a Bug
+// ticket add or modify a test file. Under the "ai" policy, changes
+// without one get another AI session, whose task file writeTask
+// writes, asking for a regression test; its cost is added to out and
+// out.HasChanges is updated from hasChanges. The result reports
+// whether the changes still include no test, in which case the PR
+// body should carry a warning.
+//
+// A failed regression test session does not block the change. Errors
+// are returned only for job cancellation, or when listing changes or
+// writeTask fails.
+func (p *Pipeline) requireRegressionTest(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	ctr *container.Container,
+	wsPath string,
+	sp scriptParams,
+	settings *models.ProjectSettings,
+	out *aiOutcome,
+	writeTask func() error,
+	hasChanges func() (bool, error),
+) (missing bool, err error) {
+	found, err := p.changesIncludeTests(wsPath, settings)
+	if err != nil || found {
+		return false, err
+	}
+
+	if settings.RegressionTests == models.RegressionTestsAI {
+		if err := p.runRegressionTestSession(ctx, logger, job, ctr, wsPath, sp, settings.MaxTicketCostUSD, out, writeTask); err != nil {
+			return false, err
+		}
+		changed, err := hasChanges()
+		if err != nil {
+			return false, fmt.Errorf("check changes: %w", err)
+		}
+		out.HasChanges = changed
+		if found, err = p.changesIncludeTests(wsPath, settings); err != nil {
+			return false, err
+		}
+	}
+
+	if !found {
+		logger.Warn("Bug fix includes no test changes",
+			zap.String("policy", settings.RegressionTests))
+	}
+	return !found, nil
+}
+
+// runRegressionTestSession runs an AI session asking for a regression
+// test for the changes in the workspace. The session is skipped when
+// the per-ticket cost cap is reached.
+func (p *Pipeline) runRegressionTestSession(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	ctr *container.Container,
+	wsPath string,
+	sp scriptParams,
+	maxTicketCost float64,
+	out *aiOutcome,
+	writeTask func() error,
+) error {
+	if p.checkTicketCostCap(logger, wsPath, maxTicketCost) {
+		logger.Info("Per-ticket cost cap reached, skipping regression test session")
+		return nil
+	}
+	if err := writeTask(); err != nil {
+		return fmt.Errorf("write regression test task file: %w", err)
+	}
+
+	execCtx, span := p.startStage(ctx, spanAISession, job.TicketKey,
+		attribute.String("ai.provider", sp.Provider),
+		attribute.Bool("ai.regression_test", true))
+	var cancel context.CancelFunc = func() {}
+	if p.cfg.SessionTimeout > 0 {
+		execCtx, cancel = context.WithTimeout(execCtx, p.cfg.SessionTimeout)
+	}
+	exitCode, execErr := p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
+	cancel()
+	span.SetAttributes(attribute.Int("ai.exit_code", exitCode))
+	endStage(span, execErr)
+	if execErr != nil && ctx.Err() != nil {
+		return fmt.Errorf("job cancelled: %w", ctx.Err())
+	}
+
+	session := readSessionOutput(wsPath)
+	p.applyCostEstimate(&session)
+	out.CostUSD += session.CostUSD
+	p.recordTicketCost(logger, wsPath, maxTicketCost, session.CostUSD)
+
+	if execErr != nil {
+		logger.Warn("AI regression test session failed", zap.Error(execErr))
+	}
+	logger.Info("AI regression test session completed",
+		zap.Int("exit_code", exitCode),
+		zap.Float64("cost_usd", session.CostUSD))
+	return nil
+}
+
+// changesIncludeTests reports whether the changes in any of the
+// workspace's repositories add or modify a test file.
+func (p *Pipeline) changesIncludeTests(wsPath string, settings *models.ProjectSettings) (bool, error) {
+	for _, repo := range settings.Repos {
+		repoDir := wsPath
+		if settings.IsMultiRepo() {
+			repoDir = filepath.Join(wsPath, repo.Name)
+		}
+		changed, err := p.git.ChangedFiles(repoDir, repo.BaseBranch)
+		if err != nil {
+			return false, fmt.Errorf("list changed files for %s: %w", repo.Name, err)
+		}
+		for _, file := range changed {
+			if isTestFile(file) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// testDirs are directory names whose files are taken to be tests.
+var testDirs = map[string]bool{
+	"test":      true,
+	"tests":     true,
+	"__tests__": true,
+	"spec":      true,
+}
+
+// isTestFile reports whether the repository-relative path names a
+// test file, judged by the naming conventions of common languages
+// (foo_test.go, test_foo.py, foo.spec.ts, FooTest.java, ...) or by
+// lying under a test directory.
+func isTestFile(file string) bool {
+	base := path.Base(file)
+	lower := strings.ToLower(base)
+	ext := path.Ext(lower)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	switch {
+	case strings.HasSuffix(lower, "_test"+ext),
+		strings.HasSuffix(lower, "_spec"+ext),
+		strings.HasPrefix(lower, "test_"),
+		strings.Contains(lower, ".test."),
+		strings.Contains(lower, ".spec."),
+		strings.HasSuffix(stem, "Test"),
+		strings.HasSuffix(stem, "Tests"):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if testDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// withRegressionTestWarning puts a warning at the top of a PR body
+// when the bug fix includes no test.
+func withRegressionTestWarning(body string, missing bool) string {
+	if !missing {
+		return body
+	}
+	return "> [!WARNING]\n" +
+		"> **No regression test.** This fixes a bug but adds or changes no test file. " +
+		"Please make sure the fix is covered by a test before merging.\n\n" + body
+}
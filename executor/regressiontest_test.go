@@ -0,0 +1,152 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+)
+
+func TestExecuteNewTicket_RegressionTests(t *testing.T) {
+	tests := []struct {
+		name         string
+		ticketType   string
+		policy       string
+		changed      []string // after the first session
+		changedAfter []string // after a regression test session
+		wantSessions int
+		wantWarning  bool
+	}{
+		{
+			name:         "warn without test",
+			ticketType:   "Bug",
+			policy:       models.RegressionTestsWarn,
+			changed:      []string{"auth/token.go"},
+			wantSessions: 1,
+			wantWarning:  true,
+		},
+		{
+			name:         "fix with test",
+			ticketType:   "bug",
+			policy:       models.RegressionTestsAI,
+			changed:      []string{"auth/token.go", "auth/token_test.go"},
+			wantSessions: 1,
+		},
+		{
+			name:         "AI adds test",
+			ticketType:   "Bug",
+			policy:       models.RegressionTestsAI,
+			changed:      []string{"auth/token.go"},
+			changedAfter: []string{"auth/token.go", "auth/token_test.go"},
+			wantSessions: 2,
+		},
+		{
+			name:         "AI adds no test",
+			ticketType:   "Bug",
+			policy:       models.RegressionTestsAI,
+			changed:      []string{"auth/token.go"},
+			changedAfter: []string{"auth/token.go"},
+			wantSessions: 2,
+			wantWarning:  true,
+		},
+		{
+			name:         "not a bug",
+			ticketType:   "Story",
+			policy:       models.RegressionTestsAI,
+			changed:      []string{"auth/token.go"},
+			wantSessions: 1,
+		},
+		{
+			name:         "disabled",
+			ticketType:   "Bug",
+			changed:      []string{"auth/token.go"},
+			wantSessions: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDeps(t)
+			resolve := d.projects.ResolveProjectFunc
+			d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+				settings, err := resolve(workItem)
+				if err == nil {
+					settings.RegressionTests = tt.policy
+				}
+				return settings, err
+			}
+			getWorkItem := d.tracker.GetWorkItemFunc
+			d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+				item, err := getWorkItem(key)
+				if err == nil {
+					item.Type = tt.ticketType
+				}
+				return item, err
+			}
+			regressionTask := false
+			d.taskWriter.WriteRegressionTestTaskFunc = func(workItem models.WorkItem, dir, _ string) error {
+				regressionTask = true
+				return os.MkdirAll(filepath.Join(dir, ".ai-session"), 0o750)
+			}
+			sessions := 0
+			d.git.ChangedFilesFunc = func(_, _ string) ([]string, error) {
+				if sessions > 1 {
+					return tt.changedAfter, nil
+				}
+				return tt.changed, nil
+			}
+			var body string
+			d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+				body = params.Body
+				return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+			}
+			runner := &executortest.StubAgentRunner{
+				RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+					sessions++
+					return agent.Result{}, nil
+				},
+			}
+
+			if _, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sessions != tt.wantSessions || regressionTask != (tt.wantSessions == 2) {
+				t.Errorf("sessions = %d, regression task written = %v; want %d sessions", sessions, regressionTask, tt.wantSessions)
+			}
+			if got := strings.HasPrefix(body, "> [!WARNING]\n> **No regression test.**"); got != tt.wantWarning {
+				t.Errorf("PR body warning = %v, want %v:\n%s", got, tt.wantWarning, body)
+			}
+		})
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"auth/token_test.go", true},
+		{"tests/test_token.py", true},
+		{"token_test.py", true},
+		{"web/src/Token.test.tsx", true},
+		{"web/src/token.spec.js", true},
+		{"web/src/__tests__/token.js", true},
+		{"src/test/java/com/acme/TokenTest.java", true},
+		{"spec/token_spec.rb", true},
+		{"auth/token.go", false},
+		{"auth/contest.go", false},
+		{"auth/testdata/token.json", false},
+		{"docs/testing.md", false},
+	}
+	for _, tt := range tests {
+		if got := executor.IsTestFile(tt.file); got != tt.want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
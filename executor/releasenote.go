@@ -0,0 +1,265 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoconfig"
+	"jira-ai-issue-solver/services"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// releaseNote is the AI's release note, read from
+// taskfile.ReleaseNotePath.
+type releaseNote struct {
+	Note string `json:"note"`
+}
+
+// readReleaseNote reads the AI's release note from the workspace.
+// Returns "" if the file is missing, malformed, or blank.
+func readReleaseNote(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, taskfile.ReleaseNotePath)) // #nosec G304 -- path is dir + constant
+	if err != nil {
+		return ""
+	}
+	var n releaseNote
+	if err := json.Unmarshal(data, &n); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(n.Note)
+}
+
+// executeReleaseNote has the AI write a user-facing release note for
+// the ticket's merged change and records it in the project's release
+// note field, in a follow-up PR adding it to the changelog, or both.
+// The changelog PR is skipped when one already exists, whether open,
+// merged, or closed, so the job can be resubmitted safely. The ticket
+// is labeled once the note is recorded.
+//
+//nolint:cyclop
+func (p *Pipeline) executeReleaseNote(ctx context.Context, job *jobmanager.Job) (result jobmanager.JobResult, retErr error) {
+	logger := p.logger.With(
+		zap.String("ticket", job.TicketKey),
+		zap.String("job_id", job.ID),
+		zap.Int("attempt", job.AttemptNum),
+	)
+	logger.Info("Starting release note pipeline")
+
+	// --- Step 1: Fetch work item ---
+	workItem, err := p.jobWorkItem(job)
+	if err != nil {
+		return result, fmt.Errorf("get work item: %w", err)
+	}
+
+	// --- Step 2: Resolve project settings ---
+	settings, err := p.projects.ResolveProject(*workItem)
+	if err != nil {
+		return result, fmt.Errorf("resolve project: %w", err)
+	}
+	notes := settings.ReleaseNotes
+	if !notes.Pending(workItem.Labels) {
+		logger.Info("Release note not needed")
+		return result, nil
+	}
+
+	defer func() {
+		if retErr != nil {
+			p.handleReleaseNoteFailure(logger, job.TicketKey, settings, retErr)
+		}
+	}()
+
+	if settings.IsMultiRepo() {
+		return result, errors.New("release notes are not supported for multi-repo workspaces")
+	}
+	repo := settings.Repos[0]
+
+	// --- Step 3: Find the merged PR ---
+	branchName := fmt.Sprintf("%s/%s", p.cfg.BotUsername, job.TicketKey)
+	merged, err := p.findMergedPR(repo, settings.PRHeads(branchName))
+	if err != nil {
+		return result, err
+	}
+
+	changelog := notes.ChangelogFile
+	noteBranch := models.ReleaseNoteBranchName(p.cfg.BotUsername, job.TicketKey)
+	if changelog != "" {
+		exists, err := p.backportPRExists(repo, settings.PRHeads(noteBranch))
+		if err != nil {
+			return result, err
+		}
+		if exists {
+			logger.Debug("Release note PR already exists, skipping the changelog")
+			changelog = ""
+		}
+	}
+
+	// --- Step 4: Prepare workspace ---
+	wsPath, _, err := p.workspaces.FindOrCreate(job.TicketKey, repo.CloneURL, repo.SparsePaths)
+	if err != nil {
+		return result, fmt.Errorf("prepare workspace: %w", err)
+	}
+	if err := p.ensureForkRemote(wsPath, settings); err != nil {
+		return result, err
+	}
+	if forkOwner := settings.ForkOwner(); forkOwner != "" && changelog != "" {
+		if err := p.git.SyncFork(forkOwner, repo.Repo, merged.BaseBranch); err != nil {
+			logger.Warn("Failed to sync fork with upstream",
+				zap.String("fork", forkOwner+"/"+repo.Repo),
+				zap.Error(err))
+		}
+	}
+	if err := p.git.CreateBranch(wsPath, noteBranch, merged.BaseBranch); err != nil {
+		return result, fmt.Errorf("create branch: %w", err)
+	}
+	repoCfg, err := repoconfig.Load(wsPath)
+	if err != nil {
+		logger.Warn("Failed to load repo config, using defaults", zap.Error(err))
+		repoCfg = repoconfig.Default()
+	}
+
+	// --- Step 5: Write task files ---
+	comments := p.fetchTicketComments(logger, *workItem)
+	if err := p.taskWriter.WriteIssue(*workItem, wsPath, nil, comments); err != nil {
+		return result, fmt.Errorf("write issue file: %w", err)
+	}
+	if err := p.taskWriter.WriteReleaseNoteTask(*workItem, *merged, changelog, wsPath); err != nil {
+		return result, fmt.Errorf("write release note task file: %w", err)
+	}
+	if err := os.Remove(filepath.Join(wsPath, taskfile.ReleaseNotePath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Failed to remove stale release note", zap.Error(err))
+	}
+
+	// --- Step 6: Run AI session ---
+	session, err := p.runRepoSession(ctx, logger, job, settings, repoCfg, wsPath, p.cfg.SessionTimeout)
+	result.CostUSD = session.CostUSD
+	if err != nil {
+		return result, err
+	}
+	note := readReleaseNote(wsPath)
+	if note == "" {
+		return result, errors.New("AI wrote no release note")
+	}
+
+	// --- Step 7: Record the note ---
+	if notes.Field != "" {
+		if err := p.tracker.SetFieldValue(job.TicketKey, notes.Field, note); err != nil {
+			return result, fmt.Errorf("set release note field: %w", err)
+		}
+	}
+	if changelog != "" {
+		pr, err := p.openReleaseNotePR(workItem, settings, repoCfg, merged, wsPath, noteBranch, changelog, note)
+		if err != nil {
+			return result, err
+		}
+		result.PRURL = pr.URL
+		result.PRNumber = pr.Number
+		logger.Info("Release note PR created",
+			zap.String("url", pr.URL),
+			zap.Int("number", pr.Number))
+
+		comment := fmt.Sprintf("Opened a PR adding the release note to %s: %s", changelog, pr.URL)
+		if err := p.tracker.AddComment(job.TicketKey, comment); err != nil {
+			logger.Warn("Failed to post release note comment", zap.Error(err))
+		}
+	}
+
+	// --- Step 8: Mark the ticket noted ---
+	if err := p.tracker.AddLabel(job.TicketKey, notes.NotedLabel()); err != nil {
+		return result, fmt.Errorf("add release note label: %w", err)
+	}
+
+	logger.Info("Release note written")
+	return result, nil
+}
+
+// openReleaseNotePR commits the AI's changelog edit and opens the
+// follow-up PR against the merged PR's base branch. The edit must
+// touch the changelog and nothing else.
+func (p *Pipeline) openReleaseNotePR(
+	workItem *models.WorkItem,
+	settings *models.ProjectSettings,
+	repoCfg *repoconfig.Config,
+	merged *models.PRDetails,
+	wsPath, branchName, changelog, note string,
+) (*models.PR, error) {
+	repo := settings.Repos[0]
+
+	files, err := p.git.ChangedFiles(wsPath, merged.BaseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("list changed files: %w", err)
+	}
+	if !slices.Contains(files, changelog) {
+		return nil, fmt.Errorf("AI did not add the release note to %s", changelog)
+	}
+	if len(files) > 1 {
+		return nil, fmt.Errorf("AI changed files other than %s: %s", changelog, strings.Join(files, ", "))
+	}
+
+	commitMsg := workItem.Key + ": add release note"
+	_, err = p.git.CommitChanges(
+		repo.Owner, settings.CommitOwner(), repo.Repo, branchName,
+		commitMsg, wsPath, merged.BaseBranch, workItem.Assignee, nil, skipFileGuardrail,
+	)
+	if errors.Is(err, services.ErrNoChanges) {
+		return nil, fmt.Errorf("AI did not add the release note to %s", changelog)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("commit changes: %w", err)
+	}
+
+	pr, err := p.git.CreatePR(models.PRParams{
+		Owner:     repo.Owner,
+		Repo:      repo.Repo,
+		Title:     workItem.Key + ": Add release note",
+		Body:      releaseNotePRBody(workItem.Key, merged, note),
+		Head:      settings.PRHead(branchName),
+		Base:      merged.BaseBranch,
+		Draft:     repoCfg.PR.Draft,
+		Labels:    repoCfg.PR.Labels,
+		Assignees: assigneesFromSettings(settings),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create PR: %w", err)
+	}
+	return pr, nil
+}
+
+// releaseNotePRBody links the release note PR to the merged PR and the
+// ticket, and quotes the note.
+func releaseNotePRBody(ticketKey string, merged *models.PRDetails, note string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Release note for #%d.\n\nRelated to %s\n\n## Release Note\n", merged.Number, ticketKey)
+	for _, line := range strings.Split(note, "\n") {
+		b.WriteString("\n> " + line)
+	}
+	return b.String()
+}
+
+// handleReleaseNoteFailure posts an error comment when writing a
+// release note fails. The ticket is left unlabeled, so that the note
+// is attempted again on a later scan until its retries are exhausted.
+func (p *Pipeline) handleReleaseNoteFailure(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	jobErr error,
+) {
+	if settings.DisableErrorComments {
+		return
+	}
+
+	comment := fmt.Sprintf("AI release note failed: %s", jobErr.Error())
+	if err := p.tracker.AddComment(ticketKey, comment); err != nil {
+		logger.Error("Failed to post error comment", zap.Error(err))
+	}
+}
@@ -0,0 +1,181 @@
+package executor_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// withReleaseNotes configures release notes for PROJ-1, makes its PR
+// #42 merged, and has the AI write note to the release note file.
+func withReleaseNotes(t *testing.T, d *testDeps, notes models.ReleaseNotes, note string) {
+	t.Helper()
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(item models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(item)
+		if err != nil {
+			return nil, err
+		}
+		settings.ReleaseNotes = notes
+		return settings, nil
+	}
+	d.git.GetMergedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		if head != "ai-bot/PROJ-1" {
+			return nil, nil
+		}
+		return &models.PRDetails{
+			Number:         42,
+			Title:          "PROJ-1: Refresh expired tokens",
+			BaseBranch:     "main",
+			URL:            "https://github.com/org/repo/pull/42",
+			MergeCommitSHA: "merge123",
+		}, nil
+	}
+	d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+		writeSessionFile(t, d, taskfile.ReleaseNotePath, note)
+		return "", 0, nil
+	}
+}
+
+func releaseNoteJob(ticketKey string) *jobmanager.Job {
+	return &jobmanager.Job{
+		ID:         "release-note-job-1",
+		TicketKey:  ticketKey,
+		Type:       jobmanager.JobTypeReleaseNote,
+		AttemptNum: 1,
+	}
+}
+
+func TestExecuteReleaseNote_WritesField(t *testing.T) {
+	d := newTestDeps(t)
+	withReleaseNotes(t, d, models.ReleaseNotes{Field: "Release Note Text"},
+		`{"note": "  Expired sessions are now renewed without signing in again.  "}`)
+
+	fields := map[string]string{}
+	d.tracker.SetFieldValueFunc = func(_, field, value string) error {
+		fields[field] = value
+		return nil
+	}
+	var labels []string
+	d.tracker.AddLabelFunc = func(_, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+	d.git.CreatePRFunc = func(models.PRParams) (*models.PR, error) {
+		t.Error("opened a PR without a changelog file configured")
+		return &models.PR{}, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), releaseNoteJob("PROJ-1")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if got := fields["Release Note Text"]; got != "Expired sessions are now renewed without signing in again." {
+		t.Errorf("release note field = %q", got)
+	}
+	if !slices.Equal(labels, []string{"ai-release-noted"}) {
+		t.Errorf("labels = %v, want ai-release-noted", labels)
+	}
+}
+
+func TestExecuteReleaseNote_OpensChangelogPR(t *testing.T) {
+	d := newTestDeps(t)
+	withReleaseNotes(t, d, models.ReleaseNotes{ChangelogFile: "CHANGELOG.md"},
+		`{"note": "Expired sessions are now renewed without signing in again."}`)
+
+	var changelog string
+	d.taskWriter.WriteReleaseNoteTaskFunc = func(_ models.WorkItem, _ models.PRDetails, file, _ string) error {
+		changelog = file
+		return nil
+	}
+	var branches []string
+	d.git.CreateBranchFunc = func(_, name, base string) error {
+		branches = append(branches, name+"<-"+base)
+		return nil
+	}
+	d.git.ChangedFilesFunc = func(string, string) ([]string, error) {
+		return []string{"CHANGELOG.md"}, nil
+	}
+	var pr models.PRParams
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		pr = params
+		return &models.PR{Number: 43, URL: "https://github.com/org/repo/pull/43"}, nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+
+	result, err := d.pipeline(t).Execute(context.Background(), releaseNoteJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if changelog != "CHANGELOG.md" {
+		t.Errorf("task changelog = %q, want CHANGELOG.md", changelog)
+	}
+	if !slices.Equal(branches, []string{"ai-bot/PROJ-1-release-note<-main"}) {
+		t.Errorf("branches = %v", branches)
+	}
+	if pr.Head != "ai-bot/PROJ-1-release-note" || pr.Base != "main" {
+		t.Errorf("PR head/base = %s/%s", pr.Head, pr.Base)
+	}
+	if !strings.Contains(pr.Body, "#42") || !strings.Contains(pr.Body, "> Expired sessions are now renewed") {
+		t.Errorf("PR body should link the merged PR and quote the note, got %q", pr.Body)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "https://github.com/org/repo/pull/43") {
+		t.Errorf("ticket comments = %v, want the release note PR", comments)
+	}
+	if result.PRNumber != 43 {
+		t.Errorf("result PR = %d, want 43", result.PRNumber)
+	}
+}
+
+func TestExecuteReleaseNote_RejectsOtherFileChanges(t *testing.T) {
+	d := newTestDeps(t)
+	withReleaseNotes(t, d, models.ReleaseNotes{ChangelogFile: "CHANGELOG.md"},
+		`{"note": "Expired sessions are now renewed."}`)
+	d.git.ChangedFilesFunc = func(string, string) ([]string, error) {
+		return []string{"CHANGELOG.md", "main.go"}, nil
+	}
+	d.git.CreatePRFunc = func(models.PRParams) (*models.PR, error) {
+		t.Error("opened a PR with changes outside the changelog")
+		return &models.PR{}, nil
+	}
+	var labels []string
+	d.tracker.AddLabelFunc = func(_, label string) error {
+		labels = append(labels, label)
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), releaseNoteJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "main.go") {
+		t.Fatalf("error = %v, want the unexpected file named", err)
+	}
+	if len(labels) != 0 {
+		t.Errorf("labels = %v, want none on failure", labels)
+	}
+}
+
+func TestExecuteReleaseNote_AlreadyNoted(t *testing.T) {
+	d := newTestDeps(t)
+	withReleaseNotes(t, d, models.ReleaseNotes{Field: "Release Note Text"}, `{"note": "x"}`)
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Labels: []string{"ai-release-noted"}}, nil
+	}
+	d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+		t.Error("ran an AI session for a ticket already noted")
+		return "", 0, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), releaseNoteJob("PROJ-1")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+}
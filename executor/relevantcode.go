@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"path"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoindex"
+)
+
+// appendRelevantCode lists the files of the ticket's repositories
+// that best match its summary and description at the end of the task
+// file, so that the AI starts from them instead of searching a large
+// codebase. Repositories confined to a subdirectory are indexed only
+// within it. In multi-repo workspaces paths are prefixed with the repo
+// directory. Index errors are logged and the repository skipped; the
+// listing is only a hint.
+func (p *Pipeline) appendRelevantCode(
+	logger *zap.Logger,
+	workItem models.WorkItem,
+	wsPath string,
+	settings *models.ProjectSettings,
+) {
+	if p.cfg.RepoIndex == nil {
+		return
+	}
+	multiRepo := settings.IsMultiRepo()
+	query := workItem.Summary + "\n" + workItem.Description
+	var entries []repoindex.Entry
+	for _, repo := range settings.Repos {
+		dir, key := wsPath, repo.CloneURL
+		if multiRepo {
+			dir = filepath.Join(wsPath, repo.Name)
+		}
+		if repo.Subdirectory != "" {
+			dir = filepath.Join(dir, filepath.FromSlash(repo.Subdirectory))
+			key += "#" + repo.Subdirectory
+		}
+		found, err := p.cfg.RepoIndex.Relevant(key, dir, query)
+		if err != nil {
+			logger.Warn("Failed to look up relevant code",
+				zap.String("repo", repo.Name), zap.Error(err))
+			continue
+		}
+		for _, e := range found {
+			e.Path = path.Join(repo.Subdirectory, e.Path)
+			if multiRepo {
+				e.Path = path.Join(repo.Name, e.Path)
+			}
+			entries = append(entries, e)
+		}
+	}
+	section := repoindex.Render(entries)
+	if section == "" {
+		return
+	}
+	if err := appendToTaskFile(wsPath, section); err != nil {
+		logger.Warn("Failed to add relevant code to task file", zap.Error(err))
+		return
+	}
+	logger.Info("Listed relevant code in task file", zap.Int("files", len(entries)))
+}
@@ -0,0 +1,78 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoindex"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestExecuteNewTicket_ListsRelevantCode(t *testing.T) {
+	d := newTestDeps(t)
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir string, _ string, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	var gotURL, gotDir string
+	index := &executortest.StubRepoIndex{
+		RelevantFunc: func(repoURL, dir, query string) ([]repoindex.Entry, error) {
+			gotURL, gotDir = repoURL, dir
+			return []repoindex.Entry{{Path: "auth/session.go", Symbols: []string{"RefreshSession"}}}, nil
+		},
+	}
+	var task string
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			data, _ := os.ReadFile(filepath.Join(req.Dir, taskfile.TaskFilePath))
+			task = string(data)
+			return agent.Result{}, nil
+		},
+	}
+
+	cfg := agentConfig(runner)
+	cfg.RepoIndex = index
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotURL != "https://github.com/org/repo.git" || gotDir != d.wsDir {
+		t.Errorf("Relevant(%q, %q), want repo clone URL and workspace", gotURL, gotDir)
+	}
+	for _, want := range []string{"# Task\n", "## Possibly Relevant Code", "- `auth/session.go`: RefreshSession"} {
+		if !strings.Contains(task, want) {
+			t.Errorf("task file missing %q:\n%s", want, task)
+		}
+	}
+}
+
+func TestExecuteNewTicket_RelevantCodeErrorIgnored(t *testing.T) {
+	d := newTestDeps(t)
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir string, _ string, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	index := &executortest.StubRepoIndex{
+		RelevantFunc: func(string, string, string) ([]repoindex.Entry, error) {
+			return nil, errors.New("walk failed")
+		},
+	}
+
+	cfg := agentConfig(&executortest.StubAgentRunner{})
+	cfg.RepoIndex = index
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("index error should not fail the job: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(d.wsDir, taskfile.TaskFilePath))
+	if strings.Contains(string(data), "Relevant Code") {
+		t.Errorf("task file lists relevant code despite index error:\n%s", data)
+	}
+}
@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// aiOutcome is the result of the last session run by
+// runAIWithRetries.
+type aiOutcome struct {
+	// ExitCode and ExecErr are the last session's exit code and exec
+	// error, as returned by runAISession.
+	ExitCode int
+	ExecErr  error
+
+	// TimedOut reports that the last session hit SessionTimeout.
+	TimedOut bool
+
+	// Session is the last session's metadata.
+	Session SessionOutput
+
+	// CostUSD is the total cost of all sessions.
+	CostUSD float64
+
+	// HasChanges reports whether the last session left changes in
+	// the workspace. It is false when ExecErr is set.
+	HasChanges bool
+
+	// Questions are the clarifying questions the last session asked
+	// instead of making changes. See [taskfile.NeedsInfoPath].
+	Questions []string
+}
+
+// runAIWithRetries runs the AI session for a new ticket and, when the
+// session fails or leaves no changes, reruns it up to
+// cfg.MaxAIRetries times. Before each retry a note explaining why the
+// previous attempt was rejected is appended to the task file, so that
+// the AI does not repeat it. Retries stop early on timeout, when the
+// AI asks clarifying questions, or once the ticket's cost cap is
+// reached.
+//
+// hasChanges reports whether the workspace has changes to commit. An
+// error is returned only for job cancellation or when hasChanges
+// fails; session failures are reported in the outcome.
+func (p *Pipeline) runAIWithRetries(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	ctr *container.Container,
+	wsPath string,
+	sp scriptParams,
+	maxTicketCost float64,
+	hasChanges func() (bool, error),
+) (aiOutcome, error) {
+	var out aiOutcome
+	for attempt := 0; ; attempt++ {
+		execCtx, span := p.startStage(ctx, spanAISession, job.TicketKey,
+			attribute.String("ai.provider", sp.Provider),
+			attribute.Int("ai.attempt", attempt))
+		var cancel context.CancelFunc = func() {}
+		if p.cfg.SessionTimeout > 0 {
+			execCtx, cancel = context.WithTimeout(execCtx, p.cfg.SessionTimeout)
+		}
+
+		// Questions left by an earlier session, e.g. the one whose
+		// answers resubmitted the ticket, must not be read again.
+		_ = os.Remove(filepath.Join(wsPath, taskfile.NeedsInfoPath))
+		out.ExitCode, out.ExecErr = p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
+		out.TimedOut = out.ExecErr != nil && execCtx.Err() != nil
+		cancel()
+		span.SetAttributes(attribute.Int("ai.exit_code", out.ExitCode))
+		endStage(span, out.ExecErr)
+		if out.ExecErr != nil {
+			if ctx.Err() != nil {
+				// Parent context cancelled (shutdown).
+				return out, fmt.Errorf("job cancelled: %w", ctx.Err())
+			}
+			logger.Warn("AI agent exec failed", zap.Error(out.ExecErr))
+		}
+
+		// Read session metadata (may be absent on abnormal exit).
+		out.Session = readSessionOutput(wsPath)
+		p.applyCostEstimate(&out.Session)
+
+		logger.Info("AI session completed",
+			zap.Int("exit_code", out.ExitCode),
+			zap.Float64("cost_usd", out.Session.CostUSD),
+			zap.Any("validation_passed", out.Session.ValidationPassed),
+			zap.String("summary", out.Session.Summary))
+		out.CostUSD += out.Session.CostUSD
+		p.recordTicketCost(logger, wsPath, maxTicketCost, out.Session.CostUSD)
+
+		out.HasChanges = false
+		if out.ExecErr == nil {
+			changed, err := hasChanges()
+			if err != nil {
+				return out, fmt.Errorf("check changes: %w", err)
+			}
+			out.HasChanges = changed
+		}
+		out.Questions = nil
+		if out.ExecErr == nil && !out.HasChanges {
+			out.Questions = readQuestions(wsPath)
+		}
+
+		if out.HasChanges || len(out.Questions) > 0 || out.TimedOut || attempt >= p.cfg.MaxAIRetries {
+			return out, nil
+		}
+		if p.checkTicketCostCap(logger, wsPath, maxTicketCost) {
+			logger.Info("Per-ticket cost cap reached, not retrying AI session")
+			return out, nil
+		}
+
+		reason := retryReason(out)
+		logger.Info("Retrying AI session",
+			zap.Int("retry", attempt+1),
+			zap.Int("max_retries", p.cfg.MaxAIRetries),
+			zap.String("reason", reason))
+		if err := appendRetryNote(wsPath, attempt+1, reason, out.Session.Summary); err != nil {
+			logger.Warn("Failed to add retry note to task file", zap.Error(err))
+		}
+	}
+}
+
+// noChangesError reports that a new-ticket AI session finished
+// without changing the repository. handleFailure posts the AI's
+// analysis and moves the ticket to its needs-human status, if one is
+// configured, instead of back to todo.
+type noChangesError struct {
+	msg      string
+	analysis string
+}
+
+func (e *noChangesError) Error() string { return e.msg }
+
+// retryReason describes why an AI session's result was rejected.
+func retryReason(out aiOutcome) string {
+	if out.ExecErr != nil {
+		return fmt.Sprintf("the session failed: %v", out.ExecErr)
+	}
+	return fmt.Sprintf("it produced no changes (exit code %d)", out.ExitCode)
+}
+
+// appendRetryNote appends a section to the task file telling the AI
+// that a previous attempt was rejected and why, quoting that
+// attempt's final message when there is one.
+func appendRetryNote(wsPath string, attempt int, reason, summary string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n## Previous Attempt %d\n\n", attempt)
+	fmt.Fprintf(&b, "A previous attempt at this task was rejected because %s.\n", reason)
+	if summary = strings.TrimSpace(summary); summary != "" {
+		b.WriteString("\nIts final message was:\n\n")
+		for line := range strings.SplitSeq(summary, "\n") {
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+	}
+	b.WriteString("\nThe task requires changes to the repository. Make them " +
+		"this time, or, if the task cannot be done, explain exactly what " +
+		"is missing or blocking.\n")
+	return appendToTaskFile(wsPath, b.String())
+}
+
+// appendToTaskFile appends text to the workspace's task file.
+func appendToTaskFile(wsPath, text string) error {
+	path := filepath.Join(wsPath, taskfile.TaskFilePath)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0) // #nosec G304 -- path is dir + constant
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,164 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func writeTaskFile(t *testing.T, wsDir string) {
+	t.Helper()
+	path := filepath.Join(wsDir, taskfile.TaskFilePath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# Task\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteNewTicket_RetriesWhenNoChanges(t *testing.T) {
+	d := newTestDeps(t)
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir string, _ string, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	var prompts []string
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			task, _ := os.ReadFile(filepath.Join(req.Dir, taskfile.TaskFilePath))
+			prompts = append(prompts, string(task))
+			return agent.Result{CostUSD: 0.5, Summary: "The bug is already fixed."}, nil
+		},
+	}
+	calls := 0
+	d.git.HasChangesFunc = func(dir, baseBranch string) (bool, error) {
+		calls++
+		return calls > 1, nil
+	}
+
+	cfg := agentConfig(runner)
+	cfg.MaxAIRetries = 2
+	result, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(prompts) != 2 {
+		t.Fatalf("sessions = %d, want 2", len(prompts))
+	}
+	if strings.Contains(prompts[0], "Previous Attempt") {
+		t.Errorf("first task file has a retry note:\n%s", prompts[0])
+	}
+	for _, want := range []string{"## Previous Attempt 1", "produced no changes", "> The bug is already fixed."} {
+		if !strings.Contains(prompts[1], want) {
+			t.Errorf("retry task file missing %q:\n%s", want, prompts[1])
+		}
+	}
+	if result.CostUSD != 1.0 {
+		t.Errorf("CostUSD = %v, want 1.0 (both sessions)", result.CostUSD)
+	}
+}
+
+func TestExecuteNewTicket_RetriesExhausted(t *testing.T) {
+	d := newTestDeps(t)
+	sessions := 0
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			sessions++
+			return agent.Result{}, nil
+		},
+	}
+	d.git.HasChangesFunc = func(dir, baseBranch string) (bool, error) {
+		return false, nil
+	}
+
+	cfg := agentConfig(runner)
+	cfg.MaxAIRetries = 2
+	_, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "no changes") {
+		t.Fatalf("err = %v, want no-changes error", err)
+	}
+	if sessions != 3 {
+		t.Errorf("sessions = %d, want 3 (1 + 2 retries)", sessions)
+	}
+}
+
+func TestExecuteNewTicket_NoRetryByDefault(t *testing.T) {
+	d := newTestDeps(t)
+	sessions := 0
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			sessions++
+			return agent.Result{}, nil
+		},
+	}
+	d.git.HasChangesFunc = func(dir, baseBranch string) (bool, error) {
+		return false, nil
+	}
+
+	_, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil {
+		t.Fatal("expected no-changes error")
+	}
+	if sessions != 1 {
+		t.Errorf("sessions = %d, want 1", sessions)
+	}
+}
+
+func TestExecuteNewTicket_NoChangesNeedsHuman(t *testing.T) {
+	d := newTestDeps(t)
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.NeedsHumanStatus = "Needs Human"
+		}
+		return settings, err
+	}
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(context.Context, agent.Request) (agent.Result, error) {
+			return agent.Result{Summary: "Cannot reproduce: the handler already validates input."}, nil
+		},
+	}
+	d.git.HasChangesFunc = func(dir, baseBranch string) (bool, error) {
+		return false, nil
+	}
+	pushed := false
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		pushed = true
+		return "abc123", nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+
+	_, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil {
+		t.Fatal("expected no-changes error")
+	}
+
+	if pushed {
+		t.Error("changes were committed despite an empty diff")
+	}
+	if last := transitions[len(transitions)-1]; last != "Needs Human" {
+		t.Errorf("transitions = %v, want last Needs Human", transitions)
+	}
+	if !strings.Contains(comment, "AI analysis:\nCannot reproduce: the handler already validates input.") {
+		t.Errorf("comment should quote the AI's analysis, got:\n%s", comment)
+	}
+}
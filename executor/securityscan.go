@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/models"
+)
+
+// changedFilesPath is the path, relative to the workspace root, where
+// the list of changed files is written for security scan commands.
+const changedFilesPath = ".ai-session/changed-files.txt"
+
+// maxScanOutputBytes caps the scanner output quoted in the ticket
+// comment for each failed scan.
+const maxScanOutputBytes = 4096
+
+// runSecurityScans runs the project's security scan commands in the
+// dev container on each repository with changes. Scans run from the
+// repository's directory with AI_BOT_BASE_BRANCH and
+// AI_BOT_CHANGED_FILES set (see [models.SecurityScan]). When any scan
+// exits non-zero, a [rejectedChangeError] quoting the scanners' output
+// is returned so that the change is not committed. A scan that cannot
+// be run is returned as an ordinary error.
+func (p *Pipeline) runSecurityScans(
+	ctx context.Context,
+	logger *zap.Logger,
+	ctr *container.Container,
+	wsPath string,
+	settings *models.ProjectSettings,
+) error {
+	var findings []string
+	for _, repo := range settings.Repos {
+		repoDir, ctrDir, where := wsPath, "/workspace", ""
+		if settings.IsMultiRepo() {
+			repoDir = filepath.Join(wsPath, repo.Name)
+			ctrDir = path.Join(ctrDir, repo.Name)
+			where = " in " + repo.Name
+		}
+
+		files, err := p.git.ChangedFiles(repoDir, repo.BaseBranch)
+		if err != nil {
+			return fmt.Errorf("list changed files for %s: %w", repo.Name, err)
+		}
+		if len(files) == 0 {
+			continue
+		}
+		listPath := filepath.Join(wsPath, changedFilesPath)
+		if err := os.WriteFile(listPath, []byte(strings.Join(files, "\n")+"\n"), 0o644); err != nil { // #nosec G306 -- read by the container user
+			return fmt.Errorf("write changed files list: %w", err)
+		}
+
+		for _, scan := range settings.SecurityScans {
+			logger.Info("Running security scan",
+				zap.String("scan", scan.Name),
+				zap.String("repo", repo.Name),
+				zap.Int("changed_files", len(files)))
+
+			cmd := []string{
+				"sh", "-c",
+				fmt.Sprintf("cd %q && export AI_BOT_BASE_BRANCH=%q AI_BOT_CHANGED_FILES=%q && %s",
+					ctrDir, repo.BaseBranch, path.Join("/workspace", changedFilesPath), scan.Command),
+			}
+			output, exitCode, err := p.containers.Exec(ctx, ctr, cmd)
+			if err != nil {
+				return fmt.Errorf("security scan %s for %s: %w", scan.Name, repo.Name, err)
+			}
+			// 126 and 127 are the shell's "not executable" and
+			// "not found": a misconfigured scanner, not a finding.
+			if exitCode == 126 || exitCode == 127 {
+				return fmt.Errorf("security scan %s for %s could not run (exit code %d): %s",
+					scan.Name, repo.Name, exitCode, truncateScanOutput(output))
+			}
+			if exitCode == 0 {
+				continue
+			}
+
+			logger.Warn("Security scan reported findings",
+				zap.String("scan", scan.Name),
+				zap.String("repo", repo.Name),
+				zap.Int("exit_code", exitCode))
+			findings = append(findings, fmt.Sprintf("%s%s exited with code %d:\n%s",
+				scan.Name, where, exitCode, truncateScanOutput(output)))
+		}
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+	return &rejectedChangeError{
+		msg:     "security scan found issues in the AI change",
+		reason:  "Security scan blocked the change",
+		details: strings.Join(findings, "\n\n"),
+	}
+}
+
+// truncateScanOutput trims scanner output to maxScanOutputBytes,
+// keeping the beginning, where scanners usually list findings.
+func truncateScanOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) <= maxScanOutputBytes {
+		return output
+	}
+	return strings.ToValidUTF8(output[:maxScanOutputBytes], "") + "\n... (output truncated)"
+}
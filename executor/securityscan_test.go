@@ -0,0 +1,140 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+)
+
+// withSecurityScans adds scans to the project settings.
+func withSecurityScans(d *testDeps, scans ...models.SecurityScan) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.SecurityScans = scans
+		}
+		return settings, err
+	}
+}
+
+func TestExecuteNewTicket_SecurityScanPasses(t *testing.T) {
+	d := newTestDeps(t)
+	withSecurityScans(d, models.SecurityScan{Name: "gitleaks", Command: "gitleaks dir --redact ."})
+	if err := os.MkdirAll(filepath.Join(d.wsDir, ".ai-session"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	d.git.ChangedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"auth/token.go", "auth/token_test.go"}, nil
+	}
+	var script string
+	d.containers.ExecFunc = func(_ context.Context, _ *container.Container, cmd []string) (string, int, error) {
+		script = cmd[len(cmd)-1]
+		return "", 0, nil
+	}
+
+	_, err := d.pipelineWithConfig(t, agentConfig(&executortest.StubAgentRunner{})).
+		Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		`cd "/workspace"`,
+		`AI_BOT_BASE_BRANCH="main"`,
+		`AI_BOT_CHANGED_FILES="/workspace/.ai-session/changed-files.txt"`,
+		"&& gitleaks dir --redact .",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("scan command missing %q: %s", want, script)
+		}
+	}
+	list, _ := os.ReadFile(filepath.Join(d.wsDir, ".ai-session", "changed-files.txt"))
+	if string(list) != "auth/token.go\nauth/token_test.go\n" {
+		t.Errorf("changed files list = %q", list)
+	}
+}
+
+func TestExecuteNewTicket_SecurityScanBlocksPR(t *testing.T) {
+	d := newTestDeps(t)
+	withSecurityScans(d,
+		models.SecurityScan{Name: "gitleaks", Command: "gitleaks dir --redact ."},
+		models.SecurityScan{Name: "gosec", Command: "gosec -severity high ./..."})
+	if err := os.MkdirAll(filepath.Join(d.wsDir, ".ai-session"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	d.git.ChangedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"config.go"}, nil
+	}
+	d.containers.ExecFunc = func(_ context.Context, _ *container.Container, cmd []string) (string, int, error) {
+		if strings.Contains(cmd[len(cmd)-1], "gitleaks") {
+			return "Finding: aws-access-token REDACTED\nFile: config.go\n", 1, nil
+		}
+		return "", 0, nil
+	}
+	committed := false
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		committed = true
+		return "abc123", nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+
+	_, err := d.pipelineWithConfig(t, agentConfig(&executortest.StubAgentRunner{})).
+		Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "security scan") {
+		t.Fatalf("err = %v, want security scan error", err)
+	}
+
+	if committed {
+		t.Error("change with scan findings was committed")
+	}
+	for _, want := range []string{
+		"Security scan blocked the change",
+		"gitleaks exited with code 1:\nFinding: aws-access-token REDACTED",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("comment missing %q, got:\n%s", want, comment)
+		}
+	}
+	if strings.Contains(comment, "gosec") {
+		t.Errorf("comment should only report failing scans, got:\n%s", comment)
+	}
+}
+
+func TestExecuteNewTicket_SecurityScanNotFound(t *testing.T) {
+	d := newTestDeps(t)
+	withSecurityScans(d, models.SecurityScan{Name: "semgrep", Command: "semgrep scan --error"})
+	if err := os.MkdirAll(filepath.Join(d.wsDir, ".ai-session"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	d.git.ChangedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"main.go"}, nil
+	}
+	d.containers.ExecFunc = func(_ context.Context, _ *container.Container, cmd []string) (string, int, error) {
+		return "sh: semgrep: not found", 127, nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+
+	_, err := d.pipelineWithConfig(t, agentConfig(&executortest.StubAgentRunner{})).
+		Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "could not run") {
+		t.Fatalf("err = %v, want could-not-run error", err)
+	}
+	if strings.Contains(comment, "blocked the change") {
+		t.Errorf("a missing scanner should not be reported as findings:\n%s", comment)
+	}
+}
@@ -0,0 +1,170 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// Verdicts a self-review session may report.
+const (
+	verdictApprove = "approve"
+	verdictAmend   = "amend"
+	verdictAbort   = "abort"
+)
+
+// selfReview is the verdict of a self-review session, read from
+// taskfile.SelfReviewPath. Verdict is empty when the session failed
+// or did not report a valid verdict.
+type selfReview struct {
+	Verdict string `json:"verdict"`
+	Summary string `json:"summary"`
+}
+
+// rejectedChangeError reports that the AI changed the code but a
+// check run before committing rejected the change. handleFailure
+// escalates the ticket like a no-changes failure and posts reason and
+// details instead of the generic failure comment.
+type rejectedChangeError struct {
+	msg     string
+	reason  string
+	details string
+}
+
+func (e *rejectedChangeError) Error() string { return e.msg }
+
+// runSelfReview runs a second AI session that reviews the changes
+// left by the new-ticket session. writeTask writes the review task
+// file; hasChanges is rechecked after an amending review. The
+// session's cost is added to out, and out.HasChanges is updated.
+//
+// A review that fails or reports no valid verdict does not block the
+// change; it is returned with an empty verdict so that the PR body
+// notes the change went unreviewed. An abort verdict is returned as a
+// [rejectedChangeError]. Other errors are returned only for job
+// cancellation, or when writeTask or hasChanges fails.
+func (p *Pipeline) runSelfReview(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	ctr *container.Container,
+	wsPath string,
+	sp scriptParams,
+	maxTicketCost float64,
+	out *aiOutcome,
+	writeTask func() error,
+	hasChanges func() (bool, error),
+) (*selfReview, error) {
+	if p.checkTicketCostCap(logger, wsPath, maxTicketCost) {
+		logger.Info("Per-ticket cost cap reached, skipping self-review")
+		return &selfReview{}, nil
+	}
+	if err := writeTask(); err != nil {
+		return nil, fmt.Errorf("write self-review task file: %w", err)
+	}
+	reviewPath := filepath.Join(wsPath, taskfile.SelfReviewPath)
+	_ = os.Remove(reviewPath)
+
+	execCtx, span := p.startStage(ctx, spanAISession, job.TicketKey,
+		attribute.String("ai.provider", sp.Provider),
+		attribute.Bool("ai.self_review", true))
+	var cancel context.CancelFunc = func() {}
+	if p.cfg.SessionTimeout > 0 {
+		execCtx, cancel = context.WithTimeout(execCtx, p.cfg.SessionTimeout)
+	}
+	exitCode, execErr := p.runAISession(execCtx, logger, job, ctr, wsPath, sp)
+	cancel()
+	span.SetAttributes(attribute.Int("ai.exit_code", exitCode))
+	endStage(span, execErr)
+	if execErr != nil && ctx.Err() != nil {
+		return nil, fmt.Errorf("job cancelled: %w", ctx.Err())
+	}
+
+	session := readSessionOutput(wsPath)
+	p.applyCostEstimate(&session)
+	out.CostUSD += session.CostUSD
+	p.recordTicketCost(logger, wsPath, maxTicketCost, session.CostUSD)
+
+	review := readSelfReview(reviewPath)
+	if execErr != nil {
+		logger.Warn("AI self-review session failed", zap.Error(execErr))
+		review = &selfReview{}
+	}
+	logger.Info("AI self-review completed",
+		zap.Int("exit_code", exitCode),
+		zap.Float64("cost_usd", session.CostUSD),
+		zap.String("verdict", review.Verdict))
+
+	// A failed or amending session may have modified the workspace.
+	if review.Verdict != verdictApprove {
+		changed, err := hasChanges()
+		if err != nil {
+			return nil, fmt.Errorf("check changes: %w", err)
+		}
+		out.HasChanges = changed
+	}
+
+	if review.Verdict == verdictAbort {
+		return review, &rejectedChangeError{
+			msg:     "AI self-review aborted the change",
+			reason:  "AI self-review rejected the change",
+			details: review.Summary,
+		}
+	}
+	return review, nil
+}
+
+// readSelfReview reads the self-review verdict at path. A missing or
+// malformed file, or an unknown verdict, yields an empty verdict.
+func readSelfReview(path string) *selfReview {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is dir + constant
+	if err != nil {
+		return &selfReview{}
+	}
+	var review selfReview
+	if err := json.Unmarshal(data, &review); err != nil {
+		return &selfReview{}
+	}
+	review.Verdict = strings.ToLower(strings.TrimSpace(review.Verdict))
+	switch review.Verdict {
+	case verdictApprove, verdictAmend, verdictAbort:
+		return &review
+	}
+	return &selfReview{}
+}
+
+// withSelfReview appends the self-review verdict to a PR body. The
+// review summary is left out when redact is set (security-level
+// tickets), since it may describe the vulnerability. A nil review
+// leaves the body unchanged.
+func withSelfReview(body string, review *selfReview, redact bool) string {
+	if review == nil {
+		return body
+	}
+	var b strings.Builder
+	b.WriteString(body)
+	b.WriteString("\n\n## AI Self-Review\n\n")
+	switch review.Verdict {
+	case verdictApprove:
+		b.WriteString("Verdict: **approved**")
+	case verdictAmend:
+		b.WriteString("Verdict: **amended** (the review fixed problems before committing)")
+	default:
+		b.WriteString("The self-review did not complete; this change was not reviewed.")
+		return b.String()
+	}
+	if summary := strings.TrimSpace(review.Summary); summary != "" && !redact {
+		fmt.Fprintf(&b, "\n\n%s", summary)
+	}
+	return b.String()
+}
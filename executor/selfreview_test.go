@@ -0,0 +1,140 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// enableSelfReview enables self-review in the project settings.
+func enableSelfReview(d *testDeps) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.SelfReview = true
+		}
+		return settings, err
+	}
+}
+
+// reviewRunner returns an agent runner whose second session writes
+// verdict to the self-review file. An empty verdict writes nothing.
+func reviewRunner(t *testing.T, sessions *int, verdict string) *executortest.StubAgentRunner {
+	t.Helper()
+	return &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			*sessions++
+			if *sessions == 2 && verdict != "" {
+				path := filepath.Join(req.Dir, taskfile.SelfReviewPath)
+				if err := os.WriteFile(path, []byte(verdict), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return agent.Result{CostUSD: 0.25}, nil
+		},
+	}
+}
+
+func TestExecuteNewTicket_SelfReviewApproves(t *testing.T) {
+	d := newTestDeps(t)
+	enableSelfReview(d)
+	reviewTask := false
+	d.taskWriter.WriteSelfReviewTaskFunc = func(_ models.WorkItem, dir, _ string) error {
+		reviewTask = true
+		return os.MkdirAll(filepath.Join(dir, ".ai-session"), 0o750)
+	}
+	var body string
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		body = params.Body
+		return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+	}
+	sessions := 0
+	runner := reviewRunner(t, &sessions, `{"verdict": "approve", "summary": "Meets the acceptance criteria."}`)
+
+	result, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reviewTask || sessions != 2 {
+		t.Fatalf("review task written = %v, sessions = %d, want a review session", reviewTask, sessions)
+	}
+	if !strings.Contains(body, "## AI Self-Review\n\nVerdict: **approved**\n\nMeets the acceptance criteria.") {
+		t.Errorf("PR body missing self-review verdict:\n%s", body)
+	}
+	if result.CostUSD != 0.5 {
+		t.Errorf("CostUSD = %v, want 0.5 (both sessions)", result.CostUSD)
+	}
+}
+
+func TestExecuteNewTicket_SelfReviewAborts(t *testing.T) {
+	d := newTestDeps(t)
+	enableSelfReview(d)
+	d.taskWriter.WriteSelfReviewTaskFunc = func(_ models.WorkItem, dir, _ string) error {
+		return os.MkdirAll(filepath.Join(dir, ".ai-session"), 0o750)
+	}
+	committed := false
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		committed = true
+		return "abc123", nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(key, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+	sessions := 0
+	runner := reviewRunner(t, &sessions, `{"verdict": "Abort", "summary": "The change disables authentication."}`)
+
+	_, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "self-review aborted") {
+		t.Fatalf("err = %v, want self-review abort", err)
+	}
+
+	if committed {
+		t.Error("aborted change was committed")
+	}
+	if last := transitions[len(transitions)-1]; last != "To Do" {
+		t.Errorf("transitions = %v, want last To Do", transitions)
+	}
+	if !strings.Contains(comment, "AI self-review rejected the change") ||
+		!strings.Contains(comment, "The change disables authentication.") {
+		t.Errorf("comment should explain the rejection, got:\n%s", comment)
+	}
+}
+
+func TestExecuteNewTicket_SelfReviewWithoutVerdict(t *testing.T) {
+	d := newTestDeps(t)
+	enableSelfReview(d)
+	d.taskWriter.WriteSelfReviewTaskFunc = func(_ models.WorkItem, dir, _ string) error {
+		return os.MkdirAll(filepath.Join(dir, ".ai-session"), 0o750)
+	}
+	var body string
+	d.git.CreatePRFunc = func(params models.PRParams) (*models.PR, error) {
+		body = params.Body
+		return &models.PR{Number: 1, URL: "https://github.com/org/repo/pull/1"}, nil
+	}
+	sessions := 0
+	runner := reviewRunner(t, &sessions, "")
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(body, "this change was not reviewed") {
+		t.Errorf("PR body should note the missing review:\n%s", body)
+	}
+}
@@ -40,6 +40,10 @@ type SessionOutput struct {
 
 	// Summary is a brief description of what the AI did.
 	Summary string `json:"summary"`
+
+	// Commands are the shell commands the AI ran, in order. Recorded
+	// for in-process agent sessions and Claude CLI sessions.
+	Commands []string `json:"commands,omitempty"`
 }
 
 // PRDescription holds the AI-generated PR title and body parsed from
@@ -202,13 +206,25 @@ func isPRMetadataLine(line string) bool {
 type CommentResponse struct {
 	CommentID int64  `json:"comment_id"`
 	Response  string `json:"response"`
+
+	// Question is set instead of making a speculative change when
+	// the comment is ambiguous. The bot posts it as the reply and
+	// waits for the reviewer's answer before acting on the comment.
+	Question string `json:"question,omitempty"`
+
+	// NotAddressed is set when the AI left the comment unaddressed,
+	// with Response giving the reason. The comment gets no reply, so
+	// it is retried in the next feedback round (see
+	// [Pipeline.reportPartialFeedback]).
+	NotAddressed bool `json:"not_addressed,omitempty"`
 }
 
-// readCommentResponses reads the AI-generated per-comment response
-// summaries from the workspace. Returns nil if the file does not exist
-// or cannot be parsed. The bot uses these to post descriptive replies
+// readCommentResponses reads the AI-generated per-comment responses
+// from the workspace, keyed by comment ID. Returns nil if the file
+// does not exist, cannot be parsed, or has no usable entries. The bot
+// uses these to post descriptive replies (or follow-up questions)
 // instead of generic "Addressed in <commit>" messages.
-func readCommentResponses(dir string) map[int64]string {
+func readCommentResponses(dir string) map[int64]CommentResponse {
 	path := filepath.Join(dir, taskfile.CommentResponsesPath)
 
 	data, err := os.ReadFile(path) // #nosec G304 -- path is dir + constant
@@ -225,10 +241,10 @@ func readCommentResponses(dir string) map[int64]string {
 		return nil
 	}
 
-	m := make(map[int64]string, len(responses))
+	m := make(map[int64]CommentResponse, len(responses))
 	for _, r := range responses {
-		if r.CommentID != 0 && r.Response != "" {
-			m[r.CommentID] = r.Response
+		if r.CommentID != 0 && (r.Response != "" || r.Question != "" || r.NotAddressed) {
+			m[r.CommentID] = r
 		}
 	}
 
@@ -274,6 +290,7 @@ func enrichFromCLIOutput(output *SessionOutput, dir string) {
 	// element. Without --verbose it's a single JSON object.
 	if cost, ok := parseClaudeCost(data); ok {
 		output.CostUSD = cost
+		output.Commands = parseClaudeCommands(data)
 		return
 	}
 
@@ -313,6 +330,43 @@ func parseClaudeCost(data []byte) (float64, bool) {
 	return 0, false
 }
 
+// claudeEvent is the part of a Claude CLI --verbose conversation event
+// that carries the assistant's tool calls.
+type claudeEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Content []struct {
+			Type  string `json:"type"`
+			Name  string `json:"name"`
+			Input struct {
+				Command string `json:"command"`
+			} `json:"input"`
+		} `json:"content"`
+	} `json:"message"`
+}
+
+// parseClaudeCommands extracts the commands of the Bash tool calls in
+// Claude CLI --verbose output. Returns nil for output without the
+// conversation.
+func parseClaudeCommands(data []byte) []string {
+	var events []claudeEvent
+	if json.Unmarshal(data, &events) != nil {
+		return nil
+	}
+	var commands []string
+	for _, e := range events {
+		if e.Type != "assistant" {
+			continue
+		}
+		for _, c := range e.Message.Content {
+			if c.Type == "tool_use" && c.Name == "Bash" && c.Input.Command != "" {
+				commands = append(commands, c.Input.Command)
+			}
+		}
+	}
+	return commands
+}
+
 type geminiCLIOutput struct {
 	Stats struct {
 		Models map[string]struct {
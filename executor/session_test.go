@@ -3,6 +3,7 @@ package executor
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"jira-ai-issue-solver/taskfile"
@@ -23,11 +24,11 @@ func TestReadCommentResponses_ValidJSON(t *testing.T) {
 	if len(m) != 2 {
 		t.Fatalf("len = %d, want 2", len(m))
 	}
-	if m[123] != "Switched to Optional pattern." {
-		t.Errorf("response for 123 = %q", m[123])
+	if m[123].Response != "Switched to Optional pattern." {
+		t.Errorf("response for 123 = %q", m[123].Response)
 	}
-	if m[456] != "Kept fallback path for compat." {
-		t.Errorf("response for 456 = %q", m[456])
+	if m[456].Response != "Kept fallback path for compat." {
+		t.Errorf("response for 456 = %q", m[456].Response)
 	}
 }
 
@@ -79,8 +80,8 @@ func TestReadCommentResponses_SkipsZeroIDAndEmptyResponse(t *testing.T) {
 	if len(m) != 1 {
 		t.Fatalf("len = %d, want 1 (only valid entry)", len(m))
 	}
-	if m[456] != "valid response" {
-		t.Errorf("response for 456 = %q", m[456])
+	if m[456].Response != "valid response" {
+		t.Errorf("response for 456 = %q", m[456].Response)
 	}
 }
 
@@ -142,6 +143,28 @@ func TestEnrichFromCLIOutput_ClaudeVerboseArray(t *testing.T) {
 	}
 }
 
+func TestEnrichFromCLIOutput_ClaudeCommands(t *testing.T) {
+	dir := t.TempDir()
+	writeSessionFile(t, dir, "cli-output.json", `[
+		{"type": "assistant", "message": {"content": [
+			{"type": "text", "text": "Running the tests"},
+			{"type": "tool_use", "name": "Bash", "input": {"command": "go test ./..."}}
+		]}},
+		{"type": "assistant", "message": {"content": [
+			{"type": "tool_use", "name": "Read", "input": {"file_path": "main.go"}},
+			{"type": "tool_use", "name": "Bash", "input": {"command": "make lint"}}
+		]}},
+		{"type": "result", "total_cost_usd": 1.25}
+	]`)
+
+	var output SessionOutput
+	enrichFromCLIOutput(&output, dir)
+
+	if want := []string{"go test ./...", "make lint"}; !slices.Equal(output.Commands, want) {
+		t.Errorf("Commands = %q, want %q", output.Commands, want)
+	}
+}
+
 func TestEnrichFromCLIOutput_Gemini(t *testing.T) {
 	dir := t.TempDir()
 	writeSessionFile(t, dir, "cli-output.json", `{
@@ -0,0 +1,205 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/models"
+)
+
+const (
+	// setupCompleteMarker marks a setup cache entry whose command
+	// succeeded. Entries without it are refilled.
+	setupCompleteMarker = ".complete"
+
+	// setupLockTimeout is how long a job may hold a setup cache
+	// entry's lock before another job assumes it crashed and takes
+	// the lock over.
+	setupLockTimeout = time.Hour
+)
+
+// repoSetup is a repository's dependency setup for one container.
+type repoSetup struct {
+	repo    string // repository name, for logs
+	command string
+	ctrDir  string // repository directory inside the container
+	mounts  []container.Mount
+
+	entry  string // host cache entry; empty when not cached
+	lock   string // lock file held while the command fills entry
+	cached bool   // entry is complete; the command is skipped
+}
+
+// planSetups prepares the setup of each repository with a setup
+// command: the cache directories to mount into the container and
+// whether the command still has to run. Cache errors are logged and
+// the affected setup runs uncached.
+func (p *Pipeline) planSetups(logger *zap.Logger, wsPath, image string, settings *models.ProjectSettings) []*repoSetup {
+	var setups []*repoSetup
+	for _, repo := range settings.Repos {
+		if repo.Setup.Command == "" {
+			continue
+		}
+		repoDir, ctrDir := wsPath, "/workspace"
+		if settings.IsMultiRepo() {
+			repoDir = filepath.Join(wsPath, repo.Name)
+			ctrDir = path.Join(ctrDir, repo.Name)
+		}
+		s := &repoSetup{repo: repo.Name, command: repo.Setup.Command, ctrDir: ctrDir}
+		setups = append(setups, s)
+		if p.cfg.SetupCacheDir == "" || len(repo.Setup.CacheDirs) == 0 {
+			continue
+		}
+		if err := p.claimSetupCache(s, repo, repoDir, image); err != nil {
+			logger.Warn("Dependency cache unavailable, running setup without it",
+				zap.String("repo", repo.Name), zap.Error(err))
+		}
+	}
+	return setups
+}
+
+// claimSetupCache selects the cache entry for the repository's lock
+// files and mounts it. An incomplete entry is locked so that this job
+// fills it; one another job is filling is left alone and the setup
+// runs uncached.
+func (p *Pipeline) claimSetupCache(s *repoSetup, repo models.RepoSettings, repoDir, image string) error {
+	key, err := setupCacheKey(repoDir, image, repo.Setup)
+	if err != nil {
+		return err
+	}
+	entry := filepath.Join(p.cfg.SetupCacheDir, repo.Owner+"_"+repo.Repo+"-"+key)
+
+	if _, err := os.Stat(filepath.Join(entry, setupCompleteMarker)); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		lock := entry + ".lock"
+		acquired, err := acquireSetupLock(lock)
+		if err != nil || !acquired {
+			return err
+		}
+		s.lock = lock
+	} else {
+		s.cached = true
+	}
+
+	for i, dir := range repo.Setup.CacheDirs {
+		source := filepath.Join(entry, strconv.Itoa(i))
+		if err := os.MkdirAll(source, 0o750); err != nil {
+			s.releaseLock()
+			s.cached = false
+			return fmt.Errorf("create cache directory: %w", err)
+		}
+		target := path.Clean(dir)
+		if !path.IsAbs(target) {
+			target = path.Join(s.ctrDir, target)
+		}
+		s.mounts = append(s.mounts, container.Mount{Source: source, Target: target})
+	}
+	s.entry = entry
+	return nil
+}
+
+// runSetups runs the setup commands that are not cached. A failed
+// command is logged and leaves its cache entry incomplete; the AI
+// session proceeds without the dependencies.
+func (p *Pipeline) runSetups(ctx context.Context, logger *zap.Logger, ctr *container.Container, setups []*repoSetup) {
+	for _, s := range setups {
+		logger := logger.With(zap.String("repo", s.repo))
+		if s.cached {
+			logger.Info("Using cached dependencies", zap.String("cache", s.entry))
+			continue
+		}
+		logger.Info("Running setup command", zap.String("command", s.command))
+		start := time.Now()
+		output, exitCode, err := p.containers.Exec(ctx, ctr, []string{
+			"sh", "-c", fmt.Sprintf("cd %q && %s", s.ctrDir, s.command),
+		})
+		switch {
+		case err != nil:
+			logger.Warn("Setup command failed to run", zap.Error(err))
+		case exitCode != 0:
+			logger.Warn("Setup command failed",
+				zap.Int("exit_code", exitCode),
+				zap.String("output", truncateScanOutput(output)))
+		default:
+			logger.Info("Setup command finished", zap.Duration("duration", time.Since(start)))
+			if s.lock != "" {
+				if err := os.WriteFile(filepath.Join(s.entry, setupCompleteMarker), nil, 0o600); err != nil {
+					logger.Warn("Failed to mark dependency cache complete", zap.Error(err))
+				}
+			}
+		}
+		s.releaseLock()
+	}
+}
+
+// releaseSetupLocks releases the cache locks held by setups, for when
+// their commands will not run.
+func releaseSetupLocks(setups []*repoSetup) {
+	for _, s := range setups {
+		s.releaseLock()
+	}
+}
+
+func (s *repoSetup) releaseLock() {
+	if s.lock != "" {
+		_ = os.Remove(s.lock)
+		s.lock = ""
+	}
+}
+
+// acquireSetupLock creates the lock file, taking over a lock older
+// than setupLockTimeout. It reports false if another job holds it.
+func acquireSetupLock(lock string) (bool, error) {
+	if err := os.MkdirAll(filepath.Dir(lock), 0o750); err != nil {
+		return false, fmt.Errorf("create setup cache directory: %w", err)
+	}
+	for range 2 {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) // #nosec G304 -- path built from config and a hash
+		if err == nil {
+			return true, f.Close()
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return false, fmt.Errorf("lock dependency cache: %w", err)
+		}
+		info, err := os.Stat(lock)
+		if err != nil || time.Since(info.ModTime()) < setupLockTimeout {
+			return false, nil
+		}
+		_ = os.Remove(lock)
+	}
+	return false, nil
+}
+
+// setupCacheKey identifies a setup's result: the container image, the
+// command, the cache directories, and the contents of the lock files.
+func setupCacheKey(repoDir, image string, setup models.SetupConfig) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q %q %q\n", image, setup.Command, setup.CacheDirs)
+	for _, file := range setup.LockFiles {
+		data, err := os.ReadFile(filepath.Join(repoDir, filepath.FromSlash(file))) // #nosec G304 -- lock file configured by the operator
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Fprintf(h, "%q missing\n", file)
+		case err != nil:
+			return "", fmt.Errorf("read lock file %s: %w", file, err)
+		default:
+			fmt.Fprintf(h, "%q %d\n", file, len(data))
+			h.Write(data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
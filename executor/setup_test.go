@@ -0,0 +1,157 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+)
+
+// withSetup gives the project's repository a setup command.
+func withSetup(d *testDeps, setup models.SetupConfig) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.Repos[0].Setup = setup
+		}
+		return settings, err
+	}
+}
+
+// setupRecorder records the setup commands run and the mounts of each
+// started container. exitCode is returned for setup commands.
+type setupRecorder struct {
+	commands []string
+	mounts   [][]container.Mount
+	exitCode int
+}
+
+func (r *setupRecorder) install(d *testDeps) {
+	start := d.containers.StartFunc
+	d.containers.StartFunc = func(ctx context.Context, cfg *container.Config, wsDir, ticketKey string, env map[string]string) (*container.Container, error) {
+		r.mounts = append(r.mounts, cfg.ExtraMounts)
+		return start(ctx, cfg, wsDir, ticketKey, env)
+	}
+	exec := d.containers.ExecFunc
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		if len(cmd) == 3 && strings.HasSuffix(cmd[2], "npm ci") {
+			r.commands = append(r.commands, cmd[2])
+			return "", r.exitCode, nil
+		}
+		if exec == nil {
+			return "", 0, nil
+		}
+		return exec(ctx, ctr, cmd)
+	}
+}
+
+func setupPipelineConfig(cacheDir string) executor.Config {
+	return executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": "test-key"},
+		MaxRetries:      3,
+		SetupCacheDir:   cacheDir,
+	}
+}
+
+var npmSetup = models.SetupConfig{
+	Command:   "npm ci",
+	LockFiles: []string{"package-lock.json"},
+	CacheDirs: []string{"node_modules", "/root/.npm"},
+}
+
+func TestExecuteNewTicket_SetupWithoutCacheRunsEveryTime(t *testing.T) {
+	d := newTestDeps(t)
+	withSetup(d, npmSetup)
+	var rec setupRecorder
+	rec.install(d)
+
+	p := d.pipelineWithConfig(t, setupPipelineConfig(""))
+	for range 2 {
+		if _, err := p.Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(rec.commands) != 2 || rec.commands[0] != `cd "/workspace" && npm ci` {
+		t.Errorf("setup commands = %q, want npm ci in /workspace twice", rec.commands)
+	}
+	if len(rec.mounts[0]) != 0 {
+		t.Errorf("mounts = %+v, want none without a cache directory", rec.mounts[0])
+	}
+}
+
+func TestExecuteNewTicket_SetupCachedByLockFile(t *testing.T) {
+	d := newTestDeps(t)
+	withSetup(d, npmSetup)
+	var rec setupRecorder
+	rec.install(d)
+	cacheDir := t.TempDir()
+	lockFile := filepath.Join(d.wsDir, "package-lock.json")
+	if err := os.WriteFile(lockFile, []byte(`{"v":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := d.pipelineWithConfig(t, setupPipelineConfig(cacheDir))
+	run := func() {
+		t.Helper()
+		if _, err := p.Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	run()
+	run()
+	if len(rec.commands) != 1 {
+		t.Fatalf("setup ran %d times, want once and then cached", len(rec.commands))
+	}
+	for _, mounts := range rec.mounts {
+		if len(mounts) != 2 || mounts[0].Target != "/workspace/node_modules" || mounts[1].Target != "/root/.npm" {
+			t.Fatalf("mounts = %+v, want the cache directories", mounts)
+		}
+		if !strings.HasPrefix(mounts[0].Source, cacheDir) || mounts[0].Source != rec.mounts[0][0].Source {
+			t.Errorf("mount source = %q, want the same entry under %s", mounts[0].Source, cacheDir)
+		}
+	}
+
+	if err := os.WriteFile(lockFile, []byte(`{"v":2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	run()
+	if len(rec.commands) != 2 {
+		t.Errorf("setup ran %d times, want a rerun after the lock file changed", len(rec.commands))
+	}
+	if rec.mounts[2][0].Source == rec.mounts[0][0].Source {
+		t.Error("changed lock file reused the old cache entry")
+	}
+}
+
+func TestExecuteNewTicket_FailedSetupIsNotCached(t *testing.T) {
+	d := newTestDeps(t)
+	withSetup(d, npmSetup)
+	rec := setupRecorder{exitCode: 1}
+	rec.install(d)
+	cacheDir := t.TempDir()
+
+	p := d.pipelineWithConfig(t, setupPipelineConfig(cacheDir))
+	for range 2 {
+		if _, err := p.Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+			t.Fatalf("failed setup should not fail the job: %v", err)
+		}
+	}
+
+	if len(rec.commands) != 2 {
+		t.Errorf("setup ran %d times, want a rerun after failing", len(rec.commands))
+	}
+	locks, _ := filepath.Glob(filepath.Join(cacheDir, "*.lock"))
+	if len(locks) != 0 {
+		t.Errorf("locks left behind: %v", locks)
+	}
+}
@@ -0,0 +1,152 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoconfig"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// readSpikeWriteUp reads the AI's spike write-up from the workspace.
+// Returns "" if the file is missing or empty.
+func readSpikeWriteUp(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, taskfile.SpikePath)) // #nosec G304 -- path is dir + constant
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// executeSpike has the AI explore a ticket labeled for a spike in a
+// time-boxed session and posts its write-up to the ticket instead of
+// solving it. Like a change preview, the ticket is then parked in
+// review with the spike label replaced by the done label; moving it
+// back to todo has the bot solve it. No code is changed.
+//
+// A session stopped by the time box still posts whatever write-up the
+// AI had saved. Failures revert the ticket like a new-ticket failure,
+// keeping the spike label so that the spike is retried.
+func (p *Pipeline) executeSpike(
+	ctx context.Context,
+	logger *zap.Logger,
+	job *jobmanager.Job,
+	workItem *models.WorkItem,
+	settings *models.ProjectSettings,
+) (result jobmanager.JobResult, retErr error) {
+	spike := settings.Spike
+	logger.Info("Spike requested, exploring instead of solving",
+		zap.String("label", spike.Label))
+
+	if settings.IsMultiRepo() {
+		return result, errors.New("spikes are not supported for multi-repo workspaces")
+	}
+	if err := p.checkProjectBudget(logger, job.TicketKey, settings); err != nil {
+		return result, err
+	}
+
+	// --- Step 1: Transition to in-progress ---
+	if err := p.tracker.TransitionStatus(job.TicketKey, settings.InProgressStatus); err != nil {
+		return result, fmt.Errorf("transition to in-progress: %w", err)
+	}
+	defer func() {
+		retErr = wrapTimeout(ctx, retErr)
+		if retErr != nil {
+			p.handleFailure(logger, job, settings, retErr)
+		}
+	}()
+
+	// --- Step 2: Prepare workspace ---
+	// As for triage, a workspace created only for the spike is
+	// removed afterwards, so that solving the ticket later starts
+	// from a fresh clone.
+	repo := settings.Repos[0]
+	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, repo.CloneURL, repo.SparsePaths)
+	if err != nil {
+		return result, fmt.Errorf("prepare workspace: %w", err)
+	}
+	if !reused {
+		defer func() {
+			if err := p.workspaces.Cleanup(job.TicketKey); err != nil {
+				logger.Warn("Failed to delete spike workspace", zap.Error(err))
+			}
+		}()
+	}
+	repoCfg, err := repoconfig.Load(wsPath)
+	if err != nil {
+		logger.Warn("Failed to load repo config, using defaults", zap.Error(err))
+		repoCfg = repoconfig.Default()
+	}
+
+	// --- Step 3: Write task files ---
+	timeBox := spike.Timeout()
+	if p.cfg.SessionTimeout > 0 {
+		timeBox = min(timeBox, p.cfg.SessionTimeout)
+	}
+	downloaded, err := p.downloadAttachments(logger, *workItem, wsPath)
+	if err != nil {
+		return result, fmt.Errorf("download attachments: %w", err)
+	}
+	comments := p.fetchTicketComments(logger, *workItem)
+	if err := p.taskWriter.WriteIssue(*workItem, wsPath, downloaded, comments); err != nil {
+		return result, fmt.Errorf("write issue file: %w", err)
+	}
+	if err := p.taskWriter.WriteSpikeTask(*workItem, wsPath, timeBox, repo.Instructions); err != nil {
+		return result, fmt.Errorf("write spike task file: %w", err)
+	}
+	if err := os.Remove(filepath.Join(wsPath, taskfile.SpikePath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Failed to remove stale spike write-up", zap.Error(err))
+	}
+
+	// --- Step 4: Run the time-boxed AI session ---
+	session, err := p.runRepoSession(ctx, logger, job, settings, repoCfg, wsPath, timeBox)
+	result.CostUSD = session.CostUSD
+	writeUp := readSpikeWriteUp(wsPath)
+	timedOut := errors.Is(err, errSessionTimeout)
+	if err != nil && (!timedOut || writeUp == "") {
+		return result, err
+	}
+	if writeUp == "" {
+		return result, errors.New("AI wrote no spike write-up")
+	}
+
+	// --- Step 5: Post the write-up and park the ticket ---
+	var body strings.Builder
+	fmt.Fprintf(&body, "AI spike of this ticket (time box: %d min):\n\n%s", int(timeBox.Round(time.Minute).Minutes()), writeUp)
+	if timedOut {
+		body.WriteString("\n\nThe time box ran out before the AI finished, so this write-up may be incomplete.")
+	}
+	fmt.Fprintf(&body, "\n\nTo have the bot solve this ticket, move it back to %q.", settings.TodoStatus)
+	if err := p.tracker.AddComment(job.TicketKey, body.String()); err != nil {
+		return result, fmt.Errorf("post spike write-up: %w", err)
+	}
+	// The spike label goes last: a ticket that fails before then is
+	// reverted to todo with the label, so the spike is retried rather
+	// than the ticket solved.
+	if err := p.tracker.TransitionStatus(job.TicketKey, settings.InReviewStatus); err != nil {
+		return result, fmt.Errorf("transition to in-review: %w", err)
+	}
+	if err := p.tracker.AddLabel(job.TicketKey, spike.FinishedLabel()); err != nil {
+		return result, fmt.Errorf("add spike done label: %w", err)
+	}
+	if err := p.tracker.RemoveLabel(job.TicketKey, spike.Label); err != nil {
+		return result, fmt.Errorf("remove spike label: %w", err)
+	}
+
+	p.cleanupStatusComment(logger, job.TicketKey)
+	p.clearFailureLabels(logger, job.TicketKey, settings.FailureLabels)
+	logger.Info("Spike write-up posted",
+		zap.Duration("time_box", timeBox),
+		zap.Bool("timed_out", timedOut),
+		zap.Float64("cost_usd", result.CostUSD))
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package executor_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// withSpike labels PROJ-1 for a spike and enables spikes for the test
+// project.
+func withSpike(d *testDeps) {
+	getWorkItem := d.tracker.GetWorkItemFunc
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		item, err := getWorkItem(key)
+		if err != nil {
+			return nil, err
+		}
+		item.Labels = []string{"ai-spike"}
+		return item, nil
+	}
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(item models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(item)
+		if err == nil {
+			settings.Spike = models.Spike{Label: "ai-spike", TimeoutMinutes: 10}
+		}
+		return settings, err
+	}
+}
+
+func TestExecuteNewTicket_SpikePostsWriteUp(t *testing.T) {
+	d := newTestDeps(t)
+	withSpike(d)
+	var timeBox time.Duration
+	d.taskWriter.WriteSpikeTaskFunc = func(_ models.WorkItem, _ string, tb time.Duration, _ string) error {
+		timeBox = tb
+		return nil
+	}
+	d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+		writeSessionFile(t, d, taskfile.SpikePath, "## Findings\nThe cache is behind an interface.\n")
+		return "", 0, nil
+	}
+	var transitions, added, removed, comments []string
+	d.tracker.TransitionStatusFunc = func(_, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	d.tracker.AddLabelFunc = func(_, label string) error {
+		added = append(added, label)
+		return nil
+	}
+	d.tracker.RemoveLabelFunc = func(_, label string) error {
+		removed = append(removed, label)
+		return nil
+	}
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+	var commits int
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		commits++
+		return "abc123", nil
+	}
+
+	result, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if timeBox != 10*time.Minute {
+		t.Errorf("time box = %v, want 10m", timeBox)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "The cache is behind an interface.") ||
+		!strings.Contains(comments[0], `move it back to "To Do"`) {
+		t.Errorf("comments = %q, want the write-up with how to have the ticket solved", comments)
+	}
+	if !slices.Equal(transitions, []string{"In Progress", "In Review"}) {
+		t.Errorf("transitions = %v, want [In Progress In Review]", transitions)
+	}
+	if !slices.Contains(added, "ai-spike-done") || !slices.Contains(removed, "ai-spike") {
+		t.Errorf("added labels %v, removed %v; want ai-spike-done added and ai-spike removed", added, removed)
+	}
+	if commits != 0 || result.PRURL != "" {
+		t.Errorf("commits = %d, PR = %q; a spike must not change code", commits, result.PRURL)
+	}
+}
+
+func TestExecuteNewTicket_SpikeTimeBoxPostsPartialWriteUp(t *testing.T) {
+	d := newTestDeps(t)
+	withSpike(d)
+	d.containers.ExecFunc = func(ctx context.Context, _ *container.Container, _ []string) (string, int, error) {
+		writeSessionFile(t, d, taskfile.SpikePath, "## Findings\nStill digging.\n")
+		<-ctx.Done()
+		return "", -1, ctx.Err()
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+
+	// The session timeout is shorter than the spike's, so it bounds
+	// the time box.
+	p := d.pipelineWithConfig(t, executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": "test-key"},
+		MaxRetries:      3,
+		SessionTimeout:  50 * time.Millisecond,
+	})
+	if _, err := p.Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(comments) != 1 || !strings.Contains(comments[0], "Still digging.") ||
+		!strings.Contains(comments[0], "may be incomplete") {
+		t.Errorf("comments = %q, want the partial write-up", comments)
+	}
+}
+
+func TestExecuteNewTicket_SpikeWithoutWriteUpFails(t *testing.T) {
+	d := newTestDeps(t)
+	withSpike(d)
+	var transitions, removed []string
+	d.tracker.TransitionStatusFunc = func(_, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	d.tracker.RemoveLabelFunc = func(_, label string) error {
+		removed = append(removed, label)
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "no spike write-up") {
+		t.Fatalf("err = %v, want missing write-up error", err)
+	}
+	if last := transitions[len(transitions)-1]; last != "To Do" {
+		t.Errorf("transitions = %v, want the ticket reverted to To Do", transitions)
+	}
+	if slices.Contains(removed, "ai-spike") {
+		t.Error("spike label removed from a failed spike")
+	}
+}
@@ -13,9 +13,9 @@ const statusCommentMarker = "[AI-BOT-STATUS]"
 // formatStatusComment builds a failure status comment body.
 // When maxRetries is negative, retry limits are disabled and the
 // attempt count is shown without a total. When the retry limit is
-// reached and retryLabel is non-empty, appends a hint telling the
-// user how to request a retry.
-func formatStatusComment(attempt, maxRetries int, retryLabel string, err error, now time.Time) string {
+// reached, appends retryHint, which tells the user how to request a
+// retry (see [retryHint]).
+func formatStatusComment(attempt, maxRetries int, retryHint string, err error, now time.Time) string {
 	var b strings.Builder
 	b.WriteString(statusCommentMarker)
 
@@ -26,16 +26,106 @@ func formatStatusComment(attempt, maxRetries int, retryLabel string, err error,
 	}
 
 	fmt.Fprintf(&b, "\n\nError: %s", err.Error())
-	fmt.Fprintf(&b, "\n\nLast attempted: %s", now.UTC().Format(time.RFC3339))
+	writeStatusFooter(&b, attempt, maxRetries, retryHint, now)
+	return b.String()
+}
 
-	exhausted := maxRetries >= 0 && attempt > maxRetries
-	if exhausted && retryLabel != "" {
-		fmt.Fprintf(&b, "\n\nTo request a retry, add the label %q to this ticket.", retryLabel)
+// formatNoChangesComment builds the status comment for an AI session
+// that finished without changing the repository. It quotes the AI's
+// analysis, which usually explains why (e.g., the bug is already
+// fixed, or the ticket lacks information), so that a human can act
+// on it.
+func formatNoChangesComment(attempt, maxRetries int, retryHint, analysis string, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(statusCommentMarker)
+
+	if maxRetries < 0 {
+		fmt.Fprintf(&b, " AI produced no changes (attempt %d)", attempt)
+	} else {
+		fmt.Fprintf(&b, " AI produced no changes (attempt %d of %d)", attempt, maxRetries+1)
+	}
+
+	b.WriteString("\n\nThe AI session finished without changing the code, so no " +
+		"branch was pushed and no pull request was created.")
+	if analysis = strings.TrimSpace(analysis); analysis != "" {
+		fmt.Fprintf(&b, "\n\nAI analysis:\n%s", analysis)
+	}
+	writeStatusFooter(&b, attempt, maxRetries, retryHint, now)
+	return b.String()
+}
+
+// formatRejectedChangeComment builds the status comment for a ticket
+// whose AI change was rejected by a check before committing. reason
+// is the headline; details, when set, explain the rejection.
+func formatRejectedChangeComment(attempt, maxRetries int, retryHint, reason, details string, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(statusCommentMarker)
+
+	if maxRetries < 0 {
+		fmt.Fprintf(&b, " %s (attempt %d)", reason, attempt)
+	} else {
+		fmt.Fprintf(&b, " %s (attempt %d of %d)", reason, attempt, maxRetries+1)
+	}
+
+	b.WriteString("\n\nThe AI changed the code, but the change was rejected before " +
+		"committing, so no branch was pushed and no pull request was created.")
+	if details = strings.TrimSpace(details); details != "" {
+		fmt.Fprintf(&b, "\n\n%s", details)
+	}
+	writeStatusFooter(&b, attempt, maxRetries, retryHint, now)
+	return b.String()
+}
+
+// formatEscalationComment builds the status comment for a ticket the
+// bot has given up on after its final attempt. It summarizes what was
+// tried — the number of attempts, the last error, and the AI's last
+// analysis — so that a human can pick up where the bot left off.
+func formatEscalationComment(attempt int, retryHint string, err error, analysis string, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(statusCommentMarker)
+	attempts := "attempts"
+	if attempt == 1 {
+		attempts = "attempt"
 	}
+	fmt.Fprintf(&b, " AI could not complete this ticket after %d %s; it needs a human", attempt, attempts)
 
+	fmt.Fprintf(&b, "\n\nLast error: %s", err.Error())
+	if analysis = strings.TrimSpace(analysis); analysis != "" {
+		fmt.Fprintf(&b, "\n\nAI analysis from the last attempt:\n%s", analysis)
+	}
+	// Escalation only happens once retries are exhausted, so the
+	// footer always includes the retry hint when one is set.
+	writeStatusFooter(&b, attempt, 0, retryHint, now)
 	return b.String()
 }
 
+// writeStatusFooter appends the last-attempted time and, when the
+// retry limit is reached and retryHint is non-empty, the hint.
+func writeStatusFooter(b *strings.Builder, attempt, maxRetries int, retryHint string, now time.Time) {
+	fmt.Fprintf(b, "\n\nLast attempted: %s", now.UTC().Format(time.RFC3339))
+
+	exhausted := maxRetries >= 0 && attempt > maxRetries
+	if exhausted && retryHint != "" {
+		b.WriteString("\n\n" + retryHint)
+	}
+}
+
+// retryHint tells the user how to request a retry of a ticket that
+// exhausted its retries: by removing deadLetterLabel when the ticket
+// was dead-lettered, or else by adding retryLabel. Returns "" when
+// neither label is set.
+func retryHint(retryLabel, deadLetterLabel string) string {
+	switch {
+	case deadLetterLabel != "":
+		return fmt.Sprintf("The bot will not pick up this ticket again while it has the label %q. "+
+			"To request a retry, remove the label.", deadLetterLabel)
+	case retryLabel != "":
+		return fmt.Sprintf("To request a retry, add the label %q to this ticket.", retryLabel)
+	default:
+		return ""
+	}
+}
+
 // findStatusComment returns the first comment whose body contains
 // the status marker, or nil if none exists.
 func findStatusComment(comments []models.Comment) *models.Comment {
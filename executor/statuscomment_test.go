@@ -14,7 +14,7 @@ func TestFormatStatusComment(t *testing.T) {
 	jobErr := errors.New("container exited with code 1")
 
 	t.Run("includes marker and attempt info", func(t *testing.T) {
-		body := formatStatusComment(2, 3, "ai-retry", jobErr, now)
+		body := formatStatusComment(2, 3, retryHint("ai-retry", ""), jobErr, now)
 
 		for _, want := range []string{
 			statusCommentMarker,
@@ -40,7 +40,7 @@ func TestFormatStatusComment(t *testing.T) {
 	})
 
 	t.Run("retry hint shown when exhausted", func(t *testing.T) {
-		body := formatStatusComment(4, 3, "ai-retry", jobErr, now)
+		body := formatStatusComment(4, 3, retryHint("ai-retry", ""), jobErr, now)
 
 		want := `add the label "ai-retry"`
 		if !strings.Contains(body, want) {
@@ -48,8 +48,19 @@ func TestFormatStatusComment(t *testing.T) {
 		}
 	})
 
+	t.Run("dead-letter hint replaces retry label hint", func(t *testing.T) {
+		body := formatStatusComment(4, 3, retryHint("ai-retry", "ai-dead-letter"), jobErr, now)
+
+		if !strings.Contains(body, `remove the label`) || !strings.Contains(body, `"ai-dead-letter"`) {
+			t.Errorf("body missing dead-letter hint, got:\n%s", body)
+		}
+		if strings.Contains(body, "add the label") {
+			t.Errorf("body should not suggest the retry label, got:\n%s", body)
+		}
+	})
+
 	t.Run("no retry hint before exhaustion", func(t *testing.T) {
-		body := formatStatusComment(2, 3, "ai-retry", jobErr, now)
+		body := formatStatusComment(2, 3, retryHint("ai-retry", ""), jobErr, now)
 
 		if strings.Contains(body, "add the label") {
 			t.Errorf("body should not contain retry hint before exhaustion, got:\n%s", body)
@@ -99,3 +110,63 @@ func TestFindStatusComment(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatNoChangesComment(t *testing.T) {
+	now := time.Date(2026, 5, 5, 14, 30, 0, 0, time.UTC)
+
+	body := formatNoChangesComment(1, 3, retryHint("ai-retry", ""), "The bug was fixed in v1.2.\n", now)
+	for _, want := range []string{
+		statusCommentMarker,
+		"AI produced no changes (attempt 1 of 4)",
+		"no pull request was created",
+		"AI analysis:\nThe bug was fixed in v1.2.",
+		"2026-05-05T14:30:00Z",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q, got:\n%s", want, body)
+		}
+	}
+
+	body = formatNoChangesComment(4, 3, retryHint("ai-retry", ""), "", now)
+	if strings.Contains(body, "AI analysis") {
+		t.Errorf("body should omit empty analysis, got:\n%s", body)
+	}
+	if !strings.Contains(body, `add the label "ai-retry"`) {
+		t.Errorf("body should include retry hint when exhausted, got:\n%s", body)
+	}
+}
+
+func TestFormatRejectedChangeComment(t *testing.T) {
+	now := time.Date(2026, 5, 5, 14, 30, 0, 0, time.UTC)
+
+	body := formatRejectedChangeComment(1, 3, retryHint("ai-retry", ""), "AI self-review rejected the change",
+		"The fix changes the public API.", now)
+	for _, want := range []string{
+		statusCommentMarker,
+		"AI self-review rejected the change (attempt 1 of 4)",
+		"rejected before committing",
+		"\n\nThe fix changes the public API.",
+		"2026-05-05T14:30:00Z",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestFormatEscalationComment(t *testing.T) {
+	now := time.Date(2026, 5, 5, 14, 30, 0, 0, time.UTC)
+
+	body := formatEscalationComment(4, retryHint("ai-retry", ""), errors.New("AI session failed: exit 2"), "Tests need a database.", now)
+	for _, want := range []string{
+		statusCommentMarker,
+		"after 4 attempts; it needs a human",
+		"Last error: AI session failed: exit 2",
+		"AI analysis from the last attempt:\nTests need a database.",
+		`add the label "ai-retry"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q, got:\n%s", want, body)
+		}
+	}
+}
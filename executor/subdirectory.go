@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// appendSubdirectoryScope tells the AI, at the end of the task file,
+// which directories it may change when the ticket's component is
+// mapped to part of a monorepo. Errors are logged; the scope is still
+// enforced by [Pipeline.checkSubdirectories].
+func (p *Pipeline) appendSubdirectoryScope(logger *zap.Logger, wsPath string, settings *models.ProjectSettings) {
+	if !settings.HasSubdirectories() {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("\n## Scope\n\n")
+	b.WriteString("This ticket's component is one part of a larger repository. Change only\n")
+	b.WriteString("files under these directories; changes anywhere else are rejected:\n\n")
+	for _, dir := range allowedDirs(settings) {
+		fmt.Fprintf(&b, "- `%s/`\n", dir)
+	}
+	if dirs := checkedOutDirs(settings); len(dirs) > 0 {
+		b.WriteString("\nOnly part of the repository is checked out. Besides the files at its\n")
+		b.WriteString("root, you can read only these directories:\n\n")
+		for _, dir := range dirs {
+			fmt.Fprintf(&b, "- `%s/`\n", dir)
+		}
+	} else {
+		b.WriteString("\nYou may read other files for context.\n")
+	}
+	if err := appendToTaskFile(wsPath, b.String()); err != nil {
+		logger.Warn("Failed to add scope to task file", zap.Error(err))
+	}
+}
+
+// allowedDirs returns the workspace-relative directories the AI may
+// change: each repository's subdirectory, or the whole repository
+// when it has none. In single-repo workspaces the repository itself
+// is the workspace root.
+func allowedDirs(settings *models.ProjectSettings) []string {
+	dirs := make([]string, 0, len(settings.Repos))
+	for _, repo := range settings.Repos {
+		dir := repo.Subdirectory
+		if settings.IsMultiRepo() {
+			dir = path.Join(repo.Name, dir)
+		}
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// checkedOutDirs returns the workspace-relative directories of
+// repositories with a sparse checkout.
+func checkedOutDirs(settings *models.ProjectSettings) []string {
+	var dirs []string
+	for _, repo := range settings.Repos {
+		for _, dir := range repo.SparsePaths {
+			if settings.IsMultiRepo() {
+				dir = path.Join(repo.Name, dir)
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// checkSubdirectories rejects the change when the AI added, modified,
+// or deleted files outside a repository's configured subdirectory. A
+// [rejectedChangeError] listing the offending files is returned so
+// that the change is not committed.
+func (p *Pipeline) checkSubdirectories(logger *zap.Logger, wsPath string, settings *models.ProjectSettings) error {
+	var outside, allowed []string
+	for _, repo := range settings.Repos {
+		if repo.Subdirectory == "" {
+			continue
+		}
+		repoDir, prefix := wsPath, ""
+		if settings.IsMultiRepo() {
+			repoDir = filepath.Join(wsPath, repo.Name)
+			prefix = repo.Name + "/"
+		}
+		allowed = append(allowed, prefix+repo.Subdirectory)
+
+		changed, err := p.git.ChangedFiles(repoDir, repo.BaseBranch)
+		if err != nil {
+			return fmt.Errorf("list changed files for %s: %w", repo.Name, err)
+		}
+		deleted, err := p.git.DeletedFiles(repoDir, repo.BaseBranch)
+		if err != nil {
+			return fmt.Errorf("list deleted files for %s: %w", repo.Name, err)
+		}
+		for _, file := range append(changed, deleted...) {
+			if !strings.HasPrefix(file, repo.Subdirectory+"/") {
+				outside = append(outside, prefix+file)
+			}
+		}
+	}
+	if len(outside) == 0 {
+		return nil
+	}
+
+	logger.Warn("AI changed files outside the component's directory",
+		zap.Strings("allowed", allowed), zap.Int("files", len(outside)))
+	dirs := make([]string, len(allowed))
+	for i, dir := range allowed {
+		dirs[i] = "`" + dir + "/`"
+	}
+	files := make([]string, len(outside))
+	for i, file := range outside {
+		files[i] = "- `" + file + "`"
+	}
+	return &rejectedChangeError{
+		msg:    "AI changed files outside the component's directory",
+		reason: "Change outside the component's directory",
+		details: fmt.Sprintf("This ticket's component is limited to %s, but the change also touches:\n\n%s",
+			strings.Join(dirs, ", "), strings.Join(files, "\n")),
+	}
+}
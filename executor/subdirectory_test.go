@@ -0,0 +1,161 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoindex"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// withSubdirectory confines the project's repository to subdir.
+func withSubdirectory(d *testDeps, subdir string) {
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.Repos[0].Subdirectory = subdir
+		}
+		return settings, err
+	}
+}
+
+func TestExecuteNewTicket_SubdirectoryScopesTaskAndIndex(t *testing.T) {
+	d := newTestDeps(t)
+	withSubdirectory(d, "apps/web")
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir string, _ string, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	var gotURL, gotDir string
+	index := &executortest.StubRepoIndex{
+		RelevantFunc: func(repoURL, dir, query string) ([]repoindex.Entry, error) {
+			gotURL, gotDir = repoURL, dir
+			return []repoindex.Entry{{Path: "src/login.ts"}}, nil
+		},
+	}
+	var task string
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			data, _ := os.ReadFile(filepath.Join(req.Dir, taskfile.TaskFilePath))
+			task = string(data)
+			return agent.Result{}, nil
+		},
+	}
+	d.git.ChangedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"apps/web/src/login.ts"}, nil
+	}
+
+	cfg := agentConfig(runner)
+	cfg.RepoIndex = index
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotURL != "https://github.com/org/repo.git#apps/web" || gotDir != filepath.Join(d.wsDir, "apps", "web") {
+		t.Errorf("Relevant(%q, %q), want the subdirectory indexed on its own", gotURL, gotDir)
+	}
+	for _, want := range []string{"## Scope", "- `apps/web/`", "- `apps/web/src/login.ts`"} {
+		if !strings.Contains(task, want) {
+			t.Errorf("task file missing %q:\n%s", want, task)
+		}
+	}
+}
+
+func TestExecuteNewTicket_ChangeOutsideSubdirectoryBlocksPR(t *testing.T) {
+	d := newTestDeps(t)
+	withSubdirectory(d, "apps/web")
+	d.git.ChangedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"apps/web/src/login.ts", "apps/webhooks/main.go"}, nil
+	}
+	d.git.DeletedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"apps/api/handler.go"}, nil
+	}
+	committed := false
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		committed = true
+		return "abc123", nil
+	}
+	var comment string
+	d.tracker.AddCommentFunc = func(key, body string) error {
+		comment = body
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1"))
+	if err == nil || !strings.Contains(err.Error(), "outside the component's directory") {
+		t.Fatalf("err = %v, want error for changes outside the subdirectory", err)
+	}
+
+	if committed {
+		t.Error("change outside the subdirectory was committed")
+	}
+	for _, want := range []string{
+		"Change outside the component's directory",
+		"limited to `apps/web/`",
+		"- `apps/webhooks/main.go`",
+		"- `apps/api/handler.go`",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("comment missing %q, got:\n%s", want, comment)
+		}
+	}
+	if strings.Contains(comment, "login.ts") {
+		t.Errorf("comment lists a file inside the subdirectory:\n%s", comment)
+	}
+}
+
+func TestExecuteNewTicket_SparseCheckout(t *testing.T) {
+	d := newTestDeps(t)
+	withSubdirectory(d, "apps/web")
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(workItem models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(workItem)
+		if err == nil {
+			settings.Repos[0].SparsePaths = []string{".ai-bot", "apps/web", "libs/ui"}
+		}
+		return settings, err
+	}
+	var gotPaths []string
+	d.workspaces.FindOrCreateFunc = func(_, _ string, sparsePaths []string) (string, bool, error) {
+		gotPaths = sparsePaths
+		return d.wsDir, false, nil
+	}
+	d.taskWriter.WriteNewTicketTaskFunc = func(_ models.WorkItem, dir string, _ string, _ string) error {
+		writeTaskFile(t, dir)
+		return nil
+	}
+	var task string
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			data, _ := os.ReadFile(filepath.Join(req.Dir, taskfile.TaskFilePath))
+			task = string(data)
+			return agent.Result{}, nil
+		},
+	}
+	d.git.ChangedFilesFunc = func(dir, baseBranch string) ([]string, error) {
+		return []string{"apps/web/src/login.ts"}, nil
+	}
+
+	if _, err := d.pipelineWithConfig(t, agentConfig(runner)).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(gotPaths, ",") != ".ai-bot,apps/web,libs/ui" {
+		t.Errorf("workspace sparse paths = %v, want the repo's", gotPaths)
+	}
+	for _, want := range []string{"Only part of the repository is checked out", "- `libs/ui/`"} {
+		if !strings.Contains(task, want) {
+			t.Errorf("task file missing %q:\n%s", want, task)
+		}
+	}
+	if strings.Contains(task, "You may read other files") {
+		t.Errorf("task file invites reading files that are not checked out:\n%s", task)
+	}
+}
@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies spans emitted by the executor.
+const tracerName = "jira-ai-issue-solver/executor"
+
+// Span attribute keys. ticket.key is set on every span so that a
+// single ticket's stages can be found without walking the trace tree.
+const (
+	attrTicketKey  = attribute.Key("ticket.key")
+	attrJobID      = attribute.Key("job.id")
+	attrJobType    = attribute.Key("job.type")
+	attrJobAttempt = attribute.Key("job.attempt")
+	attrRepo       = attribute.Key("repo")
+)
+
+// Pipeline stage span names.
+const (
+	spanFetchWorkItem = "jira.get_work_item"
+	spanPrepareWS     = "workspace.prepare"
+	spanFetchComments = "github.fetch_comments"
+	spanAISession     = "ai.session"
+	spanCommit        = "git.commit"
+	spanCreatePR      = "github.create_pr"
+)
+
+// startStage starts a child span for a pipeline stage, tagged with
+// the ticket key and any extra attributes.
+func (p *Pipeline) startStage(ctx context.Context, name, ticketKey string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attrTicketKey.String(ticketKey))
+	return p.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endStage records err (if any) on the span and ends it.
+func endStage(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,115 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+)
+
+func tracedPipeline(t *testing.T, d *testDeps) (*executor.Pipeline, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	p := d.pipelineWithConfig(t, executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": "test-key"},
+		MaxRetries:      3,
+		TracerProvider:  tp,
+	})
+	return p, recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracing_NewTicketEmitsStageSpans(t *testing.T) {
+	d := newTestDeps(t)
+	p, recorder := tracedPipeline(t, d)
+
+	if _, err := p.Execute(context.Background(), newTicketJob("PROJ-123")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	byName := make(map[string]sdktrace.ReadOnlySpan, len(spans))
+	for _, s := range spans {
+		byName[s.Name()] = s
+	}
+
+	root, ok := byName["job.new_ticket"]
+	if !ok {
+		t.Fatalf("missing root span; got %d spans", len(spans))
+	}
+
+	for _, name := range []string{
+		"jira.get_work_item",
+		"workspace.prepare",
+		"ai.session",
+		"git.commit",
+		"github.create_pr",
+	} {
+		s, ok := byName[name]
+		if !ok {
+			t.Errorf("missing span %q", name)
+			continue
+		}
+		if s.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the job span", name)
+		}
+		if v, ok := spanAttr(s, "ticket.key"); !ok || v.AsString() != "PROJ-123" {
+			t.Errorf("span %q ticket.key = %v, want PROJ-123", name, v.AsString())
+		}
+	}
+
+	if v, ok := spanAttr(root, "pr.url"); !ok || v.AsString() != "https://github.com/org/repo/pull/1" {
+		t.Errorf("root pr.url = %q, want PR URL", v.AsString())
+	}
+}
+
+func TestTracing_FailedStageRecordsError(t *testing.T) {
+	d := newTestDeps(t)
+	d.git.CreatePRFunc = func(models.PRParams) (*models.PR, error) {
+		return nil, errors.New("github unavailable")
+	}
+	p, recorder := tracedPipeline(t, d)
+
+	if _, err := p.Execute(context.Background(), newTicketJob("PROJ-123")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var sawPR, sawRoot bool
+	for _, s := range recorder.Ended() {
+		switch s.Name() {
+		case "github.create_pr":
+			sawPR = true
+			if s.Status().Code != codes.Error {
+				t.Errorf("create_pr status = %v, want Error", s.Status().Code)
+			}
+		case "job.new_ticket":
+			sawRoot = true
+			if s.Status().Code != codes.Error {
+				t.Errorf("root status = %v, want Error", s.Status().Code)
+			}
+		}
+	}
+	if !sawPR || !sawRoot {
+		t.Errorf("sawPR=%v sawRoot=%v, want both", sawPR, sawRoot)
+	}
+}
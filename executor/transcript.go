@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+)
+
+const (
+	// agentTranscriptPath is where in-process agent sessions write
+	// their JSON-lines transcript, relative to the workspace root.
+	agentTranscriptPath = ".ai-session/transcript.jsonl"
+
+	// sessionLogPath is where the wrapper script tees the AI CLI's
+	// stderr.
+	sessionLogPath = ".ai-session/session.log"
+
+	// maxTranscriptBytes keeps attachments under Jira's default
+	// 10 MB upload limit.
+	maxTranscriptBytes = 9 << 20
+)
+
+// openAgentTranscript creates (or truncates) the transcript file for
+// an in-process agent session.
+func openAgentTranscript(wsPath string) (*os.File, error) {
+	path := filepath.Join(wsPath, agentTranscriptPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) // #nosec G304 -- path is dir + constant
+}
+
+// attachTranscript uploads the AI session's transcript to the ticket
+// when transcripts are enabled, so that humans can audit why the AI
+// made a change. The attachment name carries the job ID, which also
+// appears as job_id in the logs and job.id on the trace spans, to
+// correlate the three. Errors are logged and otherwise ignored.
+func (p *Pipeline) attachTranscript(logger *zap.Logger, job *jobmanager.Job, wsPath string, agentSession bool) {
+	if !p.cfg.AttachTranscripts {
+		return
+	}
+
+	data, ext := readTranscript(wsPath, agentSession)
+	if len(data) == 0 {
+		logger.Warn("No AI transcript to attach")
+		return
+	}
+	if len(data) > maxTranscriptBytes {
+		data = append(data[:maxTranscriptBytes:maxTranscriptBytes], "\n[transcript truncated]\n"...)
+	}
+
+	filename := fmt.Sprintf("ai-transcript-%s-%s.%s", job.TicketKey, job.ID, ext)
+	if err := p.tracker.AddAttachment(job.TicketKey, filename, data); err != nil {
+		logger.Warn("Failed to attach AI transcript", zap.Error(err))
+		return
+	}
+	logger.Info("Attached AI transcript", zap.String("attachment", filename))
+}
+
+// readTranscript returns the session transcript and its file
+// extension. In-process agent sessions have a JSON-lines transcript;
+// CLI sessions have the CLI's JSON output (with --verbose, the full
+// conversation for Claude) followed by its stderr log.
+func readTranscript(wsPath string, agentSession bool) ([]byte, string) {
+	if agentSession {
+		data, _ := os.ReadFile(filepath.Join(wsPath, agentTranscriptPath)) // #nosec G304 -- path is dir + constant
+		return data, "jsonl"
+	}
+
+	var buf bytes.Buffer
+	for _, rel := range []string{cliOutputPath, sessionLogPath} {
+		data, err := os.ReadFile(filepath.Join(wsPath, rel)) // #nosec G304 -- path is dir + constant
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "==> %s <==\n", filepath.Base(rel))
+		buf.Write(data)
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes(), "txt"
+}
@@ -0,0 +1,90 @@
+package executor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/executor/executortest"
+)
+
+func TestExecuteNewTicket_AttachesCLITranscript(t *testing.T) {
+	d := newTestDeps(t)
+	d.containers.ExecFunc = func(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+		dir := filepath.Join(d.wsDir, ".ai-session")
+		_ = os.MkdirAll(dir, 0o750)
+		_ = os.WriteFile(filepath.Join(dir, "cli-output.json"), []byte(`[{"type":"result"}]`), 0o600)
+		_ = os.WriteFile(filepath.Join(dir, "session.log"), []byte("warning: x\n"), 0o600)
+		return "", 0, nil
+	}
+	var gotKey, gotName, gotData string
+	d.tracker.AddAttachmentFunc = func(key, filename string, content []byte) error {
+		gotKey, gotName, gotData = key, filename, string(content)
+		return nil
+	}
+	cfg := executor.Config{
+		BotUsername:       "ai-bot",
+		DefaultProvider:   "claude",
+		AIAPIKeys:         map[string]string{"claude": "test-key"},
+		MaxRetries:        3,
+		AttachTranscripts: true,
+	}
+
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotKey != "PROJ-1" || gotName != "ai-transcript-PROJ-1-job-1.txt" {
+		t.Errorf("attachment = (%q, %q), want (PROJ-1, ai-transcript-PROJ-1-job-1.txt)", gotKey, gotName)
+	}
+	for _, want := range []string{"==> cli-output.json <==", `[{"type":"result"}]`, "==> session.log <==", "warning: x"} {
+		if !strings.Contains(gotData, want) {
+			t.Errorf("transcript missing %q:\n%s", want, gotData)
+		}
+	}
+}
+
+func TestExecuteNewTicket_AttachesAgentTranscript(t *testing.T) {
+	d := newTestDeps(t)
+	runner := &executortest.StubAgentRunner{
+		RunFunc: func(ctx context.Context, req agent.Request) (agent.Result, error) {
+			if req.Transcript == nil {
+				t.Fatal("Transcript writer not set")
+			}
+			_, _ = req.Transcript.Write([]byte(`{"kind":"prompt","turn":0}` + "\n"))
+			return agent.Result{}, nil
+		},
+	}
+	var gotName, gotData string
+	d.tracker.AddAttachmentFunc = func(key, filename string, content []byte) error {
+		gotName, gotData = filename, string(content)
+		return nil
+	}
+	cfg := agentConfig(runner)
+	cfg.AttachTranscripts = true
+
+	if _, err := d.pipelineWithConfig(t, cfg).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotName != "ai-transcript-PROJ-1-job-1.jsonl" || gotData != `{"kind":"prompt","turn":0}`+"\n" {
+		t.Errorf("attachment = (%q, %q)", gotName, gotData)
+	}
+}
+
+func TestExecuteNewTicket_TranscriptsDisabledByDefault(t *testing.T) {
+	d := newTestDeps(t)
+	d.tracker.AddAttachmentFunc = func(string, string, []byte) error {
+		t.Error("AddAttachment should not be called when transcripts are disabled")
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
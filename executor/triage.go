@@ -0,0 +1,286 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/repoconfig"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// maxTriageCandidates bounds the number of existing tickets offered to
+// the AI as possible duplicates.
+const maxTriageCandidates = 50
+
+// errEnoughCandidates stops the candidate search once
+// maxTriageCandidates tickets are found.
+var errEnoughCandidates = errors.New("enough triage candidates")
+
+// triageProposal is the AI's triage of a ticket, read from
+// taskfile.TriagePath.
+type triageProposal struct {
+	Component  string   `json:"component"`
+	Severity   string   `json:"severity"`
+	Duplicates []string `json:"duplicates"`
+	Estimate   string   `json:"estimate"`
+	Rationale  string   `json:"rationale"`
+}
+
+// readTriageProposal reads the AI's triage proposal from the
+// workspace. Returns nil if the file is missing or malformed.
+func readTriageProposal(dir string) *triageProposal {
+	data, err := os.ReadFile(filepath.Join(dir, taskfile.TriagePath)) // #nosec G304 -- path is dir + constant
+	if err != nil {
+		return nil
+	}
+	var t triageProposal
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil
+	}
+	t.Component = strings.TrimSpace(t.Component)
+	t.Severity = strings.TrimSpace(t.Severity)
+	t.Duplicates = nonEmpty(t.Duplicates)
+	t.Estimate = strings.TrimSpace(t.Estimate)
+	t.Rationale = strings.TrimSpace(t.Rationale)
+	return &t
+}
+
+// executeTriage has the AI propose the component, severity, possible
+// duplicates, and effort of a ticket labeled for triage, writes the
+// proposals into the project's triage fields, and replaces the triage
+// label with the triaged one. No code is changed; the ticket's status
+// is left alone.
+func (p *Pipeline) executeTriage(ctx context.Context, job *jobmanager.Job) (result jobmanager.JobResult, retErr error) {
+	logger := p.logger.With(
+		zap.String("ticket", job.TicketKey),
+		zap.String("job_id", job.ID),
+		zap.Int("attempt", job.AttemptNum),
+	)
+	logger.Info("Starting triage pipeline")
+
+	// --- Step 1: Fetch work item ---
+	workItem, err := p.jobWorkItem(job)
+	if err != nil {
+		return result, fmt.Errorf("get work item: %w", err)
+	}
+
+	// --- Step 2: Resolve project settings ---
+	settings, err := p.projects.ResolveProject(*workItem)
+	if err != nil {
+		return result, fmt.Errorf("resolve project: %w", err)
+	}
+	triage := settings.Triage
+	if !triage.Requested(workItem.Labels) {
+		logger.Info("Triage not requested")
+		return result, nil
+	}
+
+	defer func() {
+		if retErr != nil {
+			p.handleTriageFailure(logger, job.TicketKey, settings, retErr)
+		}
+	}()
+
+	// --- Step 3: Prepare workspace ---
+	// Triage reads the code of the workspace's first repository. A
+	// workspace created only for triage is removed afterwards, so
+	// that solving the ticket later starts from a fresh clone.
+	repo := settings.Repos[0]
+	wsPath, reused, err := p.workspaces.FindOrCreate(job.TicketKey, repo.CloneURL, repo.SparsePaths)
+	if err != nil {
+		return result, fmt.Errorf("prepare workspace: %w", err)
+	}
+	if !reused {
+		defer func() {
+			if err := p.workspaces.Cleanup(job.TicketKey); err != nil {
+				logger.Warn("Failed to delete triage workspace", zap.Error(err))
+			}
+		}()
+	}
+	repoCfg, err := repoconfig.Load(wsPath)
+	if err != nil {
+		logger.Warn("Failed to load repo config, using defaults", zap.Error(err))
+		repoCfg = repoconfig.Default()
+	}
+
+	// --- Step 4: Write task files ---
+	comments := p.fetchTicketComments(logger, *workItem)
+	if err := p.taskWriter.WriteIssue(*workItem, wsPath, nil, comments); err != nil {
+		return result, fmt.Errorf("write issue file: %w", err)
+	}
+	candidates := p.triageCandidates(logger, workItem.Key)
+	opts := taskfile.TriageOptions{
+		Components: settings.Components,
+		Severities: triage.Severities,
+		Candidates: candidates,
+	}
+	if err := p.taskWriter.WriteTriageTask(*workItem, wsPath, opts); err != nil {
+		return result, fmt.Errorf("write triage task file: %w", err)
+	}
+	if err := os.Remove(filepath.Join(wsPath, taskfile.TriagePath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Failed to remove stale triage proposal", zap.Error(err))
+	}
+
+	// --- Step 5: Run AI session ---
+	session, err := p.runRepoSession(ctx, logger, job, settings, repoCfg, wsPath, p.cfg.SessionTimeout)
+	result.CostUSD = session.CostUSD
+	if err != nil {
+		return result, err
+	}
+	proposal := readTriageProposal(wsPath)
+	if proposal == nil {
+		return result, errors.New("AI wrote no triage proposal")
+	}
+	proposal.restrictTo(opts)
+
+	// --- Step 6: Record proposals ---
+	if err := p.recordTriage(job.TicketKey, triage, proposal); err != nil {
+		return result, err
+	}
+
+	// --- Step 7: Mark the ticket triaged ---
+	if err := p.tracker.AddLabel(job.TicketKey, triage.TriagedLabel()); err != nil {
+		return result, fmt.Errorf("add triaged label: %w", err)
+	}
+	if err := p.tracker.RemoveLabel(job.TicketKey, triage.Label); err != nil {
+		return result, fmt.Errorf("remove triage label: %w", err)
+	}
+
+	logger.Info("Ticket triaged",
+		zap.String("component", proposal.Component),
+		zap.String("severity", proposal.Severity),
+		zap.Strings("duplicates", proposal.Duplicates),
+		zap.String("estimate", proposal.Estimate))
+	return result, nil
+}
+
+// triageCandidates returns the most recently updated tickets of the
+// ticket's project, other than the ticket itself, as possible
+// duplicates. Search errors are logged and yield the tickets found so
+// far.
+func (p *Pipeline) triageCandidates(logger *zap.Logger, ticketKey string) []models.WorkItem {
+	criteria := models.SearchCriteria{
+		ProjectKeys: []string{ticketProject(ticketKey)},
+		OrderBy:     "updated DESC",
+	}
+	var candidates []models.WorkItem
+	err := p.tracker.SearchWorkItemPages(criteria, func(page []models.WorkItem) error {
+		for _, item := range page {
+			if item.Key == ticketKey {
+				continue
+			}
+			candidates = append(candidates, item)
+			if len(candidates) == maxTriageCandidates {
+				return errEnoughCandidates
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEnoughCandidates) {
+		logger.Warn("Failed to search for possible duplicates", zap.Error(err))
+	}
+	return candidates
+}
+
+// restrictTo drops the proposals that are not among the choices the
+// AI was offered: a component or severity outside a non-empty list
+// (matched case-insensitively and replaced by the listed spelling),
+// and duplicates that were not candidates.
+func (t *triageProposal) restrictTo(opts taskfile.TriageOptions) {
+	t.Component = pickOption(t.Component, opts.Components)
+	t.Severity = pickOption(t.Severity, opts.Severities)
+
+	var duplicates []string
+	for _, key := range t.Duplicates {
+		isCandidate := slices.ContainsFunc(opts.Candidates, func(c models.WorkItem) bool {
+			return strings.EqualFold(c.Key, key)
+		})
+		if isCandidate && !slices.Contains(duplicates, strings.ToUpper(key)) {
+			duplicates = append(duplicates, strings.ToUpper(key))
+		}
+	}
+	t.Duplicates = duplicates
+}
+
+// pickOption returns the entry of options equal to value ignoring
+// case, "" if there is none, or value unchanged when options is empty.
+func pickOption(value string, options []string) string {
+	if len(options) == 0 {
+		return value
+	}
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return option
+		}
+	}
+	return ""
+}
+
+// recordTriage writes the proposals into the configured triage fields
+// and posts them, with the AI's rationale, as a comment on the ticket.
+// Proposals the AI left empty are skipped. Returns the errors of the
+// field updates and of the comment, joined.
+func (p *Pipeline) recordTriage(ticketKey string, triage models.Triage, proposal *triageProposal) error {
+	duplicates := strings.Join(proposal.Duplicates, ", ")
+	entries := []struct {
+		label, field, value string
+	}{
+		{"Component", triage.Fields.Component, proposal.Component},
+		{"Severity", triage.Fields.Severity, proposal.Severity},
+		{"Possible duplicates", triage.Fields.Duplicates, duplicates},
+		{"Effort estimate", triage.Fields.Estimate, proposal.Estimate},
+	}
+
+	var errs []error
+	var comment strings.Builder
+	comment.WriteString("AI triage of this ticket:\n")
+	for _, e := range entries {
+		if e.value == "" {
+			continue
+		}
+		fmt.Fprintf(&comment, "\n- %s: %s", e.label, e.value)
+		if e.field == "" {
+			continue
+		}
+		if err := p.tracker.SetFieldValue(ticketKey, e.field, e.value); err != nil {
+			errs = append(errs, fmt.Errorf("set %s field: %w", strings.ToLower(e.label), err))
+		}
+	}
+	if proposal.Rationale != "" {
+		fmt.Fprintf(&comment, "\n\n%s", proposal.Rationale)
+	}
+
+	if err := p.tracker.AddComment(ticketKey, comment.String()); err != nil {
+		errs = append(errs, fmt.Errorf("post triage comment: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// handleTriageFailure posts an error comment when triage fails. The
+// triage label is left in place, so that the ticket is triaged again
+// on a later scan until its retries are exhausted.
+func (p *Pipeline) handleTriageFailure(
+	logger *zap.Logger,
+	ticketKey string,
+	settings *models.ProjectSettings,
+	jobErr error,
+) {
+	if settings.DisableErrorComments {
+		return
+	}
+
+	comment := fmt.Sprintf("AI triage failed: %s", jobErr.Error())
+	if err := p.tracker.AddComment(ticketKey, comment); err != nil {
+		logger.Error("Failed to post error comment", zap.Error(err))
+	}
+}
@@ -0,0 +1,193 @@
+package executor_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// withTriage labels PROJ-1 for triage into all four fields, offers
+// PROJ-7 as a possible duplicate, and has the AI write proposal to
+// the triage file.
+func withTriage(t *testing.T, d *testDeps, proposal string) {
+	t.Helper()
+	getWorkItem := d.tracker.GetWorkItemFunc
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		item, err := getWorkItem(key)
+		if err != nil {
+			return nil, err
+		}
+		item.Labels = []string{"ai-triage"}
+		return item, nil
+	}
+	resolve := d.projects.ResolveProjectFunc
+	d.projects.ResolveProjectFunc = func(item models.WorkItem) (*models.ProjectSettings, error) {
+		settings, err := resolve(item)
+		if err != nil {
+			return nil, err
+		}
+		settings.Triage = models.Triage{
+			Label:      "ai-triage",
+			Severities: []string{"Major", "Minor"},
+			Fields: models.TriageFields{
+				Component:  "AI Component",
+				Severity:   "AI Severity",
+				Duplicates: "AI Duplicates",
+				Estimate:   "AI Estimate",
+			},
+		}
+		settings.Components = []string{"backend", "frontend"}
+		return settings, nil
+	}
+	d.tracker.SearchWorkItemsFunc = func(models.SearchCriteria) ([]models.WorkItem, error) {
+		return []models.WorkItem{{Key: "PROJ-1"}, {Key: "PROJ-7", Summary: "Expired tokens are not refreshed"}}, nil
+	}
+	d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+		writeSessionFile(t, d, taskfile.TriagePath, proposal)
+		return "", 0, nil
+	}
+}
+
+func triageJob(ticketKey string) *jobmanager.Job {
+	return &jobmanager.Job{
+		ID:         "triage-job-1",
+		TicketKey:  ticketKey,
+		Type:       jobmanager.JobTypeTriage,
+		AttemptNum: 1,
+	}
+}
+
+func TestExecuteTriage_RecordsProposals(t *testing.T) {
+	d := newTestDeps(t)
+	withTriage(t, d, `{
+		"component": "Backend",
+		"severity": "critical",
+		"duplicates": ["proj-7", "PROJ-99"],
+		"estimate": "1 day",
+		"rationale": "The refresh path lives in the API server."
+	}`)
+
+	var opts taskfile.TriageOptions
+	d.taskWriter.WriteTriageTaskFunc = func(_ models.WorkItem, _ string, o taskfile.TriageOptions) error {
+		opts = o
+		return nil
+	}
+	fields := map[string]string{}
+	d.tracker.SetFieldValueFunc = func(_, field, value string) error {
+		fields[field] = value
+		return nil
+	}
+	var comments []string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+	var added, removed []string
+	d.tracker.AddLabelFunc = func(_, label string) error {
+		added = append(added, label)
+		return nil
+	}
+	d.tracker.RemoveLabelFunc = func(_, label string) error {
+		removed = append(removed, label)
+		return nil
+	}
+	var cleaned bool
+	d.workspaces.CleanupFunc = func(string) error {
+		cleaned = true
+		return nil
+	}
+	var commits int
+	d.git.CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		commits++
+		return "abc123", nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), triageJob("PROJ-1")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(opts.Candidates) != 1 || opts.Candidates[0].Key != "PROJ-7" {
+		t.Errorf("candidates = %+v, want only PROJ-7", opts.Candidates)
+	}
+	// The unknown severity and the duplicate that was not offered are
+	// dropped.
+	want := map[string]string{
+		"AI Component":  "backend",
+		"AI Duplicates": "PROJ-7",
+		"AI Estimate":   "1 day",
+	}
+	if len(fields) != len(want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+	for field, value := range want {
+		if fields[field] != value {
+			t.Errorf("field %q = %q, want %q", field, fields[field], value)
+		}
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "The refresh path lives in the API server.") {
+		t.Errorf("comments = %q, want one triage comment with the rationale", comments)
+	}
+	if !slices.Equal(added, []string{"ai-triaged"}) || !slices.Equal(removed, []string{"ai-triage"}) {
+		t.Errorf("added labels %v, removed %v; want ai-triaged added and ai-triage removed", added, removed)
+	}
+	if !cleaned {
+		t.Error("triage workspace was not deleted")
+	}
+	if commits != 0 {
+		t.Errorf("committed %d times, want no commits", commits)
+	}
+}
+
+func TestExecuteTriage_NoProposal(t *testing.T) {
+	d := newTestDeps(t)
+	withTriage(t, d, `not json`)
+
+	var comments []string
+	d.tracker.AddCommentFunc = func(_, body string) error {
+		comments = append(comments, body)
+		return nil
+	}
+	var removed []string
+	d.tracker.RemoveLabelFunc = func(_, label string) error {
+		removed = append(removed, label)
+		return nil
+	}
+
+	_, err := d.pipeline(t).Execute(context.Background(), triageJob("PROJ-1"))
+	if err == nil {
+		t.Fatal("Execute succeeded without a triage proposal")
+	}
+	if len(comments) != 1 || !strings.HasPrefix(comments[0], "AI triage failed:") {
+		t.Errorf("comments = %q, want one failure comment", comments)
+	}
+	if len(removed) != 0 {
+		t.Errorf("removed labels %v, want the triage label kept for a retry", removed)
+	}
+}
+
+func TestExecuteTriage_NotRequested(t *testing.T) {
+	d := newTestDeps(t)
+	withTriage(t, d, `{}`)
+	d.tracker.GetWorkItemFunc = func(key string) (*models.WorkItem, error) {
+		return &models.WorkItem{Key: key, Type: "Bug"}, nil
+	}
+
+	var sessions int
+	d.containers.ExecFunc = func(context.Context, *container.Container, []string) (string, int, error) {
+		sessions++
+		return "", 0, nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), triageJob("PROJ-1")); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if sessions != 0 {
+		t.Errorf("ran %d AI sessions for a ticket without the triage label", sessions)
+	}
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+)
+
+// worklogActivities describes each job type in worklog comments.
+var worklogActivities = map[jobmanager.JobType]string{
+	jobmanager.JobTypeNewTicket:   "implementation",
+	jobmanager.JobTypeFeedback:    "review feedback",
+	jobmanager.JobTypeMerge:       "merge conflict resolution",
+	jobmanager.JobTypeBackport:    "backport",
+	jobmanager.JobTypeTriage:      "triage",
+	jobmanager.JobTypeReleaseNote: "release note",
+}
+
+// aiSessionsKey is the context key of the flag that
+// [Pipeline.runAISession] sets once a job has started an AI session.
+type aiSessionsKey struct{}
+
+// trackAISessions returns a context under which
+// [Pipeline.runAISession] records that an AI session was started, and
+// the flag it sets.
+func trackAISessions(ctx context.Context) (context.Context, *atomic.Bool) {
+	ran := new(atomic.Bool)
+	return context.WithValue(ctx, aiSessionsKey{}, ran), ran
+}
+
+// markAISession records on ctx that an AI session was started.
+func markAISession(ctx context.Context) {
+	if ran, ok := ctx.Value(aiSessionsKey{}).(*atomic.Bool); ok {
+		ran.Store(true)
+	}
+}
+
+// recordWorklog logs the job's wall-clock duration on the ticket when
+// worklogs are enabled and the job ran an AI session. Jobs that were
+// deferred, skipped, or failed before reaching the AI session are not
+// recorded, since Jira would round their few seconds up to a minute;
+// jobs that failed afterwards are, since the time was spent either
+// way. Errors are logged and otherwise ignored.
+func (p *Pipeline) recordWorklog(job *jobmanager.Job, started time.Time, ranAI bool) {
+	if !p.cfg.Worklog.Enabled || !ranAI {
+		return
+	}
+
+	comment := strings.NewReplacer(
+		"{{author}}", p.cfg.Worklog.Author,
+		"{{activity}}", worklogActivities[job.Type],
+	).Replace(p.cfg.Worklog.Description)
+
+	if err := p.tracker.AddWorklog(job.TicketKey, started, time.Since(started), comment); err != nil {
+		p.logger.Warn("Failed to add worklog",
+			zap.String("ticket", job.TicketKey),
+			zap.String("job_id", job.ID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,83 @@
+package executor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+)
+
+func worklogConfig() executor.Config {
+	return executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": "test-key"},
+		MaxRetries:      3,
+		Worklog: executor.WorklogConfig{
+			Enabled:     true,
+			Author:      "AI Bot",
+			Description: "{{author}}: {{activity}}",
+		},
+	}
+}
+
+func TestExecute_RecordsWorklog(t *testing.T) {
+	d := newTestDeps(t)
+	var gotKey, gotComment string
+	d.tracker.AddWorklogFunc = func(key string, started time.Time, timeSpent time.Duration, comment string) error {
+		gotKey, gotComment = key, comment
+		if started.IsZero() || timeSpent < 0 {
+			t.Errorf("started = %v, timeSpent = %v", started, timeSpent)
+		}
+		return nil
+	}
+
+	if _, err := d.pipelineWithConfig(t, worklogConfig()).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotKey != "PROJ-1" || gotComment != "AI Bot: implementation" {
+		t.Errorf("worklog = (%q, %q), want (PROJ-1, \"AI Bot: implementation\")", gotKey, gotComment)
+	}
+}
+
+func TestExecute_DeferredJobRecordsNoWorklog(t *testing.T) {
+	d := newTestDeps(t)
+	withLinks(d, models.IssueLink{Type: "Blocks", Relation: "is blocked by", Key: "PROJ-2"})
+	d.tracker.AddWorklogFunc = func(string, time.Time, time.Duration, string) error {
+		t.Error("AddWorklog should not be called for a deferred job")
+		return nil
+	}
+
+	_, _ = d.pipelineWithConfig(t, worklogConfig()).Execute(context.Background(), newTicketJob("PROJ-1"))
+}
+
+func TestExecute_JobFailingBeforeAIRecordsNoWorklog(t *testing.T) {
+	d := newTestDeps(t)
+	d.projects.ResolveProjectFunc = func(models.WorkItem) (*models.ProjectSettings, error) {
+		return nil, errors.New("no project")
+	}
+	d.tracker.AddWorklogFunc = func(string, time.Time, time.Duration, string) error {
+		t.Error("AddWorklog should not be called for a job that never ran the AI")
+		return nil
+	}
+
+	if _, err := d.pipelineWithConfig(t, worklogConfig()).Execute(context.Background(), newTicketJob("PROJ-1")); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestExecute_WorklogDisabledByDefault(t *testing.T) {
+	d := newTestDeps(t)
+	d.tracker.AddWorklogFunc = func(string, time.Time, time.Duration, string) error {
+		t.Error("AddWorklog should not be called when worklogs are disabled")
+		return nil
+	}
+
+	if _, err := d.pipeline(t).Execute(context.Background(), newTicketJob("PROJ-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package health
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// statfsUsage reports free and total bytes for the filesystem
+// containing path. Free space is the amount available to
+// unprivileged users, which is what workspace clones consume.
+func statfsUsage(path string) (free, total uint64, err error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	bsize := uint64(st.Bsize) //nolint:gosec // block size is never negative
+	return st.Bavail * bsize, st.Blocks * bsize, nil
+}
@@ -0,0 +1,634 @@
+// Package health serves the bot's liveness and readiness endpoints.
+//
+// # Liveness (/healthz)
+//
+// Reports local process state only: scanner last-run timestamps, job
+// queue depth, the pending jobs in dispatch order, AI sessions
+// running and waiting on rate limits, each project's AI spend, and
+// the state of the circuit breakers in front of external services.
+// Returns 503 when a scanner has not completed a cycle within
+// [Config.ScannerStaleAfter], which indicates a wedged polling
+// goroutine that a restart would fix. A [HeartbeatReporter] is judged
+// by its heartbeat instead, since its scans may be hours apart.
+// External dependencies are deliberately excluded so that a Jira
+// outage does not cause the pod to be restarted.
+//
+// # Readiness (/readyz)
+//
+// Runs every registered [Probe] (e.g., Jira and GitHub connectivity,
+// container runtime availability), checks free space on the
+// workspace volume, and requires each scanner to have completed at
+// least one cycle. Returns 503 if any check fails.
+//
+// Both endpoints return a JSON [Report]. Checks and scanners are
+// sorted by name so that output is deterministic.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/ailimit"
+	"jira-ai-issue-solver/circuit"
+	"jira-ai-issue-solver/costtracker"
+	"jira-ai-issue-solver/jobmanager"
+)
+
+// Status values reported for checks and overall health.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// defaultProbeTimeout bounds each probe when [Config.ProbeTimeout]
+// is not set.
+const defaultProbeTimeout = 5 * time.Second
+
+// Probe checks a dependency and returns an error if it is
+// unavailable. Probes must honor context cancellation or return
+// promptly; the [Checker] abandons probes that exceed the timeout.
+type Probe func(ctx context.Context) error
+
+// ScanReporter reports when a scanner last finished a scan cycle.
+// Satisfied by the scanner package's scanner types.
+type ScanReporter interface {
+	LastScan() time.Time
+}
+
+// HeartbeatReporter is implemented by scanners that can wait longer
+// than [Config.ScannerStaleAfter] between scans, such as those on a
+// cron schedule. Their polling goroutine still wakes up regularly,
+// and LastHeartbeat reports when it last did. Liveness and readiness
+// are then judged on the heartbeat, while the report's last_scan
+// still shows the last real scan.
+type HeartbeatReporter interface {
+	LastHeartbeat() time.Time
+}
+
+// QueueReporter reports the job manager's workload. Satisfied by
+// [jobmanager.Coordinator].
+type QueueReporter interface {
+	Stats() jobmanager.Stats
+}
+
+// AILimitReporter reports AI sessions running and waiting on rate
+// limits. Satisfied by [ailimit.Limiter].
+type AILimitReporter interface {
+	Stats() ailimit.Stats
+}
+
+// ProjectBudgetReporter reports AI spend per Jira project. Satisfied
+// by [costtracker.ProjectTracker].
+type ProjectBudgetReporter interface {
+	Stats() []costtracker.ProjectSpend
+}
+
+// BreakerReporter reports a circuit breaker's state. Satisfied by
+// [circuit.Breaker].
+type BreakerReporter interface {
+	Stats() circuit.Stats
+}
+
+// DiskUsageFunc returns the free and total bytes of the filesystem
+// containing path.
+type DiskUsageFunc func(path string) (free, total uint64, err error)
+
+// Config holds construction parameters for [Checker].
+type Config struct {
+	// WorkspaceDir is the directory whose filesystem is checked for
+	// free space. Empty disables the disk check.
+	WorkspaceDir string
+
+	// MinFreeDiskBytes is the minimum free space required for the
+	// disk check to pass. Zero disables the threshold (usage is
+	// still reported).
+	MinFreeDiskBytes uint64
+
+	// ScannerStaleAfter is how long a scanner may go without
+	// completing a cycle before it is considered stale. Zero
+	// disables staleness detection. It can be changed later with
+	// [Checker.SetScannerStaleAfter].
+	ScannerStaleAfter time.Duration
+
+	// ProbeTimeout bounds each probe. Defaults to 5 seconds.
+	ProbeTimeout time.Duration
+
+	// DiskUsage reports filesystem usage. Defaults to a statfs-based
+	// implementation. Exposed for testing.
+	DiskUsage DiskUsageFunc
+
+	// Clock returns the current time. Defaults to [time.Now].
+	// Exposed for testing.
+	Clock func() time.Time
+}
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// DiskReport describes free space on the workspace volume.
+type DiskReport struct {
+	Status       string `json:"status"`
+	Path         string `json:"path"`
+	FreeBytes    uint64 `json:"free_bytes"`
+	TotalBytes   uint64 `json:"total_bytes"`
+	MinFreeBytes uint64 `json:"min_free_bytes"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ScannerReport describes a scanner's most recent cycle.
+// LastHeartbeat is set only for a [HeartbeatReporter].
+type ScannerReport struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	LastScan      *time.Time `json:"last_scan"`
+	LastHeartbeat *time.Time `json:"last_heartbeat,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// QueueReport describes the job manager's workload. Queued lists the
+// pending jobs in the order they will be dispatched.
+type QueueReport struct {
+	Pending       int               `json:"pending"`
+	Running       int               `json:"running"`
+	MaxConcurrent int               `json:"max_concurrent"`
+	CircuitOpen   bool              `json:"circuit_open"`
+	Queued        []QueuedJobReport `json:"queued"`
+}
+
+// QueuedJobReport describes one pending job.
+type QueuedJobReport struct {
+	Ticket        string     `json:"ticket"`
+	Type          string     `json:"type"`
+	Priority      int        `json:"priority"`
+	TicketCreated *time.Time `json:"ticket_created,omitempty"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+}
+
+// AISessionReport describes AI sessions admitted and held back by the
+// rate limiter. Providers is sorted by name.
+type AISessionReport struct {
+	Running       int                `json:"running"`
+	Waiting       int                `json:"waiting"`
+	MaxConcurrent int                `json:"max_concurrent"`
+	Providers     []AIProviderReport `json:"providers"`
+}
+
+// AIProviderReport describes one provider's AI sessions. Started,
+// Delayed, and WaitSeconds accumulate since the bot started.
+type AIProviderReport struct {
+	Provider      string  `json:"provider"`
+	Running       int     `json:"running"`
+	Waiting       int     `json:"waiting"`
+	MaxConcurrent int     `json:"max_concurrent"`
+	PerMinute     float64 `json:"per_minute,omitempty"`
+	Started       int64   `json:"started"`
+	Delayed       int64   `json:"delayed"`
+	WaitSeconds   float64 `json:"wait_seconds"`
+}
+
+// ProjectBudgetReport describes a Jira project's AI spend in the
+// current UTC day and month. Deferred counts tickets deferred for lack
+// of budget since the bot started.
+type ProjectBudgetReport struct {
+	Project    string  `json:"project"`
+	DailyUSD   float64 `json:"daily_usd"`
+	MonthlyUSD float64 `json:"monthly_usd"`
+	Deferred   int64   `json:"deferred"`
+}
+
+// BreakerReport describes the circuit breaker in front of an external
+// service. RetryAt is set while the breaker is open; Trips counts the
+// times it opened since the bot started.
+type BreakerReport struct {
+	Name            string     `json:"name"`
+	State           string     `json:"state"`
+	Failures        int        `json:"consecutive_failures"`
+	CooldownSeconds float64    `json:"cooldown_seconds"`
+	RetryAt         *time.Time `json:"retry_at,omitempty"`
+	Trips           int64      `json:"trips"`
+}
+
+// Report is the JSON body returned by both endpoints.
+type Report struct {
+	Status     string                `json:"status"`
+	Checks     []CheckResult         `json:"checks,omitempty"`
+	Disk       *DiskReport           `json:"disk,omitempty"`
+	Scanners   []ScannerReport       `json:"scanners"`
+	Queue      *QueueReport          `json:"queue,omitempty"`
+	AISessions *AISessionReport      `json:"ai_sessions,omitempty"`
+	Budgets    []ProjectBudgetReport `json:"budgets,omitempty"`
+	Breakers   []BreakerReport       `json:"circuit_breakers,omitempty"`
+}
+
+// Option configures optional behavior on a [Checker].
+type Option func(*Checker)
+
+// WithProbe registers a named dependency probe that runs on each
+// readiness request.
+func WithProbe(name string, p Probe) Option {
+	return func(c *Checker) {
+		if p != nil {
+			c.probes[name] = p
+		}
+	}
+}
+
+// WithScanner registers a named scanner whose last-run timestamp is
+// reported by both endpoints.
+func WithScanner(name string, s ScanReporter) Option {
+	return func(c *Checker) {
+		if s != nil {
+			c.scanners[name] = s
+		}
+	}
+}
+
+// WithAILimiter reports the AI session limiter's running and waiting
+// sessions in both endpoints.
+func WithAILimiter(r AILimitReporter) Option {
+	return func(c *Checker) {
+		c.aiLimits = r
+	}
+}
+
+// WithProjectBudgets reports each project's AI spend and budget
+// deferrals in both endpoints.
+func WithProjectBudgets(r ProjectBudgetReporter) Option {
+	return func(c *Checker) {
+		c.budgets = r
+	}
+}
+
+// WithCircuitBreaker reports the state of a named circuit breaker in
+// both endpoints. An open breaker does not fail either endpoint; the
+// service's probe reports the outage to readiness.
+func WithCircuitBreaker(name string, b BreakerReporter) Option {
+	return func(c *Checker) {
+		if b != nil {
+			c.breakers[name] = b
+		}
+	}
+}
+
+// Checker aggregates health information and serves it over HTTP.
+type Checker struct {
+	cfg        Config
+	staleAfter atomic.Int64 // ScannerStaleAfter, updated on reload
+	queue      QueueReporter
+	aiLimits   AILimitReporter
+	budgets    ProjectBudgetReporter
+	probes     map[string]Probe
+	scanners   map[string]ScanReporter
+	breakers   map[string]BreakerReporter
+	logger     *zap.Logger
+}
+
+// NewChecker creates a Checker. The queue reporter is optional; pass
+// nil to omit queue depth from reports. Returns an error if any
+// required parameter is invalid.
+func NewChecker(cfg Config, queue QueueReporter, logger *zap.Logger, opts ...Option) (*Checker, error) {
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if cfg.ScannerStaleAfter < 0 {
+		return nil, errors.New("scanner stale threshold must not be negative")
+	}
+	if cfg.ProbeTimeout <= 0 {
+		cfg.ProbeTimeout = defaultProbeTimeout
+	}
+	if cfg.DiskUsage == nil {
+		cfg.DiskUsage = statfsUsage
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
+	c := &Checker{
+		cfg:      cfg,
+		queue:    queue,
+		probes:   make(map[string]Probe),
+		scanners: make(map[string]ScanReporter),
+		breakers: make(map[string]BreakerReporter),
+		logger:   logger,
+	}
+	c.staleAfter.Store(int64(cfg.ScannerStaleAfter))
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// SetScannerStaleAfter replaces [Config.ScannerStaleAfter], e.g. after
+// a configuration reload changed the scanners' poll intervals. It is
+// safe to call while reports are being served. Negative values are
+// ignored.
+func (c *Checker) SetScannerStaleAfter(d time.Duration) {
+	if d >= 0 {
+		c.staleAfter.Store(int64(d))
+	}
+}
+
+// Liveness builds the liveness report. Only scanner staleness can
+// cause it to fail.
+func (c *Checker) Liveness() Report {
+	report := Report{
+		Status:     StatusOK,
+		Scanners:   c.scannerReports(false),
+		Queue:      c.queueReport(),
+		AISessions: c.aiSessionReport(),
+		Budgets:    c.budgetReports(),
+		Breakers:   c.breakerReports(),
+	}
+	for _, s := range report.Scanners {
+		if s.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// Readiness builds the readiness report, running all probes
+// concurrently.
+func (c *Checker) Readiness(ctx context.Context) Report {
+	report := Report{
+		Status:     StatusOK,
+		Checks:     c.runProbes(ctx),
+		Disk:       c.diskReport(),
+		Scanners:   c.scannerReports(true),
+		Queue:      c.queueReport(),
+		AISessions: c.aiSessionReport(),
+		Budgets:    c.budgetReports(),
+		Breakers:   c.breakerReports(),
+	}
+
+	for _, r := range report.Checks {
+		if r.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	if report.Disk != nil && report.Disk.Status != StatusOK {
+		report.Status = StatusFail
+	}
+	for _, s := range report.Scanners {
+		if s.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// LivenessHandler serves the liveness report.
+func (c *Checker) LivenessHandler(w http.ResponseWriter, _ *http.Request) {
+	c.writeReport(w, c.Liveness())
+}
+
+// ReadinessHandler serves the readiness report.
+func (c *Checker) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	c.writeReport(w, c.Readiness(r.Context()))
+}
+
+func (c *Checker) writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		c.logger.Error("Failed to write health report", zap.Error(err))
+	}
+}
+
+// runProbes executes all probes concurrently with a per-probe
+// timeout and returns results sorted by name.
+func (c *Checker) runProbes(ctx context.Context) []CheckResult {
+	names := make([]string, 0, len(c.probes))
+	for name := range c.probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.runProbe(ctx, name, c.probes[name])
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *Checker) runProbe(ctx context.Context, name string, p Probe) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ProbeTimeout)
+	defer cancel()
+
+	start := c.cfg.Clock()
+	errCh := make(chan error, 1)
+	go func() { errCh <- p(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("probe timed out after %s", c.cfg.ProbeTimeout)
+	}
+
+	result := CheckResult{
+		Name:      name,
+		Status:    StatusOK,
+		LatencyMS: c.cfg.Clock().Sub(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+		c.logger.Warn("Health probe failed",
+			zap.String("probe", name),
+			zap.Error(err))
+	}
+	return result
+}
+
+func (c *Checker) diskReport() *DiskReport {
+	if c.cfg.WorkspaceDir == "" {
+		return nil
+	}
+
+	report := &DiskReport{
+		Status:       StatusOK,
+		Path:         c.cfg.WorkspaceDir,
+		MinFreeBytes: c.cfg.MinFreeDiskBytes,
+	}
+
+	free, total, err := c.cfg.DiskUsage(c.cfg.WorkspaceDir)
+	if err != nil {
+		report.Status = StatusFail
+		report.Error = err.Error()
+		return report
+	}
+	report.FreeBytes = free
+	report.TotalBytes = total
+
+	if c.cfg.MinFreeDiskBytes > 0 && free < c.cfg.MinFreeDiskBytes {
+		report.Status = StatusFail
+		report.Error = fmt.Sprintf("free space %d bytes is below minimum %d bytes", free, c.cfg.MinFreeDiskBytes)
+	}
+	return report
+}
+
+// scannerReports returns per-scanner status sorted by name. When
+// requireScan is true, a scanner that has never completed a cycle is
+// reported as failing.
+func (c *Checker) scannerReports(requireScan bool) []ScannerReport {
+	names := make([]string, 0, len(c.scanners))
+	for name := range c.scanners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := c.cfg.Clock()
+	staleAfter := time.Duration(c.staleAfter.Load())
+	reports := make([]ScannerReport, 0, len(names))
+	for _, name := range names {
+		r := ScannerReport{Name: name, Status: StatusOK}
+		last := c.scanners[name].LastScan()
+		if !last.IsZero() {
+			r.LastScan = &last
+		}
+		alive, what := last, "scan"
+		if hb, ok := c.scanners[name].(HeartbeatReporter); ok {
+			alive, what = hb.LastHeartbeat(), "heartbeat"
+			if !alive.IsZero() {
+				r.LastHeartbeat = &alive
+			}
+		}
+
+		switch {
+		case alive.IsZero():
+			if requireScan {
+				r.Status = StatusFail
+				r.Error = "no scan cycle completed yet"
+			}
+		case staleAfter > 0 && now.Sub(alive) > staleAfter:
+			r.Status = StatusFail
+			r.Error = fmt.Sprintf("last %s %s ago exceeds %s", what, now.Sub(alive).Round(time.Second), staleAfter)
+		}
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+func (c *Checker) queueReport() *QueueReport {
+	if c.queue == nil {
+		return nil
+	}
+	stats := c.queue.Stats()
+	queued := make([]QueuedJobReport, 0, len(stats.Queue))
+	for _, q := range stats.Queue {
+		r := QueuedJobReport{
+			Ticket:      q.TicketKey,
+			Type:        string(q.Type),
+			Priority:    q.Priority,
+			SubmittedAt: q.SubmittedAt,
+		}
+		if !q.TicketCreated.IsZero() {
+			created := q.TicketCreated
+			r.TicketCreated = &created
+		}
+		queued = append(queued, r)
+	}
+	return &QueueReport{
+		Pending:       stats.Pending,
+		Running:       stats.Running,
+		MaxConcurrent: stats.MaxConcurrent,
+		CircuitOpen:   stats.CircuitOpen,
+		Queued:        queued,
+	}
+}
+
+func (c *Checker) aiSessionReport() *AISessionReport {
+	if c.aiLimits == nil {
+		return nil
+	}
+	stats := c.aiLimits.Stats()
+	providers := make([]AIProviderReport, 0, len(stats.Providers))
+	for _, p := range stats.Providers {
+		r := AIProviderReport{
+			Provider:      p.Provider,
+			Running:       p.Running,
+			Waiting:       p.Waiting,
+			MaxConcurrent: p.MaxConcurrent,
+			Started:       p.Started,
+			Delayed:       p.Delayed,
+			WaitSeconds:   p.TotalWait.Seconds(),
+		}
+		if p.MinInterval > 0 {
+			r.PerMinute = float64(time.Minute) / float64(p.MinInterval)
+		}
+		providers = append(providers, r)
+	}
+	return &AISessionReport{
+		Running:       stats.Running,
+		Waiting:       stats.Waiting,
+		MaxConcurrent: stats.MaxConcurrent,
+		Providers:     providers,
+	}
+}
+
+func (c *Checker) budgetReports() []ProjectBudgetReport {
+	if c.budgets == nil {
+		return nil
+	}
+	stats := c.budgets.Stats()
+	reports := make([]ProjectBudgetReport, 0, len(stats))
+	for _, s := range stats {
+		reports = append(reports, ProjectBudgetReport{
+			Project:    s.Project,
+			DailyUSD:   s.DailyUSD,
+			MonthlyUSD: s.MonthlyUSD,
+			Deferred:   s.Deferred,
+		})
+	}
+	return reports
+}
+
+func (c *Checker) breakerReports() []BreakerReport {
+	if len(c.breakers) == 0 {
+		return nil
+	}
+	reports := make([]BreakerReport, 0, len(c.breakers))
+	for name, b := range c.breakers {
+		stats := b.Stats()
+		r := BreakerReport{
+			Name:            name,
+			State:           stats.State,
+			Failures:        stats.Failures,
+			CooldownSeconds: stats.Cooldown.Seconds(),
+			Trips:           stats.Trips,
+		}
+		if !stats.RetryAt.IsZero() {
+			retryAt := stats.RetryAt
+			r.RetryAt = &retryAt
+		}
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+	return reports
+}
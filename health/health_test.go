@@ -0,0 +1,387 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/ailimit"
+	"jira-ai-issue-solver/circuit"
+	"jira-ai-issue-solver/costtracker"
+	"jira-ai-issue-solver/health"
+	"jira-ai-issue-solver/jobmanager"
+)
+
+var testNow = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type stubScanner struct{ last time.Time }
+
+func (s stubScanner) LastScan() time.Time { return s.last }
+
+type stubCronScanner struct{ last, heartbeat time.Time }
+
+func (s stubCronScanner) LastScan() time.Time      { return s.last }
+func (s stubCronScanner) LastHeartbeat() time.Time { return s.heartbeat }
+
+type stubQueue struct{ stats jobmanager.Stats }
+
+func (q stubQueue) Stats() jobmanager.Stats { return q.stats }
+
+type stubAILimits struct{ stats ailimit.Stats }
+
+func (s stubAILimits) Stats() ailimit.Stats { return s.stats }
+
+type stubBudgets struct{ stats []costtracker.ProjectSpend }
+
+func (s stubBudgets) Stats() []costtracker.ProjectSpend { return s.stats }
+
+type stubBreaker struct{ stats circuit.Stats }
+
+func (s stubBreaker) Stats() circuit.Stats { return s.stats }
+
+func newChecker(t *testing.T, cfg health.Config, queue health.QueueReporter, opts ...health.Option) *health.Checker {
+	t.Helper()
+	if cfg.Clock == nil {
+		cfg.Clock = func() time.Time { return testNow }
+	}
+	if cfg.DiskUsage == nil {
+		cfg.DiskUsage = func(string) (uint64, uint64, error) { return 10 << 30, 100 << 30, nil }
+	}
+	c, err := health.NewChecker(cfg, queue, zap.NewNop(), opts...)
+	if err != nil {
+		t.Fatalf("NewChecker: %v", err)
+	}
+	return c
+}
+
+func TestNewChecker_RejectsNilLogger(t *testing.T) {
+	_, err := health.NewChecker(health.Config{}, nil, nil)
+	if err == nil {
+		t.Fatal("expected error for nil logger")
+	}
+}
+
+func TestNewChecker_RejectsNegativeStaleThreshold(t *testing.T) {
+	_, err := health.NewChecker(health.Config{ScannerStaleAfter: -time.Second}, nil, zap.NewNop())
+	if err == nil {
+		t.Fatal("expected error for negative stale threshold")
+	}
+}
+
+func TestReadiness_AllHealthy(t *testing.T) {
+	c := newChecker(t,
+		health.Config{WorkspaceDir: "/ws", MinFreeDiskBytes: 1 << 30, ScannerStaleAfter: time.Minute},
+		stubQueue{stats: jobmanager.Stats{Pending: 3, Running: 2, MaxConcurrent: 5}},
+		health.WithProbe("jira", func(context.Context) error { return nil }),
+		health.WithProbe("github", func(context.Context) error { return nil }),
+		health.WithScanner("work_item", stubScanner{last: testNow.Add(-10 * time.Second)}),
+	)
+
+	report := c.Readiness(context.Background())
+
+	if report.Status != health.StatusOK {
+		t.Fatalf("Status = %q, want ok: %+v", report.Status, report)
+	}
+	if len(report.Checks) != 2 || report.Checks[0].Name != "github" || report.Checks[1].Name != "jira" {
+		t.Errorf("Checks = %+v, want github then jira", report.Checks)
+	}
+	if report.Disk == nil || report.Disk.FreeBytes != 10<<30 {
+		t.Errorf("Disk = %+v, want 10GiB free", report.Disk)
+	}
+	if report.Queue == nil || report.Queue.Pending != 3 || report.Queue.Running != 2 {
+		t.Errorf("Queue = %+v, want pending=3 running=2", report.Queue)
+	}
+	if len(report.Scanners) != 1 || report.Scanners[0].LastScan == nil {
+		t.Errorf("Scanners = %+v, want one scanner with last_scan", report.Scanners)
+	}
+}
+
+func TestReadiness_FailingProbe(t *testing.T) {
+	c := newChecker(t, health.Config{}, nil,
+		health.WithProbe("jira", func(context.Context) error { return errors.New("connection refused") }),
+	)
+
+	report := c.Readiness(context.Background())
+
+	if report.Status != health.StatusFail {
+		t.Fatalf("Status = %q, want fail", report.Status)
+	}
+	if report.Checks[0].Error != "connection refused" {
+		t.Errorf("Error = %q, want connection refused", report.Checks[0].Error)
+	}
+}
+
+func TestReadiness_ProbeTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	c, err := health.NewChecker(health.Config{ProbeTimeout: 10 * time.Millisecond}, nil, zap.NewNop(),
+		health.WithProbe("slow", func(context.Context) error {
+			<-block
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := c.Readiness(context.Background())
+
+	if report.Checks[0].Status != health.StatusFail {
+		t.Errorf("slow probe Status = %q, want fail", report.Checks[0].Status)
+	}
+}
+
+func TestReadiness_LowDiskSpace(t *testing.T) {
+	c := newChecker(t,
+		health.Config{
+			WorkspaceDir:     "/ws",
+			MinFreeDiskBytes: 1 << 30,
+			DiskUsage:        func(string) (uint64, uint64, error) { return 100 << 20, 100 << 30, nil },
+		},
+		nil)
+
+	report := c.Readiness(context.Background())
+
+	if report.Status != health.StatusFail {
+		t.Fatalf("Status = %q, want fail", report.Status)
+	}
+	if report.Disk.Status != health.StatusFail {
+		t.Errorf("Disk.Status = %q, want fail", report.Disk.Status)
+	}
+}
+
+func TestReadiness_ScannerNeverRan(t *testing.T) {
+	c := newChecker(t, health.Config{}, nil,
+		health.WithScanner("feedback", stubScanner{}),
+	)
+
+	report := c.Readiness(context.Background())
+
+	if report.Status != health.StatusFail {
+		t.Fatalf("Status = %q, want fail", report.Status)
+	}
+	if report.Scanners[0].LastScan != nil {
+		t.Errorf("LastScan = %v, want nil", report.Scanners[0].LastScan)
+	}
+}
+
+func TestLiveness_IgnoresProbesAndUnstartedScanners(t *testing.T) {
+	c := newChecker(t, health.Config{WorkspaceDir: "/ws", MinFreeDiskBytes: 1 << 40}, nil,
+		health.WithProbe("jira", func(context.Context) error { return errors.New("down") }),
+		health.WithScanner("feedback", stubScanner{}),
+	)
+
+	report := c.Liveness()
+
+	if report.Status != health.StatusOK {
+		t.Errorf("Status = %q, want ok", report.Status)
+	}
+	if report.Checks != nil || report.Disk != nil {
+		t.Errorf("liveness should not include probes or disk: %+v", report)
+	}
+}
+
+func TestLiveness_StaleScannerFails(t *testing.T) {
+	c := newChecker(t, health.Config{ScannerStaleAfter: 5 * time.Minute}, nil,
+		health.WithScanner("work_item", stubScanner{last: testNow.Add(-time.Hour)}),
+	)
+
+	report := c.Liveness()
+
+	if report.Status != health.StatusFail {
+		t.Errorf("Status = %q, want fail", report.Status)
+	}
+}
+
+func TestLiveness_SetScannerStaleAfter(t *testing.T) {
+	// A reload to a 10-minute interval makes the scanner tick every
+	// 10 minutes; the old 3-minute window would report it as stale.
+	c := newChecker(t, health.Config{ScannerStaleAfter: 3 * time.Minute}, nil,
+		health.WithScanner("merge", stubScanner{last: testNow.Add(-5 * time.Minute)}),
+	)
+	if report := c.Liveness(); report.Status != health.StatusFail {
+		t.Fatalf("Status = %q before the reload, want fail", report.Status)
+	}
+
+	c.SetScannerStaleAfter(30 * time.Minute)
+
+	if report := c.Liveness(); report.Status != health.StatusOK {
+		t.Errorf("Status = %q after the reload, want ok: %+v", report.Status, report.Scanners)
+	}
+}
+
+func TestLiveness_CronScannerJudgedByHeartbeat(t *testing.T) {
+	lastScan := testNow.Add(-24 * time.Hour)
+	tests := []struct {
+		name       string
+		heartbeat  time.Time
+		wantStatus string
+	}{
+		{name: "waiting for schedule", heartbeat: testNow.Add(-time.Minute), wantStatus: health.StatusOK},
+		{name: "wedged", heartbeat: testNow.Add(-time.Hour), wantStatus: health.StatusFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newChecker(t, health.Config{ScannerStaleAfter: 5 * time.Minute}, nil,
+				health.WithScanner("work_item", stubCronScanner{last: lastScan, heartbeat: tt.heartbeat}),
+			)
+
+			report := c.Liveness()
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", report.Status, tt.wantStatus)
+			}
+			r := report.Scanners[0]
+			if r.LastScan == nil || !r.LastScan.Equal(lastScan) {
+				t.Errorf("LastScan = %v, want %v", r.LastScan, lastScan)
+			}
+			if r.LastHeartbeat == nil || !r.LastHeartbeat.Equal(tt.heartbeat) {
+				t.Errorf("LastHeartbeat = %v, want %v", r.LastHeartbeat, tt.heartbeat)
+			}
+		})
+	}
+}
+
+func TestLiveness_ReportsQueueOrder(t *testing.T) {
+	created := testNow.Add(-48 * time.Hour)
+	c := newChecker(t, health.Config{}, stubQueue{stats: jobmanager.Stats{
+		Pending: 2,
+		Queue: []jobmanager.QueuedJob{
+			{TicketKey: "PROJ-2", Type: jobmanager.JobTypeNewTicket, Priority: 2, TicketCreated: created, SubmittedAt: testNow},
+			{TicketKey: "PROJ-1", Type: jobmanager.JobTypeFeedback, SubmittedAt: testNow},
+		},
+	}})
+
+	report := c.Liveness()
+
+	if report.Queue == nil || len(report.Queue.Queued) != 2 {
+		t.Fatalf("Queue = %+v, want two queued jobs", report.Queue)
+	}
+	first, second := report.Queue.Queued[0], report.Queue.Queued[1]
+	if first.Ticket != "PROJ-2" || first.Priority != 2 || first.TicketCreated == nil || !first.TicketCreated.Equal(created) {
+		t.Errorf("Queued[0] = %+v, want PROJ-2 priority 2 created %v", first, created)
+	}
+	if second.Ticket != "PROJ-1" || second.Type != "feedback" || second.TicketCreated != nil {
+		t.Errorf("Queued[1] = %+v, want PROJ-1 feedback without ticket_created", second)
+	}
+}
+
+func TestLiveness_ReportsAISessions(t *testing.T) {
+	c := newChecker(t, health.Config{}, nil, health.WithAILimiter(stubAILimits{stats: ailimit.Stats{
+		Running:       2,
+		Waiting:       1,
+		MaxConcurrent: 2,
+		Providers: []ailimit.ProviderStats{{
+			Provider:      "claude",
+			Running:       2,
+			Waiting:       1,
+			MaxConcurrent: 2,
+			MinInterval:   10 * time.Second,
+			Started:       7,
+			Delayed:       3,
+			TotalWait:     90 * time.Second,
+		}},
+	}}))
+
+	report := c.Liveness()
+
+	ai := report.AISessions
+	if ai == nil || ai.Running != 2 || ai.Waiting != 1 || ai.MaxConcurrent != 2 || len(ai.Providers) != 1 {
+		t.Fatalf("AISessions = %+v, want 2 running, 1 waiting, one provider", ai)
+	}
+	want := health.AIProviderReport{
+		Provider: "claude", Running: 2, Waiting: 1, MaxConcurrent: 2,
+		PerMinute: 6, Started: 7, Delayed: 3, WaitSeconds: 90,
+	}
+	if ai.Providers[0] != want {
+		t.Errorf("Providers[0] = %+v, want %+v", ai.Providers[0], want)
+	}
+	if newChecker(t, health.Config{}, nil).Liveness().AISessions != nil {
+		t.Error("AISessions reported without a limiter")
+	}
+}
+
+func TestLiveness_ReportsProjectBudgets(t *testing.T) {
+	c := newChecker(t, health.Config{}, nil, health.WithProjectBudgets(stubBudgets{stats: []costtracker.ProjectSpend{
+		{Project: "OPS", Deferred: 2},
+		{Project: "PROJ", DailyUSD: 12.5, MonthlyUSD: 80},
+	}}))
+
+	budgets := c.Liveness().Budgets
+
+	want := []health.ProjectBudgetReport{
+		{Project: "OPS", Deferred: 2},
+		{Project: "PROJ", DailyUSD: 12.5, MonthlyUSD: 80},
+	}
+	if len(budgets) != len(want) || budgets[0] != want[0] || budgets[1] != want[1] {
+		t.Errorf("Budgets = %+v, want %+v", budgets, want)
+	}
+	if newChecker(t, health.Config{}, nil).Liveness().Budgets != nil {
+		t.Error("Budgets reported without a tracker")
+	}
+}
+
+func TestLiveness_ReportsOpenBreakerWithoutFailing(t *testing.T) {
+	retryAt := testNow.Add(time.Minute)
+	c := newChecker(t, health.Config{}, nil, health.WithCircuitBreaker("jira", stubBreaker{stats: circuit.Stats{
+		State:    circuit.StateOpen,
+		Failures: 5,
+		Cooldown: time.Minute,
+		RetryAt:  retryAt,
+		Trips:    2,
+	}}))
+
+	report := c.Liveness()
+
+	if report.Status != health.StatusOK {
+		t.Errorf("Status = %s, want %s", report.Status, health.StatusOK)
+	}
+	if len(report.Breakers) != 1 {
+		t.Fatalf("Breakers = %+v, want one", report.Breakers)
+	}
+	b := report.Breakers[0]
+	if b.Name != "jira" || b.State != circuit.StateOpen || b.Failures != 5 || b.CooldownSeconds != 60 ||
+		b.Trips != 2 || b.RetryAt == nil || !b.RetryAt.Equal(retryAt) {
+		t.Errorf("Breakers[0] = %+v, want the open jira breaker", b)
+	}
+}
+
+func TestHandlers_StatusCodesAndJSON(t *testing.T) {
+	failing := newChecker(t, health.Config{}, nil,
+		health.WithProbe("jira", func(context.Context) error { return errors.New("down") }),
+	)
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		wantCode int
+	}{
+		{name: "liveness ok", handler: failing.LivenessHandler, wantCode: http.StatusOK},
+		{name: "readiness fail", handler: failing.ReadinessHandler, wantCode: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			var report health.Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package history keeps a compact log of what the bot did on each
+// ticket in a single Jira comment, which it edits as work progresses
+// instead of posting a comment per action.
+//
+// The [Recorder] subscribes to the pipeline's [events.Bus]; each
+// event it understands (work started, workspace prepared, AI session
+// completed, PR opened, feedback applied) becomes one timestamped
+// line in the ticket's [Marker] comment. Only the newest
+// [MaxEntries] lines are kept, so the comment stays readable on
+// long-lived tickets.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/events"
+	"jira-ai-issue-solver/models"
+)
+
+// Marker identifies the history comment among a ticket's comments.
+const Marker = "[AI-BOT-HISTORY]"
+
+// MaxEntries is the number of entries the history comment keeps; older
+// ones are dropped as new ones are added.
+const MaxEntries = 30
+
+// header is the history comment's first line.
+const header = Marker + " Processing history"
+
+// timeLayout formats entry timestamps.
+const timeLayout = "2006-01-02 15:04 UTC"
+
+// Tracker is the subset of the issue tracker the recorder uses.
+// Satisfied by tracker.IssueTracker.
+type Tracker interface {
+	GetComments(key string) ([]models.Comment, error)
+	AddComment(key, body string) error
+	UpdateComment(key, commentID, body string) error
+}
+
+// Recorder writes events to their ticket's history comment.
+type Recorder struct {
+	tracker Tracker
+	logger  *zap.Logger
+}
+
+// NewRecorder creates a Recorder that writes through tracker.
+func NewRecorder(tracker Tracker, logger *zap.Logger) (*Recorder, error) {
+	if tracker == nil {
+		return nil, errors.New("tracker must not be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	return &Recorder{tracker: tracker, logger: logger}, nil
+}
+
+// Handle adds e to its ticket's history comment. Matches
+// [events.Handler]. Events without a ticket or of types the history
+// does not record are ignored; tracker errors are logged.
+func (r *Recorder) Handle(e events.Event) {
+	entry := formatEntry(e)
+	if entry == "" || e.TicketKey == "" {
+		return
+	}
+	logger := r.logger.With(zap.String("ticket", e.TicketKey), zap.String("event", string(e.Type)))
+
+	comments, err := r.tracker.GetComments(e.TicketKey)
+	if err != nil {
+		logger.Warn("Failed to fetch comments for history", zap.Error(err))
+		return
+	}
+
+	existing := findComment(comments)
+	if existing == nil {
+		if err := r.tracker.AddComment(e.TicketKey, render([]string{entry})); err != nil {
+			logger.Warn("Failed to post history comment", zap.Error(err))
+		}
+		return
+	}
+
+	entries := append(parseEntries(existing.Body), entry)
+	if err := r.tracker.UpdateComment(e.TicketKey, existing.ID, render(entries)); err != nil {
+		logger.Warn("Failed to update history comment", zap.Error(err))
+	}
+}
+
+// formatEntry returns the history line for e, or "" for events the
+// history does not record.
+func formatEntry(e events.Event) string {
+	var what string
+	switch e.Type {
+	case events.TicketStarted:
+		what = fmt.Sprintf("Started work (attempt %d)", e.Attempt)
+	case events.WorkspaceReady:
+		what = "Cloned the repository"
+		if e.Reused {
+			what = "Reused the existing workspace"
+		}
+	case events.AICompleted:
+		what = fmt.Sprintf("AI session finished (%s, exit code %d, $%.2f)", e.Provider, e.ExitCode, e.CostUSD)
+	case events.PRCreated:
+		what = fmt.Sprintf("Opened PR %s", e.PRURL)
+	case events.FeedbackApplied:
+		what = fmt.Sprintf("Pushed %s addressing %s on %s", shortSHA(e.CommitSHA), plural(e.Comments, "comment"), e.PRURL)
+	default:
+		return ""
+	}
+	return e.Time.UTC().Format(timeLayout) + " · " + what
+}
+
+// render returns the history comment body for entries, keeping the
+// newest MaxEntries.
+func render(entries []string) string {
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	return header + "\n\n" + strings.Join(entries, "\n")
+}
+
+// parseEntries returns the entries of a history comment body: its
+// non-empty lines after the header. Jira may return the body with
+// paragraphs joined by single or double newlines; both parse alike.
+func parseEntries(body string) []string {
+	var entries []string
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, Marker) {
+			entries = append(entries, line)
+		}
+	}
+	return entries
+}
+
+// findComment returns the first comment carrying the history marker,
+// or nil if none exists.
+func findComment(comments []models.Comment) *models.Comment {
+	for i := range comments {
+		if strings.HasPrefix(strings.TrimSpace(comments[i].Body), Marker) {
+			return &comments[i]
+		}
+	}
+	return nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
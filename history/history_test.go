@@ -0,0 +1,113 @@
+package history_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/events"
+	"jira-ai-issue-solver/history"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/tracker/trackertest"
+)
+
+// commentStore backs a trackertest.Stub with an in-memory comment
+// list for one ticket.
+func commentStore(comments *[]models.Comment) *trackertest.Stub {
+	return &trackertest.Stub{
+		GetCommentsFunc: func(string) ([]models.Comment, error) {
+			return *comments, nil
+		},
+		AddCommentFunc: func(_, body string) error {
+			*comments = append(*comments, models.Comment{ID: fmt.Sprint(len(*comments) + 1), Body: body})
+			return nil
+		},
+		UpdateCommentFunc: func(_, id, body string) error {
+			for i := range *comments {
+				if (*comments)[i].ID == id {
+					(*comments)[i].Body = body
+					return nil
+				}
+			}
+			return errors.New("comment not found")
+		},
+	}
+}
+
+func newRecorder(t *testing.T, tracker history.Tracker) *history.Recorder {
+	t.Helper()
+	r, err := history.NewRecorder(tracker, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	return r
+}
+
+func TestRecorder_KeepsOneEditedComment(t *testing.T) {
+	comments := []models.Comment{{ID: "c0", Body: "Please also update the docs."}}
+	r := newRecorder(t, commentStore(&comments))
+	at := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	for _, e := range []events.Event{
+		{Type: events.TicketStarted, Attempt: 1},
+		{Type: events.WorkspaceReady},
+		{Type: events.AICompleted, Provider: "claude", CostUSD: 0.42},
+		{Type: events.PRCreated, PRURL: "https://github.com/org/repo/pull/7"},
+		{Type: events.FeedbackApplied, PRURL: "https://github.com/org/repo/pull/7", CommitSHA: "0123456789abcdef", Comments: 2},
+		{Type: "job.unknown"},
+	} {
+		e.TicketKey = "PROJ-1"
+		e.Time = at
+		r.Handle(e)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("ticket has %d comments, want the original plus one history comment", len(comments))
+	}
+	want := strings.Join([]string{
+		history.Marker + " Processing history",
+		"",
+		"2026-03-14 09:30 UTC · Started work (attempt 1)",
+		"2026-03-14 09:30 UTC · Cloned the repository",
+		"2026-03-14 09:30 UTC · AI session finished (claude, exit code 0, $0.42)",
+		"2026-03-14 09:30 UTC · Opened PR https://github.com/org/repo/pull/7",
+		"2026-03-14 09:30 UTC · Pushed 0123456 addressing 2 comments on https://github.com/org/repo/pull/7",
+	}, "\n")
+	if got := comments[1].Body; got != want {
+		t.Errorf("history comment =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRecorder_TrimsOldEntries(t *testing.T) {
+	var comments []models.Comment
+	r := newRecorder(t, commentStore(&comments))
+	start := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	for i := range history.MaxEntries + 5 {
+		r.Handle(events.Event{Type: events.TicketStarted, TicketKey: "PROJ-1", Attempt: i + 1, Time: start})
+	}
+
+	lines := strings.Split(comments[0].Body, "\n")
+	entries := lines[2:]
+	if len(entries) != history.MaxEntries {
+		t.Fatalf("history has %d entries, want %d", len(entries), history.MaxEntries)
+	}
+	if !strings.HasSuffix(entries[0], "(attempt 6)") || !strings.HasSuffix(entries[len(entries)-1], fmt.Sprintf("(attempt %d)", history.MaxEntries+5)) {
+		t.Errorf("kept entries %q .. %q, want the newest", entries[0], entries[len(entries)-1])
+	}
+}
+
+func TestRecorder_ParsesSingleNewlineBodies(t *testing.T) {
+	comments := []models.Comment{{ID: "h", Body: history.Marker + " Processing history\n2026-03-14 09:30 UTC · Started work (attempt 1)"}}
+	r := newRecorder(t, commentStore(&comments))
+
+	r.Handle(events.Event{Type: events.TicketStarted, TicketKey: "PROJ-1", Attempt: 2, Time: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)})
+
+	if got := strings.Count(comments[0].Body, "Started work"); got != 2 {
+		t.Errorf("history comment =\n%s\nwant both entries", comments[0].Body)
+	}
+}
@@ -0,0 +1,204 @@
+// Package httpcache makes repeated GET requests conditional. Responses
+// carrying an ETag or Last-Modified validator are kept, and the next
+// request for the same resource sends them back (If-None-Match,
+// If-Modified-Since). When the server answers 304 Not Modified, the
+// kept response is returned in its place. GitHub does not count 304
+// responses against the API rate limit, so the scanners' repeated
+// polling of unchanged PRs and comments becomes nearly free.
+//
+// Unlike a general HTTP cache, a kept response is never served
+// without asking the server: every request is still sent, so results
+// are never stale.
+package httpcache
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FromCacheHeader is set on responses rebuilt from the cache after a
+// 304 Not Modified answer.
+const FromCacheHeader = "X-From-Cache"
+
+// Cache holds validated GET responses in memory, evicting the least
+// recently used ones beyond a size limit. It is safe for concurrent
+// use and may back several transports.
+type Cache struct {
+	maxBytes      int64
+	maxEntryBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List // of *entry, most recently used first
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key    string
+	header http.Header
+	body   []byte
+}
+
+func (e *entry) size() int64 {
+	return int64(len(e.key) + len(e.body))
+}
+
+// New returns a Cache holding up to maxBytes of response bodies. A
+// single response larger than a sixteenth of maxBytes is not kept.
+func New(maxBytes int64) *Cache {
+	return &Cache{
+		maxBytes:      maxBytes,
+		maxEntryBytes: maxBytes / 16,
+		order:         list.New(),
+		entries:       make(map[string]*list.Element),
+	}
+}
+
+// Transport returns a RoundTripper that sends requests through next,
+// making GET requests conditional with the responses kept in c.
+// namespace separates the entries of transports whose responses
+// differ for the same URL, such as ones authenticating as different
+// identities; authentication added by next is not part of the key.
+func (c *Cache) Transport(next http.RoundTripper, namespace string) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{cache: c, next: next, namespace: namespace}
+}
+
+type transport struct {
+	cache     *Cache
+	next      http.RoundTripper
+	namespace string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheable(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	key := t.namespace + " " + req.Header.Get("Accept") + " " + req.URL.String()
+	cached := t.cache.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if etag := cached.header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		return cached.response(req, resp.Header), nil
+	case resp.StatusCode == http.StatusOK && storable(resp):
+		return t.store(key, resp)
+	default:
+		return resp, nil
+	}
+}
+
+// store keeps resp's body under key, unless it is too large, and
+// returns resp with a body that can still be read in full.
+func (t *transport) store(key string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.cache.maxEntryBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > t.cache.maxEntryBytes {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	t.cache.put(&entry{key: key, header: resp.Header.Clone(), body: body})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// cacheable reports whether req is a plain GET the transport may make
+// conditional. Requests that set their own validators or ask for part
+// of a resource are passed through.
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet &&
+		req.Header.Get("Range") == "" &&
+		req.Header.Get("If-None-Match") == "" &&
+		req.Header.Get("If-Modified-Since") == ""
+}
+
+// storable reports whether resp carries a validator and may be kept.
+func storable(resp *http.Response) bool {
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return false
+	}
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// response rebuilds the kept response for req. Headers of the 304
+// answer (e.g., current rate-limit counters) override the kept ones.
+func (e *entry) response(req *http.Request, fresh http.Header) *http.Response {
+	header := e.header.Clone()
+	for name, values := range fresh {
+		header[name] = values
+	}
+	header.Set("Content-Length", strconv.Itoa(len(e.body)))
+	header.Set(FromCacheHeader, "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func (c *Cache) get(key string) *entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*entry)
+}
+
+func (c *Cache) put(e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[e.key]; ok {
+		c.size -= elem.Value.(*entry).size()
+		c.order.Remove(elem)
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	c.size += e.size()
+
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		old := oldest.Value.(*entry)
+		c.order.Remove(oldest)
+		delete(c.entries, old.key)
+		c.size -= old.size()
+	}
+}
@@ -0,0 +1,152 @@
+package httpcache_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"jira-ai-issue-solver/httpcache"
+)
+
+// newETagServer serves body with an ETag derived from *version, and
+// answers 304 to requests that already have it. Full responses are
+// counted in *full.
+func newETagServer(t *testing.T, body *string, version *atomic.Int32, full *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"v` + string(rune('0'+version.Load())) + `"`
+		w.Header().Set("X-RateLimit-Remaining", r.Header.Get("X-Test-Remaining"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", etag)
+		_, _ = io.WriteString(w, *body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, client *http.Client, url, remaining string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Test-Remaining", remaining)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data)
+}
+
+func TestTransport_RevalidatesWithETag(t *testing.T) {
+	body := "first"
+	var version, full atomic.Int32
+	srv := newETagServer(t, &body, &version, &full)
+	client := &http.Client{Transport: httpcache.New(1<<20).Transport(nil, "")}
+
+	_, got := get(t, client, srv.URL+"/pulls", "10")
+	if got != "first" {
+		t.Fatalf("body = %q, want first", got)
+	}
+
+	resp, got := get(t, client, srv.URL+"/pulls", "9")
+	if resp.StatusCode != http.StatusOK || got != "first" {
+		t.Errorf("cached response = %d %q, want 200 first", resp.StatusCode, got)
+	}
+	if resp.Header.Get(httpcache.FromCacheHeader) != "1" {
+		t.Error("expected the response to be marked as from cache")
+	}
+	// Headers of the 304 answer win over the kept ones.
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "9" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 9", got)
+	}
+	if full.Load() != 1 {
+		t.Errorf("full responses = %d, want 1", full.Load())
+	}
+
+	// A changed resource is fetched again.
+	body = "second"
+	version.Store(1)
+	resp, got = get(t, client, srv.URL+"/pulls", "8")
+	if got != "second" || resp.Header.Get(httpcache.FromCacheHeader) != "" {
+		t.Errorf("body = %q (from cache %q), want a fresh second", got, resp.Header.Get(httpcache.FromCacheHeader))
+	}
+}
+
+func TestTransport_NamespacesAreSeparate(t *testing.T) {
+	body := "data"
+	var version, full atomic.Int32
+	srv := newETagServer(t, &body, &version, &full)
+	cache := httpcache.New(1 << 20)
+
+	get(t, &http.Client{Transport: cache.Transport(nil, "a")}, srv.URL, "")
+	get(t, &http.Client{Transport: cache.Transport(nil, "b")}, srv.URL, "")
+	get(t, &http.Client{Transport: cache.Transport(nil, "a")}, srv.URL, "")
+
+	if full.Load() != 2 {
+		t.Errorf("full responses = %d, want 2 (one per namespace)", full.Load())
+	}
+}
+
+func TestTransport_SkipsNonGETAndLargeResponses(t *testing.T) {
+	var version, full atomic.Int32
+	large := strings.Repeat("x", 1<<10)
+	srv := newETagServer(t, &large, &version, &full)
+	// Entries over 1/16 of the cache size are not kept.
+	client := &http.Client{Transport: httpcache.New(8<<10).Transport(nil, "")}
+
+	for range 2 {
+		if _, got := get(t, client, srv.URL, ""); got != large {
+			t.Fatalf("body length = %d, want %d", len(got), len(large))
+		}
+	}
+	if full.Load() != 2 {
+		t.Errorf("full GET responses = %d, want 2", full.Load())
+	}
+
+	small := "ok"
+	srv = newETagServer(t, &small, &version, &full)
+	full.Store(0)
+	for range 2 {
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("x"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if full.Load() != 2 {
+		t.Errorf("full POST responses = %d, want 2", full.Load())
+	}
+}
+
+func TestTransport_EvictsLeastRecentlyUsed(t *testing.T) {
+	body := strings.Repeat("y", 1000)
+	var version, full atomic.Int32
+	srv := newETagServer(t, &body, &version, &full)
+	// Room for sixteen entries of 1000 bytes plus their keys.
+	client := &http.Client{Transport: httpcache.New(16*1050).Transport(nil, "")}
+
+	for i := range 17 {
+		get(t, client, fmt.Sprintf("%s/%d", srv.URL, i), "") // the last evicts /0
+	}
+	full.Store(0)
+
+	get(t, client, srv.URL+"/16", "")
+	get(t, client, srv.URL+"/0", "")
+	if full.Load() != 1 {
+		t.Errorf("full responses = %d, want 1 (only /0 was evicted)", full.Load())
+	}
+}
@@ -0,0 +1,138 @@
+package httpserver
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ErrNoCredentials is returned by an [Authenticator] when the request
+// carries none of the credentials it checks.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticator checks the credentials of a request.
+type Authenticator interface {
+	// Authenticate returns nil when the request is authenticated.
+	Authenticate(r *http.Request) error
+}
+
+// SecretResolver resolves secret references in configured values.
+type SecretResolver interface {
+	Resolve(value string) string
+}
+
+// RequireAuth returns a handler that passes requests auth accepts to
+// next and answers the rest with 401 Unauthorized. Requests for
+// openPaths are passed without authentication. A nil auth passes
+// every request.
+func RequireAuth(next http.Handler, auth Authenticator, logger *zap.Logger, openPaths ...string) http.Handler {
+	if auth == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(openPaths, r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := auth.Authenticate(r); err != nil {
+			logger.Info("Rejected unauthenticated request",
+				zap.String("request_id", RequestIDFromContext(r.Context())),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.Error(err))
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AnyOf returns an Authenticator accepting requests that any of auths
+// accepts, or nil when auths is empty. The first error other than
+// [ErrNoCredentials] is reported for rejected requests.
+func AnyOf(auths ...Authenticator) Authenticator {
+	switch len(auths) {
+	case 0:
+		return nil
+	case 1:
+		return auths[0]
+	}
+	return anyOf(auths)
+}
+
+type anyOf []Authenticator
+
+func (a anyOf) Authenticate(r *http.Request) error {
+	result := ErrNoCredentials
+	for _, auth := range a {
+		err := auth.Authenticate(r)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(result, ErrNoCredentials) {
+			result = err
+		}
+	}
+	return result
+}
+
+// BearerTokens accepts requests carrying one of a fixed set of tokens
+// in an "Authorization: Bearer" header.
+type BearerTokens struct {
+	tokens  []string
+	secrets SecretResolver
+}
+
+// NewBearerTokens returns a BearerTokens accepting tokens. When
+// secrets is non-nil, each token is resolved through it on every
+// request, so that rotated secrets take effect without a restart.
+func NewBearerTokens(tokens []string, secrets SecretResolver) *BearerTokens {
+	return &BearerTokens{tokens: tokens, secrets: secrets}
+}
+
+// Authenticate implements [Authenticator].
+func (b *BearerTokens) Authenticate(r *http.Request) error {
+	got, ok := bearerToken(r)
+	if !ok {
+		return ErrNoCredentials
+	}
+	for _, token := range b.tokens {
+		if b.secrets != nil {
+			token = b.secrets.Resolve(token)
+		}
+		if token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+			return nil
+		}
+	}
+	return errors.New("invalid bearer token")
+}
+
+// ClientCertificate accepts requests made over TLS with a client
+// certificate that verified against the server's client CAs (see
+// [TLSConfig]).
+type ClientCertificate struct{}
+
+// Authenticate implements [Authenticator].
+func (ClientCertificate) Authenticate(r *http.Request) error {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return ErrNoCredentials
+	}
+	return nil
+}
+
+// bearerToken returns the token of the request's "Authorization:
+// Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,134 @@
+package httpserver_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/httpserver"
+)
+
+type stubResolver map[string]string
+
+func (s stubResolver) Resolve(value string) string {
+	if v, ok := s[value]; ok {
+		return v
+	}
+	return value
+}
+
+func TestListenAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		port    int
+		want    string
+	}{
+		{"", 8080, ":8080"},
+		{"127.0.0.1", 8080, "127.0.0.1:8080"},
+		{"::1", 8443, "[::1]:8443"},
+		{"0.0.0.0:9000", 8080, "0.0.0.0:9000"},
+	}
+	for _, tt := range tests {
+		if got := httpserver.ListenAddress(tt.address, tt.port); got != tt.want {
+			t.Errorf("ListenAddress(%q, %d) = %q, want %q", tt.address, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	auth := httpserver.NewBearerTokens(
+		[]string{"static-token", "${env:ROTATED}"},
+		stubResolver{"${env:ROTATED}": "rotated-token"})
+	handler := httpserver.RequireAuth(next, auth, zap.NewNop(), httpserver.ProbePaths...)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		want          int
+	}{
+		{name: "probe without credentials", path: "/healthz", want: http.StatusNoContent},
+		{name: "no credentials", path: "/status", want: http.StatusUnauthorized},
+		{name: "static token", path: "/status", authorization: "Bearer static-token", want: http.StatusNoContent},
+		{name: "resolved token", path: "/status", authorization: "bearer rotated-token", want: http.StatusNoContent},
+		{name: "unresolved reference", path: "/status", authorization: "Bearer ${env:ROTATED}", want: http.StatusUnauthorized},
+		{name: "wrong token", path: "/status", authorization: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "basic auth", path: "/status", authorization: "Basic c3RhdGljLXRva2Vu", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q, want Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireAuth_NilPassesThrough(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := httpserver.RequireAuth(next, httpserver.AnyOf(), zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	auth := httpserver.AnyOf(
+		httpserver.ClientCertificate{},
+		httpserver.NewBearerTokens([]string{"token"}, nil))
+
+	t.Run("client certificate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+		if err := auth.Authenticate(req); err != nil {
+			t.Errorf("Authenticate = %v, want nil", err)
+		}
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		if err := auth.Authenticate(req); err != nil {
+			t.Errorf("Authenticate = %v, want nil", err)
+		}
+	})
+
+	t.Run("unverified certificate and no token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.TLS = &tls.ConnectionState{}
+		if err := auth.Authenticate(req); !errors.Is(err, httpserver.ErrNoCredentials) {
+			t.Errorf("Authenticate = %v, want ErrNoCredentials", err)
+		}
+	})
+
+	t.Run("wrong token reports the token error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		err := auth.Authenticate(req)
+		if err == nil || errors.Is(err, httpserver.ErrNoCredentials) {
+			t.Errorf("Authenticate = %v, want invalid token error", err)
+		}
+	})
+}
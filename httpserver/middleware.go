@@ -0,0 +1,151 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the header carrying a request's ID. An ID sent
+// by the client (e.g., a proxy in front of the bot) is kept; otherwise
+// one is generated. Either way it is echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs, which end up
+// in every log line of the request.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID returns a handler that assigns each request an ID, stores
+// it in the request context (see [RequestIDFromContext]), and sets it
+// on the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// RequestIDFromContext returns the ID [RequestID] assigned to the
+// request of ctx, or "" outside such a request.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// LogRequests returns a handler that logs each request's method, path,
+// status, and duration once it completes. Requests for quietPaths
+// (e.g., health probes polled every few seconds) are logged at debug
+// level; the rest at info.
+func LogRequests(next http.Handler, logger *zap.Logger, quietPaths ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		log := logger.Info
+		if slices.Contains(quietPaths, r.URL.Path) {
+			log = logger.Debug
+		}
+		log("HTTP request",
+			zap.String("request_id", RequestIDFromContext(r.Context())),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", rec.status()),
+			zap.Int64("bytes", rec.bytes),
+			zap.Duration("duration", time.Since(start)),
+			zap.String("remote_addr", r.RemoteAddr))
+	})
+}
+
+// Recover returns a handler that turns a panic in next into a logged
+// error and, when nothing was written yet, a 500 response, so that one
+// faulty handler cannot take down the process. http.ErrAbortHandler is
+// re-raised: it is how handlers deliberately abort a response.
+func Recover(next http.Handler, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			logger.Error("Panic in HTTP handler",
+				zap.String("request_id", RequestIDFromContext(r.Context())),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Any("panic", v),
+				zap.Stack("stack"))
+			if rec.code == 0 {
+				http.Error(rec, "internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// statusRecorder records the status and size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	code  int
+	bytes int64
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.code == 0 {
+		s.code = code
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.code == 0 {
+		s.code = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (s *statusRecorder) status() int {
+	if s.code == 0 {
+		return http.StatusOK
+	}
+	return s.code
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
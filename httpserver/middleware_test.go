@@ -0,0 +1,117 @@
+package httpserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"jira-ai-issue-solver/httpserver"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := httpserver.RequestID(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = httpserver.RequestIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "generated", incoming: ""},
+		{name: "client supplied", incoming: "abc-123", keep: true},
+		{name: "control characters replaced", incoming: "abc\x01def"},
+		{name: "too long replaced", incoming: strings.Repeat("a", 200)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/status", nil)
+			if tt.incoming != "" {
+				req.Header.Set(httpserver.RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(httpserver.RequestIDHeader)
+			if got == "" || got != seen {
+				t.Fatalf("response ID %q, context ID %q; want equal and non-empty", got, seen)
+			}
+			if tt.keep != (got == tt.incoming) {
+				t.Errorf("ID = %q, incoming %q, keep %v", got, tt.incoming, tt.keep)
+			}
+		})
+	}
+}
+
+func TestLogRequests(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	handler := httpserver.RequestID(httpserver.LogRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}), zap.New(core), "/healthz"))
+
+	for _, path := range []string{"/status", "/missing", "/healthz"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("logged %d entries, want 3", len(entries))
+	}
+	for i, want := range []struct {
+		path   string
+		status int64
+		level  zapcore.Level
+	}{
+		{"/status", http.StatusOK, zapcore.InfoLevel},
+		{"/missing", http.StatusNotFound, zapcore.InfoLevel},
+		{"/healthz", http.StatusOK, zapcore.DebugLevel},
+	} {
+		fields := entries[i].ContextMap()
+		if fields["path"] != want.path || fields["status"] != want.status || entries[i].Level != want.level {
+			t.Errorf("entry %d = %s %v %v, want %s %d %v", i, fields["path"], fields["status"], entries[i].Level, want.path, want.status, want.level)
+		}
+		if fields["request_id"] == "" {
+			t.Errorf("entry %d has no request_id", i)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	handler := httpserver.Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}), zap.New(core))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if logs.FilterMessage("Panic in HTTP handler").Len() != 1 {
+		t.Errorf("panic was not logged: %v", logs.All())
+	}
+}
+
+func TestRecover_ReraisesAbortHandler(t *testing.T) {
+	handler := httpserver.Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), zap.NewNop())
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", v)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status", nil))
+}
@@ -0,0 +1,237 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// oidcKeyRefreshInterval is the minimum time between fetches of the
+// provider's signing keys, which are re-fetched when a token is signed
+// with an unknown key (e.g., after a key rotation).
+const oidcKeyRefreshInterval = time.Minute
+
+// oidcSigningMethods are the JWT algorithms accepted from the provider.
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// OIDC accepts requests carrying a JWT in an "Authorization: Bearer"
+// header that was signed by an OpenID Connect provider and issued for
+// the configured audience.
+type OIDC struct {
+	issuer   string
+	audience string
+	client   *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]any // key ID -> *rsa.PublicKey or *ecdsa.PublicKey
+	fetchedAt time.Time
+	now       func() time.Time
+}
+
+// NewOIDC returns an OIDC accepting tokens from issuerURL for
+// audience. Signing keys are discovered through client when the first
+// token is checked.
+func NewOIDC(issuerURL, audience string, client *http.Client) *OIDC {
+	return &OIDC{
+		issuer:   strings.TrimRight(issuerURL, "/"),
+		audience: audience,
+		client:   client,
+		now:      time.Now,
+	}
+}
+
+// Authenticate implements [Authenticator].
+func (o *OIDC) Authenticate(r *http.Request) error {
+	raw, ok := bearerToken(r)
+	if !ok {
+		return ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(oidcSigningMethods))
+	if _, err := parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return o.key(r.Context(), kid)
+	}); err != nil {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+
+	if !claims.VerifyIssuer(o.issuer, true) {
+		return errors.New("invalid token: unexpected issuer")
+	}
+	if !claims.VerifyAudience(o.audience, true) {
+		return errors.New("invalid token: unexpected audience")
+	}
+	if !claims.VerifyExpiresAt(o.now().Unix(), true) {
+		return errors.New("invalid token: missing or past expiry")
+	}
+	return nil
+}
+
+// key returns the provider's signing key kid, fetching the key set
+// when it is unknown and was not fetched recently. An empty kid
+// matches the only key of a single-key set.
+func (o *OIDC) key(ctx context.Context, kid string) (any, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if key, ok := o.lookup(kid); ok {
+		return key, nil
+	}
+	if !o.fetchedAt.IsZero() && o.now().Sub(o.fetchedAt) < oidcKeyRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := o.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o.keys = keys
+	o.fetchedAt = o.now()
+
+	if key, ok := o.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (o *OIDC) lookup(kid string) (any, bool) {
+	if kid == "" && len(o.keys) == 1 {
+		for _, key := range o.keys {
+			return key, true
+		}
+	}
+	key, ok := o.keys[kid]
+	return key, ok
+}
+
+// fetchKeys discovers the provider's JWKS URI and fetches its keys.
+func (o *OIDC) fetchKeys(ctx context.Context) (map[string]any, error) {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := o.getJSON(ctx, o.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("discover OIDC provider: %w", err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != o.issuer {
+		return nil, fmt.Errorf("discover OIDC provider: issuer %q does not match %q", discovery.Issuer, o.issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("discover OIDC provider: no jwks_uri")
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := o.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("fetch OIDC signing keys: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Keys of unsupported types are skipped; tokens signed
+			// with them fail as unknown keys.
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (o *OIDC) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jwk is a JSON Web Key (RFC 7517) of type RSA or EC.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode e: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("unsupported RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var point ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, point = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, point = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, point = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode y: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("invalid EC coordinates")
+		}
+		// ecdh validates that the point is on the curve.
+		if _, err := point.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, fmt.Errorf("invalid EC point: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
@@ -0,0 +1,122 @@
+package httpserver_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"jira-ai-issue-solver/httpserver"
+)
+
+// newOIDCProvider serves OIDC discovery and a JWKS holding key under
+// kid "k1". JWKS fetches are counted in *fetches.
+func newOIDCProvider(t *testing.T, key *rsa.PrivateKey, fetches *atomic.Int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   srv.URL,
+			"jwks_uri": srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": "k1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	return srv
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestOIDC_Authenticate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches atomic.Int32
+	provider := newOIDCProvider(t, key, &fetches)
+	auth := httpserver.NewOIDC(provider.URL, "issue-bot", provider.Client())
+
+	valid := jwt.MapClaims{
+		"iss": provider.URL,
+		"aud": "issue-bot",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	with := func(k string, v any) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for name, value := range valid {
+			claims[name] = value
+		}
+		if v == nil {
+			delete(claims, k)
+		} else {
+			claims[k] = v
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid", token: signToken(t, key, "k1", valid)},
+		{name: "audience list", token: signToken(t, key, "k1", with("aud", []string{"other", "issue-bot"}))},
+		{name: "wrong issuer", token: signToken(t, key, "k1", with("iss", "https://evil.example.com")), wantErr: true},
+		{name: "wrong audience", token: signToken(t, key, "k1", with("aud", "other")), wantErr: true},
+		{name: "expired", token: signToken(t, key, "k1", with("exp", time.Now().Add(-time.Minute).Unix())), wantErr: true},
+		{name: "no expiry", token: signToken(t, key, "k1", with("exp", nil)), wantErr: true},
+		{name: "wrong key", token: signToken(t, other, "k1", valid), wantErr: true},
+		{name: "unknown key ID", token: signToken(t, key, "k2", valid), wantErr: true},
+		{name: "not a JWT", token: "opaque", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/status", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			err := auth.Authenticate(req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Authenticate = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The unknown key ID must not refetch the key set within the
+	// refresh interval.
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", got)
+	}
+}
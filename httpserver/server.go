@@ -0,0 +1,67 @@
+// Package httpserver holds what sits between the bot's HTTP listener
+// and its handlers: the listen address, TLS termination (optionally
+// mutual), authentication, and the request ID, access log, and panic
+// recovery middleware.
+//
+// Health probes stay reachable without credentials so that
+// orchestrators can check the bot; every other endpoint, including
+// ones added later, requires authentication once any method is
+// configured.
+package httpserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"jira-ai-issue-solver/models"
+)
+
+// ProbePaths are the health endpoints served without authentication.
+var ProbePaths = []string{"/health", "/healthz", "/readyz"}
+
+// ListenAddress returns the address to listen on for server.address
+// and port. An address that includes a port is used as is; otherwise
+// port is appended to it.
+func ListenAddress(address string, port int) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	return net.JoinHostPort(address, strconv.Itoa(port))
+}
+
+// TLSConfig returns the TLS configuration for serving HTTPS as cfg
+// describes, or nil when cfg does not enable TLS. With a client CA,
+// clients may present a certificate, which is verified against it;
+// [ClientCertificate] then accepts their requests.
+func TLSConfig(cfg models.ServerTLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server.tls.cert_file: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read server.tls.client_ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("server.tls.client_ca_file contains no PEM certificates")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -51,11 +52,33 @@ type Config struct {
 	// cost tracking.
 	CostRecorder CostRecorder
 
+	// JobTimeout bounds each job's run. When it expires, the job's
+	// context is cancelled; if the ExecuteFunc has not returned
+	// JobTimeoutGrace later, the job is failed with [ErrJobTimeout]
+	// and its concurrency slot released, abandoning its goroutine.
+	// Shutdown waits at most JobTimeoutGrace for abandoned jobs to
+	// return. Zero disables the timeout.
+	JobTimeout time.Duration
+
+	// JobTimeoutGrace is how long a timed-out job may take to stop
+	// after its context is cancelled. Defaults to
+	// [DefaultJobTimeoutGrace] when zero.
+	JobTimeoutGrace time.Duration
+
+	// Locker optionally guards each job's run with a per-ticket lock
+	// so that no other process works on the same ticket at the same
+	// time. A job whose ticket is locked elsewhere is deferred. Nil
+	// relies on the Coordinator's in-process deduplication alone.
+	Locker TicketLocker
+
 	// Clock returns the current time. Defaults to [time.Now] when
 	// nil. Exposed for testing.
 	Clock func() time.Time
 }
 
+// DefaultJobTimeoutGrace is the default [Config.JobTimeoutGrace].
+const DefaultJobTimeoutGrace = time.Minute
+
 // Coordinator implements [Manager] by coordinating job lifecycle with
 // deduplication, concurrency limits, retry tracking, and circuit
 // breaker protection. Jobs are dispatched to the provided
@@ -66,7 +89,7 @@ type Coordinator struct {
 	// Job storage and indices.
 	jobs       map[string]*Job
 	ticketJobs map[string]string // ticket key -> job ID (pending/running)
-	queue      []string          // ordered pending job IDs
+	queue      []string          // pending job IDs in dispatch order
 
 	// Concurrency control.
 	running    int
@@ -78,12 +101,20 @@ type Coordinator struct {
 
 	breaker circuitBreaker
 	costs   CostRecorder // nil disables cost tracking
+	locker  TicketLocker // nil disables cross-process ticket locks
+
+	jobTimeout      time.Duration // zero disables the job timeout
+	jobTimeoutGrace time.Duration
 
 	execute ExecuteFunc
 	ctx     context.Context
 	cancel  context.CancelFunc
 	wg      sync.WaitGroup
 
+	// abandoned tracks jobs run under the job timeout, which may
+	// outlive their dispatch goroutine after timing out.
+	abandoned sync.WaitGroup
+
 	stopped bool
 	clock   func() time.Time
 	logger  *zap.Logger
@@ -108,6 +139,14 @@ func NewCoordinator(cfg Config, execute ExecuteFunc, logger *zap.Logger) (*Coord
 		clock = time.Now
 	}
 
+	if cfg.JobTimeout < 0 {
+		return nil, errors.New("job timeout must not be negative")
+	}
+	grace := cfg.JobTimeoutGrace
+	if grace <= 0 {
+		grace = DefaultJobTimeoutGrace
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Coordinator{
@@ -121,12 +160,15 @@ func NewCoordinator(cfg Config, execute ExecuteFunc, logger *zap.Logger) (*Coord
 			window:    cfg.CircuitBreakerWindow,
 			cooldown:  cfg.CircuitBreakerCooldown,
 		},
-		costs:   cfg.CostRecorder,
-		execute: execute,
-		ctx:     ctx,
-		cancel:  cancel,
-		clock:   clock,
-		logger:  logger,
+		costs:           cfg.CostRecorder,
+		locker:          cfg.Locker,
+		jobTimeout:      cfg.JobTimeout,
+		jobTimeoutGrace: grace,
+		execute:         execute,
+		ctx:             ctx,
+		cancel:          cancel,
+		clock:           clock,
+		logger:          logger,
 	}, nil
 }
 
@@ -160,18 +202,21 @@ func (c *Coordinator) Submit(event Event) (*Job, error) {
 	}
 
 	job := &Job{
-		ID:         generateJobID(),
-		TicketKey:  event.TicketKey,
-		Type:       event.Type,
-		Status:     JobStatusPending,
-		AttemptNum: c.failureCounts[event.TicketKey] + 1,
-		CreatedAt:  now,
-		CleanRetry: event.CleanRetry,
+		ID:            generateJobID(),
+		TicketKey:     event.TicketKey,
+		Type:          event.Type,
+		Status:        JobStatusPending,
+		AttemptNum:    c.failureCounts[event.TicketKey] + 1,
+		CreatedAt:     now,
+		CleanRetry:    event.CleanRetry,
+		Priority:      event.Priority,
+		TicketCreated: event.TicketCreated,
+		WorkItem:      event.WorkItem,
 	}
 
 	c.jobs[job.ID] = job
 	c.ticketJobs[event.TicketKey] = job.ID
-	c.queue = append(c.queue, job.ID)
+	c.enqueue(job)
 
 	c.logger.Info("Job submitted",
 		zap.String("job_id", job.ID),
@@ -248,8 +293,78 @@ func (c *Coordinator) ActiveJobs() []*Job {
 	return active
 }
 
+// Stats is a point-in-time summary of the [Coordinator]'s workload,
+// used by health endpoints to report queue depth.
+type Stats struct {
+	// Pending is the number of jobs waiting for a concurrency slot.
+	Pending int
+
+	// Running is the number of jobs currently executing.
+	Running int
+
+	// MaxConcurrent is the configured concurrency limit.
+	MaxConcurrent int
+
+	// CircuitOpen reports whether the circuit breaker is tripped.
+	CircuitOpen bool
+
+	// Queue lists the pending jobs in dispatch order.
+	Queue []QueuedJob
+}
+
+// QueuedJob describes a pending job's place in the dispatch queue.
+type QueuedJob struct {
+	// JobID identifies the job.
+	JobID string
+
+	// TicketKey identifies the ticket the job will process.
+	TicketKey string
+
+	// Type is the kind of work the job will perform.
+	Type JobType
+
+	// Priority is the job's dispatch priority.
+	Priority int
+
+	// TicketCreated is when the ticket was created. Zero if unknown.
+	TicketCreated time.Time
+
+	// SubmittedAt is when the job was submitted.
+	SubmittedAt time.Time
+}
+
+// Stats returns a snapshot of the current queue depth and ordering,
+// running job count, and circuit breaker state.
+func (c *Coordinator) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	queue := make([]QueuedJob, 0, len(c.queue))
+	for _, id := range c.queue {
+		job := c.jobs[id]
+		queue = append(queue, QueuedJob{
+			JobID:         job.ID,
+			TicketKey:     job.TicketKey,
+			Type:          job.Type,
+			Priority:      job.Priority,
+			TicketCreated: job.TicketCreated,
+			SubmittedAt:   job.CreatedAt,
+		})
+	}
+
+	return Stats{
+		Pending:       len(c.queue),
+		Running:       c.running,
+		MaxConcurrent: c.maxRunning,
+		CircuitOpen:   c.breaker.isOpen(c.clock()),
+		Queue:         queue,
+	}
+}
+
 // Shutdown stops accepting new jobs, cancels running jobs via context
 // cancellation, and waits for all dispatched goroutines to finish.
+// Jobs abandoned after timing out are waited for at most
+// [Config.JobTimeoutGrace].
 func (c *Coordinator) Shutdown() {
 	c.mu.Lock()
 	c.stopped = true
@@ -257,19 +372,33 @@ func (c *Coordinator) Shutdown() {
 
 	c.cancel()
 	c.wg.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		c.abandoned.Wait()
+		close(done)
+	}()
+	grace := time.NewTimer(c.jobTimeoutGrace)
+	defer grace.Stop()
+	select {
+	case <-done:
+	case <-grace.C:
+		c.logger.Warn("Abandoned jobs still running at shutdown",
+			zap.Duration("grace", c.jobTimeoutGrace))
+	}
 }
 
-// PurgeCompleted removes all terminal (completed or failed) jobs from
-// the in-memory store. This prevents unbounded memory growth when the
-// bot runs for extended periods. Active (pending or running) jobs are
-// not affected. Returns the number of jobs removed.
+// PurgeCompleted removes all terminal (completed, failed, or deferred)
+// jobs from the in-memory store. This prevents unbounded memory growth
+// when the bot runs for extended periods. Active (pending or running)
+// jobs are not affected. Returns the number of jobs removed.
 func (c *Coordinator) PurgeCompleted() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	removed := 0
 	for id, job := range c.jobs {
-		if job.Status == JobStatusCompleted || job.Status == JobStatusFailed {
+		if job.Status == JobStatusCompleted || job.Status == JobStatusFailed || job.Status == JobStatusDeferred {
 			delete(c.jobs, id)
 			removed++
 		}
@@ -299,6 +428,32 @@ func (c *Coordinator) ResetRetries(ticketKey string) error {
 
 // --- internal ---
 
+// enqueue inserts a pending job into the queue, keeping the queue
+// sorted in dispatch order. Jobs that compare equal keep their
+// submission order. Must be called with c.mu held.
+func (c *Coordinator) enqueue(job *Job) {
+	i := len(c.queue)
+	for i > 0 && dispatchesBefore(job, c.jobs[c.queue[i-1]]) {
+		i--
+	}
+	c.queue = slices.Insert(c.queue, i, job.ID)
+}
+
+// dispatchesBefore reports whether job a should be dispatched before
+// job b: higher priority first, then older tickets. Tickets of
+// unknown age come after those of known age, so that every job is
+// ordered by the ticket's creation time and never by when the job
+// was submitted.
+func dispatchesBefore(a, b *Job) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if a.TicketCreated.IsZero() || b.TicketCreated.IsZero() {
+		return !a.TicketCreated.IsZero() && b.TicketCreated.IsZero()
+	}
+	return a.TicketCreated.Before(b.TicketCreated)
+}
+
 // tryDispatch starts goroutines for pending jobs when concurrency
 // slots are available. Must be called with c.mu held.
 func (c *Coordinator) tryDispatch() {
@@ -330,7 +485,7 @@ func (c *Coordinator) tryDispatch() {
 func (c *Coordinator) runJob(jobID string, snapshot *Job) {
 	defer c.wg.Done()
 
-	result, err := c.execute(c.ctx, snapshot)
+	result, err := c.executeWithTimeout(snapshot)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -347,9 +502,12 @@ func (c *Coordinator) runJob(jobID string, snapshot *Job) {
 		c.costs.Record(result.CostUSD)
 	}
 
-	if err != nil {
+	switch {
+	case errors.Is(err, ErrDeferred):
+		c.deferLocked(job, err)
+	case err != nil:
 		c.failLocked(job, err)
-	} else {
+	default:
 		c.completeLocked(job, result)
 	}
 
@@ -357,6 +515,91 @@ func (c *Coordinator) runJob(jobID string, snapshot *Job) {
 	c.tryDispatch()
 }
 
+// jobOutcome carries an ExecuteFunc's return values across a channel.
+type jobOutcome struct {
+	result JobResult
+	err    error
+}
+
+// executeWithTimeout runs the ExecuteFunc under the job timeout. A job
+// that ignores its cancelled context is abandoned after the grace
+// period, so a hung AI CLI or git process cannot hold a concurrency
+// slot forever. Shutdown waits for an abandoned job to return for at
+// most another grace period.
+func (c *Coordinator) executeWithTimeout(job *Job) (JobResult, error) {
+	if c.jobTimeout <= 0 {
+		return c.executeLocked(c.ctx, job)
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, c.jobTimeout)
+	defer cancel()
+
+	done := make(chan jobOutcome, 1)
+	c.abandoned.Add(1)
+	go func() {
+		defer c.abandoned.Done()
+		result, err := c.executeLocked(ctx, job)
+		done <- jobOutcome{result: result, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, c.timeoutErr(ctx, o.err)
+	case <-ctx.Done():
+	}
+
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		o := <-done
+		return o.result, o.err
+	}
+
+	c.logger.Warn("Job timed out, cancelling",
+		zap.String("job_id", job.ID),
+		zap.String("ticket", job.TicketKey),
+		zap.Duration("timeout", c.jobTimeout))
+
+	grace := time.NewTimer(c.jobTimeoutGrace)
+	defer grace.Stop()
+	select {
+	case o := <-done:
+		return o.result, c.timeoutErr(ctx, o.err)
+	case <-grace.C:
+		c.logger.Error("Job did not stop after timeout, abandoning it",
+			zap.String("job_id", job.ID),
+			zap.String("ticket", job.TicketKey),
+			zap.Duration("grace", c.jobTimeoutGrace))
+		return JobResult{}, fmt.Errorf("%w after %s (job did not stop within %s)",
+			ErrJobTimeout, c.jobTimeout, c.jobTimeoutGrace)
+	}
+}
+
+// executeLocked runs the ExecuteFunc while holding the job's ticket
+// lock, released only once the ExecuteFunc returns. A ticket locked
+// elsewhere defers the job so that a later scan resubmits it.
+func (c *Coordinator) executeLocked(ctx context.Context, job *Job) (JobResult, error) {
+	if c.locker == nil {
+		return c.execute(ctx, job)
+	}
+	unlock, err := c.locker.Lock(job.TicketKey)
+	if errors.Is(err, ErrTicketLocked) {
+		return JobResult{}, fmt.Errorf("%w: %w", ErrDeferred, err)
+	}
+	if err != nil {
+		return JobResult{}, fmt.Errorf("acquire ticket lock: %w", err)
+	}
+	defer unlock()
+	return c.execute(ctx, job)
+}
+
+// timeoutErr wraps err with [ErrJobTimeout] when ctx hit its deadline
+// and the ExecuteFunc has not already done so.
+func (c *Coordinator) timeoutErr(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, ErrJobTimeout) || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w after %s: %w", ErrJobTimeout, c.jobTimeout, err)
+}
+
 func (c *Coordinator) completeLocked(job *Job, result JobResult) {
 	now := c.clock()
 	job.Status = JobStatusCompleted
@@ -374,6 +617,22 @@ func (c *Coordinator) completeLocked(job *Job, result JobResult) {
 		zap.Float64("cost_usd", result.CostUSD))
 }
 
+// deferLocked releases a job that its ExecuteFunc declined to run.
+// Unlike failLocked, it leaves the retry count and circuit breaker
+// untouched.
+func (c *Coordinator) deferLocked(job *Job, err error) {
+	job.Status = JobStatusDeferred
+	job.CompletedAt = c.clock()
+	job.Err = err
+
+	delete(c.ticketJobs, job.TicketKey)
+
+	c.logger.Info("Job deferred",
+		zap.String("job_id", job.ID),
+		zap.String("ticket", job.TicketKey),
+		zap.Error(err))
+}
+
 func (c *Coordinator) failLocked(job *Job, err error) {
 	now := c.clock()
 	job.Status = JobStatusFailed
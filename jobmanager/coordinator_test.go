@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -47,6 +49,14 @@ func TestNewCoordinator_RejectsNegativeMaxConcurrent(t *testing.T) {
 	}
 }
 
+func TestNewCoordinator_RejectsNegativeJobTimeout(t *testing.T) {
+	cfg := jobmanager.Config{MaxConcurrent: 1, JobTimeout: -time.Second}
+	_, err := jobmanager.NewCoordinator(cfg, noopExecute, zap.NewNop())
+	if err == nil {
+		t.Fatal("expected error for negative job timeout")
+	}
+}
+
 func TestNewCoordinator_ValidConfig(t *testing.T) {
 	cfg := jobmanager.Config{MaxConcurrent: 5, MaxRetries: 3}
 	coord, err := jobmanager.NewCoordinator(cfg, noopExecute, zap.NewNop())
@@ -340,6 +350,113 @@ func TestConcurrency_RespectsLimit(t *testing.T) {
 	}
 }
 
+func TestStats_ReportsPendingAndRunning(t *testing.T) {
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent: 1,
+		MaxRetries:    -1,
+	}, blockForever)
+	defer coord.Shutdown()
+
+	for _, key := range []string{"A", "B", "C"} {
+		if _, err := coord.Submit(jobmanager.Event{
+			Type:      jobmanager.JobTypeNewTicket,
+			TicketKey: key,
+		}); err != nil {
+			t.Fatalf("submit %s: %v", key, err)
+		}
+	}
+
+	stats := coord.Stats()
+	if stats.Running != 1 {
+		t.Errorf("Running = %d, want 1", stats.Running)
+	}
+	if stats.Pending != 2 {
+		t.Errorf("Pending = %d, want 2", stats.Pending)
+	}
+	if stats.MaxConcurrent != 1 {
+		t.Errorf("MaxConcurrent = %d, want 1", stats.MaxConcurrent)
+	}
+	if stats.CircuitOpen {
+		t.Error("CircuitOpen = true, want false")
+	}
+}
+
+func TestStats_QueueOrderedByPriorityThenAge(t *testing.T) {
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent: 1,
+		MaxRetries:    -1,
+	}, blockForever)
+	defer coord.Shutdown()
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	events := []jobmanager.Event{
+		{TicketKey: "RUNNING"}, // occupies the only slot
+		{TicketKey: "LOW", Priority: -1, TicketCreated: day(1)},
+		{TicketKey: "NEW-HIGH", Priority: 1, TicketCreated: day(5)},
+		{TicketKey: "UNKNOWN"},
+		{TicketKey: "FEEDBACK", Type: jobmanager.JobTypeFeedback, TicketCreated: day(4)},
+		{TicketKey: "OLD-HIGH", Priority: 1, TicketCreated: day(2)},
+		{TicketKey: "MEDIUM", TicketCreated: day(3)},
+	}
+	for _, e := range events {
+		if e.Type == "" {
+			e.Type = jobmanager.JobTypeNewTicket
+		}
+		if _, err := coord.Submit(e); err != nil {
+			t.Fatalf("submit %s: %v", e.TicketKey, err)
+		}
+	}
+
+	var got []string
+	for _, q := range coord.Stats().Queue {
+		got = append(got, q.TicketKey)
+	}
+	// Feedback jobs are ordered by ticket age like new tickets; a
+	// ticket of unknown age goes after those of known age even though
+	// it was submitted first.
+	want := []string{"OLD-HIGH", "NEW-HIGH", "MEDIUM", "FEEDBACK", "UNKNOWN", "LOW"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Queue = %v, want %v", got, want)
+	}
+}
+
+func TestDispatch_HighestPriorityRunsNext(t *testing.T) {
+	started := make(chan string, 10)
+	block := make(chan struct{})
+
+	execute := func(_ context.Context, job *jobmanager.Job) (jobmanager.JobResult, error) {
+		started <- job.TicketKey
+		<-block
+		return jobmanager.JobResult{}, nil
+	}
+
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent: 1,
+		MaxRetries:    -1,
+	}, execute)
+	defer coord.Shutdown()
+
+	for _, e := range []jobmanager.Event{
+		{TicketKey: "A"},
+		{TicketKey: "B", Priority: -1},
+		{TicketKey: "C", Priority: 2},
+	} {
+		e.Type = jobmanager.JobTypeNewTicket
+		if _, err := coord.Submit(e); err != nil {
+			t.Fatalf("submit %s: %v", e.TicketKey, err)
+		}
+	}
+
+	if key := <-started; key != "A" {
+		t.Fatalf("first started = %s, want A", key)
+	}
+	block <- struct{}{}
+	if key := <-started; key != "C" {
+		t.Errorf("second started = %s, want C", key)
+	}
+	close(block)
+}
+
 // --- Retry ---
 
 func TestRetry_SucceedsWithinLimit(t *testing.T) {
@@ -468,6 +585,41 @@ func TestRetry_ZeroMaxRetriesMeansNoRetries(t *testing.T) {
 	}
 }
 
+func TestDeferred_DoesNotCountAsFailure(t *testing.T) {
+	deferExecute := func(_ context.Context, _ *jobmanager.Job) (jobmanager.JobResult, error) {
+		return jobmanager.JobResult{}, fmt.Errorf("repo at PR limit: %w", jobmanager.ErrDeferred)
+	}
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent:           1,
+		MaxRetries:              0,
+		CircuitBreakerThreshold: 1,
+		CircuitBreakerWindow:    time.Hour,
+		CircuitBreakerCooldown:  time.Hour,
+	}, deferExecute)
+	defer coord.Shutdown()
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		job, err := coord.Submit(jobmanager.Event{
+			Type: jobmanager.JobTypeNewTicket, TicketKey: "PROJ-1",
+		})
+		if err != nil {
+			t.Fatalf("submit %d: %v", attempt, err)
+		}
+		if job.AttemptNum != 1 {
+			t.Errorf("submit %d: AttemptNum = %d, want 1", attempt, job.AttemptNum)
+		}
+		waitForTerminal(t, coord, job.ID)
+
+		got, _ := coord.GetJob(job.ID)
+		if got.Status != jobmanager.JobStatusDeferred {
+			t.Errorf("submit %d: Status = %q, want deferred", attempt, got.Status)
+		}
+	}
+	if coord.Stats().CircuitOpen {
+		t.Error("CircuitOpen = true, want false after deferrals")
+	}
+}
+
 func TestRetry_NegativeMaxRetriesDisablesLimit(t *testing.T) {
 	coord := mustCoordinator(t, jobmanager.Config{
 		MaxConcurrent: 1,
@@ -1084,6 +1236,298 @@ func TestDispatch_QueuedJobStartsWhenSlotFrees(t *testing.T) {
 	close(getBlock("B"))
 }
 
+// --- Job timeout ---
+
+func TestJobTimeout_CancelsJobAndFailsWithTimeout(t *testing.T) {
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent: 1,
+		MaxRetries:    -1,
+		JobTimeout:    20 * time.Millisecond,
+	}, blockForever)
+	defer coord.Shutdown()
+
+	job, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "A"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, job.ID)
+
+	got, _ := coord.GetJob(job.ID)
+	if got.Status != jobmanager.JobStatusFailed {
+		t.Fatalf("status = %s, want failed", got.Status)
+	}
+	if !errors.Is(got.Err, jobmanager.ErrJobTimeout) || !errors.Is(got.Err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want ErrJobTimeout wrapping the context error", got.Err)
+	}
+}
+
+func TestJobTimeout_AbandonsHungJobAndReleasesSlot(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan string, 2)
+	execute := func(_ context.Context, job *jobmanager.Job) (jobmanager.JobResult, error) {
+		started <- job.TicketKey
+		if job.TicketKey == "HUNG" {
+			<-release // ignores cancellation
+		}
+		return jobmanager.JobResult{}, nil
+	}
+
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent:   1,
+		MaxRetries:      -1,
+		JobTimeout:      20 * time.Millisecond,
+		JobTimeoutGrace: 20 * time.Millisecond,
+	}, execute)
+	defer coord.Shutdown()
+
+	hung, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "HUNG"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	next, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "NEXT"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	waitForTerminal(t, coord, next.ID)
+	got, _ := coord.GetJob(hung.ID)
+	if got.Status != jobmanager.JobStatusFailed || !errors.Is(got.Err, jobmanager.ErrJobTimeout) {
+		t.Errorf("hung job = %s (%v), want failed with ErrJobTimeout", got.Status, got.Err)
+	}
+}
+
+func TestJobTimeout_ShutdownWaitsForAbandonedJob(t *testing.T) {
+	release := make(chan struct{})
+	var returned atomic.Bool
+	execute := func(_ context.Context, _ *jobmanager.Job) (jobmanager.JobResult, error) {
+		<-release // ignores cancellation
+		returned.Store(true)
+		return jobmanager.JobResult{}, nil
+	}
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent:   1,
+		MaxRetries:      -1,
+		JobTimeout:      20 * time.Millisecond,
+		JobTimeoutGrace: 200 * time.Millisecond,
+	}, execute)
+
+	job, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "HUNG"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, job.ID)
+
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	coord.Shutdown()
+	if !returned.Load() {
+		t.Error("Shutdown returned before the abandoned job")
+	}
+}
+
+func TestJobTimeout_ShutdownBoundsWaitForAbandonedJob(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	execute := func(_ context.Context, _ *jobmanager.Job) (jobmanager.JobResult, error) {
+		<-release // ignores cancellation
+		return jobmanager.JobResult{}, nil
+	}
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent:   1,
+		MaxRetries:      -1,
+		JobTimeout:      20 * time.Millisecond,
+		JobTimeoutGrace: 20 * time.Millisecond,
+	}, execute)
+
+	job, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "HUNG"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, job.ID)
+
+	stopped := make(chan struct{})
+	go func() {
+		coord.Shutdown()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown did not return while an abandoned job hung")
+	}
+}
+
+func TestJobTimeout_ZeroDisablesTimeout(t *testing.T) {
+	execute := func(ctx context.Context, _ *jobmanager.Job) (jobmanager.JobResult, error) {
+		if _, ok := ctx.Deadline(); ok {
+			return jobmanager.JobResult{}, errors.New("unexpected deadline")
+		}
+		return jobmanager.JobResult{}, nil
+	}
+	coord := mustCoordinator(t, jobmanager.Config{MaxConcurrent: 1}, execute)
+	defer coord.Shutdown()
+
+	job, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "A"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, job.ID)
+	if got, _ := coord.GetJob(job.ID); got.Status != jobmanager.JobStatusCompleted {
+		t.Errorf("status = %s (%v), want completed", got.Status, got.Err)
+	}
+}
+
+// --- Ticket locks ---
+
+// lockStub is an in-memory [jobmanager.TicketLocker].
+type lockStub struct {
+	mu   sync.Mutex
+	held map[string]bool
+	err  error
+}
+
+func (l *lockStub) Lock(ticketKey string) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return nil, l.err
+	}
+	if l.held[ticketKey] {
+		return nil, jobmanager.ErrTicketLocked
+	}
+	if l.held == nil {
+		l.held = make(map[string]bool)
+	}
+	l.held[ticketKey] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, ticketKey)
+	}, nil
+}
+
+func (l *lockStub) isHeld(ticketKey string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held[ticketKey]
+}
+
+func TestTicketLock_LockedElsewhereDefersJob(t *testing.T) {
+	locks := &lockStub{held: map[string]bool{"PROJ-1": true}}
+	executed := false
+	execute := func(_ context.Context, _ *jobmanager.Job) (jobmanager.JobResult, error) {
+		executed = true
+		return jobmanager.JobResult{}, nil
+	}
+	coord := mustCoordinator(t, jobmanager.Config{MaxConcurrent: 1, Locker: locks}, execute)
+	defer coord.Shutdown()
+
+	job, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "PROJ-1"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, job.ID)
+
+	got, _ := coord.GetJob(job.ID)
+	if got.Status != jobmanager.JobStatusDeferred || !errors.Is(got.Err, jobmanager.ErrTicketLocked) {
+		t.Errorf("job = %s (%v), want deferred with ErrTicketLocked", got.Status, got.Err)
+	}
+	if executed {
+		t.Error("job executed while its ticket was locked elsewhere")
+	}
+}
+
+func TestTicketLock_ReleasedAfterRun(t *testing.T) {
+	locks := &lockStub{}
+	execute := func(_ context.Context, job *jobmanager.Job) (jobmanager.JobResult, error) {
+		if !locks.isHeld(job.TicketKey) {
+			return jobmanager.JobResult{}, errors.New("ran without the ticket lock")
+		}
+		return jobmanager.JobResult{}, nil
+	}
+	coord := mustCoordinator(t, jobmanager.Config{MaxConcurrent: 1, Locker: locks}, execute)
+	defer coord.Shutdown()
+
+	job, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "PROJ-1"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, job.ID)
+
+	if got, _ := coord.GetJob(job.ID); got.Status != jobmanager.JobStatusCompleted {
+		t.Errorf("status = %s (%v), want completed", got.Status, got.Err)
+	}
+	if locks.isHeld("PROJ-1") {
+		t.Error("ticket lock still held after the job finished")
+	}
+}
+
+func TestTicketLock_HeldUntilAbandonedJobReturns(t *testing.T) {
+	locks := &lockStub{}
+	release := make(chan struct{})
+	returned := make(chan struct{})
+	var calls atomic.Int32
+	execute := func(_ context.Context, _ *jobmanager.Job) (jobmanager.JobResult, error) {
+		calls.Add(1)
+		<-release // ignores cancellation
+		close(returned)
+		return jobmanager.JobResult{}, nil
+	}
+	coord := mustCoordinator(t, jobmanager.Config{
+		MaxConcurrent:   1,
+		MaxRetries:      -1,
+		JobTimeout:      20 * time.Millisecond,
+		JobTimeoutGrace: 20 * time.Millisecond,
+		Locker:          locks,
+	}, execute)
+	defer coord.Shutdown()
+
+	hung, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "PROJ-1"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, hung.ID)
+
+	// The abandoned run still holds the lock, so a resubmission of the
+	// same ticket must not run alongside it.
+	retry, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "PROJ-1"})
+	if err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	waitForTerminal(t, coord, retry.ID)
+	if got, _ := coord.GetJob(retry.ID); got.Status != jobmanager.JobStatusDeferred {
+		t.Errorf("resubmitted job = %s (%v), want deferred", got.Status, got.Err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("execute called %d times, want 1", n)
+	}
+
+	close(release)
+	<-returned
+	deadline := time.Now().Add(time.Second)
+	for locks.isHeld("PROJ-1") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if locks.isHeld("PROJ-1") {
+		t.Error("ticket lock not released after the abandoned run returned")
+	}
+}
+
+func TestTicketLock_LockErrorFailsJob(t *testing.T) {
+	locks := &lockStub{err: errors.New("lock store unavailable")}
+	coord := mustCoordinator(t, jobmanager.Config{MaxConcurrent: 1, Locker: locks}, noopExecute)
+	defer coord.Shutdown()
+
+	job, err := coord.Submit(jobmanager.Event{Type: jobmanager.JobTypeNewTicket, TicketKey: "PROJ-1"})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	waitForTerminal(t, coord, job.ID)
+	if got, _ := coord.GetJob(job.ID); got.Status != jobmanager.JobStatusFailed {
+		t.Errorf("status = %s (%v), want failed", got.Status, got.Err)
+	}
+}
+
 // --- Cost recording ---
 
 type costStub struct {
@@ -1234,7 +1678,8 @@ func waitForTerminal(t *testing.T, coord *jobmanager.Coordinator, jobID string)
 		if err != nil {
 			t.Fatalf("GetJob(%s): %v", jobID, err)
 		}
-		if job.Status == jobmanager.JobStatusCompleted || job.Status == jobmanager.JobStatusFailed {
+		switch job.Status {
+		case jobmanager.JobStatusCompleted, jobmanager.JobStatusFailed, jobmanager.JobStatusDeferred:
 			return
 		}
 		time.Sleep(5 * time.Millisecond)
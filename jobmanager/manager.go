@@ -16,7 +16,15 @@
 // tickets (whose status reverts to "todo") during subsequent poll
 // cycles and resubmit them. The Manager tracks per-ticket failure
 // counts across submissions and rejects new submissions once the retry
-// limit is exhausted.
+// limit is exhausted. Jobs that end with [ErrDeferred] are released
+// the same way but are not counted as failures.
+//
+// # Dispatch order
+//
+// When more jobs are pending than there are free concurrency slots,
+// the Manager dispatches them by [Event.Priority] (highest first),
+// then by [Event.TicketCreated] (oldest first), then in submission
+// order. [Coordinator.Stats] reports the pending queue in this order.
 //
 // Test doubles are provided in the [jobmanagertest] subpackage.
 package jobmanager
@@ -24,6 +32,8 @@ package jobmanager
 import (
 	"errors"
 	"time"
+
+	"jira-ai-issue-solver/models"
 )
 
 // JobType identifies the kind of work a job performs.
@@ -39,6 +49,21 @@ const (
 	// JobTypeMerge merges the target branch into a PR branch to
 	// resolve conflicts, optionally using AI for conflict resolution.
 	JobTypeMerge JobType = "merge"
+
+	// JobTypeBackport cherry-picks a ticket's merged change onto the
+	// release branches its backport labels request and opens a PR
+	// for each.
+	JobTypeBackport JobType = "backport"
+
+	// JobTypeTriage has the AI propose a ticket's component,
+	// severity, possible duplicates, and effort, and records them on
+	// the ticket without changing any code.
+	JobTypeTriage JobType = "triage"
+
+	// JobTypeReleaseNote has the AI write a user-facing release note
+	// for a ticket whose change was merged, and records it in a Jira
+	// field or the repository's changelog.
+	JobTypeReleaseNote JobType = "release_note"
 )
 
 // JobStatus represents the lifecycle state of a job.
@@ -49,6 +74,12 @@ const (
 	JobStatusRunning   JobStatus = "running"
 	JobStatusCompleted JobStatus = "completed"
 	JobStatusFailed    JobStatus = "failed"
+
+	// JobStatusDeferred marks a job whose [ExecuteFunc] declined to
+	// run it for now by returning [ErrDeferred]. Deferral is not a
+	// failure: it does not count toward retries or the circuit
+	// breaker.
+	JobStatusDeferred JobStatus = "deferred"
 )
 
 // Event represents a work signal from a scanner. The Manager creates
@@ -65,6 +96,21 @@ type Event struct {
 	// and the local workspace before processing. Set when the retry
 	// label triggers resubmission of an exhausted ticket.
 	CleanRetry bool
+
+	// Priority orders pending jobs when all concurrency slots are
+	// busy. Higher values are dispatched first; zero is the default.
+	Priority int
+
+	// TicketCreated is when the ticket was created. Among pending
+	// jobs of equal priority, older tickets are dispatched first.
+	// Zero (unknown) orders the job after those whose ticket age is
+	// known.
+	TicketCreated time.Time
+
+	// WorkItem is the ticket as the scanner found it, if known. The
+	// pipeline uses it instead of fetching the ticket again when the
+	// job starts soon after submission. Optional.
+	WorkItem *models.WorkItem
 }
 
 // JobResult holds the outcome of a completed job.
@@ -90,7 +136,8 @@ type JobResult struct {
 }
 
 // Job represents a unit of work tracked by the Manager. Jobs progress
-// through the states: Pending -> Running -> Completed | Failed.
+// through the states: Pending -> Running -> Completed | Failed |
+// Deferred.
 type Job struct {
 	// ID is a unique identifier assigned on creation.
 	ID string
@@ -128,6 +175,17 @@ type Job struct {
 	// CleanRetry signals the pipeline to delete stale remote branches
 	// and the local workspace before processing.
 	CleanRetry bool
+
+	// Priority is the dispatch priority copied from the [Event].
+	Priority int
+
+	// TicketCreated is the ticket creation time copied from the
+	// [Event].
+	TicketCreated time.Time
+
+	// WorkItem is the scanner's snapshot of the ticket copied from
+	// the [Event], taken at CreatedAt. Nil if none was provided.
+	WorkItem *models.WorkItem
 }
 
 // CostRecorder tracks AI session costs for budget enforcement. The
@@ -143,6 +201,17 @@ type CostRecorder interface {
 	BudgetExceeded() bool
 }
 
+// TicketLocker provides per-ticket mutual exclusion that extends
+// beyond one Coordinator, such as across replicas sharing a lock
+// store. The Coordinator holds a ticket's lock for as long as its
+// [ExecuteFunc] runs, including a run abandoned after a timeout.
+type TicketLocker interface {
+	// Lock acquires the lock for ticketKey and returns a function
+	// that releases it. Returns [ErrTicketLocked] (possibly wrapped)
+	// when another holder has the lock.
+	Lock(ticketKey string) (unlock func(), err error)
+}
+
 // Sentinel errors returned by [Manager] methods.
 var (
 	// ErrDuplicateJob indicates a pending or running job already
@@ -171,6 +240,24 @@ var (
 	// ErrShutdown indicates the manager has been shut down and is no
 	// longer accepting new jobs.
 	ErrShutdown = errors.New("job manager is shut down")
+
+	// ErrDeferred is returned (possibly wrapped) by an [ExecuteFunc]
+	// that cannot run the job yet because of backpressure, such as a
+	// repository that is at its open PR limit. The job ends in
+	// [JobStatusDeferred] without a failure being recorded, so the
+	// scanner can resubmit the ticket on a later cycle.
+	ErrDeferred = errors.New("job deferred")
+
+	// ErrJobTimeout indicates a job ran longer than the configured
+	// job timeout. The [Coordinator] cancels the job's context when
+	// the timeout expires; an [ExecuteFunc] wraps its error with
+	// ErrJobTimeout when it stops for that reason.
+	ErrJobTimeout = errors.New("job timed out")
+
+	// ErrTicketLocked is returned by a [TicketLocker] when the ticket
+	// is already being processed elsewhere. The [Coordinator] defers
+	// such a job rather than failing it.
+	ErrTicketLocked = errors.New("ticket is locked by another worker")
 )
 
 // Manager coordinates job lifecycle, enforcing deduplication,
@@ -0,0 +1,218 @@
+// Package linkcontext finds the links in ticket text and fetches the
+// text of the pages they point to, so that a ticket whose description
+// only says "see link" still gives the AI the failing CI log or gist
+// it refers to.
+//
+// Only pages on operator-allowed domains are fetched. [Fetcher.URLs]
+// picks them out of the text; [Fetcher.Fetch] downloads one, follows
+// redirects only within the allowed domains, reduces HTML to its
+// visible text, and caps the result at [Config.MaxBytes].
+package linkcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Config holds construction parameters for [Fetcher].
+type Config struct {
+	// AllowedDomains lists the hosts whose pages are fetched. A
+	// domain also allows its subdomains. Must not be empty.
+	AllowedDomains []string
+
+	// MaxLinks is the number of links [Fetcher.URLs] returns. Must be
+	// positive.
+	MaxLinks int
+
+	// MaxBytes caps the text kept from each page. Must be positive.
+	MaxBytes int
+}
+
+// Page is the text content of a linked page.
+type Page struct {
+	// URL is the link as it appeared in the ticket.
+	URL string
+
+	// Text is the page's text: the body of plain-text responses, the
+	// visible text of HTML ones.
+	Text string
+
+	// Truncated reports whether Text was cut at MaxBytes.
+	Truncated bool
+}
+
+// Fetcher fetches linked pages from allowed domains. It is safe for
+// concurrent use.
+type Fetcher struct {
+	cfg     Config
+	domains []string
+	client  *http.Client
+}
+
+// NewFetcher creates a Fetcher that makes its requests with client.
+// Requests should be bounded by client's timeout or the context passed
+// to [Fetcher.Fetch].
+func NewFetcher(cfg Config, client *http.Client) (*Fetcher, error) {
+	if len(cfg.AllowedDomains) == 0 {
+		return nil, errors.New("link context allowed domains must not be empty")
+	}
+	if cfg.MaxLinks <= 0 {
+		return nil, errors.New("link context max links must be positive")
+	}
+	if cfg.MaxBytes <= 0 {
+		return nil, errors.New("link context max bytes must be positive")
+	}
+	if client == nil {
+		return nil, errors.New("HTTP client must not be nil")
+	}
+	f := &Fetcher{cfg: cfg}
+	for _, d := range cfg.AllowedDomains {
+		f.domains = append(f.domains, strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), ".")))
+	}
+
+	c := *client
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if !f.allowed(req.URL) {
+			return fmt.Errorf("redirect to %s is not on an allowed domain", req.URL.Host)
+		}
+		return nil
+	}
+	f.client = &c
+	return f, nil
+}
+
+// urlPattern matches http and https links in plain text, Markdown, and
+// Jira markup.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'\x60|\[\]{}]+`)
+
+// URLs returns the links in text that point to allowed domains, in
+// order of first appearance and without duplicates, up to MaxLinks.
+func (f *Fetcher) URLs(text string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, raw := range urlPattern.FindAllString(text, -1) {
+		raw = trimLink(raw)
+		u, err := url.Parse(raw)
+		if err != nil || seen[raw] || !f.allowed(u) {
+			continue
+		}
+		seen[raw] = true
+		urls = append(urls, raw)
+		if len(urls) == f.cfg.MaxLinks {
+			break
+		}
+	}
+	return urls
+}
+
+// Fetch downloads the page at rawURL and returns its text. Only
+// textual responses are accepted.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Page{}, fmt.Errorf("parse link: %w", err)
+	}
+	if !f.allowed(u) {
+		return Page{}, fmt.Errorf("%s is not on an allowed domain", u.Host)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Page{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html, text/plain;q=0.9, application/json;q=0.8, */*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Page{}, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Page{}, fmt.Errorf("fetch %s: status %d", rawURL, resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	isHTML := mediaType == "text/html" || mediaType == "application/xhtml+xml"
+	if !isHTML && !isText(mediaType) {
+		return Page{}, fmt.Errorf("fetch %s: unsupported content type %q", rawURL, mediaType)
+	}
+
+	// HTML shrinks when reduced to text, so more of it is read.
+	limit := int64(f.cfg.MaxBytes)
+	if isHTML {
+		limit *= 8
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return Page{}, fmt.Errorf("read %s: %w", rawURL, err)
+	}
+	truncated := int64(len(body)) > limit
+	if truncated {
+		body = body[:limit]
+	}
+
+	text := string(body)
+	if isHTML {
+		text = htmlText(text)
+	}
+	if len(text) > f.cfg.MaxBytes {
+		text = text[:f.cfg.MaxBytes]
+		truncated = true
+	}
+	return Page{URL: rawURL, Text: strings.ToValidUTF8(text, ""), Truncated: truncated}, nil
+}
+
+// allowed reports whether u is an http(s) URL on an allowed domain or
+// one of its subdomains.
+func (f *Fetcher) allowed(u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, d := range f.domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimLink removes the punctuation that ends a sentence or encloses a
+// link rather than belonging to it. A closing parenthesis is kept when
+// the link opened one, as in Wikipedia-style URLs.
+func trimLink(link string) string {
+	for link != "" {
+		last := link[len(link)-1]
+		switch {
+		case strings.IndexByte(".,;:!?*_~", last) >= 0:
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+		default:
+			return link
+		}
+		link = link[:len(link)-1]
+	}
+	return link
+}
+
+// isText reports whether mediaType is plain enough to hand to the AI
+// as is.
+func isText(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		return true
+	case mediaType == "application/xml", strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return false
+}
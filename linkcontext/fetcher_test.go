@@ -0,0 +1,168 @@
+package linkcontext_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/linkcontext"
+)
+
+func newFetcher(t *testing.T, cfg linkcontext.Config) *linkcontext.Fetcher {
+	t.Helper()
+	f, err := linkcontext.NewFetcher(cfg, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewFetcher: %v", err)
+	}
+	return f
+}
+
+func TestFetcher_URLs(t *testing.T) {
+	f := newFetcher(t, linkcontext.Config{
+		AllowedDomains: []string{"github.com", "gist.githubusercontent.com", "grafana.example.com"},
+		MaxLinks:       3,
+		MaxBytes:       1024,
+	})
+
+	text := "CI fails, see https://github.com/org/repo/actions/runs/42.\n" +
+		"Log: [gist|https://gist.githubusercontent.com/u/abc/raw/log.txt] and " +
+		"[dashboard](https://grafana.example.com/d/x?from=now-1h&to=now), " +
+		"not https://evil.example.net/github.com or ftp://github.com/file or https://notgithub.com/x.\n" +
+		"Again: https://github.com/org/repo/actions/runs/42 and https://api.github.com/repos/org/repo"
+
+	got := f.URLs(text)
+	want := []string{
+		"https://github.com/org/repo/actions/runs/42",
+		"https://gist.githubusercontent.com/u/abc/raw/log.txt",
+		"https://grafana.example.com/d/x?from=now-1h&to=now",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("URLs() = %q, want %q", got, want)
+	}
+}
+
+func TestFetcher_URLsKeepsBalancedParentheses(t *testing.T) {
+	f := newFetcher(t, linkcontext.Config{AllowedDomains: []string{"en.wikipedia.org"}, MaxLinks: 5, MaxBytes: 1024})
+
+	got := f.URLs("(see https://en.wikipedia.org/wiki/Go_(programming_language))")
+	if len(got) != 1 || got[0] != "https://en.wikipedia.org/wiki/Go_(programming_language)" {
+		t.Errorf("URLs() = %q", got)
+	}
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/log.txt", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("panic: nil map\n  at main.go:12\n"))
+	})
+	mux.HandleFunc("/run", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><title>Run</title><script>var x = 1;</script></head>
+<body><nav>Home | Docs</nav><h1>Build   failed</h1>
+<p>Job <b>test</b> exited &amp; failed.</p>
+<pre>  FAIL pkg/foo
+    want 1, got 2</pre>
+<ul><li>retry</li><li>bisect</li></ul></body></html>`))
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(strings.Repeat("x", 300)))
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	f := newFetcher(t, linkcontext.Config{AllowedDomains: []string{"127.0.0.1"}, MaxLinks: 5, MaxBytes: 256})
+	ctx := context.Background()
+
+	page, err := f.Fetch(ctx, srv.URL+"/log.txt")
+	if err != nil {
+		t.Fatalf("Fetch text: %v", err)
+	}
+	if page.Text != "panic: nil map\n  at main.go:12\n" || page.Truncated {
+		t.Errorf("text page = %+v", page)
+	}
+
+	page, err = f.Fetch(ctx, srv.URL+"/run")
+	if err != nil {
+		t.Fatalf("Fetch HTML: %v", err)
+	}
+	want := "Build failed\n\nJob test exited & failed.\n\n  FAIL pkg/foo\n    want 1, got 2\n\n- retry\n- bisect"
+	if page.Text != want {
+		t.Errorf("HTML text:\n%s\n\nwant:\n%s", page.Text, want)
+	}
+
+	page, err = f.Fetch(ctx, srv.URL+"/big")
+	if err != nil {
+		t.Fatalf("Fetch big: %v", err)
+	}
+	if len(page.Text) != 256 || !page.Truncated {
+		t.Errorf("big page: %d bytes, truncated %v; want 256 bytes, truncated", len(page.Text), page.Truncated)
+	}
+
+	for _, path := range []string{"/image.png", "/missing"} {
+		if _, err := f.Fetch(ctx, srv.URL+path); err == nil {
+			t.Errorf("Fetch %s: expected error", path)
+		}
+	}
+	if _, err := f.Fetch(ctx, "https://example.com/"); err == nil {
+		t.Error("Fetch of a domain that is not allowed: expected error")
+	}
+}
+
+func TestFetcher_RedirectsStayOnAllowedDomains(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/inside":
+			http.Redirect(w, r, "/page", http.StatusFound)
+		case "/outside":
+			http.Redirect(w, r, "http://localhost.invalid/secret", http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	f := newFetcher(t, linkcontext.Config{AllowedDomains: []string{"127.0.0.1"}, MaxLinks: 5, MaxBytes: 64})
+
+	page, err := f.Fetch(context.Background(), srv.URL+"/inside")
+	if err != nil || page.Text != "ok" {
+		t.Errorf("redirect within allowed domain: page %+v, err %v", page, err)
+	}
+	_, err = f.Fetch(context.Background(), srv.URL+"/outside")
+	if err == nil || !strings.Contains(err.Error(), "not on an allowed domain") {
+		t.Errorf("redirect off allowed domains: err = %v", err)
+	}
+}
+
+func TestNewFetcher_Validation(t *testing.T) {
+	valid := linkcontext.Config{AllowedDomains: []string{"github.com"}, MaxLinks: 1, MaxBytes: 1}
+	tests := []struct {
+		name   string
+		modify func(*linkcontext.Config)
+	}{
+		{name: "no domains", modify: func(c *linkcontext.Config) { c.AllowedDomains = nil }},
+		{name: "zero max links", modify: func(c *linkcontext.Config) { c.MaxLinks = 0 }},
+		{name: "zero max bytes", modify: func(c *linkcontext.Config) { c.MaxBytes = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if _, err := linkcontext.NewFetcher(cfg, http.DefaultClient); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+	if _, err := linkcontext.NewFetcher(valid, nil); err == nil {
+		t.Error("nil client: expected error")
+	}
+}
@@ -0,0 +1,129 @@
+package linkcontext
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlText returns the visible text of an HTML document: scripts,
+// styles, and other non-content elements are dropped, block elements
+// start new lines, and runs of whitespace are collapsed. Preformatted
+// text keeps its layout, since that is where logs usually are.
+func htmlText(doc string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(doc))
+	skip, pre := 0, 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return tidyLines(b.String())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			switch {
+			case hiddenElements[a]:
+				if !selfClosing(z, a) {
+					skip++
+				}
+			case a == atom.Pre:
+				pre++
+				b.WriteString("\n")
+			case a == atom.Br:
+				b.WriteString("\n")
+			case a == atom.Li:
+				b.WriteString("\n- ")
+			case a == atom.Td || a == atom.Th:
+				b.WriteString(" | ")
+			case blockElements[a]:
+				b.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			switch {
+			case hiddenElements[a]:
+				if skip > 0 {
+					skip--
+				}
+			case a == atom.Pre:
+				if pre > 0 {
+					pre--
+				}
+				b.WriteString("\n")
+			case blockElements[a]:
+				b.WriteString("\n")
+			}
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := string(z.Text())
+			if pre > 0 {
+				b.WriteString(text)
+				continue
+			}
+			if fields := strings.Fields(text); len(fields) > 0 {
+				if isSpace(text[0]) && !endsInSpace(b.String()) {
+					b.WriteString(" ")
+				}
+				b.WriteString(strings.Join(fields, " "))
+				if isSpace(text[len(text)-1]) {
+					b.WriteString(" ")
+				}
+			}
+		}
+	}
+}
+
+// selfClosing reports whether the current start tag has no content to
+// skip: void elements and tags written as <tag/>.
+func selfClosing(z *html.Tokenizer, a atom.Atom) bool {
+	raw := z.Raw()
+	return a == atom.Link || a == atom.Meta || len(raw) > 1 && raw[len(raw)-2] == '/'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t' || c == '\r'
+}
+
+func endsInSpace(s string) bool {
+	return s == "" || isSpace(s[len(s)-1])
+}
+
+// tidyLines trims each line, drops blank lines beyond one in a row,
+// and trims the result.
+func tidyLines(text string) string {
+	var out []string
+	blank := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// hiddenElements are elements whose content is not page text.
+var hiddenElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true,
+	atom.Template: true, atom.Svg: true, atom.Iframe: true, atom.Link: true,
+	atom.Meta: true, atom.Nav: true, atom.Footer: true,
+}
+
+// blockElements are elements that start on a new line.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Main: true, atom.Header: true, atom.Aside: true, atom.Blockquote: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true,
+	atom.H6: true, atom.Ul: true, atom.Ol: true, atom.Tr: true, atom.Table: true,
+	atom.Hr: true, atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Form: true,
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"flag"
 	"fmt"
 	"net/http"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
@@ -15,16 +17,34 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"jira-ai-issue-solver/ailimit"
+	"jira-ai-issue-solver/circuit"
+	"jira-ai-issue-solver/configreload"
 	"jira-ai-issue-solver/container"
 	"jira-ai-issue-solver/costtracker"
+	"jira-ai-issue-solver/deppolicy"
+	"jira-ai-issue-solver/events"
 	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/health"
+	"jira-ai-issue-solver/history"
+	"jira-ai-issue-solver/httpserver"
 	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/linkcontext"
+	"jira-ai-issue-solver/modelroute"
 	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/notify"
 	"jira-ai-issue-solver/projectresolver"
 	"jira-ai-issue-solver/recovery"
+	"jira-ai-issue-solver/replay"
+	"jira-ai-issue-solver/repoindex"
 	"jira-ai-issue-solver/scanner"
+	"jira-ai-issue-solver/scm"
+	"jira-ai-issue-solver/secrets"
 	"jira-ai-issue-solver/services"
 	"jira-ai-issue-solver/taskfile"
+	"jira-ai-issue-solver/ticketindex"
+	"jira-ai-issue-solver/ticketlock"
+	"jira-ai-issue-solver/tracing"
 	"jira-ai-issue-solver/tracker/jira"
 	"jira-ai-issue-solver/workspace"
 )
@@ -42,22 +62,75 @@ func main() {
 	logger := initLogger(config)
 	defer func() { _ = logger.Sync() }()
 
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
+	if err != nil {
+		logger.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+	if config.Tracing.Enabled {
+		logger.Info("OpenTelemetry tracing enabled",
+			zap.String("endpoint", config.Tracing.Endpoint),
+			zap.Float64("sample_ratio", config.Tracing.SampleRatio))
+	}
+
+	// --- Secrets ---
+
+	secretStore, err := buildSecretStore(config, logger)
+	if err != nil {
+		logger.Fatal("Failed to create secret store", zap.Error(err))
+	}
+	secretValues := append([]string{
+		config.Jira.APIToken, config.Jira.OAuth.ClientSecret,
+		config.Claude.APIKey, config.Gemini.APIKey,
+		config.Bedrock.AccessKeyID, config.Bedrock.SecretAccessKey, config.Bedrock.SessionToken, config.Bedrock.APIKey,
+		config.AzureOpenAI.APIKey, config.AzureOpenAI.ClientSecret,
+	}, config.Server.Auth.BearerTokens...)
+	for _, instance := range config.Gitea.Instances {
+		secretValues = append(secretValues, instance.Token)
+	}
+	if config.AzureDevOps.Enabled() {
+		secretValues = append(secretValues, config.AzureDevOps.Token)
+	}
+	if err := secretStore.Load(context.Background(), secretValues...); err != nil {
+		logger.Fatal("Failed to load secrets", zap.Error(err))
+	}
+
 	// --- Infrastructure ---
 
 	jiraService := services.NewJiraService(config, logger)
+	jiraService.SetSecretResolver(secretStore)
+	jiraBreaker, err := circuit.NewBreaker(circuit.Config{
+		Threshold:   config.Jira.CircuitBreaker.Threshold,
+		Cooldown:    time.Duration(config.Jira.CircuitBreaker.CooldownSeconds) * time.Second,
+		MaxCooldown: time.Duration(config.Jira.CircuitBreaker.MaxCooldownMinutes) * time.Minute,
+	})
+	if err != nil {
+		logger.Fatal("Failed to create Jira circuit breaker", zap.Error(err))
+	}
+	jiraService.SetCircuitBreaker(jiraBreaker)
+	if err := jiraService.WarmFieldCache(); err != nil {
+		logger.Warn("Failed to load Jira field definitions, will retry on first lookup", zap.Error(err))
+	}
 	gitService := services.NewGitHubService(config, logger)
+	scmRouter, scmHosts, err := buildSCMRouter(gitService, SCMProviderOptions{
+		Config:  config,
+		Secrets: secretStore,
+		Logger:  logger,
+	})
+	if err != nil {
+		logger.Fatal("Failed to create SCM providers", zap.Error(err))
+	}
 
 	issueTracker, err := jira.NewAdapter(jiraService, logger)
 	if err != nil {
 		logger.Fatal("Failed to create issue tracker", zap.Error(err))
 	}
 
-	resolver, err := projectresolver.NewConfigResolver(config)
+	resolver, err := projectresolver.NewConfigResolver(config, projectresolver.WithDefaultBranchLookup(scmRouter))
 	if err != nil {
 		logger.Fatal("Failed to create project resolver", zap.Error(err))
 	}
 
-	wsMgr, err := workspace.NewFSManager(config.Workspaces.BaseDir, gitService, logger)
+	wsMgr, err := workspace.NewFSManager(config.Workspaces.BaseDir, scmRouter, logger)
 	if err != nil {
 		logger.Fatal("Failed to create workspace manager", zap.Error(err))
 	}
@@ -109,15 +182,35 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to create cost tracker", zap.Error(err))
 	}
+	projectCosts := costtracker.NewProjectTracker(
+		filepath.Join(config.Workspaces.BaseDir, "project-cost.json"),
+		logger)
 
 	// --- Executor pipeline ---
 
-	aiAPIKeys := make(map[string]string)
-	if config.Claude.APIKey != "" {
-		aiAPIKeys["claude"] = config.Claude.APIKey
+	aiTransport, err := services.NewHTTPTransport(config.Network)
+	if err != nil {
+		logger.Fatal("Failed to configure outbound network", zap.Error(err))
 	}
-	if config.Gemini.APIKey != "" {
-		aiAPIKeys["gemini"] = config.Gemini.APIKey
+	providers, err := buildAIProviders(AIProviderOptions{
+		Config:     config,
+		HTTPClient: &http.Client{Timeout: 5 * time.Minute, Transport: aiTransport},
+		Secrets:    secretStore,
+		Logger:     logger,
+	})
+	if err != nil {
+		logger.Fatal("Failed to create AI providers", zap.Error(err))
+	}
+	aiAPIKeys := make(map[string]string)
+	agents := make(map[string]executor.AgentRunner)
+	providerLimits := make(map[string]ailimit.ProviderLimits)
+	for name, provider := range providers {
+		if provider.Runner != nil {
+			agents[name] = provider.Runner
+		} else if provider.APIKey != "" {
+			aiAPIKeys[name] = provider.APIKey
+		}
+		providerLimits[name] = provider.Limits
 	}
 
 	var claudeVertex *executor.ClaudeVertexConfig
@@ -129,32 +222,146 @@ func main() {
 		}
 	}
 
-	pipeline, err := executor.NewPipeline(
-		executor.Config{
-			BotUsername:        config.GitHub.BotUsername,
-			DefaultProvider:    config.AIProvider,
-			AIAPIKeys:          aiAPIKeys,
-			ClaudeVertex:       claudeVertex,
-			SessionTimeout:     time.Duration(config.Guardrails.MaxContainerRuntimeMinutes) * time.Minute,
-			IgnoredUsernames:   config.GitHub.IgnoredUsernames,
-			KnownBotUsernames:  config.GitHub.KnownBotUsernames,
-			MaxThreadDepth:     config.GitHub.MaxThreadDepth,
-			DefaultClaudeModel: config.Claude.Model,
-			DefaultGeminiModel: config.Gemini.Model,
-			MaxRetries:         config.Guardrails.MaxRetries,
-			IgnoredCheckNames:  config.GitHub.IgnoredCheckNames,
-			MaxCIFixAttempts:   config.Guardrails.MaxCIFixAttempts,
-			RetryLabel:         config.Guardrails.RetryLabel,
-			JiraUsername:       config.Jira.Username,
-			MinCommentLength:   config.Guardrails.MinCommentLength,
-			GeminiPricing: executor.GeminiPricing{
-				InputPerMTok:  config.Gemini.InputPricePerMTok,
-				OutputPerMTok: config.Gemini.OutputPricePerMTok,
-				CachedPerMTok: config.Gemini.CachedPricePerMTok,
-			},
+	var repoIndex executor.RepoIndex
+	if config.RepoIndex.Enabled {
+		repoIndex, err = repoindex.NewCache(repoindex.Config{
+			Dir:      cmp.Or(config.RepoIndex.CacheDir, config.Workspaces.BaseDir),
+			MaxAge:   time.Duration(config.RepoIndex.RefreshHours) * time.Hour,
+			MaxFiles: config.RepoIndex.MaxFiles,
+		}, logger)
+		if err != nil {
+			logger.Fatal("Failed to create repo index", zap.Error(err))
+		}
+	}
+
+	var ticketIndex executor.TicketIndex
+	if config.DuplicateDetection.Enabled {
+		ticketIndex, err = ticketindex.New(ticketindex.Config{
+			Path:       cmp.Or(config.DuplicateDetection.IndexPath, filepath.Join(config.Workspaces.BaseDir, "ticket-index.json")),
+			Retention:  time.Duration(config.DuplicateDetection.RetentionDays) * 24 * time.Hour,
+			Threshold:  config.DuplicateDetection.Threshold,
+			MaxMatches: config.DuplicateDetection.MaxMatches,
+		}, logger)
+		if err != nil {
+			logger.Fatal("Failed to create ticket index", zap.Error(err))
+		}
+	}
+
+	aiLimiter, err := ailimit.NewLimiter(ailimit.Config{
+		MaxConcurrent: config.Guardrails.MaxConcurrentAISessions,
+		Providers:     providerLimits,
+	})
+	if err != nil {
+		logger.Fatal("Failed to create AI session limiter", zap.Error(err))
+	}
+
+	var links executor.LinkFetcher
+	if config.LinkContext.Enabled {
+		linkTransport, err := services.NewHTTPTransport(config.Network)
+		if err != nil {
+			logger.Fatal("Failed to configure outbound network", zap.Error(err))
+		}
+		links, err = linkcontext.NewFetcher(linkcontext.Config{
+			AllowedDomains: config.LinkContext.AllowedDomains,
+			MaxLinks:       config.LinkContext.MaxLinks,
+			MaxBytes:       config.LinkContext.MaxKB * 1024,
+		}, &http.Client{Timeout: 30 * time.Second, Transport: linkTransport})
+		if err != nil {
+			logger.Fatal("Failed to create link fetcher", zap.Error(err))
+		}
+	}
+
+	var modelRouter executor.ModelRouter
+	if config.ModelRouting.Enabled() {
+		tiers := make(map[string]modelroute.Models, len(config.ModelRouting.Models))
+		for provider, m := range config.ModelRouting.Models {
+			tiers[provider] = modelroute.Models{Simple: m.Simple, Complex: m.Complex}
+		}
+		modelRouter = modelroute.New(modelroute.Config{
+			Models:          tiers,
+			LongDescription: config.ModelRouting.LongDescription,
+			ManyComponents:  config.ModelRouting.ManyComponents,
+			ComplexTypes:    config.ModelRouting.ComplexTypes,
+			MinScore:        config.ModelRouting.MinScore,
+			ComplexLabel:    config.ModelRouting.ComplexLabel,
+			SimpleLabel:     config.ModelRouting.SimpleLabel,
+		})
+	}
+
+	licenses, err := deppolicy.NewDepsDevClient(deppolicy.DefaultDepsDevURL, &http.Client{Timeout: 30 * time.Second})
+	if err != nil {
+		logger.Fatal("Failed to create license lookup client", zap.Error(err))
+	}
+
+	// --- Event bus ---
+	eventBus := events.NewBus(logger)
+	eventBus.Subscribe("audit-log", events.LogHandler(logger))
+	if config.Jira.HistoryComment {
+		historyRecorder, err := history.NewRecorder(issueTracker, logger)
+		if err != nil {
+			logger.Fatal("Failed to create history recorder", zap.Error(err))
+		}
+		eventBus.Subscribe("history-comment", historyRecorder.Handle)
+	}
+	if webhookURL := config.Guardrails.DeadLetterWebhookURL; webhookURL != "" {
+		notifier, err := notify.NewWebhook(webhookURL, config.Jira.BaseURL, &http.Client{Timeout: 30 * time.Second}, logger)
+		if err != nil {
+			logger.Fatal("Failed to create dead-letter notifier", zap.Error(err))
+		}
+		eventBus.Subscribe("dead-letter-webhook", notifier.Handle, events.TicketDeadLettered)
+	}
+
+	pipelineCfg := executor.Config{
+		BotUsername:         config.GitHub.BotUsername,
+		DefaultProvider:     config.AIProvider,
+		AIAPIKeys:           aiAPIKeys,
+		Secrets:             secretStore,
+		ClaudeVertex:        claudeVertex,
+		Agents:              agents,
+		MaxAgentOutputBytes: config.Guardrails.MaxAIOutputMB << 20,
+		MaxAIRetries:        config.Guardrails.MaxAIRetries,
+		MaxParallelRepos:    config.Guardrails.MaxParallelRepos,
+		RepoIndex:           repoIndex,
+		Tickets:             ticketIndex,
+		DuplicateLinkType:   config.DuplicateDetection.LinkType,
+		NotDuplicateLabel:   config.DuplicateDetection.IgnoreLabel,
+		Models:              modelRouter,
+		Links:               links,
+		AILimiter:           aiLimiter,
+		ProjectCosts:        projectCosts,
+		Licenses:            licenses,
+		SetupCacheDir:       config.Container.SetupCacheDir,
+		SessionTimeout:      time.Duration(config.Guardrails.MaxContainerRuntimeMinutes) * time.Minute,
+		IgnoredUsernames:    config.GitHub.IgnoredUsernames,
+		IgnoredCommentPaths: config.GitHub.IgnoredCommentPaths,
+		KnownBotUsernames:   config.GitHub.KnownBotUsernames,
+		MaxThreadDepth:      config.GitHub.MaxThreadDepth,
+		DefaultClaudeModel:  config.Claude.Model,
+		DefaultGeminiModel:  config.Gemini.Model,
+		MaxRetries:          config.Guardrails.MaxRetries,
+		IgnoredCheckNames:   config.GitHub.IgnoredCheckNames,
+		MaxCIFixAttempts:    config.Guardrails.MaxCIFixAttempts,
+		RetryLabel:          config.Guardrails.RetryLabel,
+		DeadLetterLabel:     config.Guardrails.DeadLetterLabel,
+		JiraUsername:        config.Jira.Username,
+		MinCommentLength:    config.Guardrails.MinCommentLength,
+		AttachTranscripts:   config.Jira.AttachTranscripts,
+		Events:              eventBus,
+		Worklog: executor.WorklogConfig{
+			Enabled:     config.Jira.Worklog.Enabled,
+			Author:      cmp.Or(config.Jira.Worklog.Author, config.GitHub.BotUsername),
+			Description: config.Jira.Worklog.Description,
 		},
+		GeminiPricing: executor.GeminiPricing{
+			InputPerMTok:  config.Gemini.InputPricePerMTok,
+			OutputPerMTok: config.Gemini.OutputPricePerMTok,
+			CachedPerMTok: config.Gemini.CachedPricePerMTok,
+		},
+	}
+	pipeline, err := executor.NewPipeline(
+		pipelineCfg,
 		issueTracker,
-		gitService,
+		scmRouter,
 		containerMgr,
 		wsMgr,
 		taskfile.NewMarkdownWriter(),
@@ -165,8 +372,34 @@ func main() {
 		logger.Fatal("Failed to create executor pipeline", zap.Error(err))
 	}
 
+	execute := pipeline.Execute
+	if config.Recording.Dir != "" {
+		recorder, err := replay.NewRecorder(pipelineCfg, replay.Deps{
+			Tracker:    issueTracker,
+			Git:        scmRouter,
+			Containers: containerMgr,
+			Workspaces: wsMgr,
+			TaskWriter: taskfile.NewMarkdownWriter(),
+			Projects:   resolver,
+		}, config.Workspaces.BaseDir, config.Recording.Dir, logger)
+		if err != nil {
+			logger.Fatal("Failed to create job recorder", zap.Error(err))
+		}
+		execute = recorder.Execute
+		logger.Info("Recording jobs for replay", zap.String("dir", config.Recording.Dir))
+	}
+
 	// --- Job manager ---
 
+	lockDir := config.Guardrails.TicketLockDir
+	if lockDir == "" {
+		lockDir = filepath.Join(config.Workspaces.BaseDir, ".locks")
+	}
+	ticketLocks, err := ticketlock.NewFileLocker(lockDir, ticketlock.DefaultStaleAfter, logger)
+	if err != nil {
+		logger.Fatal("Failed to create ticket locker", zap.Error(err))
+	}
+
 	coordinator, err := jobmanager.NewCoordinator(
 		jobmanager.Config{
 			MaxConcurrent:           config.Guardrails.MaxConcurrentJobs,
@@ -175,8 +408,10 @@ func main() {
 			CircuitBreakerWindow:    time.Duration(config.Guardrails.CircuitBreakerWindowMinutes) * time.Minute,
 			CircuitBreakerCooldown:  time.Duration(config.Guardrails.CircuitBreakerCooldownMinutes) * time.Minute,
 			CostRecorder:            costs,
+			JobTimeout:              time.Duration(config.Guardrails.MaxJobRuntimeMinutes) * time.Minute,
+			Locker:                  ticketLocks,
 		},
-		pipeline.Execute,
+		execute,
 		logger,
 	)
 	if err != nil {
@@ -185,7 +420,7 @@ func main() {
 
 	// --- Crash recovery ---
 
-	todoCriteria, inReviewCriteria, activeStatuses := buildScanCriteria(config)
+	inReviewCriteria, activeStatuses := buildScanCriteria(config)
 
 	startupRunner, err := recovery.NewStartupRunner(
 		recovery.Config{
@@ -193,10 +428,11 @@ func main() {
 			WorkspaceTTL:       time.Duration(config.Workspaces.TTLDays) * 24 * time.Hour,
 			BotUsername:        config.GitHub.BotUsername,
 			InProgressCriteria: buildInProgressCriteria(config),
+			InReviewCriteria:   inReviewCriteria,
 			ActiveStatuses:     activeStatuses,
 		},
 		issueTracker,
-		gitService,
+		scmRouter,
 		wsMgr,
 		containerMgr,
 		coordinator,
@@ -223,37 +459,33 @@ func main() {
 		coordinator,
 		issueTracker,
 		config.Guardrails.RetryLabel,
-		scanner.WorkItemScannerConfig{
-			Criteria:     todoCriteria,
-			PollInterval: time.Duration(config.Jira.IntervalSeconds) * time.Second,
-		},
+		workItemScannerConfig(config),
 		logger,
 	)
 	if err != nil {
 		logger.Fatal("Failed to create work item scanner", zap.Error(err))
 	}
+	// Tickets dead-lettered by this process are retried once a human
+	// removes their label.
+	eventBus.Subscribe("dead-letter", func(e events.Event) {
+		ticketScanner.MarkDeadLettered(e.TicketKey)
+	}, events.TicketDeadLettered)
 
 	feedbackScanner, err := scanner.NewFeedbackScanner(
 		issueTracker,
 		coordinator,
-		gitService,
+		scmRouter,
 		resolver,
-		gitService,
-		scanner.FeedbackScannerConfig{
-			Criteria:          inReviewCriteria,
-			PollInterval:      time.Duration(config.Jira.IntervalSeconds) * time.Second,
-			BotUsername:       config.GitHub.BotUsername,
-			IgnoredUsernames:  config.GitHub.IgnoredUsernames,
-			KnownBotUsernames: config.GitHub.KnownBotUsernames,
-			MaxThreadDepth:    config.GitHub.MaxThreadDepth,
-			IgnoredCheckNames: config.GitHub.IgnoredCheckNames,
-			MaxCIFixAttempts:  config.Guardrails.MaxCIFixAttempts,
-			SkipPRLabel:       config.GitHub.SkipPRLabel,
-		},
+		scmRouter,
+		feedbackScannerConfig(config),
 		logger,
 		scanner.WithLabelManager(issueTracker, resolver),
 		scanner.WithLifecycleLabelManager(resolver, resolver, issueTracker),
-		scanner.WithPRLabeler(gitService),
+		scanner.WithPRLabeler(scmRouter),
+		scanner.WithBackports(resolver),
+		scanner.WithReleaseNotes(resolver),
+		scanner.WithMergeOrder(resolver),
+		scanner.WithDiffPreviews(resolver, issueTracker),
 	)
 	if err != nil {
 		logger.Fatal("Failed to create feedback scanner", zap.Error(err))
@@ -262,10 +494,7 @@ func main() {
 	cleanupScanner, err := scanner.NewWorkspaceCleanupScanner(
 		wsMgr,
 		issueTracker,
-		scanner.WorkspaceCleanupConfig{
-			PollInterval:   time.Duration(config.Jira.IntervalSeconds) * time.Second,
-			ActiveStatuses: activeStatuses,
-		},
+		workspaceCleanupConfig(config),
 		logger,
 	)
 	if err != nil {
@@ -275,26 +504,38 @@ func main() {
 	mergeScanner, err := scanner.NewMergeScanner(
 		issueTracker,
 		coordinator,
-		gitService,
+		scmRouter,
 		resolver,
-		gitService,
-		gitService,
-		scanner.MergeScannerConfig{
-			Criteria:          inReviewCriteria,
-			PollInterval:      time.Duration(config.Jira.IntervalSeconds) * time.Second,
-			BotUsername:       config.GitHub.BotUsername,
-			IdleDays:          config.Merge.IdleDays,
-			IdleLabel:         config.Merge.IdleLabel,
-			IgnoredUsernames:  config.GitHub.IgnoredUsernames,
-			KnownBotUsernames: config.GitHub.KnownBotUsernames,
-			SkipPRLabel:       config.GitHub.SkipPRLabel,
-		},
+		scmRouter,
+		scmRouter,
+		mergeScannerConfig(config),
 		logger,
 	)
 	if err != nil {
 		logger.Fatal("Failed to create merge scanner", zap.Error(err))
 	}
 
+	clarificationScanner, err := scanner.NewClarificationScanner(
+		issueTracker,
+		coordinator,
+		issueTracker,
+		clarificationScannerConfig(config),
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("Failed to create clarification scanner", zap.Error(err))
+	}
+
+	triageScanner, err := scanner.NewTriageScanner(
+		issueTracker,
+		coordinator,
+		triageScannerConfig(config),
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("Failed to create triage scanner", zap.Error(err))
+	}
+
 	if err := ticketScanner.Start(ctx); err != nil {
 		logger.Fatal("Failed to start work item scanner", zap.Error(err))
 	}
@@ -307,9 +548,71 @@ func main() {
 	if err := mergeScanner.Start(ctx); err != nil {
 		logger.Fatal("Failed to start merge scanner", zap.Error(err))
 	}
+	if err := clarificationScanner.Start(ctx); err != nil {
+		logger.Fatal("Failed to start clarification scanner", zap.Error(err))
+	}
+	if err := triageScanner.Start(ctx); err != nil {
+		logger.Fatal("Failed to start triage scanner", zap.Error(err))
+	}
 
 	logger.Info("Scanners started")
 
+	// --- Health checks ---
+
+	healthOpts := []health.Option{
+		health.WithProbe("jira", func(ctx context.Context) error { return jiraService.Ping(ctx) }),
+		health.WithProbe("github", func(ctx context.Context) error { return gitService.Ping(ctx) }),
+		health.WithProbe("container_runtime", func(context.Context) error {
+			_, err := exec.LookPath(detected.Path)
+			return err
+		}),
+		health.WithScanner("work_item", ticketScanner),
+		health.WithScanner("feedback", feedbackScanner),
+		health.WithScanner("workspace_cleanup", cleanupScanner),
+		health.WithScanner("merge", mergeScanner),
+		health.WithScanner("clarification", clarificationScanner),
+		health.WithScanner("triage", triageScanner),
+		health.WithAILimiter(aiLimiter),
+		health.WithProjectBudgets(projectCosts),
+		health.WithCircuitBreaker("jira", jiraBreaker),
+	}
+	for host, provider := range scmHosts {
+		healthOpts = append(healthOpts, health.WithProbe(host, func(ctx context.Context) error { return provider.Ping(ctx) }))
+	}
+	healthChecker, err := health.NewChecker(
+		health.Config{
+			WorkspaceDir:      config.Workspaces.BaseDir,
+			MinFreeDiskBytes:  uint64(config.Server.MinFreeDiskMB) << 20, //nolint:gosec // validated non-negative
+			ScannerStaleAfter: scannerStaleAfter(config),
+		},
+		coordinator,
+		logger,
+		healthOpts...,
+	)
+	if err != nil {
+		logger.Fatal("Failed to create health checker", zap.Error(err))
+	}
+
+	// --- Config hot-reload ---
+
+	configReloader, err := configreload.NewReloader(
+		configreload.Config{Path: *configPath},
+		models.LoadConfig,
+		func(newConfig *models.Config) {
+			applyReloadedConfig(newConfig, resolver, scmRouter, ticketScanner, feedbackScanner, cleanupScanner, mergeScanner, clarificationScanner, triageScanner, healthChecker, logger)
+		},
+		logger,
+	)
+	if err != nil {
+		logger.Fatal("Failed to create config reloader", zap.Error(err))
+	}
+	if err := configReloader.Start(ctx); err != nil {
+		logger.Fatal("Failed to start config reloader", zap.Error(err))
+	}
+	if err := secretStore.Start(ctx); err != nil {
+		logger.Fatal("Failed to start secret refresh", zap.Error(err))
+	}
+
 	// --- HTTP server ---
 
 	mux := http.NewServeMux()
@@ -317,6 +620,8 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		_, _ = fmt.Fprint(w, "OK")
 	})
+	mux.HandleFunc("/healthz", healthChecker.LivenessHandler)
+	mux.HandleFunc("/readyz", healthChecker.ReadinessHandler)
 
 	port := config.Server.Port
 	if envPort := os.Getenv("PORT"); envPort != "" {
@@ -325,9 +630,19 @@ func main() {
 		}
 	}
 
+	serverTLS, err := httpserver.TLSConfig(config.Server.TLS)
+	if err != nil {
+		logger.Fatal("Failed to configure server TLS", zap.Error(err))
+	}
+	serverAuth := buildServerAuth(config, secretStore, &http.Client{Timeout: 30 * time.Second, Transport: aiTransport})
+	if serverAuth != nil && serverTLS == nil {
+		logger.Warn("Server authentication is configured without server.tls; credentials are sent in plain text")
+	}
+
 	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", port),
-		Handler:           mux,
+		Addr:              httpserver.ListenAddress(config.Server.Address, port),
+		Handler:           serverHandler(mux, serverAuth, logger),
+		TLSConfig:         serverTLS,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
@@ -335,8 +650,17 @@ func main() {
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
-		logger.Info("Starting server", zap.Int("port", port))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Info("Starting server",
+			zap.String("address", server.Addr),
+			zap.Bool("tls", serverTLS != nil),
+			zap.Bool("auth", serverAuth != nil))
+		var err error
+		if serverTLS != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logger.Error("Server error", zap.Error(err))
 			stop <- syscall.SIGTERM
 		}
@@ -350,13 +674,18 @@ func main() {
 
 	// Stop accepting new work.
 	cancel()
+	configReloader.Stop()
+	secretStore.Stop()
 	ticketScanner.Stop()
 	feedbackScanner.Stop()
 	cleanupScanner.Stop()
 	mergeScanner.Stop()
+	clarificationScanner.Stop()
+	triageScanner.Stop()
 
-	// Drain running jobs.
+	// Drain running jobs, then the events they published.
 	coordinator.Shutdown()
+	eventBus.Close()
 
 	// Shut down HTTP server.
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -365,9 +694,76 @@ func main() {
 		logger.Error("Server shutdown error", zap.Error(err))
 	}
 
+	// Flush buffered spans.
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Tracing shutdown error", zap.Error(err))
+	}
+
 	logger.Info("Shutdown complete")
 }
 
+// buildSecretStore registers a provider for each supported secret
+// manager. Vault is registered only when an address is configured
+// (secrets.vault.address or VAULT_ADDR); AWS and GCP resolve
+// credentials from their SDK defaults on first use.
+func buildSecretStore(config *models.Config, logger *zap.Logger) (*secrets.Store, error) {
+	opts := []secrets.Option{
+		secrets.WithProvider(secrets.SchemeAWS, secrets.NewDefaultAWSProvider(config.Secrets.AWSRegion)),
+		secrets.WithProvider(secrets.SchemeGCP, secrets.NewGCPProvider(secrets.GCPConfig{})),
+	}
+
+	vaultCfg := secrets.VaultConfig{
+		Address:   config.Secrets.Vault.Address,
+		Token:     config.Secrets.Vault.Token,
+		Namespace: config.Secrets.Vault.Namespace,
+	}
+	if vaultCfg.Address == "" {
+		vaultCfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if vaultCfg.Token == "" {
+		vaultCfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if vaultCfg.Address != "" {
+		vaultProvider, err := secrets.NewVaultProvider(vaultCfg, &http.Client{Timeout: 30 * time.Second})
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, secrets.WithProvider(secrets.SchemeVault, vaultProvider))
+	}
+
+	return secrets.NewStore(
+		secrets.Config{RefreshInterval: time.Duration(config.Secrets.RefreshMinutes) * time.Minute},
+		logger,
+		opts...)
+}
+
+// buildServerAuth returns the HTTP server's authenticator for the
+// methods configured in server.auth and server.tls.client_ca_file, or
+// nil when none is. client fetches OIDC signing keys.
+func buildServerAuth(config *models.Config, secretStore *secrets.Store, client *http.Client) httpserver.Authenticator {
+	var auths []httpserver.Authenticator
+	if len(config.Server.Auth.BearerTokens) > 0 {
+		auths = append(auths, httpserver.NewBearerTokens(config.Server.Auth.BearerTokens, secretStore))
+	}
+	if oidc := config.Server.Auth.OIDC; oidc.IssuerURL != "" {
+		auths = append(auths, httpserver.NewOIDC(oidc.IssuerURL, oidc.Audience, client))
+	}
+	if config.Server.TLS.ClientCAFile != "" {
+		auths = append(auths, httpserver.ClientCertificate{})
+	}
+	return httpserver.AnyOf(auths...)
+}
+
+// serverHandler wraps mux with the middleware every request passes
+// through, outermost first: request ID, access log, panic recovery,
+// and authentication.
+func serverHandler(mux *http.ServeMux, auth httpserver.Authenticator, logger *zap.Logger) http.Handler {
+	handler := httpserver.RequireAuth(mux, auth, logger, httpserver.ProbePaths...)
+	handler = httpserver.Recover(handler, logger)
+	handler = httpserver.LogRequests(handler, logger, httpserver.ProbePaths...)
+	return httpserver.RequestID(handler)
+}
+
 // initLogger creates a structured logger from the application config.
 func initLogger(config *models.Config) *zap.Logger {
 	level := getLogLevel(config.Logging.Level)
@@ -410,11 +806,159 @@ func getLogLevel(level models.LogLevel) zapcore.Level {
 	}
 }
 
-// buildScanCriteria constructs the search criteria for both scanners
-// and the set of active statuses for workspace cleanup, derived from
-// the multi-project configuration.
-func buildScanCriteria(config *models.Config) (todo, inReview models.SearchCriteria, activeStatuses map[string]bool) {
-	todoByType := make(map[string][]string)
+// workItemScannerConfig builds the work item scanner settings from
+// the application config.
+func workItemScannerConfig(config *models.Config) scanner.WorkItemScannerConfig {
+	return scanner.WorkItemScannerConfig{
+		PollInterval:    time.Duration(config.Jira.IntervalSeconds) * time.Second,
+		Schedules:       buildWorkItemSchedules(config),
+		DeadLetterLabel: config.Guardrails.DeadLetterLabel,
+	}
+}
+
+// feedbackScannerConfig builds the feedback scanner settings from the
+// application config.
+func feedbackScannerConfig(config *models.Config) scanner.FeedbackScannerConfig {
+	inReview, _ := buildScanCriteria(config)
+	return scanner.FeedbackScannerConfig{
+		Criteria:            inReview,
+		PollInterval:        time.Duration(config.Jira.IntervalSeconds) * time.Second,
+		BotUsername:         config.GitHub.BotUsername,
+		JiraUsername:        config.Jira.Username,
+		IgnoredUsernames:    config.GitHub.IgnoredUsernames,
+		IgnoredCommentPaths: config.GitHub.IgnoredCommentPaths,
+		KnownBotUsernames:   config.GitHub.KnownBotUsernames,
+		MaxThreadDepth:      config.GitHub.MaxThreadDepth,
+		IgnoredCheckNames:   config.GitHub.IgnoredCheckNames,
+		MaxCIFixAttempts:    config.Guardrails.MaxCIFixAttempts,
+		SkipPRLabel:         config.GitHub.SkipPRLabel,
+		QuietPeriod:         time.Duration(config.GitHub.FeedbackQuietMinutes) * time.Minute,
+		Schedule:            cronSchedule(config.Jira.FeedbackSchedule),
+	}
+}
+
+// workspaceCleanupConfig builds the workspace cleanup scanner
+// settings from the application config.
+func workspaceCleanupConfig(config *models.Config) scanner.WorkspaceCleanupConfig {
+	_, activeStatuses := buildScanCriteria(config)
+	return scanner.WorkspaceCleanupConfig{
+		PollInterval:   time.Duration(config.Jira.IntervalSeconds) * time.Second,
+		ActiveStatuses: activeStatuses,
+	}
+}
+
+// mergeScannerConfig builds the merge scanner settings from the
+// application config.
+func mergeScannerConfig(config *models.Config) scanner.MergeScannerConfig {
+	inReview, _ := buildScanCriteria(config)
+	return scanner.MergeScannerConfig{
+		Criteria:          inReview,
+		PollInterval:      time.Duration(config.Jira.IntervalSeconds) * time.Second,
+		BotUsername:       config.GitHub.BotUsername,
+		IdleDays:          config.Merge.IdleDays,
+		IdleLabel:         config.Merge.IdleLabel,
+		IgnoredUsernames:  config.GitHub.IgnoredUsernames,
+		KnownBotUsernames: config.GitHub.KnownBotUsernames,
+		SkipPRLabel:       config.GitHub.SkipPRLabel,
+	}
+}
+
+// applyReloadedConfig pushes a reloaded configuration into the
+// components that support runtime updates: the SCM router (hosts of
+// added repositories), the project resolver (project mappings,
+// profiles and prompts, labels, status transitions), the scanners
+// (criteria, poll intervals, comment filters) and the health checker's
+// scanner staleness window. Everything else — credentials, guardrails, container and
+// server settings — still requires a restart.
+func applyReloadedConfig(
+	config *models.Config,
+	resolver *projectresolver.ConfigResolver,
+	scmRouter *scm.Router,
+	ticketScanner *scanner.WorkItemScanner,
+	feedbackScanner *scanner.FeedbackScanner,
+	cleanupScanner *scanner.WorkspaceCleanupScanner,
+	mergeScanner *scanner.MergeScanner,
+	clarificationScanner *scanner.ClarificationScanner,
+	triageScanner *scanner.TriageScanner,
+	healthChecker *health.Checker,
+	logger *zap.Logger,
+) {
+	addSCMRepos(scmRouter, config, logger)
+	if err := resolver.Update(config); err != nil {
+		logger.Error("Failed to update project resolver", zap.Error(err))
+	}
+	if err := ticketScanner.UpdateConfig(workItemScannerConfig(config)); err != nil {
+		logger.Error("Failed to update work item scanner", zap.Error(err))
+	}
+	if err := feedbackScanner.UpdateConfig(feedbackScannerConfig(config)); err != nil {
+		logger.Error("Failed to update feedback scanner", zap.Error(err))
+	}
+	if err := cleanupScanner.UpdateConfig(workspaceCleanupConfig(config)); err != nil {
+		logger.Error("Failed to update workspace cleanup scanner", zap.Error(err))
+	}
+	if err := mergeScanner.UpdateConfig(mergeScannerConfig(config)); err != nil {
+		logger.Error("Failed to update merge scanner", zap.Error(err))
+	}
+	if err := clarificationScanner.UpdateConfig(clarificationScannerConfig(config)); err != nil {
+		logger.Error("Failed to update clarification scanner", zap.Error(err))
+	}
+	if err := triageScanner.UpdateConfig(triageScannerConfig(config)); err != nil {
+		logger.Error("Failed to update triage scanner", zap.Error(err))
+	}
+	healthChecker.SetScannerStaleAfter(scannerStaleAfter(config))
+}
+
+// triageScannerConfig builds the triage scanner settings from the
+// application config: a query for the triage labels of the projects
+// that enable triage, empty when none does.
+func triageScannerConfig(config *models.Config) scanner.TriageScannerConfig {
+	var projectKeys, labels []string
+	for _, project := range config.Jira.Projects {
+		if !project.Triage.IsEnabled() {
+			continue
+		}
+		projectKeys = append(projectKeys, project.ProjectKeys...)
+		labels = appendUnique(labels, project.Triage.Label)
+	}
+	return scanner.TriageScannerConfig{
+		Criteria: models.SearchCriteria{
+			ProjectKeys: projectKeys,
+			Labels:      labels,
+		},
+		PollInterval: time.Duration(config.Jira.IntervalSeconds) * time.Second,
+	}
+}
+
+// clarificationScannerConfig builds the clarification scanner settings
+// from the application config: a query for the waiting_for_info
+// statuses of all projects, empty when none is configured.
+func clarificationScannerConfig(config *models.Config) scanner.ClarificationScannerConfig {
+	waitingByType := make(map[string][]string)
+	var projectKeys []string
+	for _, project := range config.Jira.Projects {
+		projectKeys = append(projectKeys, project.ProjectKeys...)
+		for ticketType, transitions := range project.StatusTransitions {
+			if transitions.WaitingForInfo != "" {
+				waitingByType[ticketType] = appendUnique(waitingByType[ticketType], transitions.WaitingForInfo)
+			}
+		}
+	}
+	return scanner.ClarificationScannerConfig{
+		Criteria: models.SearchCriteria{
+			ProjectKeys:              projectKeys,
+			StatusByType:             waitingByType,
+			ContributorIsCurrentUser: true,
+		},
+		PollInterval: time.Duration(config.Jira.IntervalSeconds) * time.Second,
+	}
+}
+
+// buildScanCriteria constructs the "in review" search criteria for the
+// feedback and merge scanners and the set of active statuses for
+// workspace cleanup, derived from the multi-project configuration.
+// New-ticket criteria are built per schedule by
+// [buildWorkItemSchedules].
+func buildScanCriteria(config *models.Config) (inReview models.SearchCriteria, activeStatuses map[string]bool) {
 	inReviewByType := make(map[string][]string)
 	activeStatuses = make(map[string]bool)
 	var projectKeys []string
@@ -423,7 +967,6 @@ func buildScanCriteria(config *models.Config) (todo, inReview models.SearchCrite
 		projectKeys = append(projectKeys, project.ProjectKeys...)
 
 		for ticketType, transitions := range project.StatusTransitions {
-			todoByType[ticketType] = appendUnique(todoByType[ticketType], transitions.Todo)
 			inReviewByType[ticketType] = appendUnique(inReviewByType[ticketType], transitions.InReview)
 
 			activeStatuses[transitions.Todo] = true
@@ -432,19 +975,127 @@ func buildScanCriteria(config *models.Config) (todo, inReview models.SearchCrite
 		}
 	}
 
-	todo = models.SearchCriteria{
+	inReview = models.SearchCriteria{
 		ProjectKeys:              projectKeys,
-		StatusByType:             todoByType,
+		StatusByType:             inReviewByType,
 		ContributorIsCurrentUser: true,
 	}
 
-	inReview = models.SearchCriteria{
+	return inReview, activeStatuses
+}
+
+// buildTodoCriteria constructs the new-ticket search criteria for the
+// given projects. Sprint and fix-version filters are taken from the
+// projects as-is; [buildWorkItemSchedules] gives every filtered project
+// its own schedule so that filters never leak into a shared query.
+func buildTodoCriteria(projects []models.ProjectConfig) models.SearchCriteria {
+	todoByType := make(map[string][]string)
+	var projectKeys []string
+	var activeSprint bool
+	var fixVersions []string
+
+	for _, project := range projects {
+		projectKeys = append(projectKeys, project.ProjectKeys...)
+		for ticketType, transitions := range project.StatusTransitions {
+			todoByType[ticketType] = appendUnique(todoByType[ticketType], transitions.Todo)
+		}
+		activeSprint = activeSprint || project.ActiveSprintOnly
+		fixVersions = append(fixVersions, project.FixVersions...)
+	}
+
+	return models.SearchCriteria{
 		ProjectKeys:              projectKeys,
-		StatusByType:             inReviewByType,
+		StatusByType:             todoByType,
 		ContributorIsCurrentUser: true,
+		ActiveSprint:             activeSprint,
+		FixVersions:              fixVersions,
+	}
+}
+
+// buildWorkItemSchedules groups projects into new-ticket scan
+// schedules. Projects without their own interval_seconds, schedule,
+// quiet_hours, or sprint/fix-version filters share one query on the
+// global interval or schedule; every other project gets its own
+// schedule. A project with only an interval_seconds of its own does
+// not inherit the global cron schedule.
+func buildWorkItemSchedules(config *models.Config) []scanner.ScanSchedule {
+	globalInterval := time.Duration(config.Jira.IntervalSeconds) * time.Second
+	globalCron := cronSchedule(config.Jira.Schedule)
+
+	var shared []models.ProjectConfig
+	var custom []scanner.ScanSchedule
+	for _, project := range config.Jira.Projects {
+		if project.IntervalSeconds == 0 && project.Schedule == "" && project.QuietHours == nil &&
+			!project.ActiveSprintOnly && len(project.FixVersions) == 0 {
+			shared = append(shared, project)
+			continue
+		}
+		sched := scanner.ScanSchedule{
+			Criteria:   buildTodoCriteria([]models.ProjectConfig{project}),
+			Interval:   globalInterval,
+			Cron:       globalCron,
+			QuietHours: project.QuietHours,
+		}
+		switch {
+		case project.Schedule != "":
+			sched.Cron = cronSchedule(project.Schedule)
+		case project.IntervalSeconds > 0:
+			sched.Interval = time.Duration(project.IntervalSeconds) * time.Second
+			sched.Cron = nil
+		}
+		custom = append(custom, sched)
+	}
+
+	var schedules []scanner.ScanSchedule
+	if len(shared) > 0 {
+		schedules = append(schedules, scanner.ScanSchedule{
+			Criteria: buildTodoCriteria(shared),
+			Interval: globalInterval,
+			Cron:     globalCron,
+		})
+	}
+	return append(schedules, custom...)
+}
+
+// cronSchedule parses a validated cron expression; empty means none.
+func cronSchedule(expr string) *models.CronSchedule {
+	if expr == "" {
+		return nil
+	}
+	c, err := models.ParseCron(expr)
+	if err != nil {
+		// Unreachable: config validation has already parsed expr.
+		return nil
 	}
+	return c
+}
 
-	return todo, inReview, activeStatuses
+// perMinuteInterval converts a sessions-per-minute limit to the
+// interval between session starts; zero means no limit.
+func perMinuteInterval(perMinute int) time.Duration {
+	if perMinute <= 0 {
+		return 0
+	}
+	return time.Minute / time.Duration(perMinute)
+}
+
+// scannerStaleAfter returns how long a scanner may go without a cycle
+// before the health checks report it stale: three of the longest poll
+// intervals.
+func scannerStaleAfter(config *models.Config) time.Duration {
+	return 3 * maxScanInterval(config)
+}
+
+// maxScanInterval returns the longest new-ticket or global poll
+// interval, used to size the scanner staleness threshold.
+func maxScanInterval(config *models.Config) time.Duration {
+	longest := time.Duration(config.Jira.IntervalSeconds) * time.Second
+	for _, project := range config.Jira.Projects {
+		if d := time.Duration(project.IntervalSeconds) * time.Second; d > longest {
+			longest = d
+		}
+	}
+	return longest
 }
 
 // buildInProgressCriteria constructs the search criteria for finding
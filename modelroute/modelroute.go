@@ -0,0 +1,132 @@
+// Package modelroute chooses the model for a ticket's AI sessions by
+// how complex the ticket looks, so that simple tickets run on a cheap,
+// fast model and complex ones on a premium model.
+//
+// A ticket scores one point for each sign of complexity: a long
+// description, many components, an issue type counted as complex, and
+// the complex label. Tickets reaching the configured score are
+// complex. The complex and simple labels also decide the tier outright,
+// overriding the score, so a ticket can be routed by hand.
+package modelroute
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"jira-ai-issue-solver/models"
+)
+
+// Tier is how complex a ticket is judged to be.
+type Tier string
+
+// Tiers.
+const (
+	Simple  Tier = "simple"
+	Complex Tier = "complex"
+)
+
+// Models are a provider's models per tier. An empty model leaves the
+// provider's configured model in place for that tier.
+type Models struct {
+	Simple  string
+	Complex string
+}
+
+// Config holds construction parameters for [Router].
+type Config struct {
+	// Models maps AI provider names to their models per tier.
+	// Tickets of other providers are not routed.
+	Models map[string]Models
+
+	// LongDescription is the description length, in characters,
+	// from which a description counts as long.
+	LongDescription int
+
+	// ManyComponents is the number of components from which a
+	// ticket counts as touching many.
+	ManyComponents int
+
+	// ComplexTypes lists the issue types counted as complex,
+	// compared case-insensitively.
+	ComplexTypes []string
+
+	// MinScore is the number of signs of complexity that make a
+	// ticket complex.
+	MinScore int
+
+	// ComplexLabel and SimpleLabel route a ticket to their tier
+	// regardless of its score. Empty disables the label.
+	ComplexLabel string
+	SimpleLabel  string
+}
+
+// Decision is the routing of one ticket.
+type Decision struct {
+	Tier Tier
+
+	// Model is the tier's model, or empty if the provider has none
+	// for the tier.
+	Model string
+
+	// Score is the number of signs of complexity found, and Reasons
+	// describes them.
+	Score   int
+	Reasons []string
+
+	// Override reports that a label decided the tier.
+	Override bool
+}
+
+// Router routes tickets to models. It is safe for concurrent use.
+type Router struct {
+	cfg Config
+}
+
+// New creates a Router.
+func New(cfg Config) *Router {
+	return &Router{cfg: cfg}
+}
+
+// Route decides the tier of item and the model provider should use for
+// it. Returns false if provider has no models to route between.
+func (r *Router) Route(provider string, item models.WorkItem) (Decision, bool) {
+	tiers, ok := r.cfg.Models[provider]
+	if !ok {
+		return Decision{}, false
+	}
+
+	var d Decision
+	if n := utf8.RuneCountInString(item.Description); r.cfg.LongDescription > 0 && n >= r.cfg.LongDescription {
+		d.Reasons = append(d.Reasons, fmt.Sprintf("description of %d characters", n))
+	}
+	if n := len(item.Components); r.cfg.ManyComponents > 0 && n >= r.cfg.ManyComponents {
+		d.Reasons = append(d.Reasons, fmt.Sprintf("%d components", n))
+	}
+	if slices.ContainsFunc(r.cfg.ComplexTypes, func(t string) bool { return strings.EqualFold(t, item.Type) }) {
+		d.Reasons = append(d.Reasons, "issue type "+item.Type)
+	}
+	complexLabel := r.cfg.ComplexLabel != "" && slices.Contains(item.Labels, r.cfg.ComplexLabel)
+	if complexLabel {
+		d.Reasons = append(d.Reasons, "label "+r.cfg.ComplexLabel)
+	}
+	d.Score = len(d.Reasons)
+
+	switch {
+	case complexLabel:
+		d.Tier, d.Override = Complex, true
+	case r.cfg.SimpleLabel != "" && slices.Contains(item.Labels, r.cfg.SimpleLabel):
+		d.Tier, d.Override = Simple, true
+	case d.Score >= r.cfg.MinScore:
+		d.Tier = Complex
+	default:
+		d.Tier = Simple
+	}
+
+	d.Model = tiers.Simple
+	if d.Tier == Complex {
+		d.Model = tiers.Complex
+	}
+	return d, true
+}
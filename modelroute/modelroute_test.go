@@ -0,0 +1,69 @@
+package modelroute_test
+
+import (
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/modelroute"
+	"jira-ai-issue-solver/models"
+)
+
+func TestRouter_Route(t *testing.T) {
+	router := modelroute.New(modelroute.Config{
+		Models: map[string]modelroute.Models{
+			"claude": {Simple: "claude-haiku-4-5", Complex: "claude-opus-4-1"},
+			"gemini": {Complex: "gemini-2.5-pro"},
+		},
+		LongDescription: 100,
+		ManyComponents:  2,
+		ComplexTypes:    []string{"Epic", "Story"},
+		MinScore:        2,
+		ComplexLabel:    "ai-complex",
+		SimpleLabel:     "ai-simple",
+	})
+	long := strings.Repeat("x", 100)
+
+	tests := []struct {
+		name         string
+		provider     string
+		item         models.WorkItem
+		wantTier     modelroute.Tier
+		wantModel    string
+		wantOverride bool
+	}{
+		{name: "short bug", provider: "claude", item: models.WorkItem{Type: "Bug", Description: "typo"},
+			wantTier: modelroute.Simple, wantModel: "claude-haiku-4-5"},
+		{name: "one sign", provider: "claude", item: models.WorkItem{Type: "Bug", Description: long},
+			wantTier: modelroute.Simple, wantModel: "claude-haiku-4-5"},
+		{name: "two signs", provider: "claude", item: models.WorkItem{Type: "story", Description: long},
+			wantTier: modelroute.Complex, wantModel: "claude-opus-4-1"},
+		{name: "many components and a long description", provider: "claude",
+			item:     models.WorkItem{Type: "Task", Description: long, Components: []string{"api", "ui"}},
+			wantTier: modelroute.Complex, wantModel: "claude-opus-4-1"},
+		{name: "complex label", provider: "claude", item: models.WorkItem{Type: "Bug", Labels: []string{"ai-complex"}},
+			wantTier: modelroute.Complex, wantModel: "claude-opus-4-1", wantOverride: true},
+		{name: "simple label beats the score", provider: "claude",
+			item:     models.WorkItem{Type: "Epic", Description: long, Labels: []string{"ai-simple"}},
+			wantTier: modelroute.Simple, wantModel: "claude-haiku-4-5", wantOverride: true},
+		{name: "tier without a model", provider: "gemini", item: models.WorkItem{Type: "Bug"},
+			wantTier: modelroute.Simple, wantModel: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := router.Route(tt.provider, tt.item)
+			if !ok {
+				t.Fatal("Route returned false")
+			}
+			if d.Tier != tt.wantTier || d.Model != tt.wantModel || d.Override != tt.wantOverride {
+				t.Errorf("decision = %+v, want tier %s, model %q, override %v", d, tt.wantTier, tt.wantModel, tt.wantOverride)
+			}
+			if d.Score != len(d.Reasons) {
+				t.Errorf("score %d does not match reasons %v", d.Score, d.Reasons)
+			}
+		})
+	}
+
+	if _, ok := router.Route("bedrock", models.WorkItem{}); ok {
+		t.Error("Route for a provider without models returned true")
+	}
+}
@@ -13,4 +13,8 @@ type Author struct {
 
 	// Username is the tracker-specific login (e.g., Jira username, GitHub login).
 	Username string
+
+	// AccountID is the tracker's account identifier, used to assign
+	// work items (e.g., a Jira Cloud accountId). Empty if unknown.
+	AccountID string
 }
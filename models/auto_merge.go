@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Merge methods for [AutoMerge.Method].
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// riskLevels are the risk ratings the AI reports for a change, in
+// increasing order.
+var riskLevels = []string{"low", "medium", "high"}
+
+// AutoMerge enables the host's auto-merge on new bot PRs: the host
+// merges a PR by itself once its required approvals and checks pass,
+// so low-risk fixes reach the target branch without a human pressing
+// merge. Branch protection decides what "ready" means; a repository
+// without required reviews or checks gets nothing merged early,
+// because GitHub refuses auto-merge for PRs that can merge right away.
+type AutoMerge struct {
+	// Method is how PRs are merged: "merge", "squash", or "rebase".
+	// Empty disables auto-merge.
+	Method string `yaml:"method,omitempty" mapstructure:"method"`
+
+	// MaxRisk limits auto-merge to PRs whose AI-reported risk is at
+	// most this: "low", "medium", or "high". PRs without a reported
+	// risk count as high. Empty allows any risk.
+	MaxRisk string `yaml:"max_risk,omitempty" mapstructure:"max_risk"`
+}
+
+// IsEnabled reports whether auto-merge is configured.
+func (a AutoMerge) IsEnabled() bool {
+	return a.Method != ""
+}
+
+// Validate checks the merge method and risk level.
+func (a AutoMerge) Validate() error {
+	switch a.Method {
+	case "", MergeMethodMerge, MergeMethodSquash, MergeMethodRebase:
+	default:
+		return fmt.Errorf("method %q must be merge, squash, or rebase", a.Method)
+	}
+	if a.MaxRisk != "" && !slices.Contains(riskLevels, a.MaxRisk) {
+		return fmt.Errorf("max_risk %q must be low, medium, or high", a.MaxRisk)
+	}
+	return nil
+}
+
+// Allows reports whether a PR whose change the AI rated risk ("" when
+// not reported) may be auto-merged. Always false when auto-merge is
+// disabled.
+func (a AutoMerge) Allows(risk string) bool {
+	if !a.IsEnabled() {
+		return false
+	}
+	if a.MaxRisk == "" {
+		return true
+	}
+	level := slices.Index(riskLevels, risk)
+	if level < 0 {
+		level = len(riskLevels) - 1
+	}
+	return level <= slices.Index(riskLevels, a.MaxRisk)
+}
@@ -0,0 +1,52 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestAutoMerge_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.AutoMerge
+		wantErr bool
+	}{
+		{name: "disabled", cfg: models.AutoMerge{}},
+		{name: "squash", cfg: models.AutoMerge{Method: "squash"}},
+		{name: "rebase with max risk", cfg: models.AutoMerge{Method: "rebase", MaxRisk: "medium"}},
+		{name: "unknown method", cfg: models.AutoMerge{Method: "fast-forward"}, wantErr: true},
+		{name: "unknown risk", cfg: models.AutoMerge{Method: "merge", MaxRisk: "none"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAutoMerge_Allows(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  models.AutoMerge
+		risk string
+		want bool
+	}{
+		{name: "disabled", cfg: models.AutoMerge{}, risk: "low", want: false},
+		{name: "any risk", cfg: models.AutoMerge{Method: "squash"}, risk: "high", want: true},
+		{name: "any risk, unreported", cfg: models.AutoMerge{Method: "squash"}, risk: "", want: true},
+		{name: "below max", cfg: models.AutoMerge{Method: "squash", MaxRisk: "medium"}, risk: "low", want: true},
+		{name: "at max", cfg: models.AutoMerge{Method: "squash", MaxRisk: "medium"}, risk: "medium", want: true},
+		{name: "above max", cfg: models.AutoMerge{Method: "squash", MaxRisk: "medium"}, risk: "high", want: false},
+		{name: "unreported counts as high", cfg: models.AutoMerge{Method: "squash", MaxRisk: "medium"}, risk: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Allows(tt.risk); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.risk, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultBackportBranchTemplate is used when Backport.BranchTemplate
+// is empty.
+const defaultBackportBranchTemplate = "release-{{version}}"
+
+// Backport configures automatic backports of a ticket's merged change
+// to release branches. A ticket labeled with LabelPrefix followed by
+// a version (e.g., "backport-4.17") gets, once its PR is merged, a
+// second PR cherry-picking the change onto the release branch for
+// that version.
+type Backport struct {
+	// LabelPrefix is the Jira label prefix requesting a backport,
+	// e.g. "backport-". The rest of the label is the version. Empty
+	// disables backports.
+	LabelPrefix string `yaml:"label_prefix,omitempty" mapstructure:"label_prefix"`
+
+	// BranchTemplate maps a version to its release branch, with
+	// {{version}} replaced by the version from the label. Empty
+	// means "release-{{version}}".
+	BranchTemplate string `yaml:"branch_template,omitempty" mapstructure:"branch_template"`
+}
+
+// IsEnabled reports whether backports are configured.
+func (b Backport) IsEnabled() bool {
+	return b.LabelPrefix != ""
+}
+
+// Validate checks that the prefix is not blank and that the branch
+// template references {{version}} and no other placeholder.
+func (b Backport) Validate() error {
+	if b.LabelPrefix != "" && strings.TrimSpace(b.LabelPrefix) == "" {
+		return errors.New("label_prefix must not be blank")
+	}
+	if b.BranchTemplate == "" {
+		return nil
+	}
+	matches := commitPlaceholder.FindAllStringSubmatch(b.BranchTemplate, -1)
+	for _, m := range matches {
+		if m[1] != "version" {
+			return fmt.Errorf("branch_template: unknown placeholder %q (use version)", m[0])
+		}
+	}
+	if len(matches) == 0 {
+		return errors.New("branch_template must contain {{version}}")
+	}
+	return nil
+}
+
+// TargetBranches returns the release branches requested by labels, in
+// label order and without duplicates. Labels without the prefix, or
+// with nothing after it, are ignored. Returns nil when backports are
+// disabled.
+func (b Backport) TargetBranches(labels []string) []string {
+	if !b.IsEnabled() {
+		return nil
+	}
+	tmpl := b.BranchTemplate
+	if tmpl == "" {
+		tmpl = defaultBackportBranchTemplate
+	}
+
+	var branches []string
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		version, ok := strings.CutPrefix(label, b.LabelPrefix)
+		if !ok || strings.TrimSpace(version) == "" {
+			continue
+		}
+		branch := commitPlaceholder.ReplaceAllLiteralString(tmpl, version)
+		if !seen[branch] {
+			seen[branch] = true
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// BackportBranchName returns the bot's branch for a ticket's backport
+// to targetBranch: "{bot}/{ticket}-backport-{target}", with slashes
+// in the target replaced by dashes so that the name does not clash
+// with the ticket's own "{bot}/{ticket}" branch.
+func BackportBranchName(botUsername, ticketKey, targetBranch string) string {
+	return fmt.Sprintf("%s/%s-backport-%s", botUsername, ticketKey,
+		strings.ReplaceAll(targetBranch, "/", "-"))
+}
@@ -0,0 +1,58 @@
+package models_test
+
+import (
+	"slices"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestBackport_TargetBranches(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    models.Backport
+		labels []string
+		want   []string
+	}{
+		{name: "disabled", labels: []string{"backport-4.17"}, want: nil},
+		{name: "default template", cfg: models.Backport{LabelPrefix: "backport-"}, labels: []string{"bug", "backport-4.17", "backport-4.16"}, want: []string{"release-4.17", "release-4.16"}},
+		{name: "custom template", cfg: models.Backport{LabelPrefix: "backport-", BranchTemplate: "release/v{{ version }}"}, labels: []string{"backport-1.2"}, want: []string{"release/v1.2"}},
+		{name: "duplicates and empty versions ignored", cfg: models.Backport{LabelPrefix: "backport-"}, labels: []string{"backport-", "backport-4.17", "backport-4.17"}, want: []string{"release-4.17"}},
+		{name: "no matching labels", cfg: models.Backport{LabelPrefix: "backport-"}, labels: []string{"Backport-4.17"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.TargetBranches(tt.labels); !slices.Equal(got, tt.want) {
+				t.Errorf("TargetBranches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackport_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.Backport
+		wantErr bool
+	}{
+		{name: "zero value"},
+		{name: "prefix only", cfg: models.Backport{LabelPrefix: "backport-"}},
+		{name: "custom template", cfg: models.Backport{LabelPrefix: "backport-", BranchTemplate: "release/{{version}}"}},
+		{name: "blank prefix", cfg: models.Backport{LabelPrefix: "  "}, wantErr: true},
+		{name: "template without version", cfg: models.Backport{LabelPrefix: "backport-", BranchTemplate: "release"}, wantErr: true},
+		{name: "unknown placeholder", cfg: models.Backport{LabelPrefix: "backport-", BranchTemplate: "{{ticket}}-{{version}}"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackportBranchName(t *testing.T) {
+	if got, want := models.BackportBranchName("ai-bot", "PROJ-1", "release/4.17"), "ai-bot/PROJ-1-backport-release-4.17"; got != want {
+		t.Errorf("BackportBranchName() = %q, want %q", got, want)
+	}
+}
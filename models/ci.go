@@ -31,3 +31,14 @@ type FailedStep struct {
 	StepName string
 	Log      string
 }
+
+// CheckRun is a completed check run the bot publishes on a commit.
+// Summary and Text are Markdown.
+type CheckRun struct {
+	Name       string
+	HeadSHA    string
+	Conclusion string // "success", "neutral", "failure"
+	Title      string
+	Summary    string
+	Text       string
+}
@@ -0,0 +1,28 @@
+package models
+
+import "strings"
+
+// ClarificationMarker identifies the bot's comment asking clarifying
+// questions on an under-specified ticket.
+const ClarificationMarker = "[AI-BOT-QUESTIONS]"
+
+// botCommentMarker is contained in the markers of all comments the
+// bot posts on tickets (e.g., "[AI-BOT-STATUS]").
+const botCommentMarker = "AI-BOT-"
+
+// ClarificationAnswered reports whether someone replied to the bot's
+// latest clarifying questions: one of comments, which are in posting
+// order, follows the latest [ClarificationMarker] comment and is not
+// one of the bot's own. Returns false when no questions were asked.
+func ClarificationAnswered(comments []Comment) bool {
+	asked, answered := false, false
+	for _, c := range comments {
+		switch {
+		case strings.Contains(c.Body, ClarificationMarker):
+			asked, answered = true, false
+		case asked && !strings.Contains(c.Body, botCommentMarker) && strings.TrimSpace(c.Body) != "":
+			answered = true
+		}
+	}
+	return answered
+}
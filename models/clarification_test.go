@@ -0,0 +1,34 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestClarificationAnswered(t *testing.T) {
+	questions := models.ClarificationMarker + " The AI needs more information"
+	tests := []struct {
+		name     string
+		comments []string
+		want     bool
+	}{
+		{name: "no questions", comments: []string{"Please fix soon"}},
+		{name: "unanswered", comments: []string{"Please fix soon", questions}},
+		{name: "answered", comments: []string{questions, "Use the v2 endpoint."}, want: true},
+		{name: "only bot comments after", comments: []string{questions, "[AI-BOT-STATUS] Retrying"}},
+		{name: "blank reply", comments: []string{questions, "  "}},
+		{name: "answer before latest questions", comments: []string{questions, "Use the v2 endpoint.", questions}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comments []models.Comment
+			for _, body := range tt.comments {
+				comments = append(comments, models.Comment{Body: body})
+			}
+			if got := models.ClarificationAnswered(comments); got != tt.want {
+				t.Errorf("ClarificationAnswered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
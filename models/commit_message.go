@@ -0,0 +1,142 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Default commit message templates used when CommitMessage.Template
+// is empty.
+const (
+	defaultCommitTemplate             = "{{ticket}}: {{summary}}"
+	defaultConventionalCommitTemplate = "{{type}}({{component}}): {{summary}} ({{ticket}})"
+)
+
+// commitPlaceholder matches a "{{name}}" placeholder.
+var commitPlaceholder = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// emptyScope matches a parenthesized {{component}} placeholder, which
+// is dropped when the ticket has no component.
+var emptyScope = regexp.MustCompile(`\(\s*\{\{\s*component\s*\}\}\s*\)`)
+
+// commitPlaceholders lists the placeholders a commit message template
+// may reference.
+var commitPlaceholders = map[string]bool{
+	"ticket":     true,
+	"summary":    true,
+	"component":  true,
+	"type":       true,
+	"issue_type": true,
+}
+
+// conventionalTypes maps lowercased Jira issue types to
+// conventional-commit types. Issue types not listed map to "chore".
+var conventionalTypes = map[string]string{
+	"bug":           "fix",
+	"defect":        "fix",
+	"vulnerability": "fix",
+	"story":         "feat",
+	"feature":       "feat",
+	"new feature":   "feat",
+	"epic":          "feat",
+	"improvement":   "feat",
+	"enhancement":   "feat",
+	"task":          "chore",
+	"sub-task":      "chore",
+	"subtask":       "chore",
+	"documentation": "docs",
+	"refactor":      "refactor",
+	"test":          "test",
+}
+
+// CommitMessage configures the subject of the commit the bot creates
+// for a new ticket. Feedback and merge commits keep their fixed
+// format.
+type CommitMessage struct {
+	// Template is the commit subject with placeholders:
+	// {{ticket}} (ticket key), {{summary}} (ticket summary),
+	// {{component}} (first Jira component), {{type}}
+	// (conventional-commit type inferred from the issue type), and
+	// {{issue_type}} (raw Jira issue type). A parenthesized
+	// {{component}} is dropped for tickets without components, so
+	// "feat({{component}}): ..." renders as "feat: ...". Empty means
+	// "{{ticket}}: {{summary}}", or the conventional-commit template
+	// when Conventional is set.
+	Template string `yaml:"template" mapstructure:"template"`
+
+	// Conventional switches the default template to
+	// "{{type}}({{component}}): {{summary}} ({{ticket}})". Has no
+	// effect when Template is set.
+	Conventional bool `yaml:"conventional" mapstructure:"conventional"`
+
+	// Types overrides the issue-type to conventional-commit type
+	// mapping used for {{type}}. Keys are Jira issue types, matched
+	// case-insensitively (e.g., {"Spike": "chore"}).
+	Types map[string]string `yaml:"types" mapstructure:"types"`
+}
+
+// Validate checks that the template is not blank, references only
+// known placeholders, and that type overrides are non-empty.
+func (c CommitMessage) Validate() error {
+	for _, m := range commitPlaceholder.FindAllStringSubmatch(c.Template, -1) {
+		if !commitPlaceholders[m[1]] {
+			return fmt.Errorf("template: unknown placeholder %q (use ticket, summary, component, type, issue_type)", m[0])
+		}
+	}
+	if c.Template != "" && strings.TrimSpace(c.Template) == "" {
+		return errors.New("template must not be blank")
+	}
+	for issueType, commitType := range c.Types {
+		if strings.TrimSpace(commitType) == "" {
+			return fmt.Errorf("types.%s must not be empty", issueType)
+		}
+	}
+	return nil
+}
+
+// Render builds the commit subject for the given ticket.
+func (c CommitMessage) Render(ticketKey string, item WorkItem) string {
+	tmpl := c.Template
+	if tmpl == "" {
+		tmpl = defaultCommitTemplate
+		if c.Conventional {
+			tmpl = defaultConventionalCommitTemplate
+		}
+	}
+
+	var component string
+	if len(item.Components) > 0 {
+		component = item.Components[0]
+	} else {
+		tmpl = emptyScope.ReplaceAllString(tmpl, "")
+	}
+	values := map[string]string{
+		"ticket":     ticketKey,
+		"summary":    item.Summary,
+		"component":  component,
+		"type":       c.conventionalType(item.Type),
+		"issue_type": item.Type,
+	}
+
+	msg := commitPlaceholder.ReplaceAllStringFunc(tmpl, func(p string) string {
+		return values[commitPlaceholder.FindStringSubmatch(p)[1]]
+	})
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+// conventionalType returns the conventional-commit type for a Jira
+// issue type, preferring the configured overrides.
+func (c CommitMessage) conventionalType(issueType string) string {
+	key := strings.ToLower(strings.TrimSpace(issueType))
+	for name, t := range c.Types {
+		if strings.ToLower(strings.TrimSpace(name)) == key {
+			return strings.TrimSpace(t)
+		}
+	}
+	if t, ok := conventionalTypes[key]; ok {
+		return t
+	}
+	return "chore"
+}
@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestCommitMessage_Render(t *testing.T) {
+	bug := models.WorkItem{Summary: "Fix crash in parse()", Type: "Bug", Components: []string{"api"}}
+	story := models.WorkItem{Summary: "Add export", Type: "Story"}
+
+	tests := []struct {
+		name string
+		cfg  models.CommitMessage
+		item models.WorkItem
+		want string
+	}{
+		{name: "default format", item: bug, want: "PROJ-1: Fix crash in parse()"},
+		{name: "conventional default", cfg: models.CommitMessage{Conventional: true}, item: bug, want: "fix(api): Fix crash in parse() (PROJ-1)"},
+		{name: "conventional without component drops scope", cfg: models.CommitMessage{Conventional: true}, item: story, want: "feat: Add export (PROJ-1)"},
+		{name: "custom template", cfg: models.CommitMessage{Template: "[{{ticket}}] {{issue_type}}: {{summary}}"}, item: story, want: "[PROJ-1] Story: Add export"},
+		{name: "unknown issue type is chore", cfg: models.CommitMessage{Template: "{{type}}: {{summary}}"}, item: models.WorkItem{Summary: "Investigate", Type: "Spike"}, want: "chore: Investigate"},
+		{name: "type override", cfg: models.CommitMessage{Template: "{{ type }}: {{summary}}", Types: map[string]string{"spike": "research"}}, item: models.WorkItem{Summary: "Investigate", Type: "Spike"}, want: "research: Investigate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Render("PROJ-1", tt.item); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitMessage_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.CommitMessage
+		wantErr bool
+	}{
+		{name: "zero value"},
+		{name: "known placeholders", cfg: models.CommitMessage{Template: "{{type}}({{component}}): {{summary}} ({{ticket}}) {{issue_type}}"}},
+		{name: "unknown placeholder", cfg: models.CommitMessage{Template: "{{ticket}}: {{title}}"}, wantErr: true},
+		{name: "blank template", cfg: models.CommitMessage{Template: "   "}, wantErr: true},
+		{name: "empty type override", cfg: models.CommitMessage{Types: map[string]string{"Bug": ""}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/url"
 	"os"
+	"path"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/mitchellh/mapstructure"
@@ -104,6 +107,33 @@ type Profile struct {
 	Instructions      string            `yaml:"instructions" mapstructure:"instructions"`
 	NewTicketWorkflow string            `yaml:"new_ticket_workflow" mapstructure:"new_ticket_workflow"`
 	FeedbackWorkflow  string            `yaml:"feedback_workflow" mapstructure:"feedback_workflow"`
+	Setup             SetupConfig       `yaml:"setup" mapstructure:"setup"`
+}
+
+// SetupConfig describes a command that prepares a repository's
+// dependencies (e.g., "npm ci", "go mod download") in the container
+// before each AI session, so that the AI's builds and test runs do not
+// spend their time installing them.
+//
+// What the command writes to CacheDirs is kept on the host under
+// container.setup_cache_dir, keyed by the container image, the command,
+// and the contents of LockFiles. Later sessions whose key matches get
+// the directories mounted and skip the command.
+type SetupConfig struct {
+	// Command is run with sh in the repository's directory inside
+	// the container. Empty disables setup.
+	Command string `yaml:"command" mapstructure:"command"`
+
+	// LockFiles are repository-relative files whose contents key the
+	// cache (e.g., "package-lock.json", "go.sum"). A change to any of
+	// them reruns the command.
+	LockFiles []string `yaml:"lock_files" mapstructure:"lock_files"`
+
+	// CacheDirs are the directories the command fills, either
+	// absolute container paths (e.g., "/root/go/pkg/mod") or relative
+	// to the repository (e.g., "node_modules"). Empty runs the
+	// command before every session without caching.
+	CacheDirs []string `yaml:"cache_dirs" mapstructure:"cache_dirs"`
 }
 
 // WorkspaceConfig defines a named workspace — a group of one or more
@@ -125,6 +155,19 @@ type WorkspaceConfig struct {
 
 	// Repos lists the repositories in this workspace.
 	Repos []RepoEntry `yaml:"repos" mapstructure:"repos"`
+
+	// MergeOrder lists repositories of a multi-repo workspace, by
+	// name, in the order their pull requests must be merged when a
+	// ticket changes several of them (e.g., an API before its
+	// clients). Each PR body lists its sibling PRs in this order;
+	// repositories not listed may be merged at any time.
+	MergeOrder []string `yaml:"merge_order" mapstructure:"merge_order"`
+
+	// DoNotMergeLabel is added to a ticket's pull requests that come
+	// after another of its pull requests in MergeOrder, and removed
+	// once every earlier one is merged (e.g., "do-not-merge"). Empty
+	// disables the label.
+	DoNotMergeLabel string `yaml:"do_not_merge_label" mapstructure:"do_not_merge_label"`
 }
 
 // RepoEntry associates a repository with a profile within a workspace.
@@ -142,13 +185,92 @@ type RepoEntry struct {
 	Profile string `yaml:"profile" mapstructure:"profile"`
 
 	// TargetBranch is the base branch for pull requests (e.g.,
-	// "main", "master"). Defaults to "main" when empty.
+	// "main", "master"). Defaults to the repository's default branch
+	// on GitHub when empty.
 	TargetBranch string `yaml:"target_branch" mapstructure:"target_branch"`
+
+	// Subdirectory confines the AI to one directory of a monorepo
+	// (e.g., "apps/web"): the task file and relevant-code listing are
+	// limited to it, and changes to files outside it are rejected.
+	// It may instead be given as a fragment of the URL
+	// ("https://github.com/org/mono.git#apps/web"). Empty allows the
+	// whole repository.
+	Subdirectory string `yaml:"subdirectory" mapstructure:"subdirectory"`
+
+	// SparseCheckout checks out only the subdirectory, SharedPaths,
+	// the files at the repository root, and the bot's configuration
+	// directories instead of the whole repository, which speeds up
+	// preparing workspaces for large monorepos and keeps the AI's
+	// working set small. Requires a subdirectory.
+	SparseCheckout bool `yaml:"sparse_checkout" mapstructure:"sparse_checkout"`
+
+	// SharedPaths lists further directories checked out with
+	// SparseCheckout, such as libraries the component builds against
+	// (e.g., "libs/common"). The AI may read them but not change them.
+	SharedPaths []string `yaml:"shared_paths" mapstructure:"shared_paths"`
+
+	// PrivateMirror is the clone URL of a private copy of the repo
+	// (e.g., a GitHub Security Advisory's temporary private fork)
+	// used in its place for embargoed tickets. See [Embargo].
+	PrivateMirror string `yaml:"private_mirror,omitempty" mapstructure:"private_mirror"`
+}
+
+// sparseConfigDirs are checked out with every sparse checkout: the
+// bot reads its per-repo configuration from them.
+var sparseConfigDirs = []string{".ai-bot", ".devcontainer"}
+
+// SparsePaths returns the directories to check out for the entry, or
+// nil when the whole repository is checked out.
+func (r RepoEntry) SparsePaths() []string {
+	_, subdir := r.Location()
+	if !r.SparseCheckout || subdir == "" {
+		return nil
+	}
+	paths := []string{subdir}
+	for _, dir := range r.SharedPaths {
+		paths = append(paths, cleanSubdirectory(dir))
+	}
+	paths = append(paths, sparseConfigDirs...)
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// Location returns the entry's clone URL without any "#subdirectory"
+// fragment, and its subdirectory, cleaned and slash-separated. The
+// Subdirectory field takes precedence over the fragment.
+func (r RepoEntry) Location() (cloneURL, subdirectory string) {
+	cloneURL, fragment, _ := strings.Cut(r.URL, "#")
+	subdirectory = r.Subdirectory
+	if subdirectory == "" {
+		subdirectory = fragment
+	}
+	return cloneURL, cleanSubdirectory(subdirectory)
+}
+
+// cleanSubdirectory normalizes a repository subdirectory to a clean
+// slash-separated path without leading or trailing slashes. The root
+// directory yields "".
+func cleanSubdirectory(dir string) string {
+	dir = strings.Trim(strings.ReplaceAll(strings.TrimSpace(dir), "\\", "/"), "/")
+	if dir == "" {
+		return ""
+	}
+	if dir = path.Clean(dir); dir == "." {
+		return ""
+	}
+	return dir
 }
 
 // ComponentConfig maps a Jira component to a workspace.
 type ComponentConfig struct {
 	Workspace string `yaml:"workspace" mapstructure:"workspace"`
+
+	// TargetBranch overrides the target branch of every repo in the
+	// workspace for tickets with this component, so that, for
+	// example, a "backport-4.18" component can share a workspace
+	// with the main component but open PRs against "release-4.18".
+	// Empty uses each repo's own target branch.
+	TargetBranch string `yaml:"target_branch" mapstructure:"target_branch"`
 }
 
 // ComponentMap preserves the case of component names when parsing YAML.
@@ -192,6 +314,29 @@ func (c *ComponentMap) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// CostBudget caps a project's AI spend. Spend is counted per Jira
+// project key, summed over the project's keys, and resets at 00:00
+// UTC each day and on the 1st of each month. Zero disables a cap.
+type CostBudget struct {
+	DailyUSD   float64 `yaml:"daily_usd" mapstructure:"daily_usd"`
+	MonthlyUSD float64 `yaml:"monthly_usd" mapstructure:"monthly_usd"`
+}
+
+// Enabled reports whether either cap is set.
+func (b CostBudget) Enabled() bool {
+	return b.DailyUSD > 0 || b.MonthlyUSD > 0
+}
+
+func (b CostBudget) validate(prefix string) error {
+	if b.DailyUSD < 0 || math.IsNaN(b.DailyUSD) || math.IsInf(b.DailyUSD, 0) {
+		return fmt.Errorf("%s.daily_usd must be a non-negative finite number", prefix)
+	}
+	if b.MonthlyUSD < 0 || math.IsNaN(b.MonthlyUSD) || math.IsInf(b.MonthlyUSD, 0) {
+		return fmt.Errorf("%s.monthly_usd must be a non-negative finite number", prefix)
+	}
+	return nil
+}
+
 // ProjectKeys is a custom type for parsing project_keys from environment variables and YAML
 type ProjectKeys []string
 
@@ -223,6 +368,32 @@ type StatusTransitions struct {
 	InProgress string `yaml:"in_progress" mapstructure:"in_progress" default:"In Progress"`
 	InReview   string `yaml:"in_review" mapstructure:"in_review" default:"In Review"`
 	Merged     string `yaml:"merged" mapstructure:"merged"`
+	NeedsHuman string `yaml:"needs_human" mapstructure:"needs_human"`
+
+	// WaitingForInfo is the status a ticket moves to when the AI
+	// finds it under-specified and asks questions on it instead of
+	// changing anything. The ticket is solved again once someone
+	// replies. Empty escalates such tickets like ones with no changes.
+	WaitingForInfo string `yaml:"waiting_for_info" mapstructure:"waiting_for_info"`
+}
+
+// statusTransitionsFromMap reads StatusTransitions from decoded
+// configuration data, ignoring fields that are not strings.
+func statusTransitionsFromMap(m map[string]interface{}) StatusTransitions {
+	var transitions StatusTransitions
+	for key, field := range map[string]*string{
+		"todo":             &transitions.Todo,
+		"in_progress":      &transitions.InProgress,
+		"in_review":        &transitions.InReview,
+		"merged":           &transitions.Merged,
+		"needs_human":      &transitions.NeedsHuman,
+		"waiting_for_info": &transitions.WaitingForInfo,
+	} {
+		if v, ok := m[key].(string); ok {
+			*field = v
+		}
+	}
+	return transitions
 }
 
 // TicketTypeStatusTransitions maps ticket types to their specific status transitions
@@ -265,20 +436,7 @@ func (t *TicketTypeStatusTransitions) UnmarshalMapstructure(data interface{}) er
 		// Convert JSON data to TicketTypeStatusTransitions
 		for ticketType, transitionData := range jsonData {
 			if transitionMap, ok := transitionData.(map[string]interface{}); ok {
-				transitions := StatusTransitions{}
-				if todo, ok := transitionMap["todo"].(string); ok {
-					transitions.Todo = todo
-				}
-				if inProgress, ok := transitionMap["in_progress"].(string); ok {
-					transitions.InProgress = inProgress
-				}
-				if inReview, ok := transitionMap["in_review"].(string); ok {
-					transitions.InReview = inReview
-				}
-				if merged, ok := transitionMap["merged"].(string); ok {
-					transitions.Merged = merged
-				}
-				(*t)[ticketType] = transitions
+				(*t)[ticketType] = statusTransitionsFromMap(transitionMap)
 			}
 		}
 		return nil
@@ -290,20 +448,7 @@ func (t *TicketTypeStatusTransitions) UnmarshalMapstructure(data interface{}) er
 		// New format - convert map[string]interface{} to map[string]StatusTransitions
 		for ticketType, transitionData := range mapData {
 			if transitionMap, ok := transitionData.(map[string]interface{}); ok {
-				transitions := StatusTransitions{}
-				if todo, ok := transitionMap["todo"].(string); ok {
-					transitions.Todo = todo
-				}
-				if inProgress, ok := transitionMap["in_progress"].(string); ok {
-					transitions.InProgress = inProgress
-				}
-				if inReview, ok := transitionMap["in_review"].(string); ok {
-					transitions.InReview = inReview
-				}
-				if merged, ok := transitionMap["merged"].(string); ok {
-					transitions.Merged = merged
-				}
-				(*t)[ticketType] = transitions
+				(*t)[ticketType] = statusTransitionsFromMap(transitionMap)
 			}
 		}
 		return nil
@@ -312,6 +457,32 @@ func (t *TicketTypeStatusTransitions) UnmarshalMapstructure(data interface{}) er
 	return fmt.Errorf("unsupported data type for TicketTypeStatusTransitions: %T", data)
 }
 
+// PromptStrategy tailors the new-ticket task file to one ticket type.
+type PromptStrategy struct {
+	// Guidance replaces the built-in approach section of the task
+	// file (e.g., "reproduce the bug with a failing test first").
+	// Empty keeps the built-in guidance for the type.
+	Guidance string `yaml:"guidance" mapstructure:"guidance"`
+
+	// Workflow replaces the profile's new_ticket_workflow for
+	// tickets of this type. Empty keeps the profile's workflow.
+	Workflow string `yaml:"workflow" mapstructure:"workflow"`
+}
+
+// TicketTypePromptStrategies maps ticket types to their prompt
+// strategies.
+type TicketTypePromptStrategies map[string]PromptStrategy
+
+// GetPromptStrategy returns the prompt strategy for a ticket type, or
+// the zero strategy if the type is not configured.
+func (t TicketTypePromptStrategies) GetPromptStrategy(ticketType string) PromptStrategy {
+	if strategy, exists := t[ticketType]; exists {
+		return strategy
+	}
+	// Viper converts YAML keys to lowercase.
+	return t[strings.ToLower(ticketType)]
+}
+
 // ProjectConfig represents configuration for a specific project or group of projects.
 type ProjectConfig struct {
 	ProjectKeys             ProjectKeys                 `yaml:"project_keys" mapstructure:"project_keys"`
@@ -369,6 +540,288 @@ type ProjectConfig struct {
 	// the global default; an explicit negative value disables per-ticket
 	// cost capping for this project.
 	MaxTicketCostUSD *float64 `yaml:"max_ticket_cost_usd,omitempty" mapstructure:"max_ticket_cost_usd"`
+
+	// MaxOpenPRsPerRepo overrides the global
+	// guardrails.max_open_prs_per_repo for this project. Nil means use
+	// the global default; zero disables the limit for this project.
+	MaxOpenPRsPerRepo *int `yaml:"max_open_prs_per_repo,omitempty" mapstructure:"max_open_prs_per_repo"`
+
+	// Budget caps the AI spend of this project's tickets per UTC day
+	// and month. New tickets are deferred while either is spent.
+	Budget CostBudget `yaml:"budget" mapstructure:"budget"`
+
+	// IntervalSeconds overrides jira.interval_seconds for new-ticket
+	// discovery in this project. Zero means use the global interval.
+	IntervalSeconds int `yaml:"interval_seconds,omitempty" mapstructure:"interval_seconds"`
+
+	// Schedule is an optional cron expression (see [CronSchedule])
+	// for new-ticket discovery in this project, overriding
+	// jira.schedule. Mutually exclusive with IntervalSeconds.
+	Schedule string `yaml:"schedule,omitempty" mapstructure:"schedule"`
+
+	// QuietHours optionally defines a recurring window during which
+	// no new tickets are picked up for this project. PR feedback and
+	// in-flight jobs are unaffected. Nil means no quiet hours.
+	QuietHours *QuietHours `yaml:"quiet_hours,omitempty" mapstructure:"quiet_hours"`
+
+	// ActiveSprintOnly limits new-ticket discovery to tickets in a
+	// currently open sprint.
+	ActiveSprintOnly bool `yaml:"active_sprint_only,omitempty" mapstructure:"active_sprint_only"`
+
+	// FixVersions limits new-ticket discovery to tickets targeting one
+	// of the listed releases. Empty means any (or no) fix version.
+	FixVersions []string `yaml:"fix_versions,omitempty" mapstructure:"fix_versions"`
+
+	// FixVersionBranches sets the base branch of a ticket's PRs from
+	// its fix versions, e.g. "4.18.z" to "release-4.18". A match takes
+	// precedence over the component's and the repos' target branches.
+	FixVersionBranches FixVersionBranches `yaml:"fix_version_branches,omitempty" mapstructure:"fix_version_branches"`
+
+	// AssessmentFieldName is an optional text custom field that
+	// receives the bot's own assessment of each new PR (a confidence
+	// level derived from validation results and diff size). Empty
+	// disables the assessment.
+	AssessmentFieldName string `yaml:"assessment_field_name,omitempty" mapstructure:"assessment_field_name"`
+
+	// AcceptanceCriteriaFieldName is an optional custom field holding
+	// a ticket's acceptance criteria (its definition of done). When
+	// set, the field's content is given to the AI alongside the
+	// ticket description and highlighted in the task file. Empty
+	// means acceptance criteria are only found in the description.
+	AcceptanceCriteriaFieldName string `yaml:"acceptance_criteria_field_name,omitempty" mapstructure:"acceptance_criteria_field_name"`
+
+	// BatchLabel groups related tickets into one AI session and one
+	// PR per repository. A ticket carrying this label is solved
+	// together with the other labeled tickets that are linked to it
+	// or share its parent (e.g., its epic), avoiding conflicting PRs
+	// that touch the same files. Empty disables batching.
+	BatchLabel string `yaml:"batch_label,omitempty" mapstructure:"batch_label"`
+
+	// EscalateLowConfidence escalates tickets whose new PR is rated
+	// low confidence (validation failed or the AI exited with an
+	// error) to a human: the failure_labels.needs_human label is
+	// applied and a comment summarizing the attempt is posted. The
+	// ticket stays in review so that PR feedback is still handled.
+	EscalateLowConfidence bool `yaml:"escalate_low_confidence,omitempty" mapstructure:"escalate_low_confidence"`
+
+	// SelfReview runs a second AI session on new-ticket changes
+	// before they are committed. The session reviews the diff against
+	// the ticket and the project instructions and approves it, amends
+	// it, or aborts; an aborted change is escalated to a human instead
+	// of opening a PR. The verdict is included in the PR body.
+	SelfReview bool `yaml:"self_review,omitempty" mapstructure:"self_review"`
+
+	// AutoMerge enables the host's auto-merge on new single-repo bot
+	// PRs, so that they merge once their required approvals and
+	// checks pass. See [AutoMerge].
+	AutoMerge AutoMerge `yaml:"auto_merge,omitempty" mapstructure:"auto_merge"`
+
+	// CheckRun publishes a check run on the commits of the bot's PRs
+	// with the AI's change summary, the self-review verdict, the
+	// commands the AI ran, and the cost, so that reviewers find them
+	// in one place. The check run never fails, so it cannot block a
+	// merge. Ignored on hosts without check runs.
+	CheckRun bool `yaml:"check_run,omitempty" mapstructure:"check_run"`
+
+	// AssignBot assigns each ticket to the jira.bot_account_id account
+	// while a new-ticket job works on it, so that people see the bot
+	// has it. The original assignee is restored when the job ends
+	// (after the PR is opened) and is still the commits' co-author.
+	AssignBot bool `yaml:"assign_bot,omitempty" mapstructure:"assign_bot"`
+
+	// MinConfidence gates new-ticket PRs on the confidence, from 0 to
+	// 100, that the AI reports for its change in its PR summary. A
+	// change reported below this threshold is not committed: the
+	// proposed diff is posted to the ticket for human review and the
+	// ticket is escalated to a human. A change without a reported
+	// confidence is not gated. Zero disables the gate.
+	MinConfidence int `yaml:"min_confidence,omitempty" mapstructure:"min_confidence"`
+
+	// DiffPreview posts new-ticket changes to the ticket for approval
+	// before opening their PR. In "low_confidence" mode it applies to
+	// the changes held back by MinConfidence instead of escalating
+	// them. See [DiffPreview].
+	DiffPreview DiffPreview `yaml:"diff_preview,omitempty" mapstructure:"diff_preview"`
+
+	// RegressionTests selects what happens when the changes for a Bug
+	// ticket add or modify no test file: "warn" puts a prominent
+	// warning at the top of the PR body, and "ai" first runs another
+	// AI session asking for a regression test, warning only if it
+	// still adds none. Empty disables the check.
+	RegressionTests string `yaml:"regression_tests,omitempty" mapstructure:"regression_tests"`
+
+	// FeedbackSplitThreshold splits large PR feedback rounds: when a
+	// round has at least this many new comments on more than one
+	// file, the comments are addressed in one AI session per file
+	// (general comments and CI failures in a final session), each
+	// with a smaller, focused task file. The sessions run one after
+	// another in the same workspace and their changes are committed
+	// together. Applies to single-repository workspaces. Zero
+	// disables splitting.
+	FeedbackSplitThreshold int `yaml:"feedback_split_threshold,omitempty" mapstructure:"feedback_split_threshold"`
+
+	// HumanPushPolicy selects what a feedback round does when someone
+	// pushes to the PR branch while the AI session runs, so that the
+	// bot's push would drop their commits: "rebase" (the default)
+	// rebases the AI's changes onto the new commits, and "defer"
+	// leaves the branch to the human, posting a PR comment instead of
+	// pushing. A rebase that conflicts is deferred. Either way, the
+	// comments of a deferred round are retried in the next round.
+	HumanPushPolicy string `yaml:"human_push_policy,omitempty" mapstructure:"human_push_policy"`
+
+	// SecurityScans are commands run inside the dev container on
+	// new-ticket changes before they are committed (e.g., gitleaks,
+	// semgrep, gosec). A command that exits non-zero blocks the PR:
+	// its output is posted to the ticket and the ticket is escalated
+	// to a human.
+	SecurityScans []SecurityScan `yaml:"security_scans,omitempty" mapstructure:"security_scans"`
+
+	// DependencyPolicy restricts the dependencies new-ticket changes
+	// may add to go.mod, package.json, and requirements.txt files. A
+	// change adding a dependency the policy rejects is not committed;
+	// the violations are posted to the ticket and the ticket is
+	// escalated to a human.
+	DependencyPolicy DependencyPolicy `yaml:"dependency_policy,omitempty" mapstructure:"dependency_policy"`
+
+	// CommitMessage configures the subject of new-ticket commits,
+	// including optional conventional-commit formatting. The zero
+	// value keeps the "KEY: summary" format.
+	CommitMessage CommitMessage `yaml:"commit_message" mapstructure:"commit_message"`
+
+	// Backport opens backport PRs to release branches for tickets
+	// carrying a backport label, once the ticket's PR is merged.
+	Backport Backport `yaml:"backport,omitempty" mapstructure:"backport"`
+
+	// ReleaseNotes has the AI write a user-facing release note for
+	// each merged ticket, into a Jira field or the repository's
+	// changelog. See [ReleaseNotes].
+	ReleaseNotes ReleaseNotes `yaml:"release_notes,omitempty" mapstructure:"release_notes"`
+
+	// Triage has the AI propose the component, severity, possible
+	// duplicates, and effort of tickets carrying the triage label,
+	// instead of solving them. See [Triage].
+	Triage Triage `yaml:"triage,omitempty" mapstructure:"triage"`
+
+	// Spike has the AI explore tickets carrying the spike label in a
+	// short, time-boxed session and post a write-up, instead of
+	// solving them. See [Spike].
+	Spike Spike `yaml:"spike,omitempty" mapstructure:"spike"`
+
+	// Embargo works on tickets for embargoed issues in private
+	// mirrors of the repos, so their fixes never appear in public
+	// PRs.
+	Embargo Embargo `yaml:"embargo,omitempty" mapstructure:"embargo"`
+
+	// PromptStrategies tailors new-ticket AI sessions to the ticket's
+	// type, keyed by type name like StatusTransitions. Types without
+	// an entry use the built-in guidance for bugs, stories, and
+	// tasks (see the taskfile package), or none for other types.
+	PromptStrategies TicketTypePromptStrategies `yaml:"prompt_strategies,omitempty" mapstructure:"prompt_strategies"`
+
+	// CompanionRepos are repositories cloned read-only into every
+	// workspace of the project for context, such as end-to-end tests
+	// kept apart from the component's code. The AI is told where they
+	// are; they are never branched, committed to, or PR'd.
+	CompanionRepos []CompanionRepo `yaml:"companion_repos,omitempty" mapstructure:"companion_repos"`
+}
+
+// CompanionRepo is a repository cloned into the workspace for context.
+// It is cloned under .ai-session/companions/<name>, which is excluded
+// from commits, so changes made to it are never pushed.
+type CompanionRepo struct {
+	// Name is the companion's directory name (e.g., "e2e").
+	Name string `yaml:"name" mapstructure:"name"`
+
+	// URL is the clone URL (e.g., "https://github.com/org/e2e").
+	URL string `yaml:"url" mapstructure:"url"`
+
+	// Ref is the branch or tag to check out. Empty means the
+	// remote's default branch.
+	Ref string `yaml:"ref" mapstructure:"ref"`
+
+	// Description tells the AI what the repository holds and how to
+	// use it (e.g., "End-to-end tests; add a scenario for new API
+	// endpoints in a follow-up PR").
+	Description string `yaml:"description" mapstructure:"description"`
+}
+
+// SecurityScan is a scanner command run on AI changes before they are
+// committed. The command runs with sh -c from the repository's
+// directory in the dev container, with these environment variables:
+//
+//   - AI_BOT_BASE_BRANCH: the branch the PR will target, so that
+//     scanners supporting a baseline (e.g., semgrep
+//     --baseline-commit) report only new findings.
+//   - AI_BOT_CHANGED_FILES: the path of a file listing the changed
+//     files, one per line, relative to the repository root.
+//
+// The command must exit non-zero only for findings that should block
+// the PR, such as new secrets or high-severity issues.
+type SecurityScan struct {
+	// Name identifies the scanner in logs and ticket comments.
+	Name string `yaml:"name" mapstructure:"name"`
+
+	// Command is the shell command to run.
+	Command string `yaml:"command" mapstructure:"command"`
+}
+
+// DependencyPolicy restricts the dependencies AI changes may add.
+// Allow and Deny entries are glob patterns (see [path.Match]) matched
+// against the dependency name, such as "github.com/acme/*" or
+// "@acme/*". License entries are SPDX identifiers, compared
+// case-insensitively. The zero value allows everything.
+type DependencyPolicy struct {
+	// Allow, when non-empty, lists the only dependencies that may be
+	// added.
+	Allow []string `yaml:"allow,omitempty" mapstructure:"allow"`
+
+	// Deny lists dependencies that may not be added. Deny takes
+	// precedence over Allow.
+	Deny []string `yaml:"deny,omitempty" mapstructure:"deny"`
+
+	// AllowedLicenses, when non-empty, lists the only licenses added
+	// dependencies may use. A dependency whose license cannot be
+	// determined is rejected.
+	AllowedLicenses []string `yaml:"allowed_licenses,omitempty" mapstructure:"allowed_licenses"`
+
+	// DeniedLicenses lists licenses added dependencies may not use. A
+	// dependency whose license cannot be determined is rejected.
+	DeniedLicenses []string `yaml:"denied_licenses,omitempty" mapstructure:"denied_licenses"`
+}
+
+// IsEnabled reports whether the policy restricts anything.
+func (d DependencyPolicy) IsEnabled() bool {
+	return len(d.Allow) > 0 || len(d.Deny) > 0 || d.ChecksLicenses()
+}
+
+// ChecksLicenses reports whether the policy restricts licenses.
+func (d DependencyPolicy) ChecksLicenses() bool {
+	return len(d.AllowedLicenses) > 0 || len(d.DeniedLicenses) > 0
+}
+
+// validate checks that no entry is empty and that every pattern is
+// well-formed.
+func (d DependencyPolicy) validate() error {
+	for _, list := range []struct {
+		name    string
+		entries []string
+		glob    bool
+	}{
+		{"allow", d.Allow, true},
+		{"deny", d.Deny, true},
+		{"allowed_licenses", d.AllowedLicenses, false},
+		{"denied_licenses", d.DeniedLicenses, false},
+	} {
+		for i, entry := range list.entries {
+			if strings.TrimSpace(entry) == "" {
+				return fmt.Errorf("%s[%d] must not be empty", list.name, i)
+			}
+			if _, err := path.Match(entry, ""); list.glob && err != nil {
+				return fmt.Errorf("%s[%d] %q is not a valid pattern", list.name, i, entry)
+			}
+		}
+	}
+	return nil
 }
 
 // FailureLabels holds optional Jira label names applied to tickets in
@@ -391,12 +844,18 @@ type FailureLabels struct {
 	// resolve the ticket assignee's GitHub username from the
 	// jira.assignee_to_github_username mapping.
 	ForkUserMissing string `yaml:"fork_user_missing" mapstructure:"fork_user_missing"`
+
+	// NeedsHuman is applied instead of Blocked when the bot escalates
+	// a ticket to a human: the AI produced no changes, retries are
+	// exhausted, or (with escalate_low_confidence) the new PR is
+	// rated low confidence.
+	NeedsHuman string `yaml:"needs_human" mapstructure:"needs_human"`
 }
 
 // All returns the configured label strings in a fixed order. Empty
 // strings (disabled labels) are included; callers should skip them.
 func (fl FailureLabels) All() []string {
-	return []string{fl.CIFailing, fl.Rejected, fl.Blocked, fl.ForkUserMissing}
+	return []string{fl.CIFailing, fl.Rejected, fl.Blocked, fl.ForkUserMissing, fl.NeedsHuman}
 }
 
 // LifecycleLabels holds optional Jira label names that track ticket
@@ -485,6 +944,369 @@ type JiraConfig struct {
 	IntervalSeconds          int               `yaml:"interval_seconds" mapstructure:"interval_seconds" default:"300"`
 	AssigneeToGitHubUsername map[string]string `yaml:"assignee_to_github_username" mapstructure:"assignee_to_github_username"`
 	Projects                 []ProjectConfig   `yaml:"projects" mapstructure:"projects"`
+
+	// MaxSearchResults caps the number of issues a single JQL search
+	// returns across all result pages. Zero means no cap.
+	MaxSearchResults int `yaml:"max_search_results" mapstructure:"max_search_results" default:"1000"`
+
+	// Schedule is an optional cron expression (see [CronSchedule])
+	// for new-ticket discovery in projects without their own
+	// schedule, e.g. "*/5 8-18 * * MON-FRI". Empty scans every
+	// IntervalSeconds.
+	Schedule string `yaml:"schedule" mapstructure:"schedule"`
+
+	// FeedbackSchedule is an optional cron expression for the PR
+	// feedback scanner. Empty scans every IntervalSeconds.
+	FeedbackSchedule string `yaml:"feedback_schedule" mapstructure:"feedback_schedule"`
+
+	// AuthType selects how requests to Jira are authenticated:
+	// "basic" (username and API token), "pat" (the API token sent as
+	// a Bearer token, e.g. a service account's token), or "oauth2"
+	// (OAuth 2.0 three-legged flow with token refresh). The bot uses
+	// the Jira Cloud REST API (v3, ADF bodies) with each of them;
+	// Jira Data Center and Server are not supported.
+	AuthType string `yaml:"auth_type" mapstructure:"auth_type" default:"basic"`
+
+	// OAuth holds the OAuth 2.0 client settings used when AuthType
+	// is "oauth2".
+	OAuth JiraOAuthConfig `yaml:"oauth" mapstructure:"oauth"`
+
+	// Worklog configures recording the bot's processing time as Jira
+	// worklog entries.
+	Worklog JiraWorklogConfig `yaml:"worklog" mapstructure:"worklog"`
+
+	// AttachTranscripts uploads the full transcript of each AI
+	// session (prompt, tool calls, and final output) to the ticket
+	// as an attachment named after the job ID, for auditing.
+	AttachTranscripts bool `yaml:"attach_transcripts" mapstructure:"attach_transcripts"`
+
+	// HistoryComment keeps a single, edited comment on each ticket
+	// listing what the bot did (work started, workspace prepared, AI
+	// sessions, PRs opened, feedback applied).
+	HistoryComment bool `yaml:"history_comment" mapstructure:"history_comment"`
+
+	// BotAccountID is the Jira account ID (accountId) of the bot's
+	// account, which projects with assign_bot assign tickets to while
+	// processing them.
+	BotAccountID string `yaml:"bot_account_id" mapstructure:"bot_account_id"`
+
+	// SecureCommentVisibility restricts the comments the bot posts on
+	// tickets with a security level to a project role or group, so
+	// that processing details of embargoed issues are hidden from
+	// other viewers of the project. Empty posts them unrestricted.
+	SecureCommentVisibility JiraCommentVisibility `yaml:"secure_comment_visibility" mapstructure:"secure_comment_visibility"`
+
+	// CircuitBreaker pauses all Jira calls while Jira is down, so that
+	// scanners and jobs stop retrying per ticket during an outage.
+	CircuitBreaker JiraCircuitBreakerConfig `yaml:"circuit_breaker" mapstructure:"circuit_breaker"`
+
+	// TransitionFields holds values to send with transitions whose
+	// screen requires them, keyed by target status name
+	// (case-insensitive), e.g. a resolution for "Done".
+	TransitionFields map[string]JiraTransitionFields `yaml:"transition_fields" mapstructure:"transition_fields"`
+}
+
+// JiraTransitionFields are the screen values sent with a transition to
+// one status. Values for fields that are not on the transition's
+// screen are left out, so one entry works across workflows that do and
+// do not show a screen for the status.
+type JiraTransitionFields struct {
+	// Resolution is the name of the resolution to set, e.g. "Done".
+	Resolution string `yaml:"resolution" mapstructure:"resolution"`
+
+	// Comment is added to the ticket with the transition.
+	Comment string `yaml:"comment" mapstructure:"comment"`
+
+	// Fields sets other screen fields by field ID (e.g.
+	// "customfield_10010"), with values in the shape the Jira REST API
+	// expects for the field.
+	Fields map[string]any `yaml:"fields" mapstructure:"fields"`
+}
+
+// TransitionFieldsFor returns the configured transition fields for a
+// transition to status.
+func (c JiraConfig) TransitionFieldsFor(status string) (JiraTransitionFields, bool) {
+	for name, tf := range c.TransitionFields {
+		if strings.EqualFold(name, status) {
+			return tf, true
+		}
+	}
+	return JiraTransitionFields{}, false
+}
+
+// JiraCircuitBreakerConfig controls the breaker in front of the Jira
+// API. After Threshold consecutive failed requests (server errors,
+// exhausted rate-limit retries, or failed connections), requests fail
+// immediately for CooldownSeconds; then one trial request is sent. A
+// failed trial doubles the pause, up to MaxCooldownMinutes.
+type JiraCircuitBreakerConfig struct {
+	// Threshold is the number of consecutive failed requests that
+	// opens the breaker. Zero disables it.
+	Threshold int `yaml:"threshold" mapstructure:"threshold" default:"5"`
+
+	// CooldownSeconds is how long requests are paused the first time
+	// the breaker opens.
+	CooldownSeconds int `yaml:"cooldown_seconds" mapstructure:"cooldown_seconds" default:"30"`
+
+	// MaxCooldownMinutes caps the pause as it doubles during a long
+	// outage.
+	MaxCooldownMinutes int `yaml:"max_cooldown_minutes" mapstructure:"max_cooldown_minutes" default:"10"`
+}
+
+// validate checks that the breaker settings are usable.
+func (b JiraCircuitBreakerConfig) validate() error {
+	if b.Threshold < 0 {
+		return errors.New("jira.circuit_breaker.threshold must be non-negative")
+	}
+	if b.Threshold > 0 && b.CooldownSeconds <= 0 {
+		return errors.New("jira.circuit_breaker.cooldown_seconds must be positive")
+	}
+	if b.MaxCooldownMinutes < 0 {
+		return errors.New("jira.circuit_breaker.max_cooldown_minutes must be non-negative")
+	}
+	return nil
+}
+
+// JiraCommentVisibility limits who can see a Jira comment.
+type JiraCommentVisibility struct {
+	// Type is "role" (a project role) or "group".
+	Type string `yaml:"type" mapstructure:"type"`
+
+	// Value is the name of the role or group.
+	Value string `yaml:"value" mapstructure:"value"`
+}
+
+// Comment visibility types for JiraCommentVisibility.Type.
+const (
+	JiraVisibilityRole  = "role"
+	JiraVisibilityGroup = "group"
+)
+
+// IsSet reports whether the visibility restricts comments.
+func (v JiraCommentVisibility) IsSet() bool {
+	return v.Value != ""
+}
+
+// validate checks that a configured visibility names a role or group.
+func (v JiraCommentVisibility) validate() error {
+	if !v.IsSet() && v.Type == "" {
+		return nil
+	}
+	if v.Type != JiraVisibilityRole && v.Type != JiraVisibilityGroup {
+		return fmt.Errorf("jira.secure_comment_visibility.type must be role or group (got %q)", v.Type)
+	}
+	if v.Value == "" {
+		return errors.New("jira.secure_comment_visibility.value is required when type is set")
+	}
+	return nil
+}
+
+// JiraWorklogConfig controls the worklog entry added to a ticket after
+// each job, so that teams tracking effort see the bot's time next to
+// human work logs. Jira records the entry under the authenticated
+// account; Author only changes who the entry's description credits.
+type JiraWorklogConfig struct {
+	// Enabled turns worklog entries on.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Author is the name substituted for {{author}} in Description.
+	// Empty means github.bot_username.
+	Author string `yaml:"author" mapstructure:"author"`
+
+	// Description is the worklog comment. {{author}} is replaced by
+	// Author and {{activity}} by the kind of work done (e.g.,
+	// "implementation", "review feedback").
+	Description string `yaml:"description" mapstructure:"description" default:"{{author}}: {{activity}}"`
+}
+
+// Human push policies for ProjectConfig.HumanPushPolicy.
+const (
+	HumanPushRebase = "rebase"
+	HumanPushDefer  = "defer"
+)
+
+// Regression test policies for ProjectConfig.RegressionTests.
+const (
+	RegressionTestsWarn = "warn"
+	RegressionTestsAI   = "ai"
+)
+
+// Jira authentication types for JiraConfig.AuthType.
+const (
+	JiraAuthBasic  = "basic"
+	JiraAuthPAT    = "pat"
+	JiraAuthOAuth2 = "oauth2"
+)
+
+// AI session modes for Config.Claude.Mode and Config.Gemini.Mode.
+const (
+	AIModeCLI = "cli"
+	AIModeAPI = "api"
+)
+
+// Default Atlassian OAuth 2.0 endpoints.
+const (
+	DefaultJiraOAuthTokenURL = "https://auth.atlassian.com/oauth/token"
+	DefaultJiraOAuthAPIURL   = "https://api.atlassian.com/ex/jira/"
+)
+
+// ServerTLSConfig holds the HTTP server's TLS settings. Setting
+// CertFile and KeyFile serves HTTPS.
+type ServerTLSConfig struct {
+	// CertFile and KeyFile are PEM files holding the server
+	// certificate (with any intermediates) and its private key.
+	CertFile string `yaml:"cert_file" mapstructure:"cert_file"`
+	KeyFile  string `yaml:"key_file" mapstructure:"key_file"`
+
+	// ClientCAFile is a PEM file of CA certificates that sign client
+	// certificates. When set, clients may present a certificate, and
+	// one that verifies authenticates its request (mutual TLS).
+	// Requires CertFile.
+	ClientCAFile string `yaml:"client_ca_file" mapstructure:"client_ca_file"`
+}
+
+// Enabled reports whether the server should serve HTTPS.
+func (t ServerTLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// ServerAuthConfig holds the HTTP server's authentication settings.
+// A request is accepted when any configured method accepts it: one of
+// BearerTokens, an OIDC ID token, or a client certificate verified
+// against server.tls.client_ca_file. With none configured, the server
+// is open.
+type ServerAuthConfig struct {
+	// BearerTokens are static tokens accepted in an
+	// "Authorization: Bearer" header. Each may be a secret reference
+	// (see SecretsConfig).
+	BearerTokens []string `yaml:"bearer_tokens" mapstructure:"bearer_tokens"`
+
+	// OIDC accepts JWTs issued by an OpenID Connect provider.
+	OIDC ServerOIDCConfig `yaml:"oidc" mapstructure:"oidc"`
+}
+
+// ServerOIDCConfig identifies the OpenID Connect provider whose tokens
+// the HTTP server accepts.
+type ServerOIDCConfig struct {
+	// IssuerURL is the provider's issuer (e.g.,
+	// "https://accounts.google.com"). Signing keys are discovered from
+	// its /.well-known/openid-configuration document.
+	IssuerURL string `yaml:"issuer_url" mapstructure:"issuer_url"`
+
+	// Audience is the "aud" claim tokens must carry, typically the
+	// client ID registered for the bot. Required with IssuerURL.
+	Audience string `yaml:"audience" mapstructure:"audience"`
+}
+
+// validateServer checks the HTTP server's listen, TLS, and auth
+// settings.
+func (c *Config) validateServer() error {
+	if c.Server.MinFreeDiskMB < 0 {
+		return errors.New("server.min_free_disk_mb must be non-negative")
+	}
+
+	tlsCfg := c.Server.TLS
+	if (tlsCfg.CertFile == "") != (tlsCfg.KeyFile == "") {
+		return errors.New("server.tls.cert_file and server.tls.key_file must be set together")
+	}
+	if tlsCfg.ClientCAFile != "" && !tlsCfg.Enabled() {
+		return errors.New("server.tls.client_ca_file requires server.tls.cert_file")
+	}
+	for _, f := range []struct{ key, path string }{
+		{"server.tls.cert_file", tlsCfg.CertFile},
+		{"server.tls.key_file", tlsCfg.KeyFile},
+		{"server.tls.client_ca_file", tlsCfg.ClientCAFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); os.IsNotExist(err) {
+			return fmt.Errorf("%s file does not exist: %s", f.key, f.path)
+		}
+	}
+
+	for i, token := range c.Server.Auth.BearerTokens {
+		if strings.TrimSpace(token) == "" {
+			return fmt.Errorf("server.auth.bearer_tokens[%d] must not be empty", i)
+		}
+	}
+	oidc := c.Server.Auth.OIDC
+	if oidc.IssuerURL != "" {
+		u, err := url.Parse(oidc.IssuerURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("server.auth.oidc.issuer_url must be an http or https URL: %s", oidc.IssuerURL)
+		}
+		if oidc.Audience == "" {
+			return errors.New("server.auth.oidc.audience is required with server.auth.oidc.issuer_url")
+		}
+	}
+	return nil
+}
+
+// JiraOAuthConfig holds the settings of an Atlassian OAuth 2.0 (3LO)
+// app. The bot exchanges the refresh token for short-lived access
+// tokens and refreshes them before they expire.
+type JiraOAuthConfig struct {
+	// ClientID is the OAuth app's client ID.
+	ClientID string `yaml:"client_id" mapstructure:"client_id"`
+
+	// ClientSecret is the OAuth app's client secret. May be a secret
+	// manager reference.
+	ClientSecret string `yaml:"client_secret" mapstructure:"client_secret"`
+
+	// RefreshToken is the refresh token obtained when the bot's
+	// Jira user authorized the app (with the offline_access scope).
+	RefreshToken string `yaml:"refresh_token" mapstructure:"refresh_token"`
+
+	// CloudID identifies the Jira Cloud site. API requests are sent
+	// to APIURL + CloudID instead of base_url.
+	CloudID string `yaml:"cloud_id" mapstructure:"cloud_id"`
+
+	// TokenURL overrides the token endpoint. Defaults to
+	// DefaultJiraOAuthTokenURL.
+	TokenURL string `yaml:"token_url" mapstructure:"token_url"`
+
+	// APIURL overrides the API gateway prefix. Defaults to
+	// DefaultJiraOAuthAPIURL.
+	APIURL string `yaml:"api_url" mapstructure:"api_url"`
+
+	// TokenFile, when set, persists rotated refresh tokens so that a
+	// restart does not fall back to an already-rotated RefreshToken.
+	// A refresh token in this file takes precedence over
+	// RefreshToken.
+	TokenFile string `yaml:"token_file" mapstructure:"token_file"`
+}
+
+// validateAuth checks that the credentials required by AuthType are
+// present.
+func (j *JiraConfig) validateAuth() error {
+	switch j.AuthType {
+	case "", JiraAuthBasic:
+		if j.Username == "" {
+			return errors.New("jira.username is required")
+		}
+		if j.APIToken == "" {
+			return errors.New("jira.api_token is required")
+		}
+	case JiraAuthPAT:
+		if j.APIToken == "" {
+			return errors.New("jira.api_token is required for auth_type pat")
+		}
+	case JiraAuthOAuth2:
+		o := j.OAuth
+		if o.ClientID == "" || o.ClientSecret == "" {
+			return errors.New("jira.oauth.client_id and jira.oauth.client_secret are required for auth_type oauth2")
+		}
+		if o.RefreshToken == "" && o.TokenFile == "" {
+			return errors.New("jira.oauth.refresh_token or jira.oauth.token_file is required for auth_type oauth2")
+		}
+		if o.CloudID == "" {
+			return errors.New("jira.oauth.cloud_id is required for auth_type oauth2")
+		}
+	default:
+		return fmt.Errorf("jira.auth_type must be one of basic, pat, oauth2 (got %q)", j.AuthType)
+	}
+	return nil
 }
 
 // Config represents the application configuration
@@ -492,6 +1314,24 @@ type Config struct {
 	// Server configuration
 	Server struct {
 		Port int `yaml:"port" mapstructure:"port" default:"8080"`
+
+		// Address is the interface to listen on (e.g., "127.0.0.1"),
+		// or a full listen address including a port (e.g.,
+		// "127.0.0.1:9090"), which overrides Port. Empty listens on
+		// all interfaces.
+		Address string `yaml:"address" mapstructure:"address"`
+
+		// MinFreeDiskMB is the minimum free space (in megabytes) on the
+		// workspace volume for /readyz to report ready. Zero disables
+		// the disk space check.
+		MinFreeDiskMB int `yaml:"min_free_disk_mb" mapstructure:"min_free_disk_mb" default:"1024"`
+
+		// TLS terminates HTTPS in the bot instead of serving plain HTTP.
+		TLS ServerTLSConfig `yaml:"tls" mapstructure:"tls"`
+
+		// Auth requires credentials on every endpoint except the
+		// health probes (/health, /healthz, /readyz).
+		Auth ServerAuthConfig `yaml:"auth" mapstructure:"auth"`
 	} `yaml:"server" mapstructure:"server"`
 
 	// Logging configuration
@@ -510,19 +1350,22 @@ type Config struct {
 		PrivateKeyPath string `yaml:"private_key_path" mapstructure:"private_key_path"`
 
 		// Common fields
-		BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-		BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"` // Optional: auto-constructed for GitHub App mode
-		PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-		SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`                         // Path to SSH private key for commit signing
-		MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`     // Maximum number of bot replies allowed in a comment thread (e.g., 5 = bot can reply up to 5 times)
-		KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`           // List of known bot usernames to prevent loops
-		IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`               // List of usernames whose PR comments are completely ignored
-		IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`           // Check run names excluded from CI failure detection
-		SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"` // GitHub label that tells the bot to skip a PR
+		BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+		BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"` // Optional: auto-constructed for GitHub App mode
+		PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+		SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`                         // Path to SSH private key for commit signing
+		MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`     // Maximum number of bot replies allowed in a comment thread (e.g., 5 = bot can reply up to 5 times)
+		KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`           // List of known bot usernames to prevent loops
+		IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`               // List of usernames whose PR comments are completely ignored
+		IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`       // File path patterns whose PR review comments are completely ignored (e.g., generated files)
+		IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`           // Check run names excluded from CI failure detection
+		SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"` // PR or Jira ticket label that tells the bot to skip the PR(s)
+		FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`     // Wait until no new review comment arrived for this long before addressing feedback (0 = next scan cycle)
+		SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`                                 // Append a Signed-off-by trailer (bot identity) to bot commits for DCO-enforcing repos
 	} `yaml:"github" mapstructure:"github"`
 
 	// AI Provider selection
-	AIProvider string `yaml:"ai_provider" mapstructure:"ai_provider" default:"claude"` // "claude" or "gemini"
+	AIProvider string `yaml:"ai_provider" mapstructure:"ai_provider" default:"claude"` // "claude", "gemini", "bedrock", "azure_openai", or a provider compiled in with RegisterAIProvider
 
 	// Claude configuration — authentication is needed at the bot level;
 	// CLI path, timeout, and tool settings are configured per-repo via
@@ -547,6 +1390,28 @@ type Config struct {
 		VertexProjectID       string `yaml:"vertex_project_id" mapstructure:"vertex_project_id"`
 		VertexRegion          string `yaml:"vertex_region" mapstructure:"vertex_region"`
 		VertexCredentialsFile string `yaml:"vertex_credentials_file" mapstructure:"vertex_credentials_file"`
+
+		// Mode selects how Claude sessions run: "cli" (default) runs
+		// the Claude Code CLI inside the dev container; "api" runs
+		// the agent loop in the bot, calling the Messages API
+		// directly, so the container image does not need the CLI.
+		// The api mode requires api_key.
+		Mode string `yaml:"mode" mapstructure:"mode"`
+
+		// MaxTurns limits the model calls per session in api mode.
+		MaxTurns int `yaml:"max_turns" mapstructure:"max_turns"`
+
+		// Token pricing (USD per million tokens) for cost estimation
+		// in api mode. Defaults match claude-sonnet-4-6 rates.
+		InputPricePerMTok  float64 `yaml:"input_price_per_mtok" mapstructure:"input_price_per_mtok"`
+		OutputPricePerMTok float64 `yaml:"output_price_per_mtok" mapstructure:"output_price_per_mtok"`
+
+		// MaxConcurrentSessions and SessionsPerMinute bound the
+		// Claude sessions running at once and how often one starts,
+		// to stay within the account's API rate limits. Zero
+		// disables each limit.
+		MaxConcurrentSessions int `yaml:"max_concurrent_sessions" mapstructure:"max_concurrent_sessions"`
+		SessionsPerMinute     int `yaml:"sessions_per_minute" mapstructure:"sessions_per_minute"`
 	} `yaml:"claude" mapstructure:"claude"`
 
 	// Gemini configuration.
@@ -559,8 +1424,37 @@ type Config struct {
 		InputPricePerMTok  float64 `yaml:"input_price_per_mtok" mapstructure:"input_price_per_mtok"`
 		OutputPricePerMTok float64 `yaml:"output_price_per_mtok" mapstructure:"output_price_per_mtok"`
 		CachedPricePerMTok float64 `yaml:"cached_price_per_mtok" mapstructure:"cached_price_per_mtok"`
+
+		// Mode selects how Gemini sessions run: "cli" (default) runs
+		// the gemini CLI inside the dev container; "api" runs the
+		// agent loop in the bot through the Google Gen AI SDK, so
+		// the container image does not need the CLI. The api mode
+		// requires api_key.
+		Mode string `yaml:"mode" mapstructure:"mode"`
+
+		// MaxTurns limits the model calls per session in api mode.
+		MaxTurns int `yaml:"max_turns" mapstructure:"max_turns"`
+
+		// MaxConcurrentSessions and SessionsPerMinute bound the
+		// Gemini sessions running at once and how often one starts.
+		// Zero disables each limit.
+		MaxConcurrentSessions int `yaml:"max_concurrent_sessions" mapstructure:"max_concurrent_sessions"`
+		SessionsPerMinute     int `yaml:"sessions_per_minute" mapstructure:"sessions_per_minute"`
 	} `yaml:"gemini" mapstructure:"gemini"`
 
+	// Bedrock configuration for Claude through Amazon Bedrock
+	Bedrock BedrockConfig `yaml:"bedrock" mapstructure:"bedrock"`
+
+	// AzureOpenAI configuration for models deployed on Azure OpenAI
+	AzureOpenAI AzureOpenAIConfig `yaml:"azure_openai" mapstructure:"azure_openai"`
+
+	// Gitea configuration for repositories on self-hosted Gitea or
+	// Forgejo instances
+	Gitea GiteaConfig `yaml:"gitea" mapstructure:"gitea"`
+
+	// AzureDevOps configuration for repositories in Azure Repos
+	AzureDevOps AzureDevOpsConfig `yaml:"azure_devops" mapstructure:"azure_devops"`
+
 	// Workspaces configuration for ticket-scoped workspace lifecycle
 	Workspaces WorkspacesConfig `yaml:"workspaces" mapstructure:"workspaces"`
 
@@ -572,6 +1466,621 @@ type Config struct {
 
 	// Merge configuration for auto-merge scanner behavior
 	Merge MergeConfig `yaml:"merge" mapstructure:"merge"`
+
+	// Tracing configuration for OpenTelemetry span export
+	Tracing TracingConfig `yaml:"tracing" mapstructure:"tracing"`
+
+	// RepoIndex configuration for pointing the AI at relevant code
+	RepoIndex RepoIndexConfig `yaml:"repo_index" mapstructure:"repo_index"`
+
+	// DuplicateDetection configuration for flagging likely duplicate
+	// tickets before solving them
+	DuplicateDetection DuplicateDetectionConfig `yaml:"duplicate_detection" mapstructure:"duplicate_detection"`
+
+	// LinkContext configuration for fetching pages linked from tickets
+	LinkContext LinkContextConfig `yaml:"link_context" mapstructure:"link_context"`
+
+	// ModelRouting configuration for choosing models by ticket
+	// complexity
+	ModelRouting ModelRoutingConfig `yaml:"model_routing" mapstructure:"model_routing"`
+
+	// Recording configuration for saving jobs' external interactions
+	// for replay
+	Recording RecordingConfig `yaml:"recording" mapstructure:"recording"`
+
+	// Secrets configuration for fetching credentials from external
+	// secret managers
+	Secrets SecretsConfig `yaml:"secrets" mapstructure:"secrets"`
+
+	// Network configuration for outbound proxies and TLS
+	Network NetworkConfig `yaml:"network" mapstructure:"network"`
+}
+
+// NetworkConfig holds settings for outbound connections to Jira,
+// GitHub (API and git), and the AI provider APIs, for networks that
+// route traffic through a proxy or intercept TLS.
+type NetworkConfig struct {
+	// ProxyURL is the proxy for outbound HTTP and HTTPS connections
+	// (e.g., "http://proxy.corp.example.com:3128"). When empty, the
+	// standard HTTPS_PROXY, HTTP_PROXY, and NO_PROXY environment
+	// variables apply.
+	ProxyURL string `yaml:"proxy_url" mapstructure:"proxy_url"`
+
+	// NoProxy lists the hosts connected to directly rather than
+	// through ProxyURL, in NO_PROXY syntax (comma-separated host
+	// names, domain suffixes, IP addresses, and CIDR ranges).
+	NoProxy string `yaml:"no_proxy" mapstructure:"no_proxy"`
+
+	// CABundle is a PEM file of CA certificates to trust in addition
+	// to the system roots, such as the root of a TLS-intercepting
+	// proxy. git uses it in place of its default bundle.
+	CABundle string `yaml:"ca_bundle" mapstructure:"ca_bundle"`
+
+	// ClientCert and ClientKey are PEM files holding a TLS client
+	// certificate and its private key, presented to servers that
+	// request one. Set both or neither.
+	ClientCert string `yaml:"client_cert" mapstructure:"client_cert"`
+	ClientKey  string `yaml:"client_key" mapstructure:"client_key"`
+}
+
+func (n *NetworkConfig) validate() error {
+	if n.ProxyURL != "" {
+		u, err := url.Parse(n.ProxyURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("network.proxy_url must be an http or https URL: %s", n.ProxyURL)
+		}
+	}
+	if (n.ClientCert == "") != (n.ClientKey == "") {
+		return errors.New("network.client_cert and network.client_key must be set together")
+	}
+	for _, f := range []struct{ key, path string }{
+		{"network.ca_bundle", n.CABundle},
+		{"network.client_cert", n.ClientCert},
+		{"network.client_key", n.ClientKey},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); os.IsNotExist(err) {
+			return fmt.Errorf("%s file does not exist: %s", f.key, f.path)
+		}
+	}
+	return nil
+}
+
+// SecretsConfig holds settings for resolving credentials stored in an
+// external secret manager. jira.api_token, jira.oauth.client_secret,
+// claude.api_key, and gemini.api_key may be set to a secret reference (e.g.,
+// "vault://secret/data/jira#api_token", "awssm://prod/jira-bot#token",
+// "gcpsm://projects/p/secrets/jira-token") instead of a plaintext
+// value. References are fetched at startup and refreshed periodically.
+type SecretsConfig struct {
+	// RefreshMinutes is how often referenced secrets are re-fetched
+	// so that rotations take effect without a restart. Zero disables
+	// refresh.
+	RefreshMinutes int `yaml:"refresh_minutes" mapstructure:"refresh_minutes" default:"15"`
+
+	// Vault connection settings, required for vault:// references.
+	Vault VaultConfig `yaml:"vault" mapstructure:"vault"`
+
+	// AWSRegion is the region for awssm:// references. When empty,
+	// the AWS SDK's default region resolution (AWS_REGION, shared
+	// config) is used.
+	AWSRegion string `yaml:"aws_region" mapstructure:"aws_region"`
+}
+
+// VaultConfig holds HashiCorp Vault connection settings.
+type VaultConfig struct {
+	// Address is the Vault server URL. When empty, VAULT_ADDR is used.
+	Address string `yaml:"address" mapstructure:"address"`
+
+	// Token authenticates to Vault. When empty, VAULT_TOKEN is used.
+	Token string `yaml:"token" mapstructure:"token"`
+
+	// Namespace is the Vault Enterprise namespace. Optional.
+	Namespace string `yaml:"namespace" mapstructure:"namespace"`
+}
+
+func (s *SecretsConfig) validate() error {
+	if s.RefreshMinutes < 0 {
+		return errors.New("secrets.refresh_minutes must be non-negative")
+	}
+	return nil
+}
+
+// TracingConfig holds OpenTelemetry tracing settings. When enabled,
+// pipeline stages (Jira fetch, clone, AI session, commit, PR creation)
+// are exported as spans over OTLP/HTTP with the ticket key attached
+// as a span attribute.
+type TracingConfig struct {
+	// Enabled turns on span export. When false, tracing calls are
+	// no-ops.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Endpoint is the OTLP/HTTP collector address (host:port, e.g.,
+	// "otel-collector:4318"). When empty, the standard
+	// OTEL_EXPORTER_OTLP_ENDPOINT environment variable or the
+	// exporter default (localhost:4318) is used.
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+
+	// Insecure disables TLS for the collector connection.
+	Insecure bool `yaml:"insecure" mapstructure:"insecure"`
+
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string `yaml:"service_name" mapstructure:"service_name" default:"jira-ai-issue-solver"`
+
+	// SampleRatio is the fraction of jobs traced, from 0 to 1.
+	SampleRatio float64 `yaml:"sample_ratio" mapstructure:"sample_ratio" default:"1"`
+}
+
+func (t *TracingConfig) validate() error {
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return errors.New("tracing.sample_ratio must be between 0 and 1")
+	}
+	if t.Enabled && strings.TrimSpace(t.ServiceName) == "" {
+		return errors.New("tracing.service_name is required when tracing is enabled")
+	}
+	return nil
+}
+
+// RepoIndexConfig holds settings for the repository index. When
+// enabled, each repository's source files and the symbols they define
+// are indexed, and the files that best match a new ticket's summary
+// and description are listed in its task file. This helps on large
+// codebases where the AI would otherwise spend much of its context
+// finding where a change belongs.
+type RepoIndexConfig struct {
+	// Enabled turns on indexing.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// CacheDir is where indexes are stored, one file per repository.
+	// When empty, workspaces.base_dir is used.
+	CacheDir string `yaml:"cache_dir" mapstructure:"cache_dir"`
+
+	// RefreshHours is how long an index is used before the repository
+	// is re-indexed from the next workspace that clones it.
+	RefreshHours int `yaml:"refresh_hours" mapstructure:"refresh_hours" default:"24"`
+
+	// MaxFiles is the number of relevant files listed in the task
+	// file.
+	MaxFiles int `yaml:"max_files" mapstructure:"max_files" default:"20"`
+}
+
+func (r *RepoIndexConfig) validate() error {
+	if !r.Enabled {
+		return nil
+	}
+	if r.RefreshHours <= 0 {
+		return errors.New("repo_index.refresh_hours must be positive")
+	}
+	if r.MaxFiles <= 0 {
+		return errors.New("repo_index.max_files must be positive")
+	}
+	return nil
+}
+
+// DuplicateDetectionConfig holds settings for duplicate detection.
+// When enabled, the summary and description of every ticket the bot
+// starts on are indexed, and a new ticket that closely resembles a
+// recently processed ticket of the same project is linked to it and
+// held back with a comment instead of being solved again.
+type DuplicateDetectionConfig struct {
+	// Enabled turns on duplicate detection.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// IndexPath is the file the ticket index is stored in. When
+	// empty, ticket-index.json under workspaces.base_dir is used.
+	IndexPath string `yaml:"index_path" mapstructure:"index_path"`
+
+	// Threshold is the similarity, between 0 and 1, at which a
+	// ticket counts as a likely duplicate. Higher values flag fewer
+	// tickets.
+	Threshold float64 `yaml:"threshold" mapstructure:"threshold" default:"0.5"`
+
+	// RetentionDays is how long a processed ticket is remembered.
+	RetentionDays int `yaml:"retention_days" mapstructure:"retention_days" default:"90"`
+
+	// MaxMatches is the number of likely duplicates linked and
+	// listed on a flagged ticket.
+	MaxMatches int `yaml:"max_matches" mapstructure:"max_matches" default:"3"`
+
+	// LinkType is the Jira link type used to link a flagged ticket
+	// to its likely duplicates.
+	LinkType string `yaml:"link_type" mapstructure:"link_type" default:"Relates"`
+
+	// IgnoreLabel, when present on a flagged ticket, tells the bot
+	// the ticket is not a duplicate, so that it is solved anyway.
+	IgnoreLabel string `yaml:"ignore_label" mapstructure:"ignore_label" default:"ai-not-duplicate"`
+}
+
+func (d *DuplicateDetectionConfig) validate() error {
+	if !d.Enabled {
+		return nil
+	}
+	if d.Threshold <= 0 || d.Threshold > 1 {
+		return errors.New("duplicate_detection.threshold must be greater than 0 and at most 1")
+	}
+	if d.RetentionDays <= 0 {
+		return errors.New("duplicate_detection.retention_days must be positive")
+	}
+	if d.MaxMatches <= 0 {
+		return errors.New("duplicate_detection.max_matches must be positive")
+	}
+	if strings.TrimSpace(d.LinkType) == "" {
+		return errors.New("duplicate_detection.link_type is required when duplicate_detection is enabled")
+	}
+	if strings.TrimSpace(d.IgnoreLabel) == "" {
+		return errors.New("duplicate_detection.ignore_label is required when duplicate_detection is enabled")
+	}
+	return nil
+}
+
+// BedrockConfig holds settings for running Claude through Amazon
+// Bedrock (ai_provider "bedrock"), for deployments that may not call
+// Anthropic directly. Sessions run in the bot, as in Claude's api
+// mode. Requests are signed with the AWS access keys, or carry a
+// Bedrock API key when no access key is set.
+type BedrockConfig struct {
+	// Region is the AWS region of the Bedrock endpoint.
+	Region string `yaml:"region" mapstructure:"region"`
+
+	// Model is the Bedrock model or inference profile ID (e.g.,
+	// "us.anthropic.claude-sonnet-4-5-20250929-v1:0").
+	Model string `yaml:"model" mapstructure:"model"`
+
+	// AWS credentials. SessionToken is only needed for temporary
+	// credentials. Each may be a secret reference.
+	AccessKeyID     string `yaml:"access_key_id" mapstructure:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" mapstructure:"secret_access_key"`
+	SessionToken    string `yaml:"session_token" mapstructure:"session_token"`
+
+	// APIKey is a Bedrock API key, used instead of access keys.
+	APIKey string `yaml:"api_key" mapstructure:"api_key"`
+
+	// Endpoint overrides the regional endpoint, e.g. for a VPC
+	// endpoint. Empty means https://bedrock-runtime.<region>.amazonaws.com.
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+
+	// MaxTurns limits the model calls per session.
+	MaxTurns int `yaml:"max_turns" mapstructure:"max_turns"`
+
+	// Token pricing (USD per million tokens) for cost estimation.
+	// Defaults match Claude Sonnet rates.
+	InputPricePerMTok  float64 `yaml:"input_price_per_mtok" mapstructure:"input_price_per_mtok"`
+	OutputPricePerMTok float64 `yaml:"output_price_per_mtok" mapstructure:"output_price_per_mtok"`
+
+	// MaxConcurrentSessions and SessionsPerMinute bound the sessions
+	// running at once and how often one starts. Zero disables each
+	// limit.
+	MaxConcurrentSessions int `yaml:"max_concurrent_sessions" mapstructure:"max_concurrent_sessions"`
+	SessionsPerMinute     int `yaml:"sessions_per_minute" mapstructure:"sessions_per_minute"`
+}
+
+// Enabled reports whether Bedrock is configured.
+func (b *BedrockConfig) Enabled() bool {
+	return b.Model != ""
+}
+
+// validate checks the Bedrock settings. selected is whether
+// ai_provider is "bedrock", which requires them.
+func (b *BedrockConfig) validate(selected bool) error {
+	if !selected && !b.Enabled() {
+		return nil
+	}
+	if b.Region == "" {
+		return errors.New("bedrock.region is required")
+	}
+	if b.Model == "" {
+		return errors.New("bedrock.model is required")
+	}
+	hasKeys := b.AccessKeyID != "" || b.SecretAccessKey != ""
+	switch {
+	case hasKeys && (b.AccessKeyID == "" || b.SecretAccessKey == ""):
+		return errors.New("bedrock: access_key_id and secret_access_key must be set together")
+	case hasKeys && b.APIKey != "":
+		return errors.New("bedrock: access keys and api_key are mutually exclusive")
+	case !hasKeys && b.APIKey == "":
+		return errors.New("bedrock: set access_key_id and secret_access_key, or api_key")
+	}
+	if b.Endpoint != "" {
+		if u, err := url.Parse(b.Endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("bedrock.endpoint %q is not an http(s) URL", b.Endpoint)
+		}
+	}
+	if b.MaxTurns <= 0 {
+		return errors.New("bedrock.max_turns must be positive")
+	}
+	return nil
+}
+
+// AzureOpenAIConfig holds settings for running sessions against a
+// model deployed on Azure OpenAI (ai_provider "azure_openai"). Sessions
+// run in the bot. Requests authenticate with the resource's API key,
+// or with a Microsoft Entra ID token for an application when no key
+// is set.
+type AzureOpenAIConfig struct {
+	// Endpoint is the resource endpoint, e.g.
+	// "https://my-resource.openai.azure.com".
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+
+	// Deployment is the name of the model deployment to use.
+	Deployment string `yaml:"deployment" mapstructure:"deployment"`
+
+	// APIVersion is the Azure OpenAI API version. Empty means the
+	// bot's default.
+	APIVersion string `yaml:"api_version" mapstructure:"api_version"`
+
+	// APIKey is the resource key. May be a secret reference.
+	APIKey string `yaml:"api_key" mapstructure:"api_key"`
+
+	// Entra ID application (service principal) credentials, used
+	// instead of an API key. ClientSecret may be a secret reference.
+	TenantID     string `yaml:"tenant_id" mapstructure:"tenant_id"`
+	ClientID     string `yaml:"client_id" mapstructure:"client_id"`
+	ClientSecret string `yaml:"client_secret" mapstructure:"client_secret"`
+
+	// MaxTurns limits the model calls per session.
+	MaxTurns int `yaml:"max_turns" mapstructure:"max_turns"`
+
+	// Token pricing (USD per million tokens) for cost estimation.
+	// Defaults match gpt-4.1 rates.
+	InputPricePerMTok  float64 `yaml:"input_price_per_mtok" mapstructure:"input_price_per_mtok"`
+	OutputPricePerMTok float64 `yaml:"output_price_per_mtok" mapstructure:"output_price_per_mtok"`
+	CachedPricePerMTok float64 `yaml:"cached_price_per_mtok" mapstructure:"cached_price_per_mtok"`
+
+	// MaxConcurrentSessions and SessionsPerMinute bound the sessions
+	// running at once and how often one starts. Zero disables each
+	// limit.
+	MaxConcurrentSessions int `yaml:"max_concurrent_sessions" mapstructure:"max_concurrent_sessions"`
+	SessionsPerMinute     int `yaml:"sessions_per_minute" mapstructure:"sessions_per_minute"`
+}
+
+// Enabled reports whether Azure OpenAI is configured.
+func (a *AzureOpenAIConfig) Enabled() bool {
+	return a.Endpoint != ""
+}
+
+// validate checks the Azure OpenAI settings. selected is whether
+// ai_provider is "azure_openai", which requires them.
+func (a *AzureOpenAIConfig) validate(selected bool) error {
+	if !selected && !a.Enabled() {
+		return nil
+	}
+	if u, err := url.Parse(a.Endpoint); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("azure_openai.endpoint %q is not an http(s) URL", a.Endpoint)
+	}
+	if a.Deployment == "" {
+		return errors.New("azure_openai.deployment is required")
+	}
+	hasEntra := a.TenantID != "" || a.ClientID != "" || a.ClientSecret != ""
+	switch {
+	case hasEntra && a.APIKey != "":
+		return errors.New("azure_openai: api_key and tenant_id/client_id/client_secret are mutually exclusive")
+	case hasEntra && (a.TenantID == "" || a.ClientID == "" || a.ClientSecret == ""):
+		return errors.New("azure_openai: tenant_id, client_id, and client_secret must be set together")
+	case !hasEntra && a.APIKey == "":
+		return errors.New("azure_openai: set api_key, or tenant_id, client_id, and client_secret")
+	}
+	if a.MaxTurns <= 0 {
+		return errors.New("azure_openai.max_turns must be positive")
+	}
+	return nil
+}
+
+// GiteaConfig lists the self-hosted Gitea or Forgejo instances that
+// serve repositories instead of GitHub. A repository whose URL host
+// matches an instance's Host is cloned, pushed, and reviewed there.
+type GiteaConfig struct {
+	Instances []GiteaInstance `yaml:"instances" mapstructure:"instances"`
+}
+
+// GiteaInstance is one Gitea or Forgejo server.
+type GiteaInstance struct {
+	// Host is the host name in repository URLs (e.g.,
+	// "forgejo.internal.example.com").
+	Host string `yaml:"host" mapstructure:"host"`
+
+	// APIURL is the API endpoint. Defaults to https://<host>/api/v1.
+	APIURL string `yaml:"api_url" mapstructure:"api_url"`
+
+	// Token is an access token of the bot's account on the instance,
+	// which should have the login github.bot_username so that the
+	// bot recognizes its own comments. May be a secret reference.
+	Token string `yaml:"token" mapstructure:"token"`
+}
+
+func (g *GiteaConfig) validate() error {
+	seen := make(map[string]bool, len(g.Instances))
+	for i, inst := range g.Instances {
+		prefix := fmt.Sprintf("gitea.instances[%d]", i)
+		host := strings.ToLower(strings.TrimSpace(inst.Host))
+		if host == "" || strings.ContainsAny(host, "/:@") {
+			return fmt.Errorf("%s.host: %q is not a host name", prefix, inst.Host)
+		}
+		if host == "github.com" {
+			return fmt.Errorf("%s.host must not be github.com", prefix)
+		}
+		if seen[host] {
+			return fmt.Errorf("%s.host: %s is listed twice", prefix, host)
+		}
+		seen[host] = true
+		if inst.APIURL != "" {
+			if u, err := url.Parse(inst.APIURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("%s.api_url: %q is not an http(s) URL", prefix, inst.APIURL)
+			}
+		}
+		if inst.Token == "" {
+			return fmt.Errorf("%s.token is required", prefix)
+		}
+	}
+	return nil
+}
+
+// AzureDevOpsConfig holds the credentials for repositories in Azure
+// Repos. Repositories whose URL host is Host (including the legacy
+// <organization>.visualstudio.com and ssh.dev.azure.com forms of
+// dev.azure.com URLs) are cloned, pushed, and reviewed there.
+type AzureDevOpsConfig struct {
+	// Host is dev.azure.com (the default) or the host of an Azure
+	// DevOps Server.
+	Host string `yaml:"host" mapstructure:"host"`
+
+	// Token is a personal access token with the Code (read & write)
+	// scope. May be a secret reference. Empty disables Azure DevOps.
+	Token string `yaml:"token" mapstructure:"token"`
+
+	// Organizations lists the organizations the bot works in, or on
+	// Azure DevOps Server the collection paths (e.g.,
+	// "tfs/DefaultCollection"). The readiness probe checks the token
+	// against each.
+	Organizations []string `yaml:"organizations" mapstructure:"organizations"`
+}
+
+// Enabled reports whether Azure DevOps is configured.
+func (a *AzureDevOpsConfig) Enabled() bool {
+	return a.Token != ""
+}
+
+func (a *AzureDevOpsConfig) validate() error {
+	if !a.Enabled() {
+		return nil
+	}
+	if a.Host == "" || strings.ContainsAny(a.Host, "/:@") {
+		return fmt.Errorf("azure_devops.host: %q is not a host name", a.Host)
+	}
+	if strings.EqualFold(a.Host, "github.com") {
+		return fmt.Errorf("azure_devops.host must not be github.com")
+	}
+	if len(a.Organizations) == 0 {
+		return fmt.Errorf("azure_devops.organizations is required when azure_devops.token is set")
+	}
+	for _, org := range a.Organizations {
+		if org == "" || strings.Trim(org, "/") != org {
+			return fmt.Errorf("azure_devops.organizations: %q is not an organization name", org)
+		}
+	}
+	return nil
+}
+
+// ModelRoutingConfig holds settings for routing tickets to models by
+// how complex they look: simple tickets to a cheap, fast model and
+// complex ones to a premium model. A ticket scores a point for each of
+// a long description, many components, an issue type in ComplexTypes,
+// and ComplexLabel; MinScore points make it complex. ComplexLabel and
+// SimpleLabel also decide the tier outright. See the modelroute
+// package.
+type ModelRoutingConfig struct {
+	// Models maps AI provider names to their models per tier.
+	// Providers not listed keep their configured model. Empty
+	// disables routing.
+	Models map[string]ModelTiers `yaml:"models" mapstructure:"models"`
+
+	// LongDescription is the description length, in characters,
+	// that counts as long.
+	LongDescription int `yaml:"long_description" mapstructure:"long_description"`
+
+	// ManyComponents is the number of components that counts as
+	// many.
+	ManyComponents int `yaml:"many_components" mapstructure:"many_components"`
+
+	// ComplexTypes lists the issue types that count as complex.
+	ComplexTypes []string `yaml:"complex_types" mapstructure:"complex_types"`
+
+	// MinScore is the number of points that makes a ticket complex.
+	MinScore int `yaml:"min_score" mapstructure:"min_score"`
+
+	// ComplexLabel and SimpleLabel are the ticket labels that force
+	// a tier. Empty disables a label.
+	ComplexLabel string `yaml:"complex_label" mapstructure:"complex_label"`
+	SimpleLabel  string `yaml:"simple_label" mapstructure:"simple_label"`
+}
+
+// ModelTiers are a provider's models for simple and complex tickets.
+// An empty model keeps the provider's configured model for that tier.
+type ModelTiers struct {
+	Simple  string `yaml:"simple" mapstructure:"simple"`
+	Complex string `yaml:"complex" mapstructure:"complex"`
+}
+
+// Enabled reports whether any provider's models are routed.
+func (m *ModelRoutingConfig) Enabled() bool {
+	return len(m.Models) > 0
+}
+
+func (m *ModelRoutingConfig) validate() error {
+	if !m.Enabled() {
+		return nil
+	}
+	for provider, tiers := range m.Models {
+		if tiers.Simple == "" && tiers.Complex == "" {
+			return fmt.Errorf("model_routing.models.%s: set simple, complex, or both", provider)
+		}
+	}
+	if m.LongDescription <= 0 {
+		return errors.New("model_routing.long_description must be positive")
+	}
+	if m.ManyComponents <= 0 {
+		return errors.New("model_routing.many_components must be positive")
+	}
+	if m.MinScore < 1 || m.MinScore > 4 {
+		return fmt.Errorf("model_routing.min_score must be between 1 and 4, got %d", m.MinScore)
+	}
+	if m.ComplexLabel != "" && m.ComplexLabel == m.SimpleLabel {
+		return errors.New("model_routing.complex_label and simple_label must differ")
+	}
+	return nil
+}
+
+// RecordingConfig holds settings for recording the external
+// interactions of each job (Jira and GitHub responses, container
+// commands, AI output) so that the job can be replayed against a
+// changed pipeline. See the replay package.
+type RecordingConfig struct {
+	// Dir is the directory recordings are written to, one file per
+	// job. Empty disables recording.
+	Dir string `yaml:"dir" mapstructure:"dir"`
+}
+
+// LinkContextConfig holds settings for fetching the pages a ticket
+// links to. Many tickets only point at a failing CI run, a dashboard,
+// or a pasted log; when enabled, the links in a new ticket's
+// description and comments that lead to allowed domains are fetched,
+// and their text is saved in the workspace and listed in the task
+// file.
+type LinkContextConfig struct {
+	// Enabled turns on fetching.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// AllowedDomains lists the hosts whose pages are fetched; each
+	// also allows its subdomains. Links elsewhere are ignored.
+	AllowedDomains []string `yaml:"allowed_domains" mapstructure:"allowed_domains"`
+
+	// MaxLinks is the number of links fetched per ticket.
+	MaxLinks int `yaml:"max_links" mapstructure:"max_links" default:"5"`
+
+	// MaxKB caps the text kept from each page, in kilobytes.
+	MaxKB int `yaml:"max_kb" mapstructure:"max_kb" default:"64"`
+}
+
+func (l *LinkContextConfig) validate() error {
+	if !l.Enabled {
+		return nil
+	}
+	if len(l.AllowedDomains) == 0 {
+		return errors.New("link_context.allowed_domains is required when link_context is enabled")
+	}
+	for _, d := range l.AllowedDomains {
+		if strings.TrimSpace(d) == "" || strings.ContainsAny(d, "/:*") {
+			return fmt.Errorf("link_context.allowed_domains: %q is not a domain name", d)
+		}
+	}
+	if l.MaxLinks <= 0 {
+		return errors.New("link_context.max_links must be positive")
+	}
+	if l.MaxKB <= 0 {
+		return errors.New("link_context.max_kb must be positive")
+	}
+	return nil
 }
 
 // MergeConfig holds settings for the auto-merge scanner that keeps
@@ -598,6 +2107,26 @@ func (m *MergeConfig) validate() error {
 	return nil
 }
 
+func (s SetupConfig) validate() error {
+	if strings.TrimSpace(s.Command) == "" {
+		if len(s.LockFiles) > 0 || len(s.CacheDirs) > 0 {
+			return errors.New("command is required")
+		}
+		return nil
+	}
+	for i, file := range s.LockFiles {
+		if file = cleanSubdirectory(file); file == "" || file == ".." || strings.HasPrefix(file, "../") {
+			return fmt.Errorf("lock_files[%d]: %q is not a file inside the repository", i, s.LockFiles[i])
+		}
+	}
+	for i, dir := range s.CacheDirs {
+		if dir = path.Clean(strings.TrimSpace(dir)); dir == "." || dir == "/" || dir == ".." || strings.HasPrefix(dir, "../") {
+			return fmt.Errorf("cache_dirs[%d]: %q is not a usable cache directory", i, s.CacheDirs[i])
+		}
+	}
+	return nil
+}
+
 // ContainerCfg holds bot-level container configuration: host-level
 // runtime policy applied to all spawned containers.
 type ContainerCfg struct {
@@ -614,6 +2143,11 @@ type ContainerCfg struct {
 	// (e.g., "keep-id", "keep-id:uid=1000,gid=1000"). This is
 	// host-level policy. Empty means the container runtime's default.
 	UserNS string `yaml:"userns" mapstructure:"userns"`
+
+	// SetupCacheDir is the host directory holding the dependency
+	// caches filled by profiles' setup commands (see SetupConfig).
+	// Empty runs setup commands before every session without caching.
+	SetupCacheDir string `yaml:"setup_cache_dir" mapstructure:"setup_cache_dir"`
 }
 
 // ContainerSettings holds per-environment container settings. This
@@ -700,10 +2234,31 @@ type GuardrailsConfig struct {
 	// per-ticket cost capping.
 	MaxTicketCostUSD float64 `yaml:"max_ticket_cost_usd" mapstructure:"max_ticket_cost_usd"`
 
+	// MaxOpenPRsPerRepo caps the number of open bot PRs per
+	// repository. When a repository is at the limit, new tickets
+	// targeting it stay in the todo status (with a comment explaining
+	// why) until a bot PR is merged or closed. Can be overridden
+	// per-project in ProjectConfig. Zero disables the limit.
+	MaxOpenPRsPerRepo int `yaml:"max_open_prs_per_repo" mapstructure:"max_open_prs_per_repo"`
+
 	// MaxContainerRuntimeMinutes is the maximum duration (in minutes)
 	// for an AI session inside a container. Zero means no timeout.
 	MaxContainerRuntimeMinutes int `yaml:"max_container_runtime_minutes" mapstructure:"max_container_runtime_minutes" default:"60"`
 
+	// MaxJobRuntimeMinutes is the maximum duration (in minutes) of a
+	// whole job, covering every AI session, git operation, and API call
+	// it makes. On expiry the job is cancelled, its workspace removed,
+	// and the ticket failed with a timeout reason. Zero means no
+	// timeout.
+	MaxJobRuntimeMinutes int `yaml:"max_job_runtime_minutes" mapstructure:"max_job_runtime_minutes" default:"180"`
+
+	// TicketLockDir is the directory holding per-ticket lock files.
+	// A job holds its ticket's lock while it runs, so that no other
+	// bot instance pointed at the same directory (e.g., on a shared
+	// volume) processes the ticket at the same time. Empty uses a
+	// ".locks" directory under workspaces.base_dir.
+	TicketLockDir string `yaml:"ticket_lock_dir" mapstructure:"ticket_lock_dir"`
+
 	// CircuitBreakerThreshold is the number of consecutive failures
 	// within CircuitBreakerWindow that trips the breaker. Zero
 	// disables the circuit breaker.
@@ -729,6 +2284,19 @@ type GuardrailsConfig struct {
 	// the label, and resubmits the ticket. Empty disables the feature.
 	RetryLabel string `yaml:"retry_label" mapstructure:"retry_label" default:"ai-retry"`
 
+	// DeadLetterLabel is the Jira label the bot adds to a ticket once
+	// it has exhausted its retry limit. New-ticket scans skip tickets
+	// carrying it, across restarts, until a human removes it; the
+	// ticket is then retried from scratch. Empty disables the
+	// feature.
+	DeadLetterLabel string `yaml:"dead_letter_label" mapstructure:"dead_letter_label"`
+
+	// DeadLetterWebhookURL is a URL the bot POSTs a JSON message to
+	// whenever it dead-letters a ticket, e.g. a Slack or Mattermost
+	// incoming webhook. Requires DeadLetterLabel. Empty disables the
+	// notification.
+	DeadLetterWebhookURL string `yaml:"dead_letter_webhook_url" mapstructure:"dead_letter_webhook_url"`
+
 	// MinCommentLength is the minimum character length for Jira ticket
 	// comments to be included in AI task files. Comments shorter than
 	// this are filtered as noise. Zero disables length filtering.
@@ -740,6 +2308,31 @@ type GuardrailsConfig struct {
 	// Zero disables the configurable limit (a hard-coded 1000-file
 	// safety cap still applies).
 	MaxCommitFiles int `yaml:"max_commit_files" mapstructure:"max_commit_files" default:"100"`
+
+	// MaxAIOutputMB aborts an in-process AI session (claude.mode or
+	// gemini.mode "api") once the model's messages and tool results
+	// exceed this many megabytes, stopping runaway sessions early.
+	// CLI sessions run to completion inside the container and are
+	// not limited; only MaxContainerRuntimeMinutes stops them. Zero
+	// disables the limit.
+	MaxAIOutputMB int `yaml:"max_ai_output_mb" mapstructure:"max_ai_output_mb" default:"20"`
+
+	// MaxAIRetries is how many times the AI session for a new ticket
+	// is rerun when it fails or produces no changes. Each retry is
+	// told why the previous attempt was rejected. Zero (the default)
+	// disables retries.
+	MaxAIRetries int `yaml:"max_ai_retries" mapstructure:"max_ai_retries"`
+
+	// MaxParallelRepos is how many repositories of a multi-repo
+	// ticket are committed and get their PRs at the same time.
+	// Zero or one processes them one after another.
+	MaxParallelRepos int `yaml:"max_parallel_repos" mapstructure:"max_parallel_repos" default:"4"`
+
+	// MaxConcurrentAISessions is how many AI sessions of all
+	// providers may run at once; further sessions wait for one to
+	// finish. Unlike MaxConcurrentJobs it does not count jobs busy
+	// with git, CI, or API work. Zero disables the limit.
+	MaxConcurrentAISessions int `yaml:"max_concurrent_ai_sessions" mapstructure:"max_concurrent_ai_sessions"`
 }
 
 // GetProjectConfigForTicket returns the project configuration for a given ticket key
@@ -789,6 +2382,16 @@ func (c *Config) GetBotEmail() string {
 	return ""
 }
 
+// GetBotName returns the name GitHub records as the author of API
+// commits made by the bot: the app's bot user ("{bot_username}[bot]")
+// for GitHub App mode, otherwise bot_username.
+func (c *Config) GetBotName() string {
+	if c.GitHub.AppID > 0 {
+		return c.GitHub.BotUsername + "[bot]"
+	}
+	return c.GitHub.BotUsername
+}
+
 // LoadConfig loads configuration from multiple sources with Viper
 // Priority order: Environment variables > Config file > .env file > Defaults
 func LoadConfig(configPath string) (*Config, error) {
@@ -811,8 +2414,8 @@ func LoadConfig(configPath string) (*Config, error) {
 
 	// Helper function to bind environment variables with error checking
 	// Panics on error since all keys are static strings and should never fail
-	bindEnv := func(key string) {
-		if err := v.BindEnv(key); err != nil {
+	bindEnv := func(key string, envNames ...string) {
+		if err := v.BindEnv(append([]string{key}, envNames...)...); err != nil {
 			panic(fmt.Sprintf("failed to bind environment variable %s: %v", key, err))
 		}
 	}
@@ -822,7 +2425,29 @@ func LoadConfig(configPath string) (*Config, error) {
 	bindEnv("jira.base_url")
 	bindEnv("jira.username")
 	bindEnv("jira.api_token")
+	bindEnv("jira.max_search_results")
+	bindEnv("jira.auth_type")
+	bindEnv("jira.oauth.client_id")
+	bindEnv("jira.oauth.client_secret")
+	bindEnv("jira.oauth.refresh_token")
+	bindEnv("jira.oauth.cloud_id")
+	bindEnv("jira.oauth.token_url")
+	bindEnv("jira.oauth.api_url")
+	bindEnv("jira.oauth.token_file")
+	bindEnv("jira.worklog.enabled")
+	bindEnv("jira.attach_transcripts")
+	bindEnv("jira.history_comment")
+	bindEnv("jira.bot_account_id")
+	bindEnv("jira.secure_comment_visibility.type")
+	bindEnv("jira.secure_comment_visibility.value")
+	bindEnv("jira.worklog.author")
+	bindEnv("jira.worklog.description")
+	bindEnv("jira.circuit_breaker.threshold")
+	bindEnv("jira.circuit_breaker.cooldown_seconds")
+	bindEnv("jira.circuit_breaker.max_cooldown_minutes")
 	bindEnv("jira.interval_seconds")
+	bindEnv("jira.schedule")
+	bindEnv("jira.feedback_schedule")
 	bindEnv("jira.assignee_to_github_username")
 	bindEnv("jira.disable_error_comments")
 	bindEnv("jira.git_pull_request_field_name")
@@ -839,8 +2464,11 @@ func LoadConfig(configPath string) (*Config, error) {
 	bindEnv("github.max_thread_depth")
 	bindEnv("github.known_bot_usernames")
 	bindEnv("github.ignored_usernames")
+	bindEnv("github.ignored_comment_paths")
 	bindEnv("github.ignored_check_names")
 	bindEnv("github.skip_pr_label")
+	bindEnv("github.feedback_quiet_minutes")
+	bindEnv("github.sign_off")
 
 	// AI configuration
 	bindEnv("ai_provider")
@@ -851,14 +2479,50 @@ func LoadConfig(configPath string) (*Config, error) {
 	bindEnv("claude.vertex_project_id")
 	bindEnv("claude.vertex_region")
 	bindEnv("claude.vertex_credentials_file")
+	bindEnv("claude.mode")
+	bindEnv("claude.max_turns")
+	bindEnv("claude.input_price_per_mtok")
+	bindEnv("claude.output_price_per_mtok")
 	bindEnv("gemini.api_key")
 	bindEnv("gemini.model")
 	bindEnv("gemini.input_price_per_mtok")
 	bindEnv("gemini.output_price_per_mtok")
 	bindEnv("gemini.cached_price_per_mtok")
+	bindEnv("gemini.mode")
+	bindEnv("gemini.max_turns")
+	bindEnv("claude.max_concurrent_sessions")
+	bindEnv("claude.sessions_per_minute")
+	bindEnv("gemini.max_concurrent_sessions")
+	bindEnv("gemini.sessions_per_minute")
+	// Bedrock and Azure OpenAI also read the variables their SDKs use
+	// (names given explicitly are not prefixed).
+	bindEnv("bedrock.region", "JIRA_AI_BEDROCK_REGION", "AWS_REGION")
+	bindEnv("bedrock.model")
+	bindEnv("bedrock.access_key_id", "JIRA_AI_BEDROCK_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID")
+	bindEnv("bedrock.secret_access_key", "JIRA_AI_BEDROCK_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY")
+	bindEnv("bedrock.session_token", "JIRA_AI_BEDROCK_SESSION_TOKEN", "AWS_SESSION_TOKEN")
+	bindEnv("bedrock.api_key", "JIRA_AI_BEDROCK_API_KEY", "AWS_BEARER_TOKEN_BEDROCK")
+	bindEnv("bedrock.endpoint")
+	bindEnv("bedrock.max_turns")
+	bindEnv("azure_openai.endpoint", "JIRA_AI_AZURE_OPENAI_ENDPOINT", "AZURE_OPENAI_ENDPOINT")
+	bindEnv("azure_openai.deployment")
+	bindEnv("azure_openai.api_version")
+	bindEnv("azure_openai.api_key", "JIRA_AI_AZURE_OPENAI_API_KEY", "AZURE_OPENAI_API_KEY")
+	bindEnv("azure_openai.tenant_id", "JIRA_AI_AZURE_OPENAI_TENANT_ID", "AZURE_TENANT_ID")
+	bindEnv("azure_openai.client_id", "JIRA_AI_AZURE_OPENAI_CLIENT_ID", "AZURE_CLIENT_ID")
+	bindEnv("azure_openai.client_secret", "JIRA_AI_AZURE_OPENAI_CLIENT_SECRET", "AZURE_CLIENT_SECRET")
+	bindEnv("azure_openai.max_turns")
 
 	// Server configuration
 	bindEnv("server.port")
+	bindEnv("server.min_free_disk_mb")
+	bindEnv("server.address")
+	bindEnv("server.tls.cert_file")
+	bindEnv("server.tls.key_file")
+	bindEnv("server.tls.client_ca_file")
+	bindEnv("server.auth.bearer_tokens")
+	bindEnv("server.auth.oidc.issuer_url")
+	bindEnv("server.auth.oidc.audience")
 	bindEnv("PORT")
 
 	// Logging configuration
@@ -879,19 +2543,64 @@ func LoadConfig(configPath string) (*Config, error) {
 	bindEnv("guardrails.max_retries")
 	bindEnv("guardrails.max_daily_cost_usd")
 	bindEnv("guardrails.max_ticket_cost_usd")
+	bindEnv("guardrails.max_open_prs_per_repo")
 	bindEnv("guardrails.max_container_runtime_minutes")
+	bindEnv("guardrails.max_job_runtime_minutes")
+	bindEnv("guardrails.ticket_lock_dir")
 	bindEnv("guardrails.circuit_breaker_threshold")
 	bindEnv("guardrails.circuit_breaker_window_minutes")
 	bindEnv("guardrails.circuit_breaker_cooldown_minutes")
 	bindEnv("guardrails.max_ci_fix_attempts")
 	bindEnv("guardrails.min_comment_length")
 	bindEnv("guardrails.retry_label")
+	bindEnv("guardrails.dead_letter_label")
+	bindEnv("guardrails.dead_letter_webhook_url")
 	bindEnv("guardrails.max_commit_files")
+	bindEnv("guardrails.max_ai_output_mb")
+	bindEnv("guardrails.max_ai_retries")
+	bindEnv("guardrails.max_parallel_repos")
+	bindEnv("guardrails.max_concurrent_ai_sessions")
 
 	// Merge configuration
 	bindEnv("merge.idle_days")
 	bindEnv("merge.idle_label")
 
+	// Tracing configuration
+	bindEnv("tracing.enabled")
+	bindEnv("tracing.endpoint")
+	bindEnv("tracing.insecure")
+	bindEnv("tracing.service_name")
+	bindEnv("tracing.sample_ratio")
+
+	// Repo index configuration
+	bindEnv("repo_index.enabled")
+	bindEnv("repo_index.cache_dir")
+	bindEnv("repo_index.refresh_hours")
+	bindEnv("repo_index.max_files")
+	bindEnv("duplicate_detection.enabled")
+	bindEnv("duplicate_detection.index_path")
+	bindEnv("duplicate_detection.threshold")
+	bindEnv("link_context.enabled")
+	bindEnv("link_context.max_links")
+	bindEnv("link_context.max_kb")
+	bindEnv("recording.dir")
+	bindEnv("azure_devops.host")
+	bindEnv("azure_devops.token")
+
+	// Secrets configuration
+	bindEnv("secrets.refresh_minutes")
+	bindEnv("secrets.vault.address")
+	bindEnv("secrets.vault.token")
+	bindEnv("secrets.vault.namespace")
+	bindEnv("secrets.aws_region")
+
+	// Network configuration
+	bindEnv("network.proxy_url")
+	bindEnv("network.no_proxy")
+	bindEnv("network.ca_bundle")
+	bindEnv("network.client_cert")
+	bindEnv("network.client_key")
+
 	// Note: component_to_repo has custom unmarshaling logic, so we don't bind it explicitly
 
 	// Load main config file if provided
@@ -944,8 +2653,11 @@ func LoadConfig(configPath string) (*Config, error) {
 
 		// Handle component_to_repo from environment. Creates a
 		// "default" profile, one workspace per component, and maps
-		// each component to its workspace. Operators who need
-		// multi-repo workspaces must use a YAML config file.
+		// each component to its workspace. A URL may end in
+		// "#subdirectory" to map the component to part of a
+		// monorepo, and then in "@branch" to set its target branch.
+		// Operators who need multi-repo workspaces must use a YAML
+		// config file.
 		componentToRepoStr := v.GetString("component_to_repo")
 		if componentToRepoStr != "" {
 			pairs := strings.Split(componentToRepoStr, ",")
@@ -956,14 +2668,15 @@ func LoadConfig(configPath string) (*Config, error) {
 				parts := strings.SplitN(pair, "=", 2)
 				if len(parts) == 2 {
 					compName := parts[0]
-					repoURL := parts[1]
-					repoName := repoNameFromURL(repoURL)
+					repoURL, branch := splitTargetBranch(parts[1])
+					cloneURL, _, _ := strings.Cut(repoURL, "#")
+					repoName := repoNameFromURL(cloneURL)
 
 					components[compName] = ComponentConfig{
 						Workspace: compName,
 					}
 					workspaces[compName] = WorkspaceConfig{
-						Repos: []RepoEntry{{Name: repoName, URL: repoURL, Profile: "default"}},
+						Repos: []RepoEntry{{Name: repoName, URL: repoURL, Profile: "default", TargetBranch: branch}},
 					}
 				}
 			}
@@ -1071,6 +2784,7 @@ func reconstructStatusTransitionsFromEnv(v *viper.Viper) TicketTypeStatusTransit
 func setDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.port", 8080)
+	v.SetDefault("server.min_free_disk_mb", 1024)
 
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
@@ -1078,6 +2792,15 @@ func setDefaults(v *viper.Viper) {
 
 	// Jira defaults
 	v.SetDefault("jira.interval_seconds", 300)
+	v.SetDefault("jira.max_search_results", 1000)
+	v.SetDefault("jira.auth_type", JiraAuthBasic)
+	v.SetDefault("jira.worklog.enabled", false)
+	v.SetDefault("jira.attach_transcripts", false)
+	v.SetDefault("jira.history_comment", false)
+	v.SetDefault("jira.worklog.description", "{{author}}: {{activity}}")
+	v.SetDefault("jira.circuit_breaker.threshold", 5)
+	v.SetDefault("jira.circuit_breaker.cooldown_seconds", 30)
+	v.SetDefault("jira.circuit_breaker.max_cooldown_minutes", 10)
 	v.SetDefault("jira.disable_error_comments", false)
 
 	// GitHub defaults
@@ -1099,10 +2822,29 @@ func setDefaults(v *viper.Viper) {
 	// AI Provider defaults
 	v.SetDefault("ai_provider", "claude")
 
+	// Claude api mode defaults (USD per million tokens, claude-sonnet-4-6 rates)
+	v.SetDefault("claude.mode", AIModeCLI)
+	v.SetDefault("claude.max_turns", 100)
+	v.SetDefault("claude.input_price_per_mtok", 3.0)
+	v.SetDefault("claude.output_price_per_mtok", 15.0)
+
 	// Gemini pricing defaults (USD per million tokens, gemini-2.5-flash rates)
 	v.SetDefault("gemini.input_price_per_mtok", 0.15)
 	v.SetDefault("gemini.output_price_per_mtok", 0.60)
 	v.SetDefault("gemini.cached_price_per_mtok", 0.0375)
+	v.SetDefault("gemini.mode", AIModeCLI)
+	v.SetDefault("gemini.max_turns", 100)
+
+	// Bedrock defaults (USD per million tokens, Claude Sonnet rates)
+	v.SetDefault("bedrock.max_turns", 100)
+	v.SetDefault("bedrock.input_price_per_mtok", 3.0)
+	v.SetDefault("bedrock.output_price_per_mtok", 15.0)
+
+	// Azure OpenAI defaults (USD per million tokens, gpt-4.1 rates)
+	v.SetDefault("azure_openai.max_turns", 100)
+	v.SetDefault("azure_openai.input_price_per_mtok", 2.0)
+	v.SetDefault("azure_openai.output_price_per_mtok", 8.0)
+	v.SetDefault("azure_openai.cached_price_per_mtok", 0.5)
 
 	// Workspace defaults
 	v.SetDefault("workspaces.ttl_days", 7)
@@ -1114,6 +2856,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("guardrails.max_concurrent_jobs", 10)
 	v.SetDefault("guardrails.max_retries", 3)
 	v.SetDefault("guardrails.max_container_runtime_minutes", 60)
+	v.SetDefault("guardrails.max_job_runtime_minutes", 180)
 	v.SetDefault("guardrails.circuit_breaker_threshold", 5)
 	v.SetDefault("guardrails.circuit_breaker_window_minutes", 10)
 	v.SetDefault("guardrails.circuit_breaker_cooldown_minutes", 5)
@@ -1121,24 +2864,84 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("guardrails.retry_label", "ai-retry")
 	v.SetDefault("guardrails.min_comment_length", 20)
 	v.SetDefault("guardrails.max_commit_files", 100)
+	v.SetDefault("guardrails.max_ai_output_mb", 20)
+	v.SetDefault("guardrails.max_ai_retries", 0)
+	v.SetDefault("guardrails.max_parallel_repos", 4)
+	v.SetDefault("guardrails.max_concurrent_ai_sessions", 0)
 	v.SetDefault("guardrails.max_ticket_cost_usd", 20.0)
 
 	// Merge configuration defaults
 	v.SetDefault("merge.idle_days", 7)
 	v.SetDefault("merge.idle_label", "ai-bot/idle")
+
+	// Tracing defaults
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "jira-ai-issue-solver")
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	// Repo index defaults
+	v.SetDefault("repo_index.enabled", false)
+	v.SetDefault("repo_index.refresh_hours", 24)
+	v.SetDefault("repo_index.max_files", 20)
+
+	// Duplicate detection defaults
+	v.SetDefault("duplicate_detection.enabled", false)
+	v.SetDefault("duplicate_detection.threshold", 0.5)
+	v.SetDefault("duplicate_detection.retention_days", 90)
+	v.SetDefault("duplicate_detection.max_matches", 3)
+	v.SetDefault("duplicate_detection.link_type", "Relates")
+	v.SetDefault("duplicate_detection.ignore_label", "ai-not-duplicate")
+
+	// Model routing defaults
+	v.SetDefault("model_routing.long_description", 2000)
+	v.SetDefault("model_routing.many_components", 2)
+	v.SetDefault("model_routing.complex_types", []string{"Epic", "Feature"})
+	v.SetDefault("model_routing.min_score", 2)
+	v.SetDefault("model_routing.complex_label", "ai-complex")
+	v.SetDefault("model_routing.simple_label", "ai-simple")
+
+	v.SetDefault("link_context.enabled", false)
+	v.SetDefault("link_context.max_links", 5)
+	v.SetDefault("link_context.max_kb", 64)
+	v.SetDefault("azure_devops.host", "dev.azure.com")
+
+	// Secrets defaults
+	v.SetDefault("secrets.refresh_minutes", 15)
 }
 
 // validate validates the entire configuration
 func (c *Config) validate() error {
 	// Validate AI provider configuration
-	if c.AIProvider != "claude" && c.AIProvider != "gemini" {
-		return errors.New("ai_provider must be either 'claude' or 'gemini'")
+	// Whether the provider is registered is checked at startup, where
+	// the provider registry is known.
+	if c.AIProvider == "" {
+		return errors.New("ai_provider must not be empty")
 	}
 
 	if err := c.validateClaudeAuth(); err != nil {
 		return err
 	}
 
+	if err := c.validateGeminiMode(); err != nil {
+		return err
+	}
+
+	if err := c.Bedrock.validate(c.AIProvider == "bedrock"); err != nil {
+		return err
+	}
+
+	if err := c.AzureOpenAI.validate(c.AIProvider == "azure_openai"); err != nil {
+		return err
+	}
+
+	if err := c.validateAILimits(); err != nil {
+		return err
+	}
+
+	if err := c.validateServer(); err != nil {
+		return err
+	}
+
 	// Validate logging configuration
 	if !c.Logging.Level.IsValid() {
 		return fmt.Errorf("invalid log level: %s. Valid options are: debug, info, warn, error", c.Logging.Level)
@@ -1151,11 +2954,27 @@ func (c *Config) validate() error {
 	if c.Jira.BaseURL == "" {
 		return errors.New("jira.base_url is required")
 	}
-	if c.Jira.Username == "" {
-		return errors.New("jira.username is required")
+	if err := c.Jira.validateAuth(); err != nil {
+		return err
 	}
-	if c.Jira.APIToken == "" {
-		return errors.New("jira.api_token is required")
+	if err := c.Jira.SecureCommentVisibility.validate(); err != nil {
+		return err
+	}
+	if err := c.Jira.CircuitBreaker.validate(); err != nil {
+		return err
+	}
+	if c.Jira.MaxSearchResults < 0 {
+		return errors.New("jira.max_search_results must be non-negative")
+	}
+	if c.Jira.Schedule != "" {
+		if err := ValidateCron(c.Jira.Schedule); err != nil {
+			return fmt.Errorf("jira.schedule: %w", err)
+		}
+	}
+	if c.Jira.FeedbackSchedule != "" {
+		if err := ValidateCron(c.Jira.FeedbackSchedule); err != nil {
+			return fmt.Errorf("jira.feedback_schedule: %w", err)
+		}
 	}
 
 	// Validate projects configuration - at least one project must be configured
@@ -1168,6 +2987,9 @@ func (c *Config) validate() error {
 		if err := project.validate(i); err != nil {
 			return err
 		}
+		if project.AssignBot && c.Jira.BotAccountID == "" {
+			return fmt.Errorf("jira.projects[%d].assign_bot requires jira.bot_account_id", i)
+		}
 	}
 
 	// GitHub validation - App credentials required
@@ -1207,6 +3029,16 @@ func (c *Config) validate() error {
 		}
 	}
 
+	if c.GitHub.FeedbackQuietMinutes < 0 {
+		return errors.New("github.feedback_quiet_minutes must be non-negative")
+	}
+
+	for i, pattern := range c.GitHub.IgnoredCommentPaths {
+		if _, err := path.Match(pattern, ""); strings.TrimSpace(pattern) == "" || err != nil {
+			return fmt.Errorf("github.ignored_comment_paths[%d] %q is not a valid pattern", i, pattern)
+		}
+	}
+
 	// Validate workspaces configuration
 	if c.Workspaces.BaseDir == "" {
 		return errors.New("workspaces.base_dir is required")
@@ -1227,6 +3059,47 @@ func (c *Config) validate() error {
 		return err
 	}
 
+	if err := c.Tracing.validate(); err != nil {
+		return err
+	}
+
+	if err := c.RepoIndex.validate(); err != nil {
+		return err
+	}
+
+	if err := c.DuplicateDetection.validate(); err != nil {
+		return err
+	}
+
+	if err := c.LinkContext.validate(); err != nil {
+		return err
+	}
+
+	if err := c.ModelRouting.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Gitea.validate(); err != nil {
+		return err
+	}
+
+	if err := c.AzureDevOps.validate(); err != nil {
+		return err
+	}
+	for _, inst := range c.Gitea.Instances {
+		if c.AzureDevOps.Enabled() && strings.EqualFold(inst.Host, c.AzureDevOps.Host) {
+			return fmt.Errorf("gitea instance %s is also azure_devops.host", inst.Host)
+		}
+	}
+
+	if err := c.Secrets.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Network.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1261,6 +3134,11 @@ func (p *ProjectConfig) validate(index int) error {
 	if len(p.Profiles) == 0 {
 		return fmt.Errorf("%s.profiles: at least one profile must be configured", prefix)
 	}
+	for name, profile := range p.Profiles {
+		if err := profile.Setup.validate(); err != nil {
+			return fmt.Errorf("%s.profiles.%s.setup: %w", prefix, name, err)
+		}
+	}
 
 	// Validate each workspace.
 	for wsName, ws := range p.Workspaces {
@@ -1276,9 +3154,30 @@ func (p *ProjectConfig) validate(index int) error {
 				return fmt.Errorf("%s.workspaces.%s.repos[%d].name: duplicate repo name %q", prefix, wsName, i, repo.Name)
 			}
 			seenRepoNames[repo.Name] = struct{}{}
-			if repo.URL == "" {
+			cloneURL, subdir := repo.Location()
+			if cloneURL == "" {
 				return fmt.Errorf("%s.workspaces.%s.repos[%d].url is required", prefix, wsName, i)
 			}
+			if subdir == ".." || strings.HasPrefix(subdir, "../") {
+				return fmt.Errorf("%s.workspaces.%s.repos[%d].subdirectory: %q is outside the repository", prefix, wsName, i, subdir)
+			}
+			if repo.SparseCheckout && subdir == "" {
+				return fmt.Errorf("%s.workspaces.%s.repos[%d].sparse_checkout requires a subdirectory", prefix, wsName, i)
+			}
+			if len(repo.SharedPaths) > 0 && !repo.SparseCheckout {
+				return fmt.Errorf("%s.workspaces.%s.repos[%d].shared_paths requires sparse_checkout", prefix, wsName, i)
+			}
+			for j, dir := range repo.SharedPaths {
+				if dir = cleanSubdirectory(dir); dir == "" || dir == ".." || strings.HasPrefix(dir, "../") {
+					return fmt.Errorf("%s.workspaces.%s.repos[%d].shared_paths[%d]: %q is not a directory inside the repository", prefix, wsName, i, j, repo.SharedPaths[j])
+				}
+			}
+			if p.Embargo.Enabled && repo.PrivateMirror == "" {
+				return fmt.Errorf("%s.workspaces.%s.repos[%d].private_mirror is required when embargo is enabled", prefix, wsName, i)
+			}
+			if repo.PrivateMirror != "" && !p.Embargo.Enabled {
+				return fmt.Errorf("%s.workspaces.%s.repos[%d].private_mirror requires embargo.enabled", prefix, wsName, i)
+			}
 			if repo.Profile != "" {
 				if _, ok := p.Profiles[repo.Profile]; !ok {
 					if !profileExistsCaseInsensitive(p.Profiles, repo.Profile) {
@@ -1287,6 +3186,19 @@ func (p *ProjectConfig) validate(index int) error {
 				}
 			}
 		}
+		seenOrder := make(map[string]struct{}, len(ws.MergeOrder))
+		for i, name := range ws.MergeOrder {
+			if _, ok := seenRepoNames[name]; !ok {
+				return fmt.Errorf("%s.workspaces.%s.merge_order[%d]: repo %q is not in the workspace", prefix, wsName, i, name)
+			}
+			if _, dup := seenOrder[name]; dup {
+				return fmt.Errorf("%s.workspaces.%s.merge_order[%d]: repo %q is listed twice", prefix, wsName, i, name)
+			}
+			seenOrder[name] = struct{}{}
+		}
+		if ws.DoNotMergeLabel != "" && len(ws.MergeOrder) < 2 {
+			return fmt.Errorf("%s.workspaces.%s.do_not_merge_label requires a merge_order of at least two repos", prefix, wsName)
+		}
 	}
 
 	// Either components or default_workspace must be configured.
@@ -1319,6 +3231,121 @@ func (p *ProjectConfig) validate(index int) error {
 		return fmt.Errorf("%s.max_ticket_cost_usd must be a finite number", prefix)
 	}
 
+	if p.MaxOpenPRsPerRepo != nil && *p.MaxOpenPRsPerRepo < 0 {
+		return fmt.Errorf("%s.max_open_prs_per_repo must be non-negative", prefix)
+	}
+
+	if err := p.Budget.validate(prefix + ".budget"); err != nil {
+		return err
+	}
+
+	if p.IntervalSeconds < 0 {
+		return fmt.Errorf("%s.interval_seconds must be non-negative", prefix)
+	}
+
+	if p.Schedule != "" {
+		if p.IntervalSeconds > 0 {
+			return fmt.Errorf("%s.schedule and %s.interval_seconds are mutually exclusive", prefix, prefix)
+		}
+		if err := ValidateCron(p.Schedule); err != nil {
+			return fmt.Errorf("%s.schedule: %w", prefix, err)
+		}
+	}
+
+	if p.FeedbackSplitThreshold < 0 {
+		return fmt.Errorf("%s.feedback_split_threshold must be non-negative", prefix)
+	}
+
+	switch p.HumanPushPolicy {
+	case "", HumanPushRebase, HumanPushDefer:
+	default:
+		return fmt.Errorf("%s.human_push_policy must be one of rebase, defer (got %q)", prefix, p.HumanPushPolicy)
+	}
+
+	if p.MinConfidence < 0 || p.MinConfidence > 100 {
+		return fmt.Errorf("%s.min_confidence must be between 0 and 100, got %d", prefix, p.MinConfidence)
+	}
+
+	if err := p.DiffPreview.Validate(); err != nil {
+		return fmt.Errorf("%s.diff_preview.%w", prefix, err)
+	}
+	if p.DiffPreview.Mode == DiffPreviewLowConfidence && p.MinConfidence == 0 {
+		return fmt.Errorf("%s.diff_preview.mode low_confidence requires min_confidence", prefix)
+	}
+
+	switch p.RegressionTests {
+	case "", RegressionTestsWarn, RegressionTestsAI:
+	default:
+		return fmt.Errorf("%s.regression_tests must be one of warn, ai (got %q)", prefix, p.RegressionTests)
+	}
+
+	for i, scan := range p.SecurityScans {
+		if strings.TrimSpace(scan.Name) == "" {
+			return fmt.Errorf("%s.security_scans[%d].name is required", prefix, i)
+		}
+		if strings.TrimSpace(scan.Command) == "" {
+			return fmt.Errorf("%s.security_scans[%d].command is required", prefix, i)
+		}
+	}
+
+	companions := make(map[string]bool, len(p.CompanionRepos))
+	for i, repo := range p.CompanionRepos {
+		if repo.Name == "" || repo.Name == "." || repo.Name == ".." || path.Base(repo.Name) != repo.Name {
+			return fmt.Errorf("%s.companion_repos[%d].name must be a simple directory name (got %q)", prefix, i, repo.Name)
+		}
+		if companions[repo.Name] {
+			return fmt.Errorf("%s.companion_repos[%d].name %q is duplicated", prefix, i, repo.Name)
+		}
+		companions[repo.Name] = true
+		if strings.TrimSpace(repo.URL) == "" {
+			return fmt.Errorf("%s.companion_repos[%d].url is required", prefix, i)
+		}
+	}
+
+	if err := p.DependencyPolicy.validate(); err != nil {
+		return fmt.Errorf("%s.dependency_policy.%w", prefix, err)
+	}
+
+	if p.QuietHours != nil {
+		if err := p.QuietHours.Validate(); err != nil {
+			return fmt.Errorf("%s.quiet_hours.%w", prefix, err)
+		}
+	}
+
+	for _, v := range p.FixVersions {
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("%s.fix_versions must not contain empty entries", prefix)
+		}
+	}
+
+	if err := p.FixVersionBranches.Validate(); err != nil {
+		return fmt.Errorf("%s.fix_version_branches%w", prefix, err)
+	}
+
+	if err := p.CommitMessage.Validate(); err != nil {
+		return fmt.Errorf("%s.commit_message.%w", prefix, err)
+	}
+
+	if err := p.Backport.Validate(); err != nil {
+		return fmt.Errorf("%s.backport.%w", prefix, err)
+	}
+
+	if err := p.ReleaseNotes.Validate(); err != nil {
+		return fmt.Errorf("%s.release_notes.%w", prefix, err)
+	}
+
+	if err := p.Triage.Validate(); err != nil {
+		return fmt.Errorf("%s.triage.%w", prefix, err)
+	}
+
+	if err := p.Spike.Validate(); err != nil {
+		return fmt.Errorf("%s.spike.%w", prefix, err)
+	}
+
+	if err := p.AutoMerge.Validate(); err != nil {
+		return fmt.Errorf("%s.auto_merge.%w", prefix, err)
+	}
+
 	return nil
 }
 
@@ -1342,6 +3369,26 @@ func workspaceExistsCaseInsensitive(workspaces map[string]WorkspaceConfig, name
 	return false
 }
 
+// splitTargetBranch splits a trailing "@branch" off a component_to_repo
+// URL, e.g. "https://github.com/org/repo.git@release-4.18". An "@" is
+// taken as the separator only when a path precedes it and no ":"
+// follows it, so that the user part of "git@github.com:org/repo.git"
+// and of "https://user@host/org/repo" is left alone.
+func splitTargetBranch(rawURL string) (repoURL, branch string) {
+	i := strings.LastIndex(rawURL, "@")
+	if i < 0 {
+		return rawURL, ""
+	}
+	before, after := rawURL[:i], rawURL[i+1:]
+	if _, rest, ok := strings.Cut(before, "://"); ok {
+		before = rest
+	}
+	if after == "" || strings.Contains(after, ":") || !strings.Contains(before, "/") {
+		return rawURL, ""
+	}
+	return rawURL[:i], after
+}
+
 // repoNameFromURL extracts a short repo name from a clone URL.
 // e.g., "https://github.com/org/backend.git" -> "backend"
 func repoNameFromURL(rawURL string) string {
@@ -1364,9 +3411,15 @@ func (g *GuardrailsConfig) validate() error {
 	if g.MaxConcurrentJobs <= 0 {
 		return errors.New("guardrails.max_concurrent_jobs must be positive")
 	}
+	if g.MaxOpenPRsPerRepo < 0 {
+		return errors.New("guardrails.max_open_prs_per_repo must be non-negative")
+	}
 	if g.MaxContainerRuntimeMinutes < 0 {
 		return errors.New("guardrails.max_container_runtime_minutes must be non-negative")
 	}
+	if g.MaxJobRuntimeMinutes < 0 {
+		return errors.New("guardrails.max_job_runtime_minutes must be non-negative")
+	}
 	if g.CircuitBreakerThreshold < 0 {
 		return errors.New("guardrails.circuit_breaker_threshold must be non-negative")
 	}
@@ -1379,6 +3432,26 @@ func (g *GuardrailsConfig) validate() error {
 	if g.MaxCommitFiles < 0 {
 		return errors.New("guardrails.max_commit_files must be non-negative")
 	}
+	if g.MaxAIOutputMB < 0 {
+		return errors.New("guardrails.max_ai_output_mb must be non-negative")
+	}
+	if g.MaxAIRetries < 0 {
+		return errors.New("guardrails.max_ai_retries must be non-negative")
+	}
+	if g.MaxParallelRepos < 0 {
+		return errors.New("guardrails.max_parallel_repos must be non-negative")
+	}
+	if g.MaxConcurrentAISessions < 0 {
+		return errors.New("guardrails.max_concurrent_ai_sessions must be non-negative")
+	}
+	if g.DeadLetterWebhookURL != "" {
+		if u, err := url.Parse(g.DeadLetterWebhookURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return errors.New("guardrails.dead_letter_webhook_url must be an http(s) URL")
+		}
+		if g.DeadLetterLabel == "" {
+			return errors.New("guardrails.dead_letter_webhook_url requires guardrails.dead_letter_label")
+		}
+	}
 	return nil
 }
 
@@ -1428,5 +3501,60 @@ func (c *Config) validateClaudeAuth() error {
 		return errors.New("claude: ai_provider is \"claude\" but no authentication configured — set api_key or all vertex_* fields")
 	}
 
+	switch c.Claude.Mode {
+	case "", AIModeCLI:
+	case AIModeAPI:
+		if !hasAPIKey {
+			return errors.New("claude: mode \"api\" requires api_key — Vertex AI is only supported in cli mode")
+		}
+		if c.Claude.MaxTurns <= 0 {
+			return errors.New("claude: max_turns must be positive")
+		}
+	default:
+		return fmt.Errorf("claude: mode must be %q or %q, got %q", AIModeCLI, AIModeAPI, c.Claude.Mode)
+	}
+
+	return nil
+}
+
+// validateAILimits checks the per-provider AI session limits.
+func (c *Config) validateAILimits() error {
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"claude.max_concurrent_sessions", c.Claude.MaxConcurrentSessions},
+		{"claude.sessions_per_minute", c.Claude.SessionsPerMinute},
+		{"gemini.max_concurrent_sessions", c.Gemini.MaxConcurrentSessions},
+		{"gemini.sessions_per_minute", c.Gemini.SessionsPerMinute},
+		{"bedrock.max_concurrent_sessions", c.Bedrock.MaxConcurrentSessions},
+		{"bedrock.sessions_per_minute", c.Bedrock.SessionsPerMinute},
+		{"azure_openai.max_concurrent_sessions", c.AzureOpenAI.MaxConcurrentSessions},
+		{"azure_openai.sessions_per_minute", c.AzureOpenAI.SessionsPerMinute},
+	}
+	for _, l := range limits {
+		if l.value < 0 {
+			return fmt.Errorf("%s must be non-negative", l.name)
+		}
+	}
 	return nil
 }
+
+// validateGeminiMode checks the Gemini session mode and its api-mode
+// requirements.
+func (c *Config) validateGeminiMode() error {
+	switch c.Gemini.Mode {
+	case "", AIModeCLI:
+		return nil
+	case AIModeAPI:
+		if c.Gemini.APIKey == "" {
+			return errors.New("gemini: mode \"api\" requires api_key")
+		}
+		if c.Gemini.MaxTurns <= 0 {
+			return errors.New("gemini: max_turns must be positive")
+		}
+		return nil
+	default:
+		return fmt.Errorf("gemini: mode must be %q or %q, got %q", AIModeCLI, AIModeAPI, c.Gemini.Mode)
+	}
+}
@@ -2,7 +2,10 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -21,30 +24,36 @@ func createTempKeyFile(t *testing.T) string {
 
 // getValidGitHubConfig returns a valid GitHub configuration for testing
 func getValidGitHubConfig() struct {
-	AppID             int64    `yaml:"app_id" mapstructure:"app_id"`
-	PrivateKeyPath    string   `yaml:"private_key_path" mapstructure:"private_key_path"`
-	BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-	BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"`
-	PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-	SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
-	MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
-	KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
-	IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
-	IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
-	SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+	AppID                int64    `yaml:"app_id" mapstructure:"app_id"`
+	PrivateKeyPath       string   `yaml:"private_key_path" mapstructure:"private_key_path"`
+	BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+	BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"`
+	PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+	SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
+	MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
+	KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
+	IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
+	IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`
+	IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
+	SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+	FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`
+	SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`
 } {
 	return struct {
-		AppID             int64    `yaml:"app_id" mapstructure:"app_id"`
-		PrivateKeyPath    string   `yaml:"private_key_path" mapstructure:"private_key_path"`
-		BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-		BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"`
-		PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-		SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
-		MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
-		KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
-		IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
-		IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
-		SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+		AppID                int64    `yaml:"app_id" mapstructure:"app_id"`
+		PrivateKeyPath       string   `yaml:"private_key_path" mapstructure:"private_key_path"`
+		BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+		BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"`
+		PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+		SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
+		MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
+		KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
+		IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
+		IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`
+		IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
+		SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+		FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`
+		SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`
 	}{
 		AppID:          123456,
 		PrivateKeyPath: "/tmp/test_key.pem",
@@ -104,17 +113,20 @@ func TestConfig_validateStatusTransitions(t *testing.T) {
 					},
 				},
 				GitHub: struct {
-					AppID             int64    `yaml:"app_id" mapstructure:"app_id"`
-					PrivateKeyPath    string   `yaml:"private_key_path" mapstructure:"private_key_path"`
-					BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-					BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"`
-					PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-					SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
-					MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
-					KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
-					IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
-					IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
-					SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					AppID                int64    `yaml:"app_id" mapstructure:"app_id"`
+					PrivateKeyPath       string   `yaml:"private_key_path" mapstructure:"private_key_path"`
+					BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+					BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"`
+					PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+					SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
+					MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
+					KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
+					IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
+					IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`
+					IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
+					SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`
+					SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`
 				}{
 					AppID:          123456,
 					PrivateKeyPath: tmpKeyPath,
@@ -546,6 +558,8 @@ jira:
           todo: "Open"
           in_progress: "In Progress"
           in_review: "Code Review"
+          needs_human: "Triage"
+          waiting_for_info: "Waiting for Info"
         story:
           todo: "Backlog"
           in_progress: "Development"
@@ -604,6 +618,12 @@ workspaces:
 	if bugTransitions.InReview != "Code Review" {
 		t.Errorf("Expected bug in_review status 'Code Review', got '%s'", bugTransitions.InReview)
 	}
+	if bugTransitions.NeedsHuman != "Triage" {
+		t.Errorf("Expected bug needs_human status 'Triage', got '%s'", bugTransitions.NeedsHuman)
+	}
+	if bugTransitions.WaitingForInfo != "Waiting for Info" {
+		t.Errorf("Expected bug waiting_for_info status 'Waiting for Info', got '%s'", bugTransitions.WaitingForInfo)
+	}
 
 	// Verify story-specific status transitions
 	storyTransitions := projectConfig.StatusTransitions.GetStatusTransitions("story")
@@ -831,17 +851,20 @@ func TestConfig_GitHubAppAuthentication(t *testing.T) {
 					},
 				},
 				GitHub: struct {
-					AppID             int64    `yaml:"app_id" mapstructure:"app_id"`
-					PrivateKeyPath    string   `yaml:"private_key_path" mapstructure:"private_key_path"`
-					BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-					BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"`
-					PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-					SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
-					MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
-					KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
-					IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
-					IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
-					SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					AppID                int64    `yaml:"app_id" mapstructure:"app_id"`
+					PrivateKeyPath       string   `yaml:"private_key_path" mapstructure:"private_key_path"`
+					BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+					BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"`
+					PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+					SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
+					MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
+					KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
+					IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
+					IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`
+					IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
+					SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`
+					SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`
 				}{
 					AppID:          123456,
 					PrivateKeyPath: tempKeyFile.Name(),
@@ -895,17 +918,20 @@ func TestConfig_GitHubAppAuthentication(t *testing.T) {
 					},
 				},
 				GitHub: struct {
-					AppID             int64    `yaml:"app_id" mapstructure:"app_id"`
-					PrivateKeyPath    string   `yaml:"private_key_path" mapstructure:"private_key_path"`
-					BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-					BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"`
-					PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-					SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
-					MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
-					KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
-					IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
-					IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
-					SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					AppID                int64    `yaml:"app_id" mapstructure:"app_id"`
+					PrivateKeyPath       string   `yaml:"private_key_path" mapstructure:"private_key_path"`
+					BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+					BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"`
+					PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+					SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
+					MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
+					KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
+					IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
+					IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`
+					IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
+					SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`
+					SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`
 				}{
 					PrivateKeyPath: tempKeyFile.Name(),
 					BotUsername:    "test-bot",
@@ -955,17 +981,20 @@ func TestConfig_GitHubAppAuthentication(t *testing.T) {
 					},
 				},
 				GitHub: struct {
-					AppID             int64    `yaml:"app_id" mapstructure:"app_id"`
-					PrivateKeyPath    string   `yaml:"private_key_path" mapstructure:"private_key_path"`
-					BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-					BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"`
-					PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-					SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
-					MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
-					KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
-					IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
-					IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
-					SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					AppID                int64    `yaml:"app_id" mapstructure:"app_id"`
+					PrivateKeyPath       string   `yaml:"private_key_path" mapstructure:"private_key_path"`
+					BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+					BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"`
+					PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+					SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
+					MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
+					KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
+					IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
+					IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`
+					IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
+					SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`
+					SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`
 				}{
 					AppID:          123456,
 					PrivateKeyPath: "/non/existent/path/key.pem",
@@ -1013,17 +1042,20 @@ func TestConfig_GitHubAppAuthentication(t *testing.T) {
 					},
 				},
 				GitHub: struct {
-					AppID             int64    `yaml:"app_id" mapstructure:"app_id"`
-					PrivateKeyPath    string   `yaml:"private_key_path" mapstructure:"private_key_path"`
-					BotUsername       string   `yaml:"bot_username" mapstructure:"bot_username"`
-					BotEmail          string   `yaml:"bot_email" mapstructure:"bot_email"`
-					PRLabel           string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
-					SSHKeyPath        string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
-					MaxThreadDepth    int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
-					KnownBotUsernames []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
-					IgnoredUsernames  []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
-					IgnoredCheckNames []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
-					SkipPRLabel       string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					AppID                int64    `yaml:"app_id" mapstructure:"app_id"`
+					PrivateKeyPath       string   `yaml:"private_key_path" mapstructure:"private_key_path"`
+					BotUsername          string   `yaml:"bot_username" mapstructure:"bot_username"`
+					BotEmail             string   `yaml:"bot_email" mapstructure:"bot_email"`
+					PRLabel              string   `yaml:"pr_label" mapstructure:"pr_label" default:"ai-pr"`
+					SSHKeyPath           string   `yaml:"ssh_key_path" mapstructure:"ssh_key_path"`
+					MaxThreadDepth       int      `yaml:"max_thread_depth" mapstructure:"max_thread_depth" default:"5"`
+					KnownBotUsernames    []string `yaml:"known_bot_usernames" mapstructure:"known_bot_usernames"`
+					IgnoredUsernames     []string `yaml:"ignored_usernames" mapstructure:"ignored_usernames"`
+					IgnoredCommentPaths  []string `yaml:"ignored_comment_paths" mapstructure:"ignored_comment_paths"`
+					IgnoredCheckNames    []string `yaml:"ignored_check_names" mapstructure:"ignored_check_names"`
+					SkipPRLabel          string   `yaml:"skip_pr_label" mapstructure:"skip_pr_label" default:"ai-bot-skip"`
+					FeedbackQuietMinutes int      `yaml:"feedback_quiet_minutes" mapstructure:"feedback_quiet_minutes"`
+					SignOff              bool     `yaml:"sign_off" mapstructure:"sign_off"`
 				}{
 					AppID:          123456,
 					PrivateKeyPath: tempKeyFile.Name(),
@@ -1391,6 +1423,54 @@ func TestConfig_validateClaudeAuth(t *testing.T) {
 		}
 	})
 
+	t.Run("api mode with api_key is valid", func(t *testing.T) {
+		config := validBaseConfig(t)
+		config.Claude.APIKey = "sk-ant-test-key"
+		config.Claude.Mode = AIModeAPI
+		config.Claude.MaxTurns = 50
+		if err := config.validate(); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	})
+
+	t.Run("api mode requires api_key", func(t *testing.T) {
+		config := validBaseConfig(t)
+		config.Claude.VertexProjectID = "my-project"
+		config.Claude.VertexRegion = "us-east5"
+		config.Claude.VertexCredentialsFile = "/host/path/to/sa-key.json"
+		config.Claude.Mode = AIModeAPI
+		config.Claude.MaxTurns = 50
+		err := config.validate()
+		if err == nil || !strings.Contains(err.Error(), "requires api_key") {
+			t.Errorf("error = %v, want 'requires api_key'", err)
+		}
+	})
+
+	t.Run("gemini api mode requires api_key", func(t *testing.T) {
+		config := validBaseConfig(t)
+		config.AIProvider = "gemini"
+		config.Gemini.Mode = AIModeAPI
+		config.Gemini.MaxTurns = 50
+		err := config.validate()
+		if err == nil || !strings.Contains(err.Error(), "gemini: mode \"api\" requires api_key") {
+			t.Errorf("error = %v, want 'requires api_key'", err)
+		}
+		config.Gemini.APIKey = "gemini-key"
+		if err := config.validate(); err != nil {
+			t.Errorf("expected no error with api_key, got: %v", err)
+		}
+	})
+
+	t.Run("unknown mode is invalid", func(t *testing.T) {
+		config := validBaseConfig(t)
+		config.Claude.APIKey = "sk-ant-test-key"
+		config.Claude.Mode = "sdk"
+		err := config.validate()
+		if err == nil || !strings.Contains(err.Error(), "mode must be") {
+			t.Errorf("error = %v, want 'mode must be'", err)
+		}
+	})
+
 }
 
 func TestGuardrailsConfig_ValidateMaxCommitFiles(t *testing.T) {
@@ -1727,6 +1807,187 @@ func TestConfig_validateWorkspaceConfiguration(t *testing.T) {
 			},
 			expectedError: "duplicate repo name",
 		},
+		{
+			name: "merge order names repo outside the workspace",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {
+						Repos: []RepoEntry{
+							{Name: "api", URL: "https://github.com/org/api", Profile: "default"},
+							{Name: "ui", URL: "https://github.com/org/ui", Profile: "default"},
+						},
+						MergeOrder: []string{"api", "web"},
+					},
+				}
+				p.DefaultWorkspace = "ws"
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "merge_order[1]: repo \"web\" is not in the workspace",
+		},
+		{
+			name: "do-not-merge label without merge order",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {
+						Repos: []RepoEntry{
+							{Name: "api", URL: "https://github.com/org/api", Profile: "default"},
+							{Name: "ui", URL: "https://github.com/org/ui", Profile: "default"},
+						},
+						DoNotMergeLabel: "do-not-merge",
+					},
+				}
+				p.DefaultWorkspace = "ws"
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "do_not_merge_label requires a merge_order",
+		},
+		{
+			name: "security scan without command",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				p.SecurityScans = []SecurityScan{{Name: "gitleaks"}}
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "security_scans[0].command is required",
+		},
+		{
+			name: "dependency policy with malformed pattern",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				p.DependencyPolicy = DependencyPolicy{Deny: []string{"github.com/[acme"}}
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "dependency_policy.deny[0]",
+		},
+		{
+			name: "repo subdirectory outside the repository",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo#apps/../../etc", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "repos[0].subdirectory",
+		},
+		{
+			name: "setup cache dirs without command",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				p.Profiles["default"] = Profile{Setup: SetupConfig{CacheDirs: []string{"node_modules"}}}
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "profiles.default.setup: command is required",
+		},
+		{
+			name: "setup lock file outside the repository",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				p.Profiles["default"] = Profile{Setup: SetupConfig{Command: "npm ci", LockFiles: []string{"../package-lock.json"}}}
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "setup: lock_files[0]",
+		},
+		{
+			name: "sparse checkout without subdirectory",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default", SparseCheckout: true}}},
+				}
+				p.DefaultWorkspace = "ws"
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "sparse_checkout requires a subdirectory",
+		},
+		{
+			name: "shared path outside the repository",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{
+						Name: "repo", URL: "https://github.com/org/repo#apps/web", Profile: "default",
+						SparseCheckout: true, SharedPaths: []string{"libs", "../other"},
+					}}},
+				}
+				p.DefaultWorkspace = "ws"
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "shared_paths[1]",
+		},
+		{
+			name: "embargo without private mirror",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				p.Embargo = Embargo{Enabled: true}
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "private_mirror is required when embargo is enabled",
+		},
+		{
+			name: "private mirror without embargo",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default", PrivateMirror: "https://github.com/org/repo-private"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "private_mirror requires embargo.enabled",
+		},
+		{
+			name: "companion repo with path name",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				p.CompanionRepos = []CompanionRepo{{Name: "../e2e", URL: "https://github.com/org/e2e"}}
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: "companion_repos[0].name must be a simple directory name",
+		},
+		{
+			name: "duplicate companion repo",
+			setup: func(c *Config) {
+				p := baseProject()
+				p.Workspaces = map[string]WorkspaceConfig{
+					"ws": {Repos: []RepoEntry{{Name: "repo", URL: "https://github.com/org/repo", Profile: "default"}}},
+				}
+				p.DefaultWorkspace = "ws"
+				p.CompanionRepos = []CompanionRepo{
+					{Name: "e2e", URL: "https://github.com/org/e2e"},
+					{Name: "e2e", URL: "https://github.com/org/e2e-legacy"},
+				}
+				c.Jira.Projects = []ProjectConfig{p}
+			},
+			expectedError: `companion_repos[1].name "e2e" is duplicated`,
+		},
 		{
 			name: "repo with empty profile is valid",
 			setup: func(c *Config) {
@@ -2080,3 +2341,757 @@ guardrails:
 		}
 	})
 }
+
+func TestLoadConfig_FixVersionBranches(t *testing.T) {
+	tmpKeyPath := createTempKeyFile(t)
+	defer func() { _ = os.Remove(tmpKeyPath) }()
+
+	yamlConfig := `
+ai_provider: claude
+claude:
+  api_key: sk-test
+jira:
+  base_url: https://test.atlassian.net
+  username: test-user
+  api_token: test-token
+  projects:
+    - project_keys:
+        - "PROJ1"
+      status_transitions:
+        bug:
+          todo: "To Do"
+          in_progress: "In Progress"
+          in_review: "In Review"
+      workspaces:
+        default:
+          repos:
+            - name: repo
+              url: "https://github.com/test/repo"
+              profile: default
+      components:
+        "comp":
+          workspace: default
+      profiles:
+        default: {}
+      fix_version_branches:
+        - fix_version: "4.18.z"
+          branch: "release-4.18"
+github:
+  app_id: 123456
+  private_key_path: "` + tmpKeyPath + `"
+  bot_username: "test-bot"
+workspaces:
+  base_dir: /tmp/test-workspaces
+  ttl_days: 7
+`
+	tmpfile, err := os.CreateTemp("", "config_test_*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	if _, err := tmpfile.WriteString(yamlConfig); err != nil {
+		t.Fatal(err)
+	}
+	_ = tmpfile.Close()
+
+	config, err := LoadConfig(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	want := FixVersionBranches{{FixVersion: "4.18.z", Branch: "release-4.18"}}
+	if got := config.Jira.Projects[0].FixVersionBranches; !slices.Equal(got, want) {
+		t.Errorf("FixVersionBranches = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadConfig_MinFreeDiskMB(t *testing.T) {
+	tmpKeyPath := createTempKeyFile(t)
+	defer func() { _ = os.Remove(tmpKeyPath) }()
+
+	baseConfig := `
+ai_provider: claude
+claude:
+  api_key: sk-test
+jira:
+  base_url: https://test.atlassian.net
+  username: test-user
+  api_token: test-token
+  projects:
+    - project_keys:
+        - "PROJ1"
+      status_transitions:
+        bug:
+          todo: "To Do"
+          in_progress: "In Progress"
+          in_review: "In Review"
+      workspaces:
+        default:
+          repos:
+            - name: repo
+              url: "https://github.com/test/repo"
+              profile: default
+      components:
+        "comp":
+          workspace: default
+      profiles:
+        default: {}
+github:
+  app_id: 123456
+  private_key_path: "` + tmpKeyPath + `"
+  bot_username: "test-bot"
+workspaces:
+  base_dir: /tmp/test-workspaces
+  ttl_days: 7
+`
+
+	tests := []struct {
+		name        string
+		extra       string
+		want        int
+		expectedErr string
+	}{
+		{name: "default value", want: 1024},
+		{name: "YAML override", extra: "server:\n  min_free_disk_mb: 4096\n", want: 4096},
+		{name: "zero disables check", extra: "server:\n  min_free_disk_mb: 0\n", want: 0},
+		{name: "negative is invalid", extra: "server:\n  min_free_disk_mb: -1\n", expectedErr: "server.min_free_disk_mb must be non-negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JIRA_AI_SERVER_MIN_FREE_DISK_MB", "")
+			tmpfile, err := os.CreateTemp("", "config_test_*.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.Remove(tmpfile.Name()) }()
+			if _, err := tmpfile.WriteString(baseConfig + tt.extra); err != nil {
+				t.Fatal(err)
+			}
+			_ = tmpfile.Close()
+
+			config, err := LoadConfig(tmpfile.Name())
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if config.Server.MinFreeDiskMB != tt.want {
+				t.Errorf("MinFreeDiskMB = %d, want %d", config.Server.MinFreeDiskMB, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracingConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           TracingConfig
+		expectedError string
+	}{
+		{name: "disabled with zero values is valid", cfg: TracingConfig{}},
+		{name: "enabled with defaults is valid", cfg: TracingConfig{Enabled: true, ServiceName: "bot", SampleRatio: 1}},
+		{name: "negative sample ratio", cfg: TracingConfig{SampleRatio: -0.1}, expectedError: "tracing.sample_ratio must be between 0 and 1"},
+		{name: "sample ratio above one", cfg: TracingConfig{SampleRatio: 1.5}, expectedError: "tracing.sample_ratio must be between 0 and 1"},
+		{name: "enabled without service name", cfg: TracingConfig{Enabled: true, SampleRatio: 1}, expectedError: "tracing.service_name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("expected error containing %q, got nil", tt.expectedError)
+				} else if !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRepoIndexConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           RepoIndexConfig
+		expectedError string
+	}{
+		{name: "disabled with zero values is valid", cfg: RepoIndexConfig{}},
+		{name: "enabled with defaults is valid", cfg: RepoIndexConfig{Enabled: true, RefreshHours: 24, MaxFiles: 20}},
+		{name: "zero refresh hours", cfg: RepoIndexConfig{Enabled: true, MaxFiles: 20}, expectedError: "repo_index.refresh_hours must be positive"},
+		{name: "zero max files", cfg: RepoIndexConfig{Enabled: true, RefreshHours: 24}, expectedError: "repo_index.max_files must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestDuplicateDetectionConfig_Validate(t *testing.T) {
+	valid := DuplicateDetectionConfig{Enabled: true, Threshold: 0.5, RetentionDays: 90, MaxMatches: 3, LinkType: "Relates", IgnoreLabel: "ai-not-duplicate"}
+	with := func(mutate func(*DuplicateDetectionConfig)) DuplicateDetectionConfig {
+		cfg := valid
+		mutate(&cfg)
+		return cfg
+	}
+	tests := []struct {
+		name          string
+		cfg           DuplicateDetectionConfig
+		expectedError string
+	}{
+		{name: "disabled with zero values is valid", cfg: DuplicateDetectionConfig{}},
+		{name: "enabled with defaults is valid", cfg: valid},
+		{name: "zero threshold", cfg: with(func(c *DuplicateDetectionConfig) { c.Threshold = 0 }), expectedError: "duplicate_detection.threshold"},
+		{name: "threshold above one", cfg: with(func(c *DuplicateDetectionConfig) { c.Threshold = 1.2 }), expectedError: "duplicate_detection.threshold"},
+		{name: "zero retention", cfg: with(func(c *DuplicateDetectionConfig) { c.RetentionDays = 0 }), expectedError: "duplicate_detection.retention_days must be positive"},
+		{name: "zero max matches", cfg: with(func(c *DuplicateDetectionConfig) { c.MaxMatches = 0 }), expectedError: "duplicate_detection.max_matches must be positive"},
+		{name: "no link type", cfg: with(func(c *DuplicateDetectionConfig) { c.LinkType = " " }), expectedError: "duplicate_detection.link_type is required"},
+		{name: "no ignore label", cfg: with(func(c *DuplicateDetectionConfig) { c.IgnoreLabel = "" }), expectedError: "duplicate_detection.ignore_label is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLinkContextConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           LinkContextConfig
+		expectedError string
+	}{
+		{name: "disabled with zero values is valid", cfg: LinkContextConfig{}},
+		{name: "enabled with defaults is valid", cfg: LinkContextConfig{Enabled: true, AllowedDomains: []string{"github.com"}, MaxLinks: 5, MaxKB: 64}},
+		{name: "no domains", cfg: LinkContextConfig{Enabled: true, MaxLinks: 5, MaxKB: 64}, expectedError: "link_context.allowed_domains is required"},
+		{name: "URL instead of domain", cfg: LinkContextConfig{Enabled: true, AllowedDomains: []string{"https://github.com"}, MaxLinks: 5, MaxKB: 64}, expectedError: "is not a domain name"},
+		{name: "wildcard domain", cfg: LinkContextConfig{Enabled: true, AllowedDomains: []string{"*.example.com"}, MaxLinks: 5, MaxKB: 64}, expectedError: "is not a domain name"},
+		{name: "zero max links", cfg: LinkContextConfig{Enabled: true, AllowedDomains: []string{"github.com"}, MaxKB: 64}, expectedError: "link_context.max_links must be positive"},
+		{name: "zero max kb", cfg: LinkContextConfig{Enabled: true, AllowedDomains: []string{"github.com"}, MaxLinks: 5}, expectedError: "link_context.max_kb must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestModelRoutingConfig_Validate(t *testing.T) {
+	valid := func(edit func(*ModelRoutingConfig)) ModelRoutingConfig {
+		c := ModelRoutingConfig{
+			Models:          map[string]ModelTiers{"claude": {Simple: "small", Complex: "large"}},
+			LongDescription: 2000,
+			ManyComponents:  2,
+			MinScore:        2,
+			ComplexLabel:    "ai-complex",
+			SimpleLabel:     "ai-simple",
+		}
+		if edit != nil {
+			edit(&c)
+		}
+		return c
+	}
+	tests := []struct {
+		name          string
+		cfg           ModelRoutingConfig
+		expectedError string
+	}{
+		{name: "disabled with zero values is valid", cfg: ModelRoutingConfig{}},
+		{name: "enabled with defaults is valid", cfg: valid(nil)},
+		{name: "one tier is valid", cfg: valid(func(c *ModelRoutingConfig) { c.Models["gemini"] = ModelTiers{Complex: "pro"} })},
+		{name: "no models for provider", cfg: valid(func(c *ModelRoutingConfig) { c.Models["gemini"] = ModelTiers{} }), expectedError: "model_routing.models.gemini"},
+		{name: "zero long description", cfg: valid(func(c *ModelRoutingConfig) { c.LongDescription = 0 }), expectedError: "model_routing.long_description must be positive"},
+		{name: "zero many components", cfg: valid(func(c *ModelRoutingConfig) { c.ManyComponents = 0 }), expectedError: "model_routing.many_components must be positive"},
+		{name: "min score too high", cfg: valid(func(c *ModelRoutingConfig) { c.MinScore = 5 }), expectedError: "model_routing.min_score must be between 1 and 4"},
+		{name: "same labels", cfg: valid(func(c *ModelRoutingConfig) { c.SimpleLabel = "ai-complex" }), expectedError: "must differ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSecretsConfig_Validate(t *testing.T) {
+	if err := (&SecretsConfig{RefreshMinutes: 15}).validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	err := (&SecretsConfig{RefreshMinutes: -1}).validate()
+	if err == nil || !strings.Contains(err.Error(), "secrets.refresh_minutes must be non-negative") {
+		t.Errorf("expected refresh_minutes error, got: %v", err)
+	}
+}
+
+func TestNetworkConfig_Validate(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(existing, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		cfg           NetworkConfig
+		expectedError string
+	}{
+		{name: "empty is valid", cfg: NetworkConfig{}},
+		{name: "proxy and files", cfg: NetworkConfig{ProxyURL: "http://proxy:3128", CABundle: existing, ClientCert: existing, ClientKey: existing}},
+		{name: "proxy without scheme", cfg: NetworkConfig{ProxyURL: "proxy:3128"}, expectedError: "network.proxy_url must be an http or https URL"},
+		{name: "socks proxy", cfg: NetworkConfig{ProxyURL: "socks5://proxy:1080"}, expectedError: "network.proxy_url must be an http or https URL"},
+		{name: "cert without key", cfg: NetworkConfig{ClientCert: existing}, expectedError: "must be set together"},
+		{name: "missing CA bundle", cfg: NetworkConfig{CABundle: existing + ".missing"}, expectedError: "network.ca_bundle file does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateServer(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "server.pem")
+	if err := os.WriteFile(existing, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tlsOn := ServerTLSConfig{CertFile: existing, KeyFile: existing}
+
+	tests := []struct {
+		name          string
+		tls           ServerTLSConfig
+		auth          ServerAuthConfig
+		expectedError string
+	}{
+		{name: "empty is valid"},
+		{name: "mutual TLS", tls: ServerTLSConfig{CertFile: existing, KeyFile: existing, ClientCAFile: existing}},
+		{name: "cert without key", tls: ServerTLSConfig{CertFile: existing}, expectedError: "must be set together"},
+		{name: "client CA without cert", tls: ServerTLSConfig{ClientCAFile: existing}, expectedError: "server.tls.client_ca_file requires server.tls.cert_file"},
+		{name: "missing key", tls: ServerTLSConfig{CertFile: existing, KeyFile: existing + ".missing"}, expectedError: "server.tls.key_file file does not exist"},
+		{name: "bearer tokens", tls: tlsOn, auth: ServerAuthConfig{BearerTokens: []string{"token"}}},
+		{name: "blank bearer token", auth: ServerAuthConfig{BearerTokens: []string{"token", " "}}, expectedError: "server.auth.bearer_tokens[1] must not be empty"},
+		{name: "oidc", auth: ServerAuthConfig{OIDC: ServerOIDCConfig{IssuerURL: "https://login.example.com", Audience: "bot"}}},
+		{name: "oidc without audience", auth: ServerAuthConfig{OIDC: ServerOIDCConfig{IssuerURL: "https://login.example.com"}}, expectedError: "server.auth.oidc.audience is required"},
+		{name: "oidc issuer without scheme", auth: ServerAuthConfig{OIDC: ServerOIDCConfig{IssuerURL: "login.example.com", Audience: "bot"}}, expectedError: "server.auth.oidc.issuer_url must be an http or https URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			cfg.Server.TLS = tt.tls
+			cfg.Server.Auth = tt.auth
+			err := cfg.validateServer()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestJiraConfig_ValidateAuth(t *testing.T) {
+	oauth := JiraOAuthConfig{ClientID: "id", ClientSecret: "secret", RefreshToken: "refresh", CloudID: "cloud"}
+
+	tests := []struct {
+		name    string
+		jira    JiraConfig
+		wantErr bool
+	}{
+		{name: "basic", jira: JiraConfig{Username: "bot", APIToken: "token"}},
+		{name: "basic without username", jira: JiraConfig{AuthType: JiraAuthBasic, APIToken: "token"}, wantErr: true},
+		{name: "pat needs no username", jira: JiraConfig{AuthType: JiraAuthPAT, APIToken: "token"}},
+		{name: "pat without token", jira: JiraConfig{AuthType: JiraAuthPAT}, wantErr: true},
+		{name: "oauth2", jira: JiraConfig{AuthType: JiraAuthOAuth2, OAuth: oauth}},
+		{name: "oauth2 with token file only", jira: JiraConfig{AuthType: JiraAuthOAuth2, OAuth: JiraOAuthConfig{ClientID: "id", ClientSecret: "secret", TokenFile: "/var/lib/bot/token", CloudID: "cloud"}}},
+		{name: "oauth2 without refresh token", jira: JiraConfig{AuthType: JiraAuthOAuth2, OAuth: JiraOAuthConfig{ClientID: "id", ClientSecret: "secret", CloudID: "cloud"}}, wantErr: true},
+		{name: "oauth2 without cloud id", jira: JiraConfig{AuthType: JiraAuthOAuth2, OAuth: JiraOAuthConfig{ClientID: "id", ClientSecret: "secret", RefreshToken: "refresh"}}, wantErr: true},
+		{name: "unknown type", jira: JiraConfig{AuthType: "kerberos"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.jira.validateAuth()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJiraCommentVisibility_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility JiraCommentVisibility
+		wantErr    bool
+	}{
+		{name: "unset"},
+		{name: "role", visibility: JiraCommentVisibility{Type: JiraVisibilityRole, Value: "Developers"}},
+		{name: "group", visibility: JiraCommentVisibility{Type: JiraVisibilityGroup, Value: "security-team"}},
+		{name: "missing type", visibility: JiraCommentVisibility{Value: "Developers"}, wantErr: true},
+		{name: "unknown type", visibility: JiraCommentVisibility{Type: "user", Value: "alice"}, wantErr: true},
+		{name: "missing value", visibility: JiraCommentVisibility{Type: JiraVisibilityRole}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.visibility.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJiraCircuitBreakerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		breaker JiraCircuitBreakerConfig
+		wantErr bool
+	}{
+		{name: "disabled"},
+		{name: "defaults", breaker: JiraCircuitBreakerConfig{Threshold: 5, CooldownSeconds: 30, MaxCooldownMinutes: 10}},
+		{name: "negative threshold", breaker: JiraCircuitBreakerConfig{Threshold: -1}, wantErr: true},
+		{name: "missing cooldown", breaker: JiraCircuitBreakerConfig{Threshold: 5}, wantErr: true},
+		{name: "negative max cooldown", breaker: JiraCircuitBreakerConfig{Threshold: 5, CooldownSeconds: 30, MaxCooldownMinutes: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.breaker.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRepoEntry_Location(t *testing.T) {
+	tests := []struct {
+		name       string
+		entry      RepoEntry
+		wantURL    string
+		wantSubdir string
+	}{
+		{"plain URL", RepoEntry{URL: "https://github.com/org/mono.git"}, "https://github.com/org/mono.git", ""},
+		{"URL fragment", RepoEntry{URL: "https://github.com/org/mono.git#apps/web/"}, "https://github.com/org/mono.git", "apps/web"},
+		{"subdirectory field", RepoEntry{URL: "https://github.com/org/mono", Subdirectory: "/apps//api"}, "https://github.com/org/mono", "apps/api"},
+		{"field overrides fragment", RepoEntry{URL: "https://github.com/org/mono#apps/web", Subdirectory: "apps/api"}, "https://github.com/org/mono", "apps/api"},
+		{"root directory", RepoEntry{URL: "https://github.com/org/mono#.", Subdirectory: ""}, "https://github.com/org/mono", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotURL, gotSubdir := tt.entry.Location()
+			if gotURL != tt.wantURL || gotSubdir != tt.wantSubdir {
+				t.Errorf("Location() = %q, %q; want %q, %q", gotURL, gotSubdir, tt.wantURL, tt.wantSubdir)
+			}
+		})
+	}
+}
+
+func TestRepoEntry_SparsePaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry RepoEntry
+		want  []string
+	}{
+		{"disabled", RepoEntry{URL: "https://github.com/org/mono#apps/web", SharedPaths: []string{"libs"}}, nil},
+		{"no subdirectory", RepoEntry{URL: "https://github.com/org/mono", SparseCheckout: true}, nil},
+		{"subdirectory only", RepoEntry{URL: "https://github.com/org/mono#apps/web", SparseCheckout: true}, []string{".ai-bot", ".devcontainer", "apps/web"}},
+		{"shared paths", RepoEntry{
+			URL: "https://github.com/org/mono", Subdirectory: "apps/web", SparseCheckout: true,
+			SharedPaths: []string{"/libs/ui/", "apps/web", "proto"},
+		}, []string{".ai-bot", ".devcontainer", "apps/web", "libs/ui", "proto"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.SparsePaths(); !slices.Equal(got, tt.want) {
+				t.Errorf("SparsePaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTargetBranch(t *testing.T) {
+	tests := []struct {
+		in, wantURL, wantBranch string
+	}{
+		{"https://github.com/org/repo.git", "https://github.com/org/repo.git", ""},
+		{"https://github.com/org/repo.git@release-4.18", "https://github.com/org/repo.git", "release-4.18"},
+		{"https://github.com/org/repo.git@release/4.18", "https://github.com/org/repo.git", "release/4.18"},
+		{"https://github.com/org/mono.git#apps/web@release-4.18", "https://github.com/org/mono.git#apps/web", "release-4.18"},
+		{"git@github.com:org/repo.git", "git@github.com:org/repo.git", ""},
+		{"git@github.com:org/repo.git@release-4.18", "git@github.com:org/repo.git", "release-4.18"},
+		{"https://user@github.com/org/repo.git", "https://user@github.com/org/repo.git", ""},
+		{"https://github.com/org/repo.git@", "https://github.com/org/repo.git@", ""},
+	}
+	for _, tt := range tests {
+		gotURL, gotBranch := splitTargetBranch(tt.in)
+		if gotURL != tt.wantURL || gotBranch != tt.wantBranch {
+			t.Errorf("splitTargetBranch(%q) = %q, %q; want %q, %q", tt.in, gotURL, gotBranch, tt.wantURL, tt.wantBranch)
+		}
+	}
+}
+
+func TestGuardrailsConfig_ValidateMaxParallelRepos(t *testing.T) {
+	g := &GuardrailsConfig{MaxConcurrentJobs: 1, MaxParallelRepos: 0}
+	if err := g.validate(); err != nil {
+		t.Errorf("zero: unexpected error: %v", err)
+	}
+	g.MaxParallelRepos = -1
+	if err := g.validate(); err == nil || !strings.Contains(err.Error(), "guardrails.max_parallel_repos must be non-negative") {
+		t.Errorf("negative: error = %v", err)
+	}
+}
+
+func TestGuardrailsConfig_ValidateDeadLetterWebhookURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		label   string
+		wantErr string
+	}{
+		{name: "unset"},
+		{name: "valid", url: "https://hooks.example.com/T0/B0/x", label: "ai-dead-letter"},
+		{name: "not http", url: "ftp://hooks.example.com/x", label: "ai-dead-letter", wantErr: "must be an http(s) URL"},
+		{name: "no host", url: "https:///x", label: "ai-dead-letter", wantErr: "must be an http(s) URL"},
+		{name: "no dead-letter label", url: "https://hooks.example.com/x", wantErr: "requires guardrails.dead_letter_label"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GuardrailsConfig{MaxConcurrentJobs: 1, DeadLetterWebhookURL: tt.url, DeadLetterLabel: tt.label}
+			err := g.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_validateAILimits(t *testing.T) {
+	var c Config
+	c.Guardrails = GuardrailsConfig{MaxConcurrentJobs: 1, MaxConcurrentAISessions: 2}
+	c.Claude.MaxConcurrentSessions = 1
+	c.Claude.SessionsPerMinute = 10
+	if err := c.validateAILimits(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := c.Guardrails.validate(); err != nil {
+		t.Errorf("unexpected guardrails error: %v", err)
+	}
+
+	c.Gemini.SessionsPerMinute = -1
+	if err := c.validateAILimits(); err == nil || !strings.Contains(err.Error(), "gemini.sessions_per_minute must be non-negative") {
+		t.Errorf("negative gemini rate: error = %v", err)
+	}
+	c.Guardrails.MaxConcurrentAISessions = -1
+	if err := c.Guardrails.validate(); err == nil || !strings.Contains(err.Error(), "guardrails.max_concurrent_ai_sessions must be non-negative") {
+		t.Errorf("negative global limit: error = %v", err)
+	}
+}
+
+func TestCostBudget_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  CostBudget
+		wantErr string
+	}{
+		{name: "disabled", budget: CostBudget{}},
+		{name: "daily and monthly", budget: CostBudget{DailyUSD: 50, MonthlyUSD: 500}},
+		{name: "negative daily", budget: CostBudget{DailyUSD: -1}, wantErr: "budget.daily_usd"},
+		{name: "infinite monthly", budget: CostBudget{MonthlyUSD: math.Inf(1)}, wantErr: "budget.monthly_usd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.budget.validate("budget")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGiteaConfig_Validate(t *testing.T) {
+	valid := GiteaInstance{Host: "forgejo.example.com", Token: "tok"}
+	tests := []struct {
+		name          string
+		instances     []GiteaInstance
+		expectedError string
+	}{
+		{name: "no instances is valid"},
+		{name: "host and token is valid", instances: []GiteaInstance{valid}},
+		{name: "API URL is valid", instances: []GiteaInstance{{Host: "git.example.com", APIURL: "https://git.example.com/gitea/api/v1", Token: "tok"}}},
+		{name: "URL instead of host", instances: []GiteaInstance{{Host: "https://forgejo.example.com", Token: "tok"}}, expectedError: "is not a host name"},
+		{name: "github.com", instances: []GiteaInstance{{Host: "github.com", Token: "tok"}}, expectedError: "must not be github.com"},
+		{name: "duplicate host", instances: []GiteaInstance{valid, {Host: "Forgejo.example.com", Token: "tok"}}, expectedError: "listed twice"},
+		{name: "bad API URL", instances: []GiteaInstance{{Host: "git.example.com", APIURL: "git.example.com/api", Token: "tok"}}, expectedError: "is not an http(s) URL"},
+		{name: "missing token", instances: []GiteaInstance{{Host: "git.example.com"}}, expectedError: "gitea.instances[0].token is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&GiteaConfig{Instances: tt.instances}).validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestAzureDevOpsConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        AzureDevOpsConfig
+		expectedError string
+	}{
+		{name: "no token is valid", config: AzureDevOpsConfig{Host: "dev.azure.com"}},
+		{name: "token and organization is valid", config: AzureDevOpsConfig{Host: "dev.azure.com", Token: "pat", Organizations: []string{"org"}}},
+		{name: "server collection is valid", config: AzureDevOpsConfig{Host: "tfs.example.com", Token: "pat", Organizations: []string{"tfs/DefaultCollection"}}},
+		{name: "URL instead of host", config: AzureDevOpsConfig{Host: "https://dev.azure.com", Token: "pat", Organizations: []string{"org"}}, expectedError: "is not a host name"},
+		{name: "github.com", config: AzureDevOpsConfig{Host: "github.com", Token: "pat", Organizations: []string{"org"}}, expectedError: "must not be github.com"},
+		{name: "missing organizations", config: AzureDevOpsConfig{Host: "dev.azure.com", Token: "pat"}, expectedError: "organizations is required"},
+		{name: "organization with slashes", config: AzureDevOpsConfig{Host: "dev.azure.com", Token: "pat", Organizations: []string{"/org/"}}, expectedError: "is not an organization name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestBedrockConfig_Validate(t *testing.T) {
+	keys := BedrockConfig{Region: "us-east-1", Model: "us.anthropic.claude-sonnet-4-5-20250929-v1:0", AccessKeyID: "AKID", SecretAccessKey: "secret", MaxTurns: 100}
+	tests := []struct {
+		name          string
+		config        func(*BedrockConfig)
+		selected      bool
+		expectedError string
+	}{
+		{name: "access keys are valid", config: func(*BedrockConfig) {}},
+		{name: "API key is valid", config: func(b *BedrockConfig) { b.AccessKeyID, b.SecretAccessKey, b.APIKey = "", "", "key" }},
+		{name: "unconfigured and not selected is valid", config: func(b *BedrockConfig) { *b = BedrockConfig{} }},
+		{name: "unconfigured but selected", config: func(b *BedrockConfig) { *b = BedrockConfig{Region: "us-east-1"} }, selected: true, expectedError: "bedrock.model is required"},
+		{name: "missing region", config: func(b *BedrockConfig) { b.Region = "" }, expectedError: "bedrock.region is required"},
+		{name: "half of the access keys", config: func(b *BedrockConfig) { b.SecretAccessKey = "" }, expectedError: "must be set together"},
+		{name: "access keys and API key", config: func(b *BedrockConfig) { b.APIKey = "key" }, expectedError: "mutually exclusive"},
+		{name: "no credentials", config: func(b *BedrockConfig) { b.AccessKeyID, b.SecretAccessKey = "", "" }, expectedError: "set access_key_id"},
+		{name: "bad endpoint", config: func(b *BedrockConfig) { b.Endpoint = "vpce.example.com" }, expectedError: "is not an http(s) URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := keys
+			tt.config(&config)
+			err := config.validate(tt.selected)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestAzureOpenAIConfig_Validate(t *testing.T) {
+	apiKey := AzureOpenAIConfig{Endpoint: "https://res.openai.azure.com", Deployment: "gpt-4.1", APIKey: "key", MaxTurns: 100}
+	tests := []struct {
+		name          string
+		config        func(*AzureOpenAIConfig)
+		selected      bool
+		expectedError string
+	}{
+		{name: "API key is valid", config: func(*AzureOpenAIConfig) {}},
+		{name: "Entra ID application is valid", config: func(a *AzureOpenAIConfig) { a.APIKey, a.TenantID, a.ClientID, a.ClientSecret = "", "t", "c", "s" }},
+		{name: "unconfigured and not selected is valid", config: func(a *AzureOpenAIConfig) { *a = AzureOpenAIConfig{} }},
+		{name: "unconfigured but selected", config: func(a *AzureOpenAIConfig) { *a = AzureOpenAIConfig{} }, selected: true, expectedError: "is not an http(s) URL"},
+		{name: "missing deployment", config: func(a *AzureOpenAIConfig) { a.Deployment = "" }, expectedError: "deployment is required"},
+		{name: "API key and Entra ID", config: func(a *AzureOpenAIConfig) { a.TenantID, a.ClientID, a.ClientSecret = "t", "c", "s" }, expectedError: "mutually exclusive"},
+		{name: "incomplete Entra ID", config: func(a *AzureOpenAIConfig) { a.APIKey, a.TenantID = "", "t" }, expectedError: "must be set together"},
+		{name: "no credentials", config: func(a *AzureOpenAIConfig) { a.APIKey = "" }, expectedError: "set api_key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := apiKey
+			tt.config(&config)
+			err := config.validate(tt.selected)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}
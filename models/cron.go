@@ -0,0 +1,224 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronTZPrefix selects the time zone a cron expression is evaluated
+// in, e.g. "CRON_TZ=Europe/Berlin */5 8-18 * * MON-FRI".
+const cronTZPrefix = "CRON_TZ="
+
+// maxCronSearch bounds how far ahead [CronSchedule.Next] looks for a
+// matching time; an expression such as "0 0 31 2 *" never matches.
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// cronField describes one of the five fields of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: cronMonthNames},
+	// 7 is accepted as Sunday, as in most cron implementations.
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// CronSchedule is a parsed five-field cron expression ("minute hour
+// day-of-month month day-of-week"), e.g. "*/5 8-18 * * MON-FRI". Each
+// field accepts "*", numbers, ranges ("8-18"), steps ("*/5",
+// "0-30/10"), lists ("1,15"), and for months and weekdays
+// three-letter names. As in standard cron, when both day fields are
+// restricted a day matches if either does. The expression is
+// evaluated in UTC unless prefixed with "CRON_TZ=<IANA zone> ".
+type CronSchedule struct {
+	expr string
+	loc  *time.Location
+
+	// minutes through weekdays hold the allowed values of each field,
+	// indexed by value.
+	minutes  [60]bool
+	hours    [24]bool
+	days     [32]bool
+	months   [13]bool
+	weekdays [7]bool
+
+	// anyDay and anyWeekday record an unrestricted ("*") day field.
+	anyDay, anyWeekday bool
+}
+
+// ParseCron parses a cron expression (see [CronSchedule]).
+func ParseCron(expr string) (*CronSchedule, error) {
+	c := &CronSchedule{expr: expr, loc: time.UTC}
+
+	rest := strings.TrimSpace(expr)
+	if strings.HasPrefix(rest, cronTZPrefix) {
+		zone, fields, _ := strings.Cut(strings.TrimPrefix(rest, cronTZPrefix), " ")
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("time zone: %w", err)
+		}
+		c.loc = loc
+		rest = fields
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	sets := make([][]bool, len(cronFields))
+	for i, f := range cronFields {
+		set, err := f.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		sets[i] = set
+	}
+	copy(c.minutes[:], sets[0])
+	copy(c.hours[:], sets[1])
+	copy(c.days[:], sets[2])
+	copy(c.months[:], sets[3])
+	copy(c.weekdays[:], sets[4])
+	c.weekdays[0] = c.weekdays[0] || sets[4][7]
+	c.anyDay = fields[2] == "*"
+	c.anyWeekday = fields[4] == "*"
+	return c, nil
+}
+
+// parse returns the set of values the field expression allows,
+// indexed by value.
+func (f cronField) parse(expr string) ([]bool, error) {
+	set := make([]bool, f.max+1)
+	for _, part := range strings.Split(expr, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return nil, err
+			}
+			if hi, err = f.value(b); err != nil {
+				return nil, err
+			}
+			if lo > hi {
+				return nil, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return nil, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// value parses a single number or name within the field's bounds.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (c *CronSchedule) String() string {
+	return c.expr
+}
+
+// Next returns the first time strictly after t that the schedule
+// matches, in t's location. Returns the zero time if the schedule
+// never matches.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	local := t.In(c.loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), 0, 0, c.loc).
+		Add(time.Minute)
+	limit := next.Add(maxCronSearch)
+
+	for next.Before(limit) {
+		switch {
+		case !c.months[next.Month()]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, c.loc)
+		case !c.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, c.loc)
+		case !c.hours[next.Hour()]:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, c.loc)
+		case !c.minutes[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next.In(t.Location())
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's rule for the two day fields: when both
+// are restricted, either may match.
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	day := c.days[t.Day()]
+	weekday := c.weekdays[t.Weekday()]
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// ValidateCron checks that expr parses and matches at some time.
+func ValidateCron(expr string) error {
+	c, err := ParseCron(expr)
+	if err != nil {
+		return err
+	}
+	if c.Next(time.Now()).IsZero() {
+		return errors.New("expression never matches")
+	}
+	return nil
+}
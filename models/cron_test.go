@@ -0,0 +1,88 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "empty", expr: ""},
+		{name: "too few fields", expr: "*/5 8-18 * *"},
+		{name: "too many fields", expr: "0 */5 8-18 * * MON-FRI"},
+		{name: "minute out of range", expr: "60 * * * *"},
+		{name: "reversed range", expr: "* 18-8 * * *"},
+		{name: "zero step", expr: "*/0 * * * *"},
+		{name: "unknown name", expr: "* * * * FUN"},
+		{name: "unknown time zone", expr: "CRON_TZ=Mars/Olympus * * * * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := models.ParseCron(tt.expr); err == nil {
+				t.Errorf("ParseCron(%q) succeeded, want error", tt.expr)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// 2025-01-06 is a Monday.
+	monday := func(hour, min int) time.Time {
+		return time.Date(2025, 1, 6, hour, min, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{name: "next step", expr: "*/5 8-18 * * MON-FRI", from: monday(9, 2), want: monday(9, 5)},
+		{name: "strictly after", expr: "*/5 8-18 * * MON-FRI", from: monday(9, 5), want: monday(9, 10)},
+		{name: "before hours", expr: "*/5 8-18 * * MON-FRI", from: monday(6, 30), want: monday(8, 0)},
+		{name: "after hours", expr: "*/5 8-18 * * MON-FRI", from: monday(18, 55), want: monday(19, 0).Add(13 * time.Hour)},
+		{
+			name: "skips weekend", expr: "*/5 8-18 * * MON-FRI",
+			from: time.Date(2025, 1, 10, 19, 0, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 13, 8, 0, 0, 0, time.UTC),
+		},
+		{name: "list", expr: "0,30 9 * * *", from: monday(9, 10), want: monday(9, 30)},
+		{
+			name: "either day field", expr: "0 0 15 * SUN",
+			from: monday(0, 0),
+			want: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{name: "seven is sunday", expr: "0 0 * * 7", from: monday(0, 0), want: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{
+			name: "month name", expr: "0 0 1 mar *",
+			from: monday(0, 0),
+			want: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "time zone", expr: "CRON_TZ=America/New_York 0 9 * * *",
+			from: monday(12, 0),
+			want: monday(14, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := models.ParseCron(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCron_NeverMatches(t *testing.T) {
+	if err := models.ValidateCron("0 0 31 2 *"); err == nil {
+		t.Error("expected error for an expression that never matches")
+	}
+}
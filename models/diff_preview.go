@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DiffPreview modes.
+const (
+	// DiffPreviewAlways previews every new-ticket change.
+	DiffPreviewAlways = "always"
+
+	// DiffPreviewLowConfidence previews only the changes held back by
+	// [ProjectConfig.MinConfidence], which are otherwise escalated.
+	DiffPreviewLowConfidence = "low_confidence"
+)
+
+// DiffPreviewMarker identifies the bot's preview comment on a ticket.
+// Approval comments only count when posted after the latest preview.
+const DiffPreviewMarker = "[AI-BOT-PREVIEW]"
+
+// Defaults used when the corresponding DiffPreview field is empty.
+const (
+	defaultDiffPreviewLabel           = "ai-awaiting-approval"
+	defaultDiffPreviewApprovalLabel   = "ai-approved"
+	defaultDiffPreviewApprovalComment = "/approve"
+)
+
+// DiffPreview posts the diff of a new-ticket change to the ticket for
+// approval before any PR is opened. The previewed ticket moves to the
+// in-review status with Label; once someone adds ApprovalLabel or
+// comments ApprovalComment, the change is committed and its PR opened.
+// Anyone who may label or comment on the ticket can approve, except
+// the bot's own Jira account; restrict approvers with Jira
+// permissions. Previews apply to single-repository, unbatched tickets.
+type DiffPreview struct {
+	// Mode selects which changes are previewed: "always" or
+	// "low_confidence". Empty disables previews.
+	Mode string `yaml:"mode,omitempty" mapstructure:"mode"`
+
+	// Label marks tickets awaiting approval. Empty means
+	// "ai-awaiting-approval".
+	Label string `yaml:"label,omitempty" mapstructure:"label"`
+
+	// ApprovalLabel approves a previewed change when added to the
+	// ticket. Empty means "ai-approved".
+	ApprovalLabel string `yaml:"approval_label,omitempty" mapstructure:"approval_label"`
+
+	// ApprovalComment approves a previewed change when posted as a
+	// ticket comment of its own, ignoring case and surrounding
+	// whitespace. Empty means "/approve".
+	ApprovalComment string `yaml:"approval_comment,omitempty" mapstructure:"approval_comment"`
+}
+
+// IsEnabled reports whether previews are configured.
+func (d DiffPreview) IsEnabled() bool {
+	return d.Mode != ""
+}
+
+// Validate checks the mode.
+func (d DiffPreview) Validate() error {
+	switch d.Mode {
+	case "", DiffPreviewAlways, DiffPreviewLowConfidence:
+		return nil
+	default:
+		return fmt.Errorf("mode must be one of always, low_confidence (got %q)", d.Mode)
+	}
+}
+
+// AwaitingLabel returns the label marking tickets awaiting approval.
+func (d DiffPreview) AwaitingLabel() string {
+	if d.Label == "" {
+		return defaultDiffPreviewLabel
+	}
+	return d.Label
+}
+
+// ApprovedLabel returns the label that approves a preview.
+func (d DiffPreview) ApprovedLabel() string {
+	if d.ApprovalLabel == "" {
+		return defaultDiffPreviewApprovalLabel
+	}
+	return d.ApprovalLabel
+}
+
+// ApprovalCommand returns the comment that approves a preview.
+func (d DiffPreview) ApprovalCommand() string {
+	if d.ApprovalComment == "" {
+		return defaultDiffPreviewApprovalComment
+	}
+	return d.ApprovalComment
+}
+
+// Awaiting reports whether labels mark a ticket as awaiting approval
+// of a previewed change. Always false when previews are disabled.
+func (d DiffPreview) Awaiting(labels []string) bool {
+	return d.IsEnabled() && slices.Contains(labels, d.AwaitingLabel())
+}
+
+// Approved reports whether a ticket's previewed change is approved:
+// labels include the approval label, or one of comments, which are in
+// posting order, is the approval command and follows the latest
+// preview comment. Comments by botEmail, the bot's Jira account, never
+// approve.
+func (d DiffPreview) Approved(labels []string, comments []Comment, botEmail string) bool {
+	if slices.Contains(labels, d.ApprovedLabel()) {
+		return true
+	}
+	command := d.ApprovalCommand()
+	approved := false
+	for _, c := range comments {
+		switch {
+		case strings.Contains(c.Body, DiffPreviewMarker):
+			approved = false
+		case botEmail != "" && strings.EqualFold(c.AuthorEmail, botEmail):
+		case strings.EqualFold(strings.TrimSpace(c.Body), command):
+			approved = true
+		}
+	}
+	return approved
+}
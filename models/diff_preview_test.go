@@ -0,0 +1,76 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestDiffPreview_Approved(t *testing.T) {
+	preview := models.DiffPreviewMarker + " Change awaiting approval"
+	tests := []struct {
+		name     string
+		cfg      models.DiffPreview
+		labels   []string
+		comments []string
+		want     bool
+	}{
+		{name: "no approval", cfg: models.DiffPreview{Mode: models.DiffPreviewAlways}, comments: []string{preview, "looks good"}},
+		{name: "approval label", cfg: models.DiffPreview{Mode: models.DiffPreviewAlways}, labels: []string{"ai-approved"}, want: true},
+		{name: "approval comment", cfg: models.DiffPreview{Mode: models.DiffPreviewAlways}, comments: []string{preview, "  /APPROVE\n"}, want: true},
+		{name: "approval before latest preview", cfg: models.DiffPreview{Mode: models.DiffPreviewAlways}, comments: []string{preview, "/approve", preview}},
+		{name: "command within a comment", cfg: models.DiffPreview{Mode: models.DiffPreviewAlways}, comments: []string{preview, "will /approve later"}},
+		{name: "custom command", cfg: models.DiffPreview{Mode: models.DiffPreviewAlways, ApprovalComment: "ship it"}, comments: []string{preview, "Ship it"}, want: true},
+		{name: "custom label", cfg: models.DiffPreview{Mode: models.DiffPreviewAlways, ApprovalLabel: "lgtm"}, labels: []string{"ai-approved"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comments []models.Comment
+			for _, body := range tt.comments {
+				comments = append(comments, models.Comment{Body: body})
+			}
+			if got := tt.cfg.Approved(tt.labels, comments, "bot@example.com"); got != tt.want {
+				t.Errorf("Approved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffPreview_Approved_IgnoresBot(t *testing.T) {
+	cfg := models.DiffPreview{Mode: models.DiffPreviewAlways}
+	comments := []models.Comment{
+		{Body: models.DiffPreviewMarker + " Change awaiting approval", AuthorEmail: "bot@example.com"},
+		{Body: "/approve", AuthorEmail: "Bot@Example.com"},
+	}
+	if cfg.Approved(nil, comments, "bot@example.com") {
+		t.Error("Approved() = true for the bot's own approval command")
+	}
+	comments = append(comments, models.Comment{Body: "/approve", AuthorEmail: "lead@example.com"})
+	if !cfg.Approved(nil, comments, "bot@example.com") {
+		t.Error("Approved() = false for a human's approval command")
+	}
+}
+
+func TestDiffPreview_Awaiting(t *testing.T) {
+	labels := []string{"bug", "ai-awaiting-approval"}
+	if (models.DiffPreview{}).Awaiting(labels) {
+		t.Error("Awaiting() = true with previews disabled")
+	}
+	if !(models.DiffPreview{Mode: models.DiffPreviewLowConfidence}).Awaiting(labels) {
+		t.Error("Awaiting() = false with the default label")
+	}
+	if (models.DiffPreview{Mode: models.DiffPreviewAlways, Label: "preview"}).Awaiting(labels) {
+		t.Error("Awaiting() = true without the custom label")
+	}
+}
+
+func TestDiffPreview_Validate(t *testing.T) {
+	for _, mode := range []string{"", models.DiffPreviewAlways, models.DiffPreviewLowConfidence} {
+		if err := (models.DiffPreview{Mode: mode}).Validate(); err != nil {
+			t.Errorf("Validate() with mode %q: %v", mode, err)
+		}
+	}
+	if err := (models.DiffPreview{Mode: "sometimes"}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown mode")
+	}
+}
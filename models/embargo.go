@@ -0,0 +1,28 @@
+package models
+
+import "slices"
+
+// Embargo keeps the fixes for embargoed issues (e.g., undisclosed
+// CVEs) out of public repositories. While it is enabled, tickets with
+// a security level or carrying Label are worked on in each repo's
+// private mirror (see [RepoEntry.PrivateMirror]) instead of the repo
+// itself: the workspace is cloned from the mirror, the branch is
+// pushed there, and the PR is opened there.
+type Embargo struct {
+	// Enabled turns the private workflow on. Every repo of the
+	// project must then configure a private_mirror.
+	Enabled bool `yaml:"enabled,omitempty" mapstructure:"enabled"`
+
+	// Label marks tickets as embargoed in addition to those with a
+	// security level (e.g., "embargoed"). Empty relies on the
+	// security level alone.
+	Label string `yaml:"label,omitempty" mapstructure:"label"`
+}
+
+// Applies reports whether item is embargoed.
+func (e Embargo) Applies(item WorkItem) bool {
+	if !e.Enabled {
+		return false
+	}
+	return item.HasSecurityLevel() || (e.Label != "" && slices.Contains(item.Labels, e.Label))
+}
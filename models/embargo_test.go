@@ -0,0 +1,29 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestEmbargo_Applies(t *testing.T) {
+	tests := []struct {
+		name    string
+		embargo models.Embargo
+		item    models.WorkItem
+		want    bool
+	}{
+		{name: "disabled", item: models.WorkItem{SecurityLevel: "Embargoed"}},
+		{name: "security level", embargo: models.Embargo{Enabled: true}, item: models.WorkItem{SecurityLevel: "Embargoed"}, want: true},
+		{name: "label", embargo: models.Embargo{Enabled: true, Label: "cve"}, item: models.WorkItem{Labels: []string{"bug", "cve"}}, want: true},
+		{name: "other labels", embargo: models.Embargo{Enabled: true, Label: "cve"}, item: models.WorkItem{Labels: []string{"bug"}}},
+		{name: "no label configured", embargo: models.Embargo{Enabled: true}, item: models.WorkItem{Labels: []string{""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.embargo.Applies(tt.item); got != tt.want {
+				t.Errorf("Applies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FixVersionBranch maps a Jira fix version to the branch its tickets'
+// PRs target, e.g. "4.18.z" to "release-4.18".
+type FixVersionBranch struct {
+	// FixVersion is the fix version name, matched ignoring case.
+	FixVersion string `yaml:"fix_version" mapstructure:"fix_version"`
+
+	// Branch is the base branch of the PRs of tickets with the fix
+	// version.
+	Branch string `yaml:"branch" mapstructure:"branch"`
+}
+
+// FixVersionBranches derives a ticket's target branch from its fix
+// versions. It is a list rather than a map because version names
+// usually contain dots, which the config loader treats as key
+// separators.
+type FixVersionBranches []FixVersionBranch
+
+// Validate checks that every entry names a fix version and a branch,
+// and that no fix version is mapped twice.
+func (m FixVersionBranches) Validate() error {
+	seen := make(map[string]bool, len(m))
+	for i, e := range m {
+		version := strings.ToLower(strings.TrimSpace(e.FixVersion))
+		if version == "" {
+			return fmt.Errorf("[%d].fix_version must not be empty", i)
+		}
+		if strings.TrimSpace(e.Branch) == "" {
+			return fmt.Errorf("[%d].branch must not be empty", i)
+		}
+		if seen[version] {
+			return fmt.Errorf("[%d].fix_version %q is mapped more than once", i, e.FixVersion)
+		}
+		seen[version] = true
+	}
+	return nil
+}
+
+// Branch returns the branch of the first entry matching one of
+// fixVersions, so that when a ticket targets several mapped releases,
+// the order of the entries decides. Returns "" when none matches.
+func (m FixVersionBranches) Branch(fixVersions []string) string {
+	for _, e := range m {
+		matches := slices.ContainsFunc(fixVersions, func(v string) bool {
+			return strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(e.FixVersion))
+		})
+		if matches {
+			return e.Branch
+		}
+	}
+	return ""
+}
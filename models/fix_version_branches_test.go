@@ -0,0 +1,59 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestFixVersionBranches_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       models.FixVersionBranches
+		wantErr bool
+	}{
+		{name: "empty", m: nil},
+		{name: "valid", m: models.FixVersionBranches{{FixVersion: "4.18.z", Branch: "release-4.18"}}},
+		{name: "missing fix version", m: models.FixVersionBranches{{Branch: "release-4.18"}}, wantErr: true},
+		{name: "missing branch", m: models.FixVersionBranches{{FixVersion: "4.18.z"}}, wantErr: true},
+		{
+			name: "fix version mapped twice",
+			m: models.FixVersionBranches{
+				{FixVersion: "4.18.z", Branch: "release-4.18"},
+				{FixVersion: "4.18.Z", Branch: "release-4.18.z"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFixVersionBranches_Branch(t *testing.T) {
+	m := models.FixVersionBranches{
+		{FixVersion: "4.19", Branch: "release-4.19"},
+		{FixVersion: "4.18.z", Branch: "release-4.18"},
+	}
+	tests := []struct {
+		name        string
+		fixVersions []string
+		want        string
+	}{
+		{"no fix versions", nil, ""},
+		{"unmapped", []string{"5.0"}, ""},
+		{"case-insensitive", []string{"4.18.Z"}, "release-4.18"},
+		{"first entry wins", []string{"4.18.z", "4.19"}, "release-4.19"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Branch(tt.fixVersions); got != tt.want {
+				t.Errorf("Branch(%v) = %q, want %q", tt.fixVersions, got, tt.want)
+			}
+		})
+	}
+}
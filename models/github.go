@@ -35,9 +35,15 @@ type GitHubPRComment struct {
 	StartLine   int        `json:"start_line"` // First line of range for multi-line comments (0 if single line)
 	Side        string     `json:"side"`       // Which side of diff: "LEFT" or "RIGHT"
 	StartSide   string     `json:"start_side"` // Which side of diff for start line
-	HTMLURL     string     `json:"html_url"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	// OriginalLine and OriginalStartLine locate the comment in the diff
+	// it was made on. Line is zero once later pushes change the code
+	// (an outdated comment); these keep the original position.
+	OriginalLine      int       `json:"original_line"`
+	OriginalStartLine int       `json:"original_start_line"`
+	DiffHunk          string    `json:"diff_hunk"` // Diff context the comment was made on
+	HTMLURL           string    `json:"html_url"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // GitHub Git Data API structures for creating verified commits
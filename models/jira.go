@@ -49,23 +49,67 @@ type JiraIssue struct {
 
 // JiraFields represents the fields of a Jira issue.
 // Jira Cloud API v3 returns description and comment bodies in Atlassian
-// Document Format (ADF). The ADFText type handles extracting plain text.
+// Document Format (ADF). The ADFText type handles extracting plain text;
+// the description is converted to Markdown by JiraMarkup.
 type JiraFields struct {
 	Summary     string           `json:"summary"`
-	Description ADFText          `json:"description"`
+	Description JiraMarkup       `json:"description"`
 	Status      JiraStatus       `json:"status"`
 	IssueType   JiraIssueType    `json:"issuetype"`
 	Project     JiraProject      `json:"project"`
 	Components  []JiraComponent  `json:"components"`
 	Labels      []string         `json:"labels"`
+	Priority    *JiraPriority    `json:"priority,omitempty"`
 	Created     JiraTime         `json:"created"`
 	Updated     JiraTime         `json:"updated"`
 	Creator     JiraUser         `json:"creator"`
 	Reporter    JiraUser         `json:"reporter"`
 	Assignee    *JiraUser        `json:"assignee,omitempty"`
-	Comment     JiraComments     `json:"comment,omitempty"`
+	Comment     *JiraComments    `json:"comment,omitempty"`
 	Security    *JiraSecurity    `json:"security,omitempty"`
 	Attachment  []JiraAttachment `json:"attachment,omitempty"`
+	IssueLinks  []JiraIssueLink  `json:"issuelinks,omitempty"`
+	Parent      *JiraParent      `json:"parent,omitempty"`
+	FixVersions []JiraVersion    `json:"fixVersions,omitempty"`
+}
+
+// JiraParent is the abbreviated parent issue (e.g., the epic) embedded
+// in an issue's fields.
+type JiraParent struct {
+	Key string `json:"key"`
+}
+
+// JiraIssueLink represents a link between two Jira issues. Exactly one
+// of InwardIssue and OutwardIssue is set: the link reads
+// "<this issue> <Type.Inward> <InwardIssue>" or
+// "<this issue> <Type.Outward> <OutwardIssue>".
+type JiraIssueLink struct {
+	ID           string            `json:"id"`
+	Type         JiraIssueLinkType `json:"type"`
+	InwardIssue  *JiraLinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *JiraLinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+// JiraIssueLinkType describes a link type, e.g. name "Blocks" with
+// inward "is blocked by" and outward "blocks".
+type JiraIssueLinkType struct {
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// JiraLinkedIssue is the abbreviated issue embedded in an issue link.
+type JiraLinkedIssue struct {
+	Key    string                `json:"key"`
+	Fields JiraLinkedIssueFields `json:"fields"`
+}
+
+// JiraLinkedIssueFields holds the subset of fields Jira includes for a
+// linked issue.
+type JiraLinkedIssueFields struct {
+	Summary   string        `json:"summary"`
+	Status    JiraStatus    `json:"status"`
+	IssueType JiraIssueType `json:"issuetype"`
 }
 
 // JiraAttachment represents a file attached to a Jira issue.
@@ -84,9 +128,23 @@ type JiraSecurity struct {
 	Description string `json:"description"`
 }
 
+// JiraPriority represents the priority of a Jira issue
+type JiraPriority struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // JiraStatus represents the status of a Jira issue
 type JiraStatus struct {
-	ID   string `json:"id"`
+	ID             string              `json:"id"`
+	Name           string              `json:"name"`
+	StatusCategory *JiraStatusCategory `json:"statusCategory,omitempty"`
+}
+
+// JiraStatusCategory is the workflow-independent category of a status:
+// "new", "indeterminate", or "done".
+type JiraStatusCategory struct {
+	Key  string `json:"key"`
 	Name string `json:"name"`
 }
 
@@ -108,6 +166,7 @@ type JiraProject struct {
 // JiraUser represents a Jira user
 type JiraUser struct {
 	ID           string `json:"id"`
+	AccountID    string `json:"accountId"`
 	Name         string `json:"name"`
 	DisplayName  string `json:"displayName"`
 	EmailAddress string `json:"emailAddress"`
@@ -165,6 +224,13 @@ type JiraComponent struct {
 	Name string `json:"name"`
 }
 
+// JiraVersion represents a project version, as listed in an issue's
+// fix versions.
+type JiraVersion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // ADFText is a string type that transparently unmarshals from Jira
 // Cloud's Atlassian Document Format (ADF). When the JSON value is a
 // string (API v2 / tests), it stores it directly. When the value is
@@ -199,11 +265,21 @@ func (a *ADFText) UnmarshalJSON(b []byte) error {
 }
 
 // adfNode is the recursive structure of an Atlassian Document Format
-// node. Only the fields needed for text extraction are represented.
+// node. Only the fields needed for text extraction and Markdown
+// conversion are represented.
 type adfNode struct {
-	Type    string    `json:"type"`
-	Text    string    `json:"text,omitempty"`
-	Content []adfNode `json:"content,omitempty"`
+	Type    string         `json:"type"`
+	Text    string         `json:"text,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Marks   []adfMark      `json:"marks,omitempty"`
+	Content []adfNode      `json:"content,omitempty"`
+}
+
+// adfMark is the formatting applied to an ADF text node (e.g.,
+// "strong", "code", or "link" with an href attribute).
+type adfMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
 }
 
 // extractADFText walks an ADF tree and returns the concatenated text
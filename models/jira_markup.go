@@ -0,0 +1,475 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JiraMarkup is a Jira rich-text field converted to Markdown. It
+// unmarshals from Atlassian Document Format (Jira Cloud, API v3) or
+// from a wiki markup string (Jira Data Center), so that code blocks,
+// lists, tables, and panels reach AI prompts and PR descriptions in a
+// form both render.
+type JiraMarkup string
+
+func (m *JiraMarkup) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*m = ""
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*m = JiraMarkup(wikiToMarkdown(s))
+		return nil
+	}
+
+	var doc adfNode
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("unmarshal ADF: %w", err)
+	}
+	*m = JiraMarkup(adfToMarkdown(&doc))
+	return nil
+}
+
+// adfToMarkdown renders an ADF document as Markdown. Media (images
+// and attachments) are dropped; unknown nodes are rendered by their
+// content.
+func adfToMarkdown(doc *adfNode) string {
+	return strings.TrimSpace(renderADFBlocks(doc.Content))
+}
+
+// renderADFBlocks renders block nodes separated by blank lines.
+func renderADFBlocks(nodes []adfNode) string {
+	var blocks []string
+	for i := range nodes {
+		if s := renderADFBlock(&nodes[i]); s != "" {
+			blocks = append(blocks, s)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func renderADFBlock(n *adfNode) string {
+	switch n.Type {
+	case "paragraph":
+		return renderADFInline(n.Content)
+	case "heading":
+		level := min(max(int(adfAttrNumber(n, "level")), 1), 6)
+		return strings.Repeat("#", level) + " " + renderADFInline(n.Content)
+	case "bulletList", "orderedList":
+		return renderADFList(n)
+	case "codeBlock":
+		return markdownFence(adfAttrString(n, "language"), extractADFText(&adfNode{Content: n.Content}))
+	case "blockquote":
+		return markdownQuote(renderADFBlocks(n.Content))
+	case "panel":
+		body := renderADFBlocks(n.Content)
+		if kind := adfAttrString(n, "panelType"); kind != "" {
+			body = "**" + strings.ToUpper(kind[:1]) + kind[1:] + ":** " + body
+		}
+		return markdownQuote(body)
+	case "rule":
+		return "---"
+	case "table":
+		return renderADFTable(n)
+	case "expand", "nestedExpand":
+		body := renderADFBlocks(n.Content)
+		if title := adfAttrString(n, "title"); title != "" {
+			body = "**" + title + "**\n\n" + body
+		}
+		return body
+	case "mediaSingle", "mediaGroup", "media":
+		return ""
+	}
+	if len(n.Content) == 0 {
+		return renderADFInline([]adfNode{*n})
+	}
+	if isADFInline(n.Content[0].Type) {
+		return renderADFInline(n.Content)
+	}
+	return renderADFBlocks(n.Content)
+}
+
+func isADFInline(nodeType string) bool {
+	switch nodeType {
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return true
+	}
+	return false
+}
+
+func renderADFInline(nodes []adfNode) string {
+	var b strings.Builder
+	for i := range nodes {
+		n := &nodes[i]
+		switch n.Type {
+		case "text":
+			b.WriteString(applyADFMarks(n.Text, n.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention", "status":
+			b.WriteString(adfAttrString(n, "text"))
+		case "emoji":
+			if text := adfAttrString(n, "text"); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(adfAttrString(n, "shortName"))
+			}
+		case "inlineCard":
+			b.WriteString(adfAttrString(n, "url"))
+		case "date":
+			// Jira sends the timestamp as a string of milliseconds.
+			if ms, err := strconv.ParseInt(adfAttrString(n, "timestamp"), 10, 64); err == nil {
+				b.WriteString(time.UnixMilli(ms).UTC().Format(time.DateOnly))
+			}
+		default:
+			b.WriteString(extractADFText(n))
+		}
+	}
+	return b.String()
+}
+
+// applyADFMarks wraps text in the Markdown for its marks. Spaces at
+// the edges stay outside the emphasis markers, which Markdown would
+// otherwise not recognize.
+func applyADFMarks(text string, marks []adfMark) string {
+	if len(marks) == 0 || strings.TrimSpace(text) == "" {
+		return text
+	}
+	core := strings.TrimSpace(text)
+	lead := text[:strings.Index(text, core)]
+	trail := text[len(lead)+len(core):]
+
+	var href string
+	for _, mark := range marks {
+		switch mark.Type {
+		case "code":
+			tick := "`"
+			if strings.Contains(core, "`") {
+				tick = "``"
+			}
+			core = tick + core + tick
+		case "strong":
+			core = "**" + core + "**"
+		case "em":
+			core = "_" + core + "_"
+		case "strike":
+			core = "~~" + core + "~~"
+		case "link":
+			href, _ = mark.Attrs["href"].(string)
+		}
+	}
+	if href != "" {
+		core = "[" + core + "](" + href + ")"
+	}
+	return lead + core + trail
+}
+
+// renderADFList renders a bullet or ordered list, indenting nested
+// content under its item.
+func renderADFList(n *adfNode) string {
+	start := 1
+	if order := int(adfAttrNumber(n, "order")); order > 0 {
+		start = order
+	}
+	items := make([]string, 0, len(n.Content))
+	for i := range n.Content {
+		marker := "- "
+		if n.Type == "orderedList" {
+			marker = fmt.Sprintf("%d. ", start+i)
+		}
+		var parts []string
+		for j := range n.Content[i].Content {
+			if s := renderADFBlock(&n.Content[i].Content[j]); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		items = append(items, marker+indentFollowing(strings.Join(parts, "\n"), strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderADFTable renders a table as a Markdown table. Markdown
+// requires a header row, so a table whose first row holds no header
+// cells gets an empty one.
+func renderADFTable(n *adfNode) string {
+	var rows [][]string
+	header := false
+	for i, row := range n.Content {
+		var cells []string
+		for j := range row.Content {
+			cell := &row.Content[j]
+			if i == 0 && cell.Type == "tableHeader" {
+				header = true
+			}
+			cells = append(cells, renderADFBlocks(cell.Content))
+		}
+		rows = append(rows, cells)
+	}
+	return markdownTable(rows, header)
+}
+
+func adfAttrString(n *adfNode, key string) string {
+	s, _ := n.Attrs[key].(string)
+	return s
+}
+
+func adfAttrNumber(n *adfNode, key string) float64 {
+	f, _ := n.Attrs[key].(float64)
+	return f
+}
+
+var (
+	wikiHeading    = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	wikiList       = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+	wikiMacro      = regexp.MustCompile(`^\{(code|noformat|quote|panel|info|note|warning|tip)(?::([^}]*))?\}(.*)$`)
+	wikiMonospace  = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiLink       = regexp.MustCompile(`\[([^\[\]]+)\]`)
+	wikiBold       = regexp.MustCompile(`(^|[^\w*])\*([^\s*](?:[^*]*[^\s*])?)\*($|[^\w*])`)
+	wikiStrike     = regexp.MustCompile(`(^|[^\w-])-([^\s-](?:[^-]*[^\s-])?)-($|[^\w-])`)
+	wikiLinkTarget = regexp.MustCompile(`^(https?|mailto|ftp):`)
+)
+
+// wikiToMarkdown converts Jira wiki markup to Markdown: headings,
+// lists, tables, {code}/{noformat} blocks, quotes, panels, rules,
+// monospace, bold, strikethrough, links, and user mentions. Italics
+// (_text_) are the same in both. Anything else is kept as written.
+func wikiToMarkdown(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if m := wikiMacro.FindStringSubmatch(line); m != nil {
+			body, after, next := wikiMacroBody(lines, i, m[1], m[3])
+			out = append(out, renderWikiMacro(m[1], m[2], body))
+			if after != "" {
+				out = append(out, wikiInline(after))
+			}
+			i = next
+			continue
+		}
+
+		if strings.HasPrefix(line, "|") {
+			var rows [][]string
+			header := strings.HasPrefix(line, "||")
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, wikiTableCells(strings.TrimSpace(lines[i])))
+			}
+			i--
+			out = append(out, markdownTable(rows, header))
+			continue
+		}
+
+		switch m := wikiHeading.FindStringSubmatch(line); {
+		case m != nil:
+			out = append(out, strings.Repeat("#", int(m[1][0]-'0'))+" "+wikiInline(m[2]))
+		case strings.HasPrefix(line, "bq. "):
+			out = append(out, "> "+wikiInline(strings.TrimPrefix(line, "bq. ")))
+		case len(line) >= 4 && strings.Trim(line, "-") == "":
+			out = append(out, "---")
+		default:
+			if m := wikiList.FindStringSubmatch(line); m != nil {
+				out = append(out, wikiListItem(m[1], wikiInline(m[2])))
+			} else {
+				out = append(out, wikiInline(lines[i]))
+			}
+		}
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// wikiMacroBody returns the body of the macro opened on lines[i] with
+// rest following the opening tag, the text after the closing tag on
+// its line, and the index of that line. An unclosed macro extends to
+// the end of the text.
+func wikiMacroBody(lines []string, i int, macro, rest string) (body, after string, end int) {
+	closing := "{" + macro + "}"
+	if before, after, ok := strings.Cut(rest, closing); ok {
+		return before, strings.TrimSpace(after), i
+	}
+	body = rest
+	for j := i + 1; j < len(lines); j++ {
+		if before, after, ok := strings.Cut(lines[j], closing); ok {
+			return body + "\n" + before, strings.TrimSpace(after), j
+		}
+		body += "\n" + lines[j]
+	}
+	return body, "", len(lines) - 1
+}
+
+func renderWikiMacro(macro, params, body string) string {
+	body = strings.Trim(body, "\n")
+	switch macro {
+	case "code", "noformat":
+		var lang string
+		for i, param := range strings.Split(params, "|") {
+			key, value, ok := strings.Cut(param, "=")
+			switch {
+			case ok && (key == "language" || key == "lang"):
+				lang = value
+			case !ok && i == 0 && macro == "code":
+				lang = param
+			}
+		}
+		return markdownFence(strings.TrimSpace(lang), body)
+	case "quote":
+		return markdownQuote(wikiToMarkdown(body))
+	}
+	label := strings.ToUpper(macro[:1]) + macro[1:] + ":"
+	for _, param := range strings.Split(params, "|") {
+		if key, value, ok := strings.Cut(param, "="); ok && key == "title" {
+			label = value
+		}
+	}
+	if macro == "panel" && label == "Panel:" {
+		return markdownQuote(wikiToMarkdown(body))
+	}
+	return markdownQuote("**" + label + "** " + wikiToMarkdown(body))
+}
+
+// wikiListItem renders a list item whose wiki marker (e.g., "*#")
+// gives its nesting and type.
+func wikiListItem(marker, text string) string {
+	var indent strings.Builder
+	for _, c := range marker[:len(marker)-1] {
+		if c == '#' {
+			indent.WriteString("   ")
+		} else {
+			indent.WriteString("  ")
+		}
+	}
+	if marker[len(marker)-1] == '#' {
+		return indent.String() + "1. " + text
+	}
+	return indent.String() + "- " + text
+}
+
+// wikiTableCells splits a wiki table row ("||a||b||" or "|a|b|")
+// into its cells. Inline markup is converted first, so the pipe in a
+// [text|url] link does not split a cell.
+func wikiTableCells(line string) []string {
+	line = wikiInline(line)
+	sep := "|"
+	if strings.HasPrefix(line, "||") {
+		sep = "||"
+	}
+	line = strings.TrimSuffix(strings.TrimPrefix(line, sep), sep)
+	cells := strings.Split(line, sep)
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// wikiInline converts inline wiki markup. Monospace spans are kept
+// verbatim.
+func wikiInline(s string) string {
+	var spans []string
+	s = wikiMonospace.ReplaceAllStringFunc(s, func(m string) string {
+		spans = append(spans, m[2:len(m)-2])
+		return fmt.Sprintf("\x00%d\x00", len(spans)-1)
+	})
+
+	s = wikiLink.ReplaceAllStringFunc(s, func(m string) string {
+		inner := m[1 : len(m)-1]
+		if text, target, ok := strings.Cut(inner, "|"); ok {
+			return "[" + text + "](" + target + ")"
+		}
+		if user, ok := strings.CutPrefix(inner, "~"); ok {
+			return "@" + user
+		}
+		if wikiLinkTarget.MatchString(inner) {
+			return "<" + inner + ">"
+		}
+		return m
+	})
+
+	// Adjacent spans share the character between them, so a second
+	// pass converts those the first one skipped.
+	for range 2 {
+		s = wikiBold.ReplaceAllString(s, "${1}**${2}**${3}")
+		s = wikiStrike.ReplaceAllString(s, "${1}~~${2}~~${3}")
+	}
+
+	for i, span := range spans {
+		s = strings.Replace(s, fmt.Sprintf("\x00%d\x00", i), "`"+span+"`", 1)
+	}
+	return s
+}
+
+// markdownFence returns code as a fenced code block, using a longer
+// fence when the code itself contains one.
+func markdownFence(lang, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + strings.TrimRight(code, "\n") + "\n" + fence
+}
+
+// markdownQuote prefixes each line of s with "> ".
+func markdownQuote(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownTable renders rows as a Markdown table, the first row as
+// its header when header is set and below an empty header otherwise.
+// Line breaks and pipes inside cells are escaped.
+func markdownTable(rows [][]string, header bool) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	if !header {
+		rows = append([][]string{make([]string, cols)}, rows...)
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		b.WriteString("|")
+		for c := range cols {
+			var cell string
+			if c < len(row) {
+				cell = strings.ReplaceAll(row[c], "|", `\|`)
+				cell = strings.ReplaceAll(cell, "\n", "<br>")
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", cols) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// indentFollowing indents every line of s but the first by pad.
+func indentFollowing(s, pad string) string {
+	lines := strings.Split(s, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = pad + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJiraMarkup_ADF(t *testing.T) {
+	input := `{
+		"type": "doc",
+		"version": 1,
+		"content": [
+			{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Steps"}]},
+			{"type": "paragraph", "content": [
+				{"type": "text", "text": "Run "},
+				{"type": "text", "text": "make test", "marks": [{"type": "code"}]},
+				{"type": "text", "text": " and see "},
+				{"type": "text", "text": "the docs ", "marks": [{"type": "strong"}, {"type": "link", "attrs": {"href": "https://example.com"}}]},
+				{"type": "mention", "attrs": {"text": "@alice"}}
+			]},
+			{"type": "orderedList", "content": [
+				{"type": "listItem", "content": [
+					{"type": "paragraph", "content": [{"type": "text", "text": "first"}]},
+					{"type": "bulletList", "content": [
+						{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "nested"}]}]}
+					]}
+				]},
+				{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "second"}]}]}
+			]},
+			{"type": "codeBlock", "attrs": {"language": "go"}, "content": [{"type": "text", "text": "func main() {}\n"}]},
+			{"type": "panel", "attrs": {"panelType": "warning"}, "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "Breaks the API."}]}
+			]},
+			{"type": "table", "content": [
+				{"type": "tableRow", "content": [
+					{"type": "tableHeader", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Input"}]}]},
+					{"type": "tableHeader", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Result"}]}]}
+				]},
+				{"type": "tableRow", "content": [
+					{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "a|b"}]}]},
+					{"type": "tableCell", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "error"}]}]}
+				]}
+			]},
+			{"type": "mediaSingle", "content": [{"type": "media", "attrs": {"id": "1"}}]}
+		]
+	}`
+
+	var m JiraMarkup
+	if err := json.Unmarshal([]byte(input), &m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "## Steps\n\n" +
+		"Run `make test` and see [**the docs**](https://example.com) @alice\n\n" +
+		"1. first\n   - nested\n2. second\n\n" +
+		"```go\nfunc main() {}\n```\n\n" +
+		"> **Warning:** Breaks the API.\n\n" +
+		"| Input | Result |\n| --- | --- |\n| a\\|b | error |"
+	if string(m) != want {
+		t.Errorf("got:\n%s\n\nwant:\n%s", m, want)
+	}
+}
+
+func TestJiraMarkup_Wiki(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain text", input: "The export fails.\nIt worked in 4.16.", want: "The export fails.\nIt worked in 4.16."},
+		{name: "heading", input: "h2. Steps to reproduce", want: "## Steps to reproduce"},
+		{name: "nested lists", input: "* one\n** nested\n# first\n#* mixed", want: "- one\n  - nested\n1. first\n   - mixed"},
+		{name: "code block", input: "{code:java}\nint x = 1;\n*not bold*\n{code}", want: "```java\nint x = 1;\n*not bold*\n```"},
+		{name: "code with title", input: "{code:title=Main.go|language=go}fmt.Println(){code}", want: "```go\nfmt.Println()\n```"},
+		{name: "noformat", input: "{noformat}\n$ make\n{noformat}", want: "```\n$ make\n```"},
+		{name: "quote", input: "{quote}\nh3. Quoted\n{quote}", want: "> ### Quoted"},
+		{name: "info panel", input: "{info}\nRestart required.\n{info}", want: "> **Info:** Restart required."},
+		{name: "titled panel", input: "{panel:title=Workaround}\nUse the CLI.\n{panel}", want: "> **Workaround** Use the CLI."},
+		{name: "table", input: "||Name||Value||\n|timeout|[docs|https://example.com]|", want: "| Name | Value |\n| --- | --- |\n| timeout | [docs](https://example.com) |"},
+		{name: "inline", input: "Set {{*flag*}} to *true*, not -false-; see [https://example.com] and ask [~bob].", want: "Set `*flag*` to **true**, not ~~false~~; see <https://example.com> and ask @bob."},
+		{name: "words with dashes and asterisks", input: "A well-known 2x*3 bug in 2024-01-05", want: "A well-known 2x*3 bug in 2024-01-05"},
+		{name: "rule", input: "above\n----\nbelow", want: "above\n---\nbelow"},
+		{name: "unclosed code", input: "{code}\nx := 1", want: "```\nx := 1\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var m JiraMarkup
+			if err := json.Unmarshal(data, &m); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(m) != tt.want {
+				t.Errorf("got:\n%s\n\nwant:\n%s", m, tt.want)
+			}
+		})
+	}
+}
@@ -13,6 +13,15 @@ type PRDetails struct {
 	URL        string
 	HeadSHA    string
 	CreatedAt  time.Time
+
+	// UpdatedAt is when the PR last changed: new comments, reviews,
+	// pushes, and label or title edits all update it. CI check runs
+	// do not.
+	UpdatedAt time.Time
+
+	// MergeCommitSHA is the commit the PR was merged as (a merge,
+	// squash, or the last rebased commit). Set only for merged PRs.
+	MergeCommitSHA string
 }
 
 // PRComment represents a single comment on a pull request.
@@ -23,10 +32,15 @@ type PRComment struct {
 	Body            string
 	FilePath        string // Empty for general (non-file-specific) comments.
 	Line            int    // Zero for general comments.
+	StartLine       int    // First line of a multi-line comment; zero for single-line comments.
+	Side            string // Diff side of Line: "RIGHT" (new code) or "LEFT" (removed code).
+	Outdated        bool   // The code changed after the comment; Line is its original position.
+	DiffHunk        string // Diff context the review comment was made on.
 	URL             string // HTML URL for linking back to the comment.
 	Timestamp       time.Time
 	InReplyTo       int64 // Zero if this is not a reply to another comment.
 	IsReviewComment bool  // True for file-level review comments, false for conversation comments.
+	Approval        bool  // True for the body of a review that approved the PR (e.g. "LGTM").
 }
 
 // PRParams contains the parameters for creating a new pull request.
@@ -51,6 +65,14 @@ type PRUpdateParams struct {
 	Draft *bool
 }
 
+// DiffStat summarizes the size of a branch's changes against its base
+// branch.
+type DiffStat struct {
+	FilesChanged int
+	Insertions   int
+	Deletions    int
+}
+
 // PR represents a created or existing pull request.
 type PR struct {
 	Number int
@@ -1,5 +1,7 @@
 package models
 
+import "slices"
+
 // RepoSettings carries per-repo profile data alongside the repo
 // coordinates. The executor uses these as fallbacks when .ai-bot/
 // files don't exist in the repo.
@@ -20,6 +22,9 @@ type RepoSettings struct {
 	// Container holds per-repo container settings from the profile.
 	Container ContainerSettings
 
+	// Setup holds the profile's dependency setup command.
+	Setup SetupConfig
+
 	// Imports declares auxiliary repositories from the profile to
 	// clone into the workspace before AI execution. Merged with
 	// repo-level imports from .ai-bot/config.yaml.
@@ -41,8 +46,18 @@ type RepoSettings struct {
 	FeedbackWorkflow string
 
 	// BaseBranch is the target branch for pull requests (e.g.,
-	// "main", "master"). Defaults to "main".
+	// "main", "master"). Defaults to the repository's default branch.
 	BaseBranch string
+
+	// Subdirectory is the slash-separated directory of a monorepo that
+	// the AI is confined to (e.g., "apps/web"). Empty means the whole
+	// repository.
+	Subdirectory string
+
+	// SparsePaths lists the directories checked out in the
+	// repository's workspace when only part of a monorepo is checked
+	// out. Empty checks out the whole repository.
+	SparsePaths []string
 }
 
 // ProjectSettings contains the resolved per-project settings needed
@@ -61,6 +76,18 @@ type ProjectSettings struct {
 	// scaffold is never branched, committed to, or PR'd.
 	RootRepoURL string
 
+	// CompanionRepos are cloned read-only into the workspace for
+	// context. See [ProjectConfig.CompanionRepos].
+	CompanionRepos []CompanionRepo
+
+	// MergeOrder lists repository names in the order their pull
+	// requests must be merged. See [WorkspaceConfig.MergeOrder].
+	MergeOrder []string
+
+	// DoNotMergeLabel is added to pull requests waiting for earlier
+	// ones in MergeOrder. See [WorkspaceConfig.DoNotMergeLabel].
+	DoNotMergeLabel string
+
 	// InProgressStatus is the tracker status name for "in progress".
 	InProgressStatus string
 
@@ -74,6 +101,14 @@ type ProjectSettings struct {
 	// Empty means PR URL is posted as a structured comment instead.
 	PRURLFieldName string
 
+	// AssessmentFieldName is the custom field that receives the
+	// bot's assessment of new PRs. Empty disables the assessment.
+	AssessmentFieldName string
+
+	// AcceptanceCriteriaFieldName is the custom field holding a
+	// ticket's acceptance criteria. Empty disables reading it.
+	AcceptanceCriteriaFieldName string
+
 	// DisableErrorComments prevents posting error details as tracker
 	// comments on job failure. Errors are still logged.
 	DisableErrorComments bool
@@ -103,6 +138,81 @@ type ProjectSettings struct {
 	// non-zero code. At most one is set on a PR at any time.
 	PRValidationLabels PRValidationLabels
 
+	// NeedsHumanStatus is the tracker status name to transition to
+	// when the bot escalates a ticket to a human (the AI produced no
+	// changes or retries are exhausted). Empty means revert to
+	// TodoStatus as for other failures.
+	NeedsHumanStatus string
+
+	// WaitingForInfoStatus is the tracker status name to transition to
+	// when the AI asks clarifying questions instead of changing
+	// anything. Empty means such tickets are escalated instead. See
+	// [StatusTransitions.WaitingForInfo].
+	WaitingForInfoStatus string
+
+	// BatchLabel groups related tickets into one AI session and PR.
+	// See [ProjectConfig.BatchLabel]. Empty disables batching.
+	BatchLabel string
+
+	// ActiveSprintOnly and FixVersions are the project's new-ticket
+	// discovery filters. See [ProjectConfig.ActiveSprintOnly] and
+	// [ProjectConfig.FixVersions].
+	ActiveSprintOnly bool
+	FixVersions      []string
+
+	// EscalateLowConfidence escalates tickets whose new PR is rated
+	// low confidence. See [ProjectConfig.EscalateLowConfidence].
+	EscalateLowConfidence bool
+
+	// SelfReview runs an AI review of new-ticket changes before
+	// committing. See [ProjectConfig.SelfReview].
+	SelfReview bool
+
+	// AutoMerge enables auto-merge on new bot PRs. See
+	// [ProjectConfig.AutoMerge].
+	AutoMerge AutoMerge
+
+	// CheckRun publishes an AI summary check run on the commits of
+	// the bot's PRs. See [ProjectConfig.CheckRun].
+	CheckRun bool
+
+	// BotAssignee is the account ID the ticket is assigned to while a
+	// new-ticket job works on it, or empty when the project does not
+	// set assign_bot. See [ProjectConfig.AssignBot].
+	BotAssignee string
+
+	// MinConfidence is the AI-reported confidence below which a
+	// new-ticket change is posted to the ticket instead of opened as
+	// a PR. See [ProjectConfig.MinConfidence]. Zero disables the gate.
+	MinConfidence int
+
+	// DiffPreview posts new-ticket changes to the ticket for approval
+	// before opening their PR. See [ProjectConfig.DiffPreview].
+	DiffPreview DiffPreview
+
+	// RegressionTests selects how Bug ticket changes without test
+	// changes are handled. See [ProjectConfig.RegressionTests]. Empty
+	// disables the check.
+	RegressionTests string
+
+	// FeedbackSplitThreshold is the number of new comments from which
+	// a feedback round is addressed in one AI session per file. See
+	// [ProjectConfig.FeedbackSplitThreshold]. Zero disables splitting.
+	FeedbackSplitThreshold int
+
+	// HumanPushPolicy selects how a feedback round handles commits
+	// pushed to the PR branch during the AI session. See
+	// [ProjectConfig.HumanPushPolicy]. Empty means rebase.
+	HumanPushPolicy string
+
+	// SecurityScans are run on new-ticket changes before committing.
+	// See [ProjectConfig.SecurityScans].
+	SecurityScans []SecurityScan
+
+	// DependencyPolicy restricts the dependencies new-ticket changes
+	// may add. See [ProjectConfig.DependencyPolicy].
+	DependencyPolicy DependencyPolicy
+
 	// MergedStatus is the tracker status name to transition to when
 	// all PRs are merged. Empty means no transition on merge.
 	MergedStatus string
@@ -112,6 +222,11 @@ type ProjectSettings struct {
 	// fork and creates cross-repo PRs.
 	ForkMode bool
 
+	// Embargoed reports that the ticket is embargoed: Repos point at
+	// the private mirrors and fork mode is off. See
+	// [ProjectConfig.Embargo].
+	Embargoed bool
+
 	// GitHubUsername is the GitHub username of the ticket assignee,
 	// resolved from the assignee-to-GitHub-username config mapping.
 	// Empty when the assignee has no mapping or the ticket is
@@ -122,6 +237,46 @@ type ProjectSettings struct {
 	// sessions are started for a ticket once its cumulative cost
 	// reaches or exceeds this value. Zero or negative means no cap.
 	MaxTicketCostUSD float64
+
+	// MaxOpenPRsPerRepo caps the number of open bot PRs per target
+	// repository. New tickets are deferred while any of the
+	// workspace's repositories is at the limit. Zero means no limit.
+	MaxOpenPRsPerRepo int
+
+	// Budget caps the AI spend of the project's tickets, counted
+	// over BudgetProjects. See [ProjectConfig.Budget].
+	Budget CostBudget
+
+	// BudgetProjects lists the Jira project keys whose spend counts
+	// toward Budget.
+	BudgetProjects []string
+
+	// CommitMessage renders the subject of new-ticket commits.
+	CommitMessage CommitMessage
+
+	// PromptStrategy is the project's prompt strategy for the
+	// ticket's type. See [ProjectConfig.PromptStrategies].
+	PromptStrategy PromptStrategy
+
+	// Backport maps the ticket's backport labels to release branches.
+	// See [ProjectConfig.Backport].
+	Backport Backport
+
+	// ReleaseNotes configures release notes for the ticket once its
+	// PR is merged. See [ProjectConfig.ReleaseNotes].
+	ReleaseNotes ReleaseNotes
+
+	// Triage configures triage mode for the ticket's project. See
+	// [ProjectConfig.Triage].
+	Triage Triage
+
+	// Spike configures spike mode for the ticket's project. See
+	// [ProjectConfig.Spike].
+	Spike Spike
+
+	// Components lists the names of the project's component mappings,
+	// sorted. Triage proposes one of them.
+	Components []string
 }
 
 // IsMultiRepo returns true when the workspace contains more than
@@ -130,6 +285,23 @@ func (s *ProjectSettings) IsMultiRepo() bool {
 	return len(s.Repos) > 1
 }
 
+// MergeRank returns the position of the named repository in
+// MergeOrder, or -1 when it is not listed.
+func (s *ProjectSettings) MergeRank(repoName string) int {
+	return slices.Index(s.MergeOrder, repoName)
+}
+
+// HasSubdirectories reports whether any repository confines the AI
+// to a subdirectory.
+func (s *ProjectSettings) HasSubdirectories() bool {
+	for _, repo := range s.Repos {
+		if repo.Subdirectory != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // ResolvedContainer returns the effective container settings.
 // Workspace-level container takes precedence; falls back to the
 // first repo's profile container for single-repo workspaces.
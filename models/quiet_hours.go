@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours defines a recurring window during which the bot does not
+// pick up new tickets for a project. The window may cross midnight
+// (e.g., 20:00–08:00); in that case Days refers to the day the window
+// starts.
+type QuietHours struct {
+	// Start is the window start time in 24-hour "HH:MM" format.
+	Start string `yaml:"start" mapstructure:"start"`
+
+	// End is the window end time in 24-hour "HH:MM" format
+	// (exclusive).
+	End string `yaml:"end" mapstructure:"end"`
+
+	// Days restricts the window to the given weekdays, using
+	// cron-style abbreviations ("mon", "tue", ..., "sun"). Empty
+	// means every day.
+	Days []string `yaml:"days" mapstructure:"days"`
+
+	// Timezone is the IANA time zone the window is evaluated in
+	// (e.g., "Europe/Berlin"). Empty means UTC.
+	Timezone string `yaml:"timezone" mapstructure:"timezone"`
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Validate checks that the window times, days, and time zone parse.
+func (q QuietHours) Validate() error {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if start == end {
+		return errors.New("start and end must differ")
+	}
+	for _, d := range q.Days {
+		if _, ok := weekdayNames[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("days: unknown weekday %q (use mon, tue, wed, thu, fri, sat, sun)", d)
+		}
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("timezone: %w", err)
+	}
+	return nil
+}
+
+// Active reports whether t falls inside the quiet window. Returns
+// false if the window is invalid.
+func (q QuietHours) Active(t time.Time) bool {
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return false
+	}
+
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return minute >= start && minute < end && q.onDay(local.Weekday())
+	}
+	// The window wraps past midnight: the evening part belongs to
+	// today, the morning part to the window that started yesterday.
+	if minute >= start {
+		return q.onDay(local.Weekday())
+	}
+	if minute < end {
+		return q.onDay((local.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+func (q QuietHours) onDay(day time.Weekday) bool {
+	if len(q.Days) == 0 {
+		return true
+	}
+	for _, d := range q.Days {
+		if wd, ok := weekdayNames[strings.ToLower(d)]; ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
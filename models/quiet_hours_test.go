@@ -0,0 +1,63 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestQuietHours_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       models.QuietHours
+		wantErr bool
+	}{
+		{name: "valid same-day window", q: models.QuietHours{Start: "09:00", End: "17:00"}},
+		{name: "valid overnight window with days and zone", q: models.QuietHours{Start: "20:00", End: "08:00", Days: []string{"Mon", "fri"}, Timezone: "Europe/Berlin"}},
+		{name: "bad start", q: models.QuietHours{Start: "9am", End: "17:00"}, wantErr: true},
+		{name: "missing end", q: models.QuietHours{Start: "09:00"}, wantErr: true},
+		{name: "empty window", q: models.QuietHours{Start: "09:00", End: "09:00"}, wantErr: true},
+		{name: "unknown day", q: models.QuietHours{Start: "09:00", End: "17:00", Days: []string{"funday"}}, wantErr: true},
+		{name: "unknown timezone", q: models.QuietHours{Start: "09:00", End: "17:00", Timezone: "Mars/Olympus"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.q.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Active(t *testing.T) {
+	// 2025-01-06 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		q    models.QuietHours
+		t    time.Time
+		want bool
+	}{
+		{name: "inside same-day window", q: models.QuietHours{Start: "09:00", End: "17:00"}, t: at(6, 12, 0), want: true},
+		{name: "end is exclusive", q: models.QuietHours{Start: "09:00", End: "17:00"}, t: at(6, 17, 0), want: false},
+		{name: "overnight evening part", q: models.QuietHours{Start: "20:00", End: "08:00"}, t: at(6, 23, 0), want: true},
+		{name: "overnight morning part", q: models.QuietHours{Start: "20:00", End: "08:00"}, t: at(7, 3, 0), want: true},
+		{name: "overnight outside", q: models.QuietHours{Start: "20:00", End: "08:00"}, t: at(7, 12, 0), want: false},
+		{name: "day filter excludes", q: models.QuietHours{Start: "09:00", End: "17:00", Days: []string{"tue"}}, t: at(6, 12, 0), want: false},
+		{name: "overnight morning belongs to start day", q: models.QuietHours{Start: "20:00", End: "08:00", Days: []string{"mon"}}, t: at(7, 3, 0), want: true},
+		{name: "overnight morning of excluded start day", q: models.QuietHours{Start: "20:00", End: "08:00", Days: []string{"mon"}}, t: at(6, 3, 0), want: false},
+		{name: "timezone applied", q: models.QuietHours{Start: "00:00", End: "06:00", Timezone: "America/New_York"}, t: at(6, 8, 0), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Active(tt.t); got != tt.want {
+				t.Errorf("Active(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
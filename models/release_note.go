@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// defaultReleaseNoteDoneLabel is used when ReleaseNotes.DoneLabel is
+// empty.
+const defaultReleaseNoteDoneLabel = "ai-release-noted"
+
+// ReleaseNotes configures release-note snippets for merged tickets.
+// Once a ticket's PR is merged, the AI writes a short user-facing note
+// from the ticket and the merged change; the note goes to Field, to
+// ChangelogFile through a follow-up PR, or both. Release notes apply
+// to single-repository workspaces.
+type ReleaseNotes struct {
+	// Field names the Jira field the note is written to, e.g.
+	// "Release Note Text".
+	Field string `yaml:"field,omitempty" mapstructure:"field"`
+
+	// ChangelogFile is the repository path of a changelog the AI adds
+	// the note to, following the file's format, e.g. "CHANGELOG.md".
+	// The change is opened as a follow-up PR against the merged PR's
+	// base branch.
+	ChangelogFile string `yaml:"changelog_file,omitempty" mapstructure:"changelog_file"`
+
+	// DoneLabel marks tickets whose release note was written. Empty
+	// means "ai-release-noted".
+	DoneLabel string `yaml:"done_label,omitempty" mapstructure:"done_label"`
+}
+
+// IsEnabled reports whether release notes are configured.
+func (r ReleaseNotes) IsEnabled() bool {
+	return r.Field != "" || r.ChangelogFile != ""
+}
+
+// Validate checks that the settings are not blank and that the
+// changelog path stays inside the repository.
+func (r ReleaseNotes) Validate() error {
+	if r.Field != "" && strings.TrimSpace(r.Field) == "" {
+		return errors.New("field must not be blank")
+	}
+	if r.DoneLabel != "" && strings.TrimSpace(r.DoneLabel) == "" {
+		return errors.New("done_label must not be blank")
+	}
+	if r.ChangelogFile != "" && !filepath.IsLocal(r.ChangelogFile) {
+		return fmt.Errorf("changelog_file must be a relative path inside the repository (got %q)", r.ChangelogFile)
+	}
+	return nil
+}
+
+// NotedLabel returns the label marking tickets whose release note was
+// written.
+func (r ReleaseNotes) NotedLabel() string {
+	if r.DoneLabel != "" {
+		return r.DoneLabel
+	}
+	return defaultReleaseNoteDoneLabel
+}
+
+// Pending reports whether a ticket with labels still needs its
+// release note. Always false when release notes are disabled.
+func (r ReleaseNotes) Pending(labels []string) bool {
+	return r.IsEnabled() && !slices.Contains(labels, r.NotedLabel())
+}
+
+// ReleaseNoteBranchName returns the bot's branch for the follow-up PR
+// adding a ticket's release note to the changelog:
+// "{bot}/{ticket}-release-note".
+func ReleaseNoteBranchName(botUsername, ticketKey string) string {
+	return fmt.Sprintf("%s/%s-release-note", botUsername, ticketKey)
+}
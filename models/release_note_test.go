@@ -0,0 +1,47 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestReleaseNotes_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.ReleaseNotes
+		wantErr bool
+	}{
+		{name: "disabled", cfg: models.ReleaseNotes{}},
+		{name: "field", cfg: models.ReleaseNotes{Field: "Release Note Text"}},
+		{name: "changelog", cfg: models.ReleaseNotes{ChangelogFile: "docs/CHANGELOG.md"}},
+		{name: "blank field", cfg: models.ReleaseNotes{Field: " "}, wantErr: true},
+		{name: "blank done label", cfg: models.ReleaseNotes{Field: "Notes", DoneLabel: " "}, wantErr: true},
+		{name: "absolute changelog", cfg: models.ReleaseNotes{ChangelogFile: "/etc/CHANGELOG.md"}, wantErr: true},
+		{name: "changelog outside repo", cfg: models.ReleaseNotes{ChangelogFile: "../CHANGELOG.md"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReleaseNotes_Pending(t *testing.T) {
+	if (models.ReleaseNotes{}).Pending(nil) {
+		t.Error("Pending() = true with release notes disabled")
+	}
+	rn := models.ReleaseNotes{Field: "Release Note Text"}
+	if !rn.Pending([]string{"bug"}) {
+		t.Error("Pending() = false without the done label")
+	}
+	if rn.Pending([]string{"ai-release-noted"}) {
+		t.Error("Pending() = true with the default done label")
+	}
+	rn.DoneLabel = "noted"
+	if rn.Pending([]string{"noted"}) {
+		t.Error("Pending() = true with the configured done label")
+	}
+}
@@ -12,6 +12,9 @@ type SearchCriteria struct {
 	// ProjectKeys limits results to work items in the specified projects.
 	ProjectKeys []string
 
+	// Keys limits results to the work items with the given keys.
+	Keys []string
+
 	// StatusByType maps work item types to acceptable statuses. This
 	// supports trackers where different types have different workflow
 	// statuses (e.g., Bug "todo" is "Open" while Story "todo" is "To Do").
@@ -38,6 +41,20 @@ type SearchCriteria struct {
 	// Labels filters by applied labels. Multiple labels are OR'd.
 	Labels []string
 
+	// ExcludeLabels omits work items carrying any of the labels.
+	ExcludeLabels []string
+
+	// Parent restricts results to children of the given work item
+	// (e.g., the stories of an epic).
+	Parent string
+
+	// ActiveSprint restricts results to work items in a currently open
+	// sprint. In Jira this maps to "sprint IN openSprints()".
+	ActiveSprint bool
+
+	// FixVersions filters by target release. Multiple versions are OR'd.
+	FixVersions []string
+
 	// OrderBy specifies the sort order (e.g., "updated DESC").
 	OrderBy string
 }
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Defaults used when the corresponding Spike field is empty.
+const (
+	defaultSpikeDoneLabel      = "ai-spike-done"
+	defaultSpikeTimeoutMinutes = 15
+)
+
+// Spike configures spike mode: instead of solving a ticket labeled
+// with Label, the bot gives the AI a short, time-boxed session to
+// explore the ticket's feasibility and posts the AI's write-up to the
+// ticket. No code is changed and no PR is opened, so a team can gauge
+// a ticket before spending a full AI session on it.
+type Spike struct {
+	// Label is the Jira label requesting a spike, e.g. "ai-spike".
+	// Empty disables spikes.
+	Label string `yaml:"label,omitempty" mapstructure:"label"`
+
+	// DoneLabel replaces Label once the write-up is posted. Empty
+	// means "ai-spike-done".
+	DoneLabel string `yaml:"done_label,omitempty" mapstructure:"done_label"`
+
+	// TimeoutMinutes caps the spike's AI session. It never extends
+	// guardrails.max_container_runtime_minutes. Zero means 15.
+	TimeoutMinutes int `yaml:"timeout_minutes,omitempty" mapstructure:"timeout_minutes"`
+}
+
+// IsEnabled reports whether spikes are configured.
+func (s Spike) IsEnabled() bool {
+	return s.Label != ""
+}
+
+// Validate checks that the labels are not blank and differ, and that
+// the timeout is not negative.
+func (s Spike) Validate() error {
+	if s.Label != "" && strings.TrimSpace(s.Label) == "" {
+		return errors.New("label must not be blank")
+	}
+	if s.DoneLabel != "" && strings.TrimSpace(s.DoneLabel) == "" {
+		return errors.New("done_label must not be blank")
+	}
+	if s.TimeoutMinutes < 0 {
+		return errors.New("timeout_minutes must not be negative")
+	}
+	if s.IsEnabled() && s.FinishedLabel() == s.Label {
+		return errors.New("done_label must differ from label")
+	}
+	return nil
+}
+
+// FinishedLabel returns the label marking a ticket whose spike
+// write-up was posted.
+func (s Spike) FinishedLabel() string {
+	if s.DoneLabel != "" {
+		return s.DoneLabel
+	}
+	return defaultSpikeDoneLabel
+}
+
+// Timeout returns the time box of the spike's AI session.
+func (s Spike) Timeout() time.Duration {
+	if s.TimeoutMinutes > 0 {
+		return time.Duration(s.TimeoutMinutes) * time.Minute
+	}
+	return defaultSpikeTimeoutMinutes * time.Minute
+}
+
+// Requested reports whether labels request a spike. Always false when
+// spikes are disabled.
+func (s Spike) Requested(labels []string) bool {
+	return s.IsEnabled() && slices.Contains(labels, s.Label)
+}
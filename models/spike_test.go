@@ -0,0 +1,59 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestSpike_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.Spike
+		wantErr bool
+	}{
+		{name: "disabled", cfg: models.Spike{}},
+		{name: "enabled", cfg: models.Spike{Label: "ai-spike", TimeoutMinutes: 10}},
+		{name: "blank label", cfg: models.Spike{Label: " "}, wantErr: true},
+		{name: "blank done label", cfg: models.Spike{Label: "ai-spike", DoneLabel: " "}, wantErr: true},
+		{name: "negative timeout", cfg: models.Spike{Label: "ai-spike", TimeoutMinutes: -1}, wantErr: true},
+		{name: "done label same as label", cfg: models.Spike{Label: "spike", DoneLabel: "spike"}, wantErr: true},
+		{name: "default done label as label", cfg: models.Spike{Label: "ai-spike-done"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpike_Defaults(t *testing.T) {
+	s := models.Spike{Label: "ai-spike"}
+	if got := s.FinishedLabel(); got != "ai-spike-done" {
+		t.Errorf("FinishedLabel() = %q, want ai-spike-done", got)
+	}
+	if got := s.Timeout(); got != 15*time.Minute {
+		t.Errorf("Timeout() = %v, want 15m", got)
+	}
+
+	s.TimeoutMinutes = 5
+	if got := s.Timeout(); got != 5*time.Minute {
+		t.Errorf("Timeout() = %v, want 5m", got)
+	}
+}
+
+func TestSpike_Requested(t *testing.T) {
+	labels := []string{"bug", "ai-spike"}
+	if (models.Spike{}).Requested(labels) {
+		t.Error("Requested() = true with spikes disabled")
+	}
+	if !(models.Spike{Label: "ai-spike"}).Requested(labels) {
+		t.Error("Requested() = false with the spike label")
+	}
+	if (models.Spike{Label: "spike-me"}).Requested(labels) {
+		t.Error("Requested() = true without the spike label")
+	}
+}
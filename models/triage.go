@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// defaultTriagedLabel is used when Triage.DoneLabel is empty.
+const defaultTriagedLabel = "ai-triaged"
+
+// Triage configures triage mode: instead of solving a ticket labeled
+// with Label, the bot has the AI propose its component, severity,
+// possible duplicates, and a rough effort estimate, and writes the
+// proposals into the configured Jira fields.
+type Triage struct {
+	// Label is the Jira label requesting triage, e.g. "ai-triage".
+	// Empty disables triage.
+	Label string `yaml:"label,omitempty" mapstructure:"label"`
+
+	// DoneLabel replaces Label once the ticket is triaged. Empty
+	// means "ai-triaged".
+	DoneLabel string `yaml:"done_label,omitempty" mapstructure:"done_label"`
+
+	// Severities lists the severity values the AI may choose from,
+	// e.g. ["Critical", "Major", "Minor"]. Empty lets the AI choose
+	// freely.
+	Severities []string `yaml:"severities,omitempty" mapstructure:"severities"`
+
+	// Fields names the Jira fields the proposals are written to.
+	Fields TriageFields `yaml:"fields,omitempty" mapstructure:"fields"`
+}
+
+// TriageFields names the Jira fields that receive the AI's triage
+// proposals. Proposals whose field is empty are only mentioned in the
+// triage comment.
+type TriageFields struct {
+	// Component receives the proposed component.
+	Component string `yaml:"component,omitempty" mapstructure:"component"`
+
+	// Severity receives the proposed severity.
+	Severity string `yaml:"severity,omitempty" mapstructure:"severity"`
+
+	// Duplicates receives the keys of likely duplicate tickets.
+	Duplicates string `yaml:"duplicates,omitempty" mapstructure:"duplicates"`
+
+	// Estimate receives the rough effort estimate.
+	Estimate string `yaml:"estimate,omitempty" mapstructure:"estimate"`
+}
+
+// IsEnabled reports whether triage is configured.
+func (t Triage) IsEnabled() bool {
+	return t.Label != ""
+}
+
+// Validate checks that the labels are not blank and that an enabled
+// triage writes to at least one field.
+func (t Triage) Validate() error {
+	if t.Label != "" && strings.TrimSpace(t.Label) == "" {
+		return errors.New("label must not be blank")
+	}
+	if t.DoneLabel != "" && strings.TrimSpace(t.DoneLabel) == "" {
+		return errors.New("done_label must not be blank")
+	}
+	if !t.IsEnabled() {
+		return nil
+	}
+	if t.TriagedLabel() == t.Label {
+		return errors.New("done_label must differ from label")
+	}
+	f := t.Fields
+	if f.Component == "" && f.Severity == "" && f.Duplicates == "" && f.Estimate == "" {
+		return errors.New("fields must name at least one field")
+	}
+	return nil
+}
+
+// TriagedLabel returns the label marking a triaged ticket.
+func (t Triage) TriagedLabel() string {
+	if t.DoneLabel != "" {
+		return t.DoneLabel
+	}
+	return defaultTriagedLabel
+}
+
+// Requested reports whether labels request triage. Always false when
+// triage is disabled.
+func (t Triage) Requested(labels []string) bool {
+	return t.IsEnabled() && slices.Contains(labels, t.Label)
+}
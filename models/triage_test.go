@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestTriage_Validate(t *testing.T) {
+	fields := models.TriageFields{Severity: "AI Severity"}
+	tests := []struct {
+		name    string
+		cfg     models.Triage
+		wantErr bool
+	}{
+		{name: "disabled", cfg: models.Triage{}},
+		{name: "enabled", cfg: models.Triage{Label: "ai-triage", Fields: fields}},
+		{name: "blank label", cfg: models.Triage{Label: " ", Fields: fields}, wantErr: true},
+		{name: "no fields", cfg: models.Triage{Label: "ai-triage"}, wantErr: true},
+		{name: "done label same as label", cfg: models.Triage{Label: "triage", DoneLabel: "triage", Fields: fields}, wantErr: true},
+		{name: "default done label as label", cfg: models.Triage{Label: "ai-triaged", Fields: fields}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTriage_Requested(t *testing.T) {
+	labels := []string{"bug", "ai-triage"}
+	if (models.Triage{}).Requested(labels) {
+		t.Error("Requested() = true with triage disabled")
+	}
+	if !(models.Triage{Label: "ai-triage"}).Requested(labels) {
+		t.Error("Requested() = false with the triage label")
+	}
+	if (models.Triage{Label: "triage-me"}).Requested(labels) {
+		t.Error("Requested() = true without the triage label")
+	}
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"strings"
+	"time"
+)
+
 // WorkItem is the tracker-agnostic representation of a unit of work.
 // It captures the fields the system needs to process a ticket regardless
 // of whether the source is Jira, GitHub Issues, or another tracker.
@@ -16,6 +21,18 @@ type WorkItem struct {
 	// Description is the full description.
 	Description string
 
+	// AcceptanceCriteria is the content of the project's acceptance
+	// criteria field, or empty when the project has none configured
+	// or the field is unset. Not populated by the tracker; the
+	// executor fills it in.
+	AcceptanceCriteria string
+
+	// TypeGuidance is the project's guidance for tickets of this
+	// type (see [ProjectSettings.PromptStrategy]), or empty for the
+	// built-in guidance. Not populated by the tracker; the executor
+	// fills it in.
+	TypeGuidance string
+
 	// Type is the work item category (e.g., "Bug", "Story", "Task").
 	Type string
 
@@ -33,6 +50,18 @@ type WorkItem struct {
 	// Always non-nil; empty slice when no labels are set.
 	Labels []string
 
+	// FixVersions lists the names of the releases the work item is
+	// targeted at (Jira's fixVersion field). Always non-nil; empty
+	// slice when no versions are set.
+	FixVersions []string
+
+	// Priority is the tracker's priority name (e.g., "High"), or
+	// empty if the ticket has no priority.
+	Priority string
+
+	// Created is when the work item was created. Zero if unknown.
+	Created time.Time
+
 	// Assignee is the person assigned, or nil if unassigned.
 	Assignee *Author
 
@@ -43,6 +72,55 @@ type WorkItem struct {
 	// Attachments lists files attached to the work item.
 	// Always non-nil; empty slice when no attachments are present.
 	Attachments []Attachment
+
+	// Links lists the work item's links to other work items.
+	// Always non-nil; empty slice when no links are present.
+	Links []IssueLink
+
+	// Parent is the key of the parent work item (e.g., the epic), or
+	// empty if the work item has no parent.
+	Parent string
+
+	// Comments holds every comment on the work item when the tracker
+	// returned them along with it, oldest first. Nil means they were
+	// not fetched (or only some were) and must be fetched separately.
+	Comments []Comment
+}
+
+// Well-known link types. Jira's defaults are matched
+// case-insensitively by name.
+const (
+	LinkTypeBlocks    = "Blocks"
+	LinkTypeDuplicate = "Duplicate"
+)
+
+// IssueLink describes a relationship between a work item and another
+// work item, read from the linking item's point of view (e.g.,
+// "PROJ-1 is blocked by PROJ-2" has Relation "is blocked by" and
+// Key "PROJ-2").
+type IssueLink struct {
+	// Type is the link type name (e.g., "Blocks", "Duplicate").
+	Type string
+
+	// Relation is the human-readable direction (e.g., "is blocked by").
+	Relation string
+
+	// Outward is true when this work item is the source of the link
+	// (e.g., it "blocks" or "duplicates" the linked item).
+	Outward bool
+
+	// Key is the linked work item's identifier.
+	Key string
+
+	// Summary is the linked work item's short title.
+	Summary string
+
+	// Status is the linked work item's workflow status.
+	Status string
+
+	// Resolved reports whether the linked work item is in a done
+	// status category.
+	Resolved bool
 }
 
 // Attachment represents a file attached to a work item.
@@ -81,7 +159,63 @@ type RepoCoord struct {
 	Repo  string
 }
 
+// MergeOrder is the order in which a ticket's pull requests in several
+// repositories must be merged. DoNotMergeLabel, when set, holds back
+// the pull requests waiting for earlier ones.
+type MergeOrder struct {
+	Repos           []RepoCoord
+	DoNotMergeLabel string
+}
+
 // HasSecurityLevel reports whether this work item has a security level set.
 func (w WorkItem) HasSecurityLevel() bool {
 	return w.SecurityLevel != ""
 }
+
+// UnresolvedBlockers returns the linked work items that block this one
+// and are not yet resolved.
+func (w WorkItem) UnresolvedBlockers() []IssueLink {
+	var blockers []IssueLink
+	for _, l := range w.Links {
+		if strings.EqualFold(l.Type, LinkTypeBlocks) && !l.Outward && !l.Resolved {
+			blockers = append(blockers, l)
+		}
+	}
+	return blockers
+}
+
+// DuplicateOf returns the work items this one is marked as a duplicate
+// of.
+func (w WorkItem) DuplicateOf() []IssueLink {
+	var originals []IssueLink
+	for _, l := range w.Links {
+		if strings.EqualFold(l.Type, LinkTypeDuplicate) && l.Outward {
+			originals = append(originals, l)
+		}
+	}
+	return originals
+}
+
+// priorityRanks maps well-known priority names (Jira's defaults and
+// their common legacy equivalents) to a rank. Higher ranks are more
+// urgent.
+var priorityRanks = map[string]int{
+	"blocker":  2,
+	"highest":  2,
+	"critical": 1,
+	"high":     1,
+	"medium":   0,
+	"major":    0,
+	"normal":   0,
+	"low":      -1,
+	"minor":    -1,
+	"lowest":   -2,
+	"trivial":  -2,
+}
+
+// PriorityRank returns the urgency of the work item's priority, where
+// higher values are more urgent. Unknown or empty priorities rank the
+// same as "Medium" (zero).
+func (w WorkItem) PriorityRank() int {
+	return priorityRanks[strings.ToLower(strings.TrimSpace(w.Priority))]
+}
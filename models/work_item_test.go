@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestWorkItem_PriorityRank(t *testing.T) {
+	tests := []struct {
+		priority string
+		want     int
+	}{
+		{priority: "Highest", want: 2},
+		{priority: "Blocker", want: 2},
+		{priority: "high", want: 1},
+		{priority: "Medium", want: 0},
+		{priority: "", want: 0},
+		{priority: "P-Custom", want: 0},
+		{priority: " Low ", want: -1},
+		{priority: "Trivial", want: -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.priority, func(t *testing.T) {
+			item := models.WorkItem{Priority: tt.priority}
+			if got := item.PriorityRank(); got != tt.want {
+				t.Errorf("PriorityRank() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkItem_UnresolvedBlockers(t *testing.T) {
+	item := models.WorkItem{Links: []models.IssueLink{
+		{Type: "Blocks", Key: "A-1"},
+		{Type: "blocks", Key: "A-2", Resolved: true},
+		{Type: "Blocks", Key: "A-3", Outward: true},
+		{Type: "Relates", Key: "A-4"},
+	}}
+	got := item.UnresolvedBlockers()
+	if len(got) != 1 || got[0].Key != "A-1" {
+		t.Errorf("UnresolvedBlockers() = %+v, want only A-1", got)
+	}
+}
+
+func TestWorkItem_DuplicateOf(t *testing.T) {
+	item := models.WorkItem{Links: []models.IssueLink{
+		{Type: "Duplicate", Key: "A-1", Outward: true},
+		{Type: "Duplicate", Key: "A-2"},
+	}}
+	got := item.DuplicateOf()
+	if len(got) != 1 || got[0].Key != "A-1" {
+		t.Errorf("DuplicateOf() = %+v, want only A-1", got)
+	}
+}
@@ -0,0 +1,131 @@
+// Package notify tells operators about tickets that need them outside
+// of Jira.
+//
+// The [Webhook] subscribes to the pipeline's [events.Bus] and POSTs a
+// JSON message for each dead-lettered ticket to a configured URL. The
+// message's "text" field is what Slack and Mattermost incoming
+// webhooks display; other endpoints can read its structured fields.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/events"
+)
+
+// requestTimeout bounds each webhook request.
+const requestTimeout = 30 * time.Second
+
+// Message is the JSON body the [Webhook] posts.
+type Message struct {
+	Text      string    `json:"text"`
+	Event     string    `json:"event"`
+	Ticket    string    `json:"ticket"`
+	TicketURL string    `json:"ticket_url,omitempty"`
+	Attempts  int       `json:"attempts"`
+	Time      time.Time `json:"time"`
+}
+
+// Webhook posts a [Message] to a URL for each dead-lettered ticket.
+type Webhook struct {
+	url     string
+	jiraURL string
+	client  *http.Client
+	logger  *zap.Logger
+}
+
+// NewWebhook creates a Webhook posting to webhookURL. jiraBaseURL,
+// when set, is used to link the ticket. A nil client uses
+// [http.DefaultClient].
+func NewWebhook(webhookURL, jiraBaseURL string, client *http.Client, logger *zap.Logger) (*Webhook, error) {
+	if webhookURL == "" {
+		return nil, errors.New("webhook URL must not be empty")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Webhook{
+		url:     webhookURL,
+		jiraURL: strings.TrimRight(jiraBaseURL, "/"),
+		client:  client,
+		logger:  logger,
+	}, nil
+}
+
+// Handle posts a message for a [events.TicketDeadLettered] event.
+// Matches [events.Handler]. Other events are ignored; delivery errors
+// are logged and not retried.
+func (w *Webhook) Handle(e events.Event) {
+	if e.Type != events.TicketDeadLettered || e.TicketKey == "" {
+		return
+	}
+	if err := w.post(w.message(e)); err != nil {
+		w.logger.Error("Failed to send dead-letter notification",
+			zap.String("ticket", e.TicketKey), zap.Error(err))
+	}
+}
+
+// message builds the notification for a dead-lettered ticket.
+func (w *Webhook) message(e events.Event) Message {
+	m := Message{
+		Event:    string(e.Type),
+		Ticket:   e.TicketKey,
+		Attempts: e.Attempt,
+		Time:     e.Time,
+	}
+	if w.jiraURL != "" {
+		m.TicketURL = w.jiraURL + "/browse/" + e.TicketKey
+	}
+	m.Text = fmt.Sprintf("%s was dead-lettered after %d failed attempts and needs a human.", e.TicketKey, e.Attempt)
+	if m.TicketURL != "" {
+		m.Text += " " + m.TicketURL
+	}
+	return m
+}
+
+// post sends m to the webhook URL.
+func (w *Webhook) post(m Message) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		// The URL often embeds a token; keep it out of the log.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("post: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"jira-ai-issue-solver/events"
+	"jira-ai-issue-solver/notify"
+)
+
+func TestNewWebhook_Validation(t *testing.T) {
+	if _, err := notify.NewWebhook("", "", nil, zap.NewNop()); err == nil {
+		t.Error("expected error for empty URL")
+	}
+	if _, err := notify.NewWebhook("https://hooks.example.com/x", "", nil, nil); err == nil {
+		t.Error("expected error for nil logger")
+	}
+}
+
+func TestWebhook_PostsDeadLetteredTicket(t *testing.T) {
+	var got []notify.Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		var m notify.Message
+		if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		got = append(got, m)
+	}))
+	defer srv.Close()
+
+	w, err := notify.NewWebhook(srv.URL, "https://jira.example.com/", srv.Client(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	at := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+	w.Handle(events.Event{Type: events.TicketStarted, TicketKey: "PROJ-1"})
+	w.Handle(events.Event{Type: events.TicketDeadLettered, TicketKey: "PROJ-1", Attempt: 4, Time: at})
+
+	want := notify.Message{
+		Text:      "PROJ-1 was dead-lettered after 4 failed attempts and needs a human. https://jira.example.com/browse/PROJ-1",
+		Event:     "ticket.dead_lettered",
+		Ticket:    "PROJ-1",
+		TicketURL: "https://jira.example.com/browse/PROJ-1",
+		Attempts:  4,
+		Time:      at,
+	}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("messages = %+v, want [%+v]", got, want)
+	}
+}
+
+func TestWebhook_LogsFailedDelivery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	core, logs := observer.New(zap.ErrorLevel)
+	w, err := notify.NewWebhook(srv.URL, "", srv.Client(), zap.New(core))
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	w.Handle(events.Event{Type: events.TicketDeadLettered, TicketKey: "PROJ-1", Attempt: 4})
+
+	if logs.FilterMessage("Failed to send dead-letter notification").Len() != 1 {
+		t.Errorf("logs = %v, want one delivery failure", logs.All())
+	}
+}
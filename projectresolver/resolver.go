@@ -11,110 +11,225 @@ package projectresolver
 
 import (
 	"fmt"
-	"net/url"
+	"maps"
+	"slices"
 	"strings"
+	"sync/atomic"
 
 	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
 )
 
 // ConfigResolver maps work items to project settings using the bot's
 // configuration. It satisfies executor.ProjectResolver,
 // recovery.ProjectResolver, and (via LocateRepo) scanner.RepoLocator.
+//
+// The configuration can be swapped at runtime via [ConfigResolver.Update];
+// each method call observes a single consistent snapshot.
 type ConfigResolver struct {
-	config *models.Config
+	config   atomic.Pointer[models.Config]
+	branches DefaultBranchLookup
+}
+
+// DefaultBranchLookup reports a repository's default branch. It is
+// used for repos that do not configure a target_branch.
+type DefaultBranchLookup interface {
+	GetDefaultBranch(owner, repo string) (string, error)
+}
+
+// Option configures optional ConfigResolver behavior.
+type Option func(*ConfigResolver)
+
+// WithDefaultBranchLookup resolves the base branch of repos without a
+// configured target_branch to the repository's default branch. Without
+// it, such repos default to "main".
+func WithDefaultBranchLookup(l DefaultBranchLookup) Option {
+	return func(r *ConfigResolver) {
+		r.branches = l
+	}
 }
 
 // NewConfigResolver returns a ConfigResolver backed by the given
 // configuration. Returns an error if config is nil.
-func NewConfigResolver(config *models.Config) (*ConfigResolver, error) {
+func NewConfigResolver(config *models.Config, opts ...Option) (*ConfigResolver, error) {
 	if config == nil {
 		return nil, fmt.Errorf("config must not be nil")
 	}
-	return &ConfigResolver{config: config}, nil
+	r := &ConfigResolver{}
+	r.config.Store(config)
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Update replaces the configuration used for subsequent lookups.
+// Calls already in progress finish against the previous configuration.
+// Returns an error if config is nil.
+func (r *ConfigResolver) Update(config *models.Config) error {
+	if config == nil {
+		return fmt.Errorf("config must not be nil")
+	}
+	r.config.Store(config)
+	return nil
 }
 
 // ResolveProject returns project-specific settings for the work item.
 // It locates the project configuration, resolves the component (or
 // default workspace) to a workspace, and maps status transitions for
 // the work item's type. Each repo in the workspace gets its own
-// RepoSettings entry populated from its profile.
+// RepoSettings entry populated from its profile. A branch mapped from
+// one of the work item's fix versions, or else the component's target
+// branch, overrides the repos' target branches. For embargoed work
+// items the repos are their private mirrors and fork mode is off.
 func (r *ConfigResolver) ResolveProject(workItem models.WorkItem) (*models.ProjectSettings, error) {
-	pc, err := r.findProjectConfig(workItem)
+	cfg := r.config.Load()
+	pc, err := findProjectConfig(cfg, workItem)
 	if err != nil {
 		return nil, err
 	}
 
-	wsName, err := r.findWorkspaceName(workItem, pc)
+	comp, err := r.findComponent(workItem, pc)
 	if err != nil {
 		return nil, err
 	}
 
-	ws, ok := lookupWorkspace(pc.Workspaces, wsName)
+	ws, ok := lookupWorkspace(pc.Workspaces, comp.Workspace)
 	if !ok {
-		return nil, fmt.Errorf("workspace %q does not exist in project config for %s", wsName, workItem.Key)
+		return nil, fmt.Errorf("workspace %q does not exist in project config for %s", comp.Workspace, workItem.Key)
 	}
 	if len(ws.Repos) == 0 {
-		return nil, fmt.Errorf("workspace %q has no repos configured for %s", wsName, workItem.Key)
+		return nil, fmt.Errorf("workspace %q has no repos configured for %s", comp.Workspace, workItem.Key)
+	}
+
+	targetBranch := comp.TargetBranch
+	if branch := pc.FixVersionBranches.Branch(workItem.FixVersions); branch != "" {
+		targetBranch = branch
 	}
 
-	repos, err := r.buildRepoSettings(workItem, pc, ws)
+	embargoed := pc.Embargo.Applies(workItem)
+	repos, err := r.buildRepoSettings(workItem, pc, ws, targetBranch, embargoed)
 	if err != nil {
 		return nil, err
 	}
 
 	transitions := pc.StatusTransitions.GetStatusTransitions(workItem.Type)
 
+	// Forks of public repos are public, so embargoed work never uses
+	// them.
+	forkMode := pc.ForkMode && !embargoed
 	var ghUsername string
-	if pc.ForkMode && workItem.Assignee != nil {
-		ghUsername = r.config.Jira.AssigneeToGitHubUsername[workItem.Assignee.Email]
+	if forkMode && workItem.Assignee != nil {
+		ghUsername = cfg.Jira.AssigneeToGitHubUsername[workItem.Assignee.Email]
 	}
 
-	maxTicketCost := r.config.Guardrails.MaxTicketCostUSD
+	var botAssignee string
+	if pc.AssignBot {
+		botAssignee = cfg.Jira.BotAccountID
+	}
+
+	maxTicketCost := cfg.Guardrails.MaxTicketCostUSD
 	if pc.MaxTicketCostUSD != nil {
 		maxTicketCost = *pc.MaxTicketCostUSD
 	}
 
+	maxOpenPRs := cfg.Guardrails.MaxOpenPRsPerRepo
+	if pc.MaxOpenPRsPerRepo != nil {
+		maxOpenPRs = *pc.MaxOpenPRsPerRepo
+	}
+
 	return &models.ProjectSettings{
-		Repos:                repos,
-		RootRepoURL:          ws.RootRepo,
-		InProgressStatus:     transitions.InProgress,
-		InReviewStatus:       transitions.InReview,
-		TodoStatus:           transitions.Todo,
-		PRURLFieldName:       pc.GitPullRequestFieldName,
-		DisableErrorComments: pc.DisableErrorComments,
-		AIProvider:           r.config.AIProvider,
-		Container:            ws.Container,
-		FailureLabels:        pc.FailureLabels,
-		LifecycleLabels:      pc.LifecycleLabels,
-		PRValidationLabels:   pc.PRValidationLabels,
-		MergedStatus:         transitions.Merged,
-		ForkMode:             pc.ForkMode,
-		GitHubUsername:       ghUsername,
-		MaxTicketCostUSD:     maxTicketCost,
+		Repos:                       repos,
+		RootRepoURL:                 ws.RootRepo,
+		CompanionRepos:              pc.CompanionRepos,
+		MergeOrder:                  ws.MergeOrder,
+		DoNotMergeLabel:             ws.DoNotMergeLabel,
+		InProgressStatus:            transitions.InProgress,
+		InReviewStatus:              transitions.InReview,
+		TodoStatus:                  transitions.Todo,
+		PRURLFieldName:              pc.GitPullRequestFieldName,
+		AssessmentFieldName:         pc.AssessmentFieldName,
+		AcceptanceCriteriaFieldName: pc.AcceptanceCriteriaFieldName,
+		DisableErrorComments:        pc.DisableErrorComments,
+		AIProvider:                  cfg.AIProvider,
+		Container:                   ws.Container,
+		FailureLabels:               pc.FailureLabels,
+		LifecycleLabels:             pc.LifecycleLabels,
+		PRValidationLabels:          pc.PRValidationLabels,
+		MergedStatus:                transitions.Merged,
+		NeedsHumanStatus:            transitions.NeedsHuman,
+		WaitingForInfoStatus:        transitions.WaitingForInfo,
+		EscalateLowConfidence:       pc.EscalateLowConfidence,
+		SelfReview:                  pc.SelfReview,
+		AutoMerge:                   pc.AutoMerge,
+		CheckRun:                    pc.CheckRun,
+		BotAssignee:                 botAssignee,
+		MinConfidence:               pc.MinConfidence,
+		DiffPreview:                 pc.DiffPreview,
+		RegressionTests:             pc.RegressionTests,
+		FeedbackSplitThreshold:      pc.FeedbackSplitThreshold,
+		HumanPushPolicy:             pc.HumanPushPolicy,
+		SecurityScans:               pc.SecurityScans,
+		DependencyPolicy:            pc.DependencyPolicy,
+		BatchLabel:                  pc.BatchLabel,
+		ActiveSprintOnly:            pc.ActiveSprintOnly,
+		FixVersions:                 pc.FixVersions,
+		ForkMode:                    forkMode,
+		Embargoed:                   embargoed,
+		GitHubUsername:              ghUsername,
+		MaxTicketCostUSD:            maxTicketCost,
+		MaxOpenPRsPerRepo:           maxOpenPRs,
+		Budget:                      pc.Budget,
+		BudgetProjects:              pc.ProjectKeys,
+		CommitMessage:               pc.CommitMessage,
+		PromptStrategy:              pc.PromptStrategies.GetPromptStrategy(workItem.Type),
+		Backport:                    pc.Backport,
+		ReleaseNotes:                pc.ReleaseNotes,
+		Triage:                      pc.Triage,
+		Spike:                       pc.Spike,
+		Components:                  slices.Sorted(maps.Keys(pc.Components)),
 	}, nil
 }
 
 // buildRepoSettings constructs a RepoSettings entry for each repo
-// in the workspace, resolving the profile for each.
-func (r *ConfigResolver) buildRepoSettings(workItem models.WorkItem, pc *models.ProjectConfig, ws models.WorkspaceConfig) ([]models.RepoSettings, error) {
+// in the workspace, resolving the profile for each. A non-empty
+// targetBranch overrides every repo's configured target branch. When
+// embargoed, each repo is replaced by its private mirror.
+func (r *ConfigResolver) buildRepoSettings(workItem models.WorkItem, pc *models.ProjectConfig, ws models.WorkspaceConfig, targetBranch string, embargoed bool) ([]models.RepoSettings, error) {
 	repos := make([]models.RepoSettings, 0, len(ws.Repos))
 	for _, entry := range ws.Repos {
-		owner, repo, err := parseRepoURL(entry.URL)
+		cloneURL, subdir := entry.Location()
+		if embargoed {
+			if entry.PrivateMirror == "" {
+				return nil, fmt.Errorf("repo %q has no private_mirror for embargoed ticket %s", entry.Name, workItem.Key)
+			}
+			cloneURL = entry.PrivateMirror
+		}
+		info, err := scm.ExtractRepoInfo(cloneURL)
 		if err != nil {
-			return nil, fmt.Errorf("parsing repo URL %q for %s: %w", entry.URL, workItem.Key, err)
+			return nil, fmt.Errorf("parsing repo URL %q for %s: %w", cloneURL, workItem.Key, err)
 		}
+		owner, repo := info.Owner, info.Repo
 
 		baseBranch := entry.TargetBranch
+		if targetBranch != "" {
+			baseBranch = targetBranch
+		}
 		if baseBranch == "" {
-			baseBranch = "main"
+			baseBranch, err = r.defaultBranch(owner, repo)
+			if err != nil {
+				return nil, fmt.Errorf("resolving default branch of %s/%s for %s: %w", owner, repo, workItem.Key, err)
+			}
 		}
 
 		rs := models.RepoSettings{
-			Name:       entry.Name,
-			Owner:      owner,
-			Repo:       repo,
-			CloneURL:   entry.URL,
-			BaseBranch: baseBranch,
+			Name:         entry.Name,
+			Owner:        owner,
+			Repo:         repo,
+			CloneURL:     cloneURL,
+			BaseBranch:   baseBranch,
+			Subdirectory: subdir,
+			SparsePaths:  entry.SparsePaths(),
 		}
 
 		if entry.Profile != "" {
@@ -123,6 +238,7 @@ func (r *ConfigResolver) buildRepoSettings(workItem models.WorkItem, pc *models.
 				return nil, fmt.Errorf("profile %q referenced by repo %q does not exist in project config for %s", entry.Profile, entry.Name, workItem.Key)
 			}
 			rs.Container = profile.Container
+			rs.Setup = profile.Setup
 			rs.Imports = profile.Imports
 			if rs.Imports == nil {
 				rs.Imports = []models.ImportConfig{}
@@ -139,6 +255,15 @@ func (r *ConfigResolver) buildRepoSettings(workItem models.WorkItem, pc *models.
 	return repos, nil
 }
 
+// defaultBranch returns the repository's default branch, or "main"
+// when no DefaultBranchLookup is configured.
+func (r *ConfigResolver) defaultBranch(owner, repo string) (string, error) {
+	if r.branches == nil {
+		return "main", nil
+	}
+	return r.branches.GetDefaultBranch(owner, repo)
+}
+
 // LocateRepo returns the GitHub owner and repo for the work item.
 // For multi-repo workspaces, returns the first repo.
 func (r *ConfigResolver) LocateRepo(workItem models.WorkItem) (string, string, error) {
@@ -174,14 +299,15 @@ func (r *ConfigResolver) ForkOwner(workItem models.WorkItem) string {
 	// Error from findProjectConfig (unknown project) is intentionally
 	// swallowed — callers treat empty as "no fork" which is correct
 	// when the project cannot be resolved.
-	pc, err := r.findProjectConfig(workItem)
-	if err != nil || !pc.ForkMode {
+	cfg := r.config.Load()
+	pc, err := findProjectConfig(cfg, workItem)
+	if err != nil || !pc.ForkMode || pc.Embargo.Applies(workItem) {
 		return ""
 	}
 	if workItem.Assignee == nil {
 		return ""
 	}
-	return r.config.Jira.AssigneeToGitHubUsername[workItem.Assignee.Email]
+	return cfg.Jira.AssigneeToGitHubUsername[workItem.Assignee.Email]
 }
 
 // ForkOwnerHeads returns candidate PR head refs in priority order.
@@ -200,7 +326,7 @@ func (r *ConfigResolver) ForkOwnerHeads(workItem models.WorkItem, branchName str
 // given work item's project. Returns a zero-value FailureLabels (all
 // labels disabled) if the project cannot be resolved.
 func (r *ConfigResolver) ResolveFailureLabels(item models.WorkItem) models.FailureLabels {
-	pc, err := r.findProjectConfig(item)
+	pc, err := findProjectConfig(r.config.Load(), item)
 	if err != nil {
 		return models.FailureLabels{}
 	}
@@ -211,7 +337,7 @@ func (r *ConfigResolver) ResolveFailureLabels(item models.WorkItem) models.Failu
 // the given work item's project. Returns a zero-value LifecycleLabels
 // (all labels disabled) if the project cannot be resolved.
 func (r *ConfigResolver) ResolveLifecycleLabels(item models.WorkItem) models.LifecycleLabels {
-	pc, err := r.findProjectConfig(item)
+	pc, err := findProjectConfig(r.config.Load(), item)
 	if err != nil {
 		return models.LifecycleLabels{}
 	}
@@ -222,7 +348,7 @@ func (r *ConfigResolver) ResolveLifecycleLabels(item models.WorkItem) models.Lif
 // given work item's project and ticket type. Returns an empty string
 // if no merged status is configured or the project cannot be resolved.
 func (r *ConfigResolver) ResolveMergedStatus(item models.WorkItem) string {
-	pc, err := r.findProjectConfig(item)
+	pc, err := findProjectConfig(r.config.Load(), item)
 	if err != nil {
 		return ""
 	}
@@ -230,32 +356,84 @@ func (r *ConfigResolver) ResolveMergedStatus(item models.WorkItem) string {
 	return transitions.Merged
 }
 
+// ResolveBackportBranches returns the release branches the given work
+// item's backport labels request. Returns nil if backports are not
+// configured or the project cannot be resolved.
+func (r *ConfigResolver) ResolveBackportBranches(item models.WorkItem) []string {
+	pc, err := findProjectConfig(r.config.Load(), item)
+	if err != nil {
+		return nil
+	}
+	return pc.Backport.TargetBranches(item.Labels)
+}
+
+// ResolveReleaseNotes returns the release note settings of the given
+// work item's project. Returns a zero ReleaseNotes (release notes
+// disabled) if the project cannot be resolved.
+func (r *ConfigResolver) ResolveReleaseNotes(item models.WorkItem) models.ReleaseNotes {
+	pc, err := findProjectConfig(r.config.Load(), item)
+	if err != nil {
+		return models.ReleaseNotes{}
+	}
+	return pc.ReleaseNotes
+}
+
+// ResolveDiffPreview returns the change preview settings of the given
+// work item's project. Returns a zero DiffPreview (previews disabled)
+// if the project cannot be resolved.
+func (r *ConfigResolver) ResolveDiffPreview(item models.WorkItem) models.DiffPreview {
+	pc, err := findProjectConfig(r.config.Load(), item)
+	if err != nil {
+		return models.DiffPreview{}
+	}
+	return pc.DiffPreview
+}
+
+// ResolveMergeOrder returns the merge order of the given work item's
+// pull requests across its workspace's repositories. Returns a zero
+// MergeOrder if none is configured or the project cannot be resolved.
+func (r *ConfigResolver) ResolveMergeOrder(item models.WorkItem) models.MergeOrder {
+	settings, err := r.ResolveProject(item)
+	if err != nil || len(settings.MergeOrder) == 0 {
+		return models.MergeOrder{}
+	}
+	order := models.MergeOrder{DoNotMergeLabel: settings.DoNotMergeLabel}
+	for _, name := range settings.MergeOrder {
+		for _, repo := range settings.Repos {
+			if repo.Name == name {
+				order.Repos = append(order.Repos, models.RepoCoord{Owner: repo.Owner, Repo: repo.Repo})
+			}
+		}
+	}
+	return order
+}
+
 // findProjectConfig returns the ProjectConfig for the work item's
 // project key. Returns an error if no configuration can be found.
-func (r *ConfigResolver) findProjectConfig(workItem models.WorkItem) (*models.ProjectConfig, error) {
-	pc := r.config.GetProjectConfigForTicket(workItem.Key)
+func findProjectConfig(cfg *models.Config, workItem models.WorkItem) (*models.ProjectConfig, error) {
+	pc := cfg.GetProjectConfigForTicket(workItem.Key)
 	if pc == nil {
 		return nil, fmt.Errorf("no project configuration found for %s", workItem.Key)
 	}
 	return pc, nil
 }
 
-// findWorkspaceName returns the workspace name for a work item by
+// findComponent returns the component mapping for a work item by
 // checking component mappings first, then falling back to
-// DefaultWorkspace.
-func (r *ConfigResolver) findWorkspaceName(workItem models.WorkItem, pc *models.ProjectConfig) (string, error) {
+// DefaultWorkspace (with no target branch override).
+func (r *ConfigResolver) findComponent(workItem models.WorkItem, pc *models.ProjectConfig) (models.ComponentConfig, error) {
 	// Try component matching if the work item has components.
 	if len(workItem.Components) > 0 {
 		for _, component := range workItem.Components {
 			// Exact match first.
 			if comp, ok := pc.Components[component]; ok {
-				return comp.Workspace, nil
+				return comp, nil
 			}
 			// Case-insensitive fallback.
 			lower := strings.ToLower(component)
 			for key, comp := range pc.Components {
 				if strings.ToLower(key) == lower {
-					return comp.Workspace, nil
+					return comp, nil
 				}
 			}
 		}
@@ -263,13 +441,13 @@ func (r *ConfigResolver) findWorkspaceName(workItem models.WorkItem, pc *models.
 
 	// Fall back to default workspace.
 	if pc.DefaultWorkspace != "" {
-		return pc.DefaultWorkspace, nil
+		return models.ComponentConfig{Workspace: pc.DefaultWorkspace}, nil
 	}
 
 	if len(workItem.Components) == 0 {
-		return "", fmt.Errorf("work item %s has no components and no default_workspace is configured", workItem.Key)
+		return models.ComponentConfig{}, fmt.Errorf("work item %s has no components and no default_workspace is configured", workItem.Key)
 	}
-	return "", fmt.Errorf(
+	return models.ComponentConfig{}, fmt.Errorf(
 		"no component mapping found for %s; components %v do not match any configured mapping and no default_workspace is configured",
 		workItem.Key, workItem.Components)
 }
@@ -301,34 +479,3 @@ func lookupProfile(profiles map[string]models.Profile, name string) (models.Prof
 	}
 	return models.Profile{}, false
 }
-
-// parseRepoURL extracts the owner and repository name from a GitHub
-// URL. Supports URLs with or without a .git suffix and with or without
-// a trailing slash.
-func parseRepoURL(rawURL string) (string, string, error) {
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return "", "", fmt.Errorf("invalid URL: %w", err)
-	}
-
-	// Trim trailing slashes and split on "/".
-	trimmed := strings.TrimRight(parsed.Path, "/")
-	segments := strings.Split(trimmed, "/")
-
-	// Filter out empty segments (from leading slash).
-	var parts []string
-	for _, s := range segments {
-		if s != "" {
-			parts = append(parts, s)
-		}
-	}
-
-	if len(parts) < 2 {
-		return "", "", fmt.Errorf("URL path %q does not contain owner/repo", parsed.Path)
-	}
-
-	owner := parts[len(parts)-2]
-	repo := strings.TrimSuffix(parts[len(parts)-1], ".git")
-
-	return owner, repo, nil
-}
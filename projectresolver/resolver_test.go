@@ -1,6 +1,8 @@
 package projectresolver_test
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 
@@ -28,6 +30,49 @@ func TestNewConfigResolver_ValidConfig(t *testing.T) {
 	}
 }
 
+// --- Update ---
+
+func TestUpdate_NilConfig(t *testing.T) {
+	r, err := projectresolver.NewConfigResolver(minimalConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Update(nil); err == nil {
+		t.Fatal("expected error for nil config")
+	}
+}
+
+func TestUpdate_NewComponentMappingTakesEffect(t *testing.T) {
+	r, err := projectresolver.NewConfigResolver(minimalConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wi := models.WorkItem{Key: "PROJ-7", Type: "Bug", Components: []string{"frontend"}}
+	if _, err := r.ResolveProject(wi); err == nil {
+		t.Fatal("expected error before frontend component is mapped")
+	}
+
+	updated := minimalConfig()
+	updated.Jira.Projects[0].Workspaces["frontend"] = models.WorkspaceConfig{
+		Repos: []models.RepoEntry{
+			{Name: "frontend", URL: "https://github.com/my-org/frontend.git", Profile: "default"},
+		},
+	}
+	updated.Jira.Projects[0].Components["frontend"] = models.ComponentConfig{Workspace: "frontend"}
+	if err := r.Update(updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ps, err := r.ResolveProject(wi)
+	if err != nil {
+		t.Fatalf("unexpected error after update: %v", err)
+	}
+	if ps.Repos[0].Repo != "frontend" {
+		t.Errorf("repo = %q, want %q", ps.Repos[0].Repo, "frontend")
+	}
+}
+
 // --- ResolveProject ---
 
 func TestResolveProject_HappyPath(t *testing.T) {
@@ -326,6 +371,171 @@ func TestResolveProject_URLWithoutGitSuffix(t *testing.T) {
 	}
 }
 
+func TestResolveProject_URLWithSubdirectory(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Jira.Projects[0].Workspaces["backend"] = models.WorkspaceConfig{
+		Repos: []models.RepoEntry{{Name: "mono", URL: "https://github.com/my-org/mono.git#apps/web", Profile: "default"}},
+	}
+
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wi := models.WorkItem{
+		Key:        "PROJ-1",
+		Type:       "Bug",
+		Components: []string{"backend"},
+	}
+
+	ps, err := r.ResolveProject(wi)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repo := ps.Repos[0]
+	if repo.Owner != "my-org" || repo.Repo != "mono" {
+		t.Errorf("expected my-org/mono, got %s/%s", repo.Owner, repo.Repo)
+	}
+	if repo.CloneURL != "https://github.com/my-org/mono.git" {
+		t.Errorf("CloneURL = %q, want the URL without its fragment", repo.CloneURL)
+	}
+	if repo.Subdirectory != "apps/web" {
+		t.Errorf("Subdirectory = %q, want %q", repo.Subdirectory, "apps/web")
+	}
+}
+
+func TestResolveProject_ComponentTargetBranch(t *testing.T) {
+	cfg := minimalConfig()
+	ws := cfg.Jira.Projects[0].Workspaces["backend"]
+	ws.Repos[0].TargetBranch = "develop"
+	cfg.Jira.Projects[0].Workspaces["backend"] = ws
+	cfg.Jira.Projects[0].Components["backport-4.18"] = models.ComponentConfig{
+		Workspace: "backend", TargetBranch: "release-4.18",
+	}
+
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for component, want := range map[string]string{"backend": "develop", "backport-4.18": "release-4.18"} {
+		ps, err := r.ResolveProject(models.WorkItem{Key: "PROJ-1", Type: "Bug", Components: []string{component}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ps.Repos[0].BaseBranch != want {
+			t.Errorf("%s: base branch = %q, want %q", component, ps.Repos[0].BaseBranch, want)
+		}
+	}
+}
+
+func TestResolveProject_FixVersionBranch(t *testing.T) {
+	cfg := minimalConfig()
+	ws := cfg.Jira.Projects[0].Workspaces["backend"]
+	ws.Repos[0].TargetBranch = "develop"
+	cfg.Jira.Projects[0].Workspaces["backend"] = ws
+	cfg.Jira.Projects[0].Components["backport-4.17"] = models.ComponentConfig{
+		Workspace: "backend", TargetBranch: "release-4.17",
+	}
+	cfg.Jira.Projects[0].FixVersionBranches = models.FixVersionBranches{
+		{FixVersion: "4.18.z", Branch: "release-4.18"},
+	}
+
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		component   string
+		fixVersions []string
+		want        string
+	}{
+		{"mapped fix version", "backend", []string{"4.18.Z"}, "release-4.18"},
+		{"unmapped fix version", "backend", []string{"4.19"}, "develop"},
+		{"fix version over component", "backport-4.17", []string{"4.18.z"}, "release-4.18"},
+		{"component without fix version", "backport-4.17", []string{}, "release-4.17"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps, err := r.ResolveProject(models.WorkItem{
+				Key: "PROJ-1", Type: "Bug", Components: []string{tt.component}, FixVersions: tt.fixVersions,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ps.Repos[0].BaseBranch != tt.want {
+				t.Errorf("base branch = %q, want %q", ps.Repos[0].BaseBranch, tt.want)
+			}
+		})
+	}
+}
+
+type branchLookupFunc func(owner, repo string) (string, error)
+
+func (f branchLookupFunc) GetDefaultBranch(owner, repo string) (string, error) {
+	return f(owner, repo)
+}
+
+func TestResolveProject_DefaultBranchLookup(t *testing.T) {
+	lookup := branchLookupFunc(func(owner, repo string) (string, error) {
+		if owner != "my-org" || repo != "backend" {
+			t.Errorf("lookup(%s, %s), want my-org/backend", owner, repo)
+		}
+		return "trunk", nil
+	})
+
+	t.Run("used when target_branch is unset", func(t *testing.T) {
+		r, err := projectresolver.NewConfigResolver(minimalConfig(), projectresolver.WithDefaultBranchLookup(lookup))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ps, err := r.ResolveProject(models.WorkItem{Key: "PROJ-1", Type: "Bug", Components: []string{"backend"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ps.Repos[0].BaseBranch != "trunk" {
+			t.Errorf("base branch = %q, want %q", ps.Repos[0].BaseBranch, "trunk")
+		}
+	})
+
+	t.Run("configured target_branch wins", func(t *testing.T) {
+		cfg := minimalConfig()
+		ws := cfg.Jira.Projects[0].Workspaces["backend"]
+		ws.Repos[0].TargetBranch = "develop"
+		cfg.Jira.Projects[0].Workspaces["backend"] = ws
+		r, err := projectresolver.NewConfigResolver(cfg, projectresolver.WithDefaultBranchLookup(branchLookupFunc(func(_, _ string) (string, error) {
+			t.Error("lookup should not be called when target_branch is set")
+			return "", nil
+		})))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ps, err := r.ResolveProject(models.WorkItem{Key: "PROJ-1", Type: "Bug", Components: []string{"backend"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ps.Repos[0].BaseBranch != "develop" {
+			t.Errorf("base branch = %q, want %q", ps.Repos[0].BaseBranch, "develop")
+		}
+	})
+
+	t.Run("lookup error fails resolution", func(t *testing.T) {
+		r, err := projectresolver.NewConfigResolver(minimalConfig(), projectresolver.WithDefaultBranchLookup(branchLookupFunc(func(_, _ string) (string, error) {
+			return "", errors.New("API down")
+		})))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = r.ResolveProject(models.WorkItem{Key: "PROJ-1", Type: "Bug", Components: []string{"backend"}})
+		if err == nil || !strings.Contains(err.Error(), "API down") {
+			t.Errorf("err = %v, want lookup error", err)
+		}
+	})
+}
+
 func TestResolveProject_URLWithTrailingSlash(t *testing.T) {
 	cfg := minimalConfig()
 	cfg.Jira.Projects[0].Workspaces["backend"] = models.WorkspaceConfig{
@@ -402,6 +612,36 @@ func TestResolveProject_StatusTransitions_DifferentTypes(t *testing.T) {
 	}
 }
 
+func TestResolveProject_PromptStrategyByType(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Jira.Projects[0].PromptStrategies = models.TicketTypePromptStrategies{
+		"bug": {Guidance: "Reproduce first.", Workflow: "1. Reproduce\n2. Fix"},
+	}
+
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		ticketType string
+		want       models.PromptStrategy
+	}{
+		{"Bug", models.PromptStrategy{Guidance: "Reproduce first.", Workflow: "1. Reproduce\n2. Fix"}},
+		{"Story", models.PromptStrategy{}},
+	} {
+		t.Run(tt.ticketType, func(t *testing.T) {
+			ps, err := r.ResolveProject(models.WorkItem{Key: "PROJ-1", Type: tt.ticketType, Components: []string{"backend"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ps.PromptStrategy != tt.want {
+				t.Errorf("PromptStrategy = %+v, want %+v", ps.PromptStrategy, tt.want)
+			}
+		})
+	}
+}
+
 func TestResolveProject_DisableErrorComments(t *testing.T) {
 	cfg := minimalConfig()
 	cfg.Jira.Projects[0].DisableErrorComments = true
@@ -1402,6 +1642,55 @@ func TestResolveProject_ForkMode(t *testing.T) {
 	})
 }
 
+func TestResolveProject_Embargo(t *testing.T) {
+	cfg := minimalConfig()
+	pc := &cfg.Jira.Projects[0]
+	pc.ForkMode = true
+	pc.Embargo = models.Embargo{Enabled: true, Label: "embargoed"}
+	pc.Workspaces["backend"].Repos[0].PrivateMirror = "https://github.com/my-org-private/backend-private.git"
+	cfg.Jira.AssigneeToGitHubUsername = map[string]string{"alice@example.com": "alice-gh"}
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		item      models.WorkItem
+		embargoed bool
+	}{
+		{name: "public ticket", item: models.WorkItem{}},
+		{name: "security level", item: models.WorkItem{SecurityLevel: "Embargoed"}, embargoed: true},
+		{name: "embargo label", item: models.WorkItem{Labels: []string{"embargoed"}}, embargoed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			item.Key, item.Type, item.Components = "PROJ-1", "Bug", []string{"backend"}
+			item.Assignee = &models.Author{Email: "alice@example.com"}
+
+			ps, err := r.ResolveProject(item)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			wantOwner, wantRepo, wantFork := "my-org", "backend", "alice-gh"
+			if tt.embargoed {
+				wantOwner, wantRepo, wantFork = "my-org-private", "backend-private", ""
+			}
+			repo := ps.Repos[0]
+			if repo.Owner != wantOwner || repo.Repo != wantRepo {
+				t.Errorf("repo = %s/%s, want %s/%s", repo.Owner, repo.Repo, wantOwner, wantRepo)
+			}
+			if ps.Embargoed != tt.embargoed || ps.ForkMode == tt.embargoed {
+				t.Errorf("Embargoed = %v, ForkMode = %v; want embargoed %v", ps.Embargoed, ps.ForkMode, tt.embargoed)
+			}
+			if got := r.ForkOwner(item); got != wantFork {
+				t.Errorf("ForkOwner = %q, want %q", got, wantFork)
+			}
+		})
+	}
+}
+
 func TestResolveProject_MaxTicketCostUSD(t *testing.T) {
 	wi := models.WorkItem{
 		Key:        "PROJ-1",
@@ -1471,6 +1760,64 @@ func TestResolveProject_MaxTicketCostUSD(t *testing.T) {
 	})
 }
 
+func TestResolveProject_MaxOpenPRsPerRepo(t *testing.T) {
+	wi := models.WorkItem{
+		Key:        "PROJ-1",
+		Type:       "Bug",
+		Components: []string{"backend"},
+	}
+
+	disabled := 0
+	tests := []struct {
+		name     string
+		override *int
+		want     int
+	}{
+		{name: "uses global default", want: 5},
+		{name: "per-project zero disables limit", override: &disabled, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig()
+			cfg.Guardrails.MaxOpenPRsPerRepo = 5
+			cfg.Jira.Projects[0].MaxOpenPRsPerRepo = tt.override
+
+			r, err := projectresolver.NewConfigResolver(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ps, err := r.ResolveProject(wi)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ps.MaxOpenPRsPerRepo != tt.want {
+				t.Errorf("MaxOpenPRsPerRepo = %d, want %d", ps.MaxOpenPRsPerRepo, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveProject_Budget(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Jira.Projects[0].ProjectKeys = models.ProjectKeys{"PROJ", "OPS"}
+	cfg.Jira.Projects[0].Budget = models.CostBudget{DailyUSD: 50, MonthlyUSD: 500}
+
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ps, err := r.ResolveProject(models.WorkItem{Key: "OPS-1", Type: "Bug", Components: []string{"backend"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ps.Budget != cfg.Jira.Projects[0].Budget {
+		t.Errorf("Budget = %+v, want %+v", ps.Budget, cfg.Jira.Projects[0].Budget)
+	}
+	if !slices.Equal(ps.BudgetProjects, []string{"PROJ", "OPS"}) {
+		t.Errorf("BudgetProjects = %v, want [PROJ OPS]", ps.BudgetProjects)
+	}
+}
+
 // assertContains is a test helper that fails if s does not contain substr.
 func assertContains(t *testing.T, s, substr string) {
 	t.Helper()
@@ -1478,3 +1825,34 @@ func assertContains(t *testing.T, s, substr string) {
 		t.Errorf("expected %q to contain %q", s, substr)
 	}
 }
+
+func TestResolveBackportBranches(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Jira.Projects[0].Backport = models.Backport{LabelPrefix: "backport-", BranchTemplate: "release/{{version}}"}
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := r.ResolveBackportBranches(models.WorkItem{Key: "PROJ-1", Labels: []string{"backport-4.17", "bug"}})
+	if len(got) != 1 || got[0] != "release/4.17" {
+		t.Errorf("branches = %v, want [release/4.17]", got)
+	}
+
+	if got := r.ResolveBackportBranches(models.WorkItem{Key: "PROJ-2", Labels: []string{"bug"}}); got != nil {
+		t.Errorf("branches without backport labels = %v, want nil", got)
+	}
+}
+
+func TestResolveReleaseNotes(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.Jira.Projects[0].ReleaseNotes = models.ReleaseNotes{ChangelogFile: "CHANGELOG.md"}
+	r, err := projectresolver.NewConfigResolver(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := r.ResolveReleaseNotes(models.WorkItem{Key: "PROJ-1"}); got.ChangelogFile != "CHANGELOG.md" {
+		t.Errorf("release notes = %+v, want the project's changelog", got)
+	}
+}
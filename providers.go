@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/ailimit"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
+	"jira-ai-issue-solver/secrets"
+	"jira-ai-issue-solver/services"
+)
+
+// AIProviderOptions are the bot-wide settings and clients an
+// [AIProviderFactory] builds its provider from.
+type AIProviderOptions struct {
+	// Config is the bot configuration. A provider without a section
+	// of its own reads its settings from the environment.
+	Config *models.Config
+
+	// HTTPClient reaches AI APIs through the configured proxy and CA
+	// bundle.
+	HTTPClient *http.Client
+
+	// Secrets resolves secret references in credentials.
+	Secrets *secrets.Store
+
+	Logger *zap.Logger
+}
+
+// AIProvider is how the bot runs one provider's AI sessions.
+type AIProvider struct {
+	// Runner runs sessions in-process. When nil, sessions run the
+	// provider's CLI in the dev container.
+	Runner executor.AgentRunner
+
+	// APIKey is passed to the dev container when Runner is nil.
+	// Optional.
+	APIKey string
+
+	// Limits bounds the provider's concurrent sessions and start rate.
+	Limits ailimit.ProviderLimits
+}
+
+// AIProviderFactory creates a provider from the bot configuration.
+// It is called once at startup, for every registered provider, so a
+// provider that is not configured should return a zero AIProvider
+// rather than an error.
+type AIProviderFactory func(AIProviderOptions) (AIProvider, error)
+
+// aiProviders holds the registered provider factories by name.
+var aiProviders = make(map[string]AIProviderFactory)
+
+// RegisterAIProvider makes a provider available under name, which
+// ai_provider can then select. Custom providers (e.g., an internal LLM
+// gateway) are compiled in by adding a file to this package whose init
+// function registers them. Panics if name is empty or already
+// registered, or factory is nil.
+func RegisterAIProvider(name string, factory AIProviderFactory) {
+	if name == "" {
+		panic("RegisterAIProvider: empty provider name")
+	}
+	if factory == nil {
+		panic("RegisterAIProvider: nil factory for provider " + name)
+	}
+	if _, dup := aiProviders[name]; dup {
+		panic("RegisterAIProvider: provider " + name + " registered twice")
+	}
+	aiProviders[name] = factory
+}
+
+func init() {
+	RegisterAIProvider("claude", newClaudeProvider)
+	RegisterAIProvider("gemini", newGeminiProvider)
+	RegisterAIProvider("bedrock", newBedrockProvider)
+	RegisterAIProvider("azure_openai", newAzureOpenAIProvider)
+}
+
+// buildAIProviders creates every registered provider. Returns an error
+// if a factory fails or ai_provider names no registered provider.
+func buildAIProviders(opts AIProviderOptions) (map[string]AIProvider, error) {
+	if _, ok := aiProviders[opts.Config.AIProvider]; !ok {
+		names := make([]string, 0, len(aiProviders))
+		for name := range aiProviders {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("ai_provider %q is not a registered provider (registered: %v)", opts.Config.AIProvider, names)
+	}
+	providers := make(map[string]AIProvider, len(aiProviders))
+	for name, factory := range aiProviders {
+		provider, err := factory(opts)
+		if err != nil {
+			return nil, fmt.Errorf("create AI provider %s: %w", name, err)
+		}
+		providers[name] = provider
+	}
+	return providers, nil
+}
+
+// newClaudeProvider runs Claude in-process in api mode, so the API
+// key is not injected into the container, and with the Claude Code
+// CLI otherwise.
+func newClaudeProvider(opts AIProviderOptions) (AIProvider, error) {
+	cfg := opts.Config.Claude
+	provider := AIProvider{
+		Limits: ailimit.ProviderLimits{
+			MaxConcurrent: cfg.MaxConcurrentSessions,
+			MinInterval:   perMinuteInterval(cfg.SessionsPerMinute),
+		},
+	}
+	if cfg.Mode != models.AIModeAPI {
+		provider.APIKey = cfg.APIKey
+		return provider, nil
+	}
+	provider.Runner = agent.NewClaudeRunner(agent.ClaudeConfig{
+		HTTPClient:    opts.HTTPClient,
+		APIKey:        cfg.APIKey,
+		Secrets:       opts.Secrets,
+		Model:         cfg.Model,
+		MaxTurns:      cfg.MaxTurns,
+		InputPerMTok:  cfg.InputPricePerMTok,
+		OutputPerMTok: cfg.OutputPricePerMTok,
+	}, opts.Logger)
+	return provider, nil
+}
+
+// newGeminiProvider runs Gemini in-process in api mode and with the
+// Gemini CLI otherwise.
+func newGeminiProvider(opts AIProviderOptions) (AIProvider, error) {
+	cfg := opts.Config.Gemini
+	provider := AIProvider{
+		Limits: ailimit.ProviderLimits{
+			MaxConcurrent: cfg.MaxConcurrentSessions,
+			MinInterval:   perMinuteInterval(cfg.SessionsPerMinute),
+		},
+	}
+	if cfg.Mode != models.AIModeAPI {
+		provider.APIKey = cfg.APIKey
+		return provider, nil
+	}
+	provider.Runner = agent.NewGeminiRunner(agent.GeminiConfig{
+		HTTPClient:    opts.HTTPClient,
+		APIKey:        cfg.APIKey,
+		Secrets:       opts.Secrets,
+		Model:         cfg.Model,
+		MaxTurns:      cfg.MaxTurns,
+		InputPerMTok:  cfg.InputPricePerMTok,
+		OutputPerMTok: cfg.OutputPricePerMTok,
+		CachedPerMTok: cfg.CachedPricePerMTok,
+	}, opts.Logger)
+	return provider, nil
+}
+
+// newBedrockProvider runs Claude through Amazon Bedrock in-process.
+// Bedrock has no CLI mode.
+func newBedrockProvider(opts AIProviderOptions) (AIProvider, error) {
+	cfg := opts.Config.Bedrock
+	provider := AIProvider{
+		Limits: ailimit.ProviderLimits{
+			MaxConcurrent: cfg.MaxConcurrentSessions,
+			MinInterval:   perMinuteInterval(cfg.SessionsPerMinute),
+		},
+	}
+	if !cfg.Enabled() {
+		return provider, nil
+	}
+	provider.Runner = agent.NewBedrockRunner(agent.BedrockConfig{
+		HTTPClient:      opts.HTTPClient,
+		Region:          cfg.Region,
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.SecretAccessKey,
+		SessionToken:    cfg.SessionToken,
+		APIKey:          cfg.APIKey,
+		Secrets:         opts.Secrets,
+		BaseURL:         cfg.Endpoint,
+		Model:           cfg.Model,
+		MaxTurns:        cfg.MaxTurns,
+		InputPerMTok:    cfg.InputPricePerMTok,
+		OutputPerMTok:   cfg.OutputPricePerMTok,
+	}, opts.Logger)
+	return provider, nil
+}
+
+// newAzureOpenAIProvider runs sessions against an Azure OpenAI
+// deployment in-process. Azure OpenAI has no CLI mode.
+func newAzureOpenAIProvider(opts AIProviderOptions) (AIProvider, error) {
+	cfg := opts.Config.AzureOpenAI
+	provider := AIProvider{
+		Limits: ailimit.ProviderLimits{
+			MaxConcurrent: cfg.MaxConcurrentSessions,
+			MinInterval:   perMinuteInterval(cfg.SessionsPerMinute),
+		},
+	}
+	if !cfg.Enabled() {
+		return provider, nil
+	}
+	provider.Runner = agent.NewAzureOpenAIRunner(agent.AzureOpenAIConfig{
+		HTTPClient:    opts.HTTPClient,
+		Endpoint:      cfg.Endpoint,
+		Deployment:    cfg.Deployment,
+		APIVersion:    cfg.APIVersion,
+		APIKey:        cfg.APIKey,
+		TenantID:      cfg.TenantID,
+		ClientID:      cfg.ClientID,
+		ClientSecret:  cfg.ClientSecret,
+		Secrets:       opts.Secrets,
+		MaxTurns:      cfg.MaxTurns,
+		InputPerMTok:  cfg.InputPricePerMTok,
+		OutputPerMTok: cfg.OutputPricePerMTok,
+		CachedPerMTok: cfg.CachedPricePerMTok,
+	}, opts.Logger)
+	return provider, nil
+}
+
+// SCMProviderOptions are the bot-wide settings an [SCMProviderFactory]
+// builds its provider from.
+type SCMProviderOptions struct {
+	// Config is the bot configuration. A provider without a section
+	// of its own reads its settings from the environment.
+	Config *models.Config
+
+	// Secrets resolves secret references in credentials.
+	Secrets *secrets.Store
+
+	Logger *zap.Logger
+}
+
+// SCMProviderFactory creates the provider for a source code
+// management host.
+type SCMProviderFactory func(SCMProviderOptions) (scm.Provider, error)
+
+// scmProviders holds the registered SCM provider factories by host.
+var scmProviders = make(map[string]SCMProviderFactory)
+
+// RegisterSCMProvider serves repositories on host (e.g.,
+// "gitlab.example.com") with the provider factory creates. Repositories
+// on other hosts use the GitHub service. Like AI providers, SCM
+// providers are compiled in from an init function in a file added to
+// this package. Panics if host is empty or already registered, or
+// factory is nil.
+func RegisterSCMProvider(host string, factory SCMProviderFactory) {
+	if host == "" {
+		panic("RegisterSCMProvider: empty host")
+	}
+	if factory == nil {
+		panic("RegisterSCMProvider: nil factory for host " + host)
+	}
+	if _, dup := scmProviders[host]; dup {
+		panic("RegisterSCMProvider: host " + host + " registered twice")
+	}
+	scmProviders[host] = factory
+}
+
+// buildSCMRouter creates every registered SCM provider and one for
+// each configured Gitea instance and for Azure DevOps, and a router that sends each
+// repository's calls to its host's provider, or to github otherwise.
+// It also returns the providers by host.
+func buildSCMRouter(github scm.Provider, opts SCMProviderOptions) (*scm.Router, map[string]scm.Provider, error) {
+	n := len(scmProviders) + len(opts.Config.Gitea.Instances) + 1
+	providers := make(map[string]scm.Provider, n)
+	routerOpts := make([]scm.RouterOption, 0, n)
+	for host, factory := range scmProviders {
+		provider, err := factory(opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create SCM provider for %s: %w", host, err)
+		}
+		providers[host] = provider
+		routerOpts = append(routerOpts, scm.WithProvider(host, provider))
+	}
+	var resolver services.SecretResolver
+	if opts.Secrets != nil {
+		resolver = opts.Secrets
+	}
+	for _, instance := range opts.Config.Gitea.Instances {
+		host := strings.ToLower(instance.Host)
+		if _, dup := providers[host]; dup {
+			return nil, nil, fmt.Errorf("gitea instance %s is also a registered SCM provider", host)
+		}
+		provider, err := services.NewGiteaService(opts.Config, instance, resolver, opts.Logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create Gitea provider for %s: %w", host, err)
+		}
+		providers[host] = provider
+		routerOpts = append(routerOpts, scm.WithProvider(host, provider))
+	}
+	if opts.Config.AzureDevOps.Enabled() {
+		host := strings.ToLower(opts.Config.AzureDevOps.Host)
+		if _, dup := providers[host]; dup {
+			return nil, nil, fmt.Errorf("azure devops host %s has another SCM provider", host)
+		}
+		provider, err := services.NewAzureDevOpsService(opts.Config, resolver, opts.Logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create Azure DevOps provider for %s: %w", host, err)
+		}
+		providers[host] = provider
+		routerOpts = append(routerOpts, scm.WithProvider(host, provider))
+	}
+	router, err := scm.NewRouter(github, opts.Logger, routerOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	addSCMRepos(router, opts.Config, opts.Logger)
+	return router, providers, nil
+}
+
+// addSCMRepos tells the router the host of every configured
+// repository and private mirror, so that calls naming a repository by
+// owner and name before it is cloned reach the right provider.
+func addSCMRepos(router *scm.Router, config *models.Config, logger *zap.Logger) {
+	for _, project := range config.Jira.Projects {
+		for _, ws := range project.Workspaces {
+			for _, entry := range ws.Repos {
+				cloneURL, _ := entry.Location()
+				for _, repoURL := range []string{cloneURL, entry.PrivateMirror} {
+					if repoURL == "" {
+						continue
+					}
+					if err := router.AddRepo(repoURL); err != nil {
+						logger.Warn("Failed to parse repository URL", zap.String("url", repoURL), zap.Error(err))
+					}
+				}
+			}
+		}
+	}
+}
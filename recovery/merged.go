@@ -0,0 +1,104 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
+)
+
+// reconcileMergedTickets finds tickets still "in review" whose PRs were
+// all merged while the service was down and gives them the merged
+// lifecycle label and merged status. The feedback scanner settles
+// merged tickets too, but doing it before scanners start keeps them
+// out of the first feedback cycle and lets terminal workspace cleanup
+// remove their workspaces right away.
+func (r *StartupRunner) reconcileMergedTickets(ctx context.Context) {
+	if len(r.cfg.InReviewCriteria.ProjectKeys) == 0 {
+		return
+	}
+
+	items, err := r.tracker.SearchWorkItems(r.cfg.InReviewCriteria)
+	if err != nil {
+		r.logger.Warn("Failed to search for in-review tickets", zap.Error(err))
+		return
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			r.logger.Info("Merged ticket reconciliation interrupted", zap.Error(ctx.Err()))
+			return
+		}
+		r.reconcileMergedTicket(item)
+	}
+}
+
+// reconcileMergedTicket moves a single in-review ticket to the merged
+// state when every repo that had a PR has it merged. Repos without a
+// PR are skipped, as the AI may only change a subset of a multi-repo
+// workspace. A still-open or rejected PR, a lookup error, or a pending
+// backport or release note leaves the ticket to the feedback scanner.
+func (r *StartupRunner) reconcileMergedTicket(item models.WorkItem) {
+	logger := r.logger.With(zap.String("ticket", item.Key))
+
+	settings, err := r.projects.ResolveProject(item)
+	if err != nil {
+		logger.Warn("Failed to resolve project, skipping", zap.Error(err))
+		return
+	}
+	if settings.MergedStatus == "" && settings.LifecycleLabels.Merged == "" {
+		return
+	}
+	// The feedback scanner submits backports and release notes before
+	// it settles a merged ticket; once the ticket leaves "in review"
+	// they would never be submitted.
+	if len(settings.Backport.TargetBranches(item.Labels)) > 0 || settings.ReleaseNotes.Pending(item.Labels) {
+		logger.Debug("Ticket has pending merge follow-ups, leaving it to the feedback scanner")
+		return
+	}
+
+	heads := settings.PRHeads(fmt.Sprintf("%s/%s", r.cfg.BotUsername, item.Key))
+	merged := 0
+	for _, repo := range settings.Repos {
+		state, err := scm.RepoPRState(r.git, repo.Owner, repo.Repo, heads)
+		if err != nil {
+			logger.Warn("Error looking up PR during recovery",
+				zap.String("repo", repo.Owner+"/"+repo.Repo), zap.Error(err))
+			return
+		}
+		switch state {
+		case scm.PRStateMerged:
+			merged++
+		case scm.PRStateOpen, scm.PRStateClosed:
+			return
+		}
+	}
+	if merged == 0 {
+		return
+	}
+
+	logger.Info("PRs merged while service was down, completing merged transition",
+		zap.String("case", "merged_while_down"))
+	if label := settings.LifecycleLabels.Merged; label != "" {
+		for _, other := range models.AllPipelineLabels(settings.FailureLabels, settings.LifecycleLabels) {
+			if other != "" && other != label {
+				if err := r.tracker.RemoveLabel(item.Key, other); err != nil {
+					logger.Debug("Failed to remove pipeline label",
+						zap.String("label", other), zap.Error(err))
+				}
+			}
+		}
+		if err := r.tracker.AddLabel(item.Key, label); err != nil {
+			logger.Warn("Failed to add merged label", zap.Error(err))
+		}
+	}
+	if settings.MergedStatus != "" {
+		if err := r.tracker.TransitionStatus(item.Key, settings.MergedStatus); err != nil {
+			logger.Warn("Failed to transition to merged status",
+				zap.String("status", settings.MergedStatus), zap.Error(err))
+		}
+	}
+}
@@ -12,8 +12,10 @@
 //  3. For each stuck ticket, determine what was interrupted and
 //     take the appropriate action (complete transition, create PR,
 //     or re-queue for execution)
-//  4. Clean up workspaces for tickets in terminal states
-//  5. Clean up stale workspaces past TTL
+//  4. Query for tickets still "in review" whose PRs were merged while
+//     the service was down, and move them to the merged state
+//  5. Clean up workspaces for tickets in terminal states
+//  6. Clean up stale workspaces past TTL
 //
 // # Consumer-defined interfaces
 //
@@ -56,6 +58,8 @@ type IssueTracker interface {
 	TransitionStatus(key, status string) error
 	SetFieldValue(key, field, value string) error
 	AddComment(key, body string) error
+	AddLabel(key, label string) error
+	RemoveLabel(key, label string) error
 }
 
 // GitService provides the git and GitHub API operations needed for
@@ -73,6 +77,16 @@ type GitService interface {
 
 	// CreatePR creates a pull request.
 	CreatePR(params models.PRParams) (*models.PR, error)
+
+	// GetMergedPRForBranch finds a merged pull request whose head
+	// branch matches the given name. Returns nil, nil when none is
+	// found.
+	GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+
+	// GetClosedPRForBranch finds a pull request closed without
+	// merging whose head branch matches the given name. Returns nil,
+	// nil when none is found.
+	GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
 }
 
 // WorkspaceCleaner manages workspace cleanup operations needed at
@@ -119,6 +133,11 @@ type Config struct {
 	// to limit results to tickets the bot is contributing to.
 	InProgressCriteria models.SearchCriteria
 
+	// InReviewCriteria defines the search query for tickets in "in
+	// review" status, checked for PRs merged while the service was
+	// down. Criteria without project keys disable the check.
+	InReviewCriteria models.SearchCriteria
+
 	// ActiveStatuses is the set of all statuses that indicate a ticket
 	// may still need processing (todo, in_progress, in_review across
 	// all projects and ticket types). Used for workspace cleanup:
@@ -40,6 +40,8 @@ type StubIssueTracker struct {
 	TransitionStatusFunc func(key, status string) error
 	SetFieldValueFunc    func(key, field, value string) error
 	AddCommentFunc       func(key, body string) error
+	AddLabelFunc         func(key, label string) error
+	RemoveLabelFunc      func(key, label string) error
 }
 
 func (s *StubIssueTracker) SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error) {
@@ -77,11 +79,27 @@ func (s *StubIssueTracker) AddComment(key, body string) error {
 	return nil
 }
 
+func (s *StubIssueTracker) AddLabel(key, label string) error {
+	if s.AddLabelFunc != nil {
+		return s.AddLabelFunc(key, label)
+	}
+	return nil
+}
+
+func (s *StubIssueTracker) RemoveLabel(key, label string) error {
+	if s.RemoveLabelFunc != nil {
+		return s.RemoveLabelFunc(key, label)
+	}
+	return nil
+}
+
 // StubGitService is a test double for [recovery.GitService].
 type StubGitService struct {
-	GetPRForBranchFunc   func(owner, repo, head string) (*models.PRDetails, error)
-	BranchHasCommitsFunc func(owner, repo, branch, base string) (bool, error)
-	CreatePRFunc         func(params models.PRParams) (*models.PR, error)
+	GetPRForBranchFunc       func(owner, repo, head string) (*models.PRDetails, error)
+	BranchHasCommitsFunc     func(owner, repo, branch, base string) (bool, error)
+	CreatePRFunc             func(params models.PRParams) (*models.PR, error)
+	GetMergedPRForBranchFunc func(owner, repo, head string) (*models.PRDetails, error)
+	GetClosedPRForBranchFunc func(owner, repo, head string) (*models.PRDetails, error)
 }
 
 func (s *StubGitService) GetPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
@@ -105,6 +123,20 @@ func (s *StubGitService) CreatePR(params models.PRParams) (*models.PR, error) {
 	return &models.PR{}, nil
 }
 
+func (s *StubGitService) GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	if s.GetMergedPRForBranchFunc != nil {
+		return s.GetMergedPRForBranchFunc(owner, repo, head)
+	}
+	return nil, nil
+}
+
+func (s *StubGitService) GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	if s.GetClosedPRForBranchFunc != nil {
+		return s.GetClosedPRForBranchFunc(owner, repo, head)
+	}
+	return nil, nil
+}
+
 // StubWorkspaceCleaner is a test double for [recovery.WorkspaceCleaner].
 type StubWorkspaceCleaner struct {
 	CleanupByFilterFunc func(shouldRemove func(ticketKey string) bool) (int, error)
@@ -100,6 +100,13 @@ func (r *StartupRunner) Run(ctx context.Context) error {
 		return nil
 	}
 
+	r.reconcileMergedTickets(ctx)
+
+	if ctx.Err() != nil {
+		r.logger.Info("Recovery interrupted after merged ticket reconciliation", zap.Error(ctx.Err()))
+		return nil
+	}
+
 	r.cleanTerminalWorkspaces(ctx)
 
 	if ctx.Err() != nil {
@@ -412,8 +419,10 @@ func (r *StartupRunner) revertAndRequeue(
 	}
 
 	_, err := r.jobs.Submit(jobmanager.Event{
-		Type:      jobmanager.JobTypeNewTicket,
-		TicketKey: item.Key,
+		Type:          jobmanager.JobTypeNewTicket,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
 	})
 	if err != nil {
 		logger.Warn("Failed to re-queue ticket", zap.Error(err))
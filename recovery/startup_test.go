@@ -405,9 +405,10 @@ func TestRun_StuckTicketWithCommitsNoPR_CreatePRFails_LeavesInProgress(t *testin
 
 func TestRun_StuckTicketNoPRNoCommits_RevertsAndRequeues(t *testing.T) {
 	d := newDeps()
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
 	d.tracker.SearchWorkItemsFunc = func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
 		return []models.WorkItem{
-			{Key: "PROJ-4", Summary: "New task", Type: "Story",
+			{Key: "PROJ-4", Summary: "New task", Type: "Story", Priority: "High", Created: created,
 				Components: []string{}, Labels: []string{}},
 		}, nil
 	}
@@ -448,6 +449,10 @@ func TestRun_StuckTicketNoPRNoCommits_RevertsAndRequeues(t *testing.T) {
 	if submitted[0].Type != jobmanager.JobTypeNewTicket {
 		t.Errorf("submitted type = %q, want new_ticket", submitted[0].Type)
 	}
+	if submitted[0].Priority != 1 || !submitted[0].TicketCreated.Equal(created) {
+		t.Errorf("submitted priority = %d, created = %v, want 1, %v",
+			submitted[0].Priority, submitted[0].TicketCreated, created)
+	}
 }
 
 // --- BranchHasCommits error ---
@@ -1105,6 +1110,185 @@ func TestRun_MultiRepo_NoPRsNoCommits_Reverts(t *testing.T) {
 	}
 }
 
+// --- Merged while down ---
+
+// inReviewDeps returns deps whose tracker reports PROJ-1 as in review
+// and whose project has merged lifecycle settings, along with the
+// config enabling the in-review check.
+func inReviewDeps(repos ...models.RepoSettings) (*deps, recovery.Config) {
+	d := newDeps()
+	cfg := recovery.Config{
+		BotUsername:      "ai-bot",
+		InReviewCriteria: models.SearchCriteria{ProjectKeys: []string{"PROJ"}, Statuses: []string{"In Review"}},
+	}
+	d.tracker.SearchWorkItemsFunc = func(c models.SearchCriteria) ([]models.WorkItem, error) {
+		if len(c.Statuses) == 1 && c.Statuses[0] == "In Review" {
+			return []models.WorkItem{{Key: "PROJ-1", Type: "Bug"}}, nil
+		}
+		return nil, nil
+	}
+	d.projects.ResolveProjectFunc = func(_ models.WorkItem) (*models.ProjectSettings, error) {
+		return &models.ProjectSettings{
+			Repos:           repos,
+			InReviewStatus:  "In Review",
+			MergedStatus:    "Done",
+			LifecycleLabels: models.LifecycleLabels{Review: "ai-review", Merged: "ai-merged"},
+		}, nil
+	}
+	return d, cfg
+}
+
+func TestRun_InReviewTicketMergedWhileDown_CompletesMerge(t *testing.T) {
+	d, cfg := inReviewDeps(models.RepoSettings{Owner: "org", Repo: "repo", BaseBranch: "main"})
+	d.git.GetMergedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		if head != "ai-bot/PROJ-1" {
+			t.Errorf("head = %q, want ai-bot/PROJ-1", head)
+		}
+		return &models.PRDetails{Number: 42, URL: "https://github.com/org/repo/pull/42"}, nil
+	}
+	var transitions, added, removed []string
+	d.tracker.TransitionStatusFunc = func(_, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+	d.tracker.AddLabelFunc = func(_, label string) error {
+		added = append(added, label)
+		return nil
+	}
+	d.tracker.RemoveLabelFunc = func(_, label string) error {
+		removed = append(removed, label)
+		return nil
+	}
+
+	_ = d.runnerWithConfig(t, cfg).Run(context.Background())
+
+	if len(transitions) != 1 || transitions[0] != "Done" {
+		t.Errorf("transitions = %v, want [Done]", transitions)
+	}
+	if len(added) != 1 || added[0] != "ai-merged" {
+		t.Errorf("added labels = %v, want [ai-merged]", added)
+	}
+	if len(removed) != 1 || removed[0] != "ai-review" {
+		t.Errorf("removed labels = %v, want [ai-review]", removed)
+	}
+}
+
+func TestRun_InReviewTicketNotMerged_Untouched(t *testing.T) {
+	tests := []struct {
+		name   string
+		open   bool
+		closed bool
+		err    error
+	}{
+		{name: "open PR", open: true},
+		{name: "closed PR", closed: true},
+		{name: "no PR"},
+		{name: "lookup error", err: errors.New("api down")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, cfg := inReviewDeps(models.RepoSettings{Owner: "org", Repo: "repo", BaseBranch: "main"})
+			d.git.GetMergedPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+				return nil, tt.err
+			}
+			d.git.GetPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+				if tt.open {
+					return &models.PRDetails{Number: 42}, nil
+				}
+				return nil, nil
+			}
+			d.git.GetClosedPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+				if tt.closed {
+					return &models.PRDetails{Number: 42}, nil
+				}
+				return nil, nil
+			}
+			d.tracker.TransitionStatusFunc = func(_, status string) error {
+				t.Errorf("unexpected transition to %q", status)
+				return nil
+			}
+			d.tracker.AddLabelFunc = func(_, label string) error {
+				t.Errorf("unexpected label %q", label)
+				return nil
+			}
+
+			_ = d.runnerWithConfig(t, cfg).Run(context.Background())
+		})
+	}
+}
+
+func TestRun_InReviewTicketMergedWhileDown_PendingFollowUps_Untouched(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		apply  func(*models.ProjectSettings)
+	}{
+		{
+			name:   "backport requested",
+			labels: []string{"backport-4.17"},
+			apply:  func(s *models.ProjectSettings) { s.Backport = models.Backport{LabelPrefix: "backport-"} },
+		},
+		{
+			name:  "release note pending",
+			apply: func(s *models.ProjectSettings) { s.ReleaseNotes = models.ReleaseNotes{Field: "Release Note"} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, cfg := inReviewDeps(models.RepoSettings{Owner: "org", Repo: "repo", BaseBranch: "main"})
+			d.tracker.SearchWorkItemsFunc = func(c models.SearchCriteria) ([]models.WorkItem, error) {
+				if len(c.Statuses) == 1 && c.Statuses[0] == "In Review" {
+					return []models.WorkItem{{Key: "PROJ-1", Type: "Bug", Labels: tt.labels}}, nil
+				}
+				return nil, nil
+			}
+			resolve := d.projects.ResolveProjectFunc
+			d.projects.ResolveProjectFunc = func(item models.WorkItem) (*models.ProjectSettings, error) {
+				settings, err := resolve(item)
+				tt.apply(settings)
+				return settings, err
+			}
+			d.git.GetMergedPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+				return &models.PRDetails{Number: 42}, nil
+			}
+			d.tracker.TransitionStatusFunc = func(_, status string) error {
+				t.Errorf("unexpected transition to %q", status)
+				return nil
+			}
+			d.tracker.AddLabelFunc = func(_, label string) error {
+				t.Errorf("unexpected label %q", label)
+				return nil
+			}
+
+			_ = d.runnerWithConfig(t, cfg).Run(context.Background())
+		})
+	}
+}
+
+func TestRun_MultiRepo_InReviewTicketMergedWhileDown_SkipsReposWithoutPRs(t *testing.T) {
+	d, cfg := inReviewDeps(
+		models.RepoSettings{Owner: "org", Repo: "svc-a", BaseBranch: "main"},
+		models.RepoSettings{Owner: "org", Repo: "svc-b", BaseBranch: "main"},
+	)
+	d.git.GetMergedPRForBranchFunc = func(_, repo, _ string) (*models.PRDetails, error) {
+		if repo == "svc-a" {
+			return &models.PRDetails{Number: 1}, nil
+		}
+		return nil, nil
+	}
+	var transitions []string
+	d.tracker.TransitionStatusFunc = func(_, status string) error {
+		transitions = append(transitions, status)
+		return nil
+	}
+
+	_ = d.runnerWithConfig(t, cfg).Run(context.Background())
+
+	if len(transitions) != 1 || transitions[0] != "Done" {
+		t.Errorf("transitions = %v, want [Done]", transitions)
+	}
+}
+
 // --- helpers ---
 
 type deps struct {
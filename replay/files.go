@@ -0,0 +1,97 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxSnapshotFileBytes bounds the size of a workspace file saved in a
+// recording. Larger files (e.g., long session logs) are left out.
+const maxSnapshotFileBytes = 4 << 20
+
+// snapshotDirs are the workspace directories saved after calls that
+// change the workspace: the repository's bot configuration and the
+// AI session's inputs and outputs.
+var snapshotDirs = []string{".ai-bot", ".ai-session"}
+
+// snapshot returns the files in the snapshot directories of dir and
+// of its immediate subdirectories (the repositories of a multi-repo
+// workspace), keyed by slash-separated path relative to dir.
+func snapshot(dir string) map[string][]byte {
+	roots := []string{dir}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			roots = append(roots, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	files := make(map[string][]byte)
+	for _, root := range roots {
+		for _, name := range snapshotDirs {
+			_ = filepath.WalkDir(filepath.Join(root, name), func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() || !d.Type().IsRegular() {
+					return nil
+				}
+				if info, err := d.Info(); err != nil || info.Size() > maxSnapshotFileBytes {
+					return nil
+				}
+				data, err := os.ReadFile(path) // #nosec G304 -- path is inside the workspace
+				if err != nil {
+					return nil
+				}
+				rel, err := filepath.Rel(dir, path)
+				if err == nil {
+					files[filepath.ToSlash(rel)] = data
+				}
+				return nil
+			})
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	return files
+}
+
+// restore writes snapshot files into dir, creating dir if needed.
+// Paths that would leave dir are skipped.
+func restore(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	for rel, data := range files {
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relocate replaces the directory from with to in JSON-encoded data,
+// where it appears as a whole string or as a path prefix.
+func relocate(data []byte, from, to string) []byte {
+	if from == "" || from == to {
+		return data
+	}
+	f, t := jsonText(from), jsonText(to)
+	data = bytes.ReplaceAll(data, []byte(`"`+f+`"`), []byte(`"`+t+`"`))
+	return bytes.ReplaceAll(data, []byte(f+"/"), []byte(t+"/"))
+}
+
+// jsonText returns s as it appears inside a JSON string.
+func jsonText(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data[1 : len(data)-1])
+}
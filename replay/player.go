@@ -0,0 +1,207 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/taskfile"
+)
+
+// errUnrecorded is returned to the pipeline for a call the recording
+// has no answer for.
+var errUnrecorded = errors.New("call not in recording")
+
+// Report is the outcome of a replay.
+type Report struct {
+	// Result and Err are what the replayed job returned.
+	Result jobmanager.JobResult
+	Err    error
+
+	// Diffs describes each way the replay differed from the
+	// recording. Empty when the pipeline behaved as recorded.
+	Diffs []string
+}
+
+// OK reports whether the replay matched the recording.
+func (r *Report) OK() bool {
+	return len(r.Diffs) == 0
+}
+
+// Replay runs the recording's job on a pipeline built from cfg whose
+// dependencies answer from the recording, with workspaces in a
+// temporary directory that is removed afterwards. cfg should match
+// the recorded run; its Agents are replaced by the recorded agent
+// sessions. Optional dependencies that reach other services (e.g.,
+// Links, RepoIndex, Licenses) are not recorded and should be nil.
+// taskWriter writes task files as in the recorded run. Returns an
+// error only if the replay cannot be set up; a job failure is part
+// of the report.
+func Replay(ctx context.Context, rec *Recording, cfg executor.Config, taskWriter taskfile.Writer, logger *zap.Logger) (*Report, error) {
+	base, err := os.MkdirTemp("", "replay-")
+	if err != nil {
+		return nil, fmt.Errorf("create replay workspace directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(base) }()
+
+	p := &player{base: base, calls: rec.Calls, used: make([]bool, len(rec.Calls))}
+	cfg.Agents = nil
+	if len(rec.Agents) > 0 {
+		cfg.Agents = make(map[string]executor.AgentRunner, len(rec.Agents))
+		for _, provider := range rec.Agents {
+			cfg.Agents[provider] = replayAgent{p: p, provider: provider}
+		}
+	}
+	pipeline, err := newPipeline(cfg, Deps{
+		Tracker:    replayTracker{p: p},
+		Git:        replayGit{p: p},
+		Containers: replayContainers{p: p},
+		Workspaces: replayWorkspaces{p: p},
+		TaskWriter: taskWriter,
+		Projects:   replayProjects{p: p},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// The job is as old as it was when recorded, so that the pipeline
+	// makes the same choices about, e.g., the work item snapshot.
+	job := rec.Job
+	shift := time.Since(rec.RecordedAt)
+	if !job.CreatedAt.IsZero() {
+		job.CreatedAt = job.CreatedAt.Add(shift)
+	}
+	if !job.StartedAt.IsZero() {
+		job.StartedAt = job.StartedAt.Add(shift)
+	}
+
+	result, jobErr := pipeline.Execute(ctx, &job)
+
+	report := &Report{Result: result, Err: jobErr, Diffs: p.diffs}
+	for i, c := range rec.Calls {
+		if !p.used[i] {
+			report.Diffs = append(report.Diffs, fmt.Sprintf("recorded call %s.%s%s was not made",
+				c.Service, c.Method, compact(c.Args)))
+		}
+	}
+	if result != rec.Result {
+		report.Diffs = append(report.Diffs, fmt.Sprintf("job result %+v, recorded %+v", result, rec.Result))
+	}
+	var errText string
+	if jobErr != nil {
+		errText = strings.ReplaceAll(jobErr.Error(), base, workspacesVar)
+	}
+	if errText != rec.Error {
+		report.Diffs = append(report.Diffs, fmt.Sprintf("job error %q, recorded %q", errText, rec.Error))
+	}
+	return report, nil
+}
+
+// player answers dependency calls from a recording.
+type player struct {
+	base string
+
+	mu    sync.Mutex
+	calls []Call
+	used  []bool
+	diffs []string
+}
+
+// replay answers a call with the first unused recorded call of the
+// same method, preferring one with equal arguments. It decodes the
+// recorded results into results, restores the workspace files saved
+// with the call, and returns the recorded error.
+func (p *player) replay(service, method string, args []any, results ...any) error {
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("encode %s.%s arguments: %w", service, method, err)
+	}
+	encoded = normalizeArgs(relocate(encoded, p.base, workspacesVar))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.match(service, method, encoded)
+	if i < 0 {
+		p.diffs = append(p.diffs, fmt.Sprintf("unrecorded call %s.%s%s", service, method, encoded))
+		return fmt.Errorf("%s.%s: %w", service, method, errUnrecorded)
+	}
+	c := p.calls[i]
+	p.used[i] = true
+	if recorded := normalizeArgs(c.Args); !bytes.Equal(recorded, encoded) {
+		p.diffs = append(p.diffs, fmt.Sprintf("%s.%s called with %s, recorded %s", service, method, encoded, recorded))
+	}
+
+	if len(results) > 0 && len(c.Results) > 0 {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(relocate(c.Results, workspacesVar, p.base), &raw); err != nil {
+			return fmt.Errorf("decode recorded %s.%s results: %w", service, method, err)
+		}
+		for j := 0; j < len(results) && j < len(raw); j++ {
+			if err := json.Unmarshal(raw[j], results[j]); err != nil {
+				return fmt.Errorf("decode recorded %s.%s results: %w", service, method, err)
+			}
+		}
+	}
+	if c.Dir != "" {
+		dir := strings.Replace(c.Dir, workspacesVar, p.base, 1)
+		if err := restore(dir, c.Files); err != nil {
+			return fmt.Errorf("restore workspace files of %s.%s: %w", service, method, err)
+		}
+	}
+	if c.Error == nil {
+		return nil
+	}
+	e := *c.Error
+	e.Message = strings.ReplaceAll(e.Message, workspacesVar, p.base)
+	return e.err()
+}
+
+// match returns the index of the recorded call to answer with, or -1.
+// Must be called with p.mu held.
+func (p *player) match(service, method string, args []byte) int {
+	first := -1
+	for i, c := range p.calls {
+		if p.used[i] || c.Service != service || c.Method != method {
+			continue
+		}
+		if bytes.Equal(normalizeArgs(c.Args), args) {
+			return i
+		}
+		if first < 0 {
+			first = i
+		}
+	}
+	return first
+}
+
+// timestampPattern matches RFC 3339 timestamps, such as the attempt
+// time in status comments.
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// normalizeArgs returns JSON-encoded arguments in the form in which
+// recorded and replayed arguments are compared: compacted, with
+// timestamps masked since they differ between runs.
+func normalizeArgs(data []byte) []byte {
+	return timestampPattern.ReplaceAll(compact(data), []byte("<time>"))
+}
+
+// compact removes insignificant space from JSON.
+func compact(data []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,551 @@
+package replay
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/tracker"
+	"jira-ai-issue-solver/workspace"
+)
+
+// Compile-time checks that the recording wrappers implement the
+// pipeline's dependency interfaces.
+var (
+	_ tracker.IssueTracker     = recordingTracker{}
+	_ executor.GitService      = recordingGit{}
+	_ container.Manager        = recordingContainers{}
+	_ workspace.Manager        = recordingWorkspaces{}
+	_ executor.ProjectResolver = recordingProjects{}
+	_ executor.AgentRunner     = recordingAgent{}
+)
+
+// okDir returns dir when the call that changed it succeeded, so that
+// its files are saved with the call.
+func okDir(err error, dir string) string {
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// redactConfig returns a copy of cfg without environment values.
+func redactConfig(cfg *container.Config) *container.Config {
+	if cfg == nil {
+		return nil
+	}
+	c := *cfg
+	c.Env = redactEnv(cfg.Env)
+	return &c
+}
+
+// redactOverride returns a copy of o without environment values.
+func redactOverride(o *container.SettingsOverride) *container.SettingsOverride {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	c.Env = redactEnv(o.Env)
+	return &c
+}
+
+// redactEnv replaces the values of env with "redacted".
+func redactEnv(env map[string]string) map[string]string {
+	if env == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(env))
+	for name := range env {
+		redacted[name] = "redacted"
+	}
+	return redacted
+}
+
+// envNames returns the sorted names of env.
+func envNames(env map[string]string) []string {
+	return slices.Sorted(maps.Keys(env))
+}
+
+// agentArgs returns the recorded arguments of an agent session. The
+// callbacks and transcript writer are not recorded.
+func agentArgs(provider string, req agent.Request) []any {
+	return []any{provider, req.Dir, req.Prompt, req.Model, req.MaxOutputBytes}
+}
+
+type recordingTracker struct {
+	s       *session
+	tracker tracker.IssueTracker
+}
+
+type recordingGit struct {
+	s   *session
+	git executor.GitService
+}
+
+type recordingContainers struct {
+	s          *session
+	containers container.Manager
+}
+
+type recordingWorkspaces struct {
+	s          *session
+	workspaces workspace.Manager
+}
+
+type recordingProjects struct {
+	s        *session
+	projects executor.ProjectResolver
+}
+
+type recordingAgent struct {
+	s        *session
+	provider string
+	runner   executor.AgentRunner
+}
+
+// SearchWorkItemPages records the pages delivered to fn.
+func (w recordingTracker) SearchWorkItemPages(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error {
+	var pages [][]models.WorkItem
+	err := w.tracker.SearchWorkItemPages(criteria, func(page []models.WorkItem) error {
+		pages = append(pages, page)
+		return fn(page)
+	})
+	w.s.record("tracker", "SearchWorkItemPages", []any{criteria}, "", err, pages)
+	return err
+}
+
+func (w recordingTracker) SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+	result, err := w.tracker.SearchWorkItems(criteria)
+	w.s.record("tracker", "SearchWorkItems", []any{criteria}, "", err, result)
+	return result, err
+}
+
+func (w recordingTracker) GetWorkItem(key string) (*models.WorkItem, error) {
+	result, err := w.tracker.GetWorkItem(key)
+	w.s.record("tracker", "GetWorkItem", []any{key}, "", err, result)
+	return result, err
+}
+
+func (w recordingTracker) TransitionStatus(key, status string) error {
+	err := w.tracker.TransitionStatus(key, status)
+	w.s.record("tracker", "TransitionStatus", []any{key, status}, "", err)
+	return err
+}
+
+func (w recordingTracker) AddComment(key, body string) error {
+	err := w.tracker.AddComment(key, body)
+	w.s.record("tracker", "AddComment", []any{key, body}, "", err)
+	return err
+}
+
+func (w recordingTracker) GetComments(key string) ([]models.Comment, error) {
+	result, err := w.tracker.GetComments(key)
+	w.s.record("tracker", "GetComments", []any{key}, "", err, result)
+	return result, err
+}
+
+func (w recordingTracker) UpdateComment(key, commentID, body string) error {
+	err := w.tracker.UpdateComment(key, commentID, body)
+	w.s.record("tracker", "UpdateComment", []any{key, commentID, body}, "", err)
+	return err
+}
+
+func (w recordingTracker) DeleteComment(key, commentID string) error {
+	err := w.tracker.DeleteComment(key, commentID)
+	w.s.record("tracker", "DeleteComment", []any{key, commentID}, "", err)
+	return err
+}
+
+func (w recordingTracker) AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error {
+	err := w.tracker.AddWorklog(key, started, timeSpent, comment)
+	w.s.record("tracker", "AddWorklog", []any{key, comment}, "", err)
+	return err
+}
+
+func (w recordingTracker) AddAttachment(key, filename string, content []byte) error {
+	err := w.tracker.AddAttachment(key, filename, content)
+	w.s.record("tracker", "AddAttachment", []any{key, filename, content}, "", err)
+	return err
+}
+
+func (w recordingTracker) AddLabel(key, label string) error {
+	err := w.tracker.AddLabel(key, label)
+	w.s.record("tracker", "AddLabel", []any{key, label}, "", err)
+	return err
+}
+
+func (w recordingTracker) RemoveLabel(key, label string) error {
+	err := w.tracker.RemoveLabel(key, label)
+	w.s.record("tracker", "RemoveLabel", []any{key, label}, "", err)
+	return err
+}
+
+func (w recordingTracker) SetFieldValue(key, field, value string) error {
+	err := w.tracker.SetFieldValue(key, field, value)
+	w.s.record("tracker", "SetFieldValue", []any{key, field, value}, "", err)
+	return err
+}
+
+func (w recordingTracker) LinkWorkItems(key, otherKey, linkType string) error {
+	err := w.tracker.LinkWorkItems(key, otherKey, linkType)
+	w.s.record("tracker", "LinkWorkItems", []any{key, otherKey, linkType}, "", err)
+	return err
+}
+
+func (w recordingTracker) AssignWorkItem(key, accountID string) error {
+	err := w.tracker.AssignWorkItem(key, accountID)
+	w.s.record("tracker", "AssignWorkItem", []any{key, accountID}, "", err)
+	return err
+}
+
+func (w recordingTracker) GetFieldValue(key, field string) (string, error) {
+	result, err := w.tracker.GetFieldValue(key, field)
+	w.s.record("tracker", "GetFieldValue", []any{key, field}, "", err, result)
+	return result, err
+}
+
+func (w recordingTracker) DownloadAttachment(url string) ([]byte, error) {
+	result, err := w.tracker.DownloadAttachment(url)
+	w.s.record("tracker", "DownloadAttachment", []any{url}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) SyncFork(forkOwner, repo, branch string) error {
+	err := w.git.SyncFork(forkOwner, repo, branch)
+	w.s.record("git", "SyncFork", []any{forkOwner, repo, branch}, "", err)
+	return err
+}
+
+func (w recordingGit) CreateBranch(dir, name, baseBranch string) error {
+	err := w.git.CreateBranch(dir, name, baseBranch)
+	w.s.record("git", "CreateBranch", []any{dir, name, baseBranch}, okDir(err, dir), err)
+	return err
+}
+
+func (w recordingGit) SwitchBranch(dir, name string) error {
+	err := w.git.SwitchBranch(dir, name)
+	w.s.record("git", "SwitchBranch", []any{dir, name}, okDir(err, dir), err)
+	return err
+}
+
+func (w recordingGit) RemoteBranchExists(owner, repo, branch string) (bool, error) {
+	result, err := w.git.RemoteBranchExists(owner, repo, branch)
+	w.s.record("git", "RemoteBranchExists", []any{owner, repo, branch}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) DeleteRemoteBranch(owner, repo, branch string) error {
+	err := w.git.DeleteRemoteBranch(owner, repo, branch)
+	w.s.record("git", "DeleteRemoteBranch", []any{owner, repo, branch}, "", err)
+	return err
+}
+
+func (w recordingGit) HasChanges(dir, baseBranch string) (bool, error) {
+	result, err := w.git.HasChanges(dir, baseBranch)
+	w.s.record("git", "HasChanges", []any{dir, baseBranch}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) DiffStat(dir, baseBranch string) (models.DiffStat, error) {
+	result, err := w.git.DiffStat(dir, baseBranch)
+	w.s.record("git", "DiffStat", []any{dir, baseBranch}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) ChangedFiles(dir, baseBranch string) ([]string, error) {
+	result, err := w.git.ChangedFiles(dir, baseBranch)
+	w.s.record("git", "ChangedFiles", []any{dir, baseBranch}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) DeletedFiles(dir, baseBranch string) ([]string, error) {
+	result, err := w.git.DeletedFiles(dir, baseBranch)
+	w.s.record("git", "DeletedFiles", []any{dir, baseBranch}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) Diff(dir, baseBranch string) (string, error) {
+	result, err := w.git.Diff(dir, baseBranch)
+	w.s.record("git", "Diff", []any{dir, baseBranch}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) BaseFile(dir, baseBranch, path string) ([]byte, bool, error) {
+	r0, r1, err := w.git.BaseFile(dir, baseBranch, path)
+	w.s.record("git", "BaseFile", []any{dir, baseBranch, path}, "", err, r0, r1)
+	return r0, r1, err
+}
+
+func (w recordingGit) CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error) {
+	result, err := w.git.CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch, coAuthor, importExcludes, skipFileGuardrail...)
+	w.s.record("git", "CommitChanges", []any{upstreamOwner, owner, repo, branch, message, dir, baseBranch, coAuthor, importExcludes, skipFileGuardrail}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) StripRemoteAuth(dir string) error {
+	err := w.git.StripRemoteAuth(dir)
+	w.s.record("git", "StripRemoteAuth", []any{dir}, "", err)
+	return err
+}
+
+func (w recordingGit) RestoreRemoteAuth(dir, owner, repo string) error {
+	err := w.git.RestoreRemoteAuth(dir, owner, repo)
+	w.s.record("git", "RestoreRemoteAuth", []any{dir, owner, repo}, "", err)
+	return err
+}
+
+func (w recordingGit) FetchRemote(dir string) error {
+	err := w.git.FetchRemote(dir)
+	w.s.record("git", "FetchRemote", []any{dir}, "", err)
+	return err
+}
+
+func (w recordingGit) SyncWithRemote(dir, branch string, importExcludes []string) error {
+	err := w.git.SyncWithRemote(dir, branch, importExcludes)
+	w.s.record("git", "SyncWithRemote", []any{dir, branch, importExcludes}, okDir(err, dir), err)
+	return err
+}
+
+func (w recordingGit) CreatePR(params models.PRParams) (*models.PR, error) {
+	result, err := w.git.CreatePR(params)
+	w.s.record("git", "CreatePR", []any{params}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) GetPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	result, err := w.git.GetPRForBranch(owner, repo, head)
+	w.s.record("git", "GetPRForBranch", []any{owner, repo, head}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	result, err := w.git.GetMergedPRForBranch(owner, repo, head)
+	w.s.record("git", "GetMergedPRForBranch", []any{owner, repo, head}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	result, err := w.git.GetClosedPRForBranch(owner, repo, head)
+	w.s.record("git", "GetClosedPRForBranch", []any{owner, repo, head}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) CountOpenPRs(owner, repo, branchPrefix string) (int, error) {
+	result, err := w.git.CountOpenPRs(owner, repo, branchPrefix)
+	w.s.record("git", "CountOpenPRs", []any{owner, repo, branchPrefix}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error) {
+	result, err := w.git.GetPRComments(owner, repo, number, since)
+	w.s.record("git", "GetPRComments", []any{owner, repo, number}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) ReplyToComment(owner, repo string, prNumber int, commentID int64, body string) error {
+	err := w.git.ReplyToComment(owner, repo, prNumber, commentID, body)
+	w.s.record("git", "ReplyToComment", []any{owner, repo, prNumber, commentID, body}, "", err)
+	return err
+}
+
+func (w recordingGit) PostIssueComment(owner, repo string, prNumber int, body string) error {
+	err := w.git.PostIssueComment(owner, repo, prNumber, body)
+	w.s.record("git", "PostIssueComment", []any{owner, repo, prNumber, body}, "", err)
+	return err
+}
+
+func (w recordingGit) ListIssueComments(owner, repo string, prNumber int) ([]models.IssueComment, error) {
+	result, err := w.git.ListIssueComments(owner, repo, prNumber)
+	w.s.record("git", "ListIssueComments", []any{owner, repo, prNumber}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) UpdateIssueComment(owner, repo string, commentID int64, body string) error {
+	err := w.git.UpdateIssueComment(owner, repo, commentID, body)
+	w.s.record("git", "UpdateIssueComment", []any{owner, repo, commentID, body}, "", err)
+	return err
+}
+
+func (w recordingGit) AddCommentReaction(owner, repo string, comment models.PRComment, reaction string) error {
+	err := w.git.AddCommentReaction(owner, repo, comment, reaction)
+	w.s.record("git", "AddCommentReaction", []any{owner, repo, comment, reaction}, "", err)
+	return err
+}
+
+func (w recordingGit) MergeBase(dir, branch, fetchURL string) ([]string, error) {
+	result, err := w.git.MergeBase(dir, branch, fetchURL)
+	w.s.record("git", "MergeBase", []any{dir, branch, fetchURL}, okDir(err, dir), err, result)
+	return result, err
+}
+
+func (w recordingGit) CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error) {
+	result, err := w.git.CherryPickPR(dir, fetchURL, prNumber, mergeCommitSHA)
+	w.s.record("git", "CherryPickPR", []any{dir, fetchURL, prNumber, mergeCommitSHA}, okDir(err, dir), err, result)
+	return result, err
+}
+
+func (w recordingGit) RebaseOnRemote(dir, branch, base string) ([]string, error) {
+	result, err := w.git.RebaseOnRemote(dir, branch, base)
+	w.s.record("git", "RebaseOnRemote", []any{dir, branch, base}, okDir(err, dir), err, result)
+	return result, err
+}
+
+func (w recordingGit) CloneImport(url, destDir, ref string) error {
+	err := w.git.CloneImport(url, destDir, ref)
+	w.s.record("git", "CloneImport", []any{url, destDir, ref}, okDir(err, destDir), err)
+	return err
+}
+
+func (w recordingGit) ListCheckRunsForRef(owner, repo, ref string) ([]models.CheckRunFailure, bool, error) {
+	r0, r1, err := w.git.ListCheckRunsForRef(owner, repo, ref)
+	w.s.record("git", "ListCheckRunsForRef", []any{owner, repo, ref}, "", err, r0, r1)
+	return r0, r1, err
+}
+
+func (w recordingGit) ListCheckRunAnnotations(owner, repo string, checkRunID int64) ([]models.CheckAnnotation, error) {
+	result, err := w.git.ListCheckRunAnnotations(owner, repo, checkRunID)
+	w.s.record("git", "ListCheckRunAnnotations", []any{owner, repo, checkRunID}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) GetFailedJobLogs(owner, repo, headSHA string, maxBytesPerStep int) (map[string][]models.FailedStep, error) {
+	result, err := w.git.GetFailedJobLogs(owner, repo, headSHA, maxBytesPerStep)
+	w.s.record("git", "GetFailedJobLogs", []any{owner, repo, headSHA, maxBytesPerStep}, "", err, result)
+	return result, err
+}
+
+func (w recordingGit) AddPRLabel(owner, repo string, number int, label string) error {
+	err := w.git.AddPRLabel(owner, repo, number, label)
+	w.s.record("git", "AddPRLabel", []any{owner, repo, number, label}, "", err)
+	return err
+}
+
+func (w recordingGit) RemovePRLabel(owner, repo string, number int, label string) error {
+	err := w.git.RemovePRLabel(owner, repo, number, label)
+	w.s.record("git", "RemovePRLabel", []any{owner, repo, number, label}, "", err)
+	return err
+}
+
+func (w recordingGit) UpdatePRBody(owner, repo string, number int, body string) error {
+	err := w.git.UpdatePRBody(owner, repo, number, body)
+	w.s.record("git", "UpdatePRBody", []any{owner, repo, number, body}, "", err)
+	return err
+}
+func (w recordingGit) EnablePRAutoMerge(owner, repo string, number int, method string) error {
+	err := w.git.EnablePRAutoMerge(owner, repo, number, method)
+	w.s.record("git", "EnablePRAutoMerge", []any{owner, repo, number, method}, "", err)
+	return err
+}
+func (w recordingGit) CreateCheckRun(owner, repo string, run models.CheckRun) error {
+	err := w.git.CreateCheckRun(owner, repo, run)
+	w.s.record("git", "CreateCheckRun", []any{owner, repo, run}, "", err)
+	return err
+}
+func (w recordingContainers) ResolveConfig(repoDir string, projectOverride *container.SettingsOverride) (*container.Config, error) {
+	cfg, err := w.containers.ResolveConfig(repoDir, projectOverride)
+	w.s.record("containers", "ResolveConfig", []any{repoDir, redactOverride(projectOverride)}, "", err, redactConfig(cfg))
+	return cfg, err
+}
+
+func (w recordingContainers) Start(ctx context.Context, cfg *container.Config, workspaceDir, ticketKey string, env map[string]string) (*container.Container, error) {
+	ctr, err := w.containers.Start(ctx, cfg, workspaceDir, ticketKey, env)
+	w.s.setContainerDir(ctr, workspaceDir)
+	w.s.record("containers", "Start", []any{redactConfig(cfg), workspaceDir, ticketKey, envNames(env)}, "", err, ctr)
+	return ctr, err
+}
+
+// Exec saves the files of the container's workspace after the
+// command, which is typically an AI session.
+func (w recordingContainers) Exec(ctx context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+	output, exitCode, err := w.containers.Exec(ctx, ctr, cmd)
+	w.s.record("containers", "Exec", []any{ctr, cmd}, w.s.containerDir(ctr), err, output, exitCode)
+	return output, exitCode, err
+}
+
+func (w recordingContainers) Stop(ctx context.Context, ctr *container.Container) error {
+	err := w.containers.Stop(ctx, ctr)
+	w.s.record("containers", "Stop", []any{ctr}, "", err)
+	return err
+}
+
+func (w recordingContainers) CleanupOrphans(ctx context.Context, prefix string) error {
+	err := w.containers.CleanupOrphans(ctx, prefix)
+	w.s.record("containers", "CleanupOrphans", []any{prefix}, "", err)
+	return err
+}
+
+func (w recordingWorkspaces) Create(ticketKey, repoURL string, sparsePaths []string) (string, error) {
+	path, err := w.workspaces.Create(ticketKey, repoURL, sparsePaths)
+	w.s.record("workspaces", "Create", []any{ticketKey, repoURL, sparsePaths}, okDir(err, path), err, path)
+	return path, err
+}
+
+func (w recordingWorkspaces) CreateMultiRepo(ticketKey string, repos []workspace.RepoEntry, rootRepoURL string) (string, error) {
+	path, err := w.workspaces.CreateMultiRepo(ticketKey, repos, rootRepoURL)
+	w.s.record("workspaces", "CreateMultiRepo", []any{ticketKey, repos, rootRepoURL}, okDir(err, path), err, path)
+	return path, err
+}
+
+func (w recordingWorkspaces) Find(ticketKey string) (string, bool) {
+	path, found := w.workspaces.Find(ticketKey)
+	var dir string
+	if found {
+		dir = path
+	}
+	w.s.record("workspaces", "Find", []any{ticketKey}, dir, nil, path, found)
+	return path, found
+}
+
+func (w recordingWorkspaces) FindOrCreate(ticketKey, repoURL string, sparsePaths []string) (string, bool, error) {
+	path, reused, err := w.workspaces.FindOrCreate(ticketKey, repoURL, sparsePaths)
+	w.s.record("workspaces", "FindOrCreate", []any{ticketKey, repoURL, sparsePaths}, okDir(err, path), err, path, reused)
+	return path, reused, err
+}
+
+func (w recordingWorkspaces) FindOrCreateMultiRepo(ticketKey string, repos []workspace.RepoEntry, rootRepoURL string) (string, bool, error) {
+	path, reused, err := w.workspaces.FindOrCreateMultiRepo(ticketKey, repos, rootRepoURL)
+	w.s.record("workspaces", "FindOrCreateMultiRepo", []any{ticketKey, repos, rootRepoURL}, okDir(err, path), err, path, reused)
+	return path, reused, err
+}
+
+func (w recordingWorkspaces) Cleanup(ticketKey string) error {
+	err := w.workspaces.Cleanup(ticketKey)
+	w.s.record("workspaces", "Cleanup", []any{ticketKey}, "", err)
+	return err
+}
+
+func (w recordingWorkspaces) CleanupStale(maxAge time.Duration) (int, error) {
+	n, err := w.workspaces.CleanupStale(maxAge)
+	w.s.record("workspaces", "CleanupStale", []any{maxAge}, "", err, n)
+	return n, err
+}
+
+func (w recordingWorkspaces) CleanupByFilter(shouldRemove func(ticketKey string) bool) (int, error) {
+	n, err := w.workspaces.CleanupByFilter(shouldRemove)
+	w.s.record("workspaces", "CleanupByFilter", nil, "", err, n)
+	return n, err
+}
+
+func (w recordingWorkspaces) List() ([]workspace.Info, error) {
+	infos, err := w.workspaces.List()
+	w.s.record("workspaces", "List", nil, "", err, infos)
+	return infos, err
+}
+
+func (w recordingProjects) ResolveProject(workItem models.WorkItem) (*models.ProjectSettings, error) {
+	settings, err := w.projects.ResolveProject(workItem)
+	w.s.record("projects", "ResolveProject", []any{workItem}, "", err, settings)
+	return settings, err
+}
+
+// Run saves the files of the session's workspace after the session.
+func (w recordingAgent) Run(ctx context.Context, req agent.Request) (agent.Result, error) {
+	result, err := w.runner.Run(ctx, req)
+	w.s.record("agent", "Run", agentArgs(w.provider, req), req.Dir, err, result)
+	return result, err
+}
@@ -0,0 +1,199 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/taskfile"
+	"jira-ai-issue-solver/tracker"
+	"jira-ai-issue-solver/workspace"
+)
+
+// Deps are the pipeline dependencies whose calls are recorded and
+// replayed, and the task file writer, which runs for real in both.
+type Deps struct {
+	Tracker    tracker.IssueTracker
+	Git        executor.GitService
+	Containers container.Manager
+	Workspaces workspace.Manager
+	TaskWriter taskfile.Writer
+	Projects   executor.ProjectResolver
+}
+
+// Recorder runs jobs on pipelines whose dependency calls are recorded,
+// and saves each job's [Recording] to a directory.
+type Recorder struct {
+	cfg          executor.Config
+	deps         Deps
+	workspaceDir string
+	dir          string
+	logger       *zap.Logger
+}
+
+// NewRecorder creates a Recorder that runs jobs on pipelines built
+// from cfg and deps and saves recordings to dir, which is created if
+// needed. workspaceDir is the workspace base directory, which
+// recordings refer to as $WORKSPACES. Returns an error if any
+// parameter is invalid.
+func NewRecorder(cfg executor.Config, deps Deps, workspaceDir, dir string, logger *zap.Logger) (*Recorder, error) {
+	if workspaceDir == "" {
+		return nil, errors.New("workspace directory must not be empty")
+	}
+	if dir == "" {
+		return nil, errors.New("recording directory must not be empty")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create recording directory: %w", err)
+	}
+	// Fail at startup rather than on the first job.
+	if _, err := newPipeline(cfg, deps, logger); err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		cfg:          cfg,
+		deps:         deps,
+		workspaceDir: filepath.Clean(workspaceDir),
+		dir:          dir,
+		logger:       logger,
+	}, nil
+}
+
+// Execute runs the job and saves its recording as
+// <ticket>-<job id>.json. Failing to save a recording is logged and
+// does not affect the job. Matches [jobmanager.ExecuteFunc].
+func (r *Recorder) Execute(ctx context.Context, job *jobmanager.Job) (jobmanager.JobResult, error) {
+	s := &session{
+		base: r.workspaceDir,
+		rec:  &Recording{Version: formatVersion, RecordedAt: time.Now(), Job: *job},
+		dirs: make(map[string]string),
+	}
+	s.rec.Job.Result = nil
+	s.rec.Job.Err = nil
+
+	cfg := r.cfg
+	if len(cfg.Agents) > 0 {
+		cfg.Agents = make(map[string]executor.AgentRunner, len(r.cfg.Agents))
+		for provider, runner := range r.cfg.Agents {
+			cfg.Agents[provider] = recordingAgent{s: s, provider: provider, runner: runner}
+			s.rec.Agents = append(s.rec.Agents, provider)
+		}
+		sort.Strings(s.rec.Agents)
+	}
+	deps := Deps{
+		Tracker:    recordingTracker{s: s, tracker: r.deps.Tracker},
+		Git:        recordingGit{s: s, git: r.deps.Git},
+		Containers: recordingContainers{s: s, containers: r.deps.Containers},
+		Workspaces: recordingWorkspaces{s: s, workspaces: r.deps.Workspaces},
+		TaskWriter: r.deps.TaskWriter,
+		Projects:   recordingProjects{s: s, projects: r.deps.Projects},
+	}
+	p, err := newPipeline(cfg, deps, r.logger)
+	if err != nil {
+		return jobmanager.JobResult{}, err
+	}
+
+	result, err := p.Execute(ctx, job)
+
+	s.rec.Result = result
+	if err != nil {
+		s.rec.Error = s.relocateText(err.Error())
+	}
+	path := filepath.Join(r.dir, job.TicketKey+"-"+job.ID+".json")
+	if saveErr := s.rec.Save(path); saveErr != nil {
+		r.logger.Warn("Failed to save job recording",
+			zap.String("ticket", job.TicketKey),
+			zap.String("path", path),
+			zap.Error(saveErr))
+	} else {
+		r.logger.Debug("Job recording saved",
+			zap.String("ticket", job.TicketKey),
+			zap.String("path", path),
+			zap.Int("calls", len(s.rec.Calls)))
+	}
+	return result, err
+}
+
+func newPipeline(cfg executor.Config, deps Deps, logger *zap.Logger) (*executor.Pipeline, error) {
+	return executor.NewPipeline(cfg, deps.Tracker, deps.Git, deps.Containers,
+		deps.Workspaces, deps.TaskWriter, deps.Projects, logger)
+}
+
+// session collects the calls of one job.
+type session struct {
+	base string
+
+	mu   sync.Mutex
+	rec  *Recording
+	dirs map[string]string // container ID -> workspace directory
+}
+
+// record appends a call. When dir is non-empty, the workspace files
+// in dir after the call are saved with it.
+func (s *session) record(service, method string, args []any, dir string, err error, results ...any) {
+	c := Call{
+		Service: service,
+		Method:  method,
+		Args:    s.encode(args),
+		Error:   newCallError(err),
+	}
+	if c.Error != nil {
+		c.Error.Message = s.relocateText(c.Error.Message)
+	}
+	if len(results) > 0 {
+		c.Results = s.encode(results)
+	}
+	if dir != "" {
+		c.Dir = s.relocateText(dir)
+		c.Files = snapshot(dir)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rec.Calls = append(s.rec.Calls, c)
+}
+
+func (s *session) encode(values []any) []byte {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil
+	}
+	return relocate(data, s.base, workspacesVar)
+}
+
+func (s *session) relocateText(text string) string {
+	return strings.ReplaceAll(text, s.base, workspacesVar)
+}
+
+func (s *session) setContainerDir(ctr *container.Container, dir string) {
+	if ctr == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirs[ctr.ID] = dir
+}
+
+func (s *session) containerDir(ctr *container.Container) string {
+	if ctr == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dirs[ctr.ID]
+}
@@ -0,0 +1,173 @@
+// Package replay records the external interactions of processed
+// tickets and re-executes the pipeline against a recording, so that
+// changes to the pipeline's logic can be regression-tested end to end
+// without Jira, GitHub, a container runtime, or an AI provider.
+//
+// # Recording
+//
+// A [Recorder] runs each job on a pipeline whose dependencies are
+// wrapped: every call to the issue tracker, git service, container
+// manager, workspace manager, project resolver, and in-process agent
+// runners is saved with its arguments and results. After calls that
+// change the workspace (clones, checkouts, AI sessions) the files
+// under .ai-bot and .ai-session are saved too, so that a replay sees
+// the repository configuration and AI output the pipeline read. Each
+// job is written to its own [Recording] file.
+//
+// # Replay
+//
+// [Replay] runs a recording's job on a pipeline whose dependencies
+// answer from the recording. A call is matched to the first unused
+// recorded call of the same method, preferring one with equal
+// arguments, so that concurrent calls may interleave differently;
+// timestamps in arguments are not compared, since they differ between
+// runs. The returned [Report] lists calls made with different
+// arguments, calls the recording has no answer for, recorded calls
+// that were not made, and a differing job result. A change in
+// pipeline behavior therefore shows up as a non-empty report.
+//
+// Workspace paths are stored relative to the workspace base directory,
+// and only the names of container environment variables are kept, so
+// API keys are not written to recordings. Recordings do contain
+// ticket content and AI output and should be handled like them.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/services"
+)
+
+// formatVersion is the version of the recording file format.
+const formatVersion = 1
+
+// workspacesVar stands for the workspace base directory in recorded
+// arguments and results.
+const workspacesVar = "$WORKSPACES"
+
+// Recording is the external interactions of one job.
+type Recording struct {
+	Version int `json:"version"`
+
+	// RecordedAt is when the job started. Replay shifts the job's
+	// timestamps by the time since then.
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Job is the job as the pipeline received it.
+	Job jobmanager.Job `json:"job"`
+
+	// Agents lists the providers run by in-process agent runners.
+	Agents []string `json:"agents,omitempty"`
+
+	// Calls lists the dependency calls in the order they returned.
+	Calls []Call `json:"calls"`
+
+	// Result and Error are the job's outcome.
+	Result jobmanager.JobResult `json:"result"`
+	Error  string               `json:"error,omitempty"`
+}
+
+// Call is one recorded dependency call.
+type Call struct {
+	// Service is the dependency called: "tracker", "git",
+	// "containers", "workspaces", "projects", or "agent".
+	Service string `json:"service"`
+	Method  string `json:"method"`
+
+	// Args and Results are the JSON-encoded arguments and non-error
+	// results, in order.
+	Args    json.RawMessage `json:"args,omitempty"`
+	Results json.RawMessage `json:"results,omitempty"`
+
+	// Error is the error the call returned, if any.
+	Error *CallError `json:"error,omitempty"`
+
+	// Dir is the workspace directory whose .ai-bot and .ai-session
+	// files after the call are held in Files, keyed by slash-separated
+	// path relative to Dir.
+	Dir   string            `json:"dir,omitempty"`
+	Files map[string][]byte `json:"files,omitempty"`
+}
+
+// CallError is a recorded error. Kind names the sentinel error the
+// pipeline tests for with errors.Is, so that replay returns an error
+// that matches it.
+type CallError struct {
+	Message string `json:"message"`
+	Kind    string `json:"kind,omitempty"`
+}
+
+// errorKinds are the sentinel errors preserved by recordings.
+var errorKinds = map[string]error{
+	"no_changes":        services.ErrNoChanges,
+	"merge_conflict":    services.ErrMergeConflict,
+	"canceled":          context.Canceled,
+	"deadline_exceeded": context.DeadlineExceeded,
+	"not_exist":         fs.ErrNotExist,
+}
+
+func newCallError(err error) *CallError {
+	if err == nil {
+		return nil
+	}
+	ce := &CallError{Message: err.Error()}
+	for kind, sentinel := range errorKinds {
+		if errors.Is(err, sentinel) {
+			ce.Kind = kind
+			break
+		}
+	}
+	return ce
+}
+
+// err returns the error to replay.
+func (e *CallError) err() error {
+	if e == nil {
+		return nil
+	}
+	return &replayedError{msg: e.Message, sentinel: errorKinds[e.Kind]}
+}
+
+// replayedError is a recorded error that matches its sentinel.
+type replayedError struct {
+	msg      string
+	sentinel error
+}
+
+func (e *replayedError) Error() string { return e.msg }
+func (e *replayedError) Unwrap() error { return e.sentinel }
+
+// Load reads a recording from a file.
+func Load(path string) (*Recording, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is caller-controlled recording file
+	if err != nil {
+		return nil, fmt.Errorf("read recording: %w", err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("parse recording %s: %w", path, err)
+	}
+	if rec.Version != formatVersion {
+		return nil, fmt.Errorf("recording %s has format version %d, want %d", path, rec.Version, formatVersion)
+	}
+	return &rec, nil
+}
+
+// Save writes the recording to a file.
+func (r *Recording) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode recording: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write recording: %w", err)
+	}
+	return nil
+}
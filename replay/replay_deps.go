@@ -0,0 +1,435 @@
+package replay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"jira-ai-issue-solver/agent"
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/tracker"
+	"jira-ai-issue-solver/workspace"
+)
+
+// Compile-time checks that the replaying dependencies implement the
+// pipeline's dependency interfaces.
+var (
+	_ tracker.IssueTracker     = replayTracker{}
+	_ executor.GitService      = replayGit{}
+	_ container.Manager        = replayContainers{}
+	_ workspace.Manager        = replayWorkspaces{}
+	_ executor.ProjectResolver = replayProjects{}
+	_ executor.AgentRunner     = replayAgent{}
+)
+
+type replayTracker struct{ p *player }
+
+type replayGit struct{ p *player }
+
+type replayContainers struct{ p *player }
+
+type replayWorkspaces struct{ p *player }
+
+type replayProjects struct{ p *player }
+
+type replayAgent struct {
+	p        *player
+	provider string
+}
+
+// SearchWorkItemPages delivers the recorded pages to fn.
+func (w replayTracker) SearchWorkItemPages(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error {
+	var pages [][]models.WorkItem
+	err := w.p.replay("tracker", "SearchWorkItemPages", []any{criteria}, &pages)
+	for _, page := range pages {
+		if fnErr := fn(page); fnErr != nil {
+			return fnErr
+		}
+	}
+	return err
+}
+
+func (w replayTracker) SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+	var result []models.WorkItem
+	err := w.p.replay("tracker", "SearchWorkItems", []any{criteria}, &result)
+	return result, err
+}
+
+func (w replayTracker) GetWorkItem(key string) (*models.WorkItem, error) {
+	var result *models.WorkItem
+	err := w.p.replay("tracker", "GetWorkItem", []any{key}, &result)
+	return result, err
+}
+
+func (w replayTracker) TransitionStatus(key, status string) error {
+	return w.p.replay("tracker", "TransitionStatus", []any{key, status})
+}
+
+func (w replayTracker) AddComment(key, body string) error {
+	return w.p.replay("tracker", "AddComment", []any{key, body})
+}
+
+func (w replayTracker) GetComments(key string) ([]models.Comment, error) {
+	var result []models.Comment
+	err := w.p.replay("tracker", "GetComments", []any{key}, &result)
+	return result, err
+}
+
+func (w replayTracker) UpdateComment(key, commentID, body string) error {
+	return w.p.replay("tracker", "UpdateComment", []any{key, commentID, body})
+}
+
+func (w replayTracker) DeleteComment(key, commentID string) error {
+	return w.p.replay("tracker", "DeleteComment", []any{key, commentID})
+}
+
+func (w replayTracker) AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error {
+	return w.p.replay("tracker", "AddWorklog", []any{key, comment})
+}
+
+func (w replayTracker) AddAttachment(key, filename string, content []byte) error {
+	return w.p.replay("tracker", "AddAttachment", []any{key, filename, content})
+}
+
+func (w replayTracker) AddLabel(key, label string) error {
+	return w.p.replay("tracker", "AddLabel", []any{key, label})
+}
+
+func (w replayTracker) RemoveLabel(key, label string) error {
+	return w.p.replay("tracker", "RemoveLabel", []any{key, label})
+}
+
+func (w replayTracker) SetFieldValue(key, field, value string) error {
+	return w.p.replay("tracker", "SetFieldValue", []any{key, field, value})
+}
+
+func (w replayTracker) LinkWorkItems(key, otherKey, linkType string) error {
+	return w.p.replay("tracker", "LinkWorkItems", []any{key, otherKey, linkType})
+}
+
+func (w replayTracker) AssignWorkItem(key, accountID string) error {
+	return w.p.replay("tracker", "AssignWorkItem", []any{key, accountID})
+}
+
+func (w replayTracker) GetFieldValue(key, field string) (string, error) {
+	var result string
+	err := w.p.replay("tracker", "GetFieldValue", []any{key, field}, &result)
+	return result, err
+}
+
+func (w replayTracker) DownloadAttachment(url string) ([]byte, error) {
+	var result []byte
+	err := w.p.replay("tracker", "DownloadAttachment", []any{url}, &result)
+	return result, err
+}
+
+func (w replayGit) SyncFork(forkOwner, repo, branch string) error {
+	return w.p.replay("git", "SyncFork", []any{forkOwner, repo, branch})
+}
+
+func (w replayGit) CreateBranch(dir, name, baseBranch string) error {
+	return w.p.replay("git", "CreateBranch", []any{dir, name, baseBranch})
+}
+
+func (w replayGit) SwitchBranch(dir, name string) error {
+	return w.p.replay("git", "SwitchBranch", []any{dir, name})
+}
+
+func (w replayGit) RemoteBranchExists(owner, repo, branch string) (bool, error) {
+	var result bool
+	err := w.p.replay("git", "RemoteBranchExists", []any{owner, repo, branch}, &result)
+	return result, err
+}
+
+func (w replayGit) DeleteRemoteBranch(owner, repo, branch string) error {
+	return w.p.replay("git", "DeleteRemoteBranch", []any{owner, repo, branch})
+}
+
+func (w replayGit) HasChanges(dir, baseBranch string) (bool, error) {
+	var result bool
+	err := w.p.replay("git", "HasChanges", []any{dir, baseBranch}, &result)
+	return result, err
+}
+
+func (w replayGit) DiffStat(dir, baseBranch string) (models.DiffStat, error) {
+	var result models.DiffStat
+	err := w.p.replay("git", "DiffStat", []any{dir, baseBranch}, &result)
+	return result, err
+}
+
+func (w replayGit) ChangedFiles(dir, baseBranch string) ([]string, error) {
+	var result []string
+	err := w.p.replay("git", "ChangedFiles", []any{dir, baseBranch}, &result)
+	return result, err
+}
+
+func (w replayGit) DeletedFiles(dir, baseBranch string) ([]string, error) {
+	var result []string
+	err := w.p.replay("git", "DeletedFiles", []any{dir, baseBranch}, &result)
+	return result, err
+}
+
+func (w replayGit) Diff(dir, baseBranch string) (string, error) {
+	var result string
+	err := w.p.replay("git", "Diff", []any{dir, baseBranch}, &result)
+	return result, err
+}
+
+func (w replayGit) BaseFile(dir, baseBranch, path string) ([]byte, bool, error) {
+	var r0 []byte
+	var r1 bool
+	err := w.p.replay("git", "BaseFile", []any{dir, baseBranch, path}, &r0, &r1)
+	return r0, r1, err
+}
+
+func (w replayGit) CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error) {
+	var result string
+	err := w.p.replay("git", "CommitChanges", []any{upstreamOwner, owner, repo, branch, message, dir, baseBranch, coAuthor, importExcludes, skipFileGuardrail}, &result)
+	return result, err
+}
+
+func (w replayGit) StripRemoteAuth(dir string) error {
+	return w.p.replay("git", "StripRemoteAuth", []any{dir})
+}
+
+func (w replayGit) RestoreRemoteAuth(dir, owner, repo string) error {
+	return w.p.replay("git", "RestoreRemoteAuth", []any{dir, owner, repo})
+}
+
+func (w replayGit) FetchRemote(dir string) error {
+	return w.p.replay("git", "FetchRemote", []any{dir})
+}
+
+func (w replayGit) SyncWithRemote(dir, branch string, importExcludes []string) error {
+	return w.p.replay("git", "SyncWithRemote", []any{dir, branch, importExcludes})
+}
+
+func (w replayGit) CreatePR(params models.PRParams) (*models.PR, error) {
+	var result *models.PR
+	err := w.p.replay("git", "CreatePR", []any{params}, &result)
+	return result, err
+}
+
+func (w replayGit) GetPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	var result *models.PRDetails
+	err := w.p.replay("git", "GetPRForBranch", []any{owner, repo, head}, &result)
+	return result, err
+}
+
+func (w replayGit) GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	var result *models.PRDetails
+	err := w.p.replay("git", "GetMergedPRForBranch", []any{owner, repo, head}, &result)
+	return result, err
+}
+
+func (w replayGit) GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	var result *models.PRDetails
+	err := w.p.replay("git", "GetClosedPRForBranch", []any{owner, repo, head}, &result)
+	return result, err
+}
+
+func (w replayGit) CountOpenPRs(owner, repo, branchPrefix string) (int, error) {
+	var result int
+	err := w.p.replay("git", "CountOpenPRs", []any{owner, repo, branchPrefix}, &result)
+	return result, err
+}
+
+func (w replayGit) GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error) {
+	var result []models.PRComment
+	err := w.p.replay("git", "GetPRComments", []any{owner, repo, number}, &result)
+	return result, err
+}
+
+func (w replayGit) ReplyToComment(owner, repo string, prNumber int, commentID int64, body string) error {
+	return w.p.replay("git", "ReplyToComment", []any{owner, repo, prNumber, commentID, body})
+}
+
+func (w replayGit) PostIssueComment(owner, repo string, prNumber int, body string) error {
+	return w.p.replay("git", "PostIssueComment", []any{owner, repo, prNumber, body})
+}
+
+func (w replayGit) ListIssueComments(owner, repo string, prNumber int) ([]models.IssueComment, error) {
+	var result []models.IssueComment
+	err := w.p.replay("git", "ListIssueComments", []any{owner, repo, prNumber}, &result)
+	return result, err
+}
+
+func (w replayGit) UpdateIssueComment(owner, repo string, commentID int64, body string) error {
+	return w.p.replay("git", "UpdateIssueComment", []any{owner, repo, commentID, body})
+}
+
+func (w replayGit) AddCommentReaction(owner, repo string, comment models.PRComment, reaction string) error {
+	return w.p.replay("git", "AddCommentReaction", []any{owner, repo, comment, reaction})
+}
+
+func (w replayGit) MergeBase(dir, branch, fetchURL string) ([]string, error) {
+	var result []string
+	err := w.p.replay("git", "MergeBase", []any{dir, branch, fetchURL}, &result)
+	return result, err
+}
+
+func (w replayGit) CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error) {
+	var result []string
+	err := w.p.replay("git", "CherryPickPR", []any{dir, fetchURL, prNumber, mergeCommitSHA}, &result)
+	return result, err
+}
+
+func (w replayGit) RebaseOnRemote(dir, branch, base string) ([]string, error) {
+	var result []string
+	err := w.p.replay("git", "RebaseOnRemote", []any{dir, branch, base}, &result)
+	return result, err
+}
+
+func (w replayGit) CloneImport(url, destDir, ref string) error {
+	return w.p.replay("git", "CloneImport", []any{url, destDir, ref})
+}
+
+func (w replayGit) ListCheckRunsForRef(owner, repo, ref string) ([]models.CheckRunFailure, bool, error) {
+	var r0 []models.CheckRunFailure
+	var r1 bool
+	err := w.p.replay("git", "ListCheckRunsForRef", []any{owner, repo, ref}, &r0, &r1)
+	return r0, r1, err
+}
+
+func (w replayGit) ListCheckRunAnnotations(owner, repo string, checkRunID int64) ([]models.CheckAnnotation, error) {
+	var result []models.CheckAnnotation
+	err := w.p.replay("git", "ListCheckRunAnnotations", []any{owner, repo, checkRunID}, &result)
+	return result, err
+}
+
+func (w replayGit) GetFailedJobLogs(owner, repo, headSHA string, maxBytesPerStep int) (map[string][]models.FailedStep, error) {
+	var result map[string][]models.FailedStep
+	err := w.p.replay("git", "GetFailedJobLogs", []any{owner, repo, headSHA, maxBytesPerStep}, &result)
+	return result, err
+}
+
+func (w replayGit) AddPRLabel(owner, repo string, number int, label string) error {
+	return w.p.replay("git", "AddPRLabel", []any{owner, repo, number, label})
+}
+
+func (w replayGit) RemovePRLabel(owner, repo string, number int, label string) error {
+	return w.p.replay("git", "RemovePRLabel", []any{owner, repo, number, label})
+}
+
+func (w replayGit) UpdatePRBody(owner, repo string, number int, body string) error {
+	return w.p.replay("git", "UpdatePRBody", []any{owner, repo, number, body})
+}
+func (w replayGit) EnablePRAutoMerge(owner, repo string, number int, method string) error {
+	return w.p.replay("git", "EnablePRAutoMerge", []any{owner, repo, number, method})
+}
+func (w replayGit) CreateCheckRun(owner, repo string, run models.CheckRun) error {
+	return w.p.replay("git", "CreateCheckRun", []any{owner, repo, run})
+}
+func (w replayContainers) ResolveConfig(repoDir string, projectOverride *container.SettingsOverride) (*container.Config, error) {
+	var cfg *container.Config
+	err := w.p.replay("containers", "ResolveConfig", []any{repoDir, redactOverride(projectOverride)}, &cfg)
+	return cfg, err
+}
+
+func (w replayContainers) Start(_ context.Context, cfg *container.Config, workspaceDir, ticketKey string, env map[string]string) (*container.Container, error) {
+	var ctr *container.Container
+	err := w.p.replay("containers", "Start", []any{redactConfig(cfg), workspaceDir, ticketKey, envNames(env)}, &ctr)
+	return ctr, err
+}
+
+func (w replayContainers) Exec(_ context.Context, ctr *container.Container, cmd []string) (string, int, error) {
+	var output string
+	var exitCode int
+	err := w.p.replay("containers", "Exec", []any{ctr, cmd}, &output, &exitCode)
+	return output, exitCode, err
+}
+
+func (w replayContainers) Stop(_ context.Context, ctr *container.Container) error {
+	return w.p.replay("containers", "Stop", []any{ctr})
+}
+
+func (w replayContainers) CleanupOrphans(_ context.Context, prefix string) error {
+	return w.p.replay("containers", "CleanupOrphans", []any{prefix})
+}
+
+func (w replayWorkspaces) Create(ticketKey, repoURL string, sparsePaths []string) (string, error) {
+	var path string
+	err := w.p.replay("workspaces", "Create", []any{ticketKey, repoURL, sparsePaths}, &path)
+	return path, err
+}
+
+func (w replayWorkspaces) CreateMultiRepo(ticketKey string, repos []workspace.RepoEntry, rootRepoURL string) (string, error) {
+	var path string
+	err := w.p.replay("workspaces", "CreateMultiRepo", []any{ticketKey, repos, rootRepoURL}, &path)
+	if err == nil {
+		err = makeRepoDirs(path, repos)
+	}
+	return path, err
+}
+
+func (w replayWorkspaces) Find(ticketKey string) (string, bool) {
+	var path string
+	var found bool
+	_ = w.p.replay("workspaces", "Find", []any{ticketKey}, &path, &found)
+	return path, found
+}
+
+func (w replayWorkspaces) FindOrCreate(ticketKey, repoURL string, sparsePaths []string) (string, bool, error) {
+	var path string
+	var reused bool
+	err := w.p.replay("workspaces", "FindOrCreate", []any{ticketKey, repoURL, sparsePaths}, &path, &reused)
+	return path, reused, err
+}
+
+func (w replayWorkspaces) FindOrCreateMultiRepo(ticketKey string, repos []workspace.RepoEntry, rootRepoURL string) (string, bool, error) {
+	var path string
+	var reused bool
+	err := w.p.replay("workspaces", "FindOrCreateMultiRepo", []any{ticketKey, repos, rootRepoURL}, &path, &reused)
+	if err == nil {
+		err = makeRepoDirs(path, repos)
+	}
+	return path, reused, err
+}
+
+func (w replayWorkspaces) Cleanup(ticketKey string) error {
+	return w.p.replay("workspaces", "Cleanup", []any{ticketKey})
+}
+
+func (w replayWorkspaces) CleanupStale(maxAge time.Duration) (int, error) {
+	var n int
+	err := w.p.replay("workspaces", "CleanupStale", []any{maxAge}, &n)
+	return n, err
+}
+
+func (w replayWorkspaces) CleanupByFilter(func(ticketKey string) bool) (int, error) {
+	var n int
+	err := w.p.replay("workspaces", "CleanupByFilter", nil, &n)
+	return n, err
+}
+
+func (w replayWorkspaces) List() ([]workspace.Info, error) {
+	var infos []workspace.Info
+	err := w.p.replay("workspaces", "List", nil, &infos)
+	return infos, err
+}
+
+func (w replayProjects) ResolveProject(workItem models.WorkItem) (*models.ProjectSettings, error) {
+	var settings *models.ProjectSettings
+	err := w.p.replay("projects", "ResolveProject", []any{workItem}, &settings)
+	return settings, err
+}
+
+func (w replayAgent) Run(_ context.Context, req agent.Request) (agent.Result, error) {
+	var result agent.Result
+	err := w.p.replay("agent", "Run", agentArgs(w.provider, req), &result)
+	return result, err
+}
+
+// makeRepoDirs creates the repository directories of a replayed
+// multi-repo workspace, which the pipeline expects to exist.
+func makeRepoDirs(path string, repos []workspace.RepoEntry) error {
+	for _, repo := range repos {
+		if err := os.MkdirAll(filepath.Join(path, repo.Name), 0o750); err != nil {
+			return err
+		}
+	}
+	return nil
+}
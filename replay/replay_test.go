@@ -0,0 +1,207 @@
+package replay_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/container"
+	"jira-ai-issue-solver/container/containertest"
+	"jira-ai-issue-solver/executor"
+	"jira-ai-issue-solver/executor/executortest"
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/replay"
+	"jira-ai-issue-solver/services"
+	"jira-ai-issue-solver/taskfile"
+	"jira-ai-issue-solver/tracker/trackertest"
+	"jira-ai-issue-solver/workspace/workspacetest"
+)
+
+func pipelineConfig() executor.Config {
+	return executor.Config{
+		BotUsername:     "ai-bot",
+		DefaultProvider: "claude",
+		AIAPIKeys:       map[string]string{"claude": "sk-secret"},
+		MaxRetries:      3,
+	}
+}
+
+// newDeps returns dependencies for a new-ticket job whose AI session
+// writes its session output into a workspace under base.
+func newDeps(t *testing.T, base string) replay.Deps {
+	t.Helper()
+	wsDir := filepath.Join(base, "PROJ-1")
+	var comments []models.Comment
+	return replay.Deps{
+		Tracker: &trackertest.Stub{
+			GetWorkItemFunc: func(key string) (*models.WorkItem, error) {
+				return &models.WorkItem{Key: key, Summary: "Fix a bug", Type: "Bug", Components: []string{}, Labels: []string{}}, nil
+			},
+			GetCommentsFunc: func(string) ([]models.Comment, error) { return comments, nil },
+			AddCommentFunc: func(_, body string) error {
+				comments = append(comments, models.Comment{ID: fmt.Sprint(len(comments) + 1), Body: body})
+				return nil
+			},
+		},
+		Git: &executortest.StubGitService{
+			HasChangesFunc: func(string, string) (bool, error) { return true, nil },
+			CommitChangesFunc: func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+				return "abc123", nil
+			},
+			CreatePRFunc: func(models.PRParams) (*models.PR, error) {
+				return &models.PR{Number: 7, URL: "https://github.com/org/repo/pull/7"}, nil
+			},
+		},
+		Containers: &containertest.StubManager{
+			ResolveConfigFunc: func(string, *container.SettingsOverride) (*container.Config, error) {
+				return &container.Config{Image: "dev:latest", Env: map[string]string{"TOKEN": "hunter2"}}, nil
+			},
+			StartFunc: func(context.Context, *container.Config, string, string, map[string]string) (*container.Container, error) {
+				return &container.Container{ID: "c1", Name: "ai-bot-PROJ-1"}, nil
+			},
+			ExecFunc: func(context.Context, *container.Container, []string) (string, int, error) {
+				dir := filepath.Join(wsDir, ".ai-session")
+				if err := os.MkdirAll(dir, 0o750); err != nil {
+					return "", 0, err
+				}
+				output := `{"exit_code": 0, "cost_usd": 1.5, "validation_passed": true}`
+				return "done", 0, os.WriteFile(filepath.Join(dir, "session-output.json"), []byte(output), 0o600)
+			},
+		},
+		Workspaces: &workspacetest.Stub{
+			FindOrCreateFunc: func(string, string, []string) (string, bool, error) {
+				return wsDir, false, os.MkdirAll(wsDir, 0o750)
+			},
+		},
+		TaskWriter: taskfile.NewMarkdownWriter(),
+		Projects: &executortest.StubProjectResolver{
+			ResolveProjectFunc: func(models.WorkItem) (*models.ProjectSettings, error) {
+				return &models.ProjectSettings{
+					Repos:            []models.RepoSettings{{Owner: "org", Repo: "repo", CloneURL: "https://github.com/org/repo.git", BaseBranch: "main"}},
+					InProgressStatus: "In Progress",
+					InReviewStatus:   "In Review",
+					TodoStatus:       "To Do",
+				}, nil
+			},
+		},
+	}
+}
+
+// record runs a new-ticket job through a Recorder and loads the saved
+// recording. It also returns the job's error.
+func record(t *testing.T, deps replay.Deps, base string) (*replay.Recording, error) {
+	t.Helper()
+	dir := t.TempDir()
+	r, err := replay.NewRecorder(pipelineConfig(), deps, base, dir, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	job := &jobmanager.Job{ID: "job-1", TicketKey: "PROJ-1", Type: jobmanager.JobTypeNewTicket, AttemptNum: 1}
+	_, jobErr := r.Execute(context.Background(), job)
+
+	path := filepath.Join(dir, "PROJ-1-job-1.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"sk-secret", "hunter2", base} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("recording contains %q", secret)
+		}
+	}
+	rec, err := replay.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return rec, jobErr
+}
+
+func TestReplay_MatchesRecording(t *testing.T) {
+	base := t.TempDir()
+	rec, err := record(t, newDeps(t, base), base)
+	if err != nil {
+		t.Fatalf("recorded job failed: %v", err)
+	}
+
+	report, err := replay.Replay(context.Background(), rec, pipelineConfig(), taskfile.NewMarkdownWriter(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("replay differs from recording:\n%s", strings.Join(report.Diffs, "\n"))
+	}
+	if report.Err != nil || report.Result.PRNumber != 7 || report.Result.CostUSD != 1.5 {
+		t.Errorf("replayed result = %+v, %v; want PR 7 costing $1.50", report.Result, report.Err)
+	}
+}
+
+func TestReplay_ReportsChangedBehavior(t *testing.T) {
+	base := t.TempDir()
+	rec, err := record(t, newDeps(t, base), base)
+	if err != nil {
+		t.Fatalf("recorded job failed: %v", err)
+	}
+
+	cfg := pipelineConfig()
+	cfg.BotUsername = "other-bot"
+	report, err := replay.Replay(context.Background(), rec, cfg, taskfile.NewMarkdownWriter(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if report.OK() {
+		t.Fatal("replay with a different bot username matched the recording")
+	}
+	var branchDiff bool
+	for _, diff := range report.Diffs {
+		if strings.Contains(diff, "git.CreateBranch") && strings.Contains(diff, "other-bot/PROJ-1") {
+			branchDiff = true
+		}
+	}
+	if !branchDiff {
+		t.Errorf("diffs do not show the changed branch name:\n%s", strings.Join(report.Diffs, "\n"))
+	}
+}
+
+func TestReplay_PreservesSentinelErrors(t *testing.T) {
+	base := t.TempDir()
+	deps := newDeps(t, base)
+	deps.Git.(*executortest.StubGitService).CommitChangesFunc = func(_, _, _, _, _, _, _ string, _ *models.Author, _ []string, _ bool) (string, error) {
+		return "", fmt.Errorf("commit: %w", services.ErrNoChanges)
+	}
+
+	rec, recordedErr := record(t, deps, base)
+	if recordedErr == nil {
+		t.Fatal("expected the recorded job to fail without changes")
+	}
+
+	report, err := replay.Replay(context.Background(), rec, pipelineConfig(), taskfile.NewMarkdownWriter(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("replay differs from recording:\n%s", strings.Join(report.Diffs, "\n"))
+	}
+	if report.Err == nil || report.Err.Error() != recordedErr.Error() {
+		t.Errorf("replayed error = %v, want %v", report.Err, recordedErr)
+	}
+}
+
+func TestLoad_RejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rec.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := replay.Load(path); err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Errorf("Load error = %v, want a version error", err)
+	}
+	if _, err := replay.Load(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load of a missing file: error = %v, want not exist", err)
+	}
+}
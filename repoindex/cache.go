@@ -0,0 +1,143 @@
+package repoindex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config holds construction parameters for [Cache].
+type Config struct {
+	// Dir is the directory index files are stored in, one per
+	// repository.
+	Dir string
+
+	// MaxAge is how long an index is used before the repository is
+	// re-indexed. Must be positive.
+	MaxAge time.Duration
+
+	// MaxFiles is the number of files [Cache.Relevant] lists. Must be
+	// positive.
+	MaxFiles int
+}
+
+// Cache builds repository indexes and persists them on disk, so that
+// a repository is re-indexed at most once per MaxAge no matter how
+// many tickets use it. It is safe for concurrent use.
+type Cache struct {
+	cfg    Config
+	mu     sync.Mutex
+	clock  func() time.Time
+	logger *zap.Logger
+}
+
+// NewCache creates a Cache storing indexes under cfg.Dir, creating
+// the directory if needed.
+func NewCache(cfg Config, logger *zap.Logger) (*Cache, error) {
+	return NewCacheWithClock(cfg, time.Now, logger)
+}
+
+// NewCacheWithClock is like [NewCache] but accepts a custom clock
+// function for testing.
+func NewCacheWithClock(cfg Config, clock func() time.Time, logger *zap.Logger) (*Cache, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("repo index directory must not be empty")
+	}
+	if cfg.MaxAge <= 0 {
+		return nil, errors.New("repo index max age must be positive")
+	}
+	if cfg.MaxFiles <= 0 {
+		return nil, errors.New("repo index max files must be positive")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create repo index directory: %w", err)
+	}
+	return &Cache{cfg: cfg, clock: clock, logger: logger}, nil
+}
+
+// Relevant returns up to MaxFiles files of the repository identified
+// by repoURL that are most relevant to query. The cached index is
+// used when it is younger than MaxAge; otherwise the repository
+// checked out at dir is re-indexed and the cache updated.
+func (c *Cache) Relevant(repoURL, dir, query string) ([]Entry, error) {
+	idx, err := c.index(repoURL, dir)
+	if err != nil {
+		return nil, err
+	}
+	return idx.Relevant(query, c.cfg.MaxFiles), nil
+}
+
+// index returns the repository's cached index, rebuilding it from dir
+// when it is missing, unreadable, or stale.
+func (c *Cache) index(repoURL, dir string) (*Index, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.path(repoURL)
+	if idx, err := load(path); err == nil && c.clock().Sub(idx.Built) < c.cfg.MaxAge {
+		return idx, nil
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		c.logger.Warn("Discarding unreadable repo index",
+			zap.String("repo", repoURL), zap.Error(err))
+	}
+
+	start := c.clock()
+	idx, err := Build(dir)
+	if err != nil {
+		return nil, err
+	}
+	idx.Built = start
+	if err := save(path, idx); err != nil {
+		c.logger.Warn("Failed to save repo index",
+			zap.String("repo", repoURL), zap.Error(err))
+	}
+	c.logger.Info("Repo indexed",
+		zap.String("repo", repoURL),
+		zap.Int("files", len(idx.Entries)))
+	return idx, nil
+}
+
+// path returns the index file for repoURL. The URL is hashed so that
+// it cannot escape the directory and credentials embedded in it are
+// not written to disk.
+func (c *Cache) path(repoURL string) string {
+	sum := sha256.Sum256([]byte(repoURL))
+	return filepath.Join(c.cfg.Dir, "repo-index-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+func load(path string) (*Index, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is dir + hash
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, err
+	}
+	return &idx, nil
+}
+
+// save writes idx to path through a temporary file, so that a crash
+// mid-write does not leave a truncated index.
+func save(path string, idx *Index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
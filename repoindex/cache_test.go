@@ -0,0 +1,73 @@
+package repoindex_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/repoindex"
+)
+
+func TestCache_ReusesFreshIndex(t *testing.T) {
+	repo := writeRepo(t, map[string]string{
+		"auth/session.go": "package auth\n\nfunc RefreshSession() {}\n",
+	})
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cache, err := repoindex.NewCacheWithClock(repoindex.Config{
+		Dir:      t.TempDir(),
+		MaxAge:   time.Hour,
+		MaxFiles: 5,
+	}, func() time.Time { return now }, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	relevant := func() string {
+		t.Helper()
+		entries, err := cache.Relevant("https://github.com/org/repo.git", repo, "session refresh fails")
+		if err != nil {
+			t.Fatalf("Relevant: %v", err)
+		}
+		return repoindex.Render(entries)
+	}
+
+	if got := relevant(); !strings.Contains(got, "`auth/session.go`: RefreshSession") {
+		t.Fatalf("first lookup missing session.go:\n%s", got)
+	}
+
+	// A new file is not seen until the index expires.
+	if err := os.WriteFile(filepath.Join(repo, "auth/refresh.go"),
+		[]byte("package auth\n\nfunc RefreshToken() {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := relevant(); strings.Contains(got, "refresh.go") {
+		t.Errorf("fresh index was rebuilt:\n%s", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if got := relevant(); !strings.Contains(got, "`auth/refresh.go`: RefreshToken") {
+		t.Errorf("stale index was not rebuilt:\n%s", got)
+	}
+}
+
+func TestNewCache_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  repoindex.Config
+	}{
+		{"empty dir", repoindex.Config{MaxAge: time.Hour, MaxFiles: 5}},
+		{"zero max age", repoindex.Config{Dir: t.TempDir(), MaxFiles: 5}},
+		{"zero max files", repoindex.Config{Dir: t.TempDir(), MaxAge: time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repoindex.NewCache(tt.cfg, zap.NewNop()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
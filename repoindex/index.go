@@ -0,0 +1,354 @@
+// Package repoindex summarizes a repository's source files and the
+// symbols they define, and selects the files most relevant to a
+// ticket.
+//
+// On large codebases the AI can spend much of its context window
+// finding where a change belongs. An [Index] lists each source file
+// with its top-level functions, types, and classes; [Index.Relevant]
+// ranks the files against the ticket text by keyword overlap, and
+// [Render] turns the best matches into a task file section that
+// points the AI at them. A [Cache] persists indexes on disk so that a
+// repository is only re-indexed once its index is older than the
+// configured refresh interval.
+package repoindex
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// maxFileBytes is the size above which files are not indexed;
+	// they are usually generated or data files.
+	maxFileBytes = 512 << 10
+
+	// maxSymbolsPerFile caps the symbols recorded, and rendered, for
+	// a single file.
+	maxSymbolsPerFile = 15
+)
+
+// skippedDirs are directory names never descended into.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"third_party":  true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+	"__pycache__":  true,
+}
+
+// symbolPatterns maps source file extensions to patterns matching
+// top-level declarations. The last submatch of each is the symbol
+// name.
+var symbolPatterns = map[string][]*regexp.Regexp{
+	".go": {
+		regexp.MustCompile(`^func (?:\([^)]*\) )?(\w+)`),
+		regexp.MustCompile(`^type (\w+)`),
+	},
+	".py": {
+		regexp.MustCompile(`^\s*(?:async )?(?:def|class) (\w+)`),
+	},
+	".js":  jsPatterns,
+	".jsx": jsPatterns,
+	".ts":  jsPatterns,
+	".tsx": jsPatterns,
+	".java": {
+		regexp.MustCompile(`^\s*(?:public |protected |private |abstract |final |static )*(?:class|interface|enum|record) (\w+)`),
+	},
+	".kt": {
+		regexp.MustCompile(`^\s*(?:\w+ )*(?:class|interface|object|fun) (\w+)`),
+	},
+	".rs": {
+		regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))? )?(?:async )?(?:fn|struct|enum|trait|mod) (\w+)`),
+	},
+	".rb": {
+		regexp.MustCompile(`^\s*(?:def|class|module) (?:self\.)?(\w+)`),
+	},
+	".c":   cPatterns,
+	".h":   cPatterns,
+	".cc":  cPatterns,
+	".cpp": cPatterns,
+	".hpp": cPatterns,
+	".cs": {
+		regexp.MustCompile(`^\s*(?:\w+ )*(?:class|interface|struct|enum|record) (\w+)`),
+	},
+	".sh": {
+		regexp.MustCompile(`^(?:function )?(\w+)\s*\(\)`),
+	},
+}
+
+var jsPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^(?:export )?(?:default )?(?:async )?(?:function\*?|class|interface|type|enum) (\w+)`),
+	regexp.MustCompile(`^(?:export )?const (\w+)\s*=`),
+}
+
+var cPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^(?:struct|class|enum|union) (\w+)`),
+	regexp.MustCompile(`^[A-Za-z_][\w\s\*&:<>]*?\b(\w+)\s*\([^;]*$`),
+}
+
+// Entry summarizes one source file.
+type Entry struct {
+	// Path is the file's slash-separated path relative to the
+	// repository root.
+	Path string `json:"path"`
+
+	// Symbols lists the names declared at the top level of the file,
+	// in order of appearance.
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+// Index summarizes the source files of a repository.
+type Index struct {
+	// Built is when the index was built.
+	Built time.Time `json:"built"`
+
+	// Entries lists the indexed files, sorted by path.
+	Entries []Entry `json:"entries"`
+}
+
+// Build walks the repository checked out at dir and indexes every
+// source file with a known extension. Hidden directories (including
+// .git), dependency and build output directories, and files larger
+// than 512 KiB are skipped. Unreadable files are left out.
+func Build(dir string) (*Index, error) {
+	idx := &Index{Built: time.Now()}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		patterns, ok := symbolPatterns[filepath.Ext(name)]
+		if !ok || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxFileBytes {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		symbols, err := extractSymbols(path, patterns)
+		if err != nil {
+			return nil
+		}
+		idx.Entries = append(idx.Entries, Entry{Path: filepath.ToSlash(rel), Symbols: symbols})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("index %s: %w", dir, err)
+	}
+	return idx, nil
+}
+
+// extractSymbols returns the distinct names declared in the file at
+// path, up to maxSymbolsPerFile.
+func extractSymbols(path string, patterns []*regexp.Regexp) ([]string, error) {
+	f, err := os.Open(path) // #nosec G304 -- path comes from walking the workspace
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var symbols []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for sc.Scan() && len(symbols) < maxSymbolsPerFile {
+		line := sc.Text()
+		for _, re := range patterns {
+			m := re.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			name := m[len(m)-1]
+			if name != "" && !slices.Contains(symbols, name) && !keywords[name] {
+				symbols = append(symbols, name)
+			}
+			break
+		}
+	}
+	if err := sc.Err(); err != nil && !errors.Is(err, bufio.ErrTooLong) {
+		return nil, err
+	}
+	return symbols, nil
+}
+
+// keywords are control-flow words the C pattern can mistake for
+// function names.
+var keywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "return": true, "sizeof": true,
+}
+
+// Relevant returns up to limit entries ranked by how well their path
+// and symbols match the words in text. Words found in many files
+// count for less than rare ones, and a match on a symbol counts for
+// more than a match on the path. Entries matching nothing are not
+// returned.
+func (idx *Index) Relevant(text string, limit int) []Entry {
+	terms := queryTerms(text)
+	if len(terms) == 0 || limit <= 0 {
+		return nil
+	}
+
+	type fileTerms struct {
+		path    map[string]bool
+		symbols map[string]bool
+	}
+	files := make([]fileTerms, len(idx.Entries))
+	docFreq := make(map[string]int)
+	for i, e := range idx.Entries {
+		ft := fileTerms{path: make(map[string]bool), symbols: make(map[string]bool)}
+		for _, w := range splitWords(e.Path) {
+			ft.path[w] = true
+		}
+		for _, s := range e.Symbols {
+			for _, w := range splitWords(s) {
+				ft.symbols[w] = true
+			}
+		}
+		for t := range terms {
+			if ft.path[t] || ft.symbols[t] {
+				docFreq[t]++
+			}
+		}
+		files[i] = ft
+	}
+
+	type scored struct {
+		entry Entry
+		score float64
+	}
+	var matches []scored
+	n := float64(len(idx.Entries))
+	for i, ft := range files {
+		var score float64
+		for t := range terms {
+			if docFreq[t] == 0 {
+				continue
+			}
+			weight := math.Log(1 + n/float64(docFreq[t]))
+			if ft.symbols[t] {
+				score += 2 * weight
+			}
+			if ft.path[t] {
+				score += weight
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{idx.Entries[i], score})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	entries := make([]Entry, len(matches))
+	for i, m := range matches {
+		entries[i] = m.entry
+	}
+	return entries
+}
+
+// Render returns a task file section listing entries and their
+// symbols, or "" when entries is empty.
+func Render(entries []Entry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n## Possibly Relevant Code\n\n")
+	b.WriteString("An index of the repository suggests these files are related " +
+		"to this ticket. Use them as a starting point; they may be " +
+		"incomplete or include unrelated files.\n\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "- `%s`", e.Path)
+		if len(e.Symbols) > 0 {
+			fmt.Fprintf(&b, ": %s", strings.Join(e.Symbols, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// stopWords are common words in ticket text that say nothing about
+// where a change belongs.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true,
+	"with": true, "from": true, "when": true, "should": true, "not": true,
+	"are": true, "was": true, "but": true, "have": true, "has": true,
+	"can": true, "will": true, "would": true, "into": true, "there": true,
+	"which": true, "all": true, "any": true, "also": true, "use": true,
+	"need": true, "needs": true, "new": true, "add": true, "make": true,
+	"get": true, "set": true, "test": true, "tests": true, "file": true,
+	"files": true, "code": true, "bug": true, "fix": true, "issue": true,
+	"error": true, "work": true, "does": true, "instead": true, "them": true,
+}
+
+// queryTerms returns the distinct words of text worth matching.
+func queryTerms(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, w := range splitWords(text) {
+		if len(w) >= 3 && !stopWords[w] {
+			terms[w] = true
+		}
+	}
+	return terms
+}
+
+// splitWords splits s into lowercase words at non-alphanumeric
+// characters and camelCase boundaries, so that "parseHTTPRequest" and
+// "parse_http_request.go" both yield "parse", "http", and "request".
+func splitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	flush := func(end int) {
+		if start >= 0 && end > start {
+			words = append(words, strings.ToLower(string(runes[start:end])))
+		}
+		start = -1
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush(i)
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	flush(len(runes))
+	return words
+}
@@ -0,0 +1,110 @@
+package repoindex_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/repoindex"
+)
+
+// writeRepo creates files under a temporary directory and returns it.
+func writeRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBuild_ExtractsSymbols(t *testing.T) {
+	dir := writeRepo(t, map[string]string{
+		"auth/token.go":           "package auth\n\ntype TokenStore struct{}\n\nfunc (s *TokenStore) Refresh() error {\n\treturn nil\n}\n\nfunc parseClaims() {}\n",
+		"web/login.ts":            "export async function submitLogin() {}\nexport const LoginForm = () => null\n",
+		"scripts/migrate.py":      "class Migration:\n    def apply(self):\n        pass\n",
+		"README.md":               "# Project\n",
+		".git/config":             "[core]\n",
+		"node_modules/x/index.js": "function vendored() {}\n",
+	})
+
+	idx, err := repoindex.Build(dir)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, e := range idx.Entries {
+		got[e.Path] = e.Symbols
+	}
+	want := map[string][]string{
+		"auth/token.go":      {"TokenStore", "Refresh", "parseClaims"},
+		"web/login.ts":       {"submitLogin", "LoginForm"},
+		"scripts/migrate.py": {"Migration", "apply"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("indexed %v, want %v", got, want)
+	}
+	for path, symbols := range want {
+		if !slices.Equal(got[path], symbols) {
+			t.Errorf("%s symbols = %v, want %v", path, got[path], symbols)
+		}
+	}
+}
+
+func TestRelevant_RanksBySymbolAndPath(t *testing.T) {
+	idx := &repoindex.Index{Entries: []repoindex.Entry{
+		{Path: "billing/invoice.go", Symbols: []string{"Invoice", "RenderPDF"}},
+		{Path: "auth/session.go", Symbols: []string{"SessionStore", "refreshToken"}},
+		{Path: "auth/login.go", Symbols: []string{"Login"}},
+		{Path: "util/strings.go", Symbols: []string{"Truncate"}},
+	}}
+
+	got := idx.Relevant("Session refresh token expires too early after login", 2)
+
+	var paths []string
+	for _, e := range got {
+		paths = append(paths, e.Path)
+	}
+	if !slices.Equal(paths, []string{"auth/session.go", "auth/login.go"}) {
+		t.Errorf("Relevant = %v, want [auth/session.go auth/login.go]", paths)
+	}
+}
+
+func TestRelevant_NoMatches(t *testing.T) {
+	idx := &repoindex.Index{Entries: []repoindex.Entry{
+		{Path: "billing/invoice.go", Symbols: []string{"Invoice"}},
+	}}
+
+	if got := idx.Relevant("Update the README wording", 5); len(got) != 0 {
+		t.Errorf("Relevant = %v, want none", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	got := repoindex.Render([]repoindex.Entry{
+		{Path: "auth/session.go", Symbols: []string{"SessionStore", "refreshToken"}},
+		{Path: "auth/doc.go"},
+	})
+
+	for _, want := range []string{
+		"## Possibly Relevant Code",
+		"- `auth/session.go`: SessionStore, refreshToken\n",
+		"- `auth/doc.go`\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render missing %q:\n%s", want, got)
+		}
+	}
+	if repoindex.Render(nil) != "" {
+		t.Error("Render(nil) should be empty")
+	}
+}
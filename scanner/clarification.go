@@ -0,0 +1,251 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// Compile-time check that ClarificationScanner implements Scanner.
+var _ Scanner = (*ClarificationScanner)(nil)
+
+// ClarificationScannerConfig holds configuration for
+// [ClarificationScanner].
+type ClarificationScannerConfig struct {
+	// Criteria defines the search query for "waiting for info"
+	// tickets. A query without statuses disables scanning.
+	Criteria models.SearchCriteria
+
+	// PollInterval is the time between scan cycles.
+	PollInterval time.Duration
+}
+
+func (c ClarificationScannerConfig) validate() error {
+	if c.PollInterval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	return nil
+}
+
+// ClarificationScanner polls for tickets waiting for information the
+// AI asked for and, once someone replies to the bot's questions (see
+// [models.ClarificationAnswered]), emits a [jobmanager.JobTypeNewTicket]
+// event so that the ticket is solved again with the answers.
+type ClarificationScanner struct {
+	searcher  IssueSearcher
+	submitter JobSubmitter
+	comments  CommentFetcher
+	cfg       ClarificationScannerConfig
+	logger    *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	lastScan scanTimestamp
+	updates  configUpdate[ClarificationScannerConfig]
+}
+
+// NewClarificationScanner creates a ClarificationScanner with the
+// given dependencies. Returns an error if any required parameter is
+// invalid.
+func NewClarificationScanner(
+	searcher IssueSearcher,
+	submitter JobSubmitter,
+	comments CommentFetcher,
+	cfg ClarificationScannerConfig,
+	logger *zap.Logger,
+) (*ClarificationScanner, error) {
+	if searcher == nil {
+		return nil, errors.New("issue searcher must not be nil")
+	}
+	if submitter == nil {
+		return nil, errors.New("job submitter must not be nil")
+	}
+	if comments == nil {
+		return nil, errors.New("comment fetcher must not be nil")
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+
+	return &ClarificationScanner{
+		searcher:  searcher,
+		submitter: submitter,
+		comments:  comments,
+		cfg:       cfg,
+		logger:    logger,
+	}, nil
+}
+
+// Start begins polling in a background goroutine.
+func (s *ClarificationScanner) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return errors.New("scanner already running")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go s.run(ctx)
+	return nil
+}
+
+// Stop cancels polling and blocks until the goroutine exits.
+func (s *ClarificationScanner) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	done := s.done
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// LastScan returns when the most recent scan cycle finished, or the
+// zero time if no cycle has completed yet.
+func (s *ClarificationScanner) LastScan() time.Time {
+	return s.lastScan.get()
+}
+
+// UpdateConfig replaces the scanner's configuration. The new
+// configuration takes effect at the start of the next scan cycle,
+// and the poll ticker is reset to the new interval. Returns an error
+// if cfg is invalid, in which case the current configuration is kept.
+func (s *ClarificationScanner) UpdateConfig(cfg ClarificationScannerConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	s.updates.offer(cfg)
+	return nil
+}
+
+func (s *ClarificationScanner) run(ctx context.Context) {
+	defer close(s.done)
+
+	s.applyPendingConfig()
+	s.scan(ctx)
+	s.lastScan.mark()
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.updates.ready():
+			if s.applyPendingConfig() {
+				ticker.Reset(s.cfg.PollInterval)
+			}
+		case <-ticker.C:
+			s.scan(ctx)
+			s.lastScan.mark()
+		}
+	}
+}
+
+// applyPendingConfig installs a configuration passed to UpdateConfig,
+// if any. Called only from the polling goroutine. Reports whether
+// the configuration changed.
+func (s *ClarificationScanner) applyPendingConfig() bool {
+	cfg, ok := s.updates.take()
+	if !ok {
+		return false
+	}
+	s.cfg = cfg
+	s.logger.Info("Scanner configuration updated",
+		zap.Duration("poll_interval", cfg.PollInterval))
+	return true
+}
+
+func (s *ClarificationScanner) scan(ctx context.Context) {
+	if len(s.cfg.Criteria.StatusByType) == 0 {
+		return
+	}
+
+	items, err := s.searcher.SearchWorkItems(s.cfg.Criteria)
+	if err != nil {
+		logSearchError(s.logger, "Failed to search for tickets waiting for info", err)
+		return
+	}
+
+	if len(items) == 0 {
+		s.logger.Debug("No tickets waiting for info found")
+		return
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+		if s.checkAndSubmit(item) {
+			return
+		}
+	}
+}
+
+// checkAndSubmit submits a new-ticket event when the bot's questions
+// on the ticket have been answered. Returns true if the scan cycle
+// should stop (circuit breaker open or shutdown).
+func (s *ClarificationScanner) checkAndSubmit(item models.WorkItem) bool {
+	logger := s.logger.With(zap.String("ticket", item.Key))
+
+	comments, err := s.comments.GetComments(item.Key)
+	if err != nil {
+		logger.Warn("Failed to fetch comments, skipping", zap.Error(err))
+		return false
+	}
+	if !models.ClarificationAnswered(comments) {
+		logger.Debug("Questions not answered yet")
+		return false
+	}
+
+	event := jobmanager.Event{
+		Type:          jobmanager.JobTypeNewTicket,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
+	}
+
+	_, err = s.submitter.Submit(event)
+	if err == nil {
+		logger.Info("Submitted answered ticket")
+		return false
+	}
+
+	switch {
+	case errors.Is(err, jobmanager.ErrDuplicateJob):
+		logger.Debug("Skipping duplicate ticket")
+	case errors.Is(err, jobmanager.ErrRetriesExhausted):
+		logger.Debug("Skipping exhausted ticket")
+	case errors.Is(err, jobmanager.ErrCircuitOpen):
+		logger.Warn("Circuit breaker open, stopping scan cycle")
+		return true
+	case errors.Is(err, jobmanager.ErrBudgetExceeded):
+		logger.Warn("Daily budget exceeded, stopping scan cycle")
+		return true
+	case errors.Is(err, jobmanager.ErrShutdown):
+		logger.Info("Job manager shut down, stopping scan cycle")
+		return true
+	default:
+		logger.Error("Failed to submit answered ticket", zap.Error(err))
+	}
+
+	return false
+}
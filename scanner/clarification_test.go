@@ -0,0 +1,94 @@
+package scanner_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scanner"
+	"jira-ai-issue-solver/scanner/scannertest"
+)
+
+// runOneClarificationScan runs a scanner for PROJ-1, waiting for info
+// with the given comments, and returns the events it submitted.
+func runOneClarificationScan(t *testing.T, criteria models.SearchCriteria, comments ...string) []jobmanager.Event {
+	t.Helper()
+
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(models.SearchCriteria) ([]models.WorkItem, error) {
+			return []models.WorkItem{{Key: "PROJ-1"}}, nil
+		},
+	}
+	fetcher := &scannertest.StubCommentFetcher{
+		GetCommentsFunc: func(string) ([]models.Comment, error) {
+			var result []models.Comment
+			for _, body := range comments {
+				result = append(result, models.Comment{Body: body})
+			}
+			return result, nil
+		},
+	}
+	var mu sync.Mutex
+	var submitted []jobmanager.Event
+	submitter := &scannertest.StubJobSubmitter{
+		SubmitFunc: func(event jobmanager.Event) (*jobmanager.Job, error) {
+			mu.Lock()
+			submitted = append(submitted, event)
+			mu.Unlock()
+			return &jobmanager.Job{}, nil
+		},
+	}
+
+	s, err := scanner.NewClarificationScanner(searcher, submitter, fetcher,
+		scanner.ClarificationScannerConfig{Criteria: criteria, PollInterval: time.Hour}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return submitted
+}
+
+func TestClarificationScanner(t *testing.T) {
+	waiting := models.SearchCriteria{StatusByType: map[string][]string{"Bug": {"Waiting for Info"}}}
+	questions := models.ClarificationMarker + " The AI needs more information"
+
+	tests := []struct {
+		name     string
+		criteria models.SearchCriteria
+		comments []string
+		want     bool
+	}{
+		{name: "answered", criteria: waiting, comments: []string{questions, "Reject them."}, want: true},
+		{name: "unanswered", criteria: waiting, comments: []string{questions}},
+		{name: "no waiting status configured", comments: []string{questions, "Reject them."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := runOneClarificationScan(t, tt.criteria, tt.comments...)
+			if !tt.want {
+				if len(events) != 0 {
+					t.Errorf("events = %+v, want none", events)
+				}
+				return
+			}
+			if len(events) != 1 || events[0].Type != jobmanager.JobTypeNewTicket || events[0].TicketKey != "PROJ-1" {
+				t.Errorf("events = %+v, want one new-ticket event for PROJ-1", events)
+			}
+		})
+	}
+}
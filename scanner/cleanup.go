@@ -24,6 +24,16 @@ type WorkspaceCleanupConfig struct {
 	ActiveStatuses map[string]bool
 }
 
+func (c WorkspaceCleanupConfig) validate() error {
+	if c.PollInterval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	if len(c.ActiveStatuses) == 0 {
+		return errors.New("active statuses must not be empty")
+	}
+	return nil
+}
+
 // WorkspaceCleanupScanner periodically removes workspaces for tickets
 // that are no longer in an active status. On each cycle it iterates
 // existing workspace directories, checks each ticket's current status
@@ -38,6 +48,9 @@ type WorkspaceCleanupScanner struct {
 	mu     sync.Mutex
 	cancel context.CancelFunc
 	done   chan struct{}
+
+	lastScan scanTimestamp
+	updates  configUpdate[WorkspaceCleanupConfig]
 }
 
 // NewWorkspaceCleanupScanner creates a WorkspaceCleanupScanner with the
@@ -55,11 +68,8 @@ func NewWorkspaceCleanupScanner(
 	if tracker == nil {
 		return nil, errors.New("ticket status checker must not be nil")
 	}
-	if cfg.PollInterval <= 0 {
-		return nil, errors.New("poll interval must be positive")
-	}
-	if len(cfg.ActiveStatuses) == 0 {
-		return nil, errors.New("active statuses must not be empty")
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 	if logger == nil {
 		return nil, errors.New("logger must not be nil")
@@ -103,10 +113,30 @@ func (s *WorkspaceCleanupScanner) Stop() {
 	}
 }
 
+// LastScan returns when the most recent scan cycle finished, or the
+// zero time if no cycle has completed yet.
+func (s *WorkspaceCleanupScanner) LastScan() time.Time {
+	return s.lastScan.get()
+}
+
+// UpdateConfig replaces the scanner's configuration. The new
+// configuration takes effect at the start of the next scan cycle,
+// and the poll ticker is reset to the new interval. Returns an error
+// if cfg is invalid, in which case the current configuration is kept.
+func (s *WorkspaceCleanupScanner) UpdateConfig(cfg WorkspaceCleanupConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	s.updates.offer(cfg)
+	return nil
+}
+
 func (s *WorkspaceCleanupScanner) run(ctx context.Context, done chan struct{}) {
 	defer close(done)
 
+	s.applyPendingConfig()
 	s.scan(ctx)
+	s.lastScan.mark()
 
 	ticker := time.NewTicker(s.cfg.PollInterval)
 	defer ticker.Stop()
@@ -115,12 +145,31 @@ func (s *WorkspaceCleanupScanner) run(ctx context.Context, done chan struct{}) {
 		select {
 		case <-ctx.Done():
 			return
+		case <-s.updates.ready():
+			if s.applyPendingConfig() {
+				ticker.Reset(s.cfg.PollInterval)
+			}
 		case <-ticker.C:
 			s.scan(ctx)
+			s.lastScan.mark()
 		}
 	}
 }
 
+// applyPendingConfig installs a configuration passed to UpdateConfig,
+// if any. Called only from the polling goroutine. Reports whether
+// the configuration changed.
+func (s *WorkspaceCleanupScanner) applyPendingConfig() bool {
+	cfg, ok := s.updates.take()
+	if !ok {
+		return false
+	}
+	s.cfg = cfg
+	s.logger.Info("Scanner configuration updated",
+		zap.Duration("poll_interval", cfg.PollInterval))
+	return true
+}
+
 func (s *WorkspaceCleanupScanner) scan(ctx context.Context) {
 	cleaned, err := s.workspaces.CleanupByFilter(func(ticketKey string) bool {
 		if ctx.Err() != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"jira-ai-issue-solver/commentfilter"
 	"jira-ai-issue-solver/jobmanager"
 	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
 )
 
 // Compile-time check that FeedbackScanner implements Scanner.
@@ -23,17 +25,32 @@ type FeedbackScannerConfig struct {
 	// Criteria defines the search query for "in review" tickets.
 	Criteria models.SearchCriteria
 
-	// PollInterval is the time between scan cycles.
+	// PollInterval is the time between scan cycles. When Schedule is
+	// set, it only bounds how long the scanner sleeps between
+	// wake-ups.
 	PollInterval time.Duration
 
+	// Schedule, when set, scans at the times the cron expression
+	// matches instead of every PollInterval. The first scan waits for
+	// the first match rather than running at startup.
+	Schedule *models.CronSchedule
+
 	// BotUsername is the bot's GitHub username, used for branch
 	// name construction and comment filtering.
 	BotUsername string
 
+	// JiraUsername is the bot's Jira account email. Its ticket
+	// comments never approve a change preview.
+	JiraUsername string
+
 	// IgnoredUsernames lists users whose comments are skipped
 	// entirely.
 	IgnoredUsernames []string
 
+	// IgnoredCommentPaths lists file path patterns whose review
+	// comments are skipped entirely.
+	IgnoredCommentPaths []string
+
 	// KnownBotUsernames lists other bots for loop prevention.
 	KnownBotUsernames []string
 
@@ -49,9 +66,33 @@ type FeedbackScannerConfig struct {
 	// disables CI failure detection. Negative means unlimited.
 	MaxCIFixAttempts int
 
-	// SkipPRLabel is the GitHub label that tells the bot to skip
-	// a PR entirely. Empty disables the check.
+	// SkipPRLabel is the label that tells the bot to skip a PR
+	// entirely, on the PR itself or on its Jira ticket (which skips
+	// all of the ticket's PRs). Empty disables the check.
 	SkipPRLabel string
+
+	// QuietPeriod delays feedback until no new review comment has
+	// arrived on a PR for this long, so that a review submitted as
+	// several comments is addressed in one AI session. Zero acts on
+	// new comments in the next scan cycle.
+	QuietPeriod time.Duration
+
+	// Clock returns the current time for PR snapshot expiry and the
+	// quiet period. Defaults to [time.Now]. Exposed for testing.
+	Clock func() time.Time
+}
+
+func (c FeedbackScannerConfig) validate() error {
+	if c.PollInterval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	if c.QuietPeriod < 0 {
+		return errors.New("quiet period must not be negative")
+	}
+	if c.BotUsername == "" {
+		return errors.New("bot username must not be empty")
+	}
+	return nil
 }
 
 // FeedbackScanner polls for tickets in "in review" status and checks
@@ -69,12 +110,25 @@ type FeedbackScanner struct {
 	lifecycleLabelResolver LifecycleLabelResolver
 	mergedStatusResolver   MergedStatusResolver
 	statusTransitioner     StatusTransitioner
+	backportResolver       BackportResolver
+	releaseNotesResolver   ReleaseNotesResolver
+	mergeOrderResolver     MergeOrderResolver
+	previewResolver        DiffPreviewResolver
+	comments               CommentFetcher
 	cfg                    FeedbackScannerConfig
 	logger                 *zap.Logger
 
 	mu     sync.Mutex
 	cancel context.CancelFunc
 	done   chan struct{}
+
+	lastScan  scanTimestamp
+	heartbeat scanTimestamp
+	updates   configUpdate[FeedbackScannerConfig]
+
+	// snapshots records PRs with nothing to act on, keyed by
+	// [snapshotKey]. Accessed only from the polling goroutine.
+	snapshots map[string]prSnapshot
 }
 
 // NewFeedbackScanner creates a FeedbackScanner with the given
@@ -101,11 +155,8 @@ func NewFeedbackScanner(
 	if repos == nil {
 		return nil, errors.New("repo locator must not be nil")
 	}
-	if cfg.PollInterval <= 0 {
-		return nil, errors.New("poll interval must be positive")
-	}
-	if cfg.BotUsername == "" {
-		return nil, errors.New("bot username must not be empty")
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 	if logger == nil {
 		return nil, errors.New("logger must not be nil")
@@ -176,6 +227,56 @@ func WithPRLabeler(pl PRLabeler) FeedbackScannerOption {
 	}
 }
 
+// WithBackports enables backports: once all of a ticket's PRs are
+// merged, the scanner submits a [jobmanager.JobTypeBackport] event
+// while any release branch the resolver returns has no backport PR.
+// If br is nil, backports are silently disabled.
+func WithBackports(br BackportResolver) FeedbackScannerOption {
+	return func(fs *FeedbackScanner) {
+		if br != nil {
+			fs.backportResolver = br
+		}
+	}
+}
+
+// WithReleaseNotes enables release notes: once all of a ticket's PRs
+// are merged, the scanner submits a [jobmanager.JobTypeReleaseNote]
+// event while the ticket's release note is pending. If rr is nil,
+// release notes are silently disabled.
+func WithReleaseNotes(rr ReleaseNotesResolver) FeedbackScannerOption {
+	return func(fs *FeedbackScanner) {
+		if rr != nil {
+			fs.releaseNotesResolver = rr
+		}
+	}
+}
+
+// WithMergeOrder enables releasing held pull requests: the scanner
+// removes the do-not-merge label from a ticket's pull request once
+// every pull request before it in the merge order is merged. Requires
+// [WithPRLabeler]. If mr is nil, holds are never released.
+func WithMergeOrder(mr MergeOrderResolver) FeedbackScannerOption {
+	return func(fs *FeedbackScanner) {
+		if mr != nil {
+			fs.mergeOrderResolver = mr
+		}
+	}
+}
+
+// WithDiffPreviews enables change previews: a ticket awaiting approval
+// of its previewed change is not checked for PR feedback, and once the
+// change is approved, the scanner submits a [jobmanager.JobTypeNewTicket]
+// event that opens its PR. If either argument is nil, previewed tickets
+// are treated as any other.
+func WithDiffPreviews(dr DiffPreviewResolver, cf CommentFetcher) FeedbackScannerOption {
+	return func(fs *FeedbackScanner) {
+		if dr != nil && cf != nil {
+			fs.previewResolver = dr
+			fs.comments = cf
+		}
+	}
+}
+
 // Start begins polling in a background goroutine.
 func (s *FeedbackScanner) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -205,28 +306,85 @@ func (s *FeedbackScanner) Stop() {
 	}
 }
 
+// LastScan returns when the most recent scan cycle finished, or the
+// zero time if no cycle has completed yet. Wake-ups while waiting for
+// a cron schedule to match do not count; see [FeedbackScanner.LastHeartbeat].
+func (s *FeedbackScanner) LastScan() time.Time {
+	return s.lastScan.get()
+}
+
+// LastHeartbeat returns when the polling goroutine last woke up, or
+// the zero time if it has not yet. It wakes at least every
+// PollInterval even while waiting for a cron schedule to match.
+func (s *FeedbackScanner) LastHeartbeat() time.Time {
+	return s.heartbeat.get()
+}
+
+// UpdateConfig replaces the scanner's configuration. The new
+// configuration takes effect at the start of the next scan cycle,
+// and the poll ticker is reset to the new interval. Returns an error
+// if cfg is invalid, in which case the current configuration is kept.
+func (s *FeedbackScanner) UpdateConfig(cfg FeedbackScannerConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	s.updates.offer(cfg)
+	return nil
+}
+
 func (s *FeedbackScanner) run(ctx context.Context) {
 	defer close(s.done)
 
-	s.scan(ctx)
+	s.applyPendingConfig()
+	var next time.Time // zero: due immediately
+	if s.cfg.Schedule != nil {
+		next = s.cfg.Schedule.Next(time.Now())
+	}
 
-	ticker := time.NewTicker(s.cfg.PollInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			s.scan(ctx)
+		case <-s.updates.ready():
+			if s.applyPendingConfig() {
+				next = nextRun(s.cfg.Schedule, s.cfg.PollInterval, time.Now())
+				timer.Reset(wakeDelay(next, s.cfg.PollInterval))
+			}
+		case <-timer.C:
+			if !time.Now().Before(next) {
+				s.scan(ctx)
+				next = nextRun(s.cfg.Schedule, s.cfg.PollInterval, time.Now())
+				s.lastScan.mark()
+			}
+			s.heartbeat.mark()
+			timer.Reset(wakeDelay(next, s.cfg.PollInterval))
 		}
 	}
 }
 
+// applyPendingConfig installs a configuration passed to UpdateConfig,
+// if any. Called only from the polling goroutine. Reports whether
+// the configuration changed.
+func (s *FeedbackScanner) applyPendingConfig() bool {
+	cfg, ok := s.updates.take()
+	if !ok {
+		return false
+	}
+	s.cfg = cfg
+	// Filtering settings may have changed what is actionable.
+	s.snapshots = nil
+	s.logger.Info("Scanner configuration updated",
+		zap.Duration("poll_interval", cfg.PollInterval))
+	return true
+}
+
 func (s *FeedbackScanner) scan(ctx context.Context) {
 	items, err := s.searcher.SearchWorkItems(s.cfg.Criteria)
 	if err != nil {
-		s.logger.Error("Failed to search for in-review tickets", zap.Error(err))
+		logSearchError(s.logger, "Failed to search for in-review tickets", err)
 		return
 	}
 
@@ -236,6 +394,7 @@ func (s *FeedbackScanner) scan(ctx context.Context) {
 	}
 
 	s.logger.Info("Found in-review tickets", zap.Int("count", len(items)))
+	s.pruneSnapshots()
 
 	for _, item := range items {
 		if ctx.Err() != nil {
@@ -253,6 +412,19 @@ func (s *FeedbackScanner) scan(ctx context.Context) {
 func (s *FeedbackScanner) checkAndSubmit(item models.WorkItem) bool {
 	logger := s.logger.With(zap.String("ticket", item.Key))
 
+	if hasTicketSkipLabel(item, s.cfg.SkipPRLabel) {
+		logger.Debug("Ticket has skip label, skipping",
+			zap.String("label", s.cfg.SkipPRLabel))
+		return false
+	}
+
+	if s.previewResolver != nil {
+		preview := s.previewResolver.ResolveDiffPreview(item)
+		if preview.Awaiting(item.Labels) {
+			return s.submitApprovedPreview(logger, item, preview)
+		}
+	}
+
 	repos, err := s.repos.LocateRepos(item)
 	if err != nil {
 		logger.Warn("Failed to locate repos, skipping", zap.Error(err))
@@ -278,15 +450,34 @@ func (s *FeedbackScanner) checkAndSubmit(item models.WorkItem) bool {
 	}
 
 	s.updateFailureLabels(logger, item, repos, heads, obs, fl, ll, allLabels)
-	s.checkAndApplyMergedLabel(logger, item, repos, heads, ll, allLabels)
+	s.releaseMergeHolds(logger, item, heads)
+
+	stop, pending := s.submitBackport(logger, item, repos, heads, obs)
+	if stop {
+		return true
+	}
+	if !pending {
+		stop, pending = s.submitReleaseNote(logger, item, repos, heads, obs)
+		if stop {
+			return true
+		}
+	}
+	// A backport or release note that could not be submitted is
+	// retried next cycle, so the ticket must not leave "in review" yet.
+	if !pending {
+		s.checkAndApplyMergedLabel(logger, item, repos, heads, ll, allLabels)
+	}
 
 	if !obs.actionable {
 		return false
 	}
 
 	event := jobmanager.Event{
-		Type:      jobmanager.JobTypeFeedback,
-		TicketKey: item.Key,
+		Type:          jobmanager.JobTypeFeedback,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
 	}
 
 	_, err = s.submitter.Submit(event)
@@ -316,6 +507,209 @@ func (s *FeedbackScanner) checkAndSubmit(item models.WorkItem) bool {
 	return false
 }
 
+// submitApprovedPreview submits a new-ticket event, which opens the
+// PR, when the ticket's previewed change is approved. Returns true if
+// the scan cycle should stop.
+func (s *FeedbackScanner) submitApprovedPreview(logger *zap.Logger, item models.WorkItem, preview models.DiffPreview) bool {
+	comments, err := s.comments.GetComments(item.Key)
+	if err != nil {
+		logger.Warn("Failed to fetch comments for change preview", zap.Error(err))
+		return false
+	}
+	if !preview.Approved(item.Labels, comments, s.cfg.JiraUsername) {
+		logger.Debug("Change preview awaiting approval")
+		return false
+	}
+
+	event := jobmanager.Event{
+		Type:          jobmanager.JobTypeNewTicket,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
+	}
+
+	_, err = s.submitter.Submit(event)
+	if err == nil {
+		logger.Info("Submitted approved change preview")
+		return false
+	}
+
+	switch {
+	case errors.Is(err, jobmanager.ErrDuplicateJob):
+		logger.Debug("Skipping duplicate approved preview")
+	case errors.Is(err, jobmanager.ErrRetriesExhausted):
+		logger.Debug("Skipping exhausted ticket")
+	case errors.Is(err, jobmanager.ErrCircuitOpen):
+		logger.Warn("Circuit breaker open, stopping scan cycle")
+		return true
+	case errors.Is(err, jobmanager.ErrBudgetExceeded):
+		logger.Warn("Daily budget exceeded, stopping scan cycle")
+		return true
+	case errors.Is(err, jobmanager.ErrShutdown):
+		logger.Info("Job manager shut down, stopping scan cycle")
+		return true
+	default:
+		logger.Error("Failed to submit approved change preview", zap.Error(err))
+	}
+
+	return false
+}
+
+// submitBackport submits a backport event when all of the ticket's PRs
+// are merged and a release branch its backport labels request has no
+// backport PR yet. Backports are supported for single-repo workspaces
+// only. Returns stop when the scan cycle should stop, and pending when
+// the event could not be submitted and should be retried next cycle.
+func (s *FeedbackScanner) submitBackport(
+	logger *zap.Logger,
+	item models.WorkItem,
+	repos []models.RepoCoord,
+	heads []string,
+	obs repoObservation,
+) (stop, pending bool) {
+	if s.backportResolver == nil || obs.hasOpenPR || len(repos) != 1 {
+		return false, false
+	}
+	targets := s.backportResolver.ResolveBackportBranches(item)
+	if len(targets) == 0 || !s.detectMerge(logger, repos, heads) {
+		return false, false
+	}
+	if !s.hasMissingBackport(logger, item, repos[0], targets) {
+		return false, false
+	}
+
+	event := jobmanager.Event{
+		Type:          jobmanager.JobTypeBackport,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+	}
+
+	_, err := s.submitter.Submit(event)
+	if err == nil {
+		logger.Info("Submitted backport event", zap.Strings("targets", targets))
+		return false, false
+	}
+
+	switch {
+	case errors.Is(err, jobmanager.ErrDuplicateJob):
+		logger.Debug("Skipping duplicate backport")
+	case errors.Is(err, jobmanager.ErrRetriesExhausted):
+		logger.Debug("Skipping exhausted backport ticket")
+		return false, false
+	case errors.Is(err, jobmanager.ErrCircuitOpen):
+		logger.Warn("Circuit breaker open, stopping scan cycle")
+		return true, true
+	case errors.Is(err, jobmanager.ErrBudgetExceeded):
+		logger.Warn("Daily budget exceeded, stopping scan cycle")
+		return true, true
+	case errors.Is(err, jobmanager.ErrShutdown):
+		logger.Info("Job manager shut down, stopping scan cycle")
+		return true, true
+	default:
+		logger.Error("Failed to submit backport event", zap.Error(err))
+	}
+	return false, true
+}
+
+// submitReleaseNote submits a release note event when all of the
+// ticket's PRs are merged and its release note is still pending.
+// Release notes are supported for single-repo workspaces only.
+// Returns stop when the scan cycle should stop, and pending when the
+// event could not be submitted and should be retried next cycle.
+func (s *FeedbackScanner) submitReleaseNote(
+	logger *zap.Logger,
+	item models.WorkItem,
+	repos []models.RepoCoord,
+	heads []string,
+	obs repoObservation,
+) (stop, pending bool) {
+	if s.releaseNotesResolver == nil || obs.hasOpenPR || len(repos) != 1 {
+		return false, false
+	}
+	notes := s.releaseNotesResolver.ResolveReleaseNotes(item)
+	if !notes.Pending(item.Labels) || !s.detectMerge(logger, repos, heads) {
+		return false, false
+	}
+
+	event := jobmanager.Event{
+		Type:          jobmanager.JobTypeReleaseNote,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
+	}
+
+	_, err := s.submitter.Submit(event)
+	if err == nil {
+		logger.Info("Submitted release note event")
+		return false, false
+	}
+
+	switch {
+	case errors.Is(err, jobmanager.ErrDuplicateJob):
+		logger.Debug("Skipping duplicate release note")
+	case errors.Is(err, jobmanager.ErrRetriesExhausted):
+		logger.Debug("Skipping exhausted release note ticket")
+		return false, false
+	case errors.Is(err, jobmanager.ErrCircuitOpen):
+		logger.Warn("Circuit breaker open, stopping scan cycle")
+		return true, true
+	case errors.Is(err, jobmanager.ErrBudgetExceeded):
+		logger.Warn("Daily budget exceeded, stopping scan cycle")
+		return true, true
+	case errors.Is(err, jobmanager.ErrShutdown):
+		logger.Info("Job manager shut down, stopping scan cycle")
+		return true, true
+	default:
+		logger.Error("Failed to submit release note event", zap.Error(err))
+	}
+	return false, true
+}
+
+// hasMissingBackport reports whether any of the target branches has no
+// backport PR (open, merged, or closed) in the repository. Lookup
+// errors count as missing so that the executor makes the final
+// decision.
+func (s *FeedbackScanner) hasMissingBackport(
+	logger *zap.Logger,
+	item models.WorkItem,
+	r models.RepoCoord,
+	targets []string,
+) bool {
+	lookups := []func(owner, repo, head string) (*models.PRDetails, error){
+		s.prs.GetPRForBranch,
+		s.prs.GetMergedPRForBranch,
+		s.prs.GetClosedPRForBranch,
+	}
+	for _, target := range targets {
+		branch := models.BackportBranchName(s.cfg.BotUsername, item.Key, target)
+		found := false
+		for _, head := range s.repos.ForkOwnerHeads(item, branch) {
+			for _, lookup := range lookups {
+				pr, err := lookup(r.Owner, r.Repo, head)
+				if err != nil {
+					logger.Debug("Error looking up backport PR",
+						zap.String("head", head), zap.Error(err))
+					return true
+				}
+				if pr != nil {
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
+
 // repoObservation summarises PR/CI state across all repos for a ticket.
 type repoObservation struct {
 	actionable  bool // at least one repo has actionable comments or CI failures
@@ -341,6 +735,14 @@ func (s *FeedbackScanner) observeRepos(
 		}
 		obs.hasOpenPR = true
 
+		if snap, ok := s.unchangedSnapshot(r, pr); ok {
+			logger.Debug("No new PR activity, skipping comment and CI checks",
+				zap.String("repo", r.Owner+"/"+r.Repo),
+				zap.Int("pr", pr.Number))
+			obs.ciChecked = obs.ciChecked || snap.ciChecked
+			continue
+		}
+
 		if s.hasSkipLabel(logger, r, pr) {
 			continue
 		}
@@ -361,13 +763,23 @@ func (s *FeedbackScanner) observeRepos(
 			}
 		}
 
-		if commentfilter.HasNewActionable(comments, s.filterConfig()) {
-			obs.actionable = true
+		newComments := commentfilter.NewActionable(comments, s.filterConfig())
+		if s.reviewInProgress(newComments) {
+			logger.Debug("Review still in progress, waiting for the quiet period",
+				zap.String("repo", r.Owner+"/"+r.Repo),
+				zap.Int("pr", pr.Number),
+				zap.Duration("quiet_period", s.cfg.QuietPeriod))
+			// Not recorded: the next cycle must check the PR again
+			// even when no further comment arrives.
+			s.recordSnapshot(r, pr, true, ciResult)
+			continue
 		}
 
-		if ciResult.actionable {
+		actionable := len(newComments) > 0 || ciResult.actionable
+		if actionable {
 			obs.actionable = true
 		}
+		s.recordSnapshot(r, pr, actionable, ciResult)
 	}
 
 	if !obs.actionable {
@@ -376,6 +788,22 @@ func (s *FeedbackScanner) observeRepos(
 	return obs
 }
 
+// reviewInProgress reports whether the newest of the actionable
+// comments arrived within the quiet period, in which case the reviewer
+// may still be adding comments. Always false without a quiet period.
+func (s *FeedbackScanner) reviewInProgress(comments []models.PRComment) bool {
+	if s.cfg.QuietPeriod <= 0 || len(comments) == 0 {
+		return false
+	}
+	var latest time.Time
+	for _, c := range comments {
+		if c.Timestamp.After(latest) {
+			latest = c.Timestamp
+		}
+	}
+	return s.now().Sub(latest) < s.cfg.QuietPeriod
+}
+
 // findOpenPRForRepo tries each candidate head for a repo and returns
 // the first open PR found, or nil if none match.
 func (s *FeedbackScanner) findOpenPRForRepo(
@@ -399,6 +827,12 @@ func (s *FeedbackScanner) findOpenPRForRepo(
 	return nil
 }
 
+// hasTicketSkipLabel reports whether the Jira ticket carries the
+// skip-PR label, which puts all of its PRs on hold.
+func hasTicketSkipLabel(item models.WorkItem, label string) bool {
+	return label != "" && slices.Contains(item.Labels, label)
+}
+
 // hasSkipLabel checks whether the skip-PR label is present on the
 // given PR. Returns false when skip-label checking is not configured
 // or on API error (fail-open).
@@ -583,15 +1017,16 @@ func (s *FeedbackScanner) detectMerge(
 ) bool {
 	hadPR := 0
 	for _, r := range repos {
-		state := s.detectRepoPRState(logger, r, heads)
+		state, ok := s.detectRepoPRState(logger, r, heads)
+		if !ok {
+			return false
+		}
 		switch state {
-		case prStateMerged:
+		case scm.PRStateMerged:
 			hadPR++
-		case prStateOpen, prStateClosed:
+		case scm.PRStateOpen, scm.PRStateClosed:
 			return false
-		case prStateError:
-			return false
-		case prStateNone:
+		case scm.PRStateNone:
 			logger.Debug("Repo skipped — no PR ever created",
 				zap.String("repo", r.Owner+"/"+r.Repo))
 		}
@@ -602,65 +1037,80 @@ func (s *FeedbackScanner) detectMerge(
 	return hadPR > 0
 }
 
-type prState int
+// releaseMergeHolds removes the do-not-merge label from the first
+// open pull request in the ticket's merge order once a pull request
+// before it was merged and none before it is still pending. Pull
+// requests further down keep the label until their turn. Repos
+// without a PR are skipped; errors are logged and retried next cycle.
+func (s *FeedbackScanner) releaseMergeHolds(logger *zap.Logger, item models.WorkItem, heads []string) {
+	if s.mergeOrderResolver == nil || s.prLabeler == nil {
+		return
+	}
+	order := s.mergeOrderResolver.ResolveMergeOrder(item)
+	if order.DoNotMergeLabel == "" {
+		return
+	}
 
-const (
-	prStateNone   prState = iota // no PR ever created under any head
-	prStateMerged                // merged PR found
-	prStateOpen                  // open (unmerged) PR found
-	prStateClosed                // closed (not merged) PR found
-	prStateError                 // API error during lookup
-)
+	mergedBefore := false
+	for _, r := range order.Repos {
+		state, ok := s.detectRepoPRState(logger, r, heads)
+		if !ok {
+			return
+		}
+		switch state {
+		case scm.PRStateNone:
+			continue
+		case scm.PRStateMerged:
+			mergedBefore = true
+			continue
+		case scm.PRStateOpen:
+			if mergedBefore {
+				s.releaseHold(logger, r, heads, order.DoNotMergeLabel)
+			}
+		}
+		return
+	}
+}
 
-// detectRepoPRState resolves the PR state for a single repo by
-// checking all candidate heads per state in priority order
-// (merged > open > closed). This ensures a merged PR under one
-// head is not masked by a stale closed PR under a different head.
+// releaseHold removes label from the open pull request of r.
+func (s *FeedbackScanner) releaseHold(logger *zap.Logger, r models.RepoCoord, heads []string, label string) {
+	logger = logger.With(zap.String("repo", r.Owner+"/"+r.Repo))
+	pr := s.findOpenPRForRepo(logger, r, heads)
+	if pr == nil {
+		return
+	}
+	held, err := s.prLabeler.HasPRLabel(r.Owner, r.Repo, pr.Number, label)
+	if err != nil {
+		logger.Warn("Failed to check do-not-merge label", zap.Int("pr", pr.Number), zap.Error(err))
+		return
+	}
+	if !held {
+		return
+	}
+	if err := s.prLabeler.RemovePRLabel(r.Owner, r.Repo, pr.Number, label); err != nil {
+		logger.Warn("Failed to remove do-not-merge label", zap.Int("pr", pr.Number), zap.Error(err))
+		return
+	}
+	logger.Info("Earlier PRs merged, removed do-not-merge label",
+		zap.Int("pr", pr.Number), zap.String("label", label))
+}
+
+// detectRepoPRState resolves the PR state for a single repo across
+// all candidate heads (see [scm.RepoPRState]). Returns false, after
+// logging it, when a lookup failed.
 func (s *FeedbackScanner) detectRepoPRState(
 	logger *zap.Logger,
 	r models.RepoCoord,
 	heads []string,
-) prState {
-	for _, head := range heads {
-		merged, err := s.prs.GetMergedPRForBranch(r.Owner, r.Repo, head)
-		if err != nil {
-			logger.Warn("Error checking for merged PR",
-				zap.String("repo", r.Owner+"/"+r.Repo),
-				zap.String("head", head),
-				zap.Error(err))
-			return prStateError
-		}
-		if merged != nil {
-			return prStateMerged
-		}
-	}
-	for _, head := range heads {
-		open, err := s.prs.GetPRForBranch(r.Owner, r.Repo, head)
-		if err != nil {
-			logger.Warn("Error checking for open PR",
-				zap.String("repo", r.Owner+"/"+r.Repo),
-				zap.String("head", head),
-				zap.Error(err))
-			return prStateError
-		}
-		if open != nil {
-			return prStateOpen
-		}
-	}
-	for _, head := range heads {
-		closed, err := s.prs.GetClosedPRForBranch(r.Owner, r.Repo, head)
-		if err != nil {
-			logger.Warn("Error checking for closed PR",
-				zap.String("repo", r.Owner+"/"+r.Repo),
-				zap.String("head", head),
-				zap.Error(err))
-			return prStateError
-		}
-		if closed != nil {
-			return prStateClosed
-		}
+) (scm.PRState, bool) {
+	state, err := scm.RepoPRState(s.prs, r.Owner, r.Repo, heads)
+	if err != nil {
+		logger.Warn("Error checking PR state",
+			zap.String("repo", r.Owner+"/"+r.Repo),
+			zap.Error(err))
+		return scm.PRStateNone, false
 	}
-	return prStateNone
+	return state, true
 }
 
 // checkCI queries CI status for a PR and returns the full state:
@@ -791,6 +1241,7 @@ func (s *FeedbackScanner) filterConfig() commentfilter.Config {
 	return commentfilter.Config{
 		BotUsername:       s.cfg.BotUsername,
 		IgnoredUsernames:  s.cfg.IgnoredUsernames,
+		IgnoredPaths:      s.cfg.IgnoredCommentPaths,
 		KnownBotUsernames: s.cfg.KnownBotUsernames,
 		MaxThreadDepth:    s.cfg.MaxThreadDepth,
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -73,6 +74,13 @@ func TestNewFeedbackScanner_Validation(t *testing.T) {
 			cfg: scanner.FeedbackScannerConfig{PollInterval: time.Minute}, logger: zap.NewNop(),
 			wantErr: "bot username",
 		},
+		{
+			name: "negative quiet period", searcher: &scannertest.StubIssueSearcher{},
+			submitter: &scannertest.StubJobSubmitter{},
+			prs:       &scannertest.StubPRFetcher{}, repos: &scannertest.StubRepoLocator{},
+			cfg: scanner.FeedbackScannerConfig{PollInterval: time.Minute, BotUsername: "bot", QuietPeriod: -time.Minute}, logger: zap.NewNop(),
+			wantErr: "quiet period",
+		},
 		{
 			name: "nil logger", searcher: &scannertest.StubIssueSearcher{},
 			submitter: &scannertest.StubJobSubmitter{},
@@ -493,6 +501,40 @@ func TestFeedbackScanner_RestartAfterStop(t *testing.T) {
 	}
 }
 
+func TestFeedbackScanner_ScheduleWaitsForMatch(t *testing.T) {
+	d := newFeedbackDeps()
+
+	var mu sync.Mutex
+	var scanCount int
+	d.searcher.SearchWorkItemsFunc = func(_ models.SearchCriteria) ([]models.WorkItem, error) {
+		mu.Lock()
+		scanCount++
+		mu.Unlock()
+		return nil, nil
+	}
+	yearly, err := models.ParseCron("0 0 1 1 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.cfg.Schedule = yearly
+	d.cfg.PollInterval = 10 * time.Millisecond
+
+	s := d.scanner(t)
+	runOneFeedbackScan(t, s)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if scanCount != 0 {
+		t.Errorf("scanned %d times before the schedule matched", scanCount)
+	}
+	if !s.LastScan().IsZero() {
+		t.Errorf("LastScan = %v before the schedule matched, want zero", s.LastScan())
+	}
+	if s.LastHeartbeat().IsZero() {
+		t.Error("LastHeartbeat not marked while waiting for the schedule")
+	}
+}
+
 // --- Branch name convention ---
 
 func TestFeedbackScanner_UsesBranchConvention(t *testing.T) {
@@ -941,6 +983,11 @@ type feedbackDeps struct {
 	lifecycleLabelResolver *scannertest.StubLifecycleLabelResolver
 	mergedStatusResolver   *scannertest.StubMergedStatusResolver
 	statusTransitioner     *scannertest.StubStatusTransitioner
+	backportResolver       *scannertest.StubBackportResolver
+	releaseNotesResolver   *scannertest.StubReleaseNotesResolver
+	mergeOrderResolver     *scannertest.StubMergeOrderResolver
+	previewResolver        *scannertest.StubDiffPreviewResolver
+	comments               *scannertest.StubCommentFetcher
 }
 
 func newFeedbackDeps() *feedbackDeps {
@@ -996,6 +1043,18 @@ func (d *feedbackDeps) scanner(t *testing.T) *scanner.FeedbackScanner {
 		}
 		opts = append(opts, scanner.WithLifecycleLabelManager(d.lifecycleLabelResolver, mr, st))
 	}
+	if d.backportResolver != nil {
+		opts = append(opts, scanner.WithBackports(d.backportResolver))
+	}
+	if d.releaseNotesResolver != nil {
+		opts = append(opts, scanner.WithReleaseNotes(d.releaseNotesResolver))
+	}
+	if d.mergeOrderResolver != nil {
+		opts = append(opts, scanner.WithMergeOrder(d.mergeOrderResolver))
+	}
+	if d.previewResolver != nil && d.comments != nil {
+		opts = append(opts, scanner.WithDiffPreviews(d.previewResolver, d.comments))
+	}
 	s, err := scanner.NewFeedbackScanner(
 		d.searcher, d.submitter, d.prs, d.repos, d.ci, d.cfg, zap.NewNop(), opts...)
 	if err != nil {
@@ -1879,6 +1938,174 @@ func TestFeedbackScanner_LifecycleLabels_MultiRepo_NoPRsAnywhere(t *testing.T) {
 	}
 }
 
+// --- PR activity snapshots ---
+
+func TestFeedbackScanner_SkipsPRsWithoutNewActivity(t *testing.T) {
+	d := newFeedbackDeps()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	d.cfg.Clock = func() time.Time { return now }
+
+	updatedAt := now.Add(-time.Minute)
+	headSHA := "abc123"
+	d.prs.GetPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, HeadSHA: headSHA, UpdatedAt: updatedAt}, nil
+	}
+	fetches := 0
+	d.prs.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		fetches++
+		return []models.PRComment{}, nil
+	}
+	d.ci = &scannertest.StubCIChecker{
+		ListCheckRunsForRefFunc: func(_, _, _ string) ([]models.CheckRunFailure, bool, error) {
+			return nil, true, nil
+		},
+	}
+
+	s := d.scanner(t)
+	runOneFeedbackScan(t, s)
+	runOneFeedbackScan(t, s)
+	if fetches != 1 {
+		t.Fatalf("comment fetches = %d, want 1 (second scan reuses the snapshot)", fetches)
+	}
+
+	updatedAt = now
+	runOneFeedbackScan(t, s)
+	if fetches != 2 {
+		t.Fatalf("comment fetches = %d, want 2 after new PR activity", fetches)
+	}
+
+	headSHA = "def456"
+	runOneFeedbackScan(t, s)
+	if fetches != 3 {
+		t.Fatalf("comment fetches = %d, want 3 after a push", fetches)
+	}
+
+	now = now.Add(time.Hour)
+	runOneFeedbackScan(t, s)
+	if fetches != 4 {
+		t.Fatalf("comment fetches = %d, want 4 after the snapshot expired", fetches)
+	}
+}
+
+func TestFeedbackScanner_RechecksPRsWithActionableFeedback(t *testing.T) {
+	d := newFeedbackDeps()
+	d.prs.GetPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, UpdatedAt: time.Now()}, nil
+	}
+	fetches := 0
+	d.prs.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		fetches++
+		return []models.PRComment{
+			{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Fix this"},
+		}, nil
+	}
+	submits := 0
+	d.submitter.SubmitFunc = func(_ jobmanager.Event) (*jobmanager.Job, error) {
+		submits++
+		return nil, jobmanager.ErrBudgetExceeded
+	}
+
+	s := d.scanner(t)
+	runOneFeedbackScan(t, s)
+	runOneFeedbackScan(t, s)
+
+	// A submission that failed must be retried on the next cycle.
+	if fetches != 2 || submits != 2 {
+		t.Errorf("fetches = %d, submits = %d, want 2 each", fetches, submits)
+	}
+}
+
+func TestFeedbackScanner_RechecksPRsWithPendingCI(t *testing.T) {
+	d := newFeedbackDeps()
+	updatedAt := time.Now()
+	d.prs.GetPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, HeadSHA: "abc123", UpdatedAt: updatedAt}, nil
+	}
+	fetches := 0
+	d.prs.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		fetches++
+		return []models.PRComment{}, nil
+	}
+	d.ci = &scannertest.StubCIChecker{
+		ListCheckRunsForRefFunc: func(_, _, _ string) ([]models.CheckRunFailure, bool, error) {
+			return nil, false, nil
+		},
+	}
+
+	s := d.scanner(t)
+	runOneFeedbackScan(t, s)
+	runOneFeedbackScan(t, s)
+
+	// Check runs finishing do not update the PR.
+	if fetches != 2 {
+		t.Errorf("comment fetches = %d, want 2 while CI is pending", fetches)
+	}
+}
+
+// --- Quiet period ---
+
+func TestFeedbackScanner_QuietPeriod_WaitsForReviewToSettle(t *testing.T) {
+	d := newFeedbackDeps()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	d.cfg.Clock = func() time.Time { return now }
+	d.cfg.QuietPeriod = 10 * time.Minute
+
+	commentedAt := now.Add(-2 * time.Minute)
+	d.prs.GetPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head, UpdatedAt: commentedAt}, nil
+	}
+	d.prs.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{
+			{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Fix this", Timestamp: now.Add(-20 * time.Minute)},
+			{ID: 2, Author: models.Author{Username: "reviewer"}, Body: "And this", Timestamp: commentedAt},
+		}, nil
+	}
+	submitted := 0
+	d.submitter.SubmitFunc = func(_ jobmanager.Event) (*jobmanager.Job, error) {
+		submitted++
+		return &jobmanager.Job{}, nil
+	}
+
+	s := d.scanner(t)
+	runOneFeedbackScan(t, s)
+	if submitted != 0 {
+		t.Fatalf("submitted %d events while the review is in progress, want 0", submitted)
+	}
+
+	// No further comments: the PR must be checked again anyway.
+	now = now.Add(8 * time.Minute)
+	runOneFeedbackScan(t, s)
+	if submitted != 1 {
+		t.Fatalf("submitted %d events after the quiet period, want 1", submitted)
+	}
+}
+
+func TestFeedbackScanner_QuietPeriod_IgnoresAddressedComments(t *testing.T) {
+	d := newFeedbackDeps()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	d.cfg.Clock = func() time.Time { return now }
+	d.cfg.QuietPeriod = 10 * time.Minute
+
+	d.prs.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{
+			{ID: 1, Author: models.Author{Username: "reviewer"}, Body: "Fix this", Timestamp: now.Add(-time.Hour)},
+			// The bot's own reply is recent but is not review activity.
+			{ID: 2, Author: models.Author{Username: "ai-bot"}, Body: "Done", Timestamp: now.Add(-time.Minute)},
+		}, nil
+	}
+	var submitted bool
+	d.submitter.SubmitFunc = func(_ jobmanager.Event) (*jobmanager.Job, error) {
+		submitted = true
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if !submitted {
+		t.Error("expected an event: the only new comment is older than the quiet period")
+	}
+}
+
 // --- Skip PR label ---
 
 func TestFeedbackScanner_SkipPRLabel_SkipsPR(t *testing.T) {
@@ -1914,6 +2141,35 @@ func TestFeedbackScanner_SkipPRLabel_SkipsPR(t *testing.T) {
 	}
 }
 
+func TestFeedbackScanner_SkipPRLabel_OnTicketSkipsPRs(t *testing.T) {
+	d := newFeedbackDeps()
+	d.cfg.SkipPRLabel = "ai-bot-skip"
+	d.searcher.SearchWorkItemsFunc = func(_ models.SearchCriteria) ([]models.WorkItem, error) {
+		return []models.WorkItem{{Key: "PROJ-1", Labels: []string{"ai-bot-skip"}}}, nil
+	}
+
+	var submitted bool
+	d.submitter.SubmitFunc = func(_ jobmanager.Event) (*jobmanager.Job, error) {
+		submitted = true
+		return &jobmanager.Job{}, nil
+	}
+
+	var prLookedUp bool
+	d.prs.GetPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+		prLookedUp = true
+		return nil, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if submitted {
+		t.Error("expected no event to be submitted when ticket has skip label")
+	}
+	if prLookedUp {
+		t.Error("expected PRs not to be looked up when ticket has skip label")
+	}
+}
+
 func TestFeedbackScanner_SkipPRLabel_ErrorFailsOpen(t *testing.T) {
 	d := newFeedbackDeps()
 	d.cfg.SkipPRLabel = "ai-bot-skip"
@@ -2033,3 +2289,366 @@ func TestFeedbackScanner_SkipPRLabel_MultiRepo_SkipsOnlyLabeledPR(t *testing.T)
 		t.Error("expected feedback event from active-repo's actionable comments")
 	}
 }
+
+// --- Backports ---
+
+// withMergedBackportTicket makes the ticket's own PR merged and
+// requests a backport to release-4.17.
+func withMergedBackportTicket(d *feedbackDeps) {
+	d.prs.GetPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+		return nil, nil
+	}
+	d.prs.GetMergedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		if head == "ai-bot/PROJ-1" {
+			return &models.PRDetails{Number: 42}, nil
+		}
+		return nil, nil
+	}
+	d.backportResolver = &scannertest.StubBackportResolver{
+		ResolveBackportBranchesFunc: func(_ models.WorkItem) []string {
+			return []string{"release-4.17"}
+		},
+	}
+}
+
+func TestFeedbackScanner_Backport_SubmittedAfterMerge(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergedBackportTicket(d)
+
+	var events []jobmanager.Event
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		events = append(events, event)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if len(events) != 1 || events[0].Type != jobmanager.JobTypeBackport || events[0].TicketKey != "PROJ-1" {
+		t.Errorf("events = %+v, want one backport event for PROJ-1", events)
+	}
+}
+
+func TestFeedbackScanner_Backport_NotSubmittedWhileOpen(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergedBackportTicket(d)
+	d.prs.GetPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		return &models.PRDetails{Number: 42, Branch: head}, nil
+	}
+	d.prs.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{}, nil
+	}
+
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		t.Errorf("unexpected %s event", event.Type)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+}
+
+func TestFeedbackScanner_Backport_NotSubmittedWhenBackportPRExists(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergedBackportTicket(d)
+	d.prs.GetClosedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		if head == "ai-bot/PROJ-1-backport-release-4.17" {
+			return &models.PRDetails{Number: 51}, nil
+		}
+		return nil, nil
+	}
+
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		t.Errorf("unexpected %s event", event.Type)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+}
+
+func TestFeedbackScanner_Backport_DefersMergedTransitionUntilSubmitted(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergedBackportTicket(d)
+	d.labels = &scannertest.StubLabelManager{
+		AddLabelFunc:    func(_, _ string) error { return nil },
+		RemoveLabelFunc: func(_, _ string) error { return nil },
+	}
+	d.labelResolver = &scannertest.StubFailureLabelResolver{}
+	d.lifecycleLabelResolver = &scannertest.StubLifecycleLabelResolver{
+		ResolveLifecycleLabelsFunc: func(_ models.WorkItem) models.LifecycleLabels {
+			return models.LifecycleLabels{Merged: "merged"}
+		},
+	}
+	d.mergedStatusResolver = &scannertest.StubMergedStatusResolver{
+		ResolveMergedStatusFunc: func(_ models.WorkItem) string { return "Closed" },
+	}
+	transitioned := false
+	d.statusTransitioner = &scannertest.StubStatusTransitioner{
+		TransitionStatusFunc: func(_, _ string) error {
+			transitioned = true
+			return nil
+		},
+	}
+	d.submitter.SubmitFunc = func(_ jobmanager.Event) (*jobmanager.Job, error) {
+		return nil, jobmanager.ErrDuplicateJob
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if transitioned {
+		t.Error("expected the ticket to stay in review until the backport is submitted")
+	}
+}
+
+// --- Release notes ---
+
+// withMergedReleaseNoteTicket makes the ticket's own PR merged and
+// configures a release note field.
+func withMergedReleaseNoteTicket(d *feedbackDeps) {
+	d.prs.GetPRForBranchFunc = func(_, _, _ string) (*models.PRDetails, error) {
+		return nil, nil
+	}
+	d.prs.GetMergedPRForBranchFunc = func(_, _, head string) (*models.PRDetails, error) {
+		if head == "ai-bot/PROJ-1" {
+			return &models.PRDetails{Number: 42}, nil
+		}
+		return nil, nil
+	}
+	d.releaseNotesResolver = &scannertest.StubReleaseNotesResolver{
+		ResolveReleaseNotesFunc: func(_ models.WorkItem) models.ReleaseNotes {
+			return models.ReleaseNotes{Field: "Release Note Text"}
+		},
+	}
+}
+
+func TestFeedbackScanner_ReleaseNote_SubmittedAfterMerge(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergedReleaseNoteTicket(d)
+
+	var events []jobmanager.Event
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		events = append(events, event)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if len(events) != 1 || events[0].Type != jobmanager.JobTypeReleaseNote || events[0].TicketKey != "PROJ-1" {
+		t.Errorf("events = %+v, want one release note event for PROJ-1", events)
+	}
+}
+
+func TestFeedbackScanner_ReleaseNote_NotSubmittedWhenNoted(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergedReleaseNoteTicket(d)
+	d.searcher.SearchWorkItemsFunc = func(_ models.SearchCriteria) ([]models.WorkItem, error) {
+		return []models.WorkItem{{Key: "PROJ-1", Labels: []string{"ai-release-noted"}}}, nil
+	}
+
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		t.Errorf("unexpected %s event", event.Type)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+}
+
+func TestFeedbackScanner_ReleaseNote_WaitsForBackport(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergedBackportTicket(d)
+	withMergedReleaseNoteTicket(d)
+
+	var events []jobmanager.JobType
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		events = append(events, event.Type)
+		return nil, jobmanager.ErrDuplicateJob
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if !slices.Equal(events, []jobmanager.JobType{jobmanager.JobTypeBackport}) {
+		t.Errorf("events = %v, want only the pending backport", events)
+	}
+}
+
+// --- Merge order ---
+
+// withMergeOrder configures a three-repo merge order api → core → ui
+// held back with the "do-not-merge" label. merged lists the repos
+// whose PR is merged; the others have an open PR numbered after
+// their position.
+func withMergeOrder(d *feedbackDeps, merged ...string) {
+	order := []models.RepoCoord{
+		{Owner: "org", Repo: "api"},
+		{Owner: "org", Repo: "core"},
+		{Owner: "org", Repo: "ui"},
+	}
+	d.repos.LocateReposFunc = func(_ models.WorkItem) ([]models.RepoCoord, error) {
+		return order, nil
+	}
+	d.prs.GetMergedPRForBranchFunc = func(_, repo, _ string) (*models.PRDetails, error) {
+		if slices.Contains(merged, repo) {
+			return &models.PRDetails{Number: 1}, nil
+		}
+		return nil, nil
+	}
+	d.prs.GetPRForBranchFunc = func(_, repo, head string) (*models.PRDetails, error) {
+		if slices.Contains(merged, repo) {
+			return nil, nil
+		}
+		number := map[string]int{"api": 10, "core": 20, "ui": 30}[repo]
+		return &models.PRDetails{Number: number, Branch: head}, nil
+	}
+	d.prs.GetPRCommentsFunc = func(_, _ string, _ int, _ time.Time) ([]models.PRComment, error) {
+		return []models.PRComment{}, nil
+	}
+	d.mergeOrderResolver = &scannertest.StubMergeOrderResolver{
+		ResolveMergeOrderFunc: func(_ models.WorkItem) models.MergeOrder {
+			return models.MergeOrder{Repos: order, DoNotMergeLabel: "do-not-merge"}
+		},
+	}
+}
+
+// recordRemovedPRLabels makes every PR carry the do-not-merge label
+// and returns the "repo#number" of PRs it was removed from.
+func recordRemovedPRLabels(d *feedbackDeps) *[]string {
+	var removed []string
+	d.prLabeler = &scannertest.StubPRLabeler{
+		HasPRLabelFunc: func(_, _ string, _ int, label string) (bool, error) {
+			return label == "do-not-merge", nil
+		},
+		RemovePRLabelFunc: func(_, repo string, number int, _ string) error {
+			removed = append(removed, fmt.Sprintf("%s#%d", repo, number))
+			return nil
+		},
+	}
+	return &removed
+}
+
+func TestFeedbackScanner_MergeOrder_ReleasesNextPRAfterMerge(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergeOrder(d, "api")
+	removed := recordRemovedPRLabels(d)
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if want := []string{"core#20"}; !slices.Equal(*removed, want) {
+		t.Errorf("removed labels from %v, want %v", *removed, want)
+	}
+}
+
+func TestFeedbackScanner_MergeOrder_KeepsHoldsWhileFirstPROpen(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergeOrder(d)
+	removed := recordRemovedPRLabels(d)
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if len(*removed) != 0 {
+		t.Errorf("removed labels from %v, want none", *removed)
+	}
+}
+
+func TestFeedbackScanner_MergeOrder_ReleasesLastPRAfterEarlierMerged(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergeOrder(d, "api", "core")
+	removed := recordRemovedPRLabels(d)
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if want := []string{"ui#30"}; !slices.Equal(*removed, want) {
+		t.Errorf("removed labels from %v, want %v", *removed, want)
+	}
+}
+
+func TestFeedbackScanner_MergeOrder_SkipsUnlabeledPR(t *testing.T) {
+	d := newFeedbackDeps()
+	withMergeOrder(d, "api")
+	removed := recordRemovedPRLabels(d)
+	d.prLabeler.HasPRLabelFunc = func(_, _ string, _ int, _ string) (bool, error) {
+		return false, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if len(*removed) != 0 {
+		t.Errorf("removed labels from %v, want none", *removed)
+	}
+}
+
+// --- Change previews ---
+
+// withPreviewAwaitingApproval makes PROJ-1 await approval of its
+// previewed change, with the given ticket comments.
+func withPreviewAwaitingApproval(d *feedbackDeps, comments ...string) {
+	d.searcher.SearchWorkItemsFunc = func(_ models.SearchCriteria) ([]models.WorkItem, error) {
+		return []models.WorkItem{{Key: "PROJ-1", Labels: []string{"ai-awaiting-approval"}}}, nil
+	}
+	d.previewResolver = &scannertest.StubDiffPreviewResolver{
+		ResolveDiffPreviewFunc: func(_ models.WorkItem) models.DiffPreview {
+			return models.DiffPreview{Mode: models.DiffPreviewAlways}
+		},
+	}
+	d.comments = &scannertest.StubCommentFetcher{
+		GetCommentsFunc: func(_ string) ([]models.Comment, error) {
+			var result []models.Comment
+			for _, body := range comments {
+				result = append(result, models.Comment{Body: body})
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestFeedbackScanner_DiffPreview_SubmitsNewTicketOnApproval(t *testing.T) {
+	d := newFeedbackDeps()
+	withPreviewAwaitingApproval(d, models.DiffPreviewMarker+" Change awaiting approval", " /Approve ")
+
+	var events []jobmanager.Event
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		events = append(events, event)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+
+	if len(events) != 1 || events[0].Type != jobmanager.JobTypeNewTicket || events[0].TicketKey != "PROJ-1" {
+		t.Errorf("events = %+v, want one new-ticket event for PROJ-1", events)
+	}
+}
+
+func TestFeedbackScanner_DiffPreview_IgnoresBotApproval(t *testing.T) {
+	d := newFeedbackDeps()
+	d.cfg.JiraUsername = "bot@example.com"
+	withPreviewAwaitingApproval(d)
+	d.comments = &scannertest.StubCommentFetcher{
+		GetCommentsFunc: func(_ string) ([]models.Comment, error) {
+			return []models.Comment{
+				{Body: models.DiffPreviewMarker + " Change awaiting approval", AuthorEmail: "bot@example.com"},
+				{Body: "/approve", AuthorEmail: "bot@example.com"},
+			}, nil
+		},
+	}
+
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		t.Errorf("unexpected %s event", event.Type)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+}
+
+func TestFeedbackScanner_DiffPreview_WaitsForApproval(t *testing.T) {
+	d := newFeedbackDeps()
+	// An approval before the latest preview does not count.
+	withPreviewAwaitingApproval(d, "/approve", models.DiffPreviewMarker+" Change awaiting approval")
+	d.repos.LocateReposFunc = func(_ models.WorkItem) ([]models.RepoCoord, error) {
+		t.Error("previewed ticket checked for PR feedback")
+		return nil, nil
+	}
+
+	d.submitter.SubmitFunc = func(event jobmanager.Event) (*jobmanager.Job, error) {
+		t.Errorf("unexpected %s event", event.Type)
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneFeedbackScan(t, d.scanner(t))
+}
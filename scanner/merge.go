@@ -48,11 +48,22 @@ type MergeScannerConfig struct {
 	// considered human activity for idle detection.
 	KnownBotUsernames []string
 
-	// SkipPRLabel is the GitHub label that tells the bot to skip
-	// a PR entirely. Empty disables the check.
+	// SkipPRLabel is the label that tells the bot to skip a PR
+	// entirely, on the PR itself or on its Jira ticket. Empty
+	// disables the check.
 	SkipPRLabel string
 }
 
+func (c MergeScannerConfig) validate() error {
+	if c.PollInterval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	if c.BotUsername == "" {
+		return errors.New("bot username must not be empty")
+	}
+	return nil
+}
+
 // MergeScanner polls for tickets in "in review" status and checks
 // whether their PRs are mergeable with the target branch. When a PR
 // has merge conflicts, the scanner emits [jobmanager.JobTypeMerge]
@@ -71,6 +82,9 @@ type MergeScanner struct {
 	mu     sync.Mutex
 	cancel context.CancelFunc
 	done   chan struct{}
+
+	lastScan scanTimestamp
+	updates  configUpdate[MergeScannerConfig]
 }
 
 // NewMergeScanner creates a MergeScanner with the given dependencies.
@@ -103,11 +117,8 @@ func NewMergeScanner(
 	if labeler == nil {
 		return nil, errors.New("PR labeler must not be nil")
 	}
-	if cfg.PollInterval <= 0 {
-		return nil, errors.New("poll interval must be positive")
-	}
-	if cfg.BotUsername == "" {
-		return nil, errors.New("bot username must not be empty")
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 	if logger == nil {
 		return nil, errors.New("logger must not be nil")
@@ -154,10 +165,30 @@ func (s *MergeScanner) Stop() {
 	}
 }
 
+// LastScan returns when the most recent scan cycle finished, or the
+// zero time if no cycle has completed yet.
+func (s *MergeScanner) LastScan() time.Time {
+	return s.lastScan.get()
+}
+
+// UpdateConfig replaces the scanner's configuration. The new
+// configuration takes effect at the start of the next scan cycle,
+// and the poll ticker is reset to the new interval. Returns an error
+// if cfg is invalid, in which case the current configuration is kept.
+func (s *MergeScanner) UpdateConfig(cfg MergeScannerConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	s.updates.offer(cfg)
+	return nil
+}
+
 func (s *MergeScanner) run(ctx context.Context) {
 	defer close(s.done)
 
+	s.applyPendingConfig()
 	s.scan(ctx)
+	s.lastScan.mark()
 
 	ticker := time.NewTicker(s.cfg.PollInterval)
 	defer ticker.Stop()
@@ -166,16 +197,35 @@ func (s *MergeScanner) run(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
+		case <-s.updates.ready():
+			if s.applyPendingConfig() {
+				ticker.Reset(s.cfg.PollInterval)
+			}
 		case <-ticker.C:
 			s.scan(ctx)
+			s.lastScan.mark()
 		}
 	}
 }
 
+// applyPendingConfig installs a configuration passed to UpdateConfig,
+// if any. Called only from the polling goroutine. Reports whether
+// the configuration changed.
+func (s *MergeScanner) applyPendingConfig() bool {
+	cfg, ok := s.updates.take()
+	if !ok {
+		return false
+	}
+	s.cfg = cfg
+	s.logger.Info("Scanner configuration updated",
+		zap.Duration("poll_interval", cfg.PollInterval))
+	return true
+}
+
 func (s *MergeScanner) scan(ctx context.Context) {
 	items, err := s.searcher.SearchWorkItems(s.cfg.Criteria)
 	if err != nil {
-		s.logger.Error("Failed to search for in-review tickets", zap.Error(err))
+		logSearchError(s.logger, "Failed to search for in-review tickets", err)
 		return
 	}
 
@@ -202,6 +252,12 @@ func (s *MergeScanner) scan(ctx context.Context) {
 func (s *MergeScanner) checkAndSubmit(item models.WorkItem) bool {
 	logger := s.logger.With(zap.String("ticket", item.Key))
 
+	if hasTicketSkipLabel(item, s.cfg.SkipPRLabel) {
+		logger.Debug("Ticket has skip label, skipping",
+			zap.String("label", s.cfg.SkipPRLabel))
+		return false
+	}
+
 	repos, err := s.repos.LocateRepos(item)
 	if err != nil {
 		logger.Warn("Failed to locate repos, skipping", zap.Error(err))
@@ -223,8 +279,11 @@ func (s *MergeScanner) checkAndSubmit(item models.WorkItem) bool {
 	}
 
 	event := jobmanager.Event{
-		Type:      jobmanager.JobTypeMerge,
-		TicketKey: item.Key,
+		Type:          jobmanager.JobTypeMerge,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
 	}
 
 	_, err = s.submitter.Submit(event)
@@ -1014,6 +1014,36 @@ func TestMergeScanner_SkipPRLabel_SkipsPR(t *testing.T) {
 	}
 }
 
+func TestMergeScanner_SkipPRLabel_OnTicketSkipsPRs(t *testing.T) {
+	d := newMergeDeps()
+	d.cfg.SkipPRLabel = "ai-bot-skip"
+	d.searcher.SearchWorkItemsFunc = func(_ models.SearchCriteria) ([]models.WorkItem, error) {
+		return []models.WorkItem{{Key: "PROJ-1", Labels: []string{"ai-bot-skip"}}}, nil
+	}
+
+	mergeChecked := false
+	mergeable := false
+	d.mergeCheck.GetPRMergeabilityFunc = func(_, _ string, _ int) (*models.PRMergeState, error) {
+		mergeChecked = true
+		return &models.PRMergeState{Mergeable: &mergeable}, nil
+	}
+
+	var submitted bool
+	d.submitter.SubmitFunc = func(_ jobmanager.Event) (*jobmanager.Job, error) {
+		submitted = true
+		return &jobmanager.Job{}, nil
+	}
+
+	runOneMergeScan(t, d.scanner(t))
+
+	if mergeChecked {
+		t.Error("expected mergeability check to be skipped when ticket has skip label")
+	}
+	if submitted {
+		t.Error("expected no merge event when ticket has skip label")
+	}
+}
+
 func TestMergeScanner_SkipPRLabel_ErrorFailsOpen(t *testing.T) {
 	d := newMergeDeps()
 	d.cfg.SkipPRLabel = "ai-bot-skip"
@@ -9,6 +9,10 @@
 // it queries each cycle and relies on the job manager for
 // deduplication.
 //
+// Discovery can be split into [ScanSchedule] entries so that projects
+// are polled on their own intervals or cron schedules and paused
+// during configured quiet hours.
+//
 // # FeedbackScanner
 //
 // Polls the issue tracker for tickets in "in review" status, then
@@ -17,6 +21,14 @@
 // [commentfilter] package before emitting [jobmanager.JobTypeFeedback]
 // events.
 //
+// # Runtime reconfiguration
+//
+// Each scanner exposes an UpdateConfig method that validates a new
+// configuration and hands it to the polling goroutine, which applies
+// it between cycles and resets its ticker to the new poll interval
+// or schedule.
+// A cycle already in progress finishes with the old configuration.
+//
 // # Consumer-defined interfaces
 //
 // The scanner defines narrow interfaces for its dependencies
@@ -30,8 +42,14 @@ package scanner
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/circuit"
 	"jira-ai-issue-solver/jobmanager"
 	"jira-ai-issue-solver/models"
 )
@@ -50,9 +68,114 @@ type Scanner interface {
 	Stop()
 }
 
+// logSearchError logs a failed ticket search with msg. A search
+// rejected because the issue tracker's circuit breaker is open is
+// expected during an outage, so it is logged as a warning: the cycle
+// is skipped and the next one tries again.
+func logSearchError(logger *zap.Logger, msg string, err error) {
+	if errors.Is(err, circuit.ErrOpen) {
+		logger.Warn("Issue tracker unavailable, skipping scan cycle", zap.Error(err))
+		return
+	}
+	logger.Error(msg, zap.Error(err))
+}
+
+// nextRun returns when a scan is next due after t: at the next match
+// of cron, or when cron is nil, interval after t.
+func nextRun(cron *models.CronSchedule, interval time.Duration, t time.Time) time.Time {
+	if cron != nil {
+		return cron.Next(t)
+	}
+	return t.Add(interval)
+}
+
+// wakeDelay returns how long to wait until next, capped at heartbeat
+// when positive. A scanner waiting for a distant cron match (e.g.
+// overnight) thus still wakes up and records a heartbeat for health
+// checks, separately from its last scan.
+func wakeDelay(next time.Time, heartbeat time.Duration) time.Duration {
+	d := time.Until(next)
+	if heartbeat > 0 && d > heartbeat {
+		return heartbeat
+	}
+	return d
+}
+
+// scanTimestamp records when a scanner last finished a scan cycle.
+// It is safe for concurrent use so that health checks can read it
+// while the polling goroutine writes it.
+type scanTimestamp struct {
+	unixNano atomic.Int64
+}
+
+// mark records the current time as the last scan completion.
+func (t *scanTimestamp) mark() {
+	t.unixNano.Store(time.Now().UnixNano())
+}
+
+// get returns the last recorded scan time, or the zero time if no
+// scan has completed.
+func (t *scanTimestamp) get() time.Time {
+	n := t.unixNano.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// configUpdate hands a replacement configuration from UpdateConfig to
+// a scanner's polling goroutine. Only the latest pending value is
+// kept. The zero value is ready to use.
+type configUpdate[T any] struct {
+	mu      sync.Mutex
+	pending *T
+	signal  chan struct{}
+}
+
+// offer stores cfg as the pending configuration and wakes the
+// polling goroutine if it is waiting.
+func (u *configUpdate[T]) offer(cfg T) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.pending = &cfg
+	select {
+	case u.signalLocked() <- struct{}{}:
+	default:
+	}
+}
+
+// ready returns a channel that receives after [configUpdate.offer].
+func (u *configUpdate[T]) ready() <-chan struct{} {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.signalLocked()
+}
+
+// take returns and clears the pending configuration. The boolean is
+// false if nothing is pending.
+func (u *configUpdate[T]) take() (T, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.pending == nil {
+		var zero T
+		return zero, false
+	}
+	cfg := *u.pending
+	u.pending = nil
+	return cfg, true
+}
+
+func (u *configUpdate[T]) signalLocked() chan struct{} {
+	if u.signal == nil {
+		u.signal = make(chan struct{}, 1)
+	}
+	return u.signal
+}
+
 // IssueSearcher searches for work items in the issue tracker.
 type IssueSearcher interface {
 	SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error)
+	SearchWorkItemPages(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error
 }
 
 // JobSubmitter creates jobs from scanner events.
@@ -96,6 +219,40 @@ type MergedStatusResolver interface {
 	ResolveMergedStatus(item models.WorkItem) string
 }
 
+// BackportResolver resolves the release branches a work item's
+// backport labels request. Used by [FeedbackScanner] to submit
+// [jobmanager.JobTypeBackport] events once the ticket's PR is merged.
+type BackportResolver interface {
+	ResolveBackportBranches(item models.WorkItem) []string
+}
+
+// ReleaseNotesResolver resolves a work item's release note settings.
+// Used by [FeedbackScanner] to submit [jobmanager.JobTypeReleaseNote]
+// events once the ticket's PR is merged.
+type ReleaseNotesResolver interface {
+	ResolveReleaseNotes(item models.WorkItem) models.ReleaseNotes
+}
+
+// MergeOrderResolver resolves the order in which a work item's pull
+// requests across repositories must be merged. Used by
+// [FeedbackScanner] to remove the do-not-merge label from a pull
+// request once the ones before it are merged.
+type MergeOrderResolver interface {
+	ResolveMergeOrder(item models.WorkItem) models.MergeOrder
+}
+
+// DiffPreviewResolver resolves a work item's change preview settings.
+// Used by [FeedbackScanner] to submit a [jobmanager.JobTypeNewTicket]
+// event, which opens the PR, once a previewed change is approved.
+type DiffPreviewResolver interface {
+	ResolveDiffPreview(item models.WorkItem) models.DiffPreview
+}
+
+// CommentFetcher fetches a work item's comments, in posting order.
+type CommentFetcher interface {
+	GetComments(key string) ([]models.Comment, error)
+}
+
 // StatusTransitioner transitions a work item to a new status.
 type StatusTransitioner interface {
 	TransitionStatus(key, status string) error
@@ -25,6 +25,9 @@ var (
 	_ scanner.FailureLabelResolver   = (*StubFailureLabelResolver)(nil)
 	_ scanner.LifecycleLabelResolver = (*StubLifecycleLabelResolver)(nil)
 	_ scanner.MergedStatusResolver   = (*StubMergedStatusResolver)(nil)
+	_ scanner.MergeOrderResolver     = (*StubMergeOrderResolver)(nil)
+	_ scanner.DiffPreviewResolver    = (*StubDiffPreviewResolver)(nil)
+	_ scanner.CommentFetcher         = (*StubCommentFetcher)(nil)
 	_ scanner.StatusTransitioner     = (*StubStatusTransitioner)(nil)
 	_ scanner.RetryResetter          = (*StubRetryResetter)(nil)
 	_ scanner.MergeabilityChecker    = (*StubMergeabilityChecker)(nil)
@@ -56,7 +59,8 @@ func (s *StubScanner) Stop() {
 // Set the corresponding Func field to control each method's behavior.
 // When a Func field is nil, the method returns an empty slice.
 type StubIssueSearcher struct {
-	SearchWorkItemsFunc func(criteria models.SearchCriteria) ([]models.WorkItem, error)
+	SearchWorkItemsFunc     func(criteria models.SearchCriteria) ([]models.WorkItem, error)
+	SearchWorkItemPagesFunc func(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error
 }
 
 func (s *StubIssueSearcher) SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error) {
@@ -66,6 +70,19 @@ func (s *StubIssueSearcher) SearchWorkItems(criteria models.SearchCriteria) ([]m
 	return []models.WorkItem{}, nil
 }
 
+// SearchWorkItemPages delivers SearchWorkItems' result as a single
+// page when SearchWorkItemPagesFunc is nil.
+func (s *StubIssueSearcher) SearchWorkItemPages(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error {
+	if s.SearchWorkItemPagesFunc != nil {
+		return s.SearchWorkItemPagesFunc(criteria, fn)
+	}
+	items, err := s.SearchWorkItems(criteria)
+	if err != nil {
+		return err
+	}
+	return fn(items)
+}
+
 // StubJobSubmitter is a test double for [scanner.JobSubmitter].
 // Set the corresponding Func field to control each method's behavior.
 // When a Func field is nil, the method returns a zero-value job.
@@ -271,6 +288,70 @@ func (s *StubMergedStatusResolver) ResolveMergedStatus(item models.WorkItem) str
 	return ""
 }
 
+// StubBackportResolver is a test double for
+// [scanner.BackportResolver].
+type StubBackportResolver struct {
+	ResolveBackportBranchesFunc func(item models.WorkItem) []string
+}
+
+func (s *StubBackportResolver) ResolveBackportBranches(item models.WorkItem) []string {
+	if s.ResolveBackportBranchesFunc != nil {
+		return s.ResolveBackportBranchesFunc(item)
+	}
+	return nil
+}
+
+// StubReleaseNotesResolver is a test double for
+// [scanner.ReleaseNotesResolver].
+type StubReleaseNotesResolver struct {
+	ResolveReleaseNotesFunc func(item models.WorkItem) models.ReleaseNotes
+}
+
+func (s *StubReleaseNotesResolver) ResolveReleaseNotes(item models.WorkItem) models.ReleaseNotes {
+	if s.ResolveReleaseNotesFunc != nil {
+		return s.ResolveReleaseNotesFunc(item)
+	}
+	return models.ReleaseNotes{}
+}
+
+// StubMergeOrderResolver is a test double for
+// [scanner.MergeOrderResolver].
+type StubMergeOrderResolver struct {
+	ResolveMergeOrderFunc func(item models.WorkItem) models.MergeOrder
+}
+
+func (s *StubMergeOrderResolver) ResolveMergeOrder(item models.WorkItem) models.MergeOrder {
+	if s.ResolveMergeOrderFunc != nil {
+		return s.ResolveMergeOrderFunc(item)
+	}
+	return models.MergeOrder{}
+}
+
+// StubDiffPreviewResolver is a test double for
+// [scanner.DiffPreviewResolver].
+type StubDiffPreviewResolver struct {
+	ResolveDiffPreviewFunc func(item models.WorkItem) models.DiffPreview
+}
+
+func (s *StubDiffPreviewResolver) ResolveDiffPreview(item models.WorkItem) models.DiffPreview {
+	if s.ResolveDiffPreviewFunc != nil {
+		return s.ResolveDiffPreviewFunc(item)
+	}
+	return models.DiffPreview{}
+}
+
+// StubCommentFetcher is a test double for [scanner.CommentFetcher].
+type StubCommentFetcher struct {
+	GetCommentsFunc func(key string) ([]models.Comment, error)
+}
+
+func (s *StubCommentFetcher) GetComments(key string) ([]models.Comment, error) {
+	if s.GetCommentsFunc != nil {
+		return s.GetCommentsFunc(key)
+	}
+	return []models.Comment{}, nil
+}
+
 // StubStatusTransitioner is a test double for
 // [scanner.StatusTransitioner].
 type StubStatusTransitioner struct {
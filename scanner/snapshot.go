@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"fmt"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+// maxSnapshotAge bounds how long a PR snapshot is reused. Changes
+// that do not update a PR's updated_at, such as a re-run CI check,
+// are picked up after at most this long.
+const maxSnapshotAge = time.Hour
+
+// prSnapshot records an open PR on which the scanner found nothing to
+// act on. While the PR's updated_at and head commit are unchanged, the
+// following scan cycles reuse the result instead of fetching the PR's
+// comments and CI state again, which on large projects is most of the
+// scanner's GitHub API usage.
+type prSnapshot struct {
+	updatedAt time.Time
+	headSHA   string
+	ciChecked bool
+	takenAt   time.Time
+}
+
+func snapshotKey(r models.RepoCoord, pr *models.PRDetails) string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, pr.Number)
+}
+
+// unchangedSnapshot returns the snapshot of pr when the PR has had no
+// activity since it was taken and the snapshot has not expired.
+func (s *FeedbackScanner) unchangedSnapshot(r models.RepoCoord, pr *models.PRDetails) (prSnapshot, bool) {
+	if pr.UpdatedAt.IsZero() {
+		return prSnapshot{}, false
+	}
+	snap, ok := s.snapshots[snapshotKey(r, pr)]
+	if !ok || !snap.updatedAt.Equal(pr.UpdatedAt) || snap.headSHA != pr.HeadSHA ||
+		s.now().Sub(snap.takenAt) >= maxSnapshotAge {
+		return prSnapshot{}, false
+	}
+	return snap, true
+}
+
+// recordSnapshot records or clears the snapshot of pr after its
+// comments and CI state were checked. Only settled, inactive PRs are
+// recorded: nothing was actionable, and CI is either not checked or
+// finished without failures. Pending or failing CI can change without
+// updating the PR, so such PRs are checked every cycle.
+func (s *FeedbackScanner) recordSnapshot(r models.RepoCoord, pr *models.PRDetails, actionable bool, ci ciCheckResult) {
+	key := snapshotKey(r, pr)
+	ciSettled := s.ci == nil || pr.HeadSHA == "" || (ci.checked && !ci.hasFailures)
+	if actionable || !ciSettled || pr.UpdatedAt.IsZero() {
+		delete(s.snapshots, key)
+		return
+	}
+	if s.snapshots == nil {
+		s.snapshots = make(map[string]prSnapshot)
+	}
+	s.snapshots[key] = prSnapshot{
+		updatedAt: pr.UpdatedAt,
+		headSHA:   pr.HeadSHA,
+		ciChecked: ci.checked,
+		takenAt:   s.now(),
+	}
+}
+
+// pruneSnapshots drops expired snapshots, including those of PRs that
+// were merged, closed, or left review.
+func (s *FeedbackScanner) pruneSnapshots() {
+	now := s.now()
+	for key, snap := range s.snapshots {
+		if now.Sub(snap.takenAt) >= maxSnapshotAge {
+			delete(s.snapshots, key)
+		}
+	}
+}
+
+func (s *FeedbackScanner) now() time.Time {
+	if s.cfg.Clock != nil {
+		return s.cfg.Clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,233 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+)
+
+// Compile-time check that TriageScanner implements Scanner.
+var _ Scanner = (*TriageScanner)(nil)
+
+// TriageScannerConfig holds configuration for [TriageScanner].
+type TriageScannerConfig struct {
+	// Criteria defines the search query for tickets labeled for
+	// triage. A query without labels disables scanning.
+	Criteria models.SearchCriteria
+
+	// PollInterval is the time between scan cycles.
+	PollInterval time.Duration
+}
+
+func (c TriageScannerConfig) validate() error {
+	if c.PollInterval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
+	return nil
+}
+
+// TriageScanner polls for tickets carrying a project's triage label
+// and emits a [jobmanager.JobTypeTriage] event for each. The executor
+// replaces the label once the ticket is triaged, so tickets are not
+// triaged twice.
+type TriageScanner struct {
+	searcher  IssueSearcher
+	submitter JobSubmitter
+	cfg       TriageScannerConfig
+	logger    *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	lastScan scanTimestamp
+	updates  configUpdate[TriageScannerConfig]
+}
+
+// NewTriageScanner creates a TriageScanner with the
+// given dependencies. Returns an error if any required parameter is
+// invalid.
+func NewTriageScanner(
+	searcher IssueSearcher,
+	submitter JobSubmitter,
+	cfg TriageScannerConfig,
+	logger *zap.Logger,
+) (*TriageScanner, error) {
+	if searcher == nil {
+		return nil, errors.New("issue searcher must not be nil")
+	}
+	if submitter == nil {
+		return nil, errors.New("job submitter must not be nil")
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+
+	return &TriageScanner{
+		searcher:  searcher,
+		submitter: submitter,
+		cfg:       cfg,
+		logger:    logger,
+	}, nil
+}
+
+// Start begins polling in a background goroutine.
+func (s *TriageScanner) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return errors.New("scanner already running")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go s.run(ctx)
+	return nil
+}
+
+// Stop cancels polling and blocks until the goroutine exits.
+func (s *TriageScanner) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	done := s.done
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// LastScan returns when the most recent scan cycle finished, or the
+// zero time if no cycle has completed yet.
+func (s *TriageScanner) LastScan() time.Time {
+	return s.lastScan.get()
+}
+
+// UpdateConfig replaces the scanner's configuration. The new
+// configuration takes effect at the start of the next scan cycle,
+// and the poll ticker is reset to the new interval. Returns an error
+// if cfg is invalid, in which case the current configuration is kept.
+func (s *TriageScanner) UpdateConfig(cfg TriageScannerConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	s.updates.offer(cfg)
+	return nil
+}
+
+func (s *TriageScanner) run(ctx context.Context) {
+	defer close(s.done)
+
+	s.applyPendingConfig()
+	s.scan(ctx)
+	s.lastScan.mark()
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.updates.ready():
+			if s.applyPendingConfig() {
+				ticker.Reset(s.cfg.PollInterval)
+			}
+		case <-ticker.C:
+			s.scan(ctx)
+			s.lastScan.mark()
+		}
+	}
+}
+
+// applyPendingConfig installs a configuration passed to UpdateConfig,
+// if any. Called only from the polling goroutine. Reports whether
+// the configuration changed.
+func (s *TriageScanner) applyPendingConfig() bool {
+	cfg, ok := s.updates.take()
+	if !ok {
+		return false
+	}
+	s.cfg = cfg
+	s.logger.Info("Scanner configuration updated",
+		zap.Duration("poll_interval", cfg.PollInterval))
+	return true
+}
+
+func (s *TriageScanner) scan(ctx context.Context) {
+	if len(s.cfg.Criteria.Labels) == 0 {
+		return
+	}
+
+	items, err := s.searcher.SearchWorkItems(s.cfg.Criteria)
+	if err != nil {
+		logSearchError(s.logger, "Failed to search for tickets to triage", err)
+		return
+	}
+
+	if len(items) == 0 {
+		s.logger.Debug("No tickets to triage found")
+		return
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return
+		}
+		if s.submit(item) {
+			return
+		}
+	}
+}
+
+// submit submits a triage event for the ticket. Returns true if the
+// scan cycle should stop (circuit breaker open or shutdown).
+func (s *TriageScanner) submit(item models.WorkItem) bool {
+	logger := s.logger.With(zap.String("ticket", item.Key))
+
+	event := jobmanager.Event{
+		Type:          jobmanager.JobTypeTriage,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
+	}
+
+	_, err := s.submitter.Submit(event)
+	if err == nil {
+		logger.Info("Submitted ticket for triage")
+		return false
+	}
+
+	switch {
+	case errors.Is(err, jobmanager.ErrDuplicateJob):
+		logger.Debug("Skipping duplicate ticket")
+	case errors.Is(err, jobmanager.ErrRetriesExhausted):
+		logger.Debug("Skipping exhausted ticket")
+	case errors.Is(err, jobmanager.ErrCircuitOpen):
+		logger.Warn("Circuit breaker open, stopping scan cycle")
+		return true
+	case errors.Is(err, jobmanager.ErrBudgetExceeded):
+		logger.Warn("Daily budget exceeded, stopping scan cycle")
+		return true
+	case errors.Is(err, jobmanager.ErrShutdown):
+		logger.Info("Job manager shut down, stopping scan cycle")
+		return true
+	default:
+		logger.Error("Failed to submit ticket for triage", zap.Error(err))
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package scanner_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scanner"
+	"jira-ai-issue-solver/scanner/scannertest"
+)
+
+// triageCreated is when the ticket runOneTriageScan finds was created.
+var triageCreated = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// runOneTriageScan runs a scanner finding PROJ-1, a High priority
+// ticket, with criteria and returns the events it submitted.
+func runOneTriageScan(t *testing.T, criteria models.SearchCriteria) []jobmanager.Event {
+	t.Helper()
+
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(models.SearchCriteria) ([]models.WorkItem, error) {
+			return []models.WorkItem{{Key: "PROJ-1", Priority: "High", Created: triageCreated}}, nil
+		},
+	}
+	var mu sync.Mutex
+	var submitted []jobmanager.Event
+	submitter := &scannertest.StubJobSubmitter{
+		SubmitFunc: func(event jobmanager.Event) (*jobmanager.Job, error) {
+			mu.Lock()
+			submitted = append(submitted, event)
+			mu.Unlock()
+			return &jobmanager.Job{}, nil
+		},
+	}
+
+	s, err := scanner.NewTriageScanner(searcher, submitter,
+		scanner.TriageScannerConfig{Criteria: criteria, PollInterval: time.Hour}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return submitted
+}
+
+func TestTriageScanner(t *testing.T) {
+	events := runOneTriageScan(t, models.SearchCriteria{Labels: []string{"ai-triage"}})
+	if len(events) != 1 || events[0].Type != jobmanager.JobTypeTriage || events[0].TicketKey != "PROJ-1" {
+		t.Errorf("events = %+v, want one triage event for PROJ-1", events)
+	}
+}
+
+func TestTriageScanner_NoTriageLabel(t *testing.T) {
+	if events := runOneTriageScan(t, models.SearchCriteria{}); len(events) != 0 {
+		t.Errorf("events = %+v, want none without a triage label", events)
+	}
+}
+
+func TestTriageScanner_RanksByPriority(t *testing.T) {
+	events := runOneTriageScan(t, models.SearchCriteria{Labels: []string{"ai-triage"}})
+	if len(events) != 1 {
+		t.Fatalf("events = %+v, want one", events)
+	}
+	if events[0].Priority != 1 || !events[0].TicketCreated.Equal(triageCreated) {
+		t.Errorf("priority = %d, created = %v, want 1, %v",
+			events[0].Priority, events[0].TicketCreated, triageCreated)
+	}
+}
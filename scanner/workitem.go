@@ -1,8 +1,10 @@
 package scanner
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"strings"
 	"sync"
@@ -20,10 +22,78 @@ var _ Scanner = (*WorkItemScanner)(nil)
 // WorkItemScannerConfig holds configuration for [WorkItemScanner].
 type WorkItemScannerConfig struct {
 	// Criteria defines the search query for discovering new tickets.
+	// Ignored when Schedules is set.
 	Criteria models.SearchCriteria
 
 	// PollInterval is the time between scan cycles.
 	PollInterval time.Duration
+
+	// Schedules optionally splits discovery into groups of projects
+	// that are scanned on their own intervals or cron schedules and
+	// may pause during quiet hours. When empty, Criteria is scanned
+	// every PollInterval. When set, PollInterval only bounds how long
+	// the scanner sleeps between wake-ups.
+	Schedules []ScanSchedule
+
+	// DeadLetterLabel, when set, names the label the pipeline adds to
+	// tickets that exhausted their retries. Every scan excludes
+	// tickets carrying it. A ticket recorded with
+	// [WorkItemScanner.MarkDeadLettered] that a scan finds again had
+	// the label removed by a human, so its retry count is reset and
+	// it is resubmitted.
+	DeadLetterLabel string
+
+	// Clock returns the current time for quiet-hours checks.
+	// Defaults to [time.Now]. Exposed for testing.
+	Clock func() time.Time
+}
+
+// ScanSchedule is one independently timed work item query.
+type ScanSchedule struct {
+	// Criteria defines the search query for this schedule.
+	Criteria models.SearchCriteria
+
+	// Interval is the time between scans of this schedule. Ignored
+	// when Cron is set.
+	Interval time.Duration
+
+	// Cron, when set, scans at the times the cron expression matches
+	// instead of every Interval. The first scan waits for the first
+	// match rather than running at startup.
+	Cron *models.CronSchedule
+
+	// QuietHours, when set, suppresses scans while the window is
+	// active. Nil means the schedule is never paused.
+	QuietHours *models.QuietHours
+}
+
+func (c WorkItemScannerConfig) validate() error {
+	if len(c.Schedules) == 0 {
+		if c.PollInterval <= 0 {
+			return errors.New("poll interval must be positive")
+		}
+		return nil
+	}
+	for i, sched := range c.Schedules {
+		if sched.Cron == nil && sched.Interval <= 0 {
+			return fmt.Errorf("schedule %d: interval must be positive", i)
+		}
+		if sched.QuietHours != nil {
+			if err := sched.QuietHours.Validate(); err != nil {
+				return fmt.Errorf("schedule %d: quiet hours: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// schedules returns the effective schedules, treating Criteria and
+// PollInterval as a single schedule when Schedules is empty.
+func (c WorkItemScannerConfig) schedules() []ScanSchedule {
+	if len(c.Schedules) > 0 {
+		return c.Schedules
+	}
+	return []ScanSchedule{{Criteria: c.Criteria, Interval: c.PollInterval}}
 }
 
 // WorkItemScanner polls the issue tracker for tickets matching the
@@ -40,6 +110,15 @@ type WorkItemScanner struct {
 	mu     sync.Mutex
 	cancel context.CancelFunc
 	done   chan struct{}
+
+	lastScan  scanTimestamp
+	heartbeat scanTimestamp
+	updates   configUpdate[WorkItemScannerConfig]
+
+	// deadLettered holds the tickets this process gave the
+	// dead-letter label. Guarded by deadMu.
+	deadMu       sync.Mutex
+	deadLettered map[string]bool
 }
 
 // NewWorkItemScanner creates a WorkItemScanner with the given
@@ -64,8 +143,8 @@ func NewWorkItemScanner(
 	if submitter == nil {
 		return nil, errors.New("job submitter must not be nil")
 	}
-	if cfg.PollInterval <= 0 {
-		return nil, errors.New("poll interval must be positive")
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 	if logger == nil {
 		return nil, errors.New("logger must not be nil")
@@ -79,9 +158,36 @@ func NewWorkItemScanner(
 		retryLabel:    retryLabel,
 		cfg:           cfg,
 		logger:        logger,
+		deadLettered:  make(map[string]bool),
 	}, nil
 }
 
+// MarkDeadLettered records that ticketKey was given the dead-letter
+// label. When a later scan finds the ticket, a human removed the
+// label, and the ticket's retry count is reset and it is resubmitted.
+// Only recorded tickets are revived: an exhausted ticket that never
+// got the label, because it failed before the pipeline could add it
+// or adding it failed, keeps its retry limit.
+func (s *WorkItemScanner) MarkDeadLettered(ticketKey string) {
+	s.deadMu.Lock()
+	defer s.deadMu.Unlock()
+	s.deadLettered[ticketKey] = true
+}
+
+// revivedDeadLetter reports whether item is a ticket recorded with
+// MarkDeadLettered that no longer carries the dead-letter label.
+func (s *WorkItemScanner) revivedDeadLetter(item models.WorkItem) bool {
+	label := s.cfg.DeadLetterLabel
+	if label == "" || slices.ContainsFunc(item.Labels, func(l string) bool {
+		return strings.EqualFold(l, label)
+	}) {
+		return false
+	}
+	s.deadMu.Lock()
+	defer s.deadMu.Unlock()
+	return s.deadLettered[item.Key]
+}
+
 // Start begins polling in a background goroutine.
 func (s *WorkItemScanner) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -111,54 +217,188 @@ func (s *WorkItemScanner) Stop() {
 	}
 }
 
+// LastScan returns when the most recent scan cycle finished, or the
+// zero time if no cycle has completed yet. Wake-ups while waiting for
+// a cron schedule to match do not count; see [WorkItemScanner.LastHeartbeat].
+func (s *WorkItemScanner) LastScan() time.Time {
+	return s.lastScan.get()
+}
+
+// LastHeartbeat returns when the polling goroutine last woke up, or
+// the zero time if it has not yet. It wakes at least every
+// PollInterval even while waiting for a cron schedule to match.
+func (s *WorkItemScanner) LastHeartbeat() time.Time {
+	return s.heartbeat.get()
+}
+
+// UpdateConfig replaces the scanner's configuration. The new
+// configuration takes effect at the start of the next scan cycle,
+// and the poll ticker is reset to the new interval. Returns an error
+// if cfg is invalid, in which case the current configuration is kept.
+func (s *WorkItemScanner) UpdateConfig(cfg WorkItemScannerConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	s.updates.offer(cfg)
+	return nil
+}
+
 func (s *WorkItemScanner) run(ctx context.Context) {
 	defer close(s.done)
 
-	s.scan(ctx)
+	s.applyPendingConfig()
+	schedules := s.cfg.schedules()
+	// Interval schedules are due immediately (zero); cron schedules
+	// wait for their first match.
+	next := make([]time.Time, len(schedules))
+	for i, sched := range schedules {
+		if sched.Cron != nil {
+			next[i] = sched.Cron.Next(time.Now())
+		}
+	}
 
-	ticker := time.NewTicker(s.cfg.PollInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			s.scan(ctx)
+		case <-s.updates.ready():
+			if s.applyPendingConfig() {
+				schedules = s.cfg.schedules()
+				now := time.Now()
+				next = make([]time.Time, len(schedules))
+				for i, sched := range schedules {
+					next[i] = nextRun(sched.Cron, sched.Interval, now)
+				}
+				timer.Reset(wakeDelay(earliest(next), s.cfg.PollInterval))
+			}
+		case <-timer.C:
+			scanned := false
+			for i, sched := range schedules {
+				if time.Now().Before(next[i]) {
+					continue
+				}
+				s.runSchedule(ctx, sched)
+				next[i] = nextRun(sched.Cron, sched.Interval, time.Now())
+				scanned = true
+			}
+			if scanned {
+				s.lastScan.mark()
+			}
+			s.heartbeat.mark()
+			timer.Reset(wakeDelay(earliest(next), s.cfg.PollInterval))
 		}
 	}
 }
 
-func (s *WorkItemScanner) scan(ctx context.Context) {
-	items, err := s.searcher.SearchWorkItems(s.cfg.Criteria)
-	if err != nil {
-		s.logger.Error("Failed to search for work items", zap.Error(err))
+// runSchedule scans one schedule unless its quiet hours are active.
+func (s *WorkItemScanner) runSchedule(ctx context.Context, sched ScanSchedule) {
+	if sched.QuietHours != nil && sched.QuietHours.Active(s.now()) {
+		s.logger.Debug("Skipping work item scan during quiet hours",
+			zap.Strings("projects", sched.Criteria.ProjectKeys))
 		return
 	}
+	s.scan(ctx, sched.Criteria)
+}
 
-	if len(items) == 0 {
-		s.logger.Debug("No work items found")
-		return
+func (s *WorkItemScanner) now() time.Time {
+	if s.cfg.Clock != nil {
+		return s.cfg.Clock()
 	}
+	return time.Now()
+}
 
-	s.logger.Info("Found work items", zap.Int("count", len(items)))
-
-	for _, item := range items {
-		if ctx.Err() != nil {
-			return
+// earliest returns the earliest of the given times.
+func earliest(times []time.Time) time.Time {
+	var first time.Time
+	for i, t := range times {
+		if i == 0 || t.Before(first) {
+			first = t
 		}
-		if s.submitEvent(item) {
-			return
+	}
+	return first
+}
+
+// applyPendingConfig installs a configuration passed to UpdateConfig,
+// if any. Called only from the polling goroutine. Reports whether
+// the configuration changed.
+func (s *WorkItemScanner) applyPendingConfig() bool {
+	cfg, ok := s.updates.take()
+	if !ok {
+		return false
+	}
+	s.cfg = cfg
+	s.logger.Info("Scanner configuration updated",
+		zap.Duration("poll_interval", cfg.PollInterval))
+	return true
+}
+
+// urgencyOrder is the JQL sort applied to new-ticket searches that do
+// not specify one, so that result pages arrive most urgent first.
+const urgencyOrder = "priority DESC, created ASC"
+
+// errStopScan ends a paged search early when the scan cycle should
+// stop.
+var errStopScan = errors.New("scan cycle stopped")
+
+func (s *WorkItemScanner) scan(ctx context.Context, criteria models.SearchCriteria) {
+	if criteria.OrderBy == "" {
+		criteria.OrderBy = urgencyOrder
+	}
+	if s.cfg.DeadLetterLabel != "" {
+		criteria.ExcludeLabels = append(slices.Clip(criteria.ExcludeLabels), s.cfg.DeadLetterLabel)
+	}
+
+	found := 0
+	err := s.searcher.SearchWorkItemPages(criteria, func(items []models.WorkItem) error {
+		found += len(items)
+
+		// Submit the most urgent tickets first so they claim any free
+		// concurrency slots before the job manager starts queueing.
+		slices.SortStableFunc(items, compareUrgency)
+
+		for _, item := range items {
+			if ctx.Err() != nil {
+				return errStopScan
+			}
+			if s.submitEvent(item) {
+				return errStopScan
+			}
 		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopScan) {
+		logSearchError(s.logger, "Failed to search for work items", err)
+		return
+	}
+
+	if found == 0 {
+		s.logger.Debug("No work items found")
+		return
 	}
+	s.logger.Info("Found work items", zap.Int("count", found))
+}
+
+// compareUrgency orders work items by priority (highest first), then
+// by creation time (oldest first).
+func compareUrgency(a, b models.WorkItem) int {
+	if c := cmp.Compare(b.PriorityRank(), a.PriorityRank()); c != 0 {
+		return c
+	}
+	return a.Created.Compare(b.Created)
 }
 
 // submitEvent emits a new ticket event. Returns true if the scan
 // cycle should stop (circuit breaker open or shutdown).
 func (s *WorkItemScanner) submitEvent(item models.WorkItem) bool {
 	event := jobmanager.Event{
-		Type:      jobmanager.JobTypeNewTicket,
-		TicketKey: item.Key,
+		Type:          jobmanager.JobTypeNewTicket,
+		TicketKey:     item.Key,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
 	}
 
 	_, err := s.submitter.Submit(event)
@@ -197,42 +437,64 @@ func (s *WorkItemScanner) submitEvent(item models.WorkItem) bool {
 }
 
 // handleRetryLabel checks whether an exhausted ticket has the retry
-// label. If so, it resets the retry count, removes the label, and
-// resubmits the ticket. Returns true if the ticket was resubmitted.
+// label, or had its dead-letter label removed. If so, it resets the
+// retry count, removes the retry label, and resubmits the ticket.
+// Returns true if the ticket was resubmitted.
 func (s *WorkItemScanner) handleRetryLabel(item models.WorkItem) bool {
-	if s.retryLabel == "" || s.labelRemover == nil || s.retryResetter == nil {
+	if s.retryResetter == nil {
 		return false
 	}
 
-	hasLabel := slices.ContainsFunc(item.Labels, func(l string) bool {
-		return strings.EqualFold(l, s.retryLabel)
-	})
-	if !hasLabel {
+	hasLabel := s.retryLabel != "" && s.labelRemover != nil &&
+		slices.ContainsFunc(item.Labels, func(l string) bool {
+			return strings.EqualFold(l, s.retryLabel)
+		})
+	revived := s.revivedDeadLetter(item)
+	switch {
+	case hasLabel:
+		s.logger.Info("Retry label detected, resetting retry count",
+			zap.String("ticket", item.Key),
+			zap.String("label", s.retryLabel))
+	case revived:
+		s.logger.Info("Dead-letter label removed, resetting retry count",
+			zap.String("ticket", item.Key),
+			zap.String("label", s.cfg.DeadLetterLabel))
+	default:
 		return false
 	}
 
-	s.logger.Info("Retry label detected, resetting retry count",
-		zap.String("ticket", item.Key),
-		zap.String("label", s.retryLabel))
-
 	if err := s.retryResetter.ResetRetries(item.Key); err != nil {
 		s.logger.Error("Failed to reset retries",
 			zap.String("ticket", item.Key),
 			zap.Error(err))
 		return false
 	}
+	s.deadMu.Lock()
+	delete(s.deadLettered, item.Key)
+	s.deadMu.Unlock()
 
+	if !hasLabel {
+		return s.resubmit(item)
+	}
 	if err := s.labelRemover.RemoveLabel(item.Key, s.retryLabel); err != nil {
 		s.logger.Error("Failed to remove retry label, skipping resubmit to avoid retry loop",
 			zap.String("ticket", item.Key),
 			zap.Error(err))
 		return false
 	}
+	return s.resubmit(item)
+}
 
+// resubmit submits a clean retry of an exhausted ticket whose retry
+// count was reset. Returns true if the ticket was resubmitted.
+func (s *WorkItemScanner) resubmit(item models.WorkItem) bool {
 	event := jobmanager.Event{
-		Type:       jobmanager.JobTypeNewTicket,
-		TicketKey:  item.Key,
-		CleanRetry: true,
+		Type:          jobmanager.JobTypeNewTicket,
+		TicketKey:     item.Key,
+		CleanRetry:    true,
+		Priority:      item.PriorityRank(),
+		TicketCreated: item.Created,
+		WorkItem:      &item,
 	}
 	if _, err := s.submitter.Submit(event); err != nil {
 		s.logger.Error("Failed to resubmit after retry reset",
@@ -3,6 +3,7 @@ package scanner_test
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -139,6 +140,94 @@ func TestWorkItemScanner_EmitsEvents(t *testing.T) {
 	}
 }
 
+func TestWorkItemScanner_SubmitsByPriorityThenAge(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(_ models.SearchCriteria) ([]models.WorkItem, error) {
+			return []models.WorkItem{
+				{Key: "PROJ-1", Priority: "Low", Created: day(1)},
+				{Key: "PROJ-2", Priority: "Highest", Created: day(4)},
+				{Key: "PROJ-3", Created: day(2)},
+				{Key: "PROJ-4", Priority: "Highest", Created: day(3)},
+			}, nil
+		},
+	}
+
+	var mu sync.Mutex
+	var submitted []jobmanager.Event
+	submitter := &scannertest.StubJobSubmitter{
+		SubmitFunc: func(event jobmanager.Event) (*jobmanager.Job, error) {
+			mu.Lock()
+			submitted = append(submitted, event)
+			mu.Unlock()
+			return &jobmanager.Job{}, nil
+		},
+	}
+
+	s := newWorkItemScanner(t, searcher, submitter)
+	runOneScan(t, s)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var got []string
+	for _, e := range submitted {
+		got = append(got, e.TicketKey)
+	}
+	want := []string{"PROJ-4", "PROJ-2", "PROJ-3", "PROJ-1"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("submission order = %v, want %v", got, want)
+	}
+	if submitted[0].Priority != 2 || !submitted[0].TicketCreated.Equal(day(3)) {
+		t.Errorf("event[0] Priority = %d, TicketCreated = %v; want 2, %v",
+			submitted[0].Priority, submitted[0].TicketCreated, day(3))
+	}
+}
+
+func TestWorkItemScanner_StreamsPagesInUrgencyOrder(t *testing.T) {
+	var gotOrder string
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemPagesFunc: func(criteria models.SearchCriteria, fn func([]models.WorkItem) error) error {
+			gotOrder = criteria.OrderBy
+			for _, page := range [][]models.WorkItem{
+				{{Key: "PROJ-1"}, {Key: "PROJ-2"}},
+				{{Key: "PROJ-3"}, {Key: "PROJ-4"}},
+			} {
+				if err := fn(page); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	var mu sync.Mutex
+	var submitted []string
+	submitter := &scannertest.StubJobSubmitter{
+		SubmitFunc: func(event jobmanager.Event) (*jobmanager.Job, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			submitted = append(submitted, event.TicketKey)
+			if event.TicketKey == "PROJ-3" {
+				return nil, jobmanager.ErrCircuitOpen
+			}
+			return &jobmanager.Job{}, nil
+		},
+	}
+
+	s := newWorkItemScanner(t, searcher, submitter)
+	runOneScan(t, s)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if gotOrder != "priority DESC, created ASC" {
+		t.Errorf("OrderBy = %q, want urgency order", gotOrder)
+	}
+	want := []string{"PROJ-1", "PROJ-2", "PROJ-3"}
+	if !slices.Equal(submitted, want) {
+		t.Errorf("submitted = %v, want %v (stop mid-page on open circuit)", submitted, want)
+	}
+}
+
 // --- No events when no tickets ---
 
 func TestWorkItemScanner_NoEventsWhenEmpty(t *testing.T) {
@@ -503,6 +592,211 @@ func TestWorkItemScanner_PassesCriteria(t *testing.T) {
 	}
 }
 
+// --- UpdateConfig ---
+
+func TestWorkItemScanner_UpdateConfig_RejectsInvalid(t *testing.T) {
+	s := newWorkItemScanner(t, &scannertest.StubIssueSearcher{}, &scannertest.StubJobSubmitter{})
+
+	if err := s.UpdateConfig(scanner.WorkItemScannerConfig{}); err == nil {
+		t.Fatal("expected error for zero poll interval")
+	}
+}
+
+func TestWorkItemScanner_UpdateConfig_AppliesWhileRunning(t *testing.T) {
+	received := make(chan models.SearchCriteria, 10)
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+			select {
+			case received <- criteria:
+			default:
+			}
+			return nil, nil
+		},
+	}
+
+	s := newWorkItemScanner(t, searcher, &scannertest.StubJobSubmitter{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("initial scan did not run")
+	}
+
+	// The initial interval is one hour, so a second scan only
+	// happens if the new interval was applied.
+	err := s.UpdateConfig(scanner.WorkItemScannerConfig{
+		Criteria:     models.SearchCriteria{ProjectKeys: []string{"NEW"}},
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case criteria := <-received:
+		if len(criteria.ProjectKeys) != 1 || criteria.ProjectKeys[0] != "NEW" {
+			t.Errorf("criteria.ProjectKeys = %v, want [NEW]", criteria.ProjectKeys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("scan with updated config did not run")
+	}
+}
+
+// --- Schedules ---
+
+func TestWorkItemScanner_Schedules_QuietHoursSkipsProject(t *testing.T) {
+	var mu sync.Mutex
+	var scanned []string
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+			mu.Lock()
+			scanned = append(scanned, criteria.ProjectKeys...)
+			mu.Unlock()
+			return nil, nil
+		},
+	}
+
+	// 2025-01-06 03:00 UTC falls inside a 20:00-08:00 window.
+	night := time.Date(2025, 1, 6, 3, 0, 0, 0, time.UTC)
+	cfg := scanner.WorkItemScannerConfig{
+		Schedules: []scanner.ScanSchedule{
+			{Criteria: models.SearchCriteria{ProjectKeys: []string{"AWAKE"}}, Interval: time.Hour},
+			{
+				Criteria:   models.SearchCriteria{ProjectKeys: []string{"ASLEEP"}},
+				Interval:   time.Hour,
+				QuietHours: &models.QuietHours{Start: "20:00", End: "08:00"},
+			},
+		},
+		Clock: func() time.Time { return night },
+	}
+
+	s, err := scanner.NewWorkItemScanner(searcher, &scannertest.StubJobSubmitter{}, nil, nil, "", cfg, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	runOneScan(t, s)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(scanned) != 1 || scanned[0] != "AWAKE" {
+		t.Errorf("scanned projects = %v, want [AWAKE]", scanned)
+	}
+}
+
+func TestWorkItemScanner_Schedules_IndependentIntervals(t *testing.T) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+			mu.Lock()
+			counts[criteria.ProjectKeys[0]]++
+			mu.Unlock()
+			return nil, nil
+		},
+	}
+
+	cfg := scanner.WorkItemScannerConfig{
+		Schedules: []scanner.ScanSchedule{
+			{Criteria: models.SearchCriteria{ProjectKeys: []string{"FAST"}}, Interval: 10 * time.Millisecond},
+			{Criteria: models.SearchCriteria{ProjectKeys: []string{"SLOW"}}, Interval: time.Hour},
+		},
+	}
+
+	s, err := scanner.NewWorkItemScanner(searcher, &scannertest.StubJobSubmitter{}, nil, nil, "", cfg, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts["SLOW"] != 1 {
+		t.Errorf("SLOW scanned %d times, want 1", counts["SLOW"])
+	}
+	if counts["FAST"] < 2 {
+		t.Errorf("FAST scanned %d times, want at least 2", counts["FAST"])
+	}
+}
+
+func TestWorkItemScanner_Schedules_CronWaitsForMatch(t *testing.T) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+			mu.Lock()
+			counts[criteria.ProjectKeys[0]]++
+			mu.Unlock()
+			return nil, nil
+		},
+	}
+
+	// Midnight on New Year's Day is too far off to match during the test.
+	yearly, err := models.ParseCron("0 0 1 1 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := scanner.WorkItemScannerConfig{
+		PollInterval: 10 * time.Millisecond,
+		Schedules: []scanner.ScanSchedule{
+			{Criteria: models.SearchCriteria{ProjectKeys: []string{"INTERVAL"}}, Interval: time.Hour},
+			{Criteria: models.SearchCriteria{ProjectKeys: []string{"CRON"}}, Cron: yearly},
+		},
+	}
+
+	s, err := scanner.NewWorkItemScanner(searcher, &scannertest.StubJobSubmitter{}, nil, nil, "", cfg, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	first, firstBeat := s.LastScan(), s.LastHeartbeat()
+	time.Sleep(50 * time.Millisecond)
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts["CRON"] != 0 {
+		t.Errorf("CRON scanned %d times before its schedule matched", counts["CRON"])
+	}
+	if counts["INTERVAL"] != 1 {
+		t.Errorf("INTERVAL scanned %d times, want 1", counts["INTERVAL"])
+	}
+	if !s.LastScan().Equal(first) {
+		t.Error("LastScan advanced without a scan")
+	}
+	if !s.LastHeartbeat().After(firstBeat) {
+		t.Error("LastHeartbeat not advanced while waiting for the schedule")
+	}
+}
+
+func TestWorkItemScanner_Schedules_RejectsInvalid(t *testing.T) {
+	cfg := scanner.WorkItemScannerConfig{
+		Schedules: []scanner.ScanSchedule{{Interval: 0}},
+	}
+	_, err := scanner.NewWorkItemScanner(&scannertest.StubIssueSearcher{}, &scannertest.StubJobSubmitter{}, nil, nil, "", cfg, zap.NewNop())
+	if err == nil {
+		t.Fatal("expected error for zero schedule interval")
+	}
+}
+
 // --- retry label ---
 
 func TestWorkItemScanner_RetryLabel_ResetsAndResubmits(t *testing.T) {
@@ -779,6 +1073,135 @@ func newWorkItemScanner(t *testing.T, searcher scanner.IssueSearcher, submitter
 	return s
 }
 
+// --- dead-letter label ---
+
+func TestWorkItemScanner_DeadLetterLabel_ExcludedFromScans(t *testing.T) {
+	var got models.SearchCriteria
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+			got = criteria
+			return nil, nil
+		},
+	}
+
+	s, err := scanner.NewWorkItemScanner(searcher, &scannertest.StubJobSubmitter{}, nil, nil, "",
+		scanner.WorkItemScannerConfig{
+			Criteria:        models.SearchCriteria{ExcludeLabels: []string{"wontfix"}},
+			PollInterval:    time.Hour,
+			DeadLetterLabel: "ai-dead-letter",
+		},
+		zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	runOneScan(t, s)
+
+	want := []string{"wontfix", "ai-dead-letter"}
+	if !slices.Equal(got.ExcludeLabels, want) {
+		t.Errorf("ExcludeLabels = %v, want %v", got.ExcludeLabels, want)
+	}
+}
+
+func TestWorkItemScanner_DeadLetterLabel_RemovedResetsAndResubmits(t *testing.T) {
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+			return []models.WorkItem{{Key: "PROJ-1"}}, nil
+		},
+	}
+
+	submitCalls := 0
+	submitter := &scannertest.StubJobSubmitter{
+		SubmitFunc: func(event jobmanager.Event) (*jobmanager.Job, error) {
+			submitCalls++
+			if submitCalls == 1 {
+				return nil, jobmanager.ErrRetriesExhausted
+			}
+			if !event.CleanRetry {
+				t.Error("expected resubmit event to have CleanRetry=true")
+			}
+			return &jobmanager.Job{}, nil
+		},
+	}
+
+	resetCalled := false
+	resetter := &scannertest.StubRetryResetter{
+		ResetRetriesFunc: func(ticketKey string) error {
+			resetCalled = true
+			return nil
+		},
+	}
+
+	s, err := scanner.NewWorkItemScanner(searcher, submitter, resetter, nil, "",
+		scanner.WorkItemScannerConfig{PollInterval: time.Hour, DeadLetterLabel: "ai-dead-letter"},
+		zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.MarkDeadLettered("PROJ-1")
+	runOneScan(t, s)
+
+	if !resetCalled {
+		t.Error("expected ResetRetries to be called")
+	}
+	if submitCalls != 2 {
+		t.Errorf("submit calls = %d, want 2 (initial + resubmit)", submitCalls)
+	}
+}
+
+// An exhausted ticket that was never dead-lettered (it failed before
+// the pipeline could add the label, or adding it failed) keeps its
+// retry limit.
+func TestWorkItemScanner_DeadLetterLabel_UnlabelledKeepsLimit(t *testing.T) {
+	searcher := &scannertest.StubIssueSearcher{
+		SearchWorkItemsFunc: func(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+			return []models.WorkItem{{Key: "PROJ-1"}}, nil
+		},
+	}
+	submitCalls := 0
+	submitter := &scannertest.StubJobSubmitter{
+		SubmitFunc: func(event jobmanager.Event) (*jobmanager.Job, error) {
+			submitCalls++
+			return nil, jobmanager.ErrRetriesExhausted
+		},
+	}
+	resetter := &scannertest.StubRetryResetter{
+		ResetRetriesFunc: func(ticketKey string) error {
+			t.Error("ResetRetries called for a ticket that was never dead-lettered")
+			return nil
+		},
+	}
+
+	s, err := scanner.NewWorkItemScanner(searcher, submitter, resetter, nil, "",
+		scanner.WorkItemScannerConfig{PollInterval: time.Hour, DeadLetterLabel: "ai-dead-letter"},
+		zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.MarkDeadLettered("PROJ-2")
+	runOneScan(t, s)
+
+	if submitCalls != 1 {
+		t.Errorf("submit calls = %d, want 1", submitCalls)
+	}
+}
+
+func TestWorkItemScanner_LastScan(t *testing.T) {
+	searcher := &scannertest.StubIssueSearcher{}
+	submitter := &scannertest.StubJobSubmitter{}
+
+	s := newWorkItemScanner(t, searcher, submitter)
+	if !s.LastScan().IsZero() {
+		t.Fatalf("LastScan() = %v before any scan, want zero", s.LastScan())
+	}
+
+	before := time.Now()
+	runOneScan(t, s)
+
+	if got := s.LastScan(); got.Before(before) {
+		t.Errorf("LastScan() = %v, want at or after %v", got, before)
+	}
+}
+
 // runOneScan starts the scanner, waits for the immediate first scan
 // to complete, then stops. Uses the long poll interval (1 hour) set
 // by newWorkItemScanner to ensure only one scan cycle runs.
@@ -0,0 +1,52 @@
+package scm
+
+import (
+	"fmt"
+
+	"jira-ai-issue-solver/models"
+)
+
+// PRLookup is the subset of [Provider] that [RepoPRState] needs.
+type PRLookup interface {
+	GetPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+	GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+	GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+}
+
+// PRState is the state of a ticket's pull request in one repository.
+type PRState int
+
+const (
+	PRStateNone   PRState = iota // no PR under any head
+	PRStateMerged                // merged PR found
+	PRStateOpen                  // open (unmerged) PR found
+	PRStateClosed                // closed (not merged) PR found
+)
+
+// RepoPRState resolves the state of the pull request from any of heads
+// in owner/repo by checking all heads per state in priority order
+// (merged > open > closed), so that a merged PR under one head, e.g.
+// after a fork migration, is not masked by a stale PR under another.
+func RepoPRState(prs PRLookup, owner, repo string, heads []string) (PRState, error) {
+	lookups := []struct {
+		state PRState
+		kind  string
+		find  func(owner, repo, head string) (*models.PRDetails, error)
+	}{
+		{PRStateMerged, "merged", prs.GetMergedPRForBranch},
+		{PRStateOpen, "open", prs.GetPRForBranch},
+		{PRStateClosed, "closed", prs.GetClosedPRForBranch},
+	}
+	for _, l := range lookups {
+		for _, head := range heads {
+			pr, err := l.find(owner, repo, head)
+			if err != nil {
+				return PRStateNone, fmt.Errorf("look up %s PR for %s: %w", l.kind, head, err)
+			}
+			if pr != nil {
+				return l.state, nil
+			}
+		}
+	}
+	return PRStateNone, nil
+}
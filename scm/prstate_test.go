@@ -0,0 +1,76 @@
+package scm_test
+
+import (
+	"errors"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
+)
+
+// prLookup finds PRs by state and head branch.
+type prLookup struct {
+	open, closed, merged map[string]bool
+	err                  error
+}
+
+func (l prLookup) find(prs map[string]bool, head string) (*models.PRDetails, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	if prs[head] {
+		return &models.PRDetails{Branch: head}, nil
+	}
+	return nil, nil
+}
+
+func (l prLookup) GetPRForBranch(_, _, head string) (*models.PRDetails, error) {
+	return l.find(l.open, head)
+}
+
+func (l prLookup) GetClosedPRForBranch(_, _, head string) (*models.PRDetails, error) {
+	return l.find(l.closed, head)
+}
+
+func (l prLookup) GetMergedPRForBranch(_, _, head string) (*models.PRDetails, error) {
+	return l.find(l.merged, head)
+}
+
+func TestRepoPRState(t *testing.T) {
+	heads := []string{"fork:bot/PROJ-1", "bot/PROJ-1"}
+	tests := []struct {
+		name   string
+		lookup prLookup
+		want   scm.PRState
+	}{
+		{"no PR", prLookup{}, scm.PRStateNone},
+		{"open", prLookup{open: map[string]bool{"bot/PROJ-1": true}}, scm.PRStateOpen},
+		{"closed", prLookup{closed: map[string]bool{"fork:bot/PROJ-1": true}}, scm.PRStateClosed},
+		{"merged under one head masks a closed one under another", prLookup{
+			closed: map[string]bool{"fork:bot/PROJ-1": true},
+			merged: map[string]bool{"bot/PROJ-1": true},
+		}, scm.PRStateMerged},
+		{"open under one head masks a closed one under another", prLookup{
+			closed: map[string]bool{"fork:bot/PROJ-1": true},
+			open:   map[string]bool{"bot/PROJ-1": true},
+		}, scm.PRStateOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scm.RepoPRState(tt.lookup, "org", "repo", heads)
+			if err != nil {
+				t.Fatalf("RepoPRState: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RepoPRState = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoPRState_LookupError(t *testing.T) {
+	boom := errors.New("boom")
+	if _, err := scm.RepoPRState(prLookup{err: boom}, "org", "repo", []string{"bot/PROJ-1"}); !errors.Is(err, boom) {
+		t.Errorf("RepoPRState error = %v, want %v", err, boom)
+	}
+}
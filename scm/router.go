@@ -0,0 +1,370 @@
+package scm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// Compile-time check that Router is a Provider.
+var _ Provider = (*Router)(nil)
+
+// Router is a [Provider] that hands each call to the provider of the
+// repository's host, or to a fallback provider for hosts without one.
+//
+// Calls name the repository in one of three ways, and the host is
+// found accordingly: from a URL with [ExtractRepoInfo]; from an
+// owner and name, looked up among the repositories the router has seen
+// (see [Router.AddRepo]); or from a working copy's directory, whose
+// origin remote is read once and cached. The same owner/name on two
+// hosts cannot be told apart; the repository seen last wins.
+type Router struct {
+	fallback  Provider
+	providers map[string]Provider // by host
+	logger    *zap.Logger
+
+	mu    sync.RWMutex
+	repos map[string]string // "owner/repo" -> host
+	dirs  map[string]string // working copy directory -> host
+}
+
+// RouterOption configures optional Router behavior.
+type RouterOption func(*Router)
+
+// WithProvider routes repositories on host (e.g., "gitlab.example.com")
+// to p.
+func WithProvider(host string, p Provider) RouterOption {
+	return func(r *Router) {
+		r.providers[strings.ToLower(host)] = p
+	}
+}
+
+// NewRouter creates a Router that uses fallback for hosts without a
+// provider of their own. Returns an error if fallback or logger is nil.
+func NewRouter(fallback Provider, logger *zap.Logger, opts ...RouterOption) (*Router, error) {
+	if fallback == nil {
+		return nil, errors.New("fallback provider must not be nil")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	r := &Router{
+		fallback:  fallback,
+		providers: make(map[string]Provider),
+		logger:    logger,
+		repos:     make(map[string]string),
+		dirs:      make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	for host, p := range r.providers {
+		if p == nil {
+			return nil, fmt.Errorf("provider for %s must not be nil", host)
+		}
+	}
+	return r, nil
+}
+
+// AddRepo records the host of the repository at repoURL, so that calls
+// naming the repository by owner and name reach its host's provider.
+// Repositories are also recorded when a call passes their URL.
+func (r *Router) AddRepo(repoURL string) error {
+	info, err := ExtractRepoInfo(repoURL)
+	if err != nil {
+		return err
+	}
+	r.remember(info)
+	return nil
+}
+
+func (r *Router) remember(info RepoInfo) {
+	key := info.Owner + "/" + info.Repo
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if prev, ok := r.repos[key]; ok && prev != info.Host {
+		r.logger.Warn("Repository name seen on two hosts; using the latter",
+			zap.String("repo", key),
+			zap.String("previous_host", prev),
+			zap.String("host", info.Host))
+	}
+	r.repos[key] = info.Host
+}
+
+// provider returns the provider for host.
+func (r *Router) provider(host string) Provider {
+	if p, ok := r.providers[host]; ok {
+		return p
+	}
+	return r.fallback
+}
+
+// forURL returns the provider for the repository at repoURL.
+func (r *Router) forURL(repoURL string) Provider {
+	info, err := ExtractRepoInfo(repoURL)
+	if err != nil {
+		return r.fallback
+	}
+	r.remember(info)
+	return r.provider(info.Host)
+}
+
+// forRepo returns the provider for the repository owner/repo.
+func (r *Router) forRepo(owner, repo string) Provider {
+	r.mu.RLock()
+	host, ok := r.repos[owner+"/"+repo]
+	r.mu.RUnlock()
+	if !ok {
+		return r.fallback
+	}
+	return r.provider(host)
+}
+
+// forDir returns the provider for the working copy in dir. A working
+// copy cloned before a restart is recognized by its origin remote.
+func (r *Router) forDir(dir string) Provider {
+	r.mu.RLock()
+	host, ok := r.dirs[dir]
+	r.mu.RUnlock()
+	if ok {
+		return r.provider(host)
+	}
+	if len(r.providers) == 0 {
+		return r.fallback
+	}
+
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output() // #nosec G204 -- dir is a workspace path
+	if err != nil {
+		return r.fallback
+	}
+	info, err := ExtractRepoInfo(strings.TrimSpace(string(out)))
+	if err != nil {
+		return r.fallback
+	}
+	r.setDir(dir, info.Host)
+	return r.provider(info.Host)
+}
+
+func (r *Router) setDir(dir, host string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dirs[dir] = host
+}
+
+// CloneRepository clones with the provider of repoURL's host and
+// remembers the host for the working copy.
+func (r *Router) CloneRepository(repoURL, directory string, sparsePaths []string) error {
+	p := r.forURL(repoURL)
+	if err := p.CloneRepository(repoURL, directory, sparsePaths); err != nil {
+		return err
+	}
+	if info, err := ExtractRepoInfo(repoURL); err == nil {
+		r.setDir(directory, info.Host)
+	}
+	return nil
+}
+
+// Ping pings the fallback and every host's provider.
+func (r *Router) Ping(ctx context.Context) error {
+	errs := []error{r.fallback.Ping(ctx)}
+	hosts := make([]string, 0, len(r.providers))
+	for host := range r.providers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		if err := r.providers[host].Ping(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Router) CloneImport(url, destDir, ref string) error {
+	return r.forURL(url).CloneImport(url, destDir, ref)
+}
+
+func (r *Router) CreateBranch(directory, branchName, baseBranch string) error {
+	return r.forDir(directory).CreateBranch(directory, branchName, baseBranch)
+}
+
+func (r *Router) SwitchBranch(directory, branchName string) error {
+	return r.forDir(directory).SwitchBranch(directory, branchName)
+}
+
+func (r *Router) HasChanges(directory, baseBranch string) (bool, error) {
+	return r.forDir(directory).HasChanges(directory, baseBranch)
+}
+
+func (r *Router) DiffStat(directory, baseBranch string) (models.DiffStat, error) {
+	return r.forDir(directory).DiffStat(directory, baseBranch)
+}
+
+func (r *Router) ChangedFiles(directory, baseBranch string) ([]string, error) {
+	return r.forDir(directory).ChangedFiles(directory, baseBranch)
+}
+
+func (r *Router) DeletedFiles(directory, baseBranch string) ([]string, error) {
+	return r.forDir(directory).DeletedFiles(directory, baseBranch)
+}
+
+func (r *Router) Diff(directory, baseBranch string) (string, error) {
+	return r.forDir(directory).Diff(directory, baseBranch)
+}
+
+func (r *Router) BaseFile(directory, baseBranch, path string) ([]byte, bool, error) {
+	return r.forDir(directory).BaseFile(directory, baseBranch, path)
+}
+
+func (r *Router) CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error) {
+	return r.forRepo(owner, repo).CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch, coAuthor, importExcludes, skipFileGuardrail...)
+}
+
+func (r *Router) StripRemoteAuth(directory string) error {
+	return r.forDir(directory).StripRemoteAuth(directory)
+}
+
+func (r *Router) RestoreRemoteAuth(directory, owner, repo string) error {
+	return r.forRepo(owner, repo).RestoreRemoteAuth(directory, owner, repo)
+}
+
+func (r *Router) FetchRemote(directory string) error {
+	return r.forDir(directory).FetchRemote(directory)
+}
+
+func (r *Router) SyncWithRemote(directory, branch string, importExcludes []string) error {
+	return r.forDir(directory).SyncWithRemote(directory, branch, importExcludes)
+}
+
+func (r *Router) MergeBase(dir, branch, fetchURL string) ([]string, error) {
+	return r.forURL(fetchURL).MergeBase(dir, branch, fetchURL)
+}
+
+func (r *Router) CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error) {
+	return r.forURL(fetchURL).CherryPickPR(dir, fetchURL, prNumber, mergeCommitSHA)
+}
+
+func (r *Router) RebaseOnRemote(dir, branch, base string) ([]string, error) {
+	return r.forDir(dir).RebaseOnRemote(dir, branch, base)
+}
+
+func (r *Router) GetDefaultBranch(owner, repo string) (string, error) {
+	return r.forRepo(owner, repo).GetDefaultBranch(owner, repo)
+}
+
+func (r *Router) SyncFork(forkOwner, repo, branch string) error {
+	return r.forRepo(forkOwner, repo).SyncFork(forkOwner, repo, branch)
+}
+
+func (r *Router) BranchHasCommits(owner, repo, branch, base string) (bool, error) {
+	return r.forRepo(owner, repo).BranchHasCommits(owner, repo, branch, base)
+}
+
+func (r *Router) RemoteBranchExists(owner, repo, branch string) (bool, error) {
+	return r.forRepo(owner, repo).RemoteBranchExists(owner, repo, branch)
+}
+
+func (r *Router) DeleteRemoteBranch(owner, repo, branch string) error {
+	return r.forRepo(owner, repo).DeleteRemoteBranch(owner, repo, branch)
+}
+
+func (r *Router) CreatePR(params models.PRParams) (*models.PR, error) {
+	return r.forRepo(params.Owner, params.Repo).CreatePR(params)
+}
+
+func (r *Router) GetPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return r.forRepo(owner, repo).GetPRForBranch(owner, repo, head)
+}
+
+func (r *Router) GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return r.forRepo(owner, repo).GetClosedPRForBranch(owner, repo, head)
+}
+
+func (r *Router) GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return r.forRepo(owner, repo).GetMergedPRForBranch(owner, repo, head)
+}
+
+func (r *Router) CountOpenPRs(owner, repo, branchPrefix string) (int, error) {
+	return r.forRepo(owner, repo).CountOpenPRs(owner, repo, branchPrefix)
+}
+
+func (r *Router) GetPRMergeability(owner, repo string, number int) (*models.PRMergeState, error) {
+	return r.forRepo(owner, repo).GetPRMergeability(owner, repo, number)
+}
+
+func (r *Router) UpdatePRBody(owner, repo string, number int, body string) error {
+	return r.forRepo(owner, repo).UpdatePRBody(owner, repo, number, body)
+}
+
+func (r *Router) EnablePRAutoMerge(owner, repo string, number int, method string) error {
+	return r.forRepo(owner, repo).EnablePRAutoMerge(owner, repo, number, method)
+}
+
+func (r *Router) AddPRLabel(owner, repo string, number int, label string) error {
+	return r.forRepo(owner, repo).AddPRLabel(owner, repo, number, label)
+}
+
+func (r *Router) RemovePRLabel(owner, repo string, number int, label string) error {
+	return r.forRepo(owner, repo).RemovePRLabel(owner, repo, number, label)
+}
+
+func (r *Router) HasPRLabel(owner, repo string, number int, label string) (bool, error) {
+	return r.forRepo(owner, repo).HasPRLabel(owner, repo, number, label)
+}
+
+func (r *Router) LastLabelRemoval(owner, repo string, number int, label string) (time.Time, error) {
+	return r.forRepo(owner, repo).LastLabelRemoval(owner, repo, number, label)
+}
+
+func (r *Router) GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error) {
+	return r.forRepo(owner, repo).GetPRComments(owner, repo, number, since)
+}
+
+func (r *Router) GetPRReviewComments(owner, repo string, number int) ([]models.PRComment, error) {
+	return r.forRepo(owner, repo).GetPRReviewComments(owner, repo, number)
+}
+
+func (r *Router) ReplyToComment(owner, repo string, prNumber int, commentID int64, body string) error {
+	return r.forRepo(owner, repo).ReplyToComment(owner, repo, prNumber, commentID, body)
+}
+
+func (r *Router) PostIssueComment(owner, repo string, prNumber int, body string) error {
+	return r.forRepo(owner, repo).PostIssueComment(owner, repo, prNumber, body)
+}
+
+func (r *Router) ListIssueComments(owner, repo string, prNumber int) ([]models.IssueComment, error) {
+	return r.forRepo(owner, repo).ListIssueComments(owner, repo, prNumber)
+}
+
+func (r *Router) UpdateIssueComment(owner, repo string, commentID int64, body string) error {
+	return r.forRepo(owner, repo).UpdateIssueComment(owner, repo, commentID, body)
+}
+
+func (r *Router) AddCommentReaction(owner, repo string, comment models.PRComment, reaction string) error {
+	return r.forRepo(owner, repo).AddCommentReaction(owner, repo, comment, reaction)
+}
+
+func (r *Router) ListCheckRunsForRef(owner, repo, ref string) ([]models.CheckRunFailure, bool, error) {
+	return r.forRepo(owner, repo).ListCheckRunsForRef(owner, repo, ref)
+}
+
+func (r *Router) ListCheckRunAnnotations(owner, repo string, checkRunID int64) ([]models.CheckAnnotation, error) {
+	return r.forRepo(owner, repo).ListCheckRunAnnotations(owner, repo, checkRunID)
+}
+
+func (r *Router) GetFailedJobLogs(owner, repo, headSHA string, maxBytesPerStep int) (map[string][]models.FailedStep, error) {
+	return r.forRepo(owner, repo).GetFailedJobLogs(owner, repo, headSHA, maxBytesPerStep)
+}
+
+func (r *Router) CreateCheckRun(owner, repo string, run models.CheckRun) error {
+	return r.forRepo(owner, repo).CreateCheckRun(owner, repo, run)
+}
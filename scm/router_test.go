@@ -0,0 +1,141 @@
+package scm_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
+)
+
+// fakeProvider records the methods called on it. Methods it does not
+// override panic through the nil embedded Provider.
+type fakeProvider struct {
+	scm.Provider
+	calls   []string
+	pingErr error
+}
+
+func (f *fakeProvider) CloneRepository(repoURL, _ string, _ []string) error {
+	f.calls = append(f.calls, "CloneRepository "+repoURL)
+	return nil
+}
+
+func (f *fakeProvider) HasChanges(directory, _ string) (bool, error) {
+	f.calls = append(f.calls, "HasChanges "+filepath.Base(directory))
+	return true, nil
+}
+
+func (f *fakeProvider) CreatePR(params models.PRParams) (*models.PR, error) {
+	f.calls = append(f.calls, "CreatePR "+params.Owner+"/"+params.Repo)
+	return &models.PR{}, nil
+}
+
+func (f *fakeProvider) GetDefaultBranch(owner, repo string) (string, error) {
+	f.calls = append(f.calls, "GetDefaultBranch "+owner+"/"+repo)
+	return "main", nil
+}
+
+func (f *fakeProvider) Ping(context.Context) error { return f.pingErr }
+
+func newRouter(t *testing.T) (*scm.Router, *fakeProvider, *fakeProvider) {
+	t.Helper()
+	github, gitlab := &fakeProvider{}, &fakeProvider{}
+	r, err := scm.NewRouter(github, zap.NewNop(), scm.WithProvider("GitLab.example.com", gitlab))
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return r, github, gitlab
+}
+
+func TestNewRouter_RejectsNilProviders(t *testing.T) {
+	if _, err := scm.NewRouter(nil, zap.NewNop()); err == nil {
+		t.Error("expected error for nil fallback")
+	}
+	if _, err := scm.NewRouter(&fakeProvider{}, zap.NewNop(), scm.WithProvider("gitlab.example.com", nil)); err == nil {
+		t.Error("expected error for nil host provider")
+	}
+}
+
+func TestRouter_RoutesByRepositoryHost(t *testing.T) {
+	r, github, gitlab := newRouter(t)
+	if err := r.AddRepo("https://gitlab.example.com/group/sub/app.git"); err != nil {
+		t.Fatalf("AddRepo: %v", err)
+	}
+
+	if _, err := r.CreatePR(models.PRParams{Owner: "group/sub", Repo: "app"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetDefaultBranch("org", "unknown"); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "ws")
+	if err := r.CloneRepository("git@gitlab.example.com:team/lib.git", dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.HasChanges(dir, "main"); err != nil {
+		t.Fatal(err)
+	}
+	// Cloning taught the router the repository's host.
+	if _, err := r.GetDefaultBranch("team", "lib"); err != nil {
+		t.Fatal(err)
+	}
+
+	wantGitLab := []string{
+		"CreatePR group/sub/app",
+		"CloneRepository git@gitlab.example.com:team/lib.git",
+		"HasChanges ws",
+		"GetDefaultBranch team/lib",
+	}
+	if strings.Join(gitlab.calls, "\n") != strings.Join(wantGitLab, "\n") {
+		t.Errorf("gitlab calls = %q, want %q", gitlab.calls, wantGitLab)
+	}
+	if want := []string{"GetDefaultBranch org/unknown"}; strings.Join(github.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("fallback calls = %q, want %q", github.calls, want)
+	}
+}
+
+func TestRouter_RoutesWorkingCopyByOriginRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "https://gitlab.example.com/team/app.git"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	r, github, gitlab := newRouter(t)
+	if _, err := r.HasChanges(dir, "main"); err != nil {
+		t.Fatal(err)
+	}
+	if len(gitlab.calls) != 1 || len(github.calls) != 0 {
+		t.Errorf("gitlab calls = %q, fallback calls = %q; want the working copy routed to gitlab", gitlab.calls, github.calls)
+	}
+}
+
+func TestRouter_PingReportsEveryHost(t *testing.T) {
+	r, github, gitlab := newRouter(t)
+	if err := r.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	github.pingErr = errors.New("github down")
+	gitlab.pingErr = errors.New("gitlab down")
+	err := r.Ping(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "github down") || !strings.Contains(err.Error(), "gitlab.example.com: gitlab down") {
+		t.Errorf("Ping error = %v, want both hosts' errors", err)
+	}
+}
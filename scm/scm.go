@@ -0,0 +1,158 @@
+// Package scm abstracts the source code management host a repository
+// lives on. [Provider] is the set of operations the bot performs
+// against a host, implemented for GitHub by services.GitHubServiceImpl;
+// implementations for other hosts (Gitea, Azure DevOps, GitLab,
+// Bitbucket) satisfy the same interface. [Router] implements Provider by handing each call
+// to the provider registered for the repository's host, which
+// [ExtractRepoInfo] reads from the repository URL.
+//
+// Consumer packages keep declaring the narrow interfaces they need;
+// the Router satisfies them all, as the GitHub service does.
+package scm
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+// Provider is a source code management host. Repositories are
+// identified by owner and name as [ExtractRepoInfo] returns them;
+// hosts with nested groups use the full group path as the owner.
+type Provider interface {
+	// Working copies. Methods taking a directory act on a clone made
+	// by CloneRepository.
+	CloneRepository(repoURL, directory string, sparsePaths []string) error
+	CloneImport(url, destDir, ref string) error
+	CreateBranch(directory, branchName, baseBranch string) error
+	SwitchBranch(directory, branchName string) error
+	HasChanges(directory, baseBranch string) (bool, error)
+	DiffStat(directory, baseBranch string) (models.DiffStat, error)
+	ChangedFiles(directory, baseBranch string) ([]string, error)
+	DeletedFiles(directory, baseBranch string) ([]string, error)
+	Diff(directory, baseBranch string) (string, error)
+	BaseFile(directory, baseBranch, path string) ([]byte, bool, error)
+	CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error)
+	StripRemoteAuth(directory string) error
+	RestoreRemoteAuth(directory, owner, repo string) error
+	FetchRemote(directory string) error
+	SyncWithRemote(directory, branch string, importExcludes []string) error
+	MergeBase(dir, branch, fetchURL string) ([]string, error)
+	CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error)
+	RebaseOnRemote(dir, branch, base string) ([]string, error)
+
+	// Repositories and branches on the host.
+	GetDefaultBranch(owner, repo string) (string, error)
+	SyncFork(forkOwner, repo, branch string) error
+	BranchHasCommits(owner, repo, branch, base string) (bool, error)
+	RemoteBranchExists(owner, repo, branch string) (bool, error)
+	DeleteRemoteBranch(owner, repo, branch string) error
+
+	// Pull (merge) requests.
+	CreatePR(params models.PRParams) (*models.PR, error)
+	GetPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+	GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+	GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error)
+	CountOpenPRs(owner, repo, branchPrefix string) (int, error)
+	GetPRMergeability(owner, repo string, number int) (*models.PRMergeState, error)
+	UpdatePRBody(owner, repo string, number int, body string) error
+	EnablePRAutoMerge(owner, repo string, number int, method string) error
+	AddPRLabel(owner, repo string, number int, label string) error
+	RemovePRLabel(owner, repo string, number int, label string) error
+	HasPRLabel(owner, repo string, number int, label string) (bool, error)
+	LastLabelRemoval(owner, repo string, number int, label string) (time.Time, error)
+
+	// PR comments and reviews.
+	GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error)
+	GetPRReviewComments(owner, repo string, number int) ([]models.PRComment, error)
+	ReplyToComment(owner, repo string, prNumber int, commentID int64, body string) error
+	PostIssueComment(owner, repo string, prNumber int, body string) error
+	ListIssueComments(owner, repo string, prNumber int) ([]models.IssueComment, error)
+	UpdateIssueComment(owner, repo string, commentID int64, body string) error
+	AddCommentReaction(owner, repo string, comment models.PRComment, reaction string) error
+
+	// CI results for a commit.
+	ListCheckRunsForRef(owner, repo, ref string) ([]models.CheckRunFailure, bool, error)
+	ListCheckRunAnnotations(owner, repo string, checkRunID int64) ([]models.CheckAnnotation, error)
+	GetFailedJobLogs(owner, repo, headSHA string, maxBytesPerStep int) (map[string][]models.FailedStep, error)
+	CreateCheckRun(owner, repo string, run models.CheckRun) error
+
+	// Ping checks that the host is reachable with the configured
+	// credentials.
+	Ping(ctx context.Context) error
+}
+
+// RepoInfo identifies a repository on a host.
+type RepoInfo struct {
+	// Host is the lower-cased host name, without port.
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// ExtractRepoInfo parses a repository URL: HTTPS or SSH URLs
+// (https://host/owner/repo.git, ssh://git@host:port/owner/repo.git)
+// and scp-like SSH addresses (git@host:owner/repo.git). The owner is
+// every path segment before the repository name, so GitLab subgroups
+// yield an owner such as "group/subgroup".
+//
+// Azure DevOps URLs (https://dev.azure.com/org/project/_git/repo) yield
+// the owner "org/project". Their legacy and SSH forms
+// (https://org.visualstudio.com/project/_git/repo,
+// git@ssh.dev.azure.com:v3/org/project/repo) are reported as the same
+// repository on dev.azure.com.
+func ExtractRepoInfo(repoURL string) (RepoInfo, error) {
+	raw := strings.TrimSpace(repoURL)
+	var host, path string
+	if strings.Contains(raw, "://") {
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			return RepoInfo{}, fmt.Errorf("invalid repository URL: %w", err)
+		}
+		host, path = parsed.Hostname(), parsed.Path
+	} else {
+		hostPart, p, ok := strings.Cut(raw, ":")
+		if !ok {
+			return RepoInfo{}, fmt.Errorf("unsupported repository URL format: %s", repoURL)
+		}
+		if i := strings.LastIndex(hostPart, "@"); i >= 0 {
+			hostPart = hostPart[i+1:]
+		}
+		host, path = hostPart, p
+	}
+
+	host = strings.ToLower(host)
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	host, path = azureRepoPath(host, path)
+	i := strings.LastIndex(path, "/")
+	if host == "" || i <= 0 || i == len(path)-1 {
+		return RepoInfo{}, fmt.Errorf("repository URL %q does not contain host and owner/repo", repoURL)
+	}
+	return RepoInfo{
+		Host:  host,
+		Owner: path[:i],
+		Repo:  path[i+1:],
+	}, nil
+}
+
+// azureRepoPath rewrites an Azure DevOps repository path to
+// "org/project/repo" on dev.azure.com, dropping the "_git" segment.
+// Azure DevOps Server paths ("collection/project/_git/repo") keep their
+// host. Other paths are returned unchanged.
+func azureRepoPath(host, path string) (string, string) {
+	switch {
+	case host == "ssh.dev.azure.com" || host == "vs-ssh.visualstudio.com":
+		host, path = "dev.azure.com", strings.TrimPrefix(path, "v3/")
+	case strings.HasSuffix(host, ".visualstudio.com"):
+		org := strings.TrimSuffix(host, ".visualstudio.com")
+		host, path = "dev.azure.com", org+"/"+strings.TrimPrefix(path, "DefaultCollection/")
+	}
+	if before, after, ok := strings.Cut(path, "/_git/"); ok {
+		path = before + "/" + after
+	}
+	return host, path
+}
@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSSecretsClient is the subset of the Secrets Manager API used by
+// [AWSProvider]. Satisfied by [secretsmanager.Client].
+type AWSSecretsClient interface {
+	GetSecretValue(ctx context.Context, in *secretsmanager.GetSecretValueInput, opts ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// AWSProvider reads secrets from AWS Secrets Manager. The reference
+// path is the secret name or ARN.
+type AWSProvider struct {
+	region string
+
+	once      sync.Once
+	client    AWSSecretsClient
+	clientErr error
+}
+
+// NewAWSProvider creates an AWSProvider backed by the given client.
+// Returns an error if client is nil.
+func NewAWSProvider(client AWSSecretsClient) (*AWSProvider, error) {
+	if client == nil {
+		return nil, errors.New("secrets manager client must not be nil")
+	}
+	return &AWSProvider{client: client}, nil
+}
+
+// NewDefaultAWSProvider creates an AWSProvider using the SDK's default
+// credential chain (environment, shared config, IRSA, instance
+// role). An empty region defers to the SDK's region resolution. The
+// AWS configuration is loaded on first fetch, so deployments without
+// aws:// references never look for AWS credentials.
+func NewDefaultAWSProvider(region string) *AWSProvider {
+	return &AWSProvider{region: region}
+}
+
+// Fetch returns the current version of the secret, or its JSON field
+// ref.Key if set.
+func (p *AWSProvider) Fetch(ctx context.Context, ref Ref) (string, error) {
+	client, err := p.secretsClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(ref.Path),
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting secret value: %w", err)
+	}
+	if out.SecretString == nil {
+		return "", errors.New("secret has no string value")
+	}
+	return selectKey(*out.SecretString, ref.Key)
+}
+
+// secretsClient returns the Secrets Manager client, loading the AWS
+// default configuration on first use when none was given.
+func (p *AWSProvider) secretsClient(ctx context.Context) (AWSSecretsClient, error) {
+	p.once.Do(func() {
+		if p.client != nil {
+			return
+		}
+		var opts []func(*awsconfig.LoadOptions) error
+		if p.region != "" {
+			opts = append(opts, awsconfig.WithRegion(p.region))
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			p.clientErr = fmt.Errorf("loading AWS config: %w", err)
+			return
+		}
+		p.client = secretsmanager.NewFromConfig(cfg)
+	})
+	return p.client, p.clientErr
+}
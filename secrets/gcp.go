@@ -0,0 +1,118 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// gcpEndpoint is the Secret Manager REST API base URL.
+const gcpEndpoint = "https://secretmanager.googleapis.com/v1/"
+
+// gcpScope is the OAuth scope required to access secrets.
+const gcpScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// GCPConfig holds connection settings for [GCPProvider].
+type GCPConfig struct {
+	// Endpoint overrides the Secret Manager API base URL. Exposed
+	// for testing.
+	Endpoint string
+
+	// TokenSource supplies OAuth tokens. Defaults to Application
+	// Default Credentials, resolved on first fetch.
+	TokenSource oauth2.TokenSource
+}
+
+// GCPProvider reads secrets from Google Cloud Secret Manager via its
+// REST API. The reference path is the secret resource name
+// ("projects/<p>/secrets/<s>"), optionally followed by
+// "/versions/<v>"; the latest version is used otherwise.
+type GCPProvider struct {
+	cfg GCPConfig
+
+	once      sync.Once
+	client    *http.Client
+	clientErr error
+}
+
+// NewGCPProvider creates a GCPProvider.
+func NewGCPProvider(cfg GCPConfig) *GCPProvider {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = gcpEndpoint
+	}
+	return &GCPProvider{cfg: cfg}
+}
+
+// Fetch returns the secret payload, or its JSON field ref.Key if set.
+func (p *GCPProvider) Fetch(ctx context.Context, ref Ref) (string, error) {
+	client, err := p.httpClient()
+	if err != nil {
+		return "", err
+	}
+
+	name := ref.Path
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+	url := strings.TrimRight(p.cfg.Endpoint, "/") + "/" + name + ":access"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting secret manager: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading secret manager response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secret manager returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("decoding secret manager response: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding secret payload: %w", err)
+	}
+	return selectKey(string(data), ref.Key)
+}
+
+// httpClient lazily builds the authenticated client so that
+// deployments without GCP references never need GCP credentials.
+// The client outlives any single fetch, so it is built with a
+// background context.
+func (p *GCPProvider) httpClient() (*http.Client, error) {
+	p.once.Do(func() {
+		ts := p.cfg.TokenSource
+		if ts == nil {
+			var err error
+			ts, err = google.DefaultTokenSource(context.Background(), gcpScope)
+			if err != nil {
+				p.clientErr = fmt.Errorf("finding GCP credentials: %w", err)
+				return
+			}
+		}
+		p.client = oauth2.NewClient(context.Background(), ts)
+	})
+	return p.client, p.clientErr
+}
@@ -0,0 +1,335 @@
+// Package secrets resolves credentials referenced from configuration
+// values instead of stored in plaintext.
+//
+// # References
+//
+// A config value such as jira.api_token may be a URI naming a secret
+// in an external manager:
+//
+//	vault://secret/data/jira#api_token         HashiCorp Vault (KV v1 or v2)
+//	awssm://prod/jira-bot#api_token            AWS Secrets Manager
+//	gcpsm://projects/p/secrets/jira-token      GCP Secret Manager (latest version)
+//
+// The fragment selects a key from a JSON secret. It is required for
+// Vault and optional for AWS and GCP, where a secret without a
+// fragment is used verbatim. Values without a recognized scheme are
+// plaintext and pass through unchanged.
+//
+// # Store
+//
+// [Store] fetches every reference once at startup via [Store.Load]
+// (failures are fatal to the caller) and, when started, refreshes
+// them periodically. A failed refresh keeps the previous value.
+// Consumers call [Store.Resolve] each time they need a credential so
+// that rotated secrets are picked up without a restart.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reference schemes recognized by [Parse].
+const (
+	SchemeVault = "vault"
+	SchemeAWS   = "awssm"
+	SchemeGCP   = "gcpsm"
+)
+
+// Ref is a parsed secret reference.
+type Ref struct {
+	// Scheme selects the provider (e.g., [SchemeVault]).
+	Scheme string
+
+	// Path identifies the secret within the provider.
+	Path string
+
+	// Key selects a field of a JSON secret. Empty means the whole
+	// secret value.
+	Key string
+}
+
+// String returns the reference in URI form.
+func (r Ref) String() string {
+	s := r.Scheme + "://" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// IsRef reports whether value is a secret reference rather than a
+// plaintext value.
+func IsRef(value string) bool {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return false
+	}
+	switch scheme {
+	case SchemeVault, SchemeAWS, SchemeGCP:
+		return true
+	}
+	return false
+}
+
+// Parse parses a secret reference. Returns an error if value is not a
+// reference or is malformed.
+func Parse(value string) (Ref, error) {
+	if !IsRef(value) {
+		return Ref{}, fmt.Errorf("not a secret reference: %q", redact(value))
+	}
+	scheme, rest, _ := strings.Cut(value, "://")
+	path, key, _ := strings.Cut(rest, "#")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return Ref{}, fmt.Errorf("secret reference %q has no path", value)
+	}
+	if scheme == SchemeVault && key == "" {
+		return Ref{}, fmt.Errorf("vault reference %q must select a key with #<key>", value)
+	}
+	return Ref{Scheme: scheme, Path: path, Key: key}, nil
+}
+
+// redact hides all but a short prefix of a value that may be a
+// plaintext credential.
+func redact(value string) string {
+	if len(value) <= 4 {
+		return "****"
+	}
+	return value[:4] + "****"
+}
+
+// Provider fetches secret values from one secret manager.
+type Provider interface {
+	Fetch(ctx context.Context, ref Ref) (string, error)
+}
+
+// Option configures optional behavior on a [Store].
+type Option func(*Store)
+
+// WithProvider registers the provider for a reference scheme.
+func WithProvider(scheme string, p Provider) Option {
+	return func(s *Store) {
+		if p != nil {
+			s.providers[scheme] = p
+		}
+	}
+}
+
+// Config holds construction parameters for [Store].
+type Config struct {
+	// RefreshInterval is the time between refreshes once the store
+	// is started. Zero disables periodic refresh.
+	RefreshInterval time.Duration
+
+	// FetchTimeout bounds each fetch. Defaults to 30 seconds.
+	FetchTimeout time.Duration
+}
+
+// defaultFetchTimeout bounds each fetch when [Config.FetchTimeout] is
+// not set.
+const defaultFetchTimeout = 30 * time.Second
+
+// Store caches resolved secret values and refreshes them in the
+// background.
+type Store struct {
+	cfg       Config
+	providers map[string]Provider
+	logger    *zap.Logger
+
+	valuesMu sync.RWMutex
+	refs     []Ref
+	values   map[string]string
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewStore creates a Store. Returns an error if any required
+// parameter is invalid.
+func NewStore(cfg Config, logger *zap.Logger, opts ...Option) (*Store, error) {
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if cfg.RefreshInterval < 0 {
+		return nil, errors.New("refresh interval must not be negative")
+	}
+	if cfg.FetchTimeout <= 0 {
+		cfg.FetchTimeout = defaultFetchTimeout
+	}
+
+	s := &Store{
+		cfg:       cfg,
+		providers: make(map[string]Provider),
+		logger:    logger,
+		values:    make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// Load parses and fetches every reference among values, caching the
+// results. Plaintext values are ignored. Returns an error naming the
+// first reference that cannot be fetched; the error never includes
+// secret material.
+func (s *Store) Load(ctx context.Context, values ...string) error {
+	var refs []Ref
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !IsRef(v) || seen[v] {
+			continue
+		}
+		ref, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		seen[v] = true
+		refs = append(refs, ref)
+	}
+
+	fetched := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		value, err := s.fetch(ctx, ref)
+		if err != nil {
+			return err
+		}
+		fetched[ref.String()] = value
+	}
+
+	s.valuesMu.Lock()
+	s.refs = append(s.refs, refs...)
+	for k, v := range fetched {
+		s.values[k] = v
+	}
+	s.valuesMu.Unlock()
+
+	if len(refs) > 0 {
+		s.logger.Info("Loaded secrets from secret manager", zap.Int("count", len(refs)))
+	}
+	return nil
+}
+
+// Resolve returns the current value for a config value. Plaintext
+// values are returned unchanged. A reference that was not passed to
+// [Store.Load] resolves to the empty string.
+func (s *Store) Resolve(value string) string {
+	if !IsRef(value) {
+		return value
+	}
+	ref, err := Parse(value)
+	if err != nil {
+		return ""
+	}
+	s.valuesMu.RLock()
+	defer s.valuesMu.RUnlock()
+	return s.values[ref.String()]
+}
+
+// Refresh re-fetches every loaded reference. References that fail to
+// refresh keep their previous value. Returns the number of failures.
+func (s *Store) Refresh(ctx context.Context) int {
+	s.valuesMu.RLock()
+	refs := append([]Ref(nil), s.refs...)
+	s.valuesMu.RUnlock()
+
+	failures := 0
+	for _, ref := range refs {
+		value, err := s.fetch(ctx, ref)
+		if err != nil {
+			failures++
+			s.logger.Warn("Failed to refresh secret, keeping previous value",
+				zap.String("ref", ref.String()),
+				zap.Error(err))
+			continue
+		}
+		s.valuesMu.Lock()
+		changed := s.values[ref.String()] != value
+		s.values[ref.String()] = value
+		s.valuesMu.Unlock()
+		if changed {
+			s.logger.Info("Secret rotated", zap.String("ref", ref.String()))
+		}
+	}
+	return failures
+}
+
+// Start begins periodic refresh in a background goroutine. A no-op
+// when [Config.RefreshInterval] is zero. Returns an error if already
+// running.
+func (s *Store) Start(ctx context.Context) error {
+	if s.cfg.RefreshInterval == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return errors.New("secret store already running")
+	}
+
+	done := make(chan struct{})
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = done
+	go s.run(ctx, done)
+	return nil
+}
+
+// Stop cancels refresh and blocks until the goroutine exits. Safe to
+// call multiple times or without a prior Start.
+func (s *Store) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	done := s.done
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (s *Store) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Refresh(ctx)
+		}
+	}
+}
+
+func (s *Store) fetch(ctx context.Context, ref Ref) (string, error) {
+	p, ok := s.providers[ref.Scheme]
+	if !ok {
+		return "", fmt.Errorf("no secret provider configured for %s", ref)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.FetchTimeout)
+	defer cancel()
+
+	value, err := p.Fetch(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("fetching %s: %w", ref, err)
+	}
+	if value == "" {
+		return "", fmt.Errorf("secret %s is empty", ref)
+	}
+	return value, nil
+}
@@ -0,0 +1,277 @@
+package secrets_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"jira-ai-issue-solver/secrets"
+)
+
+// stubProvider returns values from a map keyed by ref path, or err.
+type stubProvider struct {
+	mu     sync.Mutex
+	values map[string]string
+	err    error
+	calls  int
+}
+
+func (p *stubProvider) Fetch(_ context.Context, ref secrets.Ref) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.err != nil {
+		return "", p.err
+	}
+	return p.values[ref.Path], nil
+}
+
+func (p *stubProvider) set(path, value string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values[path] = value
+	p.err = err
+}
+
+func newStore(t *testing.T, p secrets.Provider) *secrets.Store {
+	t.Helper()
+	s, err := secrets.NewStore(secrets.Config{}, zap.NewNop(), secrets.WithProvider(secrets.SchemeVault, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// --- Parse ---
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    secrets.Ref
+		wantErr bool
+	}{
+		{value: "vault://secret/data/jira#api_token", want: secrets.Ref{Scheme: "vault", Path: "secret/data/jira", Key: "api_token"}},
+		{value: "awssm://prod/jira-bot", want: secrets.Ref{Scheme: "awssm", Path: "prod/jira-bot"}},
+		{value: "gcpsm://projects/p/secrets/s#token", want: secrets.Ref{Scheme: "gcpsm", Path: "projects/p/secrets/s", Key: "token"}},
+		{value: "vault://secret/data/jira", wantErr: true},
+		{value: "awssm://", wantErr: true},
+		{value: "plaintext-token", wantErr: true},
+		{value: "https://example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := secrets.Parse(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_ErrorDoesNotLeakPlaintext(t *testing.T) {
+	_, err := secrets.Parse("supersecrettoken")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), "supersecrettoken") {
+		t.Errorf("error %q contains the plaintext value", err)
+	}
+}
+
+// --- Store ---
+
+func TestStore_ResolvePlaintextPassesThrough(t *testing.T) {
+	s := newStore(t, &stubProvider{values: map[string]string{}})
+	if got := s.Resolve("plain"); got != "plain" {
+		t.Errorf("Resolve() = %q, want %q", got, "plain")
+	}
+}
+
+func TestStore_LoadAndResolve(t *testing.T) {
+	p := &stubProvider{values: map[string]string{"secret/data/jira": "token-1"}}
+	s := newStore(t, p)
+
+	ref := "vault://secret/data/jira#api_token"
+	if err := s.Load(context.Background(), ref, "plain", ref); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Resolve(ref); got != "token-1" {
+		t.Errorf("Resolve() = %q, want %q", got, "token-1")
+	}
+	if p.calls != 1 {
+		t.Errorf("provider called %d times, want 1 (duplicates deduplicated)", p.calls)
+	}
+}
+
+func TestStore_LoadFailsForUnknownScheme(t *testing.T) {
+	s := newStore(t, &stubProvider{values: map[string]string{}})
+	if err := s.Load(context.Background(), "awssm://prod/jira"); err == nil {
+		t.Fatal("expected error for unregistered provider")
+	}
+}
+
+func TestStore_LoadFailsForEmptySecret(t *testing.T) {
+	s := newStore(t, &stubProvider{values: map[string]string{}})
+	if err := s.Load(context.Background(), "vault://secret/data/jira#api_token"); err == nil {
+		t.Fatal("expected error for empty secret")
+	}
+}
+
+func TestStore_RefreshRotatesAndKeepsOnFailure(t *testing.T) {
+	p := &stubProvider{values: map[string]string{"secret/data/jira": "token-1"}}
+	s := newStore(t, p)
+
+	ref := "vault://secret/data/jira#api_token"
+	if err := s.Load(context.Background(), ref); err != nil {
+		t.Fatal(err)
+	}
+
+	p.set("secret/data/jira", "token-2", nil)
+	if failures := s.Refresh(context.Background()); failures != 0 {
+		t.Errorf("Refresh() failures = %d, want 0", failures)
+	}
+	if got := s.Resolve(ref); got != "token-2" {
+		t.Errorf("Resolve() after rotation = %q, want %q", got, "token-2")
+	}
+
+	p.set("secret/data/jira", "", errors.New("vault sealed"))
+	if failures := s.Refresh(context.Background()); failures != 1 {
+		t.Errorf("Refresh() failures = %d, want 1", failures)
+	}
+	if got := s.Resolve(ref); got != "token-2" {
+		t.Errorf("Resolve() after failed refresh = %q, want previous value %q", got, "token-2")
+	}
+}
+
+// --- Vault ---
+
+func TestVaultProvider_Fetch(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "kv v2", body: `{"data":{"data":{"api_token":"v2-token"},"metadata":{"version":3}}}`},
+		{name: "kv v1", body: `{"data":{"api_token":"v2-token"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/secret/data/jira" {
+					t.Errorf("path = %q, want /v1/secret/data/jira", r.URL.Path)
+				}
+				if r.Header.Get("X-Vault-Token") != "root" {
+					t.Errorf("X-Vault-Token = %q, want root", r.Header.Get("X-Vault-Token"))
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			p, err := secrets.NewVaultProvider(secrets.VaultConfig{Address: srv.URL, Token: "root"}, srv.Client())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Fetch(context.Background(), secrets.Ref{Scheme: "vault", Path: "secret/data/jira", Key: "api_token"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "v2-token" {
+				t.Errorf("Fetch() = %q, want %q", got, "v2-token")
+			}
+		})
+	}
+}
+
+func TestVaultProvider_MissingKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"other":"x"}}`))
+	}))
+	defer srv.Close()
+
+	p, err := secrets.NewVaultProvider(secrets.VaultConfig{Address: srv.URL, Token: "root"}, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Fetch(context.Background(), secrets.Ref{Path: "secret/jira", Key: "api_token"}); err == nil {
+		t.Fatal("expected error for missing key")
+	}
+}
+
+// --- AWS ---
+
+type stubAWSClient struct {
+	secretString *string
+	gotID        string
+}
+
+func (c *stubAWSClient) GetSecretValue(_ context.Context, in *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	c.gotID = aws.ToString(in.SecretId)
+	return &secretsmanager.GetSecretValueOutput{SecretString: c.secretString}, nil
+}
+
+func TestAWSProvider_Fetch(t *testing.T) {
+	client := &stubAWSClient{secretString: aws.String(`{"api_token":"aws-token"}`)}
+	p, err := secrets.NewAWSProvider(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.Fetch(context.Background(), secrets.Ref{Scheme: "awssm", Path: "prod/jira", Key: "api_token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "aws-token" {
+		t.Errorf("Fetch() = %q, want %q", got, "aws-token")
+	}
+	if client.gotID != "prod/jira" {
+		t.Errorf("SecretId = %q, want prod/jira", client.gotID)
+	}
+
+	raw, err := p.Fetch(context.Background(), secrets.Ref{Scheme: "awssm", Path: "prod/jira"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw != `{"api_token":"aws-token"}` {
+		t.Errorf("Fetch() without key = %q, want raw secret", raw)
+	}
+}
+
+// --- GCP ---
+
+func TestGCPProvider_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/p/secrets/jira/versions/latest:access" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		data := base64.StdEncoding.EncodeToString([]byte("gcp-token"))
+		_, _ = w.Write([]byte(`{"payload":{"data":"` + data + `"}}`))
+	}))
+	defer srv.Close()
+
+	p := secrets.NewGCPProvider(secrets.GCPConfig{
+		Endpoint:    srv.URL,
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"}),
+	})
+	got, err := p.Fetch(context.Background(), secrets.Ref{Scheme: "gcpsm", Path: "projects/p/secrets/jira"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "gcp-token" {
+		t.Errorf("Fetch() = %q, want %q", got, "gcp-token")
+	}
+}
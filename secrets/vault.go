@@ -0,0 +1,113 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultConfig holds connection settings for [VaultProvider].
+type VaultConfig struct {
+	// Address is the Vault server URL (e.g., "https://vault:8200").
+	Address string
+
+	// Token authenticates requests.
+	Token string
+
+	// Namespace is the Vault Enterprise namespace. Optional.
+	Namespace string
+}
+
+// VaultProvider reads secrets from HashiCorp Vault's HTTP API. The
+// reference path is the full API path below /v1/ (for KV v2 this
+// includes the "data/" segment). KV v1 and v2 responses are both
+// supported.
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider creates a VaultProvider. A nil client uses
+// [http.DefaultClient]. Returns an error if the address or token is
+// empty.
+func NewVaultProvider(cfg VaultConfig, client *http.Client) (*VaultProvider, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("vault address must not be empty")
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("vault token must not be empty")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &VaultProvider{cfg: cfg, client: client}, nil
+}
+
+// Fetch returns the field ref.Key of the secret at ref.Path.
+func (p *VaultProvider) Fetch(ctx context.Context, ref Ref) (string, error) {
+	url := strings.TrimRight(p.cfg.Address, "/") + "/v1/" + ref.Path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting vault: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", fmt.Errorf("decoding vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data alongside data.metadata.
+	data := payload.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, hasMeta := data["metadata"]; hasMeta {
+			data = inner
+		}
+	}
+
+	value, ok := data[ref.Key].(string)
+	if !ok {
+		return "", fmt.Errorf("key %q not found in vault secret", ref.Key)
+	}
+	return value, nil
+}
+
+// selectKey returns raw unchanged when key is empty; otherwise it
+// decodes raw as a JSON object and returns the string field key.
+func selectKey(raw, key string) (string, error) {
+	if key == "" {
+		return raw, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot select key %q", key)
+	}
+	value, ok := fields[key].(string)
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,1001 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
+)
+
+const (
+	// azureAPIVersion is the Azure DevOps REST API version requested.
+	azureAPIVersion = "7.1"
+
+	// azureAPITimeout bounds each Azure DevOps API request.
+	azureAPITimeout = 30 * time.Second
+
+	// azurePageSize is the page size for pull request lists.
+	azurePageSize = 100
+
+	// azureMaxDescription is the longest pull request description
+	// Azure DevOps accepts, in characters.
+	azureMaxDescription = 4000
+
+	// azureCommentBits is the width of the thread and comment parts of
+	// the IDs AzureDevOpsService gives comments; see azureCommentID.
+	azureCommentBits = 20
+)
+
+var _ scm.Provider = (*AzureDevOpsService)(nil)
+
+// AzureDevOpsService implements [scm.Provider] for Azure Repos. A
+// repository's owner is "organization/project" (see
+// [scm.ExtractRepoInfo]). As for Gitea, working-copy operations run the
+// GitHub service's git commands, authenticated with the personal access
+// token, and commits are built locally and pushed.
+//
+// Azure DevOps comments live in threads and are numbered per thread,
+// so the IDs this service returns for comments pack the pull request,
+// thread, and comment numbers into one int64. Labels on pull requests
+// are Azure DevOps tags; their removal is not recorded, so
+// LastLabelRemoval always reports none. Reactions are likes.
+type AzureDevOpsService struct {
+	config  *models.Config
+	host    string
+	baseURL string
+	client  *http.Client
+	secrets SecretResolver // Resolves the token when it is a secret reference; nil means plaintext
+	git     *GitHubServiceImpl
+	logger  *zap.Logger
+
+	// botIDs caches the identity ID of the token's user by
+	// organization, to recognize the bot's own comments.
+	botIDs sync.Map
+}
+
+// NewAzureDevOpsService creates the provider for config.AzureDevOps.
+// Outbound requests honor config.Network, as for GitHub.
+func NewAzureDevOpsService(config *models.Config, secrets SecretResolver, logger *zap.Logger, executor ...models.CommandExecutor) (*AzureDevOpsService, error) {
+	commandExecutor := exec.Command
+	if len(executor) > 0 {
+		commandExecutor = executor[0]
+	}
+
+	transport, err := NewHTTPTransport(config.Network)
+	if err != nil {
+		return nil, fmt.Errorf("configure outbound network: %w", err)
+	}
+
+	host := strings.ToLower(config.AzureDevOps.Host)
+	s := &AzureDevOpsService{
+		config:  config,
+		host:    host,
+		baseURL: "https://" + host,
+		client:  &http.Client{Transport: transport, Timeout: azureAPITimeout},
+		secrets: secrets,
+		logger:  logger.With(zap.String("azure_devops_host", host)),
+	}
+	s.git = &GitHubServiceImpl{
+		config:          config,
+		executor:        commandExecutor,
+		mergeRetryDelay: mergeabilityRetryDelay,
+		logger:          s.logger,
+		gitToken:        s.gitToken,
+	}
+	return s, nil
+}
+
+// token returns the current personal access token.
+func (s *AzureDevOpsService) token() string {
+	if s.secrets != nil {
+		return s.secrets.Resolve(s.config.AzureDevOps.Token)
+	}
+	return s.config.AzureDevOps.Token
+}
+
+// gitToken supplies the token to git commands for repositories on the
+// host.
+func (s *AzureDevOpsService) gitToken(remoteURL string) (string, bool) {
+	info, err := scm.ExtractRepoInfo(remoteURL)
+	if err != nil || info.Host != s.host {
+		return "", false
+	}
+	return s.token(), true
+}
+
+// escapePath escapes each segment of a slash-separated path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// repoWebURL is the credential-free URL of owner/repo, which is also
+// its git remote.
+func (s *AzureDevOpsService) repoWebURL(owner, repo string) string {
+	return s.baseURL + "/" + escapePath(owner) + "/_git/" + url.PathEscape(repo)
+}
+
+// --- Working copies ---
+
+// CloneRepository clones repoURL into directory, or resets an existing
+// clone to the repository's default branch; see
+// [GitHubServiceImpl.cloneFromRemote].
+func (s *AzureDevOpsService) CloneRepository(repoURL, directory string, sparsePaths []string) error {
+	info, err := scm.ExtractRepoInfo(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract repo info: %w", err)
+	}
+	return s.git.cloneFromRemote(s.repoWebURL(info.Owner, info.Repo), directory, sparsePaths, func() (string, error) {
+		return s.GetDefaultBranch(info.Owner, info.Repo)
+	})
+}
+
+// CloneImport clones an import repository; see
+// [GitHubServiceImpl.CloneImport].
+func (s *AzureDevOpsService) CloneImport(url, destDir, ref string) error {
+	return s.git.CloneImport(url, destDir, ref)
+}
+
+// CreateBranch creates branchName from the remote baseBranch.
+func (s *AzureDevOpsService) CreateBranch(directory, branchName, baseBranch string) error {
+	return s.git.CreateBranch(directory, branchName, baseBranch)
+}
+
+// SwitchBranch checks out an existing local or remote branch.
+func (s *AzureDevOpsService) SwitchBranch(directory, branchName string) error {
+	return s.git.SwitchBranch(directory, branchName)
+}
+
+// HasChanges reports whether the workspace has uncommitted changes or
+// unpushed commits.
+func (s *AzureDevOpsService) HasChanges(directory, baseBranch string) (bool, error) {
+	return s.git.HasChanges(directory, baseBranch)
+}
+
+// DiffStat summarizes the changes between origin/<baseBranch> and HEAD.
+func (s *AzureDevOpsService) DiffStat(directory, baseBranch string) (models.DiffStat, error) {
+	return s.git.DiffStat(directory, baseBranch)
+}
+
+// ChangedFiles lists the files changed against baseBranch.
+func (s *AzureDevOpsService) ChangedFiles(directory, baseBranch string) ([]string, error) {
+	return s.git.ChangedFiles(directory, baseBranch)
+}
+
+// DeletedFiles lists the files deleted against baseBranch.
+func (s *AzureDevOpsService) DeletedFiles(directory, baseBranch string) ([]string, error) {
+	return s.git.DeletedFiles(directory, baseBranch)
+}
+
+// Diff returns the working tree's changes against baseBranch.
+func (s *AzureDevOpsService) Diff(directory, baseBranch string) (string, error) {
+	return s.git.Diff(directory, baseBranch)
+}
+
+// BaseFile returns a file's content on baseBranch.
+func (s *AzureDevOpsService) BaseFile(directory, baseBranch, path string) ([]byte, bool, error) {
+	return s.git.BaseFile(directory, baseBranch, path)
+}
+
+// CommitChanges commits everything the AI produced as a single commit
+// and pushes it to branch; see [GitHubServiceImpl.pushCommit].
+func (s *AzureDevOpsService) CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error) {
+	noFileLimit := len(skipFileGuardrail) > 0 && skipFileGuardrail[0]
+	return s.git.pushCommit(branch, message, dir, baseBranch, coAuthor, importExcludes, noFileLimit)
+}
+
+// StripRemoteAuth removes any credentials from the origin remote URL.
+func (s *AzureDevOpsService) StripRemoteAuth(directory string) error {
+	return s.git.StripRemoteAuth(directory)
+}
+
+// RestoreRemoteAuth points the workspace's origin remote at owner/repo.
+// The URL carries no credentials.
+func (s *AzureDevOpsService) RestoreRemoteAuth(directory, owner, repo string) error {
+	if err := s.git.setOriginURL(directory, s.repoWebURL(owner, repo)); err != nil {
+		return fmt.Errorf("restore remote: %w", err)
+	}
+	return nil
+}
+
+// FetchRemote fetches all refs from the origin remote.
+func (s *AzureDevOpsService) FetchRemote(directory string) error {
+	return s.git.FetchRemote(directory)
+}
+
+// SyncWithRemote hard-resets the workspace to the remote branch,
+// preserving excluded artifact directories.
+func (s *AzureDevOpsService) SyncWithRemote(directory, branch string, importExcludes []string) error {
+	return s.git.SyncWithRemote(directory, branch, importExcludes)
+}
+
+// MergeBase merges a remote branch into the workspace; see
+// [GitHubServiceImpl.MergeBase].
+func (s *AzureDevOpsService) MergeBase(dir, branch, fetchURL string) ([]string, error) {
+	return s.git.MergeBase(dir, branch, fetchURL)
+}
+
+// CherryPickPR applies the changes of a completed pull request to the
+// workspace without committing them. Azure DevOps keeps no ref to a
+// completed PR's source branch, so the changes are those of the merge
+// commit: a squash merge (the default) is picked as is, and a merge
+// commit relative to its first parent. On conflict, returns
+// [ErrMergeConflict] and the conflicted files.
+func (s *AzureDevOpsService) CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error) {
+	if mergeCommitSHA == "" {
+		return nil, errors.New("merge commit SHA must not be empty")
+	}
+	remote := "origin"
+	if fetchURL != "" {
+		remote = fetchURL
+	}
+	fetchCmd, err := s.git.fetchCommand(dir, fetchURL, "fetch", remote, mergeCommitSHA)
+	if err != nil {
+		return nil, err
+	}
+	fetchCmd.Dir = dir
+	if out, err := fetchCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git fetch PR #%d: %w, output: %s", prNumber, err, string(out))
+	}
+
+	args := []string{"cherry-pick", "--no-commit"}
+	if parents, err := s.git.gitOutput(dir, "rev-parse", mergeCommitSHA+"^@"); err == nil && len(strings.Fields(parents)) > 1 {
+		args = append(args, "-m", "1")
+	}
+	pickCmd := s.git.executor("git", append(args, mergeCommitSHA)...)
+	pickCmd.Dir = dir
+	if out, err := pickCmd.CombinedOutput(); err != nil {
+		if conflictFiles := s.git.listConflictFiles(dir); len(conflictFiles) > 0 {
+			return conflictFiles, fmt.Errorf("%w: conflicted files: %v", ErrMergeConflict, conflictFiles)
+		}
+		return nil, fmt.Errorf("git cherry-pick PR #%d failed: %w, output: %s", prNumber, err, string(out))
+	}
+	return []string{}, nil
+}
+
+// RebaseOnRemote rebases local work onto the remote branch; see
+// [GitHubServiceImpl.RebaseOnRemote].
+func (s *AzureDevOpsService) RebaseOnRemote(dir, branch, base string) ([]string, error) {
+	return s.git.RebaseOnRemote(dir, branch, base)
+}
+
+// --- API ---
+
+// azureAPIError is an Azure DevOps API response with an unexpected
+// status.
+type azureAPIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *azureAPIError) Error() string {
+	return fmt.Sprintf("azure devops API %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// isAzureNotFound reports whether err is a 404 from the Azure DevOps
+// API.
+func isAzureNotFound(err error) bool {
+	var apiErr *azureAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// do sends an API request for path (below the host) with the JSON
+// encoding of body, if non-nil, and decodes a JSON response into out,
+// if non-nil. Statuses other than 2xx return an *azureAPIError.
+func (s *AzureDevOpsService) do(method, path string, query url.Values, body, out any) error {
+	return s.doContext(context.Background(), method, path, query, body, out)
+}
+
+// doContext is do bounded by ctx.
+func (s *AzureDevOpsService) doContext(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("api-version", azureAPIVersion)
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path+"?"+q.Encode(), reqBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+s.token())))
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("azure devops API %s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// An expired or invalid token gets the sign-in page with 203.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &azureAPIError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode azure devops API %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// gitAPIPath returns the path of the Git API resource of owner/repo
+// followed by parts.
+func gitAPIPath(owner, repo string, parts ...string) string {
+	path := "/" + escapePath(owner) + "/_apis/git/repositories/" + url.PathEscape(repo)
+	for _, p := range parts {
+		path += "/" + p
+	}
+	return path
+}
+
+// prAPIPath returns the path of pull request number of owner/repo
+// followed by parts.
+func prAPIPath(owner, repo string, number int, parts ...string) string {
+	return gitAPIPath(owner, repo, append([]string{"pullRequests", fmt.Sprint(number)}, parts...)...)
+}
+
+// azureCommentID packs a comment's pull request, thread, and
+// per-thread comment numbers into one ID.
+func azureCommentID(pr int, thread, comment int64) int64 {
+	return int64(pr)<<(2*azureCommentBits) | thread<<azureCommentBits | comment
+}
+
+// splitAzureCommentID unpacks an ID made by azureCommentID.
+func splitAzureCommentID(id int64) (pr int, thread, comment int64) {
+	const mask = 1<<azureCommentBits - 1
+	return int(id >> (2 * azureCommentBits)), id >> azureCommentBits & mask, id & mask
+}
+
+type azureIdentity struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+type azureCommit struct {
+	CommitID string `json:"commitId"`
+}
+
+type azurePR struct {
+	PullRequestID         int         `json:"pullRequestId"`
+	Title                 string      `json:"title"`
+	Status                string      `json:"status"`
+	SourceRefName         string      `json:"sourceRefName"`
+	TargetRefName         string      `json:"targetRefName"`
+	MergeStatus           string      `json:"mergeStatus"`
+	CreationDate          time.Time   `json:"creationDate"`
+	ClosedDate            time.Time   `json:"closedDate"`
+	LastMergeSourceCommit azureCommit `json:"lastMergeSourceCommit"`
+	LastMergeCommit       azureCommit `json:"lastMergeCommit"`
+}
+
+// details converts a pull request. Azure DevOps reports no time of the
+// last change, so UpdatedAt is when the PR was closed, or created.
+func (s *AzureDevOpsService) details(owner, repo string, pr azurePR) *models.PRDetails {
+	d := &models.PRDetails{
+		Number:     pr.PullRequestID,
+		Title:      pr.Title,
+		Branch:     strings.TrimPrefix(pr.SourceRefName, "refs/heads/"),
+		BaseBranch: strings.TrimPrefix(pr.TargetRefName, "refs/heads/"),
+		URL:        fmt.Sprintf("%s/pullrequest/%d", s.repoWebURL(owner, repo), pr.PullRequestID),
+		HeadSHA:    pr.LastMergeSourceCommit.CommitID,
+		CreatedAt:  pr.CreationDate,
+		UpdatedAt:  pr.CreationDate,
+	}
+	if pr.ClosedDate.After(d.UpdatedAt) {
+		d.UpdatedAt = pr.ClosedDate
+	}
+	if pr.Status == "completed" {
+		d.MergeCommitSHA = pr.LastMergeCommit.CommitID
+	}
+	return d
+}
+
+type azureThreadComment struct {
+	ID              int64         `json:"id"`
+	ParentCommentID int64         `json:"parentCommentId"`
+	Author          azureIdentity `json:"author"`
+	Content         string        `json:"content"`
+	PublishedDate   time.Time     `json:"publishedDate"`
+	CommentType     string        `json:"commentType"`
+	IsDeleted       bool          `json:"isDeleted"`
+}
+
+type azureFilePosition struct {
+	Line int `json:"line"`
+}
+
+type azureThread struct {
+	ID            int64                `json:"id"`
+	IsDeleted     bool                 `json:"isDeleted"`
+	Comments      []azureThreadComment `json:"comments"`
+	ThreadContext *struct {
+		FilePath       string             `json:"filePath"`
+		RightFileStart *azureFilePosition `json:"rightFileStart"`
+		RightFileEnd   *azureFilePosition `json:"rightFileEnd"`
+		LeftFileStart  *azureFilePosition `json:"leftFileStart"`
+		LeftFileEnd    *azureFilePosition `json:"leftFileEnd"`
+	} `json:"threadContext"`
+}
+
+// author converts a comment author. The token's own user is reported
+// with the bot username, so the bot recognizes its comments.
+func (s *AzureDevOpsService) author(owner string, id azureIdentity) models.Author {
+	a := models.Author{Name: id.DisplayName, Username: id.UniqueName}
+	if strings.Contains(id.UniqueName, "@") {
+		a.Email = id.UniqueName
+	}
+	if id.ID != "" && id.ID == s.botID(owner) {
+		a.Username = s.config.GitHub.BotUsername
+	}
+	return a
+}
+
+// botID returns the identity ID of the token's user in owner's
+// organization, or "" if it cannot be determined.
+func (s *AzureDevOpsService) botID(owner string) string {
+	org := owner
+	if i := strings.LastIndex(owner, "/"); i > 0 {
+		org = owner[:i]
+	}
+	if id, ok := s.botIDs.Load(org); ok {
+		return id.(string)
+	}
+	var data struct {
+		AuthenticatedUser struct {
+			ID string `json:"id"`
+		} `json:"authenticatedUser"`
+	}
+	if err := s.do(http.MethodGet, "/"+escapePath(org)+"/_apis/connectionData", nil, nil, &data); err != nil {
+		s.logger.Debug("Failed to look up the token's identity", zap.String("organization", org), zap.Error(err))
+		return ""
+	}
+	s.botIDs.Store(org, data.AuthenticatedUser.ID)
+	return data.AuthenticatedUser.ID
+}
+
+// threads lists a pull request's threads that are not deleted.
+func (s *AzureDevOpsService) threads(owner, repo string, number int) ([]azureThread, error) {
+	var resp struct {
+		Value []azureThread `json:"value"`
+	}
+	if err := s.do(http.MethodGet, prAPIPath(owner, repo, number, "threads"), nil, nil, &resp); err != nil {
+		return nil, fmt.Errorf("list threads of PR #%d: %w", number, err)
+	}
+	return slices.DeleteFunc(resp.Value, func(t azureThread) bool { return t.IsDeleted }), nil
+}
+
+// GetDefaultBranch returns the repository's default branch.
+func (s *AzureDevOpsService) GetDefaultBranch(owner, repo string) (string, error) {
+	var r struct {
+		DefaultBranch string `json:"defaultBranch"`
+	}
+	if err := s.do(http.MethodGet, gitAPIPath(owner, repo), nil, nil, &r); err != nil {
+		return "", fmt.Errorf("get repository %s/%s: %w", owner, repo, err)
+	}
+	if r.DefaultBranch == "" {
+		return "", fmt.Errorf("repository %s/%s has no default branch", owner, repo)
+	}
+	return strings.TrimPrefix(r.DefaultBranch, "refs/heads/"), nil
+}
+
+// SyncFork is not supported: Azure DevOps has no API to update a fork
+// from its upstream. Callers log the error and continue.
+func (s *AzureDevOpsService) SyncFork(forkOwner, repo, branch string) error {
+	return fmt.Errorf("syncing forks is not supported on Azure DevOps (%s/%s)", forkOwner, repo)
+}
+
+// BranchHasCommits reports whether branch has commits beyond base.
+func (s *AzureDevOpsService) BranchHasCommits(owner, repo, branch, base string) (bool, error) {
+	var diff struct {
+		AheadCount int `json:"aheadCount"`
+	}
+	query := url.Values{
+		"baseVersion":       {base},
+		"baseVersionType":   {"branch"},
+		"targetVersion":     {branch},
+		"targetVersionType": {"branch"},
+		"$top":              {"1"},
+	}
+	if err := s.do(http.MethodGet, gitAPIPath(owner, repo, "diffs", "commits"), query, nil, &diff); err != nil {
+		return false, fmt.Errorf("compare %s with %s: %w", branch, base, err)
+	}
+	return diff.AheadCount > 0, nil
+}
+
+// branchObjectID returns the commit branch points at, or "" if the
+// branch does not exist.
+func (s *AzureDevOpsService) branchObjectID(owner, repo, branch string) (string, error) {
+	var refs struct {
+		Value []struct {
+			Name     string `json:"name"`
+			ObjectID string `json:"objectId"`
+		} `json:"value"`
+	}
+	if err := s.do(http.MethodGet, gitAPIPath(owner, repo, "refs"), url.Values{"filter": {"heads/" + branch}}, nil, &refs); err != nil {
+		return "", fmt.Errorf("get branch %s: %w", branch, err)
+	}
+	for _, ref := range refs.Value {
+		// The filter matches prefixes.
+		if ref.Name == "refs/heads/"+branch {
+			return ref.ObjectID, nil
+		}
+	}
+	return "", nil
+}
+
+// RemoteBranchExists reports whether branch exists.
+func (s *AzureDevOpsService) RemoteBranchExists(owner, repo, branch string) (bool, error) {
+	id, err := s.branchObjectID(owner, repo, branch)
+	return id != "", err
+}
+
+// DeleteRemoteBranch deletes branch. Returns nil if it does not exist.
+func (s *AzureDevOpsService) DeleteRemoteBranch(owner, repo, branch string) error {
+	id, err := s.branchObjectID(owner, repo, branch)
+	if err != nil || id == "" {
+		return err
+	}
+	update := []map[string]string{{
+		"name":        "refs/heads/" + branch,
+		"oldObjectId": id,
+		"newObjectId": strings.Repeat("0", len(id)),
+	}}
+	if err := s.do(http.MethodPost, gitAPIPath(owner, repo, "refs"), nil, update, nil); err != nil {
+		return fmt.Errorf("delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// truncateDescription shortens a pull request description to the
+// length Azure DevOps accepts.
+func truncateDescription(body string) string {
+	const marker = "\n\n… (truncated)"
+	runes := []rune(body)
+	if len(runes) <= azureMaxDescription {
+		return body
+	}
+	return string(runes[:azureMaxDescription-len([]rune(marker))]) + marker
+}
+
+// CreatePR opens a pull request. The description is truncated to the
+// 4,000 characters Azure DevOps accepts. Azure DevOps pull requests
+// have reviewers rather than assignees, so Assignees are not applied.
+func (s *AzureDevOpsService) CreatePR(params models.PRParams) (*models.PR, error) {
+	labels := params.Labels
+	if len(labels) == 0 && s.config.GitHub.PRLabel != "" {
+		labels = []string{s.config.GitHub.PRLabel}
+	}
+	type label struct {
+		Name string `json:"name"`
+	}
+	req := struct {
+		SourceRefName string  `json:"sourceRefName"`
+		TargetRefName string  `json:"targetRefName"`
+		Title         string  `json:"title"`
+		Description   string  `json:"description"`
+		IsDraft       bool    `json:"isDraft"`
+		Labels        []label `json:"labels,omitempty"`
+	}{
+		SourceRefName: "refs/heads/" + headBranch(params.Head),
+		TargetRefName: "refs/heads/" + params.Base,
+		Title:         params.Title,
+		Description:   truncateDescription(params.Body),
+		IsDraft:       params.Draft,
+	}
+	for _, name := range labels {
+		req.Labels = append(req.Labels, label{Name: name})
+	}
+
+	var pr azurePR
+	if err := s.do(http.MethodPost, gitAPIPath(params.Owner, params.Repo, "pullrequests"), nil, req, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	if len(params.Assignees) > 0 {
+		s.logger.Debug("Azure DevOps pull requests have no assignees; not assigning",
+			zap.Int("pr", pr.PullRequestID), zap.Strings("assignees", params.Assignees))
+	}
+	return &models.PR{
+		Number: pr.PullRequestID,
+		URL:    fmt.Sprintf("%s/pullrequest/%d", s.repoWebURL(params.Owner, params.Repo), pr.PullRequestID),
+		State:  pr.Status,
+	}, nil
+}
+
+// listPRs returns the pull requests in status ("active", "abandoned",
+// or "completed"), from source branch head if non-empty.
+func (s *AzureDevOpsService) listPRs(owner, repo, status, head string) ([]azurePR, error) {
+	query := url.Values{
+		"searchCriteria.status": {status},
+		"$top":                  {fmt.Sprint(azurePageSize)},
+	}
+	if head != "" {
+		query.Set("searchCriteria.sourceRefName", "refs/heads/"+headBranch(head))
+	}
+	var all []azurePR
+	for page := 0; page < maxPaginationPages; page++ {
+		query.Set("$skip", fmt.Sprint(page*azurePageSize))
+		var resp struct {
+			Value []azurePR `json:"value"`
+		}
+		if err := s.do(http.MethodGet, gitAPIPath(owner, repo, "pullrequests"), query, nil, &resp); err != nil {
+			return nil, fmt.Errorf("list %s PRs: %w", status, err)
+		}
+		all = append(all, resp.Value...)
+		if len(resp.Value) < azurePageSize {
+			break
+		}
+	}
+	return all, nil
+}
+
+// firstPR returns the first pull request in status from head, or nil.
+func (s *AzureDevOpsService) firstPR(owner, repo, status, head string) (*models.PRDetails, error) {
+	prs, err := s.listPRs(owner, repo, status, head)
+	if err != nil || len(prs) == 0 {
+		return nil, err
+	}
+	return s.details(owner, repo, prs[0]), nil
+}
+
+// GetPRForBranch finds the active pull request from branch head.
+// Returns nil, nil when none exists.
+func (s *AzureDevOpsService) GetPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return s.firstPR(owner, repo, "active", head)
+}
+
+// GetClosedPRForBranch finds an abandoned pull request from branch
+// head. Returns nil, nil when none exists.
+func (s *AzureDevOpsService) GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return s.firstPR(owner, repo, "abandoned", head)
+}
+
+// GetMergedPRForBranch finds a completed pull request from branch head.
+// Returns nil, nil when none exists.
+func (s *AzureDevOpsService) GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return s.firstPR(owner, repo, "completed", head)
+}
+
+// CountOpenPRs returns the number of active pull requests whose source
+// branch starts with branchPrefix.
+func (s *AzureDevOpsService) CountOpenPRs(owner, repo, branchPrefix string) (int, error) {
+	prs, err := s.listPRs(owner, repo, "active", "")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, pr := range prs {
+		if strings.HasPrefix(pr.SourceRefName, "refs/heads/"+branchPrefix) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetPRMergeability reports whether a pull request merges cleanly.
+// Mergeable is nil while Azure DevOps is still computing it.
+func (s *AzureDevOpsService) GetPRMergeability(owner, repo string, number int) (*models.PRMergeState, error) {
+	var pr azurePR
+	if err := s.do(http.MethodGet, gitAPIPath(owner, repo, "pullrequests", fmt.Sprint(number)), nil, nil, &pr); err != nil {
+		return nil, fmt.Errorf("get PR #%d: %w", number, err)
+	}
+	state := &models.PRMergeState{BaseBranch: strings.TrimPrefix(pr.TargetRefName, "refs/heads/")}
+	switch pr.MergeStatus {
+	case "succeeded":
+		state.Mergeable = new(bool)
+		*state.Mergeable = true
+	case "conflicts", "failure", "rejectedByPolicy":
+		state.Mergeable = new(bool)
+	}
+	return state, nil
+}
+
+// UpdatePRBody replaces a pull request's description, truncated as in
+// CreatePR.
+func (s *AzureDevOpsService) UpdatePRBody(owner, repo string, number int, body string) error {
+	req := map[string]string{"description": truncateDescription(body)}
+	if err := s.do(http.MethodPatch, gitAPIPath(owner, repo, "pullrequests", fmt.Sprint(number)), nil, req, nil); err != nil {
+		return fmt.Errorf("update PR #%d body: %w", number, err)
+	}
+	return nil
+}
+
+// azureMergeStrategies maps merge methods to Azure DevOps completion
+// merge strategies.
+var azureMergeStrategies = map[string]string{
+	"merge":  "noFastForward",
+	"squash": "squash",
+	"rebase": "rebase",
+}
+
+// EnablePRAutoMerge sets a pull request to auto-complete with method
+// once its policies pass. Auto-complete is set on behalf of the
+// token's user.
+func (s *AzureDevOpsService) EnablePRAutoMerge(owner, repo string, number int, method string) error {
+	id := s.botID(owner)
+	if id == "" {
+		return fmt.Errorf("enable auto-complete on PR #%d: identity of the token's user unknown", number)
+	}
+	req := map[string]any{
+		"autoCompleteSetBy": map[string]string{"id": id},
+		"completionOptions": map[string]string{"mergeStrategy": azureMergeStrategies[method]},
+	}
+	if err := s.do(http.MethodPatch, gitAPIPath(owner, repo, "pullrequests", fmt.Sprint(number)), nil, req, nil); err != nil {
+		return fmt.Errorf("enable auto-complete on PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// AddPRLabel tags a pull request with label.
+func (s *AzureDevOpsService) AddPRLabel(owner, repo string, number int, label string) error {
+	req := map[string]string{"name": label}
+	if err := s.do(http.MethodPost, prAPIPath(owner, repo, number, "labels"), nil, req, nil); err != nil {
+		return fmt.Errorf("add label %q to PR #%d: %w", label, number, err)
+	}
+	return nil
+}
+
+// RemovePRLabel removes a tag from a pull request. Returns nil if the
+// pull request does not have it.
+func (s *AzureDevOpsService) RemovePRLabel(owner, repo string, number int, label string) error {
+	err := s.do(http.MethodDelete, prAPIPath(owner, repo, number, "labels", url.PathEscape(label)), nil, nil, nil)
+	if err != nil && !isAzureNotFound(err) {
+		return fmt.Errorf("remove label %q from PR #%d: %w", label, number, err)
+	}
+	return nil
+}
+
+// HasPRLabel reports whether a pull request has the tag label.
+func (s *AzureDevOpsService) HasPRLabel(owner, repo string, number int, label string) (bool, error) {
+	var resp struct {
+		Value []struct {
+			Name   string `json:"name"`
+			Active bool   `json:"active"`
+		} `json:"value"`
+	}
+	if err := s.do(http.MethodGet, prAPIPath(owner, repo, number, "labels"), nil, nil, &resp); err != nil {
+		return false, fmt.Errorf("list labels of PR #%d: %w", number, err)
+	}
+	for _, l := range resp.Value {
+		if l.Active && l.Name == label {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LastLabelRemoval returns zero time: Azure DevOps does not record
+// when a tag was removed.
+func (s *AzureDevOpsService) LastLabelRemoval(owner, repo string, number int, label string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// prComments converts the text comments of threads. With reviewOnly,
+// only comments in threads on a file are returned.
+func (s *AzureDevOpsService) prComments(owner string, number int, threads []azureThread, since time.Time, reviewOnly bool) []models.PRComment {
+	var comments []models.PRComment
+	for _, t := range threads {
+		ctx := t.ThreadContext
+		if reviewOnly && (ctx == nil || ctx.FilePath == "") {
+			continue
+		}
+		for _, c := range t.Comments {
+			if c.IsDeleted || c.CommentType != "text" || !c.PublishedDate.After(since) {
+				continue
+			}
+			pc := models.PRComment{
+				ID:        azureCommentID(number, t.ID, c.ID),
+				Author:    s.author(owner, c.Author),
+				Body:      c.Content,
+				Timestamp: c.PublishedDate,
+			}
+			if c.ParentCommentID != 0 {
+				pc.InReplyTo = azureCommentID(number, t.ID, c.ParentCommentID)
+			}
+			if ctx != nil && ctx.FilePath != "" {
+				pc.IsReviewComment = true
+				pc.FilePath = strings.TrimPrefix(ctx.FilePath, "/")
+				start, end, side := ctx.RightFileStart, ctx.RightFileEnd, "RIGHT"
+				if start == nil {
+					start, end, side = ctx.LeftFileStart, ctx.LeftFileEnd, "LEFT"
+				}
+				if start != nil {
+					pc.Line, pc.Side = start.Line, side
+					if end != nil && end.Line > start.Line {
+						pc.Line, pc.StartLine = end.Line, start.Line
+					}
+				}
+			}
+			comments = append(comments, pc)
+		}
+	}
+	return comments
+}
+
+// GetPRComments returns the text comments of a pull request's threads,
+// on files and in the conversation. If since is non-zero, only those
+// published after it are returned.
+func (s *AzureDevOpsService) GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error) {
+	threads, err := s.threads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	comments := s.prComments(owner, number, threads, since, false)
+	slices.SortStableFunc(comments, func(a, b models.PRComment) int { return a.Timestamp.Compare(b.Timestamp) })
+	return comments, nil
+}
+
+// GetPRReviewComments returns the comments in a pull request's threads
+// on files.
+func (s *AzureDevOpsService) GetPRReviewComments(owner, repo string, number int) ([]models.PRComment, error) {
+	threads, err := s.threads(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return s.prComments(owner, number, threads, time.Time{}, true), nil
+}
+
+// ReplyToComment replies in the thread of the comment commentID.
+func (s *AzureDevOpsService) ReplyToComment(owner, repo string, prNumber int, commentID int64, body string) error {
+	_, thread, parent := splitAzureCommentID(commentID)
+	req := map[string]any{"content": body, "parentCommentId": parent, "commentType": 1}
+	path := prAPIPath(owner, repo, prNumber, "threads", fmt.Sprint(thread), "comments")
+	if err := s.do(http.MethodPost, path, nil, req, nil); err != nil {
+		return fmt.Errorf("reply to comment in thread %d of PR #%d: %w", thread, prNumber, err)
+	}
+	return nil
+}
+
+// PostIssueComment starts a conversation thread on a pull request.
+func (s *AzureDevOpsService) PostIssueComment(owner, repo string, prNumber int, body string) error {
+	req := map[string]any{
+		"comments": []map[string]any{{"content": body, "parentCommentId": 0, "commentType": 1}},
+	}
+	if err := s.do(http.MethodPost, prAPIPath(owner, repo, prNumber, "threads"), nil, req, nil); err != nil {
+		return fmt.Errorf("comment on PR #%d: %w", prNumber, err)
+	}
+	return nil
+}
+
+// ListIssueComments returns the text comments of a pull request's
+// conversation threads.
+func (s *AzureDevOpsService) ListIssueComments(owner, repo string, prNumber int) ([]models.IssueComment, error) {
+	threads, err := s.threads(owner, repo, prNumber)
+	if err != nil {
+		return nil, err
+	}
+	var result []models.IssueComment
+	for _, c := range s.prComments(owner, prNumber, threads, time.Time{}, false) {
+		if !c.IsReviewComment {
+			result = append(result, models.IssueComment{ID: c.ID, Body: c.Body})
+		}
+	}
+	return result, nil
+}
+
+// UpdateIssueComment replaces the body of the comment commentID.
+func (s *AzureDevOpsService) UpdateIssueComment(owner, repo string, commentID int64, body string) error {
+	pr, thread, comment := splitAzureCommentID(commentID)
+	path := prAPIPath(owner, repo, pr, "threads", fmt.Sprint(thread), "comments", fmt.Sprint(comment))
+	if err := s.do(http.MethodPatch, path, nil, map[string]string{"content": body}, nil); err != nil {
+		return fmt.Errorf("update comment %d in thread %d of PR #%d: %w", comment, thread, pr, err)
+	}
+	return nil
+}
+
+// AddCommentReaction likes a comment; Azure DevOps has no other
+// reactions.
+func (s *AzureDevOpsService) AddCommentReaction(owner, repo string, comment models.PRComment, reaction string) error {
+	pr, thread, id := splitAzureCommentID(comment.ID)
+	path := prAPIPath(owner, repo, pr, "threads", fmt.Sprint(thread), "comments", fmt.Sprint(id), "likes")
+	if err := s.do(http.MethodPost, path, nil, nil, nil); err != nil {
+		return fmt.Errorf("like comment %d in thread %d of PR #%d: %w", id, thread, pr, err)
+	}
+	return nil
+}
+
+// ListCheckRunsForRef returns the failed statuses of commit ref, which
+// Azure Pipelines and other services post. The second return value is
+// false while any status is pending.
+func (s *AzureDevOpsService) ListCheckRunsForRef(owner, repo, ref string) ([]models.CheckRunFailure, bool, error) {
+	var resp struct {
+		Value []struct {
+			ID          int64  `json:"id"`
+			State       string `json:"state"`
+			Description string `json:"description"`
+			TargetURL   string `json:"targetUrl"`
+			Context     struct {
+				Name  string `json:"name"`
+				Genre string `json:"genre"`
+			} `json:"context"`
+		} `json:"value"`
+	}
+	path := gitAPIPath(owner, repo, "commits", url.PathEscape(ref), "statuses")
+	if err := s.do(http.MethodGet, path, url.Values{"latestOnly": {"true"}}, nil, &resp); err != nil {
+		return nil, false, fmt.Errorf("get statuses for %s: %w", ref, err)
+	}
+
+	allCompleted := true
+	failures := []models.CheckRunFailure{}
+	for _, st := range resp.Value {
+		switch st.State {
+		case "pending":
+			allCompleted = false
+		case "failed", "error":
+			name := st.Context.Name
+			if st.Context.Genre != "" {
+				name = st.Context.Genre + "/" + name
+			}
+			failures = append(failures, models.CheckRunFailure{
+				ID:         st.ID,
+				Name:       name,
+				HTMLURL:    st.TargetURL,
+				Conclusion: "failure",
+				Summary:    st.Description,
+			})
+		}
+	}
+	return failures, allCompleted, nil
+}
+
+// ListCheckRunAnnotations returns no annotations: commit statuses
+// carry none.
+func (s *AzureDevOpsService) ListCheckRunAnnotations(owner, repo string, checkRunID int64) ([]models.CheckAnnotation, error) {
+	return []models.CheckAnnotation{}, nil
+}
+
+// GetFailedJobLogs returns no logs: commit statuses do not identify
+// the pipeline run that posted them.
+func (s *AzureDevOpsService) GetFailedJobLogs(owner, repo, headSHA string, maxBytesPerStep int) (map[string][]models.FailedStep, error) {
+	return map[string][]models.FailedStep{}, nil
+}
+
+// CreateCheckRun does nothing: Azure DevOps has no check runs.
+func (s *AzureDevOpsService) CreateCheckRun(owner, repo string, run models.CheckRun) error {
+	return nil
+}
+
+// Ping checks that the token is valid in every configured
+// organization.
+func (s *AzureDevOpsService) Ping(ctx context.Context) error {
+	var errs []error
+	for _, org := range s.config.AzureDevOps.Organizations {
+		if err := s.doContext(ctx, http.MethodGet, "/"+escapePath(org)+"/_apis/connectionData", nil, nil, nil); err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", org, err))
+		}
+	}
+	return errors.Join(errs...)
+}
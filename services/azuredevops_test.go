@@ -0,0 +1,167 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// newTestAzureDevOpsService returns an AzureDevOpsService whose API is
+// served by handler.
+func newTestAzureDevOpsService(t *testing.T, handler http.HandlerFunc) *AzureDevOpsService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	config.AzureDevOps = models.AzureDevOpsConfig{Host: "dev.azure.com", Token: "pat", Organizations: []string{"org"}}
+	s, err := NewAzureDevOpsService(config, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewAzureDevOpsService: %v", err)
+	}
+	s.baseURL = server.URL
+	return s
+}
+
+func TestAzureDevOpsService_CreatePR(t *testing.T) {
+	var created struct {
+		SourceRefName string `json:"sourceRefName"`
+		TargetRefName string `json:"targetRefName"`
+		Description   string `json:"description"`
+		IsDraft       bool   `json:"isDraft"`
+		Labels        []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	s := newTestAzureDevOpsService(t, func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "" || pass != "pat" {
+			t.Errorf("basic auth = %q, %q, %v; want the token as password", user, pass, ok)
+		}
+		if r.URL.Query().Get("api-version") != azureAPIVersion {
+			t.Errorf("api-version = %q", r.URL.Query().Get("api-version"))
+		}
+		if r.Method != http.MethodPost || r.URL.EscapedPath() != "/org/My%20Project/_apis/git/repositories/repo/pullrequests" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"pullRequestId": 12, "status": "active"}`))
+	})
+
+	body := string(make([]rune, azureMaxDescription+10))
+	pr, err := s.CreatePR(models.PRParams{
+		Owner: "org/My Project", Repo: "repo", Title: "PROJ-1: Fix", Body: body,
+		Head: "ai-bot/PROJ-1", Base: "main", Draft: true, Labels: []string{"ai-pr"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	if want := s.baseURL + "/org/My%20Project/_git/repo/pullrequest/12"; pr.Number != 12 || pr.URL != want {
+		t.Errorf("PR = %+v, want #12 at %s", pr, want)
+	}
+	if created.SourceRefName != "refs/heads/ai-bot/PROJ-1" || created.TargetRefName != "refs/heads/main" || !created.IsDraft {
+		t.Errorf("created PR = %+v", created)
+	}
+	if n := len([]rune(created.Description)); n != azureMaxDescription {
+		t.Errorf("description has %d characters, want %d", n, azureMaxDescription)
+	}
+	if len(created.Labels) != 1 || created.Labels[0].Name != "ai-pr" {
+		t.Errorf("labels = %+v, want ai-pr", created.Labels)
+	}
+}
+
+func TestAzureDevOpsService_ThreadComments(t *testing.T) {
+	threads := `{"value": [
+		{"id": 5, "comments": [
+			{"id": 1, "author": {"id": "u1", "uniqueName": "alice@example.com"}, "content": "Please rename", "commentType": "text", "publishedDate": "2025-01-01T10:00:00Z"},
+			{"id": 2, "parentCommentId": 1, "author": {"id": "bot"}, "content": "Done", "commentType": "text", "publishedDate": "2025-01-01T11:00:00Z"}
+		], "threadContext": {"filePath": "/pkg/main.go", "rightFileStart": {"line": 3}, "rightFileEnd": {"line": 5}}},
+		{"id": 6, "comments": [
+			{"id": 1, "author": {"id": "u1"}, "content": "Status changed", "commentType": "system", "publishedDate": "2025-01-01T09:00:00Z"}
+		]},
+		{"id": 7, "comments": [
+			{"id": 1, "author": {"id": "bot"}, "content": "Working on it", "commentType": "text", "publishedDate": "2025-01-01T08:00:00Z"}
+		]}
+	]}`
+	var reply map[string]any
+	var replyPath string
+	s := newTestAzureDevOpsService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /org/_apis/connectionData":
+			_, _ = w.Write([]byte(`{"authenticatedUser": {"id": "bot"}}`))
+		case "GET /org/project/_apis/git/repositories/repo/pullRequests/12/threads":
+			_, _ = w.Write([]byte(threads))
+		case "POST /org/project/_apis/git/repositories/repo/pullRequests/12/threads/5/comments":
+			replyPath = r.URL.Path
+			_ = json.NewDecoder(r.Body).Decode(&reply)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	comments, err := s.GetPRComments("org/project", "repo", 12, time.Time{})
+	if err != nil {
+		t.Fatalf("GetPRComments: %v", err)
+	}
+	if len(comments) != 3 {
+		t.Fatalf("got %d comments, want 3 text comments: %+v", len(comments), comments)
+	}
+	general, review, botReply := comments[0], comments[1], comments[2]
+	if general.IsReviewComment || general.Author.Username != "ai-bot" {
+		t.Errorf("general comment = %+v, want a conversation comment by ai-bot", general)
+	}
+	if !review.IsReviewComment || review.FilePath != "pkg/main.go" || review.StartLine != 3 || review.Line != 5 || review.Side != "RIGHT" {
+		t.Errorf("review comment = %+v, want pkg/main.go lines 3-5", review)
+	}
+	if botReply.InReplyTo != review.ID || botReply.Author.Username != "ai-bot" {
+		t.Errorf("reply = %+v, want a reply by ai-bot to %d", botReply, review.ID)
+	}
+
+	if err := s.ReplyToComment("org/project", "repo", 12, review.ID, "Renamed"); err != nil {
+		t.Fatalf("ReplyToComment: %v", err)
+	}
+	if replyPath == "" || reply["content"] != "Renamed" || reply["parentCommentId"] != float64(1) {
+		t.Errorf("reply = %v, want Renamed in thread 5 under comment 1", reply)
+	}
+
+	issueComments, err := s.ListIssueComments("org/project", "repo", 12)
+	if err != nil || len(issueComments) != 1 || issueComments[0].Body != "Working on it" {
+		t.Errorf("ListIssueComments = %+v, %v; want the conversation comment", issueComments, err)
+	}
+	if pr, thread, comment := splitAzureCommentID(issueComments[0].ID); pr != 12 || thread != 7 || comment != 1 {
+		t.Errorf("comment ID unpacks to PR %d, thread %d, comment %d; want 12, 7, 1", pr, thread, comment)
+	}
+}
+
+func TestAzureDevOpsService_ListCheckRunsForRef(t *testing.T) {
+	statuses := `{"value": [
+		{"id": 1, "state": "succeeded", "context": {"name": "lint"}},
+		{"id": 2, "state": "failed", "description": "Build failed", "targetUrl": "https://ci/2", "context": {"name": "build", "genre": "pipelines"}}
+	]}`
+	s := newTestAzureDevOpsService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/project/_apis/git/repositories/repo/commits/abc/statuses" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(statuses))
+	})
+
+	failures, done, err := s.ListCheckRunsForRef("org/project", "repo", "abc")
+	if err != nil {
+		t.Fatalf("ListCheckRunsForRef: %v", err)
+	}
+	if !done || len(failures) != 1 || failures[0].Name != "pipelines/build" || failures[0].HTMLURL != "https://ci/2" {
+		t.Errorf("failures = %+v, completed = %v; want the failed build, completed", failures, done)
+	}
+
+	statuses = `{"value": [{"id": 3, "state": "pending", "context": {"name": "build"}}]}`
+	if _, done, err := s.ListCheckRunsForRef("org/project", "repo", "abc"); err != nil || done {
+		t.Errorf("completed = %v, %v with a pending status; want false", done, err)
+	}
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"jira-ai-issue-solver/models"
+)
+
+// Hosts without an API for creating commits (Gitea, Azure DevOps) get
+// commits built locally and pushed with git. The helpers here run on a
+// GitHubServiceImpl whose gitToken authenticates git for the host.
+
+// cloneFromRemote clones remoteURL into directory, or fetches and
+// resets an existing clone to the branch defaultBranch returns.
+// remoteURL must not carry credentials; it becomes origin, and git
+// commands authenticate per invocation. sparsePaths limits the working
+// tree as for CloneRepository. The clone commits as the bot identity.
+func (s *GitHubServiceImpl) cloneFromRemote(remoteURL, directory string, sparsePaths []string, defaultBranch func() (string, error)) error {
+	if err := os.MkdirAll(directory, 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	debugEnabled := s.logger.Core().Enabled(zapcore.DebugLevel)
+	fn := zap.String("function", "CloneRepository")
+
+	if _, err := os.Stat(filepath.Join(directory, ".git")); err == nil {
+		if err := s.setOriginURL(directory, remoteURL); err != nil {
+			return err
+		}
+		fetch, err := s.remoteGitCommand(remoteURL, "fetch", "origin")
+		if err != nil {
+			return err
+		}
+		cmd := newGitCommand(fetch, directory, debugEnabled, true)
+		if err := cmd.run(); err != nil {
+			return fmt.Errorf("failed to fetch repository: %w, stderr: %s", err, cmd.getStderr())
+		}
+
+		branch, err := defaultBranch()
+		if err != nil {
+			return err
+		}
+		ref := "origin/" + branch
+		cmd = newGitCommand(s.executor("git", "reset", "--hard", ref), directory, debugEnabled, true)
+		if err := cmd.run(); err != nil {
+			return fmt.Errorf("failed to reset to %s: %w, stderr: %s", ref, err, cmd.getStderr())
+		}
+		cmd = newGitCommand(s.executor("git", "clean", "-fdx"), directory, debugEnabled, true)
+		if err := cmd.run(); err != nil {
+			return fmt.Errorf("failed to clean repository: %w, stderr: %s", err, cmd.getStderr())
+		}
+		s.logger.Debug("Reset existing clone", fn, zap.String("ref", ref))
+	} else {
+		args := []string{"clone"}
+		if len(sparsePaths) > 0 {
+			args = append(args, "--sparse")
+		}
+		clone, err := s.remoteGitCommand(remoteURL, append(args, remoteURL, directory)...)
+		if err != nil {
+			return err
+		}
+		cmd := newGitCommand(clone, directory, debugEnabled, true)
+		if err := cmd.run(); err != nil {
+			return fmt.Errorf("failed to clone repository: %w, stderr: %s", err, cmd.getStderr())
+		}
+		s.logger.Debug("git clone", fn, zap.String("url", remoteURL), zap.String("stderr", cmd.getStderr()))
+	}
+
+	if len(sparsePaths) > 0 {
+		args := append([]string{"sparse-checkout", "set", "--cone", "--"}, sparsePaths...)
+		cmd := newGitCommand(s.executor("git", args...), directory, debugEnabled, true)
+		if err := cmd.run(); err != nil {
+			return fmt.Errorf("failed to set sparse checkout: %w, stderr: %s", err, cmd.getStderr())
+		}
+	}
+
+	for _, kv := range [][2]string{
+		{"user.name", s.config.GitHub.BotUsername},
+		{"user.email", s.config.GetBotEmail()},
+	} {
+		cmd := newGitCommand(s.executor("git", "config", kv[0], kv[1]), directory, debugEnabled, true)
+		if err := cmd.run(); err != nil {
+			return fmt.Errorf("failed to configure git %s: %w, stderr: %s", kv[0], err, cmd.getStderr())
+		}
+	}
+	return nil
+}
+
+// pushCommit commits everything the AI produced as a single commit and
+// pushes it to branch. As with CommitChanges, excluded paths and new
+// files at the repository root are left out, the file-count guardrail
+// applies unless noFileLimit is set, and the commit's parents are
+// those of local HEAD when they are already on the remote (so a merge
+// commit stays a merge commit) and the branch's merge-base with the
+// remote otherwise. Returns "" if there are no changes and
+// ErrNoChanges if every change was excluded.
+//
+// The push is not forced: a branch that moved on the remote since the
+// last fetch is rejected rather than overwritten.
+func (s *GitHubServiceImpl) pushCommit(branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, noFileLimit bool) (string, error) {
+	fn := zap.String("function", "CommitChanges")
+
+	hasChanges, err := s.HasChanges(dir, baseBranch)
+	if err != nil {
+		return "", fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !hasChanges {
+		s.logger.Info("No changes to commit")
+		return "", nil
+	}
+	if err := s.stageAndCommitLocal(dir, fn); err != nil {
+		return "", fmt.Errorf("failed to normalize local changes: %w", err)
+	}
+
+	parents, err := s.pushCommitParents(dir, branch, baseBranch)
+	if err != nil {
+		return "", err
+	}
+	tree, err := s.pushCommitTree(dir, parents[0], mergeExcludes(importExcludes), noFileLimit)
+	if err != nil {
+		return "", err
+	}
+	parentTree, err := s.gitOutput(dir, "rev-parse", parents[0]+"^{tree}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve parent tree: %w", err)
+	}
+	if tree == parentTree {
+		s.logger.Info("No changes from first parent; nothing to commit")
+		return "", ErrNoChanges
+	}
+
+	var coAuthorName, coAuthorEmail string
+	if coAuthor != nil {
+		coAuthorName, coAuthorEmail = coAuthor.Name, coAuthor.Email
+	}
+	args := []string{"commit-tree", tree}
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+	args = append(args, "-m", s.buildCommitMessage(message, coAuthorName, coAuthorEmail))
+	commitSHA, err := s.gitOutput(dir, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	push, err := s.originGitCommand(dir, "push", "origin", commitSHA+":refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	cmd := newGitCommand(push, dir, false, true)
+	if err := cmd.run(); err != nil {
+		return "", fmt.Errorf("failed to push commit to %s: %w, stderr: %s", branch, err, cmd.getStderr())
+	}
+
+	s.logger.Info("Pushed commit",
+		zap.String("branch", branch),
+		zap.String("commit_sha", commitSHA),
+		zap.Bool("isMergeCommit", len(parents) > 1))
+	return commitSHA, nil
+}
+
+// pushCommitParents returns the parents for the commit pushCommit
+// creates: local HEAD's parents when every one is on a remote-tracking
+// branch, and otherwise the merge-base of HEAD with origin/<branch>,
+// or with origin/<baseBranch> for a branch not yet pushed.
+func (s *GitHubServiceImpl) pushCommitParents(dir, branch, baseBranch string) ([]string, error) {
+	out, err := s.gitOutput(dir, "rev-parse", "HEAD^@")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent SHAs from local HEAD: %w", err)
+	}
+	parents := strings.Fields(out)
+	published := len(parents) > 0
+	for _, parent := range parents {
+		if remote, err := s.gitOutput(dir, "branch", "-r", "--contains", parent); err != nil || remote == "" {
+			published = false
+			break
+		}
+	}
+	if published {
+		return parents, nil
+	}
+
+	for _, ref := range []string{"origin/" + branch, "origin/" + baseBranch} {
+		if sha, err := s.getMergeBase(dir, ref); err == nil {
+			return []string{sha}, nil
+		}
+	}
+	return nil, fmt.Errorf("no parent for the commit: HEAD shares no history with origin/%s or origin/%s", branch, baseBranch)
+}
+
+// pushCommitTree writes the tree for the commit pushCommit creates:
+// parent's tree with HEAD's changes applied, except those to excluded
+// paths and, unless noFileLimit, new files at the repository root. It
+// uses a temporary index so that the workspace's index is untouched.
+func (s *GitHubServiceImpl) pushCommitTree(dir, parent string, excludes []string, noFileLimit bool) (string, error) {
+	out, err := s.gitOutput(dir, "diff-tree", "-r", "-z", "--no-renames", parent, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff-tree from parent: %w", err)
+	}
+	// -z output is ":<old mode> <new mode> <old sha> <new sha> <status>"
+	// and the path, each NUL-terminated.
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	if len(fields) < 2 {
+		fields = nil
+	}
+	if err := s.checkCommitFileCount(len(fields)/2, noFileLimit); err != nil {
+		return "", err
+	}
+
+	var entries bytes.Buffer
+	for i := 0; i+1 < len(fields); i += 2 {
+		meta, path := strings.Fields(fields[i]), fields[i+1]
+		if len(meta) < 5 {
+			continue
+		}
+		status := meta[4]
+		if isExcludedPath(path, excludes) {
+			continue
+		}
+		if !noFileLimit && status == "A" && !strings.Contains(path, "/") {
+			s.logger.Info("Skipping new root-level file", zap.String("file", path))
+			continue
+		}
+		if status == "D" {
+			fmt.Fprintf(&entries, "0 %s\t%s\x00", meta[2], path)
+		} else {
+			fmt.Fprintf(&entries, "%s %s\t%s\x00", meta[1], meta[3], path)
+		}
+	}
+
+	tmp, err := os.MkdirTemp("", "commit-index-")
+	if err != nil {
+		return "", fmt.Errorf("create temporary index directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	index := filepath.Join(tmp, "index")
+
+	if _, err := s.indexGit(dir, index, nil, "read-tree", parent); err != nil {
+		return "", err
+	}
+	if entries.Len() > 0 {
+		if _, err := s.indexGit(dir, index, &entries, "update-index", "-z", "--index-info"); err != nil {
+			return "", err
+		}
+	}
+	return s.indexGit(dir, index, nil, "write-tree")
+}
+
+// indexGit runs a git command in dir against the index file index,
+// with stdin as its input, and returns its trimmed stdout.
+func (s *GitHubServiceImpl) indexGit(dir, index string, stdin io.Reader, args ...string) (string, error) {
+	cmd := s.executor("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(commandEnv(cmd), "GIT_INDEX_FILE="+index)
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w, stderr: %s", args[0], err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
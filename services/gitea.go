@@ -0,0 +1,933 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
+)
+
+const (
+	// giteaAPITimeout bounds each Gitea API request.
+	giteaAPITimeout = 30 * time.Second
+
+	// giteaPageSize is the page size for list requests. Gitea caps
+	// it at the server's MAX_RESPONSE_ITEMS, 50 by default.
+	giteaPageSize = 50
+
+	// giteaLabelColor is the color of labels the bot creates.
+	giteaLabelColor = "#ededed"
+
+	// giteaDraftPrefix marks a pull request as work in progress; Gitea
+	// has no separate draft flag.
+	giteaDraftPrefix = "WIP: "
+)
+
+var _ scm.Provider = (*GiteaService)(nil)
+
+// GiteaService implements [scm.Provider] for a self-hosted Gitea or
+// Forgejo instance. Working-copy operations run the same git commands
+// as the GitHub service, authenticated with the instance token. Unlike
+// GitHub, where commits are created through the API so that they are
+// verified, CommitChanges builds the commit locally and pushes it.
+//
+// Gitea has no check runs, job logs, or threaded replies to review
+// comments: CI results come from commit statuses, and replies are
+// posted as conversation comments.
+type GiteaService struct {
+	config   *models.Config
+	instance models.GiteaInstance
+	host     string
+	apiURL   string
+	client   *http.Client
+	secrets  SecretResolver // Resolves instance.Token when it is a secret reference; nil means plaintext
+	git      *GitHubServiceImpl
+	logger   *zap.Logger
+}
+
+// NewGiteaService creates the provider for one Gitea instance. Outbound
+// requests honor config.Network, as for GitHub.
+func NewGiteaService(config *models.Config, instance models.GiteaInstance, secrets SecretResolver, logger *zap.Logger, executor ...models.CommandExecutor) (*GiteaService, error) {
+	commandExecutor := exec.Command
+	if len(executor) > 0 {
+		commandExecutor = executor[0]
+	}
+
+	transport, err := NewHTTPTransport(config.Network)
+	if err != nil {
+		return nil, fmt.Errorf("configure outbound network: %w", err)
+	}
+
+	host := strings.ToLower(instance.Host)
+	apiURL := strings.TrimSuffix(instance.APIURL, "/")
+	if apiURL == "" {
+		apiURL = "https://" + host + "/api/v1"
+	}
+
+	s := &GiteaService{
+		config:   config,
+		instance: instance,
+		host:     host,
+		apiURL:   apiURL,
+		client:   &http.Client{Transport: transport, Timeout: giteaAPITimeout},
+		secrets:  secrets,
+		logger:   logger.With(zap.String("gitea_host", host)),
+	}
+	// The GitHub service's git commands are host-agnostic; only their
+	// credentials differ.
+	s.git = &GitHubServiceImpl{
+		config:          config,
+		executor:        commandExecutor,
+		mergeRetryDelay: mergeabilityRetryDelay,
+		logger:          s.logger,
+		gitToken:        s.gitToken,
+	}
+	return s, nil
+}
+
+// token returns the current instance token.
+func (s *GiteaService) token() string {
+	if s.secrets != nil {
+		return s.secrets.Resolve(s.instance.Token)
+	}
+	return s.instance.Token
+}
+
+// gitToken supplies the instance token to git commands for
+// repositories on the instance.
+func (s *GiteaService) gitToken(remoteURL string) (string, bool) {
+	info, err := scm.ExtractRepoInfo(remoteURL)
+	if err != nil || info.Host != s.host {
+		return "", false
+	}
+	return s.token(), true
+}
+
+// remoteURL is the credential-free HTTPS URL of owner/repo.
+func (s *GiteaService) remoteURL(owner, repo string) string {
+	return fmt.Sprintf("https://%s/%s/%s.git", s.host, owner, repo)
+}
+
+// --- Working copies ---
+
+// CloneRepository clones repoURL into directory, or resets an existing
+// clone to the repository's default branch; see
+// [GitHubServiceImpl.cloneFromRemote].
+func (s *GiteaService) CloneRepository(repoURL, directory string, sparsePaths []string) error {
+	info, err := scm.ExtractRepoInfo(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract repo info: %w", err)
+	}
+	return s.git.cloneFromRemote(s.remoteURL(info.Owner, info.Repo), directory, sparsePaths, func() (string, error) {
+		return s.GetDefaultBranch(info.Owner, info.Repo)
+	})
+}
+
+// CloneImport clones an import repository; see
+// [GitHubServiceImpl.CloneImport].
+func (s *GiteaService) CloneImport(url, destDir, ref string) error {
+	return s.git.CloneImport(url, destDir, ref)
+}
+
+// CreateBranch creates branchName from the remote baseBranch.
+func (s *GiteaService) CreateBranch(directory, branchName, baseBranch string) error {
+	return s.git.CreateBranch(directory, branchName, baseBranch)
+}
+
+// SwitchBranch checks out an existing local or remote branch.
+func (s *GiteaService) SwitchBranch(directory, branchName string) error {
+	return s.git.SwitchBranch(directory, branchName)
+}
+
+// HasChanges reports whether the workspace has uncommitted changes or
+// unpushed commits.
+func (s *GiteaService) HasChanges(directory, baseBranch string) (bool, error) {
+	return s.git.HasChanges(directory, baseBranch)
+}
+
+// DiffStat summarizes the changes between origin/<baseBranch> and HEAD.
+func (s *GiteaService) DiffStat(directory, baseBranch string) (models.DiffStat, error) {
+	return s.git.DiffStat(directory, baseBranch)
+}
+
+// ChangedFiles lists the files changed against baseBranch.
+func (s *GiteaService) ChangedFiles(directory, baseBranch string) ([]string, error) {
+	return s.git.ChangedFiles(directory, baseBranch)
+}
+
+// DeletedFiles lists the files deleted against baseBranch.
+func (s *GiteaService) DeletedFiles(directory, baseBranch string) ([]string, error) {
+	return s.git.DeletedFiles(directory, baseBranch)
+}
+
+// Diff returns the working tree's changes against baseBranch.
+func (s *GiteaService) Diff(directory, baseBranch string) (string, error) {
+	return s.git.Diff(directory, baseBranch)
+}
+
+// BaseFile returns a file's content on baseBranch.
+func (s *GiteaService) BaseFile(directory, baseBranch, path string) ([]byte, bool, error) {
+	return s.git.BaseFile(directory, baseBranch, path)
+}
+
+// StripRemoteAuth removes any credentials from the origin remote URL.
+func (s *GiteaService) StripRemoteAuth(directory string) error {
+	return s.git.StripRemoteAuth(directory)
+}
+
+// RestoreRemoteAuth points the workspace's origin remote at owner/repo
+// on the instance. The URL carries no credentials.
+func (s *GiteaService) RestoreRemoteAuth(directory, owner, repo string) error {
+	if err := s.git.setOriginURL(directory, s.remoteURL(owner, repo)); err != nil {
+		return fmt.Errorf("restore remote: %w", err)
+	}
+	return nil
+}
+
+// FetchRemote fetches all refs from the origin remote.
+func (s *GiteaService) FetchRemote(directory string) error {
+	return s.git.FetchRemote(directory)
+}
+
+// SyncWithRemote hard-resets the workspace to the remote branch,
+// preserving excluded artifact directories.
+func (s *GiteaService) SyncWithRemote(directory, branch string, importExcludes []string) error {
+	return s.git.SyncWithRemote(directory, branch, importExcludes)
+}
+
+// MergeBase merges a remote branch into the workspace; see
+// [GitHubServiceImpl.MergeBase].
+func (s *GiteaService) MergeBase(dir, branch, fetchURL string) ([]string, error) {
+	return s.git.MergeBase(dir, branch, fetchURL)
+}
+
+// CherryPickPR applies a merged pull request's changes to the
+// workspace; see [GitHubServiceImpl.CherryPickPR].
+func (s *GiteaService) CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error) {
+	return s.git.CherryPickPR(dir, fetchURL, prNumber, mergeCommitSHA)
+}
+
+// RebaseOnRemote rebases local work onto the remote branch; see
+// [GitHubServiceImpl.RebaseOnRemote].
+func (s *GiteaService) RebaseOnRemote(dir, branch, base string) ([]string, error) {
+	return s.git.RebaseOnRemote(dir, branch, base)
+}
+
+// CommitChanges commits everything the AI produced as a single commit
+// and pushes it to branch; see [GitHubServiceImpl.pushCommit].
+func (s *GiteaService) CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error) {
+	noFileLimit := len(skipFileGuardrail) > 0 && skipFileGuardrail[0]
+	return s.git.pushCommit(branch, message, dir, baseBranch, coAuthor, importExcludes, noFileLimit)
+}
+
+// --- API ---
+
+// giteaAPIError is a Gitea API response with an unexpected status.
+type giteaAPIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *giteaAPIError) Error() string {
+	return fmt.Sprintf("gitea API %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// isGiteaNotFound reports whether err is a 404 from the Gitea API.
+func isGiteaNotFound(err error) bool {
+	var apiErr *giteaAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// do sends an API request with the JSON encoding of body, if non-nil,
+// and decodes a JSON response into out, if non-nil. Statuses other
+// than 2xx return a *giteaAPIError.
+func (s *GiteaService) do(method, path string, query url.Values, body, out any) error {
+	return s.doContext(context.Background(), method, path, query, body, out)
+}
+
+// doContext is do bounded by ctx.
+func (s *GiteaService) doContext(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	target := s.apiURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reqBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+s.token())
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gitea API %s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &giteaAPIError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode gitea API %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// giteaList fetches every page of a list endpoint.
+func giteaList[T any](s *GiteaService, path string, query url.Values) ([]T, error) {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", fmt.Sprint(giteaPageSize))
+	var all []T
+	for page := 1; page <= maxPaginationPages; page++ {
+		q.Set("page", fmt.Sprint(page))
+		var items []T
+		if err := s.do(http.MethodGet, path, q, nil, &items); err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) < giteaPageSize {
+			break
+		}
+	}
+	return all, nil
+}
+
+// repoPath returns the API path of owner/repo followed by parts.
+func repoPath(owner, repo string, parts ...string) string {
+	path := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+	for _, p := range parts {
+		path += "/" + p
+	}
+	return path
+}
+
+// escapeRef escapes a branch name for a URL path, keeping the slashes
+// that Gitea's branch routes expect.
+func escapeRef(ref string) string {
+	segments := strings.Split(ref, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+type giteaUser struct {
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
+func (u giteaUser) author() models.Author {
+	name := u.FullName
+	if name == "" {
+		name = u.Login
+	}
+	return models.Author{Name: name, Email: u.Email, Username: u.Login}
+}
+
+type giteaBranchRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+type giteaPR struct {
+	Number         int            `json:"number"`
+	Title          string         `json:"title"`
+	HTMLURL        string         `json:"html_url"`
+	State          string         `json:"state"`
+	Mergeable      bool           `json:"mergeable"`
+	Merged         bool           `json:"merged"`
+	MergeCommitSHA *string        `json:"merge_commit_sha"`
+	Head           giteaBranchRef `json:"head"`
+	Base           giteaBranchRef `json:"base"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+func (pr giteaPR) details() *models.PRDetails {
+	d := &models.PRDetails{
+		Number:     pr.Number,
+		Title:      pr.Title,
+		Branch:     pr.Head.Ref,
+		BaseBranch: pr.Base.Ref,
+		URL:        pr.HTMLURL,
+		HeadSHA:    pr.Head.SHA,
+		CreatedAt:  pr.CreatedAt,
+		UpdatedAt:  pr.UpdatedAt,
+	}
+	if pr.Merged && pr.MergeCommitSHA != nil {
+		d.MergeCommitSHA = *pr.MergeCommitSHA
+	}
+	return d
+}
+
+type giteaLabel struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type giteaComment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      giteaUser `json:"user"`
+	HTMLURL   string    `json:"html_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type giteaReview struct {
+	ID          int64     `json:"id"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	User        giteaUser `json:"user"`
+	HTMLURL     string    `json:"html_url"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+type giteaReviewComment struct {
+	ID               int64     `json:"id"`
+	Body             string    `json:"body"`
+	User             giteaUser `json:"user"`
+	Path             string    `json:"path"`
+	Position         int       `json:"position"`
+	OriginalPosition int       `json:"original_position"`
+	DiffHunk         string    `json:"diff_hunk"`
+	HTMLURL          string    `json:"html_url"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// comment converts a review comment. Gitea reports the line on the new
+// side as position and the line on the old side as original_position.
+func (c giteaReviewComment) comment() models.PRComment {
+	pc := models.PRComment{
+		ID:              c.ID,
+		Author:          c.User.author(),
+		Body:            c.Body,
+		FilePath:        c.Path,
+		DiffHunk:        c.DiffHunk,
+		URL:             c.HTMLURL,
+		Timestamp:       c.CreatedAt,
+		IsReviewComment: true,
+	}
+	if c.Position > 0 {
+		pc.Line, pc.Side = c.Position, "RIGHT"
+	} else {
+		pc.Line, pc.Side = c.OriginalPosition, "LEFT"
+	}
+	return pc
+}
+
+// headBranch strips the "owner:" prefix a head of a cross-repository
+// PR may carry.
+func headBranch(head string) string {
+	if _, branch, ok := strings.Cut(head, ":"); ok {
+		return branch
+	}
+	return head
+}
+
+// GetDefaultBranch returns the repository's default branch.
+func (s *GiteaService) GetDefaultBranch(owner, repo string) (string, error) {
+	var r struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := s.do(http.MethodGet, repoPath(owner, repo), nil, nil, &r); err != nil {
+		return "", fmt.Errorf("get repository %s/%s: %w", owner, repo, err)
+	}
+	if r.DefaultBranch == "" {
+		return "", fmt.Errorf("repository %s/%s has no default branch", owner, repo)
+	}
+	return r.DefaultBranch, nil
+}
+
+// SyncFork updates a fork's branch from its upstream repository with
+// the merge-upstream API (Gitea 1.22 and later, Forgejo 8 and later).
+func (s *GiteaService) SyncFork(forkOwner, repo, branch string) error {
+	body := map[string]string{"branch": branch}
+	if err := s.do(http.MethodPost, repoPath(forkOwner, repo, "merge-upstream"), nil, body, nil); err != nil {
+		return fmt.Errorf("sync fork %s/%s branch %s: %w", forkOwner, repo, branch, err)
+	}
+	return nil
+}
+
+// BranchHasCommits reports whether branch has commits beyond base.
+func (s *GiteaService) BranchHasCommits(owner, repo, branch, base string) (bool, error) {
+	var cmp struct {
+		TotalCommits int `json:"total_commits"`
+	}
+	path := repoPath(owner, repo, "compare", escapeRef(base)+"..."+escapeRef(branch))
+	if err := s.do(http.MethodGet, path, nil, nil, &cmp); err != nil {
+		return false, fmt.Errorf("compare %s...%s: %w", base, branch, err)
+	}
+	return cmp.TotalCommits > 0, nil
+}
+
+// RemoteBranchExists reports whether branch exists on the instance.
+func (s *GiteaService) RemoteBranchExists(owner, repo, branch string) (bool, error) {
+	err := s.do(http.MethodGet, repoPath(owner, repo, "branches", escapeRef(branch)), nil, nil, nil)
+	if isGiteaNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get branch %s: %w", branch, err)
+	}
+	return true, nil
+}
+
+// DeleteRemoteBranch deletes branch. Returns nil if it does not exist.
+func (s *GiteaService) DeleteRemoteBranch(owner, repo, branch string) error {
+	err := s.do(http.MethodDelete, repoPath(owner, repo, "branches", escapeRef(branch)), nil, nil, nil)
+	if err != nil && !isGiteaNotFound(err) {
+		return fmt.Errorf("delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// CreatePR opens a pull request. Gitea has no draft flag, so a draft
+// PR's title gets the "WIP: " prefix Gitea treats as work in progress.
+// Labels are created on the repository if missing, as GitHub does. As
+// for GitHub, a failure to assign the PR is logged, not returned.
+func (s *GiteaService) CreatePR(params models.PRParams) (*models.PR, error) {
+	labels := params.Labels
+	if len(labels) == 0 && s.config.GitHub.PRLabel != "" {
+		labels = []string{s.config.GitHub.PRLabel}
+	}
+	var labelIDs []int64
+	for _, name := range labels {
+		id, err := s.labelID(params.Owner, params.Repo, name, true)
+		if err != nil {
+			return nil, err
+		}
+		labelIDs = append(labelIDs, id)
+	}
+
+	title := params.Title
+	if params.Draft && !strings.HasPrefix(title, giteaDraftPrefix) {
+		title = giteaDraftPrefix + title
+	}
+	req := struct {
+		Title  string  `json:"title"`
+		Body   string  `json:"body"`
+		Head   string  `json:"head"`
+		Base   string  `json:"base"`
+		Labels []int64 `json:"labels,omitempty"`
+	}{title, params.Body, params.Head, params.Base, labelIDs}
+
+	var pr giteaPR
+	if err := s.do(http.MethodPost, repoPath(params.Owner, params.Repo, "pulls"), nil, req, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	if len(params.Assignees) > 0 {
+		body := map[string][]string{"assignees": params.Assignees}
+		path := repoPath(params.Owner, params.Repo, "issues", fmt.Sprint(pr.Number))
+		if err := s.do(http.MethodPatch, path, nil, body, nil); err != nil {
+			s.logger.Warn("Failed to assign PR",
+				zap.Int("pr", pr.Number),
+				zap.Strings("assignees", params.Assignees),
+				zap.Error(err))
+		}
+	}
+
+	return &models.PR{Number: pr.Number, URL: pr.HTMLURL, State: pr.State}, nil
+}
+
+// labelID returns the ID of the repository label name, creating the
+// label when create is set. Returns 0 if it does not exist and create
+// is not set.
+func (s *GiteaService) labelID(owner, repo, name string, create bool) (int64, error) {
+	labels, err := giteaList[giteaLabel](s, repoPath(owner, repo, "labels"), nil)
+	if err != nil {
+		return 0, fmt.Errorf("list labels: %w", err)
+	}
+	for _, l := range labels {
+		if l.Name == name {
+			return l.ID, nil
+		}
+	}
+	if !create {
+		return 0, nil
+	}
+	var created giteaLabel
+	body := map[string]string{"name": name, "color": giteaLabelColor}
+	if err := s.do(http.MethodPost, repoPath(owner, repo, "labels"), nil, body, &created); err != nil {
+		return 0, fmt.Errorf("create label %q: %w", name, err)
+	}
+	return created.ID, nil
+}
+
+// findPR returns the first PR in state ("open" or "closed") whose head
+// branch is head and that satisfies match, or nil.
+func (s *GiteaService) findPR(owner, repo, state, head string, match func(giteaPR) bool) (*models.PRDetails, error) {
+	prs, err := giteaList[giteaPR](s, repoPath(owner, repo, "pulls"), url.Values{"state": {state}})
+	if err != nil {
+		return nil, fmt.Errorf("list %s PRs for branch %s: %w", state, head, err)
+	}
+	branch := headBranch(head)
+	for _, pr := range prs {
+		if pr.Head.Ref == branch && match(pr) {
+			return pr.details(), nil
+		}
+	}
+	return nil, nil
+}
+
+// GetPRForBranch finds the open pull request whose head branch matches
+// the given name. Returns nil, nil when no matching PR is found.
+func (s *GiteaService) GetPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return s.findPR(owner, repo, "open", head, func(giteaPR) bool { return true })
+}
+
+// GetClosedPRForBranch finds a closed (not merged) pull request whose
+// head branch matches the given name. Returns nil, nil when none exists.
+func (s *GiteaService) GetClosedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return s.findPR(owner, repo, "closed", head, func(pr giteaPR) bool { return !pr.Merged })
+}
+
+// GetMergedPRForBranch finds a merged pull request whose head branch
+// matches the given name. Returns nil, nil when none exists.
+func (s *GiteaService) GetMergedPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
+	return s.findPR(owner, repo, "closed", head, func(pr giteaPR) bool { return pr.Merged })
+}
+
+// CountOpenPRs returns the number of open pull requests whose head
+// branch starts with branchPrefix.
+func (s *GiteaService) CountOpenPRs(owner, repo, branchPrefix string) (int, error) {
+	prs, err := giteaList[giteaPR](s, repoPath(owner, repo, "pulls"), url.Values{"state": {"open"}})
+	if err != nil {
+		return 0, fmt.Errorf("list open PRs: %w", err)
+	}
+	count := 0
+	for _, pr := range prs {
+		if strings.HasPrefix(pr.Head.Ref, branchPrefix) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetPRMergeability fetches whether a pull request can be merged.
+// Gitea computes it when the PR or its base changes, so no retry is
+// needed.
+func (s *GiteaService) GetPRMergeability(owner, repo string, number int) (*models.PRMergeState, error) {
+	var pr giteaPR
+	if err := s.do(http.MethodGet, repoPath(owner, repo, "pulls", fmt.Sprint(number)), nil, nil, &pr); err != nil {
+		return nil, fmt.Errorf("get PR #%d: %w", number, err)
+	}
+	mergeable := pr.Mergeable
+	return &models.PRMergeState{Mergeable: &mergeable, BaseBranch: pr.Base.Ref}, nil
+}
+
+// UpdatePRBody replaces a pull request's description.
+func (s *GiteaService) UpdatePRBody(owner, repo string, number int, body string) error {
+	req := map[string]string{"body": body}
+	if err := s.do(http.MethodPatch, repoPath(owner, repo, "pulls", fmt.Sprint(number)), nil, req, nil); err != nil {
+		return fmt.Errorf("update PR #%d body: %w", number, err)
+	}
+	return nil
+}
+
+// EnablePRAutoMerge schedules a pull request to be merged with method
+// once its required checks succeed.
+func (s *GiteaService) EnablePRAutoMerge(owner, repo string, number int, method string) error {
+	req := map[string]any{"Do": method, "merge_when_checks_succeed": true}
+	if err := s.do(http.MethodPost, repoPath(owner, repo, "pulls", fmt.Sprint(number), "merge"), nil, req, nil); err != nil {
+		return fmt.Errorf("enable auto-merge on PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// AddPRLabel adds a label to a pull request, creating the label on the
+// repository if it does not exist.
+func (s *GiteaService) AddPRLabel(owner, repo string, number int, label string) error {
+	id, err := s.labelID(owner, repo, label, true)
+	if err != nil {
+		return err
+	}
+	body := map[string][]int64{"labels": {id}}
+	if err := s.do(http.MethodPost, repoPath(owner, repo, "issues", fmt.Sprint(number), "labels"), nil, body, nil); err != nil {
+		return fmt.Errorf("add label %q to PR #%d: %w", label, number, err)
+	}
+	return nil
+}
+
+// RemovePRLabel removes a label from a pull request. Returns nil if the
+// label is absent.
+func (s *GiteaService) RemovePRLabel(owner, repo string, number int, label string) error {
+	id, err := s.labelID(owner, repo, label, false)
+	if err != nil || id == 0 {
+		return err
+	}
+	path := repoPath(owner, repo, "issues", fmt.Sprint(number), "labels", fmt.Sprint(id))
+	if err := s.do(http.MethodDelete, path, nil, nil, nil); err != nil && !isGiteaNotFound(err) {
+		return fmt.Errorf("remove label %q from PR #%d: %w", label, number, err)
+	}
+	return nil
+}
+
+// HasPRLabel reports whether a pull request has the given label.
+func (s *GiteaService) HasPRLabel(owner, repo string, number int, label string) (bool, error) {
+	var labels []giteaLabel
+	if err := s.do(http.MethodGet, repoPath(owner, repo, "issues", fmt.Sprint(number), "labels"), nil, nil, &labels); err != nil {
+		return false, fmt.Errorf("list labels of PR #%d: %w", number, err)
+	}
+	return slices.ContainsFunc(labels, func(l giteaLabel) bool { return l.Name == label }), nil
+}
+
+// LastLabelRemoval returns when the label was last removed from a pull
+// request, or zero time if it never was. In the timeline, a label
+// event's body is "1" when the label was added and empty when removed.
+func (s *GiteaService) LastLabelRemoval(owner, repo string, number int, label string) (time.Time, error) {
+	type event struct {
+		Type      string      `json:"type"`
+		Body      string      `json:"body"`
+		Label     *giteaLabel `json:"label"`
+		CreatedAt time.Time   `json:"created_at"`
+	}
+	events, err := giteaList[event](s, repoPath(owner, repo, "issues", fmt.Sprint(number), "timeline"), nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("list timeline of PR #%d: %w", number, err)
+	}
+	var last time.Time
+	for _, e := range events {
+		if e.Type == "label" && e.Body == "" && e.Label != nil && e.Label.Name == label && e.CreatedAt.After(last) {
+			last = e.CreatedAt
+		}
+	}
+	return last, nil
+}
+
+// GetPRComments returns a pull request's conversation comments, review
+// bodies, and review comments. If since is non-zero, only those created
+// after it are returned.
+func (s *GiteaService) GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error) {
+	query := url.Values{}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	issueComments, err := giteaList[giteaComment](s, repoPath(owner, repo, "issues", fmt.Sprint(number), "comments"), query)
+	if err != nil {
+		return nil, fmt.Errorf("list comments of PR #%d: %w", number, err)
+	}
+	comments := make([]models.PRComment, 0, len(issueComments))
+	for _, c := range issueComments {
+		if c.CreatedAt.After(since) {
+			comments = append(comments, models.PRComment{
+				ID:        c.ID,
+				Author:    c.User.author(),
+				Body:      c.Body,
+				URL:       c.HTMLURL,
+				Timestamp: c.CreatedAt,
+			})
+		}
+	}
+
+	reviews, err := s.reviews(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reviews {
+		if r.Body != "" && r.SubmittedAt.After(since) {
+			comments = append(comments, models.PRComment{
+				ID:        r.ID,
+				Author:    r.User.author(),
+				Body:      r.Body,
+				URL:       r.HTMLURL,
+				Timestamp: r.SubmittedAt,
+				Approval:  r.State == "APPROVED",
+			})
+		}
+	}
+	reviewComments, err := s.reviewComments(owner, repo, number, reviews)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range reviewComments {
+		if c.Timestamp.After(since) {
+			comments = append(comments, c)
+		}
+	}
+
+	slices.SortStableFunc(comments, func(a, b models.PRComment) int { return a.Timestamp.Compare(b.Timestamp) })
+	return comments, nil
+}
+
+// GetPRReviewComments returns the inline diff comments of every review
+// of a pull request.
+func (s *GiteaService) GetPRReviewComments(owner, repo string, number int) ([]models.PRComment, error) {
+	reviews, err := s.reviews(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return s.reviewComments(owner, repo, number, reviews)
+}
+
+// reviews lists a pull request's submitted reviews.
+func (s *GiteaService) reviews(owner, repo string, number int) ([]giteaReview, error) {
+	reviews, err := giteaList[giteaReview](s, repoPath(owner, repo, "pulls", fmt.Sprint(number), "reviews"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("list reviews of PR #%d: %w", number, err)
+	}
+	return slices.DeleteFunc(reviews, func(r giteaReview) bool { return r.State == "PENDING" }), nil
+}
+
+// reviewComments returns the inline comments of reviews.
+func (s *GiteaService) reviewComments(owner, repo string, number int, reviews []giteaReview) ([]models.PRComment, error) {
+	var comments []models.PRComment
+	for _, r := range reviews {
+		var rc []giteaReviewComment
+		path := repoPath(owner, repo, "pulls", fmt.Sprint(number), "reviews", fmt.Sprint(r.ID), "comments")
+		if err := s.do(http.MethodGet, path, nil, nil, &rc); err != nil {
+			return nil, fmt.Errorf("list comments of review %d: %w", r.ID, err)
+		}
+		for _, c := range rc {
+			comments = append(comments, c.comment())
+		}
+	}
+	return comments, nil
+}
+
+// ReplyToComment answers a comment. Gitea's API cannot reply in a
+// review thread, so the reply is a conversation comment that links to
+// the comment it answers.
+func (s *GiteaService) ReplyToComment(owner, repo string, prNumber int, commentID int64, body string) error {
+	var c giteaComment
+	if err := s.do(http.MethodGet, repoPath(owner, repo, "issues", "comments", fmt.Sprint(commentID)), nil, nil, &c); err == nil && c.HTMLURL != "" {
+		body = fmt.Sprintf("> In reply to %s\n\n%s", c.HTMLURL, body)
+	}
+	return s.PostIssueComment(owner, repo, prNumber, body)
+}
+
+// PostIssueComment adds a conversation comment to a pull request.
+func (s *GiteaService) PostIssueComment(owner, repo string, prNumber int, body string) error {
+	req := map[string]string{"body": body}
+	if err := s.do(http.MethodPost, repoPath(owner, repo, "issues", fmt.Sprint(prNumber), "comments"), nil, req, nil); err != nil {
+		return fmt.Errorf("comment on PR #%d: %w", prNumber, err)
+	}
+	return nil
+}
+
+// ListIssueComments returns a pull request's conversation comments.
+func (s *GiteaService) ListIssueComments(owner, repo string, prNumber int) ([]models.IssueComment, error) {
+	comments, err := giteaList[giteaComment](s, repoPath(owner, repo, "issues", fmt.Sprint(prNumber), "comments"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("list comments of PR #%d: %w", prNumber, err)
+	}
+	result := make([]models.IssueComment, 0, len(comments))
+	for _, c := range comments {
+		result = append(result, models.IssueComment{ID: c.ID, Body: c.Body})
+	}
+	return result, nil
+}
+
+// UpdateIssueComment replaces a conversation comment's body.
+func (s *GiteaService) UpdateIssueComment(owner, repo string, commentID int64, body string) error {
+	req := map[string]string{"body": body}
+	if err := s.do(http.MethodPatch, repoPath(owner, repo, "issues", "comments", fmt.Sprint(commentID)), nil, req, nil); err != nil {
+		return fmt.Errorf("update comment %d: %w", commentID, err)
+	}
+	return nil
+}
+
+// AddCommentReaction adds an emoji reaction to a comment. Review
+// comments and conversation comments share Gitea's comment API.
+func (s *GiteaService) AddCommentReaction(owner, repo string, comment models.PRComment, reaction string) error {
+	req := map[string]string{"content": reaction}
+	if err := s.do(http.MethodPost, repoPath(owner, repo, "issues", "comments", fmt.Sprint(comment.ID), "reactions"), nil, req, nil); err != nil {
+		return fmt.Errorf("add reaction to comment %d: %w", comment.ID, err)
+	}
+	return nil
+}
+
+// ListCheckRunsForRef returns the failed commit statuses of ref, which
+// are Gitea's CI results. The second return value is false while any
+// status is pending.
+func (s *GiteaService) ListCheckRunsForRef(owner, repo, ref string) ([]models.CheckRunFailure, bool, error) {
+	var combined struct {
+		Statuses []struct {
+			ID          int64  `json:"id"`
+			Status      string `json:"status"`
+			Context     string `json:"context"`
+			Description string `json:"description"`
+			TargetURL   string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := s.do(http.MethodGet, repoPath(owner, repo, "commits", escapeRef(ref), "status"), nil, nil, &combined); err != nil {
+		return nil, false, fmt.Errorf("get statuses for %s: %w", ref, err)
+	}
+
+	allCompleted := true
+	failures := []models.CheckRunFailure{}
+	for _, st := range combined.Statuses {
+		switch st.Status {
+		case "pending":
+			allCompleted = false
+		case "failure", "error":
+			failures = append(failures, models.CheckRunFailure{
+				ID:         st.ID,
+				Name:       st.Context,
+				HTMLURL:    st.TargetURL,
+				Conclusion: "failure",
+				Summary:    st.Description,
+			})
+		}
+	}
+	return failures, allCompleted, nil
+}
+
+// ListCheckRunAnnotations returns no annotations: commit statuses
+// carry none.
+func (s *GiteaService) ListCheckRunAnnotations(owner, repo string, checkRunID int64) ([]models.CheckAnnotation, error) {
+	return []models.CheckAnnotation{}, nil
+}
+
+// GetFailedJobLogs returns no logs: Gitea's API does not serve the
+// logs of Actions jobs.
+func (s *GiteaService) GetFailedJobLogs(owner, repo, headSHA string, maxBytesPerStep int) (map[string][]models.FailedStep, error) {
+	return map[string][]models.FailedStep{}, nil
+}
+
+// CreateCheckRun does nothing: Gitea has no check runs.
+func (s *GiteaService) CreateCheckRun(owner, repo string, run models.CheckRun) error {
+	return nil
+}
+
+// Ping checks that the instance is reachable and the token is valid.
+func (s *GiteaService) Ping(ctx context.Context) error {
+	if err := s.doContext(ctx, http.MethodGet, "/user", nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to reach %s: %w", s.host, err)
+	}
+	return nil
+}
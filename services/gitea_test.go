@@ -0,0 +1,267 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+// newTestGiteaService returns a GiteaService whose API is served by
+// handler.
+func newTestGiteaService(t *testing.T, handler http.HandlerFunc) *GiteaService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	s, err := NewGiteaService(config, models.GiteaInstance{
+		Host:   "forgejo.example.com",
+		APIURL: server.URL + "/api/v1",
+		Token:  "gitea-token",
+	}, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewGiteaService: %v", err)
+	}
+	return s
+}
+
+func TestGiteaService_CreatePR(t *testing.T) {
+	var created struct {
+		Title  string  `json:"title"`
+		Head   string  `json:"head"`
+		Labels []int64 `json:"labels"`
+	}
+	var newLabel, assignees string
+	s := newTestGiteaService(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token gitea-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/repos/org/repo/labels":
+			_, _ = w.Write([]byte(`[{"id": 1, "name": "ai-pr"}]`))
+		case "POST /api/v1/repos/org/repo/labels":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			newLabel = body["name"]
+			_, _ = w.Write([]byte(`{"id": 2, "name": "needs-review"}`))
+		case "POST /api/v1/repos/org/repo/pulls":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://forgejo.example.com/org/repo/pulls/7", "state": "open"}`))
+		case "PATCH /api/v1/repos/org/repo/issues/7":
+			var body struct {
+				Assignees []string `json:"assignees"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assignees = strings.Join(body.Assignees, ",")
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	pr, err := s.CreatePR(models.PRParams{
+		Owner: "org", Repo: "repo", Title: "PROJ-1: Fix", Head: "ai-bot/PROJ-1", Base: "main",
+		Draft: true, Labels: []string{"ai-pr", "needs-review"}, Assignees: []string{"alice"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	if pr.Number != 7 || pr.URL != "https://forgejo.example.com/org/repo/pulls/7" {
+		t.Errorf("PR = %+v", pr)
+	}
+	if created.Title != "WIP: PROJ-1: Fix" || created.Head != "ai-bot/PROJ-1" {
+		t.Errorf("created PR with title %q and head %q", created.Title, created.Head)
+	}
+	if !slices.Equal(created.Labels, []int64{1, 2}) || newLabel != "needs-review" {
+		t.Errorf("labels = %v, created label %q; want [1 2] and needs-review", created.Labels, newLabel)
+	}
+	if assignees != "alice" {
+		t.Errorf("assignees = %q, want alice", assignees)
+	}
+}
+
+func TestGiteaService_FindsPRsByBranchAndState(t *testing.T) {
+	s := newTestGiteaService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/org/repo/pulls" || r.URL.Query().Get("page") != "1" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		switch r.URL.Query().Get("state") {
+		case "open":
+			_, _ = w.Write([]byte(`[{"number": 3, "head": {"ref": "other"}}, {"number": 4, "head": {"ref": "ai-bot/PROJ-1", "sha": "abc"}, "base": {"ref": "main"}}]`))
+		case "closed":
+			_, _ = w.Write([]byte(`[{"number": 1, "merged": false, "head": {"ref": "ai-bot/PROJ-1"}}, {"number": 2, "merged": true, "merge_commit_sha": "def", "head": {"ref": "ai-bot/PROJ-1"}}]`))
+		}
+	})
+
+	open, err := s.GetPRForBranch("org", "repo", "fork:ai-bot/PROJ-1")
+	if err != nil || open == nil || open.Number != 4 || open.HeadSHA != "abc" || open.BaseBranch != "main" {
+		t.Errorf("GetPRForBranch = %+v, %v; want #4", open, err)
+	}
+	closed, err := s.GetClosedPRForBranch("org", "repo", "ai-bot/PROJ-1")
+	if err != nil || closed == nil || closed.Number != 1 {
+		t.Errorf("GetClosedPRForBranch = %+v, %v; want #1", closed, err)
+	}
+	merged, err := s.GetMergedPRForBranch("org", "repo", "ai-bot/PROJ-1")
+	if err != nil || merged == nil || merged.Number != 2 || merged.MergeCommitSHA != "def" {
+		t.Errorf("GetMergedPRForBranch = %+v, %v; want #2 merged as def", merged, err)
+	}
+	if n, err := s.CountOpenPRs("org", "repo", "ai-bot/"); err != nil || n != 1 {
+		t.Errorf("CountOpenPRs = %d, %v; want 1", n, err)
+	}
+	if pr, err := s.GetPRForBranch("org", "repo", "ai-bot/PROJ-2"); err != nil || pr != nil {
+		t.Errorf("GetPRForBranch for a branch without PR = %+v, %v; want nil", pr, err)
+	}
+}
+
+func TestGiteaService_ListCheckRunsForRef(t *testing.T) {
+	statuses := `{"statuses": [
+		{"id": 1, "status": "success", "context": "lint"},
+		{"id": 2, "status": "failure", "context": "test", "description": "2 tests failed", "target_url": "https://ci/2"}
+	]}`
+	s := newTestGiteaService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/org/repo/commits/abc/status" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(statuses))
+	})
+
+	failures, done, err := s.ListCheckRunsForRef("org", "repo", "abc")
+	if err != nil {
+		t.Fatalf("ListCheckRunsForRef: %v", err)
+	}
+	if !done || len(failures) != 1 || failures[0].Name != "test" || failures[0].Summary != "2 tests failed" {
+		t.Errorf("failures = %+v, completed = %v; want the failed test status, completed", failures, done)
+	}
+
+	statuses = `{"statuses": [{"id": 3, "status": "pending", "context": "build"}]}`
+	if _, done, err := s.ListCheckRunsForRef("org", "repo", "abc"); err != nil || done {
+		t.Errorf("completed = %v, %v with a pending status; want false", done, err)
+	}
+}
+
+func TestGiteaService_RemovePRLabel_Absent(t *testing.T) {
+	s := newTestGiteaService(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/repos/org/repo/labels":
+			_, _ = w.Write([]byte(`[{"id": 5, "name": "ai-pr"}]`))
+		case "DELETE /api/v1/repos/org/repo/issues/7/labels/5":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	if err := s.RemovePRLabel("org", "repo", 7, "ai-pr"); err != nil {
+		t.Errorf("RemovePRLabel of a label the PR lacks: %v", err)
+	}
+	if err := s.RemovePRLabel("org", "repo", 7, "unknown"); err != nil {
+		t.Errorf("RemovePRLabel of a label the repository lacks: %v", err)
+	}
+}
+
+func TestGiteaService_EnablePRAutoMerge(t *testing.T) {
+	var body map[string]any
+	s := newTestGiteaService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != "POST /api/v1/repos/org/repo/pulls/7/merge" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	})
+
+	if err := s.EnablePRAutoMerge("org", "repo", 7, "squash"); err != nil {
+		t.Fatalf("EnablePRAutoMerge: %v", err)
+	}
+	if body["Do"] != "squash" || body["merge_when_checks_succeed"] != true {
+		t.Errorf("request body = %v, want a squash merge once checks succeed", body)
+	}
+}
+
+func TestGiteaService_CommitChanges_PushesFilteredCommit(t *testing.T) {
+	upstream := t.TempDir()
+	clone := t.TempDir()
+
+	gitRun := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(clone, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitRun(upstream, "init", "--bare", "-b", "main")
+	gitRun(clone, "clone", upstream, ".")
+	gitRun(clone, "config", "user.name", "Test")
+	gitRun(clone, "config", "user.email", "test@example.com")
+	write("pkg/main.go", "package main\n")
+	gitRun(clone, "add", ".")
+	gitRun(clone, "commit", "-m", "initial")
+	gitRun(clone, "push", "origin", "main")
+
+	s := newTestGiteaService(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API request %s %s", r.Method, r.URL.Path)
+	})
+	if err := s.CreateBranch(clone, "ai-bot/PROJ-1", "main"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	write("pkg/main.go", "package main\n\nfunc main() {}\n")
+	write("pkg/new.go", "package main\n")
+	write("scratch.txt", "notes")
+	write(".ai-session/output.json", "{}")
+
+	sha, err := s.CommitChanges("org", "org", "repo", "ai-bot/PROJ-1", "PROJ-1: Fix", clone, "main",
+		&models.Author{Name: "Jane Doe", Email: "jane@example.com"}, nil)
+	if err != nil {
+		t.Fatalf("CommitChanges: %v", err)
+	}
+
+	if got := gitRun(upstream, "rev-parse", "ai-bot/PROJ-1"); got != sha {
+		t.Errorf("pushed branch is at %s, want %s", got, sha)
+	}
+	files := strings.Fields(gitRun(upstream, "ls-tree", "-r", "--name-only", sha))
+	if !slices.Equal(files, []string{"pkg/main.go", "pkg/new.go"}) {
+		t.Errorf("committed files = %v, want pkg/main.go and pkg/new.go", files)
+	}
+	if got := gitRun(upstream, "rev-parse", sha+"^"); got != gitRun(upstream, "rev-parse", "main") {
+		t.Errorf("commit parent = %s, want main", got)
+	}
+	if msg := gitRun(upstream, "log", "-1", "--format=%B", sha); !strings.Contains(msg, "Co-authored-by: Jane Doe <jane@example.com>") {
+		t.Errorf("commit message lacks the co-author trailer:\n%s", msg)
+	}
+
+	if err := s.SyncWithRemote(clone, "ai-bot/PROJ-1", nil); err != nil {
+		t.Fatalf("SyncWithRemote: %v", err)
+	}
+	// Only the skipped scratch file is left, which is not a change.
+	if sha, err := s.CommitChanges("org", "org", "repo", "ai-bot/PROJ-1", "again", clone, "main", nil, nil); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("CommitChanges after sync = %q, %v; want ErrNoChanges", sha, err)
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,7 +26,9 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"jira-ai-issue-solver/httpcache"
 	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/scm"
 )
 
 // ErrNoChanges is returned by CommitChanges when all workspace changes
@@ -75,12 +80,20 @@ const (
 	// mergeabilityRetryDelay is the pause between retry attempts, giving
 	// GitHub time to finish the background merge-test computation.
 	mergeabilityRetryDelay = 3 * time.Second
+
+	// responseCacheBytes bounds the GET responses kept, across all
+	// installations, to make repeated requests conditional.
+	responseCacheBytes = 64 << 20 // 64 MB
 )
 
+// Compile-time check that GitHubServiceImpl is an SCM provider.
+var _ scm.Provider = (*GitHubServiceImpl)(nil)
+
 // GitHubServiceImpl is the concrete implementation for GitHub operations.
 // There is no shared interface in this package — each consumer package
 // declares a narrow interface for only the methods it needs, and
-// GitHubServiceImpl satisfies them all implicitly.
+// GitHubServiceImpl satisfies them all implicitly. It is also the
+// GitHub [scm.Provider], behind which providers for other hosts plug in.
 type GitHubServiceImpl struct {
 	config               *models.Config
 	client               *http.Client
@@ -91,9 +104,16 @@ type GitHubServiceImpl struct {
 	installationClientMu sync.RWMutex                        // Protects installationClients map
 	installationIDs      map[string]int64                    // Cache: "owner/repo" -> installation ID
 	installationIDsMu    sync.RWMutex                        // Protects installationIDs map
+	defaultBranches      sync.Map                            // Cache: "owner/repo" -> default branch
+	responses            *httpcache.Cache                    // Validated GET responses of installation clients
 	executor             models.CommandExecutor
 	mergeRetryDelay      time.Duration
 	logger               *zap.Logger
+
+	// gitToken, when set, supplies the token for git network commands
+	// to remotes that are not on GitHub. The Gitea service sets it on
+	// the instance it runs git through.
+	gitToken func(remoteURL string) (token string, ok bool)
 }
 
 // gitCommand encapsulates a git command execution with optional stdout/stderr capture.
@@ -173,12 +193,18 @@ func NewGitHubService(config *models.Config, logger *zap.Logger, executor ...mod
 		commandExecutor = executor[0]
 	}
 
+	transport, err := NewHTTPTransport(config.Network)
+	if err != nil {
+		logger.Fatal("Failed to configure outbound network", zap.Error(err))
+	}
+
 	service := &GitHubServiceImpl{
 		config:              config,
-		client:              &http.Client{},
+		client:              &http.Client{Transport: transport},
 		installationAuth:    make(map[int64]*ghinstallation.Transport),
 		installationClients: make(map[int64]*github.Client),
 		installationIDs:     make(map[string]int64),
+		responses:           httpcache.New(responseCacheBytes),
 		executor:            commandExecutor,
 		mergeRetryDelay:     mergeabilityRetryDelay,
 		logger:              logger,
@@ -186,7 +212,7 @@ func NewGitHubService(config *models.Config, logger *zap.Logger, executor ...mod
 
 	// Initialize GitHub App transport
 	appTransport, err := ghinstallation.NewAppsTransportKeyFromFile(
-		http.DefaultTransport,
+		transport,
 		config.GitHub.AppID,
 		config.GitHub.PrivateKeyPath,
 	)
@@ -242,8 +268,11 @@ func (s *GitHubServiceImpl) getInstallationGitHubClient(installationID int64) (*
 		return existingClient, nil
 	}
 
-	// Create and cache the go-github client
-	client = github.NewClient(&http.Client{Transport: transport})
+	// Create and cache the go-github client. GETs are revalidated with
+	// ETags so that polling unchanged PRs is answered with 304s, which
+	// do not count against the rate limit.
+	namespace := strconv.FormatInt(installationID, 10)
+	client = github.NewClient(&http.Client{Transport: s.responses.Transport(transport, namespace)})
 	s.installationClients[installationID] = client
 
 	s.logger.Debug("Created new go-github client for installation",
@@ -252,8 +281,12 @@ func (s *GitHubServiceImpl) getInstallationGitHubClient(installationID int64) (*
 	return client, nil
 }
 
-// CloneRepository clones a repository to a local directory
-func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
+// CloneRepository clones a repository to a local directory. When
+// sparsePaths is non-empty, the working tree holds only those
+// directories and the files at the repository root (cone-mode sparse
+// checkout); the history is still cloned in full, so that later local
+// git commands never need to fetch missing objects.
+func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string, sparsePaths []string) error {
 	// Ensure the directory exists
 	if err := os.MkdirAll(directory, 0750); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
@@ -262,10 +295,29 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 	debugEnabled := s.logger.Core().Enabled(zapcore.DebugLevel)
 	fn := zap.String("function", "CloneRepository")
 
+	owner, repo, err := extractRepoInfo(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract repo info: %w", err)
+	}
+	// origin never carries credentials; network commands authenticate
+	// per invocation (see remoteGitCommand).
+	remoteURL := fmt.Sprintf("https://github.com/%s/%s.git", owner, repo)
+
 	// Check if the directory is already a git repository
 	if _, err := os.Stat(filepath.Join(directory, ".git")); err == nil {
+		// Replace any token-bearing URL and stored-credential helper
+		// left by earlier versions of the bot before fetching.
+		if err := s.setOriginURL(directory, remoteURL); err != nil {
+			return err
+		}
+		s.unsetCredentialHelper(directory, fn)
+
 		// Directory is already a git repository, fetch the latest changes
-		cmd := newGitCommand(s.executor("git", "fetch", "origin"), directory, debugEnabled, true)
+		fetch, err := s.remoteGitCommand(remoteURL, "fetch", "origin")
+		if err != nil {
+			return err
+		}
+		cmd := newGitCommand(fetch, directory, debugEnabled, true)
 
 		if err := cmd.run(); err != nil {
 			return fmt.Errorf("failed to fetch repository: %w, stderr: %s", err, cmd.getStderr())
@@ -273,20 +325,18 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 
 		s.logger.Debug("git fetch origin", fn, zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
 
-		// Reset to origin/main or origin/master to ensure we're up to date
-		cmd = newGitCommand(s.executor("git", "reset", "--hard", "origin/main"), directory, debugEnabled, true)
+		// Reset to the repository's default branch to ensure we're up to date
+		defaultBranch, err := s.cloneDefaultBranch(repoURL, directory)
+		if err != nil {
+			return err
+		}
+		ref := "origin/" + defaultBranch
+		cmd = newGitCommand(s.executor("git", "reset", "--hard", ref), directory, debugEnabled, true)
 
 		if err := cmd.run(); err != nil {
-			// Try with master branch
-			cmd = newGitCommand(s.executor("git", "reset", "--hard", "origin/master"), directory, debugEnabled, true)
-
-			if err := cmd.run(); err != nil {
-				return fmt.Errorf("failed to reset to origin/main or origin/master: %w, stderr: %s", err, cmd.getStderr())
-			}
-			s.logger.Debug("git reset --hard", fn, zap.String("ref", "origin/master"), zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
-		} else {
-			s.logger.Debug("git reset --hard", fn, zap.String("ref", "origin/main"), zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
+			return fmt.Errorf("failed to reset to %s: %w, stderr: %s", ref, err, cmd.getStderr())
 		}
+		s.logger.Debug("git reset --hard", fn, zap.String("ref", ref), zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
 
 		// Clean the repository
 		cmd = newGitCommand(s.executor("git", "clean", "-fdx"), directory, debugEnabled, true)
@@ -299,7 +349,15 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 
 	} else {
 		// Clone the repository
-		cmd := newGitCommand(s.executor("git", "clone", repoURL, directory), directory, debugEnabled, true)
+		args := []string{"clone"}
+		if len(sparsePaths) > 0 {
+			args = append(args, "--sparse")
+		}
+		clone, err := s.remoteGitCommand(repoURL, append(args, repoURL, directory)...)
+		if err != nil {
+			return err
+		}
+		cmd := newGitCommand(clone, directory, debugEnabled, true)
 
 		if err := cmd.run(); err != nil {
 			return fmt.Errorf("failed to clone repository: %w, stderr: %s", err, cmd.getStderr())
@@ -308,6 +366,15 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 		s.logger.Debug("git clone", fn, zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
 	}
 
+	if len(sparsePaths) > 0 {
+		args := append([]string{"sparse-checkout", "set", "--cone", "--"}, sparsePaths...)
+		cmd := newGitCommand(s.executor("git", args...), directory, debugEnabled, true)
+		if err := cmd.run(); err != nil {
+			return fmt.Errorf("failed to set sparse checkout: %w, stderr: %s", err, cmd.getStderr())
+		}
+		s.logger.Debug("git sparse-checkout set", fn, zap.Strings("paths", sparsePaths), zap.String("stderr", cmd.getStderr()))
+	}
+
 	// Configure git user for GitHub App
 	cmd := newGitCommand(s.executor("git", "config", "user.name", s.config.GitHub.BotUsername), directory, debugEnabled, true)
 
@@ -366,40 +433,99 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 		s.logger.Info("SSH signing not configured for repository")
 	}
 
-	// Configure git to use the GitHub token for authentication
-	// This prevents credential prompts during push operations
-	cmd = newGitCommand(s.executor("git", "config", "credential.helper", "store"), directory, debugEnabled, true)
+	return s.setOriginURL(directory, remoteURL)
+}
 
-	if err := cmd.run(); err != nil {
-		return fmt.Errorf("failed to configure git credential helper: %w, stderr: %s", err, cmd.getStderr())
-	}
+// gitTokenEnv is the environment variable through which
+// gitCredentialHelper receives the installation token.
+const gitTokenEnv = "AI_BOT_GIT_TOKEN" // #nosec G101 -- variable name, not a credential
 
-	s.logger.Debug("git config credential.helper", fn, zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
+// gitCredentialHelper answers git's credential "get" requests with the
+// token in gitTokenEnv. It is configured with -c on each network
+// command, so the token exists only in that git process's environment.
+const gitCredentialHelper = `!f() { test "$1" = get || exit 0; echo username=x-access-token; echo "password=$` + gitTokenEnv + `"; }; f`
 
-	// Extract owner and repo from the URL first (needed for getting auth token)
-	owner, repo, err := extractRepoInfo(repoURL)
+// remoteGitCommand returns a git command that talks to the repository
+// at remoteURL. For GitHub URLs, a fresh installation token is handed to
+// git through gitCredentialHelper and the command's environment, so it
+// is never written to .git/config, the remote URL, or a credential
+// store. The empty credential.helper entry disables any helpers from
+// the user's git config, which would otherwise be asked to store the
+// token. URLs for which gitToken supplies a token are authenticated the
+// same way. Other URLs (e.g., local paths) get a plain command.
+func (s *GitHubServiceImpl) remoteGitCommand(remoteURL string, args ...string) (*exec.Cmd, error) {
+	netEnv := gitNetworkEnv(s.config.Network)
+	if s.gitToken != nil {
+		if token, ok := s.gitToken(remoteURL); ok {
+			return s.tokenGitCommand(token, netEnv, args), nil
+		}
+	}
+	owner, repo, err := extractRepoInfo(remoteURL)
 	if err != nil {
-		return fmt.Errorf("failed to extract repo info: %w", err)
+		cmd := s.executor("git", args...)
+		if len(netEnv) > 0 {
+			cmd.Env = append(commandEnv(cmd), netEnv...)
+		}
+		return cmd, nil
 	}
 
-	// Set up the credential URL with token
 	token, err := s.getAuthTokenForRepo(owner, repo)
 	if err != nil {
-		return fmt.Errorf("failed to get auth token: %w", err)
+		return nil, fmt.Errorf("get auth token for %s/%s: %w", owner, repo, err)
+	}
+	return s.tokenGitCommand(token, netEnv, args), nil
+}
+
+// tokenGitCommand returns a git command that authenticates with token
+// through gitCredentialHelper.
+func (s *GitHubServiceImpl) tokenGitCommand(token string, netEnv, args []string) *exec.Cmd {
+	authArgs := append([]string{
+		"-c", "credential.helper=",
+		"-c", "credential.helper=" + gitCredentialHelper,
+	}, args...)
+	cmd := s.executor("git", authArgs...)
+	cmd.Env = append(append(commandEnv(cmd), netEnv...), gitTokenEnv+"="+token)
+	return cmd
+}
+
+// commandEnv returns cmd's environment, which is the process's own
+// when cmd.Env is unset.
+func commandEnv(cmd *exec.Cmd) []string {
+	if cmd.Env == nil {
+		return os.Environ()
 	}
+	return cmd.Env
+}
 
-	// Set the remote URL with embedded token
-	authURL := fmt.Sprintf("https://%s@github.com/%s/%s.git", token, owner, repo)
-	// Don't log stdout/stderr for this command since it contains the token
-	cmd = newGitCommand(s.executor("git", "remote", "set-url", "origin", authURL), directory, false, false)
+// originGitCommand is remoteGitCommand for the workspace's origin
+// remote.
+func (s *GitHubServiceImpl) originGitCommand(directory string, args ...string) (*exec.Cmd, error) {
+	originURL, err := s.gitOutput(directory, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, fmt.Errorf("get remote URL: %w", err)
+	}
+	return s.remoteGitCommand(originURL, args...)
+}
 
+// setOriginURL points the workspace's origin remote at remoteURL.
+func (s *GitHubServiceImpl) setOriginURL(directory, remoteURL string) error {
+	cmd := newGitCommand(s.executor("git", "remote", "set-url", "origin", remoteURL), directory, false, true)
 	if err := cmd.run(); err != nil {
-		return fmt.Errorf("failed to set remote URL with token: %w", err)
+		return fmt.Errorf("failed to set remote URL: %w, stderr: %s", err, cmd.getStderr())
 	}
-
 	return nil
 }
 
+// unsetCredentialHelper removes the "store" credential helper that
+// earlier versions configured in each clone. Missing entries are not
+// an error.
+func (s *GitHubServiceImpl) unsetCredentialHelper(directory string, fn zapcore.Field) {
+	cmd := newGitCommand(s.executor("git", "config", "--unset-all", "credential.helper"), directory, false, true)
+	if err := cmd.run(); err != nil {
+		s.logger.Debug("git config --unset-all credential.helper", fn, zap.Error(err), zap.String("stderr", cmd.getStderr()))
+	}
+}
+
 // getAuthTokenForRepo gets the GitHub App installation token for a repository
 func (s *GitHubServiceImpl) getAuthTokenForRepo(owner, repo string) (string, error) {
 	if s.appTransport == nil {
@@ -452,7 +578,11 @@ func (s *GitHubServiceImpl) CreateBranch(directory, branchName, baseBranch strin
 	}
 
 	// Fetch the latest changes from origin
-	cmd := newGitCommand(s.executor("git", "fetch", "origin"), directory, debugEnabled, true)
+	fetch, err := s.originGitCommand(directory, "fetch", "origin")
+	if err != nil {
+		return err
+	}
+	cmd := newGitCommand(fetch, directory, debugEnabled, true)
 
 	if err := cmd.run(); err != nil {
 		return fmt.Errorf("failed to fetch origin: %w, stderr: %s", err, cmd.getStderr())
@@ -460,22 +590,18 @@ func (s *GitHubServiceImpl) CreateBranch(directory, branchName, baseBranch strin
 
 	s.logger.Debug("git fetch origin", fn, zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
 
-	// Checkout the target branch
-	cmd = newGitCommand(s.executor("git", "checkout", baseBranch), directory, debugEnabled, true)
+	// Check out the target branch at the latest commit on origin. -B
+	// creates the local branch when the clone has only the remote one
+	// (e.g., a release branch) and resets it when it is stale; the
+	// explicit start point and "--" keep git from guessing between
+	// remotes or treating the name as a path.
+	cmd = newGitCommand(s.executor("git", "checkout", "-B", baseBranch, "origin/"+baseBranch, "--"), directory, debugEnabled, true)
 
 	if err := cmd.run(); err != nil {
 		return fmt.Errorf("failed to checkout target branch %s: %w, stderr: %s", baseBranch, err, cmd.getStderr())
 	}
 
-	s.logger.Debug("git checkout", fn, zap.String("branch", baseBranch), zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
-
-	// Reset to the latest commit on the target branch to ensure we're up to date
-	cmd = newGitCommand(s.executor("git", "reset", "--hard", "origin/"+baseBranch), directory, debugEnabled, true)
-
-	if err := cmd.run(); err != nil {
-		return fmt.Errorf("failed to reset to latest commit on target branch %s: %w, stderr: %s", baseBranch, err, cmd.getStderr())
-	}
-	s.logger.Debug("git reset --hard", fn, zap.String("ref", "origin/"+baseBranch), zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
+	s.logger.Debug("git checkout", fn, zap.String("branch", baseBranch), zap.String("ref", "origin/"+baseBranch), zap.String("stdout", cmd.getStdout()), zap.String("stderr", cmd.getStderr()))
 
 	// Check if the branch already exists locally
 	cmd = newGitCommand(s.executor("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branchName), directory, debugEnabled, true)
@@ -510,7 +636,8 @@ func (s *GitHubServiceImpl) CreateBranch(directory, branchName, baseBranch strin
 // if there are no changes; otherwise returns the commit SHA.
 //
 // If coAuthor is non-nil, a Co-authored-by trailer is appended to the
-// commit message using the author's Name and Email.
+// commit message using the author's Name and Email. If github.sign_off
+// is enabled, a Signed-off-by trailer for the bot identity follows.
 func (s *GitHubServiceImpl) CommitChanges(upstreamOwner, owner, repo, branch, message, dir, baseBranch string, coAuthor *models.Author, importExcludes []string, skipFileGuardrail ...bool) (string, error) {
 	// Extract co-author name/email (empty strings when nil).
 	var coAuthorName, coAuthorEmail string
@@ -658,11 +785,7 @@ func (s *GitHubServiceImpl) createVerifiedCommitFromLocalHEAD(upstreamOwner, own
 		zap.String("baseTree", baseTreeSHA),
 		zap.Int("entries", len(treeEntries)))
 
-	// Build commit message with optional co-author
-	commitMessage := message
-	if coAuthorName != "" && coAuthorEmail != "" {
-		commitMessage = fmt.Sprintf("%s\n\nCo-authored-by: %s <%s>", message, coAuthorName, coAuthorEmail)
-	}
+	commitMessage := s.buildCommitMessage(message, coAuthorName, coAuthorEmail)
 
 	// Create the commit with the tree and all parents from local HEAD
 	commitSHA, err := s.createCommitWithParents(owner, repo, commitMessage, treeSHA, parentSHAs, token)
@@ -750,6 +873,24 @@ func (s *GitHubServiceImpl) getLocalParentSHAs(directory string) ([]string, erro
 	return parents, nil
 }
 
+// buildCommitMessage appends the optional Co-authored-by and
+// Signed-off-by trailers to message as a single trailer block. The
+// sign-off uses the bot identity, which matches the author GitHub
+// records for API-created commits, so DCO checks pass.
+func (s *GitHubServiceImpl) buildCommitMessage(message, coAuthorName, coAuthorEmail string) string {
+	var trailers []string
+	if coAuthorName != "" && coAuthorEmail != "" {
+		trailers = append(trailers, fmt.Sprintf("Co-authored-by: %s <%s>", coAuthorName, coAuthorEmail))
+	}
+	if s.config.GitHub.SignOff {
+		trailers = append(trailers, fmt.Sprintf("Signed-off-by: %s <%s>", s.config.GetBotName(), s.config.GetBotEmail()))
+	}
+	if len(trailers) == 0 {
+		return message
+	}
+	return message + "\n\n" + strings.Join(trailers, "\n")
+}
+
 // createCommitWithParents creates a commit via GitHub API with specific parents (supports merge commits)
 func (s *GitHubServiceImpl) createCommitWithParents(owner, repo, message, treeSHA string, parentSHAs []string, token string) (string, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/commits", owner, repo)
@@ -859,23 +1000,8 @@ func (s *GitHubServiceImpl) createBlobsForFilesChangedFromParent(owner, repo, di
 	var treeEntries []models.GitHubTreeEntry
 	lines := strings.Split(strings.TrimSpace(cmd.getStdout()), "\n")
 
-	// Check file count limit to prevent oversized commits.
-	// Merge jobs skip this guardrail — merging upstream into a
-	// feature branch legitimately touches hundreds of files.
-	if noFileLimit {
-		s.logger.Info("File count guardrail skipped for merge commit",
-			zap.Int("file_count", len(lines)))
-	} else {
-		limit := maxMergeCommitFiles
-		if s.config.Guardrails.MaxCommitFiles > 0 && s.config.Guardrails.MaxCommitFiles < limit {
-			limit = s.config.Guardrails.MaxCommitFiles
-		}
-		if len(lines) > limit {
-			if limit == maxMergeCommitFiles {
-				return nil, fmt.Errorf("commit has %d changed files, exceeds hard safety cap of %d", len(lines), limit)
-			}
-			return nil, fmt.Errorf("commit has %d changed files, exceeds guardrails.max_commit_files limit of %d — the AI likely modified more files than intended; increase the limit in config if this is expected", len(lines), limit)
-		}
+	if err := s.checkCommitFileCount(len(lines), noFileLimit); err != nil {
+		return nil, err
 	}
 
 	for _, line := range lines {
@@ -998,6 +1124,29 @@ func (s *GitHubServiceImpl) createBlobsForFilesChangedFromParent(owner, repo, di
 	return treeEntries, nil
 }
 
+// checkCommitFileCount returns an error if a commit changing count
+// files would be oversized. Merge jobs skip this guardrail (noFileLimit)
+// — merging upstream into a feature branch legitimately touches
+// hundreds of files.
+func (s *GitHubServiceImpl) checkCommitFileCount(count int, noFileLimit bool) error {
+	if noFileLimit {
+		s.logger.Info("File count guardrail skipped for merge commit",
+			zap.Int("file_count", count))
+		return nil
+	}
+	limit := maxMergeCommitFiles
+	if s.config.Guardrails.MaxCommitFiles > 0 && s.config.Guardrails.MaxCommitFiles < limit {
+		limit = s.config.Guardrails.MaxCommitFiles
+	}
+	if count > limit {
+		if limit == maxMergeCommitFiles {
+			return fmt.Errorf("commit has %d changed files, exceeds hard safety cap of %d", count, limit)
+		}
+		return fmt.Errorf("commit has %d changed files, exceeds guardrails.max_commit_files limit of %d — the AI likely modified more files than intended; increase the limit in config if this is expected", count, limit)
+	}
+	return nil
+}
+
 // errSkipEntry signals that a tree entry should be skipped (e.g.,
 // the path is a directory or a bot artifact).
 var errSkipEntry = errors.New("skip entry")
@@ -1597,7 +1746,11 @@ func (s *GitHubServiceImpl) SwitchBranch(directory, branchName string) error {
 	}
 
 	// Fetch the latest changes from origin
-	cmd := newGitCommand(s.executor("git", "fetch", "origin"), directory, debugEnabled, true)
+	fetch, err := s.originGitCommand(directory, "fetch", "origin")
+	if err != nil {
+		return err
+	}
+	cmd := newGitCommand(fetch, directory, debugEnabled, true)
 
 	if err := cmd.run(); err != nil {
 		return fmt.Errorf("failed to fetch origin: %w, stderr: %s", err, cmd.getStderr())
@@ -1639,6 +1792,164 @@ func (s *GitHubServiceImpl) HasChanges(directory, baseBranch string) (bool, erro
 	return hasUnpushedCommits, nil
 }
 
+// DiffStat summarizes the changes between origin/<baseBranch> and HEAD
+// using git diff --shortstat.
+func (s *GitHubServiceImpl) DiffStat(directory, baseBranch string) (models.DiffStat, error) {
+	cmd := newGitCommand(s.executor("git", "diff", "--shortstat", fmt.Sprintf("origin/%s...HEAD", baseBranch)), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return models.DiffStat{}, fmt.Errorf("failed to compute diff stat: %w, stderr: %s", err, cmd.getStderr())
+	}
+	return parseShortStat(cmd.getStdout()), nil
+}
+
+// ChangedFiles lists the files that differ between origin/<baseBranch>
+// and the working tree, including untracked files and excluding
+// deleted ones. Bot artifacts and nested repositories (e.g., cloned
+// imports), which are never committed, are left out. Paths are
+// relative to the repository root and sorted.
+func (s *GitHubServiceImpl) ChangedFiles(directory, baseBranch string) ([]string, error) {
+	cmd := newGitCommand(s.executor("git", "diff", "--name-only", "--diff-filter=d", "origin/"+baseBranch), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w, stderr: %s", err, cmd.getStderr())
+	}
+	tracked := cmd.getStdout()
+
+	cmd = newGitCommand(s.executor("git", "ls-files", "--others", "--exclude-standard"), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w, stderr: %s", err, cmd.getStderr())
+	}
+
+	var files []string
+	for _, out := range []string{tracked, cmd.getStdout()} {
+		for line := range strings.SplitSeq(out, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasSuffix(line, "/") || isExcludedPath(line, builtinExcludes) {
+				continue
+			}
+			files = append(files, line)
+		}
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// DeletedFiles lists the files that exist on origin/<baseBranch> but
+// not in the working tree. Paths are relative to the repository root
+// and sorted.
+func (s *GitHubServiceImpl) DeletedFiles(directory, baseBranch string) ([]string, error) {
+	cmd := newGitCommand(s.executor("git", "diff", "--name-only", "--diff-filter=D", "origin/"+baseBranch), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return nil, fmt.Errorf("failed to list deleted files: %w, stderr: %s", err, cmd.getStderr())
+	}
+	var files []string
+	for line := range strings.SplitSeq(cmd.getStdout(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// Diff returns the changes between origin/<baseBranch> and the
+// working tree as a unified diff, including untracked files, which
+// appear as new files. Bot artifacts are left out.
+func (s *GitHubServiceImpl) Diff(directory, baseBranch string) (string, error) {
+	args := []string{"diff", "origin/" + baseBranch, "--", "."}
+	for _, dir := range builtinExcludes {
+		args = append(args, ":(exclude)"+dir)
+	}
+	cmd := newGitCommand(s.executor("git", args...), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return "", fmt.Errorf("failed to compute diff: %w, stderr: %s", err, cmd.getStderr())
+	}
+	var b strings.Builder
+	b.WriteString(cmd.getStdout())
+
+	cmd = newGitCommand(s.executor("git", "ls-files", "--others", "--exclude-standard"), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return "", fmt.Errorf("failed to list untracked files: %w, stderr: %s", err, cmd.getStderr())
+	}
+	for line := range strings.SplitSeq(cmd.getStdout(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, "/") || isExcludedPath(line, builtinExcludes) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(directory, line)) // #nosec G304 -- path listed by git in the workspace
+		if err != nil {
+			return "", fmt.Errorf("failed to read untracked file %s: %w", line, err)
+		}
+		writeNewFileDiff(&b, line, data)
+	}
+	return b.String(), nil
+}
+
+// writeNewFileDiff writes the diff git would show for adding the file
+// at path with the given content. Binary content is not shown.
+func writeNewFileDiff(b *strings.Builder, path string, data []byte) {
+	fmt.Fprintf(b, "diff --git a/%s b/%s\nnew file mode 100644\n", path, path)
+	if len(data) == 0 {
+		return
+	}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		fmt.Fprintf(b, "Binary files /dev/null and b/%s differ\n", path)
+		return
+	}
+	content := string(data)
+	noNewline := !strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	fmt.Fprintf(b, "--- /dev/null\n+++ b/%s\n@@ -0,0 +1,%d @@\n", path, len(lines))
+	for _, l := range lines {
+		b.WriteString("+" + l + "\n")
+	}
+	if noNewline {
+		b.WriteString("\\ No newline at end of file\n")
+	}
+}
+
+// BaseFile returns the content of the file at path, relative to the
+// repository root, as of origin/<baseBranch>. The second result is
+// false when the file does not exist on the base branch.
+func (s *GitHubServiceImpl) BaseFile(directory, baseBranch, path string) ([]byte, bool, error) {
+	ref := "origin/" + baseBranch
+	cmd := newGitCommand(s.executor("git", "ls-tree", "--name-only", ref, "--", path), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return nil, false, fmt.Errorf("failed to look up %s on %s: %w, stderr: %s", path, ref, err, cmd.getStderr())
+	}
+	if strings.TrimSpace(cmd.getStdout()) == "" {
+		return nil, false, nil
+	}
+
+	cmd = newGitCommand(s.executor("git", "show", ref+":"+path), directory, true, true)
+	if err := cmd.run(); err != nil {
+		return nil, false, fmt.Errorf("failed to read %s on %s: %w, stderr: %s", path, ref, err, cmd.getStderr())
+	}
+	return []byte(cmd.getStdout()), true, nil
+}
+
+// shortStatPart matches one "N <kind>" entry of git's --shortstat
+// summary line, e.g. "3 files changed" or "10 insertions(+)".
+var shortStatPart = regexp.MustCompile(`(\d+) (file|insertion|deletion)`)
+
+// parseShortStat parses git's --shortstat summary line, e.g.
+// " 3 files changed, 10 insertions(+), 2 deletions(-)". Counts that
+// git omits (no insertions, no deletions) are zero.
+func parseShortStat(out string) models.DiffStat {
+	var stat models.DiffStat
+	for _, m := range shortStatPart.FindAllStringSubmatch(out, -1) {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "file":
+			stat.FilesChanged = n
+		case "insertion":
+			stat.Insertions = n
+		case "deletion":
+			stat.Deletions = n
+		}
+	}
+	return stat
+}
+
 // hasWorkingTreeChanges checks if there are uncommitted changes in the working tree
 func (s *GitHubServiceImpl) hasWorkingTreeChanges(directory string, fn zapcore.Field) (bool, error) {
 	// Use git status --porcelain to get machine-readable status
@@ -1741,9 +2052,10 @@ func (s *GitHubServiceImpl) hasUnpushedCommits(directory, baseBranch string, fn
 	return logCmd.hasStdout(), nil
 }
 
-// StripRemoteAuth removes authentication credentials from the
-// workspace's origin remote URL. After this call, push operations
-// will be rejected by the remote.
+// StripRemoteAuth removes any credentials embedded in the workspace's
+// origin remote URL. The bot no longer writes tokens into remote URLs;
+// this guards workspaces cloned by earlier versions before they are
+// handed to an AI session.
 func (s *GitHubServiceImpl) StripRemoteAuth(directory string) error {
 	cmd := newGitCommand(s.executor("git", "remote", "get-url", "origin"), directory, false, true)
 	if err := cmd.run(); err != nil {
@@ -1768,23 +2080,17 @@ func (s *GitHubServiceImpl) StripRemoteAuth(directory string) error {
 	return nil
 }
 
-// RestoreRemoteAuth restores authentication credentials on the
-// workspace's origin remote URL using a fresh installation token.
+// RestoreRemoteAuth points the workspace's origin remote at
+// owner/repo on GitHub. The URL carries no credentials: fetches
+// authenticate per command with a fresh installation token (see
+// remoteGitCommand).
 func (s *GitHubServiceImpl) RestoreRemoteAuth(directory, owner, repo string) error {
-	token, err := s.getAuthTokenForRepo(owner, repo)
-	if err != nil {
-		return fmt.Errorf("get auth token: %w", err)
-	}
-
-	authURL := fmt.Sprintf("https://%s@github.com/%s/%s.git", token, owner, repo)
-	cmd := newGitCommand(
-		s.executor("git", "remote", "set-url", "origin", authURL),
-		directory, false, false)
-	if err := cmd.run(); err != nil {
-		return fmt.Errorf("restore remote auth: %w, stderr: %s", err, cmd.getStderr())
+	remoteURL := fmt.Sprintf("https://github.com/%s/%s.git", owner, repo)
+	if err := s.setOriginURL(directory, remoteURL); err != nil {
+		return fmt.Errorf("restore remote: %w", err)
 	}
 
-	s.logger.Debug("Restored remote auth", zap.String("directory", directory))
+	s.logger.Debug("Restored remote", zap.String("directory", directory), zap.String("url", remoteURL))
 	return nil
 }
 
@@ -1799,7 +2105,11 @@ func (s *GitHubServiceImpl) FetchRemote(directory string) error {
 	debugEnabled := s.logger.Core().Enabled(zapcore.DebugLevel)
 	fn := zap.String("function", "FetchRemote")
 
-	fetchCmd := newGitCommand(s.executor("git", "fetch", "origin"), directory, debugEnabled, true)
+	fetch, err := s.originGitCommand(directory, "fetch", "origin")
+	if err != nil {
+		return err
+	}
+	fetchCmd := newGitCommand(fetch, directory, debugEnabled, true)
 	if err := fetchCmd.run(); err != nil {
 		return fmt.Errorf("failed to fetch from origin: %w, stderr: %s", err, fetchCmd.getStderr())
 	}
@@ -2071,15 +2381,16 @@ func (s *GitHubServiceImpl) CloneImport(url, destDir, ref string) error {
 }
 
 // fetchPRReviewCommentsPage fetches a single page of PR review comments
-func (s *GitHubServiceImpl) fetchPRReviewCommentsPage(owner, repo string, prNumber, page, perPage int) ([]models.GitHubPRComment, error) {
+// and returns the number of the next page (zero on the last page).
+func (s *GitHubServiceImpl) fetchPRReviewCommentsPage(owner, repo string, prNumber, page, perPage int) ([]models.GitHubPRComment, int, error) {
 	installationID, err := s.getInstallationIDForRepo(owner, repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get installation ID: %w", err)
+		return nil, 0, fmt.Errorf("failed to get installation ID: %w", err)
 	}
 
 	ghClient, err := s.getInstallationGitHubClient(installationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get installation client: %w", err)
+		return nil, 0, fmt.Errorf("failed to get installation client: %w", err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), githubAPITimeout)
@@ -2092,9 +2403,9 @@ func (s *GitHubServiceImpl) fetchPRReviewCommentsPage(owner, repo string, prNumb
 		},
 	}
 
-	ghComments, _, err := ghClient.PullRequests.ListComments(ctx, owner, repo, prNumber, opts)
+	ghComments, resp, err := ghClient.PullRequests.ListComments(ctx, owner, repo, prNumber, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get PR review comments: %w", err)
+		return nil, 0, fmt.Errorf("failed to get PR review comments: %w", err)
 	}
 
 	// Convert go-github comments to our model
@@ -2122,10 +2433,14 @@ func (s *GitHubServiceImpl) fetchPRReviewCommentsPage(owner, repo string, prNumb
 			HTMLURL:   c.GetHTMLURL(),
 			CreatedAt: c.GetCreatedAt().Time,
 			UpdatedAt: c.GetUpdatedAt().Time,
+
+			OriginalLine:      c.GetOriginalLine(),
+			OriginalStartLine: c.GetOriginalStartLine(),
+			DiffHunk:          c.GetDiffHunk(),
 		})
 	}
 
-	return comments, nil
+	return comments, resp.NextPage, nil
 }
 
 // listPRReviewComments lists line-based review comments on a PR (from pulls endpoint)
@@ -2136,20 +2451,21 @@ func (s *GitHubServiceImpl) listPRReviewComments(owner, repo string, prNumber in
 	perPage := 100
 
 	for {
-		comments, err := s.fetchPRReviewCommentsPage(owner, repo, prNumber, page, perPage)
+		comments, nextPage, err := s.fetchPRReviewCommentsPage(owner, repo, prNumber, page, perPage)
 		if err != nil {
 			return nil, err
 		}
 
 		allComments = append(allComments, comments...)
 
-		// If we got fewer than perPage results, we've reached the last page
-		// Also break on empty response to avoid infinite loop edge case
-		if len(comments) == 0 || len(comments) < perPage {
+		// Follow the Link header rather than inferring the last page
+		// from a short page; GitHub may return fewer than perPage items
+		// on pages that are not the last.
+		if nextPage == 0 {
 			break
 		}
 
-		page++
+		page = nextPage
 		// Safety limit: prevent infinite loop if GitHub API misbehaves
 		if page > maxPaginationPages {
 			s.logger.Warn("Hit pagination safety limit for PR review comments",
@@ -2251,10 +2567,11 @@ func (s *GitHubServiceImpl) listPRConversationComments(owner, repo string, prNum
 }
 
 // listPRReviewBodies fetches PR reviews and returns non-empty review
-// bodies as GitHubPRComment entries. Review bodies are the top-level
-// text submitted with a review (e.g., CodeRabbit's summary with
-// inline nitpicks). These are distinct from line-level review comments.
-func (s *GitHubServiceImpl) listPRReviewBodies(owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+// bodies as PR comments. Review bodies are the top-level text
+// submitted with a review (e.g., CodeRabbit's summary with inline
+// nitpicks). These are distinct from line-level review comments.
+// Bodies of approving reviews are marked as approvals.
+func (s *GitHubServiceImpl) listPRReviewBodies(owner, repo string, prNumber int) ([]models.PRComment, error) {
 	installationID, err := s.getInstallationIDForRepo(owner, repo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get installation ID: %w", err)
@@ -2288,7 +2605,7 @@ func (s *GitHubServiceImpl) listPRReviewBodies(owner, repo string, prNumber int)
 		}
 	}
 
-	var result []models.GitHubPRComment
+	var result []models.PRComment
 	for _, r := range allReviews {
 		body := strings.TrimSpace(r.GetBody())
 		if body == "" {
@@ -2299,12 +2616,16 @@ func (s *GitHubServiceImpl) listPRReviewBodies(owner, repo string, prNumber int)
 		if user != nil {
 			login = user.GetLogin()
 		}
-		result = append(result, models.GitHubPRComment{
-			ID:        r.GetID(),
-			User:      models.GitHubUser{Login: login},
+		result = append(result, models.PRComment{
+			ID: r.GetID(),
+			Author: models.Author{
+				Name:     login,
+				Username: login,
+			},
 			Body:      body,
-			HTMLURL:   r.GetHTMLURL(),
-			CreatedAt: r.GetSubmittedAt().Time,
+			URL:       r.GetHTMLURL(),
+			Timestamp: r.GetSubmittedAt().Time,
+			Approval:  r.GetState() == "APPROVED",
 		})
 	}
 
@@ -2317,7 +2638,7 @@ func (s *GitHubServiceImpl) listPRReviewBodies(owner, repo string, prNumber int)
 // that timestamp are returned.
 func (s *GitHubServiceImpl) GetPRComments(owner, repo string, number int, since time.Time) ([]models.PRComment, error) {
 	// Get line-based review comments from pulls endpoint
-	reviewComments, err := s.listPRReviewComments(owner, repo, number)
+	reviewComments, err := s.GetPRReviewComments(owner, repo, number)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get review comments: %w", err)
 	}
@@ -2349,23 +2670,10 @@ func (s *GitHubServiceImpl) GetPRComments(owner, repo string, number int, since
 	total := len(reviewComments) + len(conversationComments) + len(reviewBodies)
 	result := make([]models.PRComment, 0, total)
 	for _, c := range reviewComments {
-		if !since.IsZero() && !c.CreatedAt.After(since) {
+		if !since.IsZero() && !c.Timestamp.After(since) {
 			continue
 		}
-		result = append(result, models.PRComment{
-			ID: c.ID,
-			Author: models.Author{
-				Name:     c.User.Login,
-				Username: c.User.Login,
-			},
-			Body:            c.Body,
-			FilePath:        c.Path,
-			Line:            c.Line,
-			URL:             c.HTMLURL,
-			Timestamp:       c.CreatedAt,
-			InReplyTo:       c.InReplyToID,
-			IsReviewComment: true,
-		})
+		result = append(result, c)
 	}
 	for _, c := range conversationComments {
 		if !since.IsZero() && !c.CreatedAt.After(since) {
@@ -2383,22 +2691,55 @@ func (s *GitHubServiceImpl) GetPRComments(owner, repo string, number int, since
 		})
 	}
 	for _, c := range reviewBodies {
-		if !since.IsZero() && !c.CreatedAt.After(since) {
+		if !since.IsZero() && !c.Timestamp.After(since) {
 			continue
 		}
-		result = append(result, models.PRComment{
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+// GetPRReviewComments returns the inline diff comments on a pull
+// request (the pulls/{n}/comments API, which is separate from the
+// conversation and review APIs), across all pages. Each comment keeps
+// its thread parent (InReplyTo) and its position: the line range and
+// diff side it is anchored to and the diff hunk it was made on. For an
+// outdated comment, whose code changed after it was written, Line is
+// the original line and Outdated is set.
+func (s *GitHubServiceImpl) GetPRReviewComments(owner, repo string, number int) ([]models.PRComment, error) {
+	raw, err := s.listPRReviewComments(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]models.PRComment, 0, len(raw))
+	for _, c := range raw {
+		pc := models.PRComment{
 			ID: c.ID,
 			Author: models.Author{
 				Name:     c.User.Login,
 				Username: c.User.Login,
 			},
-			Body:      c.Body,
-			URL:       c.HTMLURL,
-			Timestamp: c.CreatedAt,
-		})
+			Body:            c.Body,
+			FilePath:        c.Path,
+			Line:            c.Line,
+			StartLine:       c.StartLine,
+			Side:            c.Side,
+			DiffHunk:        c.DiffHunk,
+			URL:             c.HTMLURL,
+			Timestamp:       c.CreatedAt,
+			InReplyTo:       c.InReplyToID,
+			IsReviewComment: true,
+		}
+		if c.Line == 0 && c.OriginalLine > 0 {
+			pc.Line = c.OriginalLine
+			pc.StartLine = c.OriginalStartLine
+			pc.Outdated = true
+		}
+		comments = append(comments, pc)
 	}
-
-	return result, nil
+	return comments, nil
 }
 
 // extractRepoInfo extracts owner and repo from a repository URL.
@@ -2428,6 +2769,66 @@ func extractRepoInfo(repoURL string) (owner, repo string, err error) {
 	return "", "", fmt.Errorf("unsupported repository URL format: %s", repoURL)
 }
 
+// GetDefaultBranch returns the repository's default branch as reported
+// by the GitHub API. Results are cached for the life of the service;
+// a renamed default branch is picked up after a restart.
+func (s *GitHubServiceImpl) GetDefaultBranch(owner, repo string) (string, error) {
+	key := owner + "/" + repo
+	if cached, ok := s.defaultBranches.Load(key); ok {
+		return cached.(string), nil
+	}
+
+	installationID, err := s.getInstallationIDForRepo(owner, repo)
+	if err != nil {
+		return "", fmt.Errorf("get installation ID: %w", err)
+	}
+
+	client, err := s.getInstallationGitHubClient(installationID)
+	if err != nil {
+		return "", fmt.Errorf("get GitHub client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubAPITimeout)
+	defer cancel()
+
+	repository, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return "", fmt.Errorf("get repository %s: %w", key, err)
+	}
+
+	branch := repository.GetDefaultBranch()
+	if branch == "" {
+		return "", fmt.Errorf("repository %s has no default branch", key)
+	}
+
+	s.defaultBranches.Store(key, branch)
+	return branch, nil
+}
+
+// cloneDefaultBranch returns the default branch to reset an existing
+// clone to. It asks the GitHub API first and falls back to the clone's
+// origin/HEAD, which git records at clone time, when the URL is not a
+// GitHub URL or the API call fails.
+func (s *GitHubServiceImpl) cloneDefaultBranch(repoURL, directory string) (string, error) {
+	owner, repo, err := extractRepoInfo(repoURL)
+	if err == nil {
+		branch, apiErr := s.GetDefaultBranch(owner, repo)
+		if apiErr == nil {
+			return branch, nil
+		}
+		err = apiErr
+	}
+
+	s.logger.Debug("Could not detect default branch via API, using origin/HEAD",
+		zap.String("repoURL", repoURL), zap.Error(err))
+
+	head, gitErr := s.gitOutput(directory, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if gitErr != nil {
+		return "", fmt.Errorf("detect default branch of %s: %w (origin/HEAD: %v)", repoURL, err, gitErr)
+	}
+	return strings.TrimPrefix(head, "origin/"), nil
+}
+
 // getInstallationIDForRepo discovers the GitHub App installation ID for a specific repository.
 // This is used in GitHub App authentication mode to obtain installation-specific tokens.
 //
@@ -2514,6 +2915,40 @@ func (s *GitHubServiceImpl) getInstallationIDForRepo(owner, repo string) (int64,
 	return installation.ID, nil
 }
 
+// Ping verifies that the GitHub API is reachable and the App
+// credentials are valid by fetching the authenticated App. Used by
+// readiness probes; ctx bounds the request.
+func (s *GitHubServiceImpl) Ping(ctx context.Context) error {
+	if s.appTransport == nil {
+		return fmt.Errorf("GitHub App not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, githubAPITimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/app", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Transport: s.appTransport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach GitHub: %w", err)
+	}
+	defer func() {
+		if localErr := resp.Body.Close(); localErr != nil {
+			s.logger.Error("Failed to close response body", zap.Error(localErr), zap.String("operation", "Ping"))
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub App check failed (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
 // GetPRForBranch finds the open pull request whose head branch matches
 // the given name. Returns nil, nil when no matching PR is found.
 func (s *GitHubServiceImpl) GetPRForBranch(owner, repo, head string) (*models.PRDetails, error) {
@@ -2556,6 +2991,7 @@ func (s *GitHubServiceImpl) GetPRForBranch(owner, repo, head string) (*models.PR
 				URL:        pr.GetHTMLURL(),
 				HeadSHA:    pr.GetHead().GetSHA(),
 				CreatedAt:  pr.GetCreatedAt().Time,
+				UpdatedAt:  pr.GetUpdatedAt().Time,
 			}, nil
 		}
 	}
@@ -2603,6 +3039,7 @@ func (s *GitHubServiceImpl) GetClosedPRForBranch(owner, repo, head string) (*mod
 				URL:        pr.GetHTMLURL(),
 				HeadSHA:    pr.GetHead().GetSHA(),
 				CreatedAt:  pr.GetCreatedAt().Time,
+				UpdatedAt:  pr.GetUpdatedAt().Time,
 			}, nil
 		}
 	}
@@ -2642,13 +3079,15 @@ func (s *GitHubServiceImpl) GetMergedPRForBranch(owner, repo, head string) (*mod
 	for _, pr := range prs {
 		if pr.GetHead().GetRef() == refToMatch && pr.MergedAt != nil {
 			return &models.PRDetails{
-				Number:     pr.GetNumber(),
-				Title:      pr.GetTitle(),
-				Branch:     pr.GetHead().GetRef(),
-				BaseBranch: pr.GetBase().GetRef(),
-				URL:        pr.GetHTMLURL(),
-				HeadSHA:    pr.GetHead().GetSHA(),
-				CreatedAt:  pr.GetCreatedAt().Time,
+				Number:         pr.GetNumber(),
+				Title:          pr.GetTitle(),
+				Branch:         pr.GetHead().GetRef(),
+				BaseBranch:     pr.GetBase().GetRef(),
+				URL:            pr.GetHTMLURL(),
+				HeadSHA:        pr.GetHead().GetSHA(),
+				CreatedAt:      pr.GetCreatedAt().Time,
+				UpdatedAt:      pr.GetUpdatedAt().Time,
+				MergeCommitSHA: pr.GetMergeCommitSHA(),
 			}, nil
 		}
 	}
@@ -2656,6 +3095,49 @@ func (s *GitHubServiceImpl) GetMergedPRForBranch(owner, repo, head string) (*mod
 	return nil, nil
 }
 
+// CountOpenPRs returns the number of open pull requests in the
+// repository whose head branch starts with branchPrefix. The bot's
+// branches are named "{bot-username}/{ticket-key}", so passing
+// "{bot-username}/" counts the bot's open PRs, including cross-repo
+// PRs from forks.
+func (s *GitHubServiceImpl) CountOpenPRs(owner, repo, branchPrefix string) (int, error) {
+	installationID, err := s.getInstallationIDForRepo(owner, repo)
+	if err != nil {
+		return 0, fmt.Errorf("get installation ID: %w", err)
+	}
+
+	client, err := s.getInstallationGitHubClient(installationID)
+	if err != nil {
+		return 0, fmt.Errorf("get GitHub client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubAPITimeout)
+	defer cancel()
+
+	opts := &github.PullRequestListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	count := 0
+	for {
+		prs, resp, err := client.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return 0, fmt.Errorf("list open PRs: %w", err)
+		}
+		for _, pr := range prs {
+			if strings.HasPrefix(pr.GetHead().GetRef(), branchPrefix) {
+				count++
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return count, nil
+}
+
 // BranchHasCommits reports whether the branch has commits beyond the
 // base branch. Used by crash recovery to detect completed AI work.
 func (s *GitHubServiceImpl) BranchHasCommits(owner, repo, branch, base string) (bool, error) {
@@ -2742,8 +3224,8 @@ func (s *GitHubServiceImpl) DeleteRemoteBranch(owner, repo, branch string) error
 }
 
 // SyncFork syncs a fork's branch with its upstream parent using the
-// GitHub merge-upstream API. This ensures the fork's default branch
-// is current before creating feature branches, preventing PRs that
+// GitHub merge-upstream API. This ensures the fork's copy of the base
+// branch is current before creating feature branches, preventing PRs that
 // include hundreds of unrelated commits.
 func (s *GitHubServiceImpl) SyncFork(forkOwner, repo, branch string) error {
 	token, err := s.getAuthTokenForRepo(forkOwner, repo)
@@ -2818,7 +3300,10 @@ func (s *GitHubServiceImpl) MergeBase(dir, branch, fetchURL string) ([]string, e
 		remote = fetchURL
 		mergeRef = "FETCH_HEAD"
 	}
-	fetchCmd := s.executor("git", "fetch", remote, branch)
+	fetchCmd, err := s.fetchCommand(dir, fetchURL, "fetch", remote, branch)
+	if err != nil {
+		return nil, err
+	}
 	fetchCmd.Dir = dir
 	if _, err := fetchCmd.CombinedOutput(); err != nil {
 		return nil, fmt.Errorf("git fetch %s %s: %w", remote, branch, err)
@@ -2877,6 +3362,129 @@ func parseUntrackedBlockers(output string) []string {
 	return paths
 }
 
+// backportHeadRef is the local ref CherryPickPR fetches the merged
+// PR's head into.
+const backportHeadRef = "refs/backport/pr-head"
+
+// CherryPickPR applies the changes of a merged pull request to the
+// current branch without committing them. The PR's head and merge
+// commit are fetched from fetchURL, or from origin when fetchURL is
+// empty.
+//
+// The applied change is the PR's head relative to the point where it
+// last diverged from the branch it was merged into, so merge, squash,
+// and rebase merges are all backported as one change. On conflict,
+// returns [ErrMergeConflict] and the conflicted file paths (conflict
+// markers are left in the working tree).
+func (s *GitHubServiceImpl) CherryPickPR(dir, fetchURL string, prNumber int, mergeCommitSHA string) ([]string, error) {
+	if mergeCommitSHA == "" {
+		return nil, errors.New("merge commit SHA must not be empty")
+	}
+	remote := "origin"
+	if fetchURL != "" {
+		remote = fetchURL
+	}
+
+	fetchCmd, err := s.fetchCommand(dir, fetchURL, "fetch", remote,
+		fmt.Sprintf("+refs/pull/%d/head:%s", prNumber, backportHeadRef), mergeCommitSHA)
+	if err != nil {
+		return nil, err
+	}
+	fetchCmd.Dir = dir
+	if out, err := fetchCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git fetch PR #%d: %w, output: %s", prNumber, err, string(out))
+	}
+
+	// The merge commit's first parent is the target branch before the
+	// merge; the PR's changes are its head relative to the last commit
+	// the two share.
+	base, err := s.gitOutput(dir, "merge-base", backportHeadRef, mergeCommitSHA+"^1")
+	if err != nil {
+		return nil, err
+	}
+	change, err := s.gitOutput(dir, "commit-tree", backportHeadRef+"^{tree}", "-p", base,
+		"-m", fmt.Sprintf("Changes of PR #%d", prNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	pickCmd := s.executor("git", "cherry-pick", "--no-commit", change)
+	pickCmd.Dir = dir
+	if out, err := pickCmd.CombinedOutput(); err != nil {
+		conflictFiles := s.listConflictFiles(dir)
+		if len(conflictFiles) > 0 {
+			return conflictFiles, fmt.Errorf("%w: conflicted files: %v", ErrMergeConflict, conflictFiles)
+		}
+		return nil, fmt.Errorf("git cherry-pick PR #%d failed: %w, output: %s", prNumber, err, string(out))
+	}
+	return []string{}, nil
+}
+
+// RebaseOnRemote moves the local work on branch onto the remote
+// branch's current head: the working tree changes are committed
+// locally, origin is fetched, and the local commits made since base
+// (the remote head the work started from) are replayed onto
+// origin/<branch>. Replaying only the commits since base keeps
+// commits that were removed from the remote branch (e.g., by a force
+// push) from coming back. On conflict, the rebase is aborted, leaving
+// the work as it was, and [ErrMergeConflict] is returned with the
+// conflicted file paths.
+func (s *GitHubServiceImpl) RebaseOnRemote(dir, branch, base string) ([]string, error) {
+	fn := zap.String("function", "RebaseOnRemote")
+	if base == "" {
+		return nil, errors.New("rebase base must not be empty")
+	}
+
+	if err := s.stageAndCommitLocal(dir, fn); err != nil {
+		return nil, fmt.Errorf("failed to commit local changes: %w", err)
+	}
+	if err := s.FetchRemote(dir); err != nil {
+		return nil, err
+	}
+
+	ref := "origin/" + branch
+	rebaseCmd := s.executor("git", "rebase", "--onto", ref, base)
+	rebaseCmd.Dir = dir
+	if out, err := rebaseCmd.CombinedOutput(); err != nil {
+		conflictFiles := s.listConflictFiles(dir)
+		abortCmd := s.executor("git", "rebase", "--abort")
+		abortCmd.Dir = dir
+		if abortOut, abortErr := abortCmd.CombinedOutput(); abortErr != nil {
+			s.logger.Warn("Failed to abort rebase", fn,
+				zap.Error(abortErr), zap.String("output", string(abortOut)))
+		}
+		if len(conflictFiles) > 0 {
+			return conflictFiles, fmt.Errorf("%w: conflicted files: %v", ErrMergeConflict, conflictFiles)
+		}
+		return nil, fmt.Errorf("git rebase onto %s failed: %w, output: %s", ref, err, string(out))
+	}
+	s.logger.Debug("Rebased local work onto remote branch", fn, zap.String("ref", ref), zap.String("base", base))
+	return []string{}, nil
+}
+
+// gitOutput runs a git command in dir and returns its trimmed stdout.
+func (s *GitHubServiceImpl) gitOutput(dir string, args ...string) (string, error) {
+	cmd := s.executor("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w, stderr: %s", args[0], err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fetchCommand returns a git command for a fetch from fetchURL, or from
+// origin when fetchURL is empty, authenticated as remoteGitCommand
+// describes.
+func (s *GitHubServiceImpl) fetchCommand(dir, fetchURL string, args ...string) (*exec.Cmd, error) {
+	if fetchURL == "" {
+		return s.originGitCommand(dir, args...)
+	}
+	return s.remoteGitCommand(fetchURL, args...)
+}
+
 // listConflictFiles returns file paths with unresolved merge conflicts
 // by parsing git status porcelain output for unmerged entries.
 func (s *GitHubServiceImpl) listConflictFiles(dir string) []string {
@@ -3009,6 +3617,68 @@ func (s *GitHubServiceImpl) RemovePRLabel(owner, repo string, number int, label
 	return nil
 }
 
+// UpdatePRBody replaces the description of a pull request.
+func (s *GitHubServiceImpl) UpdatePRBody(owner, repo string, number int, body string) error {
+	installationID, err := s.getInstallationIDForRepo(owner, repo)
+	if err != nil {
+		return fmt.Errorf("get installation ID: %w", err)
+	}
+
+	client, err := s.getInstallationGitHubClient(installationID)
+	if err != nil {
+		return fmt.Errorf("get GitHub client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubAPITimeout)
+	defer cancel()
+
+	_, _, err = client.PullRequests.Edit(ctx, owner, repo, number, &github.PullRequest{Body: &body})
+	if err != nil {
+		return fmt.Errorf("update body of PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// EnablePRAutoMerge enables auto-merge on a pull request through the
+// GraphQL API, which has the only endpoint for it. GitHub refuses when
+// the repository does not allow auto-merge or when the PR could be
+// merged right away.
+func (s *GitHubServiceImpl) EnablePRAutoMerge(owner, repo string, number int, method string) error {
+	client, err := s.ghClientForRepo(owner, repo)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubAPITimeout)
+	defer cancel()
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return fmt.Errorf("get PR #%d: %w", number, err)
+	}
+	req, err := client.NewRequest(http.MethodPost, "graphql", map[string]any{
+		"query": `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`,
+		"variables": map[string]string{"id": pr.GetNodeID(), "method": strings.ToUpper(method)},
+	})
+	if err != nil {
+		return fmt.Errorf("build auto-merge request: %w", err)
+	}
+	var resp struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := client.Do(ctx, req, &resp); err != nil {
+		return fmt.Errorf("enable auto-merge on PR #%d: %w", number, err)
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("enable auto-merge on PR #%d: %s", number, resp.Errors[0].Message)
+	}
+	return nil
+}
+
 // HasPRLabel reports whether a pull request has the given label.
 func (s *GitHubServiceImpl) HasPRLabel(owner, repo string, number int, label string) (bool, error) {
 	installationID, err := s.getInstallationIDForRepo(owner, repo)
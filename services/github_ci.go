@@ -125,6 +125,37 @@ func (s *GitHubServiceImpl) ListCheckRunAnnotations(
 	return annotations, nil
 }
 
+// CreateCheckRun publishes a completed check run on the commit
+// run.HeadSHA. The GitHub App needs the checks:write permission.
+func (s *GitHubServiceImpl) CreateCheckRun(owner, repo string, run models.CheckRun) error {
+	client, err := s.ghClientForRepo(owner, repo)
+	if err != nil {
+		return err
+	}
+
+	opts := github.CreateCheckRunOptions{
+		Name:        run.Name,
+		HeadSHA:     run.HeadSHA,
+		Status:      github.Ptr("completed"),
+		Conclusion:  github.Ptr(run.Conclusion),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output: &github.CheckRunOutput{
+			Title:   github.Ptr(run.Title),
+			Summary: github.Ptr(run.Summary),
+		},
+	}
+	if run.Text != "" {
+		opts.Output.Text = github.Ptr(run.Text)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), githubAPITimeout)
+	defer cancel()
+	if _, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, opts); err != nil {
+		return fmt.Errorf("create check run on %s: %w", run.HeadSHA, err)
+	}
+	return nil
+}
+
 // GetFailedJobLogs returns truncated log output from failed workflow job
 // steps, keyed by job name. Each job name maps to its failed steps with
 // log output truncated to maxBytesPerStep from the tail.
@@ -238,7 +269,7 @@ func (s *GitHubServiceImpl) fetchJobLog(
 		return "", fmt.Errorf("create log request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("fetch job log: %w", err)
 	}
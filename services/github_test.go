@@ -3,6 +3,7 @@ package services
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -555,6 +557,7 @@ func TestSwitchBranch(t *testing.T) {
 	// Verify the correct commands were executed
 	expectedCommands := []string{
 		"git reset --hard HEAD",
+		"git remote get-url origin",
 		"git fetch origin",
 		"git checkout test-branch",
 	}
@@ -790,6 +793,253 @@ func TestCreateBranch_ConflictedIndex(t *testing.T) {
 	}
 }
 
+func TestCreateBranch_FromRemoteOnlyTargetBranch(t *testing.T) {
+	upstream := t.TempDir()
+	clone := t.TempDir()
+
+	keyPath := generateTestRSAKey(t)
+	t.Cleanup(func() { _ = os.Remove(keyPath) })
+
+	gitRun := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name, content string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(upstream, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(upstream, "add", ".")
+		gitRun(upstream, "commit", "-m", "update "+name)
+		return gitRun(upstream, "rev-parse", "HEAD")
+	}
+
+	gitRun(upstream, "init", "-b", "main")
+	gitRun(upstream, "config", "user.name", "Test")
+	gitRun(upstream, "config", "user.email", "test@example.com")
+	commit("file.txt", "base")
+	gitRun(upstream, "checkout", "-b", "release-4.18")
+	// A directory named like the branch must not be mistaken for a path.
+	if err := os.Mkdir(filepath.Join(upstream, "release-4.18"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	commit("release-4.18/notes.txt", "release")
+	gitRun(upstream, "checkout", "main")
+	commit("file.txt", "main-change")
+
+	gitRun(clone, "clone", upstream, ".")
+	gitRun(clone, "config", "user.name", "Test")
+	gitRun(clone, "config", "user.email", "test@example.com")
+
+	config := &models.Config{}
+	config.GitHub.AppID = 123456
+	config.GitHub.PrivateKeyPath = keyPath
+	config.GitHub.BotUsername = "test-bot"
+	githubService := NewGitHubService(config, zap.NewNop())
+
+	if err := githubService.CreateBranch(clone, "fix-1", "release-4.18"); err != nil {
+		t.Fatalf("CreateBranch failed: %v", err)
+	}
+	want := gitRun(upstream, "rev-parse", "release-4.18")
+	if got := gitRun(clone, "rev-parse", "HEAD"); got != want {
+		t.Errorf("HEAD = %s, want release-4.18 tip %s", got, want)
+	}
+
+	// The release branch advances upstream; the next branch must start
+	// from its new tip rather than the stale local copy.
+	gitRun(upstream, "checkout", "release-4.18")
+	want = commit("release-4.18/notes.txt", "release-fix")
+	if err := githubService.CreateBranch(clone, "fix-2", "release-4.18"); err != nil {
+		t.Fatalf("CreateBranch failed: %v", err)
+	}
+	if got := gitRun(clone, "rev-parse", "HEAD"); got != want {
+		t.Errorf("HEAD = %s, want updated release-4.18 tip %s", got, want)
+	}
+	if got := gitRun(clone, "rev-parse", "--abbrev-ref", "HEAD"); got != "fix-2" {
+		t.Errorf("expected branch fix-2, got %s", got)
+	}
+}
+
+func TestCloneDefaultBranch(t *testing.T) {
+	upstream := t.TempDir()
+	clone := t.TempDir()
+
+	gitRun := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+	gitRun(upstream, "init", "-b", "trunk")
+	gitRun(upstream, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "base")
+	gitRun(clone, "clone", upstream, ".")
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/repos/test-owner/test-repo", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"name": "test-repo", "default_branch": "develop"}`)
+	})
+	service := newGitHubTestService(t, handler)
+	service.executor = exec.Command
+
+	t.Run("GitHub URL uses the API", func(t *testing.T) {
+		branch, err := service.cloneDefaultBranch("https://github.com/test-owner/test-repo.git", clone)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if branch != "develop" {
+			t.Errorf("branch = %q, want %q", branch, "develop")
+		}
+	})
+
+	t.Run("falls back to origin/HEAD", func(t *testing.T) {
+		branch, err := service.cloneDefaultBranch(upstream, clone)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if branch != "trunk" {
+			t.Errorf("branch = %q, want %q", branch, "trunk")
+		}
+	})
+}
+
+func TestRemoteGitCommand_SuppliesTokenWithoutStoringIt(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/app/installations/1/access_tokens" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"token": "ghs_secret", "expires_at": "2099-01-01T00:00:00Z"}`)
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	service := newGitHubTestService(t, http.NewServeMux())
+	service.executor = exec.Command
+	tr := ghinstallation.NewFromAppsTransport(service.appTransport, 1)
+	tr.BaseURL = tokenServer.URL
+	service.installationAuth[1] = tr
+
+	dir := t.TempDir()
+	gitRun := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	gitRun("init")
+	gitRun("remote", "add", "origin", "https://ghs_old@github.com/test-owner/test-repo.git")
+
+	if err := service.RestoreRemoteAuth(dir, "test-owner", "test-repo"); err != nil {
+		t.Fatalf("RestoreRemoteAuth: %v", err)
+	}
+	if got := gitRun("remote", "get-url", "origin"); got != "https://github.com/test-owner/test-repo.git" {
+		t.Errorf("origin = %q, want a URL without credentials", got)
+	}
+
+	cmd, err := service.originGitCommand(dir, "credential", "fill")
+	if err != nil {
+		t.Fatalf("originGitCommand: %v", err)
+	}
+	if strings.Contains(strings.Join(cmd.Args, " "), "ghs_secret") {
+		t.Errorf("token leaked into command line: %v", cmd.Args)
+	}
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Env, "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=github.com\npath=test-owner/test-repo.git\n\n")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git credential fill: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "username=x-access-token") || !strings.Contains(string(out), "password=ghs_secret") {
+		t.Errorf("credential helper output = %q, want the installation token", out)
+	}
+
+	config, err := os.ReadFile(filepath.Join(dir, ".git", "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(config), "ghs_") {
+		t.Errorf(".git/config contains a token:\n%s", config)
+	}
+}
+
+func TestGetDefaultBranch(t *testing.T) {
+	calls := 0
+	handler := http.NewServeMux()
+	handler.HandleFunc("/repos/test-owner/test-repo", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"name": "test-repo", "default_branch": "develop"}`)
+	})
+
+	service := newGitHubTestService(t, handler)
+	for range 2 {
+		branch, err := service.GetDefaultBranch("test-owner", "test-repo")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if branch != "develop" {
+			t.Errorf("GetDefaultBranch() = %q, want %q", branch, "develop")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("API called %d times, want 1 (cached)", calls)
+	}
+}
+
+func TestGetPRReviewComments(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/repos/test-owner/test-repo/pulls/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = fmt.Fprint(w, `[
+				{"id": 3, "user": {"login": "bob"}, "body": "stale", "path": "b.go",
+				 "original_line": 9, "original_start_line": 8, "diff_hunk": "@@ -8,2 +8,2 @@"}
+			]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=2>; rel="next"`, r.Host, r.URL.Path))
+		_, _ = fmt.Fprint(w, `[
+			{"id": 1, "user": {"login": "alice"}, "body": "range", "path": "a.go",
+			 "line": 12, "start_line": 10, "side": "RIGHT", "original_line": 12},
+			{"id": 2, "user": {"login": "bob"}, "body": "reply", "path": "a.go",
+			 "line": 12, "in_reply_to_id": 1}
+		]`)
+	})
+
+	service := newGitHubTestService(t, handler)
+	comments, err := service.GetPRReviewComments("test-owner", "test-repo", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 3 {
+		t.Fatalf("got %d comments, want 3 across both pages", len(comments))
+	}
+
+	if c := comments[0]; c.StartLine != 10 || c.Line != 12 || c.Side != "RIGHT" || c.Outdated || !c.IsReviewComment {
+		t.Errorf("range comment = %+v", c)
+	}
+	if c := comments[1]; c.InReplyTo != 1 {
+		t.Errorf("reply InReplyTo = %d, want 1", c.InReplyTo)
+	}
+	if c := comments[2]; !c.Outdated || c.Line != 9 || c.StartLine != 8 || c.DiffHunk != "@@ -8,2 +8,2 @@" {
+		t.Errorf("outdated comment = %+v", c)
+	}
+}
+
 func TestParseUntrackedBlockers(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -930,6 +1180,236 @@ func TestMergeBase_UntrackedFileBlocker(t *testing.T) {
 	}
 }
 
+// setupBackport creates an upstream repository whose main branch has
+// merged PR #7 (refs/pull/7/head) after an unrelated commit, and a
+// workspace clone on a branch from release-1. When conflicting is
+// true, release-1 changes the line the PR changes. Returns the
+// workspace and the merge commit SHA.
+func setupBackport(t *testing.T, conflicting bool) (string, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	upstream := filepath.Join(tempDir, "upstream")
+	workspace := filepath.Join(tempDir, "workspace")
+	if err := os.MkdirAll(upstream, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	gitRun := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(upstream, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		gitRun(upstream, "add", ".")
+		gitRun(upstream, "commit", "-m", "update "+name)
+	}
+
+	gitRun(upstream, "init", "-b", "main")
+	gitRun(upstream, "config", "user.name", "Test")
+	gitRun(upstream, "config", "user.email", "test@example.com")
+	commit("file.txt", "base\n")
+	commit("other.txt", "base\n")
+	gitRun(upstream, "branch", "release-1")
+
+	gitRun(upstream, "checkout", "-b", "feature")
+	commit("file.txt", "fixed\n")
+	gitRun(upstream, "update-ref", "refs/pull/7/head", "feature")
+
+	gitRun(upstream, "checkout", "main")
+	commit("other.txt", "main only\n")
+	gitRun(upstream, "merge", "--no-ff", "-m", "Merge PR #7", "feature")
+	mergeSHA := gitRun(upstream, "rev-parse", "HEAD")
+
+	if conflicting {
+		gitRun(upstream, "checkout", "release-1")
+		commit("file.txt", "release change\n")
+		gitRun(upstream, "checkout", "main")
+	}
+
+	gitRun(tempDir, "clone", upstream, workspace)
+	gitRun(workspace, "config", "user.name", "Test")
+	gitRun(workspace, "config", "user.email", "test@example.com")
+	gitRun(workspace, "checkout", "-b", "backport", "origin/release-1")
+	return workspace, mergeSHA
+}
+
+func TestCherryPickPR(t *testing.T) {
+	keyPath := generateTestRSAKey(t)
+	t.Cleanup(func() { _ = os.Remove(keyPath) })
+	config := &models.Config{}
+	config.GitHub.AppID = 123456
+	config.GitHub.PrivateKeyPath = keyPath
+	config.GitHub.BotUsername = "test-bot"
+	githubService := NewGitHubService(config, zap.NewNop())
+
+	readFile := func(t *testing.T, dir, name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // test reads from t.TempDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("clean", func(t *testing.T) {
+		workspace, mergeSHA := setupBackport(t, false)
+
+		conflicts, err := githubService.CherryPickPR(workspace, "", 7, mergeSHA)
+		if err != nil {
+			t.Fatalf("CherryPickPR failed: %v", err)
+		}
+		if len(conflicts) != 0 {
+			t.Errorf("conflicts = %v, want none", conflicts)
+		}
+		if got := readFile(t, workspace, "file.txt"); got != "fixed\n" {
+			t.Errorf("file.txt = %q, want the PR's change", got)
+		}
+		// Commits merged to main outside the PR are not backported.
+		if got := readFile(t, workspace, "other.txt"); got != "base\n" {
+			t.Errorf("other.txt = %q, want release-1 content", got)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		workspace, mergeSHA := setupBackport(t, true)
+
+		conflicts, err := githubService.CherryPickPR(workspace, "", 7, mergeSHA)
+		if !errors.Is(err, ErrMergeConflict) {
+			t.Fatalf("err = %v, want ErrMergeConflict", err)
+		}
+		if len(conflicts) != 1 || conflicts[0] != "file.txt" {
+			t.Errorf("conflicts = %v, want [file.txt]", conflicts)
+		}
+		if got := readFile(t, workspace, "file.txt"); !strings.Contains(got, "<<<<<<<") {
+			t.Errorf("file.txt = %q, want conflict markers", got)
+		}
+	})
+
+	t.Run("missing merge commit", func(t *testing.T) {
+		workspace, _ := setupBackport(t, false)
+
+		if _, err := githubService.CherryPickPR(workspace, "", 7, ""); err == nil {
+			t.Error("expected an error without a merge commit SHA")
+		}
+	})
+}
+
+// setupRebase creates an upstream repository with a feature branch
+// and a workspace clone of it with an uncommitted change to file.txt,
+// then pushes a new commit to the upstream branch. When conflicting
+// is true, the new commit changes the line the workspace changes.
+// Returns the workspace and the branch head it was cloned at.
+func setupRebase(t *testing.T, conflicting bool) (string, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	upstream := filepath.Join(tempDir, "upstream")
+	workspace := filepath.Join(tempDir, "workspace")
+	if err := os.MkdirAll(upstream, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	gitRun := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitRun(upstream, "init", "-b", "feature")
+	gitRun(upstream, "config", "user.name", "Test")
+	gitRun(upstream, "config", "user.email", "test@example.com")
+	write(upstream, "file.txt", "base\n")
+	write(upstream, "other.txt", "base\n")
+	gitRun(upstream, "add", ".")
+	gitRun(upstream, "commit", "-m", "base")
+	base := gitRun(upstream, "rev-parse", "HEAD")
+
+	gitRun(tempDir, "clone", upstream, workspace)
+	gitRun(workspace, "config", "user.name", "Test")
+	gitRun(workspace, "config", "user.email", "test@example.com")
+	write(workspace, "file.txt", "ai change\n")
+
+	if conflicting {
+		write(upstream, "file.txt", "human change\n")
+	} else {
+		write(upstream, "other.txt", "human change\n")
+	}
+	gitRun(upstream, "commit", "-am", "human push")
+	return workspace, base
+}
+
+func TestRebaseOnRemote(t *testing.T) {
+	keyPath := generateTestRSAKey(t)
+	t.Cleanup(func() { _ = os.Remove(keyPath) })
+	config := &models.Config{}
+	config.GitHub.AppID = 123456
+	config.GitHub.PrivateKeyPath = keyPath
+	config.GitHub.BotUsername = "test-bot"
+	githubService := NewGitHubService(config, zap.NewNop())
+
+	readFile := func(t *testing.T, dir, name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // test reads from t.TempDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("clean", func(t *testing.T) {
+		workspace, base := setupRebase(t, false)
+
+		conflicts, err := githubService.RebaseOnRemote(workspace, "feature", base)
+		if err != nil {
+			t.Fatalf("RebaseOnRemote failed: %v", err)
+		}
+		if len(conflicts) != 0 {
+			t.Errorf("conflicts = %v, want none", conflicts)
+		}
+		if got := readFile(t, workspace, "file.txt"); got != "ai change\n" {
+			t.Errorf("file.txt = %q, want the local change", got)
+		}
+		if got := readFile(t, workspace, "other.txt"); got != "human change\n" {
+			t.Errorf("other.txt = %q, want the pushed change", got)
+		}
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		workspace, base := setupRebase(t, true)
+
+		conflicts, err := githubService.RebaseOnRemote(workspace, "feature", base)
+		if !errors.Is(err, ErrMergeConflict) {
+			t.Fatalf("err = %v, want ErrMergeConflict", err)
+		}
+		if len(conflicts) != 1 || conflicts[0] != "file.txt" {
+			t.Errorf("conflicts = %v, want [file.txt]", conflicts)
+		}
+		// The rebase is aborted, leaving the local work in place.
+		if got := readFile(t, workspace, "file.txt"); got != "ai change\n" {
+			t.Errorf("file.txt = %q, want the local change", got)
+		}
+	})
+}
+
 // TestGetBranchBaseCommit_BranchExists tests getting base commit when branch exists
 func TestGetBranchBaseCommit_BranchExists(t *testing.T) {
 	keyPath := generateTestRSAKey(t)
@@ -1048,6 +1528,23 @@ func TestGetBranchBaseCommit_BranchDoesNotExist(t *testing.T) {
 	}
 }
 
+func TestParseShortStat(t *testing.T) {
+	tests := []struct {
+		out  string
+		want models.DiffStat
+	}{
+		{out: " 3 files changed, 10 insertions(+), 2 deletions(-)\n", want: models.DiffStat{FilesChanged: 3, Insertions: 10, Deletions: 2}},
+		{out: " 1 file changed, 1 insertion(+)\n", want: models.DiffStat{FilesChanged: 1, Insertions: 1}},
+		{out: " 2 files changed, 7 deletions(-)\n", want: models.DiffStat{FilesChanged: 2, Deletions: 7}},
+		{out: "", want: models.DiffStat{}},
+	}
+	for _, tt := range tests {
+		if got := parseShortStat(tt.out); got != tt.want {
+			t.Errorf("parseShortStat(%q) = %+v, want %+v", tt.out, got, tt.want)
+		}
+	}
+}
+
 func TestGitHubService_HasChanges_NoChanges(t *testing.T) {
 	// Create a temporary directory for the test
 	tempDir, err := os.MkdirTemp("", "github-test-*")
@@ -1909,6 +2406,50 @@ func newGitHubTestService(t *testing.T, handler http.Handler) *GitHubServiceImpl
 	}
 }
 
+func TestEnablePRAutoMerge(t *testing.T) {
+	var mutation struct {
+		Query     string            `json:"query"`
+		Variables map[string]string `json:"variables"`
+	}
+	handler := http.NewServeMux()
+	handler.HandleFunc("/repos/test-owner/test-repo/pulls/42", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"number": 42, "node_id": "PR_kwDO42"}`))
+	})
+	handler.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&mutation)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {"enablePullRequestAutoMerge": {"clientMutationId": null}}}`))
+	})
+	service := newGitHubTestService(t, handler)
+
+	if err := service.EnablePRAutoMerge("test-owner", "test-repo", 42, "squash"); err != nil {
+		t.Fatalf("EnablePRAutoMerge: %v", err)
+	}
+	if !strings.Contains(mutation.Query, "enablePullRequestAutoMerge") ||
+		mutation.Variables["id"] != "PR_kwDO42" || mutation.Variables["method"] != "SQUASH" {
+		t.Errorf("mutation = %+v, want auto-merge of PR_kwDO42 with SQUASH", mutation)
+	}
+}
+
+func TestEnablePRAutoMerge_GraphQLError(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/repos/test-owner/test-repo/pulls/42", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"number": 42, "node_id": "PR_kwDO42"}`))
+	})
+	handler.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"errors": [{"message": "Pull request Auto merge is not allowed for this repository"}]}`))
+	})
+	service := newGitHubTestService(t, handler)
+
+	err := service.EnablePRAutoMerge("test-owner", "test-repo", 42, "merge")
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("err = %v, want the GraphQL error", err)
+	}
+}
+
 func TestGetPRMergeability_RetriesOnNilMergeable(t *testing.T) {
 	var requestCount atomic.Int32
 
@@ -2027,6 +2568,7 @@ func TestGetMergedPRForBranch(t *testing.T) {
 				"title": "Fix bug",
 				"html_url": "https://github.com/test-owner/test-repo/pull/42",
 				"merged_at": "2026-07-07T10:00:00Z",
+				"merge_commit_sha": "merge789",
 				"head": {"ref": "bot/TICKET-1", "sha": "abc123"},
 				"base": {"ref": "main"}
 			}]`)
@@ -2043,6 +2585,9 @@ func TestGetMergedPRForBranch(t *testing.T) {
 		if pr.Number != 42 {
 			t.Errorf("Number = %d, want 42", pr.Number)
 		}
+		if pr.MergeCommitSHA != "merge789" {
+			t.Errorf("MergeCommitSHA = %q, want %q", pr.MergeCommitSHA, "merge789")
+		}
 	})
 
 	t.Run("skips unmerged closed PR", func(t *testing.T) {
@@ -2123,6 +2668,68 @@ func TestGetMergedPRForBranch(t *testing.T) {
 	})
 }
 
+func TestCountOpenPRs(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/repos/test-owner/test-repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("state"); got != "open" {
+			t.Errorf("expected state=open query param, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", `<`+"http://"+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+			_, _ = fmt.Fprint(w, `[
+				{"number": 1, "head": {"ref": "bot/PROJ-1"}},
+				{"number": 2, "head": {"ref": "feature/human-work"}}
+			]`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `[
+			{"number": 3, "head": {"ref": "bot/PROJ-3"}},
+			{"number": 4, "head": {"ref": "bottle/PROJ-4"}}
+		]`)
+	})
+
+	service := newGitHubTestService(t, handler)
+	got, err := service.CountOpenPRs("test-owner", "test-repo", "bot/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("CountOpenPRs() = %d, want 2", got)
+	}
+}
+
+func TestBuildCommitMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		signOff  bool
+		coAuthor bool
+		want     string
+	}{
+		{name: "plain", want: "PROJ-1: Fix"},
+		{name: "co-author only", coAuthor: true, want: "PROJ-1: Fix\n\nCo-authored-by: Jane <jane@example.com>"},
+		{name: "sign-off only", signOff: true, want: "PROJ-1: Fix\n\nSigned-off-by: ai-bot[bot] <42+ai-bot[bot]@users.noreply.github.com>"},
+		{name: "both in one trailer block", signOff: true, coAuthor: true, want: "PROJ-1: Fix\n\nCo-authored-by: Jane <jane@example.com>\nSigned-off-by: ai-bot[bot] <42+ai-bot[bot]@users.noreply.github.com>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &models.Config{}
+			cfg.GitHub.AppID = 42
+			cfg.GitHub.BotUsername = "ai-bot"
+			cfg.GitHub.SignOff = tt.signOff
+			s := &GitHubServiceImpl{config: cfg}
+
+			var name, email string
+			if tt.coAuthor {
+				name, email = "Jane", "jane@example.com"
+			}
+			if got := s.buildCommitMessage("PROJ-1: Fix", name, email); got != tt.want {
+				t.Errorf("buildCommitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetClosedPRForBranch(t *testing.T) {
 	t.Run("finds closed unmerged PR", func(t *testing.T) {
 		handler := http.NewServeMux()
@@ -2244,3 +2851,188 @@ func TestGetClosedPRForBranch(t *testing.T) {
 		}
 	})
 }
+
+func TestGitHubService_ChangedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tempDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitRun("init", "-b", "main")
+	gitRun("config", "user.name", "Test")
+	gitRun("config", "user.email", "test@example.com")
+	// Self-referencing remote so origin/main resolves.
+	gitRun("remote", "add", "origin", tempDir)
+	write("keep.txt", "base")
+	write("edit.txt", "base")
+	write("remove.txt", "base")
+	gitRun("add", ".")
+	gitRun("commit", "-m", "initial")
+	gitRun("fetch", "origin")
+
+	gitRun("checkout", "-b", "feature")
+	write("committed.txt", "new")
+	gitRun("add", "committed.txt")
+	gitRun("commit", "-m", "feature commit")
+	write("edit.txt", "changed")
+	write("untracked.txt", "new")
+	if err := os.MkdirAll(filepath.Join(tempDir, ".ai-session"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	write(".ai-session/task.md", "task")
+	if err := os.Remove(filepath.Join(tempDir, "remove.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	keyPath := generateTestRSAKey(t)
+	defer func() { _ = os.Remove(keyPath) }()
+	config := &models.Config{}
+	config.GitHub.AppID = 123456
+	config.GitHub.PrivateKeyPath = keyPath
+	githubService := NewGitHubService(config, zap.NewNop())
+
+	files, err := githubService.ChangedFiles(tempDir, "main")
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+
+	want := []string{"committed.txt", "edit.txt", "untracked.txt"}
+	if !slices.Equal(files, want) {
+		t.Errorf("ChangedFiles = %v, want %v", files, want)
+	}
+
+	deleted, err := githubService.DeletedFiles(tempDir, "main")
+	if err != nil {
+		t.Fatalf("DeletedFiles failed: %v", err)
+	}
+	if want := []string{"remove.txt"}; !slices.Equal(deleted, want) {
+		t.Errorf("DeletedFiles = %v, want %v", deleted, want)
+	}
+}
+
+func TestGitHubService_BaseFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tempDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+
+	gitRun("init", "-b", "main")
+	gitRun("config", "user.name", "Test")
+	gitRun("config", "user.email", "test@example.com")
+	gitRun("remote", "add", "origin", tempDir)
+	if err := os.MkdirAll(filepath.Join(tempDir, "web"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "web", "package.json"), []byte("base\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", ".")
+	gitRun("commit", "-m", "initial")
+	gitRun("fetch", "origin")
+	if err := os.WriteFile(filepath.Join(tempDir, "web", "package.json"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	keyPath := generateTestRSAKey(t)
+	defer func() { _ = os.Remove(keyPath) }()
+	config := &models.Config{}
+	config.GitHub.AppID = 123456
+	config.GitHub.PrivateKeyPath = keyPath
+	githubService := NewGitHubService(config, zap.NewNop())
+
+	data, ok, err := githubService.BaseFile(tempDir, "main", "web/package.json")
+	if err != nil {
+		t.Fatalf("BaseFile failed: %v", err)
+	}
+	if !ok || string(data) != "base\n" {
+		t.Errorf("BaseFile = %q, %v; want base content", data, ok)
+	}
+
+	_, ok, err = githubService.BaseFile(tempDir, "main", "go.mod")
+	if err != nil {
+		t.Fatalf("BaseFile for missing file failed: %v", err)
+	}
+	if ok {
+		t.Error("BaseFile reported a file missing on the base branch as present")
+	}
+}
+
+func TestGitHubService_Diff(t *testing.T) {
+	tempDir := t.TempDir()
+
+	gitRun := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tempDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gitRun("init", "-b", "main")
+	gitRun("config", "user.name", "Test")
+	gitRun("config", "user.email", "test@example.com")
+	gitRun("remote", "add", "origin", tempDir)
+	writeFile("main.go", "package main\n")
+	gitRun("add", ".")
+	gitRun("commit", "-m", "initial")
+	gitRun("fetch", "origin")
+	writeFile("main.go", "package main\n\nfunc main() {}\n")
+	writeFile("util.go", "package main\n\nvar x = 1")
+	writeFile(".ai-session/pr.md", "Title\n")
+
+	keyPath := generateTestRSAKey(t)
+	defer func() { _ = os.Remove(keyPath) }()
+	config := &models.Config{}
+	config.GitHub.AppID = 123456
+	config.GitHub.PrivateKeyPath = keyPath
+	githubService := NewGitHubService(config, zap.NewNop())
+
+	diff, err := githubService.Diff(tempDir, "main")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	for _, want := range []string{
+		"diff --git a/main.go b/main.go",
+		"+func main() {}\n",
+		"diff --git a/util.go b/util.go\nnew file mode 100644\n--- /dev/null\n+++ b/util.go\n@@ -0,0 +1,3 @@\n+package main\n+\n+var x = 1\n\\ No newline at end of file\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, ".ai-session") {
+		t.Errorf("diff includes bot artifacts:\n%s", diff)
+	}
+}
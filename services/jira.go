@@ -2,20 +2,28 @@ package services
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"crypto/rand"
-	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/oauth2"
 
+	"jira-ai-issue-solver/circuit"
+	"jira-ai-issue-solver/httpcache"
 	"jira-ai-issue-solver/models"
 )
 
@@ -33,6 +41,19 @@ const (
 	// Response body truncation for logging and errors
 	maxBodyLogLength   = 500 // Max chars to log in debug
 	maxBodyErrorLength = 200 // Max chars to include in error messages
+
+	// jiraResponseCacheBytes bounds the GET responses kept to make
+	// repeated requests conditional.
+	jiraResponseCacheBytes = 16 << 20 // 16 MB
+
+	// fieldCacheTTL is how long the field name→ID mapping is used
+	// before it is fetched again, so that renamed and new custom
+	// fields are picked up without a restart.
+	fieldCacheTTL = time.Hour
+
+	// fieldCacheMissRefresh is the minimum age of the mapping before a
+	// lookup of an unknown field name fetches it again early.
+	fieldCacheMissRefresh = time.Minute
 )
 
 // truncate truncates a string to a maximum length
@@ -81,6 +102,13 @@ func randomJitter(maxSeconds float64) (float64, error) {
 	return normalized * maxSeconds, nil
 }
 
+// SecretResolver returns the current plaintext value of a config
+// value that may be a secret manager reference. Satisfied by
+// secrets.Store.
+type SecretResolver interface {
+	Resolve(value string) string
+}
+
 // JiraServiceImpl provides Jira API operations (search, get, update, comment, etc.).
 type JiraServiceImpl struct {
 	config   *models.Config
@@ -88,15 +116,31 @@ type JiraServiceImpl struct {
 	executor models.CommandExecutor
 	logger   *zap.Logger
 	sleepFn  func(time.Duration) <-chan time.Time // Returns a channel for select-based waiting
-
-	// fieldNameToID caches the field name→ID mapping from /rest/api/3/field.
-	// Populated on first call to GetFieldIDByName; nil until then.
-	fieldNameToID map[string]string
+	secrets  SecretResolver                       // Resolves jira.api_token when it is a secret reference; nil means plaintext
+	breaker  *circuit.Breaker                     // Pauses requests while Jira is down; nil means never
+
+	// oauthTokens supplies access tokens for auth_type oauth2. Built
+	// on first use; guarded by oauthMu.
+	oauthMu     sync.Mutex
+	oauthTokens oauth2.TokenSource
+
+	// fieldNameToID caches the field name→ID mapping from /rest/api/3/field,
+	// fetched at fieldsLoadedAt. Nil until the first lookup or
+	// WarmFieldCache, and after InvalidateFieldCache. Guarded by fieldMu.
+	fieldMu        sync.Mutex
+	fieldNameToID  map[string]string
+	fieldsLoadedAt time.Time
+	now            func() time.Time
 }
 
 // NewJiraService creates a new JiraServiceImpl with production defaults.
 func NewJiraService(config *models.Config, logger *zap.Logger, executor ...models.CommandExecutor) *JiraServiceImpl {
-	return NewJiraServiceForTest(config, &http.Client{}, logger, time.After, executor...)
+	transport, err := NewHTTPTransport(config.Network)
+	if err != nil {
+		logger.Fatal("Failed to configure outbound network", zap.Error(err))
+	}
+	client := &http.Client{Transport: httpcache.New(jiraResponseCacheBytes).Transport(transport, "")}
+	return NewJiraServiceForTest(config, client, logger, time.After, executor...)
 }
 
 // NewJiraServiceForTest creates a new JiraServiceImpl with a custom sleep function for testing.
@@ -111,7 +155,32 @@ func NewJiraServiceForTest(config *models.Config, client *http.Client, logger *z
 		executor: commandExecutor,
 		logger:   logger,
 		sleepFn:  sleepFn,
+		now:      time.Now,
+	}
+}
+
+// SetSecretResolver makes the service resolve jira.api_token through
+// r on every request, so that rotated tokens are picked up without a
+// restart. jira.oauth.client_secret is resolved once, when the first
+// OAuth token is requested.
+func (s *JiraServiceImpl) SetSecretResolver(r SecretResolver) {
+	s.secrets = r
+}
+
+// SetCircuitBreaker makes the service stop calling Jira while b is
+// open, failing requests with [circuit.ErrOpen] instead. Server
+// errors, exhausted rate-limit retries, and failed connections count
+// as failures.
+func (s *JiraServiceImpl) SetCircuitBreaker(b *circuit.Breaker) {
+	s.breaker = b
+}
+
+// apiToken returns the current Jira API token.
+func (s *JiraServiceImpl) apiToken() string {
+	if s.secrets != nil {
+		return s.secrets.Resolve(s.config.Jira.APIToken)
 	}
+	return s.config.Jira.APIToken
 }
 
 // doOperation makes a request to Jira and handles rate limiting errors.
@@ -122,6 +191,55 @@ func (s *JiraServiceImpl) doOperation(
 	bodyReader io.Reader,
 	okStatusCodes ...int,
 ) ([]byte, error) {
+	return s.doRequest(context.Background(), operation, url, nil, bodyReader, okStatusCodes...)
+}
+
+// doRequest is doOperation with a context and extra request headers,
+// which override the default JSON Content-Type. While the circuit
+// breaker is open it fails with [circuit.ErrOpen] without contacting
+// Jira.
+func (s *JiraServiceImpl) doRequest(
+	ctx context.Context,
+	operation string,
+	url string,
+	header http.Header,
+	bodyReader io.Reader,
+	okStatusCodes ...int,
+) ([]byte, error) {
+	if s.breaker == nil {
+		body, _, err := s.sendRequest(ctx, operation, url, header, bodyReader, okStatusCodes...)
+		return body, err
+	}
+	if !s.breaker.Allow() {
+		return nil, fmt.Errorf("failed to %s %s: jira %w", operation, url, circuit.ErrOpen)
+	}
+
+	body, statusCode, err := s.sendRequest(ctx, operation, url, header, bodyReader, okStatusCodes...)
+	// Any answer other than a server error or rate limiting shows
+	// that Jira is up, even if the request itself was rejected.
+	up := err == nil || (statusCode != 0 && statusCode < http.StatusInternalServerError &&
+		statusCode != http.StatusTooManyRequests)
+	if s.breaker.Record(up) {
+		stats := s.breaker.Stats()
+		s.logger.Warn("Jira unavailable, pausing Jira calls",
+			zap.Int("consecutive_failures", stats.Failures),
+			zap.Duration("retry_in", stats.Cooldown),
+			zap.Error(err))
+	}
+	return body, err
+}
+
+// sendRequest performs a Jira request, retrying when rate limited
+// until ctx is done. It also returns the status code of the last
+// response, or 0 if no response was received.
+func (s *JiraServiceImpl) sendRequest(
+	ctx context.Context,
+	operation string,
+	url string,
+	header http.Header,
+	bodyReader io.Reader,
+	okStatusCodes ...int,
+) ([]byte, int, error) {
 	s.logger.Debug("Doing operation", zap.String("operation", operation), zap.String("url", url))
 
 	// Buffer the request body once so it can be retried
@@ -130,7 +248,7 @@ func (s *JiraServiceImpl) doOperation(
 		var err error
 		requestBody, err = io.ReadAll(bodyReader)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
+			return nil, 0, fmt.Errorf("failed to read request body: %w", err)
 		}
 	}
 
@@ -141,19 +259,22 @@ func (s *JiraServiceImpl) doOperation(
 			bodyForRequest = bytes.NewReader(requestBody)
 		}
 
-		req, err := http.NewRequest(operation, url, bodyForRequest)
+		req, err := http.NewRequestWithContext(ctx, operation, url, bodyForRequest)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create %s request: %w", operation, err)
+			return nil, 0, fmt.Errorf("failed to create %s request: %w", operation, err)
 		}
 
-		credentials := base64.StdEncoding.EncodeToString(
-			[]byte(s.config.Jira.Username + ":" + s.config.Jira.APIToken))
-		req.Header.Set("Authorization", "Basic "+credentials)
+		if err := s.authorize(req); err != nil {
+			return nil, 0, err
+		}
 		req.Header.Set("Content-Type", "application/json")
+		for name, values := range header {
+			req.Header[name] = values
+		}
 
 		resp, err := s.client.Do(req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to send %s request: %w", operation, err)
+			return nil, 0, fmt.Errorf("failed to send %s request: %w", operation, err)
 		}
 
 		// Read the body and close immediately so we can retry if needed
@@ -163,7 +284,7 @@ func (s *JiraServiceImpl) doOperation(
 			s.logger.Error("Failed to close response body", zap.Error(closeErr), zap.String("operation", operation), zap.String("url", url))
 		}
 		if readErr != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", readErr)
+			return nil, 0, fmt.Errorf("failed to read response body: %w", readErr)
 		}
 
 		for _, okStatusCode := range okStatusCodes {
@@ -175,7 +296,7 @@ func (s *JiraServiceImpl) doOperation(
 				} else {
 					s.logger.Debug("Response body (binary, not logged)", zap.Int("size_bytes", len(body)))
 				}
-				return body, nil
+				return body, resp.StatusCode, nil
 			}
 		}
 
@@ -252,9 +373,11 @@ func (s *JiraServiceImpl) doOperation(
 				zap.Int("attempt", attempt),
 				zap.Duration("wait_duration", waitDuration))
 
-			// Wait using channel-based approach (compatible with future context support)
-			// When adding context: wrap in select with case <-ctx.Done()
-			<-s.sleepFn(waitDuration)
+			select {
+			case <-s.sleepFn(waitDuration):
+			case <-ctx.Done():
+				return nil, resp.StatusCode, fmt.Errorf("failed to %s %s: %w", operation, url, ctx.Err())
+			}
 
 			continue // Retry the request
 		}
@@ -262,14 +385,14 @@ func (s *JiraServiceImpl) doOperation(
 		// All other error cases - truncate body to avoid huge error messages.
 		// Skip body content for binary responses (images, attachments).
 		if isTextContentType(resp.Header.Get("Content-Type")) {
-			return nil, fmt.Errorf("failed to %s %s: status_code=%d, body=%s",
+			return nil, resp.StatusCode, fmt.Errorf("failed to %s %s: status_code=%d, body=%s",
 				operation, url, resp.StatusCode, truncateForError(body))
 		}
-		return nil, fmt.Errorf("failed to %s %s: status_code=%d, body=<%d bytes binary>",
+		return nil, resp.StatusCode, fmt.Errorf("failed to %s %s: status_code=%d, body=<%d bytes binary>",
 			operation, url, resp.StatusCode, len(body))
 	}
 
-	return nil, fmt.Errorf("failed to %s %s after %d retries", operation, url, maxRetries)
+	return nil, 0, fmt.Errorf("failed to %s %s after %d retries", operation, url, maxRetries)
 }
 
 // doGet is a helper function to make a GET request to Jira and process any rate limiting errors
@@ -289,9 +412,21 @@ func (s *JiraServiceImpl) doPost(url string, bodyReader io.Reader) ([]byte, erro
 	return s.doOperation("POST", url, bodyReader, http.StatusNoContent, http.StatusCreated, http.StatusOK)
 }
 
+// Ping verifies that Jira is reachable and the configured credentials
+// are accepted by fetching the authenticated user's profile. Used by
+// readiness probes; ctx bounds the request.
+func (s *JiraServiceImpl) Ping(ctx context.Context) error {
+	url := fmt.Sprintf("%s/rest/api/3/myself", s.apiBaseURL())
+
+	if _, err := s.doRequest(ctx, "GET", url, nil, nil, http.StatusOK); err != nil {
+		return fmt.Errorf("failed to reach Jira: %w", err)
+	}
+	return nil
+}
+
 // GetTicket fetches a ticket from Jira
 func (s *JiraServiceImpl) GetTicket(key string) (*models.JiraTicketResponse, error) {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.apiBaseURL(), key)
 
 	body, err := s.doGet(url)
 	if err != nil {
@@ -307,7 +442,7 @@ func (s *JiraServiceImpl) GetTicket(key string) (*models.JiraTicketResponse, err
 
 // GetTicketWithExpandedFields fetches a ticket from Jira with expanded fields for custom field access
 func (s *JiraServiceImpl) GetTicketWithExpandedFields(key string) (map[string]interface{}, map[string]string, error) {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s?expand=names", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s?expand=names", s.apiBaseURL(), key)
 
 	body, err := s.doGet(url)
 	if err != nil {
@@ -326,49 +461,81 @@ func (s *JiraServiceImpl) GetTicketWithExpandedFields(key string) (map[string]in
 	return ticketWithFields.Fields, ticketWithFields.Names, nil
 }
 
-// UpdateTicketStatus updates the status of a ticket
+// jiraTransition is an available transition as returned by the
+// transitions endpoint with its screen fields expanded.
+type jiraTransition struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	HasScreen bool   `json:"hasScreen"`
+	To        struct {
+		Name string `json:"name"`
+	} `json:"to"`
+	Fields map[string]struct {
+		Name     string `json:"name"`
+		Required bool   `json:"required"`
+	} `json:"fields"`
+}
+
+// UpdateTicketStatus updates the status of a ticket. Values configured
+// under jira.transition_fields for the status (resolution, comment,
+// other screen fields) are sent with the transition.
 func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
 	// Get available transitions
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s/transitions", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/transitions", s.apiBaseURL(), key)
 
-	body, err := s.doGet(url)
+	body, err := s.doGet(url + "?expand=transitions.fields")
 	if err != nil {
 		return fmt.Errorf("failed to get transitions, err: %w", err)
 	}
 
 	var transitions struct {
-		Transitions []struct {
-			ID   string `json:"id"`
-			Name string `json:"name"`
-			To   struct {
-				Name string `json:"name"`
-			} `json:"to"`
-		} `json:"transitions"`
+		Transitions []jiraTransition `json:"transitions"`
 	}
 
 	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&transitions); err != nil {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Find the transition ID for the target status
-	var transitionID string
-	for _, transition := range transitions.Transitions {
-		if strings.EqualFold(transition.To.Name, status) {
-			transitionID = transition.ID
+	// Find the transition for the target status
+	var transition *jiraTransition
+	for i := range transitions.Transitions {
+		if strings.EqualFold(transitions.Transitions[i].To.Name, status) {
+			transition = &transitions.Transitions[i]
 			break
 		}
 	}
 
-	if transitionID == "" {
+	if transition == nil {
 		return fmt.Errorf("no transition found for status: %s", status)
 	}
 
 	// Perform the transition
 	payload := map[string]interface{}{
 		"transition": map[string]string{
-			"id": transitionID,
+			"id": transition.ID,
 		},
 	}
+	tf, _ := s.config.Jira.TransitionFieldsFor(status)
+	fields := s.transitionScreenFields(key, transition, tf)
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
+	// A comment can only go with a transition that has a screen;
+	// otherwise it is posted once the transition succeeded.
+	commentAfter := tf.Comment != "" && !transition.HasScreen
+	if tf.Comment != "" && transition.HasScreen {
+		add := map[string]any{"body": models.TextToADF(tf.Comment)}
+		visibility, err := s.commentVisibility(key)
+		if err != nil {
+			return err
+		}
+		if visibility != nil {
+			add["visibility"] = visibility
+		}
+		payload["update"] = map[string]any{
+			"comment": []map[string]any{{"add": add}},
+		}
+	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
@@ -376,21 +543,76 @@ func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
 	}
 
 	if _, err := s.doPost(url, bytes.NewReader(jsonPayload)); err != nil {
+		if missing := unsetRequiredFields(transition, fields); len(missing) > 0 {
+			return fmt.Errorf("failed to update ticket status (the transition screen requires %s; set values under jira.transition_fields.%s): %w",
+				strings.Join(missing, ", "), strings.ToLower(status), err)
+		}
 		return fmt.Errorf("failed to update ticket status: %w", err)
 	}
 
+	if commentAfter {
+		if err := s.AddComment(key, tf.Comment); err != nil {
+			return fmt.Errorf("failed to add transition comment: %w", err)
+		}
+	}
 	return nil
 }
 
+// transitionScreenFields returns the configured field values that the
+// transition's screen accepts. Values for fields not on the screen
+// would make Jira reject the transition, so they are skipped.
+func (s *JiraServiceImpl) transitionScreenFields(key string, t *jiraTransition, tf models.JiraTransitionFields) map[string]any {
+	fields := make(map[string]any)
+	set := func(id string, value any) {
+		if _, ok := t.Fields[id]; !ok {
+			s.logger.Debug("Transition screen lacks configured field, skipping it",
+				zap.String("ticket", key),
+				zap.String("transition", t.Name),
+				zap.String("field", id))
+			return
+		}
+		fields[id] = value
+	}
+	if tf.Resolution != "" {
+		set("resolution", map[string]string{"name": tf.Resolution})
+	}
+	for id, value := range tf.Fields {
+		set(id, value)
+	}
+	return fields
+}
+
+// unsetRequiredFields lists the names of the required fields on the
+// transition's screen that fields does not set, sorted, to point the
+// operator at the configuration a rejected transition needs. Jira may
+// accept such a field when the ticket already has a value for it.
+func unsetRequiredFields(t *jiraTransition, fields map[string]any) []string {
+	var missing []string
+	for id, f := range t.Fields {
+		if _, ok := fields[id]; f.Required && !ok {
+			missing = append(missing, cmp.Or(f.Name, id))
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
 // AddComment adds a comment to a ticket.
 // The comment text is converted to Atlassian Document Format (ADF)
 // for Jira Cloud API v3.
 func (s *JiraServiceImpl) AddComment(key string, comment string) error {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment", s.apiBaseURL(), key)
 
 	payload := map[string]any{
 		"body": models.TextToADF(comment),
 	}
+	visibility, err := s.commentVisibility(key)
+	if err != nil {
+		return err
+	}
+	if visibility != nil {
+		payload["visibility"] = visibility
+	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
@@ -404,9 +626,108 @@ func (s *JiraServiceImpl) AddComment(key string, comment string) error {
 	return nil
 }
 
+// AddWorklog records time spent on a ticket. Jira tracks worklogs in
+// whole minutes, so timeSpent is rounded up to at least one minute.
+func (s *JiraServiceImpl) AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error {
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/worklog", s.apiBaseURL(), key)
+
+	minutes := max(1, int(math.Ceil(timeSpent.Minutes())))
+	payload := map[string]any{
+		"started":          started.Format("2006-01-02T15:04:05.000-0700"),
+		"timeSpentSeconds": minutes * 60,
+		"comment":          models.TextToADF(comment),
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worklog payload: %w", err)
+	}
+
+	if _, err := s.doPost(url, bytes.NewReader(jsonPayload)); err != nil {
+		return fmt.Errorf("failed to add worklog: %w", err)
+	}
+
+	return nil
+}
+
+// AddAttachment uploads content as a file attached to a ticket.
+func (s *JiraServiceImpl) AddAttachment(key, filename string, content []byte) error {
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/attachments", s.apiBaseURL(), key)
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment form: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to write attachment form: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write attachment form: %w", err)
+	}
+
+	// Jira rejects attachment uploads without the XSRF opt-out header.
+	header := http.Header{
+		"Content-Type":      {w.FormDataContentType()},
+		"X-Atlassian-Token": {"no-check"},
+	}
+	if _, err := s.doRequest(context.Background(), "POST", url, header, &body, http.StatusOK); err != nil {
+		return fmt.Errorf("failed to add attachment: %w", err)
+	}
+
+	return nil
+}
+
+// LinkIssues links two tickets with a link of the named type (e.g.,
+// "Relates"). key is sent as the link's inward issue and otherKey as
+// its outward issue.
+func (s *JiraServiceImpl) LinkIssues(key, otherKey, linkType string) error {
+	url := fmt.Sprintf("%s/rest/api/3/issueLink", s.apiBaseURL())
+
+	payload := map[string]any{
+		"type":         map[string]string{"name": linkType},
+		"inwardIssue":  map[string]string{"key": key},
+		"outwardIssue": map[string]string{"key": otherKey},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue link payload: %w", err)
+	}
+
+	if _, err := s.doPost(url, bytes.NewReader(jsonPayload)); err != nil {
+		return fmt.Errorf("failed to link issues: %w", err)
+	}
+
+	return nil
+}
+
+// AssignTicket assigns a ticket to the account with the given Jira
+// account ID. An empty accountID unassigns the ticket.
+func (s *JiraServiceImpl) AssignTicket(key, accountID string) error {
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/assignee", s.apiBaseURL(), key)
+
+	payload := map[string]any{"accountId": nil}
+	if accountID != "" {
+		payload["accountId"] = accountID
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal assignee payload: %w", err)
+	}
+
+	if _, err := s.doPut(url, bytes.NewReader(jsonPayload)); err != nil {
+		return fmt.Errorf("failed to assign ticket: %w", err)
+	}
+
+	return nil
+}
+
 // GetComments retrieves all comments on a ticket.
 func (s *JiraServiceImpl) GetComments(key string) ([]models.JiraComment, error) {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment", s.apiBaseURL(), key)
 
 	body, err := s.doGet(url)
 	if err != nil {
@@ -426,11 +747,18 @@ func (s *JiraServiceImpl) GetComments(key string) ([]models.JiraComment, error)
 
 // UpdateComment replaces the body of an existing comment.
 func (s *JiraServiceImpl) UpdateComment(key, commentID, body string) error {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment/%s", s.config.Jira.BaseURL, key, commentID)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment/%s", s.apiBaseURL(), key, commentID)
 
 	payload := map[string]any{
 		"body": models.TextToADF(body),
 	}
+	visibility, err := s.commentVisibility(key)
+	if err != nil {
+		return err
+	}
+	if visibility != nil {
+		payload["visibility"] = visibility
+	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
@@ -446,7 +774,7 @@ func (s *JiraServiceImpl) UpdateComment(key, commentID, body string) error {
 
 // DeleteComment removes a comment from a ticket.
 func (s *JiraServiceImpl) DeleteComment(key, commentID string) error {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment/%s", s.config.Jira.BaseURL, key, commentID)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s/comment/%s", s.apiBaseURL(), key, commentID)
 
 	if _, err := s.doDelete(url); err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
@@ -458,7 +786,7 @@ func (s *JiraServiceImpl) DeleteComment(key, commentID string) error {
 // AddLabel adds a single label to a ticket without affecting other
 // labels. Uses the Jira update operation syntax.
 func (s *JiraServiceImpl) AddLabel(key, label string) error {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.apiBaseURL(), key)
 
 	payload := map[string]any{
 		"update": map[string]any{
@@ -483,7 +811,7 @@ func (s *JiraServiceImpl) AddLabel(key, label string) error {
 // RemoveLabel removes a single label from a ticket without affecting
 // other labels. Uses the Jira update operation syntax.
 func (s *JiraServiceImpl) RemoveLabel(key, label string) error {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.apiBaseURL(), key)
 
 	payload := map[string]any{
 		"update": map[string]any{
@@ -507,7 +835,7 @@ func (s *JiraServiceImpl) RemoveLabel(key, label string) error {
 
 // UpdateTicketField updates a specific field of a ticket
 func (s *JiraServiceImpl) UpdateTicketField(key string, fieldID string, value interface{}) error {
-	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.config.Jira.BaseURL, key)
+	url := fmt.Sprintf("%s/rest/api/3/issue/%s", s.apiBaseURL(), key)
 
 	payload := map[string]interface{}{
 		"fields": map[string]interface{}{
@@ -537,25 +865,55 @@ func (s *JiraServiceImpl) UpdateTicketFieldByName(key string, fieldName string,
 }
 
 // GetFieldIDByName resolves a field name to its ID. The full field list
-// is fetched from Jira on the first call and cached for subsequent lookups.
+// is fetched from Jira on the first call and cached for fieldCacheTTL.
+// A name missing from a cache older than fieldCacheMissRefresh fetches
+// the list again, in case the field was created since.
 func (s *JiraServiceImpl) GetFieldIDByName(fieldName string) (string, error) {
-	if s.fieldNameToID == nil {
+	s.fieldMu.Lock()
+	defer s.fieldMu.Unlock()
+
+	age := s.now().Sub(s.fieldsLoadedAt)
+	if s.fieldNameToID == nil || age >= fieldCacheTTL {
 		if err := s.loadFieldCache(); err != nil {
 			return "", err
 		}
+		age = 0
 	}
 
 	id, ok := s.fieldNameToID[fieldName]
+	if !ok && age >= fieldCacheMissRefresh {
+		if err := s.loadFieldCache(); err != nil {
+			return "", err
+		}
+		id, ok = s.fieldNameToID[fieldName]
+	}
 	if !ok {
 		return "", fmt.Errorf("field with name '%s' not found", fieldName)
 	}
 	return id, nil
 }
 
+// WarmFieldCache fetches the field definitions ahead of the first
+// lookup, so that startup fails loudly on Jira access problems rather
+// than on the first ticket.
+func (s *JiraServiceImpl) WarmFieldCache() error {
+	s.fieldMu.Lock()
+	defer s.fieldMu.Unlock()
+	return s.loadFieldCache()
+}
+
+// InvalidateFieldCache discards the cached field definitions; the next
+// lookup fetches them again.
+func (s *JiraServiceImpl) InvalidateFieldCache() {
+	s.fieldMu.Lock()
+	defer s.fieldMu.Unlock()
+	s.fieldNameToID = nil
+}
+
 // loadFieldCache fetches all field definitions from Jira and populates
-// the name→ID cache.
+// the name→ID cache. The caller must hold fieldMu.
 func (s *JiraServiceImpl) loadFieldCache() error {
-	url := fmt.Sprintf("%s/rest/api/3/field", s.config.Jira.BaseURL)
+	url := fmt.Sprintf("%s/rest/api/3/field", s.apiBaseURL())
 
 	body, err := s.doGet(url)
 	if err != nil {
@@ -575,38 +933,112 @@ func (s *JiraServiceImpl) loadFieldCache() error {
 	for _, field := range fields {
 		s.fieldNameToID[field.Name] = field.ID
 	}
+	s.fieldsLoadedAt = s.now()
 
 	s.logger.Info("Cached Jira field definitions", zap.Int("count", len(s.fieldNameToID)))
 	return nil
 }
 
+// searchPageSize is the number of issues requested per search page.
+const searchPageSize = 100
+
 // SearchTickets searches for tickets using JQL via the Jira Cloud
-// enhanced search endpoint (POST /rest/api/3/search/jql).
+// enhanced search endpoint (POST /rest/api/3/search/jql) and returns
+// every matching issue, up to jira.max_search_results.
 func (s *JiraServiceImpl) SearchTickets(jql string) (*models.JiraSearchResponse, error) {
-	url := fmt.Sprintf("%s/rest/api/3/search/jql", s.config.Jira.BaseURL)
-
-	payload := map[string]interface{}{
-		"jql":        jql,
-		"maxResults": 100,
-		"fields":     []string{"summary", "description", "status", "issuetype", "project", "components", "labels", "assignee", "security", "created", "updated", "creator", "reporter"},
-	}
-
-	jsonPayload, err := json.Marshal(payload)
+	all := &models.JiraSearchResponse{Issues: []models.JiraIssue{}, IsLast: true}
+	err := s.SearchTicketPages(jql, func(page *models.JiraSearchResponse) error {
+		all.Issues = append(all.Issues, page.Issues...)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, err
 	}
+	return all, nil
+}
 
-	body, err := s.doPost(url, bytes.NewReader(jsonPayload))
-	if err != nil {
-		return nil, fmt.Errorf("failed to search tickets: %w", err)
-	}
+// searchFields are the issue fields requested by SearchTicketPages:
+// everything the scanners and pipelines read from a ticket, so that
+// search results need not be fetched again one by one.
+var searchFields = []string{
+	"summary", "description", "status", "issuetype", "project", "components", "labels", "priority",
+	"assignee", "security", "created", "updated", "creator", "reporter", "issuelinks", "parent",
+	"comment", "attachment", "fixVersions",
+}
 
-	var searchResponse models.JiraSearchResponse
-	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&searchResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// SearchTicketPages runs a JQL search and calls fn with each page of
+// results as it arrives, following nextPageToken until the last page.
+// Issues carry the fields in searchFields, plus the instance's custom
+// security level field when the field cache knows one.
+// When jira.max_search_results is positive, the search stops once
+// that many issues have been delivered and a warning is logged. An
+// error returned by fn stops the search and is returned unchanged.
+func (s *JiraServiceImpl) SearchTicketPages(jql string, fn func(page *models.JiraSearchResponse) error) error {
+	url := fmt.Sprintf("%s/rest/api/3/search/jql", s.apiBaseURL())
+	limit := s.config.Jira.MaxSearchResults
+
+	fields := searchFields
+	securityField := s.cachedCustomSecurityField()
+	if securityField != "" {
+		fields = append(slices.Clip(fields), securityField)
 	}
 
-	return &searchResponse, nil
+	var pageToken string
+	delivered := 0
+	for {
+		pageSize := searchPageSize
+		if limit > 0 {
+			pageSize = min(pageSize, limit-delivered)
+		}
+
+		payload := map[string]interface{}{
+			"jql":        jql,
+			"maxResults": pageSize,
+			"fields":     fields,
+		}
+		if pageToken != "" {
+			payload["nextPageToken"] = pageToken
+		}
+
+		jsonPayload, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal payload: %w", err)
+		}
+
+		body, err := s.doPost(url, bytes.NewReader(jsonPayload))
+		if err != nil {
+			return fmt.Errorf("failed to search tickets: %w", err)
+		}
+
+		var page models.JiraSearchResponse
+		if err := json.NewDecoder(bytes.NewReader(body)).Decode(&page); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if securityField != "" {
+			if err := fillCustomSecurity(&page, body, securityField); err != nil {
+				return err
+			}
+		}
+		if limit > 0 && len(page.Issues) > limit-delivered {
+			page.Issues = page.Issues[:limit-delivered]
+		}
+		delivered += len(page.Issues)
+
+		if err := fn(&page); err != nil {
+			return err
+		}
+
+		if page.IsLast || page.NextPageToken == "" {
+			return nil
+		}
+		if limit > 0 && delivered >= limit {
+			s.logger.Warn("Jira search truncated at jira.max_search_results; remaining issues are picked up in later scans",
+				zap.Int("max_search_results", limit),
+				zap.String("jql", jql))
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
 }
 
 // HasSecurityLevel checks if a ticket has a security level set (other than "None")
@@ -620,6 +1052,26 @@ func (s *JiraServiceImpl) HasSecurityLevel(key string) (bool, error) {
 	return security != nil && security.Name != "" && strings.ToLower(security.Name) != "none", nil
 }
 
+// commentVisibility returns the visibility restriction for comments
+// on key: jira.secure_comment_visibility when the ticket has a
+// security level, nil otherwise or when none is configured. When the
+// security level cannot be determined the comment is not posted,
+// rather than risk exposing an embargoed issue.
+func (s *JiraServiceImpl) commentVisibility(key string) (map[string]string, error) {
+	v := s.config.Jira.SecureCommentVisibility
+	if !v.IsSet() {
+		return nil, nil
+	}
+	secure, err := s.HasSecurityLevel(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check security level for comment visibility: %w", err)
+	}
+	if !secure {
+		return nil, nil
+	}
+	return map[string]string{"type": v.Type, "value": v.Value}, nil
+}
+
 // GetTicketSecurityLevel gets the security level of a ticket
 func (s *JiraServiceImpl) GetTicketSecurityLevel(key string) (*models.JiraSecurity, error) {
 	// First try the standard fields API
@@ -653,28 +1105,69 @@ func (s *JiraServiceImpl) GetTicketSecurityLevel(key string) (*models.JiraSecuri
 	}
 
 	// Extract security level from expanded fields
-	if securityValue, ok := fields[securityFieldID]; ok && securityValue != nil {
-		// Handle different possible formats of security field
-		switch v := securityValue.(type) {
-		case map[string]interface{}:
-			security := &models.JiraSecurity{}
-			if id, ok := v["id"].(string); ok {
-				security.ID = id
-			}
-			if name, ok := v["name"].(string); ok {
-				security.Name = name
-			}
-			if desc, ok := v["description"].(string); ok {
-				security.Description = desc
-			}
-			return security, nil
-		case string:
-			// Sometimes just the name is returned
-			return &models.JiraSecurity{Name: v}, nil
+	return parseSecurityValue(fields[securityFieldID]), nil
+}
+
+// parseSecurityValue converts the value of a custom security level
+// field into a JiraSecurity. Returns nil when the value is empty or of
+// an unknown shape.
+func parseSecurityValue(value any) *models.JiraSecurity {
+	// Handle different possible formats of security field
+	switch v := value.(type) {
+	case map[string]interface{}:
+		security := &models.JiraSecurity{}
+		if id, ok := v["id"].(string); ok {
+			security.ID = id
 		}
+		if name, ok := v["name"].(string); ok {
+			security.Name = name
+		}
+		if desc, ok := v["description"].(string); ok {
+			security.Description = desc
+		}
+		return security
+	case string:
+		// Sometimes just the name is returned
+		return &models.JiraSecurity{Name: v}
 	}
+	return nil
+}
+
+// cachedCustomSecurityField returns the ID of a custom field named
+// "Security Level" or "Security", as GetTicketSecurityLevel looks for,
+// if the field cache is loaded. It never fetches the field list, so
+// searches cost a single request; the cache is warmed at startup.
+func (s *JiraServiceImpl) cachedCustomSecurityField() string {
+	s.fieldMu.Lock()
+	defer s.fieldMu.Unlock()
+	for name, id := range s.fieldNameToID {
+		lower := strings.ToLower(name)
+		if (lower == "security level" || lower == "security") && strings.HasPrefix(id, "customfield_") {
+			return id
+		}
+	}
+	return ""
+}
 
-	return nil, nil
+// fillCustomSecurity sets the security level of the issues in page
+// that lack the standard one from the custom field fieldID, decoded
+// from the raw page body.
+func fillCustomSecurity(page *models.JiraSearchResponse, body []byte, fieldID string) error {
+	var raw struct {
+		Issues []struct {
+			Fields map[string]any `json:"fields"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	for i := range page.Issues {
+		if page.Issues[i].Fields.Security != nil || i >= len(raw.Issues) {
+			continue
+		}
+		page.Issues[i].Fields.Security = parseSecurityValue(raw.Issues[i].Fields[fieldID])
+	}
+	return nil
 }
 
 // DownloadAttachment fetches the raw content of a Jira attachment by
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"jira-ai-issue-solver/models"
+)
+
+// apiBaseURL returns the URL prefix for Jira REST requests. OAuth 2.0
+// apps must call the Atlassian API gateway rather than the site URL.
+func (s *JiraServiceImpl) apiBaseURL() string {
+	if s.config.Jira.AuthType != models.JiraAuthOAuth2 {
+		return s.config.Jira.BaseURL
+	}
+	apiURL := s.config.Jira.OAuth.APIURL
+	if apiURL == "" {
+		apiURL = models.DefaultJiraOAuthAPIURL
+	}
+	return strings.TrimRight(apiURL, "/") + "/" + s.config.Jira.OAuth.CloudID
+}
+
+// authorize sets the Authorization header for the configured
+// jira.auth_type.
+func (s *JiraServiceImpl) authorize(req *http.Request) error {
+	switch s.config.Jira.AuthType {
+	case models.JiraAuthPAT:
+		req.Header.Set("Authorization", "Bearer "+s.apiToken())
+	case models.JiraAuthOAuth2:
+		ts, err := s.oauthTokenSource()
+		if err != nil {
+			return err
+		}
+		token, err := ts.Token()
+		if err != nil {
+			return fmt.Errorf("failed to obtain Jira OAuth token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	default:
+		credentials := base64.StdEncoding.EncodeToString(
+			[]byte(s.config.Jira.Username + ":" + s.apiToken()))
+		req.Header.Set("Authorization", "Basic "+credentials)
+	}
+	return nil
+}
+
+// oauthTokenSource lazily builds the refreshing token source, so that
+// the client secret is resolved after SetSecretResolver and
+// deployments using other auth types never touch OAuth settings.
+func (s *JiraServiceImpl) oauthTokenSource() (oauth2.TokenSource, error) {
+	s.oauthMu.Lock()
+	defer s.oauthMu.Unlock()
+	if s.oauthTokens != nil {
+		return s.oauthTokens, nil
+	}
+
+	o := s.config.Jira.OAuth
+	clientSecret := o.ClientSecret
+	if s.secrets != nil {
+		clientSecret = s.secrets.Resolve(clientSecret)
+	}
+	tokenURL := o.TokenURL
+	if tokenURL == "" {
+		tokenURL = models.DefaultJiraOAuthTokenURL
+	}
+
+	refreshToken, err := loadRefreshToken(o.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+	if refreshToken == "" {
+		refreshToken = o.RefreshToken
+	}
+	if refreshToken == "" {
+		return nil, errors.New("no Jira OAuth refresh token configured")
+	}
+
+	cfg := &oauth2.Config{
+		ClientID:     o.ClientID,
+		ClientSecret: clientSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  tokenURL,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+	// The token source outlives any single request, so it is built
+	// with a background context carrying the service's HTTP client.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, s.client)
+	s.oauthTokens = &persistingTokenSource{
+		base:         cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}),
+		path:         o.TokenFile,
+		refreshToken: refreshToken,
+		logger:       s.logger,
+	}
+	return s.oauthTokens, nil
+}
+
+// persistingTokenSource writes rotated refresh tokens to a file.
+// Atlassian rotates the refresh token on every refresh; without
+// persistence a restart would reuse the original, now-invalid token.
+type persistingTokenSource struct {
+	base   oauth2.TokenSource
+	path   string
+	logger *zap.Logger
+
+	mu           sync.Mutex
+	refreshToken string
+}
+
+// Token returns a valid access token, refreshing it if necessary.
+func (p *persistingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := p.base.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.path == "" || token.RefreshToken == "" || token.RefreshToken == p.refreshToken {
+		return token, nil
+	}
+	if err := os.WriteFile(p.path, []byte(token.RefreshToken+"\n"), 0o600); err != nil {
+		// The token is still usable for this process; only a restart
+		// is affected.
+		p.logger.Error("Failed to persist rotated Jira OAuth refresh token",
+			zap.String("path", p.path), zap.Error(err))
+		return token, nil
+	}
+	p.refreshToken = token.RefreshToken
+	return token, nil
+}
+
+// loadRefreshToken reads a persisted refresh token. A missing file is
+// not an error.
+func loadRefreshToken(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read Jira OAuth token file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
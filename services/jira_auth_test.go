@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestJiraAuth_PAT(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer pat-token" {
+			t.Errorf("Authorization = %q, want Bearer pat-token", got)
+		}
+		_, _ = w.Write([]byte(`{"accountId":"bot"}`))
+	}))
+	defer srv.Close()
+
+	cfg := &models.Config{Jira: models.JiraConfig{BaseURL: srv.URL, AuthType: models.JiraAuthPAT, APIToken: "pat-token"}}
+	service := NewJiraServiceForTest(cfg, srv.Client(), zap.NewNop(), instantSleep)
+
+	if _, err := service.doOperation(http.MethodGet, service.apiBaseURL()+"/rest/api/3/myself", nil, http.StatusOK); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestJiraAuth_OAuth2RefreshesAndPersistsToken(t *testing.T) {
+	var refreshes int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if got := r.PostForm.Get("grant_type"); got != "refresh_token" {
+			t.Errorf("grant_type = %q, want refresh_token", got)
+		}
+		if got := r.PostForm.Get("refresh_token"); got != "persisted-refresh" {
+			t.Errorf("refresh_token = %q, want the persisted token", got)
+		}
+		if got := r.PostForm.Get("client_secret"); got != "resolved-secret" {
+			t.Errorf("client_secret = %q, want resolved-secret", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access-1",
+			"refresh_token": "rotated-refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	mux.HandleFunc("/ex/jira/cloud-1/rest/api/3/myself", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer access-1" {
+			t.Errorf("Authorization = %q, want Bearer access-1", got)
+		}
+		_, _ = w.Write([]byte(`{"accountId":"bot"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "jira-refresh-token")
+	if err := os.WriteFile(tokenFile, []byte("persisted-refresh\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &models.Config{Jira: models.JiraConfig{
+		BaseURL:  "https://example.atlassian.net",
+		AuthType: models.JiraAuthOAuth2,
+		OAuth: models.JiraOAuthConfig{
+			ClientID:     "client",
+			ClientSecret: "vault://secret/jira#client_secret",
+			RefreshToken: "stale-refresh",
+			CloudID:      "cloud-1",
+			TokenURL:     srv.URL + "/oauth/token",
+			APIURL:       srv.URL + "/ex/jira/",
+			TokenFile:    tokenFile,
+		},
+	}}
+	service := NewJiraServiceForTest(cfg, srv.Client(), zap.NewNop(), instantSleep)
+	service.SetSecretResolver(stubResolver{"vault://secret/jira#client_secret": "resolved-secret"})
+
+	for range 2 {
+		if _, err := service.doOperation(http.MethodGet, service.apiBaseURL()+"/rest/api/3/myself", nil, http.StatusOK); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if refreshes != 1 {
+		t.Errorf("token endpoint called %d times, want 1 (access token reused)", refreshes)
+	}
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "rotated-refresh" {
+		t.Errorf("persisted refresh token = %q, want rotated-refresh", got)
+	}
+}
+
+// stubResolver resolves secret references from a map and passes
+// other values through.
+type stubResolver map[string]string
+
+func (r stubResolver) Resolve(value string) string {
+	if v, ok := r[value]; ok {
+		return v
+	}
+	return value
+}
@@ -2,15 +2,21 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"go.uber.org/zap"
 
+	"jira-ai-issue-solver/circuit"
 	"jira-ai-issue-solver/models"
 )
 
@@ -582,6 +588,115 @@ func TestUpdateTicketStatus(t *testing.T) {
 	}
 }
 
+// transitionScreen is a transitions response whose Done transition has
+// a screen with a required resolution field.
+const transitionScreen = `{
+	"transitions": [
+		{
+			"id": "31",
+			"name": "Done",
+			"hasScreen": true,
+			"to": {"name": "Done"},
+			"fields": {
+				"resolution": {"name": "Resolution", "required": true}
+			}
+		}
+	]
+}`
+
+// updateStatusRequests runs UpdateTicketStatus against canned
+// responses and returns the decoded bodies of the POST requests made.
+func updateStatusRequests(t *testing.T, config *models.Config, transitions string, postStatus int) ([]map[string]any, error) {
+	t.Helper()
+	var posts []map[string]any
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet {
+			if req.URL.Query().Get("expand") != "transitions.fields" {
+				t.Errorf("transitions requested without screen fields: %s", req.URL)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(transitions)),
+			}, nil
+		}
+		var body map[string]any
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+		posts = append(posts, body)
+		return &http.Response{
+			StatusCode: postStatus,
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+		}, nil
+	})
+	service := NewJiraServiceForTest(config, mockClient, zap.NewNop(), instantSleep, execCommand)
+	err := service.UpdateTicketStatus("TEST-1", "Done")
+	return posts, err
+}
+
+func TestUpdateTicketStatus_TransitionFields(t *testing.T) {
+	config := newTestJiraConfig()
+	config.Jira.TransitionFields = map[string]models.JiraTransitionFields{
+		"done": {
+			Resolution: "Done",
+			Comment:    "Fixed by the bot.",
+			Fields:     map[string]any{"customfield_1": "x"},
+		},
+	}
+
+	posts, err := updateStatusRequests(t, config, transitionScreen, http.StatusNoContent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posts) != 1 {
+		t.Fatalf("got %d POST requests, want 1", len(posts))
+	}
+	fields, _ := posts[0]["fields"].(map[string]any)
+	resolution, _ := fields["resolution"].(map[string]any)
+	if resolution["name"] != "Done" {
+		t.Errorf("resolution = %v, want Done", fields["resolution"])
+	}
+	if _, ok := fields["customfield_1"]; ok {
+		t.Error("field not on the transition screen was sent")
+	}
+	update, _ := posts[0]["update"].(map[string]any)
+	if comments, _ := update["comment"].([]any); len(comments) != 1 {
+		t.Errorf("update.comment = %v, want one comment", update["comment"])
+	}
+}
+
+func TestUpdateTicketStatus_CommentWithoutScreen(t *testing.T) {
+	config := newTestJiraConfig()
+	config.Jira.TransitionFields = map[string]models.JiraTransitionFields{
+		"Done": {Resolution: "Done", Comment: "Fixed by the bot."},
+	}
+	transitions := `{"transitions": [{"id": "31", "name": "Done", "to": {"name": "Done"}}]}`
+
+	posts, err := updateStatusRequests(t, config, transitions, http.StatusNoContent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posts) != 2 {
+		t.Fatalf("got %d POST requests, want the transition and a comment", len(posts))
+	}
+	if _, ok := posts[0]["fields"]; ok {
+		t.Errorf("transition without a screen sent fields: %v", posts[0]["fields"])
+	}
+	if _, ok := posts[1]["body"]; !ok {
+		t.Errorf("second request is not a comment: %v", posts[1])
+	}
+}
+
+func TestUpdateTicketStatus_RejectedNamesRequiredFields(t *testing.T) {
+	_, err := updateStatusRequests(t, newTestJiraConfig(), transitionScreen, http.StatusBadRequest)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "Resolution") || !strings.Contains(err.Error(), "jira.transition_fields.done") {
+		t.Errorf("error %q does not point at the missing resolution", err)
+	}
+}
+
 // TestAddComment tests adding a comment to a ticket
 func TestAddComment(t *testing.T) {
 	testCases := []struct {
@@ -653,6 +768,69 @@ func TestAddComment(t *testing.T) {
 	}
 }
 
+func TestAddComment_SecureCommentVisibility(t *testing.T) {
+	testCases := []struct {
+		name           string
+		security       string
+		wantVisibility map[string]string
+	}{
+		{name: "secure ticket", security: `{"id":"1","name":"Embargoed"}`, wantVisibility: map[string]string{"type": "role", "value": "Developers"}},
+		{name: "ticket without security level", security: "null"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var payload struct {
+				Visibility map[string]string `json:"visibility"`
+			}
+			mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodPost {
+					if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+						t.Errorf("decode comment payload: %v", err)
+					}
+					return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader(`{"id":"1"}`))}, nil
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"key":"SEC-1","fields":{"security":` + tc.security + `},"names":{}}`)),
+				}, nil
+			})
+
+			config := newTestJiraConfig()
+			config.Jira.SecureCommentVisibility = models.JiraCommentVisibility{Type: "role", Value: "Developers"}
+			service := NewJiraServiceForTest(config, mockClient, zap.NewNop(), instantSleep, execCommand)
+
+			if err := service.AddComment("SEC-1", "Processing started"); err != nil {
+				t.Fatalf("AddComment: %v", err)
+			}
+			if !maps.Equal(payload.Visibility, tc.wantVisibility) {
+				t.Errorf("visibility = %v, want %v", payload.Visibility, tc.wantVisibility)
+			}
+		})
+	}
+
+	t.Run("security level lookup fails", func(t *testing.T) {
+		posted := false
+		mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodPost {
+				posted = true
+			}
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		})
+
+		config := newTestJiraConfig()
+		config.Jira.SecureCommentVisibility = models.JiraCommentVisibility{Type: "group", Value: "security-team"}
+		service := NewJiraServiceForTest(config, mockClient, zap.NewNop(), instantSleep, execCommand)
+
+		if err := service.AddComment("SEC-1", "Processing started"); err == nil {
+			t.Fatal("expected error")
+		}
+		if posted {
+			t.Error("comment was posted without knowing the security level")
+		}
+	})
+}
+
 // TestUpdateTicketField tests updating a ticket field
 func TestUpdateTicketField(t *testing.T) {
 	testCases := []struct {
@@ -778,6 +956,46 @@ func TestGetFieldIDByName(t *testing.T) {
 	}
 }
 
+// TestGetFieldIDByName_CacheExpiry tests when the field list is fetched again
+func TestGetFieldIDByName_CacheExpiry(t *testing.T) {
+	fetches := 0
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		fetches++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[{"id":"customfield_10001","name":"Custom Field"}]`))),
+		}, nil
+	})
+	service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	lookup := func(name string, wantFetches int) {
+		t.Helper()
+		_, _ = service.GetFieldIDByName(name)
+		if fetches != wantFetches {
+			t.Errorf("after looking up %q: fetches = %d, want %d", name, fetches, wantFetches)
+		}
+	}
+
+	if err := service.WarmFieldCache(); err != nil {
+		t.Fatalf("WarmFieldCache: %v", err)
+	}
+	lookup("Custom Field", 1)
+	// A fresh cache is trusted for unknown names.
+	lookup("New Field", 1)
+
+	now = now.Add(fieldCacheMissRefresh)
+	lookup("Custom Field", 1)
+	lookup("New Field", 2)
+
+	now = now.Add(fieldCacheTTL)
+	lookup("Custom Field", 3)
+
+	service.InvalidateFieldCache()
+	lookup("Custom Field", 4)
+}
+
 // TestUpdateTicketFieldByName tests updating a field by name
 func TestUpdateTicketFieldByName(t *testing.T) {
 	testCases := []struct {
@@ -947,6 +1165,134 @@ func TestSearchTickets(t *testing.T) {
 // TestSearchTickets_RequestedFields verifies that SearchTickets uses the
 // correct endpoint, requests all fields needed by mapFieldsToWorkItem,
 // and does not send the deprecated startAt parameter.
+func TestSearchTicketPages_RequestsTicketFields(t *testing.T) {
+	var requested []any
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		var body map[string]any
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		requested, _ = body["fields"].([]any)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`{"isLast":true,"issues":[
+				{"key":"TEST-1","fields":{"customfield_10100":{"id":"7","name":"Embargoed"}}},
+				{"key":"TEST-2","fields":{"security":{"name":"Internal"},"customfield_10100":{"name":"Embargoed"}}},
+				{"key":"TEST-3","fields":{}}
+			]}`)),
+		}, nil
+	})
+	service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+	// A loaded field cache naming a custom security field adds it to
+	// the search; the lookup itself makes no request.
+	service.fieldNameToID = map[string]string{"Security": "customfield_10100", "Summary": "summary"}
+
+	resp, err := service.SearchTickets("project = TEST")
+	if err != nil {
+		t.Fatalf("SearchTickets: %v", err)
+	}
+
+	for _, field := range []string{"comment", "attachment", "security", "customfield_10100"} {
+		if !slices.Contains(requested, any(field)) {
+			t.Errorf("requested fields %v lack %q", requested, field)
+		}
+	}
+	var levels []string
+	for _, issue := range resp.Issues {
+		level := ""
+		if issue.Fields.Security != nil {
+			level = issue.Fields.Security.Name
+		}
+		levels = append(levels, level)
+	}
+	if want := []string{"Embargoed", "Internal", ""}; !slices.Equal(levels, want) {
+		t.Errorf("security levels = %q, want %q", levels, want)
+	}
+}
+
+func TestSearchTicketPages_FollowsNextPageToken(t *testing.T) {
+	pages := map[string]string{
+		"":       `{"issues":[{"key":"TEST-1"},{"key":"TEST-2"}],"nextPageToken":"page-2","isLast":false}`,
+		"page-2": `{"issues":[{"key":"TEST-3"}],"nextPageToken":"page-3","isLast":false}`,
+		"page-3": `{"issues":[{"key":"TEST-4"}],"isLast":true}`,
+	}
+
+	tests := []struct {
+		name       string
+		limit      int
+		wantKeys   []string
+		wantTokens []string
+	}{
+		{name: "no cap", wantKeys: []string{"TEST-1", "TEST-2", "TEST-3", "TEST-4"}, wantTokens: []string{"", "page-2", "page-3"}},
+		{name: "cap truncates", limit: 3, wantKeys: []string{"TEST-1", "TEST-2", "TEST-3"}, wantTokens: []string{"", "page-2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokens []string
+			mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				var body map[string]any
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode request body: %v", err)
+				}
+				token, _ := body["nextPageToken"].(string)
+				tokens = append(tokens, token)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(pages[token])),
+				}, nil
+			})
+
+			cfg := newTestJiraConfig()
+			cfg.Jira.MaxSearchResults = tt.limit
+			service := NewJiraServiceForTest(cfg, mockClient, zap.NewNop(), instantSleep, execCommand)
+
+			var pageCount int
+			var keys []string
+			err := service.SearchTicketPages("project = TEST", func(page *models.JiraSearchResponse) error {
+				pageCount++
+				for _, issue := range page.Issues {
+					keys = append(keys, issue.Key)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if !slices.Equal(tokens, tt.wantTokens) {
+				t.Errorf("page tokens = %q, want %q", tokens, tt.wantTokens)
+			}
+			if pageCount != len(tt.wantTokens) {
+				t.Errorf("pages delivered = %d, want %d", pageCount, len(tt.wantTokens))
+			}
+		})
+	}
+}
+
+func TestSearchTicketPages_CallbackErrorStopsSearch(t *testing.T) {
+	requests := 0
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"issues":[{"key":"TEST-1"}],"nextPageToken":"next","isLast":false}`)),
+		}, nil
+	})
+	service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+
+	stop := errors.New("stop")
+	err := service.SearchTicketPages("project = TEST", func(*models.JiraSearchResponse) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("err = %v, want callback error", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
 func TestSearchTickets_RequestedFields(t *testing.T) {
 	requiredFields := []string{
 		"summary", "description", "status", "issuetype",
@@ -1269,6 +1615,59 @@ func TestDoOperation_UsesBasicAuth(t *testing.T) {
 	}
 }
 
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "reachable", statusCode: http.StatusOK},
+		{name: "unauthorized", statusCode: http.StatusUnauthorized, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedURL string
+			client := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				capturedURL = req.URL.String()
+				return &http.Response{
+					StatusCode: tt.statusCode,
+					Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+				}, nil
+			})
+
+			svc := NewJiraServiceForTest(newTestJiraConfig(), client, zap.NewNop(), instantSleep)
+			err := svc.Ping(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if capturedURL != "https://jira.example.com/rest/api/3/myself" {
+				t.Errorf("URL = %q, want /rest/api/3/myself", capturedURL)
+			}
+		})
+	}
+}
+
+func TestPing_StopsWaitingWhenContextDone(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": {"30"}},
+			Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+		}, nil
+	})
+	// Waiting for the Retry-After delay never ends; only ctx does.
+	neverSleep := func(time.Duration) <-chan time.Time { return nil }
+	svc := NewJiraServiceForTest(newTestJiraConfig(), client, zap.NewNop(), neverSleep)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := svc.Ping(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Ping() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
 func TestIsTextContentType(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1560,3 +1959,219 @@ func TestAddLabel(t *testing.T) {
 		}
 	})
 }
+
+func TestAddWorklog(t *testing.T) {
+	var gotPath string
+	var gotBody []byte
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotBody, _ = io.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		}, nil
+	})
+
+	service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+	started := time.Date(2025, 7, 7, 8, 29, 32, 0, time.UTC)
+	if err := service.AddWorklog("TEST-1", started, 90*time.Second, "ai-bot: implementation"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/rest/api/3/issue/TEST-1/worklog" {
+		t.Errorf("path = %q, want /rest/api/3/issue/TEST-1/worklog", gotPath)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("failed to parse payload: %v", err)
+	}
+	if payload["timeSpentSeconds"] != float64(120) {
+		t.Errorf("timeSpentSeconds = %v, want 120 (rounded up to whole minutes)", payload["timeSpentSeconds"])
+	}
+	if payload["started"] != "2025-07-07T08:29:32.000+0000" {
+		t.Errorf("started = %v, want Jira timestamp", payload["started"])
+	}
+}
+
+func TestLinkIssues(t *testing.T) {
+	var gotPath string
+	var gotBody []byte
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotBody, _ = io.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewReader([]byte(``))),
+		}, nil
+	})
+
+	service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+	if err := service.LinkIssues("TEST-2", "TEST-1", "Relates"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotPath != "/rest/api/3/issueLink" {
+		t.Errorf("path = %q, want /rest/api/3/issueLink", gotPath)
+	}
+	var payload struct {
+		Type         struct{ Name string }
+		InwardIssue  struct{ Key string }
+		OutwardIssue struct{ Key string }
+	}
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("failed to parse payload: %v", err)
+	}
+	if payload.Type.Name != "Relates" || payload.InwardIssue.Key != "TEST-2" || payload.OutwardIssue.Key != "TEST-1" {
+		t.Errorf("payload = %s, want a Relates link from TEST-2 to TEST-1", gotBody)
+	}
+}
+
+func TestAssignTicket(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		want      string
+	}{
+		{name: "assign", accountID: "abc-123", want: `{"accountId":"abc-123"}`},
+		{name: "unassign", accountID: "", want: `{"accountId":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotPath string
+			var gotBody []byte
+			mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				gotMethod, gotPath = req.Method, req.URL.Path
+				gotBody, _ = io.ReadAll(req.Body)
+				return &http.Response{
+					StatusCode: http.StatusNoContent,
+					Body:       io.NopCloser(bytes.NewReader([]byte(``))),
+				}, nil
+			})
+
+			service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+			if err := service.AssignTicket("TEST-1", tt.accountID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotMethod != http.MethodPut || gotPath != "/rest/api/3/issue/TEST-1/assignee" {
+				t.Errorf("request = %s %s, want PUT /rest/api/3/issue/TEST-1/assignee", gotMethod, gotPath)
+			}
+			if string(gotBody) != tt.want {
+				t.Errorf("payload = %s, want %s", gotBody, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddAttachment(t *testing.T) {
+	var gotReq *http.Request
+	var gotFile, gotName string
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		gotReq = req
+		file, header, err := req.FormFile("file")
+		if err != nil {
+			t.Fatalf("FormFile: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		gotFile, gotName = string(data), header.Filename
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+		}, nil
+	})
+
+	service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+	if err := service.AddAttachment("TEST-1", "transcript.txt", []byte("hello")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotReq.URL.Path != "/rest/api/3/issue/TEST-1/attachments" {
+		t.Errorf("path = %q, want /rest/api/3/issue/TEST-1/attachments", gotReq.URL.Path)
+	}
+	if gotReq.Header.Get("X-Atlassian-Token") != "no-check" {
+		t.Error("missing X-Atlassian-Token: no-check header")
+	}
+	if gotName != "transcript.txt" || gotFile != "hello" {
+		t.Errorf("file = (%q, %q), want (transcript.txt, hello)", gotName, gotFile)
+	}
+}
+
+type stubSecretResolver map[string]string
+
+func (r stubSecretResolver) Resolve(value string) string {
+	if v, ok := r[value]; ok {
+		return v
+	}
+	return value
+}
+
+func TestSetSecretResolver_ResolvesAPITokenPerRequest(t *testing.T) {
+	var capturedAuth string
+	client := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		capturedAuth = req.Header.Get("Authorization")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+		}, nil
+	})
+
+	cfg := newTestJiraConfig()
+	cfg.Jira.APIToken = "vault://secret/data/jira#api_token"
+	resolver := stubSecretResolver{cfg.Jira.APIToken: "v1"}
+
+	svc := NewJiraServiceForTest(cfg, client, zap.NewNop(), instantSleep)
+	svc.SetSecretResolver(resolver)
+
+	for _, token := range []string{"v1", "v2"} {
+		resolver[cfg.Jira.APIToken] = token
+		if err := svc.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Jira.Username+":"+token))
+		if capturedAuth != want {
+			t.Errorf("Authorization = %q, want %q", capturedAuth, want)
+		}
+	}
+}
+
+func TestJiraService_CircuitBreaker(t *testing.T) {
+	requests := 0
+	status := http.StatusServiceUnavailable
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+	})
+	now := time.Unix(0, 0)
+	breaker, err := circuit.NewBreaker(circuit.Config{
+		Threshold: 2,
+		Cooldown:  time.Minute,
+		Clock:     func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewJiraServiceForTest(newTestJiraConfig(), mockClient, zap.NewNop(), instantSleep, execCommand)
+	service.SetCircuitBreaker(breaker)
+
+	for range 2 {
+		if _, err := service.GetTicket("TEST-1"); err == nil || errors.Is(err, circuit.ErrOpen) {
+			t.Fatalf("err = %v, want the server error", err)
+		}
+	}
+	if _, err := service.GetTicket("TEST-1"); !errors.Is(err, circuit.ErrOpen) {
+		t.Errorf("err = %v, want circuit.ErrOpen", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (none while open)", requests)
+	}
+
+	// A rejected request after the cooldown still shows that Jira is up.
+	now = now.Add(time.Minute)
+	status = http.StatusNotFound
+	if _, err := service.GetTicket("TEST-1"); err == nil || errors.Is(err, circuit.ErrOpen) {
+		t.Fatalf("err = %v, want the not found error", err)
+	}
+	if got := breaker.Stats().State; got != circuit.StateClosed {
+		t.Errorf("state = %s, want %s", got, circuit.StateClosed)
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"jira-ai-issue-solver/models"
+)
+
+// NewHTTPTransport returns a transport for outbound connections that
+// applies cfg: requests go through cfg.ProxyURL (or the proxy named by
+// the environment), servers are verified against the system roots plus
+// cfg.CABundle, and cfg.ClientCert is presented when a server asks for
+// a client certificate. With an empty cfg it behaves like
+// http.DefaultTransport.
+func NewHTTPTransport(cfg models.NetworkConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxy := (&httpproxy.Config{
+			HTTPProxy:  cfg.ProxyURL,
+			HTTPSProxy: cfg.ProxyURL,
+			NoProxy:    cfg.NoProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+
+	if cfg.CABundle == "" && cfg.ClientCert == "" {
+		return transport, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("read network.ca_bundle: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, errors.New("network.ca_bundle contains no PEM certificates")
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load network.client_cert: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// gitNetworkEnv returns the environment variables that make git
+// connect as cfg configures. Empty fields add nothing, leaving git's
+// own defaults and the inherited environment in effect.
+func gitNetworkEnv(cfg models.NetworkConfig) []string {
+	var env []string
+	if cfg.ProxyURL != "" {
+		// curl reads the lowercase names; set both for helpers that
+		// read the uppercase ones.
+		env = append(env,
+			"https_proxy="+cfg.ProxyURL, "HTTPS_PROXY="+cfg.ProxyURL,
+			"http_proxy="+cfg.ProxyURL,
+			"no_proxy="+cfg.NoProxy, "NO_PROXY="+cfg.NoProxy)
+	}
+	if cfg.CABundle != "" {
+		env = append(env, "GIT_SSL_CAINFO="+cfg.CABundle)
+	}
+	if cfg.ClientCert != "" {
+		env = append(env, "GIT_SSL_CERT="+cfg.ClientCert, "GIT_SSL_KEY="+cfg.ClientKey)
+	}
+	return env
+}
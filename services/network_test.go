@@ -0,0 +1,124 @@
+package services
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestNewHTTPTransport_Proxy(t *testing.T) {
+	transport, err := NewHTTPTransport(models.NetworkConfig{
+		ProxyURL: "http://proxy.corp.example.com:3128",
+		NoProxy:  "jira.internal.example.com,.corp.example.com",
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport: %v", err)
+	}
+
+	tests := []struct {
+		url       string
+		wantProxy string
+	}{
+		{"https://api.github.com/repos", "http://proxy.corp.example.com:3128"},
+		{"https://jira.internal.example.com/rest/api/3/myself", ""},
+		{"https://git.corp.example.com/", ""},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		proxy, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("Proxy(%s): %v", tt.url, err)
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != tt.wantProxy {
+			t.Errorf("Proxy(%s) = %q, want %q", tt.url, got, tt.wantProxy)
+		}
+	}
+}
+
+func TestNewHTTPTransport_TrustsCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(bundle, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		cfg     models.NetworkConfig
+		wantErr bool
+	}{
+		{name: "system roots only", cfg: models.NetworkConfig{}, wantErr: true},
+		{name: "with CA bundle", cfg: models.NetworkConfig{CABundle: bundle}, wantErr: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := NewHTTPTransport(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewHTTPTransport: %v", err)
+			}
+			resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GET error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewHTTPTransport_InvalidFiles(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "not.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cfg := range []models.NetworkConfig{
+		{CABundle: notPEM},
+		{CABundle: filepath.Join(t.TempDir(), "missing.pem")},
+		{ClientCert: notPEM, ClientKey: notPEM},
+	} {
+		if _, err := NewHTTPTransport(cfg); err == nil {
+			t.Errorf("NewHTTPTransport(%+v) succeeded, want error", cfg)
+		}
+	}
+}
+
+func TestGitNetworkEnv(t *testing.T) {
+	if env := gitNetworkEnv(models.NetworkConfig{}); len(env) != 0 {
+		t.Errorf("empty config env = %q, want none", env)
+	}
+
+	env := gitNetworkEnv(models.NetworkConfig{
+		ProxyURL:   "http://proxy:3128",
+		NoProxy:    ".internal",
+		CABundle:   "/etc/ca.pem",
+		ClientCert: "/etc/client.pem",
+		ClientKey:  "/etc/client-key.pem",
+	})
+	for _, want := range []string{
+		"https_proxy=http://proxy:3128",
+		"no_proxy=.internal",
+		"GIT_SSL_CAINFO=/etc/ca.pem",
+		"GIT_SSL_CERT=/etc/client.pem",
+		"GIT_SSL_KEY=/etc/client-key.pem",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env %q lacks %q", env, want)
+		}
+	}
+}
@@ -81,15 +81,18 @@ func TestSyncWithRemote_ExecutesCorrectCommands(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if len(calls) != 2 {
-		t.Fatalf("expected 2 commands, got %d: %v", len(calls), calls)
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 commands, got %d: %v", len(calls), calls)
 	}
 
-	// First command: git fetch origin
-	assertCommand(t, calls[0], "git", "fetch", "origin")
+	// First command: look up origin to choose credentials
+	assertCommand(t, calls[0], "git", "remote", "get-url", "origin")
 
-	// Second command: git reset --hard origin/<branch>
-	assertCommand(t, calls[1], "git", "reset", "--hard", "origin/feature-branch")
+	// Second command: git fetch origin
+	assertCommand(t, calls[1], "git", "fetch", "origin")
+
+	// Third command: git reset --hard origin/<branch>
+	assertCommand(t, calls[2], "git", "reset", "--hard", "origin/feature-branch")
 }
 
 func TestSyncWithRemote_FetchFailure(t *testing.T) {
@@ -98,7 +101,7 @@ func TestSyncWithRemote_FetchFailure(t *testing.T) {
 	callCount := 0
 	mockExecutor := func(name string, args ...string) *exec.Cmd {
 		callCount++
-		if callCount == 1 {
+		if callCount == 2 {
 			// Fail the fetch.
 			return exec.Command("false")
 		}
@@ -113,8 +116,8 @@ func TestSyncWithRemote_FetchFailure(t *testing.T) {
 	}
 
 	// Should not proceed to reset.
-	if callCount != 1 {
-		t.Errorf("expected 1 command (fetch only), got %d", callCount)
+	if callCount != 2 {
+		t.Errorf("expected 2 commands (get-url and fetch), got %d", callCount)
 	}
 }
 
@@ -124,7 +127,7 @@ func TestSyncWithRemote_ResetFailure(t *testing.T) {
 	callCount := 0
 	mockExecutor := func(name string, args ...string) *exec.Cmd {
 		callCount++
-		if callCount == 2 {
+		if callCount == 3 {
 			// Fail the reset.
 			return exec.Command("false")
 		}
@@ -138,8 +141,8 @@ func TestSyncWithRemote_ResetFailure(t *testing.T) {
 		t.Fatal("expected error from failed reset, got nil")
 	}
 
-	if callCount != 2 {
-		t.Errorf("expected 2 commands, got %d", callCount)
+	if callCount != 3 {
+		t.Errorf("expected 3 commands, got %d", callCount)
 	}
 }
 
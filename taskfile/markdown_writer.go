@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"jira-ai-issue-solver/models"
 )
@@ -34,6 +35,18 @@ func (w *MarkdownWriter) WriteIssue(workItem models.WorkItem, dir string, attach
 		writeBlockquote(&b, "Ticket description", workItem.Description)
 	}
 
+	if workItem.AcceptanceCriteria != "" {
+		b.WriteString("\n## Acceptance Criteria\n")
+		writeBlockquote(&b, "Acceptance criteria", workItem.AcceptanceCriteria)
+	}
+
+	if len(workItem.Links) > 0 {
+		b.WriteString("\n## Linked Issues\n")
+		for _, l := range workItem.Links {
+			fmt.Fprintf(&b, "- %s %s (%s): %s\n", l.Relation, l.Key, l.Status, l.Summary)
+		}
+	}
+
 	if len(attachmentFiles) > 0 {
 		b.WriteString("\n## Attachments\n")
 		fmt.Fprintf(&b, "The following files are available in `%s/`:\n", AttachmentsDirPath)
@@ -61,6 +74,8 @@ func (w *MarkdownWriter) WriteNewTicketTask(workItem models.WorkItem, dir, overr
 	fmt.Fprintf(&b, "# Task: %s\n\n", workItem.Key)
 	fmt.Fprintf(&b, "## Summary\n%s\n\n", workItem.Summary)
 	fmt.Fprintf(&b, "The full ticket description is in `%s`.\n\n", IssueFilePath)
+	writeAcceptanceCriteria(&b, workItem.AcceptanceCriteria)
+	writeApproach(&b, workItem)
 
 	writeNewTicketInstructions(&b, workItem.HasSecurityLevel())
 
@@ -121,6 +136,8 @@ func (w *MarkdownWriter) WriteMultiRepoNewTicketTask(workItem models.WorkItem, w
 	fmt.Fprintf(&b, "# Task: %s\n\n", workItem.Key)
 	fmt.Fprintf(&b, "## Summary\n%s\n\n", workItem.Summary)
 	fmt.Fprintf(&b, "The full ticket description is in `%s`.\n\n", IssueFilePath)
+	writeAcceptanceCriteria(&b, workItem.AcceptanceCriteria)
+	writeApproach(&b, workItem)
 
 	writeNewTicketInstructions(&b, workItem.HasSecurityLevel())
 
@@ -179,6 +196,49 @@ func (w *MarkdownWriter) WriteMultiRepoFeedbackTask(
 	return writeFile(wsDir, TaskFilePath, b.String())
 }
 
+// writeAcceptanceCriteria writes the ticket's acceptance criteria, if
+// any, as a section of its own so that they are not lost in the
+// description.
+func writeAcceptanceCriteria(b *strings.Builder, criteria string) {
+	if criteria == "" {
+		return
+	}
+	b.WriteString("## Acceptance Criteria\n")
+	b.WriteString("The change is complete only when it meets every one of these criteria.\n\n")
+	writeBlockquote(b, "Acceptance criteria", criteria)
+	b.WriteString("\n")
+}
+
+// defaultApproaches is the built-in guidance for new tickets, keyed by
+// lowercase ticket type. Projects replace it per type with
+// prompt_strategies.
+var defaultApproaches = map[string]string{
+	"bug": "This ticket reports a bug. Before changing code, reproduce it: write\n" +
+		"a test that fails because of the bug. Then fix the cause rather than the\n" +
+		"symptom, and keep the test so that it guards against a regression.\n",
+	"story": "This ticket is a story: new behavior for users. Implement it end to\n" +
+		"end, add tests covering the new behavior, and update the documentation\n" +
+		"that describes it (README, docs, help text, config examples).\n",
+	"task": "This ticket is a task, usually a mechanical change (a rename, a\n" +
+		"dependency or config update, a cleanup). Make exactly the change asked\n" +
+		"for with the smallest diff that does it; do not refactor or change\n" +
+		"behavior beyond it.\n",
+}
+
+// writeApproach writes the approach section for the ticket's type:
+// the project's guidance when set, otherwise the built-in guidance.
+// Types with neither get no section.
+func writeApproach(b *strings.Builder, workItem models.WorkItem) {
+	approach := strings.TrimSpace(workItem.TypeGuidance)
+	if approach == "" {
+		approach = strings.TrimSpace(defaultApproaches[strings.ToLower(workItem.Type)])
+	}
+	if approach == "" {
+		return
+	}
+	fmt.Fprintf(b, "## Approach\n%s\n\n", approach)
+}
+
 // writeNewTicketInstructions writes the standard instructions section
 // for a new ticket task file.
 func writeNewTicketInstructions(b *strings.Builder, hasSecurityLevel bool) {
@@ -187,11 +247,37 @@ func writeNewTicketInstructions(b *strings.Builder, hasSecurityLevel bool) {
 	b.WriteString("whatever build tools this project provides. Fix any issues you find.\n")
 	b.WriteString("Do not push to git -- the system handles that.\n")
 
+	b.WriteString("\nIf the ticket lacks details you need and cannot reasonably infer from the\n")
+	b.WriteString("code or the ticket (e.g., which of several behaviors is wanted), do not\n")
+	fmt.Fprintf(b, "guess: make no changes and write a JSON file to `%s` with\n", NeedsInfoPath)
+	b.WriteString("specific questions for the reporter. Format:\n\n")
+	b.WriteString("```json\n")
+	b.WriteString("{\"questions\": [\"Should expired tokens be refreshed or rejected?\"]}\n")
+	b.WriteString("```\n")
+
 	if hasSecurityLevel {
 		b.WriteString("\nThis ticket has a security level set. Do not include specific\n")
 		b.WriteString("vulnerability details in commit messages, code comments, or any\n")
 		b.WriteString("content that may appear in the public pull request.\n")
+		return
 	}
+
+	b.WriteString("\n## Required Output\n")
+	fmt.Fprintf(b, "When you are done, write a JSON file to `%s` describing your\n", PRSummaryPath)
+	b.WriteString("change for the pull request: what you changed, how you tested it (commands\n")
+	b.WriteString("run, tests added, and anything a reviewer should verify by hand), and how\n")
+	b.WriteString("risky it is (`low`, `medium`, or `high`). Also rate your confidence that the\n")
+	b.WriteString("change is correct and complete, from 0 to 100; be honest, since a\n")
+	b.WriteString("low-confidence change may be held for human review. Format:\n\n")
+	b.WriteString("```json\n")
+	b.WriteString("{\n")
+	b.WriteString("  \"changes\": [\"Added retry with backoff to the webhook client\"],\n")
+	b.WriteString("  \"test_plan\": [\"Added unit tests for the backoff schedule\", \"Ran make test\"],\n")
+	b.WriteString("  \"risk\": \"low\",\n")
+	b.WriteString("  \"risk_notes\": \"Only webhook delivery is affected; retries are capped.\",\n")
+	b.WriteString("  \"confidence\": 85\n")
+	b.WriteString("}\n")
+	b.WriteString("```\n")
 }
 
 // writeFeedbackInstructions writes the standard instructions section
@@ -219,9 +305,19 @@ func writeFeedbackInstructions(b *strings.Builder, hasComments, hasCIFailures bo
 		b.WriteString("```json\n")
 		b.WriteString("[\n")
 		b.WriteString("  {\"comment_id\": 123, \"response\": \"Switched to Optional pattern as suggested.\"},\n")
-		b.WriteString("  {\"comment_id\": 456, \"response\": \"Kept the fallback path — needed for v1 compat.\"}\n")
+		b.WriteString("  {\"comment_id\": 456, \"response\": \"Kept the fallback path — needed for v1 compat.\"},\n")
+		b.WriteString("  {\"comment_id\": 789, \"question\": \"Should the retry limit apply per request or per session?\"}\n")
 		b.WriteString("]\n")
-		b.WriteString("```\n")
+		b.WriteString("```\n\n")
+		b.WriteString("If a comment is ambiguous and you cannot tell what the reviewer wants,\n")
+		b.WriteString("do not guess: make no change for it and write a `question` instead of a\n")
+		b.WriteString("`response`. The question is posted as the reply, and the reviewer's\n")
+		b.WriteString("answer arrives in a later feedback round.\n\n")
+		b.WriteString("If you could not address a comment (for example, the change is out of\n")
+		b.WriteString("scope or you ran out of time), add `\"not_addressed\": true` with the\n")
+		b.WriteString("reason as the `response`. Unaddressed comments are retried in the next\n")
+		b.WriteString("feedback round. Include every comment: one left out of the file is\n")
+		b.WriteString("treated as not addressed.\n")
 	}
 }
 
@@ -450,14 +546,47 @@ func writeGroupedComments(b *strings.Builder, comments []models.PRComment) {
 }
 
 // writeCommentBlockquote writes a single PR comment as a blockquote
-// with author attribution and comment ID.
+// with author attribution, position and comment ID. Outdated comments
+// include the diff hunk they were made on, since their line numbers no
+// longer match the current code.
 func writeCommentBlockquote(b *strings.Builder, c models.PRComment) {
 	if c.Line > 0 {
-		fmt.Fprintf(b, "> [@%s, line %d, comment_id %d]\n", c.Author.Username, c.Line, c.ID)
+		fmt.Fprintf(b, "> [@%s, %s, comment_id %d]\n", c.Author.Username, commentPosition(c), c.ID)
 	} else {
 		fmt.Fprintf(b, "> [@%s, comment_id %d]\n", c.Author.Username, c.ID)
 	}
-	for _, line := range strings.Split(strings.TrimRight(c.Body, "\n"), "\n") {
+	writeQuoted(b, c.Body)
+	if c.Outdated && c.DiffHunk != "" {
+		b.WriteString(">\n> Diff at the time of the comment:\n>\n> ```diff\n")
+		writeQuoted(b, c.DiffHunk)
+		b.WriteString("> ```\n")
+	}
+}
+
+// commentPosition describes where a line comment is anchored, e.g.
+// "line 12", "lines 10-12", or "line 7 (removed code, outdated)".
+func commentPosition(c models.PRComment) string {
+	pos := fmt.Sprintf("line %d", c.Line)
+	if c.StartLine > 0 && c.StartLine != c.Line {
+		pos = fmt.Sprintf("lines %d-%d", c.StartLine, c.Line)
+	}
+
+	var notes []string
+	if c.Side == "LEFT" {
+		notes = append(notes, "removed code")
+	}
+	if c.Outdated {
+		notes = append(notes, "outdated")
+	}
+	if len(notes) > 0 {
+		pos += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return pos
+}
+
+// writeQuoted writes text as blockquote lines.
+func writeQuoted(b *strings.Builder, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
 		if line == "" {
 			b.WriteString(">\n")
 		} else {
@@ -517,6 +646,111 @@ func writeTaskFile(dir, content string) error {
 	return writeFile(dir, TaskFilePath, content)
 }
 
+func (w *MarkdownWriter) WriteSelfReviewTask(workItem models.WorkItem, dir, overrideInstructions string) error {
+	var b strings.Builder
+
+	writeSelfReviewBody(&b, workItem, "Run `git status` and `git diff`")
+
+	if err := appendInstructions(&b, dir, overrideInstructions, 2); err != nil {
+		return err
+	}
+
+	return writeTaskFile(dir, b.String())
+}
+
+func (w *MarkdownWriter) WriteMultiRepoSelfReviewTask(workItem models.WorkItem, wsDir string, repos []RepoContext) error {
+	var b strings.Builder
+
+	writeSelfReviewBody(&b, workItem, "Run `git status` and `git diff` in each repository directory")
+
+	for _, repo := range repos {
+		fmt.Fprintf(&b, "\n## Repository: %s\n", repo.Name)
+		if err := appendInstructions(&b, repo.Dir, repo.OverrideInstructions, 3); err != nil {
+			return err
+		}
+	}
+
+	return writeTaskFile(wsDir, b.String())
+}
+
+// writeSelfReviewBody writes the context, instructions, and required
+// output of a self-review task file. showChanges tells the AI how to
+// see the changes.
+func writeSelfReviewBody(b *strings.Builder, workItem models.WorkItem, showChanges string) {
+	fmt.Fprintf(b, "# Task: Review Changes for %s\n\n", workItem.Key)
+	fmt.Fprintf(b, "## Summary\n%s\n\n", workItem.Summary)
+	fmt.Fprintf(b, "The full ticket description, including any acceptance criteria, is in `%s`.\n", IssueFilePath)
+	b.WriteString("A previous session implemented this ticket; its changes are uncommitted\n")
+	fmt.Fprintf(b, "in this workspace. %s to see them.\n\n", showChanges)
+	writeAcceptanceCriteria(b, workItem.AcceptanceCriteria)
+
+	b.WriteString("## Instructions\n")
+	b.WriteString("Review the changes as a careful code reviewer would. Check that they\n")
+	b.WriteString("satisfy the ticket and its acceptance criteria, follow the Project\n")
+	b.WriteString("Instructions below, include tests where appropriate, and contain nothing\n")
+	b.WriteString("unrelated to the ticket. Then choose one verdict:\n\n")
+	b.WriteString("- **approve**: the changes are correct and complete. Do not modify anything.\n")
+	b.WriteString("- **amend**: you found problems you can fix. Fix them in the workspace and\n")
+	b.WriteString("  validate that the code compiles and tests pass.\n")
+	b.WriteString("- **abort**: the changes are wrong, unsafe, or do not address the ticket,\n")
+	b.WriteString("  and you cannot fix them. Do not modify anything.\n\n")
+	b.WriteString("Do not push to git -- the system handles that.\n")
+
+	b.WriteString("\n## Required Output\n")
+	fmt.Fprintf(b, "Write a JSON file to `%s` with your verdict and a brief\n", SelfReviewPath)
+	b.WriteString("summary of what you checked and found (and, when amending, what you\n")
+	b.WriteString("changed). Format:\n\n")
+	b.WriteString("```json\n")
+	b.WriteString("{\"verdict\": \"amend\", \"summary\": \"Added the missing nil check on retry; tests pass.\"}\n")
+	b.WriteString("```\n")
+}
+
+func (w *MarkdownWriter) WriteRegressionTestTask(workItem models.WorkItem, dir, overrideInstructions string) error {
+	var b strings.Builder
+
+	writeRegressionTestBody(&b, workItem, "Run `git status` and `git diff`")
+
+	if err := appendInstructions(&b, dir, overrideInstructions, 2); err != nil {
+		return err
+	}
+
+	return writeTaskFile(dir, b.String())
+}
+
+func (w *MarkdownWriter) WriteMultiRepoRegressionTestTask(workItem models.WorkItem, wsDir string, repos []RepoContext) error {
+	var b strings.Builder
+
+	writeRegressionTestBody(&b, workItem, "Run `git status` and `git diff` in each repository directory")
+
+	for _, repo := range repos {
+		fmt.Fprintf(&b, "\n## Repository: %s\n", repo.Name)
+		if err := appendInstructions(&b, repo.Dir, repo.OverrideInstructions, 3); err != nil {
+			return err
+		}
+	}
+
+	return writeTaskFile(wsDir, b.String())
+}
+
+// writeRegressionTestBody writes the context and instructions of a
+// regression test task file. showChanges tells the AI how to see the
+// fix.
+func writeRegressionTestBody(b *strings.Builder, workItem models.WorkItem, showChanges string) {
+	fmt.Fprintf(b, "# Task: Add a Regression Test for %s\n\n", workItem.Key)
+	fmt.Fprintf(b, "## Summary\n%s\n\n", workItem.Summary)
+	fmt.Fprintf(b, "The full bug report is in `%s`.\n", IssueFilePath)
+	b.WriteString("A previous session fixed this bug; its changes are uncommitted in this\n")
+	fmt.Fprintf(b, "workspace but include no test. %s to see them.\n\n", showChanges)
+
+	b.WriteString("## Instructions\n")
+	b.WriteString("Add a regression test for the bug: a test that fails without the fix\n")
+	b.WriteString("and passes with it. Put it where the project keeps tests for the\n")
+	b.WriteString("changed code and follow the style of the existing tests.\n\n")
+	b.WriteString("Do not change the fix itself unless the test shows it is wrong. Run the\n")
+	b.WriteString("new test and the tests around it and make sure they pass.\n\n")
+	b.WriteString("Do not push to git -- the system handles that.\n")
+}
+
 func (w *MarkdownWriter) WriteMergeConflictTask(
 	prDetails models.PRDetails,
 	conflictFiles []string,
@@ -567,6 +801,183 @@ func (w *MarkdownWriter) WriteMultiRepoMergeConflictTask(
 	return writeTaskFile(wsDir, b.String())
 }
 
+func (w *MarkdownWriter) WriteBackportConflictTask(
+	prDetails models.PRDetails,
+	targetBranch string,
+	conflictFiles []string,
+	dir, overrideInstructions string,
+) error {
+	var b strings.Builder
+
+	b.WriteString("# Task: Resolve Backport Conflicts\n\n")
+	fmt.Fprintf(&b, "The original ticket is described in `%s`.\n\n", IssueFilePath)
+
+	fmt.Fprintf(&b, "## PR Context\n")
+	fmt.Fprintf(&b, "PR #%d: %s\n", prDetails.Number, prDetails.Title)
+	fmt.Fprintf(&b, "Merged into: %s\n", prDetails.BaseBranch)
+	fmt.Fprintf(&b, "Backporting to: %s\n\n", targetBranch)
+
+	b.WriteString("## Conflict Details\n\n")
+	fmt.Fprintf(&b, "The changes of the PR above have been cherry-picked onto `%s`, but ", targetBranch)
+	b.WriteString("there are conflicts that need to be resolved.\n\n")
+
+	if len(conflictFiles) > 0 {
+		b.WriteString("### Conflicted Files\n\n")
+		sort.Strings(conflictFiles)
+		for _, f := range conflictFiles {
+			fmt.Fprintf(&b, "- `%s`\n", f)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Instructions\n\n")
+	b.WriteString("1. Resolve all conflict markers (`<<<<<<<`, `=======`, `>>>>>>>`) in the files listed above.\n")
+	fmt.Fprintf(&b, "2. Apply the PR's change as it would have been written for `%s`: keep the release branch's code where the PR did not change it.\n", targetBranch)
+	b.WriteString("3. After resolving conflicts, run the validation commands in the Project Instructions section below to verify the resolution is correct.\n")
+	b.WriteString("4. Do not introduce any new features or changes beyond what is needed to resolve the conflicts. ")
+	b.WriteString("If the change cannot be applied without larger changes, leave the conflicts unresolved.\n\n")
+
+	if err := appendInstructions(&b, dir, overrideInstructions, 2); err != nil {
+		return err
+	}
+
+	return writeTaskFile(dir, b.String())
+}
+
+func (w *MarkdownWriter) WriteTriageTask(workItem models.WorkItem, dir string, opts TriageOptions) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Task: Triage %s\n\n", workItem.Key)
+	fmt.Fprintf(&b, "## Summary\n%s\n\n", workItem.Summary)
+	fmt.Fprintf(&b, "The full ticket description is in `%s`.\n\n", IssueFilePath)
+
+	b.WriteString("## Instructions\n\n")
+	b.WriteString("Do not implement the ticket and do not change any files in the repository.\n")
+	b.WriteString("Read the ticket and explore the code only as far as needed to propose:\n\n")
+	b.WriteString("1. The component the ticket belongs to.\n")
+	b.WriteString("2. Its severity: how badly it affects users.\n")
+	b.WriteString("3. Any of the existing tickets listed below that it duplicates.\n")
+	b.WriteString("4. A rough effort estimate, such as \"2 hours\", \"1 day\", or \"1 week\".\n\n")
+
+	if len(opts.Components) > 0 {
+		b.WriteString("## Components\n\nChoose one of:\n")
+		for _, c := range opts.Components {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(opts.Severities) > 0 {
+		b.WriteString("## Severities\n\nChoose one of:\n")
+		for _, s := range opts.Severities {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(opts.Candidates) > 0 {
+		b.WriteString("## Existing Tickets\n\n")
+		b.WriteString("Recently updated tickets of the project. List only those that describe the same problem or request.\n")
+		for _, c := range opts.Candidates {
+			fmt.Fprintf(&b, "- %s (%s): %s\n", c.Key, c.Status, c.Summary)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Required Output\n")
+	fmt.Fprintf(&b, "Write a JSON file to `%s` with your proposals and a short rationale for\n", TriagePath)
+	b.WriteString("them. Leave a proposal empty if you cannot make one with reasonable confidence. Format:\n\n")
+	b.WriteString("```json\n")
+	b.WriteString("{\n")
+	b.WriteString("  \"component\": \"backend\",\n")
+	b.WriteString("  \"severity\": \"Major\",\n")
+	b.WriteString("  \"duplicates\": [\"PROJ-12\"],\n")
+	b.WriteString("  \"estimate\": \"1 day\",\n")
+	b.WriteString("  \"rationale\": \"The stack trace points at the token refresh in the API server.\"\n")
+	b.WriteString("}\n")
+	b.WriteString("```\n")
+
+	return writeTaskFile(dir, b.String())
+}
+
+// WriteReleaseNoteTask generates a task file for writing a merged
+// ticket's release note.
+func (w *MarkdownWriter) WriteReleaseNoteTask(workItem models.WorkItem, prDetails models.PRDetails, changelogFile, dir string) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Task: Release Note for %s\n\n", workItem.Key)
+	fmt.Fprintf(&b, "## Summary\n%s\n\n", workItem.Summary)
+	fmt.Fprintf(&b, "The full ticket description is in `%s`.\n\n", IssueFilePath)
+
+	b.WriteString("## Merged Change\n\n")
+	fmt.Fprintf(&b, "The change for this ticket was merged into `%s` in PR #%d: %s\n", prDetails.BaseBranch, prDetails.Number, prDetails.Title)
+	if prDetails.MergeCommitSHA != "" {
+		fmt.Fprintf(&b, "Inspect it with `git diff %[1]s~1 %[1]s`.\n", prDetails.MergeCommitSHA)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Instructions\n\n")
+	b.WriteString("Write a release note for the users of this project: one to three sentences in plain\n")
+	b.WriteString("language saying what changed for them, such as a fixed problem or a new capability.\n")
+	b.WriteString("Do not describe how it was implemented, and do not mention the ticket or PR.\n\n")
+	if changelogFile != "" {
+		fmt.Fprintf(&b, "Add the note to `%s`, following the file's existing format and placing it where\n", changelogFile)
+		b.WriteString("unreleased changes go. Create the file if it does not exist. Do not change any other file.\n\n")
+	} else {
+		b.WriteString("Do not change any files in the repository.\n\n")
+	}
+
+	b.WriteString("## Required Output\n")
+	fmt.Fprintf(&b, "Write a JSON file to `%s` with the note. Format:\n\n", ReleaseNotePath)
+	b.WriteString("```json\n")
+	b.WriteString("{\n")
+	b.WriteString("  \"note\": \"Expired login sessions are now renewed automatically instead of signing you out.\"\n")
+	b.WriteString("}\n")
+	b.WriteString("```\n")
+
+	return writeTaskFile(dir, b.String())
+}
+
+// WriteSpikeTask generates a task file for a time-boxed exploration
+// of a ticket.
+func (w *MarkdownWriter) WriteSpikeTask(workItem models.WorkItem, dir string, timeBox time.Duration, overrideInstructions string) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Task: Spike %s\n\n", workItem.Key)
+	fmt.Fprintf(&b, "## Summary\n%s\n\n", workItem.Summary)
+	fmt.Fprintf(&b, "The full ticket description is in `%s`.\n\n", IssueFilePath)
+
+	b.WriteString("## Instructions\n\n")
+	b.WriteString("This is a spike: investigate whether and how the ticket can be done, so that the team can\n")
+	b.WriteString("decide whether to go ahead. Do not implement the ticket and do not change any files in the\n")
+	b.WriteString("repository. You may run commands to explore the code, but leave the working tree clean.\n\n")
+	fmt.Fprintf(&b, "You have %s. The session is stopped when the time is up, so write down your findings\n", formatTimeBox(timeBox))
+	b.WriteString("early and refine them as you go, rather than at the end.\n\n")
+
+	b.WriteString("## Required Output\n")
+	fmt.Fprintf(&b, "Write a Markdown write-up to `%s` covering:\n\n", SpikePath)
+	b.WriteString("1. **Findings**: the code involved and how it works today.\n")
+	b.WriteString("2. **Approach**: how the ticket could be done, with the options and their trade-offs.\n")
+	b.WriteString("3. **Feasibility**: whether it can be done, its risks, and a rough effort estimate.\n")
+	b.WriteString("4. **Open questions**: what must be answered before the work starts.\n\n")
+	b.WriteString("Keep it short enough to read in a few minutes. It is posted to the ticket as written.\n\n")
+
+	if err := appendInstructions(&b, dir, overrideInstructions, 2); err != nil {
+		return err
+	}
+
+	return writeTaskFile(dir, b.String())
+}
+
+// formatTimeBox renders a spike's time box, e.g. "15 minutes".
+func formatTimeBox(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
 func writeMergeConflictBody(b *strings.Builder, conflictFiles []string) {
 	b.WriteString("## Conflict Details\n\n")
 	b.WriteString("The target branch has been merged into this PR branch, but ")
@@ -157,3 +157,41 @@ func TestWriteMultiRepoMergeConflictTask(t *testing.T) {
 		t.Error("task file should list conflict files")
 	}
 }
+
+func TestWriteBackportConflictTask(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	pr := models.PRDetails{
+		Number:     42,
+		Title:      "PROJ-1: Fix auth flow",
+		Branch:     "ai-bot/PROJ-1",
+		BaseBranch: "main",
+	}
+
+	if err := w.WriteBackportConflictTask(pr, "release-4.17", []string{"pkg/auth.go", "cmd/server.go"}, dir, "Run make test"); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, taskfile.TaskFilePath)) //nolint:gosec // test reads from t.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := string(content)
+	checks := []string{
+		"# Task: Resolve Backport Conflicts",
+		"PR #42: PROJ-1: Fix auth flow",
+		"Merged into: main",
+		"Backporting to: release-4.17",
+		"- `cmd/server.go`\n- `pkg/auth.go`",
+		"conflict markers",
+		"leave the conflicts unresolved",
+		"Run make test",
+	}
+	for _, want := range checks {
+		if !strings.Contains(body, want) {
+			t.Errorf("task file should contain %q", want)
+		}
+	}
+}
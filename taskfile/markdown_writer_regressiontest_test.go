@@ -0,0 +1,78 @@
+package taskfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestWriteRegressionTestTask(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	item := models.WorkItem{Key: "PROJ-1", Type: "Bug", Summary: "Expired tokens are accepted"}
+
+	if err := w.WriteRegressionTestTask(item, dir, "Run make test before finishing."); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, taskfile.TaskFilePath)) //nolint:gosec // test reads from t.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := string(content)
+
+	checks := []string{
+		"# Task: Add a Regression Test for PROJ-1",
+		"Expired tokens are accepted",
+		taskfile.IssueFilePath,
+		"Run `git status` and `git diff` to see them.",
+		"fails without the fix",
+		"## Project Instructions\nRun make test before finishing.",
+	}
+
+	for _, want := range checks {
+		if !strings.Contains(body, want) {
+			t.Errorf("task file should contain %q", want)
+		}
+	}
+}
+
+func TestWriteMultiRepoRegressionTestTask(t *testing.T) {
+	wsDir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	item := models.WorkItem{Key: "PROJ-1", Type: "Bug", Summary: "Expired tokens are accepted"}
+	repos := []taskfile.RepoContext{
+		{Name: "api", Dir: filepath.Join(wsDir, "api"), OverrideInstructions: "Use go test ./..."},
+		{Name: "web", Dir: filepath.Join(wsDir, "web")},
+	}
+
+	if err := w.WriteMultiRepoRegressionTestTask(item, wsDir, repos); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(wsDir, taskfile.TaskFilePath)) //nolint:gosec // test reads from t.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := string(content)
+
+	checks := []string{
+		"in each repository directory",
+		"## Repository: api\n\n### Project Instructions\nUse go test ./...",
+		"## Repository: web",
+	}
+
+	for _, want := range checks {
+		if !strings.Contains(body, want) {
+			t.Errorf("task file should contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestWriteReleaseNoteTask(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	item := models.WorkItem{Key: "PROJ-1", Summary: "Login fails after token expiry"}
+	pr := models.PRDetails{Number: 42, Title: "PROJ-1: Refresh expired tokens", BaseBranch: "main", MergeCommitSHA: "abc123"}
+
+	if err := w.WriteReleaseNoteTask(item, pr, "CHANGELOG.md", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readTaskFile(t, dir)
+	for _, want := range []string{
+		"# Task: Release Note for PROJ-1",
+		"Login fails after token expiry",
+		taskfile.IssueFilePath,
+		"merged into `main` in PR #42: PROJ-1: Refresh expired tokens",
+		"`git diff abc123~1 abc123`",
+		"Add the note to `CHANGELOG.md`",
+		"Do not change any other file.",
+		"`" + taskfile.ReleaseNotePath + "`",
+	} {
+		assertContains(t, content, want)
+	}
+}
+
+func TestWriteReleaseNoteTask_WithoutChangelog(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	pr := models.PRDetails{Number: 42, BaseBranch: "main"}
+	if err := w.WriteReleaseNoteTask(models.WorkItem{Key: "PROJ-1", Summary: "Add dark mode"}, pr, "", dir); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readTaskFile(t, dir)
+	assertContains(t, content, "Do not change any files in the repository.")
+	assertNotContains(t, content, "git diff")
+	assertNotContains(t, content, "Add the note to")
+}
@@ -0,0 +1,81 @@
+package taskfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestWriteSelfReviewTask(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	item := models.WorkItem{Key: "PROJ-1", Summary: "Reject expired tokens"}
+
+	if err := w.WriteSelfReviewTask(item, dir, "Run make lint before finishing."); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, taskfile.TaskFilePath)) //nolint:gosec // test reads from t.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := string(content)
+
+	checks := []string{
+		"# Task: Review Changes for PROJ-1",
+		"Reject expired tokens",
+		taskfile.IssueFilePath,
+		"Run `git status` and `git diff` to see them.",
+		"- **approve**",
+		"- **amend**",
+		"- **abort**",
+		taskfile.SelfReviewPath,
+		"## Project Instructions\nRun make lint before finishing.",
+	}
+
+	for _, want := range checks {
+		if !strings.Contains(body, want) {
+			t.Errorf("task file should contain %q", want)
+		}
+	}
+}
+
+func TestWriteMultiRepoSelfReviewTask(t *testing.T) {
+	wsDir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	item := models.WorkItem{Key: "PROJ-1", Summary: "Reject expired tokens"}
+	repos := []taskfile.RepoContext{
+		{Name: "api", Dir: filepath.Join(wsDir, "api"), OverrideInstructions: "Use go test ./..."},
+		{Name: "web", Dir: filepath.Join(wsDir, "web")},
+	}
+
+	if err := w.WriteMultiRepoSelfReviewTask(item, wsDir, repos); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(wsDir, taskfile.TaskFilePath)) //nolint:gosec // test reads from t.TempDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := string(content)
+
+	checks := []string{
+		"in each repository directory",
+		"## Repository: api\n\n### Project Instructions\nUse go test ./...",
+		"## Repository: web",
+	}
+
+	for _, want := range checks {
+		if !strings.Contains(body, want) {
+			t.Errorf("task file should contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package taskfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestWriteSpikeTask(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	item := models.WorkItem{Key: "PROJ-1", Summary: "Evaluate moving the cache to Redis"}
+
+	if err := w.WriteSpikeTask(item, dir, 20*time.Minute, "Run make test."); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readTaskFile(t, dir)
+	for _, want := range []string{
+		"# Task: Spike PROJ-1",
+		"Evaluate moving the cache to Redis",
+		taskfile.IssueFilePath,
+		"do not change any files",
+		"You have 20 minutes.",
+		"`" + taskfile.SpikePath + "`",
+		"**Open questions**",
+		"## Project Instructions\nRun make test.",
+	} {
+		assertContains(t, content, want)
+	}
+}
+
+func TestWriteSpikeTask_ReadsRepoInstructions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, taskfile.InstructionsPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("Use the Makefile."), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w := taskfile.NewMarkdownWriter()
+	if err := w.WriteSpikeTask(models.WorkItem{Key: "PROJ-1"}, dir, time.Minute, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readTaskFile(t, dir)
+	assertContains(t, content, "You have 1 minute.")
+	assertContains(t, content, "## Project Instructions\nUse the Makefile.")
+}
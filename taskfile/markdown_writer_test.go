@@ -110,6 +110,27 @@ func TestWriteIssue_WithAttachments(t *testing.T) {
 	assertContains(t, content, "- `config.yaml`")
 }
 
+func TestWriteIssue_WithLinks(t *testing.T) {
+	dir := t.TempDir()
+	writer := taskfile.NewMarkdownWriter()
+
+	workItem := models.WorkItem{
+		Key:     "PROJ-402",
+		Summary: "Add retry to client",
+		Links: []models.IssueLink{
+			{Type: "Relates", Relation: "relates to", Key: "PROJ-9", Summary: "Client timeouts", Status: "Open"},
+		},
+	}
+
+	if err := writer.WriteIssue(workItem, dir, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := readIssueFile(t, dir)
+	assertContains(t, content, "## Linked Issues")
+	assertContains(t, content, "- relates to PROJ-9 (Open): Client timeouts")
+}
+
 func TestWriteIssue_NoAttachments_NoSection(t *testing.T) {
 	dir := t.TempDir()
 	writer := taskfile.NewMarkdownWriter()
@@ -336,6 +357,8 @@ func TestWriteNewTicketTask_SecurityLevel(t *testing.T) {
 	assertContains(t, content, "security level set")
 	assertContains(t, content, "Do not include specific")
 	assertContains(t, content, "vulnerability details")
+	// The PR body is redacted, so no PR summary is requested.
+	assertNotContains(t, content, taskfile.PRSummaryPath)
 }
 
 func TestWriteNewTicketTask_NoSecurityNote_WhenNoSecurityLevel(t *testing.T) {
@@ -356,6 +379,112 @@ func TestWriteNewTicketTask_NoSecurityNote_WhenNoSecurityLevel(t *testing.T) {
 	assertNotContains(t, content, "security level")
 }
 
+func TestWriteNewTicketTask_RequestsPRSummary(t *testing.T) {
+	dir := t.TempDir()
+	writer := taskfile.NewMarkdownWriter()
+
+	workItem := models.WorkItem{Key: "PROJ-100", Summary: "Add feature"}
+	if err := writer.WriteNewTicketTask(workItem, dir, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := readTaskFile(t, dir)
+	assertContains(t, content, "## Required Output")
+	assertContains(t, content, "`"+taskfile.PRSummaryPath+"`")
+	assertContains(t, content, "\"confidence\": 85")
+	assertContains(t, content, `"test_plan"`)
+	assertContains(t, content, `"risk": "low"`)
+}
+
+func TestWriteNewTicketTask_AllowsQuestions(t *testing.T) {
+	dir := t.TempDir()
+	writer := taskfile.NewMarkdownWriter()
+
+	workItem := models.WorkItem{Key: "PROJ-100", Summary: "Add feature", SecurityLevel: "Embargoed"}
+	if err := writer.WriteNewTicketTask(workItem, dir, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := readTaskFile(t, dir)
+	assertContains(t, content, "`"+taskfile.NeedsInfoPath+"`")
+	assertContains(t, content, `{"questions": [`)
+}
+
+func TestWriteNewTicketTask_IncludesAcceptanceCriteria(t *testing.T) {
+	dir := t.TempDir()
+	writer := taskfile.NewMarkdownWriter()
+
+	workItem := models.WorkItem{
+		Key:                "PROJ-100",
+		Summary:            "Add feature",
+		AcceptanceCriteria: "- Works offline\n- Has tests",
+	}
+	if err := writer.WriteNewTicketTask(workItem, dir, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := writer.WriteIssue(workItem, dir, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, content := range []string{readTaskFile(t, dir), readIssueFile(t, dir)} {
+		assertContains(t, content, "## Acceptance Criteria")
+		assertContains(t, content, "> [Acceptance criteria]")
+		assertContains(t, content, "> - Works offline")
+		assertContains(t, content, "> - Has tests")
+	}
+}
+
+func TestWriteNewTicketTask_NoAcceptanceCriteria_NoSection(t *testing.T) {
+	dir := t.TempDir()
+	writer := taskfile.NewMarkdownWriter()
+
+	workItem := models.WorkItem{Key: "PROJ-100", Summary: "Add feature"}
+	if err := writer.WriteNewTicketTask(workItem, dir, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertNotContains(t, readTaskFile(t, dir), "## Acceptance Criteria")
+}
+
+func TestWriteNewTicketTask_ApproachByType(t *testing.T) {
+	tests := []struct {
+		name     string
+		workItem models.WorkItem
+		want     string
+	}{
+		{name: "bug", workItem: models.WorkItem{Type: "Bug"}, want: "reproduce it: write\na test that fails"},
+		{name: "story", workItem: models.WorkItem{Type: "Story"}, want: "update the documentation"},
+		{name: "task", workItem: models.WorkItem{Type: "task"}, want: "smallest diff"},
+		{name: "project guidance replaces built-in", workItem: models.WorkItem{Type: "Bug", TypeGuidance: "Attach a core dump analysis."}, want: "## Approach\nAttach a core dump analysis.\n"},
+		{name: "project guidance for custom type", workItem: models.WorkItem{Type: "Spike", TypeGuidance: "Write findings to docs/spikes/."}, want: "Write findings to docs/spikes/."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			workItem := tt.workItem
+			workItem.Key, workItem.Summary = "PROJ-100", "Do it"
+			if err := taskfile.NewMarkdownWriter().WriteNewTicketTask(workItem, dir, "", ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			content := readTaskFile(t, dir)
+			assertContains(t, content, "## Approach")
+			assertContains(t, content, tt.want)
+			if tt.workItem.TypeGuidance != "" {
+				assertNotContains(t, content, "reproduce it")
+			}
+		})
+	}
+}
+
+func TestWriteNewTicketTask_NoApproachForUnknownType(t *testing.T) {
+	dir := t.TempDir()
+	workItem := models.WorkItem{Key: "PROJ-100", Summary: "Investigate", Type: "Spike"}
+	if err := taskfile.NewMarkdownWriter().WriteNewTicketTask(workItem, dir, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertNotContains(t, readTaskFile(t, dir), "## Approach")
+}
+
 func TestWriteNewTicketTask_CreatesDirectory(t *testing.T) {
 	dir := t.TempDir()
 	writer := taskfile.NewMarkdownWriter()
@@ -596,6 +725,32 @@ func TestWriteFeedbackTask_MultilineCommentBody(t *testing.T) {
 	assertContains(t, content, "> Second paragraph.")
 }
 
+func TestWriteFeedbackTask_CommentPosition(t *testing.T) {
+	dir := t.TempDir()
+	writer := taskfile.NewMarkdownWriter()
+
+	pr := models.PRDetails{Number: 10, Title: "PR", Branch: "b"}
+	newComments := []models.PRComment{
+		{ID: 1, Author: models.Author{Username: "r1"}, Body: "Range", FilePath: "a.go", StartLine: 10, Line: 12},
+		{ID: 2, Author: models.Author{Username: "r1"}, Body: "Removed", FilePath: "b.go", Line: 7, Side: "LEFT"},
+		{
+			ID: 3, Author: models.Author{Username: "r2"}, Body: "Stale", FilePath: "c.go", Line: 4, Outdated: true,
+			DiffHunk: "@@ -1,4 +1,4 @@\n-old()\n+new()",
+		},
+	}
+
+	if err := writer.WriteFeedbackTask(pr, newComments, nil, nil, dir, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := readTaskFile(t, dir)
+
+	assertContains(t, content, "> [@r1, lines 10-12, comment_id 1]")
+	assertContains(t, content, "> [@r1, line 7 (removed code), comment_id 2]")
+	assertContains(t, content, "> [@r2, line 4 (outdated), comment_id 3]")
+	assertContains(t, content, "> ```diff\n> @@ -1,4 +1,4 @@\n> -old()\n> +new()\n> ```")
+}
+
 func TestWriteFeedbackTask_CreatesDirectory(t *testing.T) {
 	dir := t.TempDir()
 	writer := taskfile.NewMarkdownWriter()
@@ -661,6 +816,7 @@ func TestWriteFeedbackTask_RequiredOutputSection(t *testing.T) {
 	assertContains(t, content, taskfile.CommentResponsesPath)
 	assertContains(t, content, "comment_id")
 	assertContains(t, content, "\"response\"")
+	assertContains(t, content, "\"question\"")
 
 	// Required Output should appear after Instructions.
 	idxInstr := strings.Index(content, "## Instructions")
@@ -0,0 +1,52 @@
+package taskfile_test
+
+import (
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/taskfile"
+)
+
+func TestWriteTriageTask(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	item := models.WorkItem{Key: "PROJ-1", Summary: "Login fails after token expiry"}
+	opts := taskfile.TriageOptions{
+		Components: []string{"backend", "frontend"},
+		Severities: []string{"Major", "Minor"},
+		Candidates: []models.WorkItem{{Key: "PROJ-7", Status: "Open", Summary: "Expired tokens are not refreshed"}},
+	}
+
+	if err := w.WriteTriageTask(item, dir, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readTaskFile(t, dir)
+	for _, want := range []string{
+		"# Task: Triage PROJ-1",
+		"Login fails after token expiry",
+		taskfile.IssueFilePath,
+		"do not change any files",
+		"## Components\n\nChoose one of:\n- backend\n- frontend\n",
+		"## Severities\n\nChoose one of:\n- Major\n- Minor\n",
+		"- PROJ-7 (Open): Expired tokens are not refreshed\n",
+		"`" + taskfile.TriagePath + "`",
+	} {
+		assertContains(t, content, want)
+	}
+}
+
+func TestWriteTriageTask_OmitsEmptyChoices(t *testing.T) {
+	dir := t.TempDir()
+
+	w := taskfile.NewMarkdownWriter()
+	if err := w.WriteTriageTask(models.WorkItem{Key: "PROJ-1", Summary: "Add dark mode"}, dir, taskfile.TriageOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readTaskFile(t, dir)
+	for _, section := range []string{"## Components", "## Severities", "## Existing Tickets"} {
+		assertNotContains(t, content, section)
+	}
+}
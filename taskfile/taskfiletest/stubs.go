@@ -2,6 +2,8 @@
 package taskfiletest
 
 import (
+	"time"
+
 	"jira-ai-issue-solver/models"
 	"jira-ai-issue-solver/taskfile"
 )
@@ -13,13 +15,21 @@ var _ taskfile.Writer = (*Stub)(nil)
 // Set the corresponding Func field to control each method's behavior.
 // When a Func field is nil, the method returns nil.
 type Stub struct {
-	WriteIssueFunc                      func(workItem models.WorkItem, dir string, attachmentFiles []string, comments []models.Comment) error
-	WriteNewTicketTaskFunc              func(workItem models.WorkItem, dir, overrideInstructions, overrideWorkflow string) error
-	WriteFeedbackTaskFunc               func(prDetails models.PRDetails, newComments, addressedComments []models.PRComment, ciFailures []models.CheckRunFailure, dir, overrideInstructions, overrideWorkflow string) error
-	WriteMultiRepoNewTicketTaskFunc     func(workItem models.WorkItem, wsDir string, repos []taskfile.RepoContext) error
-	WriteMultiRepoFeedbackTaskFunc      func(prDetails models.PRDetails, newComments, addressedComments []models.PRComment, ciFailures []models.CheckRunFailure, wsDir string, repos []taskfile.RepoContext) error
-	WriteMergeConflictTaskFunc          func(prDetails models.PRDetails, conflictFiles []string, dir, overrideInstructions string) error
-	WriteMultiRepoMergeConflictTaskFunc func(prDetails models.PRDetails, conflictFiles []string, wsDir string, repos []taskfile.RepoContext) error
+	WriteIssueFunc                       func(workItem models.WorkItem, dir string, attachmentFiles []string, comments []models.Comment) error
+	WriteNewTicketTaskFunc               func(workItem models.WorkItem, dir, overrideInstructions, overrideWorkflow string) error
+	WriteFeedbackTaskFunc                func(prDetails models.PRDetails, newComments, addressedComments []models.PRComment, ciFailures []models.CheckRunFailure, dir, overrideInstructions, overrideWorkflow string) error
+	WriteMultiRepoNewTicketTaskFunc      func(workItem models.WorkItem, wsDir string, repos []taskfile.RepoContext) error
+	WriteMultiRepoFeedbackTaskFunc       func(prDetails models.PRDetails, newComments, addressedComments []models.PRComment, ciFailures []models.CheckRunFailure, wsDir string, repos []taskfile.RepoContext) error
+	WriteSelfReviewTaskFunc              func(workItem models.WorkItem, dir, overrideInstructions string) error
+	WriteMultiRepoSelfReviewTaskFunc     func(workItem models.WorkItem, wsDir string, repos []taskfile.RepoContext) error
+	WriteRegressionTestTaskFunc          func(workItem models.WorkItem, dir, overrideInstructions string) error
+	WriteMultiRepoRegressionTestTaskFunc func(workItem models.WorkItem, wsDir string, repos []taskfile.RepoContext) error
+	WriteMergeConflictTaskFunc           func(prDetails models.PRDetails, conflictFiles []string, dir, overrideInstructions string) error
+	WriteMultiRepoMergeConflictTaskFunc  func(prDetails models.PRDetails, conflictFiles []string, wsDir string, repos []taskfile.RepoContext) error
+	WriteBackportConflictTaskFunc        func(prDetails models.PRDetails, targetBranch string, conflictFiles []string, dir, overrideInstructions string) error
+	WriteTriageTaskFunc                  func(workItem models.WorkItem, dir string, opts taskfile.TriageOptions) error
+	WriteReleaseNoteTaskFunc             func(workItem models.WorkItem, prDetails models.PRDetails, changelogFile, dir string) error
+	WriteSpikeTaskFunc                   func(workItem models.WorkItem, dir string, timeBox time.Duration, overrideInstructions string) error
 }
 
 func (s *Stub) WriteIssue(workItem models.WorkItem, dir string, attachmentFiles []string, comments []models.Comment) error {
@@ -57,6 +67,34 @@ func (s *Stub) WriteMultiRepoFeedbackTask(prDetails models.PRDetails, newComment
 	return nil
 }
 
+func (s *Stub) WriteSelfReviewTask(workItem models.WorkItem, dir, overrideInstructions string) error {
+	if s.WriteSelfReviewTaskFunc != nil {
+		return s.WriteSelfReviewTaskFunc(workItem, dir, overrideInstructions)
+	}
+	return nil
+}
+
+func (s *Stub) WriteMultiRepoSelfReviewTask(workItem models.WorkItem, wsDir string, repos []taskfile.RepoContext) error {
+	if s.WriteMultiRepoSelfReviewTaskFunc != nil {
+		return s.WriteMultiRepoSelfReviewTaskFunc(workItem, wsDir, repos)
+	}
+	return nil
+}
+
+func (s *Stub) WriteRegressionTestTask(workItem models.WorkItem, dir, overrideInstructions string) error {
+	if s.WriteRegressionTestTaskFunc != nil {
+		return s.WriteRegressionTestTaskFunc(workItem, dir, overrideInstructions)
+	}
+	return nil
+}
+
+func (s *Stub) WriteMultiRepoRegressionTestTask(workItem models.WorkItem, wsDir string, repos []taskfile.RepoContext) error {
+	if s.WriteMultiRepoRegressionTestTaskFunc != nil {
+		return s.WriteMultiRepoRegressionTestTaskFunc(workItem, wsDir, repos)
+	}
+	return nil
+}
+
 func (s *Stub) WriteMergeConflictTask(prDetails models.PRDetails, conflictFiles []string, dir, overrideInstructions string) error {
 	if s.WriteMergeConflictTaskFunc != nil {
 		return s.WriteMergeConflictTaskFunc(prDetails, conflictFiles, dir, overrideInstructions)
@@ -70,3 +108,31 @@ func (s *Stub) WriteMultiRepoMergeConflictTask(prDetails models.PRDetails, confl
 	}
 	return nil
 }
+
+func (s *Stub) WriteBackportConflictTask(prDetails models.PRDetails, targetBranch string, conflictFiles []string, dir, overrideInstructions string) error {
+	if s.WriteBackportConflictTaskFunc != nil {
+		return s.WriteBackportConflictTaskFunc(prDetails, targetBranch, conflictFiles, dir, overrideInstructions)
+	}
+	return nil
+}
+
+func (s *Stub) WriteTriageTask(workItem models.WorkItem, dir string, opts taskfile.TriageOptions) error {
+	if s.WriteTriageTaskFunc != nil {
+		return s.WriteTriageTaskFunc(workItem, dir, opts)
+	}
+	return nil
+}
+
+func (s *Stub) WriteReleaseNoteTask(workItem models.WorkItem, prDetails models.PRDetails, changelogFile, dir string) error {
+	if s.WriteReleaseNoteTaskFunc != nil {
+		return s.WriteReleaseNoteTaskFunc(workItem, prDetails, changelogFile, dir)
+	}
+	return nil
+}
+
+func (s *Stub) WriteSpikeTask(workItem models.WorkItem, dir string, timeBox time.Duration, overrideInstructions string) error {
+	if s.WriteSpikeTaskFunc != nil {
+		return s.WriteSpikeTaskFunc(workItem, dir, timeBox, overrideInstructions)
+	}
+	return nil
+}
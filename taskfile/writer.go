@@ -21,7 +21,11 @@
 // between bot-authored instructions and user content.
 package taskfile
 
-import "jira-ai-issue-solver/models"
+import (
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
 
 const (
 	// IssueFilePath is the path, relative to the workspace root,
@@ -59,6 +63,17 @@ const (
 	// issue file references them when present.
 	AttachmentsDirPath = ".ai-session/attachments"
 
+	// LinksDirPath is the path, relative to the workspace root,
+	// where the text of pages linked from a new ticket is saved, one
+	// file per link.
+	LinksDirPath = ".ai-session/links"
+
+	// CompanionsDirPath is the path, relative to the workspace root,
+	// under which companion repositories are cloned, one directory
+	// per companion. Like other session files they are never
+	// committed, so the AI may read but not change them.
+	CompanionsDirPath = ".ai-session/companions"
+
 	// NewTicketWorkflowPath is the path, relative to the workspace
 	// root, where optional workflow instructions for new tickets
 	// live. Unlike InstructionsPath (which applies to all task
@@ -94,8 +109,65 @@ const (
 	// the bot falls back to generic replies if the file is missing
 	// or unparseable.
 	CommentResponsesPath = ".ai-session/comment-responses.json"
+
+	// SelfReviewPath is the path, relative to the workspace root,
+	// where the AI writes its verdict after reviewing the changes of
+	// a new-ticket session. The verdict is a JSON object with a
+	// "verdict" of "approve", "amend", or "abort" and a "summary" of
+	// the review.
+	SelfReviewPath = ".ai-session/self-review.json"
+
+	// PRSummaryPath is the path, relative to the workspace root,
+	// where the AI describes a new-ticket change for the pull
+	// request: a JSON object listing the "changes" made and the
+	// "test_plan", and rating the "risk" (low, medium, or high) with
+	// optional "risk_notes". The bot renders it into the PR body in
+	// place of the ticket description. Not requested for
+	// security-level tickets, whose PR bodies are redacted.
+	PRSummaryPath = ".ai-session/pr-summary.json"
+
+	// NeedsInfoPath is the path, relative to the workspace root,
+	// where the AI asks for the details an under-specified ticket
+	// lacks instead of guessing: a JSON object with a list of
+	// "questions". The bot posts them on the ticket and solves the
+	// ticket again once someone replies.
+	NeedsInfoPath = ".ai-session/needs-info.json"
+
+	// TriagePath is the path, relative to the workspace root, where
+	// the AI writes its proposals for a triaged ticket: a JSON object
+	// with the "component", "severity", "duplicates", "estimate", and
+	// a "rationale" for them. The bot writes them into the project's
+	// triage fields.
+	TriagePath = ".ai-session/triage.json"
+
+	// ReleaseNotePath is the path, relative to the workspace root,
+	// where the AI writes the release note for a merged ticket: a
+	// JSON object with the "note". The bot writes it into the
+	// project's release note field.
+	ReleaseNotePath = ".ai-session/release-note.json"
+
+	// SpikePath is the path, relative to the workspace root, where
+	// the AI writes the Markdown write-up of a spike: what it found,
+	// whether and how the ticket can be done, and the open questions.
+	// The bot posts it to the ticket.
+	SpikePath = ".ai-session/spike.md"
 )
 
+// TriageOptions lists the choices a triage task offers the AI.
+type TriageOptions struct {
+	// Components are the names of the project's components. The AI
+	// proposes one of them.
+	Components []string
+
+	// Severities are the allowed severity values. Empty lets the AI
+	// choose freely.
+	Severities []string
+
+	// Candidates are other tickets of the project that the triaged
+	// ticket may duplicate.
+	Candidates []models.WorkItem
+}
+
 // RepoContext describes a repository within a multi-repo workspace.
 // The writer uses Dir to read repo-level .ai-bot/ config files.
 // Override fields (from the repo's profile in the config) take
@@ -170,6 +242,35 @@ type Writer interface {
 		ciFailures []models.CheckRunFailure,
 		wsDir string, repos []RepoContext) error
 
+	// WriteSelfReviewTask generates a task file asking the AI to
+	// review the uncommitted changes of a new-ticket session against
+	// the ticket and the project instructions, fix what it can, and
+	// write its verdict to SelfReviewPath. The file is written to
+	// <dir>/.ai-session/task.md. overrideInstructions takes
+	// precedence over .ai-bot/instructions.md.
+	WriteSelfReviewTask(workItem models.WorkItem, dir, overrideInstructions string) error
+
+	// WriteMultiRepoSelfReviewTask generates a self-review task file
+	// for a multi-repo workspace. Per-repo instruction sections use
+	// profile overrides when set, falling back to .ai-bot/ config
+	// files in each repo. The task file is written to
+	// <wsDir>/.ai-session/task.md.
+	WriteMultiRepoSelfReviewTask(workItem models.WorkItem, wsDir string, repos []RepoContext) error
+
+	// WriteRegressionTestTask generates a task file asking the AI to
+	// add a regression test for the uncommitted bug fix of a
+	// new-ticket session. The file is written to
+	// <dir>/.ai-session/task.md. overrideInstructions takes
+	// precedence over .ai-bot/instructions.md.
+	WriteRegressionTestTask(workItem models.WorkItem, dir, overrideInstructions string) error
+
+	// WriteMultiRepoRegressionTestTask generates a regression test
+	// task file for a multi-repo workspace. Per-repo instruction
+	// sections use profile overrides when set, falling back to
+	// .ai-bot/ config files in each repo. The task file is written to
+	// <wsDir>/.ai-session/task.md.
+	WriteMultiRepoRegressionTestTask(workItem models.WorkItem, wsDir string, repos []RepoContext) error
+
 	// WriteMergeConflictTask generates a task file for AI-assisted
 	// merge conflict resolution. conflictFiles lists the paths with
 	// unresolved conflicts (from git status). The file is written to
@@ -184,4 +285,33 @@ type Writer interface {
 	WriteMultiRepoMergeConflictTask(prDetails models.PRDetails,
 		conflictFiles []string,
 		wsDir string, repos []RepoContext) error
+
+	// WriteBackportConflictTask generates a task file for resolving
+	// the conflicts left by cherry-picking a merged PR onto
+	// targetBranch. conflictFiles lists the paths with unresolved
+	// conflicts. The file is written to <dir>/.ai-bot/task.md.
+	WriteBackportConflictTask(prDetails models.PRDetails,
+		targetBranch string, conflictFiles []string,
+		dir, overrideInstructions string) error
+
+	// WriteTriageTask generates a task file asking the AI to propose
+	// the ticket's component, severity, possible duplicates, and
+	// effort without changing any code, and to write them to
+	// TriagePath. The file is written to <dir>/.ai-session/task.md.
+	WriteTriageTask(workItem models.WorkItem, dir string, opts TriageOptions) error
+
+	// WriteReleaseNoteTask generates a task file asking the AI to
+	// write a user-facing release note for the ticket from the change
+	// merged in prDetails, to ReleaseNotePath. When changelogFile is
+	// not empty, the AI also adds the note to that file and changes
+	// nothing else. The file is written to <dir>/.ai-session/task.md.
+	WriteReleaseNoteTask(workItem models.WorkItem, prDetails models.PRDetails,
+		changelogFile, dir string) error
+
+	// WriteSpikeTask generates a task file asking the AI to explore
+	// the ticket's feasibility within timeBox, without changing any
+	// files, and to write its findings to SpikePath. The file is
+	// written to <dir>/.ai-session/task.md. overrideInstructions
+	// takes precedence over .ai-bot/instructions.md.
+	WriteSpikeTask(workItem models.WorkItem, dir string, timeBox time.Duration, overrideInstructions string) error
 }
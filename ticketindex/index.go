@@ -0,0 +1,330 @@
+// Package ticketindex remembers the tickets the bot has worked on and
+// finds new tickets that likely describe the same problem.
+//
+// An [Index] keeps the words of each processed ticket's summary and
+// description, persisted to a JSON file so that it survives restarts.
+// [Index.Similar] compares a new ticket against the indexed tickets of
+// the same project by TF-IDF cosine similarity, with summary words
+// weighted above description words, and returns those scoring at
+// least the configured threshold. Entries older than the retention
+// period are dropped.
+package ticketindex
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+)
+
+const (
+	// summaryWeight is how many times a summary word counts
+	// compared to a description word.
+	summaryWeight = 2
+
+	// maxDescriptionRunes bounds the part of a description that is
+	// indexed; the start of a long description usually states the
+	// problem, the rest is logs and detail.
+	maxDescriptionRunes = 4000
+)
+
+// Config holds construction parameters for [Index].
+type Config struct {
+	// Path is the JSON file the index is stored in.
+	Path string
+
+	// Retention is how long a ticket stays in the index after it was
+	// last added. Must be positive.
+	Retention time.Duration
+
+	// Threshold is the similarity, between 0 and 1, at which a ticket
+	// counts as a likely duplicate. Must be greater than 0 and at
+	// most 1.
+	Threshold float64
+
+	// MaxMatches is the number of likely duplicates [Index.Similar]
+	// returns. Must be positive.
+	MaxMatches int
+}
+
+// Match is an indexed ticket similar to the one looked up.
+type Match struct {
+	// Key is the indexed ticket's key.
+	Key string
+
+	// Summary is the indexed ticket's summary.
+	Summary string
+
+	// Score is the similarity between 0 and 1.
+	Score float64
+}
+
+// entry is the on-disk representation of an indexed ticket.
+type entry struct {
+	Key     string         `json:"key"`
+	Project string         `json:"project"`
+	Summary string         `json:"summary"`
+	Terms   map[string]int `json:"terms"`
+	Added   time.Time      `json:"added"`
+}
+
+// Index holds the recently processed tickets. It is safe for
+// concurrent use.
+type Index struct {
+	cfg     Config
+	mu      sync.Mutex
+	entries []entry
+	clock   func() time.Time
+	logger  *zap.Logger
+}
+
+// New creates an Index stored at cfg.Path, loading the tickets already
+// stored there. A missing file starts an empty index; an unreadable
+// one is logged and replaced on the next [Index.Add].
+func New(cfg Config, logger *zap.Logger) (*Index, error) {
+	return NewWithClock(cfg, time.Now, logger)
+}
+
+// NewWithClock is like [New] but accepts a custom clock function for
+// testing.
+func NewWithClock(cfg Config, clock func() time.Time, logger *zap.Logger) (*Index, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("ticket index path must not be empty")
+	}
+	if cfg.Retention <= 0 {
+		return nil, errors.New("ticket index retention must be positive")
+	}
+	if cfg.Threshold <= 0 || cfg.Threshold > 1 {
+		return nil, errors.New("ticket index threshold must be greater than 0 and at most 1")
+	}
+	if cfg.MaxMatches <= 0 {
+		return nil, errors.New("ticket index max matches must be positive")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("create ticket index directory: %w", err)
+	}
+
+	idx := &Index{cfg: cfg, clock: clock, logger: logger}
+	entries, err := load(cfg.Path)
+	switch {
+	case err == nil:
+		idx.entries = entries
+	case !errors.Is(err, os.ErrNotExist):
+		logger.Warn("Discarding unreadable ticket index",
+			zap.String("path", cfg.Path), zap.Error(err))
+	}
+	return idx, nil
+}
+
+// Add indexes item, replacing an earlier entry for the same ticket,
+// drops entries past the retention period, and saves the index.
+// Tickets with a security level are not indexed, so that their
+// summaries are never quoted on other tickets.
+func (idx *Index) Add(item models.WorkItem) error {
+	if item.HasSecurityLevel() {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries = slices.DeleteFunc(idx.entries, func(e entry) bool {
+		return e.Key == item.Key
+	})
+	idx.entries = append(idx.entries, entry{
+		Key:     item.Key,
+		Project: project(item),
+		Summary: item.Summary,
+		Terms:   ticketTerms(item),
+		Added:   idx.clock(),
+	})
+	idx.prune()
+	if err := save(idx.cfg.Path, idx.entries); err != nil {
+		return fmt.Errorf("save ticket index: %w", err)
+	}
+	return nil
+}
+
+// Similar returns up to MaxMatches indexed tickets of item's project,
+// other than item itself, whose similarity to item reaches the
+// threshold, most similar first. Words found in many of the project's
+// tickets count for less than rare ones.
+func (idx *Index) Similar(item models.WorkItem) []Match {
+	terms := ticketTerms(item)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.prune()
+	proj := project(item)
+	var candidates []entry
+	for _, e := range idx.entries {
+		if e.Project == proj && e.Key != item.Key {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// The ticket looked up counts as a document, so that words only
+	// it contains do not divide by zero.
+	docFreq := make(map[string]int)
+	for t := range terms {
+		docFreq[t]++
+	}
+	for _, e := range candidates {
+		for t := range e.Terms {
+			docFreq[t]++
+		}
+	}
+	n := float64(len(candidates) + 1)
+	weigh := func(counts map[string]int) map[string]float64 {
+		v := make(map[string]float64, len(counts))
+		for t, c := range counts {
+			v[t] = float64(c) * math.Log(1+n/float64(docFreq[t]))
+		}
+		return v
+	}
+
+	query := weigh(terms)
+	var matches []Match
+	for _, e := range candidates {
+		score := cosine(query, weigh(e.Terms))
+		if score >= idx.cfg.Threshold {
+			matches = append(matches, Match{Key: e.Key, Summary: e.Summary, Score: score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if len(matches) > idx.cfg.MaxMatches {
+		matches = matches[:idx.cfg.MaxMatches]
+	}
+	return matches
+}
+
+// prune drops entries older than the retention period. Must be called
+// with idx.mu held.
+func (idx *Index) prune() {
+	cutoff := idx.clock().Add(-idx.cfg.Retention)
+	idx.entries = slices.DeleteFunc(idx.entries, func(e entry) bool {
+		return e.Added.Before(cutoff)
+	})
+}
+
+// cosine returns the cosine similarity of two term vectors.
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for t, w := range a {
+		dot += w * b[t]
+		normA += w * w
+	}
+	for _, w := range b {
+		normB += w * w
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// project returns the item's project key, falling back to the prefix
+// of its ticket key.
+func project(item models.WorkItem) string {
+	if item.ProjectKey != "" {
+		return item.ProjectKey
+	}
+	p, _, _ := strings.Cut(item.Key, "-")
+	return p
+}
+
+// ticketTerms counts the words of item's summary and the start of its
+// description worth comparing.
+func ticketTerms(item models.WorkItem) map[string]int {
+	terms := make(map[string]int)
+	for _, w := range words(item.Summary) {
+		terms[w] += summaryWeight
+	}
+	desc := []rune(item.Description)
+	if len(desc) > maxDescriptionRunes {
+		desc = desc[:maxDescriptionRunes]
+	}
+	for _, w := range words(string(desc)) {
+		terms[w]++
+	}
+	return terms
+}
+
+// stopWords are common words in ticket text that say nothing about
+// the problem a ticket describes.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true,
+	"with": true, "from": true, "when": true, "should": true, "not": true,
+	"are": true, "was": true, "but": true, "have": true, "has": true,
+	"can": true, "will": true, "would": true, "into": true, "there": true,
+	"which": true, "all": true, "any": true, "also": true, "use": true,
+	"need": true, "needs": true, "new": true, "our": true, "you": true,
+	"its": true, "what": true, "does": true, "instead": true, "them": true,
+	"been": true, "being": true, "than": true, "then": true, "only": true,
+}
+
+// words splits text into lowercase words at non-alphanumeric
+// characters, leaving out stop words and words shorter than three
+// characters.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, w := range fields {
+		if len([]rune(w)) >= 3 && !stopWords[w] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func load(path string) ([]entry, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from configuration
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// save writes entries to path through a temporary file, so that a
+// crash mid-write does not leave a truncated index.
+func save(path string, entries []entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,142 @@
+package ticketindex_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/ticketindex"
+)
+
+func newIndex(t *testing.T, path string, clock func() time.Time) *ticketindex.Index {
+	t.Helper()
+	idx, err := ticketindex.NewWithClock(ticketindex.Config{
+		Path:       path,
+		Retention:  30 * 24 * time.Hour,
+		Threshold:  0.5,
+		MaxMatches: 3,
+	}, clock, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idx
+}
+
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+var (
+	tokenRefresh = models.WorkItem{
+		Key:         "PROJ-1",
+		Summary:     "Expired access tokens are not refreshed",
+		Description: "After an hour the dashboard logs users out because the refresh token request fails.",
+	}
+	darkMode = models.WorkItem{
+		Key:         "PROJ-2",
+		Summary:     "Add a dark mode theme to the settings page",
+		Description: "Users want a dark color scheme.",
+	}
+	sameProblem = models.WorkItem{
+		Key:         "PROJ-3",
+		Summary:     "Access tokens not refreshed after expiry",
+		Description: "Users are logged out of the dashboard when the refresh token request fails.",
+	}
+)
+
+func TestSimilar_FindsDuplicates(t *testing.T) {
+	idx := newIndex(t, filepath.Join(t.TempDir(), "tickets.json"), time.Now)
+	for _, item := range []models.WorkItem{tokenRefresh, darkMode} {
+		if err := idx.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches := idx.Similar(sameProblem)
+	if len(matches) != 1 || matches[0].Key != "PROJ-1" {
+		t.Fatalf("Similar() = %+v, want only PROJ-1", matches)
+	}
+	if matches[0].Summary != tokenRefresh.Summary {
+		t.Errorf("Summary = %q, want %q", matches[0].Summary, tokenRefresh.Summary)
+	}
+	if matches[0].Score < 0.5 || matches[0].Score > 1 {
+		t.Errorf("Score = %v, want between threshold and 1", matches[0].Score)
+	}
+}
+
+func TestSimilar_SkipsSelfAndOtherProjects(t *testing.T) {
+	idx := newIndex(t, filepath.Join(t.TempDir(), "tickets.json"), time.Now)
+	other := tokenRefresh
+	other.Key = "OTHER-1"
+	for _, item := range []models.WorkItem{tokenRefresh, other} {
+		if err := idx.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if matches := idx.Similar(tokenRefresh); len(matches) != 0 {
+		t.Errorf("Similar() = %+v, want no matches", matches)
+	}
+}
+
+func TestAdd_SkipsSecurityLevel(t *testing.T) {
+	idx := newIndex(t, filepath.Join(t.TempDir(), "tickets.json"), time.Now)
+	embargoed := tokenRefresh
+	embargoed.SecurityLevel = "Embargoed"
+	if err := idx.Add(embargoed); err != nil {
+		t.Fatal(err)
+	}
+
+	if matches := idx.Similar(sameProblem); len(matches) != 0 {
+		t.Errorf("Similar() = %+v, want the embargoed ticket left out", matches)
+	}
+}
+
+func TestIndex_PersistsAndExpires(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index", "tickets.json")
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := newIndex(t, path, fixedClock(start)).Add(tokenRefresh); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := newIndex(t, path, fixedClock(start.Add(24*time.Hour)))
+	if matches := reloaded.Similar(sameProblem); len(matches) != 1 {
+		t.Errorf("Similar() after reload = %+v, want PROJ-1", matches)
+	}
+
+	expired := newIndex(t, path, fixedClock(start.Add(31*24*time.Hour)))
+	if matches := expired.Similar(sameProblem); len(matches) != 0 {
+		t.Errorf("Similar() after retention = %+v, want no matches", matches)
+	}
+}
+
+func TestNew_RejectsInvalidConfig(t *testing.T) {
+	valid := ticketindex.Config{
+		Path:       filepath.Join(t.TempDir(), "tickets.json"),
+		Retention:  time.Hour,
+		Threshold:  0.7,
+		MaxMatches: 3,
+	}
+	tests := []struct {
+		name   string
+		mutate func(*ticketindex.Config)
+	}{
+		{"empty path", func(c *ticketindex.Config) { c.Path = "" }},
+		{"zero retention", func(c *ticketindex.Config) { c.Retention = 0 }},
+		{"zero threshold", func(c *ticketindex.Config) { c.Threshold = 0 }},
+		{"threshold above one", func(c *ticketindex.Config) { c.Threshold = 1.5 }},
+		{"zero max matches", func(c *ticketindex.Config) { c.MaxMatches = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+			if _, err := ticketindex.New(cfg, zap.NewNop()); err == nil {
+				t.Error("New() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package ticketlock provides per-ticket locks that hold across
+// processes, so that several bot replicas (or a restarted bot and a
+// job it abandoned) never work on the same ticket at once and open
+// duplicate branches or PRs.
+//
+// A [FileLocker] keeps one lock file per ticket in a directory that
+// every replica shares, for example a volume mounted into each
+// container. Lock files are created exclusively, so only one holder
+// succeeds. While a lock is held its file's modification time is
+// refreshed periodically; a lock file left behind by a crashed holder
+// stops being refreshed and is taken over once it is older than the
+// stale threshold.
+package ticketlock
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+)
+
+// Compile-time check that FileLocker implements jobmanager.TicketLocker.
+var _ jobmanager.TicketLocker = (*FileLocker)(nil)
+
+// DefaultStaleAfter is how long a lock file may go without a refresh
+// before another process may take it over.
+const DefaultStaleAfter = 2 * time.Minute
+
+// FileLocker implements [jobmanager.TicketLocker] with lock files.
+type FileLocker struct {
+	dir        string
+	owner      string
+	staleAfter time.Duration
+	logger     *zap.Logger
+}
+
+// NewFileLocker creates a FileLocker that keeps lock files in dir,
+// creating it if needed. A lock not refreshed for staleAfter is
+// considered abandoned; zero uses [DefaultStaleAfter].
+func NewFileLocker(dir string, staleAfter time.Duration, logger *zap.Logger) (*FileLocker, error) {
+	if dir == "" {
+		return nil, errors.New("lock directory must not be empty")
+	}
+	if logger == nil {
+		return nil, errors.New("logger must not be nil")
+	}
+	if staleAfter < 0 {
+		return nil, errors.New("stale threshold must not be negative")
+	}
+	if staleAfter == 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create lock directory: %w", err)
+	}
+
+	host, _ := os.Hostname()
+	return &FileLocker{
+		dir:        dir,
+		owner:      fmt.Sprintf("%s/%d/%s", host, os.Getpid(), rand.Text()),
+		staleAfter: staleAfter,
+		logger:     logger,
+	}, nil
+}
+
+// Lock acquires the lock for ticketKey. Returns an error wrapping
+// [jobmanager.ErrTicketLocked] when another holder's lock is fresh.
+func (l *FileLocker) Lock(ticketKey string) (func(), error) {
+	if ticketKey == "" || strings.ContainsAny(ticketKey, `/\`) || ticketKey == "." || ticketKey == ".." {
+		return nil, fmt.Errorf("invalid ticket key %q", ticketKey)
+	}
+	path := filepath.Join(l.dir, ticketKey+".lock")
+
+	created, err := l.create(path)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		info, err := os.Stat(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// Released between our attempt and the stat.
+		case err != nil:
+			return nil, fmt.Errorf("stat lock file: %w", err)
+		case time.Since(info.ModTime()) < l.staleAfter:
+			return nil, fmt.Errorf("%s: %w", ticketKey, jobmanager.ErrTicketLocked)
+		default:
+			if err := l.removeStale(ticketKey, path, info.ModTime()); err != nil {
+				return nil, err
+			}
+		}
+		if created, err = l.create(path); err != nil {
+			return nil, err
+		}
+		if !created {
+			return nil, fmt.Errorf("%s: %w", ticketKey, jobmanager.ErrTicketLocked)
+		}
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.refresh(path, stop)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			wg.Wait()
+			l.release(ticketKey, path)
+		})
+	}, nil
+}
+
+// removeStale removes the abandoned lock file at path. The file is
+// first renamed aside and checked, so that when two processes take
+// over the same stale lock at once, the slower one does not remove
+// the lock the faster one just created.
+func (l *FileLocker) removeStale(ticketKey, path string, refreshed time.Time) error {
+	holder, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read stale lock file: %w", err)
+	}
+
+	aside := path + "." + rand.Text() + ".stale"
+	if err := os.Rename(path, aside); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("remove stale lock file: %w", err)
+	}
+	defer func() { _ = os.Remove(aside) }()
+
+	if moved, _ := os.ReadFile(aside); string(moved) != string(holder) {
+		// Another process took the lock over first; put its lock back.
+		_ = os.Link(aside, path)
+		return fmt.Errorf("%s: %w", ticketKey, jobmanager.ErrTicketLocked)
+	}
+	l.logger.Warn("Took over stale ticket lock",
+		zap.String("ticket", ticketKey),
+		zap.String("holder", string(holder)),
+		zap.Time("last_refreshed", refreshed))
+	return nil
+}
+
+// create exclusively creates the lock file at path, recording this
+// locker as its holder. Reports false when the file already exists.
+func (l *FileLocker) create(path string) (bool, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if errors.Is(err, os.ErrExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("create lock file: %w", err)
+	}
+	_, err = f.WriteString(l.owner)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return false, fmt.Errorf("write lock file: %w", err)
+	}
+	return true, nil
+}
+
+// refresh keeps the lock file's modification time current until stop
+// is closed, so that other processes do not consider it stale.
+func (l *FileLocker) refresh(path string, stop <-chan struct{}) {
+	ticker := time.NewTicker(l.staleAfter / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			if err := os.Chtimes(path, now, now); err != nil {
+				l.logger.Warn("Failed to refresh ticket lock",
+					zap.String("path", path), zap.Error(err))
+			}
+		}
+	}
+}
+
+// release removes the lock file unless another process has taken it
+// over in the meantime.
+func (l *FileLocker) release(ticketKey, path string) {
+	holder, err := os.ReadFile(path)
+	if err != nil {
+		l.logger.Warn("Failed to read ticket lock on release",
+			zap.String("ticket", ticketKey), zap.Error(err))
+		return
+	}
+	if string(holder) != l.owner {
+		l.logger.Warn("Ticket lock was taken over while held",
+			zap.String("ticket", ticketKey), zap.String("holder", string(holder)))
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		l.logger.Warn("Failed to remove ticket lock",
+			zap.String("ticket", ticketKey), zap.Error(err))
+	}
+}
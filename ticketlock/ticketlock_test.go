@@ -0,0 +1,103 @@
+package ticketlock_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"jira-ai-issue-solver/jobmanager"
+	"jira-ai-issue-solver/ticketlock"
+)
+
+func newLocker(t *testing.T, dir string, staleAfter time.Duration) *ticketlock.FileLocker {
+	t.Helper()
+	l, err := ticketlock.NewFileLocker(dir, staleAfter, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewFileLocker: %v", err)
+	}
+	return l
+}
+
+func TestFileLocker_ExcludesOtherHolders(t *testing.T) {
+	dir := t.TempDir()
+	a := newLocker(t, dir, 0)
+	b := newLocker(t, dir, 0)
+
+	unlock, err := a.Lock("PROJ-1")
+	if err != nil {
+		t.Fatalf("first Lock: %v", err)
+	}
+	if _, err := b.Lock("PROJ-1"); !errors.Is(err, jobmanager.ErrTicketLocked) {
+		t.Fatalf("second Lock error = %v, want ErrTicketLocked", err)
+	}
+	if _, err := a.Lock("PROJ-1"); !errors.Is(err, jobmanager.ErrTicketLocked) {
+		t.Fatalf("relock by holder error = %v, want ErrTicketLocked", err)
+	}
+
+	unlockOther, err := b.Lock("PROJ-2")
+	if err != nil {
+		t.Fatalf("Lock of another ticket: %v", err)
+	}
+	unlockOther()
+
+	unlock()
+	unlock() // idempotent
+	unlock, err = b.Lock("PROJ-1")
+	if err != nil {
+		t.Fatalf("Lock after release: %v", err)
+	}
+	unlock()
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("lock files left behind: %v", entries)
+	}
+}
+
+func TestFileLocker_TakesOverStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "PROJ-1.lock")
+	if err := os.WriteFile(path, []byte("crashed-host/1/x"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := newLocker(t, dir, time.Minute).Lock("PROJ-1")
+	if err != nil {
+		t.Fatalf("Lock over stale file: %v", err)
+	}
+	unlock()
+}
+
+func TestFileLocker_RefreshKeepsLockFresh(t *testing.T) {
+	dir := t.TempDir()
+	staleAfter := 60 * time.Millisecond
+	a := newLocker(t, dir, staleAfter)
+	b := newLocker(t, dir, staleAfter)
+
+	unlock, err := a.Lock("PROJ-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	time.Sleep(3 * staleAfter)
+	if _, err := b.Lock("PROJ-1"); !errors.Is(err, jobmanager.ErrTicketLocked) {
+		t.Errorf("Lock of refreshed lock error = %v, want ErrTicketLocked", err)
+	}
+}
+
+func TestFileLocker_RejectsInvalidKeys(t *testing.T) {
+	l := newLocker(t, t.TempDir(), 0)
+	for _, key := range []string{"", "..", "../PROJ-1", `PROJ\1`} {
+		if _, err := l.Lock(key); err == nil || errors.Is(err, jobmanager.ErrTicketLocked) {
+			t.Errorf("Lock(%q) error = %v, want invalid key error", key, err)
+		}
+	}
+}
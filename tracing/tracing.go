@@ -0,0 +1,78 @@
+// Package tracing configures OpenTelemetry span export for the bot.
+//
+// [Setup] installs a global tracer provider that batches spans and
+// exports them over OTLP/HTTP. Instrumented packages (e.g., executor)
+// obtain tracers from the provider they are given, falling back to
+// the global provider, so tracing is a no-op until Setup runs with
+// tracing enabled.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+
+	"jira-ai-issue-solver/models"
+)
+
+// ShutdownFunc flushes buffered spans and releases exporter
+// resources. Safe to call when tracing is disabled.
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup configures the global tracer provider from cfg. When tracing
+// is disabled, the global provider is left untouched (no-op) and the
+// returned ShutdownFunc does nothing.
+func Setup(ctx context.Context, cfg models.TracingConfig) (ShutdownFunc, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	tp, err := NewProvider(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp.Shutdown, nil
+}
+
+// NewProvider builds an SDK tracer provider exporting over OTLP/HTTP
+// without installing it globally.
+func NewProvider(ctx context.Context, cfg models.TracingConfig) (*sdktrace.TracerProvider, error) {
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(cfg.ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	), nil
+}
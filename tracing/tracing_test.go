@@ -0,0 +1,43 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/tracing"
+)
+
+func TestSetup_DisabledReturnsNoopShutdown(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), models.TracingConfig{})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestNewProvider_BuildsAndShutsDown(t *testing.T) {
+	tp, err := tracing.NewProvider(context.Background(), models.TracingConfig{
+		Enabled:     true,
+		Endpoint:    "127.0.0.1:4318",
+		Insecure:    true,
+		ServiceName: "test-bot",
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+
+	_, span := tp.Tracer("test").Start(context.Background(), "noop")
+	if !span.SpanContext().IsSampled() {
+		t.Error("span not sampled with sample_ratio 1")
+	}
+	span.End()
+
+	// No collector is listening; shutdown must still return promptly.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = tp.Shutdown(ctx)
+}
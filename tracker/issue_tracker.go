@@ -11,7 +11,11 @@
 //   - GitLab Issues: planned
 package tracker
 
-import "jira-ai-issue-solver/models"
+import (
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
 
 // IssueTracker provides operations for interacting with an issue tracking
 // system. Implementations translate these operations into the tracker's
@@ -25,6 +29,12 @@ type IssueTracker interface {
 	// Returns an empty slice (not nil) when no results match.
 	SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error)
 
+	// SearchWorkItemPages finds work items matching the given criteria
+	// and calls fn with each page of results as the tracker returns
+	// it, so large result sets need not be held in memory. An error
+	// returned by fn stops the search and is returned unchanged.
+	SearchWorkItemPages(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error
+
 	// GetWorkItem retrieves a single work item by its key.
 	// Returns the complete work item including security level information.
 	GetWorkItem(key string) (*models.WorkItem, error)
@@ -47,6 +57,14 @@ type IssueTracker interface {
 	// DeleteComment removes a comment from a work item.
 	DeleteComment(key, commentID string) error
 
+	// AddWorklog records time spent on a work item, starting at
+	// started. Trackers may round timeSpent to their own granularity.
+	AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error
+
+	// AddAttachment attaches a file with the given name and content
+	// to a work item.
+	AddAttachment(key, filename string, content []byte) error
+
 	// AddLabel adds a label to a work item.
 	AddLabel(key, label string) error
 
@@ -56,6 +74,20 @@ type IssueTracker interface {
 	// SetFieldValue writes a string value to a named field on a work item.
 	SetFieldValue(key, field, value string) error
 
+	// GetFieldValue reads a named field of a work item as plain text.
+	// Returns "" when the field is unset.
+	GetFieldValue(key, field string) (string, error)
+
+	// LinkWorkItems links two work items with a link of the named
+	// type (e.g., "Relates"). For directed link types, key is the
+	// link's inward item and otherKey its outward item.
+	LinkWorkItems(key, otherKey, linkType string) error
+
+	// AssignWorkItem assigns a work item to the account with the given
+	// tracker account ID (see [models.Author.AccountID]). An empty
+	// accountID leaves the work item unassigned.
+	AssignWorkItem(key, accountID string) error
+
 	// DownloadAttachment fetches the raw content of an attachment by its
 	// tracker-specific download URL. Returns the file bytes.
 	DownloadAttachment(url string) ([]byte, error)
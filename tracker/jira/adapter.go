@@ -6,11 +6,13 @@
 package jira
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -22,11 +24,13 @@ import (
 // operations the adapter actually needs. Any concrete type whose methods
 // match (e.g. *services.JiraServiceImpl) satisfies it implicitly.
 type JiraClient interface {
-	SearchTickets(jql string) (*models.JiraSearchResponse, error)
+	SearchTicketPages(jql string, fn func(page *models.JiraSearchResponse) error) error
 	GetTicket(key string) (*models.JiraTicketResponse, error)
 	GetTicketSecurityLevel(key string) (*models.JiraSecurity, error)
 	UpdateTicketStatus(key string, status string) error
 	AddComment(key string, comment string) error
+	AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error
+	AddAttachment(key, filename string, content []byte) error
 	GetComments(key string) ([]models.JiraComment, error)
 	UpdateComment(key, commentID, body string) error
 	DeleteComment(key, commentID string) error
@@ -34,7 +38,10 @@ type JiraClient interface {
 	RemoveLabel(key, label string) error
 	UpdateTicketFieldByName(key string, fieldName string, value interface{}) error
 	GetFieldIDByName(fieldName string) (string, error)
+	GetTicketWithExpandedFields(key string) (map[string]interface{}, map[string]string, error)
 	DownloadAttachment(url string) ([]byte, error)
+	LinkIssues(key, otherKey, linkType string) error
+	AssignTicket(key, accountID string) error
 }
 
 // Compile-time check that Adapter implements tracker.IssueTracker.
@@ -96,26 +103,43 @@ func resolveContributorField(client JiraClient, logger *zap.Logger) string {
 }
 
 func (a *Adapter) SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error) {
+	items := []models.WorkItem{}
+	err := a.SearchWorkItemPages(criteria, func(page []models.WorkItem) error {
+		items = append(items, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (a *Adapter) SearchWorkItemPages(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error {
 	if err := criteria.Validate(); err != nil {
-		return nil, fmt.Errorf("search work items: %w", err)
+		return fmt.Errorf("search work items: %w", err)
 	}
 
 	jql := buildJQL(criteria, a.contributorFieldRef)
 	a.logger.Debug("Searching work items", zap.String("jql", jql))
 
-	resp, err := a.jira.SearchTickets(jql)
-	if err != nil {
-		return nil, fmt.Errorf("search work items: %w", err)
+	var fnErr error
+	err := a.jira.SearchTicketPages(jql, func(resp *models.JiraSearchResponse) error {
+		items := make([]models.WorkItem, 0, len(resp.Issues))
+		for _, issue := range resp.Issues {
+			// Search results carry the same fields as GetTicket, including
+			// comments and the security level (see SearchTicketPages).
+			items = append(items, mapFieldsToWorkItem(issue.Key, issue.Fields, issue.Fields.Security))
+		}
+		fnErr = fn(items)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
 	}
-
-	items := make([]models.WorkItem, 0, len(resp.Issues))
-	for _, issue := range resp.Issues {
-		// Search results include security level when Jira returns it in
-		// the standard field. For guaranteed security level resolution
-		// (including custom field fallback), use GetWorkItem.
-		items = append(items, mapFieldsToWorkItem(issue.Key, issue.Fields, issue.Fields.Security))
+	if err != nil {
+		return fmt.Errorf("search work items: %w", err)
 	}
-	return items, nil
+	return nil
 }
 
 func (a *Adapter) GetWorkItem(key string) (*models.WorkItem, error) {
@@ -156,6 +180,11 @@ func (a *Adapter) GetComments(key string) ([]models.Comment, error) {
 		return nil, fmt.Errorf("get comments for %s: %w", key, err)
 	}
 
+	return mapComments(jiraComments), nil
+}
+
+// mapComments converts Jira comments into tracker comments.
+func mapComments(jiraComments []models.JiraComment) []models.Comment {
 	comments := make([]models.Comment, 0, len(jiraComments))
 	for _, jc := range jiraComments {
 		comments = append(comments, models.Comment{
@@ -165,7 +194,7 @@ func (a *Adapter) GetComments(key string) ([]models.Comment, error) {
 			AuthorEmail: jc.Author.EmailAddress,
 		})
 	}
-	return comments, nil
+	return comments
 }
 
 func (a *Adapter) UpdateComment(key, commentID, body string) error {
@@ -190,6 +219,20 @@ func (a *Adapter) DownloadAttachment(url string) ([]byte, error) {
 	return data, nil
 }
 
+func (a *Adapter) AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error {
+	if err := a.jira.AddWorklog(key, started, timeSpent, comment); err != nil {
+		return fmt.Errorf("add worklog to %s: %w", key, err)
+	}
+	return nil
+}
+
+func (a *Adapter) AddAttachment(key, filename string, content []byte) error {
+	if err := a.jira.AddAttachment(key, filename, content); err != nil {
+		return fmt.Errorf("add attachment %q to %s: %w", filename, key, err)
+	}
+	return nil
+}
+
 func (a *Adapter) AddLabel(key, label string) error {
 	if err := a.jira.AddLabel(key, label); err != nil {
 		return fmt.Errorf("add label %q to %s: %w", label, key, err)
@@ -214,6 +257,73 @@ func (a *Adapter) SetFieldValue(key, field, value string) error {
 	return nil
 }
 
+func (a *Adapter) LinkWorkItems(key, otherKey, linkType string) error {
+	if err := a.jira.LinkIssues(key, otherKey, linkType); err != nil {
+		return fmt.Errorf("link %s to %s: %w", key, otherKey, err)
+	}
+	return nil
+}
+
+func (a *Adapter) AssignWorkItem(key, accountID string) error {
+	if err := a.jira.AssignTicket(key, accountID); err != nil {
+		return fmt.Errorf("assign %s: %w", key, err)
+	}
+	return nil
+}
+
+func (a *Adapter) GetFieldValue(key, field string) (string, error) {
+	fieldID, err := a.jira.GetFieldIDByName(field)
+	if err != nil {
+		return "", fmt.Errorf("get field %q on %s: %w", field, key, err)
+	}
+	fields, _, err := a.jira.GetTicketWithExpandedFields(key)
+	if err != nil {
+		return "", fmt.Errorf("get field %q on %s: %w", field, key, err)
+	}
+	return fieldText(fields[fieldID]), nil
+}
+
+// fieldText renders a Jira field value as plain text. Rich text (ADF)
+// is flattened to its text content, option fields ({"value": ...})
+// yield their value, and multi-value fields yield one line per value.
+func fieldText(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		var lines []string
+		for _, item := range v {
+			if s := fieldText(item); s != "" {
+				lines = append(lines, "- "+s)
+			}
+		}
+		return strings.Join(lines, "\n")
+	case map[string]interface{}:
+		if value, ok := v["value"].(string); ok {
+			return strings.TrimSpace(value)
+		}
+		if _, ok := v["content"]; ok {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return ""
+			}
+			var text models.ADFText
+			if err := json.Unmarshal(data, &text); err != nil {
+				return ""
+			}
+			return strings.TrimSpace(string(text))
+		}
+		if name, ok := v["name"].(string); ok {
+			return strings.TrimSpace(name)
+		}
+		return ""
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
 // jqlQuote wraps a value in double quotes for JQL, escaping any embedded
 // double quotes to prevent malformed queries or JQL injection.
 func jqlQuote(v string) string {
@@ -223,7 +333,7 @@ func jqlQuote(v string) string {
 // buildJQL converts a SearchCriteria into a Jira JQL query string.
 //
 // Conditions are emitted in a fixed order (project, type+status, status,
-// contributor, labels) and joined with AND. Map keys are sorted to ensure
+// contributor, labels, sprint, fix version) and joined with AND. Map keys are sorted to ensure
 // deterministic output for testability.
 //
 // contributorFieldRef is the JQL field reference for the Contributors
@@ -239,6 +349,14 @@ func buildJQL(criteria models.SearchCriteria, contributorFieldRef string) string
 		conditions = append(conditions, fmt.Sprintf("project IN (%s)", strings.Join(quoted, ", ")))
 	}
 
+	if len(criteria.Keys) > 0 {
+		quoted := make([]string, len(criteria.Keys))
+		for i, key := range criteria.Keys {
+			quoted[i] = jqlQuote(key)
+		}
+		conditions = append(conditions, fmt.Sprintf("key IN (%s)", strings.Join(quoted, ", ")))
+	}
+
 	if len(criteria.StatusByType) > 0 {
 		var typeConditions []string
 		for _, ticketType := range slices.Sorted(maps.Keys(criteria.StatusByType)) {
@@ -283,6 +401,31 @@ func buildJQL(criteria models.SearchCriteria, contributorFieldRef string) string
 		conditions = append(conditions, fmt.Sprintf("labels IN (%s)", strings.Join(quoted, ", ")))
 	}
 
+	if len(criteria.ExcludeLabels) > 0 {
+		quoted := make([]string, len(criteria.ExcludeLabels))
+		for i, l := range criteria.ExcludeLabels {
+			quoted[i] = jqlQuote(l)
+		}
+		// NOT IN alone would also drop work items without labels.
+		conditions = append(conditions, fmt.Sprintf("(labels IS EMPTY OR labels NOT IN (%s))", strings.Join(quoted, ", ")))
+	}
+
+	if criteria.Parent != "" {
+		conditions = append(conditions, fmt.Sprintf("parent = %s", jqlQuote(criteria.Parent)))
+	}
+
+	if criteria.ActiveSprint {
+		conditions = append(conditions, "sprint IN openSprints()")
+	}
+
+	if len(criteria.FixVersions) > 0 {
+		quoted := make([]string, len(criteria.FixVersions))
+		for i, v := range criteria.FixVersions {
+			quoted[i] = jqlQuote(v)
+		}
+		conditions = append(conditions, fmt.Sprintf("fixVersion IN (%s)", strings.Join(quoted, ", ")))
+	}
+
 	jql := strings.Join(conditions, " AND ")
 
 	if criteria.OrderBy != "" {
@@ -309,15 +452,26 @@ func mapFieldsToWorkItem(key string, fields models.JiraFields, security *models.
 		labels = []string{}
 	}
 
+	fixVersions := make([]string, 0, len(fields.FixVersions))
+	for _, v := range fields.FixVersions {
+		fixVersions = append(fixVersions, v.Name)
+	}
+
 	var assignee *models.Author
 	if fields.Assignee != nil {
 		assignee = &models.Author{
-			Name:     fields.Assignee.DisplayName,
-			Email:    fields.Assignee.EmailAddress,
-			Username: fields.Assignee.Name,
+			Name:      fields.Assignee.DisplayName,
+			Email:     fields.Assignee.EmailAddress,
+			Username:  fields.Assignee.Name,
+			AccountID: fields.Assignee.AccountID,
 		}
 	}
 
+	var priority string
+	if fields.Priority != nil {
+		priority = fields.Priority.Name
+	}
+
 	var securityLevel string
 	if security != nil && security.Name != "" && !strings.EqualFold(security.Name, "none") {
 		securityLevel = security.Name
@@ -333,6 +487,38 @@ func mapFieldsToWorkItem(key string, fields models.JiraFields, security *models.
 		})
 	}
 
+	links := make([]models.IssueLink, 0, len(fields.IssueLinks))
+	for _, l := range fields.IssueLinks {
+		relation, linked, outward := l.Type.Inward, l.InwardIssue, false
+		if l.OutwardIssue != nil {
+			relation, linked, outward = l.Type.Outward, l.OutwardIssue, true
+		}
+		if linked == nil {
+			continue
+		}
+		links = append(links, models.IssueLink{
+			Type:     l.Type.Name,
+			Relation: relation,
+			Outward:  outward,
+			Key:      linked.Key,
+			Summary:  linked.Fields.Summary,
+			Status:   linked.Fields.Status.Name,
+			Resolved: linked.Fields.Status.StatusCategory != nil && linked.Fields.Status.StatusCategory.Key == "done",
+		})
+	}
+
+	var parent string
+	if fields.Parent != nil {
+		parent = fields.Parent.Key
+	}
+
+	// Jira pages the comment field; an absent or partial page is left
+	// for GetComments to fetch in full.
+	var comments []models.Comment
+	if c := fields.Comment; c != nil && len(c.Comments) >= c.Total {
+		comments = mapComments(c.Comments)
+	}
+
 	return models.WorkItem{
 		Key:           key,
 		Summary:       fields.Summary,
@@ -342,8 +528,14 @@ func mapFieldsToWorkItem(key string, fields models.JiraFields, security *models.
 		ProjectKey:    fields.Project.Key,
 		Components:    components,
 		Labels:        labels,
+		FixVersions:   fixVersions,
+		Priority:      priority,
+		Created:       fields.Created.Time,
 		Assignee:      assignee,
 		SecurityLevel: securityLevel,
 		Attachments:   attachments,
+		Links:         links,
+		Parent:        parent,
+		Comments:      comments,
 	}
 }
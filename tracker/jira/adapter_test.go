@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -166,7 +167,10 @@ func TestAdapter_GetWorkItem(t *testing.T) {
 							{Name: "backend"},
 							{Name: "api"},
 						},
-						Labels: []string{"good-for-ai", "priority-high"},
+						Labels:      []string{"good-for-ai", "priority-high"},
+						FixVersions: []models.JiraVersion{{ID: "10001", Name: "4.18.z"}},
+						Priority:    &models.JiraPriority{Name: "High"},
+						Created:     models.JiraTime{Time: time.Date(2025, 7, 7, 8, 29, 32, 0, time.UTC)},
 						Assignee: &models.JiraUser{
 							DisplayName:  "Jane Doe",
 							EmailAddress: "jane@example.com",
@@ -195,9 +199,13 @@ func TestAdapter_GetWorkItem(t *testing.T) {
 			ProjectKey:    "PROJ",
 			Components:    []string{"backend", "api"},
 			Labels:        []string{"good-for-ai", "priority-high"},
+			FixVersions:   []string{"4.18.z"},
+			Priority:      "High",
+			Created:       time.Date(2025, 7, 7, 8, 29, 32, 0, time.UTC),
 			Assignee:      &models.Author{Name: "Jane Doe", Email: "jane@example.com", Username: "jdoe"},
 			SecurityLevel: "Internal",
 			Attachments:   []models.Attachment{},
+			Links:         []models.IssueLink{},
 		}
 
 		if !reflect.DeepEqual(got, want) {
@@ -205,6 +213,68 @@ func TestAdapter_GetWorkItem(t *testing.T) {
 		}
 	})
 
+	t.Run("maps issue links from both directions", func(t *testing.T) {
+		blocks := models.JiraIssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}
+		mock := &jiratest.Stub{
+			GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+				return &models.JiraTicketResponse{
+					Key: "PROJ-1",
+					Fields: models.JiraFields{
+						IssueLinks: []models.JiraIssueLink{
+							{Type: blocks, InwardIssue: &models.JiraLinkedIssue{
+								Key: "PROJ-2",
+								Fields: models.JiraLinkedIssueFields{
+									Summary: "Schema change",
+									Status: models.JiraStatus{
+										Name:           "Closed",
+										StatusCategory: &models.JiraStatusCategory{Key: "done"},
+									},
+								},
+							}},
+							{Type: blocks, OutwardIssue: &models.JiraLinkedIssue{
+								Key:    "PROJ-3",
+								Fields: models.JiraLinkedIssueFields{Status: models.JiraStatus{Name: "Open"}},
+							}},
+						},
+					},
+				}, nil
+			},
+		}
+
+		adapter := mustNewAdapter(t, mock)
+		got, err := adapter.GetWorkItem("PROJ-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []models.IssueLink{
+			{Type: "Blocks", Relation: "is blocked by", Key: "PROJ-2", Summary: "Schema change", Status: "Closed", Resolved: true},
+			{Type: "Blocks", Relation: "blocks", Outward: true, Key: "PROJ-3", Status: "Open"},
+		}
+		if !reflect.DeepEqual(got.Links, want) {
+			t.Errorf("Links = %+v, want %+v", got.Links, want)
+		}
+	})
+
+	t.Run("maps parent key", func(t *testing.T) {
+		mock := &jiratest.Stub{
+			GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+				return &models.JiraTicketResponse{
+					Key:    "PROJ-1",
+					Fields: models.JiraFields{Parent: &models.JiraParent{Key: "PROJ-10"}},
+				}, nil
+			},
+		}
+
+		got, err := mustNewAdapter(t, mock).GetWorkItem("PROJ-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Parent != "PROJ-10" {
+			t.Errorf("Parent = %q, want PROJ-10", got.Parent)
+		}
+	})
+
 	t.Run("maps attachments from ticket", func(t *testing.T) {
 		mock := &jiratest.Stub{
 			GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
@@ -444,6 +514,9 @@ func TestAdapter_GetWorkItem(t *testing.T) {
 		if len(got.Labels) != 0 {
 			t.Errorf("Labels should be empty, got %v", got.Labels)
 		}
+		if got.FixVersions == nil {
+			t.Error("FixVersions should be non-nil empty slice, got nil")
+		}
 	})
 
 	t.Run("propagates GetTicket error", func(t *testing.T) {
@@ -508,6 +581,14 @@ func TestAdapter_SearchWorkItems_JQL(t *testing.T) {
 			},
 			wantJQL: `project IN ("PROJ1", "PROJ2")`,
 		},
+		{
+			name: "key filter",
+			criteria: models.SearchCriteria{
+				ProjectKeys: []string{"PROJ1"},
+				Keys:        []string{"PROJ1-1", "PROJ1-2"},
+			},
+			wantJQL: `project IN ("PROJ1") AND key IN ("PROJ1-1", "PROJ1-2")`,
+		},
 		{
 			name: "single type-status pair",
 			criteria: models.SearchCriteria{
@@ -564,6 +645,32 @@ func TestAdapter_SearchWorkItems_JQL(t *testing.T) {
 			},
 			wantJQL: `labels IN ("good-for-ai", "priority-high")`,
 		},
+		{
+			name: "excluded labels keep unlabeled work items",
+			criteria: models.SearchCriteria{
+				ProjectKeys:   []string{"PROJ1"},
+				ExcludeLabels: []string{"ai-dead-letter"},
+			},
+			wantJQL: `project IN ("PROJ1") AND (labels IS EMPTY OR labels NOT IN ("ai-dead-letter"))`,
+		},
+		{
+			name: "active sprint and fix version filters",
+			criteria: models.SearchCriteria{
+				ProjectKeys:  []string{"PROJ1"},
+				ActiveSprint: true,
+				FixVersions:  []string{"1.2", "1.3"},
+			},
+			wantJQL: `project IN ("PROJ1") AND sprint IN openSprints() AND fixVersion IN ("1.2", "1.3")`,
+		},
+		{
+			name: "parent filter",
+			criteria: models.SearchCriteria{
+				ProjectKeys: []string{"PROJ1"},
+				Labels:      []string{"ai-batch"},
+				Parent:      "PROJ1-10",
+			},
+			wantJQL: `project IN ("PROJ1") AND labels IN ("ai-batch") AND parent = "PROJ1-10"`,
+		},
 		{
 			name: "order by",
 			criteria: models.SearchCriteria{
@@ -769,6 +876,42 @@ func TestAdapter_SearchWorkItems_ResultMapping(t *testing.T) {
 		}
 	})
 
+	t.Run("includes comments only when search returned all of them", func(t *testing.T) {
+		comment := models.JiraComment{ID: "1", Body: "Use the v2 API", Author: models.JiraUser{DisplayName: "Alice"}}
+		mock := &jiratest.Stub{
+			SearchTicketsFunc: func(string) (*models.JiraSearchResponse, error) {
+				return &models.JiraSearchResponse{
+					IsLast: true,
+					Issues: []models.JiraIssue{
+						{Key: "PROJ-1", Fields: models.JiraFields{Comment: &models.JiraComments{Comments: []models.JiraComment{comment}, Total: 1}}},
+						{Key: "PROJ-2", Fields: models.JiraFields{Comment: &models.JiraComments{Comments: []models.JiraComment{comment}, Total: 30}}},
+						{Key: "PROJ-3", Fields: models.JiraFields{Comment: &models.JiraComments{}}},
+						{Key: "PROJ-4"},
+					},
+				}, nil
+			},
+		}
+
+		adapter := mustNewAdapter(t, mock)
+		got, err := adapter.SearchWorkItems(models.SearchCriteria{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []models.Comment{{ID: "1", Body: "Use the v2 API", Author: "Alice"}}
+		if !reflect.DeepEqual(got[0].Comments, want) {
+			t.Errorf("complete comments = %+v, want %+v", got[0].Comments, want)
+		}
+		if got[1].Comments != nil {
+			t.Errorf("partial comments = %+v, want nil", got[1].Comments)
+		}
+		if got[2].Comments == nil || len(got[2].Comments) != 0 {
+			t.Errorf("no comments = %#v, want an empty slice", got[2].Comments)
+		}
+		if got[3].Comments != nil {
+			t.Errorf("absent comment field = %+v, want nil", got[3].Comments)
+		}
+	})
+
 	t.Run("propagates search error", func(t *testing.T) {
 		mock := &jiratest.Stub{
 			SearchTicketsFunc: func(string) (*models.JiraSearchResponse, error) {
@@ -993,6 +1136,61 @@ func TestAdapter_SetFieldValue(t *testing.T) {
 	})
 }
 
+func TestAdapter_GetFieldValue(t *testing.T) {
+	adf := map[string]any{
+		"type": "doc",
+		"content": []any{
+			map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": "Given a user"}}},
+			map[string]any{"type": "paragraph", "content": []any{map[string]any{"type": "text", "text": "Then they can log in"}}},
+		},
+	}
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"unset", nil, ""},
+		{"plain text", "  Must log in  ", "Must log in"},
+		{"rich text", adf, "Given a user\nThen they can log in"},
+		{"option", map[string]any{"value": "Done when deployed"}, "Done when deployed"},
+		{"checklist", []any{"Tests pass", map[string]any{"value": "Docs updated"}}, "- Tests pass\n- Docs updated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &jiratest.Stub{
+				GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+					if fieldName != "Acceptance Criteria" {
+						return "customfield_10001", nil
+					}
+					return "customfield_10500", nil
+				},
+				GetTicketWithExpandedFieldsFunc: func(key string) (map[string]any, map[string]string, error) {
+					return map[string]any{"customfield_10500": tt.value}, nil, nil
+				},
+			}
+
+			got, err := mustNewAdapter(t, mock).GetFieldValue("PROJ-1", "Acceptance Criteria")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetFieldValue = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		mock := &jiratest.Stub{
+			GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+				return "", errors.New("field not found")
+			},
+		}
+		if _, err := mustNewAdapter(t, mock).GetFieldValue("PROJ-1", "Missing"); err == nil {
+			t.Error("expected error for unknown field")
+		}
+	})
+}
+
 // ---------------------------------------------------------------------------
 // GetComments
 // ---------------------------------------------------------------------------
@@ -1236,3 +1434,59 @@ func TestAdapter_AddLabel(t *testing.T) {
 		}
 	})
 }
+
+func TestAdapter_SearchWorkItemPages(t *testing.T) {
+	mock := &jiratest.Stub{
+		SearchTicketPagesFunc: func(_ string, fn func(*models.JiraSearchResponse) error) error {
+			for _, keys := range [][]string{{"PROJ-1", "PROJ-2"}, {"PROJ-3"}} {
+				page := &models.JiraSearchResponse{}
+				for _, k := range keys {
+					page.Issues = append(page.Issues, models.JiraIssue{Key: k})
+				}
+				if err := fn(page); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	adapter := mustNewAdapter(t, mock)
+
+	t.Run("delivers each page", func(t *testing.T) {
+		var pages [][]string
+		err := adapter.SearchWorkItemPages(models.SearchCriteria{ProjectKeys: []string{"PROJ"}}, func(items []models.WorkItem) error {
+			var keys []string
+			for _, item := range items {
+				keys = append(keys, item.Key)
+			}
+			pages = append(pages, keys)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pages) != 2 || len(pages[0]) != 2 || pages[1][0] != "PROJ-3" {
+			t.Errorf("pages = %v, want [[PROJ-1 PROJ-2] [PROJ-3]]", pages)
+		}
+	})
+
+	t.Run("callback error is returned unwrapped", func(t *testing.T) {
+		stop := errors.New("stop")
+		err := adapter.SearchWorkItemPages(models.SearchCriteria{ProjectKeys: []string{"PROJ"}}, func([]models.WorkItem) error {
+			return stop
+		})
+		if err != stop {
+			t.Errorf("err = %v, want callback error", err)
+		}
+	})
+
+	t.Run("SearchWorkItems collects all pages", func(t *testing.T) {
+		got, err := adapter.SearchWorkItems(models.SearchCriteria{ProjectKeys: []string{"PROJ"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 3 {
+			t.Errorf("got %d items, want 3", len(got))
+		}
+	})
+}
@@ -2,6 +2,8 @@
 package jiratest
 
 import (
+	"time"
+
 	"jira-ai-issue-solver/models"
 	"jira-ai-issue-solver/tracker/jira"
 )
@@ -13,19 +15,25 @@ var _ jira.JiraClient = (*Stub)(nil)
 // Set the corresponding Func field to control each method's behavior.
 // When a Func field is nil, the method returns zero values.
 type Stub struct {
-	SearchTicketsFunc           func(jql string) (*models.JiraSearchResponse, error)
-	GetTicketFunc               func(key string) (*models.JiraTicketResponse, error)
-	GetTicketSecurityLevelFunc  func(key string) (*models.JiraSecurity, error)
-	UpdateTicketStatusFunc      func(key string, status string) error
-	AddCommentFunc              func(key string, comment string) error
-	GetCommentsFunc             func(key string) ([]models.JiraComment, error)
-	UpdateCommentFunc           func(key, commentID, body string) error
-	DeleteCommentFunc           func(key, commentID string) error
-	AddLabelFunc                func(key, label string) error
-	RemoveLabelFunc             func(key, label string) error
-	UpdateTicketFieldByNameFunc func(key string, fieldName string, value interface{}) error
-	GetFieldIDByNameFunc        func(fieldName string) (string, error)
-	DownloadAttachmentFunc      func(url string) ([]byte, error)
+	SearchTicketsFunc               func(jql string) (*models.JiraSearchResponse, error)
+	SearchTicketPagesFunc           func(jql string, fn func(page *models.JiraSearchResponse) error) error
+	GetTicketFunc                   func(key string) (*models.JiraTicketResponse, error)
+	GetTicketSecurityLevelFunc      func(key string) (*models.JiraSecurity, error)
+	UpdateTicketStatusFunc          func(key string, status string) error
+	AddCommentFunc                  func(key string, comment string) error
+	GetCommentsFunc                 func(key string) ([]models.JiraComment, error)
+	UpdateCommentFunc               func(key, commentID, body string) error
+	DeleteCommentFunc               func(key, commentID string) error
+	AddLabelFunc                    func(key, label string) error
+	AddWorklogFunc                  func(key string, started time.Time, timeSpent time.Duration, comment string) error
+	AddAttachmentFunc               func(key, filename string, content []byte) error
+	RemoveLabelFunc                 func(key, label string) error
+	UpdateTicketFieldByNameFunc     func(key string, fieldName string, value interface{}) error
+	GetFieldIDByNameFunc            func(fieldName string) (string, error)
+	GetTicketWithExpandedFieldsFunc func(key string) (map[string]interface{}, map[string]string, error)
+	DownloadAttachmentFunc          func(url string) ([]byte, error)
+	LinkIssuesFunc                  func(key, otherKey, linkType string) error
+	AssignTicketFunc                func(key, accountID string) error
 }
 
 func (s *Stub) SearchTickets(jql string) (*models.JiraSearchResponse, error) {
@@ -35,6 +43,19 @@ func (s *Stub) SearchTickets(jql string) (*models.JiraSearchResponse, error) {
 	return &models.JiraSearchResponse{}, nil
 }
 
+// SearchTicketPages delivers SearchTicketsFunc's result as a single
+// page when SearchTicketPagesFunc is nil.
+func (s *Stub) SearchTicketPages(jql string, fn func(page *models.JiraSearchResponse) error) error {
+	if s.SearchTicketPagesFunc != nil {
+		return s.SearchTicketPagesFunc(jql, fn)
+	}
+	resp, err := s.SearchTickets(jql)
+	if err != nil {
+		return err
+	}
+	return fn(resp)
+}
+
 func (s *Stub) GetTicket(key string) (*models.JiraTicketResponse, error) {
 	if s.GetTicketFunc != nil {
 		return s.GetTicketFunc(key)
@@ -84,6 +105,20 @@ func (s *Stub) DeleteComment(key, commentID string) error {
 	return nil
 }
 
+func (s *Stub) AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error {
+	if s.AddWorklogFunc != nil {
+		return s.AddWorklogFunc(key, started, timeSpent, comment)
+	}
+	return nil
+}
+
+func (s *Stub) AddAttachment(key, filename string, content []byte) error {
+	if s.AddAttachmentFunc != nil {
+		return s.AddAttachmentFunc(key, filename, content)
+	}
+	return nil
+}
+
 func (s *Stub) AddLabel(key, label string) error {
 	if s.AddLabelFunc != nil {
 		return s.AddLabelFunc(key, label)
@@ -112,9 +147,30 @@ func (s *Stub) GetFieldIDByName(fieldName string) (string, error) {
 	return fieldName, nil
 }
 
+func (s *Stub) GetTicketWithExpandedFields(key string) (map[string]interface{}, map[string]string, error) {
+	if s.GetTicketWithExpandedFieldsFunc != nil {
+		return s.GetTicketWithExpandedFieldsFunc(key)
+	}
+	return map[string]interface{}{}, map[string]string{}, nil
+}
+
 func (s *Stub) DownloadAttachment(url string) ([]byte, error) {
 	if s.DownloadAttachmentFunc != nil {
 		return s.DownloadAttachmentFunc(url)
 	}
 	return nil, nil
 }
+
+func (s *Stub) LinkIssues(key, otherKey, linkType string) error {
+	if s.LinkIssuesFunc != nil {
+		return s.LinkIssuesFunc(key, otherKey, linkType)
+	}
+	return nil
+}
+
+func (s *Stub) AssignTicket(key, accountID string) error {
+	if s.AssignTicketFunc != nil {
+		return s.AssignTicketFunc(key, accountID)
+	}
+	return nil
+}
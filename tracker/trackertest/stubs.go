@@ -2,6 +2,8 @@
 package trackertest
 
 import (
+	"time"
+
 	"jira-ai-issue-solver/models"
 	"jira-ai-issue-solver/tracker"
 )
@@ -13,17 +15,23 @@ var _ tracker.IssueTracker = (*Stub)(nil)
 // Set the corresponding Func field to control each method's behavior.
 // When a Func field is nil, the method returns zero values.
 type Stub struct {
-	SearchWorkItemsFunc    func(criteria models.SearchCriteria) ([]models.WorkItem, error)
-	GetWorkItemFunc        func(key string) (*models.WorkItem, error)
-	TransitionStatusFunc   func(key, status string) error
-	AddCommentFunc         func(key, body string) error
-	GetCommentsFunc        func(key string) ([]models.Comment, error)
-	UpdateCommentFunc      func(key, commentID, body string) error
-	DeleteCommentFunc      func(key, commentID string) error
-	AddLabelFunc           func(key, label string) error
-	RemoveLabelFunc        func(key, label string) error
-	SetFieldValueFunc      func(key, field, value string) error
-	DownloadAttachmentFunc func(url string) ([]byte, error)
+	SearchWorkItemsFunc     func(criteria models.SearchCriteria) ([]models.WorkItem, error)
+	SearchWorkItemPagesFunc func(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error
+	GetWorkItemFunc         func(key string) (*models.WorkItem, error)
+	TransitionStatusFunc    func(key, status string) error
+	AddCommentFunc          func(key, body string) error
+	GetCommentsFunc         func(key string) ([]models.Comment, error)
+	UpdateCommentFunc       func(key, commentID, body string) error
+	DeleteCommentFunc       func(key, commentID string) error
+	AddLabelFunc            func(key, label string) error
+	AddWorklogFunc          func(key string, started time.Time, timeSpent time.Duration, comment string) error
+	AddAttachmentFunc       func(key, filename string, content []byte) error
+	RemoveLabelFunc         func(key, label string) error
+	SetFieldValueFunc       func(key, field, value string) error
+	GetFieldValueFunc       func(key, field string) (string, error)
+	DownloadAttachmentFunc  func(url string) ([]byte, error)
+	LinkWorkItemsFunc       func(key, otherKey, linkType string) error
+	AssignWorkItemFunc      func(key, accountID string) error
 }
 
 func (s *Stub) SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkItem, error) {
@@ -33,6 +41,19 @@ func (s *Stub) SearchWorkItems(criteria models.SearchCriteria) ([]models.WorkIte
 	return []models.WorkItem{}, nil
 }
 
+// SearchWorkItemPages delivers SearchWorkItems' result as a single
+// page when SearchWorkItemPagesFunc is nil.
+func (s *Stub) SearchWorkItemPages(criteria models.SearchCriteria, fn func(page []models.WorkItem) error) error {
+	if s.SearchWorkItemPagesFunc != nil {
+		return s.SearchWorkItemPagesFunc(criteria, fn)
+	}
+	items, err := s.SearchWorkItems(criteria)
+	if err != nil {
+		return err
+	}
+	return fn(items)
+}
+
 func (s *Stub) GetWorkItem(key string) (*models.WorkItem, error) {
 	if s.GetWorkItemFunc != nil {
 		return s.GetWorkItemFunc(key)
@@ -75,6 +96,20 @@ func (s *Stub) DeleteComment(key, commentID string) error {
 	return nil
 }
 
+func (s *Stub) AddWorklog(key string, started time.Time, timeSpent time.Duration, comment string) error {
+	if s.AddWorklogFunc != nil {
+		return s.AddWorklogFunc(key, started, timeSpent, comment)
+	}
+	return nil
+}
+
+func (s *Stub) AddAttachment(key, filename string, content []byte) error {
+	if s.AddAttachmentFunc != nil {
+		return s.AddAttachmentFunc(key, filename, content)
+	}
+	return nil
+}
+
 func (s *Stub) AddLabel(key, label string) error {
 	if s.AddLabelFunc != nil {
 		return s.AddLabelFunc(key, label)
@@ -96,9 +131,30 @@ func (s *Stub) SetFieldValue(key, field, value string) error {
 	return nil
 }
 
+func (s *Stub) GetFieldValue(key, field string) (string, error) {
+	if s.GetFieldValueFunc != nil {
+		return s.GetFieldValueFunc(key, field)
+	}
+	return "", nil
+}
+
 func (s *Stub) DownloadAttachment(url string) ([]byte, error) {
 	if s.DownloadAttachmentFunc != nil {
 		return s.DownloadAttachmentFunc(url)
 	}
 	return nil, nil
 }
+
+func (s *Stub) LinkWorkItems(key, otherKey, linkType string) error {
+	if s.LinkWorkItemsFunc != nil {
+		return s.LinkWorkItemsFunc(key, otherKey, linkType)
+	}
+	return nil
+}
+
+func (s *Stub) AssignWorkItem(key, accountID string) error {
+	if s.AssignWorkItemFunc != nil {
+		return s.AssignWorkItemFunc(key, accountID)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -41,7 +42,7 @@ func NewFSManager(baseDir string, cloner Cloner, logger *zap.Logger) (*FSManager
 	}, nil
 }
 
-func (m *FSManager) Create(ticketKey, repoURL string) (string, error) {
+func (m *FSManager) Create(ticketKey, repoURL string, sparsePaths []string) (string, error) {
 	dir := m.workspacePath(ticketKey)
 
 	if _, err := os.Stat(dir); err == nil {
@@ -52,7 +53,7 @@ func (m *FSManager) Create(ticketKey, repoURL string) (string, error) {
 		return "", fmt.Errorf("create workspace base directory: %w", err)
 	}
 
-	if err := m.cloner.CloneRepository(repoURL, dir); err != nil {
+	if err := m.cloner.CloneRepository(repoURL, dir, sparsePaths); err != nil {
 		// Clean up the directory if clone left partial state.
 		_ = os.RemoveAll(dir)
 		return "", fmt.Errorf("clone repository for %s: %w", ticketKey, err)
@@ -90,7 +91,7 @@ func (m *FSManager) CreateMultiRepo(ticketKey string, repos []RepoEntry, rootRep
 		if err := os.MkdirAll(filepath.Dir(dir), 0o750); err != nil {
 			return "", fmt.Errorf("create workspace parent directory: %w", err)
 		}
-		if err := m.cloner.CloneRepository(rootRepoURL, dir); err != nil {
+		if err := m.cloner.CloneRepository(rootRepoURL, dir, nil); err != nil {
 			_ = os.RemoveAll(dir)
 			return "", fmt.Errorf("clone root repo for %s: %w", ticketKey, err)
 		}
@@ -102,7 +103,7 @@ func (m *FSManager) CreateMultiRepo(ticketKey string, repos []RepoEntry, rootRep
 
 	for _, repo := range repos {
 		repoDir := filepath.Join(dir, repo.Name)
-		if err := m.cloner.CloneRepository(repo.URL, repoDir); err != nil {
+		if err := m.cloner.CloneRepository(repo.URL, repoDir, repo.SparsePaths); err != nil {
 			_ = os.RemoveAll(dir)
 			return "", fmt.Errorf("clone repository %s for %s: %w", repo.Name, ticketKey, err)
 		}
@@ -129,7 +130,7 @@ func (m *FSManager) Find(ticketKey string) (string, bool) {
 	return dir, true
 }
 
-func (m *FSManager) FindOrCreate(ticketKey, repoURL string) (string, bool, error) {
+func (m *FSManager) FindOrCreate(ticketKey, repoURL string, sparsePaths []string) (string, bool, error) {
 	if dir, found := m.Find(ticketKey); found {
 		m.logger.Debug("Reusing existing workspace",
 			zap.String("ticket", ticketKey),
@@ -137,7 +138,7 @@ func (m *FSManager) FindOrCreate(ticketKey, repoURL string) (string, bool, error
 		return dir, true, nil
 	}
 
-	dir, err := m.Create(ticketKey, repoURL)
+	dir, err := m.Create(ticketKey, repoURL, sparsePaths)
 	if err != nil {
 		return "", false, err
 	}
@@ -233,8 +234,9 @@ func (m *FSManager) workspacePath(ticketKey string) string {
 }
 
 // listEntries reads the base directory and returns Info for each
-// subdirectory. Non-directory entries are silently skipped. Returns an
-// empty slice (not nil) when the base directory does not exist.
+// subdirectory. Non-directory entries and hidden directories (such as
+// the ticket lock directory) are silently skipped. Returns an empty
+// slice (not nil) when the base directory does not exist.
 func (m *FSManager) listEntries() ([]Info, error) {
 	dirEntries, err := os.ReadDir(m.baseDir)
 	if errors.Is(err, os.ErrNotExist) {
@@ -246,7 +248,7 @@ func (m *FSManager) listEntries() ([]Info, error) {
 
 	entries := make([]Info, 0, len(dirEntries))
 	for _, de := range dirEntries {
-		if !de.IsDir() {
+		if !de.IsDir() || strings.HasPrefix(de.Name(), ".") {
 			continue
 		}
 		fi, err := de.Info()
@@ -15,12 +15,12 @@ import (
 
 // stubCloner is a test double for workspace.Cloner.
 type stubCloner struct {
-	cloneFunc func(repoURL, directory string) error
+	cloneFunc func(repoURL, directory string, sparsePaths []string) error
 }
 
-func (s *stubCloner) CloneRepository(repoURL, directory string) error {
+func (s *stubCloner) CloneRepository(repoURL, directory string, sparsePaths []string) error {
 	if s.cloneFunc != nil {
-		return s.cloneFunc(repoURL, directory)
+		return s.cloneFunc(repoURL, directory, sparsePaths)
 	}
 	// Simulate a successful clone by creating the directory and a .git marker.
 	if err := os.MkdirAll(filepath.Join(directory, ".git"), 0o750); err != nil {
@@ -73,7 +73,7 @@ func TestCreate_ClonesAndReturnsPath(t *testing.T) {
 
 	var clonedURL, clonedDir string
 	cloner := &stubCloner{
-		cloneFunc: func(repoURL, directory string) error {
+		cloneFunc: func(repoURL, directory string, _ []string) error {
 			clonedURL = repoURL
 			clonedDir = directory
 			return os.MkdirAll(filepath.Join(directory, ".git"), 0o750)
@@ -82,7 +82,7 @@ func TestCreate_ClonesAndReturnsPath(t *testing.T) {
 
 	mgr := mustNewManager(t, baseDir, cloner)
 
-	path, err := mgr.Create("PROJ-123", "https://github.com/org/repo.git")
+	path, err := mgr.Create("PROJ-123", "https://github.com/org/repo.git", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -104,6 +104,24 @@ func TestCreate_ClonesAndReturnsPath(t *testing.T) {
 	}
 }
 
+func TestCreate_PassesSparsePaths(t *testing.T) {
+	var got []string
+	cloner := &stubCloner{
+		cloneFunc: func(_, directory string, sparsePaths []string) error {
+			got = sparsePaths
+			return os.MkdirAll(filepath.Join(directory, ".git"), 0o750)
+		},
+	}
+	mgr := mustNewManager(t, t.TempDir(), cloner)
+
+	if _, err := mgr.Create("PROJ-123", "https://github.com/org/mono.git", []string{".ai-bot", "apps/web"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(got, ",") != ".ai-bot,apps/web" {
+		t.Errorf("sparse paths = %v, want [.ai-bot apps/web]", got)
+	}
+}
+
 func TestCreate_ErrorsWhenWorkspaceAlreadyExists(t *testing.T) {
 	baseDir := t.TempDir()
 	cloner := &stubCloner{}
@@ -114,7 +132,7 @@ func TestCreate_ErrorsWhenWorkspaceAlreadyExists(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err := mgr.Create("PROJ-456", "https://github.com/org/repo.git")
+	_, err := mgr.Create("PROJ-456", "https://github.com/org/repo.git", nil)
 	if err == nil {
 		t.Fatal("expected error for existing workspace, got nil")
 	}
@@ -125,7 +143,7 @@ func TestCreate_CleansUpOnCloneFailure(t *testing.T) {
 
 	cloneErr := errors.New("clone failed: network timeout")
 	cloner := &stubCloner{
-		cloneFunc: func(_, directory string) error {
+		cloneFunc: func(_, directory string, _ []string) error {
 			// Simulate partial clone that created the directory.
 			_ = os.MkdirAll(directory, 0o750)
 			return cloneErr
@@ -134,7 +152,7 @@ func TestCreate_CleansUpOnCloneFailure(t *testing.T) {
 
 	mgr := mustNewManager(t, baseDir, cloner)
 
-	_, err := mgr.Create("PROJ-789", "https://github.com/org/repo.git")
+	_, err := mgr.Create("PROJ-789", "https://github.com/org/repo.git", nil)
 	if err == nil {
 		t.Fatal("expected error from failed clone, got nil")
 	}
@@ -205,7 +223,7 @@ func TestFindOrCreate_ReusesExistingWorkspace(t *testing.T) {
 
 	cloneCalled := false
 	cloner := &stubCloner{
-		cloneFunc: func(_, _ string) error {
+		cloneFunc: func(_, _ string, _ []string) error {
 			cloneCalled = true
 			return nil
 		},
@@ -219,7 +237,7 @@ func TestFindOrCreate_ReusesExistingWorkspace(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	path, reused, err := mgr.FindOrCreate("PROJ-200", "https://github.com/org/repo.git")
+	path, reused, err := mgr.FindOrCreate("PROJ-200", "https://github.com/org/repo.git", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -239,7 +257,7 @@ func TestFindOrCreate_CreatesWhenNotFound(t *testing.T) {
 	cloner := &stubCloner{}
 	mgr := mustNewManager(t, baseDir, cloner)
 
-	path, reused, err := mgr.FindOrCreate("PROJ-300", "https://github.com/org/repo.git")
+	path, reused, err := mgr.FindOrCreate("PROJ-300", "https://github.com/org/repo.git", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -464,13 +482,16 @@ func TestList_SkipsNonDirectoryEntries(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(baseDir, "some-file.txt"), []byte("data"), 0o644); err != nil {
 		t.Fatal(err)
 	}
+	if err := os.MkdirAll(filepath.Join(baseDir, ".locks"), 0o750); err != nil {
+		t.Fatal(err)
+	}
 
 	infos, err := mgr.List()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if len(infos) != 1 {
-		t.Fatalf("len(infos) = %d, want 1 (directory only)", len(infos))
+		t.Fatalf("len(infos) = %d, want 1 (visible directory only)", len(infos))
 	}
 	if infos[0].TicketKey != "PROJ-1" {
 		t.Errorf("TicketKey = %q, want PROJ-1", infos[0].TicketKey)
@@ -501,7 +522,7 @@ func TestCreateMultiRepo_ClonesAllRepos(t *testing.T) {
 
 	var clonedRepos []string
 	cloner := &stubCloner{
-		cloneFunc: func(repoURL, directory string) error {
+		cloneFunc: func(repoURL, directory string, _ []string) error {
 			clonedRepos = append(clonedRepos, filepath.Base(directory))
 			return os.MkdirAll(filepath.Join(directory, ".git"), 0o750)
 		},
@@ -537,6 +558,34 @@ func TestCreateMultiRepo_ClonesAllRepos(t *testing.T) {
 	}
 }
 
+func TestCreateMultiRepo_PassesSparsePathsPerRepo(t *testing.T) {
+	got := map[string][]string{}
+	cloner := &stubCloner{
+		cloneFunc: func(repoURL, directory string, sparsePaths []string) error {
+			got[repoURL] = sparsePaths
+			return os.MkdirAll(filepath.Join(directory, ".git"), 0o750)
+		},
+	}
+	mgr := mustNewManager(t, t.TempDir(), cloner)
+
+	repos := []workspace.RepoEntry{
+		{Name: "web", URL: "https://github.com/org/mono.git", SparsePaths: []string{"apps/web", "libs"}},
+		{Name: "docs", URL: "https://github.com/org/docs.git"},
+	}
+	if _, err := mgr.CreateMultiRepo("PROJ-100", repos, "https://github.com/org/scaffold.git"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(got["https://github.com/org/mono.git"], ",") != "apps/web,libs" {
+		t.Errorf("mono sparse paths = %v, want [apps/web libs]", got["https://github.com/org/mono.git"])
+	}
+	for _, url := range []string{"https://github.com/org/docs.git", "https://github.com/org/scaffold.git"} {
+		if got[url] != nil {
+			t.Errorf("%s sparse paths = %v, want full checkout", url, got[url])
+		}
+	}
+}
+
 func TestCreateMultiRepo_ErrorsWhenWorkspaceExists(t *testing.T) {
 	baseDir := t.TempDir()
 	cloner := &stubCloner{}
@@ -595,7 +644,7 @@ func TestCreateMultiRepo_CleansUpOnPartialCloneFailure(t *testing.T) {
 
 	callCount := 0
 	cloner := &stubCloner{
-		cloneFunc: func(repoURL, directory string) error {
+		cloneFunc: func(repoURL, directory string, _ []string) error {
 			callCount++
 			if callCount == 2 {
 				return errors.New("clone failed: auth error")
@@ -630,7 +679,7 @@ func TestCreateMultiRepo_WithRootRepo_ClonesRootFirst(t *testing.T) {
 
 	var cloneOrder []string
 	cloner := &stubCloner{
-		cloneFunc: func(repoURL, directory string) error {
+		cloneFunc: func(repoURL, directory string, _ []string) error {
 			cloneOrder = append(cloneOrder, repoURL)
 			return os.MkdirAll(filepath.Join(directory, ".git"), 0o750)
 		},
@@ -679,7 +728,7 @@ func TestCreateMultiRepo_WithRootRepo_RootCloneFailure(t *testing.T) {
 	baseDir := t.TempDir()
 
 	cloner := &stubCloner{
-		cloneFunc: func(repoURL, directory string) error {
+		cloneFunc: func(repoURL, directory string, _ []string) error {
 			return errors.New("auth error")
 		},
 	}
@@ -706,7 +755,7 @@ func TestCreateMultiRepo_WithRootRepo_ChildCloneFailure(t *testing.T) {
 
 	callCount := 0
 	cloner := &stubCloner{
-		cloneFunc: func(repoURL, directory string) error {
+		cloneFunc: func(repoURL, directory string, _ []string) error {
 			callCount++
 			if callCount == 2 {
 				return errors.New("child clone failed")
@@ -739,7 +788,7 @@ func TestFindOrCreateMultiRepo_ReusesExistingWorkspace(t *testing.T) {
 
 	cloneCalled := false
 	cloner := &stubCloner{
-		cloneFunc: func(_, _ string) error {
+		cloneFunc: func(_, _ string, _ []string) error {
 			cloneCalled = true
 			return nil
 		},
@@ -773,7 +822,7 @@ func TestFindOrCreateMultiRepo_WithRootRepo_DoesNotRecloneOnReuse(t *testing.T)
 
 	cloneCalled := false
 	cloner := &stubCloner{
-		cloneFunc: func(_, _ string) error {
+		cloneFunc: func(_, _ string, _ []string) error {
 			cloneCalled = true
 			return nil
 		},
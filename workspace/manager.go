@@ -40,8 +40,10 @@ import "time"
 // satisfies this interface.
 type Cloner interface {
 	// CloneRepository clones the repository at repoURL into directory.
-	// The implementation must create the target directory.
-	CloneRepository(repoURL, directory string) error
+	// The implementation must create the target directory. When
+	// sparsePaths is non-empty, only those directories and the files
+	// at the repository root are checked out.
+	CloneRepository(repoURL, directory string, sparsePaths []string) error
 }
 
 // RepoEntry identifies a repository to clone into a multi-repo workspace.
@@ -51,14 +53,19 @@ type RepoEntry struct {
 
 	// URL is the clone URL for the repository.
 	URL string
+
+	// SparsePaths, when non-empty, limits the checkout to these
+	// directories (see [Cloner]).
+	SparsePaths []string
 }
 
 // Manager manages the lifecycle of ticket-scoped workspace directories.
 type Manager interface {
 	// Create clones a repository into a new workspace directory for the
-	// given ticket. Returns the workspace path. Returns an error if a
-	// workspace already exists for this ticket.
-	Create(ticketKey, repoURL string) (string, error)
+	// given ticket, checking out only sparsePaths when non-empty.
+	// Returns the workspace path. Returns an error if a workspace
+	// already exists for this ticket.
+	Create(ticketKey, repoURL string, sparsePaths []string) (string, error)
 
 	// CreateMultiRepo clones multiple repositories into subdirectories
 	// of a new workspace for the given ticket. Each repo is cloned into
@@ -76,7 +83,7 @@ type Manager interface {
 	// FindOrCreate returns an existing workspace or creates a new one.
 	// The bool return value indicates whether an existing workspace was
 	// reused (true) or a new one was created (false).
-	FindOrCreate(ticketKey, repoURL string) (string, bool, error)
+	FindOrCreate(ticketKey, repoURL string, sparsePaths []string) (string, bool, error)
 
 	// FindOrCreateMultiRepo returns an existing workspace or creates a
 	// new multi-repo workspace. The bool return value indicates whether
@@ -14,10 +14,10 @@ var _ workspace.Manager = (*Stub)(nil)
 // Set the corresponding Func field to control each method's behavior.
 // When a Func field is nil, the method returns zero values.
 type Stub struct {
-	CreateFunc                func(ticketKey, repoURL string) (string, error)
+	CreateFunc                func(ticketKey, repoURL string, sparsePaths []string) (string, error)
 	CreateMultiRepoFunc       func(ticketKey string, repos []workspace.RepoEntry, rootRepoURL string) (string, error)
 	FindFunc                  func(ticketKey string) (string, bool)
-	FindOrCreateFunc          func(ticketKey, repoURL string) (string, bool, error)
+	FindOrCreateFunc          func(ticketKey, repoURL string, sparsePaths []string) (string, bool, error)
 	FindOrCreateMultiRepoFunc func(ticketKey string, repos []workspace.RepoEntry, rootRepoURL string) (string, bool, error)
 	CleanupFunc               func(ticketKey string) error
 	CleanupStaleFunc          func(maxAge time.Duration) (int, error)
@@ -25,9 +25,9 @@ type Stub struct {
 	ListFunc                  func() ([]workspace.Info, error)
 }
 
-func (s *Stub) Create(ticketKey, repoURL string) (string, error) {
+func (s *Stub) Create(ticketKey, repoURL string, sparsePaths []string) (string, error) {
 	if s.CreateFunc != nil {
-		return s.CreateFunc(ticketKey, repoURL)
+		return s.CreateFunc(ticketKey, repoURL, sparsePaths)
 	}
 	return "", nil
 }
@@ -46,9 +46,9 @@ func (s *Stub) Find(ticketKey string) (string, bool) {
 	return "", false
 }
 
-func (s *Stub) FindOrCreate(ticketKey, repoURL string) (string, bool, error) {
+func (s *Stub) FindOrCreate(ticketKey, repoURL string, sparsePaths []string) (string, bool, error) {
 	if s.FindOrCreateFunc != nil {
-		return s.FindOrCreateFunc(ticketKey, repoURL)
+		return s.FindOrCreateFunc(ticketKey, repoURL, sparsePaths)
 	}
 	return "", false, nil
 }